@@ -2,11 +2,19 @@ package main
 
 import (
 	"log"
+	"os"
 
 	"github.com/futig/agent-backend/internal/builder"
 )
 
 func main() {
+	if builder.IsMigrateCommand(os.Args[1:]) {
+		if err := builder.RunMigrateCommand(os.Stdout); err != nil {
+			log.Fatal("Migration command failed: ", err)
+		}
+		return
+	}
+
 	app, err := builder.Build()
 	if err != nil {
 		log.Fatal("Failed to build application:", err)
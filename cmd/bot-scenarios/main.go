@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/futig/agent-backend/internal/builder"
+	"github.com/futig/agent-backend/internal/telegram/fakeapi"
+)
+
+// Runs the built-in bot scenarios against the real handlers, a fake Telegram API and the
+// database of the environment, e.g. a test Postgres in CI. Exits with 1 when a scenario fails.
+func main() {
+	// Parsed by the configuration loader together with -env
+	run := flag.String("run", "", "Run only the scenarios whose name contains the text")
+
+	server := fakeapi.NewServer()
+	defer server.Close()
+
+	// The variables win over the env file
+	for key, value := range server.BotEnv() {
+		if err := os.Setenv(key, value); err != nil {
+			log.Fatal("Failed to set ", key, ": ", err)
+		}
+	}
+
+	bot, _, err := builder.BuildTelegramBot()
+	if err != nil {
+		log.Fatal("Failed to build telegram bot: ", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if err := bot.Start(ctx); err != nil {
+		log.Fatal("Failed to start telegram bot: ", err)
+	}
+
+	// Every run plays new users, so onboarding starts from scratch
+	baseUserID := time.Now().UnixMilli() * 10
+
+	// Voice messages are converted with ffmpeg, which the bot image has
+	_, ffmpegErr := exec.LookPath("ffmpeg")
+
+	failed := 0
+	for i, scenario := range fakeapi.Scenarios() {
+		if !strings.Contains(scenario.Name, *run) {
+			continue
+		}
+		if scenario.HasVoice() && ffmpegErr != nil {
+			fmt.Printf("skip %s (no ffmpeg)\n", scenario.Name)
+			continue
+		}
+
+		started := time.Now()
+		if err := scenario.Run(ctx, server, baseUserID+int64(i)); err != nil {
+			failed++
+			fmt.Printf("FAIL %s (%s)\n%v\n", scenario.Name, time.Since(started).Round(time.Millisecond), err)
+			continue
+		}
+		fmt.Printf("ok   %s (%s)\n", scenario.Name, time.Since(started).Round(time.Millisecond))
+	}
+
+	cancel()
+	if err := bot.Stop(); err != nil {
+		log.Println("Failed to stop telegram bot:", err)
+	}
+
+	if failed > 0 {
+		fmt.Printf("%d scenario(s) failed\n", failed)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/futig/agent-backend/internal/builder"
+	"github.com/futig/agent-backend/internal/telegram"
+	"github.com/futig/agent-backend/internal/telegram/fakeapi"
+)
+
+// botLoad runs the bot of the environment in-process against the fake Telegram API, so that
+// virtual users can chat with it
+type botLoad struct {
+	server   *fakeapi.Server
+	bot      telegram.Bot
+	scenario *fakeapi.Scenario
+	nextUser atomic.Int64
+}
+
+func startBotLoad(ctx context.Context, scenarioName string) (*botLoad, error) {
+	var scenario *fakeapi.Scenario
+	for _, candidate := range fakeapi.Scenarios() {
+		if candidate.Name == scenarioName {
+			scenario = candidate
+		}
+	}
+	if scenario == nil {
+		return nil, fmt.Errorf("unknown bot scenario '%s'", scenarioName)
+	}
+
+	server := fakeapi.NewServer()
+
+	// The variables win over the env file
+	for key, value := range server.BotEnv() {
+		if err := os.Setenv(key, value); err != nil {
+			server.Close()
+			return nil, fmt.Errorf("set %s: %w", key, err)
+		}
+	}
+
+	bot, _, err := builder.BuildTelegramBot()
+	if err != nil {
+		server.Close()
+		return nil, fmt.Errorf("build telegram bot: %w", err)
+	}
+	if err := bot.Start(ctx); err != nil {
+		server.Close()
+		return nil, fmt.Errorf("start telegram bot: %w", err)
+	}
+
+	load := &botLoad{
+		server:   server,
+		bot:      bot,
+		scenario: scenario,
+	}
+	// Every session is played by a new user, so onboarding starts from scratch
+	load.nextUser.Store(time.Now().UnixMilli() * 10)
+
+	return load, nil
+}
+
+// runSession plays the scenario once as a new user
+func (l *botLoad) runSession(ctx context.Context, rec *recorder, think func() time.Duration) error {
+	conversation := fakeapi.NewConversation(l.server, l.nextUser.Add(1))
+	conversation.Think = think
+	conversation.OnReply = func(action string, latency time.Duration) {
+		rec.observe("bot "+action, latency)
+	}
+
+	if err := l.scenario.Play(ctx, conversation); err != nil {
+		rec.fail("bot " + l.scenario.Name)
+		return err
+	}
+
+	rec.sessionDone()
+	return nil
+}
+
+func (l *botLoad) stop() {
+	if err := l.bot.Stop(); err != nil {
+		fmt.Fprintln(os.Stderr, "failed to stop telegram bot:", err)
+	}
+	l.server.Close()
+}
@@ -0,0 +1,273 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/google/uuid"
+)
+
+const (
+	loadGoal    = "Нужен сервис бронирования переговорных комнат для сотрудников офиса"
+	loadContext = "Компания на 300 сотрудников, два офиса, сейчас комнаты бронируют в общей таблице"
+	loadAnswer  = "Бронируют все сотрудники, отменяет автор брони или офис-менеджер, бронь от 15 минут до 8 часов"
+)
+
+// apiClient drives the session API like a client without a callback URL: every async request
+// carries an X-Request-ID and its operation is polled until it is done
+type apiClient struct {
+	baseURL  string
+	apiKey   string
+	clientID string
+	poll     time.Duration
+	timeout  time.Duration
+	http     *http.Client
+	rec      *recorder
+}
+
+// runSession plays one interview: start, answers with think times and generation
+func (c *apiClient) runSession(ctx context.Context, answers int, think func() time.Duration) error {
+	req := entity.StartSessionRequest{
+		UserGoal: loadGoal,
+		ContextQuestions: []entity.QuestionWithAnswer{
+			{Question: "Расскажите о компании", Answer: loadContext},
+		},
+	}
+
+	var iteration *entity.IterationWithQuestions
+	err := c.measure(ctx, "start", func(requestID string) (*entity.Operation, error) {
+		status, body, err := c.do(ctx, http.MethodPost, "/interview-session?sync=true", requestID, req)
+		if err != nil {
+			return nil, err
+		}
+		switch status {
+		case http.StatusOK:
+			iteration = &entity.IterationWithQuestions{}
+			return nil, json.Unmarshal(body, iteration)
+		case http.StatusAccepted:
+			// The sync start timed out, the questions come with the operation
+			operation, err := c.waitOperation(ctx, requestID)
+			if err == nil && eventOf(operation) == entity.CallbackEventTypeQuestions {
+				iteration = &entity.IterationWithQuestions{}
+				err = json.Unmarshal(operation.Result, iteration)
+			}
+			return operation, err
+		}
+		return nil, statusError(status, body)
+	})
+	if err != nil {
+		return err
+	}
+	if iteration == nil {
+		return fmt.Errorf("session start returned no questions")
+	}
+	sessionID := iteration.SessionID
+
+	for answered := 0; answered < answers; answered++ {
+		question, ok := nextQuestion(iteration)
+		if !ok {
+			break
+		}
+		if err := sleep(ctx, think()); err != nil {
+			return err
+		}
+
+		answer := loadAnswer
+		if question.AnswerType == entity.QuestionAnswerTypeScale {
+			answer = strconv.Itoa(rand.IntN(entity.ScaleMax-entity.ScaleMin+1) + entity.ScaleMin)
+		}
+
+		var operation *entity.Operation
+		err := c.measure(ctx, "answer", func(requestID string) (*entity.Operation, error) {
+			path := fmt.Sprintf("/interview-session/%s/answer/%s", sessionID, question.ID)
+			status, body, err := c.do(ctx, http.MethodPost, path, requestID, entity.SubmitAnswerRequest{Answer: answer})
+			if err != nil {
+				return nil, err
+			}
+			if status != http.StatusAccepted {
+				return nil, statusError(status, body)
+			}
+			operation, err = c.waitOperation(ctx, requestID)
+			return operation, err
+		})
+		if err != nil {
+			return err
+		}
+
+		switch eventOf(operation) {
+		case entity.CallbackEventTypeQuestions:
+			next := &entity.IterationWithQuestions{}
+			if err := json.Unmarshal(operation.Result, next); err != nil {
+				return fmt.Errorf("decode questions: %w", err)
+			}
+			iteration = next
+			markAnswered(iteration, question.ID)
+		case entity.CallbackEventTypeFinalResult:
+			// The interview ran out of questions and the result was generated right away
+			c.rec.sessionDone()
+			return nil
+		case entity.CallbackEventTypeEstimate:
+			// A large session waits for the confirmation of the generation
+			answered = answers
+		default:
+			// The answer was accepted without new questions
+			markAnswered(iteration, question.ID)
+		}
+	}
+
+	if err := sleep(ctx, think()); err != nil {
+		return err
+	}
+	err = c.measure(ctx, "generate", func(requestID string) (*entity.Operation, error) {
+		path := fmt.Sprintf("/interview-session/%s/generate", sessionID)
+		status, body, err := c.do(ctx, http.MethodPost, path, requestID, entity.GenerateSummaryRequest{})
+		if err != nil {
+			return nil, err
+		}
+		if status != http.StatusAccepted {
+			return nil, statusError(status, body)
+		}
+		return c.waitOperation(ctx, requestID)
+	})
+	if err != nil {
+		return err
+	}
+
+	c.rec.sessionDone()
+	return nil
+}
+
+// measure records the latency of the request until its operation is done, or its failure
+func (c *apiClient) measure(
+	ctx context.Context,
+	name string,
+	request func(requestID string) (*entity.Operation, error),
+) error {
+	started := time.Now()
+	operation, err := request(uuid.New().String())
+	if err == nil && operation != nil && operation.Status == entity.OperationStatusError {
+		err = fmt.Errorf("operation failed: %s", operation.Result)
+	}
+	if err != nil {
+		c.rec.fail(name)
+		return fmt.Errorf("%s: %w", name, err)
+	}
+
+	c.rec.observe(name, time.Since(started))
+	return nil
+}
+
+// waitOperation polls the operation of the request until it is done or failed
+func (c *apiClient) waitOperation(ctx context.Context, requestID string) (*entity.Operation, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	for {
+		status, body, err := c.do(ctx, http.MethodGet, "/operations/"+requestID, "", nil)
+		if err != nil {
+			return nil, err
+		}
+		if status != http.StatusOK && status != http.StatusNotFound {
+			return nil, statusError(status, body)
+		}
+		if status == http.StatusOK {
+			var operation entity.Operation
+			if err := json.Unmarshal(body, &operation); err != nil {
+				return nil, fmt.Errorf("decode operation: %w", err)
+			}
+			if operation.Status == entity.OperationStatusDone || operation.Status == entity.OperationStatusError {
+				return &operation, nil
+			}
+		}
+
+		if err := sleep(ctx, c.poll); err != nil {
+			return nil, fmt.Errorf("operation %s not done within %s", requestID, c.timeout)
+		}
+	}
+}
+
+func (c *apiClient) do(ctx context.Context, method, path, requestID string, payload any) (int, []byte, error) {
+	var body io.Reader
+	if payload != nil {
+		raw, err := json.Marshal(payload)
+		if err != nil {
+			return 0, nil, err
+		}
+		body = bytes.NewReader(raw)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return 0, nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Client-ID", c.clientID)
+	if c.apiKey != "" {
+		req.Header.Set("X-API-Key", c.apiKey)
+	}
+	if requestID != "" {
+		req.Header.Set("X-Request-ID", requestID)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	return resp.StatusCode, respBody, err
+}
+
+func nextQuestion(iteration *entity.IterationWithQuestions) (entity.QuestionDTO, bool) {
+	for _, question := range iteration.Questions {
+		if question.Status == "" || question.Status == entity.AnswerStatusUnanswered {
+			return question, true
+		}
+	}
+	return entity.QuestionDTO{}, false
+}
+
+func markAnswered(iteration *entity.IterationWithQuestions, questionID string) {
+	for i := range iteration.Questions {
+		if iteration.Questions[i].ID == questionID {
+			iteration.Questions[i].Status = entity.AnswerStatusAnswered
+		}
+	}
+}
+
+func eventOf(operation *entity.Operation) entity.CallbackEventType {
+	if operation == nil || operation.Event == nil {
+		return ""
+	}
+	return *operation.Event
+}
+
+func statusError(status int, body []byte) error {
+	const maxBody = 200
+	if len(body) > maxBody {
+		body = body[:maxBody]
+	}
+	return fmt.Errorf("unexpected status %d: %s", status, bytes.TrimSpace(body))
+}
+
+// sleep waits for the duration unless ctx is done first
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
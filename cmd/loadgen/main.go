@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"math/rand/v2"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Simulates concurrent users driving the HTTP API and, optionally, the Telegram bot through the
+// fake Telegram API, and reports throughput and latency percentiles. The API under load should
+// run with ENABLE_MOCKS=true; the bot is built in-process from the environment with mocks.
+func main() {
+	target := flag.String("target", "http://localhost:8080", "Base URL of the HTTP API")
+	apiKey := flag.String("api-key", "", "X-API-Key of the tenant to load")
+	users := flag.Int("users", 10, "Concurrent users of the HTTP API")
+	botUsers := flag.Int("bot-users", 0, "Concurrent users of the Telegram bot, 0 disables the bot")
+	botScenario := flag.String("bot-scenario", "interview", "Bot scenario played by every bot user")
+	duration := flag.Duration("duration", time.Minute, "How long users start new sessions")
+	ramp := flag.Duration("ramp", 10*time.Second, "Time over which the users join")
+	thinkMin := flag.Duration("think-min", time.Second, "Minimum think time of a user before an action")
+	thinkMax := flag.Duration("think-max", 5*time.Second, "Maximum think time of a user before an action")
+	answers := flag.Int("answers", 5, "Questions an HTTP user answers before generating the result")
+	poll := flag.Duration("poll", 250*time.Millisecond, "Polling interval of async operations")
+	timeout := flag.Duration("timeout", 2*time.Minute, "Timeout of one async operation")
+	flag.Parse()
+
+	if *thinkMax < *thinkMin {
+		log.Fatal("-think-max must not be less than -think-min")
+	}
+	think := func() time.Duration {
+		return *thinkMin + rand.N(*thinkMax-*thinkMin+1)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	rec := newRecorder()
+	stopAt := time.Now().Add(*duration)
+	httpClient := &http.Client{Timeout: *timeout}
+
+	var bot *botLoad
+	if *botUsers > 0 {
+		var err error
+		if bot, err = startBotLoad(ctx, *botScenario); err != nil {
+			log.Fatal("Failed to start bot load: ", err)
+		}
+		defer bot.stop()
+	}
+
+	var wg sync.WaitGroup
+	startUsers := func(count int, session func(user int) error) {
+		for user := 0; user < count; user++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if sleep(ctx, *ramp*time.Duration(user)/time.Duration(count)) != nil {
+					return
+				}
+				for time.Now().Before(stopAt) && ctx.Err() == nil {
+					if err := session(user); err != nil && ctx.Err() == nil {
+						log.Printf("user %d: %v", user, err)
+						// A failing target is not hammered in a loop
+						_ = sleep(ctx, think())
+					}
+				}
+			}()
+		}
+	}
+
+	startUsers(*users, func(user int) error {
+		client := &apiClient{
+			baseURL:  strings.TrimRight(*target, "/"),
+			apiKey:   *apiKey,
+			clientID: fmt.Sprintf("loadgen-%d", user),
+			poll:     *poll,
+			timeout:  *timeout,
+			http:     httpClient,
+			rec:      rec,
+		}
+		return client.runSession(ctx, *answers, think)
+	})
+	if bot != nil {
+		startUsers(*botUsers, func(int) error {
+			return bot.runSession(ctx, rec, think)
+		})
+	}
+
+	wg.Wait()
+	rec.report(os.Stdout)
+}
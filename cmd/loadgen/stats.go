@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// recorder collects the latencies and failures of the requests of all virtual users
+type recorder struct {
+	mu        sync.Mutex
+	latencies map[string][]time.Duration
+	errors    map[string]int
+	sessions  int
+	started   time.Time
+}
+
+func newRecorder() *recorder {
+	return &recorder{
+		latencies: make(map[string][]time.Duration),
+		errors:    make(map[string]int),
+		started:   time.Now(),
+	}
+}
+
+func (r *recorder) observe(name string, latency time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.latencies[name] = append(r.latencies[name], latency)
+}
+
+func (r *recorder) fail(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.errors[name]++
+}
+
+func (r *recorder) sessionDone() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sessions++
+}
+
+// report writes throughput and latency percentiles of every request kind
+func (r *recorder) report(w io.Writer) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	elapsed := time.Since(r.started)
+
+	names := make([]string, 0, len(r.latencies))
+	for name := range r.latencies {
+		names = append(names, name)
+	}
+	for name := range r.errors {
+		if _, ok := r.latencies[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	fmt.Fprintf(w, "\nduration %s, completed sessions %d (%.2f/min)\n\n",
+		elapsed.Round(time.Second), r.sessions, float64(r.sessions)/elapsed.Minutes())
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(tw, "request\tcount\terrors\trps\tp50\tp90\tp99\tmax\t")
+	for _, name := range names {
+		latencies := slices.Clone(r.latencies[name])
+		slices.Sort(latencies)
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%.2f\t%s\t%s\t%s\t%s\t\n",
+			name,
+			len(latencies),
+			r.errors[name],
+			float64(len(latencies))/elapsed.Seconds(),
+			percentile(latencies, 0.50),
+			percentile(latencies, 0.90),
+			percentile(latencies, 0.99),
+			percentile(latencies, 1),
+		)
+	}
+	tw.Flush()
+}
+
+// percentile returns the latency below which the share p of the sorted latencies falls
+func percentile(sorted []time.Duration, p float64) string {
+	if len(sorted) == 0 {
+		return "-"
+	}
+	index := int(float64(len(sorted))*p+0.5) - 1
+	index = max(0, min(index, len(sorted)-1))
+	return sorted[index].Round(time.Millisecond).String()
+}
@@ -6,12 +6,28 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/futig/agent-backend/internal/builder"
+	"github.com/futig/agent-backend/internal/pkg/tracing"
 	"go.uber.org/zap"
 )
 
 func main() {
+	if builder.IsMigrateCommand(os.Args[1:]) {
+		if err := builder.RunMigrateCommand(os.Stdout); err != nil {
+			log.Fatal("Migration command failed: ", err)
+		}
+		return
+	}
+
+	if builder.IsBackfillStateCommand(os.Args[1:]) {
+		if err := builder.RunBackfillStateCommand(os.Stdout); err != nil {
+			log.Fatal("State backfill failed: ", err)
+		}
+		return
+	}
+
 	bot, logger, err := builder.BuildTelegramBot()
 	if err != nil {
 		log.Fatal("Failed to build telegram bot:", err)
@@ -40,6 +56,12 @@ func main() {
 			logger.Error("error stopping bot",
 				zap.Error(err))
 		}
+		shutdownCtx, cancelShutdown := context.WithTimeout(context.Background(), 10*time.Second)
+		if err := tracing.Shutdown(shutdownCtx); err != nil {
+			logger.Warn("error flushing traces",
+				zap.Error(err))
+		}
+		cancelShutdown()
 		logger.Info("telegram bot stopped gracefully")
 	case err := <-errChan:
 		logger.Error("telegram bot error",
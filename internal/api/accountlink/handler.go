@@ -0,0 +1,84 @@
+package accountlink
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/pkg/logger"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+type Handler struct {
+	usecase AccountLinkUsecase
+}
+
+func NewHandler(usecase AccountLinkUsecase) *Handler {
+	return &Handler{
+		usecase: usecase,
+	}
+}
+
+// Redeem handles POST /account-links: links the X-Client-ID client to the Telegram user that
+// issued the code with /link and returns the user's current session
+func (h *Handler) Redeem(w http.ResponseWriter, r *http.Request) {
+	ctx := logger.WithAction(r.Context(), "RedeemLinkCode")
+
+	var req entity.RedeemLinkCodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.respondError(ctx, w, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	linked, err := h.usecase.Redeem(ctx, r.Header.Get("X-Client-ID"), req.Code)
+	if err != nil {
+		h.handleUsecaseError(ctx, w, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, linked)
+}
+
+// GetLinkedSession handles GET /account-links/session: the current session of the Telegram user
+// the X-Client-ID client is linked to
+func (h *Handler) GetLinkedSession(w http.ResponseWriter, r *http.Request) {
+	ctx := logger.WithAction(r.Context(), "GetLinkedSession")
+
+	linked, err := h.usecase.LinkedSession(ctx, r.Header.Get("X-Client-ID"))
+	if err != nil {
+		h.handleUsecaseError(ctx, w, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, linked)
+}
+
+func (h *Handler) handleUsecaseError(ctx context.Context, w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, entity.ErrMissingField):
+		h.respondError(ctx, w, http.StatusBadRequest, "invalid parameter", err)
+	case errors.Is(err, entity.ErrLinkCodeInvalid):
+		h.respondError(ctx, w, http.StatusNotFound, "link code is invalid, expired or already used", err)
+	case errors.Is(err, entity.ErrAccountNotLinked):
+		h.respondError(ctx, w, http.StatusNotFound, "client is not linked", err)
+	default:
+		h.respondError(ctx, w, http.StatusInternalServerError, "internal server error", err)
+	}
+}
+
+func (h *Handler) respondJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
+
+func (h *Handler) respondError(ctx context.Context, w http.ResponseWriter, status int, message string, err error) {
+	ctxzap.Error(ctx, message, zap.Error(err))
+	h.respondJSON(w, status, entity.ErrorResponse{
+		Error:   http.StatusText(status),
+		Message: message,
+	})
+}
@@ -0,0 +1,12 @@
+package accountlink
+
+import (
+	"context"
+
+	"github.com/futig/agent-backend/internal/entity"
+)
+
+type AccountLinkUsecase interface {
+	Redeem(ctx context.Context, clientID, code string) (*entity.LinkedSession, error)
+	LinkedSession(ctx context.Context, clientID string) (*entity.LinkedSession, error)
+}
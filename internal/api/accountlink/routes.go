@@ -0,0 +1,13 @@
+package accountlink
+
+import (
+	"github.com/go-chi/chi/v5"
+)
+
+// RegisterRoutes registers the routes linking API clients to Telegram users
+func RegisterRoutes(r chi.Router, h *Handler) {
+	r.Route("/account-links", func(r chi.Router) {
+		r.Post("/", h.Redeem)
+		r.Get("/session", h.GetLinkedSession)
+	})
+}
@@ -0,0 +1,110 @@
+package analytics
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/pkg/formatter"
+	"github.com/futig/agent-backend/internal/pkg/logger"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+const (
+	dateLayout = "2006-01-02"
+	// defaultPeriod is exported when the request has no from date
+	defaultPeriod = 30 * 24 * time.Hour
+)
+
+type Handler struct {
+	usecase AnalyticsUsecase
+}
+
+func NewHandler(usecase AnalyticsUsecase) *Handler {
+	return &Handler{
+		usecase: usecase,
+	}
+}
+
+// ExportAnswers handles GET /admin/analytics/answers: the anonymized questions of the sessions
+// created from the from date to the to date inclusive, in UTC, as CSV or Parquet
+func (h *Handler) ExportAnswers(w http.ResponseWriter, r *http.Request) {
+	ctx := logger.WithAction(r.Context(), "ExportAnalyticsAnswers")
+	query := r.URL.Query()
+
+	format := entity.AnalyticsExportFormat(query.Get("format"))
+	if format == "" {
+		format = entity.AnalyticsExportCSV
+	}
+	if !format.IsValid() {
+		h.respondError(ctx, w, http.StatusBadRequest, "format must be csv or parquet",
+			fmt.Errorf("%w: %s", entity.ErrInvalidFormat, format))
+		return
+	}
+
+	to := time.Now().UTC().Truncate(24 * time.Hour)
+	if value := query.Get("to"); value != "" {
+		date, err := time.Parse(dateLayout, value)
+		if err != nil {
+			h.respondError(ctx, w, http.StatusBadRequest, "to must be a YYYY-MM-DD date", err)
+			return
+		}
+		to = date
+	}
+	to = to.Add(24 * time.Hour) // the to date is included
+
+	from := to.Add(-defaultPeriod)
+	if value := query.Get("from"); value != "" {
+		date, err := time.Parse(dateLayout, value)
+		if err != nil {
+			h.respondError(ctx, w, http.StatusBadRequest, "from must be a YYYY-MM-DD date", err)
+			return
+		}
+		from = date
+	}
+
+	answers, err := h.usecase.ExportAnswers(ctx, from, to)
+	if err != nil {
+		h.handleUsecaseError(ctx, w, err)
+		return
+	}
+
+	export, err := formatter.BuildAnalyticsExport(answers, format)
+	if err != nil {
+		h.respondError(ctx, w, http.StatusInternalServerError, "failed to build export", err)
+		return
+	}
+
+	filename := formatter.AnalyticsFileName(from.Format(dateLayout), to.Add(-24*time.Hour).Format(dateLayout), format)
+	w.Header().Set("Content-Type", formatter.AnalyticsContentType(format))
+	w.Header().Set("Content-Disposition", formatter.ContentDisposition(filename))
+	w.WriteHeader(http.StatusOK)
+	w.Write(export)
+}
+
+func (h *Handler) respondJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
+
+func (h *Handler) respondError(ctx context.Context, w http.ResponseWriter, status int, message string, err error) {
+	ctxzap.Error(ctx, message, zap.Error(err))
+	h.respondJSON(w, status, entity.ErrorResponse{
+		Error:   http.StatusText(status),
+		Message: message,
+	})
+}
+
+func (h *Handler) handleUsecaseError(ctx context.Context, w http.ResponseWriter, err error) {
+	if errors.Is(err, entity.ErrInvalidParameter) {
+		h.respondError(ctx, w, http.StatusBadRequest, "invalid parameter", err)
+	} else {
+		h.respondError(ctx, w, http.StatusInternalServerError, "internal server error", err)
+	}
+}
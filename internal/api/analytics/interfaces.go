@@ -0,0 +1,12 @@
+package analytics
+
+import (
+	"context"
+	"time"
+
+	"github.com/futig/agent-backend/internal/entity"
+)
+
+type AnalyticsUsecase interface {
+	ExportAnswers(ctx context.Context, from, to time.Time) ([]*entity.AnalyticsAnswer, error)
+}
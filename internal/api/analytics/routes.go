@@ -0,0 +1,10 @@
+package analytics
+
+import (
+	"github.com/go-chi/chi/v5"
+)
+
+// RegisterAdminRoutes registers the anonymized analytics export routes that require admin authorization
+func RegisterAdminRoutes(r chi.Router, h *Handler) {
+	r.Get("/analytics/answers", h.ExportAnswers)
+}
@@ -0,0 +1,105 @@
+package featureflag
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/pkg/logger"
+	"github.com/go-chi/chi/v5"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+type Handler struct {
+	usecase FeatureFlagUsecase
+}
+
+func NewHandler(usecase FeatureFlagUsecase) *Handler {
+	return &Handler{
+		usecase: usecase,
+	}
+}
+
+// ListFlags handles GET /admin/feature-flags
+func (h *Handler) ListFlags(w http.ResponseWriter, r *http.Request) {
+	ctx := logger.WithAction(r.Context(), "ListFeatureFlags")
+
+	flags, err := h.usecase.ListFlags(ctx)
+	if err != nil {
+		h.handleUsecaseError(ctx, w, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, map[string]any{"flags": flags})
+}
+
+// SetFlag handles PUT /admin/feature-flags/{name}
+func (h *Handler) SetFlag(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	name := chi.URLParam(r, "name")
+
+	ctx = logger.AddFields(ctx,
+		zap.String("flag", name),
+		zap.String("action", "SetFeatureFlag"),
+	)
+
+	var req entity.SetFeatureFlagRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		ctxzap.Error(ctx, "failed to decode request body", zap.Error(err))
+		h.respondError(ctx, w, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	flag, err := h.usecase.SetFlag(ctx, name, &req)
+	if err != nil {
+		h.handleUsecaseError(ctx, w, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, flag)
+}
+
+// ResetFlag handles DELETE /admin/feature-flags/{name}
+func (h *Handler) ResetFlag(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	name := chi.URLParam(r, "name")
+
+	ctx = logger.AddFields(ctx,
+		zap.String("flag", name),
+		zap.String("action", "ResetFeatureFlag"),
+	)
+
+	if err := h.usecase.ResetFlag(ctx, name); err != nil {
+		h.handleUsecaseError(ctx, w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) respondJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
+
+func (h *Handler) respondError(ctx context.Context, w http.ResponseWriter, status int, message string, err error) {
+	ctxzap.Error(ctx, message, zap.Error(err))
+	h.respondJSON(w, status, entity.ErrorResponse{
+		Error:   http.StatusText(status),
+		Message: message,
+	})
+}
+
+func (h *Handler) handleUsecaseError(ctx context.Context, w http.ResponseWriter, err error) {
+	if errors.Is(err, entity.ErrFeatureFlagNotFound) {
+		h.respondError(ctx, w, http.StatusNotFound, "resource not found", err)
+	} else if errors.Is(err, entity.ErrInvalidParameter) {
+		h.respondError(ctx, w, http.StatusBadRequest, "invalid parameter", err)
+	} else {
+		h.respondError(ctx, w, http.StatusInternalServerError, "internal server error", err)
+	}
+}
@@ -0,0 +1,13 @@
+package featureflag
+
+import (
+	"context"
+
+	"github.com/futig/agent-backend/internal/entity"
+)
+
+type FeatureFlagUsecase interface {
+	ListFlags(ctx context.Context) ([]*entity.FeatureFlagState, error)
+	SetFlag(ctx context.Context, name string, req *entity.SetFeatureFlagRequest) (*entity.FeatureFlagState, error)
+	ResetFlag(ctx context.Context, name string) error
+}
@@ -0,0 +1,14 @@
+package featureflag
+
+import (
+	"github.com/go-chi/chi/v5"
+)
+
+// RegisterAdminRoutes registers feature flag routes that require admin authorization
+func RegisterAdminRoutes(r chi.Router, h *Handler) {
+	r.Route("/feature-flags", func(r chi.Router) {
+		r.Get("/", h.ListFlags)
+		r.Put("/{name}", h.SetFlag)
+		r.Delete("/{name}", h.ResetFlag)
+	})
+}
@@ -0,0 +1,67 @@
+package incident
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/pkg/logger"
+	"github.com/go-chi/chi/v5"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+type Handler struct {
+	usecase IncidentUsecase
+}
+
+func NewHandler(usecase IncidentUsecase) *Handler {
+	return &Handler{
+		usecase: usecase,
+	}
+}
+
+// GetIncidents handles GET /admin/incidents/{code}
+func (h *Handler) GetIncidents(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	code := chi.URLParam(r, "code")
+
+	ctx = logger.AddFields(ctx,
+		zap.String("incident_code", code),
+		zap.String("action", "GetIncidents"),
+	)
+
+	incidents, err := h.usecase.Lookup(ctx, code)
+	if err != nil {
+		h.handleUsecaseError(ctx, w, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, map[string]any{"incidents": incidents})
+}
+
+func (h *Handler) respondJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
+
+func (h *Handler) respondError(ctx context.Context, w http.ResponseWriter, status int, message string, err error) {
+	ctxzap.Error(ctx, message, zap.Error(err))
+	h.respondJSON(w, status, entity.ErrorResponse{
+		Error:   http.StatusText(status),
+		Message: message,
+	})
+}
+
+func (h *Handler) handleUsecaseError(ctx context.Context, w http.ResponseWriter, err error) {
+	if errors.Is(err, entity.ErrIncidentNotFound) {
+		h.respondError(ctx, w, http.StatusNotFound, "resource not found", err)
+	} else if errors.Is(err, entity.ErrMissingField) {
+		h.respondError(ctx, w, http.StatusBadRequest, "missing required field", err)
+	} else {
+		h.respondError(ctx, w, http.StatusInternalServerError, "internal server error", err)
+	}
+}
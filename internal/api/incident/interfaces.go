@@ -0,0 +1,11 @@
+package incident
+
+import (
+	"context"
+
+	"github.com/futig/agent-backend/internal/entity"
+)
+
+type IncidentUsecase interface {
+	Lookup(ctx context.Context, code string) ([]*entity.Incident, error)
+}
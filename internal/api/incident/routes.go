@@ -0,0 +1,10 @@
+package incident
+
+import (
+	"github.com/go-chi/chi/v5"
+)
+
+// RegisterAdminRoutes registers incident routes that require admin authorization
+func RegisterAdminRoutes(r chi.Router, h *Handler) {
+	r.Get("/incidents/{code}", h.GetIncidents)
+}
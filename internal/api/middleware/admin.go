@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+
+	"github.com/futig/agent-backend/internal/entity"
+)
+
+// AdminAuth middleware checks the X-Admin-Token header; admin routes are disabled when token is empty
+func AdminAuth(token string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			provided := r.Header.Get("X-Admin-Token")
+			if token == "" || subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusForbidden)
+				json.NewEncoder(w).Encode(entity.ErrorResponse{
+					Error:   http.StatusText(http.StatusForbidden),
+					Message: "admin authorization required",
+				})
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/futig/agent-backend/internal/entity"
+)
+
+// CallbackSchema middleware reads the callback payload schema version the consumer expects from the
+// X-Callback-Schema-Version header; without the header callbacks use the configured version
+func CallbackSchema(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := r.Header.Get("X-Callback-Schema-Version")
+		if header == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		version, err := strconv.Atoi(header)
+		if err != nil || version < entity.CallbackSchemaV1 || version > entity.LatestCallbackSchemaVersion {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(entity.ErrorResponse{
+				Error: http.StatusText(http.StatusBadRequest),
+				Message: fmt.Sprintf("unsupported X-Callback-Schema-Version, supported versions are %d to %d",
+					entity.CallbackSchemaV1, entity.LatestCallbackSchemaVersion),
+			})
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(entity.WithCallbackSchemaVersion(r.Context(), version)))
+	})
+}
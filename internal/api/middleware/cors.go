@@ -9,7 +9,7 @@ func CORS(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
 		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Admin-Token, X-API-Key, X-Tenant-ID, X-Request-ID, X-Client-ID, X-Callback-Schema-Version")
 		w.Header().Set("Access-Control-Max-Age", "3600")
 
 		// Handle preflight requests
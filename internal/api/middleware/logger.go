@@ -4,11 +4,15 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/futig/agent-backend/internal/entity"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
 	"go.uber.org/zap"
 )
 
+// IncidentCodeHeader carries the incident code of the request, quoted to support to find its errors
+const IncidentCodeHeader = "X-Incident-Code"
+
 // Logger is a middleware that logs HTTP requests
 func Logger(logger *zap.Logger) func(next http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
@@ -23,9 +27,15 @@ func Logger(logger *zap.Logger) func(next http.Handler) http.Handler {
 			)
 
 			requestID := middleware.GetReqID(r.Context())
-			reqLogger := logger.With(zap.String("request_id", requestID))
+			reqLogger := logger.With(
+				zap.String("request_id", requestID),
+				zap.String(entity.CorrelationIDField, requestID),
+			)
 			ctx := ctxzap.ToContext(r.Context(), reqLogger)
+			ctx = entity.WithCorrelationID(ctx, requestID)
 			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			// Errors of the request are recorded as incidents under this code
+			ww.Header().Set(IncidentCodeHeader, entity.IncidentCode(requestID))
 			next.ServeHTTP(ww, r.WithContext(ctx))
 
 			logger.Info("Finish handle HTTP request",
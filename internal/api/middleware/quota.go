@@ -0,0 +1,16 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/futig/agent-backend/internal/entity"
+)
+
+// UsageSubject middleware makes the usage of the request count against the quotas of the API client
+// of the X-Client-ID header; requests without it only count against the quotas of their tenant
+func UsageSubject(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		subject := entity.ClientUsageSubject(r.Header.Get("X-Client-ID"))
+		next.ServeHTTP(w, r.WithContext(entity.WithUsageSubject(r.Context(), subject)))
+	})
+}
@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// TenantResolver maps API keys and tenant IDs to tenants; user API keys also resolve to the user
+type TenantResolver interface {
+	ResolveAPIKey(ctx context.Context, apiKey string) (*entity.Tenant, *entity.User, error)
+	GetTenant(ctx context.Context, id string) (*entity.Tenant, error)
+}
+
+// TenantAuth middleware scopes the request to the tenant of the X-API-Key header;
+// requests without a key use the default tenant unless requireAPIKey is set. Browsers cannot
+// set headers on WebSocket handshakes, so these may pass the key in the api_key query parameter.
+// User API keys also scope the request to the projects and sessions of the user
+func TenantAuth(resolver TenantResolver, requireAPIKey bool) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			apiKey := r.Header.Get("X-API-Key")
+			if apiKey == "" && strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+				apiKey = r.URL.Query().Get("api_key")
+			}
+			if apiKey == "" && requireAPIKey {
+				respondTenantError(w, http.StatusUnauthorized, "API key required")
+				return
+			}
+
+			tenant, user, err := resolver.ResolveAPIKey(r.Context(), apiKey)
+			if err != nil {
+				if errors.Is(err, entity.ErrInvalidAPIKey) {
+					respondTenantError(w, http.StatusUnauthorized, "invalid API key")
+					return
+				}
+				ctxzap.Error(r.Context(), "failed to resolve tenant", zap.Error(err))
+				respondTenantError(w, http.StatusInternalServerError, "internal server error")
+				return
+			}
+
+			ctx := entity.WithUser(entity.WithTenant(r.Context(), tenant), user)
+			fields := []zap.Field{zap.String("tenant_id", tenant.ID)}
+			if user != nil {
+				fields = append(fields, zap.String("user_id", user.ID))
+			}
+			ctx = ctxzap.ToContext(ctx, ctxzap.Extract(ctx).With(fields...))
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// AdminTenant middleware scopes admin requests to the tenant of the X-Tenant-ID header,
+// the default tenant when the header is absent
+func AdminTenant(resolver TenantResolver) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			tenantID := r.Header.Get("X-Tenant-ID")
+			if tenantID == "" {
+				tenantID = entity.DefaultTenantID
+			}
+
+			tenant, err := resolver.GetTenant(r.Context(), tenantID)
+			if err != nil {
+				if errors.Is(err, entity.ErrTenantNotFound) {
+					respondTenantError(w, http.StatusNotFound, "tenant not found")
+					return
+				}
+				ctxzap.Error(r.Context(), "failed to resolve tenant", zap.Error(err))
+				respondTenantError(w, http.StatusInternalServerError, "internal server error")
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(entity.WithTenant(r.Context(), tenant)))
+		})
+	}
+}
+
+func respondTenantError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(entity.ErrorResponse{
+		Error:   http.StatusText(status),
+		Message: message,
+	})
+}
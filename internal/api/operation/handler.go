@@ -0,0 +1,122 @@
+package operation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/pkg/logger"
+	"github.com/go-chi/chi/v5"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+type Handler struct {
+	usecase OperationUsecase
+}
+
+func NewHandler(usecase OperationUsecase) *Handler {
+	return &Handler{
+		usecase: usecase,
+	}
+}
+
+// GetOperation handles GET /operations/{request_id}
+func (h *Handler) GetOperation(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestID := chi.URLParam(r, "request_id")
+
+	ctx = logger.AddFields(ctx,
+		zap.String("request_id", requestID),
+		zap.String("action", "GetOperation"),
+	)
+
+	ctxzap.Debug(ctx, "fetching operation")
+
+	operation, err := h.usecase.GetOperation(ctx, requestID, r.Header.Get("X-Client-ID"))
+	if err != nil {
+		h.handleUsecaseError(ctx, w, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, operation)
+}
+
+// ListOperations handles GET /operations
+func (h *Handler) ListOperations(w http.ResponseWriter, r *http.Request) {
+	ctx := logger.WithAction(r.Context(), "ListOperations")
+
+	skip, _ := strconv.Atoi(r.URL.Query().Get("skip"))
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+	req := entity.ListOperationsRequest{
+		ClientID: r.Header.Get("X-Client-ID"),
+		Skip:     skip,
+		Limit:    limit,
+	}
+
+	req.Normalize()
+
+	ctxzap.Debug(ctx, "listing operations",
+		zap.Int("skip", req.Skip),
+		zap.Int("limit", req.Limit),
+	)
+
+	resp, err := h.usecase.ListOperations(ctx, &req)
+	if err != nil {
+		h.handleUsecaseError(ctx, w, err)
+		return
+	}
+
+	ctxzap.Info(ctx, "operations listed successfully", zap.Int("count", len(resp.Operations)))
+
+	h.respondJSON(w, http.StatusOK, resp)
+}
+
+// ListCallbackDeliveries handles GET /callbacks/{request_id}
+func (h *Handler) ListCallbackDeliveries(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	requestID := chi.URLParam(r, "request_id")
+
+	ctx = logger.AddFields(ctx,
+		zap.String("request_id", requestID),
+		zap.String("action", "ListCallbackDeliveries"),
+	)
+
+	ctxzap.Debug(ctx, "listing callback deliveries")
+
+	resp, err := h.usecase.ListCallbackDeliveries(ctx, requestID)
+	if err != nil {
+		h.handleUsecaseError(ctx, w, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, resp)
+}
+
+func (h *Handler) respondJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
+
+func (h *Handler) respondError(ctx context.Context, w http.ResponseWriter, status int, message string, err error) {
+	ctxzap.Error(ctx, message, zap.Error(err))
+	h.respondJSON(w, status, entity.ErrorResponse{
+		Error:   http.StatusText(status),
+		Message: message,
+	})
+}
+
+func (h *Handler) handleUsecaseError(ctx context.Context, w http.ResponseWriter, err error) {
+	if errors.Is(err, entity.ErrOperationNotFound) || errors.Is(err, entity.ErrCallbackDeliveryNotFound) {
+		h.respondError(ctx, w, http.StatusNotFound, "resource not found", err)
+	} else if errors.Is(err, entity.ErrMissingField) || errors.Is(err, entity.ErrInvalidParameter) {
+		h.respondError(ctx, w, http.StatusBadRequest, "invalid parameter", err)
+	} else {
+		h.respondError(ctx, w, http.StatusInternalServerError, "internal server error", err)
+	}
+}
@@ -0,0 +1,13 @@
+package operation
+
+import (
+	"context"
+
+	"github.com/futig/agent-backend/internal/entity"
+)
+
+type OperationUsecase interface {
+	GetOperation(ctx context.Context, requestID, clientID string) (*entity.Operation, error)
+	ListOperations(ctx context.Context, req *entity.ListOperationsRequest) (*entity.ListOperationsResponse, error)
+	ListCallbackDeliveries(ctx context.Context, requestID string) (*entity.ListCallbackDeliveriesResponse, error)
+}
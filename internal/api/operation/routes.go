@@ -0,0 +1,15 @@
+package operation
+
+import (
+	"github.com/go-chi/chi/v5"
+)
+
+// RegisterRoutes registers operation and callback delivery routes
+func RegisterRoutes(r chi.Router, h *Handler) {
+	r.Route("/operations", func(r chi.Router) {
+		r.Get("/", h.ListOperations)
+		r.Get("/{request_id}", h.GetOperation)
+	})
+
+	r.Get("/callbacks/{request_id}", h.ListCallbackDeliveries)
+}
@@ -1,13 +1,23 @@
 package project
 
-import "github.com/futig/agent-backend/internal/entity"
+import (
+	"time"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/pkg/formatter"
+)
 
 // toProjectSummary converts Project entity to ProjectSummary DTO
-func toProjectSummary(p *entity.Project) *entity.ProjectSummary {
+func toProjectSummary(p *entity.Project, now time.Time) *entity.ProjectSummary {
 	return &entity.ProjectSummary{
-		ID:          p.ID,
-		Title:       p.Title,
-		Description: p.Description,
+		ID:            p.ID,
+		Title:         p.Title,
+		Description:   p.Description,
+		SessionCount:  p.SessionCount,
+		LastUsedAt:    p.LastUsedAt,
+		Usage:         formatter.ProjectUsage(p.SessionCount, p.LastUsedAt, now),
+		LastIndexedAt: p.LastIndexedAt,
+		Stale:         p.Stale,
 	}
 }
 
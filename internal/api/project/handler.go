@@ -6,9 +6,12 @@ import (
 	"errors"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/futig/agent-backend/internal/config"
 	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/pkg/formatter"
+	"github.com/futig/agent-backend/internal/pkg/jobqueue"
 	"github.com/futig/agent-backend/internal/pkg/logger"
 	"github.com/futig/agent-backend/internal/pkg/validator"
 	"github.com/go-chi/chi/v5"
@@ -20,6 +23,7 @@ type Handler struct {
 	usecase      ProjectUsecase
 	cfg          config.FileUploadConfig
 	callbackConn CallbackConnector
+	jobs         JobQueue
 	validator    *validator.Validator
 }
 
@@ -27,12 +31,14 @@ func NewHandler(
 	usecase ProjectUsecase,
 	cfg config.FileUploadConfig,
 	callbackConn CallbackConnector,
+	jobs JobQueue,
 	validator *validator.Validator,
 ) *Handler {
 	return &Handler{
 		usecase:      usecase,
 		cfg:          cfg,
 		callbackConn: callbackConn,
+		jobs:         jobs,
 		validator:    validator,
 	}
 }
@@ -62,7 +68,7 @@ func (h *Handler) CreateProject(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.validator.ValidateCreateProject(&req); err != nil {
+	if err := h.validator.ValidateCreateProject(ctx, &req); err != nil {
 		ctxzap.Error(ctx, "failed to validate request", zap.Error(err))
 		h.respondError(ctx, w, http.StatusBadRequest, "validation failed", err)
 		return
@@ -81,8 +87,8 @@ func (h *Handler) CreateProject(w http.ResponseWriter, r *http.Request) {
 	})
 
 	// Process creation and indexing asynchronously
-	go func() {
-		bgCtx := logger.AddFields(ctxzap.ToContext(context.Background(), ctxzap.Extract(ctx)),
+	h.jobs.Submit(jobqueue.LaneReindex, jobOwner(r), func() {
+		bgCtx := logger.AddFields(detachedContext(ctx),
 			zap.String("request_id", requestID),
 			zap.String("action", "CreateProject-async"),
 		)
@@ -99,7 +105,7 @@ func (h *Handler) CreateProject(w http.ResponseWriter, r *http.Request) {
 		ctxzap.Info(bgCtx, "project created successfully", zap.String("project_id", proj.ID))
 
 		h.callbackConn.SendProjectUpdated(bgCtx, req.CallbackURL, requestID, toCallbackProjectUpdated(proj))
-	}()
+	})
 }
 
 // ListProjects handles GET /projects
@@ -121,21 +127,23 @@ func (h *Handler) ListProjects(w http.ResponseWriter, r *http.Request) {
 		zap.Int("limit", limit),
 	)
 
-	projects, err := h.usecase.ListProjects(ctx, &req)
+	projects, total, err := h.usecase.ListProjects(ctx, &req)
 	if err != nil {
 		h.handleUsecaseError(ctx, w, err)
 		return
 	}
 
+	now := time.Now()
 	summaries := make([]*entity.ProjectSummary, 0, len(projects))
 	for _, p := range projects {
-		summaries = append(summaries, toProjectSummary(p))
+		summaries = append(summaries, toProjectSummary(p, now))
 	}
 
 	ctxzap.Info(ctx, "projects listed successfully", zap.Int("count", len(summaries)))
 
 	h.respondJSON(w, http.StatusOK, &entity.ListProjectsResponse{
 		Projects: summaries,
+		Total:    total,
 	})
 }
 
@@ -157,8 +165,13 @@ func (h *Handler) GetProject(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	detail := toProjectDetail(proj)
+	if detail.Freshness, err = h.usecase.GetProjectFreshness(ctx, projectID); err != nil {
+		ctxzap.Warn(ctx, "failed to get project freshness", zap.Error(err))
+	}
+
 	ctxzap.Info(ctx, "project fetched successfully")
-	h.respondJSON(w, http.StatusOK, toProjectDetail(proj))
+	h.respondJSON(w, http.StatusOK, detail)
 }
 
 // DeleteProject handles DELETE /projects/{project_id}
@@ -224,8 +237,8 @@ func (h *Handler) AddFiles(w http.ResponseWriter, r *http.Request) {
 	})
 
 	// Process file addition and indexing asynchronously
-	go func() {
-		bgCtx := logger.AddFields(ctxzap.ToContext(context.Background(), ctxzap.Extract(ctx)),
+	h.jobs.Submit(jobqueue.LaneReindex, jobOwner(r), func() {
+		bgCtx := logger.AddFields(detachedContext(ctx),
 			zap.String("request_id", requestID),
 			zap.String("project_id", projectID),
 			zap.String("action", "AddFiles-async"),
@@ -259,7 +272,7 @@ func (h *Handler) AddFiles(w http.ResponseWriter, r *http.Request) {
 		}
 
 		h.callbackConn.SendProjectUpdated(bgCtx, req.CallbackURL, requestID, toCallbackProjectUpdated(proj))
-	}()
+	})
 }
 
 // ListFiles handles GET /projects/{project_id}/files
@@ -292,6 +305,143 @@ func (h *Handler) ListFiles(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+// GetFile handles GET /projects/{project_id}/files/{file_id} - Download the original content of a file
+func (h *Handler) GetFile(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	projectID := chi.URLParam(r, "project_id")
+	fileID := chi.URLParam(r, "file_id")
+
+	ctx = logger.AddFields(ctx,
+		zap.String("project_id", projectID),
+		zap.String("file_id", fileID),
+		zap.String("action", "GetFile"),
+	)
+
+	ctxzap.Debug(ctx, "fetching file content")
+
+	file, content, err := h.usecase.GetFileContent(ctx, projectID, fileID)
+	if err != nil {
+		h.handleUsecaseError(ctx, w, err)
+		return
+	}
+
+	contentType := file.ContentType
+	if contentType == "" {
+		contentType = http.DetectContentType(content)
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", formatter.ContentDisposition(file.Filename))
+	w.WriteHeader(http.StatusOK)
+	w.Write(content)
+}
+
+// DeleteFile handles DELETE /projects/{project_id}/files/{file_id}
+func (h *Handler) DeleteFile(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	projectID := chi.URLParam(r, "project_id")
+	fileID := chi.URLParam(r, "file_id")
+
+	ctx = logger.AddFields(ctx,
+		zap.String("project_id", projectID),
+		zap.String("file_id", fileID),
+		zap.String("action", "DeleteFile"),
+	)
+
+	ctxzap.Info(ctx, "deleting file")
+
+	if err := h.usecase.DeleteFile(ctx, projectID, fileID); err != nil {
+		h.handleUsecaseError(ctx, w, err)
+		return
+	}
+
+	ctxzap.Info(ctx, "file deleted successfully")
+	h.respondJSON(w, http.StatusOK, &entity.DeleteProjectResponse{
+		Status: "deleted",
+	})
+}
+
+// CreateSchedule handles POST /projects/{project_id}/schedules
+func (h *Handler) CreateSchedule(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	projectID := chi.URLParam(r, "project_id")
+
+	ctx = logger.AddFields(ctx,
+		zap.String("project_id", projectID),
+		zap.String("action", "CreateSchedule"),
+	)
+
+	var req entity.CreateScheduleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		ctxzap.Error(ctx, "failed to decode request body", zap.Error(err))
+		h.respondError(ctx, w, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+	req.ProjectID = projectID
+
+	if err := h.validator.ValidateCreateSchedule(&req); err != nil {
+		ctxzap.Error(ctx, "failed to validate request", zap.Error(err))
+		h.respondError(ctx, w, http.StatusBadRequest, "validation failed", err)
+		return
+	}
+
+	ctxzap.Info(ctx, "creating schedule", zap.String("cron", req.CronExpr))
+
+	schedule, err := h.usecase.CreateSchedule(ctx, &req)
+	if err != nil {
+		h.handleUsecaseError(ctx, w, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusCreated, schedule)
+}
+
+// ListSchedules handles GET /projects/{project_id}/schedules
+func (h *Handler) ListSchedules(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	projectID := chi.URLParam(r, "project_id")
+
+	ctx = logger.AddFields(ctx,
+		zap.String("project_id", projectID),
+		zap.String("action", "ListSchedules"),
+	)
+
+	ctxzap.Debug(ctx, "listing schedules")
+
+	schedules, err := h.usecase.ListSchedules(ctx, projectID)
+	if err != nil {
+		h.handleUsecaseError(ctx, w, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, &entity.ListSchedulesResponse{
+		Schedules: schedules,
+	})
+}
+
+// DeleteSchedule handles DELETE /projects/{project_id}/schedules/{schedule_id}
+func (h *Handler) DeleteSchedule(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	projectID := chi.URLParam(r, "project_id")
+	scheduleID := chi.URLParam(r, "schedule_id")
+
+	ctx = logger.AddFields(ctx,
+		zap.String("project_id", projectID),
+		zap.String("schedule_id", scheduleID),
+		zap.String("action", "DeleteSchedule"),
+	)
+
+	if err := h.usecase.DeleteSchedule(ctx, projectID, scheduleID); err != nil {
+		h.handleUsecaseError(ctx, w, err)
+		return
+	}
+
+	ctxzap.Info(ctx, "schedule deleted successfully")
+	h.respondJSON(w, http.StatusOK, &entity.DeleteProjectResponse{
+		Status: "deleted",
+	})
+}
+
 // Helper methods
 func (h *Handler) respondJSON(w http.ResponseWriter, status int, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
@@ -312,8 +462,10 @@ func (h *Handler) respondError(ctx context.Context, w http.ResponseWriter, statu
 }
 
 func (h *Handler) handleUsecaseError(ctx context.Context, w http.ResponseWriter, err error) {
-	if errors.Is(err, entity.ErrProjectNotFound) {
+	if errors.Is(err, entity.ErrProjectNotFound) || errors.Is(err, entity.ErrScheduleNotFound) || errors.Is(err, entity.ErrFileNotFound) {
 		h.respondError(ctx, w, http.StatusNotFound, "resource not found", err)
+	} else if errors.Is(err, entity.ErrFileNotStored) {
+		h.respondError(ctx, w, http.StatusGone, "file content is not stored", err)
 	} else if errors.Is(err, entity.ErrInvalidParameter) || errors.Is(err, entity.ErrMissingField) {
 		h.respondError(ctx, w, http.StatusBadRequest, "invalid parameter", err)
 	} else if errors.Is(err, entity.ErrInvalidFile) || errors.Is(err, entity.ErrFileTooLarge) || errors.Is(err, entity.ErrTooManyFiles) || errors.Is(err, entity.ErrInvalidExtension) || errors.Is(err, entity.ErrTotalSizeTooLarge) {
@@ -322,3 +474,18 @@ func (h *Handler) handleUsecaseError(ctx context.Context, w http.ResponseWriter,
 		h.respondError(ctx, w, http.StatusInternalServerError, "internal server error", err)
 	}
 }
+
+// jobOwner is the tenant and the client async jobs of the request are fair-scheduled for
+func jobOwner(r *http.Request) string {
+	return jobqueue.Owner(entity.TenantIDFromContext(r.Context()), r.Header.Get("X-Client-ID"))
+}
+
+// detachedContext outlives the request for async work: it keeps the tenant, the user, the logger and the
+// negotiated callback schema version of the request but not its cancellation
+func detachedContext(ctx context.Context) context.Context {
+	bgCtx := entity.WithUser(entity.WithTenant(context.Background(), entity.TenantFromContext(ctx)), entity.UserFromContext(ctx))
+	if version := entity.CallbackSchemaVersionFromContext(ctx); version != 0 {
+		bgCtx = entity.WithCallbackSchemaVersion(bgCtx, version)
+	}
+	return ctxzap.ToContext(bgCtx, ctxzap.Extract(ctx))
+}
@@ -4,18 +4,30 @@ import (
 	"context"
 
 	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/pkg/jobqueue"
 )
 
 type ProjectUsecase interface {
 	CreateProject(ctx context.Context, req *entity.CreateProjectRequest) (*entity.Project, error)
-	ListProjects(ctx context.Context, req *entity.ListProjectsRequest) ([]*entity.Project, error)
+	ListProjects(ctx context.Context, req *entity.ListProjectsRequest) ([]*entity.Project, int, error)
 	GetProject(ctx context.Context, id string) (*entity.Project, error)
+	GetProjectFreshness(ctx context.Context, id string) (*entity.ProjectFreshness, error)
 	DeleteProject(ctx context.Context, id string) error
 	AddFiles(ctx context.Context, req *entity.AddFilesRequest) ([]*entity.File, error)
 	ListFiles(ctx context.Context, projectID string) ([]*entity.File, error)
+	GetFileContent(ctx context.Context, projectID, fileID string) (*entity.File, []byte, error)
+	DeleteFile(ctx context.Context, projectID, fileID string) error
+	CreateSchedule(ctx context.Context, req *entity.CreateScheduleRequest) (*entity.ProjectSchedule, error)
+	ListSchedules(ctx context.Context, projectID string) ([]*entity.ProjectSchedule, error)
+	DeleteSchedule(ctx context.Context, projectID, scheduleID string) error
 }
 
 type CallbackConnector interface {
 	SendError(ctx context.Context, callbackURL string, requestID string, message string, details map[string]any)
 	SendProjectUpdated(ctx context.Context, callbackURL string, requestID string, data *entity.CallbackProjectUpdatedData)
 }
+
+// JobQueue runs async workflows in priority lanes, taking turns between tenants and clients
+type JobQueue interface {
+	Submit(lane jobqueue.Lane, owner string, run func())
+}
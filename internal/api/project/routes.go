@@ -15,6 +15,14 @@ func RegisterRoutes(r chi.Router, h *Handler) {
 			r.Delete("/", h.DeleteProject)
 			r.Post("/", h.AddFiles)
 			r.Get("/files", h.ListFiles)
+			r.Get("/files/{file_id}", h.GetFile)
+			r.Delete("/files/{file_id}", h.DeleteFile)
+
+			r.Route("/schedules", func(r chi.Router) {
+				r.Post("/", h.CreateSchedule)
+				r.Get("/", h.ListSchedules)
+				r.Delete("/{schedule_id}", h.DeleteSchedule)
+			})
 		})
 	})
 }
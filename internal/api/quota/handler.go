@@ -0,0 +1,62 @@
+package quota
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/pkg/logger"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+type Handler struct {
+	usecase QuotaUsecase
+}
+
+func NewHandler(usecase QuotaUsecase) *Handler {
+	return &Handler{
+		usecase: usecase,
+	}
+}
+
+// GetUsage handles GET /quota: the quotas of the X-Client-ID client and of its tenant
+func (h *Handler) GetUsage(w http.ResponseWriter, r *http.Request) {
+	ctx := logger.WithAction(r.Context(), "GetQuotaUsage")
+
+	usage, err := h.usecase.Usage(ctx)
+	if err != nil {
+		h.respondError(ctx, w, http.StatusInternalServerError, "internal server error", err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, usage)
+}
+
+// GetAnalytics handles GET /admin/analytics/quotas: the tenant quotas and their heaviest users
+func (h *Handler) GetAnalytics(w http.ResponseWriter, r *http.Request) {
+	ctx := logger.WithAction(r.Context(), "GetQuotaAnalytics")
+
+	analytics, err := h.usecase.Analytics(ctx)
+	if err != nil {
+		h.respondError(ctx, w, http.StatusInternalServerError, "internal server error", err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, analytics)
+}
+
+func (h *Handler) respondJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
+
+func (h *Handler) respondError(ctx context.Context, w http.ResponseWriter, status int, message string, err error) {
+	ctxzap.Error(ctx, message, zap.Error(err))
+	h.respondJSON(w, status, entity.ErrorResponse{
+		Error:   http.StatusText(status),
+		Message: message,
+	})
+}
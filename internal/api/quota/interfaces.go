@@ -0,0 +1,12 @@
+package quota
+
+import (
+	"context"
+
+	"github.com/futig/agent-backend/internal/entity"
+)
+
+type QuotaUsecase interface {
+	Usage(ctx context.Context) (*entity.QuotaUsage, error)
+	Analytics(ctx context.Context) (*entity.QuotaAnalytics, error)
+}
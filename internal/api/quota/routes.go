@@ -0,0 +1,15 @@
+package quota
+
+import (
+	"github.com/go-chi/chi/v5"
+)
+
+// RegisterRoutes registers the quota routes of API clients
+func RegisterRoutes(r chi.Router, h *Handler) {
+	r.Get("/quota", h.GetUsage)
+}
+
+// RegisterAdminRoutes registers quota routes that require admin authorization
+func RegisterAdminRoutes(r chi.Router, h *Handler) {
+	r.Get("/analytics/quotas", h.GetAnalytics)
+}
@@ -4,23 +4,48 @@ import (
 	"net/http"
 	"time"
 
+	accountlinkapi "github.com/futig/agent-backend/internal/api/accountlink"
+	analyticsapi "github.com/futig/agent-backend/internal/api/analytics"
 	"github.com/futig/agent-backend/internal/api/docs"
+	featureflagapi "github.com/futig/agent-backend/internal/api/featureflag"
+	incidentapi "github.com/futig/agent-backend/internal/api/incident"
 	"github.com/futig/agent-backend/internal/api/middleware"
+	operationapi "github.com/futig/agent-backend/internal/api/operation"
 	projectapi "github.com/futig/agent-backend/internal/api/project"
+	quotaapi "github.com/futig/agent-backend/internal/api/quota"
 	sessionapi "github.com/futig/agent-backend/internal/api/session"
+	tenantapi "github.com/futig/agent-backend/internal/api/tenant"
+	themeapi "github.com/futig/agent-backend/internal/api/theme"
 	"github.com/go-chi/chi/v5"
 	chimiddleware "github.com/go-chi/chi/v5/middleware"
 	"go.uber.org/zap"
 )
 
 // SetupRouter creates and configures the HTTP router
-func SetupRouter(projectHandler *projectapi.Handler, sessionHandler *sessionapi.Handler, logger *zap.Logger) http.Handler {
+func SetupRouter(
+	projectHandler *projectapi.Handler,
+	sessionHandler *sessionapi.Handler,
+	operationHandler *operationapi.Handler,
+	tenantHandler *tenantapi.Handler,
+	themeHandler *themeapi.Handler,
+	featureFlagHandler *featureflagapi.Handler,
+	quotaHandler *quotaapi.Handler,
+	accountLinkHandler *accountlinkapi.Handler,
+	incidentHandler *incidentapi.Handler,
+	analyticsHandler *analyticsapi.Handler,
+	tenantResolver middleware.TenantResolver,
+	requireAPIKey bool,
+	adminToken string,
+	logger *zap.Logger,
+) http.Handler {
 	r := chi.NewRouter()
 
 	// Middleware stack
 	r.Use(chimiddleware.Recoverer)                 // Recover from panics
 	r.Use(chimiddleware.RequestID)                 // Add request ID
 	r.Use(middleware.Logger(logger))               // Log requests
+	r.Use(middleware.Tracing)                      // Trace requests
+	r.Use(middleware.Metrics)                      // Record request latency
 	r.Use(middleware.CORS)                         // Handle CORS
 	r.Use(chimiddleware.Timeout(60 * time.Second)) // Default timeout
 
@@ -34,9 +59,31 @@ func SetupRouter(projectHandler *projectapi.Handler, sessionHandler *sessionapi.
 	// Swagger documentation endpoints
 	docs.RegisterRoutes(r)
 
-	// Register routes
-	projectapi.RegisterRoutes(r, projectHandler)
-	sessionapi.RegisterRoutes(r, sessionHandler)
+	// Register tenant-scoped routes
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.TenantAuth(tenantResolver, requireAPIKey))
+		r.Use(middleware.CallbackSchema)
+		r.Use(middleware.UsageSubject)
+		projectapi.RegisterRoutes(r, projectHandler)
+		sessionapi.RegisterRoutes(r, sessionHandler)
+		operationapi.RegisterRoutes(r, operationHandler)
+		quotaapi.RegisterRoutes(r, quotaHandler)
+		accountlinkapi.RegisterRoutes(r, accountLinkHandler)
+	})
+
+	// Admin routes
+	r.Route("/admin", func(r chi.Router) {
+		r.Use(middleware.AdminAuth(adminToken))
+		tenantapi.RegisterAdminRoutes(r, tenantHandler)
+		featureflagapi.RegisterAdminRoutes(r, featureFlagHandler)
+		incidentapi.RegisterAdminRoutes(r, incidentHandler)
+		r.With(middleware.AdminTenant(tenantResolver)).Group(func(r chi.Router) {
+			sessionapi.RegisterAdminRoutes(r, sessionHandler)
+			themeapi.RegisterAdminRoutes(r, themeHandler)
+			quotaapi.RegisterAdminRoutes(r, quotaHandler)
+			analyticsapi.RegisterAdminRoutes(r, analyticsHandler)
+		})
+	})
 
 	return r
 }
@@ -4,14 +4,39 @@ import "github.com/futig/agent-backend/internal/entity"
 
 // toSessionDTO converts Session entity to SessionDTO
 func toSessionDTO(session *entity.Session) *entity.SessionDTO {
-	return &entity.SessionDTO{
+	dto := &entity.SessionDTO{
 		ID:               session.ID,
 		ProjectID:        session.ProjectID,
 		Status:           session.Status,
 		CurrentIteration: session.CurrentIteration,
 		Result:           session.Result,
 		Error:            session.Error,
+		IsDemo:           session.IsDemo,
 		CreatedAt:        session.CreatedAt,
 		UpdatedAt:        session.UpdatedAt,
+		LastActivityAt:   session.LastActivityAt,
+
+		CallbackGranularity: session.CallbackGranularity,
+		Language:            session.Language,
+		LanguageExplicit:    session.LanguageExplicit,
+		SummaryStyle:        session.SummaryStyle,
+		PurgedAt:            session.PurgedAt,
+	}
+
+	if q := session.CurrentQuestion; q != nil {
+		dto.CurrentQuestionID = &q.ID
+		dto.CurrentIterationID = &q.IterationID
+		dto.CurrentQuestion = &entity.QuestionDTO{
+			ID:               q.ID,
+			QuestionNumber:   q.QuestionNumber,
+			Status:           q.Status,
+			Question:         q.Question,
+			Explanation:      q.Explanation,
+			Options:          q.Options,
+			ParentQuestionID: q.ParentQuestionID,
+			AnswerType:       q.AnswerType,
+		}
 	}
+
+	return dto
 }
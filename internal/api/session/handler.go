@@ -5,36 +5,67 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"mime"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/futig/agent-backend/internal/entity"
 	"github.com/futig/agent-backend/internal/pkg/formatter"
+	"github.com/futig/agent-backend/internal/pkg/jobqueue"
 	"github.com/futig/agent-backend/internal/pkg/logger"
+	"github.com/futig/agent-backend/internal/pkg/stream"
 	"github.com/futig/agent-backend/internal/pkg/validator"
 	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
 	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
 	"go.uber.org/zap"
 )
 
 type Handler struct {
-	usecase      SessionUsecase
-	callbackConn CallbackConnector
-	validator    *validator.Validator
+	usecase          SessionUsecase
+	callbackConn     CallbackConnector
+	operations       OperationTracker
+	jobs             JobQueue
+	streams          SessionStreams
+	streamCfg        stream.Config
+	validator        *validator.Validator
+	syncStartTimeout time.Duration // how long sync=true starts wait before falling back to 202
 }
 
 func NewHandler(
 	usecase SessionUsecase,
 	validator *validator.Validator,
 	callbackConn CallbackConnector,
+	operations OperationTracker,
+	jobs JobQueue,
+	streams SessionStreams,
+	streamCfg stream.Config,
+	syncStartTimeout time.Duration,
 ) *Handler {
 	return &Handler{
-		usecase:      usecase,
-		validator:    validator,
-		callbackConn: callbackConn,
+		usecase:   usecase,
+		validator: validator,
+		callbackConn: &trackingCallbackConnector{
+			next:        callbackConn,
+			operations:  operations,
+			pending:     usecase,
+			granularity: usecase,
+			streams:     streams,
+		},
+		operations:       operations,
+		jobs:             jobs,
+		streams:          streams,
+		streamCfg:        streamCfg,
+		syncStartTimeout: syncStartTimeout,
 	}
 }
 
-// StartSession handles POST /interview-session - Start new session
+// StartSession handles POST /interview-session - Start new session.
+// With sync=true the first iteration is returned in the response body when questions are
+// generated within the sync timeout; otherwise 202 is returned and the result is delivered
+// via callback (when callback_url is set) or can be polled.
 func (h *Handler) StartSession(w http.ResponseWriter, r *http.Request) {
 	ctx := logger.WithAction(r.Context(), "StartSession")
 
@@ -47,41 +78,246 @@ func (h *Handler) StartSession(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if sync := r.URL.Query().Get("sync"); sync != "" {
+		var err error
+		if req.Sync, err = strconv.ParseBool(sync); err != nil {
+			h.respondError(ctx, w, http.StatusBadRequest, "invalid sync parameter", err)
+			return
+		}
+	}
+
 	if err := h.validator.ValidateStartSession(&req); err != nil {
 		ctxzap.Error(ctx, "failed to validate request", zap.Error(err))
 		h.respondError(ctx, w, http.StatusBadRequest, "validation failed", err)
 		return
 	}
 
+	// Sync starts return the result in the response
+	if !req.Sync {
+		if err := h.validator.ValidateAsyncDelivery(requestID, req.CallbackURL); err != nil {
+			ctxzap.Error(ctx, "failed to validate request", zap.Error(err))
+			h.respondError(ctx, w, http.StatusBadRequest, "validation failed", err)
+			return
+		}
+	}
+
+	req.SessionID = uuid.New().String()
+
+	ctx = logger.AddFields(ctx, zap.String("session_id", req.SessionID))
+
 	ctxzap.Info(ctx, "starting interview session", zap.Any("request", req))
 
+	h.operations.TrackOperation(ctx, requestID, r.Header.Get("X-Client-ID"), entity.OperationKindStartSession, req.SessionID)
+
+	bgCtx := logger.AddFields(detachedContext(ctx),
+		zap.String("request_id", requestID),
+		zap.String("action", "StartSession-async"),
+	)
+
+	done := make(chan startSessionResult, 1)
+	h.jobs.Submit(jobqueue.LaneInteractive, jobOwner(r), func() {
+		h.operations.MarkProcessing(bgCtx, requestID)
+
+		questionsBlock, err := h.usecase.StartHTTPSession(bgCtx, &req)
+		done <- startSessionResult{iteration: questionsBlock, err: err}
+	})
+
+	if req.Sync {
+		timer := time.NewTimer(h.syncStartTimeout)
+		defer timer.Stop()
+
+		select {
+		case res := <-done:
+			// The response carries the result, so it is only recorded for polling
+			h.deliverStartedSession(bgCtx, "", requestID, res)
+
+			if res.err != nil {
+				h.handleUsecaseError(ctx, w, res.err)
+				return
+			}
+
+			ctxzap.Info(ctx, "session started synchronously")
+
+			h.respondJSON(w, http.StatusOK, res.iteration)
+			return
+		case <-timer.C:
+			ctxzap.Info(ctx, "sync start timed out, falling back to async delivery",
+				zap.Duration("timeout", h.syncStartTimeout),
+			)
+		case <-r.Context().Done():
+			ctxzap.Info(ctx, "client disconnected before questions were generated")
+		}
+	}
+
 	go func() {
-		bgCtx := logger.AddFields(ctxzap.ToContext(context.Background(), ctxzap.Extract(ctx)),
+		h.deliverStartedSession(bgCtx, req.CallbackURL, requestID, <-done)
+	}()
+
+	// Return accepted status
+	h.respondJSON(w, http.StatusAccepted, map[string]string{
+		"status":     "accepted",
+		"message":    "session creation is being processed",
+		"session_id": req.SessionID,
+	})
+}
+
+type startSessionResult struct {
+	iteration *entity.IterationWithQuestions
+	err       error
+}
+
+// deliverStartedSession sends the result of question generation to the callback URL, if any
+func (h *Handler) deliverStartedSession(
+	ctx context.Context,
+	callbackURL, requestID string,
+	res startSessionResult,
+) {
+	if res.err != nil {
+		ctxzap.Error(ctx, "failed to start session", zap.Error(res.err))
+		h.callbackConn.SendError(ctx, callbackURL, requestID, "failed to start session", map[string]any{
+			"error": res.err.Error(),
+		})
+		return
+	}
+
+	ctxzap.Info(ctx, "session started successfully")
+
+	h.callbackConn.SendQuestions(ctx, callbackURL, requestID, res.iteration)
+}
+
+// StartTranscriptSession handles POST /interview-session/from-transcript - Generate requirements from a
+// meeting transcript in one go. The transcript is sent either as JSON or as a multipart "file" with the
+// other fields as form values; the result is delivered via callback or can be polled.
+func (h *Handler) StartTranscriptSession(w http.ResponseWriter, r *http.Request) {
+	ctx := logger.WithAction(r.Context(), "StartTranscriptSession")
+
+	requestID := r.Header.Get("X-Request-ID")
+
+	req, err := h.decodeTranscriptSessionRequest(r)
+	if err != nil {
+		ctxzap.Error(ctx, "failed to decode request", zap.Error(err))
+		h.handleUsecaseError(ctx, w, err)
+		return
+	}
+
+	if err := h.validator.ValidateTranscriptSession(req); err != nil {
+		ctxzap.Error(ctx, "failed to validate request", zap.Error(err))
+		h.respondError(ctx, w, http.StatusBadRequest, "validation failed", err)
+		return
+	}
+
+	if err := h.validator.ValidateAsyncDelivery(requestID, req.CallbackURL); err != nil {
+		ctxzap.Error(ctx, "failed to validate request", zap.Error(err))
+		h.respondError(ctx, w, http.StatusBadRequest, "validation failed", err)
+		return
+	}
+
+	req.SessionID = uuid.New().String()
+
+	ctx = logger.AddFields(ctx, zap.String("session_id", req.SessionID))
+
+	ctxzap.Info(ctx, "starting transcript session", zap.Int("transcript_bytes", len(req.Transcript)))
+
+	h.operations.TrackOperation(ctx, requestID, r.Header.Get("X-Client-ID"), entity.OperationKindTranscriptSession, req.SessionID)
+
+	h.jobs.Submit(jobqueue.LaneGeneration, jobOwner(r), func() {
+		bgCtx := logger.AddFields(detachedContext(ctx),
 			zap.String("request_id", requestID),
-			zap.String("action", "StartSession-async"),
+			zap.String("action", "StartTranscriptSession-async"),
 		)
 
-		questionsBlock, err := h.usecase.StartHTTPSession(bgCtx, &req)
-		if err != nil {
-			ctxzap.Error(bgCtx, "failed to start session", zap.Error(err))
-			h.callbackConn.SendError(bgCtx, req.CallbackURL, requestID, "failed to start session", map[string]any{
-				"error": err.Error(),
+		h.operations.MarkProcessing(bgCtx, requestID)
+
+		if _, err := h.usecase.StartTranscriptSession(bgCtx, req); err != nil {
+			ctxzap.Error(bgCtx, "failed to prepare transcript session", zap.Error(err))
+			h.callbackConn.SendError(bgCtx, req.CallbackURL, requestID, "failed to process transcript", map[string]any{
+				"session_id": req.SessionID,
+				"error":      err.Error(),
 			})
 			return
 		}
 
-		ctxzap.Info(bgCtx, "session started successfully")
-
-		h.callbackConn.SendQuestions(bgCtx, req.CallbackURL, requestID, questionsBlock)
-	}()
+		h.estimateOrGenerate(bgCtx, req.CallbackURL, requestID, req.SessionID)
+	})
 
-	// Return accepted status
 	h.respondJSON(w, http.StatusAccepted, map[string]string{
-		"status":  "accepted",
-		"message": "session creation is being processed",
+		"status":     "accepted",
+		"message":    "transcript is being processed",
+		"session_id": req.SessionID,
 	})
 }
 
+// decodeTranscriptSessionRequest reads a transcript session request from a JSON or multipart body
+func (h *Handler) decodeTranscriptSessionRequest(r *http.Request) (*entity.TranscriptSessionRequest, error) {
+	var req entity.TranscriptSessionRequest
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "multipart/form-data" {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			return nil, fmt.Errorf("%w: request body: %v", entity.ErrInvalidFormat, err)
+		}
+		return &req, nil
+	}
+
+	// Parse multipart form (max 10MB)
+	if err := r.ParseMultipartForm(10 << 20); err != nil {
+		return nil, fmt.Errorf("%w: multipart form: %v", entity.ErrInvalidFormat, err)
+	}
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		return nil, fmt.Errorf("%w: file", entity.ErrMissingField)
+	}
+	defer file.Close()
+
+	if err := h.validator.ValidateTranscriptFile(header); err != nil {
+		return nil, err
+	}
+
+	transcript, err := io.ReadAll(file)
+	if err != nil {
+		return nil, fmt.Errorf("read transcript file: %w", err)
+	}
+
+	req.Transcript = string(transcript)
+	req.UserGoal = r.FormValue("user_goal")
+	req.CallbackURL = r.FormValue("callback_url")
+	req.Demo = r.FormValue("demo") == "true"
+
+	if projectID := r.FormValue("project_id"); projectID != "" {
+		req.ProjectID = &projectID
+	}
+
+	if contextQuestions := r.FormValue("context_questions"); contextQuestions != "" {
+		if err := json.Unmarshal([]byte(contextQuestions), &req.ContextQuestions); err != nil {
+			return nil, fmt.Errorf("%w: context_questions: %v", entity.ErrInvalidFormat, err)
+		}
+	}
+
+	return &req, nil
+}
+
+// GetCurrentQuestions handles GET /interview-session/{id}/questions - Get current iteration questions
+func (h *Handler) GetCurrentQuestions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	sessionID := chi.URLParam(r, "id")
+
+	ctx = logger.AddFields(ctx,
+		zap.String("session_id", sessionID),
+		zap.String("action", "GetCurrentQuestions"),
+	)
+
+	ctxzap.Debug(ctx, "fetching current questions")
+
+	iteration, err := h.usecase.GetCurrentQuestions(ctx, sessionID)
+	if err != nil {
+		h.handleUsecaseError(ctx, w, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, iteration)
+}
+
 // GetSession handles GET /interview-session/{id} - Get session status
 func (h *Handler) GetSession(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -133,19 +369,29 @@ func (h *Handler) SubmitTextAnswer(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := h.validator.ValidateAsyncDelivery(requestID, req.CallbackURL); err != nil {
+		ctxzap.Error(ctx, "failed to validate request", zap.Error(err))
+		h.respondError(ctx, w, http.StatusBadRequest, "validation failed", err)
+		return
+	}
+
 	ctxzap.Info(ctx, "submitting text answer",
 		zap.String("question_id", questionID),
 		zap.Bool("is_skipped", req.IsSkipped),
 	)
 
-	go func() {
-		bgCtx := logger.AddFields(ctxzap.ToContext(context.Background(), ctxzap.Extract(ctx)),
+	h.operations.TrackOperation(ctx, requestID, r.Header.Get("X-Client-ID"), entity.OperationKindSubmitAnswer, sessionID)
+
+	h.jobs.Submit(jobqueue.LaneInteractive, jobOwner(r), func() {
+		bgCtx := logger.AddFields(detachedContext(ctx),
 			zap.String("request_id", requestID),
 			zap.String("session_id", sessionID),
 			zap.String("question_id", questionID),
 			zap.String("action", "SubmitTextAnswer-async"),
 		)
 
+		h.operations.MarkProcessing(bgCtx, requestID)
+
 		ctxzap.Info(bgCtx, "processing text answer")
 
 		var iteration *entity.IterationWithQuestions
@@ -153,6 +399,9 @@ func (h *Handler) SubmitTextAnswer(w http.ResponseWriter, r *http.Request) {
 
 		if req.IsSkipped {
 			iteration, err = h.usecase.SkipAnswer(bgCtx, sessionID, questionID)
+			if err == nil && req.SkipReason != "" {
+				err = h.usecase.SetSkipReason(bgCtx, sessionID, questionID, req.SkipReason)
+			}
 		} else {
 			iteration, err = h.usecase.SubmitTextAnswer(bgCtx, sessionID, questionID, req.Answer)
 		}
@@ -166,6 +415,13 @@ func (h *Handler) SubmitTextAnswer(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		h.sendQuestionEvent(bgCtx, req.CallbackURL, requestID, &entity.CallbackQuestionEventData{
+			SessionID:  sessionID,
+			QuestionID: questionID,
+			Answer:     req.Answer,
+			SkipReason: req.SkipReason,
+		}, req.IsSkipped)
+
 		if iteration != nil {
 			h.callbackConn.SendQuestions(bgCtx, req.CallbackURL, requestID, iteration)
 			return
@@ -186,18 +442,8 @@ func (h *Handler) SubmitTextAnswer(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		session, err := h.usecase.GenerateSummary(bgCtx, sessionID)
-		if err != nil {
-			ctxzap.Error(bgCtx, "failed to generate summary", zap.Error(err))
-			h.callbackConn.SendError(bgCtx, req.CallbackURL, requestID, "failed to generate summary", map[string]any{
-				"session_id": sessionID,
-				"error":      err.Error(),
-			})
-			return
-		}
-
-		h.callbackConn.SendFinalResult(bgCtx, req.CallbackURL, requestID, toSessionDTO(session))
-	}()
+		h.estimateOrGenerate(bgCtx, req.CallbackURL, requestID, sessionID)
+	})
 
 	h.respondJSON(w, http.StatusAccepted, map[string]string{
 		"status":  "accepted",
@@ -250,19 +496,29 @@ func (h *Handler) SubmitAudioAnswer(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if err := h.validator.ValidateAsyncDelivery(requestID, req.CallbackURL); err != nil {
+		ctxzap.Error(ctx, "failed to validate request", zap.Error(err))
+		h.respondError(ctx, w, http.StatusBadRequest, "validation failed", err)
+		return
+	}
+
 	ctxzap.Info(ctx, "submitting audio answer",
 		zap.Int64("size_bytes", header.Size),
 		zap.Bool("is_skipped", isSkipped),
 	)
 
-	go func() {
-		bgCtx := logger.AddFields(ctxzap.ToContext(context.Background(), ctxzap.Extract(ctx)),
+	h.operations.TrackOperation(ctx, requestID, r.Header.Get("X-Client-ID"), entity.OperationKindSubmitAnswer, sessionID)
+
+	h.jobs.Submit(jobqueue.LaneInteractive, jobOwner(r), func() {
+		bgCtx := logger.AddFields(detachedContext(ctx),
 			zap.String("request_id", requestID),
 			zap.String("session_id", sessionID),
 			zap.String("question_id", questionID),
 			zap.String("action", "SubmitAudioAnswer-async"),
 		)
 
+		h.operations.MarkProcessing(bgCtx, requestID)
+
 		ctxzap.Info(bgCtx, "processing audio answer")
 
 		var iteration *entity.IterationWithQuestions
@@ -283,6 +539,11 @@ func (h *Handler) SubmitAudioAnswer(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		h.sendQuestionEvent(bgCtx, req.CallbackURL, requestID, &entity.CallbackQuestionEventData{
+			SessionID:  sessionID,
+			QuestionID: questionID,
+		}, req.IsSkipped)
+
 		if iteration != nil {
 			h.callbackConn.SendQuestions(bgCtx, req.CallbackURL, requestID, iteration)
 			return
@@ -303,18 +564,8 @@ func (h *Handler) SubmitAudioAnswer(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
-		session, err := h.usecase.GenerateSummary(bgCtx, sessionID)
-		if err != nil {
-			ctxzap.Error(bgCtx, "failed to generate summary", zap.Error(err))
-			h.callbackConn.SendError(bgCtx, req.CallbackURL, requestID, "failed to generate summary", map[string]any{
-				"session_id": sessionID,
-				"error":      err.Error(),
-			})
-			return
-		}
-
-		h.callbackConn.SendFinalResult(bgCtx, req.CallbackURL, requestID, toSessionDTO(session))
-	}()
+		h.estimateOrGenerate(bgCtx, req.CallbackURL, requestID, sessionID)
+	})
 
 	h.respondJSON(w, http.StatusAccepted, map[string]string{
 		"status":  "accepted",
@@ -322,109 +573,1244 @@ func (h *Handler) SubmitAudioAnswer(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
-// GetSessionResult handles GET /interview-session/{id}/result - Get final result
-func (h *Handler) GetSessionResult(w http.ResponseWriter, r *http.Request) {
+// EstimateGeneration handles GET /interview-session/{id}/estimate - Get generation estimate
+func (h *Handler) EstimateGeneration(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	sessionID := chi.URLParam(r, "id")
 
 	ctx = logger.AddFields(ctx,
 		zap.String("session_id", sessionID),
-		zap.String("action", "GetSessionResult"),
+		zap.String("action", "EstimateGeneration"),
 	)
 
-	formatParam := r.URL.Query().Get("format")
-	if formatParam == "" {
-		formatParam = "markdown"
-	}
+	ctxzap.Debug(ctx, "estimating generation")
 
-	format := entity.ResultFormat(formatParam)
-	if !format.IsValid() {
-		ctxzap.Warn(ctx, "invalid format parameter", zap.String("format", formatParam))
-		h.respondError(ctx, w, http.StatusBadRequest, "invalid format parameter",
-			fmt.Errorf("format must be one of: markdown, json, docx, pdf"))
+	estimate, err := h.usecase.EstimateGeneration(ctx, sessionID)
+	if err != nil {
+		h.handleUsecaseError(ctx, w, err)
 		return
 	}
 
-	ctx = logger.AddFields(ctx, zap.String("format", string(format)))
-	ctxzap.Debug(ctx, "fetching session result")
+	h.respondJSON(w, http.StatusOK, estimate)
+}
 
-	result, err := h.usecase.GetSessionResult(ctx, sessionID)
-	if err != nil {
-		h.handleUsecaseError(ctx, w, err)
+// GenerateSummary handles POST /interview-session/{id}/generate - Confirm generation after an estimate event
+func (h *Handler) GenerateSummary(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	sessionID := chi.URLParam(r, "id")
+
+	requestID := r.Header.Get("X-Request-ID")
+
+	ctx = logger.AddFields(ctx,
+		zap.String("session_id", sessionID),
+		zap.String("action", "GenerateSummary"),
+	)
+
+	var req entity.GenerateSummaryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		ctxzap.Error(ctx, "failed to decode request body", zap.Error(err))
+		h.respondError(ctx, w, http.StatusBadRequest, "invalid request body", err)
 		return
 	}
 
-	// Create formatter
-	factory := formatter.NewFactory()
-	fmtr, err := factory.Create(format)
-	if err != nil {
-		ctxzap.Error(ctx, "format not implemented", zap.Error(err))
-		h.respondError(ctx, w, http.StatusNotImplemented, "format not implemented", err)
+	if err := h.validator.ValidateAsyncDelivery(requestID, req.CallbackURL); err != nil {
+		ctxzap.Error(ctx, "failed to validate request", zap.Error(err))
+		h.respondError(ctx, w, http.StatusBadRequest, "validation failed", err)
 		return
 	}
 
-	formattedResult, err := fmtr.Format(result)
-	if err != nil {
-		ctxzap.Error(ctx, "failed to format result", zap.Error(err))
-		h.respondError(ctx, w, http.StatusInternalServerError, "failed to format result", err)
+	if h.rejectBusySession(ctx, w, sessionID) {
 		return
 	}
 
-	ctxzap.Info(ctx, "session result fetched and formatted successfully")
-	w.Header().Set("Content-Type", fmtr.ContentType())
-	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"requirements-%s%s\"", sessionID, fmtr.FileExtension()))
-	w.WriteHeader(http.StatusOK)
-	w.Write(formattedResult)
+	ctxzap.Info(ctx, "generation confirmed")
+
+	h.operations.TrackOperation(ctx, requestID, r.Header.Get("X-Client-ID"), entity.OperationKindGenerateSummary, sessionID)
+
+	h.jobs.Submit(jobqueue.LaneGeneration, jobOwner(r), func() {
+		bgCtx := logger.AddFields(detachedContext(ctx),
+			zap.String("request_id", requestID),
+			zap.String("session_id", sessionID),
+			zap.String("action", "GenerateSummary-async"),
+		)
+
+		h.operations.MarkProcessing(bgCtx, requestID)
+
+		h.generateSummary(bgCtx, req.CallbackURL, requestID, sessionID)
+	})
+
+	h.respondJSON(w, http.StatusAccepted, map[string]string{
+		"status":  "accepted",
+		"message": "summary is being generated",
+	})
 }
 
-// CancelSession handles POST /interview-session/{id}/cancel - Cancel session
-func (h *Handler) CancelSession(w http.ResponseWriter, r *http.Request) {
+// ApproveGeneration handles POST /admin/interview-session/{id}/approve-generation - Admin approval for large sessions
+func (h *Handler) ApproveGeneration(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	sessionID := chi.URLParam(r, "id")
 
 	ctx = logger.AddFields(ctx,
 		zap.String("session_id", sessionID),
-		zap.String("action", "CancelSession"),
+		zap.String("action", "ApproveGeneration"),
 	)
 
-	ctxzap.Info(ctx, "cancelling session")
-
-	if err := h.usecase.CancelSession(ctx, sessionID); err != nil {
+	estimate, err := h.usecase.ApproveGeneration(ctx, sessionID)
+	if err != nil {
 		h.handleUsecaseError(ctx, w, err)
 		return
 	}
 
-	ctxzap.Info(ctx, "session cancelled successfully")
-	h.respondJSON(w, http.StatusOK, map[string]string{
-		"message": "session cancelled successfully",
-	})
-}
+	ctxzap.Info(ctx, "generation approved by admin",
+		zap.Int("estimated_tokens", estimate.EstimatedTokens),
+	)
 
-// Helper methods
-func (h *Handler) respondJSON(w http.ResponseWriter, status int, data interface{}) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
-	json.NewEncoder(w).Encode(data)
+	h.respondJSON(w, http.StatusOK, estimate)
 }
 
-func (h *Handler) respondError(ctx context.Context, w http.ResponseWriter, status int, message string, err error) {
-	ctxzap.Error(ctx, message, zap.Error(err))
-	h.respondJSON(w, status, entity.ErrorResponse{
-		Error:   http.StatusText(status),
-		Message: message,
-	})
+// AdminSearch handles GET /admin/search - Full-text search across all projects and sessions
+func (h *Handler) AdminSearch(w http.ResponseWriter, r *http.Request) {
+	ctx := logger.WithAction(r.Context(), "AdminSearch")
+
+	skip, _ := strconv.Atoi(r.URL.Query().Get("skip"))
+	limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+	req := entity.AdminSearchRequest{
+		Query: r.URL.Query().Get("q"),
+		Skip:  skip,
+		Limit: limit,
+	}
+
+	req.Normalize()
+
+	ctxzap.Debug(ctx, "admin search",
+		zap.Int("skip", req.Skip),
+		zap.Int("limit", req.Limit),
+	)
+
+	resp, err := h.usecase.AdminSearch(ctx, &req)
+	if err != nil {
+		h.handleUsecaseError(ctx, w, err)
+		return
+	}
+
+	ctxzap.Info(ctx, "admin search completed", zap.Int("total", resp.Total))
+
+	h.respondJSON(w, http.StatusOK, resp)
 }
 
-func (h *Handler) handleUsecaseError(ctx context.Context, w http.ResponseWriter, err error) {
-	if errors.Is(err, entity.ErrSessionNotFound) || errors.Is(err, entity.ErrProjectNotFound) || errors.Is(err, entity.ErrIterationNotFound) {
-		h.respondError(ctx, w, http.StatusNotFound, "resource not found", err)
-	} else if errors.Is(err, entity.ErrInvalidParameter) || errors.Is(err, entity.ErrInvalidFormat) || errors.Is(err, entity.ErrMissingField) {
-		h.respondError(ctx, w, http.StatusBadRequest, "invalid parameter", err)
-	} else if errors.Is(err, entity.ErrSessionNotActive) || errors.Is(err, entity.ErrSessionCancelled) || errors.Is(err, entity.ErrSessionCompleted) || errors.Is(err, entity.ErrInvalidSessionStatus) || errors.Is(err, entity.ErrNoResult) {
-		h.respondError(ctx, w, http.StatusConflict, "invalid session state", err)
-	} else if errors.Is(err, entity.ErrInvalidExtension) || errors.Is(err, entity.ErrFileTooLarge) {
-		h.respondError(ctx, w, http.StatusBadRequest, "invalid file", err)
-	} else {
-		h.respondError(ctx, w, http.StatusInternalServerError, "internal server error", err)
+// GetSessionResult handles GET /interview-session/{id}/result - Get final result
+func (h *Handler) GetSessionResult(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	sessionID := chi.URLParam(r, "id")
+
+	ctx = logger.AddFields(ctx,
+		zap.String("session_id", sessionID),
+		zap.String("action", "GetSessionResult"),
+	)
+
+	format, ok := h.resultFormat(ctx, w, r)
+	if !ok {
+		return
+	}
+
+	language := entity.ResultLanguage(r.URL.Query().Get("lang"))
+	if language != "" && !language.IsValid() {
+		ctxzap.Warn(ctx, "invalid lang parameter", zap.String("lang", string(language)))
+		h.respondError(ctx, w, http.StatusBadRequest, "invalid lang parameter",
+			fmt.Errorf("lang must be one of: ru, en, de, fr, es, zh"))
+		return
+	}
+
+	ctx = logger.AddFields(ctx,
+		zap.String("format", string(format)),
+		zap.String("lang", string(language)),
+	)
+	ctxzap.Debug(ctx, "fetching session result")
+
+	var result string
+	var err error
+	if language != "" {
+		result, err = h.usecase.GetTranslatedSessionResult(ctx, sessionID, language)
+	} else {
+		result, err = h.usecase.GetSessionResult(ctx, sessionID)
+	}
+	if err != nil {
+		h.handleUsecaseError(ctx, w, err)
+		return
+	}
+
+	fileInfo, err := h.usecase.GetResultFileInfo(ctx, sessionID)
+	if err != nil {
+		h.handleUsecaseError(ctx, w, err)
+		return
+	}
+
+	h.writeResultDocument(ctx, w, result, format, language, fileInfo, string(language))
+}
+
+// ListResultVersions handles GET /interview-session/{id}/results - List generated result versions
+func (h *Handler) ListResultVersions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	sessionID := chi.URLParam(r, "id")
+
+	ctx = logger.AddFields(ctx,
+		zap.String("session_id", sessionID),
+		zap.String("action", "ListResultVersions"),
+	)
+
+	ctxzap.Debug(ctx, "listing result versions")
+
+	versions, err := h.usecase.ListResultVersions(ctx, sessionID)
+	if err != nil {
+		h.handleUsecaseError(ctx, w, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, versions)
+}
+
+// GetResultVersion handles GET /interview-session/{id}/results/{version} - Get a generated result version
+func (h *Handler) GetResultVersion(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	sessionID := chi.URLParam(r, "id")
+	versionParam := chi.URLParam(r, "version")
+
+	ctx = logger.AddFields(ctx,
+		zap.String("session_id", sessionID),
+		zap.String("version", versionParam),
+		zap.String("action", "GetResultVersion"),
+	)
+
+	number, err := strconv.Atoi(versionParam)
+	if err != nil || number < 1 {
+		ctxzap.Warn(ctx, "invalid version parameter")
+		h.respondError(ctx, w, http.StatusBadRequest, "invalid version parameter",
+			fmt.Errorf("version must be a positive number"))
+		return
+	}
+
+	format, ok := h.resultFormat(ctx, w, r)
+	if !ok {
+		return
+	}
+
+	ctxzap.Debug(ctx, "fetching result version", zap.String("format", string(format)))
+
+	version, result, err := h.usecase.GetResultVersion(ctx, sessionID, number)
+	if err != nil {
+		h.handleUsecaseError(ctx, w, err)
+		return
+	}
+
+	fileInfo, err := h.usecase.GetResultFileInfo(ctx, sessionID)
+	if err != nil {
+		h.handleUsecaseError(ctx, w, err)
+		return
+	}
+	fileInfo.Date = entity.LocalTime(ctx, version.CreatedAt)
+
+	h.writeResultDocument(ctx, w, result, format, "", fileInfo, fmt.Sprintf("r%d", version.Version))
+}
+
+// resultFormat reads the format query parameter of a result download, falling back to the
+// default of the tenant; it answers 400 itself when the format is unknown
+func (h *Handler) resultFormat(ctx context.Context, w http.ResponseWriter, r *http.Request) (entity.ResultFormat, bool) {
+	formatParam := r.URL.Query().Get("format")
+	if formatParam == "" {
+		// Tenants may choose their own default result template
+		formatParam = "markdown"
+		if tenant := entity.TenantFromContext(ctx); tenant != nil && tenant.Settings.ResultFormat != "" {
+			formatParam = string(tenant.Settings.ResultFormat)
+		}
+	}
+
+	format := entity.ResultFormat(formatParam)
+	if !format.IsValid() {
+		ctxzap.Warn(ctx, "invalid format parameter", zap.String("format", formatParam))
+		h.respondError(ctx, w, http.StatusBadRequest, "invalid format parameter",
+			fmt.Errorf("format must be one of: markdown, json, docx, pdf"))
+		return "", false
+	}
+
+	return format, true
+}
+
+// writeResultDocument formats a result and sends it as a file named after fileInfo and suffix
+func (h *Handler) writeResultDocument(
+	ctx context.Context,
+	w http.ResponseWriter,
+	result string,
+	format entity.ResultFormat,
+	language entity.ResultLanguage,
+	fileInfo *entity.ResultFileInfo,
+	suffix string,
+) {
+	// Create formatter localized for the document language
+	factory := formatter.NewFactory()
+	fmtr, err := factory.Create(format, language, fileInfo.Date, fileInfo.Theme)
+	if err != nil {
+		ctxzap.Error(ctx, "format not implemented", zap.Error(err))
+		h.respondError(ctx, w, http.StatusNotImplemented, "format not implemented", err)
+		return
+	}
+
+	formattedResult, err := fmtr.Format(result)
+	if err != nil {
+		ctxzap.Error(ctx, "failed to format result", zap.Error(err))
+		h.respondError(ctx, w, http.StatusInternalServerError, "failed to format result", err)
+		return
+	}
+
+	ctxzap.Info(ctx, "session result fetched and formatted successfully")
+	w.Header().Set("Content-Type", fmtr.ContentType())
+	filename := formatter.FileName(fileInfo, suffix, fmtr.FileExtension())
+	w.Header().Set("Content-Disposition", formatter.ContentDisposition(filename))
+	w.WriteHeader(http.StatusOK)
+	w.Write(formattedResult)
+}
+
+// GetSessionBundle handles GET /interview-session/{id}/bundle.zip - Download all session artifacts as a ZIP
+func (h *Handler) GetSessionBundle(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	sessionID := chi.URLParam(r, "id")
+
+	ctx = logger.AddFields(ctx,
+		zap.String("session_id", sessionID),
+		zap.String("action", "GetSessionBundle"),
+	)
+
+	bundle, err := h.usecase.GetSessionBundle(ctx, sessionID)
+	if err != nil {
+		h.handleUsecaseError(ctx, w, err)
+		return
+	}
+
+	archive, skipped, err := formatter.BuildBundle(bundle)
+	if err != nil {
+		ctxzap.Error(ctx, "failed to build bundle", zap.Error(err))
+		h.respondError(ctx, w, http.StatusInternalServerError, "failed to build bundle", err)
+		return
+	}
+	if len(skipped) > 0 {
+		ctxzap.Warn(ctx, "bundle built without some artifacts", zap.Strings("skipped", skipped))
+	}
+
+	ctxzap.Info(ctx, "session bundle built", zap.Int("size", len(archive)))
+	w.Header().Set("Content-Type", formatter.BundleContentType)
+	w.Header().Set("Content-Disposition", formatter.ContentDisposition(formatter.BundleFileName(bundle.FileInfo)))
+	w.WriteHeader(http.StatusOK)
+	w.Write(archive)
+}
+
+// ExportQuestionScript handles GET /interview-session/{id}/questions/export - Download the generated questions without answers
+func (h *Handler) ExportQuestionScript(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	sessionID := chi.URLParam(r, "id")
+
+	ctx = logger.AddFields(ctx,
+		zap.String("session_id", sessionID),
+		zap.String("action", "ExportQuestionScript"),
+	)
+
+	formatParam := r.URL.Query().Get("format")
+	if formatParam == "" {
+		formatParam = string(entity.FormatMarkdown)
+	}
+	format := entity.ResultFormat(formatParam)
+	if formatParam != "json" && !format.IsValid() {
+		ctxzap.Warn(ctx, "invalid format parameter", zap.String("format", formatParam))
+		h.respondError(ctx, w, http.StatusBadRequest, "invalid format parameter",
+			fmt.Errorf("format must be one of: markdown, json, docx, pdf"))
+		return
+	}
+
+	script, err := h.usecase.GetQuestionScript(ctx, sessionID)
+	if err != nil {
+		h.handleUsecaseError(ctx, w, err)
+		return
+	}
+
+	// json returns the blocks as they are for analysts' own tooling
+	if formatParam == "json" {
+		h.respondJSON(w, http.StatusOK, script)
+		return
+	}
+
+	fileInfo, err := h.usecase.GetResultFileInfo(ctx, sessionID)
+	if err != nil {
+		h.handleUsecaseError(ctx, w, err)
+		return
+	}
+
+	fmtr, err := formatter.NewQuestionScriptFormatter(format, fileInfo.Date, fileInfo.Theme)
+	if err != nil {
+		h.respondError(ctx, w, http.StatusBadRequest, "unsupported format", err)
+		return
+	}
+
+	data, err := fmtr.Format(formatter.FormatQuestionScript(script))
+	if err != nil {
+		ctxzap.Error(ctx, "failed to format question script", zap.Error(err))
+		h.respondError(ctx, w, http.StatusInternalServerError, "failed to format question script", err)
+		return
+	}
+
+	ctxzap.Info(ctx, "question script exported", zap.String("format", string(format)), zap.Int("size", len(data)))
+	w.Header().Set("Content-Type", fmtr.ContentType())
+	w.Header().Set("Content-Disposition", formatter.ContentDisposition(formatter.QuestionScriptFileName(fileInfo, fmtr.FileExtension())))
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+// ListResultSections handles GET /interview-session/{id}/sections - List sections of a sectioned result
+func (h *Handler) ListResultSections(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	sessionID := chi.URLParam(r, "id")
+
+	ctx = logger.AddFields(ctx,
+		zap.String("session_id", sessionID),
+		zap.String("action", "ListResultSections"),
+	)
+
+	ctxzap.Debug(ctx, "listing result sections")
+
+	sections, err := h.usecase.ListResultSections(ctx, sessionID)
+	if err != nil {
+		h.handleUsecaseError(ctx, w, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, sections)
+}
+
+// RegenerateResultSection handles POST /interview-session/{id}/sections/{index}/regenerate - Regenerate one section with optional guidance
+func (h *Handler) RegenerateResultSection(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	sessionID := chi.URLParam(r, "id")
+	indexParam := chi.URLParam(r, "index")
+
+	requestID := r.Header.Get("X-Request-ID")
+
+	ctx = logger.AddFields(ctx,
+		zap.String("session_id", sessionID),
+		zap.String("section_index", indexParam),
+		zap.String("action", "RegenerateResultSection"),
+	)
+
+	sectionIndex, err := strconv.Atoi(indexParam)
+	if err != nil || sectionIndex < 0 {
+		h.respondError(ctx, w, http.StatusBadRequest, "invalid section index",
+			fmt.Errorf("section index must be a non-negative integer"))
+		return
+	}
+
+	var req entity.RegenerateSectionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		ctxzap.Error(ctx, "failed to decode request body", zap.Error(err))
+		h.respondError(ctx, w, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	if err := h.validator.ValidateAsyncDelivery(requestID, req.CallbackURL); err != nil {
+		ctxzap.Error(ctx, "failed to validate request", zap.Error(err))
+		h.respondError(ctx, w, http.StatusBadRequest, "validation failed", err)
+		return
+	}
+
+	if h.rejectBusySession(ctx, w, sessionID) {
+		return
+	}
+
+	ctxzap.Info(ctx, "regenerating result section")
+
+	h.operations.TrackOperation(ctx, requestID, r.Header.Get("X-Client-ID"), entity.OperationKindRegenerateSection, sessionID)
+
+	h.jobs.Submit(jobqueue.LaneGeneration, jobOwner(r), func() {
+		bgCtx := logger.AddFields(detachedContext(ctx),
+			zap.String("request_id", requestID),
+			zap.String("session_id", sessionID),
+			zap.Int("section_index", sectionIndex),
+			zap.String("action", "RegenerateResultSection-async"),
+		)
+
+		h.operations.MarkProcessing(bgCtx, requestID)
+
+		session, err := h.usecase.RegenerateResultSection(bgCtx, sessionID, sectionIndex, req.Guidance)
+		if err != nil {
+			ctxzap.Error(bgCtx, "failed to regenerate section", zap.Error(err))
+			h.callbackConn.SendError(bgCtx, req.CallbackURL, requestID, "failed to regenerate section", map[string]any{
+				"session_id":    sessionID,
+				"section_index": sectionIndex,
+				"error":         err.Error(),
+			})
+			return
+		}
+
+		h.callbackConn.SendFinalResult(bgCtx, req.CallbackURL, requestID, toSessionDTO(session))
+	})
+
+	h.respondJSON(w, http.StatusAccepted, map[string]string{
+		"status":  "accepted",
+		"message": "section is being regenerated",
+	})
+}
+
+// CreateComment handles POST /interview-session/{id}/comments - Attach a reviewer comment to the result
+func (h *Handler) CreateComment(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	sessionID := chi.URLParam(r, "id")
+
+	ctx = logger.AddFields(ctx,
+		zap.String("session_id", sessionID),
+		zap.String("action", "CreateComment"),
+	)
+
+	var req entity.CreateCommentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		ctxzap.Error(ctx, "failed to decode request body", zap.Error(err))
+		h.respondError(ctx, w, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	if err := h.validator.ValidateCreateComment(&req); err != nil {
+		ctxzap.Error(ctx, "failed to validate request", zap.Error(err))
+		h.respondError(ctx, w, http.StatusBadRequest, "validation failed", err)
+		return
+	}
+
+	ctxzap.Info(ctx, "creating comment")
+
+	comment, err := h.usecase.AddComment(ctx, sessionID, &req)
+	if err != nil {
+		h.handleUsecaseError(ctx, w, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusCreated, comment)
+}
+
+// ListComments handles GET /interview-session/{id}/comments - List reviewer comments
+func (h *Handler) ListComments(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	sessionID := chi.URLParam(r, "id")
+
+	ctx = logger.AddFields(ctx,
+		zap.String("session_id", sessionID),
+		zap.String("action", "ListComments"),
+	)
+
+	status := r.URL.Query().Get("status")
+	if status != "" && status != "all" && status != "unresolved" {
+		ctxzap.Warn(ctx, "invalid status parameter", zap.String("status", status))
+		h.respondError(ctx, w, http.StatusBadRequest, "invalid status parameter",
+			fmt.Errorf("status must be one of: all, unresolved"))
+		return
+	}
+
+	ctxzap.Debug(ctx, "listing comments")
+
+	comments, err := h.usecase.ListComments(ctx, sessionID, status == "unresolved")
+	if err != nil {
+		h.handleUsecaseError(ctx, w, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, comments)
+}
+
+// ResolveComment handles POST /interview-session/{id}/comments/{comment_id}/resolve - Resolve a reviewer comment
+func (h *Handler) ResolveComment(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	sessionID := chi.URLParam(r, "id")
+	commentID := chi.URLParam(r, "comment_id")
+
+	ctx = logger.AddFields(ctx,
+		zap.String("session_id", sessionID),
+		zap.String("comment_id", commentID),
+		zap.String("action", "ResolveComment"),
+	)
+
+	ctxzap.Info(ctx, "resolving comment")
+
+	comment, err := h.usecase.ResolveComment(ctx, sessionID, commentID)
+	if err != nil {
+		h.handleUsecaseError(ctx, w, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, comment)
+}
+
+// ListConflicts handles GET /interview-session/{id}/conflicts - List conflicts with earlier project requirements
+func (h *Handler) ListConflicts(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	sessionID := chi.URLParam(r, "id")
+
+	ctx = logger.AddFields(ctx,
+		zap.String("session_id", sessionID),
+		zap.String("action", "ListConflicts"),
+	)
+
+	ctxzap.Debug(ctx, "listing conflicts")
+
+	conflicts, err := h.usecase.ListConflicts(ctx, sessionID)
+	if err != nil {
+		h.handleUsecaseError(ctx, w, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, conflicts)
+}
+
+// ResolveConflict handles POST /interview-session/{id}/conflicts/{conflict_id}/resolve - Decide a conflict
+func (h *Handler) ResolveConflict(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	sessionID := chi.URLParam(r, "id")
+	conflictID := chi.URLParam(r, "conflict_id")
+
+	ctx = logger.AddFields(ctx,
+		zap.String("session_id", sessionID),
+		zap.String("conflict_id", conflictID),
+		zap.String("action", "ResolveConflict"),
+	)
+
+	var req entity.ResolveConflictRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		ctxzap.Error(ctx, "failed to decode request body", zap.Error(err))
+		h.respondError(ctx, w, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	if err := h.validator.ValidateResolveConflict(&req); err != nil {
+		ctxzap.Error(ctx, "failed to validate request", zap.Error(err))
+		h.respondError(ctx, w, http.StatusBadRequest, "validation failed", err)
+		return
+	}
+
+	ctxzap.Info(ctx, "resolving conflict", zap.String("resolution", string(req.Resolution)))
+
+	conflict, err := h.usecase.ResolveConflict(ctx, sessionID, conflictID, req.Resolution)
+	if err != nil {
+		h.handleUsecaseError(ctx, w, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, conflict)
+}
+
+// RefineResult handles POST /interview-session/{id}/refine - Revise the result to address unresolved comments
+func (h *Handler) RefineResult(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	sessionID := chi.URLParam(r, "id")
+
+	requestID := r.Header.Get("X-Request-ID")
+
+	ctx = logger.AddFields(ctx,
+		zap.String("session_id", sessionID),
+		zap.String("action", "RefineResult"),
+	)
+
+	var req entity.RefineResultRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		ctxzap.Error(ctx, "failed to decode request body", zap.Error(err))
+		h.respondError(ctx, w, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	if err := h.validator.ValidateAsyncDelivery(requestID, req.CallbackURL); err != nil {
+		ctxzap.Error(ctx, "failed to validate request", zap.Error(err))
+		h.respondError(ctx, w, http.StatusBadRequest, "validation failed", err)
+		return
+	}
+
+	if h.rejectBusySession(ctx, w, sessionID) {
+		return
+	}
+
+	ctxzap.Info(ctx, "refining result by comments")
+
+	h.operations.TrackOperation(ctx, requestID, r.Header.Get("X-Client-ID"), entity.OperationKindRefineResult, sessionID)
+
+	h.jobs.Submit(jobqueue.LaneGeneration, jobOwner(r), func() {
+		bgCtx := logger.AddFields(detachedContext(ctx),
+			zap.String("request_id", requestID),
+			zap.String("session_id", sessionID),
+			zap.String("action", "RefineResult-async"),
+		)
+
+		h.operations.MarkProcessing(bgCtx, requestID)
+
+		session, err := h.usecase.RefineResult(bgCtx, sessionID)
+		if err != nil {
+			ctxzap.Error(bgCtx, "failed to refine result", zap.Error(err))
+			h.callbackConn.SendError(bgCtx, req.CallbackURL, requestID, "failed to refine result", map[string]any{
+				"session_id": sessionID,
+				"error":      err.Error(),
+			})
+			return
+		}
+
+		h.callbackConn.SendFinalResult(bgCtx, req.CallbackURL, requestID, toSessionDTO(session))
+	})
+
+	h.respondJSON(w, http.StatusAccepted, map[string]string{
+		"status":  "accepted",
+		"message": "result is being refined",
+	})
+}
+
+// RegenerateWithFeedback handles POST /interview-session/{id}/feedback - Revise the result following free-text feedback
+func (h *Handler) RegenerateWithFeedback(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	sessionID := chi.URLParam(r, "id")
+
+	requestID := r.Header.Get("X-Request-ID")
+
+	ctx = logger.AddFields(ctx,
+		zap.String("session_id", sessionID),
+		zap.String("action", "RegenerateWithFeedback"),
+	)
+
+	var req entity.ResultFeedbackRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		ctxzap.Error(ctx, "failed to decode request body", zap.Error(err))
+		h.respondError(ctx, w, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	if err := h.validator.ValidateResultFeedback(&req); err != nil {
+		ctxzap.Error(ctx, "failed to validate request", zap.Error(err))
+		h.respondError(ctx, w, http.StatusBadRequest, "validation failed", err)
+		return
+	}
+
+	if err := h.validator.ValidateAsyncDelivery(requestID, req.CallbackURL); err != nil {
+		ctxzap.Error(ctx, "failed to validate request", zap.Error(err))
+		h.respondError(ctx, w, http.StatusBadRequest, "validation failed", err)
+		return
+	}
+
+	if h.rejectBusySession(ctx, w, sessionID) {
+		return
+	}
+
+	ctxzap.Info(ctx, "regenerating result with feedback")
+
+	h.operations.TrackOperation(ctx, requestID, r.Header.Get("X-Client-ID"), entity.OperationKindResultFeedback, sessionID)
+
+	h.jobs.Submit(jobqueue.LaneGeneration, jobOwner(r), func() {
+		bgCtx := logger.AddFields(detachedContext(ctx),
+			zap.String("request_id", requestID),
+			zap.String("session_id", sessionID),
+			zap.String("action", "RegenerateWithFeedback-async"),
+		)
+
+		h.operations.MarkProcessing(bgCtx, requestID)
+
+		session, err := h.usecase.RegenerateSummaryWithFeedback(bgCtx, sessionID, req.Feedback)
+		if err != nil {
+			ctxzap.Error(bgCtx, "failed to regenerate result with feedback", zap.Error(err))
+			h.callbackConn.SendError(bgCtx, req.CallbackURL, requestID, "failed to regenerate result with feedback", map[string]any{
+				"session_id": sessionID,
+				"error":      err.Error(),
+			})
+			return
+		}
+
+		h.callbackConn.SendFinalResult(bgCtx, req.CallbackURL, requestID, toSessionDTO(session))
+	})
+
+	h.respondJSON(w, http.StatusAccepted, map[string]string{
+		"status":  "accepted",
+		"message": "result is being regenerated with feedback",
+	})
+}
+
+// SearchSessionContent handles GET /interview-session/{id}/search - Full-text search over collected answers and drafts
+func (h *Handler) SearchSessionContent(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	sessionID := chi.URLParam(r, "id")
+
+	ctx = logger.AddFields(ctx,
+		zap.String("session_id", sessionID),
+		zap.String("action", "SearchSessionContent"),
+	)
+
+	ctxzap.Debug(ctx, "searching session content")
+
+	hits, err := h.usecase.SearchSessionContent(ctx, sessionID, r.URL.Query().Get("q"))
+	if err != nil {
+		h.handleUsecaseError(ctx, w, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, hits)
+}
+
+// GetChangeLog handles GET /interview-session/{id}/changelog - Get changes made by a delta session
+func (h *Handler) GetChangeLog(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	sessionID := chi.URLParam(r, "id")
+
+	ctx = logger.AddFields(ctx,
+		zap.String("session_id", sessionID),
+		zap.String("action", "GetChangeLog"),
+	)
+
+	ctxzap.Debug(ctx, "fetching change log")
+
+	delta, err := h.usecase.GetChangeLog(ctx, sessionID)
+	if err != nil {
+		h.handleUsecaseError(ctx, w, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, delta)
+}
+
+// DiffResults handles GET /requirements/diff - Compare the requirements of two sessions section by section
+func (h *Handler) DiffResults(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	baseSessionID := r.URL.Query().Get("base")
+	compareSessionID := r.URL.Query().Get("compare")
+
+	ctx = logger.AddFields(ctx,
+		zap.String("base_session_id", baseSessionID),
+		zap.String("compare_session_id", compareSessionID),
+		zap.String("action", "DiffResults"),
+	)
+
+	ctxzap.Debug(ctx, "comparing results")
+
+	diff, err := h.usecase.DiffResults(ctx, baseSessionID, compareSessionID)
+	if err != nil {
+		h.handleUsecaseError(ctx, w, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, diff)
+}
+
+// GetReview handles GET /interview-session/{id}/review - Get result approval state
+func (h *Handler) GetReview(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	sessionID := chi.URLParam(r, "id")
+
+	ctx = logger.AddFields(ctx,
+		zap.String("session_id", sessionID),
+		zap.String("action", "GetReview"),
+	)
+
+	ctxzap.Debug(ctx, "fetching review")
+
+	review, err := h.usecase.GetReview(ctx, sessionID)
+	if err != nil {
+		h.handleUsecaseError(ctx, w, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, review)
+}
+
+// SubmitForReview handles POST /interview-session/{id}/review/submit - Send result to approvers
+func (h *Handler) SubmitForReview(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	sessionID := chi.URLParam(r, "id")
+
+	requestID := r.Header.Get("X-Request-ID")
+
+	ctx = logger.AddFields(ctx,
+		zap.String("session_id", sessionID),
+		zap.String("action", "SubmitForReview"),
+	)
+
+	var req entity.SubmitReviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		ctxzap.Error(ctx, "failed to decode request body", zap.Error(err))
+		h.respondError(ctx, w, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	if err := h.validator.ValidateSubmitReview(&req); err != nil {
+		ctxzap.Error(ctx, "failed to validate request", zap.Error(err))
+		h.respondError(ctx, w, http.StatusBadRequest, "validation failed", err)
+		return
+	}
+
+	ctxzap.Info(ctx, "submitting result for review", zap.Int("approvers", len(req.Approvers)))
+
+	review, err := h.usecase.SubmitForReview(ctx, sessionID, req.Approvers)
+	if err != nil {
+		h.handleUsecaseError(ctx, w, err)
+		return
+	}
+
+	// Telegram approvers are notified by the bot, API approvers via the client callback
+	if req.CallbackURL != "" {
+		go func() {
+			bgCtx := logger.AddFields(detachedContext(ctx),
+				zap.String("request_id", requestID),
+				zap.String("session_id", sessionID),
+				zap.String("action", "SubmitForReview-async"),
+			)
+
+			h.callbackConn.SendReviewRequested(bgCtx, req.CallbackURL, requestID, review)
+		}()
+	}
+
+	h.respondJSON(w, http.StatusOK, review)
+}
+
+// DecideReview handles POST /interview-session/{id}/review/decision - Approve or reject the result
+func (h *Handler) DecideReview(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	sessionID := chi.URLParam(r, "id")
+
+	ctx = logger.AddFields(ctx,
+		zap.String("session_id", sessionID),
+		zap.String("action", "DecideReview"),
+	)
+
+	var req entity.ReviewDecisionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		ctxzap.Error(ctx, "failed to decode request body", zap.Error(err))
+		h.respondError(ctx, w, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	if err := h.validator.ValidateReviewDecision(&req); err != nil {
+		ctxzap.Error(ctx, "failed to validate request", zap.Error(err))
+		h.respondError(ctx, w, http.StatusBadRequest, "validation failed", err)
+		return
+	}
+
+	ctxzap.Info(ctx, "deciding review", zap.String("decision", req.Decision))
+
+	review, err := h.usecase.DecideReview(ctx, sessionID, req.Approver, req.Decision == entity.ReviewDecisionApprove, req.Comment)
+	if err != nil {
+		h.handleUsecaseError(ctx, w, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, review)
+}
+
+// CancelSession handles POST /interview-session/{id}/cancel - Cancel session
+func (h *Handler) CancelSession(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	sessionID := chi.URLParam(r, "id")
+
+	ctx = logger.AddFields(ctx,
+		zap.String("session_id", sessionID),
+		zap.String("action", "CancelSession"),
+	)
+
+	ctxzap.Info(ctx, "cancelling session")
+
+	if err := h.usecase.CancelSession(ctx, sessionID); err != nil {
+		h.handleUsecaseError(ctx, w, err)
+		return
+	}
+
+	ctxzap.Info(ctx, "session cancelled successfully")
+	h.respondJSON(w, http.StatusOK, map[string]string{
+		"message": "session cancelled successfully",
+	})
+}
+
+// Heartbeat handles POST /interview-session/{id}/heartbeat - Keep a session driven by an external orchestrator alive
+func (h *Handler) Heartbeat(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	sessionID := chi.URLParam(r, "id")
+
+	ctx = logger.AddFields(ctx,
+		zap.String("session_id", sessionID),
+		zap.String("action", "Heartbeat"),
+	)
+
+	session, err := h.usecase.Heartbeat(ctx, sessionID)
+	if err != nil {
+		h.handleUsecaseError(ctx, w, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, toSessionDTO(session))
+}
+
+// ExplainQuestion handles GET /interview-session/{id}/questions/{question_id}/explanation - Explains why a question is asked
+func (h *Handler) ExplainQuestion(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	sessionID := chi.URLParam(r, "id")
+	questionID := chi.URLParam(r, "question_id")
+
+	ctx = logger.AddFields(ctx,
+		zap.String("session_id", sessionID),
+		zap.String("question_id", questionID),
+		zap.String("action", "ExplainQuestion"),
+	)
+
+	explanation, err := h.usecase.ExplainQuestion(ctx, sessionID, questionID)
+	if err != nil {
+		h.handleUsecaseError(ctx, w, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, explanation)
+}
+
+// SetSessionLanguage handles POST /interview-session/{id}/language - Sets the document language explicitly
+func (h *Handler) SetSessionLanguage(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	sessionID := chi.URLParam(r, "id")
+
+	ctx = logger.AddFields(ctx,
+		zap.String("session_id", sessionID),
+		zap.String("action", "SetSessionLanguage"),
+	)
+
+	var req entity.SetSessionLanguageRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		ctxzap.Error(ctx, "failed to decode request body", zap.Error(err))
+		h.respondError(ctx, w, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	session, err := h.usecase.SetSessionLanguage(ctx, sessionID, req.Language)
+	if err != nil {
+		h.handleUsecaseError(ctx, w, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, toSessionDTO(session))
+}
+
+// SetSummaryStyle handles POST /interview-session/{id}/style - Sets the document style, rewriting a generated result
+func (h *Handler) SetSummaryStyle(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	sessionID := chi.URLParam(r, "id")
+
+	ctx = logger.AddFields(ctx,
+		zap.String("session_id", sessionID),
+		zap.String("action", "SetSummaryStyle"),
+	)
+
+	var req entity.SetSummaryStyleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		ctxzap.Error(ctx, "failed to decode request body", zap.Error(err))
+		h.respondError(ctx, w, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	session, err := h.usecase.SetSummaryStyle(ctx, sessionID, req.Style)
+	if err != nil {
+		h.handleUsecaseError(ctx, w, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, toSessionDTO(session))
+}
+
+// GetSessionFeatures handles GET /interview-session/{id}/features - Feature flags of the session
+func (h *Handler) GetSessionFeatures(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	sessionID := chi.URLParam(r, "id")
+
+	ctx = logger.AddFields(ctx,
+		zap.String("session_id", sessionID),
+		zap.String("action", "GetSessionFeatures"),
+	)
+
+	features, err := h.usecase.GetSessionFeatures(ctx, sessionID)
+	if err != nil {
+		h.handleUsecaseError(ctx, w, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, map[string]any{"features": features})
+}
+
+// ListPendingQuestions handles GET /interview-session/{id}/pending-questions - Questions blocks
+// whose callback was not delivered
+func (h *Handler) ListPendingQuestions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	sessionID := chi.URLParam(r, "id")
+
+	ctx = logger.AddFields(ctx,
+		zap.String("session_id", sessionID),
+		zap.String("action", "ListPendingQuestions"),
+	)
+
+	pending, err := h.usecase.ListPendingQuestions(ctx, sessionID)
+	if err != nil {
+		h.handleUsecaseError(ctx, w, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, map[string]any{"pending_questions": pending})
+}
+
+// AcknowledgePendingQuestions handles POST /interview-session/{id}/pending-questions/{iteration_id}/ack -
+// Confirm a pulled questions block was received
+func (h *Handler) AcknowledgePendingQuestions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	sessionID := chi.URLParam(r, "id")
+	iterationID := chi.URLParam(r, "iteration_id")
+
+	ctx = logger.AddFields(ctx,
+		zap.String("session_id", sessionID),
+		zap.String("iteration_id", iterationID),
+		zap.String("action", "AcknowledgePendingQuestions"),
+	)
+
+	ack, err := h.usecase.AcknowledgePendingQuestions(ctx, sessionID, iterationID)
+	if err != nil {
+		h.handleUsecaseError(ctx, w, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, ack)
+}
+
+// Helper methods
+
+// sendQuestionEvent reports the answered or skipped question to sessions with question callback granularity
+func (h *Handler) sendQuestionEvent(
+	ctx context.Context, callbackURL, requestID string, data *entity.CallbackQuestionEventData, skipped bool,
+) {
+	event := entity.CallbackEventTypeQuestionAnswered
+	data.Status = entity.AnswerStatusAnswered
+	if skipped {
+		event = entity.CallbackEventTypeQuestionSkipped
+		data.Status = entity.AnswerStatusSkiped
+		data.Answer = ""
+	}
+
+	h.callbackConn.SendQuestionEvent(ctx, callbackURL, requestID, event, data)
+}
+
+// estimateOrGenerate sends an estimate event instead of generating when the session needs confirmation or approval
+func (h *Handler) estimateOrGenerate(ctx context.Context, callbackURL, requestID, sessionID string) {
+	estimate, err := h.usecase.EstimateGeneration(ctx, sessionID)
+	if err != nil {
+		ctxzap.Error(ctx, "failed to estimate generation", zap.Error(err))
+		h.callbackConn.SendError(ctx, callbackURL, requestID, "failed to estimate generation", map[string]any{
+			"session_id": sessionID,
+			"error":      err.Error(),
+		})
+		return
+	}
+
+	if estimate.RequiresConfirmation || estimate.AwaitingApproval() {
+		ctxzap.Info(ctx, "generation awaits confirmation",
+			zap.Int("estimated_tokens", estimate.EstimatedTokens),
+			zap.Bool("awaiting_approval", estimate.AwaitingApproval()),
+		)
+		h.callbackConn.SendEstimate(ctx, callbackURL, requestID, estimate)
+		return
+	}
+
+	h.generateSummary(ctx, callbackURL, requestID, sessionID)
+}
+
+func (h *Handler) generateSummary(ctx context.Context, callbackURL, requestID, sessionID string) {
+	session, err := h.usecase.GenerateSummary(ctx, sessionID)
+	if err != nil {
+		ctxzap.Error(ctx, "failed to generate summary", zap.Error(err))
+		h.callbackConn.SendError(ctx, callbackURL, requestID, "failed to generate summary", map[string]any{
+			"session_id": sessionID,
+			"error":      err.Error(),
+		})
+		return
+	}
+
+	h.callbackConn.SendFinalResult(ctx, callbackURL, requestID, toSessionDTO(session))
+}
+
+func (h *Handler) respondJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
+
+func (h *Handler) respondError(ctx context.Context, w http.ResponseWriter, status int, message string, err error) {
+	ctxzap.Error(ctx, message, zap.Error(err))
+	h.respondJSON(w, status, entity.ErrorResponse{
+		Error:   http.StatusText(status),
+		Message: message,
+	})
+}
+
+// rejectBusySession answers 409 while a heavy operation runs on the session, so that an async
+// request is refused right away instead of failing in its callback; the usecase checks again
+func (h *Handler) rejectBusySession(ctx context.Context, w http.ResponseWriter, sessionID string) bool {
+	op, err := h.usecase.GetActiveOperation(ctx, sessionID)
+	if err != nil {
+		if !errors.Is(err, entity.ErrNoOperation) {
+			ctxzap.Warn(ctx, "failed to check session operation", zap.Error(err))
+		}
+		return false
+	}
+
+	h.handleUsecaseError(ctx, w, op.InProgressError())
+	return true
+}
+
+func (h *Handler) handleUsecaseError(ctx context.Context, w http.ResponseWriter, err error) {
+	if errors.Is(err, entity.ErrSessionNotFound) || errors.Is(err, entity.ErrProjectNotFound) || errors.Is(err, entity.ErrIterationNotFound) || errors.Is(err, entity.ErrSectionNotFound) || errors.Is(err, entity.ErrCommentNotFound) || errors.Is(err, entity.ErrConflictNotFound) || errors.Is(err, entity.ErrPendingQuestionsNotFound) || errors.Is(err, entity.ErrQuestionNotFound) || errors.Is(err, entity.ErrResultVersionNotFound) {
+		h.respondError(ctx, w, http.StatusNotFound, "resource not found", err)
+	} else if errors.Is(err, entity.ErrResultVersionPurged) {
+		h.respondError(ctx, w, http.StatusGone, "result version is no longer stored", err)
+	} else if errors.Is(err, entity.ErrInvalidParameter) || errors.Is(err, entity.ErrInvalidFormat) || errors.Is(err, entity.ErrMissingField) {
+		h.respondError(ctx, w, http.StatusBadRequest, "invalid parameter", err)
+	} else if errors.Is(err, entity.ErrSessionNotActive) || errors.Is(err, entity.ErrSessionCancelled) || errors.Is(err, entity.ErrSessionCompleted) || errors.Is(err, entity.ErrInvalidSessionStatus) || errors.Is(err, entity.ErrQuestionsNotReady) || errors.Is(err, entity.ErrNoResult) || errors.Is(err, entity.ErrNoBaseline) || errors.Is(err, entity.ErrNoChangeLog) || errors.Is(err, entity.ErrUnresolvedConflicts) || errors.Is(err, entity.ErrNoOpenComments) || errors.Is(err, entity.ErrInvalidReviewTransition) {
+		h.respondError(ctx, w, http.StatusConflict, "invalid session state", err)
+	} else if errors.Is(err, entity.ErrInvalidExtension) || errors.Is(err, entity.ErrFileTooLarge) {
+		h.respondError(ctx, w, http.StatusBadRequest, "invalid file", err)
+	} else if errors.Is(err, entity.ErrAdminApprovalRequired) {
+		h.respondError(ctx, w, http.StatusForbidden, "generation requires admin approval", err)
+	} else if errors.Is(err, entity.ErrResultNotApproved) {
+		h.respondError(ctx, w, http.StatusForbidden, "result requires approval", err)
+	} else if errors.Is(err, entity.ErrNotApprover) {
+		h.respondError(ctx, w, http.StatusForbidden, "not an assigned approver", err)
+	} else if errors.Is(err, entity.ErrDemoSession) {
+		h.respondError(ctx, w, http.StatusForbidden, "unavailable in demo session", err)
+	} else if errors.Is(err, entity.ErrContentBlocked) {
+		h.respondError(ctx, w, http.StatusUnprocessableEntity, "content rejected by moderation", err)
+	} else if errors.Is(err, entity.ErrSessionBusy) {
+		w.Header().Set("Retry-After", "5")
+		h.respondError(ctx, w, http.StatusConflict, "session is busy with another action", err)
+	} else if errors.Is(err, entity.ErrOperationInProgress) {
+		h.respondError(ctx, w, http.StatusConflict, "another operation is in progress on the session", err)
+	} else if errors.Is(err, entity.ErrHeartbeatTooFrequent) {
+		h.respondError(ctx, w, http.StatusTooManyRequests, "heartbeat too frequent", err)
+	} else if errors.Is(err, entity.ErrExplanationCooldown) {
+		h.respondError(ctx, w, http.StatusTooManyRequests, "explanations requested too often", err)
+	} else if errors.Is(err, entity.ErrQuotaExceeded) {
+		h.respondError(ctx, w, http.StatusTooManyRequests, "usage quota exceeded", err)
+	} else if errors.Is(err, entity.ErrLLMOverloaded) {
+		w.Header().Set("Retry-After", "30")
+		h.respondError(ctx, w, http.StatusServiceUnavailable, "llm service is overloaded", err)
+	} else if errors.Is(err, entity.ErrASRUnavailable) {
+		w.Header().Set("Retry-After", "30")
+		h.respondError(ctx, w, http.StatusServiceUnavailable, "speech recognition is unavailable", err)
+	} else {
+		h.respondError(ctx, w, http.StatusInternalServerError, "internal server error", err)
+	}
+}
+
+// jobOwner is the tenant and the client async jobs of the request are fair-scheduled for
+func jobOwner(r *http.Request) string {
+	return jobqueue.Owner(entity.TenantIDFromContext(r.Context()), r.Header.Get("X-Client-ID"))
+}
+
+// detachedContext outlives the request for async work: it keeps the tenant, the user, the usage subject, the logger
+// and the negotiated callback schema version of the request but not its cancellation
+func detachedContext(ctx context.Context) context.Context {
+	bgCtx := entity.WithUser(entity.WithTenant(context.Background(), entity.TenantFromContext(ctx)), entity.UserFromContext(ctx))
+	bgCtx = entity.WithUsageSubject(bgCtx, entity.UsageSubjectFromContext(ctx))
+	if version := entity.CallbackSchemaVersionFromContext(ctx); version != 0 {
+		bgCtx = entity.WithCallbackSchemaVersion(bgCtx, version)
 	}
+	return ctxzap.ToContext(bgCtx, ctxzap.Extract(ctx))
 }
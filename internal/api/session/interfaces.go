@@ -5,23 +5,93 @@ import (
 	"mime/multipart"
 
 	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/pkg/jobqueue"
 )
 
 type SessionUsecase interface {
 	StartHTTPSession(ctx context.Context, req *entity.StartSessionRequest) (*entity.IterationWithQuestions, error)
+	StartTranscriptSession(ctx context.Context, req *entity.TranscriptSessionRequest) (*entity.Session, error)
 	LoadSessionQuestions(ctx context.Context, sessionID string) ([]*entity.IterationWithQuestions, error)
+	GetCurrentQuestions(ctx context.Context, sessionID string) (*entity.IterationWithQuestions, error)
 	SkipAnswer(ctx context.Context, sessionID, questionID string) (*entity.IterationWithQuestions, error)
+	SetSkipReason(ctx context.Context, sessionID, questionID string, reason entity.SkipReason) error
 	SubmitTextAnswer(ctx context.Context, sessionID, questionID, answer string) (*entity.IterationWithQuestions, error)
 	SubmitHTTPAudioAnswer(ctx context.Context, sessionID, questionID string, audioFile *multipart.FileHeader) (*entity.IterationWithQuestions, error)
 	ValidateAnswers(ctx context.Context, sessionID string) (*entity.IterationWithQuestions, error)
 	GenerateSummary(ctx context.Context, sessionID string) (*entity.Session, error)
+	GenerateSummaryStream(ctx context.Context, sessionID string, onProgress func(partial string)) (*entity.Session, error)
+	EstimateGeneration(ctx context.Context, sessionID string) (*entity.GenerationEstimate, error)
+	ApproveGeneration(ctx context.Context, sessionID string) (*entity.GenerationEstimate, error)
+	AdminSearch(ctx context.Context, req *entity.AdminSearchRequest) (*entity.AdminSearchResponse, error)
+	ListResultSections(ctx context.Context, sessionID string) ([]*entity.ResultSection, error)
+	RegenerateResultSection(ctx context.Context, sessionID string, sectionIndex int, guidance string) (*entity.Session, error)
+	AddComment(ctx context.Context, sessionID string, req *entity.CreateCommentRequest) (*entity.SessionComment, error)
+	ListComments(ctx context.Context, sessionID string, unresolvedOnly bool) ([]*entity.SessionComment, error)
+	ResolveComment(ctx context.Context, sessionID, commentID string) (*entity.SessionComment, error)
+	ListConflicts(ctx context.Context, sessionID string) ([]*entity.RequirementConflict, error)
+	ResolveConflict(ctx context.Context, sessionID, conflictID string, resolution entity.ConflictResolution) (*entity.RequirementConflict, error)
+	RefineResult(ctx context.Context, sessionID string) (*entity.Session, error)
+	RegenerateSummaryWithFeedback(ctx context.Context, sessionID, feedback string) (*entity.Session, error)
+	GetActiveOperation(ctx context.Context, sessionID string) (*entity.SessionOperation, error)
+	SearchSessionContent(ctx context.Context, sessionID, query string) ([]*entity.SessionSearchHit, error)
+	GetChangeLog(ctx context.Context, sessionID string) (*entity.SessionDelta, error)
+	DiffResults(ctx context.Context, baseSessionID, compareSessionID string) (*entity.ResultDiff, error)
+	GetReview(ctx context.Context, sessionID string) (*entity.ResultReview, error)
+	SubmitForReview(ctx context.Context, sessionID string, approvers []entity.ResultApprover) (*entity.ResultReview, error)
+	DecideReview(ctx context.Context, sessionID string, approver entity.ResultApprover, approve bool, comment string) (*entity.ResultReview, error)
 	GetSession(ctx context.Context, sessionID string) (*entity.Session, error)
 	GetSessionResult(ctx context.Context, sessionID string) (string, error)
+	GetResultFileInfo(ctx context.Context, sessionID string) (*entity.ResultFileInfo, error)
+	ListResultVersions(ctx context.Context, sessionID string) (*entity.ResultVersionsResponse, error)
+	GetResultVersion(ctx context.Context, sessionID string, number int) (*entity.ResultVersion, string, error)
+	GetSessionBundle(ctx context.Context, sessionID string) (*entity.SessionBundle, error)
+	GetQuestionScript(ctx context.Context, sessionID string) ([]*entity.BundleIteration, error)
+	GetTranslatedSessionResult(ctx context.Context, sessionID string, language entity.ResultLanguage) (string, error)
 	CancelSession(ctx context.Context, sessionID string) error
+	Heartbeat(ctx context.Context, sessionID string) (*entity.Session, error)
+	ExplainQuestion(ctx context.Context, sessionID, questionID string) (*entity.QuestionExplanation, error)
+	SetSessionLanguage(ctx context.Context, sessionID string, language entity.ResultLanguage) (*entity.Session, error)
+	SetSummaryStyle(ctx context.Context, sessionID string, style entity.SummaryStyle) (*entity.Session, error)
+	GetSessionFeatures(ctx context.Context, sessionID string) (map[entity.FeatureFlag]bool, error)
+	CallbackGranularity(ctx context.Context, sessionID string) entity.CallbackGranularity
+	SavePendingQuestions(ctx context.Context, sessionID, requestID string, data *entity.IterationWithQuestions, deliveryErr error)
+	ListPendingQuestions(ctx context.Context, sessionID string) ([]*entity.PendingQuestions, error)
+	AcknowledgePendingQuestions(ctx context.Context, sessionID, iterationID string) (*entity.PendingQuestionsAck, error)
+}
+
+// CallbackGranularityResolver tells which callback events a session emits to its consumer
+type CallbackGranularityResolver interface {
+	CallbackGranularity(ctx context.Context, sessionID string) entity.CallbackGranularity
+}
+
+// PendingQuestionsStore keeps questions blocks whose callback was not delivered for the consumer to pull
+type PendingQuestionsStore interface {
+	SavePendingQuestions(ctx context.Context, sessionID, requestID string, data *entity.IterationWithQuestions, deliveryErr error)
+}
+
+// OperationTracker records async workflows for clients polling by request ID; tracking is best-effort
+type OperationTracker interface {
+	TrackOperation(ctx context.Context, requestID, clientID string, kind entity.OperationKind, sessionID string)
+	MarkProcessing(ctx context.Context, requestID string)
+	CompleteOperation(ctx context.Context, requestID string, event entity.CallbackEventType, data any)
+}
+
+// SessionStreams delivers the progress events of sessions to their WebSocket streams
+type SessionStreams interface {
+	Subscribe(sessionID string) (<-chan entity.StreamEvent, func())
+	Publish(event entity.StreamEvent)
 }
 
 type CallbackConnector interface {
 	SendError(ctx context.Context, callbackURL string, requestID string, message string, details map[string]any)
-	SendQuestions(ctx context.Context, callbackURL string, requestID string, data *entity.IterationWithQuestions)
+	SendQuestions(ctx context.Context, callbackURL string, requestID string, data *entity.IterationWithQuestions) error
+	SendQuestionEvent(ctx context.Context, callbackURL string, requestID string, event entity.CallbackEventType, data *entity.CallbackQuestionEventData)
 	SendFinalResult(ctx context.Context, callbackURL string, requestID string, data *entity.SessionDTO)
+	SendEstimate(ctx context.Context, callbackURL string, requestID string, data *entity.GenerationEstimate)
+	SendReviewRequested(ctx context.Context, callbackURL string, requestID string, data *entity.ResultReview)
+}
+
+// JobQueue runs async workflows in priority lanes, taking turns between tenants and clients
+type JobQueue interface {
+	Submit(lane jobqueue.Lane, owner string, run func())
 }
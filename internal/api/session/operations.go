@@ -0,0 +1,110 @@
+package session
+
+import (
+	"context"
+
+	"github.com/futig/agent-backend/internal/entity"
+)
+
+// trackingCallbackConnector stores every workflow callback event as the result of the request's
+// operation and delivers it only when the client gave a callback URL and the callback granularity
+// of the session includes the event. Questions the callback URL did not accept are kept for the
+// client to pull. Every event is also pushed to the progress streams of the session
+type trackingCallbackConnector struct {
+	next        CallbackConnector
+	operations  OperationTracker
+	pending     PendingQuestionsStore
+	granularity CallbackGranularityResolver
+	streams     SessionStreams
+}
+
+var _ CallbackConnector = &trackingCallbackConnector{}
+
+func (c *trackingCallbackConnector) SendError(
+	ctx context.Context, callbackURL string, requestID string, message string, details map[string]any,
+) {
+	data := &entity.CallbackErrorData{
+		Error: entity.CallbackErrorDetails{
+			Message: message,
+			Details: details,
+		},
+	}
+	c.operations.CompleteOperation(ctx, requestID, entity.CallbackEventTypeError, data)
+	if sessionID, ok := details["session_id"].(string); ok {
+		c.publish(sessionID, entity.CallbackEventTypeError, data)
+	}
+	if callbackURL != "" {
+		c.next.SendError(ctx, callbackURL, requestID, message, details)
+	}
+}
+
+func (c *trackingCallbackConnector) SendQuestions(
+	ctx context.Context, callbackURL string, requestID string, data *entity.IterationWithQuestions,
+) error {
+	c.operations.CompleteOperation(ctx, requestID, entity.CallbackEventTypeQuestions, data)
+	if data != nil {
+		c.publish(data.SessionID, entity.CallbackEventTypeQuestions, data)
+	}
+	if callbackURL == "" || data == nil || !c.emits(ctx, data.SessionID, entity.CallbackEventTypeQuestions) {
+		return nil
+	}
+
+	err := c.next.SendQuestions(ctx, callbackURL, requestID, data)
+	if err != nil {
+		c.pending.SavePendingQuestions(ctx, data.SessionID, requestID, data, err)
+	}
+
+	return err
+}
+
+func (c *trackingCallbackConnector) SendFinalResult(
+	ctx context.Context, callbackURL string, requestID string, data *entity.SessionDTO,
+) {
+	c.operations.CompleteOperation(ctx, requestID, entity.CallbackEventTypeFinalResult, data)
+	c.publish(data.ID, entity.CallbackEventTypeFinalResult, data)
+	if callbackURL != "" {
+		c.next.SendFinalResult(ctx, callbackURL, requestID, data)
+	}
+}
+
+func (c *trackingCallbackConnector) SendEstimate(
+	ctx context.Context, callbackURL string, requestID string, data *entity.GenerationEstimate,
+) {
+	c.operations.CompleteOperation(ctx, requestID, entity.CallbackEventTypeEstimate, data)
+	c.publish(data.SessionID, entity.CallbackEventTypeEstimate, data)
+	if callbackURL != "" && c.emits(ctx, data.SessionID, entity.CallbackEventTypeEstimate) {
+		c.next.SendEstimate(ctx, callbackURL, requestID, data)
+	}
+}
+
+// SendQuestionEvent reports a single answered or skipped question; the operation is completed by
+// the event that follows it
+func (c *trackingCallbackConnector) SendQuestionEvent(
+	ctx context.Context, callbackURL string, requestID string, event entity.CallbackEventType, data *entity.CallbackQuestionEventData,
+) {
+	c.publish(data.SessionID, event, data)
+	if callbackURL != "" && c.emits(ctx, data.SessionID, event) {
+		c.next.SendQuestionEvent(ctx, callbackURL, requestID, event, data)
+	}
+}
+
+// SendReviewRequested notifies API approvers; review submission is synchronous, so there is no operation to complete
+func (c *trackingCallbackConnector) SendReviewRequested(
+	ctx context.Context, callbackURL string, requestID string, data *entity.ResultReview,
+) {
+	c.next.SendReviewRequested(ctx, callbackURL, requestID, data)
+}
+
+// publish pushes a workflow event to the progress streams of the session
+func (c *trackingCallbackConnector) publish(sessionID string, event entity.CallbackEventType, data any) {
+	c.streams.Publish(entity.StreamEvent{
+		Event:     event,
+		SessionID: sessionID,
+		Data:      data,
+	})
+}
+
+// emits reports whether the callback granularity of the session includes the event
+func (c *trackingCallbackConnector) emits(ctx context.Context, sessionID string, event entity.CallbackEventType) bool {
+	return c.granularity.CallbackGranularity(ctx, sessionID).Emits(event)
+}
@@ -8,10 +8,50 @@ import (
 func RegisterRoutes(r chi.Router, h *Handler) {
 	r.Route("/interview-session", func(r chi.Router) {
 		r.Post("/", h.StartSession)
+		r.Post("/from-transcript", h.StartTranscriptSession)
 		r.Get("/{id}", h.GetSession)
+		r.Get("/{id}/stream", h.StreamSession)
+		r.Get("/{id}/questions", h.GetCurrentQuestions)
+		r.Get("/{id}/questions/export", h.ExportQuestionScript)
+		r.Get("/{id}/questions/{question_id}/explanation", h.ExplainQuestion)
 		r.Post("/{id}/answer/{question_id}", h.SubmitTextAnswer)
 		r.Post("/{id}/answer/audio/{question_id}", h.SubmitAudioAnswer)
+		r.Get("/{id}/search", h.SearchSessionContent)
+		r.Get("/{id}/estimate", h.EstimateGeneration)
+		r.Post("/{id}/generate", h.GenerateSummary)
+		r.Post("/{id}/generate/stream", h.StreamGenerateSummary)
 		r.Get("/{id}/result", h.GetSessionResult)
+		r.Get("/{id}/results", h.ListResultVersions)
+		r.Get("/{id}/results/{version}", h.GetResultVersion)
+		r.Get("/{id}/bundle.zip", h.GetSessionBundle)
+		r.Get("/{id}/sections", h.ListResultSections)
+		r.Post("/{id}/sections/{index}/regenerate", h.RegenerateResultSection)
+		r.Post("/{id}/comments", h.CreateComment)
+		r.Get("/{id}/comments", h.ListComments)
+		r.Post("/{id}/comments/{comment_id}/resolve", h.ResolveComment)
+		r.Get("/{id}/conflicts", h.ListConflicts)
+		r.Post("/{id}/conflicts/{conflict_id}/resolve", h.ResolveConflict)
+		r.Post("/{id}/refine", h.RefineResult)
+		r.Post("/{id}/feedback", h.RegenerateWithFeedback)
+		r.Get("/{id}/changelog", h.GetChangeLog)
+		r.Get("/{id}/review", h.GetReview)
+		r.Post("/{id}/review/submit", h.SubmitForReview)
+		r.Post("/{id}/review/decision", h.DecideReview)
 		r.Post("/{id}/cancel", h.CancelSession)
+		r.Post("/{id}/heartbeat", h.Heartbeat)
+		r.Post("/{id}/language", h.SetSessionLanguage)
+		r.Post("/{id}/style", h.SetSummaryStyle)
+		r.Get("/{id}/features", h.GetSessionFeatures)
+		r.Get("/{id}/pending-questions", h.ListPendingQuestions)
+		r.Post("/{id}/pending-questions/{iteration_id}/ack", h.AcknowledgePendingQuestions)
 	})
+	r.Get("/requirements/diff", h.DiffResults)
+}
+
+// RegisterAdminRoutes registers session routes that require admin authorization
+func RegisterAdminRoutes(r chi.Router, h *Handler) {
+	r.Route("/interview-session", func(r chi.Router) {
+		r.Post("/{id}/approve-generation", h.ApproveGeneration)
+	})
+	r.Get("/search", h.AdminSearch)
 }
@@ -0,0 +1,138 @@
+package session
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/pkg/logger"
+	"github.com/go-chi/chi/v5"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+	"golang.org/x/net/websocket"
+)
+
+// streamWriteTimeout bounds sending one event to a stream, so a stalled client does not hold it
+const streamWriteTimeout = 10 * time.Second
+
+// StreamSession handles GET /interview-session/{id}/stream - WebSocket stream of the progress of a session.
+// The stream starts with the session as a status event, then pushes status changes, generated questions,
+// answered and skipped questions, estimates, errors and the final result as they happen. It is closed
+// once the session is finished or after the maximum stream duration
+func (h *Handler) StreamSession(w http.ResponseWriter, r *http.Request) {
+	sessionID := chi.URLParam(r, "id")
+	ctx := logger.AddFields(r.Context(),
+		zap.String("session_id", sessionID),
+		zap.String("action", "StreamSession"),
+	)
+
+	// Subscribe before reading the session, so no event between the two is missed
+	events, unsubscribe := h.streams.Subscribe(sessionID)
+	defer unsubscribe()
+
+	session, err := h.usecase.GetSession(ctx, sessionID)
+	if err != nil {
+		h.handleUsecaseError(ctx, w, err)
+		return
+	}
+
+	// The router timeout would end the stream after a minute, so it runs on its own deadline
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), h.streamCfg.MaxDuration)
+	defer cancel()
+
+	server := websocket.Server{
+		// Clients are authorized by the API key, so streams are accepted from any origin like the rest of the API
+		Handshake: func(*websocket.Config, *http.Request) error { return nil },
+		Handler: func(conn *websocket.Conn) {
+			h.runStream(ctx, conn, session, events)
+		},
+	}
+	server.ServeHTTP(w, r)
+}
+
+// runStream pushes the events of the session to the connection until the session is finished,
+// the client goes away or the stream times out. Status changes are found by reading the session,
+// so streams also follow workflows running on other replicas
+func (h *Handler) runStream(ctx context.Context, conn *websocket.Conn, session *entity.Session, events <-chan entity.StreamEvent) {
+	defer conn.Close()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Clients send nothing but the close frame; reading notices a closed connection
+	go func() {
+		defer cancel()
+		var discard []byte
+		for websocket.Message.Receive(conn, &discard) == nil {
+		}
+	}()
+
+	ctxzap.Info(ctx, "session stream opened")
+	defer ctxzap.Info(ctx, "session stream closed")
+
+	status := session.Status
+	if !h.sendStreamEvent(ctx, conn, statusStreamEvent(session)) || sessionFinished(status) {
+		return
+	}
+
+	ticker := time.NewTicker(h.streamCfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event := <-events:
+			if !h.sendStreamEvent(ctx, conn, event) {
+				return
+			}
+		case <-ticker.C:
+			current, err := h.usecase.GetSession(ctx, session.ID)
+			if err != nil {
+				ctxzap.Warn(ctx, "failed to check session status for stream", zap.Error(err))
+				continue
+			}
+			if current.Status == status {
+				continue
+			}
+
+			status = current.Status
+			if !h.sendStreamEvent(ctx, conn, statusStreamEvent(current)) || sessionFinished(status) {
+				return
+			}
+		}
+	}
+}
+
+// sendStreamEvent writes the event to the connection and reports whether the stream is still open
+func (h *Handler) sendStreamEvent(ctx context.Context, conn *websocket.Conn, event entity.StreamEvent) bool {
+	conn.SetWriteDeadline(time.Now().Add(streamWriteTimeout))
+	if err := websocket.JSON.Send(conn, event); err != nil {
+		ctxzap.Debug(ctx, "failed to send stream event",
+			zap.String("event", string(event.Event)),
+			zap.Error(err),
+		)
+		return false
+	}
+	return true
+}
+
+// statusStreamEvent reports the session in its current status
+func statusStreamEvent(session *entity.Session) entity.StreamEvent {
+	return entity.StreamEvent{
+		Event:     entity.StreamEventTypeStatus,
+		SessionID: session.ID,
+		Timestamp: time.Now().UTC(),
+		Data:      toSessionDTO(session),
+	}
+}
+
+// sessionFinished reports whether the session can no longer change on its own
+func sessionFinished(status entity.SessionStatus) bool {
+	switch status {
+	case entity.SessionStatusDone, entity.SessionStatusPartial, entity.SessionStatusError, entity.SessionStatusCanceled:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,142 @@
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/pkg/logger"
+	"github.com/go-chi/chi/v5"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// sseKeepAliveInterval keeps proxies from closing a summary stream while no chunk arrives
+const sseKeepAliveInterval = 15 * time.Second
+
+// StreamGenerateSummary handles POST /interview-session/{id}/generate/stream - Generate requirements
+// as Server-Sent Events. While the streaming feature is on for the session, chunk events carry the
+// document as it is generated; the stream ends with a finalResult or an error event. Generation goes
+// on when the client goes away and its result is saved as usual
+func (h *Handler) StreamGenerateSummary(w http.ResponseWriter, r *http.Request) {
+	sessionID := chi.URLParam(r, "id")
+	ctx := logger.AddFields(r.Context(),
+		zap.String("session_id", sessionID),
+		zap.String("action", "StreamGenerateSummary"),
+	)
+
+	// The server write timeout would cut the stream, it is bounded by the maximum stream duration instead
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+		ctxzap.Warn(ctx, "failed to clear write deadline of summary stream", zap.Error(err))
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	events := &sseWriter{w: w, rc: rc}
+	stopKeepAlive := events.keepAlive(sseKeepAliveInterval)
+	defer stopKeepAlive()
+
+	ctxzap.Info(ctx, "summary stream opened")
+	defer ctxzap.Info(ctx, "summary stream closed")
+
+	// The router timeout and a client going away must not interrupt the generation
+	genCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), h.streamCfg.MaxDuration)
+	defer cancel()
+
+	sent := 0
+	session, err := h.usecase.GenerateSummaryStream(genCtx, sessionID, func(partial string) {
+		events.send(ctx, entity.StreamEvent{
+			Event:     entity.StreamEventTypeChunk,
+			SessionID: sessionID,
+			Timestamp: time.Now().UTC(),
+			Data:      entity.SummaryChunkDTO{Content: partial[sent:]},
+		})
+		sent = len(partial)
+	})
+	if err != nil {
+		ctxzap.Error(ctx, "failed to generate summary", zap.Error(err))
+		events.send(ctx, entity.StreamEvent{
+			Event:     entity.CallbackEventTypeError,
+			SessionID: sessionID,
+			Timestamp: time.Now().UTC(),
+			Data: entity.ErrorResponse{
+				Error:   "failed to generate summary",
+				Message: err.Error(),
+			},
+		})
+		return
+	}
+
+	events.send(ctx, entity.StreamEvent{
+		Event:     entity.CallbackEventTypeFinalResult,
+		SessionID: sessionID,
+		Timestamp: time.Now().UTC(),
+		Data:      toSessionDTO(session),
+	})
+}
+
+// sseWriter writes Server-Sent Events, flushing each one; writes after the client went away are dropped
+type sseWriter struct {
+	mu sync.Mutex
+	w  http.ResponseWriter
+	rc *http.ResponseController
+}
+
+// send writes the event named after its type with the whole event as JSON data, like the WebSocket stream
+func (s *sseWriter) send(ctx context.Context, event entity.StreamEvent) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		ctxzap.Error(ctx, "failed to encode summary stream event", zap.Error(err))
+		return
+	}
+	s.write(ctx, fmt.Sprintf("event: %s\ndata: %s\n\n", event.Event, data))
+}
+
+// keepAlive writes a comment every interval until the returned func is called, which waits for
+// the last write so nothing is written once the handler returned
+func (s *sseWriter) keepAlive(interval time.Duration) func() {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				s.write(context.Background(), ": keep-alive\n\n")
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			<-stopped
+		})
+	}
+}
+
+func (s *sseWriter) write(ctx context.Context, text string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.w.Write([]byte(text)); err != nil {
+		ctxzap.Debug(ctx, "failed to write summary stream event", zap.Error(err))
+		return
+	}
+	if err := s.rc.Flush(); err != nil {
+		ctxzap.Debug(ctx, "failed to flush summary stream event", zap.Error(err))
+	}
+}
@@ -0,0 +1,197 @@
+package tenant
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/pkg/logger"
+	"github.com/go-chi/chi/v5"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+type Handler struct {
+	usecase TenantUsecase
+}
+
+func NewHandler(usecase TenantUsecase) *Handler {
+	return &Handler{
+		usecase: usecase,
+	}
+}
+
+// CreateTenant handles POST /admin/tenants
+func (h *Handler) CreateTenant(w http.ResponseWriter, r *http.Request) {
+	ctx := logger.WithAction(r.Context(), "CreateTenant")
+
+	var req entity.CreateTenantRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		ctxzap.Error(ctx, "failed to decode request body", zap.Error(err))
+		h.respondError(ctx, w, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	ctx = logger.AddFields(ctx, zap.String("tenant_id", req.ID))
+
+	resp, err := h.usecase.CreateTenant(ctx, &req)
+	if err != nil {
+		h.handleUsecaseError(ctx, w, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusCreated, resp)
+}
+
+// ListTenants handles GET /admin/tenants
+func (h *Handler) ListTenants(w http.ResponseWriter, r *http.Request) {
+	ctx := logger.WithAction(r.Context(), "ListTenants")
+
+	tenants, err := h.usecase.ListTenants(ctx)
+	if err != nil {
+		h.handleUsecaseError(ctx, w, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, map[string]any{"tenants": tenants})
+}
+
+// UpdateSettings handles PUT /admin/tenants/{tenant_id}/settings
+func (h *Handler) UpdateSettings(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID := chi.URLParam(r, "tenant_id")
+
+	ctx = logger.AddFields(ctx,
+		zap.String("tenant_id", tenantID),
+		zap.String("action", "UpdateTenantSettings"),
+	)
+
+	var settings entity.TenantSettings
+	if err := json.NewDecoder(r.Body).Decode(&settings); err != nil {
+		ctxzap.Error(ctx, "failed to decode request body", zap.Error(err))
+		h.respondError(ctx, w, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	tenant, err := h.usecase.UpdateSettings(ctx, tenantID, &settings)
+	if err != nil {
+		h.handleUsecaseError(ctx, w, err)
+		return
+	}
+
+	ctxzap.Info(ctx, "tenant settings updated")
+
+	h.respondJSON(w, http.StatusOK, tenant)
+}
+
+// ListPurges handles GET /admin/tenants/{tenant_id}/purges
+func (h *Handler) ListPurges(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID := chi.URLParam(r, "tenant_id")
+
+	ctx = logger.AddFields(ctx,
+		zap.String("tenant_id", tenantID),
+		zap.String("action", "ListRetentionPurges"),
+	)
+
+	purges, err := h.usecase.ListPurges(ctx, tenantID)
+	if err != nil {
+		h.handleUsecaseError(ctx, w, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, map[string]any{"purges": purges})
+}
+
+// CreateUser handles POST /admin/tenants/{tenant_id}/users
+func (h *Handler) CreateUser(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID := chi.URLParam(r, "tenant_id")
+
+	ctx = logger.AddFields(ctx,
+		zap.String("tenant_id", tenantID),
+		zap.String("action", "CreateUser"),
+	)
+
+	var req entity.CreateUserRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		ctxzap.Error(ctx, "failed to decode request body", zap.Error(err))
+		h.respondError(ctx, w, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	resp, err := h.usecase.CreateUser(ctx, tenantID, &req)
+	if err != nil {
+		h.handleUsecaseError(ctx, w, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusCreated, resp)
+}
+
+// ListUsers handles GET /admin/tenants/{tenant_id}/users
+func (h *Handler) ListUsers(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID := chi.URLParam(r, "tenant_id")
+
+	ctx = logger.AddFields(ctx,
+		zap.String("tenant_id", tenantID),
+		zap.String("action", "ListUsers"),
+	)
+
+	users, err := h.usecase.ListUsers(ctx, tenantID)
+	if err != nil {
+		h.handleUsecaseError(ctx, w, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, map[string]any{"users": users})
+}
+
+// DeleteUser handles DELETE /admin/tenants/{tenant_id}/users/{user_id}
+func (h *Handler) DeleteUser(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	tenantID := chi.URLParam(r, "tenant_id")
+	userID := chi.URLParam(r, "user_id")
+
+	ctx = logger.AddFields(ctx,
+		zap.String("tenant_id", tenantID),
+		zap.String("user_id", userID),
+		zap.String("action", "DeleteUser"),
+	)
+
+	if err := h.usecase.DeleteUser(ctx, tenantID, userID); err != nil {
+		h.handleUsecaseError(ctx, w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) respondJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
+
+func (h *Handler) respondError(ctx context.Context, w http.ResponseWriter, status int, message string, err error) {
+	ctxzap.Error(ctx, message, zap.Error(err))
+	h.respondJSON(w, status, entity.ErrorResponse{
+		Error:   http.StatusText(status),
+		Message: message,
+	})
+}
+
+func (h *Handler) handleUsecaseError(ctx context.Context, w http.ResponseWriter, err error) {
+	if errors.Is(err, entity.ErrTenantNotFound) || errors.Is(err, entity.ErrUserNotFound) {
+		h.respondError(ctx, w, http.StatusNotFound, "resource not found", err)
+	} else if errors.Is(err, entity.ErrTenantExists) {
+		h.respondError(ctx, w, http.StatusConflict, "tenant already exists", err)
+	} else if errors.Is(err, entity.ErrMissingField) || errors.Is(err, entity.ErrInvalidParameter) {
+		h.respondError(ctx, w, http.StatusBadRequest, "invalid parameter", err)
+	} else {
+		h.respondError(ctx, w, http.StatusInternalServerError, "internal server error", err)
+	}
+}
@@ -0,0 +1,17 @@
+package tenant
+
+import (
+	"context"
+
+	"github.com/futig/agent-backend/internal/entity"
+)
+
+type TenantUsecase interface {
+	CreateTenant(ctx context.Context, req *entity.CreateTenantRequest) (*entity.CreateTenantResponse, error)
+	ListTenants(ctx context.Context) ([]*entity.Tenant, error)
+	UpdateSettings(ctx context.Context, id string, settings *entity.TenantSettings) (*entity.Tenant, error)
+	ListPurges(ctx context.Context, tenantID string) ([]*entity.RetentionPurge, error)
+	CreateUser(ctx context.Context, tenantID string, req *entity.CreateUserRequest) (*entity.CreateUserResponse, error)
+	ListUsers(ctx context.Context, tenantID string) ([]*entity.User, error)
+	DeleteUser(ctx context.Context, tenantID, userID string) error
+}
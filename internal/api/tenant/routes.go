@@ -0,0 +1,20 @@
+package tenant
+
+import (
+	"github.com/go-chi/chi/v5"
+)
+
+// RegisterAdminRoutes registers tenant management routes that require admin authorization
+func RegisterAdminRoutes(r chi.Router, h *Handler) {
+	r.Route("/tenants", func(r chi.Router) {
+		r.Post("/", h.CreateTenant)
+		r.Get("/", h.ListTenants)
+		r.Put("/{tenant_id}/settings", h.UpdateSettings)
+		r.Get("/{tenant_id}/purges", h.ListPurges)
+		r.Route("/{tenant_id}/users", func(r chi.Router) {
+			r.Post("/", h.CreateUser)
+			r.Get("/", h.ListUsers)
+			r.Delete("/{user_id}", h.DeleteUser)
+		})
+	})
+}
@@ -0,0 +1,173 @@
+package theme
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/pkg/logger"
+	"github.com/go-chi/chi/v5"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// maxThemeFormSize bounds the multipart form of a theme with its logo and font
+const maxThemeFormSize = 8 << 20
+
+type Handler struct {
+	usecase ThemeUsecase
+}
+
+func NewHandler(usecase ThemeUsecase) *Handler {
+	return &Handler{
+		usecase: usecase,
+	}
+}
+
+// CreateTheme handles POST /admin/themes - multipart form with optional logo and font files
+func (h *Handler) CreateTheme(w http.ResponseWriter, r *http.Request) {
+	ctx := logger.WithAction(r.Context(), "CreateTheme")
+
+	if err := r.ParseMultipartForm(maxThemeFormSize); err != nil {
+		ctxzap.Error(ctx, "failed to parse multipart form", zap.Error(err))
+		h.respondError(ctx, w, http.StatusBadRequest, "invalid form data or size too large", err)
+		return
+	}
+
+	req := entity.CreateThemeRequest{
+		Name:         r.FormValue("name"),
+		PrimaryColor: r.FormValue("primary_color"),
+		HeaderText:   r.FormValue("header_text"),
+		FooterText:   r.FormValue("footer_text"),
+		FontName:     r.FormValue("font_name"),
+	}
+
+	var err error
+	if req.Logo, err = readAsset(r, "logo"); err != nil {
+		h.respondError(ctx, w, http.StatusBadRequest, "invalid logo file", err)
+		return
+	}
+	if req.Font, err = readAsset(r, "font"); err != nil {
+		h.respondError(ctx, w, http.StatusBadRequest, "invalid font file", err)
+		return
+	}
+
+	theme, err := h.usecase.CreateTheme(ctx, &req)
+	if err != nil {
+		h.handleUsecaseError(ctx, w, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusCreated, theme)
+}
+
+// ListThemes handles GET /admin/themes
+func (h *Handler) ListThemes(w http.ResponseWriter, r *http.Request) {
+	ctx := logger.WithAction(r.Context(), "ListThemes")
+
+	themes, err := h.usecase.ListThemes(ctx)
+	if err != nil {
+		h.handleUsecaseError(ctx, w, err)
+		return
+	}
+
+	h.respondJSON(w, http.StatusOK, map[string]any{"themes": themes})
+}
+
+// DeleteTheme handles DELETE /admin/themes/{theme_id}
+func (h *Handler) DeleteTheme(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	themeID := chi.URLParam(r, "theme_id")
+
+	ctx = logger.AddFields(ctx,
+		zap.String("theme_id", themeID),
+		zap.String("action", "DeleteTheme"),
+	)
+
+	if err := h.usecase.DeleteTheme(ctx, themeID); err != nil {
+		h.handleUsecaseError(ctx, w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// SetProjectTheme handles PUT /admin/projects/{project_id}/theme
+func (h *Handler) SetProjectTheme(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	projectID := chi.URLParam(r, "project_id")
+
+	ctx = logger.AddFields(ctx,
+		zap.String("project_id", projectID),
+		zap.String("action", "SetProjectTheme"),
+	)
+
+	var req entity.SetProjectThemeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		ctxzap.Error(ctx, "failed to decode request body", zap.Error(err))
+		h.respondError(ctx, w, http.StatusBadRequest, "invalid request body", err)
+		return
+	}
+
+	if err := h.usecase.SetProjectTheme(ctx, projectID, &req); err != nil {
+		h.handleUsecaseError(ctx, w, err)
+		return
+	}
+
+	ctxzap.Info(ctx, "project theme updated")
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// readAsset reads an optional file of the theme form; the content type is detected from the data
+func readAsset(r *http.Request, field string) (*entity.ThemeAsset, error) {
+	file, _, err := r.FormFile(field)
+	if errors.Is(err, http.ErrMissingFile) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", field, err)
+	}
+
+	return &entity.ThemeAsset{
+		Data:        data,
+		ContentType: http.DetectContentType(data),
+	}, nil
+}
+
+func (h *Handler) respondJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(data)
+}
+
+func (h *Handler) respondError(ctx context.Context, w http.ResponseWriter, status int, message string, err error) {
+	ctxzap.Error(ctx, message, zap.Error(err))
+	h.respondJSON(w, status, entity.ErrorResponse{
+		Error:   http.StatusText(status),
+		Message: message,
+	})
+}
+
+func (h *Handler) handleUsecaseError(ctx context.Context, w http.ResponseWriter, err error) {
+	if errors.Is(err, entity.ErrThemeNotFound) || errors.Is(err, entity.ErrProjectNotFound) {
+		h.respondError(ctx, w, http.StatusNotFound, "resource not found", err)
+	} else if errors.Is(err, entity.ErrFileTooLarge) {
+		h.respondError(ctx, w, http.StatusRequestEntityTooLarge, "file too large", err)
+	} else if errors.Is(err, entity.ErrMissingField) || errors.Is(err, entity.ErrInvalidParameter) ||
+		errors.Is(err, entity.ErrInvalidFile) || errors.Is(err, entity.ErrThemeAssetsUnavailable) {
+		h.respondError(ctx, w, http.StatusBadRequest, "invalid parameter", err)
+	} else {
+		h.respondError(ctx, w, http.StatusInternalServerError, "internal server error", err)
+	}
+}
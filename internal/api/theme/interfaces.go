@@ -0,0 +1,14 @@
+package theme
+
+import (
+	"context"
+
+	"github.com/futig/agent-backend/internal/entity"
+)
+
+type ThemeUsecase interface {
+	CreateTheme(ctx context.Context, req *entity.CreateThemeRequest) (*entity.DocumentTheme, error)
+	ListThemes(ctx context.Context) ([]*entity.DocumentTheme, error)
+	DeleteTheme(ctx context.Context, id string) error
+	SetProjectTheme(ctx context.Context, projectID string, req *entity.SetProjectThemeRequest) error
+}
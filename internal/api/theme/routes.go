@@ -0,0 +1,15 @@
+package theme
+
+import (
+	"github.com/go-chi/chi/v5"
+)
+
+// RegisterAdminRoutes registers document theme routes that require admin authorization
+func RegisterAdminRoutes(r chi.Router, h *Handler) {
+	r.Route("/themes", func(r chi.Router) {
+		r.Post("/", h.CreateTheme)
+		r.Get("/", h.ListThemes)
+		r.Delete("/{theme_id}", h.DeleteTheme)
+	})
+	r.Put("/projects/{project_id}/theme", h.SetProjectTheme)
+}
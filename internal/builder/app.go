@@ -8,19 +8,67 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/futig/agent-backend/internal/callbackoutbox"
+	"github.com/futig/agent-backend/internal/integration/common"
+	"github.com/futig/agent-backend/internal/pkg/jobqueue"
+	"github.com/futig/agent-backend/internal/pkg/tracing"
+	"github.com/futig/agent-backend/internal/retention"
+	"github.com/futig/agent-backend/internal/scheduler"
+	"github.com/futig/agent-backend/internal/voicequeue"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"go.uber.org/zap"
 )
 
 // App represents the application with all its components
 type App struct {
-	server *http.Server
-	db     *pgxpool.Pool
-	logger *zap.Logger
+	server         *http.Server
+	metricsServer  *http.Server // nil when METRICS_ADDR is empty
+	jobs           *jobqueue.Queue
+	scheduler      *scheduler.Scheduler   // nil when scheduled sessions are disabled
+	voiceQueue     *voicequeue.Worker     // nil when voice answers are not queued
+	callbackOutbox *callbackoutbox.Worker // nil when callback events are not kept in the outbox
+	cleaners       []*retention.Cleaner
+	warmers        []*common.Warmer
+	db             *pgxpool.Pool
+	logger         *zap.Logger
 }
 
 // Run starts the application and all its daemons
 func (a *App) Run() error {
+	daemonCtx, stopDaemons := context.WithCancel(context.Background())
+	defer stopDaemons()
+
+	go a.jobs.Run(daemonCtx)
+
+	if a.scheduler != nil {
+		go a.scheduler.Run(daemonCtx)
+	}
+
+	if a.voiceQueue != nil {
+		go a.voiceQueue.Run(daemonCtx)
+	}
+
+	if a.callbackOutbox != nil {
+		go a.callbackOutbox.Run(daemonCtx)
+	}
+
+	for _, cleaner := range a.cleaners {
+		go cleaner.Run(daemonCtx)
+	}
+
+	for _, warmer := range a.warmers {
+		go warmer.Run(daemonCtx)
+	}
+
+	if a.metricsServer != nil {
+		go func() {
+			a.logger.Info("Starting metrics server", zap.String("addr", a.metricsServer.Addr))
+			if err := a.metricsServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				a.logger.Error("Metrics server error", zap.Error(err))
+			}
+		}()
+	}
+
 	// Start HTTP server in goroutine
 	errChan := make(chan error, 1)
 	go func() {
@@ -43,6 +91,7 @@ func (a *App) Run() error {
 	}
 
 	// Graceful shutdown
+	stopDaemons()
 	return a.shutdown()
 }
 
@@ -58,6 +107,16 @@ func (a *App) shutdown() error {
 		return err
 	}
 
+	if a.metricsServer != nil {
+		if err := a.metricsServer.Shutdown(ctx); err != nil {
+			a.logger.Warn("Metrics server shutdown error", zap.Error(err))
+		}
+	}
+
+	if err := tracing.Shutdown(ctx); err != nil {
+		a.logger.Warn("Tracing shutdown error", zap.Error(err))
+	}
+
 	a.logger.Info("Closing database connections")
 	if a.db != nil {
 		a.db.Close()
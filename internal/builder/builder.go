@@ -7,19 +7,49 @@ import (
 	"time"
 
 	"github.com/futig/agent-backend/internal/api"
+	accountlinkapi "github.com/futig/agent-backend/internal/api/accountlink"
+	analyticsapi "github.com/futig/agent-backend/internal/api/analytics"
+	featureflagapi "github.com/futig/agent-backend/internal/api/featureflag"
+	incidentapi "github.com/futig/agent-backend/internal/api/incident"
+	operationapi "github.com/futig/agent-backend/internal/api/operation"
 	projectapi "github.com/futig/agent-backend/internal/api/project"
+	quotaapi "github.com/futig/agent-backend/internal/api/quota"
 	sessionapi "github.com/futig/agent-backend/internal/api/session"
+	tenantapi "github.com/futig/agent-backend/internal/api/tenant"
+	themeapi "github.com/futig/agent-backend/internal/api/theme"
+	"github.com/futig/agent-backend/internal/callbackoutbox"
 	"github.com/futig/agent-backend/internal/config"
 	"github.com/futig/agent-backend/internal/integration/asr"
 	"github.com/futig/agent-backend/internal/integration/callback"
+	"github.com/futig/agent-backend/internal/integration/common"
 	"github.com/futig/agent-backend/internal/integration/llm"
 	"github.com/futig/agent-backend/internal/integration/rag"
+	"github.com/futig/agent-backend/internal/pkg/estimate"
+	"github.com/futig/agent-backend/internal/pkg/jobqueue"
+	"github.com/futig/agent-backend/internal/pkg/metrics"
+	"github.com/futig/agent-backend/internal/pkg/stream"
+	"github.com/futig/agent-backend/internal/pkg/tracing"
 	"github.com/futig/agent-backend/internal/pkg/validator"
 	"github.com/futig/agent-backend/internal/repository"
+	"github.com/futig/agent-backend/internal/retention"
+	"github.com/futig/agent-backend/internal/scheduler"
 	"github.com/futig/agent-backend/internal/telegram"
+	"github.com/futig/agent-backend/internal/telegram/state"
+	"github.com/futig/agent-backend/internal/telegram/store"
+	"github.com/futig/agent-backend/internal/usecase/accountlink"
+	"github.com/futig/agent-backend/internal/usecase/analytics"
+	"github.com/futig/agent-backend/internal/usecase/demo"
+	"github.com/futig/agent-backend/internal/usecase/featureflag"
+	"github.com/futig/agent-backend/internal/usecase/incident"
+	"github.com/futig/agent-backend/internal/usecase/operation"
 	"github.com/futig/agent-backend/internal/usecase/project"
+	"github.com/futig/agent-backend/internal/usecase/quota"
 	"github.com/futig/agent-backend/internal/usecase/session"
+	"github.com/futig/agent-backend/internal/usecase/tenant"
+	"github.com/futig/agent-backend/internal/usecase/theme"
+	"github.com/futig/agent-backend/internal/voicequeue"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
 func Build() (*App, error) {
@@ -40,59 +70,140 @@ func Build() (*App, error) {
 		zap.String("server_addr", cfg.ServerAddr),
 	)
 
+	if err := tracing.Setup(ctx, cfg.TracingCfg, "api", logger); err != nil {
+		return nil, fmt.Errorf("setup tracing: %w", err)
+	}
+
 	// Setup database connection
 	db, err := setupDatabase(ctx, cfg, logger)
 	if err != nil {
 		return nil, fmt.Errorf("setup database: %w", err)
 	}
 
-	// Run database migrations
-	logger.Info("Running database migrations")
-	if err := repository.RunMigrations(cfg.DatabaseURL); err != nil {
+	// Apply pending database migrations or make sure there are none
+	if err := prepareSchema(cfg, logger); err != nil {
 		db.Close()
-		return nil, fmt.Errorf("run migrations: %w", err)
+		return nil, err
 	}
-	logger.Info("Database migrations completed successfully")
 
 	// Initialize repositories
-	projectRepo := repository.NewProjectPostgres(db)
+	var projectRepo repository.ProjectRepository = repository.NewProjectPostgres(db)
+	if cfg.ProjectListCacheCfg.TTL > 0 {
+		projectRepo = repository.NewProjectListCache(projectRepo, cfg.ProjectListCacheCfg.TTL, cfg.ProjectListCacheCfg.MaxEntries)
+	}
 	projectFileRepo := repository.NewProjectFilePostgres(db)
 	sessionRepo := repository.NewSessionPostgres(db)
 	iterationRepo := repository.NewIterationPostgres(db)
 	questionRepo := repository.NewQuestionPostgres(db)
 	sessionMessageRepo := repository.NewSessionMessagePostgres(db)
+	sessionTranslationRepo := repository.NewSessionTranslationPostgres(db)
+	auditRepo := repository.NewAuditPostgres(db)
+	generationApprovalRepo := repository.NewGenerationApprovalPostgres(db)
+	resultSectionRepo := repository.NewResultSectionPostgres(db)
+	commentRepo := repository.NewCommentPostgres(db)
+	reviewRepo := repository.NewReviewPostgres(db)
+	scheduleRepo := repository.NewSchedulePostgres(db)
+	deltaRepo := repository.NewDeltaPostgres(db)
+	conflictRepo := repository.NewConflictPostgres(db)
+	searchRepo := repository.NewSearchPostgres(db)
+	resultVersionRepo := repository.NewResultVersionPostgres(db)
+	timeBudgetRepo := repository.NewTimeBudgetPostgres(db)
+	conversationLogRepo := repository.NewConversationLogPostgres(db)
+	pendingVoiceRepo := repository.NewPendingVoiceAnswerPostgres(db)
+	pendingQuestionsRepo := repository.NewPendingQuestionsPostgres(db)
+	factsRepo := repository.NewSessionFactsPostgres(db)
+	sessionLockRepo := repository.NewSessionLockPostgres(db)
+	sessionOperationRepo := repository.NewSessionOperationPostgres(db)
+	genFailureRepo := repository.NewGenerationFailurePostgres(db)
+	explanationRepo := repository.NewQuestionExplanationPostgres(db)
+	accountLinkRepo := repository.NewAccountLinkPostgres(db)
+	operationRepo := repository.NewOperationPostgres(db)
+	callbackOutboxRepo := repository.NewCallbackOutboxPostgres(db)
+	// Telegram users may turn transcript normalization off for the sessions they started
+	telegramStateRepo := repository.NewTelegramStateRepository(db)
+	tenantRepo := repository.NewTenantPostgres(db)
+	retentionRepo := repository.NewRetentionPostgres(db)
+	userRepo := repository.NewUserPostgres(db)
+	themeRepo := repository.NewThemePostgres(db)
+	featureFlagRepo := repository.NewFeatureFlagPostgres(db)
+	quotaRepo := repository.NewQuotaPostgres(db)
+	incidentRepo := repository.NewIncidentPostgres(db)
+	analyticsRepo := repository.NewAnalyticsPostgres(db)
 	logger.Info("Repositories initialized")
 
+	// Errors logged with a correlation ID are recorded as incidents, found by the code shown to the user
+	incidentUC := incident.NewUsecase(incidentRepo, logger)
+	logger = logger.WithOptions(zap.WrapCore(func(c zapcore.Core) zapcore.Core {
+		return zapcore.NewTee(c, incidentUC.Core())
+	}))
+
 	// Initialize connectors
+	// With the outbox, callback events are stored before they are sent and retried until delivered
 	callbackConnector := callback.NewConnector(cfg.CallbackConnectorCfg, logger)
+	if cfg.CallbackOutboxCfg.Enabled {
+		callbackConnector.WithOutbox(callbackOutboxRepo, cfg.CallbackOutboxCfg)
+	}
 
 	// Initialize external service connectors (with mock support)
 	var ragConnector project.RagConnector
 	var llmConnector session.LLMConnector
 	var asrConnector session.ASRConnector
+	var warmers []*common.Warmer // connections of real connectors are set up ahead of requests
 
 	if cfg.EnableMocks {
 		logger.Info("Using mock connectors for external services")
 		ragConnector = rag.NewMockConnector(logger)
 		llmConnector = llm.NewMockConnector(logger)
-		asrConnector = asr.NewMockConnector(logger)
+		asrConnector = asr.NewMockConnector(logger).WithTranscripts(cfg.ASRConnectorCfg.MockTranscripts)
 	} else {
 		logger.Info("Using real connectors for external services")
-		ragConnector = rag.NewConnector(cfg.RAGConnectorCfg, logger)
-		llmConnector = llm.NewConnector(cfg.LLMConnectorCfg, logger)
-		asrConnector = asr.NewConnector(cfg.ASRConnectorCfg, logger)
+		realRAG := rag.NewConnector(cfg.RAGConnectorCfg, logger)
+		realLLM := llm.NewConnector(cfg.LLMConnectorCfg, logger)
+		realASR := asr.NewConnector(cfg.ASRConnectorCfg, logger)
+		warmers = append(warmers, realRAG.Warmer(), realLLM.Warmer(), realASR.Warmer())
+		ragConnector, llmConnector, asrConnector = realRAG, realLLM, realASR
 	}
 
 	// Initialize validators
 	fileValidator := validator.NewFileValidator(cfg.FileUploadCfg)
 	logger.Info("Validators initialized")
 
+	moderator, err := setupModerator(cfg.ModerationCfg, logger)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("setup moderator: %w", err)
+	}
+	normalizer := setupNormalizer(cfg.NormalizationCfg, llmConnector, logger)
+
+	notifier, err := setupNotifier(ctx, cfg, tenantRepo, logger)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	resultStore := setupResultStore(ctx, cfg, logger)
+
 	// Initialize use cases
+	themeUC := theme.NewUsecase(themeRepo, projectRepo, resultStore, fileValidator, logger)
+	featureFlagUC := featureflag.NewUsecase(
+		featureFlagRepo,
+		cfg.FeatureFlagsCfg.Rollouts,
+		cfg.FeatureFlagsCfg.RefreshInterval,
+		fileValidator,
+		logger,
+	)
+	quotaUC := quota.NewUsecase(quotaRepo, cfg.QuotaCfg, logger)
+
 	projectUC := project.NewUsecase(
 		projectRepo,
 		projectFileRepo,
+		scheduleRepo,
+		telegramStateRepo,
 		fileValidator,
 		ragConnector,
+		llmConnector,
+		resultStore,
+		cfg.ProjectDescriptionCfg,
+		cfg.ProjectFreshnessCfg,
 		logger,
 	)
 
@@ -102,23 +213,126 @@ func Build() (*App, error) {
 		questionRepo,
 		projectRepo,
 		sessionMessageRepo,
+		sessionTranslationRepo,
+		auditRepo,
+		generationApprovalRepo,
+		resultSectionRepo,
+		commentRepo,
+		reviewRepo,
+		scheduleRepo,
+		deltaRepo,
+		conflictRepo,
+		searchRepo,
+		resultVersionRepo,
+		timeBudgetRepo,
+		tenantRepo,
+		conversationLogRepo,
+		pendingVoiceRepo,
+		pendingQuestionsRepo,
+		factsRepo,
+		sessionLockRepo,
+		sessionOperationRepo,
+		genFailureRepo,
+		explanationRepo,
 		fileValidator,
 		ragConnector,
 		llmConnector,
 		asrConnector,
+		setupDemoConnectors(logger),
+		moderator,
+		normalizer,
+		telegramStateRepo,
+		estimate.NewEstimator(cfg.EstimateCfg),
+		notifier,
+		notifier,
+		notifier,
+		resultStore,
+		themeUC,
+		featureFlagUC,
+		quotaUC,
+		cfg.ReviewCfg.RequireApproval,
+		cfg.ResultStorageCfg.InlineThreshold,
+		cfg.TimeBudgetCfg.Default,
+		cfg.TimeBudgetCfg.WarnThreshold,
+		cfg.HeartbeatCfg.MinInterval,
+		cfg.VoiceQueueCfg.Enabled,
+		setupConversationWindow(cfg.ConversationLogCfg),
+		setupContextSnapshot(cfg.ContextSnapshotCfg),
+		cfg.GoalQualityCfg.MinWords,
+		cfg.QuestionDedupCfg.Threshold,
+		cfg.SessionLockCfg.WaitTimeout,
+		cfg.SessionLockCfg.OperationTTL,
+		cfg.GenerationFallbackCfg.MaxFailures,
+		cfg.ExplanationCfg.OnDemand,
+		cfg.ExplanationCfg.Cooldown,
 		logger,
 	)
+
+	operationUC := operation.NewUsecase(operationRepo, callbackOutboxRepo, logger)
+	accountLinkUC := accountlink.NewUsecase(accountLinkRepo, sessionRepo, cfg.AccountLinkCfg, logger)
+	tenantUC := tenant.NewUsecase(tenantRepo, userRepo, retentionRepo, resultStore, cfg.ContentRetentionCfg, fileValidator, logger)
+	analyticsUC := analytics.NewUsecase(analyticsRepo, cfg.AnalyticsCfg, logger)
 	logger.Info("Use cases initialized")
 
 	// Setup API handlers
-	projectHandler := projectapi.NewHandler(projectUC, cfg.FileUploadCfg, callbackConnector, fileValidator)
-	sessionHandler := sessionapi.NewHandler(sessionUC, fileValidator, callbackConnector)
+	jobQueue := jobqueue.New(cfg.JobQueueCfg, logger)
+	projectHandler := projectapi.NewHandler(projectUC, cfg.FileUploadCfg, callbackConnector, jobQueue, fileValidator)
+	streamHub := stream.NewHub(cfg.StreamCfg.Buffer)
+	sessionHandler := sessionapi.NewHandler(sessionUC, fileValidator, callbackConnector, operationUC, jobQueue, streamHub, cfg.StreamCfg, cfg.SyncStartTimeout)
+	operationHandler := operationapi.NewHandler(operationUC)
+	tenantHandler := tenantapi.NewHandler(tenantUC)
+	themeHandler := themeapi.NewHandler(themeUC)
+	featureFlagHandler := featureflagapi.NewHandler(featureFlagUC)
+	quotaHandler := quotaapi.NewHandler(quotaUC)
+	accountLinkHandler := accountlinkapi.NewHandler(accountLinkUC)
+	incidentHandler := incidentapi.NewHandler(incidentUC)
+	analyticsHandler := analyticsapi.NewHandler(analyticsUC)
 	logger.Info("API handlers initialized")
 
 	// Setup router
-	router := api.SetupRouter(projectHandler, sessionHandler, logger)
+	router := api.SetupRouter(
+		projectHandler,
+		sessionHandler,
+		operationHandler,
+		tenantHandler,
+		themeHandler,
+		featureFlagHandler,
+		quotaHandler,
+		accountLinkHandler,
+		incidentHandler,
+		analyticsHandler,
+		tenantUC,
+		cfg.TenancyCfg.RequireAPIKey,
+		cfg.AdminToken,
+		logger,
+	)
 	logger.Info("HTTP router configured")
 
+	var sessionScheduler *scheduler.Scheduler
+	if cfg.SchedulerCfg.Enabled {
+		sessionScheduler = scheduler.New(cfg.SchedulerCfg, sessionUC, logger)
+	}
+
+	cleaners := []*retention.Cleaner{
+		retention.New(cfg.OperationsCfg, operationUC, logger),
+		retention.NewDemoSessions(cfg.DemoCfg, sessionUC, logger),
+		retention.NewIncidents(cfg.IncidentsCfg, incidentUC, logger),
+		retention.NewContent(cfg.ContentRetentionCfg, tenantUC, logger),
+	}
+
+	// Voice answers are queued by the bots and submitted here once speech recognition recovers
+	var voiceQueue *voicequeue.Worker
+	if cfg.VoiceQueueCfg.Enabled {
+		voiceQueue = voicequeue.New(cfg.VoiceQueueCfg, sessionUC, logger)
+		cleaners = append(cleaners, retention.NewPendingVoiceAnswers(cfg.VoiceQueueCfg, sessionUC, logger))
+	}
+
+	var callbackOutbox *callbackoutbox.Worker
+	if cfg.CallbackOutboxCfg.Enabled {
+		callbackOutbox = callbackoutbox.New(cfg.CallbackOutboxCfg, callbackConnector, logger)
+		cleaners = append(cleaners, retention.NewCallbackDeliveries(cfg.CallbackOutboxCfg, operationUC, logger))
+	}
+
 	// Create HTTP server
 	server := &http.Server{
 		Addr:         cfg.ServerAddr,
@@ -128,14 +342,30 @@ func Build() (*App, error) {
 		IdleTimeout:  60 * time.Second,
 	}
 
+	var metricsServer *http.Server
+	if cfg.MetricsAddr != "" {
+		metricsServer = &http.Server{
+			Addr:              cfg.MetricsAddr,
+			Handler:           metrics.NewServeMux(),
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+	}
+
 	logger.Info("Application built successfully",
 		zap.String("environment", cfg.Environment),
 	)
 
 	return &App{
-		server: server,
-		db:     db,
-		logger: logger,
+		server:         server,
+		metricsServer:  metricsServer,
+		jobs:           jobQueue,
+		scheduler:      sessionScheduler,
+		voiceQueue:     voiceQueue,
+		callbackOutbox: callbackOutbox,
+		cleaners:       cleaners,
+		warmers:        warmers,
+		db:             db,
+		logger:         logger,
 	}, nil
 }
 
@@ -157,57 +387,129 @@ func BuildTelegramBot() (telegram.Bot, *zap.Logger, error) {
 		zap.String("environment", cfg.Environment),
 	)
 
+	if err := tracing.Setup(ctx, cfg.TracingCfg, "telegram-bot", logger); err != nil {
+		return nil, nil, fmt.Errorf("setup tracing: %w", err)
+	}
+
 	// Setup database connection
 	db, err := setupDatabase(ctx, cfg, logger)
 	if err != nil {
 		return nil, nil, fmt.Errorf("setup database: %w", err)
 	}
 
-	// Run database migrations
-	logger.Info("Running database migrations")
-	if err := repository.RunMigrations(cfg.DatabaseURL); err != nil {
+	// Apply pending database migrations or make sure there are none
+	if err := prepareSchema(cfg, logger); err != nil {
 		db.Close()
-		return nil, nil, fmt.Errorf("run migrations: %w", err)
+		return nil, nil, err
 	}
-	logger.Info("Database migrations completed successfully")
 
 	// Initialize repositories
-	projectRepo := repository.NewProjectPostgres(db)
+	var projectRepo repository.ProjectRepository = repository.NewProjectPostgres(db)
+	if cfg.ProjectListCacheCfg.TTL > 0 {
+		projectRepo = repository.NewProjectListCache(projectRepo, cfg.ProjectListCacheCfg.TTL, cfg.ProjectListCacheCfg.MaxEntries)
+	}
 	projectFileRepo := repository.NewProjectFilePostgres(db)
 	sessionRepo := repository.NewSessionPostgres(db)
 	iterationRepo := repository.NewIterationPostgres(db)
 	questionRepo := repository.NewQuestionPostgres(db)
 	sessionMessageRepo := repository.NewSessionMessagePostgres(db)
+	sessionTranslationRepo := repository.NewSessionTranslationPostgres(db)
+	auditRepo := repository.NewAuditPostgres(db)
+	generationApprovalRepo := repository.NewGenerationApprovalPostgres(db)
+	resultSectionRepo := repository.NewResultSectionPostgres(db)
+	commentRepo := repository.NewCommentPostgres(db)
+	reviewRepo := repository.NewReviewPostgres(db)
+	scheduleRepo := repository.NewSchedulePostgres(db)
+	deltaRepo := repository.NewDeltaPostgres(db)
+	conflictRepo := repository.NewConflictPostgres(db)
+	searchRepo := repository.NewSearchPostgres(db)
+	resultVersionRepo := repository.NewResultVersionPostgres(db)
+	timeBudgetRepo := repository.NewTimeBudgetPostgres(db)
+	conversationLogRepo := repository.NewConversationLogPostgres(db)
+	pendingVoiceRepo := repository.NewPendingVoiceAnswerPostgres(db)
+	pendingQuestionsRepo := repository.NewPendingQuestionsPostgres(db)
+	factsRepo := repository.NewSessionFactsPostgres(db)
+	sessionLockRepo := repository.NewSessionLockPostgres(db)
+	sessionOperationRepo := repository.NewSessionOperationPostgres(db)
+	genFailureRepo := repository.NewGenerationFailurePostgres(db)
+	explanationRepo := repository.NewQuestionExplanationPostgres(db)
+	accountLinkRepo := repository.NewAccountLinkPostgres(db)
 	telegramStateRepo := repository.NewTelegramStateRepository(db)
+	tenantRepo := repository.NewTenantPostgres(db)
+	retentionRepo := repository.NewRetentionPostgres(db)
+	userRepo := repository.NewUserPostgres(db)
+	themeRepo := repository.NewThemePostgres(db)
+	featureFlagRepo := repository.NewFeatureFlagPostgres(db)
+	quotaRepo := repository.NewQuotaPostgres(db)
+	incidentRepo := repository.NewIncidentPostgres(db)
 	logger.Info("Repositories initialized")
 
+	// Errors logged with a correlation ID are recorded as incidents, found by the code shown to the user
+	incidentUC := incident.NewUsecase(incidentRepo, logger)
+	logger = logger.WithOptions(zap.WrapCore(func(c zapcore.Core) zapcore.Core {
+		return zapcore.NewTee(c, incidentUC.Core())
+	}))
+
 	// Initialize connectors
 	var ragConnector project.RagConnector
 	var llmConnector session.LLMConnector
 	var asrConnector session.ASRConnector
+	var warmers []*common.Warmer // connections of real connectors are set up ahead of requests
 
 	if cfg.EnableMocks {
 		logger.Info("Using mock connectors for external services")
 		ragConnector = rag.NewMockConnector(logger)
 		llmConnector = llm.NewMockConnector(logger)
-		asrConnector = asr.NewMockConnector(logger)
+		asrConnector = asr.NewMockConnector(logger).WithTranscripts(cfg.ASRConnectorCfg.MockTranscripts)
 	} else {
 		logger.Info("Using real connectors for external services")
-		ragConnector = rag.NewConnector(cfg.RAGConnectorCfg, logger)
-		llmConnector = llm.NewConnector(cfg.LLMConnectorCfg, logger)
-		asrConnector = asr.NewConnector(cfg.ASRConnectorCfg, logger)
+		realRAG := rag.NewConnector(cfg.RAGConnectorCfg, logger)
+		realLLM := llm.NewConnector(cfg.LLMConnectorCfg, logger)
+		realASR := asr.NewConnector(cfg.ASRConnectorCfg, logger)
+		warmers = append(warmers, realRAG.Warmer(), realLLM.Warmer(), realASR.Warmer())
+		ragConnector, llmConnector, asrConnector = realRAG, realLLM, realASR
 	}
 
 	// Initialize validators
 	fileValidator := validator.NewFileValidator(cfg.FileUploadCfg)
 	logger.Info("Validators initialized")
 
+	moderator, err := setupModerator(cfg.ModerationCfg, logger)
+	if err != nil {
+		db.Close()
+		return nil, nil, fmt.Errorf("setup moderator: %w", err)
+	}
+	normalizer := setupNormalizer(cfg.NormalizationCfg, llmConnector, logger)
+
+	notifier, err := setupNotifier(ctx, cfg, tenantRepo, logger)
+	if err != nil {
+		db.Close()
+		return nil, nil, err
+	}
+	resultStore := setupResultStore(ctx, cfg, logger)
+
 	// Initialize use cases
+	themeUC := theme.NewUsecase(themeRepo, projectRepo, resultStore, fileValidator, logger)
+	featureFlagUC := featureflag.NewUsecase(
+		featureFlagRepo,
+		cfg.FeatureFlagsCfg.Rollouts,
+		cfg.FeatureFlagsCfg.RefreshInterval,
+		fileValidator,
+		logger,
+	)
+	quotaUC := quota.NewUsecase(quotaRepo, cfg.QuotaCfg, logger)
+
 	projectUC := project.NewUsecase(
 		projectRepo,
 		projectFileRepo,
+		scheduleRepo,
+		telegramStateRepo,
 		fileValidator,
 		ragConnector,
+		llmConnector,
+		resultStore,
+		cfg.ProjectDescriptionCfg,
+		cfg.ProjectFreshnessCfg,
 		logger,
 	)
 
@@ -217,24 +519,127 @@ func BuildTelegramBot() (telegram.Bot, *zap.Logger, error) {
 		questionRepo,
 		projectRepo,
 		sessionMessageRepo,
+		sessionTranslationRepo,
+		auditRepo,
+		generationApprovalRepo,
+		resultSectionRepo,
+		commentRepo,
+		reviewRepo,
+		scheduleRepo,
+		deltaRepo,
+		conflictRepo,
+		searchRepo,
+		resultVersionRepo,
+		timeBudgetRepo,
+		tenantRepo,
+		conversationLogRepo,
+		pendingVoiceRepo,
+		pendingQuestionsRepo,
+		factsRepo,
+		sessionLockRepo,
+		sessionOperationRepo,
+		genFailureRepo,
+		explanationRepo,
 		fileValidator,
 		ragConnector,
 		llmConnector,
 		asrConnector,
+		setupDemoConnectors(logger),
+		moderator,
+		normalizer,
+		telegramStateRepo,
+		estimate.NewEstimator(cfg.EstimateCfg),
+		notifier,
+		notifier,
+		notifier,
+		resultStore,
+		themeUC,
+		featureFlagUC,
+		quotaUC,
+		cfg.ReviewCfg.RequireApproval,
+		cfg.ResultStorageCfg.InlineThreshold,
+		cfg.TimeBudgetCfg.Default,
+		cfg.TimeBudgetCfg.WarnThreshold,
+		cfg.HeartbeatCfg.MinInterval,
+		cfg.VoiceQueueCfg.Enabled,
+		setupConversationWindow(cfg.ConversationLogCfg),
+		setupContextSnapshot(cfg.ContextSnapshotCfg),
+		cfg.GoalQualityCfg.MinWords,
+		cfg.QuestionDedupCfg.Threshold,
+		cfg.SessionLockCfg.WaitTimeout,
+		cfg.SessionLockCfg.OperationTTL,
+		cfg.GenerationFallbackCfg.MaxFailures,
+		cfg.ExplanationCfg.OnDemand,
+		cfg.ExplanationCfg.Cooldown,
 		logger,
 	)
+	// The onboarding demo always runs against the mock LLM, so it is free and predictable
+	demoUC := demo.NewUsecase(llm.NewMockConnector(logger))
+	accountLinkUC := accountlink.NewUsecase(accountLinkRepo, sessionRepo, cfg.AccountLinkCfg, logger)
+	tenantUC := tenant.NewUsecase(tenantRepo, userRepo, retentionRepo, resultStore, cfg.ContentRetentionCfg, fileValidator, logger)
 	logger.Info("Use cases initialized")
 
-	// Initialize Telegram bot
-	bot, err := telegram.NewBot(&cfg.TelegramCfg, cfg.ContextQuestions, telegramStateRepo, sessionUC, projectUC, logger)
+	// Each bot serves the tenant its token is mapped to; bots of one tenant would share the
+	// Telegram state of their users, so every bot needs a tenant of its own
+	botStore, err := store.New(cfg.TelegramCfg.Store)
 	if err != nil {
 		db.Close()
-		return nil, nil, fmt.Errorf("initialize telegram bot: %w", err)
+		return nil, nil, fmt.Errorf("setup telegram store: %w", err)
+	}
+	logger.Info("Telegram store initialized", zap.String("backend", cfg.TelegramCfg.Store.Backend))
+
+	registry := telegram.NewRegistry(&cfg.TelegramCfg, botStore, logger)
+
+	// The state cache is an optimization, so an unreachable Redis leaves the state in Postgres only
+	var stateStorage state.Storage = telegramStateRepo
+	if cfg.TelegramCfg.StateCache.Enabled {
+		cachedStorage, err := state.NewRedisCachedStorage(telegramStateRepo, cfg.TelegramCfg.Store, cfg.TelegramCfg.StateCache, logger)
+		if err != nil {
+			logger.Warn("Telegram state cache unavailable, reading state from Postgres", zap.Error(err))
+		} else {
+			stateStorage = cachedStorage
+			registry.AddCloser(cachedStorage)
+			logger.Info("Telegram state cache enabled", zap.Duration("ttl", cfg.TelegramCfg.StateCache.TTL))
+		}
+	}
+
+	botTenants := make(map[string]string, len(cfg.TelegramBots))
+	for _, botDef := range cfg.TelegramBots {
+		botLogger := logger.With(zap.String("bot", botDef.Name))
+
+		botTenant, err := tenantUC.ResolveBotToken(ctx, botDef.Token)
+		if err != nil {
+			botStore.Close()
+			db.Close()
+			return nil, nil, fmt.Errorf("resolve tenant of bot '%s': %w", botDef.Name, err)
+		}
+		if other, ok := botTenants[botTenant.ID]; ok {
+			botStore.Close()
+			db.Close()
+			return nil, nil, fmt.Errorf("bots '%s' and '%s' both serve tenant '%s'", other, botDef.Name, botTenant.ID)
+		}
+		botTenants[botTenant.ID] = botDef.Name
+		botLogger.Info("Telegram bot tenant resolved", zap.String("tenant_id", botTenant.ID))
+
+		botCfg := cfg.TelegramCfg.ForBot(botDef)
+		bot, err := telegram.NewBot(&botCfg, botTenant, botDef.ContextQuestions, stateStorage, botStore, sessionUC, projectUC, demoUC, accountLinkUC, tenantUC, botLogger)
+		if err != nil {
+			botStore.Close()
+			db.Close()
+			return nil, nil, fmt.Errorf("initialize telegram bot '%s': %w", botDef.Name, err)
+		}
+		registry.Register(botDef.Name, botDef.WebhookPath, bot)
+	}
+
+	// The bot process runs until it exits, so do the warmers of its connectors
+	for _, warmer := range warmers {
+		go warmer.Run(ctx)
 	}
 
 	logger.Info("Telegram bot built successfully",
 		zap.String("environment", cfg.Environment),
+		zap.Int("bots", len(cfg.TelegramBots)),
 	)
 
-	return bot, logger, nil
+	return registry, logger, nil
 }
@@ -0,0 +1,27 @@
+package builder
+
+import (
+	"github.com/futig/agent-backend/internal/config"
+	"github.com/futig/agent-backend/internal/usecase/session"
+)
+
+// setupConversationWindow limits the interview transcript sent to the LLM; disabled transcripts send none
+func setupConversationWindow(cfg config.ConversationLogConfig) session.ConversationWindow {
+	if !cfg.InPrompts {
+		return session.ConversationWindow{}
+	}
+
+	return session.ConversationWindow{
+		MaxEntries: cfg.MaxEntries,
+		MaxChars:   cfg.MaxChars,
+	}
+}
+
+// setupContextSnapshot limits the facts already provided in a session sent to the LLM; disabled snapshots send none
+func setupContextSnapshot(cfg config.ContextSnapshotConfig) session.ContextSnapshot {
+	if !cfg.Enabled {
+		return session.ContextSnapshot{}
+	}
+
+	return session.ContextSnapshot{MaxFacts: cfg.MaxFacts}
+}
@@ -0,0 +1,18 @@
+package builder
+
+import (
+	"github.com/futig/agent-backend/internal/integration/asr"
+	"github.com/futig/agent-backend/internal/integration/llm"
+	"github.com/futig/agent-backend/internal/integration/rag"
+	"github.com/futig/agent-backend/internal/usecase/session"
+	"go.uber.org/zap"
+)
+
+// setupDemoConnectors creates the connectors used by sandbox demo sessions, which never reach real services
+func setupDemoConnectors(logger *zap.Logger) session.DemoConnectors {
+	return session.DemoConnectors{
+		RAG: rag.NewMockConnector(logger),
+		LLM: llm.NewMockConnector(logger),
+		ASR: asr.NewMockConnector(logger),
+	}
+}
@@ -0,0 +1,193 @@
+package builder
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/futig/agent-backend/internal/config"
+	"github.com/futig/agent-backend/internal/repository"
+	"go.uber.org/zap"
+)
+
+// migrateCommand is the subcommand of both binaries that manages schema migrations
+const migrateCommand = "migrate"
+
+const migrateUsage = `usage: migrate <command>
+  up [N]       apply N pending migrations, all of them when N is omitted
+  down [N]     roll back N applied migrations (default 1)
+  status       show the schema version and pending migrations
+  force V      set the schema version to V and clear the dirty flag (-1 for an empty schema)
+  check        exit with an error while migrations are pending or dirty
+  compress-texts
+               compress long draft messages and project contexts stored before compression`
+
+// prepareSchema applies pending migrations or, in check mode, refuses to start until they are applied
+func prepareSchema(cfg *config.Config, logger *zap.Logger) error {
+	if cfg.MigrationsCfg.OnStart == config.MigrationsOnStartCheck {
+		logger.Info("Checking database migrations")
+		if err := repository.CheckMigrations(cfg.DatabaseURL, cfg.MigrationsCfg.Dir); err != nil {
+			return fmt.Errorf("check migrations: %w", err)
+		}
+		logger.Info("Database schema is up to date")
+		return nil
+	}
+
+	logger.Info("Running database migrations")
+	if err := repository.RunMigrations(cfg.DatabaseURL, cfg.MigrationsCfg.Dir); err != nil {
+		return fmt.Errorf("run migrations: %w", err)
+	}
+	logger.Info("Database migrations completed successfully")
+
+	return nil
+}
+
+// IsMigrateCommand reports whether the binary was started with the migrate subcommand,
+// e.g. `agent-backend -env prod migrate status`
+func IsMigrateCommand(args []string) bool {
+	return isSubcommand(args, migrateCommand)
+}
+
+// isSubcommand reports whether the first argument after the flags is the subcommand
+func isSubcommand(args []string, command string) bool {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "-") {
+			return arg == command
+		}
+		// The -env flag takes its value from the next argument unless written as -env=value
+		if arg == "-env" || arg == "--env" {
+			i++
+		}
+	}
+	return false
+}
+
+// RunMigrateCommand runs the migrate subcommand against the configured database
+func RunMigrateCommand(out io.Writer) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+
+	args := flag.Args()
+	if len(args) < 2 || args[0] != migrateCommand {
+		return fmt.Errorf("missing migrate command\n%s", migrateUsage)
+	}
+
+	mg, err := repository.NewMigrator(cfg.DatabaseURL, cfg.MigrationsCfg.Dir)
+	if err != nil {
+		return err
+	}
+	defer mg.Close()
+
+	command, params := args[1], args[2:]
+	switch command {
+	case "up":
+		steps, err := migrateCount(params, 0)
+		if err != nil {
+			return err
+		}
+		if err := mg.Up(steps); err != nil {
+			return err
+		}
+	case "down":
+		steps, err := migrateCount(params, 1)
+		if err != nil {
+			return err
+		}
+		if err := mg.Down(steps); err != nil {
+			return err
+		}
+	case "force":
+		if len(params) != 1 {
+			return fmt.Errorf("force requires a version\n%s", migrateUsage)
+		}
+		version, err := strconv.Atoi(params[0])
+		if err != nil {
+			return fmt.Errorf("invalid version %q: %w", params[0], err)
+		}
+		if err := mg.Force(version); err != nil {
+			return err
+		}
+	case "check":
+		if err := mg.Check(); err != nil {
+			return err
+		}
+	case "compress-texts":
+		if err := compressStoredTexts(cfg, out); err != nil {
+			return err
+		}
+	case "status":
+	default:
+		return fmt.Errorf("unknown migrate command %q\n%s", command, migrateUsage)
+	}
+
+	status, err := mg.Status()
+	if err != nil {
+		return err
+	}
+	printMigrationStatus(out, status)
+
+	return nil
+}
+
+// compressStoredTexts runs the one-off backfill compressing the texts stored before compression
+func compressStoredTexts(cfg *config.Config, out io.Writer) error {
+	ctx := context.Background()
+
+	db, err := setupDatabase(ctx, cfg, zap.NewNop())
+	if err != nil {
+		return fmt.Errorf("setup database: %w", err)
+	}
+	defer db.Close()
+
+	compressed, err := repository.CompressStoredTexts(ctx, db)
+	if err != nil {
+		return fmt.Errorf("compress stored texts: %w", err)
+	}
+
+	fmt.Fprintf(out, "compressed: %d rows\n", compressed)
+	return nil
+}
+
+// migrateCount parses the optional step count of up/down
+func migrateCount(params []string, defaultCount int) (int, error) {
+	if len(params) == 0 {
+		return defaultCount, nil
+	}
+
+	count, err := strconv.Atoi(params[0])
+	if err != nil || count <= 0 {
+		return 0, fmt.Errorf("invalid step count %q: must be a positive number", params[0])
+	}
+
+	return count, nil
+}
+
+func printMigrationStatus(out io.Writer, status *repository.MigrationStatus) {
+	state := "clean"
+	if status.Dirty {
+		state = "DIRTY"
+	}
+
+	fmt.Fprintf(out, "version: %d (%s)\n", status.Version, state)
+	fmt.Fprintf(out, "latest:  %d\n", status.Latest)
+
+	pending := make([]string, 0, len(status.Pending))
+	for _, version := range status.Pending {
+		pending = append(pending, strconv.FormatUint(uint64(version), 10))
+	}
+	if len(pending) == 0 {
+		fmt.Fprintln(out, "pending: none")
+	} else {
+		fmt.Fprintf(out, "pending: %d (%s)\n", len(pending), strings.Join(pending, ", "))
+	}
+
+	if status.Dirty {
+		fmt.Fprintf(out, "\n%v\n", repository.DirtyMigrationError(status.Version))
+	}
+}
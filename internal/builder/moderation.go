@@ -0,0 +1,42 @@
+package builder
+
+import (
+	"fmt"
+
+	"github.com/futig/agent-backend/internal/config"
+	"github.com/futig/agent-backend/internal/entity"
+	moderationapi "github.com/futig/agent-backend/internal/integration/moderation"
+	"github.com/futig/agent-backend/internal/pkg/moderation"
+	"go.uber.org/zap"
+)
+
+// setupModerator creates the input moderator from word lists and the optional moderation API
+func setupModerator(cfg config.ModerationConfig, logger *zap.Logger) (*moderation.Moderator, error) {
+	if !cfg.Enabled {
+		logger.Info("Input moderation disabled")
+		return moderation.NewModerator(false, entity.ModerationAction(cfg.Action), nil, nil), nil
+	}
+
+	lists, err := moderation.LoadWordLists(cfg.WordListsDir)
+	if err != nil {
+		return nil, fmt.Errorf("load word lists: %w", err)
+	}
+
+	var api moderation.APIChecker
+	if cfg.APIURL != "" {
+		api = moderationapi.NewConnector(cfg, logger)
+	}
+
+	languages := make([]string, 0, len(lists))
+	for lang := range lists {
+		languages = append(languages, lang)
+	}
+
+	logger.Info("Input moderation enabled",
+		zap.String("action", cfg.Action),
+		zap.Strings("languages", languages),
+		zap.Bool("external_api", api != nil),
+	)
+
+	return moderation.NewModerator(true, entity.ModerationAction(cfg.Action), moderation.NewWordFilter(lists), api), nil
+}
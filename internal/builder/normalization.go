@@ -0,0 +1,26 @@
+package builder
+
+import (
+	"github.com/futig/agent-backend/internal/config"
+	"github.com/futig/agent-backend/internal/pkg/normalization"
+	"go.uber.org/zap"
+)
+
+// setupNormalizer creates the voice transcription normalizer; the LLM pass is used in llm mode only
+func setupNormalizer(cfg config.NormalizationConfig, llm normalization.LLMNormalizer, logger *zap.Logger) *normalization.Normalizer {
+	if !cfg.Enabled {
+		logger.Info("Transcript normalization disabled")
+		return normalization.NewNormalizer(false, nil, cfg.MaxLength)
+	}
+
+	if cfg.Mode != "llm" {
+		llm = nil
+	}
+
+	logger.Info("Transcript normalization enabled",
+		zap.String("mode", cfg.Mode),
+		zap.Int("max_length", cfg.MaxLength),
+	)
+
+	return normalization.NewNormalizer(true, llm, cfg.MaxLength)
+}
@@ -0,0 +1,40 @@
+package builder
+
+import (
+	"context"
+
+	"github.com/futig/agent-backend/internal/config"
+	"github.com/futig/agent-backend/internal/integration/blob"
+	"github.com/futig/agent-backend/internal/usecase/session"
+	"go.uber.org/zap"
+)
+
+// setupResultStore creates blob storage for large results; nil keeps every result in Postgres.
+// A lifecycle configuration failure is not fatal, superseded objects are then kept until removed manually.
+func setupResultStore(ctx context.Context, cfg *config.Config, logger *zap.Logger) session.ResultStore {
+	storageCfg := cfg.ResultStorageCfg
+	if !storageCfg.Enabled {
+		logger.Info("Result blob storage disabled, results are stored in Postgres")
+		return nil
+	}
+
+	var store interface {
+		session.ResultStore
+		EnsureLifecycle(ctx context.Context, days int) error
+	}
+	if cfg.EnableMocks {
+		store = blob.NewMockConnector(logger)
+	} else {
+		store = blob.NewConnector(storageCfg, logger)
+	}
+
+	if err := store.EnsureLifecycle(ctx, storageCfg.SupersededDays); err != nil {
+		logger.Warn("failed to configure result storage lifecycle", zap.Error(err))
+	}
+
+	logger.Info("Result blob storage enabled",
+		zap.String("bucket", storageCfg.Bucket),
+		zap.Int("inline_threshold", storageCfg.InlineThreshold),
+	)
+	return store
+}
@@ -0,0 +1,99 @@
+package builder
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/futig/agent-backend/internal/config"
+	"github.com/futig/agent-backend/internal/repository"
+	"github.com/futig/agent-backend/internal/telegram/state"
+)
+
+// backfillStateCommand is the subcommand of the telegram bot that upgrades the stored state data
+const backfillStateCommand = "backfill-state"
+
+const backfillStateUsage = `usage: backfill-state [-dry-run]
+  -dry-run     count the outdated state data without rewriting it`
+
+// IsBackfillStateCommand reports whether the binary was started with the backfill-state subcommand,
+// e.g. `telegram-bot -env prod backfill-state -dry-run`
+func IsBackfillStateCommand(args []string) bool {
+	return isSubcommand(args, backfillStateCommand)
+}
+
+// RunBackfillStateCommand upgrades the telegram state data of all tenants to the current version,
+// run during deploys after the migrations so the new bot reads no outdated state data
+func RunBackfillStateCommand(out io.Writer) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+
+	args := flag.Args()
+	if len(args) == 0 || args[0] != backfillStateCommand {
+		return fmt.Errorf("missing backfill-state command\n%s", backfillStateUsage)
+	}
+
+	flags := flag.NewFlagSet(backfillStateCommand, flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	dryRun := flags.Bool("dry-run", false, "")
+	if err := flags.Parse(args[1:]); err != nil {
+		return fmt.Errorf("%w\n%s", err, backfillStateUsage)
+	}
+	if flags.NArg() > 0 {
+		return fmt.Errorf("unexpected arguments %v\n%s", flags.Args(), backfillStateUsage)
+	}
+
+	logger, err := setupLogger(cfg.LogLevel)
+	if err != nil {
+		return fmt.Errorf("setup logger: %w", err)
+	}
+	defer logger.Sync()
+
+	ctx := context.Background()
+	db, err := setupDatabase(ctx, cfg, logger)
+	if err != nil {
+		return fmt.Errorf("setup database: %w", err)
+	}
+	defer db.Close()
+
+	// The running bot may hold the rewritten sessions in its state cache
+	var cache repository.StateDataCache
+	if cfg.TelegramCfg.StateCache.Enabled && !*dryRun {
+		cachedStorage, err := state.NewRedisCachedStorage(
+			repository.NewTelegramStateRepository(db), cfg.TelegramCfg.Store, cfg.TelegramCfg.StateCache, logger,
+		)
+		if err != nil {
+			return fmt.Errorf("connect state cache: %w", err)
+		}
+		defer cachedStorage.Close()
+		cache = cachedStorage
+	}
+
+	report, err := repository.BackfillStateData(ctx, db, cache, *dryRun, logger)
+	if report != nil {
+		printStateBackfill(out, report, *dryRun)
+	}
+	if err != nil {
+		return err
+	}
+	if report.Failed > 0 {
+		return fmt.Errorf("%d state data blobs could not be upgraded", report.Failed)
+	}
+
+	return nil
+}
+
+func printStateBackfill(out io.Writer, report *repository.StateDataBackfill, dryRun bool) {
+	fmt.Fprintf(out, "version:  %d\n", state.StateDataCurrentVersion)
+	fmt.Fprintf(out, "outdated: %d\n", report.Outdated)
+	if dryRun {
+		fmt.Fprintln(out, "dry run:  nothing rewritten")
+	} else {
+		fmt.Fprintf(out, "upgraded: %d\n", report.Upgraded)
+		fmt.Fprintf(out, "changed:  %d (upgraded on the next read)\n", report.Changed)
+	}
+	fmt.Fprintf(out, "failed:   %d\n", report.Failed)
+}
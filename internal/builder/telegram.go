@@ -0,0 +1,42 @@
+package builder
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/futig/agent-backend/internal/config"
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/repository"
+	"github.com/futig/agent-backend/internal/telegram"
+	"go.uber.org/zap"
+)
+
+// setupNotifier creates the Telegram notifier; users of tenants mapped to one of the configured
+// bots are notified by that bot, everyone else by the bot of TELEGRAM_BOT_TOKEN
+func setupNotifier(
+	ctx context.Context,
+	cfg *config.Config,
+	tenantRepo repository.TenantRepository,
+	logger *zap.Logger,
+) (*telegram.Notifier, error) {
+	tenantTokens := make(map[string]string)
+	for _, bot := range cfg.TelegramBots {
+		if bot.Token == cfg.TelegramCfg.BotToken {
+			continue
+		}
+
+		tenant, err := tenantRepo.GetByBotToken(ctx, bot.Token)
+		if errors.Is(err, entity.ErrTenantNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("resolve tenant of bot '%s': %w", bot.Name, err)
+		}
+		tenantTokens[tenant.ID] = bot.Token
+	}
+
+	logger.Info("Telegram notifier initialized", zap.Int("tenant_bots", len(tenantTokens)))
+
+	return telegram.NewNotifier(&cfg.TelegramCfg, tenantTokens, logger), nil
+}
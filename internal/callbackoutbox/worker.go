@@ -0,0 +1,66 @@
+package callbackoutbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/futig/agent-backend/internal/config"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// Dispatcher sends callback events whose retry is due
+type Dispatcher interface {
+	DispatchPendingCallbacks(ctx context.Context, limit int) (int, error)
+}
+
+// Worker periodically retries callback events kept in the outbox, so events are delivered
+// after the consumer recovers or the process that first sent them went down
+type Worker struct {
+	dispatcher Dispatcher
+	interval   time.Duration
+	batchSize  int
+	logger     *zap.Logger
+}
+
+// New creates a worker retrying callback events every cfg.PollInterval
+func New(cfg config.CallbackOutboxConfig, dispatcher Dispatcher, logger *zap.Logger) *Worker {
+	return &Worker{
+		dispatcher: dispatcher,
+		interval:   cfg.PollInterval,
+		batchSize:  cfg.BatchSize,
+		logger:     logger,
+	}
+}
+
+// Run retries callback events until ctx is cancelled
+func (w *Worker) Run(ctx context.Context) {
+	ctx = ctxzap.ToContext(ctx, w.logger.With(zap.String("component", "callback_outbox")))
+	ctxzap.Info(ctx, "callback outbox dispatcher started", zap.Duration("interval", w.interval))
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		w.tick(ctx)
+
+		select {
+		case <-ctx.Done():
+			ctxzap.Info(ctx, "callback outbox dispatcher stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *Worker) tick(ctx context.Context) {
+	delivered, err := w.dispatcher.DispatchPendingCallbacks(ctx, w.batchSize)
+	if err != nil {
+		ctxzap.Error(ctx, "failed to dispatch callback events", zap.Error(err))
+		return
+	}
+
+	if delivered > 0 {
+		ctxzap.Info(ctx, "retried callback events delivered", zap.Int("count", delivered))
+	}
+}
@@ -6,17 +6,26 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/caarlos0/env/v11"
+	pkgBreaker "github.com/futig/agent-backend/internal/pkg/breaker"
+	pkgEstimate "github.com/futig/agent-backend/internal/pkg/estimate"
+	pkgJobQueue "github.com/futig/agent-backend/internal/pkg/jobqueue"
+	pkgLimiter "github.com/futig/agent-backend/internal/pkg/limiter"
 	pkgRetry "github.com/futig/agent-backend/internal/pkg/retry"
+	pkgStream "github.com/futig/agent-backend/internal/pkg/stream"
+	pkgTracing "github.com/futig/agent-backend/internal/pkg/tracing"
 	"github.com/joho/godotenv"
 )
 
 // Config holds the application configuration
 type Config struct {
 	// Server configuration
-	ServerAddr string `env:"SERVER_ADDR,notEmpty"`
+	ServerAddr       string        `env:"SERVER_ADDR,notEmpty"`
+	SyncStartTimeout time.Duration `env:"SYNC_START_TIMEOUT" envDefault:"25s"` // must stay below the 60s router timeout
+	MetricsAddr      string        `env:"METRICS_ADDR"`                        // serves Prometheus /metrics, empty disables it
 
 	// Database configuration
 	DatabaseURL         string        `env:"DATABASE_URL,notEmpty"`
@@ -26,6 +35,9 @@ type Config struct {
 	DBMaxConnIdleTime   time.Duration `env:"DB_MAX_CONN_IDLE_TIME" envDefault:"30m"`
 	DBHealthCheckPeriod time.Duration `env:"DB_HEALTH_CHECK_PERIOD" envDefault:"1m"`
 
+	// Schema migrations configuration
+	MigrationsCfg MigrationsConfig `envPrefix:"MIGRATIONS_"`
+
 	// External service configurations
 	RAGConnectorCfg      RAGConnectorConfig      `envPrefix:"RAG_"`
 	LLMConnectorCfg      LLMConnectorConfig      `envPrefix:"LLM_"`
@@ -38,9 +50,111 @@ type Config struct {
 	// File upload configuration
 	FileUploadCfg FileUploadConfig `envPrefix:"FILE_UPLOAD_"`
 
+	// Input moderation configuration
+	ModerationCfg ModerationConfig `envPrefix:"MODERATION_"`
+
+	// Voice transcription normalization configuration
+	NormalizationCfg NormalizationConfig `envPrefix:"NORMALIZATION_"`
+
+	// Generation pre-estimate configuration
+	EstimateCfg pkgEstimate.Config `envPrefix:"ESTIMATE_"`
+
+	// Result approval workflow configuration
+	ReviewCfg ReviewConfig `envPrefix:"REVIEW_"`
+
+	// Scheduled check-in sessions configuration
+	SchedulerCfg SchedulerConfig `envPrefix:"SCHEDULER_"`
+
+	// Async operations polling configuration
+	OperationsCfg OperationsConfig `envPrefix:"OPERATIONS_"`
+
+	// Persistent outbox of callback events
+	CallbackOutboxCfg CallbackOutboxConfig `envPrefix:"CALLBACK_OUTBOX_"`
+
+	// Async job queue configuration
+	JobQueueCfg pkgJobQueue.Config `envPrefix:"JOB_QUEUE_"`
+
+	// WebSocket session progress streams configuration
+	StreamCfg pkgStream.Config `envPrefix:"STREAM_"`
+
+	// Sandbox demo sessions configuration
+	DemoCfg DemoConfig `envPrefix:"DEMO_"`
+
+	// Interview time-boxing configuration
+	TimeBudgetCfg TimeBudgetConfig `envPrefix:"TIME_BUDGET_"`
+
+	// Session heartbeats of external orchestrators configuration
+	HeartbeatCfg HeartbeatConfig `envPrefix:"HEARTBEAT_"`
+
+	// Voice answers queued while speech recognition is unavailable
+	VoiceQueueCfg VoiceQueueConfig `envPrefix:"VOICE_QUEUE_"`
+
+	// Interview conversation log configuration
+	ConversationLogCfg ConversationLogConfig `envPrefix:"CONVERSATION_LOG_"`
+
+	// Context snapshot of already provided facts configuration
+	ContextSnapshotCfg ContextSnapshotConfig `envPrefix:"CONTEXT_SNAPSHOT_"`
+
+	// Descriptions of projects created from saved requirements
+	ProjectDescriptionCfg ProjectDescriptionConfig `envPrefix:"PROJECT_DESCRIPTION_"`
+
+	// Freshness of the indexed materials of projects
+	ProjectFreshnessCfg ProjectFreshnessConfig `envPrefix:"PROJECT_FRESHNESS_"`
+
+	// Cache of the project lists of the Telegram project selector
+	ProjectListCacheCfg ProjectListCacheConfig `envPrefix:"PROJECT_LIST_CACHE_"`
+
+	// User goal quality gate configuration
+	GoalQualityCfg GoalQualityConfig `envPrefix:"GOAL_QUALITY_"`
+
+	// Links of API clients to Telegram users configuration
+	AccountLinkCfg AccountLinkConfig `envPrefix:"ACCOUNT_LINK_"`
+
+	// Cross-process locks of sessions configuration
+	SessionLockCfg SessionLockConfig `envPrefix:"SESSION_LOCK_"`
+
+	// Deduplication of generated questions configuration
+	QuestionDedupCfg QuestionDedupConfig `envPrefix:"QUESTION_DEDUP_"`
+
+	// Collected materials fallback of failing generations configuration
+	GenerationFallbackCfg GenerationFallbackConfig `envPrefix:"GENERATION_FALLBACK_"`
+
+	// Incident codes shown to users on errors configuration
+	IncidentsCfg IncidentsConfig `envPrefix:"INCIDENTS_"`
+
+	// Gradual rollouts of risky capabilities
+	FeatureFlagsCfg FeatureFlagsConfig `envPrefix:"FEATURE_FLAGS_"`
+
+	// Generated results blob storage configuration
+	ResultStorageCfg ResultStorageConfig `envPrefix:"RESULT_STORAGE_"`
+
+	// Multi-tenant isolation configuration
+	TenancyCfg TenancyConfig `envPrefix:"TENANCY_"`
+
+	// Usage quotas per user and tenant
+	QuotaCfg QuotaConfig `envPrefix:"QUOTA_"`
+
+	// Anonymized analytics export configuration
+	AnalyticsCfg AnalyticsConfig `envPrefix:"ANALYTICS_"`
+
+	// Purge of session content past the retention period of its tenant
+	ContentRetentionCfg ContentRetentionConfig `envPrefix:"CONTENT_RETENTION_"`
+
+	// On-demand question explanations, their cache and the cooldown of the users requesting them
+	ExplanationCfg ExplanationConfig `envPrefix:"EXPLANATION_"`
+
+	// OpenTelemetry tracing of the API and the bot, exported over OTLP/HTTP
+	TracingCfg pkgTracing.Config `envPrefix:"TRACING_"`
+
+	// Admin API token (admin endpoints are disabled when empty)
+	AdminToken string `env:"ADMIN_TOKEN"`
+
 	// Context questions configuration (loaded from JSON file)
 	ContextQuestions []string
 
+	// Telegram bots served by the bot process: the configured bot followed by the bots of TELEGRAM_BOTS_FILE
+	TelegramBots []TelegramBotConfig
+
 	// Mock configuration
 	EnableMocks bool `env:"ENABLE_MOCKS,notEmpty"`
 
@@ -61,37 +175,235 @@ type TelegramConfig struct {
 	MaxDraftMessages      int    `env:"MAX_DRAFT_MESSAGES,notEmpty"`
 	RateLimitPerMinute    int    `env:"RATE_LIMIT_PER_MINUTE,notEmpty"`
 	RateLimitBurst        int    `env:"RATE_LIMIT_BURST,notEmpty"`
+	// RateLimit weighs updates and adapts the per-user limits of the adaptive limiter
+	RateLimit TelegramRateLimitConfig `envPrefix:"RATE_LIMIT_"`
 	ShutdownTimeout       int    `env:"SHUTDOWN_TIMEOUT,notEmpty"` // seconds
+	// QuestionNumbering is the default numbering of questions in bot messages: block or global
+	QuestionNumbering     string `env:"QUESTION_NUMBERING" envDefault:"block"`
+	// ProgressHeader shows the block, question and answered counts above questions unless a user hid it
+	ProgressHeader bool `env:"PROGRESS_HEADER" envDefault:"true"`
+	// MediaGroupWindow is how long the bot waits for further items of an album before processing it
+	MediaGroupWindow time.Duration `env:"MEDIA_GROUP_WINDOW" envDefault:"1500ms"`
+	// KeepForwardMetadata prepends the original sender and date to forwarded draft materials
+	KeepForwardMetadata bool `env:"KEEP_FORWARD_METADATA" envDefault:"true"`
+	// WebhookListenAddr is the address of the webhook server shared by all bots of the process
+	WebhookListenAddr string `env:"WEBHOOK_LISTEN_ADDR" envDefault:":8443"`
+	// WebhookSecret is the secret token Telegram sends with every webhook update;
+	// empty derives one from the bot token so that all replicas of a bot agree on it
+	WebhookSecret string `env:"WEBHOOK_SECRET"`
+	// BotsFile is a JSON file with additional bots served by the same process
+	BotsFile string `env:"BOTS_FILE"`
+	// Branding texts of the bot; empty texts keep the built-in ones
+	Branding TelegramBranding `envPrefix:"BRANDING_"`
+	// AdminIDs are Telegram user IDs of support operators allowed to take over user sessions
+	AdminIDs []int64 `env:"ADMIN_IDS"`
+	// APIEndpoint is the Bot API endpoint in the "https://api.telegram.org/bot%s/%s" format,
+	// set to run the bot against a fake API; empty uses the Telegram one
+	APIEndpoint string `env:"API_ENDPOINT"`
+	// FileEndpoint is the file download endpoint in the "https://api.telegram.org/file/bot%s/%s" format,
+	// set together with APIEndpoint; empty uses the Telegram one
+	FileEndpoint string `env:"FILE_ENDPOINT"`
+	// HandlerTimeout bounds the handling of one update, so a hung call does not hold its goroutine forever
+	HandlerTimeout time.Duration `env:"HANDLER_TIMEOUT" envDefault:"2m"`
+	// GenerationTimeout replaces HandlerTimeout in the handler states that can start requirement generation
+	GenerationTimeout time.Duration `env:"GENERATION_TIMEOUT" envDefault:"15m"`
+	// HandlerTimeouts overrides the timeout of single handler states, e.g. "ASK_USER_GOAL:1m,COMMAND:30s"
+	HandlerTimeouts map[string]time.Duration `env:"HANDLER_TIMEOUTS"`
+	// MetricsAddr is the address serving the bot process metrics on /metrics; empty disables it
+	MetricsAddr string `env:"METRICS_ADDR"`
+	// Store keeps the rate limits, recent button presses and operations in flight of the bots
+	Store TelegramStoreConfig `envPrefix:"STORE_"`
+	// StateCache keeps the telegram sessions in the Redis of Store in front of Postgres
+	StateCache TelegramStateCacheConfig `envPrefix:"STATE_CACHE_"`
+	// DefaultTimezone is the IANA timezone of users who have not chosen one and whose language gives no guess
+	DefaultTimezone string `env:"DEFAULT_TIMEZONE" envDefault:"UTC"`
+	// QuestionButtons turns off buttons of the question keyboard
+	QuestionButtons TelegramQuestionButtons `envPrefix:"QUESTION_BUTTONS_"`
+}
+
+// Rate limiter modes of the bot
+const (
+	RateLimitModeAdaptive = "adaptive"
+	RateLimitModeSimple   = "simple"
+)
+
+// TelegramRateLimitConfig configures the adaptive limiter of bot updates. Updates cost tokens of the
+// RATE_LIMIT_PER_MINUTE bucket by their weight, the bucket of a user grows and shrinks with the user's
+// reputation, and LLM work costs more while handlers of the bot time out
+type TelegramRateLimitConfig struct {
+	// Mode is adaptive or simple, the fixed bucket where every update costs one token
+	Mode string `env:"MODE" envDefault:"adaptive"`
+	// LLMCost is the weight of updates that start LLM, transcription or RAG work
+	LLMCost float64 `env:"LLM_COST" envDefault:"3"`
+	// NavigationCost is the weight of buttons that only move between steps
+	NavigationCost float64 `env:"NAVIGATION_COST" envDefault:"0.25"`
+	// MinReputation and MaxReputation bound the factor of a user's bucket size and refill rate
+	MinReputation float64 `env:"MIN_REPUTATION" envDefault:"0.5"`
+	MaxReputation float64 `env:"MAX_REPUTATION" envDefault:"1.5"`
+}
+
+// Store backends of the bot state shared between updates
+const (
+	StoreBackendMemory = "memory"
+	StoreBackendRedis  = "redis"
+)
+
+// TelegramStoreConfig selects where the bots keep the rate limits of users, recent button presses and
+// operations in flight. The memory store loses them on restart; Redis keeps them and shares them between replicas
+type TelegramStoreConfig struct {
+	// Backend is memory or redis
+	Backend       string `env:"BACKEND" envDefault:"memory"`
+	RedisAddr     string `env:"REDIS_ADDR" envDefault:"localhost:6379"`
+	RedisPassword string `env:"REDIS_PASSWORD"`
+	RedisDB       int    `env:"REDIS_DB" envDefault:"0"`
+	// KeyPrefix is prepended to the Redis keys, so several deployments can share one Redis
+	KeyPrefix string `env:"KEY_PREFIX" envDefault:"agent:telegram:"`
+	// CallbackDedupWindow is how long repeated presses of the same button are ignored; 0 disables it
+	CallbackDedupWindow time.Duration `env:"CALLBACK_DEDUP_WINDOW" envDefault:"2s"`
+}
+
+// TelegramStateCacheConfig enables the Redis cache of the telegram sessions; writes still go to Postgres first
+type TelegramStateCacheConfig struct {
+	Enabled bool `env:"ENABLED" envDefault:"false"`
+	// TTL bounds how long a cached state lives without being read from Postgres again
+	TTL time.Duration `env:"TTL" envDefault:"10m"`
+}
+
+// TelegramBranding holds texts that differ between brands served by one process
+type TelegramBranding struct {
+	WelcomeText string `env:"WELCOME_TEXT" json:"welcome_text,omitempty"`
+	HelpHeader  string `env:"HELP_HEADER" json:"help_header,omitempty"`
+}
+
+// TelegramQuestionButtons turns off buttons of the question keyboard by name: scale, skip, explain,
+// defer, answers, previous, search, script, generate and finish. Callbacks of turned off buttons are rejected
+type TelegramQuestionButtons struct {
+	// Disabled buttons are left out in sessions of every type without an own list, e.g. "skip,generate"
+	Disabled []string `env:"DISABLED" json:"disabled,omitempty"`
+	// ByType replaces Disabled for single session types with "|"-separated lists, "none" keeps every
+	// button, e.g. "DRAFT:none,DELTA:skip|defer"
+	ByType map[string]string `env:"BY_TYPE" json:"by_type,omitempty"`
+}
+
+// DisabledByType splits the lists of ByType into button names
+func (c TelegramQuestionButtons) DisabledByType() map[string][]string {
+	byType := make(map[string][]string, len(c.ByType))
+	for sessionType, list := range c.ByType {
+		names := []string{}
+		if list != "none" {
+			names = strings.Split(list, "|")
+		}
+		byType[sessionType] = names
+	}
+	return byType
+}
+
+// defaultBotName is the name of the bot configured with TELEGRAM_BOT_TOKEN
+const defaultBotName = "default"
+
+// TelegramBotConfig describes one bot of the bot process; unset fields inherit TelegramConfig
+type TelegramBotConfig struct {
+	Name               string           `json:"name"`
+	Token              string           `json:"token"`
+	WebhookPath        string           `json:"webhook_path,omitempty"` // defaults to /telegram/<name>
+	WebhookSecret      string           `json:"webhook_secret,omitempty"`
+	ContextQuestions   []string         `json:"context_questions,omitempty"`
+	MaxDraftMessages   int              `json:"max_draft_messages,omitempty"`
+	RateLimitPerMinute int              `json:"rate_limit_per_minute,omitempty"`
+	RateLimitBurst     int              `json:"rate_limit_burst,omitempty"`
+	Branding           TelegramBranding `json:"branding"`
+	// QuestionButtons replaces TELEGRAM_QUESTION_BUTTONS_* for the bot
+	QuestionButtons *TelegramQuestionButtons `json:"question_buttons,omitempty"`
+}
+
+// ForBot returns the configuration of one bot with its overrides applied
+func (c TelegramConfig) ForBot(bot TelegramBotConfig) TelegramConfig {
+	c.BotToken = bot.Token
+	if bot.WebhookSecret != "" {
+		c.WebhookSecret = bot.WebhookSecret
+	}
+	if bot.MaxDraftMessages > 0 {
+		c.MaxDraftMessages = bot.MaxDraftMessages
+	}
+	if bot.RateLimitPerMinute > 0 {
+		c.RateLimitPerMinute = bot.RateLimitPerMinute
+	}
+	if bot.RateLimitBurst > 0 {
+		c.RateLimitBurst = bot.RateLimitBurst
+	}
+	if bot.Branding.WelcomeText != "" {
+		c.Branding.WelcomeText = bot.Branding.WelcomeText
+	}
+	if bot.Branding.HelpHeader != "" {
+		c.Branding.HelpHeader = bot.Branding.HelpHeader
+	}
+	if bot.QuestionButtons != nil {
+		c.QuestionButtons = *bot.QuestionButtons
+	}
+	return c
 }
 
 type RAGConnectorConfig struct {
 	HTTPClientConfig
-	IndexEndpoint   string               `env:"INDEX_ENDPOINT,notEmpty"`
-	DeleteEndpoint  string               `env:"DELETE_ENDPOINT,notEmpty"`
-	ContextEndpoint string               `env:"CONTEXT_ENDPOINT,notEmpty"`
-	Retry           pkgRetry.RetryConfig `envPrefix:"RETRY_"`
+	IndexEndpoint      string               `env:"INDEX_ENDPOINT,notEmpty"`
+	DeleteEndpoint     string               `env:"DELETE_ENDPOINT,notEmpty"`
+	DeleteFileEndpoint string               `env:"DELETE_FILE_ENDPOINT" envDefault:"/v1/rag/project/{project_id}/files/{filename}"` // {project_id} and {filename} are substituted
+	ContextEndpoint    string               `env:"CONTEXT_ENDPOINT,notEmpty"`
+	Retry              pkgRetry.RetryConfig `envPrefix:"RETRY_"`
 }
 
 type LLMConnectorConfig struct {
 	HTTPClientConfig
-	GenerateQuestionsEndpoint    string               `env:"GENERATE_QUESTIONS_ENDPOINT,notEmpty"`
-	ValidateAnswersEndpoint      string               `env:"VALIDATE_ANSWERS_ENDPOINT,notEmpty"`
-	GenerateSummaryEndpoint      string               `env:"GENERATE_SUMMARY_ENDPOINT,notEmpty"`
-	ValidateDraftEndpoint        string               `env:"VALIDATE_DRAFT_ENDPOINT,notEmpty"`
-	GenerateDraftSummaryEndpoint string               `env:"GENERATE_DRAFT_SUMMARY_ENDPOINT,notEmpty"`
-	Retry                        pkgRetry.RetryConfig `envPrefix:"RETRY_"`
+	GenerateQuestionsEndpoint      string               `env:"GENERATE_QUESTIONS_ENDPOINT,notEmpty"`
+	ValidateAnswersEndpoint        string               `env:"VALIDATE_ANSWERS_ENDPOINT,notEmpty"`
+	GenerateSummaryEndpoint        string               `env:"GENERATE_SUMMARY_ENDPOINT,notEmpty"`
+	GenerateSummaryStreamEndpoint  string               `env:"GENERATE_SUMMARY_STREAM_ENDPOINT,notEmpty"`
+	ValidateDraftEndpoint          string               `env:"VALIDATE_DRAFT_ENDPOINT,notEmpty"`
+	GenerateDraftSummaryEndpoint   string               `env:"GENERATE_DRAFT_SUMMARY_ENDPOINT,notEmpty"`
+	GenerateOutlineEndpoint        string               `env:"GENERATE_OUTLINE_ENDPOINT,notEmpty"`
+	GenerateSectionEndpoint        string               `env:"GENERATE_SECTION_ENDPOINT,notEmpty"`
+	RefineResultEndpoint           string               `env:"REFINE_RESULT_ENDPOINT,notEmpty"`
+	RestyleResultEndpoint          string               `env:"RESTYLE_RESULT_ENDPOINT,notEmpty"`
+	GenerateDeltaQuestionsEndpoint string               `env:"GENERATE_DELTA_QUESTIONS_ENDPOINT,notEmpty"`
+	GenerateDeltaSummaryEndpoint   string               `env:"GENERATE_DELTA_SUMMARY_ENDPOINT,notEmpty"`
+	DetectConflictsEndpoint        string               `env:"DETECT_CONFLICTS_ENDPOINT,notEmpty"`
+	TranslateEndpoint              string               `env:"TRANSLATE_ENDPOINT,notEmpty"`
+	NormalizeTranscriptEndpoint    string               `env:"NORMALIZE_TRANSCRIPT_ENDPOINT,notEmpty"`
+	DescribeProjectEndpoint        string               `env:"DESCRIBE_PROJECT_ENDPOINT,notEmpty"`
+	ExtractFactsEndpoint           string               `env:"EXTRACT_FACTS_ENDPOINT,notEmpty"`
+	ExplainQuestionEndpoint        string               `env:"EXPLAIN_QUESTION_ENDPOINT"` // required with EXPLANATION_ON_DEMAND
+	Retry                          pkgRetry.RetryConfig `envPrefix:"RETRY_"`
+	Limits                         pkgLimiter.Config    `envPrefix:"LIMIT_"`
+	// StreamTimeout bounds a streamed summary instead of TIMEOUT, which covers the whole response body
+	StreamTimeout time.Duration `env:"STREAM_TIMEOUT" envDefault:"10m"`
 }
 
 type ASRConnectorConfig struct {
 	HTTPClientConfig
 	TranscribeEndpoint string               `env:"TRANSCRIBE_ENDPOINT,notEmpty"`
 	Retry              pkgRetry.RetryConfig `envPrefix:"RETRY_"`
+	Breaker            pkgBreaker.Config    `envPrefix:"BREAKER_"`
+	// MockTranscriptsFile holds canned transcripts of the mock connector for deterministic E2E runs
+	MockTranscriptsFile string `env:"MOCK_TRANSCRIPTS_FILE"`
+	// MockTranscripts are loaded from MockTranscriptsFile
+	MockTranscripts []MockTranscript
+}
+
+// MockTranscript is a canned transcript of the mock ASR connector for audio with the file name or the size
+type MockTranscript struct {
+	FileName string `json:"file_name,omitempty"`
+	Size     int    `json:"size,omitempty"` // bytes of the audio sent to ASR, i.e. WAV after conversion for Telegram voices
+	Text     string `json:"text"`
+	// FailAttempts makes the first attempts for the audio fail as if the service were unavailable,
+	// so retries and queued voice answers can be exercised
+	FailAttempts int `json:"fail_attempts,omitempty"`
 }
 
 type CallbackConnectorConfig struct {
 	HTTPClientConfig
 	CallbackEndpoint string               `env:"ENDPOINT,notEmpty"`
 	Retry            pkgRetry.RetryConfig `envPrefix:"RETRY_"`
+	SchemaVersion    int                  `env:"SCHEMA_VERSION" envDefault:"1"` // payload schema for consumers without X-Callback-Schema-Version
 }
 
 type HTTPClientConfig struct {
@@ -102,18 +414,250 @@ type HTTPClientConfig struct {
 	ResponseHeaderTimeout time.Duration `env:"RESPONSE_HEADER_TIMEOUT,notEmpty"`
 	Token                 string        `env:"TOKEN"`
 	Url                   string        `env:"SERVICE_URL,notEmpty"`
+	MaxIdleConnsPerHost   int           `env:"MAX_IDLE_CONNS_PER_HOST" envDefault:"10"`
+	Warmup                WarmupConfig  `envPrefix:"WARMUP_"`
+}
+
+// WarmupConfig sets up the connections of a connector before requests need them: a ping on startup
+// and after idle periods keeps a connection in the pool, resolved addresses skip DNS lookups
+type WarmupConfig struct {
+	Enabled  bool   `env:"ENABLED" envDefault:"false"`
+	Endpoint string `env:"ENDPOINT" envDefault:"/"` // any HTTP status of the response counts
+	// IdleInterval is the time without requests after which the connector is pinged again; keep it
+	// below IDLE_CONN_TIMEOUT so the pooled connection is not closed. 0 pings only on startup
+	IdleInterval time.Duration `env:"IDLE_INTERVAL" envDefault:"0"`
+	// DNSRefresh is how long resolved addresses are used before they are resolved again; 0 resolves
+	// on every new connection
+	DNSRefresh time.Duration `env:"DNS_REFRESH" envDefault:"0"`
 }
 
 // FileUploadConfig holds file upload limits
 type FileUploadConfig struct {
-	MaxFileSize      int64 `env:"MAX_FILE_SIZE,notEmpty"`       // 5 MiB
-	MaxTotalSize     int64 `env:"MAX_TOTAL_SIZE,notEmpty"`      // 25 MiB
-	MaxFileCount     int   `env:"MAX_FILE_COUNT,notEmpty"`      // Max 64 files
-	MaxAudioFileSize int64 `env:"MAX_AUDIO_FILE_SIZE,notEmpty"` // 25 MiB
-	MaxUploadSize    int64 `env:"MAX_UPLOAD_SIZE,notEmpty"`     // 32 MB
+	MaxFileSize       int64 `env:"MAX_FILE_SIZE,notEmpty"`                   // 5 MiB
+	MaxTotalSize      int64 `env:"MAX_TOTAL_SIZE,notEmpty"`                  // 25 MiB
+	MaxFileCount      int   `env:"MAX_FILE_COUNT,notEmpty"`                  // Max 64 files
+	MaxAudioFileSize  int64 `env:"MAX_AUDIO_FILE_SIZE,notEmpty"`             // 25 MiB
+	MaxUploadSize     int64 `env:"MAX_UPLOAD_SIZE,notEmpty"`                 // 32 MB
+	MaxTranscriptSize int64 `env:"MAX_TRANSCRIPT_SIZE" envDefault:"1048576"` // 1 MiB, transcripts of one-shot sessions
+}
+
+// ModerationConfig holds user input moderation settings
+type ModerationConfig struct {
+	Enabled      bool          `env:"ENABLED" envDefault:"false"`
+	Action       string        `env:"ACTION" envDefault:"warn"` // warn, redact or block
+	WordListsDir string        `env:"WORD_LISTS_DIR" envDefault:"internal/config/moderation"`
+	APIURL       string        `env:"API_URL"` // optional external moderation service
+	APIEndpoint  string        `env:"API_ENDPOINT" envDefault:"/moderate"`
+	APIToken     string        `env:"API_TOKEN"`
+	APITimeout   time.Duration `env:"API_TIMEOUT" envDefault:"5s"`
+}
+
+// NormalizationConfig holds the spell-checking pass applied to voice transcriptions before storage
+type NormalizationConfig struct {
+	Enabled   bool   `env:"ENABLED" envDefault:"false"`
+	Mode      string `env:"MODE" envDefault:"rules"`      // rules or llm
+	MaxLength int    `env:"MAX_LENGTH" envDefault:"8000"` // longer transcriptions get the rules pass only
+}
+
+// ReviewConfig holds result approval workflow settings
+type ReviewConfig struct {
+	RequireApproval bool `env:"REQUIRE_APPROVAL" envDefault:"false"` // block project save and export until approved
+}
+
+// SchedulerConfig holds scheduled check-in sessions settings
+type SchedulerConfig struct {
+	Enabled      bool          `env:"ENABLED" envDefault:"true"`
+	PollInterval time.Duration `env:"POLL_INTERVAL" envDefault:"1m"`
+}
+
+// OperationsConfig holds retention settings of pollable async operations
+type OperationsConfig struct {
+	Retention       time.Duration `env:"RETENTION" envDefault:"168h"`
+	CleanupInterval time.Duration `env:"CLEANUP_INTERVAL" envDefault:"1h"`
+}
+
+// CallbackOutboxConfig holds settings of the outbox keeping callback events until they are delivered.
+// Failed deliveries are retried after BaseDelay, doubled on every attempt up to MaxDelay
+type CallbackOutboxConfig struct {
+	Enabled         bool          `env:"ENABLED" envDefault:"false"`
+	PollInterval    time.Duration `env:"POLL_INTERVAL" envDefault:"5s"`
+	BatchSize       int           `env:"BATCH_SIZE" envDefault:"50"`
+	MaxAttempts     int           `env:"MAX_ATTEMPTS" envDefault:"10"`
+	BaseDelay       time.Duration `env:"BASE_DELAY" envDefault:"5s"`
+	MaxDelay        time.Duration `env:"MAX_DELAY" envDefault:"30m"`
+	Lease           time.Duration `env:"LEASE" envDefault:"1m"`       // an attempt not settled by then is made again
+	Retention       time.Duration `env:"RETENTION" envDefault:"168h"` // delivered and failed events are removed after
+	CleanupInterval time.Duration `env:"CLEANUP_INTERVAL" envDefault:"1h"`
+}
+
+// TenancyConfig controls how API requests are mapped to tenants
+type TenancyConfig struct {
+	// RequireAPIKey rejects requests without X-API-Key instead of serving them as the default tenant
+	RequireAPIKey bool `env:"REQUIRE_API_KEY" envDefault:"false"`
+}
+
+// Startup behaviours for pending schema migrations
+const (
+	MigrationsOnStartApply = "apply" // apply pending migrations before starting
+	MigrationsOnStartCheck = "check" // refuse to start while migrations are pending or dirty
+)
+
+// MigrationsConfig holds schema migration settings
+type MigrationsConfig struct {
+	Dir     string `env:"DIR" envDefault:"internal/repository/migrations"`
+	OnStart string `env:"ON_START" envDefault:"apply"`
+}
+
+// DemoConfig holds lifetime settings of sandbox demo sessions
+type DemoConfig struct {
+	SessionTTL      time.Duration `env:"SESSION_TTL" envDefault:"2h"`
+	CleanupInterval time.Duration `env:"CLEANUP_INTERVAL" envDefault:"10m"`
+}
+
+// TimeBudgetConfig holds interview time-boxing settings
+type TimeBudgetConfig struct {
+	Default       time.Duration `env:"DEFAULT" envDefault:"0s"`         // 0 leaves interviews without a budget unless a session sets one
+	WarnThreshold float64       `env:"WARN_THRESHOLD" envDefault:"0.8"` // share of the budget after which the user is warned
+}
+
+// HeartbeatConfig holds rate limits of session heartbeats
+type HeartbeatConfig struct {
+	MinInterval time.Duration `env:"MIN_INTERVAL" envDefault:"10s"` // heartbeats sooner than this after the previous one are rejected
+}
+
+// VoiceQueueConfig holds settings of voice answers queued while speech recognition is unavailable
+type VoiceQueueConfig struct {
+	Enabled      bool          `env:"ENABLED" envDefault:"false"`
+	PollInterval time.Duration `env:"POLL_INTERVAL" envDefault:"30s"`
+	BatchSize    int           `env:"BATCH_SIZE" envDefault:"20"`
+	MaxAge       time.Duration `env:"MAX_AGE" envDefault:"24h"` // answers not submitted by then are dropped
+}
+
+// ConversationLogConfig controls the interview transcript sent along with validation and generation requests
+type ConversationLogConfig struct {
+	InPrompts  bool `env:"IN_PROMPTS" envDefault:"false"`
+	MaxEntries int  `env:"MAX_ENTRIES" envDefault:"50"`  // latest entries sent to the LLM
+	MaxChars   int  `env:"MAX_CHARS" envDefault:"12000"` // older entries are dropped once the transcript grows past this
+}
+
+// ContextSnapshotConfig controls the facts already provided in a session, extracted by the LLM and sent
+// along with validation requests so that additional questions skip the covered topics
+type ContextSnapshotConfig struct {
+	Enabled  bool `env:"ENABLED" envDefault:"false"`
+	MaxFacts int  `env:"MAX_FACTS" envDefault:"40"` // facts kept per session
+}
+
+// ProjectDescriptionConfig controls generation of descriptions for projects created from saved requirements
+type ProjectDescriptionConfig struct {
+	AutoGenerate bool `env:"AUTO_GENERATE" envDefault:"false"`
+	MaxChars     int  `env:"MAX_CHARS" envDefault:"500"`
+}
+
+// ProjectFreshnessConfig controls when the indexed materials of a project are reported as stale
+type ProjectFreshnessConfig struct {
+	StaleAfter time.Duration `env:"STALE_AFTER" envDefault:"2160h"` // 0 never reports materials as stale
+}
+
+// ProjectListCacheConfig controls the cache of the project pages shown by the Telegram project selector
+type ProjectListCacheConfig struct {
+	TTL        time.Duration `env:"TTL" envDefault:"30s"`          // 0 disables the cache
+	MaxEntries int           `env:"MAX_ENTRIES" envDefault:"10000"` // cached pages of all users of a replica
+}
+
+// GoalQualityConfig controls the clarifying question asked for too vague user goals
+type GoalQualityConfig struct {
+	MinWords int `env:"MIN_WORDS" envDefault:"3"` // goals with fewer words get one clarifying question; 0 disables the check
+}
+
+// AccountLinkConfig controls the one-time codes the bot issues to link another client to the user's session
+type AccountLinkConfig struct {
+	CodeTTL time.Duration `env:"CODE_TTL" envDefault:"10m"`
+}
+
+// SessionLockConfig controls the locks serializing the actions on a session of the bot and the HTTP API
+type SessionLockConfig struct {
+	WaitTimeout time.Duration `env:"WAIT_TIMEOUT" envDefault:"30s"` // an action waiting longer fails with a busy session; 0 disables the locks
+	// OperationTTL is how long a heavy operation, e.g. a generation, keeps its session from other ones after
+	// its last renewal; renewed every third of it, so a dead process frees the session within it. 0 disables the check
+	OperationTTL time.Duration `env:"OPERATION_TTL" envDefault:"2m"`
+}
+
+// QuestionDedupConfig controls merging of near-duplicate questions generated in different blocks
+type QuestionDedupConfig struct {
+	Threshold float64 `env:"THRESHOLD" envDefault:"0.8"` // word similarity from 0 to 1 from which questions are merged; 0 disables the pass
+}
+
+// GenerationFallbackConfig controls saving the collected materials as a PARTIAL result when the final generation keeps failing
+type GenerationFallbackConfig struct {
+	MaxFailures int `env:"MAX_FAILURES" envDefault:"3"` // failed generations in a row before the fallback; 0 disables it
+}
+
+// IncidentsConfig holds retention settings of the incidents recorded for errors; users quote their codes to support
+type IncidentsConfig struct {
+	Retention       time.Duration `env:"RETENTION" envDefault:"720h"`
+	CleanupInterval time.Duration `env:"CLEANUP_INTERVAL" envDefault:"1h"`
+}
+
+// ContentRetentionConfig holds the default retention of session content; tenants override Days in their settings
+type ContentRetentionConfig struct {
+	Days            int           `env:"DAYS" envDefault:"0"` // days without activity before the content is purged; 0 keeps it
+	CleanupInterval time.Duration `env:"CLEANUP_INTERVAL" envDefault:"1h"`
+}
+
+// ExplanationConfig controls explanations generated when the user asks for them instead of the ones
+// returned with the questions; generated explanations are cached per question
+type ExplanationConfig struct {
+	OnDemand bool          `env:"ON_DEMAND" envDefault:"false"`
+	Cooldown time.Duration `env:"COOLDOWN" envDefault:"30s"` // between explanations generated for one user; 0 disables it
+}
+
+// FeatureFlagsConfig holds the configured rollouts of feature flags; admin overrides stored in the database take precedence
+type FeatureFlagsConfig struct {
+	Rollouts        map[string]int `env:"ROLLOUTS" envKeyValSeparator:":"`   // e.g. streaming:10,hybrid_mode:50 (percent of sessions)
+	RefreshInterval time.Duration  `env:"REFRESH_INTERVAL" envDefault:"30s"` // how often the database overrides are reloaded
+}
+
+// QuotaConfig holds usage quotas of users and tenants; a zero limit disables the quota.
+// Sessions are counted per UTC day and generations per UTC month
+type QuotaConfig struct {
+	UserSessionsPerDay        int     `env:"USER_SESSIONS_PER_DAY" envDefault:"0"`
+	TenantSessionsPerDay      int     `env:"TENANT_SESSIONS_PER_DAY" envDefault:"0"`
+	UserGenerationsPerMonth   int     `env:"USER_GENERATIONS_PER_MONTH" envDefault:"0"`
+	TenantGenerationsPerMonth int     `env:"TENANT_GENERATIONS_PER_MONTH" envDefault:"0"`
+	WarnThreshold             float64 `env:"WARN_THRESHOLD" envDefault:"0.8"` // share of a quota after which the user is warned
+}
+
+// AnalyticsConfig holds settings of the anonymized question-answer analytics export. Without a salt
+// the hashes of an export are keyed randomly and cannot be joined with those of another export
+type AnalyticsConfig struct {
+	HashSalt string        `env:"HASH_SALT"`
+	MaxRows  int           `env:"MAX_ROWS" envDefault:"100000"` // rows of a single export
+	MaxRange time.Duration `env:"MAX_RANGE" envDefault:"2208h"` // longest period of a single export
+}
+
+// ResultStorageConfig holds S3-compatible storage settings for large generated results
+type ResultStorageConfig struct {
+	Enabled         bool          `env:"ENABLED" envDefault:"false"`
+	Endpoint        string        `env:"ENDPOINT"` // e.g. https://s3.eu-central-1.amazonaws.com or http://minio:9000
+	Region          string        `env:"REGION" envDefault:"us-east-1"`
+	Bucket          string        `env:"BUCKET"`
+	AccessKeyID     string        `env:"ACCESS_KEY_ID"`
+	SecretAccessKey string        `env:"SECRET_ACCESS_KEY"`
+	KeyPrefix       string        `env:"KEY_PREFIX" envDefault:"results/"`
+	InlineThreshold int           `env:"INLINE_THRESHOLD" envDefault:"65536"` // bytes, smaller results stay in Postgres
+	SupersededDays  int           `env:"SUPERSEDED_DAYS" envDefault:"30"`     // lifecycle expiration of superseded objects, e.g. assets of deleted themes
+	Timeout         time.Duration `env:"TIMEOUT" envDefault:"30s"`
 }
 
 // contextQuestions represents the structure of context_questions.json
+type telegramBots struct {
+	Bots []TelegramBotConfig `json:"bots"`
+}
+
+type mockTranscripts struct {
+	Transcripts []MockTranscript `json:"transcripts"`
+}
+
 type contextQuestions struct {
 	Questions []string `json:"questions"`
 }
@@ -146,6 +690,15 @@ func LoadConfig() (*Config, error) {
 		return nil, fmt.Errorf("load context questions: %w", err)
 	}
 
+	// Load additional Telegram bots after the context questions they default to
+	if err := loadTelegramBots(cfg); err != nil {
+		return nil, fmt.Errorf("load telegram bots: %w", err)
+	}
+
+	if err := loadMockTranscripts(cfg); err != nil {
+		return nil, fmt.Errorf("load mock transcripts: %w", err)
+	}
+
 	return cfg, nil
 }
 
@@ -165,10 +718,256 @@ func validateConfig(cfg *Config) error {
 		errors = append(errors, fmt.Sprintf("TELEGRAM_RATE_LIMIT_BURST must be between 1 and 20, got %d", cfg.TelegramCfg.RateLimitBurst))
 	}
 
+	rateLimit := cfg.TelegramCfg.RateLimit
+	if rateLimit.Mode != RateLimitModeAdaptive && rateLimit.Mode != RateLimitModeSimple {
+		errors = append(errors, fmt.Sprintf("TELEGRAM_RATE_LIMIT_MODE must be 'adaptive' or 'simple', got '%s'", rateLimit.Mode))
+	}
+	if rateLimit.LLMCost <= 0 || rateLimit.NavigationCost <= 0 {
+		errors = append(errors, "TELEGRAM_RATE_LIMIT_LLM_COST and TELEGRAM_RATE_LIMIT_NAVIGATION_COST must be positive")
+	}
+	if rateLimit.MinReputation <= 0 || rateLimit.MinReputation > 1 || rateLimit.MaxReputation < 1 {
+		errors = append(errors, fmt.Sprintf("TELEGRAM_RATE_LIMIT_MIN_REPUTATION must be in (0, 1] and TELEGRAM_RATE_LIMIT_MAX_REPUTATION at least 1, got %g and %g", rateLimit.MinReputation, rateLimit.MaxReputation))
+	}
+
+	store := cfg.TelegramCfg.Store
+	if store.Backend != StoreBackendMemory && store.Backend != StoreBackendRedis {
+		errors = append(errors, fmt.Sprintf("TELEGRAM_STORE_BACKEND must be 'memory' or 'redis', got '%s'", store.Backend))
+	}
+	if store.Backend == StoreBackendRedis && store.RedisAddr == "" {
+		errors = append(errors, "TELEGRAM_STORE_REDIS_ADDR is required for the redis store")
+	}
+	if store.CallbackDedupWindow < 0 {
+		errors = append(errors, "TELEGRAM_STORE_CALLBACK_DEDUP_WINDOW must not be negative")
+	}
+	if stateCache := cfg.TelegramCfg.StateCache; stateCache.Enabled {
+		if store.RedisAddr == "" {
+			errors = append(errors, "TELEGRAM_STORE_REDIS_ADDR is required for the state cache")
+		}
+		if stateCache.TTL <= 0 {
+			errors = append(errors, "TELEGRAM_STATE_CACHE_TTL must be positive")
+		}
+	}
+
 	if cfg.TelegramCfg.ShutdownTimeout < 1 || cfg.TelegramCfg.ShutdownTimeout > 300 {
 		errors = append(errors, fmt.Sprintf("TELEGRAM_SHUTDOWN_TIMEOUT must be between 1 and 300 seconds, got %d", cfg.TelegramCfg.ShutdownTimeout))
 	}
 
+	switch cfg.TelegramCfg.QuestionNumbering {
+	case "block", "global":
+	default:
+		errors = append(errors, fmt.Sprintf("TELEGRAM_QUESTION_NUMBERING must be one of block, global, got %q", cfg.TelegramCfg.QuestionNumbering))
+	}
+
+	if _, err := time.LoadLocation(cfg.TelegramCfg.DefaultTimezone); err != nil || cfg.TelegramCfg.DefaultTimezone == "" {
+		errors = append(errors, fmt.Sprintf("TELEGRAM_DEFAULT_TIMEZONE must be an IANA timezone, got %q", cfg.TelegramCfg.DefaultTimezone))
+	}
+
+	if cfg.TelegramCfg.WebhookSecret != "" && !validWebhookSecret(cfg.TelegramCfg.WebhookSecret) {
+		errors = append(errors, "TELEGRAM_WEBHOOK_SECRET must be 1-256 characters of A-Z, a-z, 0-9, _ and -")
+	}
+
+	if cfg.TelegramCfg.MediaGroupWindow <= 0 || cfg.TelegramCfg.MediaGroupWindow > 10*time.Second {
+		errors = append(errors, fmt.Sprintf("TELEGRAM_MEDIA_GROUP_WINDOW must be between 0 and 10s, got %s", cfg.TelegramCfg.MediaGroupWindow))
+	}
+
+	if cfg.TelegramCfg.HandlerTimeout <= 0 {
+		errors = append(errors, fmt.Sprintf("TELEGRAM_HANDLER_TIMEOUT must be positive, got %s", cfg.TelegramCfg.HandlerTimeout))
+	}
+
+	if cfg.TelegramCfg.GenerationTimeout < cfg.TelegramCfg.HandlerTimeout {
+		errors = append(errors, fmt.Sprintf("TELEGRAM_GENERATION_TIMEOUT must not be less than TELEGRAM_HANDLER_TIMEOUT, got %s", cfg.TelegramCfg.GenerationTimeout))
+	}
+
+	for handlerState, timeout := range cfg.TelegramCfg.HandlerTimeouts {
+		if timeout <= 0 {
+			errors = append(errors, fmt.Sprintf("TELEGRAM_HANDLER_TIMEOUTS of %s must be positive, got %s", handlerState, timeout))
+		}
+	}
+
+	// Validate server configuration
+	if cfg.SyncStartTimeout <= 0 || cfg.SyncStartTimeout >= 60*time.Second {
+		errors = append(errors, fmt.Sprintf("SYNC_START_TIMEOUT must be between 0 and 60s, got %s", cfg.SyncStartTimeout))
+	}
+
+	// Validate operations configuration
+	if cfg.OperationsCfg.Retention <= 0 || cfg.OperationsCfg.CleanupInterval <= 0 {
+		errors = append(errors, "OPERATIONS_RETENTION and OPERATIONS_CLEANUP_INTERVAL must be positive")
+	}
+
+	// Validate time budget configuration
+	if cfg.TimeBudgetCfg.Default < 0 {
+		errors = append(errors, fmt.Sprintf("TIME_BUDGET_DEFAULT must not be negative, got %s", cfg.TimeBudgetCfg.Default))
+	}
+	if cfg.TimeBudgetCfg.WarnThreshold <= 0 || cfg.TimeBudgetCfg.WarnThreshold >= 1 {
+		errors = append(errors, fmt.Sprintf("TIME_BUDGET_WARN_THRESHOLD must be between 0 and 1, got %g", cfg.TimeBudgetCfg.WarnThreshold))
+	}
+
+	// Validate voice queue configuration
+	if cfg.VoiceQueueCfg.Enabled && (cfg.VoiceQueueCfg.PollInterval <= 0 || cfg.VoiceQueueCfg.BatchSize <= 0 || cfg.VoiceQueueCfg.MaxAge <= 0) {
+		errors = append(errors, "VOICE_QUEUE_POLL_INTERVAL, VOICE_QUEUE_BATCH_SIZE and VOICE_QUEUE_MAX_AGE must be positive when VOICE_QUEUE_ENABLED is set")
+	}
+
+	// Validate conversation log configuration
+	if cfg.ConversationLogCfg.InPrompts && (cfg.ConversationLogCfg.MaxEntries <= 0 || cfg.ConversationLogCfg.MaxChars <= 0) {
+		errors = append(errors, "CONVERSATION_LOG_MAX_ENTRIES and CONVERSATION_LOG_MAX_CHARS must be positive when CONVERSATION_LOG_IN_PROMPTS is set")
+	}
+
+	// Validate context snapshot configuration
+	if cfg.ContextSnapshotCfg.Enabled && cfg.ContextSnapshotCfg.MaxFacts <= 0 {
+		errors = append(errors, "CONTEXT_SNAPSHOT_MAX_FACTS must be positive when CONTEXT_SNAPSHOT_ENABLED is set")
+	}
+
+	// Validate project description configuration
+	if cfg.ProjectDescriptionCfg.AutoGenerate && cfg.ProjectDescriptionCfg.MaxChars <= 0 {
+		errors = append(errors, "PROJECT_DESCRIPTION_MAX_CHARS must be positive when PROJECT_DESCRIPTION_AUTO_GENERATE is set")
+	}
+
+	// Validate project freshness configuration
+	if cfg.ProjectFreshnessCfg.StaleAfter < 0 {
+		errors = append(errors, "PROJECT_FRESHNESS_STALE_AFTER must not be negative")
+	}
+
+	// Validate project list cache configuration
+	if cfg.ProjectListCacheCfg.TTL < 0 {
+		errors = append(errors, "PROJECT_LIST_CACHE_TTL must not be negative")
+	}
+	if cfg.ProjectListCacheCfg.TTL > 0 && cfg.ProjectListCacheCfg.MaxEntries < 1 {
+		errors = append(errors, "PROJECT_LIST_CACHE_MAX_ENTRIES must be positive when the cache is enabled")
+	}
+
+	// Validate goal quality configuration
+	if cfg.GoalQualityCfg.MinWords < 0 {
+		errors = append(errors, "GOAL_QUALITY_MIN_WORDS must not be negative")
+	}
+
+	// Validate account link configuration
+	if cfg.AccountLinkCfg.CodeTTL <= 0 {
+		errors = append(errors, "ACCOUNT_LINK_CODE_TTL must be positive")
+	}
+
+	// Validate session lock configuration
+	if cfg.SessionLockCfg.WaitTimeout < 0 {
+		errors = append(errors, "SESSION_LOCK_WAIT_TIMEOUT must not be negative")
+	}
+	if cfg.SessionLockCfg.OperationTTL != 0 && cfg.SessionLockCfg.OperationTTL < 3*time.Second {
+		errors = append(errors, fmt.Sprintf("SESSION_LOCK_OPERATION_TTL must be 0 or at least 3s, got %s", cfg.SessionLockCfg.OperationTTL))
+	}
+
+	// Validate question deduplication configuration
+	if cfg.QuestionDedupCfg.Threshold < 0 || cfg.QuestionDedupCfg.Threshold > 1 {
+		errors = append(errors, fmt.Sprintf("QUESTION_DEDUP_THRESHOLD must be between 0 and 1, got %g", cfg.QuestionDedupCfg.Threshold))
+	}
+
+	// Validate generation fallback configuration
+	if cfg.GenerationFallbackCfg.MaxFailures < 0 {
+		errors = append(errors, "GENERATION_FALLBACK_MAX_FAILURES must not be negative")
+	}
+
+	// Validate incidents configuration
+	if cfg.IncidentsCfg.Retention <= 0 || cfg.IncidentsCfg.CleanupInterval <= 0 {
+		errors = append(errors, "INCIDENTS_RETENTION and INCIDENTS_CLEANUP_INTERVAL must be positive")
+	}
+
+	// Validate explanation configuration
+	if cfg.ExplanationCfg.Cooldown < 0 {
+		errors = append(errors, "EXPLANATION_COOLDOWN must not be negative")
+	}
+	if cfg.ExplanationCfg.OnDemand && !cfg.EnableMocks && cfg.LLMConnectorCfg.ExplainQuestionEndpoint == "" {
+		errors = append(errors, "LLM_EXPLAIN_QUESTION_ENDPOINT is required with EXPLANATION_ON_DEMAND")
+	}
+
+	// Validate tracing configuration
+	if cfg.TracingCfg.SampleRatio < 0 || cfg.TracingCfg.SampleRatio > 1 {
+		errors = append(errors, fmt.Sprintf("TRACING_SAMPLE_RATIO must be between 0 and 1, got %g", cfg.TracingCfg.SampleRatio))
+	}
+
+	// Validate content retention configuration
+	if cfg.ContentRetentionCfg.Days < 0 {
+		errors = append(errors, "CONTENT_RETENTION_DAYS must not be negative")
+	}
+	if cfg.ContentRetentionCfg.CleanupInterval <= 0 {
+		errors = append(errors, "CONTENT_RETENTION_CLEANUP_INTERVAL must be positive")
+	}
+
+	// Validate LLM summary streaming configuration
+	if cfg.LLMConnectorCfg.StreamTimeout <= 0 {
+		errors = append(errors, "LLM_STREAM_TIMEOUT must be positive")
+	}
+
+	// Validate session stream configuration
+	if cfg.StreamCfg.PollInterval <= 0 || cfg.StreamCfg.MaxDuration <= 0 {
+		errors = append(errors, "STREAM_POLL_INTERVAL and STREAM_MAX_DURATION must be positive")
+	}
+	if cfg.StreamCfg.Buffer < 1 {
+		errors = append(errors, fmt.Sprintf("STREAM_BUFFER must be at least 1, got %d", cfg.StreamCfg.Buffer))
+	}
+
+	// Validate callback configuration
+	if cfg.CallbackConnectorCfg.SchemaVersion != 1 && cfg.CallbackConnectorCfg.SchemaVersion != 2 {
+		errors = append(errors, fmt.Sprintf("CALLBACK_SCHEMA_VERSION must be 1 or 2, got %d", cfg.CallbackConnectorCfg.SchemaVersion))
+	}
+	if outbox := cfg.CallbackOutboxCfg; outbox.Enabled {
+		if outbox.PollInterval <= 0 || outbox.BatchSize <= 0 || outbox.MaxAttempts <= 0 {
+			errors = append(errors, "CALLBACK_OUTBOX_POLL_INTERVAL, CALLBACK_OUTBOX_BATCH_SIZE and CALLBACK_OUTBOX_MAX_ATTEMPTS must be positive when CALLBACK_OUTBOX_ENABLED is set")
+		}
+		if outbox.BaseDelay <= 0 || outbox.MaxDelay < outbox.BaseDelay {
+			errors = append(errors, fmt.Sprintf("CALLBACK_OUTBOX_BASE_DELAY must be positive and at most CALLBACK_OUTBOX_MAX_DELAY, got %s and %s", outbox.BaseDelay, outbox.MaxDelay))
+		}
+		// The lease must outlast a delivery attempt, or events would be sent twice while still in flight
+		if outbox.Lease <= cfg.CallbackConnectorCfg.RequestTimeout {
+			errors = append(errors, fmt.Sprintf("CALLBACK_OUTBOX_LEASE must be longer than CALLBACK_TIMEOUT, got %s", outbox.Lease))
+		}
+		if outbox.Retention <= 0 || outbox.CleanupInterval <= 0 {
+			errors = append(errors, "CALLBACK_OUTBOX_RETENTION and CALLBACK_OUTBOX_CLEANUP_INTERVAL must be positive when CALLBACK_OUTBOX_ENABLED is set")
+		}
+	}
+
+	// Validate feature flags configuration
+	for name, percent := range cfg.FeatureFlagsCfg.Rollouts {
+		if percent < 0 || percent > 100 {
+			errors = append(errors, fmt.Sprintf("FEATURE_FLAGS_ROLLOUTS percent of %s must be between 0 and 100, got %d", name, percent))
+		}
+	}
+	if cfg.FeatureFlagsCfg.RefreshInterval <= 0 {
+		errors = append(errors, "FEATURE_FLAGS_REFRESH_INTERVAL must be positive")
+	}
+
+	// Validate quota configuration
+	if cfg.QuotaCfg.UserSessionsPerDay < 0 || cfg.QuotaCfg.TenantSessionsPerDay < 0 ||
+		cfg.QuotaCfg.UserGenerationsPerMonth < 0 || cfg.QuotaCfg.TenantGenerationsPerMonth < 0 {
+		errors = append(errors, "QUOTA_* limits must not be negative")
+	}
+	if cfg.QuotaCfg.WarnThreshold <= 0 || cfg.QuotaCfg.WarnThreshold >= 1 {
+		errors = append(errors, fmt.Sprintf("QUOTA_WARN_THRESHOLD must be between 0 and 1, got %g", cfg.QuotaCfg.WarnThreshold))
+	}
+
+	// Validate analytics export configuration
+	if cfg.AnalyticsCfg.MaxRows <= 0 || cfg.AnalyticsCfg.MaxRange <= 0 {
+		errors = append(errors, "ANALYTICS_MAX_ROWS and ANALYTICS_MAX_RANGE must be positive")
+	}
+
+	// Validate schema migrations configuration
+	if cfg.MigrationsCfg.OnStart != MigrationsOnStartApply && cfg.MigrationsCfg.OnStart != MigrationsOnStartCheck {
+		errors = append(errors, fmt.Sprintf("MIGRATIONS_ON_START must be '%s' or '%s', got '%s'",
+			MigrationsOnStartApply, MigrationsOnStartCheck, cfg.MigrationsCfg.OnStart))
+	}
+
+	// Validate demo sessions configuration
+	if cfg.DemoCfg.SessionTTL <= 0 || cfg.DemoCfg.CleanupInterval <= 0 {
+		errors = append(errors, "DEMO_SESSION_TTL and DEMO_CLEANUP_INTERVAL must be positive")
+	}
+
+	// Validate result storage configuration
+	if cfg.ResultStorageCfg.Enabled && !cfg.EnableMocks {
+		if cfg.ResultStorageCfg.Endpoint == "" || cfg.ResultStorageCfg.Bucket == "" ||
+			cfg.ResultStorageCfg.AccessKeyID == "" || cfg.ResultStorageCfg.SecretAccessKey == "" {
+			errors = append(errors, "RESULT_STORAGE_ENDPOINT, RESULT_STORAGE_BUCKET and credentials are required when result storage is enabled")
+		}
+	}
+	if cfg.ResultStorageCfg.InlineThreshold < 0 || cfg.ResultStorageCfg.SupersededDays < 1 {
+		errors = append(errors, "RESULT_STORAGE_INLINE_THRESHOLD must not be negative and RESULT_STORAGE_SUPERSEDED_DAYS must be positive")
+	}
+
 	// Validate Database configuration
 	if cfg.DBMaxConns < 1 || cfg.DBMaxConns > 200 {
 		errors = append(errors, fmt.Sprintf("DB_MAX_CONNS must be between 1 and 200, got %d", cfg.DBMaxConns))
@@ -178,6 +977,33 @@ func validateConfig(cfg *Config) error {
 		errors = append(errors, fmt.Sprintf("DB_MIN_CONNS must be between 0 and DB_MAX_CONNS(%d), got %d", cfg.DBMaxConns, cfg.DBMinConns))
 	}
 
+	// Validate moderation configuration
+	switch cfg.ModerationCfg.Action {
+	case "warn", "redact", "block":
+	default:
+		errors = append(errors, fmt.Sprintf("MODERATION_ACTION must be one of warn, redact, block, got %q", cfg.ModerationCfg.Action))
+	}
+
+	// Validate transcription normalization configuration
+	switch cfg.NormalizationCfg.Mode {
+	case "rules", "llm":
+	default:
+		errors = append(errors, fmt.Sprintf("NORMALIZATION_MODE must be one of rules, llm, got %q", cfg.NormalizationCfg.Mode))
+	}
+
+	if cfg.NormalizationCfg.MaxLength < 1 {
+		errors = append(errors, fmt.Sprintf("NORMALIZATION_MAX_LENGTH must be positive, got %d", cfg.NormalizationCfg.MaxLength))
+	}
+
+	// Validate generation estimate configuration
+	if cfg.EstimateCfg.CharsPerToken <= 0 {
+		errors = append(errors, fmt.Sprintf("ESTIMATE_CHARS_PER_TOKEN must be positive, got %v", cfg.EstimateCfg.CharsPerToken))
+	}
+
+	if cfg.EstimateCfg.ConfirmThresholdTokens < 0 || cfg.EstimateCfg.AdminApprovalThresholdTokens < 0 {
+		errors = append(errors, "ESTIMATE_CONFIRM_THRESHOLD_TOKENS and ESTIMATE_ADMIN_APPROVAL_THRESHOLD_TOKENS must not be negative")
+	}
+
 	if len(errors) > 0 {
 		return fmt.Errorf("configuration validation errors:\n  - %s", fmt.Sprintf("%s", errors[0]))
 	}
@@ -227,6 +1053,122 @@ func loadContextQuestions(cfg *Config) error {
 	return nil
 }
 
+// loadMockTranscripts loads the canned transcripts of the mock ASR connector from ASR_MOCK_TRANSCRIPTS_FILE
+func loadMockTranscripts(cfg *Config) error {
+	path := cfg.ASRConnectorCfg.MockTranscriptsFile
+	if path == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read mock transcripts file: %w", err)
+	}
+
+	var transcriptsData mockTranscripts
+	if err := json.Unmarshal(data, &transcriptsData); err != nil {
+		return fmt.Errorf("parse mock transcripts JSON: %w", err)
+	}
+
+	for i, transcript := range transcriptsData.Transcripts {
+		switch {
+		case transcript.FileName == "" && transcript.Size <= 0:
+			return fmt.Errorf("transcript #%d has neither file_name nor size", i)
+		case transcript.Text == "":
+			return fmt.Errorf("transcript #%d has no text", i)
+		case transcript.FailAttempts < 0:
+			return fmt.Errorf("transcript #%d: fail_attempts must not be negative", i)
+		}
+	}
+
+	cfg.ASRConnectorCfg.MockTranscripts = transcriptsData.Transcripts
+	return nil
+}
+
+// loadTelegramBots builds the bot list from the configured bot and TELEGRAM_BOTS_FILE
+func loadTelegramBots(cfg *Config) error {
+	bots := []TelegramBotConfig{{
+		Name:  defaultBotName,
+		Token: cfg.TelegramCfg.BotToken,
+	}}
+
+	if path := cfg.TelegramCfg.BotsFile; path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("read bots file: %w", err)
+		}
+
+		var botsData telegramBots
+		if err := json.Unmarshal(data, &botsData); err != nil {
+			return fmt.Errorf("parse bots file JSON: %w", err)
+		}
+		bots = append(bots, botsData.Bots...)
+	}
+
+	names := make(map[string]bool, len(bots))
+	tokens := make(map[string]bool, len(bots))
+	paths := make(map[string]bool, len(bots))
+	for i := range bots {
+		bot := &bots[i]
+		switch {
+		case bot.Name == "":
+			return fmt.Errorf("bot #%d has no name", i)
+		case bot.Token == "":
+			return fmt.Errorf("bot '%s' has no token", bot.Name)
+		case names[bot.Name]:
+			return fmt.Errorf("duplicate bot name '%s'", bot.Name)
+		case tokens[bot.Token]:
+			return fmt.Errorf("bot '%s' reuses the token of another bot", bot.Name)
+		}
+
+		if bot.WebhookPath == "" {
+			bot.WebhookPath = "/telegram/" + bot.Name
+		}
+		if paths[bot.WebhookPath] {
+			return fmt.Errorf("bot '%s' reuses webhook path %s", bot.Name, bot.WebhookPath)
+		}
+		if len(bot.ContextQuestions) == 0 {
+			bot.ContextQuestions = cfg.ContextQuestions
+		}
+
+		botCfg := cfg.TelegramCfg.ForBot(*bot)
+		if botCfg.MaxDraftMessages < 1 || botCfg.MaxDraftMessages > 50 {
+			return fmt.Errorf("bot '%s': max_draft_messages must be between 1 and 50, got %d", bot.Name, botCfg.MaxDraftMessages)
+		}
+		if botCfg.RateLimitPerMinute > 60 {
+			return fmt.Errorf("bot '%s': rate_limit_per_minute must be between 1 and 60, got %d", bot.Name, botCfg.RateLimitPerMinute)
+		}
+		if botCfg.RateLimitBurst > 20 {
+			return fmt.Errorf("bot '%s': rate_limit_burst must be between 1 and 20, got %d", bot.Name, botCfg.RateLimitBurst)
+		}
+		if bot.WebhookSecret != "" && !validWebhookSecret(bot.WebhookSecret) {
+			return fmt.Errorf("bot '%s': webhook_secret must be 1-256 characters of A-Z, a-z, 0-9, _ and -", bot.Name)
+		}
+
+		names[bot.Name] = true
+		tokens[bot.Token] = true
+		paths[bot.WebhookPath] = true
+	}
+
+	cfg.TelegramBots = bots
+	return nil
+}
+
+// validWebhookSecret reports whether Telegram accepts the value as a webhook secret token
+func validWebhookSecret(secret string) bool {
+	if len(secret) > 256 {
+		return false
+	}
+	for _, r := range secret {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == '-':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
 func getEnvFile(environment string) string {
 	switch environment {
 	case "prod", "production":
@@ -0,0 +1,24 @@
+package entity
+
+import "time"
+
+// AccountLinkCode is a one-time code the Telegram bot issues so that another client of the user,
+// e.g. the web client, can work on the same session
+type AccountLinkCode struct {
+	Code           string    `json:"code"`
+	TelegramUserID int64     `json:"-"`
+	ExpiresAt      time.Time `json:"expires_at"`
+}
+
+// RedeemLinkCodeRequest links the X-Client-ID client to the Telegram user that issued the code
+type RedeemLinkCodeRequest struct {
+	Code string `json:"code"`
+}
+
+// LinkedSession is the current session of the Telegram user a client is linked to; SessionID is
+// empty while the user has no session in the bot
+type LinkedSession struct {
+	TelegramUserID int64         `json:"telegram_user_id"`
+	SessionID      string        `json:"session_id,omitempty"`
+	Status         SessionStatus `json:"status,omitempty"`
+}
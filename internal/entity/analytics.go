@@ -0,0 +1,63 @@
+package entity
+
+import "time"
+
+// AnalyticsExportFormat is the file format of an analytics export
+type AnalyticsExportFormat string
+
+const (
+	AnalyticsExportCSV     AnalyticsExportFormat = "csv"
+	AnalyticsExportParquet AnalyticsExportFormat = "parquet"
+)
+
+// IsValid checks if the export format is supported
+func (f AnalyticsExportFormat) IsValid() bool {
+	return f == AnalyticsExportCSV || f == AnalyticsExportParquet
+}
+
+// AnswerStats is a question of a session as stored, reduced to what analytics may derive from: the
+// identifiers still have to be hashed and the lengths generalized
+type AnswerStats struct {
+	SessionID        string
+	OwnerID          *string
+	SessionType      SessionType
+	SessionStatus    SessionStatus
+	HasProject       bool
+	SessionCreatedAt time.Time
+	IterationNumber  int
+	Additional       bool // asked by the validation of the collected answers
+	QuestionNumber   int
+	Status           QuestionStatus
+	AnswerType       string
+	SkipReason       *SkipReason
+	FollowUp         bool
+	QuestionChars    int
+	AnswerChars      int
+	AnswerWords      int
+	Normalized       bool // the transcript of a voice answer was normalized
+	CreatedAt        time.Time
+	AnsweredAt       *time.Time
+}
+
+// AnalyticsAnswer is an anonymized row of the analytics export: identifiers are keyed hashes, times
+// are ISO weeks and lengths are buckets, so no customer content can be recovered from it
+type AnalyticsAnswer struct {
+	SessionHash     string `json:"session_hash"`
+	UserHash        string `json:"user_hash"` // empty for sessions without an owner
+	SessionType     string `json:"session_type"`
+	SessionStatus   string `json:"session_status"`
+	HasProject      bool   `json:"has_project"`
+	SessionWeek     string `json:"session_week"` // e.g. 2026-W07
+	IterationNumber int64  `json:"iteration_number"`
+	BlockCategory   string `json:"block_category"` // generated or additional
+	QuestionNumber  int64  `json:"question_number"`
+	QuestionLength  string `json:"question_length"`
+	Status          string `json:"status"`
+	AnswerType      string `json:"answer_type"`
+	SkipReason      string `json:"skip_reason"`
+	FollowUp        bool   `json:"follow_up"`
+	AnswerLength    string `json:"answer_length"`
+	AnswerWords     string `json:"answer_words"`
+	Normalized      bool   `json:"normalized"`
+	ResponseTime    string `json:"response_time"` // from asking to answering the question
+}
@@ -1,6 +1,24 @@
 package entity
 
+import "context"
+
 // ASRTranscribeResponse represents the response from transcription
 type ASRTranscribeResponse struct {
 	Transcriptions string `json:"transcriptions"`
 }
+
+type audioFileNameContextKey struct{}
+
+// WithAudioFileName passes the name of the uploaded audio file along with the audio transcribed in ctx
+func WithAudioFileName(ctx context.Context, name string) context.Context {
+	if name == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, audioFileNameContextKey{}, name)
+}
+
+// AudioFileNameFromContext returns the name of the uploaded audio file, empty when it is unknown
+func AudioFileNameFromContext(ctx context.Context) string {
+	name, _ := ctx.Value(audioFileNameContextKey{}).(string)
+	return name
+}
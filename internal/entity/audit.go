@@ -0,0 +1,34 @@
+package entity
+
+import "time"
+
+type AuditEventType string
+
+const (
+	AuditEventModeration         AuditEventType = "moderation"
+	AuditEventGenerationApproved AuditEventType = "generation_approved"
+	AuditEventReviewSubmitted    AuditEventType = "review_submitted"
+	AuditEventReviewDecided      AuditEventType = "review_decided"
+	AuditEventSessionScheduled   AuditEventType = "session_scheduled"
+	AuditEventOperatorTakeover   AuditEventType = "operator_takeover"
+	AuditEventProjectChanged     AuditEventType = "project_changed"
+	AuditEventAnswerEdited       AuditEventType = "answer_edited"
+)
+
+// TakeoverAction is a step of a support operator acting in a user's Telegram session
+type TakeoverAction string
+
+const (
+	TakeoverActionAttach   TakeoverAction = "attach"
+	TakeoverActionAnswer   TakeoverAction = "answer"
+	TakeoverActionGenerate TakeoverAction = "generate"
+	TakeoverActionRelease  TakeoverAction = "release"
+)
+
+type AuditEvent struct {
+	ID        string         `json:"id"`
+	SessionID string         `json:"session_id,omitempty"`
+	Type      AuditEventType `json:"event_type"`
+	Details   map[string]any `json:"details"`
+	CreatedAt time.Time      `json:"created_at"`
+}
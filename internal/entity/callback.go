@@ -1,5 +1,7 @@
 package entity
 
+import "context"
+
 // CallbackEventType represents the type of callback event
 type CallbackEventType string
 
@@ -7,14 +9,106 @@ const (
 	CallbackEventTypeQuestions      CallbackEventType = "questions"
 	CallbackEventTypeProjectUpdated CallbackEventType = "projectUpdated"
 	CallbackEventTypeFinalResult    CallbackEventType = "finalResult"
+	CallbackEventTypeEstimate       CallbackEventType = "estimate"
+	CallbackEventTypeReviewRequest  CallbackEventType = "reviewRequested"
 	CallbackEventTypeError          CallbackEventType = "error"
+
+	CallbackEventTypeQuestionAnswered CallbackEventType = "questionAnswered"
+	CallbackEventTypeQuestionSkipped  CallbackEventType = "questionSkipped"
+)
+
+// CallbackGranularity selects which callback events a session emits to its consumer
+type CallbackGranularity string
+
+const (
+	CallbackGranularityIteration CallbackGranularity = "iteration" // question blocks, estimates and the final result
+	CallbackGranularityQuestion  CallbackGranularity = "question"  // also every answered or skipped question
+	CallbackGranularityFinal     CallbackGranularity = "final"     // only the final result
+)
+
+// Emits reports whether a session with the granularity sends the event to its consumer.
+// Errors are always sent, and so are review requests, which go to the approvers
+func (g CallbackGranularity) Emits(event CallbackEventType) bool {
+	switch event {
+	case CallbackEventTypeError, CallbackEventTypeFinalResult, CallbackEventTypeReviewRequest:
+		return true
+	case CallbackEventTypeQuestionAnswered, CallbackEventTypeQuestionSkipped:
+		return g == CallbackGranularityQuestion
+	default:
+		return g != CallbackGranularityFinal
+	}
+}
+
+// Callback payload schema versions; a consumer picks one with the X-Callback-Schema-Version header
+const (
+	CallbackSchemaV1 = 1 // questions carry their text and explanation
+	CallbackSchemaV2 = 2 // questions also carry answer options and types, parent linkage and block metadata
+
+	LatestCallbackSchemaVersion = CallbackSchemaV2
 )
 
 // CallbackEvent represents a callback event
 type CallbackEvent struct {
-	Event     CallbackEventType `json:"event"`
-	Timestamp string            `json:"timestamp"` // ISO-8601 UTC
-	Data      any               `json:"data"`
+	Event         CallbackEventType `json:"event"`
+	SchemaVersion int               `json:"schema_version"`
+	Timestamp     string            `json:"timestamp"` // ISO-8601 UTC
+	Data          any               `json:"data"`
+}
+
+type callbackSchemaContextKey struct{}
+
+// WithCallbackSchemaVersion makes the callbacks sent for ctx use the payload schema version negotiated with the consumer
+func WithCallbackSchemaVersion(ctx context.Context, version int) context.Context {
+	return context.WithValue(ctx, callbackSchemaContextKey{}, version)
+}
+
+// CallbackSchemaVersionFromContext returns the negotiated callback schema version, 0 when the consumer did not pick one
+func CallbackSchemaVersionFromContext(ctx context.Context) int {
+	version, _ := ctx.Value(callbackSchemaContextKey{}).(int)
+	return version
+}
+
+// CallbackQuestionsData represents data for questions event of schema version 2
+type CallbackQuestionsData struct {
+	SessionID       string             `json:"session_id"`
+	IterationID     string             `json:"iteration_id"`
+	IterationNumber int                `json:"iteration_number"`
+	Title           string             `json:"title"`
+	Block           CallbackBlockInfo  `json:"block"`
+	Questions       []CallbackQuestion `json:"questions"`
+}
+
+// CallbackBlockInfo describes the block of questions an event carries
+type CallbackBlockInfo struct {
+	ID              string `json:"id"`
+	Number          int    `json:"number"`
+	Title           string `json:"title"`
+	QuestionCount   int    `json:"question_count"`
+	UnansweredCount int    `json:"unanswered_count"`
+	IsFollowUp      bool   `json:"is_follow_up"` // the block clarifies answers given earlier
+}
+
+// CallbackQuestion represents a question in questions event of schema version 2
+type CallbackQuestion struct {
+	ID               string             `json:"id"`
+	QuestionNumber   int                `json:"question_number"`
+	Status           QuestionStatus     `json:"status"`
+	Question         string             `json:"question"`
+	Explanation      string             `json:"explanation"`
+	Options          []string           `json:"options"`
+	AnswerType       QuestionAnswerType `json:"answer_type"`
+	ParentQuestionID *string            `json:"parent_question_id"`
+	BlockNumber      int                `json:"block_number"`
+	BlockTitle       string             `json:"block_title"`
+}
+
+// CallbackQuestionEventData represents data for question answered and skipped events
+type CallbackQuestionEventData struct {
+	SessionID  string         `json:"session_id"`
+	QuestionID string         `json:"question_id"`
+	Status     QuestionStatus `json:"status"`
+	Answer     string         `json:"answer,omitempty"`
+	SkipReason SkipReason     `json:"skip_reason,omitempty"`
 }
 
 // CallbackProjectUpdatedData represents data for project updated event
@@ -0,0 +1,39 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// CallbackDeliveryStatus is the delivery state of a callback event kept in the outbox
+type CallbackDeliveryStatus string
+
+const (
+	CallbackDeliveryStatusPending   CallbackDeliveryStatus = "pending" // waiting for its next attempt
+	CallbackDeliveryStatusDelivered CallbackDeliveryStatus = "delivered"
+	CallbackDeliveryStatusFailed    CallbackDeliveryStatus = "failed" // given up after the last attempt
+)
+
+// CallbackDelivery is a callback event kept in the outbox until the consumer accepted it,
+// so that events survive restarts and failed deliveries are retried
+type CallbackDelivery struct {
+	ID            string                 `json:"id"`
+	TenantID      string                 `json:"-"`
+	RequestID     string                 `json:"request_id"`
+	CallbackURL   string                 `json:"callback_url"`
+	Event         CallbackEventType      `json:"event"`
+	Payload       json.RawMessage        `json:"-"` // the callback event as sent
+	Status        CallbackDeliveryStatus `json:"status"`
+	Attempts      int                    `json:"attempts"`
+	NextAttemptAt *time.Time             `json:"next_attempt_at,omitempty"` // only set while pending
+	LastError     *string                `json:"last_error,omitempty"`
+	CreatedAt     time.Time              `json:"created_at"`
+	UpdatedAt     time.Time              `json:"updated_at"`
+	DeliveredAt   *time.Time             `json:"delivered_at,omitempty"`
+}
+
+// ListCallbackDeliveriesResponse lists the callback events sent for one request, oldest first
+type ListCallbackDeliveriesResponse struct {
+	RequestID  string              `json:"request_id"`
+	Deliveries []*CallbackDelivery `json:"deliveries"`
+}
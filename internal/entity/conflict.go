@@ -0,0 +1,31 @@
+package entity
+
+import "time"
+
+// ConflictResolution is the user's decision on a contradiction with earlier project requirements
+type ConflictResolution string
+
+const (
+	ConflictResolutionKeepNew      ConflictResolution = "keep_new"
+	ConflictResolutionKeepPrevious ConflictResolution = "keep_previous"
+)
+
+func (r ConflictResolution) IsValid() bool {
+	switch r {
+	case ConflictResolutionKeepNew, ConflictResolutionKeepPrevious:
+		return true
+	}
+	return false
+}
+
+// RequirementConflict is a contradiction between the session result and earlier project requirements
+type RequirementConflict struct {
+	ID          string              `json:"id"`
+	SessionID   string              `json:"session_id"`
+	Requirement string              `json:"requirement"`
+	Previous    string              `json:"previous"`
+	Source      *string             `json:"source,omitempty"`
+	Resolution  *ConflictResolution `json:"resolution,omitempty"`
+	ResolvedAt  *time.Time          `json:"resolved_at,omitempty"`
+	CreatedAt   time.Time           `json:"created_at"`
+}
@@ -0,0 +1,13 @@
+package entity
+
+// DemoQuestion is a question of the onboarding demo interview with a prepared answer
+type DemoQuestion struct {
+	// Goal is the example project the demo interview is about
+	Goal         string `json:"goal"`
+	Number       int    `json:"number"`
+	Total        int    `json:"total"`
+	Title        string `json:"title"`
+	Question     string `json:"question"`
+	Explanation  string `json:"explanation"`
+	SampleAnswer string `json:"sample_answer"`
+}
@@ -0,0 +1,53 @@
+package entity
+
+// SectionChange tells how a section of the compared result differs from the base result
+type SectionChange string
+
+const (
+	SectionChangeAdded     SectionChange = "added"     // only in the compared result
+	SectionChangeRemoved   SectionChange = "removed"   // only in the base result
+	SectionChangeChanged   SectionChange = "changed"   // in both results with different content
+	SectionChangeUnchanged SectionChange = "unchanged" // in both results with the same content
+)
+
+// DiffLineOp tells whether a line of a section was kept, added or removed
+type DiffLineOp string
+
+const (
+	DiffLineEqual  DiffLineOp = "equal"
+	DiffLineInsert DiffLineOp = "insert"
+	DiffLineDelete DiffLineOp = "delete"
+)
+
+// ResultDiff compares the requirements of two sessions section by section; sections are matched
+// by their titles
+type ResultDiff struct {
+	BaseSessionID    string            `json:"base_session_id"`
+	CompareSessionID string            `json:"compare_session_id"`
+	Summary          ResultDiffSummary `json:"summary"`
+	Sections         []*SectionDiff    `json:"sections"`
+	Markdown         string            `json:"markdown"`
+}
+
+// ResultDiffSummary counts the sections of a diff by their change
+type ResultDiffSummary struct {
+	Added     int `json:"added"`
+	Removed   int `json:"removed"`
+	Changed   int `json:"changed"`
+	Unchanged int `json:"unchanged"`
+}
+
+// SectionDiff is a section of a result diff; lines are only listed for sections that differ
+type SectionDiff struct {
+	Title        string        `json:"title"`
+	Change       SectionChange `json:"change"`
+	BaseIndex    *int          `json:"base_index,omitempty"`
+	CompareIndex *int          `json:"compare_index,omitempty"`
+	Lines        []DiffLine    `json:"lines,omitempty"`
+}
+
+// DiffLine is a line of a section in a result diff
+type DiffLine struct {
+	Op   DiffLineOp `json:"op"`
+	Text string     `json:"text"`
+}
@@ -5,8 +5,10 @@ import "errors"
 // Domain errors
 var (
 	// Project errors
-	ErrProjectNotFound = errors.New("project not found")
-	ErrInvalidProject  = errors.New("invalid project data")
+	ErrProjectNotFound  = errors.New("project not found")
+	ErrInvalidProject   = errors.New("invalid project data")
+	ErrScheduleNotFound = errors.New("schedule not found")
+	ErrPinLimitReached  = errors.New("pinned projects limit reached")
 
 	// File errors
 	ErrInvalidFile       = errors.New("invalid file")
@@ -14,18 +16,90 @@ var (
 	ErrTooManyFiles      = errors.New("too many files")
 	ErrInvalidExtension  = errors.New("invalid file extension")
 	ErrTotalSizeTooLarge = errors.New("total file size too large")
+	ErrFileNotFound      = errors.New("file not found")
+	ErrFileNotStored     = errors.New("file content is not stored")
 
 	// Session errors
-	ErrSessionNotFound      = errors.New("session not found")
-	ErrSessionNotActive     = errors.New("session is not active")
-	ErrSessionCancelled     = errors.New("session is cancelled")
-	ErrSessionCompleted     = errors.New("session is already completed")
-	ErrInvalidSessionStatus = errors.New("invalid session status")
-	ErrIterationNotFound    = errors.New("iteration not found")
-	ErrIterationExists      = errors.New("iteration already exists")
-	ErrInvalidIteration     = errors.New("invalid iteration number")
-	ErrQuestionNotFound     = errors.New("question not found")
-	ErrNoResult             = errors.New("session result not available")
+	ErrSessionNotFound          = errors.New("session not found")
+	ErrSessionNotActive         = errors.New("session is not active")
+	ErrHeartbeatTooFrequent     = errors.New("session heartbeat is too frequent")
+	ErrGoalTooVague             = errors.New("user goal is too vague")
+	ErrSessionCancelled         = errors.New("session is cancelled")
+	ErrSessionCompleted         = errors.New("session is already completed")
+	ErrInvalidSessionStatus     = errors.New("invalid session status")
+	ErrIterationNotFound        = errors.New("iteration not found")
+	ErrIterationExists          = errors.New("iteration already exists")
+	ErrInvalidIteration         = errors.New("invalid iteration number")
+	ErrQuestionNotFound         = errors.New("question not found")
+	ErrExplanationNotFound      = errors.New("question has no generated explanation")
+	ErrExplanationCooldown      = errors.New("explanations are requested too often")
+	ErrQuestionsNotReady        = errors.New("questions are still being generated")
+	ErrNoResult                 = errors.New("session result not available")
+	ErrResultVersionNotFound    = errors.New("result version not found")
+	ErrResultVersionPurged      = errors.New("result version is no longer stored")
+	ErrTranslationNotFound      = errors.New("translation not found")
+	ErrSectionNotFound          = errors.New("result section not found")
+	ErrCommentNotFound          = errors.New("comment not found")
+	ErrNoOpenComments           = errors.New("no unresolved comments")
+	ErrNoBaseline               = errors.New("project has no requirements to compare with")
+	ErrNoChangeLog              = errors.New("change log not available")
+	ErrConflictNotFound         = errors.New("conflict not found or already resolved")
+	ErrUnresolvedConflicts      = errors.New("result has unresolved conflicts")
+	ErrDemoSession              = errors.New("action is unavailable in a demo session")
+	ErrTimeBudgetNotFound       = errors.New("session has no time budget")
+	ErrPendingQuestionsNotFound = errors.New("no undelivered questions for the iteration")
+	ErrSessionFactsNotFound     = errors.New("session has no context snapshot")
+	ErrSessionBusy              = errors.New("session is busy with another action")
+	ErrOperationInProgress      = errors.New("another operation is in progress on the session")
+	ErrNoOperation              = errors.New("no operation is in progress on the session")
+	ErrLinkCodeInvalid          = errors.New("link code is invalid, expired or already used")
+	ErrAccountNotLinked         = errors.New("client is not linked to a telegram account")
+
+	// Review errors
+	ErrReviewNotFound          = errors.New("review not found")
+	ErrInvalidReviewTransition = errors.New("invalid review transition")
+	ErrNotApprover             = errors.New("not an assigned approver")
+	ErrResultNotApproved       = errors.New("result is not approved")
+
+	// Tenant errors
+	ErrTenantNotFound = errors.New("tenant not found")
+	ErrTenantExists   = errors.New("tenant already exists")
+	ErrInvalidAPIKey  = errors.New("invalid API key")
+	ErrUserNotFound   = errors.New("user not found")
+
+	// Blob storage errors
+	ErrObjectNotFound = errors.New("stored object not found")
+
+	// Theme errors
+	ErrThemeNotFound          = errors.New("document theme not found")
+	ErrThemeAssetsUnavailable = errors.New("theme assets require result blob storage")
+
+	// Feature flag errors
+	ErrFeatureFlagNotFound = errors.New("feature flag not found")
+
+	// Quota errors
+	ErrQuotaExceeded = errors.New("usage quota exceeded")
+
+	// Operation errors
+	ErrOperationNotFound = errors.New("operation not found")
+
+	// Callback outbox errors
+	ErrCallbackDeliveryNotFound = errors.New("callback delivery not found")
+
+	// Incident errors
+	ErrIncidentNotFound = errors.New("incident not found")
+
+	// Generation errors
+	ErrAdminApprovalRequired = errors.New("generation requires admin approval")
+
+	// Moderation errors
+	ErrContentBlocked = errors.New("content blocked by moderation")
+
+	// LLM errors
+	ErrLLMOverloaded = errors.New("llm service is overloaded")
+
+	// ASR errors
+	ErrASRUnavailable = errors.New("speech recognition is temporarily unavailable")
 
 	// Validation errors
 	ErrMissingField     = errors.New("required field is missing")
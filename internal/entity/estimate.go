@@ -0,0 +1,20 @@
+package entity
+
+// GenerationEstimate is a pre-estimate of the final generation step based on collected material size
+type GenerationEstimate struct {
+	SessionID             string   `json:"session_id"`
+	MaterialChars         int      `json:"material_chars"`
+	EstimatedTokens       int      `json:"estimated_tokens"`
+	EstimatedSeconds      int      `json:"estimated_seconds"`
+	EstimatedCost         *float64 `json:"estimated_cost,omitempty"` // Set only when token accounting is enabled
+	Currency              string   `json:"currency,omitempty"`
+	RequiresConfirmation  bool     `json:"requires_confirmation"`
+	RequiresAdminApproval bool     `json:"requires_admin_approval"`
+	AdminApproved         bool     `json:"admin_approved"`
+	Sectioned             bool     `json:"sectioned"` // Document is generated section by section
+}
+
+// AwaitingApproval reports whether generation is blocked until an admin approves it
+func (e *GenerationEstimate) AwaitingApproval() bool {
+	return e.RequiresAdminApproval && !e.AdminApproved
+}
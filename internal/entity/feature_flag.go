@@ -0,0 +1,54 @@
+package entity
+
+import "time"
+
+// FeatureFlag names a risky capability that is rolled out gradually
+type FeatureFlag string
+
+const (
+	FeatureStreaming             FeatureFlag = "streaming"
+	FeatureIncrementalValidation FeatureFlag = "incremental_validation"
+	FeatureHybridMode            FeatureFlag = "hybrid_mode"
+)
+
+// KnownFeatureFlags lists the flags that can be configured and overridden
+var KnownFeatureFlags = []FeatureFlag{
+	FeatureStreaming,
+	FeatureIncrementalValidation,
+	FeatureHybridMode,
+}
+
+// IsKnownFeatureFlag reports whether name is one of KnownFeatureFlags
+func IsKnownFeatureFlag(name string) bool {
+	for _, flag := range KnownFeatureFlags {
+		if string(flag) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// FeatureFlagOverride is an admin override of a flag stored in the database;
+// it takes precedence over the configured rollout
+type FeatureFlagOverride struct {
+	Name           string
+	RolloutPercent *int // nil keeps the configured rollout
+	Disabled       bool // kill switch, turns the flag off for everyone
+	UpdatedAt      time.Time
+}
+
+// FeatureFlagState describes the effective state of a flag for the admin API
+type FeatureFlagState struct {
+	Name              string     `json:"name"`
+	ConfiguredPercent int        `json:"configured_percent"`
+	OverridePercent   *int       `json:"override_percent,omitempty"`
+	Disabled          bool       `json:"disabled"`
+	RolloutPercent    int        `json:"rollout_percent"` // effective share of sessions with the flag on
+	UpdatedAt         *time.Time `json:"updated_at,omitempty"`
+}
+
+// SetFeatureFlagRequest represents an admin request to override a flag
+type SetFeatureFlagRequest struct {
+	RolloutPercent *int `json:"rollout_percent,omitempty"`
+	Disabled       bool `json:"disabled"`
+}
@@ -0,0 +1,63 @@
+package entity
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+)
+
+// CorrelationIDField is the log field carrying the correlation ID of a request or a bot update
+const CorrelationIDField = "correlation_id"
+
+// Incident is an error logged while handling a request or a bot update, found by the incident code
+// the user was shown
+type Incident struct {
+	ID            string         `json:"id"`
+	Code          string         `json:"code"`
+	CorrelationID string         `json:"correlation_id"`
+	Level         string         `json:"level"`
+	Message       string         `json:"message"`
+	Fields        map[string]any `json:"fields,omitempty"`
+	CreatedAt     time.Time      `json:"created_at"`
+}
+
+type correlationIDContextKey struct{}
+
+// WithCorrelationID ties the work in ctx to a request or a bot update
+func WithCorrelationID(ctx context.Context, correlationID string) context.Context {
+	if correlationID == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, correlationIDContextKey{}, correlationID)
+}
+
+// CorrelationIDFromContext returns the correlation ID of the work in ctx, empty outside requests and updates
+func CorrelationIDFromContext(ctx context.Context) string {
+	correlationID, _ := ctx.Value(correlationIDContextKey{}).(string)
+	return correlationID
+}
+
+// NewCorrelationID returns a random correlation ID
+func NewCorrelationID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// IncidentCode derives the short code users quote to support from a correlation ID, e.g. A1B2C3
+func IncidentCode(correlationID string) string {
+	sum := sha256.Sum256([]byte(correlationID))
+	return strings.ToUpper(hex.EncodeToString(sum[:3]))
+}
+
+// IncidentCodeFromContext returns the incident code of the work in ctx, empty outside requests and updates
+func IncidentCodeFromContext(ctx context.Context) string {
+	correlationID := CorrelationIDFromContext(ctx)
+	if correlationID == "" {
+		return ""
+	}
+	return IncidentCode(correlationID)
+}
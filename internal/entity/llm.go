@@ -1,5 +1,37 @@
 package entity
 
+import "context"
+
+// LLMPriority orders LLM calls waiting for a free slot
+type LLMPriority int
+
+const (
+	// LLMPriorityBatch is the priority of background work such as HTTP async operations
+	LLMPriorityBatch LLMPriority = iota
+	// LLMPriorityInteractive is the priority of calls a user waits for in a chat
+	LLMPriorityInteractive
+)
+
+func (p LLMPriority) String() string {
+	if p == LLMPriorityInteractive {
+		return "interactive"
+	}
+	return "batch"
+}
+
+type llmPriorityContextKey struct{}
+
+// WithLLMPriority sets the priority of the LLM calls made with ctx
+func WithLLMPriority(ctx context.Context, priority LLMPriority) context.Context {
+	return context.WithValue(ctx, llmPriorityContextKey{}, priority)
+}
+
+// LLMPriorityFromContext returns the priority of the LLM calls made with ctx, batch when it is not set
+func LLMPriorityFromContext(ctx context.Context) LLMPriority {
+	priority, _ := ctx.Value(llmPriorityContextKey{}).(LLMPriority)
+	return priority
+}
+
 type UserContext struct {
 	Goal  string `json:"goal"`
 	Task  string `json:"task"`
@@ -10,11 +42,18 @@ type LLMGenerateQuestionsRequest struct {
 	UserGoal           string  `json:"user_goal"`
 	ProjectContext     string  `json:"project_context"`
 	ProjectDescription *string `json:"project_description,omitempty"`
+	// TargetLanguage is the language of the user inputs, the output must be written in it;
+	// the target_language of the other requests means the same
+	TargetLanguage string `json:"target_language,omitempty"`
 }
 
 type LLMQuestion struct {
-	Text        string `json:"text"`
-	Explanation string `json:"explanation"`
+	Text        string   `json:"text"`
+	Explanation string   `json:"explanation"`
+	Options     []string `json:"options,omitempty"`
+	Type        string   `json:"type,omitempty"` // "scale" for questions rated from 1 to 5, text otherwise
+	// ParentQuestion is the text of the answered question a follow-up question clarifies
+	ParentQuestion string `json:"parent_question,omitempty"`
 }
 
 type QuestionsBlock struct {
@@ -33,9 +72,15 @@ type QuestionWithAnswer struct {
 
 type LLMValidateAnswersRequest struct {
 	CompleteQuestions  []QuestionWithAnswer `json:"answered_questions"`
+	DeclinedQuestions  []string             `json:"declined_questions,omitempty"` // skipped as not relevant, must not be asked again
 	UserGoal           string               `json:"user_goal"`
 	ProjectContext     string               `json:"project_context"`
 	ProjectDescription *string              `json:"project_description,omitempty"`
+	// Conversation is the latest part of the interview in the order it happened, when enabled
+	Conversation []ConversationEntry `json:"conversation,omitempty"`
+	// KnownFacts are the facts the user already provided, topics they cover must not be asked about
+	KnownFacts     []string `json:"known_facts,omitempty"`
+	TargetLanguage string   `json:"target_language,omitempty"`
 }
 
 type LLMValidateAnswersResponse struct {
@@ -47,18 +92,37 @@ type LLMGenerateSummaryRequest struct {
 	UserGoal           string               `json:"user_goal"`
 	ProjectContext     string               `json:"project_context"`
 	ProjectDescription *string              `json:"project_description,omitempty"`
+	Conversation       []ConversationEntry  `json:"conversation,omitempty"`
+	TargetLanguage     string               `json:"target_language,omitempty"`
+	// Style is the tone and structure of the document, empty for the default
+	Style SummaryStyle `json:"style,omitempty"`
 }
 
 type LLMGenerateSummaryResponse struct {
 	Result string `json:"result"`
 }
 
+// LLMSummaryStreamEvent is the data of one server-sent event of a streamed summary
+type LLMSummaryStreamEvent struct {
+	Content string `json:"content"`
+	Error   string `json:"error,omitempty"`
+}
+
+// LLMSummaryChunk is the next piece of a streamed summary, the last chunk of a failed stream carries Err
+type LLMSummaryChunk struct {
+	Content string
+	Err     error
+}
+
 type LLMValidateDraftRequest struct {
 	Messages            []string             `json:"messages"`
 	AdditionalQuestions []QuestionWithAnswer `json:"additional_questions"`
 	UserGoal            string               `json:"user_goal"`
 	ProjectContext      string               `json:"project_context"`
 	ProjectDescription  *string              `json:"project_description,omitempty"`
+	// KnownFacts are the facts the user already provided, topics they cover must not be asked about
+	KnownFacts     []string `json:"known_facts,omitempty"`
+	TargetLanguage string   `json:"target_language,omitempty"`
 }
 
 type LLMGenerateDraftSummaryRequest struct {
@@ -67,4 +131,168 @@ type LLMGenerateDraftSummaryRequest struct {
 	UserGoal            string               `json:"user_goal"`
 	ProjectContext      string               `json:"project_context"`
 	ProjectDescription  *string              `json:"project_description,omitempty"`
+	TargetLanguage      string               `json:"target_language,omitempty"`
+	// Style is the tone and structure of the document, empty for the default
+	Style SummaryStyle `json:"style,omitempty"`
+}
+
+// DocumentSection is an outline entry of a sectioned requirements document
+type DocumentSection struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+}
+
+// LLMSectionedContext is the collected material shared by outline and section generation
+type LLMSectionedContext struct {
+	Messages           []string             `json:"messages,omitempty"`
+	CompleteQuestions  []QuestionWithAnswer `json:"answered_questions"`
+	UserGoal           string               `json:"user_goal"`
+	ProjectContext     string               `json:"project_context"`
+	ProjectDescription *string              `json:"project_description,omitempty"`
+	Conversation       []ConversationEntry  `json:"conversation,omitempty"`
+	TargetLanguage     string               `json:"target_language,omitempty"`
+	// Style is the tone and structure of the document, empty for the default
+	Style SummaryStyle `json:"style,omitempty"`
+}
+
+type LLMGenerateOutlineRequest struct {
+	LLMSectionedContext
+}
+
+type LLMGenerateOutlineResponse struct {
+	Sections []DocumentSection `json:"sections"`
+}
+
+type LLMGenerateSectionRequest struct {
+	LLMSectionedContext
+	Outline      []DocumentSection `json:"outline"`
+	Section      DocumentSection   `json:"section"`
+	SectionIndex int               `json:"section_index"`
+	// Set when regenerating an existing section
+	CurrentContent string `json:"current_content,omitempty"`
+	Guidance       string `json:"guidance,omitempty"`
+}
+
+// LLMGenerateDeltaQuestionsRequest asks only about changes relative to the baseline document
+type LLMGenerateDeltaQuestionsRequest struct {
+	Baseline           string  `json:"baseline"`
+	UserGoal           string  `json:"user_goal"`
+	ProjectContext     string  `json:"project_context"`
+	ProjectDescription *string `json:"project_description,omitempty"`
+	TargetLanguage     string  `json:"target_language,omitempty"`
+}
+
+// LLMGenerateDeltaSummaryRequest applies the answered changes to the baseline document
+type LLMGenerateDeltaSummaryRequest struct {
+	Baseline           string               `json:"baseline"`
+	CompleteQuestions  []QuestionWithAnswer `json:"answered_questions"`
+	UserGoal           string               `json:"user_goal"`
+	ProjectContext     string               `json:"project_context"`
+	ProjectDescription *string              `json:"project_description,omitempty"`
+	TargetLanguage     string               `json:"target_language,omitempty"`
+	// Style is the tone and structure of the document, empty for the default
+	Style SummaryStyle `json:"style,omitempty"`
+}
+
+// LLMGenerateDeltaSummaryResponse holds the change log and the updated full document
+type LLMGenerateDeltaSummaryResponse struct {
+	ChangeLog string `json:"change_log"`
+	Result    string `json:"result"`
+}
+
+// LLMDetectConflictsRequest compares new requirements with earlier project requirements
+type LLMDetectConflictsRequest struct {
+	Requirements         string  `json:"requirements"`
+	ProjectContext       string  `json:"project_context"`
+	PreviousRequirements *string `json:"previous_requirements,omitempty"`
+}
+
+// LLMConflict is a new requirement contradicting an earlier statement
+type LLMConflict struct {
+	Requirement string `json:"requirement"`
+	Previous    string `json:"previous"`
+	Source      string `json:"source,omitempty"`
+}
+
+type LLMDetectConflictsResponse struct {
+	Conflicts []LLMConflict `json:"conflicts"`
+}
+
+// DocumentComment is a reviewer comment addressed by the refinement pass
+type DocumentComment struct {
+	SectionTitle  string `json:"section_title,omitempty"`
+	RequirementID string `json:"requirement_id,omitempty"`
+	Text          string `json:"text"`
+}
+
+type LLMRefineResultRequest struct {
+	Result         string            `json:"result"`
+	Comments       []DocumentComment `json:"comments"`
+	TargetLanguage string            `json:"target_language,omitempty"`
+}
+
+// LLMRestyleResultRequest asks to rewrite a requirements document in another style without
+// changing its content
+type LLMRestyleResultRequest struct {
+	Result         string       `json:"result"`
+	Style          SummaryStyle `json:"style"`
+	TargetLanguage string       `json:"target_language,omitempty"`
+}
+
+type LLMTranslateRequest struct {
+	Text           string `json:"text"`
+	TargetLanguage string `json:"target_language"`
+}
+
+type LLMTranslateResponse struct {
+	Result string `json:"result"`
+}
+
+// LLMNormalizeTranscriptRequest asks to fix spelling, punctuation and ASR artifacts
+// without changing the meaning of a transcription
+type LLMNormalizeTranscriptRequest struct {
+	Text string `json:"text"`
+}
+
+// LLMExtractFactsRequest asks for the facts the user already provided in the session, condensed
+// into short statements; KnownFacts are the facts extracted earlier, kept unless contradicted
+type LLMExtractFactsRequest struct {
+	Messages           []string             `json:"messages,omitempty"`
+	CompleteQuestions  []QuestionWithAnswer `json:"answered_questions,omitempty"`
+	UserGoal           string               `json:"user_goal"`
+	ProjectContext     string               `json:"project_context"`
+	ProjectDescription *string              `json:"project_description,omitempty"`
+	KnownFacts         []string             `json:"known_facts,omitempty"`
+	MaxFacts           int                  `json:"max_facts"`
+}
+
+type LLMExtractFactsResponse struct {
+	Facts []string `json:"facts"`
+}
+
+// LLMDescribeProjectRequest asks for a concise project description from saved requirements;
+// Description is the text the user typed, used as a hint
+type LLMDescribeProjectRequest struct {
+	Title        string `json:"title"`
+	Description  string `json:"description,omitempty"`
+	Requirements string `json:"requirements"`
+	MaxChars     int    `json:"max_chars"`
+}
+
+type LLMDescribeProjectResponse struct {
+	Description string `json:"description"`
+}
+
+// LLMExplainQuestionRequest asks why a question is asked and what a good answer covers;
+// Explanation is the short explanation generated with the question, used as a hint
+type LLMExplainQuestionRequest struct {
+	Question       string `json:"question"`
+	Explanation    string `json:"explanation,omitempty"`
+	UserGoal       string `json:"user_goal"`
+	ProjectContext string `json:"project_context"`
+	TargetLanguage string `json:"target_language,omitempty"`
+}
+
+type LLMExplainQuestionResponse struct {
+	Explanation string `json:"explanation"`
 }
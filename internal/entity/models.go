@@ -2,6 +2,8 @@ package entity
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -35,10 +37,16 @@ const (
 	SessionStatusDone     SessionStatus = "DONE"     // Session completed successfully
 	SessionStatusError    SessionStatus = "ERROR"    // Session failed with error
 	SessionStatusCanceled SessionStatus = "CANCELED" // Session cancelled by user
+	// Generation failed repeatedly, the result is a document of the collected materials; it can be generated again
+	SessionStatusPartial SessionStatus = "PARTIAL"
 
 	// Project save states
 	SessionStatusAskProjectName        SessionStatus = "ASK_PROJECT_NAME"        // Asking for new project name
 	SessionStatusAskProjectDescription SessionStatus = "ASK_PROJECT_DESCRIPTION" // Asking for new project description
+
+	// Result editing states
+	SessionStatusAskSectionGuidance SessionStatus = "ASK_SECTION_GUIDANCE" // Asking for section regeneration guidance
+	SessionStatusAskResultFeedback  SessionStatus = "ASK_RESULT_FEEDBACK"  // Asking for feedback on the whole result
 )
 
 type SessionType string
@@ -46,11 +54,12 @@ type SessionType string
 const (
 	SessionTypeDraft     SessionType = "DRAFT"
 	SessionTypeInterview SessionType = "INTERVIEW"
+	SessionTypeDelta     SessionType = "DELTA" // Interview about changes since the latest project requirements
 )
 
 func (st *SessionType) Validate() error {
 	switch *st {
-	case SessionTypeDraft, SessionTypeInterview:
+	case SessionTypeDraft, SessionTypeInterview, SessionTypeDelta:
 		return nil
 	default:
 		return fmt.Errorf("unknown session type: %s", *st)
@@ -63,20 +72,93 @@ const (
 	AnswerStatusUnanswered QuestionStatus = "UNANSWERED"
 	AnswerStatusSkiped     QuestionStatus = "SKIPED"
 	AnswerStatusAnswered   QuestionStatus = "ANSWERED"
+	// AnswerStatusDeferred questions are asked again after the last block, before validation
+	AnswerStatusDeferred QuestionStatus = "DEFERRED"
+)
+
+// SkipReason is the quick reason the user gave for skipping a question
+type SkipReason string
+
+const (
+	SkipReasonUnknown    SkipReason = "DONT_KNOW"
+	SkipReasonIrrelevant SkipReason = "NOT_RELEVANT"
+	SkipReasonLater      SkipReason = "ANSWER_LATER"
 )
 
+func (r SkipReason) IsValid() bool {
+	switch r {
+	case SkipReasonUnknown, SkipReasonIrrelevant, SkipReasonLater:
+		return true
+	default:
+		return false
+	}
+}
+
+// QuestionAnswerType is the kind of answer the LLM expects for a question
+type QuestionAnswerType string
+
+const (
+	QuestionAnswerTypeText QuestionAnswerType = "text"
+	// QuestionAnswerTypeScale questions are rated from ScaleMin to ScaleMax, e.g. "how critical is performance (1–5)?"
+	QuestionAnswerTypeScale QuestionAnswerType = "scale"
+)
+
+// Bounds of the rating of scale questions
+const (
+	ScaleMin = 1
+	ScaleMax = 5
+)
+
+// ParseQuestionAnswerType converts the type the LLM marked a question with, unknown types are text questions
+func ParseQuestionAnswerType(value string) QuestionAnswerType {
+	if QuestionAnswerType(strings.ToLower(strings.TrimSpace(value))) == QuestionAnswerTypeScale {
+		return QuestionAnswerTypeScale
+	}
+	return QuestionAnswerTypeText
+}
+
+// ParseScaleAnswer returns the rating an answer to a scale question starts with,
+// so that both "4" and "4, но только в пиковые часы" are rated 4
+func ParseScaleAnswer(answer string) (int, bool) {
+	answer = strings.TrimSpace(answer)
+	end := 0
+	for end < len(answer) && answer[end] >= '0' && answer[end] <= '9' {
+		end++
+	}
+	if end == 0 {
+		return 0, false
+	}
+
+	value, err := strconv.Atoi(answer[:end])
+	if err != nil || value < ScaleMin || value > ScaleMax {
+		return 0, false
+	}
+	return value, true
+}
+
 type Session struct {
-	ID               string        `json:"session_id"`
-	ProjectID        *string       `json:"project_id,omitempty"`
-	Status           SessionStatus `json:"session_status"`
-	Type             *SessionType  `json:"session_type,omitempty"`
-	UserGoal         *string       `json:"user_goal,omitempty"`
-	ProjectContext   *string       `json:"project_context,omitempty"`
-	CurrentIteration int           `json:"iteration_number"`
-	Result           *string       `json:"final_result,omitempty"`
-	Error            *string       `json:"error,omitempty"`
-	CreatedAt        time.Time     `json:"created_at"`
-	UpdatedAt        time.Time     `json:"updated_at"`
+	ID                  string              `json:"session_id"`
+	ProjectID           *string             `json:"project_id,omitempty"`
+	Status              SessionStatus       `json:"session_status"`
+	Type                *SessionType        `json:"session_type,omitempty"`
+	UserGoal            *string             `json:"user_goal,omitempty"`
+	ProjectContext      *string             `json:"project_context,omitempty"`
+	CurrentIteration    int                 `json:"iteration_number"`
+	Result              *string             `json:"final_result,omitempty"`
+	Error               *string             `json:"error,omitempty"`
+	IsDemo              bool                `json:"is_demo,omitempty"`
+	CreatedAt           time.Time           `json:"created_at"`
+	UpdatedAt           time.Time           `json:"updated_at"`
+	LastActivityAt      *time.Time          `json:"last_activity_at,omitempty"`     // last heartbeat of an external orchestrator
+	CallbackGranularity CallbackGranularity `json:"callback_granularity,omitempty"` // callback events of an API session
+	CurrentQuestionID   *string             `json:"current_question_id,omitempty"`  // first open question of the interview
+	OwnerID             *string             `json:"owner_id,omitempty"`             // user who created the session, nil when shared in the tenant
+	Language            *ResultLanguage     `json:"language,omitempty"`             // language of the user inputs, the target language of the documents
+	LanguageExplicit    bool                `json:"language_explicit,omitempty"`    // language set by the user, not detected
+	SummaryStyle        *SummaryStyle       `json:"summary_style,omitempty"`        // style of the generated document, nil for the LLM default
+	PurgedAt            *time.Time          `json:"purged_at,omitempty"`            // content removed by the retention policy of the tenant
+	// CurrentQuestion is the question of CurrentQuestionID, filled when a single session is read
+	CurrentQuestion *Question `json:"current_question,omitempty"`
 }
 
 type Iteration struct {
@@ -95,8 +177,18 @@ type Question struct {
 	Question       string         `json:"question"`
 	Explanation    string         `json:"explanation"`
 	Answer         *string        `json:"answer,omitempty"`
-	CreatedAt      time.Time      `json:"created_at"`
-	AnsweredAt     *time.Time     `json:"answered_at,omitempty"`
+	// RawAnswer is the original transcription when the normalization pass changed it
+	RawAnswer  *string     `json:"raw_answer,omitempty"`
+	SkipReason *SkipReason `json:"skip_reason,omitempty"`
+	// Options are answers suggested by the LLM, the user may still answer freely
+	Options    []string           `json:"options,omitempty"`
+	AnswerType QuestionAnswerType `json:"answer_type,omitempty"`
+	// ScaleValue is the rating of an answered scale question
+	ScaleValue *int `json:"scale_value,omitempty"`
+	// ParentQuestionID is the answered question a follow-up question clarifies
+	ParentQuestionID *string    `json:"parent_question_id,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+	AnsweredAt       *time.Time `json:"answered_at,omitempty"`
 }
 
 type Project struct {
@@ -104,7 +196,24 @@ type Project struct {
 	Title       string    `json:"title"`
 	Description string    `json:"description"`
 	CreatedAt   time.Time `json:"created_at"`
+	ThemeID     *string   `json:"theme_id,omitempty"` // overrides the document theme of the tenant
+	OwnerID     *string   `json:"owner_id,omitempty"` // user who created the project, nil when shared in the tenant
 	Files       []*File   `json:"files,omitempty"`
+	// SessionCount, LastUsedAt, LastIndexedAt and Stale are filled only when projects are listed
+	SessionCount  int        `json:"session_count,omitempty"`
+	LastUsedAt    *time.Time `json:"last_used_at,omitempty"`
+	LastIndexedAt *time.Time `json:"last_indexed_at,omitempty"` // when files were last indexed in RAG
+	Stale         bool       `json:"stale,omitempty"`           // files were last indexed too long ago
+	Pinned        bool       `json:"pinned,omitempty"`          // pinned by the Telegram user the projects are listed for
+}
+
+// ProjectFreshness tells how old the indexed materials of a project are
+type ProjectFreshness struct {
+	FileCount     int        `json:"file_count"`
+	LastIndexedAt *time.Time `json:"last_indexed_at,omitempty"`
+	OldestFileAt  *time.Time `json:"oldest_file_at,omitempty"`
+	// Stale is set when the files were last indexed longer ago than PROJECT_FRESHNESS_STALE_AFTER
+	Stale bool `json:"stale"`
 }
 
 type File struct {
@@ -114,12 +223,190 @@ type File struct {
 	Size        int64     `json:"size"`
 	ContentType string    `json:"content_type"`
 	CreatedAt   time.Time `json:"created_at"`
+	ObjectKey   *string   `json:"-"` // original content in the blob storage, nil when it is not kept
 }
 
 // SessionMessage represents a draft message in a session
 type SessionMessage struct {
-	ID          string    `json:"id"`
-	SessionID   string    `json:"session_id"`
-	MessageText string    `json:"message_text"`
-	CreatedAt   time.Time `json:"created_at"`
+	ID          string `json:"id"`
+	SessionID   string `json:"session_id"`
+	MessageText string `json:"message_text"`
+	// RawMessageText is the original transcription when the normalization pass changed it
+	RawMessageText *string   `json:"raw_message_text,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// ConversationEntryKind is what happened to a question in the interview log
+type ConversationEntryKind string
+
+const (
+	ConversationEntryAnswer ConversationEntryKind = "answer"
+	ConversationEntrySkip   ConversationEntryKind = "skip"
+	ConversationEntryDefer  ConversationEntryKind = "defer"
+	// ConversationEntryClarification is a follow-up question asked after validating the answers
+	ConversationEntryClarification ConversationEntryKind = "clarification"
+	// ConversationEntryEdit replaces an earlier answer to the question
+	ConversationEntryEdit ConversationEntryKind = "edit"
+)
+
+// ConversationEntry is one step of the interview in the order it happened
+type ConversationEntry struct {
+	Kind      ConversationEntryKind `json:"kind"`
+	Question  string                `json:"question"`
+	Content   string                `json:"content,omitempty"` // the answer for answer entries
+	CreatedAt time.Time             `json:"created_at"`
+}
+
+// SearchSource is the kind of collected session material a search hit comes from
+type SearchSource string
+
+const (
+	SearchSourceAnswer       SearchSource = "answer"
+	SearchSourceDraftMessage SearchSource = "draft_message"
+)
+
+// SessionSearchHit is a piece of collected session material matching a search query
+type SessionSearchHit struct {
+	Source    SearchSource `json:"source"`
+	ID        string       `json:"id"`
+	Question  *string      `json:"question,omitempty"`
+	Snippet   string       `json:"snippet"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+// AdminSearchSource is the kind of record an admin search hit comes from
+type AdminSearchSource string
+
+const (
+	AdminSearchSourceProject       AdminSearchSource = "project"
+	AdminSearchSourceFile          AdminSearchSource = "file"
+	AdminSearchSourceSessionGoal   AdminSearchSource = "session_goal"
+	AdminSearchSourceSessionResult AdminSearchSource = "session_result"
+)
+
+// AdminSearchHit is a project, file or session matching an admin search query.
+// ID is the project, file or session ID depending on Source.
+type AdminSearchHit struct {
+	Source    AdminSearchSource `json:"source"`
+	ID        string            `json:"id"`
+	ProjectID *string           `json:"project_id,omitempty"`
+	Snippet   string            `json:"snippet"`
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+// ResultSection is a separately generated section of a sectioned requirements document
+type ResultSection struct {
+	SessionID    string    `json:"session_id"`
+	SectionIndex int       `json:"section_index"`
+	Title        string    `json:"title"`
+	Description  string    `json:"description"`
+	Content      string    `json:"content"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// ResultStorage is where the body of a result version is kept
+type ResultStorage string
+
+const (
+	// ResultStorageInline keeps the body in the sessions table
+	ResultStorageInline ResultStorage = "inline"
+	// ResultStorageBlob keeps the body in S3-compatible storage
+	ResultStorageBlob ResultStorage = "blob"
+	// ResultStoragePurged marks a version whose body was removed by the retention policy
+	ResultStoragePurged ResultStorage = "purged"
+)
+
+// ResultVersion is the metadata of a generated session result version
+type ResultVersion struct {
+	ID        string        `json:"id"`
+	SessionID string        `json:"session_id"`
+	Version   int           `json:"version"`
+	Storage   ResultStorage `json:"storage"`
+	ObjectKey *string       `json:"object_key,omitempty"`
+	SizeBytes int           `json:"size_bytes"`
+	Checksum  string        `json:"checksum"`
+	CreatedAt time.Time     `json:"created_at"`
+	Available bool          `json:"available"` // the body can still be downloaded
+	Content   *string       `json:"-"`         // body of a replaced inline version; the current one is the session result
+}
+
+// ResultVersionsResponse lists the generated result versions of a session, newest first
+type ResultVersionsResponse struct {
+	SessionID string           `json:"session_id"`
+	Current   int              `json:"current"` // version returned by /result, 0 without a result
+	Versions  []*ResultVersion `json:"versions"`
+}
+
+// ResultFileInfo describes a session result for building document file names
+type ResultFileInfo struct {
+	Title   string         // project title, empty for sessions without a project
+	Date    time.Time      // last update of the result
+	Version int            // interview iteration the result was generated from
+	Theme   *DocumentTheme // branding of PDF and DOCX documents, nil for unbranded results
+}
+
+// SessionComment is a reviewer comment attached to a result section or requirement
+type SessionComment struct {
+	ID            string     `json:"id"`
+	SessionID     string     `json:"session_id"`
+	SectionIndex  *int       `json:"section_index,omitempty"`
+	RequirementID *string    `json:"requirement_id,omitempty"`
+	Author        string     `json:"author,omitempty"`
+	Text          string     `json:"text"`
+	Resolved      bool       `json:"resolved"`
+	ResolvedAt    *time.Time `json:"resolved_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// PendingVoiceAnswer is a Telegram voice answer received while speech recognition was unavailable,
+// waiting to be transcribed and submitted
+type PendingVoiceAnswer struct {
+	ID             string
+	TenantID       string
+	SessionID      string
+	QuestionID     string
+	TelegramUserID int64
+	Audio          []byte
+	CreatedAt      time.Time
+}
+
+// SessionTimeBudget is the interview time budget of a session
+type SessionTimeBudget struct {
+	SessionID string
+	Budget    time.Duration
+	StartedAt time.Time
+	WarnedAt  *time.Time
+}
+
+// SessionFacts is the context snapshot of a session: the facts the user already provided and
+// the hash of the material they were extracted from
+type SessionFacts struct {
+	SessionID    string
+	Facts        []string
+	MaterialHash string
+	UpdatedAt    time.Time
+}
+
+// TimeBudgetStatus tells how much of the interview time budget is used
+type TimeBudgetStatus struct {
+	Budget  time.Duration
+	Elapsed time.Duration
+	Warn    bool // set only by the check that first crosses the warning threshold
+}
+
+// Remaining returns the time left in the budget, never negative
+func (s *TimeBudgetStatus) Remaining() time.Duration {
+	if s.Elapsed >= s.Budget {
+		return 0
+	}
+	return s.Budget - s.Elapsed
+}
+
+// SessionDelta is the baseline document of a delta session and the change log produced from it
+type SessionDelta struct {
+	SessionID         string    `json:"session_id"`
+	BaselineSessionID *string   `json:"baseline_session_id,omitempty"`
+	Baseline          string    `json:"-"`
+	ChangeLog         *string   `json:"change_log,omitempty"`
+	UpdatedAt         time.Time `json:"updated_at"`
 }
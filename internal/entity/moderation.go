@@ -0,0 +1,46 @@
+package entity
+
+type ModerationAction string
+
+const (
+	ModerationActionWarn   ModerationAction = "warn"
+	ModerationActionRedact ModerationAction = "redact"
+	ModerationActionBlock  ModerationAction = "block"
+)
+
+func (a ModerationAction) IsValid() bool {
+	switch a {
+	case ModerationActionWarn, ModerationActionRedact, ModerationActionBlock:
+		return true
+	default:
+		return false
+	}
+}
+
+type ModerationSource string
+
+const (
+	ModerationSourceAnswer       ModerationSource = "answer"
+	ModerationSourceDraftMessage ModerationSource = "draft_message"
+	ModerationSourceGuidance     ModerationSource = "section_guidance"
+	ModerationSourceComment      ModerationSource = "comment"
+	ModerationSourceFeedback     ModerationSource = "result_feedback"
+)
+
+// ModerationResult is the outcome of checking a user input
+type ModerationResult struct {
+	Flagged      bool             `json:"flagged"`
+	Action       ModerationAction `json:"action,omitempty"`
+	MatchedTerms []string         `json:"matched_terms,omitempty"`
+	Categories   []string         `json:"categories,omitempty"`
+	Text         string           `json:"-"` // Text to persist (redacted when action is redact)
+}
+
+type ModerationAPIRequest struct {
+	Text string `json:"text"`
+}
+
+type ModerationAPIResponse struct {
+	Flagged    bool     `json:"flagged"`
+	Categories []string `json:"categories"`
+}
@@ -0,0 +1,87 @@
+package entity
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// OperationStatus is the processing state of an async request to the session API
+type OperationStatus string
+
+const (
+	OperationStatusQueued     OperationStatus = "queued"
+	OperationStatusProcessing OperationStatus = "processing"
+	OperationStatusDone       OperationStatus = "done"
+	OperationStatusError      OperationStatus = "error"
+)
+
+// OperationKind names the async workflow an operation runs
+type OperationKind string
+
+const (
+	OperationKindStartSession      OperationKind = "start_session"
+	OperationKindSubmitAnswer      OperationKind = "submit_answer"
+	OperationKindGenerateSummary   OperationKind = "generate_summary"
+	OperationKindRegenerateSection OperationKind = "regenerate_section"
+	OperationKindRefineResult      OperationKind = "refine_result"
+	OperationKindResultFeedback    OperationKind = "result_feedback"
+	// OperationKindTranscriptSession runs a one-shot session from a transcript up to the result
+	OperationKindTranscriptSession OperationKind = "transcript_session"
+	// Kinds only registered as session operations, the bot runs them too
+	OperationKindValidateAnswers OperationKind = "validate_answers"
+	OperationKindRestyleResult   OperationKind = "restyle_result"
+)
+
+// SessionOperation is a heavy operation in flight on a session, e.g. a generation. One runs at a
+// time per session across the bot and the HTTP API; its holder renews it until it finishes
+type SessionOperation struct {
+	ID        string        `json:"id"`
+	SessionID string        `json:"session_id"`
+	Kind      OperationKind `json:"kind"`
+	Owner     string        `json:"owner"` // usage subject that started it, e.g. tg:<user_id> or client:<client_id>
+	StartedAt time.Time     `json:"started_at"`
+	ExpiresAt time.Time     `json:"expires_at"`
+}
+
+// InProgressError is the error of another operation started on the session while this one runs
+func (op *SessionOperation) InProgressError() error {
+	return fmt.Errorf("%w: %s started at %s", ErrOperationInProgress, op.Kind, op.StartedAt.Format(time.RFC3339))
+}
+
+// Operation tracks an async workflow by its X-Request-ID so that clients without
+// a callback URL can poll for the callback event it produced
+type Operation struct {
+	RequestID string             `json:"request_id"`
+	ClientID  *string            `json:"client_id,omitempty"`
+	Kind      OperationKind      `json:"kind"`
+	SessionID *string            `json:"session_id,omitempty"`
+	Status    OperationStatus    `json:"status"`
+	Event     *CallbackEventType `json:"event,omitempty"`
+	Result    json.RawMessage    `json:"result,omitempty"` // data of the callback event
+	CreatedAt time.Time          `json:"created_at"`
+	UpdatedAt time.Time          `json:"updated_at"`
+}
+
+// ListOperationsRequest pages through the operations of one client
+type ListOperationsRequest struct {
+	ClientID string
+	Skip     int
+	Limit    int
+}
+
+func (r *ListOperationsRequest) Normalize() {
+	if r.Skip < 0 {
+		r.Skip = 0
+	}
+	if r.Limit <= 0 {
+		r.Limit = 20
+	}
+
+	r.Limit = min(r.Limit, 100)
+}
+
+type ListOperationsResponse struct {
+	Operations []*Operation `json:"operations"`
+	Total      int          `json:"total"`
+}
@@ -2,6 +2,7 @@ package entity
 
 import (
 	"mime/multipart"
+	"time"
 )
 
 type ResultFormat string
@@ -21,6 +22,44 @@ func (f ResultFormat) IsValid() bool {
 	}
 }
 
+type ResultLanguage string
+
+const (
+	LanguageRussian ResultLanguage = "ru"
+	LanguageEnglish ResultLanguage = "en"
+	LanguageGerman  ResultLanguage = "de"
+	LanguageFrench  ResultLanguage = "fr"
+	LanguageSpanish ResultLanguage = "es"
+	LanguageChinese ResultLanguage = "zh"
+)
+
+func (l ResultLanguage) IsValid() bool {
+	switch l {
+	case LanguageRussian, LanguageEnglish, LanguageGerman, LanguageFrench, LanguageSpanish, LanguageChinese:
+		return true
+	default:
+		return false
+	}
+}
+
+// SummaryStyle is the tone and structure of the generated requirements document
+type SummaryStyle string
+
+const (
+	SummaryStyleFormal    SummaryStyle = "formal"    // formal specification in the manner of GOST 34
+	SummaryStyleExecutive SummaryStyle = "executive" // concise summary for decision makers
+	SummaryStyleDeveloper SummaryStyle = "developer" // user stories, acceptance criteria and technical details
+)
+
+func (s SummaryStyle) IsValid() bool {
+	switch s {
+	case SummaryStyleFormal, SummaryStyleExecutive, SummaryStyleDeveloper:
+		return true
+	default:
+		return false
+	}
+}
+
 type CreateProjectRequest struct {
 	Title       string
 	Description string
@@ -33,9 +72,14 @@ type CreateProjectResponse struct {
 	ProjectID string `json:"project_id"`
 }
 
+// MaxPinnedProjects is how many favorite projects a Telegram user can pin in the selector
+const MaxPinnedProjects = 3
+
 type ListProjectsRequest struct {
 	Skip  int
 	Limit int
+	// TelegramUserID lists the projects this Telegram user picked first
+	TelegramUserID int64
 }
 
 func (lp *ListProjectsRequest) Normalize() {
@@ -48,12 +92,20 @@ func (lp *ListProjectsRequest) Normalize() {
 
 type ListProjectsResponse struct {
 	Projects []*ProjectSummary `json:"projects"`
+	// Total is the number of projects of all pages
+	Total int `json:"total"`
 }
 
 type ProjectSummary struct {
-	ID          string `json:"id"`
-	Title       string `json:"title"`
-	Description string `json:"description"`
+	ID           string     `json:"id"`
+	Title        string     `json:"title"`
+	Description  string     `json:"description"`
+	SessionCount int        `json:"session_count"`
+	LastUsedAt   *time.Time `json:"last_used_at,omitempty"`
+	// Usage is a human-readable label, e.g. "• 3 сессии • 2 дня назад"
+	Usage         string     `json:"usage"`
+	LastIndexedAt *time.Time `json:"last_indexed_at,omitempty"`
+	Stale         bool       `json:"stale"`
 }
 
 type ProjectDetailResponse struct {
@@ -62,6 +114,8 @@ type ProjectDetailResponse struct {
 	Description string        `json:"description"`
 	Size        int64         `json:"size"`
 	Files       []*FileDetail `json:"files"`
+	// Freshness is left out when it cannot be read
+	Freshness *ProjectFreshness `json:"freshness,omitempty"`
 }
 
 type FileDetail struct {
@@ -85,6 +139,20 @@ type AddFilesResponse struct {
 	Status string `json:"status"`
 }
 
+// CreateScheduleRequest sets up recurring check-in sessions for a project
+type CreateScheduleRequest struct {
+	ProjectID      string `json:"-"`
+	CronExpr       string `json:"cron"`
+	TelegramUserID int64  `json:"telegram_user_id"`
+	UserGoal       string `json:"user_goal,omitempty"`
+	// Timezone is the IANA timezone of the cron expression; the timezone of the Telegram user by default
+	Timezone string `json:"timezone,omitempty"`
+}
+
+type ListSchedulesResponse struct {
+	Schedules []*ProjectSchedule `json:"schedules"`
+}
+
 type ListFilesResponse struct {
 	Files []*FileDetail `json:"files"`
 }
@@ -0,0 +1,84 @@
+package entity
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// QuotaKind is a quota-limited kind of usage
+type QuotaKind string
+
+const (
+	QuotaKindSessions    QuotaKind = "sessions"    // started sessions, counted per UTC day
+	QuotaKindGenerations QuotaKind = "generations" // LLM requirement generations, counted per UTC month
+)
+
+// QuotaScope is who a quota limits
+type QuotaScope string
+
+const (
+	QuotaScopeUser   QuotaScope = "user"
+	QuotaScopeTenant QuotaScope = "tenant"
+)
+
+// QuotaStatus is the usage of one quota in its current period
+type QuotaStatus struct {
+	Kind     QuotaKind  `json:"kind"`
+	Scope    QuotaScope `json:"scope"`
+	Used     int        `json:"used"`
+	Limit    int        `json:"limit"`
+	ResetsAt time.Time  `json:"resets_at"`
+	Warning  bool       `json:"warning"`  // the usage reached the warn threshold
+	Exceeded bool       `json:"exceeded"` // the usage reached the limit, new usage is blocked
+}
+
+// QuotaUsage is the usage of every enabled quota of a user and its tenant
+type QuotaUsage struct {
+	Subject string        `json:"subject,omitempty"`
+	Quotas  []QuotaStatus `json:"quotas"`
+}
+
+// SubjectQuotaUsage is the usage of one user within its tenant
+type SubjectQuotaUsage struct {
+	Subject string    `json:"subject"`
+	Kind    QuotaKind `json:"kind"`
+	Used    int       `json:"used"`
+	Limit   int       `json:"limit"`
+}
+
+// QuotaAnalytics is the quota usage of a tenant with its heaviest users, for admins
+type QuotaAnalytics struct {
+	TenantID    string              `json:"tenant_id"`
+	Quotas      []QuotaStatus       `json:"quotas"`
+	TopSubjects []SubjectQuotaUsage `json:"top_subjects"`
+}
+
+type usageSubjectContextKey struct{}
+
+// WithUsageSubject makes the usage in ctx count against the quotas of subject, e.g. a Telegram user or an API client
+func WithUsageSubject(ctx context.Context, subject string) context.Context {
+	if subject == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, usageSubjectContextKey{}, subject)
+}
+
+// UsageSubjectFromContext returns the subject the usage in ctx counts against, empty when only tenant quotas apply
+func UsageSubjectFromContext(ctx context.Context) string {
+	subject, _ := ctx.Value(usageSubjectContextKey{}).(string)
+	return subject
+}
+
+// TelegramUsageSubject is the quota subject of a Telegram user
+func TelegramUsageSubject(userID int64) string {
+	return fmt.Sprintf("tg:%d", userID)
+}
+
+// ClientUsageSubject is the quota subject of an API client identified by X-Client-ID
+func ClientUsageSubject(clientID string) string {
+	if clientID == "" {
+		return ""
+	}
+	return "client:" + clientID
+}
@@ -1,5 +1,7 @@
 package entity
 
+import "time"
+
 type RAGChunk struct {
 	Text string `json:"text"`
 }
@@ -13,6 +15,14 @@ type RAGGetContextRequest struct {
 	UserGoal     string `json:"user_goal"`
 	TopK         int    `json:"top_k"`
 	MaxQuestions int    `json:"max_questions"`
+	// Freshness tells the RAG service how old the indexed materials of the project are
+	Freshness *RAGFreshness `json:"freshness,omitempty"`
+}
+
+type RAGFreshness struct {
+	FileCount     int        `json:"file_count"`
+	LastIndexedAt *time.Time `json:"last_indexed_at,omitempty"`
+	OldestFileAt  *time.Time `json:"oldest_file_at,omitempty"`
 }
 
 type RAGGetContextResponse struct {
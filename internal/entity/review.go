@@ -0,0 +1,60 @@
+package entity
+
+import "time"
+
+// ReviewStatus is the approval state of a session result
+type ReviewStatus string
+
+const (
+	ReviewStatusDraft    ReviewStatus = "draft"
+	ReviewStatusInReview ReviewStatus = "in_review"
+	ReviewStatusApproved ReviewStatus = "approved"
+	ReviewStatusRejected ReviewStatus = "rejected"
+)
+
+const (
+	ReviewDecisionApprove = "approve"
+	ReviewDecisionReject  = "reject"
+)
+
+// ApproverType is the channel an approver is identified and notified by
+type ApproverType string
+
+const (
+	ApproverTypeAPI      ApproverType = "api"
+	ApproverTypeTelegram ApproverType = "telegram"
+)
+
+func (t ApproverType) IsValid() bool {
+	switch t {
+	case ApproverTypeAPI, ApproverTypeTelegram:
+		return true
+	}
+	return false
+}
+
+// ResultApprover identifies an approver: an API identity or a Telegram user ID
+type ResultApprover struct {
+	Type ApproverType `json:"type"`
+	ID   string       `json:"id"`
+}
+
+// ResultReview is the approval workflow state of a session result
+type ResultReview struct {
+	SessionID       string           `json:"session_id"`
+	Status          ReviewStatus     `json:"status"`
+	Approvers       []ResultApprover `json:"approvers"`
+	DecidedBy       *ResultApprover  `json:"decided_by,omitempty"`
+	DecisionComment *string          `json:"decision_comment,omitempty"`
+	UpdatedAt       time.Time        `json:"updated_at"`
+}
+
+// HasApprover reports whether the approver is assigned to the review
+func (r *ResultReview) HasApprover(approver ResultApprover) bool {
+	for _, a := range r.Approvers {
+		if a == approver {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,21 @@
+package entity
+
+import "time"
+
+// DefaultScheduleGoal is the goal of scheduled check-in sessions when none is configured
+const DefaultScheduleGoal = "Что изменилось в проекте с прошлой сессии?"
+
+// ProjectSchedule creates recurring check-in sessions for a project and offers them
+// to the bound Telegram user; run times are stored in UTC
+type ProjectSchedule struct {
+	ID             string     `json:"id"`
+	ProjectID      string     `json:"project_id"`
+	CronExpr       string     `json:"cron"`
+	Timezone       string     `json:"timezone"` // IANA timezone the cron expression is evaluated in
+	TelegramUserID int64      `json:"telegram_user_id"`
+	UserGoal       string     `json:"user_goal"`
+	NextRunAt      time.Time  `json:"next_run_at"`
+	LastRunAt      *time.Time `json:"last_run_at,omitempty"`
+	LastSessionID  *string    `json:"last_session_id,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
@@ -10,14 +10,118 @@ type StartSessionRequest struct {
 	UserGoal         string               `json:"user_goal"`
 	ContextQuestions []QuestionWithAnswer `json:"context_questions,omitempty"`
 	CallbackURL      string               `json:"callback_url,omitempty"`
+	SessionType      SessionType          `json:"session_type,omitempty"`
+	Demo             bool                 `json:"demo,omitempty"`                // sandbox session on mock connectors, purged after DEMO_SESSION_TTL
+	TimeBudgetMin    int                  `json:"time_budget_minutes,omitempty"` // overrides TIME_BUDGET_DEFAULT for this interview
+
+	CallbackGranularity CallbackGranularity `json:"callback_granularity,omitempty"` // iteration (default), question or final
+
+	SessionID string `json:"-"` // preassigned by the handler so clients can poll a pending start
+	Sync      bool   `json:"-"` // set from the sync query parameter
+}
+
+// TranscriptSessionRequest starts a one-shot session generating requirements from a meeting transcript
+type TranscriptSessionRequest struct {
+	ProjectID        *string              `json:"project_id,omitempty"`
+	UserGoal         string               `json:"user_goal"`
+	ContextQuestions []QuestionWithAnswer `json:"context_questions,omitempty"`
+	Transcript       string               `json:"transcript"`
+	CallbackURL      string               `json:"callback_url,omitempty"`
+	Demo             bool                 `json:"demo,omitempty"`
+
+	SessionID string `json:"-"` // preassigned by the handler so clients can poll the pipeline
 }
 
 type SubmitAnswerRequest struct {
-	Answer      string `json:"answers"`
-	IsSkipped   bool   `json:"is_skipped"`
+	Answer      string     `json:"answers"`
+	IsSkipped   bool       `json:"is_skipped"`
+	SkipReason  SkipReason `json:"skip_reason,omitempty"`
+	CallbackURL string     `json:"callback_url"`
+}
+
+// GenerateSummaryRequest confirms generation after an estimate event
+type GenerateSummaryRequest struct {
+	CallbackURL string `json:"callback_url"`
+}
+
+// RegenerateSectionRequest regenerates one result section with optional guidance
+type RegenerateSectionRequest struct {
+	Guidance    string `json:"guidance"`
 	CallbackURL string `json:"callback_url"`
 }
 
+// SetSessionLanguageRequest sets the language of the generated documents explicitly
+type SetSessionLanguageRequest struct {
+	Language ResultLanguage `json:"language"`
+}
+
+// SetSummaryStyleRequest sets the style of the generated document
+type SetSummaryStyleRequest struct {
+	Style SummaryStyle `json:"style"`
+}
+
+// ResolveConflictRequest records the decision on a detected requirements conflict
+type ResolveConflictRequest struct {
+	Resolution ConflictResolution `json:"resolution"`
+}
+
+// CreateCommentRequest attaches a reviewer comment to a result section or requirement
+type CreateCommentRequest struct {
+	SectionIndex  *int   `json:"section_index,omitempty"`
+	RequirementID string `json:"requirement_id,omitempty"`
+	Author        string `json:"author,omitempty"`
+	Text          string `json:"text"`
+}
+
+// RefineResultRequest revises the result to address unresolved comments
+type RefineResultRequest struct {
+	CallbackURL string `json:"callback_url"`
+}
+
+// ResultFeedbackRequest regenerates the result following free-text feedback
+type ResultFeedbackRequest struct {
+	Feedback    string `json:"feedback"`
+	CallbackURL string `json:"callback_url"`
+}
+
+// SubmitReviewRequest sends the result to the assigned approvers
+type SubmitReviewRequest struct {
+	Approvers   []ResultApprover `json:"approvers"`
+	CallbackURL string           `json:"callback_url"` // receives reviewRequested event for API approvers
+}
+
+// ReviewDecisionRequest records an approver decision
+type ReviewDecisionRequest struct {
+	Approver ResultApprover `json:"approver"`
+	Decision string         `json:"decision"` // approve or reject
+	Comment  string         `json:"comment,omitempty"`
+}
+
+// AdminSearchRequest is a paginated full-text search across all projects and sessions
+type AdminSearchRequest struct {
+	Query string
+	Skip  int
+	Limit int
+}
+
+func (r *AdminSearchRequest) Normalize() {
+	if r.Skip < 0 {
+		r.Skip = 0
+	}
+	if r.Limit <= 0 {
+		r.Limit = 20
+	}
+
+	r.Limit = min(r.Limit, 100)
+}
+
+type AdminSearchResponse struct {
+	Hits  []*AdminSearchHit `json:"hits"`
+	Total int               `json:"total"`
+	Skip  int               `json:"skip"`
+	Limit int               `json:"limit"`
+}
+
 type SubmitAudioAnswerRequest struct {
 	AudioFile   *multipart.FileHeader
 	IsSkipped   bool   `json:"is_skipped"`
@@ -30,11 +134,14 @@ type ErrorResponse struct {
 }
 
 type QuestionDTO struct {
-	ID             string         `json:"id"`
-	QuestionNumber int            `json:"question_number"`
-	Status         QuestionStatus `json:"status"`
-	Question       string         `json:"question"`
-	Explanation    string         `json:"explanation"`
+	ID               string             `json:"id"`
+	QuestionNumber   int                `json:"question_number"`
+	Status           QuestionStatus     `json:"status"`
+	Question         string             `json:"question"`
+	Explanation      string             `json:"explanation"`
+	Options          []string           `json:"options,omitempty"`
+	ParentQuestionID *string            `json:"parent_question_id,omitempty"`
+	AnswerType       QuestionAnswerType `json:"answer_type,omitempty"`
 }
 
 type IterationWithQuestions struct {
@@ -45,6 +152,25 @@ type IterationWithQuestions struct {
 	Questions       []QuestionDTO `json:"questions"`
 }
 
+// QuestionProgress is the position of a question among all questions of its session
+type QuestionProgress struct {
+	Number int `json:"number"`
+	Total  int `json:"total"`
+	// Block is the number of the block of the question among the Blocks generated so far
+	Block  int `json:"block"`
+	Blocks int `json:"blocks"`
+	// Answered counts the answered questions of the session, skipped and deferred ones are not
+	Answered int `json:"answered"`
+}
+
+// QuestionExplanation is the explanation of a question shown when the user asks for it
+type QuestionExplanation struct {
+	QuestionID  string `json:"question_id"`
+	Explanation string `json:"explanation"`
+	// Cached is set when the explanation was generated earlier and no LLM call was made
+	Cached bool `json:"cached"`
+}
+
 type SessionDTO struct {
 	ID               string        `json:"session_id"`
 	ProjectID        *string       `json:"project_id,omitempty"`
@@ -52,6 +178,68 @@ type SessionDTO struct {
 	CurrentIteration int           `json:"iteration_number"`
 	Result           *string       `json:"final_result,omitempty"`
 	Error            *string       `json:"error,omitempty"`
+	IsDemo           bool          `json:"is_demo,omitempty"`
 	CreatedAt        time.Time     `json:"created_at"`
 	UpdatedAt        time.Time     `json:"updated_at"`
+	LastActivityAt   *time.Time    `json:"last_activity_at,omitempty"`
+
+	CallbackGranularity CallbackGranularity `json:"callback_granularity,omitempty"`
+
+	// Language of the user inputs the documents are written in, detected unless set explicitly
+	Language         *ResultLanguage `json:"language,omitempty"`
+	LanguageExplicit bool            `json:"language_explicit,omitempty"`
+
+	// Style the document is written in, the default style when absent
+	SummaryStyle *SummaryStyle `json:"summary_style,omitempty"`
+
+	// Time the content of the session was removed by the retention policy of its tenant
+	PurgedAt *time.Time `json:"purged_at,omitempty"`
+
+	// The question the session waits an answer for and its block, present while it waits for answers
+	CurrentQuestionID  *string      `json:"current_question_id,omitempty"`
+	CurrentIterationID *string      `json:"current_iteration_id,omitempty"`
+	CurrentQuestion    *QuestionDTO `json:"current_question,omitempty"`
+}
+
+// PendingQuestions is a questions callback the consumer did not receive even after retries.
+// It is kept until the consumer pulls it and acknowledges the iteration
+type PendingQuestions struct {
+	ID            string                  `json:"id"`
+	SessionID     string                  `json:"session_id"`
+	IterationID   string                  `json:"iteration_id"`
+	RequestID     string                  `json:"request_id,omitempty"`
+	Questions     *IterationWithQuestions `json:"questions"`
+	DeliveryError string                  `json:"delivery_error"`
+	CreatedAt     time.Time               `json:"created_at"`
+}
+
+// PendingQuestionsAck is the result of acknowledging a pulled questions block. Once nothing is
+// pending the current questions are returned, so the consumer resumes answering right away
+type PendingQuestionsAck struct {
+	Pending          int                     `json:"pending"`
+	CurrentQuestions *IterationWithQuestions `json:"current_questions,omitempty"`
+}
+
+// SessionBundle collects all artifacts of a finished session for the bundle download
+type SessionBundle struct {
+	Session        *Session           `json:"session"`
+	Iterations     []*BundleIteration `json:"iterations"`
+	DraftMessages  []*SessionMessage  `json:"draft_messages,omitempty"`
+	ResultSections []*ResultSection   `json:"result_sections,omitempty"`
+	FileInfo       *ResultFileInfo    `json:"-"`
+}
+
+// BundleIteration is an interview iteration with its answered and skipped questions
+type BundleIteration struct {
+	IterationNumber int         `json:"iteration_number"`
+	Title           string      `json:"title"`
+	Questions       []*Question `json:"questions"`
+}
+
+// DraftCoverage is a preview of the draft validation: what the collected materials already cover
+// and what the requirements would still ask about
+type DraftCoverage struct {
+	MessageCount int      `json:"message_count"`
+	Covered      []string `json:"covered"`
+	Missing      []string `json:"missing"`
 }
@@ -0,0 +1,25 @@
+package entity
+
+import "time"
+
+// StreamEventTypeStatus reports a status change of a session to its progress streams; the data is
+// the session with its current question and, once done, its result
+const StreamEventTypeStatus CallbackEventType = "status"
+
+// StreamEventTypeChunk carries the next part of a requirements document streamed while it is
+// generated; the data is a SummaryChunkDTO
+const StreamEventTypeChunk CallbackEventType = "chunk"
+
+// SummaryChunkDTO is the data of a chunk event
+type SummaryChunkDTO struct {
+	Content string `json:"content"`
+}
+
+// StreamEvent is a progress event pushed to the streams of a session. Besides status changes
+// streams get the workflow events delivered by callbacks, whatever the callback granularity
+type StreamEvent struct {
+	Event     CallbackEventType `json:"event"`
+	SessionID string            `json:"session_id"`
+	Timestamp time.Time         `json:"timestamp"`
+	Data      any               `json:"data"`
+}
@@ -0,0 +1,85 @@
+package entity
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultTenantID owns the data created before multi-tenancy and requests without an API key
+const DefaultTenantID = "default"
+
+// Tenant is a business unit whose projects, sessions and Telegram users are isolated from other tenants
+type Tenant struct {
+	ID        string         `json:"id"`
+	Name      string         `json:"name"`
+	Settings  TenantSettings `json:"settings"`
+	CreatedAt time.Time      `json:"created_at"`
+}
+
+// TenantSettings overrides the global configuration for one tenant; zero values keep the global setting
+type TenantSettings struct {
+	// Upload limits
+	MaxFileSize  int64 `json:"max_file_size,omitempty"`
+	MaxTotalSize int64 `json:"max_total_size,omitempty"`
+	MaxFileCount int   `json:"max_file_count,omitempty"`
+	// ResultFormat is the result template used when a download does not ask for one
+	ResultFormat ResultFormat `json:"result_format,omitempty"`
+	// LLMProvider is passed to the LLM service to pick the model provider of the tenant
+	LLMProvider string `json:"llm_provider,omitempty"`
+	// ThemeID is the document theme of results whose project has no theme of its own
+	ThemeID string `json:"theme_id,omitempty"`
+	// ContentRetentionDays is how long the content of inactive sessions is kept before it is purged
+	ContentRetentionDays int `json:"content_retention_days,omitempty"`
+}
+
+// RetentionPurge reports the volumes one purge run removed from the sessions of a tenant
+type RetentionPurge struct {
+	ID       string `json:"id"`
+	TenantID string `json:"tenant_id"`
+	// Cutoff is the time the purged sessions had no activity since
+	Cutoff         time.Time `json:"cutoff"`
+	Sessions       int       `json:"sessions"`
+	Questions      int       `json:"questions"`
+	Messages       int       `json:"messages"`
+	ResultVersions int       `json:"result_versions"`
+	ResultBytes    int64     `json:"result_bytes"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// CreateTenantRequest represents an admin request to register a tenant
+type CreateTenantRequest struct {
+	ID       string         `json:"id"`
+	Name     string         `json:"name"`
+	BotToken string         `json:"bot_token,omitempty"` // maps a Telegram bot to the tenant
+	Settings TenantSettings `json:"settings"`
+}
+
+// CreateTenantResponse returns the API key of a new tenant; the key is not stored and cannot be shown again
+type CreateTenantResponse struct {
+	Tenant *Tenant `json:"tenant"`
+	APIKey string  `json:"api_key"`
+}
+
+type tenantContextKey struct{}
+
+// WithTenant scopes ctx to the tenant; a nil tenant leaves ctx unchanged
+func WithTenant(ctx context.Context, tenant *Tenant) context.Context {
+	if tenant == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, tenantContextKey{}, tenant)
+}
+
+// TenantFromContext returns the tenant ctx is scoped to, nil when it is not scoped
+func TenantFromContext(ctx context.Context) *Tenant {
+	tenant, _ := ctx.Value(tenantContextKey{}).(*Tenant)
+	return tenant
+}
+
+// TenantIDFromContext returns the ID of the tenant ctx is scoped to, the default tenant when it is not scoped
+func TenantIDFromContext(ctx context.Context) string {
+	if tenant := TenantFromContext(ctx); tenant != nil {
+		return tenant.ID
+	}
+	return DefaultTenantID
+}
@@ -0,0 +1,44 @@
+package entity
+
+import "time"
+
+// DocumentTheme brands the PDF and DOCX results of a tenant or a project
+type DocumentTheme struct {
+	ID           string    `json:"id"`
+	Name         string    `json:"name"`
+	PrimaryColor string    `json:"primary_color,omitempty"` // #RRGGBB of the title and headings
+	HeaderText   string    `json:"header_text,omitempty"`
+	FooterText   string    `json:"footer_text,omitempty"`
+	FontName     string    `json:"font_name,omitempty"` // font family of DOCX documents
+	LogoKey      string    `json:"-"`
+	FontKey      string    `json:"-"`
+	HasLogo      bool      `json:"has_logo"`
+	HasFont      bool      `json:"has_font"`
+	CreatedAt    time.Time `json:"created_at"`
+
+	// Assets loaded from blob storage for rendering
+	Logo *ThemeAsset `json:"-"`
+	Font *ThemeAsset `json:"-"`
+}
+
+// ThemeAsset is a logo image or a TTF font of a theme
+type ThemeAsset struct {
+	Data        []byte
+	ContentType string
+}
+
+// CreateThemeRequest represents an admin request to create a document theme
+type CreateThemeRequest struct {
+	Name         string      `json:"name"`
+	PrimaryColor string      `json:"primary_color,omitempty"`
+	HeaderText   string      `json:"header_text,omitempty"`
+	FooterText   string      `json:"footer_text,omitempty"`
+	FontName     string      `json:"font_name,omitempty"`
+	Logo         *ThemeAsset `json:"-"`
+	Font         *ThemeAsset `json:"-"`
+}
+
+// SetProjectThemeRequest selects the theme of a project; null falls back to the tenant theme
+type SetProjectThemeRequest struct {
+	ThemeID *string `json:"theme_id"`
+}
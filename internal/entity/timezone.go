@@ -0,0 +1,70 @@
+package entity
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	// The images have no zoneinfo database, so it is built into the binaries
+	_ "time/tzdata"
+)
+
+// DefaultTimezone is the timezone of users and schedules that have not chosen one
+const DefaultTimezone = "UTC"
+
+// languageTimezones guesses the timezone of a new Telegram user from the language of the client
+var languageTimezones = map[string]string{
+	"ru": "Europe/Moscow",
+	"be": "Europe/Minsk",
+	"uk": "Europe/Kyiv",
+	"kk": "Asia/Almaty",
+	"uz": "Asia/Tashkent",
+	"hy": "Asia/Yerevan",
+	"ka": "Asia/Tbilisi",
+	"az": "Asia/Baku",
+	"de": "Europe/Berlin",
+}
+
+// LoadTimezone returns the location of an IANA timezone name, e.g. Europe/Moscow
+func LoadTimezone(name string) (*time.Location, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || name == "Local" {
+		return nil, fmt.Errorf("%w: timezone '%s'", ErrInvalidParameter, name)
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: timezone '%s'", ErrInvalidParameter, name)
+	}
+
+	return loc, nil
+}
+
+// TimezoneForLanguage returns the likely timezone of a user of the language, empty when unknown
+func TimezoneForLanguage(languageCode string) string {
+	language, _, _ := strings.Cut(strings.ToLower(languageCode), "-")
+	return languageTimezones[language]
+}
+
+type locationContextKey struct{}
+
+// WithLocation sets the timezone the dates and times of the work in ctx are shown in
+func WithLocation(ctx context.Context, loc *time.Location) context.Context {
+	if loc == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, locationContextKey{}, loc)
+}
+
+// LocationFromContext returns the timezone of the work in ctx, UTC when none is set
+func LocationFromContext(ctx context.Context) *time.Location {
+	if loc, ok := ctx.Value(locationContextKey{}).(*time.Location); ok {
+		return loc
+	}
+	return time.UTC
+}
+
+// LocalTime returns t in the timezone of the work in ctx
+func LocalTime(ctx context.Context, t time.Time) time.Time {
+	return t.In(LocationFromContext(ctx))
+}
@@ -0,0 +1,53 @@
+package entity
+
+import (
+	"context"
+	"time"
+)
+
+// User owns the projects and sessions they create inside a tenant. API clients act as a user
+// when they authenticate with a user API key, Telegram users get a user on first contact with the bot
+type User struct {
+	ID             string    `json:"id"`
+	TenantID       string    `json:"tenant_id"`
+	Name           string    `json:"name"`
+	TelegramUserID *int64    `json:"telegram_user_id,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// CreateUserRequest represents an admin request to register a user of a tenant; a Telegram user ID
+// makes the API key and the bot act as the same user
+type CreateUserRequest struct {
+	Name           string `json:"name"`
+	TelegramUserID *int64 `json:"telegram_user_id,omitempty"`
+}
+
+// CreateUserResponse returns the API key of a new user; the key is not stored and cannot be shown again
+type CreateUserResponse struct {
+	User   *User  `json:"user"`
+	APIKey string `json:"api_key"`
+}
+
+type userContextKey struct{}
+
+// WithUser scopes ctx to the projects and sessions of the user; a nil user leaves ctx unchanged
+func WithUser(ctx context.Context, user *User) context.Context {
+	if user == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, userContextKey{}, user)
+}
+
+// UserFromContext returns the user ctx is scoped to, nil when ctx sees the whole tenant
+func UserFromContext(ctx context.Context) *User {
+	user, _ := ctx.Value(userContextKey{}).(*User)
+	return user
+}
+
+// OwnerIDFromContext returns the ID of the user ctx is scoped to, nil when ctx sees the whole tenant
+func OwnerIDFromContext(ctx context.Context) *string {
+	if user := UserFromContext(ctx); user != nil {
+		return &user.ID
+	}
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"mime/multipart"
 	"net/http"
@@ -11,14 +12,18 @@ import (
 	"github.com/futig/agent-backend/internal/config"
 	"github.com/futig/agent-backend/internal/entity"
 	"github.com/futig/agent-backend/internal/integration/common"
+	"github.com/futig/agent-backend/internal/pkg/breaker"
+	"github.com/futig/agent-backend/internal/pkg/tracing"
 	pkghttp "github.com/futig/agent-backend/pkg/http"
 	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/zap"
 )
 
 type Connector struct {
 	config    config.ASRConnectorConfig
 	connector *pkghttp.Connector
+	breaker   *breaker.Breaker
 	logger    *zap.Logger
 }
 
@@ -26,19 +31,36 @@ func NewConnector(
 	cfg config.ASRConnectorConfig,
 	logger *zap.Logger,
 ) *Connector {
+	cb := breaker.New(cfg.Breaker)
+	cb.OnStateChange(func(from, to breaker.State) {
+		logger.Warn("ASR circuit breaker state changed",
+			zap.Stringer("from", from),
+			zap.Stringer("to", to),
+		)
+	})
+
 	return &Connector{
-		connector: common.NewBaseConnector(cfg.HTTPClientConfig, logger),
+		connector: common.NewBaseConnector("asr", cfg.HTTPClientConfig, logger),
+		breaker:   cb,
 		config:    cfg,
 		logger:    logger,
 	}
 }
 
+// Warmer keeps the connections to the ASR service ready, see config.WarmupConfig
+func (c *Connector) Warmer() *common.Warmer {
+	return common.NewWarmer("asr", c.connector, c.config.Warmup, c.logger)
+}
+
 // transcribeBytes is the internal method for transcribing audio bytes
 func (c *Connector) TranscribeBytes(ctx context.Context, audioData []byte, filename string) (string, error) {
 	if len(audioData) == 0 {
 		return "", fmt.Errorf("empty audio data provided")
 	}
 
+	ctx, span := tracing.Start(ctx, "asr TranscribeBytes", attribute.Int("audio.size", len(audioData)))
+	defer span.End()
+
 	hash := sha256.Sum256(audioData)
 	checksum := hex.EncodeToString(hash[:])
 
@@ -67,9 +89,17 @@ func (c *Connector) TranscribeBytes(ctx context.Context, audioData []byte, filen
 		return nil
 	}
 
+	// While the circuit is open voice messages fail fast instead of waiting for timeouts
+	if err := c.breaker.Allow(); err != nil {
+		tracing.Fail(span, err)
+		return "", fmt.Errorf("%w: %w", entity.ErrASRUnavailable, err)
+	}
+
 	var resp entity.ASRTranscribeResponse
 	err := c.connector.DoMultipartRequest(ctx, http.MethodPost, c.config.TranscribeEndpoint, prepareBody, &resp)
+	c.recordOutcome(ctx, err)
 	if err != nil {
+		tracing.Fail(span, err)
 		return "", fmt.Errorf("failed to transcribe audio: %w", err)
 	}
 
@@ -77,3 +107,23 @@ func (c *Connector) TranscribeBytes(ctx context.Context, audioData []byte, filen
 
 	return resp.Transcriptions, nil
 }
+
+// recordOutcome counts network errors and server errors towards opening the circuit;
+// rejected audio says nothing about the health of the service
+func (c *Connector) recordOutcome(ctx context.Context, err error) {
+	if err != nil && ctx.Err() != nil {
+		c.breaker.Abandon()
+		return
+	}
+
+	var netErr *pkghttp.NetworkError
+	var httpErr *pkghttp.HTTPError
+	switch {
+	case errors.As(err, &netErr):
+		c.breaker.Record(err)
+	case errors.As(err, &httpErr) && httpErr.StatusCode >= http.StatusInternalServerError:
+		c.breaker.Record(err)
+	default:
+		c.breaker.Record(nil)
+	}
+}
@@ -3,7 +3,10 @@ package asr
 import (
 	"context"
 	"fmt"
+	"sync"
 
+	"github.com/futig/agent-backend/internal/config"
+	"github.com/futig/agent-backend/internal/entity"
 	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
 	"go.uber.org/zap"
 )
@@ -11,6 +14,11 @@ import (
 // MockConnector - мок-реализация ASR коннектора для тестирования
 type MockConnector struct {
 	logger *zap.Logger
+
+	// Canned transcripts keyed by file name or audio size, for deterministic E2E runs
+	transcripts []config.MockTranscript
+	mu          sync.Mutex
+	attempts    map[int]int // transcript index -> transcription attempts so far
 }
 
 func NewMockConnector(logger *zap.Logger) *MockConnector {
@@ -19,6 +27,14 @@ func NewMockConnector(logger *zap.Logger) *MockConnector {
 	}
 }
 
+// WithTranscripts makes the mock return the canned transcripts instead of the default one
+// for the audio they match
+func (m *MockConnector) WithTranscripts(transcripts []config.MockTranscript) *MockConnector {
+	m.transcripts = transcripts
+	m.attempts = make(map[int]int, len(transcripts))
+	return m
+}
+
 // TranscribeBytes - мок транскрибации аудио
 func (m *MockConnector) TranscribeBytes(ctx context.Context, audioData []byte, filename string) (string, error) {
 	if len(audioData) == 0 {
@@ -30,6 +46,10 @@ func (m *MockConnector) TranscribeBytes(ctx context.Context, audioData []byte, f
 		zap.Int("size", len(audioData)),
 	)
 
+	if index, ok := m.match(filename, len(audioData)); ok {
+		return m.canned(ctx, index)
+	}
+
 	// Возвращаем мок-транскрипцию
 	mockTranscription := `Добрый день. Я хочу рассказать о требованиях к нашей системе.
 Во-первых, необходимо реализовать функциональность регистрации и авторизации пользователей.
@@ -41,3 +61,42 @@ func (m *MockConnector) TranscribeBytes(ctx context.Context, audioData []byte, f
 	ctxzap.Info(ctx, "[MOCK] audio transcribed", zap.Int("transcription_length", len(mockTranscription)))
 	return mockTranscription, nil
 }
+
+// match finds the canned transcript of the audio: a file name match wins over a size match
+func (m *MockConnector) match(filename string, size int) (int, bool) {
+	for i, transcript := range m.transcripts {
+		if transcript.FileName != "" && transcript.FileName == filename {
+			return i, true
+		}
+	}
+	for i, transcript := range m.transcripts {
+		if transcript.FileName == "" && transcript.Size == size {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// canned returns the canned transcript, failing its first FailAttempts attempts as an unavailable service
+func (m *MockConnector) canned(ctx context.Context, index int) (string, error) {
+	transcript := m.transcripts[index]
+
+	m.mu.Lock()
+	m.attempts[index]++
+	attempt := m.attempts[index]
+	m.mu.Unlock()
+
+	if attempt <= transcript.FailAttempts {
+		ctxzap.Info(ctx, "[MOCK] failing audio transcription",
+			zap.Int("attempt", attempt),
+			zap.Int("fail_attempts", transcript.FailAttempts),
+		)
+		return "", fmt.Errorf("%w: mock failure %d of %d", entity.ErrASRUnavailable, attempt, transcript.FailAttempts)
+	}
+
+	ctxzap.Info(ctx, "[MOCK] canned transcript returned",
+		zap.Int("attempt", attempt),
+		zap.Int("transcription_length", len(transcript.Text)),
+	)
+	return transcript.Text, nil
+}
@@ -0,0 +1,74 @@
+package asr
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/futig/agent-backend/internal/config"
+	"github.com/futig/agent-backend/internal/entity"
+	"go.uber.org/zap"
+)
+
+func TestMockConnectorTranscripts(t *testing.T) {
+	transcripts := []config.MockTranscript{
+		{FileName: "goal.ogg", Text: "goal by name"},
+		{Size: 4, Text: "four bytes"},
+		{FileName: "answer.ogg", Size: 4, Text: "answer by name"},
+	}
+
+	tests := []struct {
+		name     string
+		filename string
+		audio    []byte
+		want     string
+	}{
+		{name: "file name", filename: "goal.ogg", audio: []byte("audio"), want: "goal by name"},
+		{name: "size", filename: "other.ogg", audio: []byte("abcd"), want: "four bytes"},
+		{name: "file name wins over size", filename: "goal.ogg", audio: []byte("abcd"), want: "goal by name"},
+		{name: "size ignores transcripts with a file name", filename: "other.ogg", audio: []byte("abcd"), want: "four bytes"},
+		{name: "no match", filename: "other.ogg", audio: []byte("audio"), want: "Добрый день"},
+	}
+
+	m := NewMockConnector(zap.NewNop()).WithTranscripts(transcripts)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := m.TranscribeBytes(context.Background(), tt.audio, tt.filename)
+			if err != nil {
+				t.Fatalf("TranscribeBytes() error = %v", err)
+			}
+			if !strings.HasPrefix(got, tt.want) {
+				t.Errorf("TranscribeBytes() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMockConnectorFailAttempts(t *testing.T) {
+	m := NewMockConnector(zap.NewNop()).WithTranscripts([]config.MockTranscript{
+		{FileName: "retry.ogg", Text: "transcribed", FailAttempts: 2},
+	})
+
+	for attempt := 1; attempt <= 2; attempt++ {
+		_, err := m.TranscribeBytes(context.Background(), []byte("audio"), "retry.ogg")
+		if !errors.Is(err, entity.ErrASRUnavailable) {
+			t.Fatalf("attempt %d: error = %v, want ErrASRUnavailable", attempt, err)
+		}
+	}
+
+	got, err := m.TranscribeBytes(context.Background(), []byte("audio"), "retry.ogg")
+	if err != nil {
+		t.Fatalf("attempt 3: error = %v", err)
+	}
+	if got != "transcribed" {
+		t.Errorf("attempt 3 = %q, want %q", got, "transcribed")
+	}
+}
+
+func TestMockConnectorEmptyAudio(t *testing.T) {
+	m := NewMockConnector(zap.NewNop())
+	if _, err := m.TranscribeBytes(context.Background(), nil, "empty.ogg"); err == nil {
+		t.Error("TranscribeBytes() of empty audio succeeded, want an error")
+	}
+}
@@ -0,0 +1,279 @@
+package blob
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/futig/agent-backend/internal/config"
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+const (
+	signingAlgorithm = "AWS4-HMAC-SHA256"
+	signingService   = "s3"
+	amzDateLayout    = "20060102T150405Z"
+	amzDayLayout     = "20060102"
+
+	// supersededTag marks objects no longer referenced, e.g. assets of deleted themes, for the lifecycle expiration rule
+	supersededTag = "superseded"
+)
+
+// Connector stores objects in an S3-compatible bucket using path-style requests signed with SigV4
+type Connector struct {
+	config config.ResultStorageConfig
+	client *http.Client
+	logger *zap.Logger
+}
+
+func NewConnector(cfg config.ResultStorageConfig, logger *zap.Logger) *Connector {
+	return &Connector{
+		config: cfg,
+		client: &http.Client{Timeout: cfg.Timeout},
+		logger: logger,
+	}
+}
+
+// PutObject uploads an object under the configured key prefix
+func (c *Connector) PutObject(ctx context.Context, key string, data []byte, contentType string) error {
+	resp, err := c.do(ctx, http.MethodPut, key, nil, data, map[string]string{"content-type": contentType})
+	if err != nil {
+		return fmt.Errorf("put object: %w", err)
+	}
+	resp.Body.Close()
+
+	ctxzap.Debug(ctx, "object stored", zap.String("key", key), zap.Int("size", len(data)))
+	return nil
+}
+
+// GetObject downloads an object stored under the configured key prefix
+func (c *Connector) GetObject(ctx context.Context, key string) ([]byte, error) {
+	resp, err := c.do(ctx, http.MethodGet, key, nil, nil, nil)
+	if err != nil {
+		return nil, fmt.Errorf("get object: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read object: %w", err)
+	}
+	return data, nil
+}
+
+// MarkSuperseded tags a replaced object so the lifecycle rule expires it
+func (c *Connector) MarkSuperseded(ctx context.Context, key string) error {
+	body := []byte(`<Tagging><TagSet><Tag><Key>` + supersededTag + `</Key><Value>true</Value></Tag></TagSet></Tagging>`)
+	resp, err := c.do(ctx, http.MethodPut, key, url.Values{"tagging": {""}}, body, map[string]string{
+		"content-md5":  contentMD5(body),
+		"content-type": "application/xml",
+	})
+	if err != nil {
+		return fmt.Errorf("tag object: %w", err)
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// DeleteObject removes an object stored under the configured key prefix
+func (c *Connector) DeleteObject(ctx context.Context, key string) error {
+	resp, err := c.do(ctx, http.MethodDelete, key, nil, nil, nil)
+	if err != nil {
+		return fmt.Errorf("delete object: %w", err)
+	}
+	resp.Body.Close()
+
+	ctxzap.Debug(ctx, "object deleted", zap.String("key", key))
+	return nil
+}
+
+// EnsureLifecycle installs the bucket rule expiring superseded objects after the given days.
+// It replaces the whole lifecycle configuration, so the bucket should be dedicated to results.
+func (c *Connector) EnsureLifecycle(ctx context.Context, days int) error {
+	body := []byte(fmt.Sprintf(
+		`<LifecycleConfiguration><Rule><ID>expire-superseded-results</ID>`+
+			`<Filter><And><Prefix>%s</Prefix><Tag><Key>%s</Key><Value>true</Value></Tag></And></Filter>`+
+			`<Status>Enabled</Status><Expiration><Days>%d</Days></Expiration></Rule></LifecycleConfiguration>`,
+		c.config.KeyPrefix, supersededTag, days,
+	))
+	resp, err := c.do(ctx, http.MethodPut, "", url.Values{"lifecycle": {""}}, body, map[string]string{
+		"content-md5":  contentMD5(body),
+		"content-type": "application/xml",
+	})
+	if err != nil {
+		return fmt.Errorf("put bucket lifecycle: %w", err)
+	}
+	resp.Body.Close()
+
+	ctxzap.Info(ctx, "result storage lifecycle configured",
+		zap.String("bucket", c.config.Bucket),
+		zap.Int("superseded_days", days),
+	)
+	return nil
+}
+
+// do sends a signed request for an object key (or the bucket when key is empty)
+// and returns the response when the status is successful
+func (c *Connector) do(
+	ctx context.Context,
+	method, key string,
+	query url.Values,
+	body []byte,
+	headers map[string]string,
+) (*http.Response, error) {
+	endpoint, err := url.Parse(c.config.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid endpoint: %w", err)
+	}
+
+	path := "/" + c.config.Bucket
+	if key != "" {
+		path += "/" + c.config.KeyPrefix + key
+	}
+	canonicalURI := uriEncode(path, false)
+
+	reqURL := *endpoint
+	reqURL.Opaque = "//" + endpoint.Host + canonicalURI
+	reqURL.RawQuery = canonicalQuery(query)
+
+	req, err := http.NewRequestWithContext(ctx, method, reqURL.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+
+	c.sign(req, canonicalURI, body, headers, time.Now().UTC())
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("send request: %w", err)
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, fmt.Errorf("%w: %s", entity.ErrObjectNotFound, key)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("storage responded %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+
+	return resp, nil
+}
+
+// sign adds the AWS Signature Version 4 headers to the request
+func (c *Connector) sign(req *http.Request, canonicalURI string, body []byte, headers map[string]string, now time.Time) {
+	payloadHash := sha256Hex(body)
+	amzDate := now.Format(amzDateLayout)
+	day := now.Format(amzDayLayout)
+
+	signed := map[string]string{
+		"host":                 req.URL.Host,
+		"x-amz-content-sha256": payloadHash,
+		"x-amz-date":           amzDate,
+	}
+	for name, value := range headers {
+		signed[name] = value
+	}
+
+	names := make([]string, 0, len(signed))
+	for name := range signed {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(signed[name]) + "\n")
+		if name != "host" {
+			req.Header.Set(name, signed[name])
+		}
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		canonicalURI,
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := strings.Join([]string{day, c.config.Region, signingService, "aws4_request"}, "/")
+	stringToSign := strings.Join([]string{signingAlgorithm, amzDate, scope, sha256Hex([]byte(canonicalRequest))}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+c.config.SecretAccessKey), day)
+	key = hmacSHA256(key, c.config.Region)
+	key = hmacSHA256(key, signingService)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		signingAlgorithm, c.config.AccessKeyID, scope, signedHeaders, signature))
+}
+
+// canonicalQuery encodes query parameters sorted by name as required by SigV4
+func canonicalQuery(query url.Values) string {
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		for _, value := range query[name] {
+			parts = append(parts, uriEncode(name, true)+"="+uriEncode(value, true))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// uriEncode percent-encodes everything except unreserved characters (and slashes unless encodeSlash)
+func uriEncode(s string, encodeSlash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~':
+			b.WriteByte(c)
+		case c == '/' && !encodeSlash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func contentMD5(data []byte) string {
+	sum := md5.Sum(data)
+	return base64.StdEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,64 @@
+package blob
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// MockConnector keeps objects in memory for local runs without S3-compatible storage
+type MockConnector struct {
+	mu      sync.RWMutex
+	objects map[string][]byte
+	logger  *zap.Logger
+}
+
+func NewMockConnector(logger *zap.Logger) *MockConnector {
+	return &MockConnector{
+		objects: make(map[string][]byte),
+		logger:  logger,
+	}
+}
+
+func (m *MockConnector) PutObject(ctx context.Context, key string, data []byte, contentType string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.objects[key] = append([]byte(nil), data...)
+	ctxzap.Info(ctx, "[MOCK] object stored", zap.String("key", key), zap.Int("size", len(data)))
+	return nil
+}
+
+func (m *MockConnector) GetObject(ctx context.Context, key string) ([]byte, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	data, ok := m.objects[key]
+	if !ok {
+		return nil, fmt.Errorf("get object: %w: %s", entity.ErrObjectNotFound, key)
+	}
+	return data, nil
+}
+
+func (m *MockConnector) MarkSuperseded(ctx context.Context, key string) error {
+	ctxzap.Info(ctx, "[MOCK] object marked superseded", zap.String("key", key))
+	return nil
+}
+
+func (m *MockConnector) DeleteObject(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.objects, key)
+	ctxzap.Info(ctx, "[MOCK] object deleted", zap.String("key", key))
+	return nil
+}
+
+func (m *MockConnector) EnsureLifecycle(ctx context.Context, days int) error {
+	m.logger.Info("[MOCK] result storage lifecycle configured", zap.Int("superseded_days", days))
+	return nil
+}
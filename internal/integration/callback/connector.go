@@ -9,14 +9,18 @@ import (
 	"github.com/futig/agent-backend/internal/config"
 	"github.com/futig/agent-backend/internal/entity"
 	"github.com/futig/agent-backend/internal/integration/common"
+	"github.com/futig/agent-backend/internal/pkg/tracing"
 	pkghttp "github.com/futig/agent-backend/pkg/http"
 	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/zap"
 )
 
 type Connector struct {
 	config    config.CallbackConnectorConfig
 	connector *pkghttp.Connector
+	outbox    Outbox // nil when events are sent once, without being stored
+	outboxCfg config.CallbackOutboxConfig
 	logger    *zap.Logger
 }
 
@@ -25,21 +29,32 @@ func NewConnector(
 	logger *zap.Logger,
 ) *Connector {
 	return &Connector{
-		connector: common.NewBaseConnector(cfg.HTTPClientConfig, logger),
+		connector: common.NewBaseConnector("callback", cfg.HTTPClientConfig, logger),
 		config:    cfg,
 		logger:    logger,
 	}
 }
 
-// SendQuestions sends a questions event to the specified callback URL
-func (c *Connector) SendQuestions(ctx context.Context, callbackURL string, requestID string, data *entity.IterationWithQuestions) {
+// SendQuestions sends a questions event to the specified callback URL in the schema version
+// negotiated with the consumer. The error is returned when delivery failed after all retries
+func (c *Connector) SendQuestions(ctx context.Context, callbackURL string, requestID string, data *entity.IterationWithQuestions) error {
+	version := c.schemaVersion(ctx)
+
+	var payload any = toCallbackQuestionsV1(data)
+	if version >= entity.CallbackSchemaV2 {
+		payload = toCallbackQuestionsV2(data)
+	}
+
 	err := c.Send(ctx, callbackURL, requestID, &entity.CallbackEvent{
-		Event: entity.CallbackEventTypeQuestions,
-		Data:  data,
+		Event:         entity.CallbackEventTypeQuestions,
+		SchemaVersion: version,
+		Data:          payload,
 	})
 	if err != nil {
 		ctxzap.Error(ctx, "failed to send questions callback", zap.Error(err))
 	}
+
+	return err
 }
 
 // SendProjectUpdated sends a project updated event to the specified callback URL
@@ -53,6 +68,19 @@ func (c *Connector) SendProjectUpdated(ctx context.Context, callbackURL string,
 	}
 }
 
+// SendQuestionEvent sends a question answered or skipped event to the specified callback URL
+func (c *Connector) SendQuestionEvent(
+	ctx context.Context, callbackURL string, requestID string, event entity.CallbackEventType, data *entity.CallbackQuestionEventData,
+) {
+	err := c.Send(ctx, callbackURL, requestID, &entity.CallbackEvent{
+		Event: event,
+		Data:  data,
+	})
+	if err != nil {
+		ctxzap.Error(ctx, "failed to send question callback", zap.Error(err), zap.String("event", string(event)))
+	}
+}
+
 // SendFinalResult sends a final result event to the specified callback URL
 func (c *Connector) SendFinalResult(ctx context.Context, callbackURL string, requestID string, data *entity.SessionDTO) {
 	err := c.Send(ctx, callbackURL, requestID, &entity.CallbackEvent{
@@ -64,6 +92,28 @@ func (c *Connector) SendFinalResult(ctx context.Context, callbackURL string, req
 	}
 }
 
+// SendEstimate sends a generation estimate event to the specified callback URL
+func (c *Connector) SendEstimate(ctx context.Context, callbackURL string, requestID string, data *entity.GenerationEstimate) {
+	err := c.Send(ctx, callbackURL, requestID, &entity.CallbackEvent{
+		Event: entity.CallbackEventTypeEstimate,
+		Data:  data,
+	})
+	if err != nil {
+		ctxzap.Error(ctx, "failed to send estimate callback", zap.Error(err))
+	}
+}
+
+// SendReviewRequested sends a review requested event to the specified callback URL
+func (c *Connector) SendReviewRequested(ctx context.Context, callbackURL string, requestID string, data *entity.ResultReview) {
+	err := c.Send(ctx, callbackURL, requestID, &entity.CallbackEvent{
+		Event: entity.CallbackEventTypeReviewRequest,
+		Data:  data,
+	})
+	if err != nil {
+		ctxzap.Error(ctx, "failed to send review requested callback", zap.Error(err))
+	}
+}
+
 // SendError sends an error event to the specified callback URL
 func (c *Connector) SendError(ctx context.Context, callbackURL string, requestID string, message string, details map[string]any) {
 	err := c.Send(ctx, callbackURL, requestID, &entity.CallbackEvent{
@@ -80,10 +130,15 @@ func (c *Connector) SendError(ctx context.Context, callbackURL string, requestID
 	}
 }
 
+// Send sends the event to the callback URL. With the outbox, the event is stored first and retried
+// by the dispatcher when this attempt fails; the error of this attempt is returned either way
 func (c *Connector) Send(ctx context.Context, callbackURL string, requestID string, event *entity.CallbackEvent) error {
 	if event.Timestamp == "" {
 		event.Timestamp = time.Now().UTC().Format(time.RFC3339)
 	}
+	if event.SchemaVersion == 0 {
+		event.SchemaVersion = c.schemaVersion(ctx)
+	}
 
 	ctxzap.Debug(ctx, "sending callback event",
 		zap.String("event_type", string(event.Event)),
@@ -92,20 +147,43 @@ func (c *Connector) Send(ctx context.Context, callbackURL string, requestID stri
 		zap.String("timestamp", event.Timestamp),
 	)
 
+	if c.outbox != nil {
+		return c.sendThroughOutbox(ctx, callbackURL, requestID, event)
+	}
+
+	return c.deliver(ctx, callbackURL, requestID, event.Event, event)
+}
+
+// deliver makes a single delivery attempt of the event, body is the event or its stored JSON
+func (c *Connector) deliver(
+	ctx context.Context, callbackURL string, requestID string, eventType entity.CallbackEventType, body any,
+) error {
+	ctx, span := tracing.Start(ctx, "callback "+string(eventType), attribute.String("callback.request_id", requestID))
+	defer span.End()
+
 	opts := []pkghttp.RequestOpt{
 		pkghttp.WithHeader("X-Request-ID", requestID),
 		pkghttp.WithURL(callbackURL),
 	}
 
-	err := c.connector.DoRequest(ctx, http.MethodPost, "", event, nil, opts...)
+	err := c.connector.DoRequest(ctx, http.MethodPost, "", body, nil, opts...)
 	if err != nil {
-		return fmt.Errorf("failed to send callback, event_type: %s, url: %s, error: %w", string(event.Event), callbackURL, err)
+		tracing.Fail(span, err)
+		return fmt.Errorf("failed to send callback, event_type: %s, url: %s, error: %w", string(eventType), callbackURL, err)
 	}
 
 	ctxzap.Info(ctx, "callback sent successfully",
-		zap.String("event_type", string(event.Event)),
+		zap.String("event_type", string(eventType)),
 		zap.String("callback_url", callbackURL),
 		zap.String("request_id", requestID),
 	)
 	return nil
 }
+
+// schemaVersion returns the callback schema version picked by the consumer, the configured one otherwise
+func (c *Connector) schemaVersion(ctx context.Context) int {
+	if version := entity.CallbackSchemaVersionFromContext(ctx); version != 0 {
+		return version
+	}
+	return c.config.SchemaVersion
+}
@@ -0,0 +1,74 @@
+package callback
+
+import "github.com/futig/agent-backend/internal/entity"
+
+// toCallbackQuestionsV1 keeps the questions payload of schema version 1 unchanged: fields added
+// to questions later are left out
+func toCallbackQuestionsV1(data *entity.IterationWithQuestions) *entity.IterationWithQuestions {
+	if data == nil {
+		return nil
+	}
+
+	v1 := *data
+	v1.Questions = make([]entity.QuestionDTO, 0, len(data.Questions))
+	for _, q := range data.Questions {
+		q.Options = nil
+		q.ParentQuestionID = nil
+		q.AnswerType = ""
+		v1.Questions = append(v1.Questions, q)
+	}
+
+	return &v1
+}
+
+// toCallbackQuestionsV2 describes every question completely, with its answer options, the question
+// it follows up on and the block it belongs to
+func toCallbackQuestionsV2(data *entity.IterationWithQuestions) *entity.CallbackQuestionsData {
+	if data == nil {
+		return nil
+	}
+
+	block := entity.CallbackBlockInfo{
+		ID:            data.IterationID,
+		Number:        data.IterationNumber,
+		Title:         data.Title,
+		QuestionCount: len(data.Questions),
+	}
+
+	questions := make([]entity.CallbackQuestion, 0, len(data.Questions))
+	for _, q := range data.Questions {
+		if q.Status == entity.AnswerStatusUnanswered || q.Status == entity.AnswerStatusDeferred {
+			block.UnansweredCount++
+		}
+		if q.ParentQuestionID != nil {
+			block.IsFollowUp = true
+		}
+
+		options := q.Options
+		if options == nil {
+			options = []string{}
+		}
+
+		questions = append(questions, entity.CallbackQuestion{
+			ID:               q.ID,
+			QuestionNumber:   q.QuestionNumber,
+			Status:           q.Status,
+			Question:         q.Question,
+			Explanation:      q.Explanation,
+			Options:          options,
+			AnswerType:       q.AnswerType,
+			ParentQuestionID: q.ParentQuestionID,
+			BlockNumber:      data.IterationNumber,
+			BlockTitle:       data.Title,
+		})
+	}
+
+	return &entity.CallbackQuestionsData{
+		SessionID:       data.SessionID,
+		IterationID:     data.IterationID,
+		IterationNumber: data.IterationNumber,
+		Title:           data.Title,
+		Block:           block,
+		Questions:       questions,
+	}
+}
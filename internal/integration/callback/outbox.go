@@ -0,0 +1,146 @@
+package callback
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/futig/agent-backend/internal/config"
+	"github.com/futig/agent-backend/internal/entity"
+	pkghttp "github.com/futig/agent-backend/pkg/http"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// Outbox keeps callback events until the consumer accepted them
+type Outbox interface {
+	CreateCallbackDelivery(ctx context.Context, delivery *entity.CallbackDelivery, lease time.Duration) (*entity.CallbackDelivery, error)
+	ClaimDueCallbackDeliveries(ctx context.Context, lease time.Duration, limit int) ([]*entity.CallbackDelivery, error)
+	MarkCallbackDelivered(ctx context.Context, id string) error
+	ScheduleCallbackRetry(ctx context.Context, id string, delay time.Duration, lastError string) error
+	MarkCallbackFailed(ctx context.Context, id string, lastError string) error
+}
+
+// WithOutbox stores every event in the outbox before it is sent, so events whose delivery failed
+// or was cut short by a restart are sent again by DispatchPendingCallbacks
+func (c *Connector) WithOutbox(outbox Outbox, cfg config.CallbackOutboxConfig) *Connector {
+	c.outbox = outbox
+	c.outboxCfg = cfg
+	return c
+}
+
+// DispatchPendingCallbacks makes the next attempt of up to limit events whose retry is due and
+// returns how many of them were delivered
+func (c *Connector) DispatchPendingCallbacks(ctx context.Context, limit int) (int, error) {
+	deliveries, err := c.outbox.ClaimDueCallbackDeliveries(ctx, c.outboxCfg.Lease, limit)
+	if err != nil {
+		return 0, fmt.Errorf("claim due callback deliveries: %w", err)
+	}
+
+	delivered := 0
+	for _, delivery := range deliveries {
+		deliveryCtx := ctxzap.ToContext(ctx, ctxzap.Extract(ctx).With(
+			zap.String("delivery_id", delivery.ID),
+			zap.String("request_id", delivery.RequestID),
+			zap.Int("attempt", delivery.Attempts),
+		))
+
+		err := c.deliver(deliveryCtx, delivery.CallbackURL, delivery.RequestID, delivery.Event, delivery.Payload)
+		if err == nil {
+			delivered++
+		}
+		c.settle(deliveryCtx, delivery, err)
+	}
+
+	return delivered, nil
+}
+
+// sendThroughOutbox stores the event and makes its first attempt right away. An event the outbox
+// failed to store is still sent, once
+func (c *Connector) sendThroughOutbox(ctx context.Context, callbackURL string, requestID string, event *entity.CallbackEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("marshal callback event: %w", err)
+	}
+
+	delivery, err := c.outbox.CreateCallbackDelivery(ctx, &entity.CallbackDelivery{
+		RequestID:   requestID,
+		CallbackURL: callbackURL,
+		Event:       event.Event,
+		Payload:     payload,
+	}, c.outboxCfg.Lease)
+	if err != nil {
+		ctxzap.Warn(ctx, "failed to store callback event in outbox, sending it without retries", zap.Error(err))
+		return c.deliver(ctx, callbackURL, requestID, event.Event, event)
+	}
+
+	err = c.deliver(ctx, callbackURL, requestID, event.Event, json.RawMessage(payload))
+	c.settle(ctx, delivery, err)
+
+	return err
+}
+
+// settle records the outcome of an attempt: the event is delivered, retried after a backoff or,
+// once attempts run out or the consumer rejected it, failed
+func (c *Connector) settle(ctx context.Context, delivery *entity.CallbackDelivery, deliveryErr error) {
+	// The outcome is recorded even when the request that sent the event is already done
+	ctx = context.WithoutCancel(ctx)
+
+	var err error
+	switch {
+	case deliveryErr == nil:
+		err = c.outbox.MarkCallbackDelivered(ctx, delivery.ID)
+	case delivery.Attempts >= c.outboxCfg.MaxAttempts || !retryable(deliveryErr):
+		ctxzap.Error(ctx, "giving up callback delivery",
+			zap.Error(deliveryErr),
+			zap.String("event_type", string(delivery.Event)),
+			zap.Int("attempts", delivery.Attempts),
+		)
+		err = c.outbox.MarkCallbackFailed(ctx, delivery.ID, deliveryErr.Error())
+	default:
+		delay := c.retryDelay(delivery.Attempts)
+		ctxzap.Warn(ctx, "callback delivery failed, retry scheduled",
+			zap.Error(deliveryErr),
+			zap.String("event_type", string(delivery.Event)),
+			zap.Int("attempts", delivery.Attempts),
+			zap.Duration("retry_in", delay),
+		)
+		err = c.outbox.ScheduleCallbackRetry(ctx, delivery.ID, delay, deliveryErr.Error())
+	}
+
+	if err != nil {
+		ctxzap.Error(ctx, "failed to record callback delivery outcome",
+			zap.Error(err),
+			zap.String("delivery_id", delivery.ID),
+		)
+	}
+}
+
+// retryDelay is the backoff after the given number of attempts: BaseDelay doubled on every
+// further attempt, up to MaxDelay
+func (c *Connector) retryDelay(attempts int) time.Duration {
+	delay := c.outboxCfg.BaseDelay
+	for i := 1; i < attempts && delay < c.outboxCfg.MaxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, c.outboxCfg.MaxDelay)
+}
+
+// retryable reports whether a failed delivery may succeed later; a consumer rejecting the event
+// with a client error other than a timeout or rate limit will reject it again
+func retryable(err error) bool {
+	var httpErr *pkghttp.HTTPError
+	if !errors.As(err, &httpErr) {
+		return true
+	}
+
+	switch httpErr.StatusCode {
+	case http.StatusRequestTimeout, http.StatusTooManyRequests:
+		return true
+	default:
+		return httpErr.StatusCode >= http.StatusInternalServerError
+	}
+}
@@ -2,18 +2,19 @@ package common
 
 import (
 	"github.com/futig/agent-backend/internal/config"
+	"github.com/futig/agent-backend/internal/pkg/metrics"
 	pkgHTTP "github.com/futig/agent-backend/pkg/http"
 	"go.uber.org/zap"
 )
 
-func NewBaseConnector(cfg config.HTTPClientConfig, logger *zap.Logger) *pkgHTTP.Connector {
+// NewBaseConnector creates the HTTP connector of an external service; name keys its latency metrics and spans
+func NewBaseConnector(name string, cfg config.HTTPClientConfig, logger *zap.Logger) *pkgHTTP.Connector {
 	connCfg := &pkgHTTP.ConnectorConfig{
 		Logger:  logger,
 		BaseURL: cfg.Url,
 	}
 
-	return pkgHTTP.NewConnector(
-		connCfg,
+	opts := []pkgHTTP.HttpOpts{
 		pkgHTTP.WithRequestTimeout(cfg.RequestTimeout),
 		pkgHTTP.WithConnClientTimeout(cfg.ConnTimeout),
 		pkgHTTP.WithClientKeepAlive(cfg.KeepAlive),
@@ -21,5 +22,24 @@ func NewBaseConnector(cfg config.HTTPClientConfig, logger *zap.Logger) *pkgHTTP.
 		pkgHTTP.WithResponseHeaderTimeout(cfg.ResponseHeaderTimeout),
 		pkgHTTP.WithRequestLogging(),
 		pkgHTTP.WithAuthToken(cfg.Token),
-	)
+		pkgHTTP.WithRequestObserver(func(stats pkgHTTP.RequestStats) {
+			metrics.ConnectorRequestDuration.WithLabelValues(name).Observe(stats.Duration.Seconds())
+			if stats.NewConn {
+				metrics.ConnectorNewConnections.WithLabelValues(name).Inc()
+			}
+			if stats.Err != nil {
+				metrics.ConnectorErrors.WithLabelValues(name).Inc()
+			}
+		}),
+	}
+	if cfg.MaxIdleConnsPerHost > 0 {
+		opts = append(opts, pkgHTTP.WithMaxIdleConnsPerHost(cfg.MaxIdleConnsPerHost))
+	}
+	if cfg.Warmup.DNSRefresh > 0 {
+		opts = append(opts, pkgHTTP.WithDNSCache(cfg.Warmup.DNSRefresh))
+	}
+	// Outermost, so the span covers the whole request and the trace headers reach the service
+	opts = append(opts, pkgHTTP.WithTracing(name))
+
+	return pkgHTTP.NewConnector(connCfg, opts...)
 }
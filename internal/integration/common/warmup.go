@@ -0,0 +1,74 @@
+package common
+
+import (
+	"context"
+	"time"
+
+	"github.com/futig/agent-backend/internal/config"
+	"github.com/futig/agent-backend/internal/pkg/metrics"
+	pkgHTTP "github.com/futig/agent-backend/pkg/http"
+	"go.uber.org/zap"
+)
+
+// Warmer pings a connector on startup and whenever it stayed idle for the configured interval, so
+// the first real request after a pause reuses a pooled connection instead of paying DNS, TCP and TLS
+type Warmer struct {
+	name      string
+	connector *pkgHTTP.Connector
+	cfg       config.WarmupConfig
+	logger    *zap.Logger
+}
+
+func NewWarmer(name string, connector *pkgHTTP.Connector, cfg config.WarmupConfig, logger *zap.Logger) *Warmer {
+	return &Warmer{
+		name:      name,
+		connector: connector,
+		cfg:       cfg,
+		logger:    logger,
+	}
+}
+
+// Run pings the connector until ctx is done; it returns right away when warm-up is disabled
+func (w *Warmer) Run(ctx context.Context) {
+	if !w.cfg.Enabled {
+		return
+	}
+
+	w.ping(ctx)
+	if w.cfg.IdleInterval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(w.cfg.IdleInterval / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if w.connector.IdleFor() >= w.cfg.IdleInterval {
+				w.ping(ctx)
+			}
+		}
+	}
+}
+
+func (w *Warmer) ping(ctx context.Context) {
+	latency, err := w.connector.Warmup(ctx, w.cfg.Endpoint)
+	if err != nil {
+		if ctx.Err() == nil {
+			w.logger.Warn("connector warm-up failed",
+				zap.String("connector", w.name),
+				zap.Error(err),
+			)
+		}
+		return
+	}
+
+	metrics.ConnectorWarmupLatency.WithLabelValues(w.name).Set(latency.Seconds())
+	w.logger.Debug("connector warmed up",
+		zap.String("connector", w.name),
+		zap.Duration("latency", latency),
+	)
+}
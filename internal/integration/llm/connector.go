@@ -2,12 +2,17 @@ package llm
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"sync"
+	"time"
 
 	"github.com/futig/agent-backend/internal/config"
 	"github.com/futig/agent-backend/internal/entity"
 	"github.com/futig/agent-backend/internal/integration/common"
+	"github.com/futig/agent-backend/internal/pkg/limiter"
+	"github.com/futig/agent-backend/internal/pkg/tracing"
 	pkghttp "github.com/futig/agent-backend/pkg/http"
 	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
 	"go.uber.org/zap"
@@ -16,20 +21,37 @@ import (
 type Connector struct {
 	config    config.LLMConnectorConfig
 	connector *pkghttp.Connector
-	logger    *zap.Logger
+	// streamConnector reads streamed summaries, bounded by StreamTimeout instead of the request timeout
+	streamConnector *pkghttp.Connector
+	limiter         *limiter.Limiter
+	logger          *zap.Logger
+
+	reportMu   sync.Mutex
+	lastReport time.Time
 }
 
 func NewConnector(
 	cfg config.LLMConnectorConfig,
 	logger *zap.Logger,
 ) *Connector {
+	streamCfg := cfg.HTTPClientConfig
+	streamCfg.RequestTimeout = cfg.StreamTimeout
+
 	return &Connector{
-		connector: common.NewBaseConnector(cfg.HTTPClientConfig, logger),
-		config:    cfg,
-		logger:    logger,
+		connector:       common.NewBaseConnector("llm", cfg.HTTPClientConfig, logger),
+		streamConnector: common.NewBaseConnector("llm_stream", streamCfg, logger),
+		config:          cfg,
+		limiter:         limiter.New(cfg.Limits),
+		logger:          logger,
+		lastReport:      time.Now(),
 	}
 }
 
+// Warmer keeps the connections to the LLM service ready, see config.WarmupConfig
+func (c *Connector) Warmer() *common.Warmer {
+	return common.NewWarmer("llm", c.connector, c.config.Warmup, c.logger)
+}
+
 // GenerateQuestions generates interview questions
 func (c *Connector) GenerateQuestions(ctx context.Context, req *entity.LLMGenerateQuestionsRequest) (
 	*entity.LLMGenerateQuestionsResponse, error,
@@ -37,7 +59,7 @@ func (c *Connector) GenerateQuestions(ctx context.Context, req *entity.LLMGenera
 	ctxzap.Info(ctx, "generating questions via LLM service")
 
 	var rawResp entity.LLMGenerateQuestionsResponse
-	err := c.connector.DoRequest(ctx, http.MethodPost, c.config.GenerateQuestionsEndpoint, req, &rawResp)
+	err := c.doRequest(ctx, c.config.GenerateQuestionsEndpoint, req, &rawResp)
 	if err != nil {
 		return nil, err
 	}
@@ -54,7 +76,7 @@ func (c *Connector) ValidateAnswers(ctx context.Context, req *entity.LLMValidate
 	ctxzap.Info(ctx, "validating answers via LLM service")
 
 	var resp entity.LLMValidateAnswersResponse
-	err := c.connector.DoRequest(ctx, http.MethodPost, c.config.ValidateAnswersEndpoint, req, &resp)
+	err := c.doRequest(ctx, c.config.ValidateAnswersEndpoint, req, &resp)
 	if err != nil {
 		return nil, fmt.Errorf("validate answers failed: %w", err)
 	}
@@ -69,7 +91,7 @@ func (c *Connector) GenerateSummary(ctx context.Context, req *entity.LLMGenerate
 	ctxzap.Info(ctx, "generating summary via LLM service")
 
 	var resp entity.LLMGenerateSummaryResponse
-	err := c.connector.DoRequest(ctx, http.MethodPost, c.config.GenerateSummaryEndpoint, req, &resp)
+	err := c.doRequest(ctx, c.config.GenerateSummaryEndpoint, req, &resp)
 	if err != nil {
 		return "", fmt.Errorf("generate summary failed: %w", err)
 	}
@@ -90,7 +112,7 @@ func (c *Connector) ValidateDraft(ctx context.Context, req *entity.LLMValidateDr
 	ctxzap.Info(ctx, "validating answers via LLM service")
 
 	var resp entity.LLMValidateAnswersResponse
-	err := c.connector.DoRequest(ctx, http.MethodPost, c.config.ValidateDraftEndpoint, req, &resp)
+	err := c.doRequest(ctx, c.config.ValidateDraftEndpoint, req, &resp)
 	if err != nil {
 		return nil, fmt.Errorf("validate answers failed: %w", err)
 	}
@@ -105,7 +127,7 @@ func (c *Connector) GenerateDraftSummary(ctx context.Context, req *entity.LLMGen
 	ctxzap.Info(ctx, "generating summary via LLM service")
 
 	var resp entity.LLMGenerateSummaryResponse
-	err := c.connector.DoRequest(ctx, http.MethodPost, c.config.GenerateDraftSummaryEndpoint, req, &resp)
+	err := c.doRequest(ctx, c.config.GenerateDraftSummaryEndpoint, req, &resp)
 	if err != nil {
 		return "", fmt.Errorf("generate summary failed: %w", err)
 	}
@@ -118,3 +140,323 @@ func (c *Connector) GenerateDraftSummary(ctx context.Context, req *entity.LLMGen
 
 	return resp.Result, nil
 }
+
+// GenerateOutline generates the section outline for sectioned document generation
+func (c *Connector) GenerateOutline(ctx context.Context, req *entity.LLMGenerateOutlineRequest) (
+	*entity.LLMGenerateOutlineResponse, error,
+) {
+	ctxzap.Info(ctx, "generating document outline via LLM service")
+
+	var resp entity.LLMGenerateOutlineResponse
+	err := c.doRequest(ctx, c.config.GenerateOutlineEndpoint, req, &resp)
+	if err != nil {
+		return nil, fmt.Errorf("generate outline failed: %w", err)
+	}
+
+	if len(resp.Sections) == 0 {
+		return nil, fmt.Errorf("invalid outline response: empty or missing sections field")
+	}
+
+	ctxzap.Info(ctx, "outline generated successfully", zap.Int("sections", len(resp.Sections)))
+
+	return &resp, nil
+}
+
+// GenerateSection generates a single document section guided by the outline
+func (c *Connector) GenerateSection(ctx context.Context, req *entity.LLMGenerateSectionRequest) (string, error) {
+	ctxzap.Info(ctx, "generating document section via LLM service",
+		zap.Int("section_index", req.SectionIndex),
+		zap.String("section_title", req.Section.Title),
+	)
+
+	var resp entity.LLMGenerateSummaryResponse
+	err := c.doRequest(ctx, c.config.GenerateSectionEndpoint, req, &resp)
+	if err != nil {
+		return "", fmt.Errorf("generate section failed: %w", err)
+	}
+
+	if resp.Result == "" {
+		return "", fmt.Errorf("invalid section response: empty or missing result field")
+	}
+
+	ctxzap.Info(ctx, "section generated successfully", zap.Int("result_length", len(resp.Result)))
+
+	return resp.Result, nil
+}
+
+// GenerateDeltaQuestions generates interview questions about changes since the baseline document
+func (c *Connector) GenerateDeltaQuestions(ctx context.Context, req *entity.LLMGenerateDeltaQuestionsRequest) (
+	*entity.LLMGenerateQuestionsResponse, error,
+) {
+	ctxzap.Info(ctx, "generating delta questions via LLM service", zap.Int("baseline_length", len(req.Baseline)))
+
+	var rawResp entity.LLMGenerateQuestionsResponse
+	err := c.doRequest(ctx, c.config.GenerateDeltaQuestionsEndpoint, req, &rawResp)
+	if err != nil {
+		return nil, err
+	}
+
+	ctxzap.Info(ctx, "delta questions generated successfully", zap.Int("block_count", len(rawResp.Iterations)))
+
+	return &rawResp, nil
+}
+
+// GenerateDeltaSummary produces a change log and the updated baseline document
+func (c *Connector) GenerateDeltaSummary(ctx context.Context, req *entity.LLMGenerateDeltaSummaryRequest) (
+	*entity.LLMGenerateDeltaSummaryResponse, error,
+) {
+	ctxzap.Info(ctx, "generating delta summary via LLM service")
+
+	var resp entity.LLMGenerateDeltaSummaryResponse
+	err := c.doRequest(ctx, c.config.GenerateDeltaSummaryEndpoint, req, &resp)
+	if err != nil {
+		return nil, fmt.Errorf("generate delta summary failed: %w", err)
+	}
+
+	if resp.Result == "" || resp.ChangeLog == "" {
+		return nil, fmt.Errorf("invalid delta summary response: empty or missing result or change_log field")
+	}
+
+	ctxzap.Info(ctx, "delta summary generated successfully",
+		zap.Int("result_length", len(resp.Result)),
+		zap.Int("change_log_length", len(resp.ChangeLog)),
+	)
+
+	return &resp, nil
+}
+
+// RefineResult revises a requirements document to address reviewer comments
+func (c *Connector) RefineResult(ctx context.Context, req *entity.LLMRefineResultRequest) (string, error) {
+	ctxzap.Info(ctx, "refining result via LLM service", zap.Int("comments", len(req.Comments)))
+
+	var resp entity.LLMGenerateSummaryResponse
+	err := c.doRequest(ctx, c.config.RefineResultEndpoint, req, &resp)
+	if err != nil {
+		return "", fmt.Errorf("refine result failed: %w", err)
+	}
+
+	if resp.Result == "" {
+		return "", fmt.Errorf("invalid refine response: empty or missing result field")
+	}
+
+	ctxzap.Info(ctx, "result refined successfully", zap.Int("result_length", len(resp.Result)))
+
+	return resp.Result, nil
+}
+
+// RestyleResult rewrites a requirements document in another style keeping its content
+func (c *Connector) RestyleResult(ctx context.Context, req *entity.LLMRestyleResultRequest) (string, error) {
+	ctxzap.Info(ctx, "restyling result via LLM service", zap.String("style", string(req.Style)))
+
+	var resp entity.LLMGenerateSummaryResponse
+	err := c.doRequest(ctx, c.config.RestyleResultEndpoint, req, &resp)
+	if err != nil {
+		return "", fmt.Errorf("restyle result failed: %w", err)
+	}
+
+	if resp.Result == "" {
+		return "", fmt.Errorf("invalid restyle response: empty or missing result field")
+	}
+
+	ctxzap.Info(ctx, "result restyled successfully", zap.Int("result_length", len(resp.Result)))
+
+	return resp.Result, nil
+}
+
+// DetectConflicts finds new requirements contradicting earlier project requirements
+func (c *Connector) DetectConflicts(ctx context.Context, req *entity.LLMDetectConflictsRequest) (
+	*entity.LLMDetectConflictsResponse, error,
+) {
+	ctxzap.Info(ctx, "detecting requirement conflicts via LLM service")
+
+	var resp entity.LLMDetectConflictsResponse
+	err := c.doRequest(ctx, c.config.DetectConflictsEndpoint, req, &resp)
+	if err != nil {
+		return nil, fmt.Errorf("detect conflicts failed: %w", err)
+	}
+
+	ctxzap.Info(ctx, "requirement conflicts detected", zap.Int("conflicts", len(resp.Conflicts)))
+
+	return &resp, nil
+}
+
+// Translate translates a requirements document into the target language
+func (c *Connector) Translate(ctx context.Context, req *entity.LLMTranslateRequest) (string, error) {
+	ctxzap.Info(ctx, "translating result via LLM service", zap.String("target_language", req.TargetLanguage))
+
+	var resp entity.LLMTranslateResponse
+	err := c.doRequest(ctx, c.config.TranslateEndpoint, req, &resp)
+	if err != nil {
+		return "", fmt.Errorf("translate failed: %w", err)
+	}
+
+	if resp.Result == "" {
+		return "", fmt.Errorf("invalid translate response: empty or missing result field")
+	}
+
+	ctxzap.Info(ctx, "result translated successfully", zap.Int("result_length", len(resp.Result)))
+
+	return resp.Result, nil
+}
+
+// NormalizeTranscript fixes spelling, punctuation and ASR artifacts in a transcription
+func (c *Connector) NormalizeTranscript(ctx context.Context, req *entity.LLMNormalizeTranscriptRequest) (string, error) {
+	ctxzap.Info(ctx, "normalizing transcript via LLM service", zap.Int("text_length", len(req.Text)))
+
+	var resp entity.LLMGenerateSummaryResponse
+	err := c.doRequest(ctx, c.config.NormalizeTranscriptEndpoint, req, &resp)
+	if err != nil {
+		return "", fmt.Errorf("normalize transcript failed: %w", err)
+	}
+
+	if resp.Result == "" {
+		return "", fmt.Errorf("invalid normalize response: empty or missing result field")
+	}
+
+	return resp.Result, nil
+}
+
+// DescribeProject writes a concise project description from the saved requirements
+func (c *Connector) DescribeProject(ctx context.Context, req *entity.LLMDescribeProjectRequest) (string, error) {
+	ctxzap.Info(ctx, "describing project via LLM service", zap.Int("requirements_length", len(req.Requirements)))
+
+	var resp entity.LLMDescribeProjectResponse
+	err := c.doRequest(ctx, c.config.DescribeProjectEndpoint, req, &resp)
+	if err != nil {
+		return "", fmt.Errorf("describe project failed: %w", err)
+	}
+
+	if resp.Description == "" {
+		return "", fmt.Errorf("invalid describe project response: empty or missing description field")
+	}
+
+	return resp.Description, nil
+}
+
+// ExtractFacts condenses the facts the user already provided in the session
+func (c *Connector) ExtractFacts(ctx context.Context, req *entity.LLMExtractFactsRequest) ([]string, error) {
+	ctxzap.Info(ctx, "extracting session facts via LLM service",
+		zap.Int("messages_count", len(req.Messages)),
+		zap.Int("answers_count", len(req.CompleteQuestions)),
+		zap.Int("known_facts_count", len(req.KnownFacts)),
+	)
+
+	var resp entity.LLMExtractFactsResponse
+	err := c.doRequest(ctx, c.config.ExtractFactsEndpoint, req, &resp)
+	if err != nil {
+		return nil, fmt.Errorf("extract facts failed: %w", err)
+	}
+
+	if resp.Facts == nil {
+		return nil, fmt.Errorf("invalid extract facts response: missing facts field")
+	}
+
+	return resp.Facts, nil
+}
+
+// ExplainQuestion explains why the question is asked and what a good answer covers
+func (c *Connector) ExplainQuestion(ctx context.Context, req *entity.LLMExplainQuestionRequest) (string, error) {
+	ctxzap.Info(ctx, "explaining question via LLM service", zap.Int("question_length", len(req.Question)))
+
+	var resp entity.LLMExplainQuestionResponse
+	err := c.doRequest(ctx, c.config.ExplainQuestionEndpoint, req, &resp)
+	if err != nil {
+		return "", fmt.Errorf("explain question failed: %w", err)
+	}
+
+	if resp.Explanation == "" {
+		return "", fmt.Errorf("invalid explain question response: empty or missing explanation field")
+	}
+
+	return resp.Explanation, nil
+}
+
+// doRequest posts req to the LLM service once the limiter grants a slot for the provider of the tenant.
+// Calls waiting longer than the queue timeout or finding the queue full fail with ErrLLMOverloaded.
+func (c *Connector) doRequest(ctx context.Context, endpoint string, req, resp any) error {
+	ctx, span := tracing.Start(ctx, "llm "+endpoint)
+	defer span.End()
+
+	release, err := c.acquire(ctx)
+	if err != nil {
+		tracing.Fail(span, err)
+		return err
+	}
+	defer release()
+
+	if err := c.connector.DoRequest(ctx, http.MethodPost, endpoint, req, resp, tenantOpts(ctx)...); err != nil {
+		tracing.Fail(span, err)
+		return err
+	}
+	return nil
+}
+
+// acquire takes a concurrency slot of the provider of ctx, the returned func releases it
+func (c *Connector) acquire(ctx context.Context) (func(), error) {
+	provider := providerKey(ctx)
+	priority := entity.LLMPriorityFromContext(ctx)
+
+	release, err := c.limiter.Acquire(ctx, provider, int(priority))
+	c.reportSaturation()
+	if err != nil {
+		if errors.Is(err, limiter.ErrQueueFull) || errors.Is(err, limiter.ErrQueueTimeout) {
+			ctxzap.Warn(ctx, "LLM call rejected by concurrency limits",
+				zap.String("provider", provider),
+				zap.Stringer("priority", priority),
+				zap.Error(err),
+			)
+			return nil, fmt.Errorf("%w: %v", entity.ErrLLMOverloaded, err)
+		}
+		return nil, err
+	}
+	return release, nil
+}
+
+// reportSaturation logs the limiter saturation once per report interval
+func (c *Connector) reportSaturation() {
+	interval := c.config.Limits.ReportInterval
+	if interval <= 0 {
+		return
+	}
+
+	c.reportMu.Lock()
+	if time.Since(c.lastReport) < interval {
+		c.reportMu.Unlock()
+		return
+	}
+	c.lastReport = time.Now()
+	c.reportMu.Unlock()
+
+	stats := c.limiter.Report()
+	c.logger.Info("LLM limiter saturation",
+		zap.Int("in_flight", stats.InFlight),
+		zap.Int("queued", stats.Queued),
+		zap.Any("in_flight_per_provider", stats.InFlightPerKey),
+		zap.Int("peak_in_flight", stats.PeakInFlight),
+		zap.Int("peak_queued", stats.PeakQueued),
+		zap.Int("acquired", stats.Acquired),
+		zap.Int("waited", stats.Waited),
+		zap.Int("rejected", stats.Rejected),
+		zap.Int("timed_out", stats.TimedOut),
+		zap.Duration("max_wait", stats.MaxWait),
+		zap.Int("max_concurrent", c.config.Limits.MaxConcurrent),
+		zap.Int("max_concurrent_per_provider", c.config.Limits.MaxConcurrentPerKey),
+	)
+}
+
+// providerKey returns the model provider of the tenant of ctx, the limits of the default provider apply otherwise
+func providerKey(ctx context.Context) string {
+	if tenant := entity.TenantFromContext(ctx); tenant != nil && tenant.Settings.LLMProvider != "" {
+		return tenant.Settings.LLMProvider
+	}
+	return "default"
+}
+
+// tenantOpts asks the LLM service for the model provider configured for the tenant of ctx
+func tenantOpts(ctx context.Context) []pkghttp.RequestOpt {
+	tenant := entity.TenantFromContext(ctx)
+	if tenant == nil || tenant.Settings.LLMProvider == "" {
+		return nil
+	}
+	return []pkghttp.RequestOpt{pkghttp.WithHeader("X-LLM-Provider", tenant.Settings.LLMProvider)}
+}
@@ -2,6 +2,9 @@ package llm
 
 import (
 	"context"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/futig/agent-backend/internal/entity"
 	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
@@ -66,8 +69,9 @@ func (m *MockConnector) GenerateQuestions(ctx context.Context, req *entity.LLMGe
 				Title: "Нефункциональные требования",
 				Questions: []entity.LLMQuestion{
 					{
-						Text:        "Какие требования к производительности системы?",
+						Text:        "Насколько критична производительность системы (1–5)?",
 						Explanation: "Определение ожидаемой нагрузки и скорости работы",
+						Type:        string(entity.QuestionAnswerTypeScale),
 					},
 					{
 						Text:        "Какие требования к безопасности данных?",
@@ -175,6 +179,38 @@ func (m *MockConnector) GenerateSummary(ctx context.Context, req *entity.LLMGene
 	return summary, nil
 }
 
+// mockStreamDelay - пауза между частями потокового резюме
+const mockStreamDelay = 300 * time.Millisecond
+
+// GenerateSummaryStream - мок потоковой генерации резюме, отдает мок-резюме по абзацам
+func (m *MockConnector) GenerateSummaryStream(ctx context.Context, req *entity.LLMGenerateSummaryRequest) (
+	<-chan entity.LLMSummaryChunk, error,
+) {
+	summary, err := m.GenerateSummary(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	chunks := make(chan entity.LLMSummaryChunk)
+	go func() {
+		defer close(chunks)
+		for _, paragraph := range strings.SplitAfter(summary, "\n\n") {
+			select {
+			case <-time.After(mockStreamDelay):
+			case <-ctx.Done():
+				return
+			}
+			select {
+			case chunks <- entity.LLMSummaryChunk{Content: paragraph}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return chunks, nil
+}
+
 // ValidateDraft - мок валидации черновика
 func (m *MockConnector) ValidateDraft(ctx context.Context, req *entity.LLMValidateDraftRequest) (
 	*entity.LLMValidateAnswersResponse, error,
@@ -209,3 +245,201 @@ func (m *MockConnector) GenerateDraftSummary(ctx context.Context, req *entity.LL
 	ctxzap.Info(ctx, "[MOCK] draft summary generated", zap.Int("result_length", len(summary)))
 	return summary, nil
 }
+
+// GenerateOutline - мок генерации плана документа
+func (m *MockConnector) GenerateOutline(ctx context.Context, req *entity.LLMGenerateOutlineRequest) (
+	*entity.LLMGenerateOutlineResponse, error,
+) {
+	ctxzap.Info(ctx, "[MOCK] generating document outline via LLM")
+
+	resp := &entity.LLMGenerateOutlineResponse{
+		Sections: []entity.DocumentSection{
+			{Title: "Обзор проекта", Description: "Цели, контекст и заинтересованные стороны"},
+			{Title: "Функциональные требования", Description: "Основная функциональность системы"},
+			{Title: "Нефункциональные требования", Description: "Производительность, безопасность, надёжность"},
+			{Title: "Ограничения и предположения", Description: "Сроки, бюджет, технические ограничения"},
+		},
+	}
+
+	ctxzap.Info(ctx, "[MOCK] outline generated", zap.Int("sections", len(resp.Sections)))
+	return resp, nil
+}
+
+// GenerateSection - мок генерации раздела документа
+func (m *MockConnector) GenerateSection(ctx context.Context, req *entity.LLMGenerateSectionRequest) (string, error) {
+	ctxzap.Info(ctx, "[MOCK] generating document section via LLM", zap.Int("section_index", req.SectionIndex))
+
+	section := fmt.Sprintf("%s.\n\n- Требование раздела %d (MOCK)", req.Section.Description, req.SectionIndex+1)
+
+	ctxzap.Info(ctx, "[MOCK] section generated", zap.Int("result_length", len(section)))
+	return section, nil
+}
+
+// RefineResult - мок доработки документа по комментариям
+func (m *MockConnector) RefineResult(ctx context.Context, req *entity.LLMRefineResultRequest) (string, error) {
+	ctxzap.Info(ctx, "[MOCK] refining result via LLM", zap.Int("comments", len(req.Comments)))
+
+	// Мок не меняет документ, а дописывает учтённые комментарии
+	var sb strings.Builder
+	sb.WriteString(req.Result)
+	sb.WriteString("\n\n## Учтённые комментарии (MOCK)\n")
+	for _, c := range req.Comments {
+		fmt.Fprintf(&sb, "\n- %s", c.Text)
+	}
+	result := sb.String()
+
+	ctxzap.Info(ctx, "[MOCK] result refined", zap.Int("result_length", len(result)))
+	return result, nil
+}
+
+// GenerateDeltaQuestions - мок вопросов об изменениях относительно базового документа
+func (m *MockConnector) GenerateDeltaQuestions(ctx context.Context, req *entity.LLMGenerateDeltaQuestionsRequest) (
+	*entity.LLMGenerateQuestionsResponse, error,
+) {
+	ctxzap.Info(ctx, "[MOCK] generating delta questions via LLM", zap.Int("baseline_length", len(req.Baseline)))
+
+	resp := &entity.LLMGenerateQuestionsResponse{
+		Iterations: []entity.QuestionsBlock{
+			{
+				Title: "Изменения с прошлой версии",
+				Questions: []entity.LLMQuestion{
+					{
+						Text:        "Какие новые функции или сценарии появились с прошлой версии требований?",
+						Explanation: "Нужно выявить добавленную функциональность",
+					},
+					{
+						Text:        "Какие требования устарели или больше не актуальны?",
+						Explanation: "Нужно понять, что убрать из документа",
+					},
+					{
+						Text:        "Изменились ли ограничения: сроки, бюджет, нагрузка, интеграции?",
+						Explanation: "Нужно обновить нефункциональные требования",
+					},
+				},
+			},
+		},
+	}
+
+	ctxzap.Info(ctx, "[MOCK] delta questions generated", zap.Int("block_count", len(resp.Iterations)))
+	return resp, nil
+}
+
+// GenerateDeltaSummary - мок журнала изменений и обновлённого документа
+func (m *MockConnector) GenerateDeltaSummary(ctx context.Context, req *entity.LLMGenerateDeltaSummaryRequest) (
+	*entity.LLMGenerateDeltaSummaryResponse, error,
+) {
+	ctxzap.Info(ctx, "[MOCK] generating delta summary via LLM", zap.Int("answers", len(req.CompleteQuestions)))
+
+	// Мок не переписывает документ, а дописывает ответы об изменениях
+	var changeLog strings.Builder
+	changeLog.WriteString("# Что изменилось (MOCK)\n")
+	for _, qa := range req.CompleteQuestions {
+		fmt.Fprintf(&changeLog, "\n- %s: %s", qa.Question, qa.Answer)
+	}
+
+	resp := &entity.LLMGenerateDeltaSummaryResponse{
+		ChangeLog: changeLog.String(),
+		Result:    req.Baseline + "\n\n" + strings.Replace(changeLog.String(), "# ", "## ", 1),
+	}
+
+	ctxzap.Info(ctx, "[MOCK] delta summary generated", zap.Int("result_length", len(resp.Result)))
+	return resp, nil
+}
+
+// DetectConflicts - мок поиска противоречий с прежними требованиями проекта
+func (m *MockConnector) DetectConflicts(ctx context.Context, req *entity.LLMDetectConflictsRequest) (
+	*entity.LLMDetectConflictsResponse, error,
+) {
+	ctxzap.Info(ctx, "[MOCK] detecting requirement conflicts via LLM", zap.Int("requirements_length", len(req.Requirements)))
+
+	// Мок находит противоречие только при наличии прежних требований проекта
+	resp := &entity.LLMDetectConflictsResponse{Conflicts: []entity.LLMConflict{}}
+	if req.PreviousRequirements != nil && *req.PreviousRequirements != "" {
+		resp.Conflicts = append(resp.Conflicts, entity.LLMConflict{
+			Requirement: "Система должна поддерживать вход по одноразовому коду (MOCK)",
+			Previous:    "Вход в систему выполняется только по логину и паролю (MOCK)",
+			Source:      "previous requirements",
+		})
+	}
+
+	ctxzap.Info(ctx, "[MOCK] requirement conflicts detected", zap.Int("conflicts", len(resp.Conflicts)))
+	return resp, nil
+}
+
+// RestyleResult - мок смены стиля документа
+func (m *MockConnector) RestyleResult(ctx context.Context, req *entity.LLMRestyleResultRequest) (string, error) {
+	ctxzap.Info(ctx, "[MOCK] restyling result via LLM", zap.String("style", string(req.Style)))
+
+	// Мок не переписывает текст, а помечает его выбранным стилем
+	result := fmt.Sprintf("<!-- style: %s (MOCK) -->\n\n%s", req.Style, req.Result)
+
+	ctxzap.Info(ctx, "[MOCK] result restyled", zap.Int("result_length", len(result)))
+	return result, nil
+}
+
+// Translate - мок перевода документа
+func (m *MockConnector) Translate(ctx context.Context, req *entity.LLMTranslateRequest) (string, error) {
+	ctxzap.Info(ctx, "[MOCK] translating result via LLM", zap.String("target_language", req.TargetLanguage))
+
+	// Мок не переводит текст, а помечает его целевым языком
+	result := fmt.Sprintf("<!-- translated to: %s (MOCK) -->\n\n%s", req.TargetLanguage, req.Text)
+
+	ctxzap.Info(ctx, "[MOCK] result translated", zap.Int("result_length", len(result)))
+	return result, nil
+}
+
+// NormalizeTranscript - мок исправления расшифровки
+func (m *MockConnector) NormalizeTranscript(ctx context.Context, req *entity.LLMNormalizeTranscriptRequest) (string, error) {
+	ctxzap.Info(ctx, "[MOCK] normalizing transcript via LLM", zap.Int("text_length", len(req.Text)))
+
+	// Мок не исправляет текст, а возвращает его без лишних пробелов
+	return strings.Join(strings.Fields(req.Text), " "), nil
+}
+
+// DescribeProject - мок описания проекта по требованиям
+func (m *MockConnector) DescribeProject(ctx context.Context, req *entity.LLMDescribeProjectRequest) (string, error) {
+	ctxzap.Info(ctx, "[MOCK] describing project via LLM", zap.Int("requirements_length", len(req.Requirements)))
+
+	// Мок описывает проект по названию и началу требований
+	summary := []rune(strings.Join(strings.Fields(req.Requirements), " "))
+	if len(summary) > req.MaxChars/2 {
+		summary = summary[:req.MaxChars/2]
+	}
+
+	return fmt.Sprintf("%s: %s… (MOCK)", req.Title, string(summary)), nil
+}
+
+// ExtractFacts - мок извлечения уже известных фактов сессии
+func (m *MockConnector) ExtractFacts(ctx context.Context, req *entity.LLMExtractFactsRequest) ([]string, error) {
+	ctxzap.Info(ctx, "[MOCK] extracting session facts via LLM",
+		zap.Int("messages_count", len(req.Messages)),
+		zap.Int("answers_count", len(req.CompleteQuestions)),
+	)
+
+	// Мок считает фактом каждый ответ и сообщение, сохраняя ранее известные
+	facts := append([]string{}, req.KnownFacts...)
+	for _, qa := range req.CompleteQuestions {
+		if qa.Answer != "" {
+			facts = append(facts, fmt.Sprintf("%s: %s", qa.Question, qa.Answer))
+		}
+	}
+	for _, message := range req.Messages {
+		facts = append(facts, strings.Join(strings.Fields(message), " "))
+	}
+	if req.MaxFacts > 0 && len(facts) > req.MaxFacts {
+		facts = facts[len(facts)-req.MaxFacts:]
+	}
+
+	return facts, nil
+}
+
+// ExplainQuestion - мок пояснения вопроса по запросу пользователя
+func (m *MockConnector) ExplainQuestion(ctx context.Context, req *entity.LLMExplainQuestionRequest) (string, error) {
+	ctxzap.Info(ctx, "[MOCK] explaining question via LLM", zap.Int("question_length", len(req.Question)))
+
+	// Мок дополняет пояснение, пришедшее вместе с вопросом
+	if req.Explanation != "" {
+		return fmt.Sprintf("%s\n\nВопрос помогает уточнить цель: %s (MOCK)", req.Explanation, req.UserGoal), nil
+	}
+	return fmt.Sprintf("Вопрос «%s» помогает уточнить цель: %s (MOCK)", req.Question, req.UserGoal), nil
+}
@@ -0,0 +1,112 @@
+package llm
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/pkg/tracing"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+const (
+	// streamDone is the data of the event closing a successful summary stream
+	streamDone = "[DONE]"
+	// maxStreamEventSize bounds a single server-sent event line
+	maxStreamEventSize = 1 << 20
+)
+
+// GenerateSummaryStream generates the summary as server-sent events, each `data:` line carries an
+// LLMSummaryStreamEvent and `data: [DONE]` ends the stream. The channel is closed after the last
+// chunk; a stream that fails or ends without [DONE] delivers a chunk with Err last
+func (c *Connector) GenerateSummaryStream(ctx context.Context, req *entity.LLMGenerateSummaryRequest) (
+	<-chan entity.LLMSummaryChunk, error,
+) {
+	ctxzap.Info(ctx, "streaming summary via LLM service")
+
+	// The span lasts until the stream ends, not until the call returns
+	ctx, span := tracing.Start(ctx, "llm "+c.config.GenerateSummaryStreamEndpoint)
+
+	release, err := c.acquire(ctx)
+	if err != nil {
+		tracing.Fail(span, err)
+		span.End()
+		return nil, fmt.Errorf("generate summary stream failed: %w", err)
+	}
+
+	body, err := c.streamConnector.DoStreamRequest(ctx, http.MethodPost, c.config.GenerateSummaryStreamEndpoint, req, tenantOpts(ctx)...)
+	if err != nil {
+		release()
+		tracing.Fail(span, err)
+		span.End()
+		return nil, fmt.Errorf("generate summary stream failed: %w", err)
+	}
+
+	chunks := make(chan entity.LLMSummaryChunk)
+	go func() {
+		defer span.End()
+		defer close(chunks)
+		defer release()
+		defer body.Close()
+
+		send := func(chunk entity.LLMSummaryChunk) bool {
+			if chunk.Err != nil {
+				tracing.Fail(span, chunk.Err)
+			}
+			select {
+			case chunks <- chunk:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		length := 0
+		scanner := bufio.NewScanner(body)
+		scanner.Buffer(make([]byte, 0, 64*1024), maxStreamEventSize)
+		for scanner.Scan() {
+			data, ok := strings.CutPrefix(scanner.Text(), "data:")
+			if !ok {
+				// Event names, ids and keep-alive comments carry nothing of the summary
+				continue
+			}
+			data = strings.TrimSpace(data)
+			if data == streamDone {
+				ctxzap.Info(ctx, "summary streamed successfully", zap.Int("result_length", length))
+				return
+			}
+
+			var event entity.LLMSummaryStreamEvent
+			if err := json.Unmarshal([]byte(data), &event); err != nil {
+				send(entity.LLMSummaryChunk{Err: fmt.Errorf("decode summary stream event: %w", err)})
+				return
+			}
+			if event.Error != "" {
+				send(entity.LLMSummaryChunk{Err: fmt.Errorf("summary stream failed: %s", event.Error)})
+				return
+			}
+			if event.Content == "" {
+				continue
+			}
+
+			length += len(event.Content)
+			if !send(entity.LLMSummaryChunk{Content: event.Content}) {
+				return
+			}
+		}
+
+		err := scanner.Err()
+		if err == nil {
+			err = errors.New("summary stream ended before completion")
+		}
+		send(entity.LLMSummaryChunk{Err: fmt.Errorf("read summary stream: %w", err)})
+	}()
+
+	return chunks, nil
+}
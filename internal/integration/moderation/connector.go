@@ -0,0 +1,55 @@
+package moderation
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/futig/agent-backend/internal/config"
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/integration/common"
+	pkghttp "github.com/futig/agent-backend/pkg/http"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+type Connector struct {
+	config    config.ModerationConfig
+	connector *pkghttp.Connector
+	logger    *zap.Logger
+}
+
+func NewConnector(
+	cfg config.ModerationConfig,
+	logger *zap.Logger,
+) *Connector {
+	httpCfg := config.HTTPClientConfig{
+		RequestTimeout:        cfg.APITimeout,
+		ConnTimeout:           2 * time.Second,
+		KeepAlive:             30 * time.Second,
+		IdleConnTimeout:       30 * time.Second,
+		ResponseHeaderTimeout: cfg.APITimeout,
+		Token:                 cfg.APIToken,
+		Url:                   cfg.APIURL,
+	}
+
+	return &Connector{
+		connector: common.NewBaseConnector("moderation", httpCfg, logger),
+		config:    cfg,
+		logger:    logger,
+	}
+}
+
+// Check sends text to the external moderation service
+func (c *Connector) Check(ctx context.Context, text string) (*entity.ModerationAPIResponse, error) {
+	ctxzap.Debug(ctx, "checking text via moderation service")
+
+	var resp entity.ModerationAPIResponse
+	err := c.connector.DoRequest(ctx, http.MethodPost, c.config.APIEndpoint, &entity.ModerationAPIRequest{Text: text}, &resp)
+	if err != nil {
+		return nil, fmt.Errorf("moderation check failed: %w", err)
+	}
+
+	return &resp, nil
+}
@@ -5,11 +5,13 @@ import (
 	"fmt"
 	"mime/multipart"
 	"net/http"
+	"net/url"
 	"strings"
 
 	"github.com/futig/agent-backend/internal/config"
 	"github.com/futig/agent-backend/internal/entity"
 	"github.com/futig/agent-backend/internal/integration/common"
+	"github.com/futig/agent-backend/internal/pkg/tracing"
 	pkghttp "github.com/futig/agent-backend/pkg/http"
 	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
 	"go.uber.org/zap"
@@ -26,15 +28,23 @@ func NewConnector(
 	logger *zap.Logger,
 ) *Connector {
 	return &Connector{
-		connector: common.NewBaseConnector(cfg.HTTPClientConfig, logger),
+		connector: common.NewBaseConnector("rag", cfg.HTTPClientConfig, logger),
 		config:    cfg,
 		logger:    logger,
 	}
 }
 
+// Warmer keeps the connections to the RAG service ready, see config.WarmupConfig
+func (c *Connector) Warmer() *common.Warmer {
+	return common.NewWarmer("rag", c.connector, c.config.Warmup, c.logger)
+}
+
 // IndexFiles indexes files for a project
 // POST {index_endpoint}?project_id={id} with multipart/form-data
 func (c *Connector) IndexFiles(ctx context.Context, projectID string, files []entity.FileData) error {
+	ctx, span := tracing.Start(ctx, "rag IndexFiles", tracing.ProjectID(projectID))
+	defer span.End()
+
 	endpoint := fmt.Sprintf("%s?project_id=%s", c.config.IndexEndpoint, projectID)
 
 	ctxzap.Info(ctx, "indexing files in RAG service", zap.Int("file_count", len(files)))
@@ -56,6 +66,7 @@ func (c *Connector) IndexFiles(ctx context.Context, projectID string, files []en
 	err := c.connector.DoMultipartRequest(ctx, http.MethodPost, endpoint, prepareBody, nil)
 	if err != nil {
 		ctxzap.Error(ctx, "failed to index files", zap.Error(err))
+		tracing.Fail(span, err)
 		return err
 	}
 
@@ -67,6 +78,9 @@ func (c *Connector) IndexFiles(ctx context.Context, projectID string, files []en
 // DELETE {delete_endpoint} with {project_id} substituted
 // Returns true on any 2xx, false on HTTP error/exception
 func (c *Connector) DeleteIndex(ctx context.Context, projectID string) error {
+	ctx, span := tracing.Start(ctx, "rag DeleteIndex", tracing.ProjectID(projectID))
+	defer span.End()
+
 	endpoint := strings.Replace(c.config.DeleteEndpoint, "{project_id}", projectID, 1)
 
 	ctxzap.Info(ctx, "deleting RAG index")
@@ -75,6 +89,7 @@ func (c *Connector) DeleteIndex(ctx context.Context, projectID string) error {
 	err := c.connector.DoRequest(ctx, http.MethodDelete, endpoint, nil, &resp)
 	if err != nil {
 		ctxzap.Error(ctx, "failed to delete index", zap.Error(err))
+		tracing.Fail(span, err)
 		return err
 	}
 
@@ -82,13 +97,43 @@ func (c *Connector) DeleteIndex(ctx context.Context, projectID string) error {
 	return nil
 }
 
+// DeleteFile deletes the chunks of a single file from the index of a project; the RAG service
+// knows files by the names they were indexed with
+// DELETE {delete_file_endpoint} with {project_id} and {filename} substituted
+func (c *Connector) DeleteFile(ctx context.Context, projectID, filename string) error {
+	ctx, span := tracing.Start(ctx, "rag DeleteFile", tracing.ProjectID(projectID))
+	defer span.End()
+
+	endpoint := strings.NewReplacer(
+		"{project_id}", projectID,
+		"{filename}", url.PathEscape(filename),
+	).Replace(c.config.DeleteFileEndpoint)
+
+	ctxzap.Info(ctx, "deleting file from RAG index", zap.String("filename", filename))
+
+	var resp entity.RAGDeleteIndexResponse
+	err := c.connector.DoRequest(ctx, http.MethodDelete, endpoint, nil, &resp)
+	if err != nil {
+		ctxzap.Error(ctx, "failed to delete file from index", zap.Error(err))
+		tracing.Fail(span, err)
+		return err
+	}
+
+	ctxzap.Info(ctx, "file deleted from index", zap.Int("deleted_count", resp.DeletedCount))
+	return nil
+}
+
 // GetContext retrieves relevant context from RAG service
 func (c *Connector) GetContext(ctx context.Context, req *entity.RAGGetContextRequest) (string, error) {
+	ctx, span := tracing.Start(ctx, "rag GetContext")
+	defer span.End()
+
 	ctxzap.Debug(ctx, "getting context from RAG service")
 
 	var resp entity.RAGGetContextResponse
 	err := c.connector.DoRequest(ctx, http.MethodPost, c.config.ContextEndpoint, req, &resp)
 	if err != nil {
+		tracing.Fail(span, err)
 		return "", fmt.Errorf("failed to get context: %w", err)
 	}
 
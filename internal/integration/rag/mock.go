@@ -37,6 +37,15 @@ func (m *MockConnector) DeleteIndex(ctx context.Context, projectID string) error
 	return nil
 }
 
+// DeleteFile - мок удаления файла из индекса
+func (m *MockConnector) DeleteFile(ctx context.Context, projectID, filename string) error {
+	ctxzap.Info(ctx, "[MOCK] deleting file from RAG index",
+		zap.String("project_id", projectID),
+		zap.String("filename", filename),
+	)
+	return nil
+}
+
 // GetContext - мок получения контекста из RAG
 func (m *MockConnector) GetContext(ctx context.Context, req *entity.RAGGetContextRequest) (string, error) {
 	ctxzap.Info(ctx, "[MOCK] getting context from RAG",
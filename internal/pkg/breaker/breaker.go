@@ -0,0 +1,133 @@
+package breaker
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrOpen is returned while the circuit is open and calls are rejected without reaching the service
+var ErrOpen = errors.New("circuit breaker is open")
+
+// State is the state of a circuit
+type State int
+
+const (
+	// StateClosed lets all calls through
+	StateClosed State = iota
+	// StateOpen rejects calls until the open timeout passes
+	StateOpen
+	// StateHalfOpen lets a single probe call through to check whether the service recovered
+	StateHalfOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	}
+	return "unknown"
+}
+
+// Config sets when the circuit opens; a zero failure threshold never opens it
+type Config struct {
+	FailureThreshold int           `env:"FAILURE_THRESHOLD" envDefault:"5"`
+	OpenTimeout      time.Duration `env:"OPEN_TIMEOUT" envDefault:"30s"`
+}
+
+// Breaker opens the circuit after FailureThreshold consecutive failures. Once OpenTimeout
+// passes, one probe call is let through: its success closes the circuit, its failure opens it again.
+type Breaker struct {
+	cfg Config
+
+	mu       sync.Mutex
+	state    State
+	failures int
+	openedAt time.Time
+	probing  bool
+	onChange func(from, to State)
+}
+
+func New(cfg Config) *Breaker {
+	return &Breaker{cfg: cfg}
+}
+
+// OnStateChange sets a function called on every state change; it runs with the breaker locked,
+// so it must not call the breaker
+func (b *Breaker) OnStateChange(fn func(from, to State)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.onChange = fn
+}
+
+// Allow reports whether a call may go to the service; every allowed call must be followed by Record or Abandon
+func (b *Breaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case StateOpen:
+		if time.Since(b.openedAt) < b.cfg.OpenTimeout {
+			return ErrOpen
+		}
+		b.setState(StateHalfOpen)
+		b.probing = true
+		return nil
+	case StateHalfOpen:
+		if b.probing {
+			return ErrOpen
+		}
+		b.probing = true
+		return nil
+	}
+	return nil
+}
+
+// Record reports the outcome of an allowed call; a nil failure counts as a success
+func (b *Breaker) Record(failure error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+	if failure == nil {
+		b.failures = 0
+		b.setState(StateClosed)
+		return
+	}
+
+	b.failures++
+	if b.state == StateHalfOpen || (b.cfg.FailureThreshold > 0 && b.failures >= b.cfg.FailureThreshold) {
+		b.openedAt = time.Now()
+		b.setState(StateOpen)
+	}
+}
+
+// Abandon reports that an allowed call ended without telling anything about the service,
+// like a call cancelled by its caller; a probe may then be sent by the next call
+func (b *Breaker) Abandon() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+}
+
+// State returns the current state of the circuit
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+func (b *Breaker) setState(to State) {
+	if b.state == to {
+		return
+	}
+	from := b.state
+	b.state = to
+	if b.onChange != nil {
+		b.onChange(from, to)
+	}
+}
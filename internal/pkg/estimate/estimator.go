@@ -0,0 +1,55 @@
+package estimate
+
+import (
+	"math"
+	"time"
+
+	"github.com/futig/agent-backend/internal/entity"
+)
+
+// Config holds generation estimate settings
+type Config struct {
+	CharsPerToken                float64       `env:"CHARS_PER_TOKEN" envDefault:"3"`
+	BaseDuration                 time.Duration `env:"BASE_DURATION" envDefault:"15s"`
+	DurationPerThousandTokens    time.Duration `env:"DURATION_PER_THOUSAND_TOKENS" envDefault:"2s"`
+	TokenAccountingEnabled       bool          `env:"TOKEN_ACCOUNTING_ENABLED" envDefault:"false"`
+	CostPerThousandTokens        float64       `env:"COST_PER_THOUSAND_TOKENS" envDefault:"0.002"`
+	Currency                     string        `env:"CURRENCY" envDefault:"USD"`
+	ConfirmThresholdTokens       int           `env:"CONFIRM_THRESHOLD_TOKENS" envDefault:"30000"`
+	AdminApprovalThresholdTokens int           `env:"ADMIN_APPROVAL_THRESHOLD_TOKENS" envDefault:"0"` // 0 disables admin approval
+	SectionedThresholdTokens     int           `env:"SECTIONED_THRESHOLD_TOKENS" envDefault:"20000"`  // 0 disables sectioned generation
+}
+
+// Estimator converts collected material size into time and cost estimates
+type Estimator struct {
+	cfg Config
+}
+
+// NewEstimator creates a new generation estimator
+func NewEstimator(cfg Config) *Estimator {
+	return &Estimator{cfg: cfg}
+}
+
+// Estimate calculates the generation estimate for materialChars characters of input
+func (e *Estimator) Estimate(materialChars int) *entity.GenerationEstimate {
+	tokens := int(math.Ceil(float64(materialChars) / e.cfg.CharsPerToken))
+
+	duration := e.cfg.BaseDuration + time.Duration(float64(e.cfg.DurationPerThousandTokens)*float64(tokens)/1000)
+
+	estimate := &entity.GenerationEstimate{
+		MaterialChars:         materialChars,
+		EstimatedTokens:       tokens,
+		EstimatedSeconds:      int(math.Ceil(duration.Seconds())),
+		RequiresConfirmation:  e.cfg.ConfirmThresholdTokens > 0 && tokens >= e.cfg.ConfirmThresholdTokens,
+		RequiresAdminApproval: e.cfg.AdminApprovalThresholdTokens > 0 && tokens >= e.cfg.AdminApprovalThresholdTokens,
+		Sectioned:             e.cfg.SectionedThresholdTokens > 0 && tokens >= e.cfg.SectionedThresholdTokens,
+	}
+
+	if e.cfg.TokenAccountingEnabled {
+		cost := math.Round(float64(tokens)/1000*e.cfg.CostPerThousandTokens*10000) / 10000
+		estimate.EstimatedCost = &cost
+		estimate.Currency = e.cfg.Currency
+	}
+
+	return estimate
+}
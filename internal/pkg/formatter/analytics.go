@@ -0,0 +1,99 @@
+package formatter
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/parquet-go/parquet-go"
+)
+
+// analyticsRecord is the schema of the analytics export; the parquet tags name the Parquet and CSV columns
+type analyticsRecord struct {
+	SessionHash     string `parquet:"session_hash"`
+	UserHash        string `parquet:"user_hash"`
+	SessionType     string `parquet:"session_type"`
+	SessionStatus   string `parquet:"session_status"`
+	HasProject      bool   `parquet:"has_project"`
+	SessionWeek     string `parquet:"session_week"`
+	IterationNumber int64  `parquet:"iteration_number"`
+	BlockCategory   string `parquet:"block_category"`
+	QuestionNumber  int64  `parquet:"question_number"`
+	QuestionLength  string `parquet:"question_length"`
+	Status          string `parquet:"status"`
+	AnswerType      string `parquet:"answer_type"`
+	SkipReason      string `parquet:"skip_reason"`
+	FollowUp        bool   `parquet:"follow_up"`
+	AnswerLength    string `parquet:"answer_length"`
+	AnswerWords     string `parquet:"answer_words"`
+	Normalized      bool   `parquet:"normalized"`
+	ResponseTime    string `parquet:"response_time"`
+}
+
+// AnalyticsContentType returns the content type of an analytics export
+func AnalyticsContentType(format entity.AnalyticsExportFormat) string {
+	if format == entity.AnalyticsExportParquet {
+		return "application/vnd.apache.parquet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// AnalyticsFileName names an analytics export of the period
+func AnalyticsFileName(from, to string, format entity.AnalyticsExportFormat) string {
+	return fmt.Sprintf("analytics-answers_%s_%s.%s", from, to, format)
+}
+
+// BuildAnalyticsExport writes the anonymized answers as CSV with a header row or as a zstd-compressed Parquet file
+func BuildAnalyticsExport(answers []*entity.AnalyticsAnswer, format entity.AnalyticsExportFormat) ([]byte, error) {
+	records := make([]analyticsRecord, 0, len(answers))
+	for _, a := range answers {
+		records = append(records, analyticsRecord(*a))
+	}
+
+	var buf bytes.Buffer
+	switch format {
+	case entity.AnalyticsExportParquet:
+		if err := parquet.Write(&buf, records, parquet.Compression(&parquet.Zstd)); err != nil {
+			return nil, fmt.Errorf("write parquet: %w", err)
+		}
+	case entity.AnalyticsExportCSV:
+		w := csv.NewWriter(&buf)
+		recordType := reflect.TypeFor[analyticsRecord]()
+		header := make([]string, 0, recordType.NumField())
+		for i := range recordType.NumField() {
+			header = append(header, recordType.Field(i).Tag.Get("parquet"))
+		}
+		w.Write(header)
+		for _, record := range records {
+			w.Write(csvRecord(record))
+		}
+		w.Flush()
+		if err := w.Error(); err != nil {
+			return nil, fmt.Errorf("write csv: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("%w: analytics export format %s", entity.ErrInvalidFormat, format)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// csvRecord renders the fields of the record in the order of the header
+func csvRecord(record analyticsRecord) []string {
+	v := reflect.ValueOf(record)
+	values := make([]string, 0, v.NumField())
+	for i := range v.NumField() {
+		switch field := v.Field(i); field.Kind() {
+		case reflect.Bool:
+			values = append(values, strconv.FormatBool(field.Bool()))
+		case reflect.Int64:
+			values = append(values, strconv.FormatInt(field.Int(), 10))
+		default:
+			values = append(values, field.String())
+		}
+	}
+	return values
+}
@@ -0,0 +1,108 @@
+package formatter
+
+import (
+	"bytes"
+	"encoding/csv"
+	"reflect"
+	"testing"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/parquet-go/parquet-go"
+)
+
+func testAnalyticsAnswers() []*entity.AnalyticsAnswer {
+	return []*entity.AnalyticsAnswer{
+		{
+			SessionHash:     "5f2b",
+			UserHash:        "a91c",
+			SessionType:     "interview",
+			SessionStatus:   "DONE",
+			HasProject:      true,
+			SessionWeek:     "2026-W42",
+			IterationNumber: 1,
+			BlockCategory:   "generated",
+			QuestionNumber:  3,
+			QuestionLength:  "50-100",
+			Status:          "answered",
+			AnswerType:      "text",
+			FollowUp:        true,
+			AnswerLength:    "100-500",
+			AnswerWords:     "20-50",
+			Normalized:      true,
+			ResponseTime:    "1-5m",
+		},
+		{
+			SessionHash:     "5f2b",
+			SessionType:     "draft",
+			SessionStatus:   "WAITING_FOR_ANSWERS",
+			SessionWeek:     "2026-W42",
+			IterationNumber: 2,
+			BlockCategory:   "additional",
+			QuestionNumber:  1,
+			QuestionLength:  "<50",
+			Status:          "skipped",
+			SkipReason:      "not_applicable",
+		},
+	}
+}
+
+func TestBuildAnalyticsExportParquetRoundTrip(t *testing.T) {
+	answers := testAnalyticsAnswers()
+
+	data, err := BuildAnalyticsExport(answers, entity.AnalyticsExportParquet)
+	if err != nil {
+		t.Fatalf("BuildAnalyticsExport() error = %v", err)
+	}
+
+	records, err := parquet.Read[analyticsRecord](bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("read parquet: %v", err)
+	}
+	if len(records) != len(answers) {
+		t.Fatalf("read %d rows, want %d", len(records), len(answers))
+	}
+	for i, record := range records {
+		if got := entity.AnalyticsAnswer(record); !reflect.DeepEqual(&got, answers[i]) {
+			t.Errorf("row %d = %+v, want %+v", i, got, *answers[i])
+		}
+	}
+
+	file, err := parquet.OpenFile(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("open parquet: %v", err)
+	}
+	columns := file.Schema().Columns()
+	if len(columns) != 18 || columns[0][0] != "session_hash" || columns[17][0] != "response_time" {
+		t.Errorf("schema columns = %v", columns)
+	}
+}
+
+func TestBuildAnalyticsExportCSV(t *testing.T) {
+	data, err := BuildAnalyticsExport(testAnalyticsAnswers(), entity.AnalyticsExportCSV)
+	if err != nil {
+		t.Fatalf("BuildAnalyticsExport() error = %v", err)
+	}
+
+	rows, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		t.Fatalf("read csv: %v", err)
+	}
+	want := [][]string{
+		{"session_hash", "user_hash", "session_type", "session_status", "has_project", "session_week",
+			"iteration_number", "block_category", "question_number", "question_length", "status", "answer_type",
+			"skip_reason", "follow_up", "answer_length", "answer_words", "normalized", "response_time"},
+		{"5f2b", "a91c", "interview", "DONE", "true", "2026-W42", "1", "generated", "3", "50-100", "answered",
+			"text", "", "true", "100-500", "20-50", "true", "1-5m"},
+		{"5f2b", "", "draft", "WAITING_FOR_ANSWERS", "false", "2026-W42", "2", "additional", "1", "<50", "skipped",
+			"", "not_applicable", "false", "", "", "false", ""},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("csv rows =\n%v\nwant\n%v", rows, want)
+	}
+}
+
+func TestBuildAnalyticsExportUnknownFormat(t *testing.T) {
+	if _, err := BuildAnalyticsExport(nil, "xlsx"); err == nil {
+		t.Error("BuildAnalyticsExport() with an unknown format returned no error")
+	}
+}
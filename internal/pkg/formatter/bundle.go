@@ -0,0 +1,126 @@
+package formatter
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/futig/agent-backend/internal/entity"
+)
+
+const (
+	// BundleContentType is the content type of the artifacts archive
+	BundleContentType   = "application/zip"
+	bundleFileExtension = ".zip"
+)
+
+// BundleFileName names the artifacts archive of a session
+func BundleFileName(info *entity.ResultFileInfo) string {
+	return FileName(info, "bundle", bundleFileExtension)
+}
+
+// BuildBundle packs the result in md/pdf/docx, the Q&A spreadsheet,
+// the transcript and the structured JSON into a ZIP archive.
+// Artifacts that fail to render are left out and returned as skipped so one broken format
+// does not block the rest of the download.
+func BuildBundle(bundle *entity.SessionBundle) ([]byte, []string, error) {
+	result := ""
+	if bundle.Session.Result != nil {
+		result = *bundle.Session.Result
+	}
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	var skipped []string
+
+	add := func(name string, render func() ([]byte, error)) error {
+		data, err := render()
+		if err != nil {
+			skipped = append(skipped, fmt.Sprintf("%s: %v", name, err))
+			return nil
+		}
+		return addBundleFile(archive, name, data)
+	}
+
+	factory := NewFactory()
+	for _, format := range []entity.ResultFormat{entity.FormatMarkdown, entity.FormatPDF, entity.FormatDOCX} {
+		fmtr, err := factory.Create(format, "", bundle.FileInfo.Date, bundle.FileInfo.Theme)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := add(FileName(bundle.FileInfo, "", fmtr.FileExtension()), func() ([]byte, error) {
+			return fmtr.Format(result)
+		}); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if err := add(FileName(bundle.FileInfo, "qa", qaSpreadsheetExtension), func() ([]byte, error) {
+		return FormatQASpreadsheet(bundle.Iterations)
+	}); err != nil {
+		return nil, nil, err
+	}
+
+	if err := add(FileName(bundle.FileInfo, "transcript", ".txt"), func() ([]byte, error) {
+		return formatTranscript(bundle), nil
+	}); err != nil {
+		return nil, nil, err
+	}
+
+	if err := add(FileName(bundle.FileInfo, "", ".json"), func() ([]byte, error) {
+		return json.MarshalIndent(bundle, "", "  ")
+	}); err != nil {
+		return nil, nil, err
+	}
+
+	if err := archive.Close(); err != nil {
+		return nil, nil, fmt.Errorf("close archive: %w", err)
+	}
+	return buf.Bytes(), skipped, nil
+}
+
+func addBundleFile(archive *zip.Writer, name string, data []byte) error {
+	w, err := archive.Create(name)
+	if err != nil {
+		return fmt.Errorf("create %s: %w", name, err)
+	}
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("write %s: %w", name, err)
+	}
+	return nil
+}
+
+// formatTranscript renders the collected material in the order it was given:
+// the goal, draft messages and every question with its answer
+func formatTranscript(bundle *entity.SessionBundle) []byte {
+	var b strings.Builder
+
+	if bundle.Session.UserGoal != nil && *bundle.Session.UserGoal != "" {
+		fmt.Fprintf(&b, "Цель: %s\n\n", *bundle.Session.UserGoal)
+	}
+
+	for _, message := range bundle.DraftMessages {
+		fmt.Fprintf(&b, "[%s]\n%s\n\n", message.CreatedAt.Format("2006-01-02 15:04"), message.MessageText)
+	}
+
+	for _, iteration := range bundle.Iterations {
+		fmt.Fprintf(&b, "=== Итерация %d: %s ===\n\n", iteration.IterationNumber, iteration.Title)
+		for _, question := range iteration.Questions {
+			fmt.Fprintf(&b, "Вопрос %d: %s\n", question.QuestionNumber, question.Question)
+			switch {
+			case question.Answer != nil && *question.Answer != "":
+				fmt.Fprintf(&b, "Ответ: %s\n\n", *question.Answer)
+			case question.Status == entity.AnswerStatusSkiped:
+				b.WriteString("Ответ: (пропущен)\n\n")
+			case question.Status == entity.AnswerStatusDeferred:
+				b.WriteString("Ответ: (отложен)\n\n")
+			default:
+				b.WriteString("Ответ: (нет ответа)\n\n")
+			}
+		}
+	}
+
+	return []byte(b.String())
+}
@@ -0,0 +1,155 @@
+package formatter
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/futig/agent-backend/internal/entity"
+)
+
+// DocumentOptions configures how a template renders the result text
+type DocumentOptions struct {
+	Locale *Locale
+	// Title replaces the localized document title when set
+	Title string
+	// Date is shown under the title when set
+	Date time.Time
+	// NumberSections prefixes headings below the document title with nested numbers
+	NumberSections bool
+	// LocalizeQuotes replaces paired straight double quotes with the locale quotes
+	LocalizeQuotes bool
+	// Theme brands PDF and DOCX documents; markdown ignores it
+	Theme *entity.DocumentTheme
+}
+
+// templateDefaults are the per-template rendering settings; markdown keeps straight quotes
+// so the file stays convenient to edit and re-upload as project material
+var templateDefaults = map[entity.ResultFormat]DocumentOptions{
+	entity.FormatMarkdown: {NumberSections: true},
+	entity.FormatDOCX:     {NumberSections: true, LocalizeQuotes: true},
+	entity.FormatPDF:      {NumberSections: true, LocalizeQuotes: true},
+}
+
+var (
+	headingPattern = regexp.MustCompile(`^(#{1,6})\s+(.*)$`)
+	// sectionNumberPattern matches existing heading numbers like "1.", "2)", "2.1" or "2.1.",
+	// a bare number such as a year in "2025 Goals" belongs to the heading text
+	sectionNumberPattern = regexp.MustCompile(`^(\d+(\.\d+)+[.)]?|\d+[.)])\s+`)
+)
+
+// TemplateOptions returns the settings of a format template for the document language, date and theme
+func TemplateOptions(format entity.ResultFormat, language entity.ResultLanguage, date time.Time, theme *entity.DocumentTheme) DocumentOptions {
+	opts := templateDefaults[format]
+	opts.Locale = LocaleFor(language)
+	opts.Date = date
+	opts.Theme = theme
+	return opts
+}
+
+// title returns the localized document title
+func (o DocumentOptions) title() string {
+	if o.Title != "" {
+		return o.Title
+	}
+	return o.locale().Title
+}
+
+// dateLine returns the localized date line or an empty string when no date is set
+func (o DocumentOptions) dateLine() string {
+	if o.Date.IsZero() {
+		return ""
+	}
+	l := o.locale()
+	return l.DateLabel + ": " + l.FormatDate(o.Date)
+}
+
+func (o DocumentOptions) locale() *Locale {
+	if o.Locale == nil {
+		return LocaleFor("")
+	}
+	return o.Locale
+}
+
+// localize applies section numbering and quotation style to markdown text, leaving code blocks intact
+func (o DocumentOptions) localize(text string) string {
+	if !o.NumberSections && !o.LocalizeQuotes {
+		return text
+	}
+
+	l := o.locale()
+	lines := strings.Split(text, "\n")
+	var counters []int
+	inCode := false
+
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inCode = !inCode
+			continue
+		}
+		if inCode {
+			continue
+		}
+
+		if o.LocalizeQuotes {
+			line = localizeQuotes(line, l)
+		}
+
+		// The first level is the document title and is not numbered
+		if m := headingPattern.FindStringSubmatch(line); o.NumberSections && m != nil && len(m[1]) > 1 {
+			level := len(m[1]) - 1
+			for len(counters) < level {
+				counters = append(counters, 0)
+			}
+			counters = counters[:level]
+			counters[level-1]++
+			for j := range counters[:level-1] {
+				counters[j] = max(counters[j], 1)
+			}
+			heading := sectionNumberPattern.ReplaceAllString(m[2], "")
+			line = m[1] + " " + l.FormatSectionNumber(counters) + " " + heading
+		}
+
+		lines[i] = line
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// localizeQuotes replaces straight double quotes outside inline code when they are paired
+func localizeQuotes(line string, l *Locale) string {
+	quotes := 0
+	inCode := false
+	for _, r := range line {
+		switch {
+		case r == '`':
+			inCode = !inCode
+		case r == '"' && !inCode:
+			quotes++
+		}
+	}
+	if quotes == 0 || quotes%2 != 0 {
+		return line
+	}
+
+	var b strings.Builder
+	open := true
+	inCode = false
+	for _, r := range line {
+		switch {
+		case r == '`':
+			inCode = !inCode
+			b.WriteRune(r)
+		case r == '"' && !inCode:
+			if open {
+				b.WriteString(l.QuoteOpen)
+			} else {
+				b.WriteString(l.QuoteClose)
+			}
+			open = !open
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,70 @@
+package formatter
+
+import (
+	"testing"
+
+	"github.com/futig/agent-backend/internal/entity"
+)
+
+func TestDocumentOptionsLocalize(t *testing.T) {
+	tests := []struct {
+		name     string
+		language entity.ResultLanguage
+		opts     DocumentOptions
+		text     string
+		want     string
+	}{
+		{
+			name:     "ru numbering and quotes",
+			language: entity.LanguageRussian,
+			opts:     DocumentOptions{NumberSections: true, LocalizeQuotes: true},
+			text:     "# Требования\n## Цели\nПроект \"Альфа\"\n### Объем\n## Риски",
+			want:     "# Требования\n## 1. Цели\nПроект «Альфа»\n### 1.1. Объем\n## 2. Риски",
+		},
+		{
+			name:     "en numbering and quotes",
+			language: entity.LanguageEnglish,
+			opts:     DocumentOptions{NumberSections: true, LocalizeQuotes: true},
+			text:     "# Requirements\n## Goals\nProject \"Alpha\"\n### Scope\n## Risks",
+			want:     "# Requirements\n## 1 Goals\nProject “Alpha”\n### 1.1 Scope\n## 2 Risks",
+		},
+		{
+			name:     "existing numbers are replaced",
+			language: entity.LanguageEnglish,
+			opts:     DocumentOptions{NumberSections: true},
+			text:     "## 1. Intro\n## 2) Goals\n### 2.1 Scope\n### 2.2. Limits",
+			want:     "## 1 Intro\n## 2 Goals\n### 2.1 Scope\n### 2.2 Limits",
+		},
+		{
+			name:     "bare numbers stay in the heading",
+			language: entity.LanguageEnglish,
+			opts:     DocumentOptions{NumberSections: true},
+			text:     "## 2025 Goals\n## 3 Months Plan",
+			want:     "## 1 2025 Goals\n## 2 3 Months Plan",
+		},
+		{
+			name:     "code blocks are left intact",
+			language: entity.LanguageRussian,
+			opts:     DocumentOptions{NumberSections: true, LocalizeQuotes: true},
+			text:     "```\n## \"raw\"\n```\n## Итог",
+			want:     "```\n## \"raw\"\n```\n## 1. Итог",
+		},
+		{
+			name:     "unpaired quotes are kept",
+			language: entity.LanguageRussian,
+			opts:     DocumentOptions{LocalizeQuotes: true},
+			text:     `Размер 5" и "8"`,
+			want:     `Размер 5" и "8"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := tt.opts
+			opts.Locale = LocaleFor(tt.language)
+			if got := opts.localize(tt.text); got != tt.want {
+				t.Errorf("localize() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
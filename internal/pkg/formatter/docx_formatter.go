@@ -1,44 +1,190 @@
 package formatter
 
 import (
+	"archive/zip"
 	"bytes"
-
-	"github.com/unidoc/unioffice/document"
+	"fmt"
+	"image"
+	_ "image/jpeg" // decode the size of JPEG logos
+	_ "image/png"  // decode the size of PNG logos
+	"strings"
 )
 
 const (
 	docxContentType   = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
 	docxFileExtension = ".docx"
+
+	// docxTextWidth is the width of the A4 text area between the 2 cm margins, in twips
+	docxTextWidth = 9638
+	// docxEMUPerMM converts millimeters to the English Metric Units of drawings
+	docxEMUPerMM = 36000
 )
 
-type DOCXFormatter struct{}
+// DOCXFormatter renders the markdown result as a WordprocessingML document: headings, bullet and
+// numbered lists, pipe tables, code blocks and quotes become native Word elements
+type DOCXFormatter struct {
+	opts DocumentOptions
+}
 
-func NewDOCXFormatter() *DOCXFormatter {
-	return &DOCXFormatter{}
+func NewDOCXFormatter(opts DocumentOptions) *DOCXFormatter {
+	return &DOCXFormatter{opts: opts}
 }
 
 func (mf *DOCXFormatter) Format(text string) ([]byte, error) {
-	doc := document.New()
-	defer doc.Close()
+	doc := &docxDocument{}
+
+	doc.paragraph("Title", "", []markdownInline{{text: mf.opts.title()}})
+	if date := mf.opts.dateLine(); date != "" {
+		doc.paragraph("Subtitle", "", []markdownInline{{text: date}})
+	}
+
+	for _, block := range parseMarkdownBlocks(mf.opts.localize(text)) {
+		doc.block(block)
+	}
+
+	return mf.pack(doc)
+}
+
+// pack writes the parts of the document package; the header and footer parts exist only for themed documents
+func (mf *DOCXFormatter) pack(doc *docxDocument) ([]byte, error) {
+	theme := mf.opts.Theme
 
-	titlePar := doc.AddParagraph()
-	titlePar.SetStyle("Heading1")
-	titleRun := titlePar.AddRun()
-	titleRun.AddText(baseTitle)
+	var logo *docxImage
+	if asset := themeLogo(theme); asset != nil {
+		cfg, format, err := image.DecodeConfig(bytes.NewReader(asset.Data))
+		if err != nil {
+			return nil, fmt.Errorf("decode theme logo: %w", err)
+		}
+		logo = &docxImage{data: asset.Data, ext: format, width: cfg.Width, height: cfg.Height}
+		if format == "jpeg" {
+			logo.ext = "jpg"
+		}
+	}
 
-	doc.AddParagraph()
+	var sectionRefs string
+	docRels := []docxRelationship{
+		{id: "rIdStyles", typ: docxRelStyles, target: "styles.xml"},
+		{id: "rIdNumbering", typ: docxRelNumbering, target: "numbering.xml"},
+	}
+	docRels = append(docRels, doc.links...)
 
-	bodyPar := doc.AddParagraph()
-	bodyRun := bodyPar.AddRun()
-	bodyRun.AddText(text)
+	parts := map[string]string{
+		"word/styles.xml":    mf.stylesXML(),
+		"word/numbering.xml": docxNumberingXML(doc.lists),
+		"_rels/.rels": docxRelationshipsXML([]docxRelationship{
+			{id: "rIdDocument", typ: docxRelDocument, target: "word/document.xml"},
+		}),
+	}
+	if theme != nil {
+		if themeHeader(theme) {
+			parts["word/header1.xml"] = mf.headerXML(logo)
+			docRels = append(docRels, docxRelationship{id: "rIdHeader", typ: docxRelHeader, target: "header1.xml"})
+			sectionRefs += `<w:headerReference w:type="default" r:id="rIdHeader"/>`
+		}
+		if logo != nil {
+			parts["word/_rels/header1.xml.rels"] = docxRelationshipsXML([]docxRelationship{
+				{id: "rIdLogo", typ: docxRelImage, target: "media/logo." + logo.ext},
+			})
+		}
+		parts["word/footer1.xml"] = mf.footerXML()
+		docRels = append(docRels, docxRelationship{id: "rIdFooter", typ: docxRelFooter, target: "footer1.xml"})
+		sectionRefs += `<w:footerReference w:type="default" r:id="rIdFooter"/>`
+	}
+	parts["word/_rels/document.xml.rels"] = docxRelationshipsXML(docRels)
+	parts["word/document.xml"] = docxDocumentXML(doc.body.String(), sectionRefs)
+	parts["[Content_Types].xml"] = docxContentTypesXML(parts, logo)
 
 	var buf bytes.Buffer
-	if err := doc.Save(&buf); err != nil {
+	zw := zip.NewWriter(&buf)
+	// The content types part goes first, as some readers expect
+	names := append([]string{"[Content_Types].xml"}, sortedPartNames(parts)...)
+	for _, name := range names {
+		w, err := zw.Create(name)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := w.Write([]byte(parts[name])); err != nil {
+			return nil, err
+		}
+	}
+	if logo != nil {
+		w, err := zw.Create("word/media/logo." + logo.ext)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := w.Write(logo.data); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
 		return nil, err
 	}
+
 	return buf.Bytes(), nil
 }
 
+// headerXML draws the theme logo and header text on top of every page
+func (mf *DOCXFormatter) headerXML(logo *docxImage) string {
+	theme := mf.opts.Theme
+
+	var b strings.Builder
+	b.WriteString(`<w:p>`)
+	if logo != nil {
+		height := themeLogoHeightMM * docxEMUPerMM
+		width := height
+		if logo.height > 0 {
+			width = height * logo.width / logo.height
+		}
+		fmt.Fprintf(&b, docxInlineImageXML, width, height)
+	}
+	if theme.HeaderText != "" {
+		b.WriteString(`<w:r><w:rPr><w:color w:val="808080"/></w:rPr>`)
+		if logo != nil {
+			b.WriteString(`<w:tab/>`)
+		}
+		writeDOCXText(&b, theme.HeaderText)
+		b.WriteString(`</w:r>`)
+	}
+	b.WriteString(`</w:p>`)
+
+	return docxPartXML("w:hdr", b.String())
+}
+
+// footerXML writes the theme footer text and the page number at the bottom of every page
+func (mf *DOCXFormatter) footerXML() string {
+	var b strings.Builder
+	b.WriteString(`<w:p>`)
+	if text := mf.opts.Theme.FooterText; text != "" {
+		b.WriteString(`<w:r><w:rPr><w:color w:val="808080"/></w:rPr>`)
+		writeDOCXText(&b, text)
+		b.WriteString(`<w:tab/></w:r>`)
+	}
+	b.WriteString(`<w:r><w:rPr><w:color w:val="808080"/></w:rPr><w:fldChar w:fldCharType="begin"/></w:r>`)
+	b.WriteString(`<w:r><w:rPr><w:color w:val="808080"/></w:rPr><w:instrText xml:space="preserve"> PAGE </w:instrText></w:r>`)
+	b.WriteString(`<w:r><w:rPr><w:color w:val="808080"/></w:rPr><w:fldChar w:fldCharType="separate"/></w:r>`)
+	b.WriteString(`<w:r><w:rPr><w:color w:val="808080"/></w:rPr><w:t>1</w:t></w:r>`)
+	b.WriteString(`<w:r><w:rPr><w:color w:val="808080"/></w:rPr><w:fldChar w:fldCharType="end"/></w:r>`)
+	b.WriteString(`</w:p>`)
+
+	return docxPartXML("w:ftr", b.String())
+}
+
+// stylesXML defines the paragraph styles of the document in the theme font and title color
+func (mf *DOCXFormatter) stylesXML() string {
+	font := "Calibri"
+	headingColor := "1F3864"
+	if theme := mf.opts.Theme; theme != nil {
+		if theme.FontName != "" {
+			font = theme.FontName
+		}
+		if r, g, b, ok := primaryRGB(theme); ok {
+			headingColor = fmt.Sprintf("%02X%02X%02X", r, g, b)
+		}
+	}
+
+	return fmt.Sprintf(docxStylesXML, escapeDOCX(font), headingColor)
+}
+
 func (mf *DOCXFormatter) ContentType() string {
 	return docxContentType
 }
@@ -0,0 +1,166 @@
+package formatter
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/futig/agent-backend/internal/entity"
+)
+
+func TestDOCXFormatterDocumentXML(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     DocumentOptions
+		markdown string
+		contains []string
+		excludes []string
+	}{
+		{
+			name:     "headings",
+			markdown: "# Requirements\n## Goals\n### Scope\n##### Details",
+			contains: []string{
+				`<w:pStyle w:val="Title"/></w:pPr><w:r><w:t xml:space="preserve">Бизнес требования</w:t>`,
+				`<w:pStyle w:val="Heading1"/></w:pPr><w:r><w:t xml:space="preserve">Requirements</w:t>`,
+				`<w:pStyle w:val="Heading2"/></w:pPr><w:r><w:t xml:space="preserve">Goals</w:t>`,
+				`<w:pStyle w:val="Heading3"/></w:pPr><w:r><w:t xml:space="preserve">Scope</w:t>`,
+				`<w:pStyle w:val="Heading4"/></w:pPr><w:r><w:t xml:space="preserve">Details</w:t>`,
+			},
+		},
+		{
+			name:     "numbered headings",
+			opts:     TemplateOptions(entity.FormatDOCX, entity.LanguageEnglish, time.Time{}, nil),
+			markdown: "# Requirements\n## 1. Goals\n### Scope",
+			contains: []string{
+				`<w:t xml:space="preserve">Business Requirements</w:t>`,
+				`<w:t xml:space="preserve">1 Goals</w:t>`,
+				`<w:t xml:space="preserve">1.1 Scope</w:t>`,
+			},
+			excludes: []string{"1. Goals"},
+		},
+		{
+			name:     "bullet list",
+			markdown: "- first\n  - nested\n- second",
+			contains: []string{
+				`<w:pStyle w:val="ListParagraph"/><w:numPr><w:ilvl w:val="0"/><w:numId w:val="1"/></w:numPr></w:pPr><w:r><w:t xml:space="preserve">first</w:t>`,
+				`<w:numPr><w:ilvl w:val="1"/><w:numId w:val="1"/></w:numPr></w:pPr><w:r><w:t xml:space="preserve">nested</w:t>`,
+				`<w:numPr><w:ilvl w:val="0"/><w:numId w:val="1"/></w:numPr></w:pPr><w:r><w:t xml:space="preserve">second</w:t>`,
+			},
+		},
+		{
+			name:     "ordered lists",
+			markdown: "1. one\n2. two\n\nText\n\n1. again",
+			contains: []string{
+				`<w:numPr><w:ilvl w:val="0"/><w:numId w:val="2"/></w:numPr></w:pPr><w:r><w:t xml:space="preserve">one</w:t>`,
+				`<w:numPr><w:ilvl w:val="0"/><w:numId w:val="2"/></w:numPr></w:pPr><w:r><w:t xml:space="preserve">two</w:t>`,
+				`<w:numPr><w:ilvl w:val="0"/><w:numId w:val="3"/></w:numPr></w:pPr><w:r><w:t xml:space="preserve">again</w:t>`,
+			},
+		},
+		{
+			name:     "table",
+			markdown: "| Name | Value |\n|------|-------|\n| a | **b** |\n| c |",
+			contains: []string{
+				`<w:tbl><w:tblPr><w:tblStyle w:val="TableGrid"/>`,
+				`<w:trPr><w:tblHeader/></w:trPr>`,
+				`<w:rPr><w:b/></w:rPr><w:t xml:space="preserve">Name</w:t>`,
+				`<w:pStyle w:val="TableText"/></w:pPr><w:r><w:t xml:space="preserve">a</w:t>`,
+				`<w:rPr><w:b/></w:rPr><w:t xml:space="preserve">b</w:t>`,
+				`<w:pStyle w:val="TableText"/></w:pPr></w:p></w:tc></w:tr></w:tbl>`,
+			},
+		},
+		{
+			name:     "inline formatting and escaping",
+			markdown: "Use `a<b` and [docs](https://example.com/?a=1&b=2) & *more*",
+			contains: []string{
+				`<w:rFonts w:ascii="Courier New" w:hAnsi="Courier New" w:cs="Courier New"/></w:rPr><w:t xml:space="preserve">a&lt;b</w:t>`,
+				`<w:hyperlink r:id="rIdLink1"><w:r><w:rPr><w:rStyle w:val="Hyperlink"/></w:rPr><w:t xml:space="preserve">docs</w:t></w:r></w:hyperlink>`,
+				`<w:t xml:space="preserve"> &amp; </w:t>`,
+				`<w:rPr><w:i/></w:rPr><w:t xml:space="preserve">more</w:t>`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := NewDOCXFormatter(tt.opts).Format(tt.markdown)
+			if err != nil {
+				t.Fatalf("Format() error = %v", err)
+			}
+
+			document := readDOCXPart(t, data, "word/document.xml")
+			checkWellFormed(t, document)
+			for _, want := range tt.contains {
+				if !strings.Contains(document, want) {
+					t.Errorf("document.xml does not contain %s\n%s", want, document)
+				}
+			}
+			for _, unwanted := range tt.excludes {
+				if strings.Contains(document, unwanted) {
+					t.Errorf("document.xml contains %s", unwanted)
+				}
+			}
+		})
+	}
+}
+
+func TestDOCXFormatterPackage(t *testing.T) {
+	data, err := NewDOCXFormatter(DocumentOptions{}).Format("1. one\n\nSee [site](https://example.com)")
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "word/_rels/document.xml.rels", "word/styles.xml", "word/numbering.xml"} {
+		checkWellFormed(t, readDOCXPart(t, data, name))
+	}
+
+	rels := readDOCXPart(t, data, "word/_rels/document.xml.rels")
+	if !strings.Contains(rels, `Id="rIdLink1"`) || !strings.Contains(rels, `Target="https://example.com"`) {
+		t.Errorf("document relationships miss the hyperlink:\n%s", rels)
+	}
+	numbering := readDOCXPart(t, data, "word/numbering.xml")
+	if !strings.Contains(numbering, `<w:num w:numId="2">`) {
+		t.Errorf("numbering.xml misses the ordered list:\n%s", numbering)
+	}
+}
+
+// readDOCXPart returns a part of a DOCX package
+func readDOCXPart(t *testing.T, data []byte, name string) string {
+	t.Helper()
+
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("open docx: %v", err)
+	}
+	f, err := zr.Open(name)
+	if err != nil {
+		t.Fatalf("open %s: %v", name, err)
+	}
+	defer f.Close()
+
+	content, err := io.ReadAll(f)
+	if err != nil {
+		t.Fatalf("read %s: %v", name, err)
+	}
+	return string(content)
+}
+
+// checkWellFormed fails the test when the part is not well-formed XML
+func checkWellFormed(t *testing.T, part string) {
+	t.Helper()
+
+	dec := xml.NewDecoder(strings.NewReader(part))
+	for {
+		_, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			return
+		}
+		if err != nil {
+			t.Fatalf("malformed xml: %v\n%s", err, part)
+		}
+	}
+}
@@ -0,0 +1,358 @@
+package formatter
+
+import (
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Relationship types of the parts of a WordprocessingML package
+const (
+	docxRelDocument  = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument"
+	docxRelStyles    = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles"
+	docxRelNumbering = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/numbering"
+	docxRelHeader    = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/header"
+	docxRelFooter    = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/footer"
+	docxRelImage     = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/image"
+	docxRelHyperlink = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/hyperlink"
+
+	docxNamespaces = `xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main" ` +
+		`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships" ` +
+		`xmlns:wp="http://schemas.openxmlformats.org/drawingml/2006/wordprocessingDrawing" ` +
+		`xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main" ` +
+		`xmlns:pic="http://schemas.openxmlformats.org/drawingml/2006/picture"`
+
+	// docxBulletNumID is the numbering of bullet lists; ordered lists follow it, one numbering each
+	docxBulletNumID = 1
+)
+
+// docxRelationship links a part of the package to another part or to an external URL
+type docxRelationship struct {
+	id       string
+	typ      string
+	target   string
+	external bool
+}
+
+// docxImage is the theme logo embedded in the page header
+type docxImage struct {
+	data   []byte
+	ext    string
+	width  int
+	height int
+}
+
+// docxDocument collects the body of a document with the hyperlinks and ordered lists it refers to
+type docxDocument struct {
+	body  strings.Builder
+	links []docxRelationship
+	lists int
+}
+
+// block writes a markdown block as Word paragraphs or a table
+func (d *docxDocument) block(block markdownBlock) {
+	switch block.kind {
+	case blockHeading:
+		d.paragraph(fmt.Sprintf("Heading%d", min(block.level, 4)), "", parseMarkdownInline(block.text, markdownInline{}))
+	case blockListItem:
+		numID := docxBulletNumID
+		if block.list > 0 {
+			numID = docxBulletNumID + block.list
+			d.lists = max(d.lists, block.list)
+		}
+		numbering := fmt.Sprintf(`<w:numPr><w:ilvl w:val="%d"/><w:numId w:val="%d"/></w:numPr>`, block.level, numID)
+		d.paragraph("ListParagraph", numbering, parseMarkdownInline(block.text, markdownInline{}))
+	case blockQuote:
+		d.paragraph("Quote", "", parseMarkdownInline(block.text, markdownInline{}))
+	case blockCode:
+		for _, line := range block.lines {
+			d.paragraph("Code", "", []markdownInline{{text: line}})
+		}
+	case blockRule:
+		d.body.WriteString(`<w:p><w:pPr><w:pBdr><w:bottom w:val="single" w:sz="6" w:space="1" w:color="auto"/></w:pBdr></w:pPr></w:p>`)
+	case blockTable:
+		d.table(block.rows)
+	default:
+		d.paragraph("", "", parseMarkdownInline(block.text, markdownInline{}))
+	}
+}
+
+// paragraph writes a paragraph in the style with extra paragraph properties that follow the style
+func (d *docxDocument) paragraph(style, properties string, runs []markdownInline) {
+	d.body.WriteString(`<w:p>`)
+	if style != "" || properties != "" {
+		d.body.WriteString(`<w:pPr>`)
+		if style != "" {
+			fmt.Fprintf(&d.body, `<w:pStyle w:val="%s"/>`, style)
+		}
+		d.body.WriteString(properties)
+		d.body.WriteString(`</w:pPr>`)
+	}
+	for _, run := range runs {
+		d.run(run)
+	}
+	d.body.WriteString(`</w:p>`)
+}
+
+// run writes a run of text with its formatting; links are wrapped in hyperlinks to their URL
+func (d *docxDocument) run(run markdownInline) {
+	if run.link != "" {
+		fmt.Fprintf(&d.body, `<w:hyperlink r:id="%s">`, d.linkID(run.link))
+	}
+
+	d.body.WriteString(`<w:r>`)
+	var props strings.Builder
+	if run.link != "" {
+		props.WriteString(`<w:rStyle w:val="Hyperlink"/>`)
+	}
+	if run.code {
+		props.WriteString(`<w:rFonts w:ascii="Courier New" w:hAnsi="Courier New" w:cs="Courier New"/>`)
+	}
+	if run.bold {
+		props.WriteString(`<w:b/>`)
+	}
+	if run.italic {
+		props.WriteString(`<w:i/>`)
+	}
+	if run.strike {
+		props.WriteString(`<w:strike/>`)
+	}
+	if props.Len() > 0 {
+		d.body.WriteString(`<w:rPr>` + props.String() + `</w:rPr>`)
+	}
+	writeDOCXText(&d.body, run.text)
+	d.body.WriteString(`</w:r>`)
+
+	if run.link != "" {
+		d.body.WriteString(`</w:hyperlink>`)
+	}
+}
+
+// linkID returns the relationship of the URL, adding it on first use
+func (d *docxDocument) linkID(url string) string {
+	for _, link := range d.links {
+		if link.target == url {
+			return link.id
+		}
+	}
+	id := fmt.Sprintf("rIdLink%d", len(d.links)+1)
+	d.links = append(d.links, docxRelationship{id: id, typ: docxRelHyperlink, target: url, external: true})
+	return id
+}
+
+// table writes a pipe table over the text width; the header row repeats on every page and rows
+// are cut or padded to the columns of the header
+func (d *docxDocument) table(rows [][]string) {
+	columns := len(rows[0])
+	width := docxTextWidth / columns
+
+	d.body.WriteString(`<w:tbl><w:tblPr><w:tblStyle w:val="TableGrid"/><w:tblW w:w="5000" w:type="pct"/></w:tblPr><w:tblGrid>`)
+	for range columns {
+		fmt.Fprintf(&d.body, `<w:gridCol w:w="%d"/>`, width)
+	}
+	d.body.WriteString(`</w:tblGrid>`)
+
+	for i, row := range rows {
+		header := i == 0
+		d.body.WriteString(`<w:tr>`)
+		if header {
+			d.body.WriteString(`<w:trPr><w:tblHeader/></w:trPr>`)
+		}
+		for c := range columns {
+			d.body.WriteString(`<w:tc><w:tcPr>`)
+			fmt.Fprintf(&d.body, `<w:tcW w:w="%d" w:type="dxa"/>`, width)
+			if header {
+				d.body.WriteString(`<w:shd w:val="clear" w:color="auto" w:fill="D9E2F3"/>`)
+			}
+			d.body.WriteString(`</w:tcPr>`)
+
+			var cell string
+			if c < len(row) {
+				cell = row[c]
+			}
+			d.paragraph("TableText", "", parseMarkdownInline(cell, markdownInline{bold: header}))
+			d.body.WriteString(`</w:tc>`)
+		}
+		d.body.WriteString(`</w:tr>`)
+	}
+	d.body.WriteString(`</w:tbl>`)
+
+	// Adjacent tables would merge into one without a paragraph between them
+	d.body.WriteString(`<w:p/>`)
+}
+
+// writeDOCXText writes text keeping its spaces; tabs become tab elements
+func writeDOCXText(b *strings.Builder, text string) {
+	for i, part := range strings.Split(text, "\t") {
+		if i > 0 {
+			b.WriteString(`<w:tab/>`)
+		}
+		if part != "" {
+			b.WriteString(`<w:t xml:space="preserve">` + escapeDOCX(part) + `</w:t>`)
+		}
+	}
+}
+
+// escapeDOCX escapes text for XML, replacing the characters XML 1.0 does not allow
+func escapeDOCX(text string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(text))
+	return b.String()
+}
+
+// docxPartXML wraps the content of a header or footer part
+func docxPartXML(root, content string) string {
+	return `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n" +
+		`<` + root + ` ` + docxNamespaces + `>` + content + `</` + root + `>`
+}
+
+// docxDocumentXML wraps the body on A4 pages with 2 cm margins and the header and footer references
+func docxDocumentXML(body, sectionRefs string) string {
+	return `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n" +
+		`<w:document ` + docxNamespaces + `><w:body>` + body +
+		`<w:sectPr>` + sectionRefs +
+		`<w:pgSz w:w="11906" w:h="16838"/>` +
+		`<w:pgMar w:top="1134" w:right="1134" w:bottom="1134" w:left="1134" w:header="567" w:footer="567" w:gutter="0"/>` +
+		`</w:sectPr></w:body></w:document>`
+}
+
+// docxRelationshipsXML writes a relationships part
+func docxRelationshipsXML(rels []docxRelationship) string {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n")
+	b.WriteString(`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+	for _, rel := range rels {
+		mode := ""
+		if rel.external {
+			mode = ` TargetMode="External"`
+		}
+		fmt.Fprintf(&b, `<Relationship Id="%s" Type="%s" Target="%s"%s/>`, rel.id, rel.typ, escapeDOCX(rel.target), mode)
+	}
+	b.WriteString(`</Relationships>`)
+	return b.String()
+}
+
+// docxContentTypesXML declares the content types of the written parts and of the logo image
+func docxContentTypesXML(parts map[string]string, logo *docxImage) string {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n")
+	b.WriteString(`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">`)
+	b.WriteString(`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>`)
+	b.WriteString(`<Default Extension="xml" ContentType="application/xml"/>`)
+	if logo != nil {
+		contentType := "image/png"
+		if logo.ext == "jpg" {
+			contentType = "image/jpeg"
+		}
+		fmt.Fprintf(&b, `<Default Extension="%s" ContentType="%s"/>`, logo.ext, contentType)
+	}
+
+	for _, override := range []struct{ name, contentType string }{
+		{"word/document.xml", "application/vnd.openxmlformats-officedocument.wordprocessingml.document.main+xml"},
+		{"word/styles.xml", "application/vnd.openxmlformats-officedocument.wordprocessingml.styles+xml"},
+		{"word/numbering.xml", "application/vnd.openxmlformats-officedocument.wordprocessingml.numbering+xml"},
+		{"word/header1.xml", "application/vnd.openxmlformats-officedocument.wordprocessingml.header+xml"},
+		{"word/footer1.xml", "application/vnd.openxmlformats-officedocument.wordprocessingml.footer+xml"},
+	} {
+		if _, ok := parts[override.name]; ok {
+			fmt.Fprintf(&b, `<Override PartName="/%s" ContentType="%s"/>`, override.name, override.contentType)
+		}
+	}
+	b.WriteString(`</Types>`)
+	return b.String()
+}
+
+// docxNumberingXML defines the bullet list and a numbering restarting from 1 for each ordered list
+func docxNumberingXML(lists int) string {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n")
+	b.WriteString(`<w:numbering ` + docxNamespaces + `>`)
+
+	bullets := []string{"•", "◦", "▪"}
+	b.WriteString(`<w:abstractNum w:abstractNumId="0"><w:multiLevelType w:val="hybridMultilevel"/>`)
+	for level := range 9 {
+		fmt.Fprintf(&b, `<w:lvl w:ilvl="%d"><w:start w:val="1"/><w:numFmt w:val="bullet"/><w:lvlText w:val="%s"/><w:lvlJc w:val="left"/>`+
+			`<w:pPr><w:ind w:left="%d" w:hanging="360"/></w:pPr></w:lvl>`, level, bullets[level%len(bullets)], 720+level*360)
+	}
+	b.WriteString(`</w:abstractNum>`)
+
+	b.WriteString(`<w:abstractNum w:abstractNumId="1"><w:multiLevelType w:val="hybridMultilevel"/>`)
+	for level := range 9 {
+		format := "decimal"
+		if level%3 == 1 {
+			format = "lowerLetter"
+		} else if level%3 == 2 {
+			format = "lowerRoman"
+		}
+		fmt.Fprintf(&b, `<w:lvl w:ilvl="%d"><w:start w:val="1"/><w:numFmt w:val="%s"/><w:lvlText w:val="%%%d."/><w:lvlJc w:val="left"/>`+
+			`<w:pPr><w:ind w:left="%d" w:hanging="360"/></w:pPr></w:lvl>`, level, format, level+1, 720+level*360)
+	}
+	b.WriteString(`</w:abstractNum>`)
+
+	fmt.Fprintf(&b, `<w:num w:numId="%d"><w:abstractNumId w:val="0"/></w:num>`, docxBulletNumID)
+	for list := 1; list <= lists; list++ {
+		fmt.Fprintf(&b, `<w:num w:numId="%d"><w:abstractNumId w:val="1"/>`+
+			`<w:lvlOverride w:ilvl="0"><w:startOverride w:val="1"/></w:lvlOverride></w:num>`, docxBulletNumID+list)
+	}
+
+	b.WriteString(`</w:numbering>`)
+	return b.String()
+}
+
+// sortedPartNames lists the parts to write after the content types part
+func sortedPartNames(parts map[string]string) []string {
+	names := make([]string, 0, len(parts))
+	for name := range parts {
+		if name != "[Content_Types].xml" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// docxInlineImageXML draws the logo of the header in its width and height in EMU
+const docxInlineImageXML = `<w:r><w:drawing><wp:inline distT="0" distB="0" distL="0" distR="0">` +
+	`<wp:extent cx="%[1]d" cy="%[2]d"/><wp:docPr id="1" name="Logo"/>` +
+	`<a:graphic><a:graphicData uri="http://schemas.openxmlformats.org/drawingml/2006/picture">` +
+	`<pic:pic><pic:nvPicPr><pic:cNvPr id="1" name="Logo"/><pic:cNvPicPr/></pic:nvPicPr>` +
+	`<pic:blipFill><a:blip r:embed="rIdLogo"/><a:stretch><a:fillRect/></a:stretch></pic:blipFill>` +
+	`<pic:spPr><a:xfrm><a:off x="0" y="0"/><a:ext cx="%[1]d" cy="%[2]d"/></a:xfrm><a:prstGeom prst="rect"><a:avLst/></a:prstGeom></pic:spPr>` +
+	`</pic:pic></a:graphicData></a:graphic></wp:inline></w:drawing></w:r>`
+
+// docxStylesXML defines the styles in the font %[1]s with headings in the color %[2]s
+const docxStylesXML = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>
+<w:styles xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main">` +
+	`<w:docDefaults><w:rPrDefault><w:rPr><w:rFonts w:ascii="%[1]s" w:hAnsi="%[1]s" w:eastAsia="%[1]s" w:cs="%[1]s"/>` +
+	`<w:sz w:val="22"/><w:szCs w:val="22"/></w:rPr></w:rPrDefault>` +
+	`<w:pPrDefault><w:pPr><w:spacing w:after="120" w:line="276" w:lineRule="auto"/></w:pPr></w:pPrDefault></w:docDefaults>` +
+	`<w:style w:type="paragraph" w:default="1" w:styleId="Normal"><w:name w:val="Normal"/><w:qFormat/></w:style>` +
+	`<w:style w:type="paragraph" w:styleId="Title"><w:name w:val="Title"/><w:basedOn w:val="Normal"/><w:next w:val="Normal"/><w:qFormat/>` +
+	`<w:pPr><w:spacing w:after="120"/></w:pPr><w:rPr><w:b/><w:color w:val="%[2]s"/><w:sz w:val="40"/><w:szCs w:val="40"/></w:rPr></w:style>` +
+	`<w:style w:type="paragraph" w:styleId="Subtitle"><w:name w:val="Subtitle"/><w:basedOn w:val="Normal"/><w:next w:val="Normal"/><w:qFormat/>` +
+	`<w:pPr><w:spacing w:after="360"/></w:pPr><w:rPr><w:color w:val="595959"/></w:rPr></w:style>` +
+	`<w:style w:type="paragraph" w:styleId="Heading1"><w:name w:val="heading 1"/><w:basedOn w:val="Normal"/><w:next w:val="Normal"/><w:qFormat/>` +
+	`<w:pPr><w:keepNext/><w:spacing w:before="360" w:after="120"/><w:outlineLvl w:val="0"/></w:pPr><w:rPr><w:b/><w:color w:val="%[2]s"/><w:sz w:val="32"/><w:szCs w:val="32"/></w:rPr></w:style>` +
+	`<w:style w:type="paragraph" w:styleId="Heading2"><w:name w:val="heading 2"/><w:basedOn w:val="Normal"/><w:next w:val="Normal"/><w:qFormat/>` +
+	`<w:pPr><w:keepNext/><w:spacing w:before="240" w:after="120"/><w:outlineLvl w:val="1"/></w:pPr><w:rPr><w:b/><w:color w:val="%[2]s"/><w:sz w:val="28"/><w:szCs w:val="28"/></w:rPr></w:style>` +
+	`<w:style w:type="paragraph" w:styleId="Heading3"><w:name w:val="heading 3"/><w:basedOn w:val="Normal"/><w:next w:val="Normal"/><w:qFormat/>` +
+	`<w:pPr><w:keepNext/><w:spacing w:before="200" w:after="80"/><w:outlineLvl w:val="2"/></w:pPr><w:rPr><w:b/><w:color w:val="%[2]s"/><w:sz w:val="24"/><w:szCs w:val="24"/></w:rPr></w:style>` +
+	`<w:style w:type="paragraph" w:styleId="Heading4"><w:name w:val="heading 4"/><w:basedOn w:val="Normal"/><w:next w:val="Normal"/><w:qFormat/>` +
+	`<w:pPr><w:keepNext/><w:spacing w:before="160" w:after="80"/><w:outlineLvl w:val="3"/></w:pPr><w:rPr><w:b/><w:i/><w:color w:val="%[2]s"/></w:rPr></w:style>` +
+	`<w:style w:type="paragraph" w:styleId="ListParagraph"><w:name w:val="List Paragraph"/><w:basedOn w:val="Normal"/><w:qFormat/>` +
+	`<w:pPr><w:spacing w:after="60"/><w:contextualSpacing/></w:pPr></w:style>` +
+	`<w:style w:type="paragraph" w:styleId="Quote"><w:name w:val="Quote"/><w:basedOn w:val="Normal"/><w:qFormat/>` +
+	`<w:pPr><w:pBdr><w:left w:val="single" w:sz="12" w:space="8" w:color="BFBFBF"/></w:pBdr><w:ind w:left="567"/></w:pPr><w:rPr><w:i/><w:color w:val="595959"/></w:rPr></w:style>` +
+	`<w:style w:type="paragraph" w:styleId="Code"><w:name w:val="Code"/><w:basedOn w:val="Normal"/>` +
+	`<w:pPr><w:shd w:val="clear" w:color="auto" w:fill="F2F2F2"/><w:spacing w:after="0" w:line="240" w:lineRule="auto"/></w:pPr>` +
+	`<w:rPr><w:rFonts w:ascii="Courier New" w:hAnsi="Courier New" w:cs="Courier New"/><w:sz w:val="20"/><w:szCs w:val="20"/></w:rPr></w:style>` +
+	`<w:style w:type="paragraph" w:styleId="TableText"><w:name w:val="Table Text"/><w:basedOn w:val="Normal"/>` +
+	`<w:pPr><w:spacing w:after="0" w:line="240" w:lineRule="auto"/></w:pPr></w:style>` +
+	`<w:style w:type="character" w:styleId="Hyperlink"><w:name w:val="Hyperlink"/><w:rPr><w:color w:val="0563C1"/><w:u w:val="single"/></w:rPr></w:style>` +
+	`<w:style w:type="table" w:styleId="TableGrid"><w:name w:val="Table Grid"/>` +
+	`<w:tblPr><w:tblBorders><w:top w:val="single" w:sz="4" w:space="0" w:color="A6A6A6"/><w:left w:val="single" w:sz="4" w:space="0" w:color="A6A6A6"/>` +
+	`<w:bottom w:val="single" w:sz="4" w:space="0" w:color="A6A6A6"/><w:right w:val="single" w:sz="4" w:space="0" w:color="A6A6A6"/>` +
+	`<w:insideH w:val="single" w:sz="4" w:space="0" w:color="A6A6A6"/><w:insideV w:val="single" w:sz="4" w:space="0" w:color="A6A6A6"/></w:tblBorders>` +
+	`<w:tblCellMar><w:top w:w="57" w:type="dxa"/><w:left w:w="108" w:type="dxa"/><w:bottom w:w="57" w:type="dxa"/><w:right w:w="108" w:type="dxa"/></w:tblCellMar></w:tblPr></w:style>` +
+	`</w:styles>`
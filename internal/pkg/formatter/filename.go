@@ -0,0 +1,118 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/futig/agent-backend/internal/entity"
+)
+
+const (
+	defaultFileTitle   = "requirements"
+	maxFileTitleLength = 60
+	fileDateLayout     = "2006-01-02"
+)
+
+// cyrillicToLatin transliterates lowercase Cyrillic letters for ASCII file names
+var cyrillicToLatin = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "yo",
+	'ж': "zh", 'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m",
+	'н': "n", 'о': "o", 'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u",
+	'ф': "f", 'х': "kh", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "",
+	'ы': "y", 'ь': "", 'э': "e", 'ю': "yu", 'я': "ya",
+}
+
+// FileName builds a document file name from the project title slug, date and version,
+// e.g. "crm-система_2025-01-31_v2_en.md"; suffix (language, "changelog") is optional
+func FileName(info *entity.ResultFileInfo, suffix, ext string) string {
+	parts := []string{
+		slugify(info.Title),
+		info.Date.Format(fileDateLayout),
+		fmt.Sprintf("v%d", max(info.Version, 1)),
+	}
+	if suffix != "" {
+		parts = append(parts, slugify(suffix))
+	}
+	return strings.Join(parts, "_") + ext
+}
+
+// ASCIIFileName transliterates a file name for clients that mishandle non-ASCII names
+func ASCIIFileName(name string) string {
+	var b strings.Builder
+	for _, r := range name {
+		switch {
+		case r < unicode.MaxASCII && r != '"' && r != '\\' && unicode.IsPrint(r):
+			b.WriteRune(r)
+		case unicode.IsUpper(r):
+			if latin, ok := cyrillicToLatin[unicode.ToLower(r)]; ok {
+				b.WriteString(strings.ToUpper(latin))
+				continue
+			}
+			b.WriteByte('_')
+		default:
+			if latin, ok := cyrillicToLatin[r]; ok {
+				b.WriteString(latin)
+				continue
+			}
+			b.WriteByte('_')
+		}
+	}
+	return b.String()
+}
+
+// ContentDisposition builds an attachment header with an ASCII fallback name
+// and the original UTF-8 name encoded per RFC 5987
+func ContentDisposition(name string) string {
+	return fmt.Sprintf("attachment; filename=\"%s\"; filename*=UTF-8''%s", ASCIIFileName(name), encodeRFC5987(name))
+}
+
+// slugify lowercases the title and joins its letters and digits with dashes
+func slugify(title string) string {
+	var b strings.Builder
+	pendingDash := false
+	length := 0
+	for _, r := range strings.ToLower(title) {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			pendingDash = length > 0
+			continue
+		}
+		if length >= maxFileTitleLength {
+			break
+		}
+		if pendingDash {
+			b.WriteByte('-')
+			length++
+			pendingDash = false
+		}
+		b.WriteRune(r)
+		length++
+	}
+
+	if b.Len() == 0 {
+		return defaultFileTitle
+	}
+	return b.String()
+}
+
+// encodeRFC5987 percent-encodes every byte outside the RFC 5987 attr-char set
+func encodeRFC5987(value string) string {
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if isAttrChar(c) {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
+
+func isAttrChar(c byte) bool {
+	switch {
+	case 'a' <= c && c <= 'z', 'A' <= c && c <= 'Z', '0' <= c && c <= '9':
+		return true
+	}
+	return strings.IndexByte("!#$&+-.^_`|~", c) >= 0
+}
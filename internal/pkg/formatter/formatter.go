@@ -2,6 +2,7 @@ package formatter
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/futig/agent-backend/internal/entity"
 )
@@ -20,14 +21,26 @@ func NewFactory() *Factory {
 	return &Factory{}
 }
 
-func (f *Factory) Create(format entity.ResultFormat) (Formatter, error) {
+// Create returns the template of the format localized for the document language and date;
+// a nil theme renders the document unbranded
+func (f *Factory) Create(
+	format entity.ResultFormat,
+	language entity.ResultLanguage,
+	date time.Time,
+	theme *entity.DocumentTheme,
+) (Formatter, error) {
+	return newFormatter(format, TemplateOptions(format, language, date, theme))
+}
+
+// newFormatter returns the template of the format rendered with opts
+func newFormatter(format entity.ResultFormat, opts DocumentOptions) (Formatter, error) {
 	switch format {
 	case entity.FormatMarkdown:
-		return NewMarkdownFormatter(), nil
+		return NewMarkdownFormatter(opts), nil
 	case entity.FormatDOCX:
-		return NewDOCXFormatter(), nil
+		return NewDOCXFormatter(opts), nil
 	case entity.FormatPDF:
-		return NewPDFFormatter(), nil
+		return NewPDFFormatter(opts), nil
 	default:
 		return nil, fmt.Errorf("unsupported format: %s", format)
 	}
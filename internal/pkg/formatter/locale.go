@@ -0,0 +1,124 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/futig/agent-backend/internal/entity"
+)
+
+// Locale holds the language-specific conventions applied to rendered documents
+type Locale struct {
+	Language entity.ResultLanguage
+	Title    string
+	// DateLabel prefixes the document date under the title
+	DateLabel string
+	// months are the genitive month names used in long dates
+	months     [12]string
+	dateFormat func(l *Locale, t time.Time) string
+	// QuoteOpen and QuoteClose replace paired straight double quotes
+	QuoteOpen  string
+	QuoteClose string
+	// SectionSuffix ends section numbers, e.g. "." for "1.2."
+	SectionSuffix string
+}
+
+var locales = map[entity.ResultLanguage]*Locale{
+	entity.LanguageRussian: {
+		Language:  entity.LanguageRussian,
+		Title:     baseTitle,
+		DateLabel: "Дата",
+		months: [12]string{"января", "февраля", "марта", "апреля", "мая", "июня",
+			"июля", "августа", "сентября", "октября", "ноября", "декабря"},
+		dateFormat: func(l *Locale, t time.Time) string {
+			return fmt.Sprintf("%d %s %d г.", t.Day(), l.months[t.Month()-1], t.Year())
+		},
+		QuoteOpen:     "«",
+		QuoteClose:    "»",
+		SectionSuffix: ".",
+	},
+	entity.LanguageEnglish: {
+		Language:  entity.LanguageEnglish,
+		Title:     "Business Requirements",
+		DateLabel: "Date",
+		months: [12]string{"January", "February", "March", "April", "May", "June",
+			"July", "August", "September", "October", "November", "December"},
+		dateFormat: func(l *Locale, t time.Time) string {
+			return fmt.Sprintf("%s %d, %d", l.months[t.Month()-1], t.Day(), t.Year())
+		},
+		QuoteOpen:  "“",
+		QuoteClose: "”",
+	},
+	entity.LanguageGerman: {
+		Language:  entity.LanguageGerman,
+		Title:     "Geschäftsanforderungen",
+		DateLabel: "Datum",
+		months: [12]string{"Januar", "Februar", "März", "April", "Mai", "Juni",
+			"Juli", "August", "September", "Oktober", "November", "Dezember"},
+		dateFormat: func(l *Locale, t time.Time) string {
+			return fmt.Sprintf("%d. %s %d", t.Day(), l.months[t.Month()-1], t.Year())
+		},
+		QuoteOpen:  "„",
+		QuoteClose: "“",
+	},
+	entity.LanguageFrench: {
+		Language:  entity.LanguageFrench,
+		Title:     "Exigences métier",
+		DateLabel: "Date",
+		months: [12]string{"janvier", "février", "mars", "avril", "mai", "juin",
+			"juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+		dateFormat: func(l *Locale, t time.Time) string {
+			return fmt.Sprintf("%d %s %d", t.Day(), l.months[t.Month()-1], t.Year())
+		},
+		QuoteOpen:     "« ",
+		QuoteClose:    " »",
+		SectionSuffix: ".",
+	},
+	entity.LanguageSpanish: {
+		Language:  entity.LanguageSpanish,
+		Title:     "Requisitos de negocio",
+		DateLabel: "Fecha",
+		months: [12]string{"enero", "febrero", "marzo", "abril", "mayo", "junio",
+			"julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+		dateFormat: func(l *Locale, t time.Time) string {
+			return fmt.Sprintf("%d de %s de %d", t.Day(), l.months[t.Month()-1], t.Year())
+		},
+		QuoteOpen:     "«",
+		QuoteClose:    "»",
+		SectionSuffix: ".",
+	},
+	entity.LanguageChinese: {
+		Language:  entity.LanguageChinese,
+		Title:     "业务需求",
+		DateLabel: "日期",
+		dateFormat: func(_ *Locale, t time.Time) string {
+			return fmt.Sprintf("%d年%d月%d日", t.Year(), t.Month(), t.Day())
+		},
+		QuoteOpen:  "“",
+		QuoteClose: "”",
+	},
+}
+
+// LocaleFor returns the locale of a document language; results are generated in Russian,
+// so an empty or unknown language falls back to it
+func LocaleFor(language entity.ResultLanguage) *Locale {
+	if l, ok := locales[language]; ok {
+		return l
+	}
+	return locales[entity.LanguageRussian]
+}
+
+// FormatDate renders a long date, e.g. "16 октября 2026 г." or "October 16, 2026"
+func (l *Locale) FormatDate(t time.Time) string {
+	return l.dateFormat(l, t)
+}
+
+// FormatSectionNumber joins nested section counters, e.g. [1 2] -> "1.2." for ru
+func (l *Locale) FormatSectionNumber(counters []int) string {
+	parts := make([]string, len(counters))
+	for i, c := range counters {
+		parts[i] = fmt.Sprint(c)
+	}
+	return strings.Join(parts, ".") + l.SectionSuffix
+}
@@ -0,0 +1,70 @@
+package formatter
+
+import (
+	"testing"
+	"time"
+
+	"github.com/futig/agent-backend/internal/entity"
+)
+
+func TestLocaleFor(t *testing.T) {
+	tests := []struct {
+		name     string
+		language entity.ResultLanguage
+		want     entity.ResultLanguage
+	}{
+		{name: "russian", language: entity.LanguageRussian, want: entity.LanguageRussian},
+		{name: "english", language: entity.LanguageEnglish, want: entity.LanguageEnglish},
+		{name: "empty falls back to russian", language: "", want: entity.LanguageRussian},
+		{name: "unknown falls back to russian", language: "xx", want: entity.LanguageRussian},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := LocaleFor(tt.language).Language; got != tt.want {
+				t.Errorf("LocaleFor(%q).Language = %q, want %q", tt.language, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLocaleFormatDate(t *testing.T) {
+	date := time.Date(2026, time.October, 16, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		language entity.ResultLanguage
+		want     string
+	}{
+		{language: entity.LanguageRussian, want: "16 октября 2026 г."},
+		{language: entity.LanguageEnglish, want: "October 16, 2026"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.language), func(t *testing.T) {
+			if got := LocaleFor(tt.language).FormatDate(date); got != tt.want {
+				t.Errorf("FormatDate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLocaleFormatSectionNumber(t *testing.T) {
+	tests := []struct {
+		language entity.ResultLanguage
+		counters []int
+		want     string
+	}{
+		{language: entity.LanguageRussian, counters: []int{1}, want: "1."},
+		{language: entity.LanguageRussian, counters: []int{1, 2}, want: "1.2."},
+		{language: entity.LanguageEnglish, counters: []int{1}, want: "1"},
+		{language: entity.LanguageEnglish, counters: []int{1, 2}, want: "1.2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.language), func(t *testing.T) {
+			if got := LocaleFor(tt.language).FormatSectionNumber(tt.counters); got != tt.want {
+				t.Errorf("FormatSectionNumber(%v) = %q, want %q", tt.counters, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,213 @@
+package formatter
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	blockListPattern           = regexp.MustCompile(`^(\s*)([-*+]|\d+[.)])\s+(.*)$`)
+	blockQuotePattern          = regexp.MustCompile(`^\s*>\s?(.*)$`)
+	blockTableSeparatorPattern = regexp.MustCompile(`^\s*\|?\s*:?-+:?\s*(\|\s*:?-+:?\s*)*\|?\s*$`)
+	inlinePattern              = regexp.MustCompile("`([^`]+)`|\\[([^\\]]+)\\]\\((https?://[^)\\s]+)\\)|\\*\\*(.+?)\\*\\*|__(.+?)__|~~(.+?)~~|\\*([^*\\s][^*]*)\\*")
+)
+
+// markdownBlockKind is the kind of a block of a markdown document
+type markdownBlockKind int
+
+const (
+	blockParagraph markdownBlockKind = iota
+	blockHeading
+	blockListItem
+	blockTable
+	blockCode
+	blockQuote
+	blockRule
+)
+
+// markdownBlock is a block of a markdown document as the document renderers lay it out
+type markdownBlock struct {
+	kind  markdownBlockKind
+	text  string     // paragraph, heading, list item and quote text with inline markup
+	level int        // heading level from 1, list item depth from 0
+	list  int        // ordered list the item belongs to from 1, 0 for bullet items
+	lines []string   // lines of a code block
+	rows  [][]string // table cells, the first row is the header
+}
+
+// markdownInline is a run of text with the same inline formatting
+type markdownInline struct {
+	text   string
+	bold   bool
+	italic bool
+	strike bool
+	code   bool
+	link   string // http(s) URL of a link
+}
+
+// parseMarkdownBlocks splits markdown, usually produced by the LLM, into blocks. Lines of a
+// paragraph are joined, items of an ordered list keep their list across blank lines so numbering
+// continues, and pipe tables need the separator line under their header
+func parseMarkdownBlocks(markdown string) []markdownBlock {
+	lines := strings.Split(strings.ReplaceAll(markdown, "\r\n", "\n"), "\n")
+
+	var blocks []markdownBlock
+	var paragraph []string
+	var code *markdownBlock
+	lists := 0
+	openList := 0 // the ordered list continues over blank lines and nested bullets until another block starts
+
+	flush := func() {
+		if len(paragraph) > 0 {
+			blocks = append(blocks, markdownBlock{kind: blockParagraph, text: strings.Join(paragraph, " ")})
+			paragraph = nil
+		}
+	}
+	add := func(block markdownBlock) {
+		flush()
+		if block.kind != blockListItem {
+			openList = 0
+		}
+		blocks = append(blocks, block)
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimRight(lines[i], " \t")
+
+		if previewFencePattern.MatchString(line) {
+			if code != nil {
+				blocks = append(blocks, *code)
+				code = nil
+				continue
+			}
+			flush()
+			openList = 0
+			code = &markdownBlock{kind: blockCode}
+			continue
+		}
+		if code != nil {
+			code.lines = append(code.lines, line)
+			continue
+		}
+
+		if strings.TrimSpace(line) == "" {
+			flush()
+			continue
+		}
+
+		if m := headingPattern.FindStringSubmatch(line); m != nil {
+			add(markdownBlock{kind: blockHeading, text: strings.TrimSpace(m[2]), level: len(m[1])})
+			continue
+		}
+		if previewRulePattern.MatchString(line) {
+			add(markdownBlock{kind: blockRule})
+			continue
+		}
+		if m := blockListPattern.FindStringSubmatch(line); m != nil {
+			item := markdownBlock{kind: blockListItem, text: m[3], level: min(listDepth(m[1]), 8)}
+			switch {
+			case !strings.ContainsAny(m[2][:1], "-*+"):
+				if openList == 0 {
+					lists++
+					openList = lists
+				}
+				item.list = openList
+			case item.level == 0:
+				openList = 0
+			}
+			add(item)
+			continue
+		}
+		if isTableRow(line) && i+1 < len(lines) && blockTableSeparatorPattern.MatchString(lines[i+1]) {
+			table := markdownBlock{kind: blockTable, rows: [][]string{tableCells(line)}}
+			i++
+			for i+1 < len(lines) && isTableRow(lines[i+1]) {
+				i++
+				table.rows = append(table.rows, tableCells(lines[i]))
+			}
+			add(table)
+			continue
+		}
+		if m := blockQuotePattern.FindStringSubmatch(line); m != nil {
+			add(markdownBlock{kind: blockQuote, text: m[1]})
+			continue
+		}
+
+		if len(paragraph) == 0 {
+			openList = 0
+		}
+		paragraph = append(paragraph, strings.TrimSpace(line))
+	}
+
+	flush()
+	if code != nil {
+		blocks = append(blocks, *code)
+	}
+
+	return blocks
+}
+
+// listDepth is the nesting depth of a list item by its indent: two spaces or a tab per level
+func listDepth(indent string) int {
+	return len(strings.ReplaceAll(indent, "\t", "  ")) / 2
+}
+
+func isTableRow(line string) bool {
+	return strings.HasPrefix(strings.TrimSpace(line), "|")
+}
+
+// tableCells splits a pipe table row into trimmed cells
+func tableCells(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	line = strings.TrimSuffix(line, "|")
+
+	cells := strings.Split(line, "|")
+	for i, cell := range cells {
+		cells[i] = strings.TrimSpace(cell)
+	}
+	return cells
+}
+
+// parseMarkdownInline splits text into runs of code spans, links, bold, italic and strikethrough
+// text; emphasis may nest, the text of code spans stays as it is
+func parseMarkdownInline(text string, base markdownInline) []markdownInline {
+	var runs []markdownInline
+	plain := func(s string) {
+		if s != "" {
+			run := base
+			run.text = s
+			runs = append(runs, run)
+		}
+	}
+
+	last := 0
+	for _, loc := range inlinePattern.FindAllStringSubmatchIndex(text, -1) {
+		plain(text[last:loc[0]])
+		last = loc[1]
+
+		nested := base
+		var inner string
+		switch {
+		case loc[2] >= 0:
+			run := base
+			run.text, run.code = text[loc[2]:loc[3]], true
+			runs = append(runs, run)
+			continue
+		case loc[4] >= 0:
+			nested.link, inner = text[loc[6]:loc[7]], text[loc[4]:loc[5]]
+		case loc[8] >= 0:
+			nested.bold, inner = true, text[loc[8]:loc[9]]
+		case loc[10] >= 0:
+			nested.bold, inner = true, text[loc[10]:loc[11]]
+		case loc[12] >= 0:
+			nested.strike, inner = true, text[loc[12]:loc[13]]
+		default:
+			nested.italic, inner = true, text[loc[14]:loc[15]]
+		}
+		runs = append(runs, parseMarkdownInline(inner, nested)...)
+	}
+	plain(text[last:])
+
+	return runs
+}
@@ -0,0 +1,120 @@
+package formatter
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseMarkdownBlocks(t *testing.T) {
+	tests := []struct {
+		name     string
+		markdown string
+		want     []markdownBlock
+	}{
+		{
+			name:     "headings and paragraphs",
+			markdown: "# Title\nfirst line\nsecond line\n\n## Section\r\ntext",
+			want: []markdownBlock{
+				{kind: blockHeading, text: "Title", level: 1},
+				{kind: blockParagraph, text: "first line second line"},
+				{kind: blockHeading, text: "Section", level: 2},
+				{kind: blockParagraph, text: "text"},
+			},
+		},
+		{
+			name:     "nested bullet list",
+			markdown: "- a\n  - b\n\t- c\n* d",
+			want: []markdownBlock{
+				{kind: blockListItem, text: "a", level: 0},
+				{kind: blockListItem, text: "b", level: 1},
+				{kind: blockListItem, text: "c", level: 1},
+				{kind: blockListItem, text: "d", level: 0},
+			},
+		},
+		{
+			name:     "ordered list continues over blank lines and nested bullets",
+			markdown: "1. one\n  - detail\n\n2) two\n\nText\n\n1. new",
+			want: []markdownBlock{
+				{kind: blockListItem, text: "one", level: 0, list: 1},
+				{kind: blockListItem, text: "detail", level: 1},
+				{kind: blockListItem, text: "two", level: 0, list: 1},
+				{kind: blockParagraph, text: "Text"},
+				{kind: blockListItem, text: "new", level: 0, list: 2},
+			},
+		},
+		{
+			name:     "table",
+			markdown: "| A | B |\n| :-- | --: |\n| 1 | 2 |\n|3|",
+			want: []markdownBlock{
+				{kind: blockTable, rows: [][]string{{"A", "B"}, {"1", "2"}, {"3"}}},
+			},
+		},
+		{
+			name:     "pipe line without separator is a paragraph",
+			markdown: "| not a table |",
+			want: []markdownBlock{
+				{kind: blockParagraph, text: "| not a table |"},
+			},
+		},
+		{
+			name:     "code, quote and rule",
+			markdown: "```go\n# not a heading\n```\n> quoted\n---",
+			want: []markdownBlock{
+				{kind: blockCode, lines: []string{"# not a heading"}},
+				{kind: blockQuote, text: "quoted"},
+				{kind: blockRule},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseMarkdownBlocks(tt.markdown); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseMarkdownBlocks() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseMarkdownInline(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []markdownInline
+	}{
+		{
+			name: "plain",
+			text: "just text",
+			want: []markdownInline{{text: "just text"}},
+		},
+		{
+			name: "emphasis",
+			text: "a **bold** and *italic* ~~gone~~",
+			want: []markdownInline{
+				{text: "a "},
+				{text: "bold", bold: true},
+				{text: " and "},
+				{text: "italic", italic: true},
+				{text: " "},
+				{text: "gone", strike: true},
+			},
+		},
+		{
+			name: "code and link",
+			text: "`**raw**` [site](https://example.com)",
+			want: []markdownInline{
+				{text: "**raw**", code: true},
+				{text: " "},
+				{text: "site", link: "https://example.com"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseMarkdownInline(tt.text, markdownInline{}); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseMarkdownInline() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -10,15 +10,21 @@ const (
 	markdownFileExtension = ".md"
 )
 
-type MarkdownFormatter struct{}
+type MarkdownFormatter struct {
+	opts DocumentOptions
+}
 
-func NewMarkdownFormatter() *MarkdownFormatter {
-	return &MarkdownFormatter{}
+func NewMarkdownFormatter(opts DocumentOptions) *MarkdownFormatter {
+	return &MarkdownFormatter{opts: opts}
 }
 
 func (mf *MarkdownFormatter) Format(text string) ([]byte, error) {
 	var buf bytes.Buffer
-	fmt.Fprintf(&buf, "# %s\n\n%s\n", baseTitle, text)
+	fmt.Fprintf(&buf, "# %s\n\n", mf.opts.title())
+	if date := mf.opts.dateLine(); date != "" {
+		fmt.Fprintf(&buf, "_%s_\n\n", date)
+	}
+	fmt.Fprintf(&buf, "%s\n", mf.opts.localize(text))
 	return buf.Bytes(), nil
 }
 
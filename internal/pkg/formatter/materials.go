@@ -0,0 +1,68 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/futig/agent-backend/internal/entity"
+)
+
+// FormatCollectedMaterials renders the material of a session as a Markdown document without the LLM:
+// the goal, the draft messages and the answered questions by block. It stands in for the requirements
+// when their generation keeps failing, so the user still leaves with what they told
+func FormatCollectedMaterials(session *entity.Session, iterations []*entity.BundleIteration, messages []*entity.SessionMessage) string {
+	var b strings.Builder
+
+	b.WriteString("# Собранные материалы\n\n")
+	b.WriteString("Сформировать требования автоматически пока не удалось. Ниже собрано всё, что было рассказано в сессии; " +
+		"требования можно сформировать заново позже.\n\n")
+
+	if session.UserGoal != nil && *session.UserGoal != "" {
+		fmt.Fprintf(&b, "## Цель\n\n%s\n\n", strings.TrimSpace(*session.UserGoal))
+	}
+
+	if len(messages) > 0 {
+		b.WriteString("## Материалы\n\n")
+		for i, message := range messages {
+			fmt.Fprintf(&b, "### Сообщение %d (%s)\n\n%s\n\n", i+1, message.CreatedAt.Format("2006-01-02 15:04"), strings.TrimSpace(message.MessageText))
+		}
+	}
+
+	var answered, open []*entity.Question
+	for _, iteration := range iterations {
+		for _, question := range iteration.Questions {
+			if question.Answer != nil && strings.TrimSpace(*question.Answer) != "" {
+				answered = append(answered, question)
+			} else {
+				open = append(open, question)
+			}
+		}
+	}
+
+	if len(answered) > 0 {
+		b.WriteString("## Ответы на вопросы\n\n")
+		for _, iteration := range iterations {
+			title := false
+			for _, question := range iteration.Questions {
+				if question.Answer == nil || strings.TrimSpace(*question.Answer) == "" {
+					continue
+				}
+				if !title {
+					fmt.Fprintf(&b, "### %s\n\n", iteration.Title)
+					title = true
+				}
+				fmt.Fprintf(&b, "**%s**\n\n%s\n\n", strings.TrimSpace(question.Question), strings.TrimSpace(*question.Answer))
+			}
+		}
+	}
+
+	if len(open) > 0 {
+		b.WriteString("## Вопросы без ответа\n\n")
+		for _, question := range open {
+			fmt.Fprintf(&b, "- %s\n", strings.TrimSpace(question.Question))
+		}
+		b.WriteString("\n")
+	}
+
+	return strings.TrimRight(b.String(), "\n") + "\n"
+}
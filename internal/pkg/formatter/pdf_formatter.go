@@ -2,6 +2,7 @@ package formatter
 
 import (
 	"bytes"
+	"fmt"
 	"os"
 
 	"github.com/jung-kurt/gofpdf"
@@ -22,12 +23,18 @@ const (
 
 	// Source-relative path (useful when running from repo root with `go run`).
 	pdfFontSourcePath = "internal/pkg/formatter/ttf/DejaVuSans.ttf"
+
+	// pdfThemeFontName is the internal name of the font uploaded with a theme
+	pdfThemeFontName = "ThemeFont"
+	pdfThemeLogoName = "theme-logo"
 )
 
-type PDFFormatter struct{}
+type PDFFormatter struct {
+	opts DocumentOptions
+}
 
-func NewPDFFormatter() *PDFFormatter {
-	return &PDFFormatter{}
+func NewPDFFormatter(opts DocumentOptions) *PDFFormatter {
+	return &PDFFormatter{opts: opts}
 }
 
 // resolveFontPath tries to find the DejaVuSans font in
@@ -48,7 +55,6 @@ func resolveFontPath() string {
 
 func (mf *PDFFormatter) Format(text string) ([]byte, error) {
 	pdf := gofpdf.New("P", "mm", "A4", "")
-	pdf.AddPage()
 
 	// Try to use UTF-8 capable DejaVuSans font, bundled with the project.
 	fontName := "Arial"
@@ -58,14 +64,29 @@ func (mf *PDFFormatter) Format(text string) ([]byte, error) {
 		pdf.AddUTF8Font(pdfFontName, "B", fontPath)
 		fontName = pdfFontName
 	}
+	if theme := mf.opts.Theme; theme != nil && theme.Font != nil && len(theme.Font.Data) > 0 {
+		pdf.AddUTF8FontFromBytes(pdfThemeFontName, "", theme.Font.Data)
+		pdf.AddUTF8FontFromBytes(pdfThemeFontName, "B", theme.Font.Data)
+		fontName = pdfThemeFontName
+	}
+	mf.applyTheme(pdf, fontName)
+	pdf.AddPage()
 
+	if r, g, b, ok := primaryRGB(mf.opts.Theme); ok {
+		pdf.SetTextColor(r, g, b)
+	}
 	pdf.SetFont(fontName, "B", 20)
-	pdf.Cell(0, 10, baseTitle)
+	pdf.Cell(0, 10, mf.opts.title())
 	pdf.Ln(12)
+	pdf.SetTextColor(0, 0, 0)
 
 	pdf.SetFont(fontName, "", 12)
 	_, lineHeight := pdf.GetFontSize()
-	pdf.MultiCell(0, lineHeight*1.5, text, "", "", false)
+	if date := mf.opts.dateLine(); date != "" {
+		pdf.Cell(0, lineHeight, date)
+		pdf.Ln(lineHeight * 2)
+	}
+	pdf.MultiCell(0, lineHeight*1.5, mf.opts.localize(text), "", "", false)
 
 	var buf bytes.Buffer
 	if err := pdf.Output(&buf); err != nil {
@@ -74,6 +95,55 @@ func (mf *PDFFormatter) Format(text string) ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
+// applyTheme draws the theme logo and header text on top of every page
+// and the footer text with the page number at the bottom
+func (mf *PDFFormatter) applyTheme(pdf *gofpdf.Fpdf, fontName string) {
+	theme := mf.opts.Theme
+	if theme == nil {
+		return
+	}
+
+	logo := ""
+	if asset := themeLogo(theme); asset != nil {
+		imageType := "PNG"
+		if asset.ContentType == "image/jpeg" {
+			imageType = "JPG"
+		}
+		pdf.RegisterImageOptionsReader(pdfThemeLogoName, gofpdf.ImageOptions{ImageType: imageType}, bytes.NewReader(asset.Data))
+		logo = pdfThemeLogoName
+	}
+
+	if themeHeader(theme) {
+		pdf.SetHeaderFunc(func() {
+			left, top, _, _ := pdf.GetMargins()
+			if logo != "" {
+				pdf.ImageOptions(logo, left, top, 0, themeLogoHeightMM, false, gofpdf.ImageOptions{}, 0, "")
+			}
+			if theme.HeaderText != "" {
+				pdf.SetFont(fontName, "", 9)
+				pdf.SetTextColor(128, 128, 128)
+				pdf.CellFormat(0, themeLogoHeightMM, theme.HeaderText, "", 0, "RM", false, 0, "")
+				pdf.SetTextColor(0, 0, 0)
+			}
+			pdf.SetY(top + themeLogoHeightMM + 4)
+		})
+	}
+
+	pdf.AliasNbPages("")
+	pdf.SetFooterFunc(func() {
+		left, _, _, _ := pdf.GetMargins()
+		pdf.SetY(-15)
+		pdf.SetFont(fontName, "", 9)
+		pdf.SetTextColor(128, 128, 128)
+		if theme.FooterText != "" {
+			pdf.CellFormat(0, 10, theme.FooterText, "", 0, "L", false, 0, "")
+			pdf.SetX(left)
+		}
+		pdf.CellFormat(0, 10, fmt.Sprintf("%d / {nb}", pdf.PageNo()), "", 0, "R", false, 0, "")
+		pdf.SetTextColor(0, 0, 0)
+	})
+}
+
 func (mf *PDFFormatter) ContentType() string {
 	return pdfContentType
 }
@@ -0,0 +1,55 @@
+package formatter
+
+import (
+	"bytes"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/unidoc/unioffice/spreadsheet"
+)
+
+const qaSpreadsheetExtension = ".xlsx"
+
+// questionStatusTitles are the spreadsheet labels of question statuses
+var questionStatusTitles = map[entity.QuestionStatus]string{
+	entity.AnswerStatusAnswered:   "Отвечен",
+	entity.AnswerStatusSkiped:     "Пропущен",
+	entity.AnswerStatusUnanswered: "Без ответа",
+	entity.AnswerStatusDeferred:   "Отложен",
+}
+
+// FormatQASpreadsheet renders interview questions and answers as an xlsx sheet, one question per row
+func FormatQASpreadsheet(iterations []*entity.BundleIteration) ([]byte, error) {
+	wb := spreadsheet.New()
+	defer wb.Close()
+
+	sheet := wb.AddSheet()
+	sheet.SetName("Вопросы и ответы")
+
+	header := sheet.AddRow()
+	for _, title := range []string{"Итерация", "Блок", "№", "Вопрос", "Пояснение", "Статус", "Ответ"} {
+		header.AddCell().SetString(title)
+	}
+
+	for _, iteration := range iterations {
+		for _, question := range iteration.Questions {
+			row := sheet.AddRow()
+			row.AddCell().SetNumber(float64(iteration.IterationNumber))
+			row.AddCell().SetString(iteration.Title)
+			row.AddCell().SetNumber(float64(question.QuestionNumber))
+			row.AddCell().SetString(question.Question)
+			row.AddCell().SetString(question.Explanation)
+			row.AddCell().SetString(questionStatusTitles[question.Status])
+			answer := ""
+			if question.Answer != nil {
+				answer = *question.Answer
+			}
+			row.AddCell().SetString(answer)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := wb.Save(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,55 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/futig/agent-backend/internal/entity"
+)
+
+const questionScriptTitle = "Сценарий интервью"
+
+// QuestionScriptFileName names the interview script document of a session
+func QuestionScriptFileName(info *entity.ResultFileInfo, ext string) string {
+	return FileName(info, "questions", ext)
+}
+
+// NewQuestionScriptFormatter returns the template of the format titled as an interview script
+func NewQuestionScriptFormatter(format entity.ResultFormat, date time.Time, theme *entity.DocumentTheme) (Formatter, error) {
+	opts := TemplateOptions(format, "", date, theme)
+	opts.Title = questionScriptTitle
+	return newFormatter(format, opts)
+}
+
+// FormatQuestionScript renders the interview blocks with their questions and explanations as markdown
+// for analysts who conduct the interview live; answers are left out
+func FormatQuestionScript(iterations []*entity.BundleIteration) string {
+	var b strings.Builder
+	for _, iteration := range iterations {
+		if len(iteration.Questions) == 0 {
+			continue
+		}
+
+		title := iteration.Title
+		if title == "" {
+			title = fmt.Sprintf("Блок %d", iteration.IterationNumber)
+		}
+		fmt.Fprintf(&b, "## %s\n\n", title)
+
+		for i, question := range iteration.Questions {
+			fmt.Fprintf(&b, "%d. %s\n", i+1, question.Question)
+			if question.AnswerType == entity.QuestionAnswerTypeScale {
+				fmt.Fprintf(&b, "   Оценка от %d до %d\n", entity.ScaleMin, entity.ScaleMax)
+			}
+			if len(question.Options) > 0 {
+				fmt.Fprintf(&b, "   Варианты: %s\n", strings.Join(question.Options, "; "))
+			}
+			if question.Explanation != "" {
+				fmt.Fprintf(&b, "   Пояснение: %s\n", question.Explanation)
+			}
+			b.WriteString("\n")
+		}
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
@@ -0,0 +1,56 @@
+package formatter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/futig/agent-backend/internal/entity"
+)
+
+// sectionChangeTitles labels the sections of a result diff
+var sectionChangeTitles = map[entity.SectionChange]string{
+	entity.SectionChangeAdded:   "новый раздел",
+	entity.SectionChangeRemoved: "раздел удалён",
+	entity.SectionChangeChanged: "раздел изменён",
+}
+
+// diffLinePrefixes marks the lines of a section in a diff block
+var diffLinePrefixes = map[entity.DiffLineOp]string{
+	entity.DiffLineEqual:  "  ",
+	entity.DiffLineInsert: "+ ",
+	entity.DiffLineDelete: "- ",
+}
+
+// FormatResultDiff renders a diff of two requirements documents as Markdown: the counts of the
+// changed sections, then every section that differs with its lines in a diff block
+func FormatResultDiff(diff *entity.ResultDiff) string {
+	var b strings.Builder
+
+	b.WriteString("# Сравнение версий требований\n\n")
+	fmt.Fprintf(&b, "Новых разделов: %d, удалённых: %d, изменённых: %d, без изменений: %d.\n\n",
+		diff.Summary.Added, diff.Summary.Removed, diff.Summary.Changed, diff.Summary.Unchanged)
+
+	if diff.Summary.Added+diff.Summary.Removed+diff.Summary.Changed == 0 {
+		b.WriteString("Версии совпадают.\n")
+		return b.String()
+	}
+
+	for _, section := range diff.Sections {
+		if section.Change == entity.SectionChangeUnchanged {
+			continue
+		}
+
+		title := section.Title
+		if title == "" {
+			title = "Без заголовка"
+		}
+		fmt.Fprintf(&b, "## %s (%s)\n\n```diff\n", title, sectionChangeTitles[section.Change])
+		for _, line := range section.Lines {
+			b.WriteString(strings.TrimRight(diffLinePrefixes[line.Op]+line.Text, " "))
+			b.WriteString("\n")
+		}
+		b.WriteString("```\n\n")
+	}
+
+	return b.String()
+}
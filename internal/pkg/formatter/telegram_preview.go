@@ -0,0 +1,227 @@
+package formatter
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	previewHeadingPattern = regexp.MustCompile(`^#{1,6}\s+(.*)$`)
+	previewListPattern    = regexp.MustCompile(`^(\s*)[-*+]\s+(.*)$`)
+	previewRulePattern    = regexp.MustCompile(`^\s*([-*_]\s*){3,}$`)
+	previewFencePattern   = regexp.MustCompile("^\\s*```")
+	previewCodePattern    = regexp.MustCompile("`([^`]+)`")
+	previewLinkPattern    = regexp.MustCompile(`\[([^\]]+)\]\((https?://[^)\s]+)\)`)
+	previewBoldPattern    = regexp.MustCompile(`\*\*([^*]+)\*\*|__([^_]+)__`)
+	previewItalicPattern  = regexp.MustCompile(`\*([^*\s][^*]*)\*`)
+	previewStrikePattern  = regexp.MustCompile(`~~([^~]+)~~`)
+)
+
+// TelegramHTML converts markdown, usually produced by the LLM, to text safe to send with the Telegram
+// HTML parse mode. Everything that is not converted to a tag is escaped, so unbalanced markers and
+// stray "<" or "&" never break the message.
+func TelegramHTML(markdown string) string {
+	lines := previewLines(markdown)
+	rendered := make([]string, 0, len(lines))
+	for _, line := range lines {
+		if line.fence {
+			continue
+		}
+		rendered = append(rendered, line.render())
+	}
+
+	return strings.TrimSpace(strings.Join(rendered, "\n"))
+}
+
+// previewSourceLine is a markdown line with the code block it belongs to
+type previewSourceLine struct {
+	text  string
+	code  bool // inside a fenced code block
+	fence bool // the fence itself, never rendered
+}
+
+func (l previewSourceLine) render() string {
+	if l.code {
+		return previewCodeLine(l.text)
+	}
+	return previewLine(l.text)
+}
+
+// previewLines splits markdown into lines and marks fenced code blocks
+func previewLines(markdown string) []previewSourceLine {
+	var lines []previewSourceLine
+	inCode := false
+	for _, line := range strings.Split(strings.ReplaceAll(markdown, "\r\n", "\n"), "\n") {
+		line = strings.TrimRight(line, " \t")
+		if previewFencePattern.MatchString(line) {
+			inCode = !inCode
+			lines = append(lines, previewSourceLine{text: line, fence: true})
+			continue
+		}
+		lines = append(lines, previewSourceLine{text: line, code: inCode})
+	}
+
+	return lines
+}
+
+// PreviewPages renders a markdown result as Telegram HTML messages of at most limit characters.
+// Pages break before headings when possible, sections longer than a page break between lines.
+func PreviewPages(markdown string, limit int) []string {
+	var pages []string
+	var page strings.Builder
+
+	flush := func() {
+		if text := strings.TrimSpace(page.String()); text != "" {
+			pages = append(pages, text)
+		}
+		page.Reset()
+	}
+	add := func(text string) {
+		if page.Len() > 0 && utf8.RuneCountInString(page.String())+1+utf8.RuneCountInString(text) > limit {
+			flush()
+		}
+		if page.Len() > 0 {
+			page.WriteString("\n")
+		}
+		page.WriteString(text)
+	}
+
+	for _, section := range previewSections(markdown) {
+		lines := make([]string, 0, len(section))
+		for _, line := range section {
+			lines = append(lines, line.render())
+		}
+
+		// A section that fits on a page is never split; it starts a new page instead
+		if text := strings.Join(lines, "\n"); utf8.RuneCountInString(text) <= limit {
+			add(text)
+			continue
+		}
+
+		for i, line := range lines {
+			if utf8.RuneCountInString(line) <= limit {
+				add(line)
+				continue
+			}
+			for _, chunk := range splitPreviewLine(section[i], limit) {
+				add(chunk)
+			}
+		}
+	}
+	flush()
+
+	return pages
+}
+
+// previewSections groups the lines of the markdown by headings; "#" inside a code block is not a heading
+func previewSections(markdown string) [][]previewSourceLine {
+	var sections [][]previewSourceLine
+	var current []previewSourceLine
+
+	for _, line := range previewLines(markdown) {
+		if line.fence {
+			continue
+		}
+		if !line.code && previewHeadingPattern.MatchString(line.text) && len(current) > 0 {
+			sections = append(sections, current)
+			current = nil
+		}
+		current = append(current, line)
+	}
+	if len(current) > 0 {
+		sections = append(sections, current)
+	}
+
+	return sections
+}
+
+// splitPreviewLine cuts a markdown line too long for one page into pieces rendered separately
+func splitPreviewLine(line previewSourceLine, limit int) []string {
+	var chunks []string
+	runes := []rune(line.text)
+	size := limit
+
+	for len(runes) > 0 {
+		n := min(size, len(runes))
+		chunk := previewSourceLine{text: string(runes[:n]), code: line.code}.render()
+		// Escaping may make the chunk longer than the raw text
+		if utf8.RuneCountInString(chunk) > limit && n > 1 {
+			size = n / 2
+			continue
+		}
+		chunks = append(chunks, chunk)
+		runes = runes[n:]
+		size = limit
+	}
+
+	return chunks
+}
+
+// previewLine converts a markdown line to Telegram HTML: headings are bold, list items get bullets
+func previewLine(line string) string {
+	if m := previewHeadingPattern.FindStringSubmatch(line); m != nil {
+		return "<b>" + html.EscapeString(stripInlineMarkers(m[1])) + "</b>"
+	}
+	if previewRulePattern.MatchString(line) {
+		return "—————"
+	}
+	if m := previewListPattern.FindStringSubmatch(line); m != nil {
+		return m[1] + "• " + previewInline(m[2])
+	}
+	return previewInline(line)
+}
+
+// previewCodeLine renders a line of a fenced code block as is, in monospace.
+// Each line is a tag of its own, so a page may break inside a code block
+func previewCodeLine(line string) string {
+	if strings.TrimSpace(line) == "" {
+		return ""
+	}
+	return "<code>" + html.EscapeString(line) + "</code>"
+}
+
+// previewInline converts code spans, links, bold, italic and strikethrough text;
+// the text inside code spans stays as it is
+func previewInline(text string) string {
+	var b strings.Builder
+	last := 0
+	for _, loc := range previewCodePattern.FindAllStringSubmatchIndex(text, -1) {
+		b.WriteString(previewLinks(text[last:loc[0]]))
+		b.WriteString("<code>" + html.EscapeString(text[loc[2]:loc[3]]) + "</code>")
+		last = loc[1]
+	}
+	b.WriteString(previewLinks(text[last:]))
+
+	return b.String()
+}
+
+// previewLinks converts markdown links to anchors; only http(s) links are kept, Telegram rejects the rest
+func previewLinks(text string) string {
+	var b strings.Builder
+	last := 0
+	for _, loc := range previewLinkPattern.FindAllStringSubmatchIndex(text, -1) {
+		b.WriteString(previewEmphasis(text[last:loc[0]]))
+		b.WriteString(`<a href="` + html.EscapeString(text[loc[4]:loc[5]]) + `">`)
+		b.WriteString(previewEmphasis(text[loc[2]:loc[3]]) + "</a>")
+		last = loc[1]
+	}
+	b.WriteString(previewEmphasis(text[last:]))
+
+	return b.String()
+}
+
+func previewEmphasis(text string) string {
+	text = html.EscapeString(text)
+	text = previewBoldPattern.ReplaceAllStringFunc(text, func(m string) string {
+		return "<b>" + m[2:len(m)-2] + "</b>"
+	})
+	text = previewStrikePattern.ReplaceAllString(text, "<s>$1</s>")
+	return previewItalicPattern.ReplaceAllString(text, "<i>$1</i>")
+}
+
+// stripInlineMarkers drops emphasis markers of headings, which are bold as a whole
+func stripInlineMarkers(text string) string {
+	return strings.NewReplacer("**", "", "__", "", "`", "").Replace(text)
+}
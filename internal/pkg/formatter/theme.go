@@ -0,0 +1,36 @@
+package formatter
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/futig/agent-backend/internal/entity"
+)
+
+// themeLogoHeightMM is the height of the theme logo in document headers
+const themeLogoHeightMM = 12
+
+// primaryRGB parses the theme primary color; ok is false when the theme has none
+func primaryRGB(theme *entity.DocumentTheme) (r, g, b int, ok bool) {
+	if theme == nil || len(theme.PrimaryColor) != 7 {
+		return 0, 0, 0, false
+	}
+	v, err := strconv.ParseUint(strings.TrimPrefix(theme.PrimaryColor, "#"), 16, 32)
+	if err != nil {
+		return 0, 0, 0, false
+	}
+	return int(v >> 16 & 0xff), int(v >> 8 & 0xff), int(v & 0xff), true
+}
+
+// themeLogo returns the logo of the theme, nil when the theme has none
+func themeLogo(theme *entity.DocumentTheme) *entity.ThemeAsset {
+	if theme == nil || theme.Logo == nil || len(theme.Logo.Data) == 0 {
+		return nil
+	}
+	return theme.Logo
+}
+
+// themeHeader reports whether the theme draws anything in the page header
+func themeHeader(theme *entity.DocumentTheme) bool {
+	return theme != nil && (theme.HeaderText != "" || themeLogo(theme) != nil)
+}
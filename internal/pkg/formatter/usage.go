@@ -0,0 +1,68 @@
+package formatter
+
+import (
+	"fmt"
+	"time"
+)
+
+// ProjectUsage renders the usage label of a project shown next to its title,
+// e.g. "• 3 сессии • 2 дня назад"
+func ProjectUsage(sessionCount int, lastUsedAt *time.Time, now time.Time) string {
+	label := "• нет сессий"
+	if sessionCount > 0 {
+		label = fmt.Sprintf("• %d %s", sessionCount, pluralRu(sessionCount, "сессия", "сессии", "сессий"))
+	}
+
+	if lastUsedAt != nil {
+		label += " • " + relativeDayRu(*lastUsedAt, now)
+	}
+
+	return label
+}
+
+// MaterialsAge renders how long ago the materials of a project were last indexed, e.g. "8 месяцев назад"
+func MaterialsAge(lastIndexedAt, now time.Time) string {
+	return relativeDayRu(lastIndexedAt, now)
+}
+
+// relativeDayRu describes how many calendar days ago t was
+func relativeDayRu(t, now time.Time) string {
+	t = t.In(now.Location())
+	days := int(truncateDay(now).Sub(truncateDay(t)).Hours() / 24)
+
+	switch {
+	case days <= 0:
+		return "сегодня"
+	case days == 1:
+		return "вчера"
+	case days < 30:
+		return fmt.Sprintf("%d %s назад", days, pluralRu(days, "день", "дня", "дней"))
+	case days < 365:
+		months := days / 30
+		return fmt.Sprintf("%d %s назад", months, pluralRu(months, "месяц", "месяца", "месяцев"))
+	default:
+		years := days / 365
+		return fmt.Sprintf("%d %s назад", years, pluralRu(years, "год", "года", "лет"))
+	}
+}
+
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// pluralRu picks the Russian noun form for n: one (1, 21), few (2-4, 22-24) or many (5-20, 25)
+func pluralRu(n int, one, few, many string) string {
+	n %= 100
+	if n >= 11 && n <= 14 {
+		return many
+	}
+
+	switch n % 10 {
+	case 1:
+		return one
+	case 2, 3, 4:
+		return few
+	default:
+		return many
+	}
+}
@@ -0,0 +1,266 @@
+package jobqueue
+
+import (
+	"context"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Lane is the priority class of a job; lower lanes run first
+type Lane int
+
+const (
+	// LaneInteractive holds jobs a client waits on to continue, like answers and session starts
+	LaneInteractive Lane = iota
+	// LaneGeneration holds result generations and rewrites
+	LaneGeneration
+	// LaneReindex holds project file indexing
+	LaneReindex
+
+	laneCount
+)
+
+func (l Lane) String() string {
+	switch l {
+	case LaneInteractive:
+		return "interactive"
+	case LaneGeneration:
+		return "generation"
+	case LaneReindex:
+		return "reindex"
+	}
+	return "unknown"
+}
+
+// Config sets the worker pool size of the queue
+type Config struct {
+	Workers        int           `env:"WORKERS" envDefault:"8"`
+	ReportInterval time.Duration `env:"REPORT_INTERVAL" envDefault:"1m"`
+}
+
+// Queue runs async jobs on a fixed pool of workers. Lanes are served by priority,
+// and within a lane the owners (tenant and client) with pending jobs take turns,
+// so one heavy owner cannot starve the others.
+type Queue struct {
+	cfg    Config
+	logger *zap.Logger
+
+	mu     sync.Mutex
+	cond   *sync.Cond
+	lanes  [laneCount]*lane
+	closed bool
+}
+
+type job struct {
+	run      func()
+	enqueued time.Time
+}
+
+type lane struct {
+	pending map[string][]*job
+	owners  []string // owners with pending jobs in turn order
+	next    int
+	depth   int
+
+	// Report window counters
+	started   int
+	totalWait time.Duration
+	maxWait   time.Duration
+}
+
+// LaneStats describes the depth and the waits of a lane in the last report window
+type LaneStats struct {
+	Lane    Lane
+	Depth   int
+	Owners  int
+	Started int
+	AvgWait time.Duration
+	MaxWait time.Duration
+}
+
+func New(cfg Config, logger *zap.Logger) *Queue {
+	q := &Queue{
+		cfg:    cfg,
+		logger: logger,
+	}
+	q.cond = sync.NewCond(&q.mu)
+	for i := range q.lanes {
+		q.lanes[i] = &lane{pending: make(map[string][]*job)}
+	}
+	return q
+}
+
+// Owner identifies the tenant and the client a job is fair-scheduled for
+func Owner(tenantID, clientID string) string {
+	return tenantID + "/" + clientID
+}
+
+// Submit queues run in lane on behalf of owner. Jobs submitted after the queue
+// stopped are dropped.
+func (q *Queue) Submit(l Lane, owner string, run func()) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed {
+		q.logger.Warn("job queue is stopped, dropping job",
+			zap.Stringer("lane", l),
+			zap.String("owner", owner),
+		)
+		return
+	}
+
+	ln := q.lanes[l]
+	if len(ln.pending[owner]) == 0 {
+		ln.owners = append(ln.owners, owner)
+	}
+	ln.pending[owner] = append(ln.pending[owner], &job{run: run, enqueued: time.Now()})
+	ln.depth++
+	q.cond.Signal()
+}
+
+// Run starts the workers and blocks until ctx is done. Running jobs finish on their own,
+// jobs still queued are dropped.
+func (q *Queue) Run(ctx context.Context) {
+	workers := max(q.cfg.Workers, 1)
+	q.logger.Info("Job queue started", zap.Int("workers", workers))
+
+	for range workers {
+		go q.work()
+	}
+
+	var report <-chan time.Time
+	if q.cfg.ReportInterval > 0 {
+		ticker := time.NewTicker(q.cfg.ReportInterval)
+		defer ticker.Stop()
+		report = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			q.stop()
+			return
+		case <-report:
+			q.report()
+		}
+	}
+}
+
+// Stats returns the lane stats and resets the counters of the report window
+func (q *Queue) Stats() []LaneStats {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	stats := make([]LaneStats, 0, laneCount)
+	for i, ln := range q.lanes {
+		s := LaneStats{
+			Lane:    Lane(i),
+			Depth:   ln.depth,
+			Owners:  len(ln.owners),
+			Started: ln.started,
+			MaxWait: ln.maxWait,
+		}
+		if ln.started > 0 {
+			s.AvgWait = ln.totalWait / time.Duration(ln.started)
+		}
+		stats = append(stats, s)
+
+		ln.started, ln.totalWait, ln.maxWait = 0, 0, 0
+	}
+	return stats
+}
+
+func (q *Queue) work() {
+	for {
+		q.mu.Lock()
+		j, ok := q.pop()
+		for !ok && !q.closed {
+			q.cond.Wait()
+			j, ok = q.pop()
+		}
+		q.mu.Unlock()
+
+		if !ok {
+			return
+		}
+		q.runJob(j)
+	}
+}
+
+// pop takes the next job: the highest priority lane with pending jobs,
+// then the owner whose turn it is; callers hold mu
+func (q *Queue) pop() (*job, bool) {
+	if q.closed {
+		return nil, false
+	}
+
+	for _, ln := range q.lanes {
+		if ln.depth == 0 {
+			continue
+		}
+
+		if ln.next >= len(ln.owners) {
+			ln.next = 0
+		}
+		owner := ln.owners[ln.next]
+		jobs := ln.pending[owner]
+		j := jobs[0]
+
+		if len(jobs) == 1 {
+			delete(ln.pending, owner)
+			ln.owners = append(ln.owners[:ln.next], ln.owners[ln.next+1:]...)
+		} else {
+			ln.pending[owner] = jobs[1:]
+			ln.next++
+		}
+		ln.depth--
+
+		wait := time.Since(j.enqueued)
+		ln.started++
+		ln.totalWait += wait
+		ln.maxWait = max(ln.maxWait, wait)
+		return j, true
+	}
+	return nil, false
+}
+
+func (q *Queue) runJob(j *job) {
+	defer func() {
+		if r := recover(); r != nil {
+			q.logger.Error("job panicked",
+				zap.Any("panic", r),
+				zap.String("stack", string(debug.Stack())),
+			)
+		}
+	}()
+	j.run()
+}
+
+func (q *Queue) stop() {
+	q.mu.Lock()
+	q.closed = true
+	dropped := 0
+	for _, ln := range q.lanes {
+		dropped += ln.depth
+	}
+	q.cond.Broadcast()
+	q.mu.Unlock()
+
+	q.logger.Info("Job queue stopped", zap.Int("dropped_jobs", dropped))
+}
+
+func (q *Queue) report() {
+	for _, s := range q.Stats() {
+		q.logger.Info("Job queue lane saturation",
+			zap.Stringer("lane", s.Lane),
+			zap.Int("depth", s.Depth),
+			zap.Int("owners", s.Owners),
+			zap.Int("started", s.Started),
+			zap.Duration("avg_wait", s.AvgWait),
+			zap.Duration("max_wait", s.MaxWait),
+		)
+	}
+}
@@ -0,0 +1,58 @@
+// Package langdetect detects the language of short user inputs among the languages of the
+// generated documents
+package langdetect
+
+import (
+	"unicode"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/pemistahl/lingua-go"
+)
+
+const (
+	// minLetters is the number of letters below which no detection is confident
+	minLetters = 12
+	// minRelativeDistance is how far the best language must lead the second one to be confident;
+	// short answers such as "ok" or "да" stay undetected instead of being guessed
+	minRelativeDistance = 0.25
+)
+
+// languages maps the detectable languages to the languages of the generated documents
+var languages = map[lingua.Language]entity.ResultLanguage{
+	lingua.Russian: entity.LanguageRussian,
+	lingua.English: entity.LanguageEnglish,
+	lingua.German:  entity.LanguageGerman,
+	lingua.French:  entity.LanguageFrench,
+	lingua.Spanish: entity.LanguageSpanish,
+	lingua.Chinese: entity.LanguageChinese,
+}
+
+// detector only tells apart the document languages; its models are loaded on first use
+var detector = lingua.NewLanguageDetectorBuilder().
+	FromLanguages(lingua.Russian, lingua.English, lingua.German, lingua.French, lingua.Spanish, lingua.Chinese).
+	WithMinimumRelativeDistance(minRelativeDistance).
+	Build()
+
+// Detect returns the language of the text and whether the detection is confident. Short texts and
+// texts no language clearly leads are not confident and return an empty language
+func Detect(text string) (entity.ResultLanguage, bool) {
+	letters := 0
+	for _, r := range text {
+		switch {
+		// A Chinese character carries a word or a syllable, a few of them are already a phrase
+		case unicode.Is(unicode.Han, r):
+			letters += 4
+		case unicode.IsLetter(r):
+			letters++
+		}
+	}
+	if letters < minLetters {
+		return "", false
+	}
+
+	language, ok := detector.DetectLanguageOf(text)
+	if !ok {
+		return "", false
+	}
+	return languages[language], true
+}
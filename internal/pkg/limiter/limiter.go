@@ -0,0 +1,209 @@
+package limiter
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrQueueFull is returned when the number of waiting calls reached the queue size
+	ErrQueueFull = errors.New("limiter queue is full")
+	// ErrQueueTimeout is returned when a call waited for a slot longer than the queue timeout
+	ErrQueueTimeout = errors.New("limiter queue timeout")
+)
+
+// Config bounds the calls running at once; zero limits are unbounded
+type Config struct {
+	MaxConcurrent       int           `env:"MAX_CONCURRENT" envDefault:"16"`
+	MaxConcurrentPerKey int           `env:"MAX_CONCURRENT_PER_PROVIDER" envDefault:"8"`
+	MaxQueue            int           `env:"MAX_QUEUE" envDefault:"200"`
+	QueueTimeout        time.Duration `env:"QUEUE_TIMEOUT" envDefault:"30s"`
+	ReportInterval      time.Duration `env:"REPORT_INTERVAL" envDefault:"1m"`
+}
+
+// Limiter is a semaphore shared by all calls with a nested semaphore per key.
+// Waiting calls get free slots by priority, then in arrival order.
+type Limiter struct {
+	cfg Config
+
+	mu       sync.Mutex
+	inFlight int
+	perKey   map[string]int
+	queue    []*waiter
+	window   Stats
+}
+
+type waiter struct {
+	key      string
+	priority int
+	ready    chan struct{}
+	granted  bool
+}
+
+// Stats describes the saturation of a limiter: current gauges and counters of the last report window
+type Stats struct {
+	InFlight       int
+	Queued         int
+	InFlightPerKey map[string]int
+
+	PeakInFlight int
+	PeakQueued   int
+	Acquired     int
+	Waited       int // acquired after queueing
+	Rejected     int
+	TimedOut     int
+	MaxWait      time.Duration
+}
+
+func New(cfg Config) *Limiter {
+	return &Limiter{
+		cfg:    cfg,
+		perKey: make(map[string]int),
+	}
+}
+
+// Acquire waits for a slot of key; calls of higher priority are served first.
+// The returned release must be called once the call is done.
+func (l *Limiter) Acquire(ctx context.Context, key string, priority int) (release func(), err error) {
+	l.mu.Lock()
+	if l.available(key) {
+		l.grant(key)
+		l.mu.Unlock()
+		return l.releaser(key), nil
+	}
+	if l.cfg.MaxQueue > 0 && len(l.queue) >= l.cfg.MaxQueue {
+		l.window.Rejected++
+		l.mu.Unlock()
+		return nil, ErrQueueFull
+	}
+	w := &waiter{key: key, priority: priority, ready: make(chan struct{})}
+	l.enqueue(w)
+	l.mu.Unlock()
+
+	started := time.Now()
+	var timeout <-chan time.Time
+	if l.cfg.QueueTimeout > 0 {
+		timer := time.NewTimer(l.cfg.QueueTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case <-w.ready:
+		l.mu.Lock()
+		l.window.Waited++
+		if wait := time.Since(started); wait > l.window.MaxWait {
+			l.window.MaxWait = wait
+		}
+		l.mu.Unlock()
+		return l.releaser(key), nil
+	case <-ctx.Done():
+		err = ctx.Err()
+	case <-timeout:
+		err = ErrQueueTimeout
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if w.granted {
+		// The slot was granted while giving up, hand it to the next waiter
+		l.free(key)
+	} else {
+		l.dequeue(w)
+	}
+	if errors.Is(err, ErrQueueTimeout) {
+		l.window.TimedOut++
+	}
+	return nil, err
+}
+
+// Report returns the current saturation and resets the counters of the report window
+func (l *Limiter) Report() Stats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	stats := l.window
+	stats.InFlight = l.inFlight
+	stats.Queued = len(l.queue)
+	stats.InFlightPerKey = make(map[string]int, len(l.perKey))
+	for key, n := range l.perKey {
+		stats.InFlightPerKey[key] = n
+	}
+
+	l.window = Stats{PeakInFlight: l.inFlight, PeakQueued: len(l.queue)}
+	return stats
+}
+
+// available reports whether a call of key may start now; callers hold mu
+func (l *Limiter) available(key string) bool {
+	if l.cfg.MaxConcurrent > 0 && l.inFlight >= l.cfg.MaxConcurrent {
+		return false
+	}
+	return l.cfg.MaxConcurrentPerKey <= 0 || l.perKey[key] < l.cfg.MaxConcurrentPerKey
+}
+
+func (l *Limiter) grant(key string) {
+	l.inFlight++
+	l.perKey[key]++
+	l.window.Acquired++
+	if l.inFlight > l.window.PeakInFlight {
+		l.window.PeakInFlight = l.inFlight
+	}
+}
+
+func (l *Limiter) releaser(key string) func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			l.free(key)
+		})
+	}
+}
+
+// free returns the slot of key and grants free slots to the waiters; callers hold mu
+func (l *Limiter) free(key string) {
+	l.inFlight--
+	if l.perKey[key]--; l.perKey[key] <= 0 {
+		delete(l.perKey, key)
+	}
+
+	remaining := l.queue[:0]
+	for _, w := range l.queue {
+		if l.available(w.key) {
+			w.granted = true
+			l.grant(w.key)
+			close(w.ready)
+			continue
+		}
+		remaining = append(remaining, w)
+	}
+	clear(l.queue[len(remaining):])
+	l.queue = remaining
+}
+
+// enqueue inserts w after the waiters of the same or higher priority; callers hold mu
+func (l *Limiter) enqueue(w *waiter) {
+	i := len(l.queue)
+	for i > 0 && l.queue[i-1].priority < w.priority {
+		i--
+	}
+	l.queue = append(l.queue, nil)
+	copy(l.queue[i+1:], l.queue[i:])
+	l.queue[i] = w
+	if len(l.queue) > l.window.PeakQueued {
+		l.window.PeakQueued = len(l.queue)
+	}
+}
+
+func (l *Limiter) dequeue(w *waiter) {
+	for i, queued := range l.queue {
+		if queued == w {
+			l.queue = append(l.queue[:i], l.queue[i+1:]...)
+			return
+		}
+	}
+}
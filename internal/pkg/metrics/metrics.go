@@ -0,0 +1,105 @@
+// Package metrics holds the process-wide Prometheus collectors, registered in the default registry
+// next to the Go runtime and process collectors
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Buckets of the latency histograms, in seconds
+var (
+	// RequestBuckets suit API requests answered within seconds
+	RequestBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+	// CallBuckets suit LLM calls and bot handlers that may take minutes
+	CallBuckets = []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300, 900}
+)
+
+var (
+	// HTTPRequestDuration is the latency of API requests by method, route pattern and status code
+	HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "Latency of API requests",
+		Buckets: RequestBuckets,
+	}, []string{"method", "route", "status"})
+	// TelegramHandlerDuration is the time bot handlers take, by handler state
+	TelegramHandlerDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "telegram_handler_duration_seconds",
+		Help:    "Time bot handlers take to handle an update",
+		Buckets: CallBuckets,
+	}, []string{"state"})
+	// TelegramHandlerTimeouts counts bot handlers stopped by their timeout, by handler state
+	TelegramHandlerTimeouts = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "telegram_handler_timeouts_total",
+		Help: "Bot handlers stopped by their timeout",
+	}, []string{"state"})
+	// TelegramHandlerCancellations counts bot handlers stopped by /cancel of the user, by handler state
+	TelegramHandlerCancellations = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "telegram_handler_cancellations_total",
+		Help: "Bot handlers stopped by /cancel of the user",
+	}, []string{"state"})
+	// TelegramUpdates counts updates received by the bots, by bot and update type
+	TelegramUpdates = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "telegram_updates_total",
+		Help: "Updates received by the bots",
+	}, []string{"bot", "type"})
+	// ConnectorRequestDuration is the time outbound requests take up to their response headers, by connector
+	ConnectorRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "connector_request_duration_seconds",
+		Help:    "Time outbound requests of external connectors take up to their response headers",
+		Buckets: CallBuckets,
+	}, []string{"connector"})
+	// ConnectorNewConnections counts requests that set up a connection instead of reusing one, by connector
+	ConnectorNewConnections = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "connector_new_connections_total",
+		Help: "Outbound requests that set up a new connection",
+	}, []string{"connector"})
+	// ConnectorErrors counts outbound requests failed without a response, by connector
+	ConnectorErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "connector_errors_total",
+		Help: "Outbound requests failed without a response",
+	}, []string{"connector"})
+	// ConnectorWarmupLatency is the latency of the last warm-up ping, by connector
+	ConnectorWarmupLatency = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "connector_warmup_latency_seconds",
+		Help: "Latency of the last warm-up ping",
+	}, []string{"connector"})
+	// SessionStatusChanges counts sessions moved to a status, by the status
+	SessionStatusChanges = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "session_status_changes_total",
+		Help: "Sessions moved to a status",
+	}, []string{"status"})
+	// SessionTransitionsRejected counts status transitions refused because a concurrent flow
+	// moved the session to another status, by "FROM->TO"
+	SessionTransitionsRejected = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "session_status_transitions_rejected_total",
+		Help: "Status transitions refused because a concurrent flow moved the session",
+	}, []string{"transition"})
+	// SessionTransitionsRepeated counts transitions that found the session already in the target status, by "FROM->TO"
+	SessionTransitionsRepeated = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "session_status_transitions_repeated_total",
+		Help: "Status transitions that found the session already in the target status",
+	}, []string{"transition"})
+	// RateLimitRejections counts requests refused by a rate limit or quota, by source: api or telegram
+	RateLimitRejections = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "rate_limit_rejections_total",
+		Help: "Requests refused by a rate limit or quota",
+	}, []string{"source"})
+	// ExplanationRequests counts requested question explanations by result: hit when served from the cache,
+	// miss when generated, throttled when refused by the cooldown and stored when the explanation generated
+	// with the question was returned instead
+	ExplanationRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "question_explanation_requests_total",
+		Help: "Requested question explanations by cache result",
+	}, []string{"result"})
+)
+
+// NewServeMux serves the metrics on /metrics, for a metrics listener of its own
+func NewServeMux() *http.ServeMux {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.Handler())
+	return mux
+}
@@ -0,0 +1,140 @@
+package moderation
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// WordFilter matches inputs against multilingual word lists
+type WordFilter struct {
+	terms map[string]struct{}
+}
+
+// NewWordFilter creates a filter from word lists keyed by language
+func NewWordFilter(lists map[string][]string) *WordFilter {
+	terms := make(map[string]struct{})
+	for _, words := range lists {
+		for _, w := range words {
+			w = strings.ToLower(strings.TrimSpace(w))
+			if w != "" {
+				terms[w] = struct{}{}
+			}
+		}
+	}
+
+	return &WordFilter{terms: terms}
+}
+
+// LoadWordLists reads <lang>.txt files from dir, one term per line, '#' starts a comment
+func LoadWordLists(dir string) (map[string][]string, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.txt"))
+	if err != nil {
+		return nil, fmt.Errorf("list word lists: %w", err)
+	}
+
+	lists := make(map[string][]string, len(paths))
+	for _, path := range paths {
+		lang := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+
+		words, err := readWordList(path)
+		if err != nil {
+			return nil, fmt.Errorf("read word list %s: %w", path, err)
+		}
+
+		lists[lang] = words
+	}
+
+	return lists, nil
+}
+
+func readWordList(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var words []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		words = append(words, line)
+	}
+
+	return words, scanner.Err()
+}
+
+// Find returns the distinct listed terms found in text
+func (f *WordFilter) Find(text string) []string {
+	if len(f.terms) == 0 {
+		return nil
+	}
+
+	found := make(map[string]struct{})
+	for _, word := range splitWords(text) {
+		lower := strings.ToLower(word)
+		if _, ok := f.terms[lower]; ok {
+			found[lower] = struct{}{}
+		}
+	}
+
+	matched := make([]string, 0, len(found))
+	for term := range found {
+		matched = append(matched, term)
+	}
+	sort.Strings(matched)
+
+	return matched
+}
+
+// Redact replaces every listed word in text with asterisks of the same length
+func (f *WordFilter) Redact(text string) string {
+	if len(f.terms) == 0 {
+		return text
+	}
+
+	var b strings.Builder
+	var word []rune
+
+	flush := func() {
+		if len(word) == 0 {
+			return
+		}
+		if _, ok := f.terms[strings.ToLower(string(word))]; ok {
+			b.WriteString(strings.Repeat("*", len(word)))
+		} else {
+			b.WriteString(string(word))
+		}
+		word = word[:0]
+	}
+
+	for _, r := range text {
+		if isWordRune(r) {
+			word = append(word, r)
+			continue
+		}
+		flush()
+		b.WriteRune(r)
+	}
+	flush()
+
+	return b.String()
+}
+
+func splitWords(text string) []string {
+	return strings.FieldsFunc(text, func(r rune) bool {
+		return !isWordRune(r)
+	})
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
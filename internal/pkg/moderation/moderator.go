@@ -0,0 +1,70 @@
+package moderation
+
+import (
+	"context"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// APIChecker is an optional external moderation service
+type APIChecker interface {
+	Check(ctx context.Context, text string) (*entity.ModerationAPIResponse, error)
+}
+
+// Moderator checks user inputs against word lists and an optional external API
+type Moderator struct {
+	enabled bool
+	action  entity.ModerationAction
+	filter  *WordFilter
+	api     APIChecker
+}
+
+// NewModerator creates a moderator; api may be nil
+func NewModerator(enabled bool, action entity.ModerationAction, filter *WordFilter, api APIChecker) *Moderator {
+	return &Moderator{
+		enabled: enabled,
+		action:  action,
+		filter:  filter,
+		api:     api,
+	}
+}
+
+// Moderate checks text and returns the configured action when it is flagged
+func (m *Moderator) Moderate(ctx context.Context, text string) (*entity.ModerationResult, error) {
+	result := &entity.ModerationResult{Text: text}
+	if !m.enabled {
+		return result, nil
+	}
+
+	if m.filter != nil {
+		result.MatchedTerms = m.filter.Find(text)
+	}
+
+	if m.api != nil {
+		resp, err := m.api.Check(ctx, text)
+		if err != nil {
+			// External moderation is best-effort, word lists still apply
+			ctxzap.Warn(ctx, "moderation API check failed", zap.Error(err))
+		} else if resp.Flagged {
+			result.Categories = resp.Categories
+			result.Flagged = true
+		}
+	}
+
+	if len(result.MatchedTerms) > 0 {
+		result.Flagged = true
+	}
+
+	if !result.Flagged {
+		return result, nil
+	}
+
+	result.Action = m.action
+	if m.action == entity.ModerationActionRedact && m.filter != nil {
+		result.Text = m.filter.Redact(text)
+	}
+
+	return result, nil
+}
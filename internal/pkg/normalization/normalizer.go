@@ -0,0 +1,53 @@
+package normalization
+
+import (
+	"context"
+	"unicode/utf8"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// LLMNormalizer fixes spelling, punctuation and ASR artifacts with the LLM service
+type LLMNormalizer interface {
+	NormalizeTranscript(ctx context.Context, req *entity.LLMNormalizeTranscriptRequest) (string, error)
+}
+
+// Normalizer cleans up voice transcriptions before they are stored
+type Normalizer struct {
+	enabled   bool
+	llm       LLMNormalizer
+	maxLength int
+}
+
+// NewNormalizer creates a normalizer; llm may be nil to apply the rules pass only
+func NewNormalizer(enabled bool, llm LLMNormalizer, maxLength int) *Normalizer {
+	return &Normalizer{
+		enabled:   enabled,
+		llm:       llm,
+		maxLength: maxLength,
+	}
+}
+
+// Normalize returns the cleaned up transcription. The rules pass always applies; the LLM pass
+// only to transcriptions up to maxLength characters and is skipped when the service fails
+func (n *Normalizer) Normalize(ctx context.Context, text string) string {
+	if !n.enabled {
+		return text
+	}
+
+	normalized := Cleanup(text)
+	if n.llm == nil || utf8.RuneCountInString(normalized) > n.maxLength {
+		return normalized
+	}
+
+	result, err := n.llm.NormalizeTranscript(ctx, &entity.LLMNormalizeTranscriptRequest{Text: normalized})
+	if err != nil {
+		// Normalization is best-effort, the rules result is still an improvement
+		ctxzap.Warn(ctx, "LLM transcript normalization failed", zap.Error(err))
+		return normalized
+	}
+
+	return result
+}
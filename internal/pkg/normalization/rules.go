@@ -0,0 +1,93 @@
+package normalization
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+const trailingPunctuation = ",.!?;:…"
+
+var (
+	// fillerPattern matches hesitation sounds that ASR writes out as words
+	fillerPattern           = regexp.MustCompile(`^(э+|э+-э+|эм+|м{2,}|хм+|u+h+|u+m+|er+m+|h+m+)$`)
+	spaceBeforePunctPattern = regexp.MustCompile(`\s+([,.!?;:…])`)
+	spaceAfterCommaPattern  = regexp.MustCompile(`([,;:])(\pL)`)
+)
+
+// Cleanup applies the local normalization rules to a transcription: hesitation sounds and
+// immediately repeated words are dropped, whitespace and punctuation spacing are fixed and
+// sentences start with a capital letter
+func Cleanup(text string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		lines[i] = cleanupLine(line)
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
+
+func cleanupLine(line string) string {
+	tokens := strings.Fields(line)
+	kept := make([]string, 0, len(tokens))
+	previous := ""
+
+	for _, token := range tokens {
+		word := strings.TrimRight(token, trailingPunctuation)
+		punct := token[len(word):]
+		core := strings.ToLower(word)
+
+		if core != "" && fillerPattern.MatchString(core) {
+			// Keep a sentence end the filler carried
+			if strings.ContainsAny(punct, ".!?…") && len(kept) > 0 {
+				kept[len(kept)-1] = strings.TrimRight(kept[len(kept)-1], trailingPunctuation) + punct
+			}
+			continue
+		}
+
+		// "я я думаю" -> "я думаю"; repeats separated by punctuation are kept
+		if core != "" && core == previous {
+			kept[len(kept)-1] = strings.TrimRight(kept[len(kept)-1], trailingPunctuation) + punct
+			if punct != "" {
+				previous = ""
+			}
+			continue
+		}
+
+		kept = append(kept, token)
+		previous = core
+		if punct != "" {
+			// A punctuation mark ends the repeat check
+			previous = ""
+		}
+	}
+
+	cleaned := spaceBeforePunctPattern.ReplaceAllString(strings.Join(kept, " "), "$1")
+	cleaned = spaceAfterCommaPattern.ReplaceAllString(cleaned, "$1 $2")
+	return capitalizeSentences(cleaned)
+}
+
+// capitalizeSentences uppercases the first letter of the line and of every sentence in it;
+// a sentence end must be followed by a space, so "т.е." is left as is
+func capitalizeSentences(line string) string {
+	runes := []rune(line)
+	sentenceStart := true
+	sentenceEnd := false
+	for i, r := range runes {
+		switch {
+		case r == '.' || r == '!' || r == '?' || r == '…':
+			sentenceEnd = true
+		case unicode.IsSpace(r):
+			if sentenceEnd {
+				sentenceStart = true
+			}
+			sentenceEnd = false
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if sentenceStart && unicode.IsLetter(r) {
+				runes[i] = unicode.ToUpper(r)
+			}
+			sentenceStart = false
+			sentenceEnd = false
+		}
+	}
+	return string(runes)
+}
@@ -0,0 +1,76 @@
+package stream
+
+import (
+	"sync"
+	"time"
+
+	"github.com/futig/agent-backend/internal/entity"
+)
+
+// Config sets the limits of session progress streams
+type Config struct {
+	PollInterval time.Duration `env:"POLL_INTERVAL" envDefault:"2s"` // how often a stream checks the status of its session
+	MaxDuration  time.Duration `env:"MAX_DURATION" envDefault:"1h"`  // streams are closed after this time, clients reconnect
+	Buffer       int           `env:"BUFFER" envDefault:"32"`        // events kept for a slow stream before they are dropped
+}
+
+// Hub fans out the progress events of sessions to the streams subscribed to them. Events are
+// published in the process running the workflow, so a stream only receives the events of
+// workflows of its own replica; status changes are found by the streams themselves
+type Hub struct {
+	buffer int
+
+	mu          sync.Mutex
+	subscribers map[string]map[chan entity.StreamEvent]struct{} // session ID -> streams
+}
+
+// NewHub creates a new hub; every subscriber keeps up to buffer undelivered events
+func NewHub(buffer int) *Hub {
+	return &Hub{
+		buffer:      buffer,
+		subscribers: make(map[string]map[chan entity.StreamEvent]struct{}),
+	}
+}
+
+// Subscribe returns the events of the session and the function ending the subscription
+func (h *Hub) Subscribe(sessionID string) (<-chan entity.StreamEvent, func()) {
+	events := make(chan entity.StreamEvent, h.buffer)
+
+	h.mu.Lock()
+	if h.subscribers[sessionID] == nil {
+		h.subscribers[sessionID] = make(map[chan entity.StreamEvent]struct{})
+	}
+	h.subscribers[sessionID][events] = struct{}{}
+	h.mu.Unlock()
+
+	var once sync.Once
+	return events, func() {
+		once.Do(func() {
+			h.mu.Lock()
+			defer h.mu.Unlock()
+
+			delete(h.subscribers[sessionID], events)
+			if len(h.subscribers[sessionID]) == 0 {
+				delete(h.subscribers, sessionID)
+			}
+		})
+	}
+}
+
+// Publish sends the event to the streams of its session without waiting; a stream whose buffer
+// is full misses the event
+func (h *Hub) Publish(event entity.StreamEvent) {
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now().UTC()
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for events := range h.subscribers[event.SessionID] {
+		select {
+		case events <- event:
+		default:
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package textdiff
+
+// maxCells bounds the table of the longest common subsequence; longer inputs are reported as
+// replaced as a whole instead of aligned
+const maxCells = 4_000_000
+
+// Op tells what happened to an item of the compared sequences
+type Op int
+
+const (
+	// Equal items are in both sequences
+	Equal Op = iota
+	// Delete items are only in the first sequence
+	Delete
+	// Insert items are only in the second sequence
+	Insert
+)
+
+// Edit is one step of turning the first sequence into the second; AIndex and BIndex are the
+// positions of the item in the sequences, -1 for the sequence it is not in
+type Edit struct {
+	Op     Op
+	AIndex int
+	BIndex int
+}
+
+// Diff aligns two sequences by their longest common subsequence and returns the edits turning a
+// into b; deletions come before the insertions that replace them
+func Diff(a, b []string) []Edit {
+	// Common prefix and suffix are aligned without the table
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	edits := make([]Edit, 0, len(a)+len(b))
+	for i := 0; i < prefix; i++ {
+		edits = append(edits, Edit{Op: Equal, AIndex: i, BIndex: i})
+	}
+	edits = append(edits, diffMiddle(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix], prefix, prefix)...)
+	for i := suffix; i > 0; i-- {
+		edits = append(edits, Edit{Op: Equal, AIndex: len(a) - i, BIndex: len(b) - i})
+	}
+
+	return edits
+}
+
+// diffMiddle aligns the parts of the sequences between their common prefix and suffix;
+// aOffset and bOffset map the positions back to the whole sequences
+func diffMiddle(a, b []string, aOffset, bOffset int) []Edit {
+	n, m := len(a), len(b)
+	if n*m > maxCells {
+		edits := make([]Edit, 0, n+m)
+		for i := range a {
+			edits = append(edits, Edit{Op: Delete, AIndex: aOffset + i, BIndex: -1})
+		}
+		for j := range b {
+			edits = append(edits, Edit{Op: Insert, AIndex: -1, BIndex: bOffset + j})
+		}
+		return edits
+	}
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, n+1)
+	for i := range lcs {
+		lcs[i] = make([]int, m+1)
+	}
+	for i := n - 1; i >= 0; i-- {
+		for j := m - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	edits := make([]Edit, 0, n+m)
+	i, j := 0, 0
+	for i < n || j < m {
+		switch {
+		case i < n && j < m && a[i] == b[j]:
+			edits = append(edits, Edit{Op: Equal, AIndex: aOffset + i, BIndex: bOffset + j})
+			i++
+			j++
+		case j == m || (i < n && lcs[i+1][j] >= lcs[i][j+1]):
+			edits = append(edits, Edit{Op: Delete, AIndex: aOffset + i, BIndex: -1})
+			i++
+		default:
+			edits = append(edits, Edit{Op: Insert, AIndex: -1, BIndex: bOffset + j})
+			j++
+		}
+	}
+
+	return edits
+}
@@ -0,0 +1,121 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// instrumentationName is the tracer name of all spans of the backend
+const instrumentationName = "github.com/futig/agent-backend"
+
+// Config sets where spans are exported; an empty endpoint keeps tracing off while trace
+// context is still propagated from incoming to outgoing requests
+type Config struct {
+	Endpoint      string            `env:"ENDPOINT"`                                // OTLP/HTTP collector, e.g. http://otel-collector:4318
+	Headers       map[string]string `env:"HEADERS" envKeyValSeparator:"="`          // sent with every export, e.g. api-key=secret
+	ServiceName   string            `env:"SERVICE_NAME" envDefault:"agent-backend"` // service.name of the exported spans
+	SampleRatio   float64           `env:"SAMPLE_RATIO" envDefault:"1"`             // share of new traces recorded; sampled parents are always followed
+	ExportTimeout time.Duration     `env:"EXPORT_TIMEOUT" envDefault:"10s"`
+}
+
+var provider *sdktrace.TracerProvider
+
+// Setup installs the trace context propagator and, with an endpoint configured, a tracer provider
+// exporting the spans of the component (api or telegram-bot) in batches over OTLP/HTTP
+func Setup(ctx context.Context, cfg Config, component string, logger *zap.Logger) error {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	if cfg.Endpoint == "" {
+		return nil
+	}
+
+	exporter, err := otlptracehttp.New(ctx,
+		otlptracehttp.WithEndpointURL(strings.TrimSuffix(cfg.Endpoint, "/")+"/v1/traces"),
+		otlptracehttp.WithHeaders(cfg.Headers),
+		otlptracehttp.WithTimeout(cfg.ExportTimeout),
+	)
+	if err != nil {
+		return fmt.Errorf("create trace exporter: %w", err)
+	}
+
+	res := resource.NewSchemaless(
+		attribute.String("service.name", cfg.ServiceName),
+		attribute.String("service.component", component),
+	)
+	provider = sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		logger.Warn("Tracing error", zap.Error(err))
+	}))
+
+	logger.Info("Tracing enabled",
+		zap.String("endpoint", cfg.Endpoint),
+		zap.Float64("sample_ratio", cfg.SampleRatio),
+	)
+	return nil
+}
+
+// Shutdown exports the spans still buffered; a no-op when tracing is off
+func Shutdown(ctx context.Context) error {
+	if provider == nil {
+		return nil
+	}
+	return provider.Shutdown(ctx)
+}
+
+// Start starts an internal span as a child of the span in ctx
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// StartKind starts a span of the kind, e.g. a server span for an incoming request
+func StartKind(ctx context.Context, kind trace.SpanKind, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, trace.WithSpanKind(kind), trace.WithAttributes(attrs...))
+}
+
+// Fail marks the span as failed with err
+func Fail(span trace.Span, err error) {
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// Extract returns ctx with the trace context of the incoming request headers
+func Extract(ctx context.Context, header http.Header) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.HeaderCarrier(header))
+}
+
+// TraceID returns the trace ID of the span in ctx, empty without a recorded trace
+func TraceID(ctx context.Context) string {
+	spanCtx := trace.SpanContextFromContext(ctx)
+	if !spanCtx.HasTraceID() {
+		return ""
+	}
+	return spanCtx.TraceID().String()
+}
+
+// SessionID is the attribute of the interview session a span works on
+func SessionID(id string) attribute.KeyValue {
+	return attribute.String("session.id", id)
+}
+
+// ProjectID is the attribute of the project a span works on
+func ProjectID(id string) attribute.KeyValue {
+	return attribute.String("project.id", id)
+}
@@ -0,0 +1,16 @@
+package validator
+
+import (
+	"fmt"
+
+	"github.com/futig/agent-backend/internal/entity"
+)
+
+// ValidateSetFeatureFlag validates SetFeatureFlagRequest
+func (v *Validator) ValidateSetFeatureFlag(req *entity.SetFeatureFlagRequest) error {
+	if req.RolloutPercent != nil && (*req.RolloutPercent < 0 || *req.RolloutPercent > 100) {
+		return fmt.Errorf("%w: rollout_percent must be between 0 and 100", entity.ErrInvalidParameter)
+	}
+
+	return nil
+}
@@ -1,6 +1,7 @@
 package validator
 
 import (
+	"context"
 	"fmt"
 	"mime/multipart"
 	"path/filepath"
@@ -8,6 +9,7 @@ import (
 
 	"github.com/futig/agent-backend/internal/config"
 	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/pkg/cron"
 )
 
 var AllowedExtensions = map[string]bool{
@@ -25,7 +27,7 @@ func NewFileValidator(cfg config.FileUploadConfig) *Validator {
 	return &Validator{cfg: cfg}
 }
 
-func (v *Validator) ValidateCreateProject(req *entity.CreateProjectRequest) error {
+func (v *Validator) ValidateCreateProject(ctx context.Context, req *entity.CreateProjectRequest) error {
 	if req.Title == "" {
 		return fmt.Errorf("%w: title", entity.ErrMissingField)
 	}
@@ -39,17 +41,33 @@ func (v *Validator) ValidateCreateProject(req *entity.CreateProjectRequest) erro
 		return fmt.Errorf("%w: files", entity.ErrMissingField)
 	}
 
-	return v.ValidateUpload(req.Files)
+	return v.ValidateUpload(ctx, req.Files)
 }
 
-// ValidateUpload validates multiple file uploads
-func (v *Validator) ValidateUpload(files []*multipart.FileHeader) error {
+// ValidateCreateSchedule validates a project check-in schedule
+func (v *Validator) ValidateCreateSchedule(req *entity.CreateScheduleRequest) error {
+	if req.CronExpr == "" {
+		return fmt.Errorf("%w: cron", entity.ErrMissingField)
+	}
+	if _, err := cron.Parse(req.CronExpr); err != nil {
+		return fmt.Errorf("%w: cron: %v", entity.ErrInvalidParameter, err)
+	}
+	if req.TelegramUserID <= 0 {
+		return fmt.Errorf("%w: telegram_user_id", entity.ErrMissingField)
+	}
+
+	return nil
+}
+
+// ValidateUpload validates multiple file uploads against the limits of the tenant
+func (v *Validator) ValidateUpload(ctx context.Context, files []*multipart.FileHeader) error {
 	if len(files) == 0 {
 		return entity.ErrMissingField
 	}
 
-	if len(files) > int(v.cfg.MaxFileCount) {
-		return fmt.Errorf("%w: maximum %d files allowed, got %d", entity.ErrTooManyFiles, v.cfg.MaxFileCount, len(files))
+	limits := v.uploadLimits(ctx)
+	if len(files) > limits.MaxFileCount {
+		return fmt.Errorf("%w: maximum %d files allowed, got %d", entity.ErrTooManyFiles, limits.MaxFileCount, len(files))
 	}
 
 	var totalSize int64
@@ -59,20 +77,42 @@ func (v *Validator) ValidateUpload(files []*multipart.FileHeader) error {
 			return fmt.Errorf("%w: %s (allowed: txt, md, docx)", entity.ErrInvalidExtension, ext)
 		}
 
-		if fh.Size > v.cfg.MaxFileSize {
-			return fmt.Errorf("%w: file '%s' is %d bytes (max %d)", entity.ErrFileTooLarge, fh.Filename, fh.Size, v.cfg.MaxFileSize)
+		if fh.Size > limits.MaxFileSize {
+			return fmt.Errorf("%w: file '%s' is %d bytes (max %d)", entity.ErrFileTooLarge, fh.Filename, fh.Size, limits.MaxFileSize)
 		}
 
 		totalSize += fh.Size
 	}
 
-	if totalSize > v.cfg.MaxTotalSize {
-		return fmt.Errorf("%w: total size is %d bytes (max %d)", entity.ErrTotalSizeTooLarge, totalSize, v.cfg.MaxTotalSize)
+	if totalSize > limits.MaxTotalSize {
+		return fmt.Errorf("%w: total size is %d bytes (max %d)", entity.ErrTotalSizeTooLarge, totalSize, limits.MaxTotalSize)
 	}
 
 	return nil
 }
 
+// uploadLimits returns the upload limits with the overrides of the tenant ctx is scoped to
+func (v *Validator) uploadLimits(ctx context.Context) config.FileUploadConfig {
+	limits := v.cfg
+
+	tenant := entity.TenantFromContext(ctx)
+	if tenant == nil {
+		return limits
+	}
+
+	if tenant.Settings.MaxFileSize > 0 {
+		limits.MaxFileSize = tenant.Settings.MaxFileSize
+	}
+	if tenant.Settings.MaxTotalSize > 0 {
+		limits.MaxTotalSize = tenant.Settings.MaxTotalSize
+	}
+	if tenant.Settings.MaxFileCount > 0 {
+		limits.MaxFileCount = tenant.Settings.MaxFileCount
+	}
+
+	return limits
+}
+
 // SanitizeFilename sanitizes a filename for safe storage
 func SanitizeFilename(filename string) string {
 	filename = filepath.Base(filename)
@@ -4,21 +4,25 @@ import (
 	"fmt"
 	"mime/multipart"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"unicode/utf8"
 
 	"github.com/futig/agent-backend/internal/entity"
 )
 
+// maxTimeBudgetMinutes caps the interview time budget at one working day
+const maxTimeBudgetMinutes = 8 * 60
+
+// maxFeedbackLength caps result feedback, which is sent to the LLM together with the whole document
+const maxFeedbackLength = 4000
+
 // ValidateStartSession validates StartSessionRequest
 func (v *Validator) ValidateStartSession(req *entity.StartSessionRequest) error {
 	if req.UserGoal == "" {
 		return fmt.Errorf("%w: context_goal", entity.ErrMissingField)
 	}
 
-	if req.CallbackURL == "" {
-		return fmt.Errorf("%w: callback_url", entity.ErrMissingField)
-	}
-
 	if (req.ProjectID == nil || *req.ProjectID == "") && len(req.ContextQuestions) == 0 {
 		return fmt.Errorf("project_id and context_questions must not be both empty at the same time")
 	}
@@ -27,26 +31,160 @@ func (v *Validator) ValidateStartSession(req *entity.StartSessionRequest) error
 		return fmt.Errorf("project_id and context_questions must not be both filled at the same time")
 	}
 
+	switch req.SessionType {
+	case "", entity.SessionTypeInterview:
+	case entity.SessionTypeDelta:
+		if req.ProjectID == nil || *req.ProjectID == "" {
+			return fmt.Errorf("%w: project_id", entity.ErrMissingField)
+		}
+	default:
+		return fmt.Errorf("%w: session_type must be one of: INTERVIEW, DELTA", entity.ErrInvalidParameter)
+	}
+
+	if req.TimeBudgetMin < 0 || req.TimeBudgetMin > maxTimeBudgetMinutes {
+		return fmt.Errorf("%w: time_budget_minutes must be between 0 and %d", entity.ErrInvalidParameter, maxTimeBudgetMinutes)
+	}
+
+	switch req.CallbackGranularity {
+	case "", entity.CallbackGranularityIteration, entity.CallbackGranularityQuestion, entity.CallbackGranularityFinal:
+	default:
+		return fmt.Errorf("%w: callback_granularity must be one of: iteration, question, final", entity.ErrInvalidParameter)
+	}
+
+	return nil
+}
+
+// ValidateAsyncDelivery ensures the result of an async request can be delivered to the
+// callback URL or polled by request ID
+func (v *Validator) ValidateAsyncDelivery(requestID, callbackURL string) error {
+	if requestID == "" && callbackURL == "" {
+		return fmt.Errorf("%w: callback_url or X-Request-ID header", entity.ErrMissingField)
+	}
+
 	return nil
 }
 
 // ValidateSubmitAnswer validates answer submission
 func (v *Validator) ValidateSubmitAnswer(req *entity.SubmitAnswerRequest) error {
-	if req.CallbackURL == "" {
-		return fmt.Errorf("%w: callback_url", entity.ErrMissingField)
-	}
 	if !req.IsSkipped && req.Answer == "" {
 		return fmt.Errorf("%w: answers", entity.ErrMissingField)
 	}
 
+	if req.SkipReason != "" {
+		if !req.IsSkipped {
+			return fmt.Errorf("%w: skip_reason is allowed only for skipped answers", entity.ErrInvalidParameter)
+		}
+		if !req.SkipReason.IsValid() {
+			return fmt.Errorf("%w: skip_reason must be one of: DONT_KNOW, NOT_RELEVANT, ANSWER_LATER", entity.ErrInvalidParameter)
+		}
+	}
+
 	return nil
 }
 
-// ValidateSubmitAudioAnswer validates audio answer submission
-func (v *Validator) ValidateSubmitAudioAnswer(req *entity.SubmitAudioAnswerRequest) error {
-	if req.CallbackURL == "" {
+// ValidateCreateComment validates reviewer comment creation
+func (v *Validator) ValidateCreateComment(req *entity.CreateCommentRequest) error {
+	if strings.TrimSpace(req.Text) == "" {
+		return fmt.Errorf("%w: text", entity.ErrMissingField)
+	}
+
+	if req.SectionIndex != nil && *req.SectionIndex < 0 {
+		return fmt.Errorf("%w: section_index must be non-negative", entity.ErrInvalidParameter)
+	}
+
+	if len(req.RequirementID) > 64 {
+		return fmt.Errorf("%w: requirement_id is too long", entity.ErrInvalidParameter)
+	}
+
+	if len(req.Author) > 255 {
+		return fmt.Errorf("%w: author is too long", entity.ErrInvalidParameter)
+	}
+
+	return nil
+}
+
+// ValidateResultFeedback validates result feedback
+func (v *Validator) ValidateResultFeedback(req *entity.ResultFeedbackRequest) error {
+	if strings.TrimSpace(req.Feedback) == "" {
+		return fmt.Errorf("%w: feedback", entity.ErrMissingField)
+	}
+
+	if utf8.RuneCountInString(req.Feedback) > maxFeedbackLength {
+		return fmt.Errorf("%w: feedback must be at most %d characters", entity.ErrInvalidParameter, maxFeedbackLength)
+	}
+
+	return nil
+}
+
+// ValidateResolveConflict validates conflict resolution
+func (v *Validator) ValidateResolveConflict(req *entity.ResolveConflictRequest) error {
+	if req.Resolution == "" {
+		return fmt.Errorf("%w: resolution", entity.ErrMissingField)
+	}
+
+	if !req.Resolution.IsValid() {
+		return fmt.Errorf("%w: resolution must be one of: keep_new, keep_previous", entity.ErrInvalidParameter)
+	}
+
+	return nil
+}
+
+// ValidateSubmitReview validates review submission
+func (v *Validator) ValidateSubmitReview(req *entity.SubmitReviewRequest) error {
+	if len(req.Approvers) == 0 {
+		return fmt.Errorf("%w: approvers", entity.ErrMissingField)
+	}
+
+	hasAPIApprover := false
+	for _, a := range req.Approvers {
+		if err := validateApprover(a); err != nil {
+			return err
+		}
+		if a.Type == entity.ApproverTypeAPI {
+			hasAPIApprover = true
+		}
+	}
+
+	if hasAPIApprover && req.CallbackURL == "" {
 		return fmt.Errorf("%w: callback_url", entity.ErrMissingField)
 	}
+
+	return nil
+}
+
+// ValidateReviewDecision validates approver decision
+func (v *Validator) ValidateReviewDecision(req *entity.ReviewDecisionRequest) error {
+	if err := validateApprover(req.Approver); err != nil {
+		return err
+	}
+
+	if req.Decision != entity.ReviewDecisionApprove && req.Decision != entity.ReviewDecisionReject {
+		return fmt.Errorf("%w: decision must be one of: approve, reject", entity.ErrInvalidParameter)
+	}
+
+	return nil
+}
+
+func validateApprover(approver entity.ResultApprover) error {
+	if !approver.Type.IsValid() {
+		return fmt.Errorf("%w: approver type must be one of: api, telegram", entity.ErrInvalidParameter)
+	}
+
+	if approver.ID == "" || len(approver.ID) > 255 {
+		return fmt.Errorf("%w: approver id", entity.ErrInvalidParameter)
+	}
+
+	if approver.Type == entity.ApproverTypeTelegram {
+		if _, err := strconv.ParseInt(approver.ID, 10, 64); err != nil {
+			return fmt.Errorf("%w: telegram approver id must be a user ID", entity.ErrInvalidParameter)
+		}
+	}
+
+	return nil
+}
+
+// ValidateSubmitAudioAnswer validates audio answer submission
+func (v *Validator) ValidateSubmitAudioAnswer(req *entity.SubmitAudioAnswerRequest) error {
 	if !req.IsSkipped && req.AudioFile == nil {
 		return fmt.Errorf("%w: audio file", entity.ErrMissingField)
 	}
@@ -86,3 +224,54 @@ func (v *Validator) ValidateAudioFile(file *multipart.FileHeader) error {
 
 	return nil
 }
+
+// transcriptExtensions are the plain-text formats transcripts of one-shot sessions are accepted in
+var transcriptExtensions = map[string]struct{}{
+	".txt": {},
+	".md":  {},
+	".vtt": {},
+	".srt": {},
+}
+
+// ValidateTranscriptSession validates TranscriptSessionRequest
+func (v *Validator) ValidateTranscriptSession(req *entity.TranscriptSessionRequest) error {
+	if req.UserGoal == "" {
+		return fmt.Errorf("%w: user_goal", entity.ErrMissingField)
+	}
+
+	if (req.ProjectID == nil || *req.ProjectID == "") && len(req.ContextQuestions) == 0 {
+		return fmt.Errorf("project_id and context_questions must not be both empty at the same time")
+	}
+
+	if (req.ProjectID != nil && *req.ProjectID != "") && len(req.ContextQuestions) != 0 {
+		return fmt.Errorf("project_id and context_questions must not be both filled at the same time")
+	}
+
+	if strings.TrimSpace(req.Transcript) == "" {
+		return fmt.Errorf("%w: transcript", entity.ErrMissingField)
+	}
+
+	if int64(len(req.Transcript)) > v.cfg.MaxTranscriptSize {
+		return fmt.Errorf("%w: transcript is %d bytes (max %d)", entity.ErrFileTooLarge, len(req.Transcript), v.cfg.MaxTranscriptSize)
+	}
+
+	if !utf8.ValidString(req.Transcript) {
+		return fmt.Errorf("%w: transcript must be UTF-8 text", entity.ErrInvalidFormat)
+	}
+
+	return nil
+}
+
+// ValidateTranscriptFile validates transcript uploads (plain text formats only)
+func (v *Validator) ValidateTranscriptFile(file *multipart.FileHeader) error {
+	ext := strings.ToLower(filepath.Ext(file.Filename))
+	if _, ok := transcriptExtensions[ext]; !ok {
+		return fmt.Errorf("%w: %s (allowed: txt, md, vtt, srt)", entity.ErrInvalidExtension, ext)
+	}
+
+	if file.Size > v.cfg.MaxTranscriptSize {
+		return fmt.Errorf("%w: file '%s' is %d bytes (max %d)", entity.ErrFileTooLarge, file.Filename, file.Size, v.cfg.MaxTranscriptSize)
+	}
+
+	return nil
+}
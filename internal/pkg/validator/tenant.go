@@ -0,0 +1,60 @@
+package validator
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/google/uuid"
+)
+
+// tenantIDPattern keeps tenant IDs short slugs that are safe in headers and logs
+var tenantIDPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{1,63}$`)
+
+// ValidateCreateTenant validates CreateTenantRequest
+func (v *Validator) ValidateCreateTenant(req *entity.CreateTenantRequest) error {
+	if req.ID == "" {
+		return fmt.Errorf("%w: id", entity.ErrMissingField)
+	}
+	if !tenantIDPattern.MatchString(req.ID) {
+		return fmt.Errorf("%w: id must be 2-64 lowercase letters, digits, '-' or '_'", entity.ErrInvalidParameter)
+	}
+	if req.Name == "" {
+		return fmt.Errorf("%w: name", entity.ErrMissingField)
+	}
+
+	return v.ValidateTenantSettings(&req.Settings)
+}
+
+// ValidateCreateUser validates CreateUserRequest
+func (v *Validator) ValidateCreateUser(req *entity.CreateUserRequest) error {
+	if strings.TrimSpace(req.Name) == "" {
+		return fmt.Errorf("%w: name", entity.ErrMissingField)
+	}
+	if req.TelegramUserID != nil && *req.TelegramUserID <= 0 {
+		return fmt.Errorf("%w: telegram_user_id must be positive", entity.ErrInvalidParameter)
+	}
+
+	return nil
+}
+
+// ValidateTenantSettings validates the configuration overrides of a tenant
+func (v *Validator) ValidateTenantSettings(settings *entity.TenantSettings) error {
+	if settings.MaxFileSize < 0 || settings.MaxTotalSize < 0 || settings.MaxFileCount < 0 {
+		return fmt.Errorf("%w: upload limits must not be negative", entity.ErrInvalidParameter)
+	}
+	if settings.ResultFormat != "" && !settings.ResultFormat.IsValid() {
+		return fmt.Errorf("%w: result_format must be one of: markdown, docx, pdf", entity.ErrInvalidParameter)
+	}
+	if settings.ThemeID != "" {
+		if _, err := uuid.Parse(settings.ThemeID); err != nil {
+			return fmt.Errorf("%w: theme_id must be a UUID", entity.ErrInvalidParameter)
+		}
+	}
+	if settings.ContentRetentionDays < 0 {
+		return fmt.Errorf("%w: content_retention_days must not be negative", entity.ErrInvalidParameter)
+	}
+
+	return nil
+}
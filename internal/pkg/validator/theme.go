@@ -0,0 +1,67 @@
+package validator
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/google/uuid"
+)
+
+const (
+	maxThemeLogoSize = 1 << 20 // 1 MB
+	maxThemeFontSize = 5 << 20 // 5 MB
+	maxThemeTextLen  = 200
+)
+
+var themeColorPattern = regexp.MustCompile(`^#[0-9A-Fa-f]{6}$`)
+
+// themeLogoTypes are the logo formats supported by both the PDF and the DOCX renderer
+var themeLogoTypes = map[string]bool{
+	"image/png":  true,
+	"image/jpeg": true,
+}
+
+// ValidateCreateTheme validates CreateThemeRequest including its asset files
+func (v *Validator) ValidateCreateTheme(req *entity.CreateThemeRequest) error {
+	if req.Name == "" {
+		return fmt.Errorf("%w: name", entity.ErrMissingField)
+	}
+	if req.PrimaryColor != "" && !themeColorPattern.MatchString(req.PrimaryColor) {
+		return fmt.Errorf("%w: primary_color must be a #RRGGBB color", entity.ErrInvalidParameter)
+	}
+	if len([]rune(req.HeaderText)) > maxThemeTextLen || len([]rune(req.FooterText)) > maxThemeTextLen {
+		return fmt.Errorf("%w: header and footer texts are limited to %d characters", entity.ErrInvalidParameter, maxThemeTextLen)
+	}
+
+	if req.Logo != nil {
+		if !themeLogoTypes[req.Logo.ContentType] {
+			return fmt.Errorf("%w: logo must be a PNG or JPEG image", entity.ErrInvalidFile)
+		}
+		if len(req.Logo.Data) > maxThemeLogoSize {
+			return fmt.Errorf("%w: logo exceeds %d bytes", entity.ErrFileTooLarge, maxThemeLogoSize)
+		}
+	}
+	if req.Font != nil {
+		if req.Font.ContentType != "font/ttf" {
+			return fmt.Errorf("%w: font must be a TrueType (.ttf) file", entity.ErrInvalidFile)
+		}
+		if len(req.Font.Data) > maxThemeFontSize {
+			return fmt.Errorf("%w: font exceeds %d bytes", entity.ErrFileTooLarge, maxThemeFontSize)
+		}
+	}
+
+	return nil
+}
+
+// ValidateSetProjectTheme validates SetProjectThemeRequest
+func (v *Validator) ValidateSetProjectTheme(req *entity.SetProjectThemeRequest) error {
+	if req.ThemeID == nil {
+		return nil
+	}
+	if _, err := uuid.Parse(*req.ThemeID); err != nil {
+		return fmt.Errorf("%w: theme_id must be a UUID", entity.ErrInvalidParameter)
+	}
+
+	return nil
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/repository/sqlc"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// AccountLinkRepository defines the interface for links of API clients to Telegram users persistence
+type AccountLinkRepository interface {
+	CreateCode(ctx context.Context, code string, telegramUserID int64, ttl time.Duration) (*entity.AccountLinkCode, error)
+	RedeemCode(ctx context.Context, code string) (*entity.AccountLinkCode, error)
+	SaveLink(ctx context.Context, clientID string, telegramUserID int64) error
+	GetLinkedSession(ctx context.Context, clientID string) (*entity.LinkedSession, error)
+}
+
+var _ AccountLinkRepository = &AccountLinkPostgres{}
+
+// AccountLinkPostgres implements AccountLinkRepository using PostgreSQL
+type AccountLinkPostgres struct {
+	db      *pgxpool.Pool
+	queries *sqlc.Queries
+}
+
+func NewAccountLinkPostgres(db *pgxpool.Pool) *AccountLinkPostgres {
+	return &AccountLinkPostgres{
+		db:      db,
+		queries: sqlc.New(db),
+	}
+}
+
+func (r *AccountLinkPostgres) CreateCode(ctx context.Context, code string, telegramUserID int64, ttl time.Duration) (*entity.AccountLinkCode, error) {
+	dbCode, err := r.queries.CreateAccountLinkCode(ctx, sqlc.CreateAccountLinkCodeParams{
+		TenantID:       entity.TenantIDFromContext(ctx),
+		Code:           code,
+		TelegramUserID: telegramUserID,
+		TtlSeconds:     int32(ttl.Seconds()),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create account link code: %w", err)
+	}
+
+	return toEntityAccountLinkCode(&dbCode), nil
+}
+
+// RedeemCode uses up the code; unknown, expired and already used codes are ErrLinkCodeInvalid
+func (r *AccountLinkPostgres) RedeemCode(ctx context.Context, code string) (*entity.AccountLinkCode, error) {
+	dbCode, err := r.queries.RedeemAccountLinkCode(ctx, sqlc.RedeemAccountLinkCodeParams{
+		TenantID: entity.TenantIDFromContext(ctx),
+		Code:     code,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, entity.ErrLinkCodeInvalid
+		}
+		return nil, fmt.Errorf("redeem account link code: %w", err)
+	}
+
+	return toEntityAccountLinkCode(&dbCode), nil
+}
+
+// SaveLink binds the client to the Telegram user, replacing an earlier link of the client
+func (r *AccountLinkPostgres) SaveLink(ctx context.Context, clientID string, telegramUserID int64) error {
+	_, err := r.queries.UpsertAccountLink(ctx, sqlc.UpsertAccountLinkParams{
+		TenantID:       entity.TenantIDFromContext(ctx),
+		ClientID:       clientID,
+		TelegramUserID: telegramUserID,
+	})
+	if err != nil {
+		return fmt.Errorf("upsert account link: %w", err)
+	}
+
+	return nil
+}
+
+// GetLinkedSession returns the Telegram user the client is linked to with the user's current session
+func (r *AccountLinkPostgres) GetLinkedSession(ctx context.Context, clientID string) (*entity.LinkedSession, error) {
+	row, err := r.queries.GetAccountLinkSession(ctx, sqlc.GetAccountLinkSessionParams{
+		TenantID: entity.TenantIDFromContext(ctx),
+		ClientID: clientID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, entity.ErrAccountNotLinked
+		}
+		return nil, fmt.Errorf("get account link session: %w", err)
+	}
+
+	linked := &entity.LinkedSession{TelegramUserID: row.TelegramUserID}
+	if row.SessionID.Valid {
+		linked.SessionID = uuid.UUID(row.SessionID.Bytes).String()
+	}
+
+	return linked, nil
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/repository/sqlc"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// AnalyticsRepository defines the interface for reading the question-answer statistics of the tenant in ctx
+type AnalyticsRepository interface {
+	// ListAnswerStats returns the questions of the sessions created in [from, to), at most limit
+	ListAnswerStats(ctx context.Context, from, to time.Time, limit int) ([]*entity.AnswerStats, error)
+}
+
+var _ AnalyticsRepository = &AnalyticsPostgres{}
+
+// AnalyticsPostgres implements AnalyticsRepository using PostgreSQL
+type AnalyticsPostgres struct {
+	db      *pgxpool.Pool
+	queries *sqlc.Queries
+}
+
+func NewAnalyticsPostgres(db *pgxpool.Pool) *AnalyticsPostgres {
+	return &AnalyticsPostgres{
+		db:      db,
+		queries: sqlc.New(db),
+	}
+}
+
+func (r *AnalyticsPostgres) ListAnswerStats(ctx context.Context, from, to time.Time, limit int) ([]*entity.AnswerStats, error) {
+	rows, err := r.queries.ListAnalyticsAnswers(ctx, sqlc.ListAnalyticsAnswersParams{
+		TenantID: entity.TenantIDFromContext(ctx),
+		FromTime: pgtype.Timestamp{Time: from, Valid: true},
+		ToTime:   pgtype.Timestamp{Time: to, Valid: true},
+		MaxRows:  int32(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list analytics answers: %w", err)
+	}
+
+	stats := make([]*entity.AnswerStats, 0, len(rows))
+	for i := range rows {
+		stats = append(stats, toEntityAnswerStats(&rows[i]))
+	}
+
+	return stats, nil
+}
+
+func toEntityAnswerStats(row *sqlc.ListAnalyticsAnswersRow) *entity.AnswerStats {
+	stats := &entity.AnswerStats{
+		SessionID:        uuid.UUID(row.SessionID.Bytes).String(),
+		SessionType:      entity.SessionType(row.SessionType.String),
+		SessionStatus:    entity.SessionStatus(row.SessionStatus),
+		HasProject:       row.HasProject,
+		SessionCreatedAt: row.SessionCreatedAt.Time,
+		IterationNumber:  int(row.IterationNumber),
+		Additional:       row.Additional,
+		QuestionNumber:   int(row.QuestionNumber),
+		Status:           entity.QuestionStatus(row.Status),
+		AnswerType:       row.AnswerType,
+		FollowUp:         row.FollowUp,
+		QuestionChars:    int(row.QuestionChars),
+		AnswerChars:      int(row.AnswerChars),
+		AnswerWords:      int(row.AnswerWords),
+		Normalized:       row.Normalized,
+		CreatedAt:        row.CreatedAt.Time,
+	}
+
+	if row.OwnerID.Valid {
+		ownerID := uuid.UUID(row.OwnerID.Bytes).String()
+		stats.OwnerID = &ownerID
+	}
+	if row.SkipReason.Valid {
+		reason := entity.SkipReason(row.SkipReason.String)
+		stats.SkipReason = &reason
+	}
+	if row.AnsweredAt.Valid {
+		answeredAt := row.AnsweredAt.Time
+		stats.AnsweredAt = &answeredAt
+	}
+
+	return stats
+}
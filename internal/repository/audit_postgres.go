@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/repository/sqlc"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// AuditRepository defines the interface for audit log persistence
+type AuditRepository interface {
+	RecordEvent(ctx context.Context, event *entity.AuditEvent) error
+}
+
+var _ AuditRepository = &AuditPostgres{}
+
+// AuditPostgres implements AuditRepository using PostgreSQL
+type AuditPostgres struct {
+	db      *pgxpool.Pool
+	queries *sqlc.Queries
+}
+
+func NewAuditPostgres(db *pgxpool.Pool) *AuditPostgres {
+	return &AuditPostgres{
+		db:      db,
+		queries: sqlc.New(db),
+	}
+}
+
+func (r *AuditPostgres) RecordEvent(ctx context.Context, event *entity.AuditEvent) error {
+	var sessionID pgtype.UUID
+	if event.SessionID != "" {
+		sessID, err := uuid.Parse(event.SessionID)
+		if err != nil {
+			return fmt.Errorf("invalid session ID: %w", err)
+		}
+		sessionID = pgtype.UUID{Bytes: sessID, Valid: true}
+	}
+
+	details := event.Details
+	if details == nil {
+		details = map[string]any{}
+	}
+
+	detailsJSON, err := json.Marshal(details)
+	if err != nil {
+		return fmt.Errorf("marshal audit details: %w", err)
+	}
+
+	if _, err := r.queries.CreateAuditEvent(ctx, sqlc.CreateAuditEventParams{
+		SessionID: sessionID,
+		EventType: string(event.Type),
+		Details:   detailsJSON,
+	}); err != nil {
+		return fmt.Errorf("create audit event: %w", err)
+	}
+
+	return nil
+}
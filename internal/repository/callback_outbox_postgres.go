@@ -0,0 +1,165 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/repository/sqlc"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// CallbackOutboxRepository defines the interface for persistence of callback events awaiting delivery
+type CallbackOutboxRepository interface {
+	CreateCallbackDelivery(ctx context.Context, delivery *entity.CallbackDelivery, lease time.Duration) (*entity.CallbackDelivery, error)
+	ClaimDueCallbackDeliveries(ctx context.Context, lease time.Duration, limit int) ([]*entity.CallbackDelivery, error)
+	MarkCallbackDelivered(ctx context.Context, id string) error
+	ScheduleCallbackRetry(ctx context.Context, id string, delay time.Duration, lastError string) error
+	MarkCallbackFailed(ctx context.Context, id string, lastError string) error
+	ListCallbackDeliveries(ctx context.Context, requestID string) ([]*entity.CallbackDelivery, error)
+	DeleteSettledCallbackDeliveriesBefore(ctx context.Context, before time.Time) (int, error)
+}
+
+var _ CallbackOutboxRepository = &CallbackOutboxPostgres{}
+
+// CallbackOutboxPostgres implements CallbackOutboxRepository using PostgreSQL
+type CallbackOutboxPostgres struct {
+	db      *pgxpool.Pool
+	queries *sqlc.Queries
+}
+
+func NewCallbackOutboxPostgres(db *pgxpool.Pool) *CallbackOutboxPostgres {
+	return &CallbackOutboxPostgres{
+		db:      db,
+		queries: sqlc.New(db),
+	}
+}
+
+// CreateCallbackDelivery stores an event in the tenant ctx is scoped to as being attempted right away;
+// the event is claimed by the dispatcher once the lease ends unless it was settled before
+func (r *CallbackOutboxPostgres) CreateCallbackDelivery(
+	ctx context.Context,
+	delivery *entity.CallbackDelivery,
+	lease time.Duration,
+) (*entity.CallbackDelivery, error) {
+	row, err := r.queries.CreateCallbackDelivery(ctx, sqlc.CreateCallbackDeliveryParams{
+		TenantID:     entity.TenantIDFromContext(ctx),
+		RequestID:    delivery.RequestID,
+		CallbackUrl:  delivery.CallbackURL,
+		Event:        string(delivery.Event),
+		Payload:      delivery.Payload,
+		LeaseSeconds: int32(lease.Seconds()),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create callback delivery: %w", err)
+	}
+
+	return toEntityCallbackDelivery(&row), nil
+}
+
+// ClaimDueCallbackDeliveries leases up to limit due events of all tenants, counting the attempt
+// about to be made; events still pending once the lease ends are claimed again
+func (r *CallbackOutboxPostgres) ClaimDueCallbackDeliveries(
+	ctx context.Context,
+	lease time.Duration,
+	limit int,
+) ([]*entity.CallbackDelivery, error) {
+	rows, err := r.queries.ClaimDueCallbackDeliveries(ctx, sqlc.ClaimDueCallbackDeliveriesParams{
+		LeaseSeconds: int32(lease.Seconds()),
+		BatchSize:    int32(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("claim due callback deliveries: %w", err)
+	}
+
+	deliveries := make([]*entity.CallbackDelivery, 0, len(rows))
+	for i := range rows {
+		deliveries = append(deliveries, toEntityCallbackDelivery(&rows[i]))
+	}
+
+	return deliveries, nil
+}
+
+func (r *CallbackOutboxPostgres) MarkCallbackDelivered(ctx context.Context, id string) error {
+	deliveryID, err := uuid.Parse(id)
+	if err != nil {
+		return fmt.Errorf("invalid callback delivery ID: %w", err)
+	}
+
+	if err := r.queries.MarkCallbackDelivered(ctx, pgtype.UUID{Bytes: deliveryID, Valid: true}); err != nil {
+		return fmt.Errorf("mark callback delivered: %w", err)
+	}
+
+	return nil
+}
+
+// ScheduleCallbackRetry sets the next attempt of a pending event delay from now
+func (r *CallbackOutboxPostgres) ScheduleCallbackRetry(
+	ctx context.Context,
+	id string,
+	delay time.Duration,
+	lastError string,
+) error {
+	deliveryID, err := uuid.Parse(id)
+	if err != nil {
+		return fmt.Errorf("invalid callback delivery ID: %w", err)
+	}
+
+	if err := r.queries.ScheduleCallbackRetry(ctx, sqlc.ScheduleCallbackRetryParams{
+		DelaySeconds: int32(delay.Seconds()),
+		LastError:    pgtype.Text{String: lastError, Valid: true},
+		ID:           pgtype.UUID{Bytes: deliveryID, Valid: true},
+	}); err != nil {
+		return fmt.Errorf("schedule callback retry: %w", err)
+	}
+
+	return nil
+}
+
+func (r *CallbackOutboxPostgres) MarkCallbackFailed(ctx context.Context, id string, lastError string) error {
+	deliveryID, err := uuid.Parse(id)
+	if err != nil {
+		return fmt.Errorf("invalid callback delivery ID: %w", err)
+	}
+
+	if err := r.queries.MarkCallbackFailed(ctx, sqlc.MarkCallbackFailedParams{
+		ID:        pgtype.UUID{Bytes: deliveryID, Valid: true},
+		LastError: pgtype.Text{String: lastError, Valid: true},
+	}); err != nil {
+		return fmt.Errorf("mark callback failed: %w", err)
+	}
+
+	return nil
+}
+
+// ListCallbackDeliveries returns the events sent for a request in the tenant ctx is scoped to, oldest first
+func (r *CallbackOutboxPostgres) ListCallbackDeliveries(ctx context.Context, requestID string) ([]*entity.CallbackDelivery, error) {
+	rows, err := r.queries.ListCallbackDeliveries(ctx, sqlc.ListCallbackDeliveriesParams{
+		TenantID:  entity.TenantIDFromContext(ctx),
+		RequestID: requestID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list callback deliveries: %w", err)
+	}
+
+	deliveries := make([]*entity.CallbackDelivery, 0, len(rows))
+	for i := range rows {
+		deliveries = append(deliveries, toEntityCallbackDelivery(&rows[i]))
+	}
+
+	return deliveries, nil
+}
+
+// DeleteSettledCallbackDeliveriesBefore removes delivered and failed events not updated since before
+// and returns how many were removed; pending events are kept until they are settled
+func (r *CallbackOutboxPostgres) DeleteSettledCallbackDeliveriesBefore(ctx context.Context, before time.Time) (int, error) {
+	deleted, err := r.queries.DeleteSettledCallbackDeliveriesBefore(ctx, pgtype.Timestamp{Time: before, Valid: true})
+	if err != nil {
+		return 0, fmt.Errorf("delete settled callback deliveries: %w", err)
+	}
+
+	return int(deleted), nil
+}
@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/repository/sqlc"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// CommentRepository defines the interface for reviewer comments persistence
+type CommentRepository interface {
+	CreateComment(ctx context.Context, comment *entity.SessionComment) (*entity.SessionComment, error)
+	ListComments(ctx context.Context, sessionID string, unresolvedOnly bool) ([]*entity.SessionComment, error)
+	ResolveComment(ctx context.Context, sessionID, commentID string) (*entity.SessionComment, error)
+	ResolveComments(ctx context.Context, sessionID string, commentIDs []string) error
+}
+
+var _ CommentRepository = &CommentPostgres{}
+
+// CommentPostgres implements CommentRepository using PostgreSQL
+type CommentPostgres struct {
+	db      *pgxpool.Pool
+	queries *sqlc.Queries
+}
+
+func NewCommentPostgres(db *pgxpool.Pool) *CommentPostgres {
+	return &CommentPostgres{
+		db:      db,
+		queries: sqlc.New(db),
+	}
+}
+
+func (r *CommentPostgres) CreateComment(ctx context.Context, comment *entity.SessionComment) (*entity.SessionComment, error) {
+	sessID, err := uuid.Parse(comment.SessionID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid session ID: %w", err)
+	}
+
+	params := sqlc.CreateSessionCommentParams{
+		SessionID: pgtype.UUID{Bytes: sessID, Valid: true},
+		Author:    comment.Author,
+		Text:      comment.Text,
+	}
+
+	if comment.SectionIndex != nil {
+		params.SectionIndex = pgtype.Int4{Int32: int32(*comment.SectionIndex), Valid: true}
+	}
+
+	if comment.RequirementID != nil {
+		params.RequirementID = pgtype.Text{String: *comment.RequirementID, Valid: true}
+	}
+
+	dbComment, err := r.queries.CreateSessionComment(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("create session comment: %w", err)
+	}
+
+	return toEntitySessionComment(&dbComment), nil
+}
+
+func (r *CommentPostgres) ListComments(
+	ctx context.Context,
+	sessionID string,
+	unresolvedOnly bool,
+) ([]*entity.SessionComment, error) {
+	sessID, err := uuid.Parse(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid session ID: %w", err)
+	}
+	pgSessionID := pgtype.UUID{Bytes: sessID, Valid: true}
+
+	var dbComments []sqlc.SessionComment
+	if unresolvedOnly {
+		dbComments, err = r.queries.ListUnresolvedSessionComments(ctx, pgSessionID)
+	} else {
+		dbComments, err = r.queries.ListSessionComments(ctx, pgSessionID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("list session comments: %w", err)
+	}
+
+	comments := make([]*entity.SessionComment, 0, len(dbComments))
+	for i := range dbComments {
+		comments = append(comments, toEntitySessionComment(&dbComments[i]))
+	}
+
+	return comments, nil
+}
+
+func (r *CommentPostgres) ResolveComment(ctx context.Context, sessionID, commentID string) (*entity.SessionComment, error) {
+	sessID, err := uuid.Parse(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid session ID: %w", err)
+	}
+
+	commID, err := uuid.Parse(commentID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid comment ID: %w", entity.ErrInvalidParameter)
+	}
+
+	dbComment, err := r.queries.ResolveSessionComment(ctx, sqlc.ResolveSessionCommentParams{
+		ID:        pgtype.UUID{Bytes: commID, Valid: true},
+		SessionID: pgtype.UUID{Bytes: sessID, Valid: true},
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, entity.ErrCommentNotFound
+		}
+		return nil, fmt.Errorf("resolve session comment: %w", err)
+	}
+
+	return toEntitySessionComment(&dbComment), nil
+}
+
+func (r *CommentPostgres) ResolveComments(ctx context.Context, sessionID string, commentIDs []string) error {
+	sessID, err := uuid.Parse(sessionID)
+	if err != nil {
+		return fmt.Errorf("invalid session ID: %w", err)
+	}
+
+	ids := make([]pgtype.UUID, 0, len(commentIDs))
+	for _, commentID := range commentIDs {
+		commID, err := uuid.Parse(commentID)
+		if err != nil {
+			return fmt.Errorf("invalid comment ID: %w", err)
+		}
+		ids = append(ids, pgtype.UUID{Bytes: commID, Valid: true})
+	}
+
+	if err := r.queries.ResolveSessionComments(ctx, sqlc.ResolveSessionCommentsParams{
+		SessionID: pgtype.UUID{Bytes: sessID, Valid: true},
+		Ids:       ids,
+	}); err != nil {
+		return fmt.Errorf("resolve session comments: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/futig/agent-backend/internal/repository/sqlc"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	// compressionThreshold is the text size in bytes above which draft messages
+	// and project contexts are stored compressed
+	compressionThreshold = 16 * 1024
+	compressionBatchSize = 100
+)
+
+// The encoder and decoder are safe for concurrent EncodeAll and DecodeAll calls
+var (
+	textEncoder = newTextEncoder()
+	textDecoder = newTextDecoder()
+)
+
+// newTextEncoder creates the zstd encoder of stored texts; it only fails on invalid options
+func newTextEncoder() *zstd.Encoder {
+	encoder, err := zstd.NewWriter(nil)
+	if err != nil {
+		panic(fmt.Sprintf("create zstd encoder: %v", err))
+	}
+	return encoder
+}
+
+// newTextDecoder creates the zstd decoder of stored texts; it only fails on invalid options
+func newTextDecoder() *zstd.Decoder {
+	decoder, err := zstd.NewReader(nil)
+	if err != nil {
+		panic(fmt.Sprintf("create zstd decoder: %v", err))
+	}
+	return decoder
+}
+
+// compressText compresses a text above the threshold with zstd; nil means the text is stored
+// as is, either because it is short or because it does not shrink
+func compressText(text string) []byte {
+	if len(text) <= compressionThreshold {
+		return nil
+	}
+
+	data := textEncoder.EncodeAll([]byte(text), nil)
+	if len(data) >= len(text) {
+		return nil
+	}
+	return data
+}
+
+// storedText returns the compressed text when it is set and the plain one otherwise
+func storedText(plain string, compressed []byte) (string, error) {
+	if compressed == nil {
+		return plain, nil
+	}
+
+	data, err := textDecoder.DecodeAll(compressed, nil)
+	if err != nil {
+		return "", fmt.Errorf("decompress text: %w", err)
+	}
+	return string(data), nil
+}
+
+// CompressStoredTexts compresses draft messages and project contexts above the threshold
+// that were stored before compression was introduced; it returns the number of compressed rows.
+// It is a one-off backfill run by `migrate compress-texts`
+func CompressStoredTexts(ctx context.Context, db *pgxpool.Pool) (int, error) {
+	queries := sqlc.New(db)
+	compressed := 0
+
+	// The zero UUID sorts before every id, so paging starts from it
+	afterID := pgtype.UUID{Valid: true}
+	for {
+		rows, err := queries.ListUncompressedSessionMessages(ctx, sqlc.ListUncompressedSessionMessagesParams{
+			MinSize:   compressionThreshold,
+			AfterID:   afterID,
+			BatchSize: compressionBatchSize,
+		})
+		if err != nil {
+			return compressed, fmt.Errorf("list uncompressed session messages: %w", err)
+		}
+
+		for _, row := range rows {
+			data := compressText(row.MessageText)
+			if data == nil {
+				continue
+			}
+			if err := queries.SetSessionMessageCompressed(ctx, sqlc.SetSessionMessageCompressedParams{
+				ID:                    row.ID,
+				MessageTextCompressed: data,
+			}); err != nil {
+				return compressed, fmt.Errorf("compress session message: %w", err)
+			}
+			compressed++
+		}
+
+		if len(rows) < compressionBatchSize {
+			break
+		}
+		afterID = rows[len(rows)-1].ID
+	}
+
+	afterID = pgtype.UUID{Valid: true}
+	for {
+		rows, err := queries.ListUncompressedSessionContexts(ctx, sqlc.ListUncompressedSessionContextsParams{
+			MinSize:   compressionThreshold,
+			AfterID:   afterID,
+			BatchSize: compressionBatchSize,
+		})
+		if err != nil {
+			return compressed, fmt.Errorf("list uncompressed project contexts: %w", err)
+		}
+
+		for _, row := range rows {
+			data := compressText(row.ProjectContext)
+			if data == nil {
+				continue
+			}
+			if err := queries.SetSessionProjectContextCompressed(ctx, sqlc.SetSessionProjectContextCompressedParams{
+				ID:                       row.ID,
+				ProjectContextCompressed: data,
+			}); err != nil {
+				return compressed, fmt.Errorf("compress project context: %w", err)
+			}
+			compressed++
+		}
+
+		if len(rows) < compressionBatchSize {
+			break
+		}
+		afterID = rows[len(rows)-1].ID
+	}
+
+	return compressed, nil
+}
@@ -0,0 +1,154 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/repository/sqlc"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ConflictRepository defines the interface for requirements conflicts persistence
+type ConflictRepository interface {
+	ReplaceConflicts(ctx context.Context, sessionID string, conflicts []*entity.RequirementConflict) ([]*entity.RequirementConflict, error)
+	ListConflicts(ctx context.Context, sessionID string) ([]*entity.RequirementConflict, error)
+	CountUnresolvedConflicts(ctx context.Context, sessionID string) (int, error)
+	ResolveConflict(
+		ctx context.Context,
+		sessionID, conflictID string,
+		resolution entity.ConflictResolution,
+	) (*entity.RequirementConflict, error)
+}
+
+var _ ConflictRepository = &ConflictPostgres{}
+
+// ConflictPostgres implements ConflictRepository using PostgreSQL
+type ConflictPostgres struct {
+	db      *pgxpool.Pool
+	queries *sqlc.Queries
+}
+
+func NewConflictPostgres(db *pgxpool.Pool) *ConflictPostgres {
+	return &ConflictPostgres{
+		db:      db,
+		queries: sqlc.New(db),
+	}
+}
+
+// ReplaceConflicts atomically replaces all conflicts detected for a session result
+func (r *ConflictPostgres) ReplaceConflicts(
+	ctx context.Context,
+	sessionID string,
+	conflicts []*entity.RequirementConflict,
+) ([]*entity.RequirementConflict, error) {
+	sessID, err := uuid.Parse(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid session ID: %w", err)
+	}
+	pgSessionID := pgtype.UUID{Bytes: sessID, Valid: true}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	q := r.queries.WithTx(tx)
+
+	if err := q.DeleteSessionConflicts(ctx, pgSessionID); err != nil {
+		return nil, fmt.Errorf("delete session conflicts: %w", err)
+	}
+
+	saved := make([]*entity.RequirementConflict, 0, len(conflicts))
+	for i, conflict := range conflicts {
+		params := sqlc.CreateSessionConflictParams{
+			SessionID:   pgSessionID,
+			Position:    int32(i),
+			Requirement: conflict.Requirement,
+			Previous:    conflict.Previous,
+		}
+		if conflict.Source != nil {
+			params.Source = pgtype.Text{String: *conflict.Source, Valid: true}
+		}
+
+		dbConflict, err := q.CreateSessionConflict(ctx, params)
+		if err != nil {
+			return nil, fmt.Errorf("save session conflict %d: %w", i, err)
+		}
+		saved = append(saved, toEntityRequirementConflict(&dbConflict))
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return saved, nil
+}
+
+func (r *ConflictPostgres) ListConflicts(ctx context.Context, sessionID string) ([]*entity.RequirementConflict, error) {
+	sessID, err := uuid.Parse(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid session ID: %w", err)
+	}
+
+	dbConflicts, err := r.queries.ListSessionConflicts(ctx, pgtype.UUID{Bytes: sessID, Valid: true})
+	if err != nil {
+		return nil, fmt.Errorf("list session conflicts: %w", err)
+	}
+
+	conflicts := make([]*entity.RequirementConflict, 0, len(dbConflicts))
+	for i := range dbConflicts {
+		conflicts = append(conflicts, toEntityRequirementConflict(&dbConflicts[i]))
+	}
+
+	return conflicts, nil
+}
+
+func (r *ConflictPostgres) CountUnresolvedConflicts(ctx context.Context, sessionID string) (int, error) {
+	sessID, err := uuid.Parse(sessionID)
+	if err != nil {
+		return 0, fmt.Errorf("invalid session ID: %w", err)
+	}
+
+	count, err := r.queries.CountUnresolvedSessionConflicts(ctx, pgtype.UUID{Bytes: sessID, Valid: true})
+	if err != nil {
+		return 0, fmt.Errorf("count unresolved session conflicts: %w", err)
+	}
+
+	return int(count), nil
+}
+
+func (r *ConflictPostgres) ResolveConflict(
+	ctx context.Context,
+	sessionID, conflictID string,
+	resolution entity.ConflictResolution,
+) (*entity.RequirementConflict, error) {
+	sessID, err := uuid.Parse(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid session ID: %w", err)
+	}
+
+	confID, err := uuid.Parse(conflictID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid conflict ID: %w", entity.ErrInvalidParameter)
+	}
+
+	dbConflict, err := r.queries.ResolveSessionConflict(ctx, sqlc.ResolveSessionConflictParams{
+		ID:         pgtype.UUID{Bytes: confID, Valid: true},
+		SessionID:  pgtype.UUID{Bytes: sessID, Valid: true},
+		Resolution: pgtype.Text{String: string(resolution), Valid: true},
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, entity.ErrConflictNotFound
+		}
+		return nil, fmt.Errorf("resolve session conflict: %w", err)
+	}
+
+	return toEntityRequirementConflict(&dbConflict), nil
+}
@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/repository/sqlc"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ConversationLogRepository defines the interface for the ordered interview log persistence
+type ConversationLogRepository interface {
+	AppendEntry(ctx context.Context, sessionID, questionID string, kind entity.ConversationEntryKind, content string) error
+	ListRecentEntries(ctx context.Context, sessionID string, limit int) ([]entity.ConversationEntry, error)
+}
+
+var _ ConversationLogRepository = &ConversationLogPostgres{}
+
+// ConversationLogPostgres implements ConversationLogRepository using PostgreSQL
+type ConversationLogPostgres struct {
+	db      *pgxpool.Pool
+	queries *sqlc.Queries
+}
+
+func NewConversationLogPostgres(db *pgxpool.Pool) *ConversationLogPostgres {
+	return &ConversationLogPostgres{
+		db:      db,
+		queries: sqlc.New(db),
+	}
+}
+
+// AppendEntry logs what happened to a question; the question text is taken from the question itself
+func (r *ConversationLogPostgres) AppendEntry(
+	ctx context.Context,
+	sessionID, questionID string,
+	kind entity.ConversationEntryKind,
+	content string,
+) error {
+	sessID, err := uuid.Parse(sessionID)
+	if err != nil {
+		return fmt.Errorf("invalid session ID: %w", err)
+	}
+
+	qID, err := uuid.Parse(questionID)
+	if err != nil {
+		return fmt.Errorf("invalid question ID: %w", err)
+	}
+
+	if err := r.queries.AppendConversationEntry(ctx, sqlc.AppendConversationEntryParams{
+		SessionID: pgtype.UUID{
+			Bytes: sessID,
+			Valid: true,
+		},
+		Kind:    string(kind),
+		Content: content,
+		QuestionID: pgtype.UUID{
+			Bytes: qID,
+			Valid: true,
+		},
+	}); err != nil {
+		return fmt.Errorf("append conversation entry: %w", err)
+	}
+
+	return nil
+}
+
+// ListRecentEntries returns up to limit latest entries of the session, oldest first
+func (r *ConversationLogPostgres) ListRecentEntries(
+	ctx context.Context,
+	sessionID string,
+	limit int,
+) ([]entity.ConversationEntry, error) {
+	sessID, err := uuid.Parse(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid session ID: %w", err)
+	}
+
+	rows, err := r.queries.ListRecentConversationEntries(ctx, sqlc.ListRecentConversationEntriesParams{
+		SessionID: pgtype.UUID{
+			Bytes: sessID,
+			Valid: true,
+		},
+		MaxEntries: int32(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list conversation entries: %w", err)
+	}
+
+	entries := make([]entity.ConversationEntry, 0, len(rows))
+	for i := range rows {
+		entries = append(entries, toEntityConversationEntry(&rows[i]))
+	}
+
+	return entries, nil
+}
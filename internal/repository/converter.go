@@ -1,27 +1,90 @@
 package repository
 
 import (
+	"encoding/json"
+	"fmt"
+	"time"
+
 	"github.com/futig/agent-backend/internal/entity"
 	"github.com/futig/agent-backend/internal/repository/sqlc"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 func toEntityProject(dbProject *sqlc.Project) *entity.Project {
 	projectUUID := uuid.UUID(dbProject.ID.Bytes)
 
-	return &entity.Project{
+	project := &entity.Project{
 		ID:          projectUUID.String(),
 		Title:       dbProject.Title,
 		Description: dbProject.Description.String,
 		CreatedAt:   dbProject.CreatedAt.Time,
 	}
+
+	if dbProject.ThemeID.Valid {
+		themeID := uuid.UUID(dbProject.ThemeID.Bytes).String()
+		project.ThemeID = &themeID
+	}
+
+	if dbProject.OwnerID.Valid {
+		ownerID := uuid.UUID(dbProject.OwnerID.Bytes).String()
+		project.OwnerID = &ownerID
+	}
+
+	return project
+}
+
+// toEntityProjectWithUsage converts a listed project; the last use is the later of the user's
+// pick in the selector and the last session of the project
+func toEntityProjectWithUsage(row *sqlc.ListProjectsRow) *entity.Project {
+	project := toEntityProject(&sqlc.Project{
+		ID:          row.ID,
+		Title:       row.Title,
+		Description: row.Description,
+		CreatedAt:   row.CreatedAt,
+		TenantID:    row.TenantID,
+		ThemeID:     row.ThemeID,
+	})
+	project.SessionCount = int(row.SessionCount)
+
+	if row.LastIndexedAt.Valid {
+		lastIndexedAt := row.LastIndexedAt.Time
+		project.LastIndexedAt = &lastIndexedAt
+	}
+
+	for _, ts := range []pgtype.Timestamp{row.LastSessionAt, row.LastUsedAt} {
+		if ts.Valid && (project.LastUsedAt == nil || ts.Time.After(*project.LastUsedAt)) {
+			lastUsedAt := ts.Time
+			project.LastUsedAt = &lastUsedAt
+		}
+	}
+
+	return project
+}
+
+func toEntityProjectFreshness(row *sqlc.GetProjectFreshnessRow) *entity.ProjectFreshness {
+	freshness := &entity.ProjectFreshness{
+		FileCount: int(row.FileCount),
+	}
+
+	if row.LastIndexedAt.Valid {
+		lastIndexedAt := row.LastIndexedAt.Time
+		freshness.LastIndexedAt = &lastIndexedAt
+	}
+
+	if row.OldestFileAt.Valid {
+		oldestFileAt := row.OldestFileAt.Time
+		freshness.OldestFileAt = &oldestFileAt
+	}
+
+	return freshness
 }
 
 func toEntityFile(dbFile *sqlc.ProjectFile) *entity.File {
 	fileUUID := uuid.UUID(dbFile.ID.Bytes)
 	projectUUID := uuid.UUID(dbFile.ProjectID.Bytes)
 
-	return &entity.File{
+	file := &entity.File{
 		ID:          fileUUID.String(),
 		ProjectID:   projectUUID.String(),
 		Filename:    dbFile.Filename,
@@ -29,17 +92,56 @@ func toEntityFile(dbFile *sqlc.ProjectFile) *entity.File {
 		ContentType: dbFile.ContentType,
 		CreatedAt:   dbFile.CreatedAt.Time,
 	}
+	if dbFile.ObjectKey.Valid {
+		objectKey := dbFile.ObjectKey.String
+		file.ObjectKey = &objectKey
+	}
+
+	return file
 }
 
-func toEntitySession(dbSession *sqlc.Session) *entity.Session {
+func toEntitySession(dbSession *sqlc.Session) (*entity.Session, error) {
 	sessionUUID := uuid.UUID(dbSession.ID.Bytes)
 
 	session := &entity.Session{
-		ID:               sessionUUID.String(),
-		Status:           entity.SessionStatus(dbSession.Status),
-		CurrentIteration: int(dbSession.CurrentIteration),
-		CreatedAt:        dbSession.CreatedAt.Time,
-		UpdatedAt:        dbSession.UpdatedAt.Time,
+		ID:                  sessionUUID.String(),
+		Status:              entity.SessionStatus(dbSession.Status),
+		CurrentIteration:    int(dbSession.CurrentIteration),
+		IsDemo:              dbSession.IsDemo,
+		LanguageExplicit:    dbSession.LanguageExplicit,
+		CallbackGranularity: entity.CallbackGranularity(dbSession.CallbackGranularity),
+		CreatedAt:           dbSession.CreatedAt.Time,
+		UpdatedAt:           dbSession.UpdatedAt.Time,
+	}
+
+	if dbSession.LastActivityAt.Valid {
+		lastActivityAt := dbSession.LastActivityAt.Time
+		session.LastActivityAt = &lastActivityAt
+	}
+
+	if dbSession.CurrentQuestionID.Valid {
+		questionID := uuid.UUID(dbSession.CurrentQuestionID.Bytes).String()
+		session.CurrentQuestionID = &questionID
+	}
+
+	if dbSession.OwnerID.Valid {
+		ownerID := uuid.UUID(dbSession.OwnerID.Bytes).String()
+		session.OwnerID = &ownerID
+	}
+
+	if dbSession.SummaryStyle.Valid {
+		style := entity.SummaryStyle(dbSession.SummaryStyle.String)
+		session.SummaryStyle = &style
+	}
+
+	if dbSession.PurgedAt.Valid {
+		purgedAt := dbSession.PurgedAt.Time
+		session.PurgedAt = &purgedAt
+	}
+
+	if dbSession.Language.Valid {
+		language := entity.ResultLanguage(dbSession.Language.String)
+		session.Language = &language
 	}
 
 	if dbSession.ProjectID.Valid {
@@ -58,8 +160,11 @@ func toEntitySession(dbSession *sqlc.Session) *entity.Session {
 		session.UserGoal = &userGoal
 	}
 
-	if dbSession.ProjectContext.Valid {
-		projectContext := dbSession.ProjectContext.String
+	if dbSession.ProjectContext.Valid || dbSession.ProjectContextCompressed != nil {
+		projectContext, err := storedText(dbSession.ProjectContext.String, dbSession.ProjectContextCompressed)
+		if err != nil {
+			return nil, fmt.Errorf("project context: %w", err)
+		}
 		session.ProjectContext = &projectContext
 	}
 
@@ -73,7 +178,7 @@ func toEntitySession(dbSession *sqlc.Session) *entity.Session {
 		session.Error = &errorMsg
 	}
 
-	return session
+	return session, nil
 }
 
 func toEntityIteration(dbIter *sqlc.SessionIteration) *entity.Iteration {
@@ -102,12 +207,35 @@ func toEntityQuestion(dbQuestion *sqlc.IterationQuestion) *entity.Question {
 		Status:         entity.QuestionStatus(dbQuestion.Status),
 		Question:       dbQuestion.Question,
 		Explanation:    dbQuestion.Explanation,
+		Options:        dbQuestion.Options,
+		AnswerType:     entity.QuestionAnswerType(dbQuestion.AnswerType),
 		CreatedAt:      dbQuestion.CreatedAt.Time,
 	}
 
+	if dbQuestion.ParentQuestionID.Valid {
+		parentID := uuid.UUID(dbQuestion.ParentQuestionID.Bytes).String()
+		question.ParentQuestionID = &parentID
+	}
+
 	if dbQuestion.Answer.Valid {
 		answer := dbQuestion.Answer.String
 		question.Answer = &answer
+
+		if question.AnswerType == entity.QuestionAnswerTypeScale {
+			if value, ok := entity.ParseScaleAnswer(answer); ok {
+				question.ScaleValue = &value
+			}
+		}
+	}
+
+	if dbQuestion.RawAnswer.Valid {
+		rawAnswer := dbQuestion.RawAnswer.String
+		question.RawAnswer = &rawAnswer
+	}
+
+	if dbQuestion.SkipReason.Valid {
+		skipReason := entity.SkipReason(dbQuestion.SkipReason.String)
+		question.SkipReason = &skipReason
 	}
 
 	if dbQuestion.AnsweredAt.Valid {
@@ -118,14 +246,524 @@ func toEntityQuestion(dbQuestion *sqlc.IterationQuestion) *entity.Question {
 	return question
 }
 
-func toEntitySessionMessage(dbMsg *sqlc.SessionMessage) *entity.SessionMessage {
+func toEntitySessionMessage(dbMsg *sqlc.SessionMessage) (*entity.SessionMessage, error) {
 	msgUUID := uuid.UUID(dbMsg.ID.Bytes)
 	sessionUUID := uuid.UUID(dbMsg.SessionID.Bytes)
 
-	return &entity.SessionMessage{
+	messageText, err := storedText(dbMsg.MessageText, dbMsg.MessageTextCompressed)
+	if err != nil {
+		return nil, fmt.Errorf("message %s: %w", msgUUID, err)
+	}
+
+	message := &entity.SessionMessage{
 		ID:          msgUUID.String(),
 		SessionID:   sessionUUID.String(),
-		MessageText: dbMsg.MessageText,
+		MessageText: messageText,
 		CreatedAt:   dbMsg.CreatedAt.Time,
 	}
+
+	if dbMsg.RawMessageText.Valid {
+		rawMessageText := dbMsg.RawMessageText.String
+		message.RawMessageText = &rawMessageText
+	}
+
+	return message, nil
+}
+
+func toEntityConversationEntry(row *sqlc.SessionConversationLog) entity.ConversationEntry {
+	return entity.ConversationEntry{
+		Kind:      entity.ConversationEntryKind(row.Kind),
+		Question:  row.Question,
+		Content:   row.Content,
+		CreatedAt: row.CreatedAt.Time,
+	}
+}
+
+func toEntityPendingVoiceAnswer(row *sqlc.PendingVoiceAnswer) *entity.PendingVoiceAnswer {
+	return &entity.PendingVoiceAnswer{
+		ID:             uuid.UUID(row.ID.Bytes).String(),
+		TenantID:       row.TenantID,
+		SessionID:      uuid.UUID(row.SessionID.Bytes).String(),
+		QuestionID:     uuid.UUID(row.QuestionID.Bytes).String(),
+		TelegramUserID: row.TelegramUserID,
+		Audio:          row.Audio,
+		CreatedAt:      row.CreatedAt.Time,
+	}
+}
+
+func toEntityCallbackDelivery(row *sqlc.CallbackOutbox) *entity.CallbackDelivery {
+	delivery := &entity.CallbackDelivery{
+		ID:          uuid.UUID(row.ID.Bytes).String(),
+		TenantID:    row.TenantID,
+		RequestID:   row.RequestID,
+		CallbackURL: row.CallbackUrl,
+		Event:       entity.CallbackEventType(row.Event),
+		Payload:     row.Payload,
+		Status:      entity.CallbackDeliveryStatus(row.Status),
+		Attempts:    int(row.Attempts),
+		CreatedAt:   row.CreatedAt.Time,
+		UpdatedAt:   row.UpdatedAt.Time,
+	}
+
+	if delivery.Status == entity.CallbackDeliveryStatusPending {
+		nextAttemptAt := row.NextAttemptAt.Time
+		delivery.NextAttemptAt = &nextAttemptAt
+	}
+
+	if row.LastError.Valid {
+		lastError := row.LastError.String
+		delivery.LastError = &lastError
+	}
+
+	if row.DeliveredAt.Valid {
+		deliveredAt := row.DeliveredAt.Time
+		delivery.DeliveredAt = &deliveredAt
+	}
+
+	return delivery
+}
+
+func toEntityPendingQuestions(row *sqlc.PendingQuestionDelivery) (*entity.PendingQuestions, error) {
+	var questions entity.IterationWithQuestions
+	if err := json.Unmarshal(row.Payload, &questions); err != nil {
+		return nil, fmt.Errorf("decode pending questions: %w", err)
+	}
+
+	return &entity.PendingQuestions{
+		ID:            uuid.UUID(row.ID.Bytes).String(),
+		SessionID:     uuid.UUID(row.SessionID.Bytes).String(),
+		IterationID:   uuid.UUID(row.IterationID.Bytes).String(),
+		RequestID:     row.RequestID,
+		Questions:     &questions,
+		DeliveryError: row.DeliveryError,
+		CreatedAt:     row.CreatedAt.Time,
+	}, nil
+}
+
+func toEntitySessionSearchHit(row *sqlc.SearchSessionContentRow) *entity.SessionSearchHit {
+	hitUUID := uuid.UUID(row.ID.Bytes)
+
+	hit := &entity.SessionSearchHit{
+		Source:    entity.SearchSource(row.Source),
+		ID:        hitUUID.String(),
+		Snippet:   row.Snippet,
+		CreatedAt: row.CreatedAt.Time,
+	}
+
+	if row.Question != "" {
+		question := row.Question
+		hit.Question = &question
+	}
+
+	return hit
+}
+
+func toEntityAdminSearchHit(row *sqlc.SearchAllRow) *entity.AdminSearchHit {
+	hitUUID := uuid.UUID(row.ID.Bytes)
+
+	hit := &entity.AdminSearchHit{
+		Source:    entity.AdminSearchSource(row.Source),
+		ID:        hitUUID.String(),
+		Snippet:   row.Snippet,
+		CreatedAt: row.CreatedAt.Time,
+	}
+
+	if row.ProjectID.Valid {
+		projectID := uuid.UUID(row.ProjectID.Bytes).String()
+		hit.ProjectID = &projectID
+	}
+
+	return hit
+}
+
+func toEntityResultSection(dbSection *sqlc.SessionResultSection) *entity.ResultSection {
+	sessionUUID := uuid.UUID(dbSection.SessionID.Bytes)
+
+	return &entity.ResultSection{
+		SessionID:    sessionUUID.String(),
+		SectionIndex: int(dbSection.SectionIndex),
+		Title:        dbSection.Title,
+		Description:  dbSection.Description,
+		Content:      dbSection.Content,
+		UpdatedAt:    dbSection.UpdatedAt.Time,
+	}
+}
+
+func toEntitySessionComment(dbComment *sqlc.SessionComment) *entity.SessionComment {
+	commentUUID := uuid.UUID(dbComment.ID.Bytes)
+	sessionUUID := uuid.UUID(dbComment.SessionID.Bytes)
+
+	comment := &entity.SessionComment{
+		ID:        commentUUID.String(),
+		SessionID: sessionUUID.String(),
+		Author:    dbComment.Author,
+		Text:      dbComment.Text,
+		CreatedAt: dbComment.CreatedAt.Time,
+	}
+
+	if dbComment.SectionIndex.Valid {
+		sectionIndex := int(dbComment.SectionIndex.Int32)
+		comment.SectionIndex = &sectionIndex
+	}
+
+	if dbComment.RequirementID.Valid {
+		requirementID := dbComment.RequirementID.String
+		comment.RequirementID = &requirementID
+	}
+
+	if dbComment.ResolvedAt.Valid {
+		resolvedAt := dbComment.ResolvedAt.Time
+		comment.Resolved = true
+		comment.ResolvedAt = &resolvedAt
+	}
+
+	return comment
+}
+
+func toEntityRequirementConflict(dbConflict *sqlc.SessionConflict) *entity.RequirementConflict {
+	conflictUUID := uuid.UUID(dbConflict.ID.Bytes)
+	sessionUUID := uuid.UUID(dbConflict.SessionID.Bytes)
+
+	conflict := &entity.RequirementConflict{
+		ID:          conflictUUID.String(),
+		SessionID:   sessionUUID.String(),
+		Requirement: dbConflict.Requirement,
+		Previous:    dbConflict.Previous,
+		CreatedAt:   dbConflict.CreatedAt.Time,
+	}
+
+	if dbConflict.Source.Valid {
+		source := dbConflict.Source.String
+		conflict.Source = &source
+	}
+
+	if dbConflict.Resolution.Valid {
+		resolution := entity.ConflictResolution(dbConflict.Resolution.String)
+		conflict.Resolution = &resolution
+	}
+
+	if dbConflict.ResolvedAt.Valid {
+		resolvedAt := dbConflict.ResolvedAt.Time
+		conflict.ResolvedAt = &resolvedAt
+	}
+
+	return conflict
+}
+
+func toEntityResultReview(dbReview *sqlc.SessionReview, dbApprovers []sqlc.SessionReviewApprover) *entity.ResultReview {
+	sessionUUID := uuid.UUID(dbReview.SessionID.Bytes)
+
+	review := &entity.ResultReview{
+		SessionID: sessionUUID.String(),
+		Status:    entity.ReviewStatus(dbReview.Status),
+		Approvers: make([]entity.ResultApprover, 0, len(dbApprovers)),
+		UpdatedAt: dbReview.UpdatedAt.Time,
+	}
+
+	for _, a := range dbApprovers {
+		review.Approvers = append(review.Approvers, entity.ResultApprover{
+			Type: entity.ApproverType(a.ApproverType),
+			ID:   a.ApproverID,
+		})
+	}
+
+	if dbReview.DecidedByType.Valid && dbReview.DecidedByID.Valid {
+		review.DecidedBy = &entity.ResultApprover{
+			Type: entity.ApproverType(dbReview.DecidedByType.String),
+			ID:   dbReview.DecidedByID.String,
+		}
+	}
+
+	if dbReview.DecisionComment.Valid {
+		comment := dbReview.DecisionComment.String
+		review.DecisionComment = &comment
+	}
+
+	return review
+}
+
+func toEntityProjectSchedule(dbSchedule *sqlc.ProjectSchedule) *entity.ProjectSchedule {
+	scheduleUUID := uuid.UUID(dbSchedule.ID.Bytes)
+	projectUUID := uuid.UUID(dbSchedule.ProjectID.Bytes)
+
+	schedule := &entity.ProjectSchedule{
+		ID:             scheduleUUID.String(),
+		ProjectID:      projectUUID.String(),
+		CronExpr:       dbSchedule.CronExpr,
+		Timezone:       dbSchedule.Timezone,
+		TelegramUserID: dbSchedule.TelegramUserID,
+		UserGoal:       dbSchedule.UserGoal,
+		NextRunAt:      dbSchedule.NextRunAt.Time,
+		CreatedAt:      dbSchedule.CreatedAt.Time,
+	}
+
+	if dbSchedule.LastRunAt.Valid {
+		lastRunAt := dbSchedule.LastRunAt.Time
+		schedule.LastRunAt = &lastRunAt
+	}
+
+	if dbSchedule.LastSessionID.Valid {
+		lastSessionID := uuid.UUID(dbSchedule.LastSessionID.Bytes).String()
+		schedule.LastSessionID = &lastSessionID
+	}
+
+	return schedule
+}
+
+func toEntityOperation(dbOperation *sqlc.Operation) *entity.Operation {
+	operation := &entity.Operation{
+		RequestID: dbOperation.RequestID,
+		Kind:      entity.OperationKind(dbOperation.Kind),
+		Status:    entity.OperationStatus(dbOperation.Status),
+		Result:    dbOperation.Result,
+		CreatedAt: dbOperation.CreatedAt.Time,
+		UpdatedAt: dbOperation.UpdatedAt.Time,
+	}
+
+	if dbOperation.ClientID.Valid {
+		clientID := dbOperation.ClientID.String
+		operation.ClientID = &clientID
+	}
+
+	if dbOperation.SessionID.Valid {
+		sessionID := uuid.UUID(dbOperation.SessionID.Bytes).String()
+		operation.SessionID = &sessionID
+	}
+
+	if dbOperation.Event.Valid {
+		event := entity.CallbackEventType(dbOperation.Event.String)
+		operation.Event = &event
+	}
+
+	return operation
+}
+
+func toEntitySessionDelta(dbDelta *sqlc.SessionDelta) *entity.SessionDelta {
+	sessionUUID := uuid.UUID(dbDelta.SessionID.Bytes)
+
+	delta := &entity.SessionDelta{
+		SessionID: sessionUUID.String(),
+		Baseline:  dbDelta.Baseline,
+		UpdatedAt: dbDelta.UpdatedAt.Time,
+	}
+
+	if dbDelta.BaselineSessionID.Valid {
+		baselineSessionID := uuid.UUID(dbDelta.BaselineSessionID.Bytes).String()
+		delta.BaselineSessionID = &baselineSessionID
+	}
+
+	if dbDelta.ChangeLog.Valid {
+		changeLog := dbDelta.ChangeLog.String
+		delta.ChangeLog = &changeLog
+	}
+
+	return delta
+}
+
+func toEntitySessionTimeBudget(dbBudget *sqlc.SessionTimeBudget) *entity.SessionTimeBudget {
+	budget := &entity.SessionTimeBudget{
+		SessionID: uuid.UUID(dbBudget.SessionID.Bytes).String(),
+		Budget:    time.Duration(dbBudget.BudgetSeconds) * time.Second,
+		StartedAt: dbBudget.StartedAt.Time,
+	}
+
+	if dbBudget.WarnedAt.Valid {
+		warnedAt := dbBudget.WarnedAt.Time
+		budget.WarnedAt = &warnedAt
+	}
+
+	return budget
+}
+
+func toEntitySessionFacts(dbFacts *sqlc.SessionFact) (*entity.SessionFacts, error) {
+	facts := &entity.SessionFacts{
+		SessionID:    uuid.UUID(dbFacts.SessionID.Bytes).String(),
+		MaterialHash: dbFacts.MaterialHash,
+		UpdatedAt:    dbFacts.UpdatedAt.Time,
+	}
+
+	if err := json.Unmarshal(dbFacts.Facts, &facts.Facts); err != nil {
+		return nil, fmt.Errorf("unmarshal session facts: %w", err)
+	}
+
+	return facts, nil
+}
+
+func toEntityAccountLinkCode(dbCode *sqlc.AccountLinkCode) *entity.AccountLinkCode {
+	return &entity.AccountLinkCode{
+		Code:           dbCode.Code,
+		TelegramUserID: dbCode.TelegramUserID,
+		ExpiresAt:      dbCode.ExpiresAt.Time,
+	}
+}
+
+func toEntityResultVersion(dbVersion *sqlc.SessionResultVersion) *entity.ResultVersion {
+	version := &entity.ResultVersion{
+		ID:        uuid.UUID(dbVersion.ID.Bytes).String(),
+		SessionID: uuid.UUID(dbVersion.SessionID.Bytes).String(),
+		Version:   int(dbVersion.Version),
+		Storage:   entity.ResultStorage(dbVersion.Storage),
+		SizeBytes: int(dbVersion.SizeBytes),
+		Checksum:  dbVersion.Checksum,
+		CreatedAt: dbVersion.CreatedAt.Time,
+	}
+
+	if dbVersion.ObjectKey.Valid {
+		objectKey := dbVersion.ObjectKey.String
+		version.ObjectKey = &objectKey
+	}
+
+	if dbVersion.Content.Valid {
+		content := dbVersion.Content.String
+		version.Content = &content
+	}
+	version.Available = resultVersionAvailable(version.Storage, version.ObjectKey != nil, dbVersion.Content.Valid)
+
+	return version
+}
+
+func toEntityResultVersionListRow(row *sqlc.ListSessionResultVersionsRow) *entity.ResultVersion {
+	version := &entity.ResultVersion{
+		ID:        uuid.UUID(row.ID.Bytes).String(),
+		SessionID: uuid.UUID(row.SessionID.Bytes).String(),
+		Version:   int(row.Version),
+		Storage:   entity.ResultStorage(row.Storage),
+		SizeBytes: int(row.SizeBytes),
+		Checksum:  row.Checksum,
+		CreatedAt: row.CreatedAt.Time,
+	}
+
+	if row.ObjectKey.Valid {
+		objectKey := row.ObjectKey.String
+		version.ObjectKey = &objectKey
+	}
+	version.Available = resultVersionAvailable(version.Storage, row.ObjectKey.Valid, row.HasContent)
+
+	return version
+}
+
+// resultVersionAvailable reports whether the body of a version is still stored; inline versions
+// saved before their bodies were kept per version have none
+func resultVersionAvailable(storage entity.ResultStorage, hasObject, hasContent bool) bool {
+	switch storage {
+	case entity.ResultStorageBlob:
+		return hasObject
+	case entity.ResultStorageInline:
+		return hasContent
+	default:
+		return false
+	}
+}
+
+func toEntityTenant(dbTenant *sqlc.Tenant) (*entity.Tenant, error) {
+	tenant := &entity.Tenant{
+		ID:        dbTenant.ID,
+		Name:      dbTenant.Name,
+		CreatedAt: dbTenant.CreatedAt.Time,
+	}
+
+	if len(dbTenant.Settings) > 0 {
+		if err := json.Unmarshal(dbTenant.Settings, &tenant.Settings); err != nil {
+			return nil, fmt.Errorf("unmarshal tenant settings: %w", err)
+		}
+	}
+
+	return tenant, nil
+}
+
+func toEntityUser(dbUser *sqlc.User) *entity.User {
+	user := &entity.User{
+		ID:        uuid.UUID(dbUser.ID.Bytes).String(),
+		TenantID:  dbUser.TenantID,
+		Name:      dbUser.Name,
+		CreatedAt: dbUser.CreatedAt.Time,
+	}
+
+	if dbUser.TelegramUserID.Valid {
+		telegramUserID := dbUser.TelegramUserID.Int64
+		user.TelegramUserID = &telegramUserID
+	}
+
+	return user
+}
+
+func toEntityDocumentTheme(dbTheme *sqlc.DocumentTheme) *entity.DocumentTheme {
+	return &entity.DocumentTheme{
+		ID:           uuid.UUID(dbTheme.ID.Bytes).String(),
+		Name:         dbTheme.Name,
+		PrimaryColor: dbTheme.PrimaryColor.String,
+		HeaderText:   dbTheme.HeaderText.String,
+		FooterText:   dbTheme.FooterText.String,
+		FontName:     dbTheme.FontName.String,
+		LogoKey:      dbTheme.LogoKey.String,
+		FontKey:      dbTheme.FontKey.String,
+		HasLogo:      dbTheme.LogoKey.Valid,
+		HasFont:      dbTheme.FontKey.Valid,
+		CreatedAt:    dbTheme.CreatedAt.Time,
+	}
+}
+
+func toEntityFeatureFlagOverride(dbOverride *sqlc.FeatureFlagOverride) *entity.FeatureFlagOverride {
+	override := &entity.FeatureFlagOverride{
+		Name:      dbOverride.Name,
+		Disabled:  dbOverride.Disabled,
+		UpdatedAt: dbOverride.UpdatedAt.Time,
+	}
+	if dbOverride.RolloutPercent.Valid {
+		percent := int(dbOverride.RolloutPercent.Int32)
+		override.RolloutPercent = &percent
+	}
+
+	return override
+}
+
+func toEntityIncident(dbIncident *sqlc.Incident) (*entity.Incident, error) {
+	incident := &entity.Incident{
+		ID:            uuid.UUID(dbIncident.ID.Bytes).String(),
+		Code:          dbIncident.Code,
+		CorrelationID: dbIncident.CorrelationID,
+		Level:         dbIncident.Level,
+		Message:       dbIncident.Message,
+		CreatedAt:     dbIncident.CreatedAt.Time,
+	}
+
+	if len(dbIncident.Fields) > 0 {
+		if err := json.Unmarshal(dbIncident.Fields, &incident.Fields); err != nil {
+			return nil, fmt.Errorf("unmarshal incident fields: %w", err)
+		}
+	}
+
+	return incident, nil
+}
+
+func toEntityRetentionPurge(dbPurge *sqlc.RetentionPurge) *entity.RetentionPurge {
+	return &entity.RetentionPurge{
+		ID:             uuid.UUID(dbPurge.ID.Bytes).String(),
+		TenantID:       dbPurge.TenantID,
+		Cutoff:         dbPurge.Cutoff.Time,
+		Sessions:       int(dbPurge.Sessions),
+		Questions:      int(dbPurge.Questions),
+		Messages:       int(dbPurge.Messages),
+		ResultVersions: int(dbPurge.ResultVersions),
+		ResultBytes:    dbPurge.ResultBytes,
+		CreatedAt:      dbPurge.CreatedAt.Time,
+	}
+}
+
+func toEntitySessionOperation(dbOperation *sqlc.SessionOperation) *entity.SessionOperation {
+	return &entity.SessionOperation{
+		ID:        uuid.UUID(dbOperation.ID.Bytes).String(),
+		SessionID: uuid.UUID(dbOperation.SessionID.Bytes).String(),
+		Kind:      entity.OperationKind(dbOperation.Operation),
+		Owner:     dbOperation.Owner,
+		StartedAt: dbOperation.StartedAt.Time,
+		ExpiresAt: dbOperation.ExpiresAt.Time,
+	}
+}
+
+func toEntityQuestionExplanation(dbExplanation *sqlc.QuestionExplanation) *entity.QuestionExplanation {
+	return &entity.QuestionExplanation{
+		QuestionID:  uuid.UUID(dbExplanation.QuestionID.Bytes).String(),
+		Explanation: dbExplanation.Explanation,
+	}
 }
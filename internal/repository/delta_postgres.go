@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/repository/sqlc"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// DeltaRepository defines the interface for delta session baselines and change logs persistence
+type DeltaRepository interface {
+	SaveBaseline(ctx context.Context, sessionID string, baselineSessionID *string, baseline string) (*entity.SessionDelta, error)
+	GetDelta(ctx context.Context, sessionID string) (*entity.SessionDelta, error)
+	SaveChangeLog(ctx context.Context, sessionID, changeLog string) (*entity.SessionDelta, error)
+}
+
+var _ DeltaRepository = &DeltaPostgres{}
+
+// DeltaPostgres implements DeltaRepository using PostgreSQL
+type DeltaPostgres struct {
+	db      *pgxpool.Pool
+	queries *sqlc.Queries
+}
+
+func NewDeltaPostgres(db *pgxpool.Pool) *DeltaPostgres {
+	return &DeltaPostgres{
+		db:      db,
+		queries: sqlc.New(db),
+	}
+}
+
+// SaveBaseline sets the baseline document of the session and drops a previously produced change log
+func (r *DeltaPostgres) SaveBaseline(
+	ctx context.Context,
+	sessionID string,
+	baselineSessionID *string,
+	baseline string,
+) (*entity.SessionDelta, error) {
+	sessID, err := uuid.Parse(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid session ID: %w", err)
+	}
+
+	params := sqlc.UpsertSessionDeltaParams{
+		SessionID: pgtype.UUID{Bytes: sessID, Valid: true},
+		Baseline:  baseline,
+	}
+
+	if baselineSessionID != nil {
+		baselineID, err := uuid.Parse(*baselineSessionID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid baseline session ID: %w", err)
+		}
+		params.BaselineSessionID = pgtype.UUID{Bytes: baselineID, Valid: true}
+	}
+
+	dbDelta, err := r.queries.UpsertSessionDelta(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("upsert session delta: %w", err)
+	}
+
+	return toEntitySessionDelta(&dbDelta), nil
+}
+
+func (r *DeltaPostgres) GetDelta(ctx context.Context, sessionID string) (*entity.SessionDelta, error) {
+	sessID, err := uuid.Parse(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid session ID: %w", err)
+	}
+
+	dbDelta, err := r.queries.GetSessionDelta(ctx, pgtype.UUID{Bytes: sessID, Valid: true})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, entity.ErrNoBaseline
+		}
+		return nil, fmt.Errorf("get session delta: %w", err)
+	}
+
+	return toEntitySessionDelta(&dbDelta), nil
+}
+
+func (r *DeltaPostgres) SaveChangeLog(ctx context.Context, sessionID, changeLog string) (*entity.SessionDelta, error) {
+	sessID, err := uuid.Parse(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid session ID: %w", err)
+	}
+
+	dbDelta, err := r.queries.UpdateSessionDeltaChangeLog(ctx, sqlc.UpdateSessionDeltaChangeLogParams{
+		SessionID: pgtype.UUID{Bytes: sessID, Valid: true},
+		ChangeLog: pgtype.Text{String: changeLog, Valid: true},
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, entity.ErrNoBaseline
+		}
+		return nil, fmt.Errorf("update session delta change log: %w", err)
+	}
+
+	return toEntitySessionDelta(&dbDelta), nil
+}
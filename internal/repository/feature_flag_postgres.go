@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/repository/sqlc"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// FeatureFlagRepository defines the interface for feature flag override persistence.
+// Overrides are global, they are not scoped to a tenant.
+type FeatureFlagRepository interface {
+	ListOverrides(ctx context.Context) ([]*entity.FeatureFlagOverride, error)
+	SetOverride(ctx context.Context, override entity.FeatureFlagOverride) (*entity.FeatureFlagOverride, error)
+	DeleteOverride(ctx context.Context, name string) error
+}
+
+var _ FeatureFlagRepository = &FeatureFlagPostgres{}
+
+// FeatureFlagPostgres implements FeatureFlagRepository using PostgreSQL
+type FeatureFlagPostgres struct {
+	db      *pgxpool.Pool
+	queries *sqlc.Queries
+}
+
+func NewFeatureFlagPostgres(db *pgxpool.Pool) *FeatureFlagPostgres {
+	return &FeatureFlagPostgres{
+		db:      db,
+		queries: sqlc.New(db),
+	}
+}
+
+func (r *FeatureFlagPostgres) ListOverrides(ctx context.Context) ([]*entity.FeatureFlagOverride, error) {
+	dbOverrides, err := r.queries.ListFeatureFlagOverrides(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list feature flag overrides: %w", err)
+	}
+
+	overrides := make([]*entity.FeatureFlagOverride, 0, len(dbOverrides))
+	for _, dbOverride := range dbOverrides {
+		overrides = append(overrides, toEntityFeatureFlagOverride(&dbOverride))
+	}
+
+	return overrides, nil
+}
+
+func (r *FeatureFlagPostgres) SetOverride(ctx context.Context, override entity.FeatureFlagOverride) (*entity.FeatureFlagOverride, error) {
+	var percent pgtype.Int4
+	if override.RolloutPercent != nil {
+		percent = pgtype.Int4{Int32: int32(*override.RolloutPercent), Valid: true}
+	}
+
+	dbOverride, err := r.queries.UpsertFeatureFlagOverride(ctx, sqlc.UpsertFeatureFlagOverrideParams{
+		Name:           override.Name,
+		RolloutPercent: percent,
+		Disabled:       override.Disabled,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("upsert feature flag override: %w", err)
+	}
+
+	return toEntityFeatureFlagOverride(&dbOverride), nil
+}
+
+func (r *FeatureFlagPostgres) DeleteOverride(ctx context.Context, name string) error {
+	rows, err := r.queries.DeleteFeatureFlagOverride(ctx, name)
+	if err != nil {
+		return fmt.Errorf("delete feature flag override: %w", err)
+	}
+	if rows == 0 {
+		return entity.ErrFeatureFlagNotFound
+	}
+
+	return nil
+}
@@ -2,11 +2,13 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/futig/agent-backend/internal/entity"
 	"github.com/futig/agent-backend/internal/repository/sqlc"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
@@ -15,6 +17,7 @@ import (
 type ProjectFileRepository interface {
 	AddFile(ctx context.Context, file entity.File) (*entity.File, error)
 	GetFiles(ctx context.Context, projectID string) ([]*entity.File, error)
+	GetFile(ctx context.Context, projectID, fileID string) (*entity.File, error)
 	DeleteFile(ctx context.Context, fileID string) error
 }
 
@@ -44,14 +47,18 @@ func (r *ProjectFilePostgres) AddFile(ctx context.Context, file entity.File) (*e
 		return nil, fmt.Errorf("parse project ID: %w", err)
 	}
 
-	result, err := r.queries.AddFile(ctx, sqlc.AddFileParams{
+	params := sqlc.AddFileParams{
 		ID:          pgtype.UUID{Bytes: fileID, Valid: true},
 		ProjectID:   pgtype.UUID{Bytes: projectID, Valid: true},
 		Filename:    file.Filename,
 		Size:        file.Size,
 		ContentType: file.ContentType,
-	})
+	}
+	if file.ObjectKey != nil {
+		params.ObjectKey = pgtype.Text{String: *file.ObjectKey, Valid: true}
+	}
 
+	result, err := r.queries.AddFile(ctx, params)
 	if err != nil {
 		return nil, fmt.Errorf("add file: %w", err)
 	}
@@ -65,7 +72,10 @@ func (r *ProjectFilePostgres) DeleteFile(ctx context.Context, fileID string) err
 		return fmt.Errorf("parse file ID: %w", err)
 	}
 
-	err = r.queries.DeleteProjectFile(ctx, pgtype.UUID{Bytes: fid, Valid: true})
+	err = r.queries.DeleteProjectFile(ctx, sqlc.DeleteProjectFileParams{
+		ID:       pgtype.UUID{Bytes: fid, Valid: true},
+		TenantID: entity.TenantIDFromContext(ctx),
+	})
 	if err != nil {
 		return fmt.Errorf("delete file: %w", err)
 	}
@@ -73,13 +83,41 @@ func (r *ProjectFilePostgres) DeleteFile(ctx context.Context, fileID string) err
 	return nil
 }
 
+func (r *ProjectFilePostgres) GetFile(ctx context.Context, projectID, fileID string) (*entity.File, error) {
+	pid, err := uuid.Parse(projectID)
+	if err != nil {
+		return nil, fmt.Errorf("parse project ID: %w", err)
+	}
+
+	fid, err := uuid.Parse(fileID)
+	if err != nil {
+		return nil, fmt.Errorf("parse file ID: %w", err)
+	}
+
+	result, err := r.queries.GetProjectFile(ctx, sqlc.GetProjectFileParams{
+		ID:        pgtype.UUID{Bytes: fid, Valid: true},
+		ProjectID: pgtype.UUID{Bytes: pid, Valid: true},
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, entity.ErrFileNotFound
+		}
+		return nil, fmt.Errorf("get file: %w", err)
+	}
+
+	return toEntityFile(&result), nil
+}
+
 func (r *ProjectFilePostgres) GetFiles(ctx context.Context, projectID string) ([]*entity.File, error) {
 	pid, err := uuid.Parse(projectID)
 	if err != nil {
 		return nil, fmt.Errorf("parse project ID: %w", err)
 	}
 
-	results, err := r.queries.GetFiles(ctx, pgtype.UUID{Bytes: pid, Valid: true})
+	results, err := r.queries.GetFiles(ctx, sqlc.GetFilesParams{
+		ProjectID: pgtype.UUID{Bytes: pid, Valid: true},
+		TenantID:  entity.TenantIDFromContext(ctx),
+	})
 	if err != nil {
 		return nil, fmt.Errorf("get files: %w", err)
 	}
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/futig/agent-backend/internal/repository/sqlc"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// GenerationApprovalRepository defines the interface for admin generation approvals persistence
+type GenerationApprovalRepository interface {
+	Approve(ctx context.Context, sessionID string) error
+	IsApproved(ctx context.Context, sessionID string) (bool, error)
+}
+
+var _ GenerationApprovalRepository = &GenerationApprovalPostgres{}
+
+// GenerationApprovalPostgres implements GenerationApprovalRepository using PostgreSQL
+type GenerationApprovalPostgres struct {
+	db      *pgxpool.Pool
+	queries *sqlc.Queries
+}
+
+func NewGenerationApprovalPostgres(db *pgxpool.Pool) *GenerationApprovalPostgres {
+	return &GenerationApprovalPostgres{
+		db:      db,
+		queries: sqlc.New(db),
+	}
+}
+
+func (r *GenerationApprovalPostgres) Approve(ctx context.Context, sessionID string) error {
+	sessID, err := uuid.Parse(sessionID)
+	if err != nil {
+		return fmt.Errorf("invalid session ID: %w", err)
+	}
+
+	if err := r.queries.ApproveSessionGeneration(ctx, pgtype.UUID{
+		Bytes: sessID,
+		Valid: true,
+	}); err != nil {
+		return fmt.Errorf("approve session generation: %w", err)
+	}
+
+	return nil
+}
+
+func (r *GenerationApprovalPostgres) IsApproved(ctx context.Context, sessionID string) (bool, error) {
+	sessID, err := uuid.Parse(sessionID)
+	if err != nil {
+		return false, fmt.Errorf("invalid session ID: %w", err)
+	}
+
+	approved, err := r.queries.IsSessionGenerationApproved(ctx, pgtype.UUID{
+		Bytes: sessID,
+		Valid: true,
+	})
+	if err != nil {
+		return false, fmt.Errorf("check session generation approval: %w", err)
+	}
+
+	return approved, nil
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/futig/agent-backend/internal/repository/sqlc"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// GenerationFailureRepository defines the interface for counting failed final generations of sessions
+type GenerationFailureRepository interface {
+	RecordFailure(ctx context.Context, sessionID, lastError string) (int, error)
+	ResetFailures(ctx context.Context, sessionID string) error
+}
+
+var _ GenerationFailureRepository = &GenerationFailurePostgres{}
+
+// GenerationFailurePostgres implements GenerationFailureRepository using PostgreSQL
+type GenerationFailurePostgres struct {
+	db      *pgxpool.Pool
+	queries *sqlc.Queries
+}
+
+func NewGenerationFailurePostgres(db *pgxpool.Pool) *GenerationFailurePostgres {
+	return &GenerationFailurePostgres{
+		db:      db,
+		queries: sqlc.New(db),
+	}
+}
+
+// RecordFailure counts a failed generation of the session and returns the failures since its last result
+func (r *GenerationFailurePostgres) RecordFailure(ctx context.Context, sessionID, lastError string) (int, error) {
+	sessID, err := uuid.Parse(sessionID)
+	if err != nil {
+		return 0, fmt.Errorf("invalid session ID: %w", err)
+	}
+
+	failures, err := r.queries.RecordGenerationFailure(ctx, sqlc.RecordGenerationFailureParams{
+		SessionID: pgtype.UUID{Bytes: sessID, Valid: true},
+		LastError: lastError,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("record generation failure: %w", err)
+	}
+
+	return int(failures), nil
+}
+
+// ResetFailures forgets the failed generations of the session once it got a result
+func (r *GenerationFailurePostgres) ResetFailures(ctx context.Context, sessionID string) error {
+	sessID, err := uuid.Parse(sessionID)
+	if err != nil {
+		return fmt.Errorf("invalid session ID: %w", err)
+	}
+
+	if err := r.queries.ResetGenerationFailures(ctx, pgtype.UUID{Bytes: sessID, Valid: true}); err != nil {
+		return fmt.Errorf("reset generation failures: %w", err)
+	}
+
+	return nil
+}
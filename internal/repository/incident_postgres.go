@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/repository/sqlc"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// IncidentRepository defines the interface for incidents persistence
+type IncidentRepository interface {
+	CreateIncident(ctx context.Context, incident *entity.Incident) error
+	ListIncidentsByCode(ctx context.Context, code string, limit int) ([]*entity.Incident, error)
+	DeleteIncidentsBefore(ctx context.Context, before time.Time) (int, error)
+}
+
+var _ IncidentRepository = &IncidentPostgres{}
+
+// IncidentPostgres implements IncidentRepository using PostgreSQL
+type IncidentPostgres struct {
+	db      *pgxpool.Pool
+	queries *sqlc.Queries
+}
+
+func NewIncidentPostgres(db *pgxpool.Pool) *IncidentPostgres {
+	return &IncidentPostgres{
+		db:      db,
+		queries: sqlc.New(db),
+	}
+}
+
+// CreateIncident records an incident
+func (r *IncidentPostgres) CreateIncident(ctx context.Context, incident *entity.Incident) error {
+	fields := []byte("{}")
+	if len(incident.Fields) > 0 {
+		var err error
+		fields, err = json.Marshal(incident.Fields)
+		if err != nil {
+			return fmt.Errorf("marshal incident fields: %w", err)
+		}
+	}
+
+	err := r.queries.CreateIncident(ctx, sqlc.CreateIncidentParams{
+		Code:          incident.Code,
+		CorrelationID: incident.CorrelationID,
+		Level:         incident.Level,
+		Message:       incident.Message,
+		Fields:        fields,
+	})
+	if err != nil {
+		return fmt.Errorf("create incident: %w", err)
+	}
+
+	return nil
+}
+
+// ListIncidentsByCode returns the latest incidents with the code, newest first
+func (r *IncidentPostgres) ListIncidentsByCode(ctx context.Context, code string, limit int) ([]*entity.Incident, error) {
+	dbIncidents, err := r.queries.ListIncidentsByCode(ctx, sqlc.ListIncidentsByCodeParams{
+		Code:  code,
+		Limit: int32(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list incidents: %w", err)
+	}
+
+	incidents := make([]*entity.Incident, 0, len(dbIncidents))
+	for i := range dbIncidents {
+		incident, err := toEntityIncident(&dbIncidents[i])
+		if err != nil {
+			return nil, err
+		}
+		incidents = append(incidents, incident)
+	}
+
+	return incidents, nil
+}
+
+// DeleteIncidentsBefore removes incidents recorded before before and returns how many were removed
+func (r *IncidentPostgres) DeleteIncidentsBefore(ctx context.Context, before time.Time) (int, error) {
+	deleted, err := r.queries.DeleteIncidentsBefore(ctx, pgtype.Timestamp{Time: before, Valid: true})
+	if err != nil {
+		return 0, fmt.Errorf("delete incidents: %w", err)
+	}
+
+	return int(deleted), nil
+}
@@ -19,6 +19,7 @@ type IterationRepository interface {
 	GetCurrentIteration(ctx context.Context, sessionID string) (*entity.Iteration, error)
 	ListIterationsBySession(ctx context.Context, sessionID string) ([]*entity.Iteration, error)
 	GetMaxIterationNumber(ctx context.Context, sessionID string) (int, error)
+	DeleteIterationsBySession(ctx context.Context, sessionID string) (int64, error)
 }
 
 var _ IterationRepository = &IterationPostgres{}
@@ -163,3 +164,22 @@ func (r *IterationPostgres) GetMaxIterationNumber(ctx context.Context, sessionID
 
 	return int(maxNumber), nil
 }
+
+// DeleteIterationsBySession removes the iterations of a session with their questions and answers and
+// starts the session over from its first iteration
+func (r *IterationPostgres) DeleteIterationsBySession(ctx context.Context, sessionID string) (int64, error) {
+	sessID, err := uuid.Parse(sessionID)
+	if err != nil {
+		return 0, fmt.Errorf("invalid session ID: %w", err)
+	}
+
+	deleted, err := r.queries.DeleteIterationsBySession(ctx, pgtype.UUID{
+		Bytes: sessID,
+		Valid: true,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("delete iterations: %w", err)
+	}
+
+	return deleted, nil
+}
@@ -1,55 +1,204 @@
 package repository
 
 import (
+	"errors"
 	"fmt"
+	"os"
 
 	"github.com/golang-migrate/migrate/v4"
 	_ "github.com/golang-migrate/migrate/v4/database/postgres"
+	"github.com/golang-migrate/migrate/v4/source"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
 )
 
-// RunMigrations runs database migrations
-func RunMigrations(databaseURL string) error {
-	m, err := migrate.New(
-		"file://internal/repository/migrations",
-		databaseURL,
-	)
+var (
+	// ErrPendingMigrations is returned when the schema is behind the available migrations
+	ErrPendingMigrations = errors.New("database has pending migrations")
+	// ErrDirtyMigration is returned when a migration failed halfway and the schema needs a manual repair
+	ErrDirtyMigration = errors.New("database migration is dirty")
+)
+
+// MigrationStatus describes the schema version against the available migrations
+type MigrationStatus struct {
+	Version uint // 0 when no migration has been applied
+	Dirty   bool
+	Latest  uint
+	Pending []uint
+}
+
+// Migrator applies and rolls back versioned schema migrations
+type Migrator struct {
+	m      *migrate.Migrate
+	source source.Driver
+}
+
+// NewMigrator opens the migrations in dir against the database
+func NewMigrator(databaseURL, dir string) (*Migrator, error) {
+	src, err := source.Open("file://" + dir)
 	if err != nil {
-		return fmt.Errorf("create migration instance: %w", err)
+		return nil, fmt.Errorf("open migrations source: %w", err)
 	}
-	defer m.Close()
 
-	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
-		// Handle dirty database state by forcing to the previous clean version
-		if dirtyErr, ok := err.(migrate.ErrDirty); ok {
-			version, dirty, verr := m.Version()
-			if verr != nil {
-				return fmt.Errorf("get current migration version: %w", verr)
-			}
+	m, err := migrate.NewWithSourceInstance("file", src, databaseURL)
+	if err != nil {
+		src.Close()
+		return nil, fmt.Errorf("create migration instance: %w", err)
+	}
 
-			if dirty {
-				forceVersion := int(version) - 1
-				if forceVersion < 0 {
-					forceVersion = 0
-				}
+	return &Migrator{m: m, source: src}, nil
+}
 
-				if ferr := m.Force(forceVersion); ferr != nil {
-					return fmt.Errorf("force clean migration version %d: %w", forceVersion, ferr)
-				}
+// Close releases the database connection and the migrations source
+func (mg *Migrator) Close() error {
+	srcErr, dbErr := mg.m.Close()
+	return errors.Join(srcErr, dbErr)
+}
 
-				// Retry migrations after cleaning dirty state
-				if err := m.Up(); err != nil && err != migrate.ErrNoChange {
-					return fmt.Errorf("rerun migrations after dirty state: %w", err)
-				}
+// Status returns the current schema version and the migrations not applied yet
+func (mg *Migrator) Status() (*MigrationStatus, error) {
+	status := &MigrationStatus{}
 
-				return nil
-			}
+	version, dirty, err := mg.m.Version()
+	if err != nil && !errors.Is(err, migrate.ErrNilVersion) {
+		return nil, fmt.Errorf("get migration version: %w", err)
+	}
+	status.Version = version
+	status.Dirty = dirty
 
-			return fmt.Errorf("dirty migrations at version %d and could not auto-fix", dirtyErr.Version)
+	next, err := mg.source.First()
+	for err == nil {
+		status.Latest = next
+		if next > status.Version {
+			status.Pending = append(status.Pending, next)
 		}
+		next, err = mg.source.Next(next)
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("list migrations: %w", err)
+	}
+
+	return status, nil
+}
+
+// Up applies the given number of pending migrations, all of them when steps is 0
+func (mg *Migrator) Up(steps int) error {
+	if err := mg.ensureClean(); err != nil {
+		return err
+	}
 
-		return fmt.Errorf("run migrations: %w", err)
+	var err error
+	if steps == 0 {
+		err = mg.m.Up()
+	} else {
+		err = mg.m.Steps(steps)
+	}
+	if err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("apply migrations: %w", mg.explain(err))
 	}
 
 	return nil
 }
+
+// Down rolls back the given number of applied migrations
+func (mg *Migrator) Down(steps int) error {
+	if steps <= 0 {
+		return fmt.Errorf("rollback steps must be positive, got %d", steps)
+	}
+
+	if err := mg.ensureClean(); err != nil {
+		return err
+	}
+
+	if err := mg.m.Steps(-steps); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("roll back migrations: %w", mg.explain(err))
+	}
+
+	return nil
+}
+
+// Force sets the schema version without running migrations and clears the dirty flag.
+// It is the way out of a dirty state once the schema has been repaired by hand.
+func (mg *Migrator) Force(version int) error {
+	if err := mg.m.Force(version); err != nil {
+		return fmt.Errorf("force migration version %d: %w", version, err)
+	}
+
+	return nil
+}
+
+// Check returns ErrDirtyMigration or ErrPendingMigrations when the schema is not up to date
+func (mg *Migrator) Check() error {
+	status, err := mg.Status()
+	if err != nil {
+		return err
+	}
+
+	if status.Dirty {
+		return DirtyMigrationError(status.Version)
+	}
+
+	if len(status.Pending) > 0 {
+		return fmt.Errorf("%w: version %d, latest %d; run `migrate up` to apply them",
+			ErrPendingMigrations, status.Version, status.Latest)
+	}
+
+	return nil
+}
+
+// ensureClean refuses to migrate a dirty schema
+func (mg *Migrator) ensureClean() error {
+	version, dirty, err := mg.m.Version()
+	if err != nil && !errors.Is(err, migrate.ErrNilVersion) {
+		return fmt.Errorf("get migration version: %w", err)
+	}
+
+	if dirty {
+		return DirtyMigrationError(version)
+	}
+
+	return nil
+}
+
+// explain adds remediation to a migration that failed halfway
+func (mg *Migrator) explain(err error) error {
+	var dirtyErr migrate.ErrDirty
+	if errors.As(err, &dirtyErr) {
+		return DirtyMigrationError(uint(dirtyErr.Version))
+	}
+
+	return err
+}
+
+// DirtyMigrationError describes how to recover from a migration that failed halfway
+func DirtyMigrationError(version uint) error {
+	previous := 0
+	if version > 0 {
+		previous = int(version) - 1
+	}
+
+	return fmt.Errorf("%w at version %d: finish or undo migration %d by hand, then run "+
+		"`migrate force %d` if it is fully applied or `migrate force %d` if it is fully undone",
+		ErrDirtyMigration, version, version, version, previous)
+}
+
+// RunMigrations applies all pending migrations
+func RunMigrations(databaseURL, dir string) error {
+	mg, err := NewMigrator(databaseURL, dir)
+	if err != nil {
+		return err
+	}
+	defer mg.Close()
+
+	return mg.Up(0)
+}
+
+// CheckMigrations fails when the schema is dirty or has pending migrations, without changing it
+func CheckMigrations(databaseURL, dir string) error {
+	mg, err := NewMigrator(databaseURL, dir)
+	if err != nil {
+		return err
+	}
+	defer mg.Close()
+
+	return mg.Check()
+}
@@ -0,0 +1,8 @@
+-- Cached translations of session results, one per target language
+CREATE TABLE IF NOT EXISTS session_translations (
+    session_id UUID NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
+    language VARCHAR(10) NOT NULL,
+    result TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (session_id, language)
+);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_audit_log_event_type_created_at;
+DROP INDEX IF EXISTS idx_audit_log_session_id;
+DROP TABLE IF EXISTS audit_log;
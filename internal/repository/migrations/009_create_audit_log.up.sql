@@ -0,0 +1,11 @@
+-- Append-only audit log of notable session events (moderation, etc.)
+CREATE TABLE IF NOT EXISTS audit_log (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    session_id UUID REFERENCES sessions(id) ON DELETE SET NULL,
+    event_type VARCHAR(64) NOT NULL,
+    details JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_audit_log_session_id ON audit_log(session_id);
+CREATE INDEX idx_audit_log_event_type_created_at ON audit_log(event_type, created_at DESC);
@@ -0,0 +1,5 @@
+-- Admin approvals for generating unusually large sessions
+CREATE TABLE IF NOT EXISTS session_generation_approvals (
+    session_id UUID PRIMARY KEY REFERENCES sessions(id) ON DELETE CASCADE,
+    approved_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
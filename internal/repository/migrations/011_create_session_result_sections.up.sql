@@ -0,0 +1,10 @@
+-- Sections of results produced by sectioned (multi-part) generation
+CREATE TABLE IF NOT EXISTS session_result_sections (
+    session_id UUID NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
+    section_index INTEGER NOT NULL,
+    title TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    content TEXT NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (session_id, section_index)
+);
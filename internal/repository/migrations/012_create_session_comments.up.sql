@@ -0,0 +1,13 @@
+-- Reviewer comments attached to sections or requirements of session results
+CREATE TABLE IF NOT EXISTS session_comments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    session_id UUID NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
+    section_index INTEGER,
+    requirement_id VARCHAR(64),
+    author VARCHAR(255) NOT NULL DEFAULT '',
+    text TEXT NOT NULL,
+    resolved_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_session_comments_session_id_created_at ON session_comments(session_id, created_at);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS session_review_approvers;
+DROP TABLE IF EXISTS session_reviews;
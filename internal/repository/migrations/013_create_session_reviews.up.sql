@@ -0,0 +1,18 @@
+-- Approval workflow state of session results
+CREATE TABLE IF NOT EXISTS session_reviews (
+    session_id UUID PRIMARY KEY REFERENCES sessions(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'draft',
+    decided_by_type VARCHAR(20),
+    decided_by_id VARCHAR(255),
+    decision_comment TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Approvers assigned to a session result review
+CREATE TABLE IF NOT EXISTS session_review_approvers (
+    session_id UUID NOT NULL REFERENCES session_reviews(session_id) ON DELETE CASCADE,
+    approver_type VARCHAR(20) NOT NULL,
+    approver_id VARCHAR(255) NOT NULL,
+    PRIMARY KEY (session_id, approver_type, approver_id)
+);
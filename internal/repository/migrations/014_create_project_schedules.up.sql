@@ -0,0 +1,16 @@
+-- Recurring check-in sessions created per project on a cron schedule
+CREATE TABLE IF NOT EXISTS project_schedules (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    cron_expr VARCHAR(100) NOT NULL,
+    telegram_user_id BIGINT NOT NULL,
+    user_goal TEXT NOT NULL,
+    next_run_at TIMESTAMP NOT NULL,
+    last_run_at TIMESTAMP,
+    last_session_id UUID REFERENCES sessions(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_project_schedules_project_id ON project_schedules(project_id);
+CREATE INDEX idx_project_schedules_next_run_at ON project_schedules(next_run_at);
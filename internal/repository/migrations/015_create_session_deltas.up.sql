@@ -0,0 +1,9 @@
+-- Baseline documents and change logs of delta ("what changed") sessions
+CREATE TABLE IF NOT EXISTS session_deltas (
+    session_id UUID PRIMARY KEY REFERENCES sessions(id) ON DELETE CASCADE,
+    baseline_session_id UUID REFERENCES sessions(id) ON DELETE SET NULL,
+    baseline TEXT NOT NULL,
+    change_log TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
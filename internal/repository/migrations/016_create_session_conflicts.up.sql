@@ -0,0 +1,14 @@
+-- Contradictions between session results and earlier project requirements
+CREATE TABLE IF NOT EXISTS session_conflicts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    session_id UUID NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    requirement TEXT NOT NULL,
+    previous TEXT NOT NULL,
+    source TEXT,
+    resolution VARCHAR(32),
+    resolved_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_session_conflicts_session_id_position ON session_conflicts(session_id, position);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_sessions_result_search;
+DROP INDEX IF EXISTS idx_sessions_user_goal_search;
+DROP INDEX IF EXISTS idx_project_files_search;
+DROP INDEX IF EXISTS idx_projects_search;
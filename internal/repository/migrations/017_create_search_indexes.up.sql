@@ -0,0 +1,13 @@
+-- Full-text indexes for the admin search across projects and sessions; expressions must match
+-- the ones in queries/search.sql for the planner to use them
+CREATE INDEX IF NOT EXISTS idx_projects_search
+    ON projects USING GIN (to_tsvector('russian', title || ' ' || COALESCE(description, '')));
+
+CREATE INDEX IF NOT EXISTS idx_project_files_search
+    ON project_files USING GIN (to_tsvector('simple', filename));
+
+CREATE INDEX IF NOT EXISTS idx_sessions_user_goal_search
+    ON sessions USING GIN (to_tsvector('russian', COALESCE(user_goal, '')));
+
+CREATE INDEX IF NOT EXISTS idx_sessions_result_search
+    ON sessions USING GIN (to_tsvector('russian', COALESCE(result, '')));
@@ -0,0 +1,15 @@
+-- Async workflows started by the session API, pollable by request ID for clients without callbacks
+CREATE TABLE IF NOT EXISTS operations (
+    request_id VARCHAR(255) PRIMARY KEY,
+    client_id VARCHAR(255),
+    kind VARCHAR(64) NOT NULL,
+    session_id UUID,
+    status VARCHAR(32) NOT NULL,
+    event VARCHAR(64),
+    result JSONB,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_operations_client_id_created_at ON operations(client_id, created_at DESC);
+CREATE INDEX idx_operations_updated_at ON operations(updated_at);
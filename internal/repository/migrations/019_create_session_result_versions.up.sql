@@ -0,0 +1,13 @@
+-- Versions of generated session results; large bodies live in blob storage
+-- and only their metadata is kept here, small ones stay inline in sessions.result
+CREATE TABLE IF NOT EXISTS session_result_versions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    session_id UUID NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
+    version INTEGER NOT NULL,
+    storage VARCHAR(20) NOT NULL,
+    object_key TEXT,
+    size_bytes INTEGER NOT NULL,
+    checksum VARCHAR(64) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (session_id, version)
+);
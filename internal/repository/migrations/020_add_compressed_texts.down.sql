@@ -0,0 +1,11 @@
+-- Compressed texts can only be restored by the application, so refuse to drop them
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM session_messages WHERE message_text_compressed IS NOT NULL)
+        OR EXISTS (SELECT 1 FROM sessions WHERE project_context_compressed IS NOT NULL) THEN
+        RAISE EXCEPTION 'compressed texts exist, decompress them before rolling back';
+    END IF;
+END $$;
+
+ALTER TABLE sessions DROP COLUMN IF EXISTS project_context_compressed;
+ALTER TABLE session_messages DROP COLUMN IF EXISTS message_text_compressed;
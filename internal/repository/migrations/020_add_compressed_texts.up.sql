@@ -0,0 +1,4 @@
+-- Long draft messages and project contexts are stored zstd-compressed by the application;
+-- the plain text column is emptied for compressed rows
+ALTER TABLE session_messages ADD COLUMN IF NOT EXISTS message_text_compressed BYTEA;
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS project_context_compressed BYTEA;
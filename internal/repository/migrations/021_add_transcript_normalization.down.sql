@@ -0,0 +1,3 @@
+ALTER TABLE telegram_users DROP COLUMN IF EXISTS normalize_transcripts;
+ALTER TABLE session_messages DROP COLUMN IF EXISTS raw_message_text;
+ALTER TABLE iteration_questions DROP COLUMN IF EXISTS raw_answer;
@@ -0,0 +1,6 @@
+-- Original transcriptions are kept when the normalization pass changed them
+ALTER TABLE iteration_questions ADD COLUMN IF NOT EXISTS raw_answer TEXT;
+ALTER TABLE session_messages ADD COLUMN IF NOT EXISTS raw_message_text TEXT;
+
+-- Telegram users can turn the normalization pass off for their transcriptions
+ALTER TABLE telegram_users ADD COLUMN IF NOT EXISTS normalize_transcripts BOOLEAN NOT NULL DEFAULT TRUE;
@@ -0,0 +1,2 @@
+-- Telegram users can choose per-block or global question numbering; NULL keeps the deployment default
+ALTER TABLE telegram_users ADD COLUMN IF NOT EXISTS question_numbering VARCHAR(10);
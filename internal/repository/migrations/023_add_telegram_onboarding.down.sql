@@ -0,0 +1 @@
+ALTER TABLE telegram_users DROP COLUMN IF EXISTS onboarded_at;
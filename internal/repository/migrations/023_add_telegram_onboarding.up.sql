@@ -0,0 +1,8 @@
+-- First /start shows the onboarding tutorial; users who already used the bot have seen the flow
+ALTER TABLE telegram_users ADD COLUMN IF NOT EXISTS onboarded_at TIMESTAMP;
+
+UPDATE telegram_users SET onboarded_at = created_at WHERE onboarded_at IS NULL;
+
+INSERT INTO telegram_users (user_id, onboarded_at)
+SELECT user_id, created_at FROM telegram_sessions
+ON CONFLICT (user_id) DO NOTHING;
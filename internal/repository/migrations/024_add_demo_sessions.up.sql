@@ -0,0 +1,4 @@
+-- Demo sandbox sessions run against mock connectors and are removed shortly after creation
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS is_demo BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE INDEX IF NOT EXISTS idx_sessions_demo_created ON sessions(created_at) WHERE is_demo;
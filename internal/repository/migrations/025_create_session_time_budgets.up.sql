@@ -0,0 +1,7 @@
+-- Interview time budgets: the clock starts once the first questions are generated
+CREATE TABLE IF NOT EXISTS session_time_budgets (
+    session_id UUID PRIMARY KEY REFERENCES sessions(id) ON DELETE CASCADE,
+    budget_seconds INTEGER NOT NULL CHECK (budget_seconds > 0),
+    started_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    warned_at TIMESTAMP
+);
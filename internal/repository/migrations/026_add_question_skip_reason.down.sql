@@ -0,0 +1 @@
+ALTER TABLE iteration_questions DROP COLUMN IF EXISTS skip_reason;
@@ -0,0 +1,2 @@
+-- Optional reason the user gave when skipping a question
+ALTER TABLE iteration_questions ADD COLUMN IF NOT EXISTS skip_reason VARCHAR(50);
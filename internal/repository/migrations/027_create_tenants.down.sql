@@ -0,0 +1,18 @@
+-- Rows of other tenants would collide on the old primary keys
+DELETE FROM telegram_sessions WHERE tenant_id <> 'default';
+DELETE FROM telegram_users WHERE tenant_id <> 'default';
+
+ALTER TABLE telegram_users DROP CONSTRAINT telegram_users_pkey;
+ALTER TABLE telegram_users ADD PRIMARY KEY (user_id);
+ALTER TABLE telegram_sessions DROP CONSTRAINT telegram_sessions_pkey;
+ALTER TABLE telegram_sessions ADD PRIMARY KEY (user_id);
+
+DROP INDEX IF EXISTS idx_sessions_tenant;
+DROP INDEX IF EXISTS idx_projects_tenant_created;
+
+ALTER TABLE telegram_users DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE telegram_sessions DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE sessions DROP COLUMN IF EXISTS tenant_id;
+ALTER TABLE projects DROP COLUMN IF EXISTS tenant_id;
+
+DROP TABLE IF EXISTS tenants;
@@ -0,0 +1,28 @@
+-- Tenants isolate the projects, sessions and Telegram users of business units.
+-- API keys and bot tokens are stored as SHA-256 hashes
+CREATE TABLE IF NOT EXISTS tenants (
+    id VARCHAR(64) PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    api_key_hash CHAR(64) UNIQUE,
+    bot_token_hash CHAR(64) UNIQUE,
+    settings JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Existing data belongs to the default tenant
+INSERT INTO tenants (id, name) VALUES ('default', 'Default')
+ON CONFLICT (id) DO NOTHING;
+
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(64) NOT NULL DEFAULT 'default' REFERENCES tenants(id);
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(64) NOT NULL DEFAULT 'default' REFERENCES tenants(id);
+ALTER TABLE telegram_sessions ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(64) NOT NULL DEFAULT 'default' REFERENCES tenants(id);
+ALTER TABLE telegram_users ADD COLUMN IF NOT EXISTS tenant_id VARCHAR(64) NOT NULL DEFAULT 'default' REFERENCES tenants(id);
+
+CREATE INDEX IF NOT EXISTS idx_projects_tenant_created ON projects(tenant_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_sessions_tenant ON sessions(tenant_id);
+
+-- The same Telegram user may talk to the bots of several tenants
+ALTER TABLE telegram_sessions DROP CONSTRAINT telegram_sessions_pkey;
+ALTER TABLE telegram_sessions ADD PRIMARY KEY (tenant_id, user_id);
+ALTER TABLE telegram_users DROP CONSTRAINT telegram_users_pkey;
+ALTER TABLE telegram_users ADD PRIMARY KEY (tenant_id, user_id);
@@ -0,0 +1,3 @@
+ALTER TABLE projects DROP COLUMN IF EXISTS theme_id;
+
+DROP TABLE IF EXISTS document_themes;
@@ -0,0 +1,18 @@
+-- Document themes brand the PDF and DOCX results of a tenant; logo and font files live in blob storage
+CREATE TABLE IF NOT EXISTS document_themes (
+    id UUID PRIMARY KEY,
+    tenant_id VARCHAR(64) NOT NULL DEFAULT 'default' REFERENCES tenants(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    primary_color VARCHAR(7),
+    header_text TEXT,
+    footer_text TEXT,
+    font_name VARCHAR(100),
+    logo_key TEXT,
+    font_key TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_document_themes_tenant ON document_themes(tenant_id, created_at DESC);
+
+-- Projects may override the theme of their tenant
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS theme_id UUID REFERENCES document_themes(id) ON DELETE SET NULL;
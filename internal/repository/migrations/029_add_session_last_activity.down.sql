@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_sessions_demo_activity;
+CREATE INDEX IF NOT EXISTS idx_sessions_demo_created ON sessions(created_at) WHERE is_demo;
+
+ALTER TABLE sessions DROP COLUMN IF EXISTS last_activity_at;
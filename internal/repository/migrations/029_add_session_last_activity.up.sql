@@ -0,0 +1,6 @@
+-- External orchestrators report that a session is still in use through heartbeats,
+-- demo sessions then expire after the last heartbeat instead of their creation
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS last_activity_at TIMESTAMP;
+
+DROP INDEX IF EXISTS idx_sessions_demo_created;
+CREATE INDEX IF NOT EXISTS idx_sessions_demo_activity ON sessions(GREATEST(created_at, last_activity_at)) WHERE is_demo;
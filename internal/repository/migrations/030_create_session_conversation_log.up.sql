@@ -0,0 +1,13 @@
+-- Ordered log of the interview: answers, skips, deferrals and follow-up questions as they happened.
+-- The question text is copied so the log stays readable when questions are regenerated
+CREATE TABLE IF NOT EXISTS session_conversation_log (
+    id BIGSERIAL PRIMARY KEY,
+    session_id UUID NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
+    question_id UUID REFERENCES iteration_questions(id) ON DELETE SET NULL,
+    kind VARCHAR(20) NOT NULL,
+    question TEXT NOT NULL,
+    content TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_session_conversation_log_session ON session_conversation_log(session_id, id);
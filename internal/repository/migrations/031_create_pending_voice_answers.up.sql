@@ -0,0 +1,14 @@
+-- Voice answers received while speech recognition was down; they are transcribed and
+-- submitted once it recovers unless the question got answered meanwhile
+CREATE TABLE IF NOT EXISTS pending_voice_answers (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(64) NOT NULL REFERENCES tenants(id),
+    session_id UUID NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
+    question_id UUID NOT NULL REFERENCES iteration_questions(id) ON DELETE CASCADE,
+    telegram_user_id BIGINT NOT NULL,
+    audio BYTEA NOT NULL,
+    locked_until TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_pending_voice_answers_created ON pending_voice_answers(created_at);
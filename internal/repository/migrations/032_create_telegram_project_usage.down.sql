@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_sessions_project_created;
+DROP TABLE IF EXISTS telegram_project_usage;
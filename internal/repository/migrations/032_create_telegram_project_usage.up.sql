@@ -0,0 +1,10 @@
+-- Projects picked by Telegram users in the selector, used to list the recently used ones first
+CREATE TABLE IF NOT EXISTS telegram_project_usage (
+    tenant_id VARCHAR(64) NOT NULL REFERENCES tenants(id),
+    user_id BIGINT NOT NULL,
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    last_used_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (tenant_id, user_id, project_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_sessions_project_created ON sessions(project_id, created_at);
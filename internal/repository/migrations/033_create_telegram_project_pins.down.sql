@@ -0,0 +1 @@
+DROP TABLE IF EXISTS telegram_project_pins;
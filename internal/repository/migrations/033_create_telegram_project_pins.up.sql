@@ -0,0 +1,8 @@
+-- Favorite projects of Telegram users, shown on top of every page of the project selector
+CREATE TABLE IF NOT EXISTS telegram_project_pins (
+    tenant_id VARCHAR(64) NOT NULL REFERENCES tenants(id),
+    user_id BIGINT NOT NULL,
+    project_id UUID NOT NULL REFERENCES projects(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (tenant_id, user_id, project_id)
+);
@@ -0,0 +1,8 @@
+-- Feature flag settings changed through the admin API; they take precedence over the
+-- configured rollouts and apply to all tenants
+CREATE TABLE IF NOT EXISTS feature_flag_overrides (
+    name VARCHAR(64) PRIMARY KEY,
+    rollout_percent INT CHECK (rollout_percent BETWEEN 0 AND 100),
+    disabled BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
@@ -0,0 +1,2 @@
+ALTER TABLE iteration_questions DROP COLUMN IF EXISTS parent_question_id;
+ALTER TABLE iteration_questions DROP COLUMN IF EXISTS options;
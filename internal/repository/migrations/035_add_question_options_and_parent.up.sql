@@ -0,0 +1,3 @@
+-- Answer options suggested by the LLM and the answered question a follow-up question clarifies
+ALTER TABLE iteration_questions ADD COLUMN IF NOT EXISTS options TEXT[] NOT NULL DEFAULT '{}';
+ALTER TABLE iteration_questions ADD COLUMN IF NOT EXISTS parent_question_id UUID REFERENCES iteration_questions(id) ON DELETE SET NULL;
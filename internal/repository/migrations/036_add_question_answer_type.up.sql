@@ -0,0 +1,2 @@
+-- Kind of answer the LLM expects: free text or a 1-5 scale rating
+ALTER TABLE iteration_questions ADD COLUMN IF NOT EXISTS answer_type TEXT NOT NULL DEFAULT 'text';
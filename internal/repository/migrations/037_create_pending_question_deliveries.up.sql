@@ -0,0 +1,14 @@
+-- Questions callbacks the consumer did not receive; kept until the consumer pulls and acknowledges them
+CREATE TABLE IF NOT EXISTS pending_question_deliveries (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    session_id UUID NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
+    iteration_id UUID NOT NULL REFERENCES session_iterations(id) ON DELETE CASCADE,
+    request_id VARCHAR(255) NOT NULL DEFAULT '',
+    payload JSONB NOT NULL,
+    delivery_error TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    acknowledged_at TIMESTAMP,
+    UNIQUE (session_id, iteration_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_pending_question_deliveries_session ON pending_question_deliveries(session_id) WHERE acknowledged_at IS NULL;
@@ -0,0 +1,2 @@
+-- Which callback events an API session emits: per iteration, per question or only the final result
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS callback_granularity TEXT NOT NULL DEFAULT 'iteration';
@@ -0,0 +1,11 @@
+-- Quota-limited usage (started sessions, LLM generations) of tenants and their users
+CREATE TABLE IF NOT EXISTS quota_usage_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(64) NOT NULL REFERENCES tenants(id),
+    subject VARCHAR(255) NOT NULL DEFAULT '',
+    kind VARCHAR(32) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_quota_usage_events_tenant ON quota_usage_events(tenant_id, kind, created_at);
+CREATE INDEX IF NOT EXISTS idx_quota_usage_events_subject ON quota_usage_events(tenant_id, subject, kind, created_at);
@@ -0,0 +1,13 @@
+-- Raw text messages of Telegram users kept from arrival until their submission succeeds, so that
+-- a failed answer can be sent again without retyping it
+CREATE TABLE IF NOT EXISTS telegram_inbox (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(64) NOT NULL REFERENCES tenants(id),
+    user_id BIGINT NOT NULL,
+    session_id UUID NOT NULL REFERENCES sessions(id) ON DELETE CASCADE,
+    question_id UUID REFERENCES iteration_questions(id) ON DELETE CASCADE,
+    text TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_telegram_inbox_user ON telegram_inbox(tenant_id, user_id);
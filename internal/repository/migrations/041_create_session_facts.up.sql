@@ -0,0 +1,7 @@
+-- Facts already provided in the session, extracted by the LLM to keep validation from asking about them again
+CREATE TABLE IF NOT EXISTS session_facts (
+    session_id UUID PRIMARY KEY REFERENCES sessions(id) ON DELETE CASCADE,
+    facts JSONB NOT NULL DEFAULT '[]',
+    material_hash VARCHAR(64) NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
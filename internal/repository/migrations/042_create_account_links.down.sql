@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS account_links;
+DROP TABLE IF EXISTS account_link_codes;
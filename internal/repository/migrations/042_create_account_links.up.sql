@@ -0,0 +1,20 @@
+-- One-time codes issued by the Telegram bot to link another client of the user to the bot session
+CREATE TABLE IF NOT EXISTS account_link_codes (
+    tenant_id VARCHAR(64) NOT NULL REFERENCES tenants(id),
+    code VARCHAR(16) NOT NULL,
+    telegram_user_id BIGINT NOT NULL,
+    expires_at TIMESTAMP NOT NULL,
+    redeemed_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (tenant_id, code)
+);
+
+-- External identities (X-Client-ID) bound to Telegram users; the linked client follows the current
+-- session of the Telegram user
+CREATE TABLE IF NOT EXISTS account_links (
+    tenant_id VARCHAR(64) NOT NULL REFERENCES tenants(id),
+    client_id VARCHAR(255) NOT NULL,
+    telegram_user_id BIGINT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (tenant_id, client_id)
+);
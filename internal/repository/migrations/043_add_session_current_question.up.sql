@@ -0,0 +1,12 @@
+-- The question the session waits an answer for, so clients without their own state can resume it
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS current_question_id UUID REFERENCES iteration_questions(id) ON DELETE SET NULL;
+
+UPDATE sessions
+SET current_question_id = (
+    SELECT iq.id FROM iteration_questions iq
+    JOIN session_iterations si ON si.id = iq.iteration_id
+    WHERE si.session_id = sessions.id
+      AND iq.status IN ('UNANSWERED', 'DEFERRED')
+    ORDER BY iq.status = 'DEFERRED', si.iteration_number ASC, iq.question_number ASC
+    LIMIT 1
+);
@@ -0,0 +1,8 @@
+-- Failed final generations of a session since its last result; enough of them give the session
+-- a document of the collected materials instead
+CREATE TABLE IF NOT EXISTS session_generation_failures (
+    session_id UUID PRIMARY KEY REFERENCES sessions(id) ON DELETE CASCADE,
+    failures INT NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
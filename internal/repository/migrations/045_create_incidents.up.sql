@@ -0,0 +1,13 @@
+-- Error log entries of requests and bot updates, looked up by the incident code shown to the user
+CREATE TABLE IF NOT EXISTS incidents (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    code VARCHAR(16) NOT NULL,
+    correlation_id VARCHAR(255) NOT NULL,
+    level VARCHAR(16) NOT NULL,
+    message TEXT NOT NULL,
+    fields JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_incidents_code ON incidents(code, created_at);
+CREATE INDEX IF NOT EXISTS idx_incidents_created_at ON incidents(created_at);
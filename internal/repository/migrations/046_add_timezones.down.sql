@@ -0,0 +1,2 @@
+ALTER TABLE project_schedules DROP COLUMN IF EXISTS timezone;
+ALTER TABLE telegram_users DROP COLUMN IF EXISTS timezone;
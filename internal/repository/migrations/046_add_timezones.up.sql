@@ -0,0 +1,5 @@
+-- Telegram users get dates and times in their IANA timezone; NULL keeps the deployment default
+ALTER TABLE telegram_users ADD COLUMN IF NOT EXISTS timezone VARCHAR(64);
+
+-- Cron expressions of check-in schedules are evaluated in the timezone of the schedule
+ALTER TABLE project_schedules ADD COLUMN IF NOT EXISTS timezone VARCHAR(64) NOT NULL DEFAULT 'UTC';
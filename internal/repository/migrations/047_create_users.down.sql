@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_sessions_owner;
+DROP INDEX IF EXISTS idx_projects_owner;
+
+ALTER TABLE sessions DROP COLUMN IF EXISTS owner_id;
+ALTER TABLE projects DROP COLUMN IF EXISTS owner_id;
+
+DROP TABLE IF EXISTS users;
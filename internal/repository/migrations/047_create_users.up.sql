@@ -0,0 +1,20 @@
+-- Users own the projects and sessions they create. API clients authenticate with the key of their user,
+-- Telegram users get a user on first contact with the bot. API keys are stored as SHA-256 hashes
+CREATE TABLE IF NOT EXISTS users (
+    id UUID PRIMARY KEY,
+    tenant_id VARCHAR(64) NOT NULL REFERENCES tenants(id),
+    name VARCHAR(255) NOT NULL,
+    api_key_hash CHAR(64) UNIQUE,
+    telegram_user_id BIGINT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    UNIQUE (tenant_id, telegram_user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_users_tenant_created ON users(tenant_id, created_at);
+
+-- Rows without an owner were created before ownership or with a tenant key and stay shared in the tenant
+ALTER TABLE projects ADD COLUMN IF NOT EXISTS owner_id UUID REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS owner_id UUID REFERENCES users(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_projects_owner ON projects(owner_id) WHERE owner_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_sessions_owner ON sessions(owner_id) WHERE owner_id IS NOT NULL;
@@ -0,0 +1,2 @@
+ALTER TABLE sessions DROP COLUMN IF EXISTS language_explicit;
+ALTER TABLE sessions DROP COLUMN IF EXISTS language;
@@ -0,0 +1,4 @@
+-- Language of the user inputs of a session, the target language of the generated documents
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS language VARCHAR(8);
+-- Set by the user, automatic detection must not override it
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS language_explicit BOOLEAN NOT NULL DEFAULT FALSE;
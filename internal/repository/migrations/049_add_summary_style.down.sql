@@ -0,0 +1,2 @@
+ALTER TABLE telegram_users DROP COLUMN IF EXISTS summary_style;
+ALTER TABLE sessions DROP COLUMN IF EXISTS summary_style;
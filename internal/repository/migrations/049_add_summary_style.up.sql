@@ -0,0 +1,5 @@
+-- Style of the generated requirements document: formal, executive or developer; NULL keeps the LLM default
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS summary_style VARCHAR(16);
+
+-- Style the Telegram user chose last, applied to the next sessions
+ALTER TABLE telegram_users ADD COLUMN IF NOT EXISTS summary_style VARCHAR(16);
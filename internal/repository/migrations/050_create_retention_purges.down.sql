@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS retention_purges;
+DROP INDEX IF EXISTS idx_sessions_tenant_unpurged;
+ALTER TABLE sessions DROP COLUMN IF EXISTS purged_at;
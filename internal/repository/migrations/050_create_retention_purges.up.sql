@@ -0,0 +1,19 @@
+-- Sessions whose content was removed by the retention policy of their tenant; the rows stay as anonymized stubs
+ALTER TABLE sessions ADD COLUMN IF NOT EXISTS purged_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_sessions_tenant_unpurged ON sessions(tenant_id, updated_at) WHERE purged_at IS NULL;
+
+-- Volumes removed by each purge run of a tenant, reported in the admin API
+CREATE TABLE IF NOT EXISTS retention_purges (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(64) NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    cutoff TIMESTAMP NOT NULL,
+    sessions INTEGER NOT NULL,
+    questions INTEGER NOT NULL,
+    messages INTEGER NOT NULL,
+    result_versions INTEGER NOT NULL,
+    result_bytes BIGINT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_retention_purges_tenant ON retention_purges(tenant_id, created_at DESC);
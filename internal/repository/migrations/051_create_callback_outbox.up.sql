@@ -0,0 +1,20 @@
+-- Callback events kept until the consumer accepted them, retried by the outbox dispatcher
+CREATE TABLE IF NOT EXISTS callback_outbox (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    tenant_id VARCHAR(64) NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    request_id VARCHAR(255) NOT NULL,
+    callback_url TEXT NOT NULL,
+    event VARCHAR(64) NOT NULL,
+    payload JSONB NOT NULL,
+    status VARCHAR(32) NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP NOT NULL,
+    last_error TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    delivered_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_callback_outbox_due ON callback_outbox(next_attempt_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_callback_outbox_request ON callback_outbox(tenant_id, request_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_callback_outbox_settled ON callback_outbox(updated_at) WHERE status <> 'pending';
@@ -0,0 +1,2 @@
+-- Telegram users can show or hide the progress header of question messages; NULL keeps the deployment default
+ALTER TABLE telegram_users ADD COLUMN IF NOT EXISTS progress_header BOOLEAN;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS explanation_cooldowns;
+DROP TABLE IF EXISTS question_explanations;
@@ -0,0 +1,14 @@
+-- Explanations generated on demand, one per question, shared by the bot and the HTTP API
+CREATE TABLE IF NOT EXISTS question_explanations (
+    question_id UUID PRIMARY KEY REFERENCES iteration_questions(id) ON DELETE CASCADE,
+    explanation TEXT NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+-- Latest on-demand explanation generated for a user, so a user cannot spam the LLM
+CREATE TABLE IF NOT EXISTS explanation_cooldowns (
+    tenant_id VARCHAR(64) NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    subject VARCHAR(255) NOT NULL,
+    requested_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (tenant_id, subject)
+);
@@ -0,0 +1,10 @@
+-- Heavy operation in flight on a session, e.g. a generation; the primary key lets one run at a time
+-- across the bot and the HTTP API, and a row its holder stopped renewing expires
+CREATE TABLE IF NOT EXISTS session_operations (
+    session_id UUID PRIMARY KEY REFERENCES sessions(id) ON DELETE CASCADE,
+    id UUID NOT NULL DEFAULT gen_random_uuid(),
+    operation VARCHAR(50) NOT NULL,
+    owner VARCHAR(255) NOT NULL,
+    started_at TIMESTAMP NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP NOT NULL
+);
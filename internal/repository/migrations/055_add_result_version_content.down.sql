@@ -0,0 +1 @@
+ALTER TABLE session_result_versions DROP COLUMN IF EXISTS content;
@@ -0,0 +1,3 @@
+-- Inline result versions keep their own body once a newer version overwrites sessions.result,
+-- so earlier versions stay retrievable; the body of the current version is sessions.result
+ALTER TABLE session_result_versions ADD COLUMN IF NOT EXISTS content TEXT;
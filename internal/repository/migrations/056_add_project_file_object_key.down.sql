@@ -0,0 +1 @@
+ALTER TABLE project_files DROP COLUMN IF EXISTS object_key;
@@ -0,0 +1,3 @@
+-- Original content of project files kept in the blob storage; NULL for files uploaded
+-- before it was kept or while the blob storage is disabled
+ALTER TABLE project_files ADD COLUMN IF NOT EXISTS object_key TEXT;
@@ -0,0 +1,11 @@
+-- Usage of a quota per period, updated with a conditional UPDATE so that concurrent
+-- reservations cannot pass the limit; scope 'tenant' rows have an empty subject
+CREATE TABLE IF NOT EXISTS quota_counters (
+    tenant_id VARCHAR(64) NOT NULL REFERENCES tenants(id),
+    kind VARCHAR(32) NOT NULL,
+    period_start TIMESTAMP NOT NULL,
+    scope VARCHAR(16) NOT NULL,
+    subject VARCHAR(255) NOT NULL DEFAULT '',
+    used INTEGER NOT NULL DEFAULT 0,
+    PRIMARY KEY (tenant_id, kind, period_start, scope, subject)
+);
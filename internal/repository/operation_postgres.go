@@ -0,0 +1,162 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/repository/sqlc"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// OperationRepository defines the interface for async operations persistence
+type OperationRepository interface {
+	CreateOperation(ctx context.Context, operation *entity.Operation) (*entity.Operation, error)
+	UpdateOperationStatus(ctx context.Context, requestID string, status entity.OperationStatus) error
+	CompleteOperation(
+		ctx context.Context,
+		requestID string,
+		status entity.OperationStatus,
+		event entity.CallbackEventType,
+		result []byte,
+	) error
+	GetOperation(ctx context.Context, requestID string) (*entity.Operation, error)
+	ListClientOperations(ctx context.Context, clientID string, skip, limit int) ([]*entity.Operation, int, error)
+	DeleteOperationsBefore(ctx context.Context, before time.Time) (int, error)
+}
+
+var _ OperationRepository = &OperationPostgres{}
+
+// OperationPostgres implements OperationRepository using PostgreSQL
+type OperationPostgres struct {
+	db      *pgxpool.Pool
+	queries *sqlc.Queries
+}
+
+func NewOperationPostgres(db *pgxpool.Pool) *OperationPostgres {
+	return &OperationPostgres{
+		db:      db,
+		queries: sqlc.New(db),
+	}
+}
+
+func (r *OperationPostgres) CreateOperation(
+	ctx context.Context,
+	operation *entity.Operation,
+) (*entity.Operation, error) {
+	params := sqlc.CreateOperationParams{
+		RequestID: operation.RequestID,
+		Kind:      string(operation.Kind),
+		Status:    string(operation.Status),
+	}
+
+	if operation.ClientID != nil {
+		params.ClientID = pgtype.Text{String: *operation.ClientID, Valid: true}
+	}
+
+	if operation.SessionID != nil {
+		sessID, err := uuid.Parse(*operation.SessionID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid session ID: %w", err)
+		}
+		params.SessionID = pgtype.UUID{Bytes: sessID, Valid: true}
+	}
+
+	dbOperation, err := r.queries.CreateOperation(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("create operation: %w", err)
+	}
+
+	return toEntityOperation(&dbOperation), nil
+}
+
+func (r *OperationPostgres) UpdateOperationStatus(
+	ctx context.Context,
+	requestID string,
+	status entity.OperationStatus,
+) error {
+	if err := r.queries.UpdateOperationStatus(ctx, sqlc.UpdateOperationStatusParams{
+		RequestID: requestID,
+		Status:    string(status),
+	}); err != nil {
+		return fmt.Errorf("update operation status: %w", err)
+	}
+
+	return nil
+}
+
+func (r *OperationPostgres) CompleteOperation(
+	ctx context.Context,
+	requestID string,
+	status entity.OperationStatus,
+	event entity.CallbackEventType,
+	result []byte,
+) error {
+	if err := r.queries.CompleteOperation(ctx, sqlc.CompleteOperationParams{
+		RequestID: requestID,
+		Status:    string(status),
+		Event:     pgtype.Text{String: string(event), Valid: true},
+		Result:    result,
+	}); err != nil {
+		return fmt.Errorf("complete operation: %w", err)
+	}
+
+	return nil
+}
+
+func (r *OperationPostgres) GetOperation(ctx context.Context, requestID string) (*entity.Operation, error) {
+	dbOperation, err := r.queries.GetOperation(ctx, requestID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, entity.ErrOperationNotFound
+		}
+		return nil, fmt.Errorf("get operation: %w", err)
+	}
+
+	return toEntityOperation(&dbOperation), nil
+}
+
+// ListClientOperations returns one page of the client's operations, newest first, and their total number
+func (r *OperationPostgres) ListClientOperations(
+	ctx context.Context,
+	clientID string,
+	skip, limit int,
+) ([]*entity.Operation, int, error) {
+	client := pgtype.Text{String: clientID, Valid: true}
+
+	dbOperations, err := r.queries.ListClientOperations(ctx, sqlc.ListClientOperationsParams{
+		ClientID: client,
+		Limit:    int32(limit),
+		Offset:   int32(skip),
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("list client operations: %w", err)
+	}
+
+	total, err := r.queries.CountClientOperations(ctx, client)
+	if err != nil {
+		return nil, 0, fmt.Errorf("count client operations: %w", err)
+	}
+
+	operations := make([]*entity.Operation, 0, len(dbOperations))
+	for i := range dbOperations {
+		operations = append(operations, toEntityOperation(&dbOperations[i]))
+	}
+
+	return operations, int(total), nil
+}
+
+// DeleteOperationsBefore removes operations not updated since before and returns how many were removed
+func (r *OperationPostgres) DeleteOperationsBefore(ctx context.Context, before time.Time) (int, error) {
+	deleted, err := r.queries.DeleteOperationsBefore(ctx, pgtype.Timestamp{Time: before, Valid: true})
+	if err != nil {
+		return 0, fmt.Errorf("delete operations: %w", err)
+	}
+
+	return int(deleted), nil
+}
@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/repository/sqlc"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PendingQuestionsRepository defines the interface for persistence of undelivered questions callbacks
+type PendingQuestionsRepository interface {
+	SavePendingQuestions(ctx context.Context, pending *entity.PendingQuestions) (*entity.PendingQuestions, error)
+	ListPendingQuestions(ctx context.Context, sessionID string) ([]*entity.PendingQuestions, error)
+	AcknowledgePendingQuestions(ctx context.Context, sessionID, iterationID string) (bool, error)
+}
+
+var _ PendingQuestionsRepository = &PendingQuestionsPostgres{}
+
+// PendingQuestionsPostgres implements PendingQuestionsRepository using PostgreSQL
+type PendingQuestionsPostgres struct {
+	db      *pgxpool.Pool
+	queries *sqlc.Queries
+}
+
+func NewPendingQuestionsPostgres(db *pgxpool.Pool) *PendingQuestionsPostgres {
+	return &PendingQuestionsPostgres{
+		db:      db,
+		queries: sqlc.New(db),
+	}
+}
+
+// SavePendingQuestions stores the undelivered block, replacing an earlier undelivered payload of the same iteration
+func (r *PendingQuestionsPostgres) SavePendingQuestions(
+	ctx context.Context,
+	pending *entity.PendingQuestions,
+) (*entity.PendingQuestions, error) {
+	sessID, err := uuid.Parse(pending.SessionID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid session ID: %w", err)
+	}
+
+	iterID, err := uuid.Parse(pending.IterationID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid iteration ID: %w", err)
+	}
+
+	payload, err := json.Marshal(pending.Questions)
+	if err != nil {
+		return nil, fmt.Errorf("encode pending questions: %w", err)
+	}
+
+	row, err := r.queries.UpsertPendingQuestionDelivery(ctx, sqlc.UpsertPendingQuestionDeliveryParams{
+		SessionID: pgtype.UUID{
+			Bytes: sessID,
+			Valid: true,
+		},
+		IterationID: pgtype.UUID{
+			Bytes: iterID,
+			Valid: true,
+		},
+		RequestID:     pending.RequestID,
+		Payload:       payload,
+		DeliveryError: pending.DeliveryError,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("save pending questions: %w", err)
+	}
+
+	return toEntityPendingQuestions(&row)
+}
+
+// ListPendingQuestions returns the unacknowledged blocks of the session, oldest first
+func (r *PendingQuestionsPostgres) ListPendingQuestions(ctx context.Context, sessionID string) ([]*entity.PendingQuestions, error) {
+	sessID, err := uuid.Parse(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid session ID: %w", err)
+	}
+
+	rows, err := r.queries.ListPendingQuestionDeliveries(ctx, pgtype.UUID{Bytes: sessID, Valid: true})
+	if err != nil {
+		return nil, fmt.Errorf("list pending questions: %w", err)
+	}
+
+	pending := make([]*entity.PendingQuestions, 0, len(rows))
+	for i := range rows {
+		p, err := toEntityPendingQuestions(&rows[i])
+		if err != nil {
+			return nil, err
+		}
+		pending = append(pending, p)
+	}
+
+	return pending, nil
+}
+
+// AcknowledgePendingQuestions marks the block of the iteration delivered and reports whether it was pending
+func (r *PendingQuestionsPostgres) AcknowledgePendingQuestions(ctx context.Context, sessionID, iterationID string) (bool, error) {
+	sessID, err := uuid.Parse(sessionID)
+	if err != nil {
+		return false, fmt.Errorf("invalid session ID: %w", err)
+	}
+
+	iterID, err := uuid.Parse(iterationID)
+	if err != nil {
+		return false, fmt.Errorf("%w: invalid iteration ID", entity.ErrInvalidParameter)
+	}
+
+	acknowledged, err := r.queries.AcknowledgePendingQuestionDelivery(ctx, sqlc.AcknowledgePendingQuestionDeliveryParams{
+		SessionID:   pgtype.UUID{Bytes: sessID, Valid: true},
+		IterationID: pgtype.UUID{Bytes: iterID, Valid: true},
+	})
+	if err != nil {
+		return false, fmt.Errorf("acknowledge pending questions: %w", err)
+	}
+
+	return acknowledged > 0, nil
+}
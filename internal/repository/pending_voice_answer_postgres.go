@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/repository/sqlc"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// PendingVoiceAnswerRepository defines the interface for persistence of voice answers awaiting transcription
+type PendingVoiceAnswerRepository interface {
+	CreatePendingVoiceAnswer(ctx context.Context, sessionID, questionID string, telegramUserID int64, audio []byte) (*entity.PendingVoiceAnswer, error)
+	ClaimPendingVoiceAnswers(ctx context.Context, lease time.Duration, limit int) ([]*entity.PendingVoiceAnswer, error)
+	DeletePendingVoiceAnswer(ctx context.Context, id string) error
+	DeletePendingVoiceAnswersBefore(ctx context.Context, before time.Time) (int, error)
+}
+
+var _ PendingVoiceAnswerRepository = &PendingVoiceAnswerPostgres{}
+
+// PendingVoiceAnswerPostgres implements PendingVoiceAnswerRepository using PostgreSQL
+type PendingVoiceAnswerPostgres struct {
+	db      *pgxpool.Pool
+	queries *sqlc.Queries
+}
+
+func NewPendingVoiceAnswerPostgres(db *pgxpool.Pool) *PendingVoiceAnswerPostgres {
+	return &PendingVoiceAnswerPostgres{
+		db:      db,
+		queries: sqlc.New(db),
+	}
+}
+
+// CreatePendingVoiceAnswer queues a voice answer in the tenant ctx is scoped to
+func (r *PendingVoiceAnswerPostgres) CreatePendingVoiceAnswer(
+	ctx context.Context,
+	sessionID, questionID string,
+	telegramUserID int64,
+	audio []byte,
+) (*entity.PendingVoiceAnswer, error) {
+	sessID, err := uuid.Parse(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid session ID: %w", err)
+	}
+
+	qID, err := uuid.Parse(questionID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid question ID: %w", err)
+	}
+
+	row, err := r.queries.CreatePendingVoiceAnswer(ctx, sqlc.CreatePendingVoiceAnswerParams{
+		TenantID: entity.TenantIDFromContext(ctx),
+		SessionID: pgtype.UUID{
+			Bytes: sessID,
+			Valid: true,
+		},
+		QuestionID: pgtype.UUID{
+			Bytes: qID,
+			Valid: true,
+		},
+		TelegramUserID: telegramUserID,
+		Audio:          audio,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create pending voice answer: %w", err)
+	}
+
+	return toEntityPendingVoiceAnswer(&row), nil
+}
+
+// ClaimPendingVoiceAnswers locks up to limit oldest answers of all tenants for lease;
+// answers still present once the lease ends are claimed again
+func (r *PendingVoiceAnswerPostgres) ClaimPendingVoiceAnswers(
+	ctx context.Context,
+	lease time.Duration,
+	limit int,
+) ([]*entity.PendingVoiceAnswer, error) {
+	rows, err := r.queries.ClaimPendingVoiceAnswers(ctx, sqlc.ClaimPendingVoiceAnswersParams{
+		LeaseSeconds: int32(lease.Seconds()),
+		BatchSize:    int32(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("claim pending voice answers: %w", err)
+	}
+
+	answers := make([]*entity.PendingVoiceAnswer, 0, len(rows))
+	for i := range rows {
+		answers = append(answers, toEntityPendingVoiceAnswer(&rows[i]))
+	}
+
+	return answers, nil
+}
+
+func (r *PendingVoiceAnswerPostgres) DeletePendingVoiceAnswer(ctx context.Context, id string) error {
+	answerID, err := uuid.Parse(id)
+	if err != nil {
+		return fmt.Errorf("invalid pending voice answer ID: %w", err)
+	}
+
+	if err := r.queries.DeletePendingVoiceAnswer(ctx, pgtype.UUID{
+		Bytes: answerID,
+		Valid: true,
+	}); err != nil {
+		return fmt.Errorf("delete pending voice answer: %w", err)
+	}
+
+	return nil
+}
+
+// DeletePendingVoiceAnswersBefore drops answers queued before the given time and returns how many were dropped
+func (r *PendingVoiceAnswerPostgres) DeletePendingVoiceAnswersBefore(ctx context.Context, before time.Time) (int, error) {
+	deleted, err := r.queries.DeletePendingVoiceAnswersBefore(ctx, pgtype.Timestamp{Time: before, Valid: true})
+	if err != nil {
+		return 0, fmt.Errorf("delete expired pending voice answers: %w", err)
+	}
+
+	return int(deleted), nil
+}
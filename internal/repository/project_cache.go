@@ -0,0 +1,210 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/futig/agent-backend/internal/entity"
+)
+
+var _ ProjectRepository = &ProjectListCache{}
+
+// ProjectListCache caches the project lists of Telegram users in front of a ProjectRepository,
+// so flipping through the pages of the project selector does not query the database every time.
+// Lists are cached per tenant, owner filter, user and page for ttl; pins and picks of a user drop
+// the lists of the user, created, deleted and changed projects drop all lists. The cache is kept
+// per replica, so changes made on another replica show up once the entries expire
+type ProjectListCache struct {
+	ProjectRepository
+
+	ttl        time.Duration
+	maxEntries int
+
+	mu      sync.Mutex
+	entries map[projectListKey]projectListEntry
+}
+
+type projectListKey struct {
+	tenantID       string
+	ownerID        string
+	telegramUserID int64
+	pinned         bool
+	skip           int
+	limit          int
+}
+
+type projectListEntry struct {
+	projects  []*entity.Project
+	total     int
+	expiresAt time.Time
+}
+
+// NewProjectListCache wraps the repository with a cache of the project lists of Telegram users
+func NewProjectListCache(repo ProjectRepository, ttl time.Duration, maxEntries int) *ProjectListCache {
+	return &ProjectListCache{
+		ProjectRepository: repo,
+		ttl:               ttl,
+		maxEntries:        maxEntries,
+		entries:           make(map[projectListKey]projectListEntry),
+	}
+}
+
+// List returns a cached page for a Telegram user; lists without a user, i.e. of the API, are not cached
+func (c *ProjectListCache) List(ctx context.Context, skip, limit int, telegramUserID int64) ([]*entity.Project, int, error) {
+	if telegramUserID == 0 {
+		return c.ProjectRepository.List(ctx, skip, limit, telegramUserID)
+	}
+
+	key := listKey(ctx, telegramUserID, false)
+	key.skip, key.limit = skip, limit
+	if projects, total, ok := c.get(key); ok {
+		return projects, total, nil
+	}
+
+	projects, total, err := c.ProjectRepository.List(ctx, skip, limit, telegramUserID)
+	if err != nil {
+		return nil, 0, err
+	}
+	c.set(key, projects, total)
+
+	return cloneProjects(projects), total, nil
+}
+
+func (c *ProjectListCache) ListPinned(ctx context.Context, telegramUserID int64) ([]*entity.Project, error) {
+	key := listKey(ctx, telegramUserID, true)
+	if projects, _, ok := c.get(key); ok {
+		return projects, nil
+	}
+
+	projects, err := c.ProjectRepository.ListPinned(ctx, telegramUserID)
+	if err != nil {
+		return nil, err
+	}
+	c.set(key, projects, len(projects))
+
+	return cloneProjects(projects), nil
+}
+
+func (c *ProjectListCache) Create(ctx context.Context, project entity.Project) (*entity.Project, error) {
+	created, err := c.ProjectRepository.Create(ctx, project)
+	c.invalidateAll()
+	return created, err
+}
+
+func (c *ProjectListCache) Delete(ctx context.Context, id string) error {
+	err := c.ProjectRepository.Delete(ctx, id)
+	c.invalidateAll()
+	return err
+}
+
+func (c *ProjectListCache) SetDescription(ctx context.Context, id, description string) error {
+	err := c.ProjectRepository.SetDescription(ctx, id, description)
+	c.invalidateAll()
+	return err
+}
+
+func (c *ProjectListCache) SetTheme(ctx context.Context, id string, themeID *string) error {
+	err := c.ProjectRepository.SetTheme(ctx, id, themeID)
+	c.invalidateAll()
+	return err
+}
+
+func (c *ProjectListCache) TouchUsage(ctx context.Context, id string, telegramUserID int64) error {
+	err := c.ProjectRepository.TouchUsage(ctx, id, telegramUserID)
+	c.invalidateUser(telegramUserID)
+	return err
+}
+
+func (c *ProjectListCache) Pin(ctx context.Context, id string, telegramUserID int64, maxPins int) (bool, error) {
+	pinned, err := c.ProjectRepository.Pin(ctx, id, telegramUserID, maxPins)
+	c.invalidateUser(telegramUserID)
+	return pinned, err
+}
+
+func (c *ProjectListCache) Unpin(ctx context.Context, id string, telegramUserID int64) (bool, error) {
+	unpinned, err := c.ProjectRepository.Unpin(ctx, id, telegramUserID)
+	c.invalidateUser(telegramUserID)
+	return unpinned, err
+}
+
+// get returns a copy of a cached list, callers mark projects as stale on the returned ones
+func (c *ProjectListCache) get(key projectListKey) ([]*entity.Project, int, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return nil, 0, false
+	}
+	if time.Now().After(entry.expiresAt) {
+		delete(c.entries, key)
+		return nil, 0, false
+	}
+
+	return cloneProjects(entry.projects), entry.total, true
+}
+
+func (c *ProjectListCache) set(key projectListKey, projects []*entity.Project, total int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if len(c.entries) >= c.maxEntries {
+		for k, entry := range c.entries {
+			if now.After(entry.expiresAt) {
+				delete(c.entries, k)
+			}
+		}
+	}
+	// Every entry is still fresh, starting over is cheaper than tracking the oldest one
+	if len(c.entries) >= c.maxEntries {
+		c.entries = make(map[projectListKey]projectListEntry)
+	}
+
+	c.entries[key] = projectListEntry{
+		projects:  cloneProjects(projects),
+		total:     total,
+		expiresAt: now.Add(c.ttl),
+	}
+}
+
+func (c *ProjectListCache) invalidateUser(telegramUserID int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key := range c.entries {
+		if key.telegramUserID == telegramUserID {
+			delete(c.entries, key)
+		}
+	}
+}
+
+func (c *ProjectListCache) invalidateAll() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries = make(map[projectListKey]projectListEntry)
+}
+
+// listKey scopes a list like the queries do: by the tenant and the owner filter of ctx
+func listKey(ctx context.Context, telegramUserID int64, pinned bool) projectListKey {
+	key := projectListKey{
+		tenantID:       entity.TenantIDFromContext(ctx),
+		telegramUserID: telegramUserID,
+		pinned:         pinned,
+	}
+	if ownerID := entity.OwnerIDFromContext(ctx); ownerID != nil {
+		key.ownerID = *ownerID
+	}
+	return key
+}
+
+func cloneProjects(projects []*entity.Project) []*entity.Project {
+	cloned := make([]*entity.Project, 0, len(projects))
+	for _, project := range projects {
+		p := *project
+		cloned = append(cloned, &p)
+	}
+	return cloned
+}
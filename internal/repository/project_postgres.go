@@ -17,8 +17,25 @@ import (
 type ProjectRepository interface {
 	Create(ctx context.Context, project entity.Project) (*entity.Project, error)
 	Get(ctx context.Context, id string) (*entity.Project, error)
-	List(ctx context.Context, skip, limit int) ([]*entity.Project, error)
+	// GetFreshness returns the file count and indexing times of a project; Stale is left to the caller
+	GetFreshness(ctx context.Context, id string) (*entity.ProjectFreshness, error)
+	// List returns a page of projects with their session statistics and the total number of
+	// projects paged through; projects the Telegram user picked are listed first, most recent first,
+	// and the projects the user pinned are left out; a zero telegramUserID keeps creation order
+	List(ctx context.Context, skip, limit int, telegramUserID int64) ([]*entity.Project, int, error)
 	Delete(ctx context.Context, id string) error
+	SetDescription(ctx context.Context, id, description string) error
+	// SetTheme selects the document theme of a project; nil themeID clears it
+	SetTheme(ctx context.Context, id string, themeID *string) error
+	// TouchUsage records that the Telegram user picked the project
+	TouchUsage(ctx context.Context, id string, telegramUserID int64) error
+	// ListPinned returns the projects pinned by the Telegram user in pin order
+	ListPinned(ctx context.Context, telegramUserID int64) ([]*entity.Project, error)
+	// Pin pins a project for the Telegram user; false is returned when the user
+	// already has maxPins pinned projects or the project is pinned
+	Pin(ctx context.Context, id string, telegramUserID int64, maxPins int) (bool, error)
+	// Unpin reports whether the project was pinned
+	Unpin(ctx context.Context, id string, telegramUserID int64) (bool, error)
 }
 
 var _ ProjectRepository = &ProjectPostgres{}
@@ -46,6 +63,8 @@ func (r *ProjectPostgres) Create(ctx context.Context, project entity.Project) (*
 		ID:          pgtype.UUID{Bytes: projectID, Valid: true},
 		Title:       project.Title,
 		Description: pgtype.Text{String: project.Description, Valid: project.Description != ""},
+		TenantID:    entity.TenantIDFromContext(ctx),
+		OwnerID:     ownerFilter(ctx),
 	})
 
 	if err != nil {
@@ -61,7 +80,11 @@ func (r *ProjectPostgres) Get(ctx context.Context, id string) (*entity.Project,
 		return nil, fmt.Errorf("parse project ID: %w", err)
 	}
 
-	result, err := r.queries.GetProject(ctx, pgtype.UUID{Bytes: projectID, Valid: true})
+	result, err := r.queries.GetProject(ctx, sqlc.GetProjectParams{
+		ID:       pgtype.UUID{Bytes: projectID, Valid: true},
+		TenantID: entity.TenantIDFromContext(ctx),
+		OwnerID:  ownerFilter(ctx),
+	})
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, entity.ErrProjectNotFound
@@ -72,22 +95,55 @@ func (r *ProjectPostgres) Get(ctx context.Context, id string) (*entity.Project,
 	return toEntityProject(&result), nil
 }
 
-func (r *ProjectPostgres) List(ctx context.Context, skip, limit int) ([]*entity.Project, error) {
+func (r *ProjectPostgres) GetFreshness(ctx context.Context, id string) (*entity.ProjectFreshness, error) {
+	projectID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, fmt.Errorf("parse project ID: %w", err)
+	}
+
+	result, err := r.queries.GetProjectFreshness(ctx, sqlc.GetProjectFreshnessParams{
+		ID:       pgtype.UUID{Bytes: projectID, Valid: true},
+		TenantID: entity.TenantIDFromContext(ctx),
+		OwnerID:  ownerFilter(ctx),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, entity.ErrProjectNotFound
+		}
+		return nil, fmt.Errorf("get project freshness: %w", err)
+	}
+
+	return toEntityProjectFreshness(&result), nil
+}
+
+func (r *ProjectPostgres) List(ctx context.Context, skip, limit int, telegramUserID int64) ([]*entity.Project, int, error) {
 	results, err := r.queries.ListProjects(ctx, sqlc.ListProjectsParams{
-		Limit:  int32(limit),
-		Offset: int32(skip),
+		TenantID:    entity.TenantIDFromContext(ctx),
+		UserID:      telegramUserID,
+		OwnerID:     ownerFilter(ctx),
+		LimitCount:  int32(limit),
+		OffsetCount: int32(skip),
 	})
 
 	if err != nil {
-		return nil, fmt.Errorf("list projects: %w", err)
+		return nil, 0, fmt.Errorf("list projects: %w", err)
+	}
+
+	total, err := r.queries.CountProjects(ctx, sqlc.CountProjectsParams{
+		TenantID: entity.TenantIDFromContext(ctx),
+		OwnerID:  ownerFilter(ctx),
+		UserID:   telegramUserID,
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("count projects: %w", err)
 	}
 
 	projects := make([]*entity.Project, 0, len(results))
 	for _, result := range results {
-		projects = append(projects, toEntityProject(&result))
+		projects = append(projects, toEntityProjectWithUsage(&result))
 	}
 
-	return projects, nil
+	return projects, int(total), nil
 }
 
 func (r *ProjectPostgres) Delete(ctx context.Context, id string) error {
@@ -96,13 +152,145 @@ func (r *ProjectPostgres) Delete(ctx context.Context, id string) error {
 		return fmt.Errorf("parse project ID: %w", err)
 	}
 
-	err = r.queries.DeleteProject(ctx, pgtype.UUID{Bytes: projectID, Valid: true})
+	rows, err := r.queries.DeleteProject(ctx, sqlc.DeleteProjectParams{
+		ID:       pgtype.UUID{Bytes: projectID, Valid: true},
+		TenantID: entity.TenantIDFromContext(ctx),
+		OwnerID:  ownerFilter(ctx),
+	})
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return entity.ErrProjectNotFound
-		}
 		return fmt.Errorf("delete project: %w", err)
 	}
+	if rows == 0 {
+		return entity.ErrProjectNotFound
+	}
 
 	return nil
 }
+
+func (r *ProjectPostgres) SetDescription(ctx context.Context, id, description string) error {
+	projectID, err := uuid.Parse(id)
+	if err != nil {
+		return fmt.Errorf("parse project ID: %w", err)
+	}
+
+	rows, err := r.queries.SetProjectDescription(ctx, sqlc.SetProjectDescriptionParams{
+		ID:          pgtype.UUID{Bytes: projectID, Valid: true},
+		Description: pgtype.Text{String: description, Valid: description != ""},
+		TenantID:    entity.TenantIDFromContext(ctx),
+		OwnerID:     ownerFilter(ctx),
+	})
+	if err != nil {
+		return fmt.Errorf("set project description: %w", err)
+	}
+	if rows == 0 {
+		return entity.ErrProjectNotFound
+	}
+
+	return nil
+}
+
+func (r *ProjectPostgres) SetTheme(ctx context.Context, id string, themeID *string) error {
+	projectID, err := uuid.Parse(id)
+	if err != nil {
+		return fmt.Errorf("parse project ID: %w", err)
+	}
+
+	var dbThemeID pgtype.UUID
+	if themeID != nil {
+		parsed, err := uuid.Parse(*themeID)
+		if err != nil {
+			return fmt.Errorf("parse theme ID: %w", err)
+		}
+		dbThemeID = pgtype.UUID{Bytes: parsed, Valid: true}
+	}
+
+	rows, err := r.queries.SetProjectTheme(ctx, sqlc.SetProjectThemeParams{
+		ID:       pgtype.UUID{Bytes: projectID, Valid: true},
+		ThemeID:  dbThemeID,
+		TenantID: entity.TenantIDFromContext(ctx),
+		OwnerID:  ownerFilter(ctx),
+	})
+	if err != nil {
+		return fmt.Errorf("set project theme: %w", err)
+	}
+	if rows == 0 {
+		return entity.ErrProjectNotFound
+	}
+
+	return nil
+}
+
+func (r *ProjectPostgres) TouchUsage(ctx context.Context, id string, telegramUserID int64) error {
+	projectID, err := uuid.Parse(id)
+	if err != nil {
+		return fmt.Errorf("parse project ID: %w", err)
+	}
+
+	if err := r.queries.TouchTelegramProjectUsage(ctx, sqlc.TouchTelegramProjectUsageParams{
+		TenantID:  entity.TenantIDFromContext(ctx),
+		UserID:    telegramUserID,
+		ProjectID: pgtype.UUID{Bytes: projectID, Valid: true},
+	}); err != nil {
+		return fmt.Errorf("touch project usage: %w", err)
+	}
+
+	return nil
+}
+
+func (r *ProjectPostgres) ListPinned(ctx context.Context, telegramUserID int64) ([]*entity.Project, error) {
+	results, err := r.queries.ListPinnedProjects(ctx, sqlc.ListPinnedProjectsParams{
+		TenantID: entity.TenantIDFromContext(ctx),
+		UserID:   telegramUserID,
+		OwnerID:  ownerFilter(ctx),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list pinned projects: %w", err)
+	}
+
+	projects := make([]*entity.Project, 0, len(results))
+	for _, result := range results {
+		row := sqlc.ListProjectsRow(result)
+		project := toEntityProjectWithUsage(&row)
+		project.Pinned = true
+		projects = append(projects, project)
+	}
+
+	return projects, nil
+}
+
+func (r *ProjectPostgres) Pin(ctx context.Context, id string, telegramUserID int64, maxPins int) (bool, error) {
+	projectID, err := uuid.Parse(id)
+	if err != nil {
+		return false, fmt.Errorf("parse project ID: %w", err)
+	}
+
+	rows, err := r.queries.PinProject(ctx, sqlc.PinProjectParams{
+		TenantID:  entity.TenantIDFromContext(ctx),
+		UserID:    telegramUserID,
+		ProjectID: pgtype.UUID{Bytes: projectID, Valid: true},
+		MaxPins:   int32(maxPins),
+	})
+	if err != nil {
+		return false, fmt.Errorf("pin project: %w", err)
+	}
+
+	return rows > 0, nil
+}
+
+func (r *ProjectPostgres) Unpin(ctx context.Context, id string, telegramUserID int64) (bool, error) {
+	projectID, err := uuid.Parse(id)
+	if err != nil {
+		return false, fmt.Errorf("parse project ID: %w", err)
+	}
+
+	rows, err := r.queries.UnpinProject(ctx, sqlc.UnpinProjectParams{
+		TenantID:  entity.TenantIDFromContext(ctx),
+		UserID:    telegramUserID,
+		ProjectID: pgtype.UUID{Bytes: projectID, Valid: true},
+	})
+	if err != nil {
+		return false, fmt.Errorf("unpin project: %w", err)
+	}
+
+	return rows > 0, nil
+}
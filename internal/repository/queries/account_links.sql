@@ -0,0 +1,26 @@
+-- name: CreateAccountLinkCode :one
+INSERT INTO account_link_codes (tenant_id, code, telegram_user_id, expires_at)
+VALUES ($1, $2, $3, NOW() + make_interval(secs => sqlc.arg(ttl_seconds)::int))
+RETURNING *;
+
+-- name: RedeemAccountLinkCode :one
+-- A code is redeemed once and only before it expires
+UPDATE account_link_codes
+SET redeemed_at = NOW()
+WHERE tenant_id = $1 AND code = $2 AND redeemed_at IS NULL AND expires_at > NOW()
+RETURNING *;
+
+-- name: UpsertAccountLink :one
+INSERT INTO account_links (tenant_id, client_id, telegram_user_id)
+VALUES ($1, $2, $3)
+ON CONFLICT (tenant_id, client_id) DO UPDATE
+SET telegram_user_id = EXCLUDED.telegram_user_id,
+    created_at = NOW()
+RETURNING *;
+
+-- name: GetAccountLinkSession :one
+SELECT l.telegram_user_id, t.session_id
+FROM account_links l
+LEFT JOIN telegram_sessions t ON t.tenant_id = l.tenant_id AND t.user_id = l.telegram_user_id
+WHERE l.tenant_id = $1 AND l.client_id = $2;
+
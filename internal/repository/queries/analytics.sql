@@ -0,0 +1,30 @@
+-- name: ListAnalyticsAnswers :many
+-- Only lengths and flags of the texts leave the database; demo sessions are not analyzed
+SELECT s.id AS session_id,
+       s.owner_id,
+       s.type AS session_type,
+       s.status AS session_status,
+       s.project_id IS NOT NULL AS has_project,
+       s.created_at AS session_created_at,
+       i.iteration_number,
+       i.title = 'Дополнительные вопросы' AS additional,
+       q.question_number,
+       q.status,
+       q.answer_type,
+       q.skip_reason,
+       q.parent_question_id IS NOT NULL AS follow_up,
+       char_length(q.question) AS question_chars,
+       COALESCE(char_length(q.answer), 0)::int AS answer_chars,
+       COALESCE(array_length(regexp_split_to_array(NULLIF(btrim(q.answer), ''), '\s+'), 1), 0)::int AS answer_words,
+       (q.raw_answer IS NOT NULL AND q.raw_answer IS DISTINCT FROM q.answer) AS normalized,
+       q.created_at,
+       q.answered_at
+FROM iteration_questions q
+JOIN session_iterations i ON i.id = q.iteration_id
+JOIN sessions s ON s.id = i.session_id
+WHERE s.tenant_id = sqlc.arg(tenant_id)
+  AND NOT s.is_demo
+  AND s.created_at >= sqlc.arg(from_time)::timestamp
+  AND s.created_at < sqlc.arg(to_time)::timestamp
+ORDER BY s.created_at, s.id, i.iteration_number, q.question_number
+LIMIT sqlc.arg(max_rows);
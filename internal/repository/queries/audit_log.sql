@@ -0,0 +1,4 @@
+-- name: CreateAuditEvent :one
+INSERT INTO audit_log (session_id, event_type, details, created_at)
+VALUES ($1, $2, $3, NOW())
+RETURNING *;
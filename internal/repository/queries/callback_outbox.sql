@@ -0,0 +1,55 @@
+-- name: CreateCallbackDelivery :one
+-- The first attempt is made right away by the sender, so the event is stored as already attempted
+-- and leased until next_attempt_at
+INSERT INTO callback_outbox (tenant_id, request_id, callback_url, event, payload, status, attempts, next_attempt_at, created_at, updated_at)
+VALUES (
+    sqlc.arg(tenant_id), sqlc.arg(request_id), sqlc.arg(callback_url), sqlc.arg(event), sqlc.arg(payload),
+    'pending', 1, NOW() + make_interval(secs => sqlc.arg(lease_seconds)::int), NOW(), NOW()
+)
+RETURNING *;
+
+-- name: ClaimDueCallbackDeliveries :many
+-- Leases the due events of all tenants for lease_seconds, events not settled by then are claimed again
+UPDATE callback_outbox
+SET attempts = attempts + 1,
+    next_attempt_at = NOW() + make_interval(secs => sqlc.arg(lease_seconds)::int),
+    updated_at = NOW()
+WHERE id IN (
+    SELECT id FROM callback_outbox
+    WHERE status = 'pending' AND next_attempt_at <= NOW()
+    ORDER BY next_attempt_at
+    LIMIT sqlc.arg(batch_size)
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: MarkCallbackDelivered :exec
+UPDATE callback_outbox
+SET status = 'delivered',
+    last_error = NULL,
+    delivered_at = NOW(),
+    updated_at = NOW()
+WHERE id = $1;
+
+-- name: ScheduleCallbackRetry :exec
+UPDATE callback_outbox
+SET next_attempt_at = NOW() + make_interval(secs => sqlc.arg(delay_seconds)::int),
+    last_error = sqlc.arg(last_error),
+    updated_at = NOW()
+WHERE id = sqlc.arg(id) AND status = 'pending';
+
+-- name: MarkCallbackFailed :exec
+UPDATE callback_outbox
+SET status = 'failed',
+    last_error = $2,
+    updated_at = NOW()
+WHERE id = $1;
+
+-- name: ListCallbackDeliveries :many
+SELECT * FROM callback_outbox
+WHERE tenant_id = $1 AND request_id = $2
+ORDER BY created_at;
+
+-- name: DeleteSettledCallbackDeliveriesBefore :execrows
+DELETE FROM callback_outbox
+WHERE status <> 'pending' AND updated_at < sqlc.arg(before)::timestamp;
@@ -0,0 +1,16 @@
+-- name: ListFeatureFlagOverrides :many
+SELECT * FROM feature_flag_overrides
+ORDER BY name;
+
+-- name: UpsertFeatureFlagOverride :one
+INSERT INTO feature_flag_overrides (name, rollout_percent, disabled, updated_at)
+VALUES ($1, $2, $3, NOW())
+ON CONFLICT (name) DO UPDATE
+SET rollout_percent = EXCLUDED.rollout_percent,
+    disabled = EXCLUDED.disabled,
+    updated_at = EXCLUDED.updated_at
+RETURNING *;
+
+-- name: DeleteFeatureFlagOverride :execrows
+DELETE FROM feature_flag_overrides
+WHERE name = $1;
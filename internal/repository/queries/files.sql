@@ -1,13 +1,21 @@
 -- name: AddFile :one
-INSERT INTO project_files (id, project_id, filename, size, content_type)
-VALUES ($1, $2, $3, $4, $5)
+INSERT INTO project_files (id, project_id, filename, size, content_type, object_key)
+VALUES ($1, $2, $3, $4, $5, $6)
 RETURNING *;
 
 -- name: GetFiles :many
 SELECT *
 FROM project_files
 WHERE project_id = $1
+  AND project_id IN (SELECT id FROM projects WHERE tenant_id = $2)
 ORDER BY created_at ASC;
 
+-- name: GetProjectFile :one
+SELECT *
+FROM project_files
+WHERE id = $1 AND project_id = $2;
+
 -- name: DeleteProjectFile :exec
-DELETE FROM projects WHERE id = $1;
+DELETE FROM project_files
+WHERE id = $1
+  AND project_id IN (SELECT id FROM projects WHERE tenant_id = $2);
@@ -0,0 +1,13 @@
+-- name: CreateIncident :exec
+INSERT INTO incidents (code, correlation_id, level, message, fields)
+VALUES ($1, $2, $3, $4, $5);
+
+-- name: ListIncidentsByCode :many
+SELECT * FROM incidents
+WHERE code = $1
+ORDER BY created_at DESC
+LIMIT $2;
+
+-- name: DeleteIncidentsBefore :execrows
+DELETE FROM incidents
+WHERE created_at < $1;
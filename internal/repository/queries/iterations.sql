@@ -39,3 +39,13 @@ SELECT si.* FROM session_iterations as si
 JOIN sessions as ss on ss.id = si.session_id
 WHERE si.session_id = $1 AND si.iteration_number = ss.current_iteration
 LIMIT 1;
+
+-- name: DeleteIterationsBySession :execrows
+WITH reset AS (
+    UPDATE sessions
+    SET current_iteration = 1,
+        updated_at = NOW()
+    WHERE id = $1
+)
+DELETE FROM session_iterations
+WHERE session_id = $1;
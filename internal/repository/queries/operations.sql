@@ -0,0 +1,46 @@
+-- name: CreateOperation :one
+-- A reused request ID restarts the operation
+INSERT INTO operations (request_id, client_id, kind, session_id, status, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, NOW(), NOW())
+ON CONFLICT (request_id) DO UPDATE
+SET client_id = EXCLUDED.client_id,
+    kind = EXCLUDED.kind,
+    session_id = EXCLUDED.session_id,
+    status = EXCLUDED.status,
+    event = NULL,
+    result = NULL,
+    created_at = NOW(),
+    updated_at = NOW()
+RETURNING *;
+
+-- name: UpdateOperationStatus :exec
+UPDATE operations
+SET status = $2,
+    updated_at = NOW()
+WHERE request_id = $1;
+
+-- name: CompleteOperation :exec
+UPDATE operations
+SET status = $2,
+    event = $3,
+    result = $4,
+    updated_at = NOW()
+WHERE request_id = $1;
+
+-- name: GetOperation :one
+SELECT * FROM operations
+WHERE request_id = $1;
+
+-- name: ListClientOperations :many
+SELECT * FROM operations
+WHERE client_id = $1
+ORDER BY created_at DESC
+LIMIT $2 OFFSET $3;
+
+-- name: CountClientOperations :one
+SELECT COUNT(*) FROM operations
+WHERE client_id = $1;
+
+-- name: DeleteOperationsBefore :execrows
+DELETE FROM operations
+WHERE updated_at < $1;
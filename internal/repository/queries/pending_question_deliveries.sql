@@ -0,0 +1,21 @@
+-- name: UpsertPendingQuestionDelivery :one
+-- A block that fails to be delivered again replaces the earlier payload and is pending again
+INSERT INTO pending_question_deliveries (session_id, iteration_id, request_id, payload, delivery_error, created_at)
+VALUES ($1, $2, $3, $4, $5, NOW())
+ON CONFLICT (session_id, iteration_id) DO UPDATE
+SET request_id = EXCLUDED.request_id,
+    payload = EXCLUDED.payload,
+    delivery_error = EXCLUDED.delivery_error,
+    created_at = NOW(),
+    acknowledged_at = NULL
+RETURNING *;
+
+-- name: ListPendingQuestionDeliveries :many
+SELECT * FROM pending_question_deliveries
+WHERE session_id = $1 AND acknowledged_at IS NULL
+ORDER BY created_at ASC;
+
+-- name: AcknowledgePendingQuestionDelivery :execrows
+UPDATE pending_question_deliveries
+SET acknowledged_at = NOW()
+WHERE session_id = $1 AND iteration_id = $2 AND acknowledged_at IS NULL;
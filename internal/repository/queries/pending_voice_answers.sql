@@ -0,0 +1,27 @@
+-- name: CreatePendingVoiceAnswer :one
+INSERT INTO pending_voice_answers (tenant_id, session_id, question_id, telegram_user_id, audio, created_at)
+VALUES ($1, $2, $3, $4, $5, NOW())
+RETURNING *;
+
+-- name: ClaimPendingVoiceAnswers :many
+-- Locks the oldest unlocked answers of all tenants for lease_seconds, so that concurrent
+-- workers never transcribe the same answer
+UPDATE pending_voice_answers
+SET locked_until = NOW() + make_interval(secs => sqlc.arg(lease_seconds)::int)
+WHERE id IN (
+    SELECT id
+    FROM pending_voice_answers
+    WHERE locked_until IS NULL OR locked_until < NOW()
+    ORDER BY created_at
+    LIMIT sqlc.arg(batch_size)
+    FOR UPDATE SKIP LOCKED
+)
+RETURNING *;
+
+-- name: DeletePendingVoiceAnswer :exec
+DELETE FROM pending_voice_answers
+WHERE id = $1;
+
+-- name: DeletePendingVoiceAnswersBefore :execrows
+DELETE FROM pending_voice_answers
+WHERE created_at < sqlc.arg(before)::timestamp;
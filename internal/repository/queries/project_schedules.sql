@@ -0,0 +1,45 @@
+-- name: CreateProjectSchedule :one
+INSERT INTO project_schedules (project_id, cron_expr, telegram_user_id, user_goal, next_run_at, timezone)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING *;
+
+-- name: ListProjectSchedules :many
+SELECT * FROM project_schedules
+WHERE project_id = $1
+ORDER BY created_at ASC;
+
+-- name: ListDueProjectSchedules :many
+SELECT * FROM project_schedules
+WHERE next_run_at <= $1
+ORDER BY next_run_at ASC;
+
+-- name: ListTelegramUserProjectSchedules :many
+SELECT ps.* FROM project_schedules ps
+JOIN projects p ON p.id = ps.project_id
+WHERE ps.telegram_user_id = $1 AND p.tenant_id = $2
+ORDER BY ps.created_at ASC;
+
+-- name: ClaimProjectSchedule :one
+UPDATE project_schedules
+SET next_run_at = $3,
+    last_run_at = NOW(),
+    updated_at = NOW()
+WHERE id = $1 AND next_run_at = $2
+RETURNING *;
+
+-- name: SetProjectScheduleLastSession :exec
+UPDATE project_schedules
+SET last_session_id = $2,
+    updated_at = NOW()
+WHERE id = $1;
+
+-- name: SetProjectScheduleTimezone :exec
+UPDATE project_schedules
+SET timezone = $2,
+    next_run_at = $3,
+    updated_at = NOW()
+WHERE id = $1;
+
+-- name: DeleteProjectSchedule :execrows
+DELETE FROM project_schedules
+WHERE id = $1 AND project_id = $2;
@@ -1,18 +1,123 @@
 -- name: CreateProject :one
-INSERT INTO projects (id, title, description, created_at)
-VALUES ($1, $2, $3, NOW())
+INSERT INTO projects (id, title, description, created_at, tenant_id, owner_id)
+VALUES ($1, $2, $3, NOW(), $4, $5)
 RETURNING *;
 
 -- name: GetProject :one
+-- A user sees the projects they own and the projects nobody owns; a NULL owner_id sees the whole tenant
 SELECT *
 FROM projects
-WHERE id = $1;
+WHERE id = sqlc.arg(id) AND tenant_id = sqlc.arg(tenant_id)
+  AND (sqlc.narg(owner_id)::UUID IS NULL OR owner_id IS NULL OR owner_id = sqlc.narg(owner_id));
+
+-- name: GetProjectFreshness :one
+-- Files are saved once the RAG service indexed them, so their creation times tell how old the indexed materials are
+SELECT COUNT(f.id)::INT AS file_count,
+       MAX(f.created_at)::TIMESTAMP AS last_indexed_at,
+       MIN(f.created_at)::TIMESTAMP AS oldest_file_at
+FROM projects p
+LEFT JOIN project_files f ON f.project_id = p.id
+WHERE p.id = sqlc.arg(id) AND p.tenant_id = sqlc.arg(tenant_id)
+  AND (sqlc.narg(owner_id)::UUID IS NULL OR p.owner_id IS NULL OR p.owner_id = sqlc.narg(owner_id))
+GROUP BY p.id;
 
 -- name: ListProjects :many
-SELECT *
-FROM projects
-ORDER BY created_at DESC
-LIMIT $1 OFFSET $2;
+SELECT p.id, p.title, p.description, p.created_at, p.tenant_id, p.theme_id,
+       COALESCE(s.session_count, 0)::BIGINT AS session_count,
+       s.last_session_at,
+       u.last_used_at,
+       f.last_indexed_at
+FROM projects p
+LEFT JOIN (
+    SELECT project_id, COUNT(*) AS session_count, MAX(created_at) AS last_session_at
+    FROM sessions
+    WHERE tenant_id = sqlc.arg(tenant_id) AND NOT is_demo
+    GROUP BY project_id
+) s ON s.project_id = p.id
+LEFT JOIN (
+    SELECT project_id, MAX(created_at) AS last_indexed_at
+    FROM project_files
+    GROUP BY project_id
+) f ON f.project_id = p.id
+LEFT JOIN telegram_project_usage u
+    ON u.project_id = p.id AND u.tenant_id = p.tenant_id AND u.user_id = sqlc.arg(user_id)
+WHERE p.tenant_id = sqlc.arg(tenant_id)
+  AND (sqlc.narg(owner_id)::UUID IS NULL OR p.owner_id IS NULL OR p.owner_id = sqlc.narg(owner_id))
+  AND NOT EXISTS (
+    SELECT 1 FROM telegram_project_pins pn
+    WHERE pn.tenant_id = p.tenant_id AND pn.user_id = sqlc.arg(user_id) AND pn.project_id = p.id
+  )
+ORDER BY u.last_used_at DESC NULLS LAST, p.created_at DESC
+LIMIT sqlc.arg(limit_count) OFFSET sqlc.arg(offset_count);
+
+-- name: CountProjects :one
+-- Counts the projects ListProjects pages through, i.e. without the projects pinned by the user
+SELECT COUNT(*)
+FROM projects p
+WHERE p.tenant_id = sqlc.arg(tenant_id)
+  AND (sqlc.narg(owner_id)::UUID IS NULL OR p.owner_id IS NULL OR p.owner_id = sqlc.narg(owner_id))
+  AND NOT EXISTS (
+    SELECT 1 FROM telegram_project_pins pn
+    WHERE pn.tenant_id = p.tenant_id AND pn.user_id = sqlc.arg(user_id) AND pn.project_id = p.id
+  );
+
+-- name: ListPinnedProjects :many
+SELECT p.id, p.title, p.description, p.created_at, p.tenant_id, p.theme_id,
+       COALESCE(s.session_count, 0)::BIGINT AS session_count,
+       s.last_session_at,
+       u.last_used_at,
+       f.last_indexed_at
+FROM telegram_project_pins pn
+JOIN projects p ON p.id = pn.project_id
+LEFT JOIN (
+    SELECT project_id, COUNT(*) AS session_count, MAX(created_at) AS last_session_at
+    FROM sessions
+    WHERE tenant_id = sqlc.arg(tenant_id) AND NOT is_demo
+    GROUP BY project_id
+) s ON s.project_id = p.id
+LEFT JOIN (
+    SELECT project_id, MAX(created_at) AS last_indexed_at
+    FROM project_files
+    GROUP BY project_id
+) f ON f.project_id = p.id
+LEFT JOIN telegram_project_usage u
+    ON u.project_id = p.id AND u.tenant_id = pn.tenant_id AND u.user_id = pn.user_id
+WHERE pn.tenant_id = sqlc.arg(tenant_id) AND pn.user_id = sqlc.arg(user_id)
+  AND (sqlc.narg(owner_id)::UUID IS NULL OR p.owner_id IS NULL OR p.owner_id = sqlc.narg(owner_id))
+ORDER BY pn.created_at;
+
+-- name: DeleteProject :execrows
+DELETE FROM projects
+WHERE id = sqlc.arg(id) AND tenant_id = sqlc.arg(tenant_id)
+  AND (sqlc.narg(owner_id)::UUID IS NULL OR owner_id IS NULL OR owner_id = sqlc.narg(owner_id));
+
+-- name: SetProjectTheme :execrows
+UPDATE projects
+SET theme_id = sqlc.narg(theme_id)
+WHERE id = sqlc.arg(id) AND tenant_id = sqlc.arg(tenant_id)
+  AND (sqlc.narg(owner_id)::UUID IS NULL OR owner_id IS NULL OR owner_id = sqlc.narg(owner_id));
+
+-- name: SetProjectDescription :execrows
+UPDATE projects
+SET description = sqlc.narg(description)
+WHERE id = sqlc.arg(id) AND tenant_id = sqlc.arg(tenant_id)
+  AND (sqlc.narg(owner_id)::UUID IS NULL OR owner_id IS NULL OR owner_id = sqlc.narg(owner_id));
+
+-- name: TouchTelegramProjectUsage :exec
+INSERT INTO telegram_project_usage (tenant_id, user_id, project_id, last_used_at)
+VALUES ($1, $2, $3, NOW())
+ON CONFLICT (tenant_id, user_id, project_id) DO UPDATE SET last_used_at = EXCLUDED.last_used_at;
+
+-- name: PinProject :execrows
+-- Nothing is inserted once the user has max_pins pinned projects
+INSERT INTO telegram_project_pins (tenant_id, user_id, project_id)
+SELECT sqlc.arg(tenant_id)::VARCHAR, sqlc.arg(user_id)::BIGINT, sqlc.arg(project_id)::UUID
+WHERE (
+    SELECT COUNT(*) FROM telegram_project_pins
+    WHERE tenant_id = sqlc.arg(tenant_id) AND user_id = sqlc.arg(user_id)
+) < sqlc.arg(max_pins)::INT
+ON CONFLICT (tenant_id, user_id, project_id) DO NOTHING;
 
--- name: DeleteProject :exec
-DELETE FROM projects WHERE id = $1;
+-- name: UnpinProject :execrows
+DELETE FROM telegram_project_pins
+WHERE tenant_id = $1 AND user_id = $2 AND project_id = $3;
@@ -0,0 +1,18 @@
+-- name: GetQuestionExplanation :one
+SELECT * FROM question_explanations
+WHERE question_id = $1;
+
+-- name: SaveQuestionExplanation :one
+-- Explanations generated concurrently for the same question keep the latest one
+INSERT INTO question_explanations (question_id, explanation)
+VALUES ($1, $2)
+ON CONFLICT (question_id) DO UPDATE SET explanation = EXCLUDED.explanation, created_at = NOW()
+RETURNING *;
+
+-- name: StartExplanationCooldown :one
+-- Starts the cooldown of the subject unless it is still running; no row means it is
+INSERT INTO explanation_cooldowns (tenant_id, subject, requested_at)
+VALUES (sqlc.arg(tenant_id), sqlc.arg(subject), NOW())
+ON CONFLICT (tenant_id, subject) DO UPDATE SET requested_at = NOW()
+WHERE explanation_cooldowns.requested_at <= NOW() - make_interval(secs => sqlc.arg(cooldown_seconds)::int)
+RETURNING requested_at;
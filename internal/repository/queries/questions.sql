@@ -5,9 +5,12 @@ INSERT INTO iteration_questions (
     question_number,
     status,
     question,
-    explanation
+    explanation,
+    options,
+    parent_question_id,
+    answer_type
 ) VALUES (
-    $1, $2, $3, $4, $5, $6
+    $1, $2, $3, $4, $5, $6, $7, $8, $9
 )
 RETURNING *;
 
@@ -18,11 +21,26 @@ INSERT INTO iteration_questions (
     question_number,
     status,
     question,
-    explanation
+    explanation,
+    options,
+    parent_question_id,
+    answer_type
 ) VALUES (
-    $1, $2, $3, $4, $5, $6
+    $1, $2, $3, $4, $5, $6, $7, $8, $9
 );
 
+-- name: DeferQuestion :exec
+UPDATE iteration_questions
+SET status = 'DEFERRED'
+WHERE id = $1 AND status = 'UNANSWERED';
+
+-- name: GetDeferredQuestions :many
+SELECT iq.* FROM iteration_questions iq
+JOIN session_iterations si ON si.id = iq.iteration_id
+WHERE si.session_id = $1
+  AND iq.status = 'DEFERRED'
+ORDER BY si.iteration_number ASC, iq.question_number ASC;
+
 -- name: GetQuestionByID :one
 SELECT * FROM iteration_questions
 WHERE id = $1;
@@ -38,21 +56,46 @@ JOIN session_iterations si ON si.id = iq.iteration_id
 WHERE si.session_id = $1
 ORDER BY si.iteration_number ASC, iq.question_number ASC;
 
--- name: UpdateQuestionAnswer :exec
+-- name: UpdateQuestionAnswer :execrows
 UPDATE iteration_questions
 SET answer = $2,
+    raw_answer = $3,
     status = 'ANSWERED',
+    skip_reason = NULL,
     answered_at = NOW()
-WHERE id = $1;
+WHERE id = $1
+  AND iteration_id IN (SELECT id FROM session_iterations WHERE session_id = $4);
 
 -- name: SkipQustion :exec
 UPDATE iteration_questions
 SET status = 'SKIPED'
-WHERE id = $1 AND status = 'UNANSWERED';
+WHERE id = $1 AND status IN ('UNANSWERED', 'DEFERRED');
+
+-- name: SetQuestionSkipReason :execrows
+UPDATE iteration_questions
+SET skip_reason = $2
+WHERE id = $1 AND status = 'SKIPED'
+  AND iteration_id IN (SELECT id FROM session_iterations WHERE session_id = $3);
+
+-- name: SkipUnansweredSessionQuestions :execrows
+UPDATE iteration_questions
+SET status = 'SKIPED'
+WHERE status IN ('UNANSWERED', 'DEFERRED')
+  AND iteration_id IN (SELECT id FROM session_iterations WHERE session_id = $1);
 
 -- name: GetUnansweredQuestions :many
 SELECT iq.* FROM iteration_questions iq
 JOIN session_iterations si ON si.id = iq.iteration_id
 WHERE si.session_id = $1
-  AND (iq.status = 'UNANSWERED' OR iq.status = 'SKIPED')
+  AND iq.status IN ('UNANSWERED', 'SKIPED', 'DEFERRED')
 ORDER BY si.iteration_number ASC, iq.question_number ASC;
+
+-- name: AnswerOpenQuestion :execrows
+-- Saves an answer unless the question has been answered meanwhile
+UPDATE iteration_questions
+SET answer = $2,
+    raw_answer = $3,
+    status = 'ANSWERED',
+    skip_reason = NULL,
+    answered_at = NOW()
+WHERE id = $1 AND status <> 'ANSWERED';
@@ -0,0 +1,48 @@
+-- name: RecordQuotaUsage :one
+INSERT INTO quota_usage_events (tenant_id, subject, kind)
+VALUES ($1, $2, $3)
+RETURNING id;
+
+-- name: DeleteQuotaUsage :execrows
+DELETE FROM quota_usage_events
+WHERE id = $1 AND tenant_id = $2;
+
+-- name: EnsureQuotaCounter :exec
+-- Creates the counter of the period from the usage recorded so far, so a limit enabled
+-- within the period counts the earlier usage
+INSERT INTO quota_counters (tenant_id, kind, period_start, scope, subject, used)
+SELECT sqlc.arg(tenant_id), sqlc.arg(kind), sqlc.arg(period_start)::timestamp, sqlc.arg(scope), sqlc.arg(subject), COUNT(*)
+FROM quota_usage_events
+WHERE tenant_id = sqlc.arg(tenant_id) AND kind = sqlc.arg(kind) AND created_at >= sqlc.arg(period_start)::timestamp
+  AND (sqlc.arg(scope) = 'tenant' OR subject = sqlc.arg(subject))
+ON CONFLICT DO NOTHING;
+
+-- name: IncrementQuotaCounter :execrows
+-- Counts one usage unless the limit is reached, 0 disabling it; the row lock serializes
+-- concurrent reservations, which see the count of each other
+UPDATE quota_counters
+SET used = used + 1
+WHERE tenant_id = sqlc.arg(tenant_id) AND kind = sqlc.arg(kind) AND period_start = sqlc.arg(period_start)::timestamp
+  AND scope = sqlc.arg(scope) AND subject = sqlc.arg(subject)
+  AND (sqlc.arg(max_used)::int = 0 OR used < sqlc.arg(max_used)::int);
+
+-- name: DecrementQuotaCounter :exec
+UPDATE quota_counters
+SET used = GREATEST(used - 1, 0)
+WHERE tenant_id = sqlc.arg(tenant_id) AND kind = sqlc.arg(kind) AND period_start = sqlc.arg(period_start)::timestamp
+  AND scope = sqlc.arg(scope) AND subject = sqlc.arg(subject);
+
+-- name: CountQuotaUsage :one
+-- Counts the usage of the whole tenant and of one of its subjects in a single pass
+SELECT COUNT(*) AS tenant_count,
+       COUNT(*) FILTER (WHERE subject = sqlc.arg(subject)) AS subject_count
+FROM quota_usage_events
+WHERE tenant_id = sqlc.arg(tenant_id) AND kind = sqlc.arg(kind) AND created_at >= sqlc.arg(since)::timestamp;
+
+-- name: ListQuotaUsageBySubject :many
+SELECT subject, COUNT(*) AS used
+FROM quota_usage_events
+WHERE tenant_id = sqlc.arg(tenant_id) AND kind = sqlc.arg(kind) AND created_at >= sqlc.arg(since)::timestamp
+GROUP BY subject
+ORDER BY used DESC, subject
+LIMIT sqlc.arg(max_subjects);
@@ -0,0 +1,85 @@
+-- name: PurgeSessionsContent :many
+-- Clears the goal, context and result of the sessions of a tenant without activity since before.
+-- The rows stay as stubs keeping status, type and timestamps for the metrics; sessions still in
+-- progress are canceled, since their content is gone
+UPDATE sessions
+SET user_goal = NULL,
+    project_context = NULL,
+    project_context_compressed = NULL,
+    result = NULL,
+    error = NULL,
+    current_question_id = NULL,
+    status = CASE WHEN status IN ('DONE', 'PARTIAL', 'ERROR', 'CANCELED') THEN status ELSE 'CANCELED' END,
+    purged_at = NOW()
+WHERE tenant_id = sqlc.arg(tenant_id)
+  AND purged_at IS NULL
+  AND NOT is_demo
+  AND GREATEST(updated_at, last_activity_at) < sqlc.arg(before)::timestamp
+RETURNING id;
+
+-- name: PurgeSessionQuestions :execrows
+-- Keeps the questions with their status, type and skip reason, so answer rates stay countable
+UPDATE iteration_questions q
+SET question = '',
+    explanation = '',
+    answer = NULL,
+    raw_answer = NULL,
+    options = '{}'
+FROM session_iterations i
+WHERE q.iteration_id = i.id
+  AND i.session_id = ANY(sqlc.arg(session_ids)::uuid[]);
+
+-- name: PurgeSessionMessages :execrows
+UPDATE session_messages
+SET message_text = '',
+    message_text_compressed = NULL,
+    raw_message_text = NULL
+WHERE session_id = ANY(sqlc.arg(session_ids)::uuid[]);
+
+-- name: PurgeSessionResultVersions :many
+-- Keeps size and checksum of the versions and returns the blob keys the caller removes
+WITH purged AS (
+    SELECT id, object_key
+    FROM session_result_versions
+    WHERE session_id = ANY(sqlc.arg(session_ids)::uuid[]) AND storage <> 'purged'
+    FOR UPDATE
+)
+UPDATE session_result_versions v
+SET storage = 'purged',
+    object_key = NULL,
+    content = NULL
+FROM purged p
+WHERE v.id = p.id
+RETURNING p.object_key, v.size_bytes;
+
+-- name: DeleteSessionsDerivedContent :exec
+-- Removes the content derived from the answers: translations, sections, the conversation log,
+-- facts, comments, delta baselines, conflicts and queued question deliveries
+WITH translations AS (
+    DELETE FROM session_translations WHERE session_id = ANY(sqlc.arg(session_ids)::uuid[])
+), sections AS (
+    DELETE FROM session_result_sections WHERE session_id = ANY(sqlc.arg(session_ids)::uuid[])
+), conversation AS (
+    DELETE FROM session_conversation_log WHERE session_id = ANY(sqlc.arg(session_ids)::uuid[])
+), facts AS (
+    DELETE FROM session_facts WHERE session_id = ANY(sqlc.arg(session_ids)::uuid[])
+), comments AS (
+    DELETE FROM session_comments WHERE session_id = ANY(sqlc.arg(session_ids)::uuid[])
+), deltas AS (
+    DELETE FROM session_deltas WHERE session_id = ANY(sqlc.arg(session_ids)::uuid[])
+), conflicts AS (
+    DELETE FROM session_conflicts WHERE session_id = ANY(sqlc.arg(session_ids)::uuid[])
+)
+DELETE FROM pending_question_deliveries
+WHERE session_id = ANY(sqlc.arg(session_ids)::uuid[]);
+
+-- name: CreateRetentionPurge :one
+INSERT INTO retention_purges (tenant_id, cutoff, sessions, questions, messages, result_versions, result_bytes)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: ListRetentionPurges :many
+SELECT * FROM retention_purges
+WHERE tenant_id = $1
+ORDER BY created_at DESC
+LIMIT $2;
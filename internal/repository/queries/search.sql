@@ -0,0 +1,53 @@
+-- name: SearchAll :many
+-- Full-text search across project descriptions, file names, session goals and results for
+-- support staff; backed by the GIN indexes from migration 017
+WITH hits AS (
+    SELECT
+        'project'::text AS source,
+        p.id,
+        p.id AS project_id,
+        ts_headline('russian', p.title || ' ' || COALESCE(p.description, ''), websearch_to_tsquery('russian', sqlc.arg(query)::text),
+            'StartSel=«, StopSel=», MaxFragments=2, FragmentDelimiter=" … "')::text AS snippet,
+        ts_rank(to_tsvector('russian', p.title || ' ' || COALESCE(p.description, '')), websearch_to_tsquery('russian', sqlc.arg(query)::text))::real AS rank,
+        p.created_at
+    FROM projects p
+    WHERE to_tsvector('russian', p.title || ' ' || COALESCE(p.description, '')) @@ websearch_to_tsquery('russian', sqlc.arg(query)::text)
+    UNION ALL
+    SELECT
+        'file'::text AS source,
+        f.id,
+        f.project_id,
+        ts_headline('simple', f.filename, websearch_to_tsquery('simple', sqlc.arg(query)::text),
+            'StartSel=«, StopSel=»')::text AS snippet,
+        ts_rank(to_tsvector('simple', f.filename), websearch_to_tsquery('simple', sqlc.arg(query)::text))::real AS rank,
+        f.created_at
+    FROM project_files f
+    WHERE to_tsvector('simple', f.filename) @@ websearch_to_tsquery('simple', sqlc.arg(query)::text)
+    UNION ALL
+    SELECT
+        'session_goal'::text AS source,
+        s.id,
+        s.project_id,
+        ts_headline('russian', COALESCE(s.user_goal, ''), websearch_to_tsquery('russian', sqlc.arg(query)::text),
+            'StartSel=«, StopSel=», MaxFragments=2, FragmentDelimiter=" … "')::text AS snippet,
+        ts_rank(to_tsvector('russian', COALESCE(s.user_goal, '')), websearch_to_tsquery('russian', sqlc.arg(query)::text))::real AS rank,
+        s.created_at
+    FROM sessions s
+    WHERE to_tsvector('russian', COALESCE(s.user_goal, '')) @@ websearch_to_tsquery('russian', sqlc.arg(query)::text) AND NOT s.is_demo
+    UNION ALL
+    SELECT
+        'session_result'::text AS source,
+        s.id,
+        s.project_id,
+        ts_headline('russian', COALESCE(s.result, ''), websearch_to_tsquery('russian', sqlc.arg(query)::text),
+            'StartSel=«, StopSel=», MaxFragments=2, FragmentDelimiter=" … "')::text AS snippet,
+        ts_rank(to_tsvector('russian', COALESCE(s.result, '')), websearch_to_tsquery('russian', sqlc.arg(query)::text))::real AS rank,
+        s.created_at
+    FROM sessions s
+    WHERE to_tsvector('russian', COALESCE(s.result, '')) @@ websearch_to_tsquery('russian', sqlc.arg(query)::text) AND NOT s.is_demo
+)
+SELECT source, id, project_id, snippet, rank, created_at, COUNT(*) OVER () AS total_count
+FROM hits
+ORDER BY rank DESC, created_at DESC
+LIMIT sqlc.arg(max_results) OFFSET sqlc.arg(skip)
+;
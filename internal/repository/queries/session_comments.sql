@@ -0,0 +1,25 @@
+-- name: CreateSessionComment :one
+INSERT INTO session_comments (session_id, section_index, requirement_id, author, text, created_at)
+VALUES ($1, $2, $3, $4, $5, NOW())
+RETURNING *;
+
+-- name: ListSessionComments :many
+SELECT * FROM session_comments
+WHERE session_id = $1
+ORDER BY created_at ASC;
+
+-- name: ListUnresolvedSessionComments :many
+SELECT * FROM session_comments
+WHERE session_id = $1 AND resolved_at IS NULL
+ORDER BY created_at ASC;
+
+-- name: ResolveSessionComment :one
+UPDATE session_comments
+SET resolved_at = COALESCE(resolved_at, NOW())
+WHERE id = $1 AND session_id = $2
+RETURNING *;
+
+-- name: ResolveSessionComments :exec
+UPDATE session_comments
+SET resolved_at = NOW()
+WHERE session_id = $1 AND id = ANY(sqlc.arg(ids)::uuid[]) AND resolved_at IS NULL;
@@ -0,0 +1,23 @@
+-- name: CreateSessionConflict :one
+INSERT INTO session_conflicts (session_id, position, requirement, previous, source, created_at)
+VALUES ($1, $2, $3, $4, $5, NOW())
+RETURNING *;
+
+-- name: DeleteSessionConflicts :exec
+DELETE FROM session_conflicts
+WHERE session_id = $1;
+
+-- name: ListSessionConflicts :many
+SELECT * FROM session_conflicts
+WHERE session_id = $1
+ORDER BY position ASC;
+
+-- name: CountUnresolvedSessionConflicts :one
+SELECT COUNT(*) FROM session_conflicts
+WHERE session_id = $1 AND resolved_at IS NULL;
+
+-- name: ResolveSessionConflict :one
+UPDATE session_conflicts
+SET resolution = $3, resolved_at = NOW()
+WHERE id = $1 AND session_id = $2 AND resolved_at IS NULL
+RETURNING *;
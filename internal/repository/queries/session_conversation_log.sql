@@ -0,0 +1,18 @@
+-- name: AppendConversationEntry :exec
+-- Copies the question text next to the entry so the log reads on its own
+INSERT INTO session_conversation_log (session_id, question_id, kind, question, content, created_at)
+SELECT sqlc.arg(session_id), q.id, sqlc.arg(kind), q.question, sqlc.arg(content), NOW()
+FROM iteration_questions q
+WHERE q.id = sqlc.arg(question_id);
+
+-- name: ListRecentConversationEntries :many
+-- Returns the latest entries of a session in chronological order
+SELECT *
+FROM (
+    SELECT *
+    FROM session_conversation_log
+    WHERE session_id = sqlc.arg(session_id)
+    ORDER BY id DESC
+    LIMIT sqlc.arg(max_entries)
+) recent
+ORDER BY id ASC;
@@ -0,0 +1,20 @@
+-- name: UpsertSessionDelta :one
+INSERT INTO session_deltas (session_id, baseline_session_id, baseline)
+VALUES ($1, $2, $3)
+ON CONFLICT (session_id) DO UPDATE
+SET baseline_session_id = EXCLUDED.baseline_session_id,
+    baseline = EXCLUDED.baseline,
+    change_log = NULL,
+    updated_at = NOW()
+RETURNING *;
+
+-- name: GetSessionDelta :one
+SELECT * FROM session_deltas
+WHERE session_id = $1;
+
+-- name: UpdateSessionDeltaChangeLog :one
+UPDATE session_deltas
+SET change_log = $2,
+    updated_at = NOW()
+WHERE session_id = $1
+RETURNING *;
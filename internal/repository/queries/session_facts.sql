@@ -0,0 +1,12 @@
+-- name: GetSessionFacts :one
+SELECT * FROM session_facts
+WHERE session_id = $1;
+
+-- name: UpsertSessionFacts :one
+INSERT INTO session_facts (session_id, facts, material_hash)
+VALUES ($1, $2, $3)
+ON CONFLICT (session_id) DO UPDATE
+SET facts = EXCLUDED.facts,
+    material_hash = EXCLUDED.material_hash,
+    updated_at = NOW()
+RETURNING *;
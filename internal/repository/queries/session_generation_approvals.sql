@@ -0,0 +1,9 @@
+-- name: ApproveSessionGeneration :exec
+INSERT INTO session_generation_approvals (session_id, approved_at)
+VALUES ($1, NOW())
+ON CONFLICT (session_id) DO NOTHING;
+
+-- name: IsSessionGenerationApproved :one
+SELECT EXISTS (
+    SELECT 1 FROM session_generation_approvals WHERE session_id = $1
+) AS approved;
@@ -0,0 +1,12 @@
+-- name: RecordGenerationFailure :one
+INSERT INTO session_generation_failures (session_id, failures, last_error, updated_at)
+VALUES ($1, 1, $2, NOW())
+ON CONFLICT (session_id) DO UPDATE
+SET failures = session_generation_failures.failures + 1,
+    last_error = EXCLUDED.last_error,
+    updated_at = NOW()
+RETURNING failures;
+
+-- name: ResetGenerationFailures :exec
+DELETE FROM session_generation_failures
+WHERE session_id = $1;
@@ -0,0 +1,6 @@
+-- name: LockSession :exec
+-- Session-level advisory lock, held by the connection until UnlockSession
+SELECT pg_advisory_lock(hashtextextended($1::text, 0));
+
+-- name: UnlockSession :exec
+SELECT pg_advisory_unlock(hashtextextended($1::text, 0));
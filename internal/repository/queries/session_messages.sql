@@ -1,6 +1,6 @@
 -- name: CreateSessionMessage :one
-INSERT INTO session_messages (session_id, message_text, created_at)
-VALUES ($1, $2, NOW())
+INSERT INTO session_messages (session_id, message_text, message_text_compressed, raw_message_text, created_at)
+VALUES ($1, $2, $3, $4, NOW())
 RETURNING *;
 
 -- name: GetSessionMessages :many
@@ -13,3 +13,52 @@ ORDER BY created_at ASC;
 DELETE FROM session_messages
 WHERE session_id = $1;
 
+-- name: ListUncompressedSessionMessages :many
+-- Pages through plain messages above the size threshold by id, so rows that do not shrink
+-- and stay plain are not returned again
+SELECT id, message_text
+FROM session_messages
+WHERE message_text_compressed IS NULL
+  AND octet_length(message_text) > sqlc.arg(min_size)::int
+  AND id > sqlc.arg(after_id)
+ORDER BY id
+LIMIT sqlc.arg(batch_size);
+
+-- name: SetSessionMessageCompressed :exec
+UPDATE session_messages
+SET message_text = '',
+    message_text_compressed = $2
+WHERE id = $1;
+
+
+-- name: SearchSessionContent :many
+-- Full-text search over answers and draft messages of one session; the session filter keeps the
+-- scanned set small, so no full-text indexes are maintained. Compressed draft messages have an
+-- empty message_text and are not searched
+SELECT
+    'answer'::text AS source,
+    q.id,
+    q.question AS question,
+    ts_headline('russian', q.answer, websearch_to_tsquery('russian', sqlc.arg(query)::text),
+        'StartSel=«, StopSel=», MaxFragments=2, FragmentDelimiter=" … "')::text AS snippet,
+    ts_rank(to_tsvector('russian', q.answer), websearch_to_tsquery('russian', sqlc.arg(query)::text))::real AS rank,
+    COALESCE(q.answered_at, q.created_at)::timestamp AS created_at
+FROM iteration_questions q
+JOIN session_iterations i ON i.id = q.iteration_id
+WHERE i.session_id = sqlc.arg(session_id)
+  AND q.answer IS NOT NULL
+  AND to_tsvector('russian', q.answer) @@ websearch_to_tsquery('russian', sqlc.arg(query)::text)
+UNION ALL
+SELECT
+    'draft_message'::text AS source,
+    m.id,
+    ''::text AS question,
+    ts_headline('russian', m.message_text, websearch_to_tsquery('russian', sqlc.arg(query)::text),
+        'StartSel=«, StopSel=», MaxFragments=2, FragmentDelimiter=" … "')::text AS snippet,
+    ts_rank(to_tsvector('russian', m.message_text), websearch_to_tsquery('russian', sqlc.arg(query)::text))::real AS rank,
+    m.created_at
+FROM session_messages m
+WHERE m.session_id = sqlc.arg(session_id)
+  AND to_tsvector('russian', m.message_text) @@ websearch_to_tsquery('russian', sqlc.arg(query)::text)
+ORDER BY rank DESC, created_at ASC
+LIMIT sqlc.arg(max_results);
@@ -0,0 +1,26 @@
+-- name: StartSessionOperation :one
+-- Registers the operation unless another one runs on the session; an expired row is taken over
+-- and no row means the session is busy
+INSERT INTO session_operations (session_id, operation, owner, started_at, expires_at)
+VALUES (sqlc.arg(session_id), sqlc.arg(operation), sqlc.arg(owner), NOW(), NOW() + make_interval(secs => sqlc.arg(ttl_seconds)::int))
+ON CONFLICT (session_id) DO UPDATE SET
+    id = gen_random_uuid(),
+    operation = EXCLUDED.operation,
+    owner = EXCLUDED.owner,
+    started_at = EXCLUDED.started_at,
+    expires_at = EXCLUDED.expires_at
+WHERE session_operations.expires_at <= NOW()
+RETURNING *;
+
+-- name: GetSessionOperation :one
+SELECT * FROM session_operations
+WHERE session_id = $1 AND expires_at > NOW();
+
+-- name: RenewSessionOperation :execrows
+UPDATE session_operations
+SET expires_at = NOW() + make_interval(secs => sqlc.arg(ttl_seconds)::int)
+WHERE session_id = sqlc.arg(session_id) AND id = sqlc.arg(id);
+
+-- name: FinishSessionOperation :exec
+DELETE FROM session_operations
+WHERE session_id = $1 AND id = $2;
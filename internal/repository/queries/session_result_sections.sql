@@ -0,0 +1,18 @@
+-- name: UpsertResultSection :one
+INSERT INTO session_result_sections (session_id, section_index, title, description, content, updated_at)
+VALUES ($1, $2, $3, $4, $5, NOW())
+ON CONFLICT (session_id, section_index) DO UPDATE
+SET title = EXCLUDED.title,
+    description = EXCLUDED.description,
+    content = EXCLUDED.content,
+    updated_at = NOW()
+RETURNING *;
+
+-- name: ListResultSections :many
+SELECT * FROM session_result_sections
+WHERE session_id = $1
+ORDER BY section_index ASC;
+
+-- name: DeleteResultSections :exec
+DELETE FROM session_result_sections
+WHERE session_id = $1;
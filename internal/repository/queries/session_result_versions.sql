@@ -0,0 +1,34 @@
+-- name: CreateSessionResultVersion :one
+INSERT INTO session_result_versions (session_id, version, storage, object_key, size_bytes, checksum, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, NOW())
+RETURNING *;
+
+-- name: ArchiveSessionResultVersionContent :exec
+-- The body of the current inline version is sessions.result; it moves into the version right
+-- before a newer one overwrites sessions.result
+UPDATE session_result_versions v
+SET content = s.result
+FROM sessions s
+WHERE v.id = $1
+  AND v.session_id = s.id
+  AND v.storage = 'inline'
+  AND v.content IS NULL
+  AND s.result IS NOT NULL;
+
+-- name: GetLatestSessionResultVersion :one
+SELECT * FROM session_result_versions
+WHERE session_id = $1
+ORDER BY version DESC
+LIMIT 1;
+
+-- name: GetSessionResultVersion :one
+SELECT * FROM session_result_versions
+WHERE session_id = $1 AND version = $2;
+
+-- name: ListSessionResultVersions :many
+-- Leaves the inline bodies out, it only reports whether a version still has one
+SELECT id, session_id, version, storage, object_key, size_bytes, checksum, created_at,
+    (content IS NOT NULL)::boolean AS has_content
+FROM session_result_versions
+WHERE session_id = $1
+ORDER BY version DESC;
@@ -0,0 +1,28 @@
+-- name: UpsertSessionReview :one
+INSERT INTO session_reviews (session_id, status, decided_by_type, decided_by_id, decision_comment, created_at, updated_at)
+VALUES ($1, $2, $3, $4, $5, NOW(), NOW())
+ON CONFLICT (session_id) DO UPDATE
+SET status = EXCLUDED.status,
+    decided_by_type = EXCLUDED.decided_by_type,
+    decided_by_id = EXCLUDED.decided_by_id,
+    decision_comment = EXCLUDED.decision_comment,
+    updated_at = NOW()
+RETURNING *;
+
+-- name: GetSessionReview :one
+SELECT * FROM session_reviews
+WHERE session_id = $1;
+
+-- name: DeleteReviewApprovers :exec
+DELETE FROM session_review_approvers
+WHERE session_id = $1;
+
+-- name: AddReviewApprover :exec
+INSERT INTO session_review_approvers (session_id, approver_type, approver_id)
+VALUES ($1, $2, $3)
+ON CONFLICT DO NOTHING;
+
+-- name: ListReviewApprovers :many
+SELECT * FROM session_review_approvers
+WHERE session_id = $1
+ORDER BY approver_type, approver_id;
@@ -0,0 +1,16 @@
+-- name: StartSessionTimeBudget :one
+-- A repeated start keeps the original start time
+INSERT INTO session_time_budgets (session_id, budget_seconds)
+VALUES ($1, $2)
+ON CONFLICT (session_id) DO UPDATE
+SET budget_seconds = EXCLUDED.budget_seconds
+RETURNING *;
+
+-- name: GetSessionTimeBudget :one
+SELECT * FROM session_time_budgets
+WHERE session_id = $1;
+
+-- name: MarkSessionTimeBudgetWarned :execrows
+UPDATE session_time_budgets
+SET warned_at = NOW()
+WHERE session_id = $1 AND warned_at IS NULL;
@@ -0,0 +1,16 @@
+-- name: GetSessionTranslation :one
+SELECT *
+FROM session_translations
+WHERE session_id = $1 AND language = $2;
+
+-- name: UpsertSessionTranslation :one
+INSERT INTO session_translations (session_id, language, result, created_at)
+VALUES ($1, $2, $3, NOW())
+ON CONFLICT (session_id, language) DO UPDATE
+SET result = EXCLUDED.result,
+    created_at = NOW()
+RETURNING *;
+
+-- name: DeleteSessionTranslations :exec
+DELETE FROM session_translations
+WHERE session_id = $1;
@@ -1,9 +1,12 @@
 -- name: CreateSession :one
 INSERT INTO sessions (
     id,
-    status
+    status,
+    is_demo,
+    tenant_id,
+    owner_id
 ) VALUES (
-    $1, $2
+    $1, $2, $3, $4, $5
 ) RETURNING *;
 
 -- name: CreateFilledSession :one
@@ -13,57 +16,76 @@ INSERT INTO sessions (
     status,
     type,
     user_goal,
-    project_context
+    project_context,
+    project_context_compressed,
+    is_demo,
+    tenant_id,
+    callback_granularity,
+    owner_id
 ) VALUES (
-    $1, $2, $3, $4, $5, $6
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
 ) RETURNING *;
 
 -- name: GetSessionByID :one
+-- A user sees the sessions they own and the sessions nobody owns; a NULL owner_id sees the whole tenant
 SELECT * FROM sessions
-WHERE id = $1;
+WHERE id = sqlc.arg(id) AND tenant_id = sqlc.arg(tenant_id)
+  AND (sqlc.narg(owner_id)::UUID IS NULL OR owner_id IS NULL OR owner_id = sqlc.narg(owner_id));
 
 -- name: AquireSessionByID :one
 UPDATE sessions
 SET status = 'Processing', 
     updated_at = NOW()
-WHERE id = $1 AND status = 'WaitingForAnswers'
+WHERE id = sqlc.arg(id) AND tenant_id = sqlc.arg(tenant_id) AND status = 'WaitingForAnswers'
+  AND (sqlc.narg(owner_id)::UUID IS NULL OR owner_id IS NULL OR owner_id = sqlc.narg(owner_id))
 RETURNING *;
 
 -- name: UpdateSessionStatus :one
 UPDATE sessions
 SET status = $2,
     updated_at = NOW()
-WHERE id = $1
+WHERE id = $1 AND tenant_id = $3
+RETURNING *;
+
+-- name: TransitionSessionStatus :one
+-- Changes the status only while the session is still in the expected one,
+-- so a concurrent or repeated transition affects no row
+UPDATE sessions
+SET status = sqlc.arg(to_status),
+    updated_at = NOW()
+WHERE id = sqlc.arg(id) AND tenant_id = sqlc.arg(tenant_id) AND status = sqlc.arg(from_status)
 RETURNING *;
 
 -- name: UpdateSessionRAGProjectContext :one
 UPDATE sessions
 SET project_context = $1, 
     project_id = $3, 
+    project_context_compressed = $4,
     updated_at = NOW()
-WHERE id = $2
+WHERE id = $2 AND tenant_id = $5
 RETURNING *;
 
 -- name: UpdateSessionProjectContext :one
 UPDATE sessions
 SET project_context = $1, 
     project_id = NULL, 
+    project_context_compressed = $3,
     updated_at = NOW()
-WHERE id = $2
+WHERE id = $2 AND tenant_id = $4
 RETURNING *;
 
 -- name: UpdateSessionIteration :one
 UPDATE sessions
 SET current_iteration = current_iteration + 1,
     updated_at = NOW()
-WHERE id = $1
+WHERE id = $1 AND tenant_id = $2
 RETURNING *;
 
 -- name: ResetSessionIteration :one
 UPDATE sessions
 SET current_iteration = current_iteration - 1,
     updated_at = NOW()
-WHERE id = $1
+WHERE id = $1 AND tenant_id = $2
 RETURNING *;
 
 -- name: UpdateSessionResult :one
@@ -72,23 +94,102 @@ SET status = $2,
     result = $3,
     error = $4,
     updated_at = NOW()
-WHERE id = $1
+WHERE id = $1 AND tenant_id = $5
 RETURNING *;
 
 -- name: UpdateSessionType :one
 UPDATE sessions
 SET type = $2,
     updated_at = NOW()
-WHERE id = $1
+WHERE id = $1 AND tenant_id = $3
+RETURNING *;
+
+-- name: UpdateSessionSummaryStyle :one
+UPDATE sessions
+SET summary_style = $2,
+    updated_at = NOW()
+WHERE id = $1 AND tenant_id = $3
 RETURNING *;
 
 -- name: UpdateSessionUserGoal :one
 UPDATE sessions
 SET user_goal = $2,
     updated_at = NOW()
-WHERE id = $1
+WHERE id = $1 AND tenant_id = $3
+RETURNING *;
+
+-- name: UpdateSessionLanguage :one
+-- A detected language ($3 false) never replaces a language the user has set explicitly
+UPDATE sessions
+SET language = CASE WHEN language_explicit AND NOT $3::boolean THEN language ELSE $2 END,
+    language_explicit = language_explicit OR $3::boolean,
+    updated_at = NOW()
+WHERE id = $1 AND tenant_id = $4
+RETURNING *;
+
+-- name: TouchSessionActivity :one
+UPDATE sessions
+SET last_activity_at = NOW()
+WHERE id = $1 AND tenant_id = $2
+RETURNING *;
+
+-- name: RefreshSessionCurrentQuestion :one
+-- Points the session at its first open question: the unanswered ones in interview order,
+-- then the deferred ones, which are asked last
+UPDATE sessions
+SET current_question_id = (
+    SELECT iq.id FROM iteration_questions iq
+    JOIN session_iterations si ON si.id = iq.iteration_id
+    WHERE si.session_id = sessions.id
+      AND iq.status IN ('UNANSWERED', 'DEFERRED')
+    ORDER BY iq.status = 'DEFERRED', si.iteration_number ASC, iq.question_number ASC
+    LIMIT 1
+)
+WHERE id = $1 AND tenant_id = $2
 RETURNING *;
 
 -- name: DeleteSession :exec
 DELETE FROM sessions
+WHERE id = $1 AND tenant_id = $2;
+
+-- name: GetLatestProjectResultSession :one
+SELECT * FROM sessions
+WHERE project_id = sqlc.arg(project_id) AND tenant_id = sqlc.arg(tenant_id) AND status = 'DONE' AND NOT is_demo
+  AND (sqlc.narg(owner_id)::UUID IS NULL OR owner_id IS NULL OR owner_id = sqlc.narg(owner_id))
+  AND (result IS NOT NULL OR EXISTS (SELECT 1 FROM session_result_versions v WHERE v.session_id = sessions.id))
+ORDER BY updated_at DESC
+LIMIT 1;
+
+-- name: GetPreviousProjectResultSession :one
+-- The latest completed session of the project with a result started before the given time
+SELECT * FROM sessions
+WHERE project_id = sqlc.arg(project_id) AND tenant_id = sqlc.arg(tenant_id) AND status = 'DONE' AND NOT is_demo
+  AND created_at < sqlc.arg(before)::timestamp
+  AND (sqlc.narg(owner_id)::UUID IS NULL OR owner_id IS NULL OR owner_id = sqlc.narg(owner_id))
+  AND (result IS NOT NULL OR EXISTS (SELECT 1 FROM session_result_versions v WHERE v.session_id = sessions.id))
+ORDER BY created_at DESC
+LIMIT 1;
+
+-- name: DeleteDemoSessionsBefore :execrows
+-- Related rows go with the session through ON DELETE CASCADE; demo sessions of all tenants expire
+-- once neither their creation nor their last heartbeat is newer than before
+DELETE FROM sessions
+WHERE is_demo AND GREATEST(created_at, last_activity_at) < sqlc.arg(before)::timestamp;
+
+-- name: ListUncompressedSessionContexts :many
+-- Pages through plain project contexts above the size threshold by id, so rows that do not
+-- shrink and stay plain are not returned again. Maintenance runs across all tenants
+SELECT id, project_context::text AS project_context
+FROM sessions
+WHERE project_context_compressed IS NULL
+  AND octet_length(project_context) > sqlc.arg(min_size)::int
+  AND id > sqlc.arg(after_id)
+ORDER BY id
+LIMIT sqlc.arg(batch_size);
+
+-- name: SetSessionProjectContextCompressed :exec
+-- Leaves updated_at untouched: compression does not change the session
+UPDATE sessions
+SET project_context = NULL,
+    project_context_compressed = $2
 WHERE id = $1;
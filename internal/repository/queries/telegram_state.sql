@@ -1,7 +1,7 @@
 -- name: GetTelegramSession :one
-SELECT user_id, session_id, state_data, created_at, updated_at
+SELECT user_id, session_id, state_data, created_at, updated_at, tenant_id
 FROM telegram_sessions
-WHERE user_id = $1;
+WHERE user_id = $1 AND tenant_id = $2;
 
 -- name: GetTelegramSessionWithSession :one
 SELECT
@@ -16,21 +16,132 @@ SELECT
     s.project_id as session_project_id
 FROM telegram_sessions ts
 LEFT JOIN sessions s ON ts.session_id = s.id
-WHERE ts.user_id = $1;
+WHERE ts.user_id = $1 AND ts.tenant_id = $2;
 
 -- name: GetTelegramSessionBySessionID :one
-SELECT user_id, session_id, state_data, created_at, updated_at
+SELECT user_id, session_id, state_data, created_at, updated_at, tenant_id
 FROM telegram_sessions
-WHERE session_id = $1;
+WHERE session_id = $1 AND tenant_id = $2;
 
 -- name: UpsertTelegramSession :exec
-INSERT INTO telegram_sessions (user_id, session_id, state_data, created_at, updated_at)
-VALUES ($1, $2, $3, $4, $5)
-ON CONFLICT (user_id) DO UPDATE SET
+INSERT INTO telegram_sessions (user_id, session_id, state_data, created_at, updated_at, tenant_id)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (tenant_id, user_id) DO UPDATE SET
     session_id = EXCLUDED.session_id,
     state_data = EXCLUDED.state_data,
     updated_at = EXCLUDED.updated_at;
 
 -- name: DeleteTelegramSession :exec
 DELETE FROM telegram_sessions
-WHERE user_id = $1;
+WHERE user_id = $1 AND tenant_id = $2;
+
+-- name: GetTelegramUserNormalizeTranscripts :one
+SELECT normalize_transcripts
+FROM telegram_users
+WHERE user_id = $1 AND tenant_id = $2;
+
+-- name: SetTelegramUserNormalizeTranscripts :exec
+INSERT INTO telegram_users (user_id, normalize_transcripts, tenant_id)
+VALUES ($1, $2, $3)
+ON CONFLICT (tenant_id, user_id) DO UPDATE SET
+    normalize_transcripts = EXCLUDED.normalize_transcripts,
+    last_active_at = NOW();
+
+-- name: GetSessionNormalizeTranscripts :one
+SELECT u.normalize_transcripts
+FROM telegram_sessions ts
+JOIN telegram_users u ON u.tenant_id = ts.tenant_id AND u.user_id = ts.user_id
+WHERE ts.session_id = $1 AND ts.tenant_id = $2;
+
+-- name: GetTelegramUserQuestionNumbering :one
+SELECT question_numbering
+FROM telegram_users
+WHERE user_id = $1 AND tenant_id = $2;
+
+-- name: SetTelegramUserQuestionNumbering :exec
+INSERT INTO telegram_users (user_id, question_numbering, tenant_id)
+VALUES ($1, $2, $3)
+ON CONFLICT (tenant_id, user_id) DO UPDATE SET
+    question_numbering = EXCLUDED.question_numbering,
+    last_active_at = NOW();
+
+-- name: GetTelegramUserProgressHeader :one
+SELECT progress_header
+FROM telegram_users
+WHERE user_id = $1 AND tenant_id = $2;
+
+-- name: SetTelegramUserProgressHeader :exec
+INSERT INTO telegram_users (user_id, progress_header, tenant_id)
+VALUES ($1, $2, $3)
+ON CONFLICT (tenant_id, user_id) DO UPDATE SET
+    progress_header = EXCLUDED.progress_header,
+    last_active_at = NOW();
+
+-- name: GetTelegramUserSummaryStyle :one
+SELECT summary_style
+FROM telegram_users
+WHERE user_id = $1 AND tenant_id = $2;
+
+-- name: SetTelegramUserSummaryStyle :exec
+INSERT INTO telegram_users (user_id, summary_style, tenant_id)
+VALUES ($1, $2, $3)
+ON CONFLICT (tenant_id, user_id) DO UPDATE SET
+    summary_style = EXCLUDED.summary_style,
+    last_active_at = NOW();
+
+-- name: GetTelegramUserTimezone :one
+SELECT timezone
+FROM telegram_users
+WHERE user_id = $1 AND tenant_id = $2;
+
+-- name: SetTelegramUserTimezone :exec
+INSERT INTO telegram_users (user_id, timezone, tenant_id)
+VALUES ($1, $2, $3)
+ON CONFLICT (tenant_id, user_id) DO UPDATE SET
+    timezone = EXCLUDED.timezone,
+    last_active_at = NOW();
+
+-- name: MarkTelegramUserOnboarded :execrows
+-- Affects a row only the first time, so concurrent /start commands show the tutorial once
+INSERT INTO telegram_users (user_id, onboarded_at, tenant_id)
+VALUES ($1, NOW(), $2)
+ON CONFLICT (tenant_id, user_id) DO UPDATE SET
+    onboarded_at = NOW(),
+    last_active_at = NOW()
+WHERE telegram_users.onboarded_at IS NULL;
+
+-- name: CreateTelegramInboxMessage :one
+INSERT INTO telegram_inbox (tenant_id, user_id, session_id, question_id, text, created_at)
+VALUES ($1, $2, $3, $4, $5, NOW())
+RETURNING *;
+
+-- name: GetTelegramInboxMessage :one
+SELECT *
+FROM telegram_inbox
+WHERE id = $1 AND user_id = $2 AND tenant_id = $3;
+
+-- name: DeleteTelegramInboxMessage :exec
+DELETE FROM telegram_inbox
+WHERE id = $1 AND user_id = $2 AND tenant_id = $3;
+
+-- name: ListOutdatedTelegramStateData :many
+-- Pages through the telegram sessions of all tenants whose state data is older than the version;
+-- a version that is not a number counts as 0, so the caller sees the broken blob
+SELECT tenant_id, user_id, state_data
+FROM telegram_sessions
+WHERE (tenant_id, user_id) > (sqlc.arg(after_tenant_id)::varchar, sqlc.arg(after_user_id)::bigint)
+  AND CASE WHEN jsonb_typeof(state_data->'version') = 'number'
+        THEN (state_data->>'version')::numeric
+        ELSE 0
+      END < sqlc.arg(version)::int
+ORDER BY tenant_id, user_id
+LIMIT sqlc.arg(batch_size);
+
+-- name: ReplaceTelegramStateData :execrows
+-- Affects no row when the state data changed since it was read, e.g. by the bot meanwhile;
+-- updated_at is kept, so a backfill does not extend the lifetime of idle sessions
+UPDATE telegram_sessions
+SET state_data = sqlc.arg(state_data)
+WHERE tenant_id = sqlc.arg(tenant_id)
+  AND user_id = sqlc.arg(user_id)
+  AND state_data IS NOT DISTINCT FROM sqlc.narg(previous_state_data)::jsonb;
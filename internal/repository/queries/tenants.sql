@@ -0,0 +1,32 @@
+-- name: CreateTenant :one
+INSERT INTO tenants (id, name, api_key_hash, bot_token_hash, settings)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: GetTenant :one
+SELECT * FROM tenants
+WHERE id = $1;
+
+-- name: GetTenantByAPIKeyHash :one
+SELECT * FROM tenants
+WHERE api_key_hash = $1;
+
+-- name: GetTenantByBotTokenHash :one
+SELECT * FROM tenants
+WHERE bot_token_hash = $1;
+
+-- name: GetProjectTenant :one
+-- Resolves the tenant of background work that starts from a project, such as scheduled sessions
+SELECT t.* FROM tenants t
+JOIN projects p ON p.tenant_id = t.id
+WHERE p.id = $1;
+
+-- name: ListTenants :many
+SELECT * FROM tenants
+ORDER BY created_at ASC;
+
+-- name: UpdateTenantSettings :one
+UPDATE tenants
+SET settings = $2
+WHERE id = $1
+RETURNING *;
@@ -0,0 +1,18 @@
+-- name: CreateDocumentTheme :one
+INSERT INTO document_themes (id, tenant_id, name, primary_color, header_text, footer_text, font_name, logo_key, font_key, created_at)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, NOW())
+RETURNING *;
+
+-- name: GetDocumentTheme :one
+SELECT *
+FROM document_themes
+WHERE id = $1 AND tenant_id = $2;
+
+-- name: ListDocumentThemes :many
+SELECT *
+FROM document_themes
+WHERE tenant_id = $1
+ORDER BY created_at DESC;
+
+-- name: DeleteDocumentTheme :execrows
+DELETE FROM document_themes WHERE id = $1 AND tenant_id = $2;
@@ -0,0 +1,29 @@
+-- name: CreateUser :one
+-- Creating an API user for a Telegram user who already talked to the bot gives that user the API key
+INSERT INTO users (id, tenant_id, name, api_key_hash, telegram_user_id)
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (tenant_id, telegram_user_id) DO UPDATE
+SET name = EXCLUDED.name,
+    api_key_hash = EXCLUDED.api_key_hash
+RETURNING *;
+
+-- name: DeleteUser :execrows
+DELETE FROM users
+WHERE id = $1 AND tenant_id = $2;
+
+-- name: EnsureTelegramUser :one
+-- The no-op update makes RETURNING yield the existing user
+INSERT INTO users (id, tenant_id, name, telegram_user_id)
+VALUES ($1, $2, $3, $4)
+ON CONFLICT (tenant_id, telegram_user_id) DO UPDATE
+SET telegram_user_id = EXCLUDED.telegram_user_id
+RETURNING *;
+
+-- name: GetUserByAPIKeyHash :one
+SELECT * FROM users
+WHERE api_key_hash = $1;
+
+-- name: ListUsers :many
+SELECT * FROM users
+WHERE tenant_id = $1
+ORDER BY created_at ASC;
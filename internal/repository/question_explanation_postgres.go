@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/repository/sqlc"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// QuestionExplanationRepository defines the interface for the cache of on-demand explanations
+// and the cooldowns of the users requesting them
+type QuestionExplanationRepository interface {
+	GetExplanation(ctx context.Context, questionID string) (*entity.QuestionExplanation, error)
+	SaveExplanation(ctx context.Context, questionID, explanation string) (*entity.QuestionExplanation, error)
+	// StartCooldown starts the cooldown of the subject in the tenant of ctx, false while the previous one runs
+	StartCooldown(ctx context.Context, subject string, cooldown time.Duration) (bool, error)
+}
+
+var _ QuestionExplanationRepository = &QuestionExplanationPostgres{}
+
+// QuestionExplanationPostgres implements QuestionExplanationRepository using PostgreSQL
+type QuestionExplanationPostgres struct {
+	db      *pgxpool.Pool
+	queries *sqlc.Queries
+}
+
+func NewQuestionExplanationPostgres(db *pgxpool.Pool) *QuestionExplanationPostgres {
+	return &QuestionExplanationPostgres{
+		db:      db,
+		queries: sqlc.New(db),
+	}
+}
+
+func (r *QuestionExplanationPostgres) GetExplanation(ctx context.Context, questionID string) (*entity.QuestionExplanation, error) {
+	qID, err := uuid.Parse(questionID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid question ID: %w", err)
+	}
+
+	dbExplanation, err := r.queries.GetQuestionExplanation(ctx, pgtype.UUID{Bytes: qID, Valid: true})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, entity.ErrExplanationNotFound
+		}
+		return nil, fmt.Errorf("get question explanation: %w", err)
+	}
+
+	return toEntityQuestionExplanation(&dbExplanation), nil
+}
+
+// SaveExplanation stores the generated explanation of the question, replacing an earlier one
+func (r *QuestionExplanationPostgres) SaveExplanation(ctx context.Context, questionID, explanation string) (*entity.QuestionExplanation, error) {
+	qID, err := uuid.Parse(questionID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid question ID: %w", err)
+	}
+
+	dbExplanation, err := r.queries.SaveQuestionExplanation(ctx, sqlc.SaveQuestionExplanationParams{
+		QuestionID:  pgtype.UUID{Bytes: qID, Valid: true},
+		Explanation: explanation,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("save question explanation: %w", err)
+	}
+
+	return toEntityQuestionExplanation(&dbExplanation), nil
+}
+
+func (r *QuestionExplanationPostgres) StartCooldown(ctx context.Context, subject string, cooldown time.Duration) (bool, error) {
+	_, err := r.queries.StartExplanationCooldown(ctx, sqlc.StartExplanationCooldownParams{
+		TenantID:        entity.TenantIDFromContext(ctx),
+		Subject:         subject,
+		CooldownSeconds: int32(cooldown.Seconds()),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		return false, fmt.Errorf("start explanation cooldown: %w", err)
+	}
+
+	return true, nil
+}
@@ -20,9 +20,14 @@ type QuestionRepository interface {
 	GetQuestionByID(ctx context.Context, id string) (*entity.Question, error)
 	ListQuestionsByIteration(ctx context.Context, iterationID string) ([]*entity.Question, error)
 	ListQuestionsBySession(ctx context.Context, sessionID string) ([]*entity.Question, error)
-	UpdateQuestionAnswer(ctx context.Context, questionID string, answer string) error
+	UpdateQuestionAnswer(ctx context.Context, sessionID, questionID string, answer string, rawAnswer *string) error
+	AnswerOpenQuestion(ctx context.Context, questionID string, answer string, rawAnswer *string) (bool, error)
 	GetUnansweredQuestions(ctx context.Context, sessionID string) ([]*entity.Question, error)
 	SkipQuestion(ctx context.Context, questionID string) error
+	DeferQuestion(ctx context.Context, questionID string) error
+	GetDeferredQuestions(ctx context.Context, sessionID string) ([]*entity.Question, error)
+	SetSkipReason(ctx context.Context, sessionID, questionID string, reason entity.SkipReason) error
+	SkipUnansweredQuestions(ctx context.Context, sessionID string) (int, error)
 }
 
 type QuestionPostgres struct {
@@ -49,6 +54,11 @@ func (r *QuestionPostgres) CreateQuestion(ctx context.Context, question entity.Q
 		return nil, fmt.Errorf("invalid iteration ID: %w", err)
 	}
 
+	parentID, err := parseParentQuestionID(question.ParentQuestionID)
+	if err != nil {
+		return nil, err
+	}
+
 	dbQuestion, err := r.queries.CreateQuestion(ctx, sqlc.CreateQuestionParams{
 		ID: pgtype.UUID{
 			Bytes: questionID,
@@ -58,10 +68,13 @@ func (r *QuestionPostgres) CreateQuestion(ctx context.Context, question entity.Q
 			Bytes: iterationID,
 			Valid: true,
 		},
-		QuestionNumber: int32(question.QuestionNumber),
-		Status:         string(question.Status),
-		Question:       question.Question,
-		Explanation:    question.Explanation,
+		QuestionNumber:   int32(question.QuestionNumber),
+		Status:           string(question.Status),
+		Question:         question.Question,
+		Explanation:      question.Explanation,
+		Options:          questionOptions(question.Options),
+		ParentQuestionID: parentID,
+		AnswerType:       questionAnswerType(question.AnswerType),
 	})
 	if err != nil {
 		ctxzap.Error(ctx, "failed to create question", zap.Error(err))
@@ -86,6 +99,11 @@ func (r *QuestionPostgres) CreateQuestions(ctx context.Context, questions []enti
 			return fmt.Errorf("invalid iteration ID: %w", err)
 		}
 
+		parentID, err := parseParentQuestionID(q.ParentQuestionID)
+		if err != nil {
+			return err
+		}
+
 		rows = append(rows, []interface{}{
 			pgtype.UUID{Bytes: questionID, Valid: true},
 			pgtype.UUID{Bytes: iterationID, Valid: true},
@@ -93,13 +111,16 @@ func (r *QuestionPostgres) CreateQuestions(ctx context.Context, questions []enti
 			string(q.Status),
 			q.Question,
 			q.Explanation,
+			questionOptions(q.Options),
+			parentID,
+			questionAnswerType(q.AnswerType),
 		})
 	}
 
 	_, err := r.db.CopyFrom(
 		ctx,
 		pgx.Identifier{"iteration_questions"},
-		[]string{"id", "iteration_id", "question_number", "status", "question", "explanation"},
+		[]string{"id", "iteration_id", "question_number", "status", "question", "explanation", "options", "parent_question_id", "answer_type"},
 		pgx.CopyFromRows(rows),
 	)
 	if err != nil {
@@ -180,14 +201,19 @@ func (r *QuestionPostgres) ListQuestionsBySession(ctx context.Context, sessionID
 	return questions, nil
 }
 
-// UpdateQuestionAnswer updates a question's answer
-func (r *QuestionPostgres) UpdateQuestionAnswer(ctx context.Context, questionID string, answer string) error {
+// UpdateQuestionAnswer updates the answer of a question of the session; rawAnswer keeps the original transcription
+func (r *QuestionPostgres) UpdateQuestionAnswer(ctx context.Context, sessionID, questionID string, answer string, rawAnswer *string) error {
 	qID, err := uuid.Parse(questionID)
 	if err != nil {
 		return fmt.Errorf("invalid question ID: %w", err)
 	}
 
-	err = r.queries.UpdateQuestionAnswer(ctx, sqlc.UpdateQuestionAnswerParams{
+	sessID, err := uuid.Parse(sessionID)
+	if err != nil {
+		return fmt.Errorf("invalid session ID: %w", err)
+	}
+
+	params := sqlc.UpdateQuestionAnswerParams{
 		ID: pgtype.UUID{
 			Bytes: qID,
 			Valid: true,
@@ -196,15 +222,67 @@ func (r *QuestionPostgres) UpdateQuestionAnswer(ctx context.Context, questionID
 			String: answer,
 			Valid:  true,
 		},
-	})
+		SessionID: pgtype.UUID{
+			Bytes: sessID,
+			Valid: true,
+		},
+	}
+
+	if rawAnswer != nil {
+		params.RawAnswer = pgtype.Text{
+			String: *rawAnswer,
+			Valid:  true,
+		}
+	}
+
+	rows, err := r.queries.UpdateQuestionAnswer(ctx, params)
 	if err != nil {
 		ctxzap.Error(ctx, "failed to update question answer", zap.Error(err))
 		return err
 	}
 
+	// Questions of other sessions are not touched
+	if rows == 0 {
+		return entity.ErrQuestionNotFound
+	}
+
 	return nil
 }
 
+// AnswerOpenQuestion saves an answer unless the question has been answered meanwhile;
+// it reports whether the answer was saved
+func (r *QuestionPostgres) AnswerOpenQuestion(ctx context.Context, questionID string, answer string, rawAnswer *string) (bool, error) {
+	qID, err := uuid.Parse(questionID)
+	if err != nil {
+		return false, fmt.Errorf("invalid question ID: %w", err)
+	}
+
+	params := sqlc.AnswerOpenQuestionParams{
+		ID: pgtype.UUID{
+			Bytes: qID,
+			Valid: true,
+		},
+		Answer: pgtype.Text{
+			String: answer,
+			Valid:  true,
+		},
+	}
+
+	if rawAnswer != nil {
+		params.RawAnswer = pgtype.Text{
+			String: *rawAnswer,
+			Valid:  true,
+		}
+	}
+
+	rows, err := r.queries.AnswerOpenQuestion(ctx, params)
+	if err != nil {
+		return false, fmt.Errorf("answer open question: %w", err)
+	}
+
+	return rows > 0, nil
+}
+
 func (r *QuestionPostgres) SkipQuestion(ctx context.Context, questionID string) error {
 	qID, err := uuid.Parse(questionID)
 	if err != nil {
@@ -223,6 +301,51 @@ func (r *QuestionPostgres) SkipQuestion(ctx context.Context, questionID string)
 	return nil
 }
 
+// SetSkipReason stores why a skipped question of the session was skipped
+func (r *QuestionPostgres) SetSkipReason(ctx context.Context, sessionID, questionID string, reason entity.SkipReason) error {
+	qID, err := uuid.Parse(questionID)
+	if err != nil {
+		return fmt.Errorf("invalid question ID: %w", err)
+	}
+
+	sessID, err := uuid.Parse(sessionID)
+	if err != nil {
+		return fmt.Errorf("invalid session ID: %w", err)
+	}
+
+	rows, err := r.queries.SetQuestionSkipReason(ctx, sqlc.SetQuestionSkipReasonParams{
+		ID:         pgtype.UUID{Bytes: qID, Valid: true},
+		SkipReason: pgtype.Text{String: string(reason), Valid: true},
+		SessionID:  pgtype.UUID{Bytes: sessID, Valid: true},
+	})
+	if err != nil {
+		ctxzap.Error(ctx, "failed to set question skip reason", zap.Error(err))
+		return err
+	}
+
+	// Only skipped questions of the session carry a reason
+	if rows == 0 {
+		return entity.ErrQuestionNotFound
+	}
+
+	return nil
+}
+
+// SkipUnansweredQuestions marks all unanswered questions of a session as skipped
+func (r *QuestionPostgres) SkipUnansweredQuestions(ctx context.Context, sessionID string) (int, error) {
+	sessID, err := uuid.Parse(sessionID)
+	if err != nil {
+		return 0, fmt.Errorf("invalid session ID: %w", err)
+	}
+
+	rows, err := r.queries.SkipUnansweredSessionQuestions(ctx, pgtype.UUID{Bytes: sessID, Valid: true})
+	if err != nil {
+		return 0, fmt.Errorf("skip unanswered questions: %w", err)
+	}
+
+	return int(rows), nil
+}
+
 // GetUnansweredQuestions gets all unanswered questions for a session
 func (r *QuestionPostgres) GetUnansweredQuestions(ctx context.Context, sessionID string) ([]*entity.Question, error) {
 	sessID, err := uuid.Parse(sessionID)
@@ -246,3 +369,69 @@ func (r *QuestionPostgres) GetUnansweredQuestions(ctx context.Context, sessionID
 
 	return questions, nil
 }
+
+// DeferQuestion moves an unanswered question to the deferred queue
+func (r *QuestionPostgres) DeferQuestion(ctx context.Context, questionID string) error {
+	qID, err := uuid.Parse(questionID)
+	if err != nil {
+		return fmt.Errorf("invalid question ID: %w", err)
+	}
+
+	if err := r.queries.DeferQuestion(ctx, pgtype.UUID{Bytes: qID, Valid: true}); err != nil {
+		ctxzap.Error(ctx, "failed to defer question", zap.Error(err))
+		return err
+	}
+
+	return nil
+}
+
+// GetDeferredQuestions returns deferred questions of a session in interview order
+func (r *QuestionPostgres) GetDeferredQuestions(ctx context.Context, sessionID string) ([]*entity.Question, error) {
+	sessID, err := uuid.Parse(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid session ID: %w", err)
+	}
+
+	dbQuestions, err := r.queries.GetDeferredQuestions(ctx, pgtype.UUID{Bytes: sessID, Valid: true})
+	if err != nil {
+		ctxzap.Error(ctx, "failed to get deferred questions", zap.Error(err))
+		return nil, err
+	}
+
+	questions := make([]*entity.Question, 0, len(dbQuestions))
+	for _, dbQ := range dbQuestions {
+		questions = append(questions, toEntityQuestion(&dbQ))
+	}
+
+	return questions, nil
+}
+
+// parseParentQuestionID converts the optional parent of a follow-up question
+func parseParentQuestionID(id *string) (pgtype.UUID, error) {
+	if id == nil {
+		return pgtype.UUID{}, nil
+	}
+
+	parentID, err := uuid.Parse(*id)
+	if err != nil {
+		return pgtype.UUID{}, fmt.Errorf("invalid parent question ID: %w", err)
+	}
+
+	return pgtype.UUID{Bytes: parentID, Valid: true}, nil
+}
+
+// questionOptions keeps the NOT NULL options column an empty array for questions without options
+func questionOptions(options []string) []string {
+	if options == nil {
+		return []string{}
+	}
+	return options
+}
+
+// questionAnswerType stores questions created without a type as text questions
+func questionAnswerType(answerType entity.QuestionAnswerType) string {
+	if answerType == "" {
+		return string(entity.QuestionAnswerTypeText)
+	}
+	return string(answerType)
+}
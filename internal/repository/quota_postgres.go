@@ -0,0 +1,223 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/repository/sqlc"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// QuotaRepository defines the interface for persistence of quota-limited usage of the tenant in ctx
+type QuotaRepository interface {
+	// ReserveUsage counts one usage of subject, empty for none, in the period starting at periodStart unless
+	// it would pass a limit, 0 disabling one; the check and the count are one atomic step. It returns the ID
+	// of the usage, or the scope of the limit reached and no ID
+	ReserveUsage(ctx context.Context, subject string, kind entity.QuotaKind, periodStart time.Time, limits QuotaLimits) (string, entity.QuotaScope, error)
+	// ReleaseUsage gives back a usage reserved in the period starting at periodStart
+	ReleaseUsage(ctx context.Context, usageID, subject string, kind entity.QuotaKind, periodStart time.Time) error
+	// CountUsage returns the usage of the tenant and of subject since the time
+	CountUsage(ctx context.Context, subject string, kind entity.QuotaKind, since time.Time) (tenantUsed, subjectUsed int, err error)
+	ListUsageBySubject(ctx context.Context, kind entity.QuotaKind, since time.Time, limit int) ([]entity.SubjectQuotaUsage, error)
+}
+
+var _ QuotaRepository = &QuotaPostgres{}
+
+// QuotaLimits are the limits of a reservation, 0 when a limit is disabled
+type QuotaLimits struct {
+	Tenant  int
+	Subject int
+}
+
+// quotaCounter is one counter a reservation updates
+type quotaCounter struct {
+	scope   entity.QuotaScope
+	subject string
+	limit   int
+}
+
+// quotaCounters returns the counters of the tenant and of the subject, in the order they are locked,
+// so concurrent reservations cannot deadlock
+func quotaCounters(subject string, limits QuotaLimits) []quotaCounter {
+	counters := []quotaCounter{{scope: entity.QuotaScopeTenant, limit: limits.Tenant}}
+	if subject != "" {
+		counters = append(counters, quotaCounter{scope: entity.QuotaScopeUser, subject: subject, limit: limits.Subject})
+	}
+	return counters
+}
+
+// QuotaPostgres implements QuotaRepository using PostgreSQL
+type QuotaPostgres struct {
+	db      *pgxpool.Pool
+	queries *sqlc.Queries
+}
+
+func NewQuotaPostgres(db *pgxpool.Pool) *QuotaPostgres {
+	return &QuotaPostgres{
+		db:      db,
+		queries: sqlc.New(db),
+	}
+}
+
+func (r *QuotaPostgres) ReserveUsage(
+	ctx context.Context,
+	subject string,
+	kind entity.QuotaKind,
+	periodStart time.Time,
+	limits QuotaLimits,
+) (string, entity.QuotaScope, error) {
+	tenantID := entity.TenantIDFromContext(ctx)
+	period := pgtype.Timestamp{Time: periodStart, Valid: true}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return "", "", fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	q := r.queries.WithTx(tx)
+
+	for _, counter := range quotaCounters(subject, limits) {
+		if err := q.EnsureQuotaCounter(ctx, sqlc.EnsureQuotaCounterParams{
+			TenantID:    tenantID,
+			Kind:        string(kind),
+			PeriodStart: period,
+			Scope:       string(counter.scope),
+			Subject:     counter.subject,
+		}); err != nil {
+			return "", "", fmt.Errorf("ensure quota counter: %w", err)
+		}
+
+		rows, err := q.IncrementQuotaCounter(ctx, sqlc.IncrementQuotaCounterParams{
+			TenantID:    tenantID,
+			Kind:        string(kind),
+			PeriodStart: period,
+			Scope:       string(counter.scope),
+			Subject:     counter.subject,
+			MaxUsed:     int32(counter.limit),
+		})
+		if err != nil {
+			return "", "", fmt.Errorf("increment quota counter: %w", err)
+		}
+		if rows == 0 {
+			return "", counter.scope, nil
+		}
+	}
+
+	id, err := q.RecordQuotaUsage(ctx, sqlc.RecordQuotaUsageParams{
+		TenantID: tenantID,
+		Subject:  subject,
+		Kind:     string(kind),
+	})
+	if err != nil {
+		return "", "", fmt.Errorf("record quota usage: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return "", "", fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return uuid.UUID(id.Bytes).String(), "", nil
+}
+
+func (r *QuotaPostgres) ReleaseUsage(
+	ctx context.Context,
+	usageID, subject string,
+	kind entity.QuotaKind,
+	periodStart time.Time,
+) error {
+	id, err := uuid.Parse(usageID)
+	if err != nil {
+		return fmt.Errorf("parse usage ID: %w", err)
+	}
+	tenantID := entity.TenantIDFromContext(ctx)
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	q := r.queries.WithTx(tx)
+
+	rows, err := q.DeleteQuotaUsage(ctx, sqlc.DeleteQuotaUsageParams{
+		ID:       pgtype.UUID{Bytes: id, Valid: true},
+		TenantID: tenantID,
+	})
+	if err != nil {
+		return fmt.Errorf("delete quota usage: %w", err)
+	}
+	// Released already
+	if rows == 0 {
+		return nil
+	}
+
+	for _, counter := range quotaCounters(subject, QuotaLimits{}) {
+		if err := q.DecrementQuotaCounter(ctx, sqlc.DecrementQuotaCounterParams{
+			TenantID:    tenantID,
+			Kind:        string(kind),
+			PeriodStart: pgtype.Timestamp{Time: periodStart, Valid: true},
+			Scope:       string(counter.scope),
+			Subject:     counter.subject,
+		}); err != nil {
+			return fmt.Errorf("decrement quota counter: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *QuotaPostgres) CountUsage(
+	ctx context.Context,
+	subject string,
+	kind entity.QuotaKind,
+	since time.Time,
+) (int, int, error) {
+	row, err := r.queries.CountQuotaUsage(ctx, sqlc.CountQuotaUsageParams{
+		Subject:  subject,
+		TenantID: entity.TenantIDFromContext(ctx),
+		Kind:     string(kind),
+		Since:    pgtype.Timestamp{Time: since, Valid: true},
+	})
+	if err != nil {
+		return 0, 0, fmt.Errorf("count quota usage: %w", err)
+	}
+
+	return int(row.TenantCount), int(row.SubjectCount), nil
+}
+
+func (r *QuotaPostgres) ListUsageBySubject(
+	ctx context.Context,
+	kind entity.QuotaKind,
+	since time.Time,
+	limit int,
+) ([]entity.SubjectQuotaUsage, error) {
+	rows, err := r.queries.ListQuotaUsageBySubject(ctx, sqlc.ListQuotaUsageBySubjectParams{
+		TenantID:    entity.TenantIDFromContext(ctx),
+		Kind:        string(kind),
+		Since:       pgtype.Timestamp{Time: since, Valid: true},
+		MaxSubjects: int32(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list quota usage by subject: %w", err)
+	}
+
+	usage := make([]entity.SubjectQuotaUsage, 0, len(rows))
+	for _, row := range rows {
+		usage = append(usage, entity.SubjectQuotaUsage{
+			Subject: row.Subject,
+			Kind:    kind,
+			Used:    int(row.Used),
+		})
+	}
+
+	return usage, nil
+}
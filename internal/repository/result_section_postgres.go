@@ -0,0 +1,98 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/repository/sqlc"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ResultSectionRepository defines the interface for sectioned result persistence
+type ResultSectionRepository interface {
+	ReplaceSections(ctx context.Context, sessionID string, sections []*entity.ResultSection) error
+	ListSections(ctx context.Context, sessionID string) ([]*entity.ResultSection, error)
+}
+
+var _ ResultSectionRepository = &ResultSectionPostgres{}
+
+// ResultSectionPostgres implements ResultSectionRepository using PostgreSQL
+type ResultSectionPostgres struct {
+	db      *pgxpool.Pool
+	queries *sqlc.Queries
+}
+
+func NewResultSectionPostgres(db *pgxpool.Pool) *ResultSectionPostgres {
+	return &ResultSectionPostgres{
+		db:      db,
+		queries: sqlc.New(db),
+	}
+}
+
+// ReplaceSections atomically replaces all sections of a session result
+func (r *ResultSectionPostgres) ReplaceSections(
+	ctx context.Context,
+	sessionID string,
+	sections []*entity.ResultSection,
+) error {
+	sessID, err := uuid.Parse(sessionID)
+	if err != nil {
+		return fmt.Errorf("invalid session ID: %w", err)
+	}
+	pgSessionID := pgtype.UUID{Bytes: sessID, Valid: true}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	q := r.queries.WithTx(tx)
+
+	if err := q.DeleteResultSections(ctx, pgSessionID); err != nil {
+		return fmt.Errorf("delete result sections: %w", err)
+	}
+
+	for _, section := range sections {
+		if _, err := q.UpsertResultSection(ctx, sqlc.UpsertResultSectionParams{
+			SessionID:    pgSessionID,
+			SectionIndex: int32(section.SectionIndex),
+			Title:        section.Title,
+			Description:  section.Description,
+			Content:      section.Content,
+		}); err != nil {
+			return fmt.Errorf("save result section %d: %w", section.SectionIndex, err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *ResultSectionPostgres) ListSections(ctx context.Context, sessionID string) ([]*entity.ResultSection, error) {
+	sessID, err := uuid.Parse(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid session ID: %w", err)
+	}
+
+	dbSections, err := r.queries.ListResultSections(ctx, pgtype.UUID{
+		Bytes: sessID,
+		Valid: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list result sections: %w", err)
+	}
+
+	sections := make([]*entity.ResultSection, 0, len(dbSections))
+	for i := range dbSections {
+		sections = append(sections, toEntityResultSection(&dbSections[i]))
+	}
+
+	return sections, nil
+}
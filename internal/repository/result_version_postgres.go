@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/repository/sqlc"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ResultVersionRepository defines the interface for session result version metadata
+type ResultVersionRepository interface {
+	CreateVersion(ctx context.Context, version *entity.ResultVersion) (*entity.ResultVersion, error)
+	GetLatestVersion(ctx context.Context, sessionID string) (*entity.ResultVersion, error)
+	// ArchiveContent copies the body of an inline version from sessions.result before it is replaced
+	ArchiveContent(ctx context.Context, version *entity.ResultVersion) error
+	GetVersion(ctx context.Context, sessionID string, version int) (*entity.ResultVersion, error)
+	ListVersions(ctx context.Context, sessionID string) ([]*entity.ResultVersion, error)
+}
+
+var _ ResultVersionRepository = &ResultVersionPostgres{}
+
+// ResultVersionPostgres implements ResultVersionRepository using PostgreSQL
+type ResultVersionPostgres struct {
+	db      *pgxpool.Pool
+	queries *sqlc.Queries
+}
+
+func NewResultVersionPostgres(db *pgxpool.Pool) *ResultVersionPostgres {
+	return &ResultVersionPostgres{
+		db:      db,
+		queries: sqlc.New(db),
+	}
+}
+
+// CreateVersion records the metadata of a new result version
+func (r *ResultVersionPostgres) CreateVersion(ctx context.Context, version *entity.ResultVersion) (*entity.ResultVersion, error) {
+	sessionID, err := uuid.Parse(version.SessionID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid session ID: %w", err)
+	}
+
+	params := sqlc.CreateSessionResultVersionParams{
+		SessionID: pgtype.UUID{Bytes: sessionID, Valid: true},
+		Version:   int32(version.Version),
+		Storage:   string(version.Storage),
+		SizeBytes: int32(version.SizeBytes),
+		Checksum:  version.Checksum,
+	}
+	if version.ObjectKey != nil {
+		params.ObjectKey = pgtype.Text{String: *version.ObjectKey, Valid: true}
+	}
+
+	dbVersion, err := r.queries.CreateSessionResultVersion(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("create result version: %w", err)
+	}
+
+	return toEntityResultVersion(&dbVersion), nil
+}
+
+func (r *ResultVersionPostgres) ArchiveContent(ctx context.Context, version *entity.ResultVersion) error {
+	id, err := uuid.Parse(version.ID)
+	if err != nil {
+		return fmt.Errorf("invalid result version ID: %w", err)
+	}
+
+	if err := r.queries.ArchiveSessionResultVersionContent(ctx, pgtype.UUID{Bytes: id, Valid: true}); err != nil {
+		return fmt.Errorf("archive result version content: %w", err)
+	}
+
+	return nil
+}
+
+// GetLatestVersion returns the current result version of a session or ErrNoResult
+func (r *ResultVersionPostgres) GetLatestVersion(ctx context.Context, sessionID string) (*entity.ResultVersion, error) {
+	sessID, err := uuid.Parse(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid session ID: %w", err)
+	}
+
+	dbVersion, err := r.queries.GetLatestSessionResultVersion(ctx, pgtype.UUID{Bytes: sessID, Valid: true})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, entity.ErrNoResult
+		}
+		return nil, fmt.Errorf("get latest result version: %w", err)
+	}
+
+	return toEntityResultVersion(&dbVersion), nil
+}
+
+// GetVersion returns a result version of a session with its inline body or ErrResultVersionNotFound
+func (r *ResultVersionPostgres) GetVersion(ctx context.Context, sessionID string, version int) (*entity.ResultVersion, error) {
+	sessID, err := uuid.Parse(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid session ID: %w", err)
+	}
+
+	dbVersion, err := r.queries.GetSessionResultVersion(ctx, sqlc.GetSessionResultVersionParams{
+		SessionID: pgtype.UUID{Bytes: sessID, Valid: true},
+		Version:   int32(version),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, entity.ErrResultVersionNotFound
+		}
+		return nil, fmt.Errorf("get result version: %w", err)
+	}
+
+	return toEntityResultVersion(&dbVersion), nil
+}
+
+// ListVersions returns the result versions of a session without their bodies, newest first
+func (r *ResultVersionPostgres) ListVersions(ctx context.Context, sessionID string) ([]*entity.ResultVersion, error) {
+	sessID, err := uuid.Parse(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid session ID: %w", err)
+	}
+
+	rows, err := r.queries.ListSessionResultVersions(ctx, pgtype.UUID{Bytes: sessID, Valid: true})
+	if err != nil {
+		return nil, fmt.Errorf("list result versions: %w", err)
+	}
+
+	versions := make([]*entity.ResultVersion, 0, len(rows))
+	for i := range rows {
+		versions = append(versions, toEntityResultVersionListRow(&rows[i]))
+	}
+
+	return versions, nil
+}
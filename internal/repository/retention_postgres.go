@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/repository/sqlc"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// RetentionRepository defines the interface for purging session content under the retention policy of a tenant
+type RetentionRepository interface {
+	// PurgeSessions removes the content of the sessions of the tenant without activity since before and
+	// records the purged volumes. It returns the report, nil when nothing expired, and the blob keys of
+	// the purged result versions, which the caller removes from the result storage
+	PurgeSessions(ctx context.Context, tenantID string, before time.Time) (*entity.RetentionPurge, []string, error)
+	ListPurges(ctx context.Context, tenantID string, limit int) ([]*entity.RetentionPurge, error)
+}
+
+var _ RetentionRepository = &RetentionPostgres{}
+
+// RetentionPostgres implements RetentionRepository using PostgreSQL
+type RetentionPostgres struct {
+	db      *pgxpool.Pool
+	queries *sqlc.Queries
+}
+
+func NewRetentionPostgres(db *pgxpool.Pool) *RetentionPostgres {
+	return &RetentionPostgres{
+		db:      db,
+		queries: sqlc.New(db),
+	}
+}
+
+func (r *RetentionPostgres) PurgeSessions(
+	ctx context.Context,
+	tenantID string,
+	before time.Time,
+) (*entity.RetentionPurge, []string, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	q := r.queries.WithTx(tx)
+
+	sessionIDs, err := q.PurgeSessionsContent(ctx, sqlc.PurgeSessionsContentParams{
+		TenantID: tenantID,
+		Before:   pgtype.Timestamp{Time: before, Valid: true},
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("purge sessions: %w", err)
+	}
+	if len(sessionIDs) == 0 {
+		return nil, nil, nil
+	}
+
+	questions, err := q.PurgeSessionQuestions(ctx, sessionIDs)
+	if err != nil {
+		return nil, nil, fmt.Errorf("purge questions: %w", err)
+	}
+
+	messages, err := q.PurgeSessionMessages(ctx, sessionIDs)
+	if err != nil {
+		return nil, nil, fmt.Errorf("purge messages: %w", err)
+	}
+
+	versions, err := q.PurgeSessionResultVersions(ctx, sessionIDs)
+	if err != nil {
+		return nil, nil, fmt.Errorf("purge result versions: %w", err)
+	}
+
+	if err := q.DeleteSessionsDerivedContent(ctx, sessionIDs); err != nil {
+		return nil, nil, fmt.Errorf("delete derived content: %w", err)
+	}
+
+	var resultBytes int64
+	objectKeys := make([]string, 0, len(versions))
+	for _, version := range versions {
+		resultBytes += int64(version.SizeBytes)
+		if version.ObjectKey.Valid {
+			objectKeys = append(objectKeys, version.ObjectKey.String)
+		}
+	}
+
+	dbPurge, err := q.CreateRetentionPurge(ctx, sqlc.CreateRetentionPurgeParams{
+		TenantID:       tenantID,
+		Cutoff:         pgtype.Timestamp{Time: before, Valid: true},
+		Sessions:       int32(len(sessionIDs)),
+		Questions:      int32(questions),
+		Messages:       int32(messages),
+		ResultVersions: int32(len(versions)),
+		ResultBytes:    resultBytes,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("record retention purge: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, nil, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return toEntityRetentionPurge(&dbPurge), objectKeys, nil
+}
+
+func (r *RetentionPostgres) ListPurges(ctx context.Context, tenantID string, limit int) ([]*entity.RetentionPurge, error) {
+	dbPurges, err := r.queries.ListRetentionPurges(ctx, sqlc.ListRetentionPurgesParams{
+		TenantID: tenantID,
+		Limit:    int32(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list retention purges: %w", err)
+	}
+
+	purges := make([]*entity.RetentionPurge, 0, len(dbPurges))
+	for i := range dbPurges {
+		purges = append(purges, toEntityRetentionPurge(&dbPurges[i]))
+	}
+
+	return purges, nil
+}
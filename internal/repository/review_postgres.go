@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/repository/sqlc"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ReviewRepository defines the interface for result approval workflow persistence
+type ReviewRepository interface {
+	GetReview(ctx context.Context, sessionID string) (*entity.ResultReview, error)
+	SaveReview(ctx context.Context, review *entity.ResultReview) (*entity.ResultReview, error)
+}
+
+var _ ReviewRepository = &ReviewPostgres{}
+
+// ReviewPostgres implements ReviewRepository using PostgreSQL
+type ReviewPostgres struct {
+	db      *pgxpool.Pool
+	queries *sqlc.Queries
+}
+
+func NewReviewPostgres(db *pgxpool.Pool) *ReviewPostgres {
+	return &ReviewPostgres{
+		db:      db,
+		queries: sqlc.New(db),
+	}
+}
+
+func (r *ReviewPostgres) GetReview(ctx context.Context, sessionID string) (*entity.ResultReview, error) {
+	sessID, err := uuid.Parse(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid session ID: %w", err)
+	}
+	pgSessionID := pgtype.UUID{Bytes: sessID, Valid: true}
+
+	dbReview, err := r.queries.GetSessionReview(ctx, pgSessionID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, entity.ErrReviewNotFound
+		}
+		return nil, fmt.Errorf("get session review: %w", err)
+	}
+
+	dbApprovers, err := r.queries.ListReviewApprovers(ctx, pgSessionID)
+	if err != nil {
+		return nil, fmt.Errorf("list review approvers: %w", err)
+	}
+
+	return toEntityResultReview(&dbReview, dbApprovers), nil
+}
+
+// SaveReview atomically saves the review state together with its approvers
+func (r *ReviewPostgres) SaveReview(ctx context.Context, review *entity.ResultReview) (*entity.ResultReview, error) {
+	sessID, err := uuid.Parse(review.SessionID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid session ID: %w", err)
+	}
+	pgSessionID := pgtype.UUID{Bytes: sessID, Valid: true}
+
+	params := sqlc.UpsertSessionReviewParams{
+		SessionID: pgSessionID,
+		Status:    string(review.Status),
+	}
+
+	if review.DecidedBy != nil {
+		params.DecidedByType = pgtype.Text{String: string(review.DecidedBy.Type), Valid: true}
+		params.DecidedByID = pgtype.Text{String: review.DecidedBy.ID, Valid: true}
+	}
+
+	if review.DecisionComment != nil {
+		params.DecisionComment = pgtype.Text{String: *review.DecisionComment, Valid: true}
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	q := r.queries.WithTx(tx)
+
+	dbReview, err := q.UpsertSessionReview(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("save session review: %w", err)
+	}
+
+	if err := q.DeleteReviewApprovers(ctx, pgSessionID); err != nil {
+		return nil, fmt.Errorf("delete review approvers: %w", err)
+	}
+
+	for _, approver := range review.Approvers {
+		if err := q.AddReviewApprover(ctx, sqlc.AddReviewApproverParams{
+			SessionID:    pgSessionID,
+			ApproverType: string(approver.Type),
+			ApproverID:   approver.ID,
+		}); err != nil {
+			return nil, fmt.Errorf("save review approver: %w", err)
+		}
+	}
+
+	dbApprovers, err := q.ListReviewApprovers(ctx, pgSessionID)
+	if err != nil {
+		return nil, fmt.Errorf("list review approvers: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("commit transaction: %w", err)
+	}
+
+	return toEntityResultReview(&dbReview, dbApprovers), nil
+}
@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/repository/sqlc"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ScheduleRepository defines the interface for project check-in schedules persistence
+type ScheduleRepository interface {
+	CreateSchedule(ctx context.Context, schedule *entity.ProjectSchedule) (*entity.ProjectSchedule, error)
+	ListSchedules(ctx context.Context, projectID string) ([]*entity.ProjectSchedule, error)
+	ListDueSchedules(ctx context.Context, now time.Time) ([]*entity.ProjectSchedule, error)
+	// ClaimSchedule moves the schedule to its next run; ok is false when another
+	// worker has already claimed the run planned at plannedAt
+	ClaimSchedule(ctx context.Context, scheduleID string, plannedAt, nextRunAt time.Time) (ok bool, err error)
+	SetLastSession(ctx context.Context, scheduleID, sessionID string) error
+	// ListUserSchedules returns the schedules bound to the Telegram user in the tenant ctx is scoped to
+	ListUserSchedules(ctx context.Context, telegramUserID int64) ([]*entity.ProjectSchedule, error)
+	SetTimezone(ctx context.Context, scheduleID, timezone string, nextRunAt time.Time) error
+	DeleteSchedule(ctx context.Context, projectID, scheduleID string) error
+}
+
+var _ ScheduleRepository = &SchedulePostgres{}
+
+// SchedulePostgres implements ScheduleRepository using PostgreSQL
+type SchedulePostgres struct {
+	db      *pgxpool.Pool
+	queries *sqlc.Queries
+}
+
+func NewSchedulePostgres(db *pgxpool.Pool) *SchedulePostgres {
+	return &SchedulePostgres{
+		db:      db,
+		queries: sqlc.New(db),
+	}
+}
+
+func (r *SchedulePostgres) CreateSchedule(
+	ctx context.Context,
+	schedule *entity.ProjectSchedule,
+) (*entity.ProjectSchedule, error) {
+	projID, err := uuid.Parse(schedule.ProjectID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid project ID: %w", err)
+	}
+
+	dbSchedule, err := r.queries.CreateProjectSchedule(ctx, sqlc.CreateProjectScheduleParams{
+		ProjectID:      pgtype.UUID{Bytes: projID, Valid: true},
+		CronExpr:       schedule.CronExpr,
+		TelegramUserID: schedule.TelegramUserID,
+		UserGoal:       schedule.UserGoal,
+		NextRunAt:      pgtype.Timestamp{Time: schedule.NextRunAt, Valid: true},
+		Timezone:       schedule.Timezone,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create project schedule: %w", err)
+	}
+
+	return toEntityProjectSchedule(&dbSchedule), nil
+}
+
+func (r *SchedulePostgres) ListSchedules(ctx context.Context, projectID string) ([]*entity.ProjectSchedule, error) {
+	projID, err := uuid.Parse(projectID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid project ID: %w", err)
+	}
+
+	dbSchedules, err := r.queries.ListProjectSchedules(ctx, pgtype.UUID{Bytes: projID, Valid: true})
+	if err != nil {
+		return nil, fmt.Errorf("list project schedules: %w", err)
+	}
+
+	return toEntityProjectSchedules(dbSchedules), nil
+}
+
+func (r *SchedulePostgres) ListDueSchedules(ctx context.Context, now time.Time) ([]*entity.ProjectSchedule, error) {
+	dbSchedules, err := r.queries.ListDueProjectSchedules(ctx, pgtype.Timestamp{Time: now, Valid: true})
+	if err != nil {
+		return nil, fmt.Errorf("list due project schedules: %w", err)
+	}
+
+	return toEntityProjectSchedules(dbSchedules), nil
+}
+
+func (r *SchedulePostgres) ClaimSchedule(
+	ctx context.Context,
+	scheduleID string,
+	plannedAt, nextRunAt time.Time,
+) (bool, error) {
+	schedID, err := uuid.Parse(scheduleID)
+	if err != nil {
+		return false, fmt.Errorf("invalid schedule ID: %w", err)
+	}
+
+	_, err = r.queries.ClaimProjectSchedule(ctx, sqlc.ClaimProjectScheduleParams{
+		ID:          pgtype.UUID{Bytes: schedID, Valid: true},
+		NextRunAt:   pgtype.Timestamp{Time: plannedAt, Valid: true},
+		NextRunAt_2: pgtype.Timestamp{Time: nextRunAt, Valid: true},
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, nil
+		}
+		return false, fmt.Errorf("claim project schedule: %w", err)
+	}
+
+	return true, nil
+}
+
+func (r *SchedulePostgres) SetLastSession(ctx context.Context, scheduleID, sessionID string) error {
+	schedID, err := uuid.Parse(scheduleID)
+	if err != nil {
+		return fmt.Errorf("invalid schedule ID: %w", err)
+	}
+
+	sessID, err := uuid.Parse(sessionID)
+	if err != nil {
+		return fmt.Errorf("invalid session ID: %w", err)
+	}
+
+	if err := r.queries.SetProjectScheduleLastSession(ctx, sqlc.SetProjectScheduleLastSessionParams{
+		ID:            pgtype.UUID{Bytes: schedID, Valid: true},
+		LastSessionID: pgtype.UUID{Bytes: sessID, Valid: true},
+	}); err != nil {
+		return fmt.Errorf("set schedule last session: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SchedulePostgres) ListUserSchedules(ctx context.Context, telegramUserID int64) ([]*entity.ProjectSchedule, error) {
+	dbSchedules, err := r.queries.ListTelegramUserProjectSchedules(ctx, sqlc.ListTelegramUserProjectSchedulesParams{
+		TelegramUserID: telegramUserID,
+		TenantID:       entity.TenantIDFromContext(ctx),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("list user project schedules: %w", err)
+	}
+
+	return toEntityProjectSchedules(dbSchedules), nil
+}
+
+func (r *SchedulePostgres) SetTimezone(ctx context.Context, scheduleID, timezone string, nextRunAt time.Time) error {
+	schedID, err := uuid.Parse(scheduleID)
+	if err != nil {
+		return fmt.Errorf("invalid schedule ID: %w", err)
+	}
+
+	if err := r.queries.SetProjectScheduleTimezone(ctx, sqlc.SetProjectScheduleTimezoneParams{
+		ID:        pgtype.UUID{Bytes: schedID, Valid: true},
+		Timezone:  timezone,
+		NextRunAt: pgtype.Timestamp{Time: nextRunAt, Valid: true},
+	}); err != nil {
+		return fmt.Errorf("set schedule timezone: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SchedulePostgres) DeleteSchedule(ctx context.Context, projectID, scheduleID string) error {
+	projID, err := uuid.Parse(projectID)
+	if err != nil {
+		return fmt.Errorf("invalid project ID: %w", err)
+	}
+
+	schedID, err := uuid.Parse(scheduleID)
+	if err != nil {
+		return fmt.Errorf("invalid schedule ID: %w", entity.ErrInvalidParameter)
+	}
+
+	deleted, err := r.queries.DeleteProjectSchedule(ctx, sqlc.DeleteProjectScheduleParams{
+		ID:        pgtype.UUID{Bytes: schedID, Valid: true},
+		ProjectID: pgtype.UUID{Bytes: projID, Valid: true},
+	})
+	if err != nil {
+		return fmt.Errorf("delete project schedule: %w", err)
+	}
+
+	if deleted == 0 {
+		return entity.ErrScheduleNotFound
+	}
+
+	return nil
+}
+
+func toEntityProjectSchedules(dbSchedules []sqlc.ProjectSchedule) []*entity.ProjectSchedule {
+	schedules := make([]*entity.ProjectSchedule, 0, len(dbSchedules))
+	for i := range dbSchedules {
+		schedules = append(schedules, toEntityProjectSchedule(&dbSchedules[i]))
+	}
+	return schedules
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/repository/sqlc"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// SearchRepository defines the interface for the admin full-text search
+type SearchRepository interface {
+	SearchAll(ctx context.Context, query string, skip, limit int) ([]*entity.AdminSearchHit, int, error)
+}
+
+var _ SearchRepository = &SearchPostgres{}
+
+// SearchPostgres implements SearchRepository using PostgreSQL
+type SearchPostgres struct {
+	db      *pgxpool.Pool
+	queries *sqlc.Queries
+}
+
+func NewSearchPostgres(db *pgxpool.Pool) *SearchPostgres {
+	return &SearchPostgres{
+		db:      db,
+		queries: sqlc.New(db),
+	}
+}
+
+// SearchAll returns one page of hits across projects, files and sessions and the total number of hits
+func (r *SearchPostgres) SearchAll(
+	ctx context.Context,
+	query string,
+	skip, limit int,
+) ([]*entity.AdminSearchHit, int, error) {
+	rows, err := r.queries.SearchAll(ctx, sqlc.SearchAllParams{
+		Query:      query,
+		MaxResults: int32(limit),
+		Skip:       int32(skip),
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("search all: %w", err)
+	}
+
+	total := 0
+	hits := make([]*entity.AdminSearchHit, 0, len(rows))
+	for i := range rows {
+		hits = append(hits, toEntityAdminSearchHit(&rows[i]))
+		total = int(rows[i].TotalCount)
+	}
+
+	return hits, total, nil
+}
@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/repository/sqlc"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// SessionFactsRepository defines the interface for session context snapshots persistence
+type SessionFactsRepository interface {
+	GetFacts(ctx context.Context, sessionID string) (*entity.SessionFacts, error)
+	SaveFacts(ctx context.Context, sessionID string, facts []string, materialHash string) (*entity.SessionFacts, error)
+}
+
+var _ SessionFactsRepository = &SessionFactsPostgres{}
+
+// SessionFactsPostgres implements SessionFactsRepository using PostgreSQL
+type SessionFactsPostgres struct {
+	db      *pgxpool.Pool
+	queries *sqlc.Queries
+}
+
+func NewSessionFactsPostgres(db *pgxpool.Pool) *SessionFactsPostgres {
+	return &SessionFactsPostgres{
+		db:      db,
+		queries: sqlc.New(db),
+	}
+}
+
+func (r *SessionFactsPostgres) GetFacts(ctx context.Context, sessionID string) (*entity.SessionFacts, error) {
+	sessID, err := uuid.Parse(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid session ID: %w", err)
+	}
+
+	dbFacts, err := r.queries.GetSessionFacts(ctx, pgtype.UUID{Bytes: sessID, Valid: true})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, entity.ErrSessionFactsNotFound
+		}
+		return nil, fmt.Errorf("get session facts: %w", err)
+	}
+
+	return toEntitySessionFacts(&dbFacts)
+}
+
+// SaveFacts replaces the context snapshot of the session
+func (r *SessionFactsPostgres) SaveFacts(ctx context.Context, sessionID string, facts []string, materialHash string) (*entity.SessionFacts, error) {
+	sessID, err := uuid.Parse(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid session ID: %w", err)
+	}
+
+	if facts == nil {
+		facts = []string{}
+	}
+	raw, err := json.Marshal(facts)
+	if err != nil {
+		return nil, fmt.Errorf("marshal session facts: %w", err)
+	}
+
+	dbFacts, err := r.queries.UpsertSessionFacts(ctx, sqlc.UpsertSessionFactsParams{
+		SessionID:    pgtype.UUID{Bytes: sessID, Valid: true},
+		Facts:        raw,
+		MaterialHash: materialHash,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("upsert session facts: %w", err)
+	}
+
+	return toEntitySessionFacts(&dbFacts)
+}
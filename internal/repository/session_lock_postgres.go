@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/futig/agent-backend/internal/repository/sqlc"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// unlockTimeout bounds the release of a session lock, which runs after the context of the work may be done
+const unlockTimeout = 5 * time.Second
+
+// SessionLockRepository serializes the actions on a session across processes, e.g. the bot and the
+// HTTP API working on a session linked to both
+type SessionLockRepository interface {
+	// Lock waits until the session is free and returns the function releasing it
+	Lock(ctx context.Context, sessionID string) (func(), error)
+}
+
+var _ SessionLockRepository = &SessionLockPostgres{}
+
+// SessionLockPostgres implements SessionLockRepository with PostgreSQL advisory locks; a lock holds
+// its pool connection until it is released
+type SessionLockPostgres struct {
+	db *pgxpool.Pool
+}
+
+func NewSessionLockPostgres(db *pgxpool.Pool) *SessionLockPostgres {
+	return &SessionLockPostgres{db: db}
+}
+
+func (r *SessionLockPostgres) Lock(ctx context.Context, sessionID string) (func(), error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("acquire connection: %w", err)
+	}
+
+	queries := sqlc.New(conn)
+	if err := queries.LockSession(ctx, sessionID); err != nil {
+		// A lock wait cancelled halfway leaves the connection in an unknown state
+		conn.Hijack().Close(context.Background())
+		return nil, fmt.Errorf("lock session: %w", err)
+	}
+
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), unlockTimeout)
+		defer cancel()
+
+		if err := queries.UnlockSession(ctx, sessionID); err != nil {
+			// Closing the connection ends its database session, which releases the lock
+			conn.Hijack().Close(ctx)
+			return
+		}
+		conn.Release()
+	}, nil
+}
@@ -13,9 +13,10 @@ import (
 
 // SessionMessageRepository defines the interface for session draft messages persistence
 type SessionMessageRepository interface {
-	CreateMessage(ctx context.Context, sessionID, messageText string) (*entity.SessionMessage, error)
+	CreateMessage(ctx context.Context, sessionID, messageText string, rawMessageText *string) (*entity.SessionMessage, error)
 	GetSessionMessages(ctx context.Context, sessionID string) ([]*entity.SessionMessage, error)
 	DeleteSessionMessages(ctx context.Context, sessionID string) error
+	SearchSessionContent(ctx context.Context, sessionID, query string, limit int) ([]*entity.SessionSearchHit, error)
 }
 
 var _ SessionMessageRepository = &SessionMessagePostgres{}
@@ -37,24 +38,41 @@ func (r *SessionMessagePostgres) CreateMessage(
 	ctx context.Context,
 	sessionID string,
 	messageText string,
+	rawMessageText *string,
 ) (*entity.SessionMessage, error) {
 	sessID, err := uuid.Parse(sessionID)
 	if err != nil {
 		return nil, fmt.Errorf("invalid session ID: %w", err)
 	}
 
-	dbMsg, err := r.queries.CreateSessionMessage(ctx, sqlc.CreateSessionMessageParams{
+	params := sqlc.CreateSessionMessageParams{
 		SessionID: pgtype.UUID{
 			Bytes: sessID,
 			Valid: true,
 		},
 		MessageText: messageText,
-	})
+	}
+
+	// Long messages such as transcribed meetings are kept compressed
+	params.MessageTextCompressed = compressText(messageText)
+	if params.MessageTextCompressed != nil {
+		params.MessageText = ""
+	}
+
+	// The original transcription is kept when the normalization pass changed it
+	if rawMessageText != nil {
+		params.RawMessageText = pgtype.Text{
+			String: *rawMessageText,
+			Valid:  true,
+		}
+	}
+
+	dbMsg, err := r.queries.CreateSessionMessage(ctx, params)
 	if err != nil {
 		return nil, fmt.Errorf("create session message: %w", err)
 	}
 
-	return toEntitySessionMessage(&dbMsg), nil
+	return toEntitySessionMessage(&dbMsg)
 }
 
 func (r *SessionMessagePostgres) GetSessionMessages(
@@ -76,7 +94,11 @@ func (r *SessionMessagePostgres) GetSessionMessages(
 
 	messages := make([]*entity.SessionMessage, 0, len(dbMsgs))
 	for i := range dbMsgs {
-		messages = append(messages, toEntitySessionMessage(&dbMsgs[i]))
+		message, err := toEntitySessionMessage(&dbMsgs[i])
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, message)
 	}
 
 	return messages, nil
@@ -97,3 +119,34 @@ func (r *SessionMessagePostgres) DeleteSessionMessages(ctx context.Context, sess
 
 	return nil
 }
+
+// SearchSessionContent runs a full-text search over answers and draft messages of the session
+func (r *SessionMessagePostgres) SearchSessionContent(
+	ctx context.Context,
+	sessionID, query string,
+	limit int,
+) ([]*entity.SessionSearchHit, error) {
+	sessID, err := uuid.Parse(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid session ID: %w", err)
+	}
+
+	rows, err := r.queries.SearchSessionContent(ctx, sqlc.SearchSessionContentParams{
+		Query: query,
+		SessionID: pgtype.UUID{
+			Bytes: sessID,
+			Valid: true,
+		},
+		MaxResults: int32(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("search session content: %w", err)
+	}
+
+	hits := make([]*entity.SessionSearchHit, 0, len(rows))
+	for i := range rows {
+		hits = append(hits, toEntitySessionSearchHit(&rows[i]))
+	}
+
+	return hits, nil
+}
@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/repository/sqlc"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// SessionOperationRepository registers the heavy operations in flight on sessions, one per session.
+// Unlike SessionLockRepository it does not wait: a second operation fails while the first one runs
+type SessionOperationRepository interface {
+	// StartOperation registers the operation for ttl; ErrOperationInProgress while another one runs
+	StartOperation(ctx context.Context, op *entity.SessionOperation, ttl time.Duration) (*entity.SessionOperation, error)
+	// GetOperation returns the operation in flight on the session, ErrNoOperation when none runs
+	GetOperation(ctx context.Context, sessionID string) (*entity.SessionOperation, error)
+	// RenewOperation keeps the operation registered for another ttl; ErrNoOperation once it was taken over
+	RenewOperation(ctx context.Context, op *entity.SessionOperation, ttl time.Duration) error
+	FinishOperation(ctx context.Context, op *entity.SessionOperation) error
+}
+
+var _ SessionOperationRepository = &SessionOperationPostgres{}
+
+// SessionOperationPostgres implements SessionOperationRepository using PostgreSQL
+type SessionOperationPostgres struct {
+	db      *pgxpool.Pool
+	queries *sqlc.Queries
+}
+
+func NewSessionOperationPostgres(db *pgxpool.Pool) *SessionOperationPostgres {
+	return &SessionOperationPostgres{
+		db:      db,
+		queries: sqlc.New(db),
+	}
+}
+
+func (r *SessionOperationPostgres) StartOperation(
+	ctx context.Context, op *entity.SessionOperation, ttl time.Duration,
+) (*entity.SessionOperation, error) {
+	sID, err := uuid.Parse(op.SessionID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid session ID: %w", err)
+	}
+
+	dbOperation, err := r.queries.StartSessionOperation(ctx, sqlc.StartSessionOperationParams{
+		SessionID:  pgtype.UUID{Bytes: sID, Valid: true},
+		Operation:  string(op.Kind),
+		Owner:      op.Owner,
+		TtlSeconds: ttlSeconds(ttl),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, entity.ErrOperationInProgress
+		}
+		return nil, fmt.Errorf("start session operation: %w", err)
+	}
+
+	return toEntitySessionOperation(&dbOperation), nil
+}
+
+func (r *SessionOperationPostgres) GetOperation(ctx context.Context, sessionID string) (*entity.SessionOperation, error) {
+	sID, err := uuid.Parse(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid session ID: %w", err)
+	}
+
+	dbOperation, err := r.queries.GetSessionOperation(ctx, pgtype.UUID{Bytes: sID, Valid: true})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, entity.ErrNoOperation
+		}
+		return nil, fmt.Errorf("get session operation: %w", err)
+	}
+
+	return toEntitySessionOperation(&dbOperation), nil
+}
+
+func (r *SessionOperationPostgres) RenewOperation(ctx context.Context, op *entity.SessionOperation, ttl time.Duration) error {
+	sessionID, operationID, err := operationKey(op)
+	if err != nil {
+		return err
+	}
+
+	renewed, err := r.queries.RenewSessionOperation(ctx, sqlc.RenewSessionOperationParams{
+		TtlSeconds: ttlSeconds(ttl),
+		SessionID:  sessionID,
+		ID:         operationID,
+	})
+	if err != nil {
+		return fmt.Errorf("renew session operation: %w", err)
+	}
+	if renewed == 0 {
+		return entity.ErrNoOperation
+	}
+
+	return nil
+}
+
+// FinishOperation removes the registration; one already taken over by another operation stays
+func (r *SessionOperationPostgres) FinishOperation(ctx context.Context, op *entity.SessionOperation) error {
+	sessionID, operationID, err := operationKey(op)
+	if err != nil {
+		return err
+	}
+
+	if err := r.queries.FinishSessionOperation(ctx, sqlc.FinishSessionOperationParams{
+		SessionID: sessionID,
+		ID:        operationID,
+	}); err != nil {
+		return fmt.Errorf("finish session operation: %w", err)
+	}
+
+	return nil
+}
+
+// operationKey identifies the registration of one run of an operation
+func operationKey(op *entity.SessionOperation) (pgtype.UUID, pgtype.UUID, error) {
+	sID, err := uuid.Parse(op.SessionID)
+	if err != nil {
+		return pgtype.UUID{}, pgtype.UUID{}, fmt.Errorf("invalid session ID: %w", err)
+	}
+
+	opID, err := uuid.Parse(op.ID)
+	if err != nil {
+		return pgtype.UUID{}, pgtype.UUID{}, fmt.Errorf("invalid operation ID: %w", err)
+	}
+
+	return pgtype.UUID{Bytes: sID, Valid: true}, pgtype.UUID{Bytes: opID, Valid: true}, nil
+}
+
+// ttlSeconds rounds ttl up to whole seconds, so a registration never expires before ttl
+func ttlSeconds(ttl time.Duration) int32 {
+	return int32((ttl + time.Second - 1) / time.Second)
+}
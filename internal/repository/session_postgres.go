@@ -2,11 +2,15 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/pkg/metrics"
 	"github.com/futig/agent-backend/internal/repository/sqlc"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
@@ -16,18 +20,28 @@ type SessionRepository interface {
 	CreateSession(ctx context.Context, session entity.Session) (*entity.Session, error)
 	CreateFilledSession(ctx context.Context, session *entity.Session) (*entity.Session, error)
 	GetSessionByID(ctx context.Context, id string) (*entity.Session, error)
+	GetLatestProjectResultSession(ctx context.Context, projectID string) (*entity.Session, error)
+	GetPreviousProjectResultSession(ctx context.Context, projectID string, before time.Time) (*entity.Session, error)
 	AquireSessionByID(ctx context.Context, id string) (*entity.Session, error)
 	UpdateSessionStatus(ctx context.Context, id string, status entity.SessionStatus) (*entity.Session, error)
+	// TransitionSessionStatus changes the status only while the session is in the from status;
+	// otherwise nothing changes and the current session is returned with applied set to false
+	TransitionSessionStatus(ctx context.Context, id string, from, to entity.SessionStatus) (session *entity.Session, applied bool, err error)
 	UpdateSessionIteration(ctx context.Context, id string) (*entity.Session, error)
 	ResetSessionIteration(ctx context.Context, id string) (*entity.Session, error)
 	UpdateSessionProjectContext(ctx context.Context, id, projectCtx string) (*entity.Session, error)
 	UpdateSessionRAGProjectContext(ctx context.Context, sessionID, projectID, projectCtx string) (*entity.Session, error)
 	UpdateSessionUserGoal(ctx context.Context, id, userGoal string) (*entity.Session, error)
 	UpdateSessionType(ctx context.Context, id string, sessionType entity.SessionType) (*entity.Session, error)
+	UpdateSessionLanguage(ctx context.Context, id string, language entity.ResultLanguage, explicit bool) (*entity.Session, error)
+	UpdateSessionSummaryStyle(ctx context.Context, id string, style entity.SummaryStyle) (*entity.Session, error)
 	UpdateSessionResult(ctx context.Context, id string, status entity.SessionStatus, result, err *string) (
 		*entity.Session, error,
 	)
+	TouchSessionActivity(ctx context.Context, id string) (*entity.Session, error)
+	RefreshSessionCurrentQuestion(ctx context.Context, id string) (*entity.Session, error)
 	DeleteSession(ctx context.Context, id string) error
+	DeleteDemoSessionsBefore(ctx context.Context, before time.Time) (int, error)
 }
 
 var _ SessionRepository = &SessionPostgres{}
@@ -56,7 +70,10 @@ func (r *SessionPostgres) CreateSession(ctx context.Context, session entity.Sess
 			Bytes: sessionID,
 			Valid: true,
 		},
-		Status: string(session.Status),
+		Status:   string(session.Status),
+		IsDemo:   session.IsDemo,
+		TenantID: entity.TenantIDFromContext(ctx),
+		OwnerID:  ownerFilter(ctx),
 	}
 
 	dbSession, err := r.queries.CreateSession(ctx, params)
@@ -64,7 +81,7 @@ func (r *SessionPostgres) CreateSession(ctx context.Context, session entity.Sess
 		return nil, fmt.Errorf("create session: %w", err)
 	}
 
-	return toEntitySession(&dbSession), nil
+	return toEntitySession(&dbSession)
 }
 
 func (r *SessionPostgres) CreateFilledSession(ctx context.Context, session *entity.Session) (*entity.Session, error) {
@@ -78,7 +95,14 @@ func (r *SessionPostgres) CreateFilledSession(ctx context.Context, session *enti
 			Bytes: sessionID,
 			Valid: true,
 		},
-		Status: string(session.Status),
+		Status:              string(session.Status),
+		IsDemo:              session.IsDemo,
+		TenantID:            entity.TenantIDFromContext(ctx),
+		CallbackGranularity: string(session.CallbackGranularity),
+		OwnerID:             ownerFilter(ctx),
+	}
+	if params.CallbackGranularity == "" {
+		params.CallbackGranularity = string(entity.CallbackGranularityIteration)
 	}
 
 	// Set optional project_id
@@ -109,11 +133,12 @@ func (r *SessionPostgres) CreateFilledSession(ctx context.Context, session *enti
 		}
 	}
 
-	// Set optional project_context
+	// Set optional project_context, compressed when it is long
 	if session.ProjectContext != nil {
+		params.ProjectContextCompressed = compressText(*session.ProjectContext)
 		params.ProjectContext = pgtype.Text{
 			String: *session.ProjectContext,
-			Valid:  true,
+			Valid:  params.ProjectContextCompressed == nil,
 		}
 	}
 
@@ -122,7 +147,7 @@ func (r *SessionPostgres) CreateFilledSession(ctx context.Context, session *enti
 		return nil, fmt.Errorf("create filled session: %w", err)
 	}
 
-	return toEntitySession(&dbSession), nil
+	return toEntitySession(&dbSession)
 }
 
 func (r *SessionPostgres) GetSessionByID(ctx context.Context, id string) (*entity.Session, error) {
@@ -131,15 +156,75 @@ func (r *SessionPostgres) GetSessionByID(ctx context.Context, id string) (*entit
 		return nil, fmt.Errorf("invalid session ID: %w", err)
 	}
 
-	dbSession, err := r.queries.GetSessionByID(ctx, pgtype.UUID{
-		Bytes: sessionID,
-		Valid: true,
+	dbSession, err := r.queries.GetSessionByID(ctx, sqlc.GetSessionByIDParams{
+		ID: pgtype.UUID{
+			Bytes: sessionID,
+			Valid: true,
+		},
+		TenantID: entity.TenantIDFromContext(ctx),
+		OwnerID:  ownerFilter(ctx),
 	})
 	if err != nil {
 		return nil, fmt.Errorf("get session: %w", err)
 	}
 
-	return toEntitySession(&dbSession), nil
+	return toEntitySession(&dbSession)
+}
+
+// GetLatestProjectResultSession returns the most recently completed session of the project
+// with a result; ErrSessionNotFound when the project has none
+func (r *SessionPostgres) GetLatestProjectResultSession(ctx context.Context, projectID string) (*entity.Session, error) {
+	projID, err := uuid.Parse(projectID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid project ID: %w", err)
+	}
+
+	dbSession, err := r.queries.GetLatestProjectResultSession(ctx, sqlc.GetLatestProjectResultSessionParams{
+		ProjectID: pgtype.UUID{
+			Bytes: projID,
+			Valid: true,
+		},
+		TenantID: entity.TenantIDFromContext(ctx),
+		OwnerID:  ownerFilter(ctx),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, entity.ErrSessionNotFound
+		}
+		return nil, fmt.Errorf("get latest project result session: %w", err)
+	}
+
+	return toEntitySession(&dbSession)
+}
+
+// GetPreviousProjectResultSession returns the most recent completed session of the project with
+// a result started before the given time; ErrSessionNotFound when there is none
+func (r *SessionPostgres) GetPreviousProjectResultSession(ctx context.Context, projectID string, before time.Time) (*entity.Session, error) {
+	projID, err := uuid.Parse(projectID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid project ID: %w", err)
+	}
+
+	dbSession, err := r.queries.GetPreviousProjectResultSession(ctx, sqlc.GetPreviousProjectResultSessionParams{
+		ProjectID: pgtype.UUID{
+			Bytes: projID,
+			Valid: true,
+		},
+		TenantID: entity.TenantIDFromContext(ctx),
+		Before: pgtype.Timestamp{
+			Time:  before.UTC(),
+			Valid: true,
+		},
+		OwnerID: ownerFilter(ctx),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, entity.ErrSessionNotFound
+		}
+		return nil, fmt.Errorf("get previous project result session: %w", err)
+	}
+
+	return toEntitySession(&dbSession)
 }
 
 func (r *SessionPostgres) AquireSessionByID(ctx context.Context, id string) (*entity.Session, error) {
@@ -148,15 +233,19 @@ func (r *SessionPostgres) AquireSessionByID(ctx context.Context, id string) (*en
 		return nil, fmt.Errorf("invalid session ID: %w", err)
 	}
 
-	dbSession, err := r.queries.AquireSessionByID(ctx, pgtype.UUID{
-		Bytes: sessionID,
-		Valid: true,
+	dbSession, err := r.queries.AquireSessionByID(ctx, sqlc.AquireSessionByIDParams{
+		ID: pgtype.UUID{
+			Bytes: sessionID,
+			Valid: true,
+		},
+		TenantID: entity.TenantIDFromContext(ctx),
+		OwnerID:  ownerFilter(ctx),
 	})
 	if err != nil {
 		return nil, fmt.Errorf("get session: %w", err)
 	}
 
-	return toEntitySession(&dbSession), nil
+	return toEntitySession(&dbSession)
 }
 
 func (r *SessionPostgres) UpdateSessionStatus(ctx context.Context, id string, status entity.SessionStatus) (
@@ -172,13 +261,53 @@ func (r *SessionPostgres) UpdateSessionStatus(ctx context.Context, id string, st
 			Bytes: sessionID,
 			Valid: true,
 		},
-		Status: string(status),
+		Status:   string(status),
+		TenantID: entity.TenantIDFromContext(ctx),
 	})
 	if err != nil {
 		return nil, fmt.Errorf("update session status: %w", err)
 	}
+	metrics.SessionStatusChanges.WithLabelValues(string(status)).Inc()
 
-	return toEntitySession(&dbSession), nil
+	return toEntitySession(&dbSession)
+}
+
+func (r *SessionPostgres) TransitionSessionStatus(ctx context.Context, id string, from, to entity.SessionStatus) (
+	*entity.Session, bool, error,
+) {
+	sessionID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, false, fmt.Errorf("invalid session ID: %w", err)
+	}
+
+	dbSession, err := r.queries.TransitionSessionStatus(ctx, sqlc.TransitionSessionStatusParams{
+		ToStatus: string(to),
+		ID: pgtype.UUID{
+			Bytes: sessionID,
+			Valid: true,
+		},
+		TenantID:   entity.TenantIDFromContext(ctx),
+		FromStatus: string(from),
+	})
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return nil, false, fmt.Errorf("transition session status: %w", err)
+		}
+
+		current, err := r.GetSessionByID(ctx, id)
+		if err != nil {
+			return nil, false, err
+		}
+		return current, false, nil
+	}
+
+	metrics.SessionStatusChanges.WithLabelValues(string(to)).Inc()
+
+	session, err := toEntitySession(&dbSession)
+	if err != nil {
+		return nil, false, err
+	}
+	return session, true, nil
 }
 
 func (r *SessionPostgres) UpdateSessionIteration(ctx context.Context, id string) (*entity.Session, error) {
@@ -187,15 +316,42 @@ func (r *SessionPostgres) UpdateSessionIteration(ctx context.Context, id string)
 		return nil, fmt.Errorf("invalid session ID: %w", err)
 	}
 
-	dbSession, err := r.queries.UpdateSessionIteration(ctx, pgtype.UUID{
-		Bytes: sessionID,
-		Valid: true,
+	dbSession, err := r.queries.UpdateSessionIteration(ctx, sqlc.UpdateSessionIterationParams{
+		ID: pgtype.UUID{
+			Bytes: sessionID,
+			Valid: true,
+		},
+		TenantID: entity.TenantIDFromContext(ctx),
 	})
 	if err != nil {
 		return nil, fmt.Errorf("update session status: %w", err)
 	}
 
-	return toEntitySession(&dbSession), nil
+	return toEntitySession(&dbSession)
+}
+
+// RefreshSessionCurrentQuestion points the session at its first open question, or at none
+func (r *SessionPostgres) RefreshSessionCurrentQuestion(ctx context.Context, id string) (*entity.Session, error) {
+	sessionID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid session ID: %w", err)
+	}
+
+	dbSession, err := r.queries.RefreshSessionCurrentQuestion(ctx, sqlc.RefreshSessionCurrentQuestionParams{
+		ID: pgtype.UUID{
+			Bytes: sessionID,
+			Valid: true,
+		},
+		TenantID: entity.TenantIDFromContext(ctx),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, entity.ErrSessionNotFound
+		}
+		return nil, fmt.Errorf("refresh session current question: %w", err)
+	}
+
+	return toEntitySession(&dbSession)
 }
 
 func (r *SessionPostgres) ResetSessionIteration(ctx context.Context, id string) (*entity.Session, error) {
@@ -204,15 +360,18 @@ func (r *SessionPostgres) ResetSessionIteration(ctx context.Context, id string)
 		return nil, fmt.Errorf("invalid session ID: %w", err)
 	}
 
-	dbSession, err := r.queries.ResetSessionIteration(ctx, pgtype.UUID{
-		Bytes: sessionID,
-		Valid: true,
+	dbSession, err := r.queries.ResetSessionIteration(ctx, sqlc.ResetSessionIterationParams{
+		ID: pgtype.UUID{
+			Bytes: sessionID,
+			Valid: true,
+		},
+		TenantID: entity.TenantIDFromContext(ctx),
 	})
 	if err != nil {
 		return nil, fmt.Errorf("update session status: %w", err)
 	}
 
-	return toEntitySession(&dbSession), nil
+	return toEntitySession(&dbSession)
 }
 
 func (r *SessionPostgres) UpdateSessionProjectContext(ctx context.Context, sessionID, projectCtx string) (
@@ -223,6 +382,8 @@ func (r *SessionPostgres) UpdateSessionProjectContext(ctx context.Context, sessi
 		return nil, fmt.Errorf("invalid session ID: %w", err)
 	}
 
+	compressed := compressText(projectCtx)
+
 	dbSession, err := r.queries.UpdateSessionProjectContext(ctx, sqlc.UpdateSessionProjectContextParams{
 		ID: pgtype.UUID{
 			Bytes: sID,
@@ -230,14 +391,16 @@ func (r *SessionPostgres) UpdateSessionProjectContext(ctx context.Context, sessi
 		},
 		ProjectContext: pgtype.Text{
 			String: projectCtx,
-			Valid:  true,
+			Valid:  compressed == nil,
 		},
+		ProjectContextCompressed: compressed,
+		TenantID:                 entity.TenantIDFromContext(ctx),
 	})
 	if err != nil {
 		return nil, fmt.Errorf("update project contex: %w", err)
 	}
 
-	return toEntitySession(&dbSession), nil
+	return toEntitySession(&dbSession)
 }
 
 func (r *SessionPostgres) UpdateSessionResult(
@@ -253,7 +416,8 @@ func (r *SessionPostgres) UpdateSessionResult(
 			Bytes: sessionID,
 			Valid: true,
 		},
-		Status: string(status),
+		Status:   string(status),
+		TenantID: entity.TenantIDFromContext(ctx),
 	}
 
 	if result != nil {
@@ -275,7 +439,7 @@ func (r *SessionPostgres) UpdateSessionResult(
 		return nil, fmt.Errorf("update session status: %w", err)
 	}
 
-	return toEntitySession(&session), nil
+	return toEntitySession(&session)
 }
 
 func (r *SessionPostgres) UpdateSessionRAGProjectContext(ctx context.Context, sessionID, projectID, projectCtx string) (*entity.Session, error) {
@@ -289,11 +453,14 @@ func (r *SessionPostgres) UpdateSessionRAGProjectContext(ctx context.Context, se
 		return nil, fmt.Errorf("invalid project ID: %w", err)
 	}
 
+	compressed := compressText(projectCtx)
+
 	dbSession, err := r.queries.UpdateSessionRAGProjectContext(ctx, sqlc.UpdateSessionRAGProjectContextParams{
 		ProjectContext: pgtype.Text{
 			String: projectCtx,
-			Valid:  projectCtx != "",
+			Valid:  projectCtx != "" && compressed == nil,
 		},
+		ProjectContextCompressed: compressed,
 		ID: pgtype.UUID{
 			Bytes: sessionUUID,
 			Valid: true,
@@ -302,12 +469,13 @@ func (r *SessionPostgres) UpdateSessionRAGProjectContext(ctx context.Context, se
 			Bytes: projectUUID,
 			Valid: true,
 		},
+		TenantID: entity.TenantIDFromContext(ctx),
 	})
 	if err != nil {
 		return nil, fmt.Errorf("update rag project context: %w", err)
 	}
 
-	return toEntitySession(&dbSession), nil
+	return toEntitySession(&dbSession)
 }
 
 func (r *SessionPostgres) UpdateSessionUserGoal(ctx context.Context, id, userGoal string) (*entity.Session, error) {
@@ -325,12 +493,73 @@ func (r *SessionPostgres) UpdateSessionUserGoal(ctx context.Context, id, userGoa
 			String: userGoal,
 			Valid:  true,
 		},
+		TenantID: entity.TenantIDFromContext(ctx),
 	})
 	if err != nil {
 		return nil, fmt.Errorf("update user goal: %w", err)
 	}
 
-	return toEntitySession(&dbSession), nil
+	return toEntitySession(&dbSession)
+}
+
+// UpdateSessionLanguage stores the language of the session; a detected language (explicit false)
+// does not replace the language the user has set
+func (r *SessionPostgres) UpdateSessionLanguage(
+	ctx context.Context, id string, language entity.ResultLanguage, explicit bool,
+) (*entity.Session, error) {
+	sessionID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid session ID: %w", err)
+	}
+
+	dbSession, err := r.queries.UpdateSessionLanguage(ctx, sqlc.UpdateSessionLanguageParams{
+		ID: pgtype.UUID{
+			Bytes: sessionID,
+			Valid: true,
+		},
+		Language: pgtype.Text{
+			String: string(language),
+			Valid:  true,
+		},
+		Explicit: explicit,
+		TenantID: entity.TenantIDFromContext(ctx),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, entity.ErrSessionNotFound
+		}
+		return nil, fmt.Errorf("update session language: %w", err)
+	}
+
+	return toEntitySession(&dbSession)
+}
+
+// UpdateSessionSummaryStyle stores the style the session result is generated in
+func (r *SessionPostgres) UpdateSessionSummaryStyle(ctx context.Context, id string, style entity.SummaryStyle) (*entity.Session, error) {
+	sessionID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid session ID: %w", err)
+	}
+
+	dbSession, err := r.queries.UpdateSessionSummaryStyle(ctx, sqlc.UpdateSessionSummaryStyleParams{
+		ID: pgtype.UUID{
+			Bytes: sessionID,
+			Valid: true,
+		},
+		SummaryStyle: pgtype.Text{
+			String: string(style),
+			Valid:  style != "",
+		},
+		TenantID: entity.TenantIDFromContext(ctx),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, entity.ErrSessionNotFound
+		}
+		return nil, fmt.Errorf("update session summary style: %w", err)
+	}
+
+	return toEntitySession(&dbSession)
 }
 
 func (r *SessionPostgres) UpdateSessionType(ctx context.Context, id string, sessionType entity.SessionType) (*entity.Session, error) {
@@ -348,12 +577,37 @@ func (r *SessionPostgres) UpdateSessionType(ctx context.Context, id string, sess
 			String: string(sessionType),
 			Valid:  true,
 		},
+		TenantID: entity.TenantIDFromContext(ctx),
 	})
 	if err != nil {
 		return nil, fmt.Errorf("update session type: %w", err)
 	}
 
-	return toEntitySession(&dbSession), nil
+	return toEntitySession(&dbSession)
+}
+
+// TouchSessionActivity records a heartbeat of the session; ErrSessionNotFound when it does not exist
+func (r *SessionPostgres) TouchSessionActivity(ctx context.Context, id string) (*entity.Session, error) {
+	sessionID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid session ID: %w", err)
+	}
+
+	dbSession, err := r.queries.TouchSessionActivity(ctx, sqlc.TouchSessionActivityParams{
+		ID: pgtype.UUID{
+			Bytes: sessionID,
+			Valid: true,
+		},
+		TenantID: entity.TenantIDFromContext(ctx),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, entity.ErrSessionNotFound
+		}
+		return nil, fmt.Errorf("touch session activity: %w", err)
+	}
+
+	return toEntitySession(&dbSession)
 }
 
 func (r *SessionPostgres) DeleteSession(ctx context.Context, id string) error {
@@ -362,9 +616,12 @@ func (r *SessionPostgres) DeleteSession(ctx context.Context, id string) error {
 		return fmt.Errorf("invalid session ID: %w", err)
 	}
 
-	err = r.queries.DeleteSession(ctx, pgtype.UUID{
-		Bytes: sessionID,
-		Valid: true,
+	err = r.queries.DeleteSession(ctx, sqlc.DeleteSessionParams{
+		ID: pgtype.UUID{
+			Bytes: sessionID,
+			Valid: true,
+		},
+		TenantID: entity.TenantIDFromContext(ctx),
 	})
 	if err != nil {
 		return fmt.Errorf("delete session: %w", err)
@@ -372,3 +629,14 @@ func (r *SessionPostgres) DeleteSession(ctx context.Context, id string) error {
 
 	return nil
 }
+
+// DeleteDemoSessionsBefore removes demo sessions neither created nor heartbeated since the given time
+// and returns how many were removed
+func (r *SessionPostgres) DeleteDemoSessionsBefore(ctx context.Context, before time.Time) (int, error) {
+	deleted, err := r.queries.DeleteDemoSessionsBefore(ctx, pgtype.Timestamp{Time: before, Valid: true})
+	if err != nil {
+		return 0, fmt.Errorf("delete demo sessions: %w", err)
+	}
+
+	return int(deleted), nil
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/repository/sqlc"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// SessionTranslationRepository defines the interface for translated session results persistence
+type SessionTranslationRepository interface {
+	GetTranslation(ctx context.Context, sessionID string, language entity.ResultLanguage) (string, error)
+	SaveTranslation(ctx context.Context, sessionID string, language entity.ResultLanguage, result string) error
+	DeleteTranslations(ctx context.Context, sessionID string) error
+}
+
+var _ SessionTranslationRepository = &SessionTranslationPostgres{}
+
+// SessionTranslationPostgres implements SessionTranslationRepository using PostgreSQL
+type SessionTranslationPostgres struct {
+	db      *pgxpool.Pool
+	queries *sqlc.Queries
+}
+
+func NewSessionTranslationPostgres(db *pgxpool.Pool) *SessionTranslationPostgres {
+	return &SessionTranslationPostgres{
+		db:      db,
+		queries: sqlc.New(db),
+	}
+}
+
+func (r *SessionTranslationPostgres) GetTranslation(
+	ctx context.Context,
+	sessionID string,
+	language entity.ResultLanguage,
+) (string, error) {
+	sessID, err := uuid.Parse(sessionID)
+	if err != nil {
+		return "", fmt.Errorf("invalid session ID: %w", err)
+	}
+
+	dbTranslation, err := r.queries.GetSessionTranslation(ctx, sqlc.GetSessionTranslationParams{
+		SessionID: pgtype.UUID{
+			Bytes: sessID,
+			Valid: true,
+		},
+		Language: string(language),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", entity.ErrTranslationNotFound
+		}
+		return "", fmt.Errorf("get session translation: %w", err)
+	}
+
+	return dbTranslation.Result, nil
+}
+
+func (r *SessionTranslationPostgres) SaveTranslation(
+	ctx context.Context,
+	sessionID string,
+	language entity.ResultLanguage,
+	result string,
+) error {
+	sessID, err := uuid.Parse(sessionID)
+	if err != nil {
+		return fmt.Errorf("invalid session ID: %w", err)
+	}
+
+	if _, err := r.queries.UpsertSessionTranslation(ctx, sqlc.UpsertSessionTranslationParams{
+		SessionID: pgtype.UUID{
+			Bytes: sessID,
+			Valid: true,
+		},
+		Language: string(language),
+		Result:   result,
+	}); err != nil {
+		return fmt.Errorf("save session translation: %w", err)
+	}
+
+	return nil
+}
+
+func (r *SessionTranslationPostgres) DeleteTranslations(ctx context.Context, sessionID string) error {
+	sessID, err := uuid.Parse(sessionID)
+	if err != nil {
+		return fmt.Errorf("invalid session ID: %w", err)
+	}
+
+	if err := r.queries.DeleteSessionTranslations(ctx, pgtype.UUID{
+		Bytes: sessID,
+		Valid: true,
+	}); err != nil {
+		return fmt.Errorf("delete session translations: %w", err)
+	}
+
+	return nil
+}
@@ -70,6 +70,9 @@ func (r iteratorForCreateQuestions) Values() ([]interface{}, error) {
 		r.rows[0].Status,
 		r.rows[0].Question,
 		r.rows[0].Explanation,
+		r.rows[0].Options,
+		r.rows[0].ParentQuestionID,
+		r.rows[0].AnswerType,
 	}, nil
 }
 
@@ -78,5 +81,5 @@ func (r iteratorForCreateQuestions) Err() error {
 }
 
 func (q *Queries) CreateQuestions(ctx context.Context, arg []CreateQuestionsParams) (int64, error) {
-	return q.db.CopyFrom(ctx, []string{"iteration_questions"}, []string{"id", "iteration_id", "question_number", "status", "question", "explanation"}, &iteratorForCreateQuestions{rows: arg})
+	return q.db.CopyFrom(ctx, []string{"iteration_questions"}, []string{"id", "iteration_id", "question_number", "status", "question", "explanation", "options", "parent_question_id", "answer_type"}, &iteratorForCreateQuestions{rows: arg})
 }
@@ -12,9 +12,9 @@ import (
 )
 
 const addFile = `-- name: AddFile :one
-INSERT INTO project_files (id, project_id, filename, size, content_type)
-VALUES ($1, $2, $3, $4, $5)
-RETURNING id, project_id, filename, size, content_type, created_at
+INSERT INTO project_files (id, project_id, filename, size, content_type, object_key)
+VALUES ($1, $2, $3, $4, $5, $6)
+RETURNING id, project_id, filename, size, content_type, created_at, object_key
 `
 
 type AddFileParams struct {
@@ -23,6 +23,7 @@ type AddFileParams struct {
 	Filename    string      `json:"filename"`
 	Size        int64       `json:"size"`
 	ContentType string      `json:"content_type"`
+	ObjectKey   pgtype.Text `json:"object_key"`
 }
 
 func (q *Queries) AddFile(ctx context.Context, arg AddFileParams) (ProjectFile, error) {
@@ -32,6 +33,7 @@ func (q *Queries) AddFile(ctx context.Context, arg AddFileParams) (ProjectFile,
 		arg.Filename,
 		arg.Size,
 		arg.ContentType,
+		arg.ObjectKey,
 	)
 	var i ProjectFile
 	err := row.Scan(
@@ -41,28 +43,42 @@ func (q *Queries) AddFile(ctx context.Context, arg AddFileParams) (ProjectFile,
 		&i.Size,
 		&i.ContentType,
 		&i.CreatedAt,
+		&i.ObjectKey,
 	)
 	return i, err
 }
 
 const deleteProjectFile = `-- name: DeleteProjectFile :exec
-DELETE FROM projects WHERE id = $1
+DELETE FROM project_files
+WHERE id = $1
+  AND project_id IN (SELECT id FROM projects WHERE tenant_id = $2)
 `
 
-func (q *Queries) DeleteProjectFile(ctx context.Context, id pgtype.UUID) error {
-	_, err := q.db.Exec(ctx, deleteProjectFile, id)
+type DeleteProjectFileParams struct {
+	ID       pgtype.UUID `json:"id"`
+	TenantID string      `json:"tenant_id"`
+}
+
+func (q *Queries) DeleteProjectFile(ctx context.Context, arg DeleteProjectFileParams) error {
+	_, err := q.db.Exec(ctx, deleteProjectFile, arg.ID, arg.TenantID)
 	return err
 }
 
 const getFiles = `-- name: GetFiles :many
-SELECT id, project_id, filename, size, content_type, created_at
+SELECT id, project_id, filename, size, content_type, created_at, object_key
 FROM project_files
 WHERE project_id = $1
+  AND project_id IN (SELECT id FROM projects WHERE tenant_id = $2)
 ORDER BY created_at ASC
 `
 
-func (q *Queries) GetFiles(ctx context.Context, projectID pgtype.UUID) ([]ProjectFile, error) {
-	rows, err := q.db.Query(ctx, getFiles, projectID)
+type GetFilesParams struct {
+	ProjectID pgtype.UUID `json:"project_id"`
+	TenantID  string      `json:"tenant_id"`
+}
+
+func (q *Queries) GetFiles(ctx context.Context, arg GetFilesParams) ([]ProjectFile, error) {
+	rows, err := q.db.Query(ctx, getFiles, arg.ProjectID, arg.TenantID)
 	if err != nil {
 		return nil, err
 	}
@@ -77,6 +93,7 @@ func (q *Queries) GetFiles(ctx context.Context, projectID pgtype.UUID) ([]Projec
 			&i.Size,
 			&i.ContentType,
 			&i.CreatedAt,
+			&i.ObjectKey,
 		); err != nil {
 			return nil, err
 		}
@@ -87,3 +104,29 @@ func (q *Queries) GetFiles(ctx context.Context, projectID pgtype.UUID) ([]Projec
 	}
 	return items, nil
 }
+
+const getProjectFile = `-- name: GetProjectFile :one
+SELECT id, project_id, filename, size, content_type, created_at, object_key
+FROM project_files
+WHERE id = $1 AND project_id = $2
+`
+
+type GetProjectFileParams struct {
+	ID        pgtype.UUID `json:"id"`
+	ProjectID pgtype.UUID `json:"project_id"`
+}
+
+func (q *Queries) GetProjectFile(ctx context.Context, arg GetProjectFileParams) (ProjectFile, error) {
+	row := q.db.QueryRow(ctx, getProjectFile, arg.ID, arg.ProjectID)
+	var i ProjectFile
+	err := row.Scan(
+		&i.ID,
+		&i.ProjectID,
+		&i.Filename,
+		&i.Size,
+		&i.ContentType,
+		&i.CreatedAt,
+		&i.ObjectKey,
+	)
+	return i, err
+}
@@ -55,6 +55,25 @@ type CreateIterationsParams struct {
 	Title           string      `json:"title"`
 }
 
+const deleteIterationsBySession = `-- name: DeleteIterationsBySession :execrows
+WITH reset AS (
+    UPDATE sessions
+    SET current_iteration = 1,
+        updated_at = NOW()
+    WHERE id = $1
+)
+DELETE FROM session_iterations
+WHERE session_id = $1
+`
+
+func (q *Queries) DeleteIterationsBySession(ctx context.Context, sessionID pgtype.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteIterationsBySession, sessionID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const getCurrentIteration = `-- name: GetCurrentIteration :one
 SELECT si.id, si.session_id, si.iteration_number, si.title, si.created_at FROM session_iterations as si
 JOIN sessions as ss on ss.id = si.session_id
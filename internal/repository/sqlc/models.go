@@ -8,16 +8,131 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type AccountLink struct {
+	TenantID       string           `json:"tenant_id"`
+	ClientID       string           `json:"client_id"`
+	TelegramUserID int64            `json:"telegram_user_id"`
+	CreatedAt      pgtype.Timestamp `json:"created_at"`
+}
+
+type AccountLinkCode struct {
+	TenantID       string           `json:"tenant_id"`
+	Code           string           `json:"code"`
+	TelegramUserID int64            `json:"telegram_user_id"`
+	ExpiresAt      pgtype.Timestamp `json:"expires_at"`
+	RedeemedAt     pgtype.Timestamp `json:"redeemed_at"`
+	CreatedAt      pgtype.Timestamp `json:"created_at"`
+}
+
+type AuditLog struct {
+	ID        pgtype.UUID      `json:"id"`
+	SessionID pgtype.UUID      `json:"session_id"`
+	EventType string           `json:"event_type"`
+	Details   []byte           `json:"details"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+}
+
+type CallbackOutbox struct {
+	ID            pgtype.UUID      `json:"id"`
+	TenantID      string           `json:"tenant_id"`
+	RequestID     string           `json:"request_id"`
+	CallbackUrl   string           `json:"callback_url"`
+	Event         string           `json:"event"`
+	Payload       []byte           `json:"payload"`
+	Status        string           `json:"status"`
+	Attempts      int32            `json:"attempts"`
+	NextAttemptAt pgtype.Timestamp `json:"next_attempt_at"`
+	LastError     pgtype.Text      `json:"last_error"`
+	CreatedAt     pgtype.Timestamp `json:"created_at"`
+	UpdatedAt     pgtype.Timestamp `json:"updated_at"`
+	DeliveredAt   pgtype.Timestamp `json:"delivered_at"`
+}
+
+type DocumentTheme struct {
+	ID           pgtype.UUID      `json:"id"`
+	TenantID     string           `json:"tenant_id"`
+	Name         string           `json:"name"`
+	PrimaryColor pgtype.Text      `json:"primary_color"`
+	HeaderText   pgtype.Text      `json:"header_text"`
+	FooterText   pgtype.Text      `json:"footer_text"`
+	FontName     pgtype.Text      `json:"font_name"`
+	LogoKey      pgtype.Text      `json:"logo_key"`
+	FontKey      pgtype.Text      `json:"font_key"`
+	CreatedAt    pgtype.Timestamp `json:"created_at"`
+}
+
+type ExplanationCooldown struct {
+	TenantID    string           `json:"tenant_id"`
+	Subject     string           `json:"subject"`
+	RequestedAt pgtype.Timestamp `json:"requested_at"`
+}
+
+type FeatureFlagOverride struct {
+	Name           string           `json:"name"`
+	RolloutPercent pgtype.Int4      `json:"rollout_percent"`
+	Disabled       bool             `json:"disabled"`
+	UpdatedAt      pgtype.Timestamp `json:"updated_at"`
+}
+
+type Incident struct {
+	ID            pgtype.UUID      `json:"id"`
+	Code          string           `json:"code"`
+	CorrelationID string           `json:"correlation_id"`
+	Level         string           `json:"level"`
+	Message       string           `json:"message"`
+	Fields        []byte           `json:"fields"`
+	CreatedAt     pgtype.Timestamp `json:"created_at"`
+}
+
 type IterationQuestion struct {
+	ID               pgtype.UUID      `json:"id"`
+	IterationID      pgtype.UUID      `json:"iteration_id"`
+	QuestionNumber   int32            `json:"question_number"`
+	Status           string           `json:"status"`
+	Question         string           `json:"question"`
+	Explanation      string           `json:"explanation"`
+	Answer           pgtype.Text      `json:"answer"`
+	CreatedAt        pgtype.Timestamp `json:"created_at"`
+	AnsweredAt       pgtype.Timestamp `json:"answered_at"`
+	RawAnswer        pgtype.Text      `json:"raw_answer"`
+	SkipReason       pgtype.Text      `json:"skip_reason"`
+	Options          []string         `json:"options"`
+	ParentQuestionID pgtype.UUID      `json:"parent_question_id"`
+	AnswerType       string           `json:"answer_type"`
+}
+
+type Operation struct {
+	RequestID string           `json:"request_id"`
+	ClientID  pgtype.Text      `json:"client_id"`
+	Kind      string           `json:"kind"`
+	SessionID pgtype.UUID      `json:"session_id"`
+	Status    string           `json:"status"`
+	Event     pgtype.Text      `json:"event"`
+	Result    []byte           `json:"result"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+	UpdatedAt pgtype.Timestamp `json:"updated_at"`
+}
+
+type PendingQuestionDelivery struct {
 	ID             pgtype.UUID      `json:"id"`
+	SessionID      pgtype.UUID      `json:"session_id"`
 	IterationID    pgtype.UUID      `json:"iteration_id"`
-	QuestionNumber int32            `json:"question_number"`
-	Status         string           `json:"status"`
-	Question       string           `json:"question"`
-	Explanation    string           `json:"explanation"`
-	Answer         pgtype.Text      `json:"answer"`
+	RequestID      string           `json:"request_id"`
+	Payload        []byte           `json:"payload"`
+	DeliveryError  string           `json:"delivery_error"`
+	CreatedAt      pgtype.Timestamp `json:"created_at"`
+	AcknowledgedAt pgtype.Timestamp `json:"acknowledged_at"`
+}
+
+type PendingVoiceAnswer struct {
+	ID             pgtype.UUID      `json:"id"`
+	TenantID       string           `json:"tenant_id"`
+	SessionID      pgtype.UUID      `json:"session_id"`
+	QuestionID     pgtype.UUID      `json:"question_id"`
+	TelegramUserID int64            `json:"telegram_user_id"`
+	Audio          []byte           `json:"audio"`
+	LockedUntil    pgtype.Timestamp `json:"locked_until"`
 	CreatedAt      pgtype.Timestamp `json:"created_at"`
-	AnsweredAt     pgtype.Timestamp `json:"answered_at"`
 }
 
 type Project struct {
@@ -25,6 +140,9 @@ type Project struct {
 	Title       string           `json:"title"`
 	Description pgtype.Text      `json:"description"`
 	CreatedAt   pgtype.Timestamp `json:"created_at"`
+	TenantID    string           `json:"tenant_id"`
+	ThemeID     pgtype.UUID      `json:"theme_id"`
+	OwnerID     pgtype.UUID      `json:"owner_id"`
 }
 
 type ProjectFile struct {
@@ -34,20 +152,142 @@ type ProjectFile struct {
 	Size        int64            `json:"size"`
 	ContentType string           `json:"content_type"`
 	CreatedAt   pgtype.Timestamp `json:"created_at"`
+	ObjectKey   pgtype.Text      `json:"object_key"`
+}
+
+type ProjectSchedule struct {
+	ID             pgtype.UUID      `json:"id"`
+	ProjectID      pgtype.UUID      `json:"project_id"`
+	CronExpr       string           `json:"cron_expr"`
+	TelegramUserID int64            `json:"telegram_user_id"`
+	UserGoal       string           `json:"user_goal"`
+	NextRunAt      pgtype.Timestamp `json:"next_run_at"`
+	LastRunAt      pgtype.Timestamp `json:"last_run_at"`
+	LastSessionID  pgtype.UUID      `json:"last_session_id"`
+	CreatedAt      pgtype.Timestamp `json:"created_at"`
+	UpdatedAt      pgtype.Timestamp `json:"updated_at"`
+	Timezone       string           `json:"timezone"`
+}
+
+type QuestionExplanation struct {
+	QuestionID  pgtype.UUID      `json:"question_id"`
+	Explanation string           `json:"explanation"`
+	CreatedAt   pgtype.Timestamp `json:"created_at"`
+}
+
+type QuotaCounter struct {
+	TenantID    string           `json:"tenant_id"`
+	Kind        string           `json:"kind"`
+	PeriodStart pgtype.Timestamp `json:"period_start"`
+	Scope       string           `json:"scope"`
+	Subject     string           `json:"subject"`
+	Used        int32            `json:"used"`
+}
+
+type QuotaUsageEvent struct {
+	ID        pgtype.UUID      `json:"id"`
+	TenantID  string           `json:"tenant_id"`
+	Subject   string           `json:"subject"`
+	Kind      string           `json:"kind"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+}
+
+type RetentionPurge struct {
+	ID             pgtype.UUID      `json:"id"`
+	TenantID       string           `json:"tenant_id"`
+	Cutoff         pgtype.Timestamp `json:"cutoff"`
+	Sessions       int32            `json:"sessions"`
+	Questions      int32            `json:"questions"`
+	Messages       int32            `json:"messages"`
+	ResultVersions int32            `json:"result_versions"`
+	ResultBytes    int64            `json:"result_bytes"`
+	CreatedAt      pgtype.Timestamp `json:"created_at"`
 }
 
 type Session struct {
-	ID               pgtype.UUID      `json:"id"`
-	ProjectID        pgtype.UUID      `json:"project_id"`
-	Status           string           `json:"status"`
-	Type             pgtype.Text      `json:"type"`
-	UserGoal         pgtype.Text      `json:"user_goal"`
-	ProjectContext   pgtype.Text      `json:"project_context"`
-	CurrentIteration int32            `json:"current_iteration"`
-	Result           pgtype.Text      `json:"result"`
-	Error            pgtype.Text      `json:"error"`
-	CreatedAt        pgtype.Timestamp `json:"created_at"`
-	UpdatedAt        pgtype.Timestamp `json:"updated_at"`
+	ID                       pgtype.UUID      `json:"id"`
+	ProjectID                pgtype.UUID      `json:"project_id"`
+	Status                   string           `json:"status"`
+	Type                     pgtype.Text      `json:"type"`
+	UserGoal                 pgtype.Text      `json:"user_goal"`
+	ProjectContext           pgtype.Text      `json:"project_context"`
+	CurrentIteration         int32            `json:"current_iteration"`
+	Result                   pgtype.Text      `json:"result"`
+	Error                    pgtype.Text      `json:"error"`
+	CreatedAt                pgtype.Timestamp `json:"created_at"`
+	UpdatedAt                pgtype.Timestamp `json:"updated_at"`
+	ProjectContextCompressed []byte           `json:"project_context_compressed"`
+	IsDemo                   bool             `json:"is_demo"`
+	TenantID                 string           `json:"tenant_id"`
+	LastActivityAt           pgtype.Timestamp `json:"last_activity_at"`
+	CallbackGranularity      string           `json:"callback_granularity"`
+	CurrentQuestionID        pgtype.UUID      `json:"current_question_id"`
+	OwnerID                  pgtype.UUID      `json:"owner_id"`
+	Language                 pgtype.Text      `json:"language"`
+	LanguageExplicit         bool             `json:"language_explicit"`
+	SummaryStyle             pgtype.Text      `json:"summary_style"`
+	PurgedAt                 pgtype.Timestamp `json:"purged_at"`
+}
+
+type SessionComment struct {
+	ID            pgtype.UUID      `json:"id"`
+	SessionID     pgtype.UUID      `json:"session_id"`
+	SectionIndex  pgtype.Int4      `json:"section_index"`
+	RequirementID pgtype.Text      `json:"requirement_id"`
+	Author        string           `json:"author"`
+	Text          string           `json:"text"`
+	ResolvedAt    pgtype.Timestamp `json:"resolved_at"`
+	CreatedAt     pgtype.Timestamp `json:"created_at"`
+}
+
+type SessionConflict struct {
+	ID          pgtype.UUID      `json:"id"`
+	SessionID   pgtype.UUID      `json:"session_id"`
+	Position    int32            `json:"position"`
+	Requirement string           `json:"requirement"`
+	Previous    string           `json:"previous"`
+	Source      pgtype.Text      `json:"source"`
+	Resolution  pgtype.Text      `json:"resolution"`
+	ResolvedAt  pgtype.Timestamp `json:"resolved_at"`
+	CreatedAt   pgtype.Timestamp `json:"created_at"`
+}
+
+type SessionConversationLog struct {
+	ID         int64            `json:"id"`
+	SessionID  pgtype.UUID      `json:"session_id"`
+	QuestionID pgtype.UUID      `json:"question_id"`
+	Kind       string           `json:"kind"`
+	Question   string           `json:"question"`
+	Content    string           `json:"content"`
+	CreatedAt  pgtype.Timestamp `json:"created_at"`
+}
+
+type SessionDelta struct {
+	SessionID         pgtype.UUID      `json:"session_id"`
+	BaselineSessionID pgtype.UUID      `json:"baseline_session_id"`
+	Baseline          string           `json:"baseline"`
+	ChangeLog         pgtype.Text      `json:"change_log"`
+	CreatedAt         pgtype.Timestamp `json:"created_at"`
+	UpdatedAt         pgtype.Timestamp `json:"updated_at"`
+}
+
+type SessionFact struct {
+	SessionID    pgtype.UUID      `json:"session_id"`
+	Facts        []byte           `json:"facts"`
+	MaterialHash string           `json:"material_hash"`
+	UpdatedAt    pgtype.Timestamp `json:"updated_at"`
+}
+
+type SessionGenerationApproval struct {
+	SessionID  pgtype.UUID      `json:"session_id"`
+	ApprovedAt pgtype.Timestamp `json:"approved_at"`
+}
+
+type SessionGenerationFailure struct {
+	SessionID pgtype.UUID      `json:"session_id"`
+	Failures  int32            `json:"failures"`
+	LastError string           `json:"last_error"`
+	UpdatedAt pgtype.Timestamp `json:"updated_at"`
 }
 
 type SessionIteration struct {
@@ -59,10 +299,96 @@ type SessionIteration struct {
 }
 
 type SessionMessage struct {
-	ID          pgtype.UUID      `json:"id"`
-	SessionID   pgtype.UUID      `json:"session_id"`
-	MessageText string           `json:"message_text"`
-	CreatedAt   pgtype.Timestamp `json:"created_at"`
+	ID                    pgtype.UUID      `json:"id"`
+	SessionID             pgtype.UUID      `json:"session_id"`
+	MessageText           string           `json:"message_text"`
+	CreatedAt             pgtype.Timestamp `json:"created_at"`
+	MessageTextCompressed []byte           `json:"message_text_compressed"`
+	RawMessageText        pgtype.Text      `json:"raw_message_text"`
+}
+
+type SessionOperation struct {
+	SessionID pgtype.UUID      `json:"session_id"`
+	ID        pgtype.UUID      `json:"id"`
+	Operation string           `json:"operation"`
+	Owner     string           `json:"owner"`
+	StartedAt pgtype.Timestamp `json:"started_at"`
+	ExpiresAt pgtype.Timestamp `json:"expires_at"`
+}
+
+type SessionResultSection struct {
+	SessionID    pgtype.UUID      `json:"session_id"`
+	SectionIndex int32            `json:"section_index"`
+	Title        string           `json:"title"`
+	Description  string           `json:"description"`
+	Content      string           `json:"content"`
+	UpdatedAt    pgtype.Timestamp `json:"updated_at"`
+}
+
+type SessionResultVersion struct {
+	ID        pgtype.UUID      `json:"id"`
+	SessionID pgtype.UUID      `json:"session_id"`
+	Version   int32            `json:"version"`
+	Storage   string           `json:"storage"`
+	ObjectKey pgtype.Text      `json:"object_key"`
+	SizeBytes int32            `json:"size_bytes"`
+	Checksum  string           `json:"checksum"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+	Content   pgtype.Text      `json:"content"`
+}
+
+type SessionReview struct {
+	SessionID       pgtype.UUID      `json:"session_id"`
+	Status          string           `json:"status"`
+	DecidedByType   pgtype.Text      `json:"decided_by_type"`
+	DecidedByID     pgtype.Text      `json:"decided_by_id"`
+	DecisionComment pgtype.Text      `json:"decision_comment"`
+	CreatedAt       pgtype.Timestamp `json:"created_at"`
+	UpdatedAt       pgtype.Timestamp `json:"updated_at"`
+}
+
+type SessionReviewApprover struct {
+	SessionID    pgtype.UUID `json:"session_id"`
+	ApproverType string      `json:"approver_type"`
+	ApproverID   string      `json:"approver_id"`
+}
+
+type SessionTimeBudget struct {
+	SessionID     pgtype.UUID      `json:"session_id"`
+	BudgetSeconds int32            `json:"budget_seconds"`
+	StartedAt     pgtype.Timestamp `json:"started_at"`
+	WarnedAt      pgtype.Timestamp `json:"warned_at"`
+}
+
+type SessionTranslation struct {
+	SessionID pgtype.UUID      `json:"session_id"`
+	Language  string           `json:"language"`
+	Result    string           `json:"result"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+}
+
+type TelegramInbox struct {
+	ID         pgtype.UUID      `json:"id"`
+	TenantID   string           `json:"tenant_id"`
+	UserID     int64            `json:"user_id"`
+	SessionID  pgtype.UUID      `json:"session_id"`
+	QuestionID pgtype.UUID      `json:"question_id"`
+	Text       string           `json:"text"`
+	CreatedAt  pgtype.Timestamp `json:"created_at"`
+}
+
+type TelegramProjectPin struct {
+	TenantID  string           `json:"tenant_id"`
+	UserID    int64            `json:"user_id"`
+	ProjectID pgtype.UUID      `json:"project_id"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+}
+
+type TelegramProjectUsage struct {
+	TenantID   string           `json:"tenant_id"`
+	UserID     int64            `json:"user_id"`
+	ProjectID  pgtype.UUID      `json:"project_id"`
+	LastUsedAt pgtype.Timestamp `json:"last_used_at"`
 }
 
 type TelegramSession struct {
@@ -71,14 +397,40 @@ type TelegramSession struct {
 	StateData []byte           `json:"state_data"`
 	CreatedAt pgtype.Timestamp `json:"created_at"`
 	UpdatedAt pgtype.Timestamp `json:"updated_at"`
+	TenantID  string           `json:"tenant_id"`
 }
 
 type TelegramUser struct {
-	UserID       int64            `json:"user_id"`
-	Username     pgtype.Text      `json:"username"`
-	FirstName    pgtype.Text      `json:"first_name"`
-	LastName     pgtype.Text      `json:"last_name"`
-	LanguageCode pgtype.Text      `json:"language_code"`
+	UserID               int64            `json:"user_id"`
+	Username             pgtype.Text      `json:"username"`
+	FirstName            pgtype.Text      `json:"first_name"`
+	LastName             pgtype.Text      `json:"last_name"`
+	LanguageCode         pgtype.Text      `json:"language_code"`
+	CreatedAt            pgtype.Timestamp `json:"created_at"`
+	LastActiveAt         pgtype.Timestamp `json:"last_active_at"`
+	NormalizeTranscripts bool             `json:"normalize_transcripts"`
+	QuestionNumbering    pgtype.Text      `json:"question_numbering"`
+	OnboardedAt          pgtype.Timestamp `json:"onboarded_at"`
+	TenantID             string           `json:"tenant_id"`
+	Timezone             pgtype.Text      `json:"timezone"`
+	SummaryStyle         pgtype.Text      `json:"summary_style"`
+	ProgressHeader       pgtype.Bool      `json:"progress_header"`
+}
+
+type Tenant struct {
+	ID           string           `json:"id"`
+	Name         string           `json:"name"`
+	ApiKeyHash   pgtype.Text      `json:"api_key_hash"`
+	BotTokenHash pgtype.Text      `json:"bot_token_hash"`
+	Settings     []byte           `json:"settings"`
 	CreatedAt    pgtype.Timestamp `json:"created_at"`
-	LastActiveAt pgtype.Timestamp `json:"last_active_at"`
+}
+
+type User struct {
+	ID             pgtype.UUID      `json:"id"`
+	TenantID       string           `json:"tenant_id"`
+	Name           string           `json:"name"`
+	ApiKeyHash     pgtype.Text      `json:"api_key_hash"`
+	TelegramUserID pgtype.Int8      `json:"telegram_user_id"`
+	CreatedAt      pgtype.Timestamp `json:"created_at"`
 }
@@ -11,83 +11,299 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const countProjects = `-- name: CountProjects :one
+SELECT COUNT(*)
+FROM projects p
+WHERE p.tenant_id = $1
+  AND ($2::UUID IS NULL OR p.owner_id IS NULL OR p.owner_id = $2)
+  AND NOT EXISTS (
+    SELECT 1 FROM telegram_project_pins pn
+    WHERE pn.tenant_id = p.tenant_id AND pn.user_id = $3 AND pn.project_id = p.id
+  )
+`
+
+type CountProjectsParams struct {
+	TenantID string      `json:"tenant_id"`
+	OwnerID  pgtype.UUID `json:"owner_id"`
+	UserID   int64       `json:"user_id"`
+}
+
+// Counts the projects ListProjects pages through, i.e. without the projects pinned by the user
+func (q *Queries) CountProjects(ctx context.Context, arg CountProjectsParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countProjects, arg.TenantID, arg.OwnerID, arg.UserID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createProject = `-- name: CreateProject :one
-INSERT INTO projects (id, title, description, created_at)
-VALUES ($1, $2, $3, NOW())
-RETURNING id, title, description, created_at
+INSERT INTO projects (id, title, description, created_at, tenant_id, owner_id)
+VALUES ($1, $2, $3, NOW(), $4, $5)
+RETURNING id, title, description, created_at, tenant_id, theme_id, owner_id
 `
 
 type CreateProjectParams struct {
 	ID          pgtype.UUID `json:"id"`
 	Title       string      `json:"title"`
 	Description pgtype.Text `json:"description"`
+	TenantID    string      `json:"tenant_id"`
+	OwnerID     pgtype.UUID `json:"owner_id"`
 }
 
 func (q *Queries) CreateProject(ctx context.Context, arg CreateProjectParams) (Project, error) {
-	row := q.db.QueryRow(ctx, createProject, arg.ID, arg.Title, arg.Description)
+	row := q.db.QueryRow(ctx, createProject,
+		arg.ID,
+		arg.Title,
+		arg.Description,
+		arg.TenantID,
+		arg.OwnerID,
+	)
 	var i Project
 	err := row.Scan(
 		&i.ID,
 		&i.Title,
 		&i.Description,
 		&i.CreatedAt,
+		&i.TenantID,
+		&i.ThemeID,
+		&i.OwnerID,
 	)
 	return i, err
 }
 
-const deleteProject = `-- name: DeleteProject :exec
-DELETE FROM projects WHERE id = $1
+const deleteProject = `-- name: DeleteProject :execrows
+DELETE FROM projects
+WHERE id = $1 AND tenant_id = $2
+  AND ($3::UUID IS NULL OR owner_id IS NULL OR owner_id = $3)
 `
 
-func (q *Queries) DeleteProject(ctx context.Context, id pgtype.UUID) error {
-	_, err := q.db.Exec(ctx, deleteProject, id)
-	return err
+type DeleteProjectParams struct {
+	ID       pgtype.UUID `json:"id"`
+	TenantID string      `json:"tenant_id"`
+	OwnerID  pgtype.UUID `json:"owner_id"`
+}
+
+func (q *Queries) DeleteProject(ctx context.Context, arg DeleteProjectParams) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteProject, arg.ID, arg.TenantID, arg.OwnerID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
 }
 
 const getProject = `-- name: GetProject :one
-SELECT id, title, description, created_at
+SELECT id, title, description, created_at, tenant_id, theme_id, owner_id
 FROM projects
-WHERE id = $1
+WHERE id = $1 AND tenant_id = $2
+  AND ($3::UUID IS NULL OR owner_id IS NULL OR owner_id = $3)
 `
 
-func (q *Queries) GetProject(ctx context.Context, id pgtype.UUID) (Project, error) {
-	row := q.db.QueryRow(ctx, getProject, id)
+type GetProjectParams struct {
+	ID       pgtype.UUID `json:"id"`
+	TenantID string      `json:"tenant_id"`
+	OwnerID  pgtype.UUID `json:"owner_id"`
+}
+
+// A user sees the projects they own and the projects nobody owns; a NULL owner_id sees the whole tenant
+func (q *Queries) GetProject(ctx context.Context, arg GetProjectParams) (Project, error) {
+	row := q.db.QueryRow(ctx, getProject, arg.ID, arg.TenantID, arg.OwnerID)
 	var i Project
 	err := row.Scan(
 		&i.ID,
 		&i.Title,
 		&i.Description,
 		&i.CreatedAt,
+		&i.TenantID,
+		&i.ThemeID,
+		&i.OwnerID,
 	)
 	return i, err
 }
 
+const getProjectFreshness = `-- name: GetProjectFreshness :one
+SELECT COUNT(f.id)::INT AS file_count,
+       MAX(f.created_at)::TIMESTAMP AS last_indexed_at,
+       MIN(f.created_at)::TIMESTAMP AS oldest_file_at
+FROM projects p
+LEFT JOIN project_files f ON f.project_id = p.id
+WHERE p.id = $1 AND p.tenant_id = $2
+  AND ($3::UUID IS NULL OR p.owner_id IS NULL OR p.owner_id = $3)
+GROUP BY p.id
+`
+
+type GetProjectFreshnessParams struct {
+	ID       pgtype.UUID `json:"id"`
+	TenantID string      `json:"tenant_id"`
+	OwnerID  pgtype.UUID `json:"owner_id"`
+}
+
+type GetProjectFreshnessRow struct {
+	FileCount     int32            `json:"file_count"`
+	LastIndexedAt pgtype.Timestamp `json:"last_indexed_at"`
+	OldestFileAt  pgtype.Timestamp `json:"oldest_file_at"`
+}
+
+// Files are saved once the RAG service indexed them, so their creation times tell how old the indexed materials are
+func (q *Queries) GetProjectFreshness(ctx context.Context, arg GetProjectFreshnessParams) (GetProjectFreshnessRow, error) {
+	row := q.db.QueryRow(ctx, getProjectFreshness, arg.ID, arg.TenantID, arg.OwnerID)
+	var i GetProjectFreshnessRow
+	err := row.Scan(&i.FileCount, &i.LastIndexedAt, &i.OldestFileAt)
+	return i, err
+}
+
+const listPinnedProjects = `-- name: ListPinnedProjects :many
+SELECT p.id, p.title, p.description, p.created_at, p.tenant_id, p.theme_id,
+       COALESCE(s.session_count, 0)::BIGINT AS session_count,
+       s.last_session_at,
+       u.last_used_at,
+       f.last_indexed_at
+FROM telegram_project_pins pn
+JOIN projects p ON p.id = pn.project_id
+LEFT JOIN (
+    SELECT project_id, COUNT(*) AS session_count, MAX(created_at) AS last_session_at
+    FROM sessions
+    WHERE tenant_id = $1 AND NOT is_demo
+    GROUP BY project_id
+) s ON s.project_id = p.id
+LEFT JOIN (
+    SELECT project_id, MAX(created_at) AS last_indexed_at
+    FROM project_files
+    GROUP BY project_id
+) f ON f.project_id = p.id
+LEFT JOIN telegram_project_usage u
+    ON u.project_id = p.id AND u.tenant_id = pn.tenant_id AND u.user_id = pn.user_id
+WHERE pn.tenant_id = $1 AND pn.user_id = $2
+  AND ($3::UUID IS NULL OR p.owner_id IS NULL OR p.owner_id = $3)
+ORDER BY pn.created_at
+`
+
+type ListPinnedProjectsParams struct {
+	TenantID string      `json:"tenant_id"`
+	UserID   int64       `json:"user_id"`
+	OwnerID  pgtype.UUID `json:"owner_id"`
+}
+
+type ListPinnedProjectsRow struct {
+	ID            pgtype.UUID      `json:"id"`
+	Title         string           `json:"title"`
+	Description   pgtype.Text      `json:"description"`
+	CreatedAt     pgtype.Timestamp `json:"created_at"`
+	TenantID      string           `json:"tenant_id"`
+	ThemeID       pgtype.UUID      `json:"theme_id"`
+	SessionCount  int64            `json:"session_count"`
+	LastSessionAt pgtype.Timestamp `json:"last_session_at"`
+	LastUsedAt    pgtype.Timestamp `json:"last_used_at"`
+	LastIndexedAt pgtype.Timestamp `json:"last_indexed_at"`
+}
+
+func (q *Queries) ListPinnedProjects(ctx context.Context, arg ListPinnedProjectsParams) ([]ListPinnedProjectsRow, error) {
+	rows, err := q.db.Query(ctx, listPinnedProjects, arg.TenantID, arg.UserID, arg.OwnerID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListPinnedProjectsRow{}
+	for rows.Next() {
+		var i ListPinnedProjectsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Title,
+			&i.Description,
+			&i.CreatedAt,
+			&i.TenantID,
+			&i.ThemeID,
+			&i.SessionCount,
+			&i.LastSessionAt,
+			&i.LastUsedAt,
+			&i.LastIndexedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listProjects = `-- name: ListProjects :many
-SELECT id, title, description, created_at
-FROM projects
-ORDER BY created_at DESC
-LIMIT $1 OFFSET $2
+SELECT p.id, p.title, p.description, p.created_at, p.tenant_id, p.theme_id,
+       COALESCE(s.session_count, 0)::BIGINT AS session_count,
+       s.last_session_at,
+       u.last_used_at,
+       f.last_indexed_at
+FROM projects p
+LEFT JOIN (
+    SELECT project_id, COUNT(*) AS session_count, MAX(created_at) AS last_session_at
+    FROM sessions
+    WHERE tenant_id = $1 AND NOT is_demo
+    GROUP BY project_id
+) s ON s.project_id = p.id
+LEFT JOIN (
+    SELECT project_id, MAX(created_at) AS last_indexed_at
+    FROM project_files
+    GROUP BY project_id
+) f ON f.project_id = p.id
+LEFT JOIN telegram_project_usage u
+    ON u.project_id = p.id AND u.tenant_id = p.tenant_id AND u.user_id = $2
+WHERE p.tenant_id = $1
+  AND ($3::UUID IS NULL OR p.owner_id IS NULL OR p.owner_id = $3)
+  AND NOT EXISTS (
+    SELECT 1 FROM telegram_project_pins pn
+    WHERE pn.tenant_id = p.tenant_id AND pn.user_id = $2 AND pn.project_id = p.id
+  )
+ORDER BY u.last_used_at DESC NULLS LAST, p.created_at DESC
+LIMIT $4 OFFSET $5
 `
 
 type ListProjectsParams struct {
-	Limit  int32 `json:"limit"`
-	Offset int32 `json:"offset"`
+	TenantID    string      `json:"tenant_id"`
+	UserID      int64       `json:"user_id"`
+	OwnerID     pgtype.UUID `json:"owner_id"`
+	LimitCount  int32       `json:"limit_count"`
+	OffsetCount int32       `json:"offset_count"`
+}
+
+type ListProjectsRow struct {
+	ID            pgtype.UUID      `json:"id"`
+	Title         string           `json:"title"`
+	Description   pgtype.Text      `json:"description"`
+	CreatedAt     pgtype.Timestamp `json:"created_at"`
+	TenantID      string           `json:"tenant_id"`
+	ThemeID       pgtype.UUID      `json:"theme_id"`
+	SessionCount  int64            `json:"session_count"`
+	LastSessionAt pgtype.Timestamp `json:"last_session_at"`
+	LastUsedAt    pgtype.Timestamp `json:"last_used_at"`
+	LastIndexedAt pgtype.Timestamp `json:"last_indexed_at"`
 }
 
-func (q *Queries) ListProjects(ctx context.Context, arg ListProjectsParams) ([]Project, error) {
-	rows, err := q.db.Query(ctx, listProjects, arg.Limit, arg.Offset)
+func (q *Queries) ListProjects(ctx context.Context, arg ListProjectsParams) ([]ListProjectsRow, error) {
+	rows, err := q.db.Query(ctx, listProjects,
+		arg.TenantID,
+		arg.UserID,
+		arg.OwnerID,
+		arg.LimitCount,
+		arg.OffsetCount,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []Project{}
+	items := []ListProjectsRow{}
 	for rows.Next() {
-		var i Project
+		var i ListProjectsRow
 		if err := rows.Scan(
 			&i.ID,
 			&i.Title,
 			&i.Description,
 			&i.CreatedAt,
+			&i.TenantID,
+			&i.ThemeID,
+			&i.SessionCount,
+			&i.LastSessionAt,
+			&i.LastUsedAt,
+			&i.LastIndexedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -98,3 +314,124 @@ func (q *Queries) ListProjects(ctx context.Context, arg ListProjectsParams) ([]P
 	}
 	return items, nil
 }
+
+const pinProject = `-- name: PinProject :execrows
+INSERT INTO telegram_project_pins (tenant_id, user_id, project_id)
+SELECT $1::VARCHAR, $2::BIGINT, $3::UUID
+WHERE (
+    SELECT COUNT(*) FROM telegram_project_pins
+    WHERE tenant_id = $1 AND user_id = $2
+) < $4::INT
+ON CONFLICT (tenant_id, user_id, project_id) DO NOTHING
+`
+
+type PinProjectParams struct {
+	TenantID  string      `json:"tenant_id"`
+	UserID    int64       `json:"user_id"`
+	ProjectID pgtype.UUID `json:"project_id"`
+	MaxPins   int32       `json:"max_pins"`
+}
+
+// Nothing is inserted once the user has max_pins pinned projects
+func (q *Queries) PinProject(ctx context.Context, arg PinProjectParams) (int64, error) {
+	result, err := q.db.Exec(ctx, pinProject,
+		arg.TenantID,
+		arg.UserID,
+		arg.ProjectID,
+		arg.MaxPins,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const setProjectDescription = `-- name: SetProjectDescription :execrows
+UPDATE projects
+SET description = $1
+WHERE id = $2 AND tenant_id = $3
+  AND ($4::UUID IS NULL OR owner_id IS NULL OR owner_id = $4)
+`
+
+type SetProjectDescriptionParams struct {
+	Description pgtype.Text `json:"description"`
+	ID          pgtype.UUID `json:"id"`
+	TenantID    string      `json:"tenant_id"`
+	OwnerID     pgtype.UUID `json:"owner_id"`
+}
+
+func (q *Queries) SetProjectDescription(ctx context.Context, arg SetProjectDescriptionParams) (int64, error) {
+	result, err := q.db.Exec(ctx, setProjectDescription,
+		arg.Description,
+		arg.ID,
+		arg.TenantID,
+		arg.OwnerID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const setProjectTheme = `-- name: SetProjectTheme :execrows
+UPDATE projects
+SET theme_id = $1
+WHERE id = $2 AND tenant_id = $3
+  AND ($4::UUID IS NULL OR owner_id IS NULL OR owner_id = $4)
+`
+
+type SetProjectThemeParams struct {
+	ThemeID  pgtype.UUID `json:"theme_id"`
+	ID       pgtype.UUID `json:"id"`
+	TenantID string      `json:"tenant_id"`
+	OwnerID  pgtype.UUID `json:"owner_id"`
+}
+
+func (q *Queries) SetProjectTheme(ctx context.Context, arg SetProjectThemeParams) (int64, error) {
+	result, err := q.db.Exec(ctx, setProjectTheme,
+		arg.ThemeID,
+		arg.ID,
+		arg.TenantID,
+		arg.OwnerID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const touchTelegramProjectUsage = `-- name: TouchTelegramProjectUsage :exec
+INSERT INTO telegram_project_usage (tenant_id, user_id, project_id, last_used_at)
+VALUES ($1, $2, $3, NOW())
+ON CONFLICT (tenant_id, user_id, project_id) DO UPDATE SET last_used_at = EXCLUDED.last_used_at
+`
+
+type TouchTelegramProjectUsageParams struct {
+	TenantID  string      `json:"tenant_id"`
+	UserID    int64       `json:"user_id"`
+	ProjectID pgtype.UUID `json:"project_id"`
+}
+
+func (q *Queries) TouchTelegramProjectUsage(ctx context.Context, arg TouchTelegramProjectUsageParams) error {
+	_, err := q.db.Exec(ctx, touchTelegramProjectUsage, arg.TenantID, arg.UserID, arg.ProjectID)
+	return err
+}
+
+const unpinProject = `-- name: UnpinProject :execrows
+DELETE FROM telegram_project_pins
+WHERE tenant_id = $1 AND user_id = $2 AND project_id = $3
+`
+
+type UnpinProjectParams struct {
+	TenantID  string      `json:"tenant_id"`
+	UserID    int64       `json:"user_id"`
+	ProjectID pgtype.UUID `json:"project_id"`
+}
+
+func (q *Queries) UnpinProject(ctx context.Context, arg UnpinProjectParams) (int64, error) {
+	result, err := q.db.Exec(ctx, unpinProject, arg.TenantID, arg.UserID, arg.ProjectID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
@@ -11,47 +11,277 @@ import (
 )
 
 type Querier interface {
+	AcknowledgePendingQuestionDelivery(ctx context.Context, arg AcknowledgePendingQuestionDeliveryParams) (int64, error)
 	AddFile(ctx context.Context, arg AddFileParams) (ProjectFile, error)
-	AquireSessionByID(ctx context.Context, id pgtype.UUID) (Session, error)
+	AddReviewApprover(ctx context.Context, arg AddReviewApproverParams) error
+	// Saves an answer unless the question has been answered meanwhile
+	AnswerOpenQuestion(ctx context.Context, arg AnswerOpenQuestionParams) (int64, error)
+	// Copies the question text next to the entry so the log reads on its own
+	AppendConversationEntry(ctx context.Context, arg AppendConversationEntryParams) error
+	ApproveSessionGeneration(ctx context.Context, sessionID pgtype.UUID) error
+	AquireSessionByID(ctx context.Context, arg AquireSessionByIDParams) (Session, error)
+	// The body of the current inline version is sessions.result; it moves into the version right
+	// before a newer one overwrites sessions.result
+	ArchiveSessionResultVersionContent(ctx context.Context, id pgtype.UUID) error
+	// Leases the due events of all tenants for lease_seconds, events not settled by then are claimed again
+	ClaimDueCallbackDeliveries(ctx context.Context, arg ClaimDueCallbackDeliveriesParams) ([]CallbackOutbox, error)
+	// Locks the oldest unlocked answers of all tenants for lease_seconds, so that concurrent
+	// workers never transcribe the same answer
+	ClaimPendingVoiceAnswers(ctx context.Context, arg ClaimPendingVoiceAnswersParams) ([]PendingVoiceAnswer, error)
+	ClaimProjectSchedule(ctx context.Context, arg ClaimProjectScheduleParams) (ProjectSchedule, error)
+	CompleteOperation(ctx context.Context, arg CompleteOperationParams) error
+	CountClientOperations(ctx context.Context, clientID pgtype.Text) (int64, error)
+	// Counts the projects ListProjects pages through, i.e. without the projects pinned by the user
+	CountProjects(ctx context.Context, arg CountProjectsParams) (int64, error)
+	// Counts the usage of the whole tenant and of one of its subjects in a single pass
+	CountQuotaUsage(ctx context.Context, arg CountQuotaUsageParams) (CountQuotaUsageRow, error)
+	CountUnresolvedSessionConflicts(ctx context.Context, sessionID pgtype.UUID) (int64, error)
+	CreateAccountLinkCode(ctx context.Context, arg CreateAccountLinkCodeParams) (AccountLinkCode, error)
+	CreateAuditEvent(ctx context.Context, arg CreateAuditEventParams) (AuditLog, error)
+	// The first attempt is made right away by the sender, so the event is stored as already attempted
+	// and leased until next_attempt_at
+	CreateCallbackDelivery(ctx context.Context, arg CreateCallbackDeliveryParams) (CallbackOutbox, error)
+	CreateDocumentTheme(ctx context.Context, arg CreateDocumentThemeParams) (DocumentTheme, error)
 	CreateFilledSession(ctx context.Context, arg CreateFilledSessionParams) (Session, error)
+	CreateIncident(ctx context.Context, arg CreateIncidentParams) error
 	CreateIteration(ctx context.Context, arg CreateIterationParams) (SessionIteration, error)
 	CreateIterations(ctx context.Context, arg []CreateIterationsParams) (int64, error)
+	// A reused request ID restarts the operation
+	CreateOperation(ctx context.Context, arg CreateOperationParams) (Operation, error)
+	CreatePendingVoiceAnswer(ctx context.Context, arg CreatePendingVoiceAnswerParams) (PendingVoiceAnswer, error)
 	CreateProject(ctx context.Context, arg CreateProjectParams) (Project, error)
+	CreateProjectSchedule(ctx context.Context, arg CreateProjectScheduleParams) (ProjectSchedule, error)
 	CreateQuestion(ctx context.Context, arg CreateQuestionParams) (IterationQuestion, error)
 	CreateQuestions(ctx context.Context, arg []CreateQuestionsParams) (int64, error)
+	CreateRetentionPurge(ctx context.Context, arg CreateRetentionPurgeParams) (RetentionPurge, error)
 	CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error)
+	CreateSessionComment(ctx context.Context, arg CreateSessionCommentParams) (SessionComment, error)
+	CreateSessionConflict(ctx context.Context, arg CreateSessionConflictParams) (SessionConflict, error)
 	CreateSessionMessage(ctx context.Context, arg CreateSessionMessageParams) (SessionMessage, error)
-	DeleteProject(ctx context.Context, id pgtype.UUID) error
-	DeleteProjectFile(ctx context.Context, id pgtype.UUID) error
-	DeleteSession(ctx context.Context, id pgtype.UUID) error
+	CreateSessionResultVersion(ctx context.Context, arg CreateSessionResultVersionParams) (SessionResultVersion, error)
+	CreateTelegramInboxMessage(ctx context.Context, arg CreateTelegramInboxMessageParams) (TelegramInbox, error)
+	CreateTenant(ctx context.Context, arg CreateTenantParams) (Tenant, error)
+	// Creating an API user for a Telegram user who already talked to the bot gives that user the API key
+	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
+	DecrementQuotaCounter(ctx context.Context, arg DecrementQuotaCounterParams) error
+	DeferQuestion(ctx context.Context, id pgtype.UUID) error
+	// Related rows go with the session through ON DELETE CASCADE; demo sessions of all tenants expire
+	// once neither their creation nor their last heartbeat is newer than before
+	DeleteDemoSessionsBefore(ctx context.Context, before pgtype.Timestamp) (int64, error)
+	DeleteDocumentTheme(ctx context.Context, arg DeleteDocumentThemeParams) (int64, error)
+	DeleteFeatureFlagOverride(ctx context.Context, name string) (int64, error)
+	DeleteIncidentsBefore(ctx context.Context, createdAt pgtype.Timestamp) (int64, error)
+	DeleteIterationsBySession(ctx context.Context, sessionID pgtype.UUID) (int64, error)
+	DeleteOperationsBefore(ctx context.Context, updatedAt pgtype.Timestamp) (int64, error)
+	DeletePendingVoiceAnswer(ctx context.Context, id pgtype.UUID) error
+	DeletePendingVoiceAnswersBefore(ctx context.Context, before pgtype.Timestamp) (int64, error)
+	DeleteProject(ctx context.Context, arg DeleteProjectParams) (int64, error)
+	DeleteProjectFile(ctx context.Context, arg DeleteProjectFileParams) error
+	DeleteProjectSchedule(ctx context.Context, arg DeleteProjectScheduleParams) (int64, error)
+	DeleteQuotaUsage(ctx context.Context, arg DeleteQuotaUsageParams) (int64, error)
+	DeleteResultSections(ctx context.Context, sessionID pgtype.UUID) error
+	DeleteReviewApprovers(ctx context.Context, sessionID pgtype.UUID) error
+	DeleteSession(ctx context.Context, arg DeleteSessionParams) error
+	DeleteSessionConflicts(ctx context.Context, sessionID pgtype.UUID) error
 	DeleteSessionMessages(ctx context.Context, sessionID pgtype.UUID) error
-	DeleteTelegramSession(ctx context.Context, userID int64) error
+	DeleteSessionTranslations(ctx context.Context, sessionID pgtype.UUID) error
+	// Removes the content derived from the answers: translations, sections, the conversation log,
+	// facts, comments, delta baselines, conflicts and queued question deliveries
+	DeleteSessionsDerivedContent(ctx context.Context, sessionIds []pgtype.UUID) error
+	DeleteSettledCallbackDeliveriesBefore(ctx context.Context, before pgtype.Timestamp) (int64, error)
+	DeleteTelegramInboxMessage(ctx context.Context, arg DeleteTelegramInboxMessageParams) error
+	DeleteTelegramSession(ctx context.Context, arg DeleteTelegramSessionParams) error
+	DeleteUser(ctx context.Context, arg DeleteUserParams) (int64, error)
+	// The no-op update makes RETURNING yield the existing user
+	// Creates the counter of the period from the usage recorded so far, so a limit enabled
+	// within the period counts the earlier usage
+	EnsureQuotaCounter(ctx context.Context, arg EnsureQuotaCounterParams) error
+	EnsureTelegramUser(ctx context.Context, arg EnsureTelegramUserParams) (User, error)
+	FinishSessionOperation(ctx context.Context, arg FinishSessionOperationParams) error
+	GetAccountLinkSession(ctx context.Context, arg GetAccountLinkSessionParams) (GetAccountLinkSessionRow, error)
 	GetCurrentIteration(ctx context.Context, sessionID pgtype.UUID) (SessionIteration, error)
-	GetFiles(ctx context.Context, projectID pgtype.UUID) ([]ProjectFile, error)
+	GetDeferredQuestions(ctx context.Context, sessionID pgtype.UUID) ([]IterationQuestion, error)
+	GetDocumentTheme(ctx context.Context, arg GetDocumentThemeParams) (DocumentTheme, error)
+	GetFiles(ctx context.Context, arg GetFilesParams) ([]ProjectFile, error)
 	GetIterationByID(ctx context.Context, id pgtype.UUID) (SessionIteration, error)
+	GetLatestProjectResultSession(ctx context.Context, arg GetLatestProjectResultSessionParams) (Session, error)
+	GetLatestSessionResultVersion(ctx context.Context, sessionID pgtype.UUID) (SessionResultVersion, error)
 	GetNextIteration(ctx context.Context, sessionID pgtype.UUID) (SessionIteration, error)
-	GetProject(ctx context.Context, id pgtype.UUID) (Project, error)
+	GetOperation(ctx context.Context, requestID string) (Operation, error)
+	// The latest completed session of the project with a result started before the given time
+	GetPreviousProjectResultSession(ctx context.Context, arg GetPreviousProjectResultSessionParams) (Session, error)
+	// A user sees the projects they own and the projects nobody owns; a NULL owner_id sees the whole tenant
+	GetProject(ctx context.Context, arg GetProjectParams) (Project, error)
+	GetProjectFile(ctx context.Context, arg GetProjectFileParams) (ProjectFile, error)
+	// Files are saved once the RAG service indexed them, so their creation times tell how old the indexed materials are
+	GetProjectFreshness(ctx context.Context, arg GetProjectFreshnessParams) (GetProjectFreshnessRow, error)
+	// Resolves the tenant of background work that starts from a project, such as scheduled sessions
+	GetProjectTenant(ctx context.Context, id pgtype.UUID) (Tenant, error)
 	GetQuestionByID(ctx context.Context, id pgtype.UUID) (IterationQuestion, error)
-	GetSessionByID(ctx context.Context, id pgtype.UUID) (Session, error)
+	GetQuestionExplanation(ctx context.Context, questionID pgtype.UUID) (QuestionExplanation, error)
+	// A user sees the sessions they own and the sessions nobody owns; a NULL owner_id sees the whole tenant
+	GetSessionByID(ctx context.Context, arg GetSessionByIDParams) (Session, error)
+	GetSessionDelta(ctx context.Context, sessionID pgtype.UUID) (SessionDelta, error)
+	GetSessionFacts(ctx context.Context, sessionID pgtype.UUID) (SessionFact, error)
 	GetSessionMessages(ctx context.Context, sessionID pgtype.UUID) ([]SessionMessage, error)
-	GetTelegramSession(ctx context.Context, userID int64) (TelegramSession, error)
-	GetTelegramSessionBySessionID(ctx context.Context, sessionID pgtype.UUID) (TelegramSession, error)
-	GetTelegramSessionWithSession(ctx context.Context, userID int64) (GetTelegramSessionWithSessionRow, error)
+	GetSessionNormalizeTranscripts(ctx context.Context, arg GetSessionNormalizeTranscriptsParams) (bool, error)
+	GetSessionOperation(ctx context.Context, sessionID pgtype.UUID) (SessionOperation, error)
+	GetSessionResultVersion(ctx context.Context, arg GetSessionResultVersionParams) (SessionResultVersion, error)
+	GetSessionReview(ctx context.Context, sessionID pgtype.UUID) (SessionReview, error)
+	GetSessionTimeBudget(ctx context.Context, sessionID pgtype.UUID) (SessionTimeBudget, error)
+	GetSessionTranslation(ctx context.Context, arg GetSessionTranslationParams) (SessionTranslation, error)
+	GetTelegramInboxMessage(ctx context.Context, arg GetTelegramInboxMessageParams) (TelegramInbox, error)
+	GetTelegramSession(ctx context.Context, arg GetTelegramSessionParams) (TelegramSession, error)
+	GetTelegramSessionBySessionID(ctx context.Context, arg GetTelegramSessionBySessionIDParams) (TelegramSession, error)
+	GetTelegramSessionWithSession(ctx context.Context, arg GetTelegramSessionWithSessionParams) (GetTelegramSessionWithSessionRow, error)
+	GetTelegramUserNormalizeTranscripts(ctx context.Context, arg GetTelegramUserNormalizeTranscriptsParams) (bool, error)
+	GetTelegramUserProgressHeader(ctx context.Context, arg GetTelegramUserProgressHeaderParams) (pgtype.Bool, error)
+	GetTelegramUserQuestionNumbering(ctx context.Context, arg GetTelegramUserQuestionNumberingParams) (pgtype.Text, error)
+	GetTelegramUserSummaryStyle(ctx context.Context, arg GetTelegramUserSummaryStyleParams) (pgtype.Text, error)
+	GetTelegramUserTimezone(ctx context.Context, arg GetTelegramUserTimezoneParams) (pgtype.Text, error)
+	GetTenant(ctx context.Context, id string) (Tenant, error)
+	GetTenantByAPIKeyHash(ctx context.Context, apiKeyHash pgtype.Text) (Tenant, error)
+	GetTenantByBotTokenHash(ctx context.Context, botTokenHash pgtype.Text) (Tenant, error)
 	GetUnansweredQuestions(ctx context.Context, sessionID pgtype.UUID) ([]IterationQuestion, error)
+	GetUserByAPIKeyHash(ctx context.Context, apiKeyHash pgtype.Text) (User, error)
+	// Counts one usage unless the limit is reached, 0 disabling it; the row lock serializes
+	// concurrent reservations, which see the count of each other
+	IncrementQuotaCounter(ctx context.Context, arg IncrementQuotaCounterParams) (int64, error)
+	IsSessionGenerationApproved(ctx context.Context, sessionID pgtype.UUID) (bool, error)
+	// Only lengths and flags of the texts leave the database; demo sessions are not analyzed
+	ListAnalyticsAnswers(ctx context.Context, arg ListAnalyticsAnswersParams) ([]ListAnalyticsAnswersRow, error)
+	ListCallbackDeliveries(ctx context.Context, arg ListCallbackDeliveriesParams) ([]CallbackOutbox, error)
+	ListClientOperations(ctx context.Context, arg ListClientOperationsParams) ([]Operation, error)
+	ListDocumentThemes(ctx context.Context, tenantID string) ([]DocumentTheme, error)
+	ListDueProjectSchedules(ctx context.Context, nextRunAt pgtype.Timestamp) ([]ProjectSchedule, error)
+	ListFeatureFlagOverrides(ctx context.Context) ([]FeatureFlagOverride, error)
+	ListIncidentsByCode(ctx context.Context, arg ListIncidentsByCodeParams) ([]Incident, error)
 	ListIterationsBySession(ctx context.Context, sessionID pgtype.UUID) ([]SessionIteration, error)
-	ListProjects(ctx context.Context, arg ListProjectsParams) ([]Project, error)
+	// Pages through the telegram sessions of all tenants whose state data is older than the version;
+	// a version that is not a number counts as 0, so the caller sees the broken blob
+	ListOutdatedTelegramStateData(ctx context.Context, arg ListOutdatedTelegramStateDataParams) ([]ListOutdatedTelegramStateDataRow, error)
+	ListPendingQuestionDeliveries(ctx context.Context, sessionID pgtype.UUID) ([]PendingQuestionDelivery, error)
+	ListPinnedProjects(ctx context.Context, arg ListPinnedProjectsParams) ([]ListPinnedProjectsRow, error)
+	ListProjectSchedules(ctx context.Context, projectID pgtype.UUID) ([]ProjectSchedule, error)
+	ListProjects(ctx context.Context, arg ListProjectsParams) ([]ListProjectsRow, error)
 	ListQuestionsByIteration(ctx context.Context, iterationID pgtype.UUID) ([]IterationQuestion, error)
 	ListQuestionsBySession(ctx context.Context, sessionID pgtype.UUID) ([]IterationQuestion, error)
-	ResetSessionIteration(ctx context.Context, id pgtype.UUID) (Session, error)
+	ListQuotaUsageBySubject(ctx context.Context, arg ListQuotaUsageBySubjectParams) ([]ListQuotaUsageBySubjectRow, error)
+	// Returns the latest entries of a session in chronological order
+	ListRecentConversationEntries(ctx context.Context, arg ListRecentConversationEntriesParams) ([]SessionConversationLog, error)
+	ListResultSections(ctx context.Context, sessionID pgtype.UUID) ([]SessionResultSection, error)
+	ListRetentionPurges(ctx context.Context, arg ListRetentionPurgesParams) ([]RetentionPurge, error)
+	ListReviewApprovers(ctx context.Context, sessionID pgtype.UUID) ([]SessionReviewApprover, error)
+	ListSessionComments(ctx context.Context, sessionID pgtype.UUID) ([]SessionComment, error)
+	ListSessionConflicts(ctx context.Context, sessionID pgtype.UUID) ([]SessionConflict, error)
+	// Leaves the inline bodies out, it only reports whether a version still has one
+	ListSessionResultVersions(ctx context.Context, sessionID pgtype.UUID) ([]ListSessionResultVersionsRow, error)
+	ListTelegramUserProjectSchedules(ctx context.Context, arg ListTelegramUserProjectSchedulesParams) ([]ProjectSchedule, error)
+	ListTenants(ctx context.Context) ([]Tenant, error)
+	// Pages through plain project contexts above the size threshold by id, so rows that do not
+	// shrink and stay plain are not returned again. Maintenance runs across all tenants
+	ListUncompressedSessionContexts(ctx context.Context, arg ListUncompressedSessionContextsParams) ([]ListUncompressedSessionContextsRow, error)
+	// Pages through plain messages above the size threshold by id, so rows that do not shrink
+	// and stay plain are not returned again
+	ListUncompressedSessionMessages(ctx context.Context, arg ListUncompressedSessionMessagesParams) ([]ListUncompressedSessionMessagesRow, error)
+	ListUnresolvedSessionComments(ctx context.Context, sessionID pgtype.UUID) ([]SessionComment, error)
+	ListUsers(ctx context.Context, tenantID string) ([]User, error)
+	// Session-level advisory lock, held by the connection until UnlockSession
+	LockSession(ctx context.Context, dollar_1 string) error
+	MarkCallbackDelivered(ctx context.Context, id pgtype.UUID) error
+	MarkCallbackFailed(ctx context.Context, arg MarkCallbackFailedParams) error
+	MarkSessionTimeBudgetWarned(ctx context.Context, sessionID pgtype.UUID) (int64, error)
+	// Affects a row only the first time, so concurrent /start commands show the tutorial once
+	MarkTelegramUserOnboarded(ctx context.Context, arg MarkTelegramUserOnboardedParams) (int64, error)
+	// Nothing is inserted once the user has max_pins pinned projects
+	PinProject(ctx context.Context, arg PinProjectParams) (int64, error)
+	PurgeSessionMessages(ctx context.Context, sessionIds []pgtype.UUID) (int64, error)
+	// Keeps the questions with their status, type and skip reason, so answer rates stay countable
+	PurgeSessionQuestions(ctx context.Context, sessionIds []pgtype.UUID) (int64, error)
+	// Keeps size and checksum of the versions and returns the blob keys the caller removes
+	PurgeSessionResultVersions(ctx context.Context, sessionIds []pgtype.UUID) ([]PurgeSessionResultVersionsRow, error)
+	// Clears the goal, context and result of the sessions of a tenant without activity since before.
+	// The rows stay as stubs keeping status, type and timestamps for the metrics; sessions still in
+	// progress are canceled, since their content is gone
+	PurgeSessionsContent(ctx context.Context, arg PurgeSessionsContentParams) ([]pgtype.UUID, error)
+	RecordGenerationFailure(ctx context.Context, arg RecordGenerationFailureParams) (int32, error)
+	RecordQuotaUsage(ctx context.Context, arg RecordQuotaUsageParams) (pgtype.UUID, error)
+	// A code is redeemed once and only before it expires
+	RedeemAccountLinkCode(ctx context.Context, arg RedeemAccountLinkCodeParams) (AccountLinkCode, error)
+	// Points the session at its first open question: the unanswered ones in interview order,
+	// then the deferred ones, which are asked last
+	RefreshSessionCurrentQuestion(ctx context.Context, arg RefreshSessionCurrentQuestionParams) (Session, error)
+	RenewSessionOperation(ctx context.Context, arg RenewSessionOperationParams) (int64, error)
+	// Affects no row when the state data changed since it was read, e.g. by the bot meanwhile;
+	// updated_at is kept, so a backfill does not extend the lifetime of idle sessions
+	ReplaceTelegramStateData(ctx context.Context, arg ReplaceTelegramStateDataParams) (int64, error)
+	ResetGenerationFailures(ctx context.Context, sessionID pgtype.UUID) error
+	ResetSessionIteration(ctx context.Context, arg ResetSessionIterationParams) (Session, error)
+	ResolveSessionComment(ctx context.Context, arg ResolveSessionCommentParams) (SessionComment, error)
+	ResolveSessionComments(ctx context.Context, arg ResolveSessionCommentsParams) error
+	ResolveSessionConflict(ctx context.Context, arg ResolveSessionConflictParams) (SessionConflict, error)
+	// Explanations generated concurrently for the same question keep the latest one
+	SaveQuestionExplanation(ctx context.Context, arg SaveQuestionExplanationParams) (QuestionExplanation, error)
+	ScheduleCallbackRetry(ctx context.Context, arg ScheduleCallbackRetryParams) error
+	// Full-text search across project descriptions, file names, session goals and results for
+	// support staff; backed by the GIN indexes from migration 017
+	SearchAll(ctx context.Context, arg SearchAllParams) ([]SearchAllRow, error)
+	// Full-text search over answers and draft messages of one session; the session filter keeps the
+	// scanned set small, so no full-text indexes are maintained. Compressed draft messages have an
+	// empty message_text and are not searched
+	SearchSessionContent(ctx context.Context, arg SearchSessionContentParams) ([]SearchSessionContentRow, error)
+	SetProjectDescription(ctx context.Context, arg SetProjectDescriptionParams) (int64, error)
+	SetProjectScheduleLastSession(ctx context.Context, arg SetProjectScheduleLastSessionParams) error
+	SetProjectScheduleTimezone(ctx context.Context, arg SetProjectScheduleTimezoneParams) error
+	SetProjectTheme(ctx context.Context, arg SetProjectThemeParams) (int64, error)
+	SetQuestionSkipReason(ctx context.Context, arg SetQuestionSkipReasonParams) (int64, error)
+	SetSessionMessageCompressed(ctx context.Context, arg SetSessionMessageCompressedParams) error
+	// Leaves updated_at untouched: compression does not change the session
+	SetSessionProjectContextCompressed(ctx context.Context, arg SetSessionProjectContextCompressedParams) error
+	SetTelegramUserNormalizeTranscripts(ctx context.Context, arg SetTelegramUserNormalizeTranscriptsParams) error
+	SetTelegramUserProgressHeader(ctx context.Context, arg SetTelegramUserProgressHeaderParams) error
+	SetTelegramUserQuestionNumbering(ctx context.Context, arg SetTelegramUserQuestionNumberingParams) error
+	SetTelegramUserSummaryStyle(ctx context.Context, arg SetTelegramUserSummaryStyleParams) error
+	SetTelegramUserTimezone(ctx context.Context, arg SetTelegramUserTimezoneParams) error
 	SkipQustion(ctx context.Context, id pgtype.UUID) error
-	UpdateQuestionAnswer(ctx context.Context, arg UpdateQuestionAnswerParams) error
-	UpdateSessionIteration(ctx context.Context, id pgtype.UUID) (Session, error)
+	SkipUnansweredSessionQuestions(ctx context.Context, sessionID pgtype.UUID) (int64, error)
+	// Starts the cooldown of the subject unless it is still running; no row means it is
+	StartExplanationCooldown(ctx context.Context, arg StartExplanationCooldownParams) (pgtype.Timestamp, error)
+	// Registers the operation unless another one runs on the session; an expired row is taken over
+	// and no row means the session is busy
+	StartSessionOperation(ctx context.Context, arg StartSessionOperationParams) (SessionOperation, error)
+	// A repeated start keeps the original start time
+	StartSessionTimeBudget(ctx context.Context, arg StartSessionTimeBudgetParams) (SessionTimeBudget, error)
+	TouchSessionActivity(ctx context.Context, arg TouchSessionActivityParams) (Session, error)
+	TouchTelegramProjectUsage(ctx context.Context, arg TouchTelegramProjectUsageParams) error
+	// Changes the status only while the session is still in the expected one,
+	// so a concurrent or repeated transition affects no row
+	TransitionSessionStatus(ctx context.Context, arg TransitionSessionStatusParams) (Session, error)
+	UnlockSession(ctx context.Context, dollar_1 string) error
+	UnpinProject(ctx context.Context, arg UnpinProjectParams) (int64, error)
+	UpdateOperationStatus(ctx context.Context, arg UpdateOperationStatusParams) error
+	UpdateQuestionAnswer(ctx context.Context, arg UpdateQuestionAnswerParams) (int64, error)
+	UpdateSessionDeltaChangeLog(ctx context.Context, arg UpdateSessionDeltaChangeLogParams) (SessionDelta, error)
+	UpdateSessionIteration(ctx context.Context, arg UpdateSessionIterationParams) (Session, error)
+	// A detected language ($3 false) never replaces a language the user has set explicitly
+	UpdateSessionLanguage(ctx context.Context, arg UpdateSessionLanguageParams) (Session, error)
 	UpdateSessionProjectContext(ctx context.Context, arg UpdateSessionProjectContextParams) (Session, error)
 	UpdateSessionRAGProjectContext(ctx context.Context, arg UpdateSessionRAGProjectContextParams) (Session, error)
 	UpdateSessionResult(ctx context.Context, arg UpdateSessionResultParams) (Session, error)
 	UpdateSessionStatus(ctx context.Context, arg UpdateSessionStatusParams) (Session, error)
+	UpdateSessionSummaryStyle(ctx context.Context, arg UpdateSessionSummaryStyleParams) (Session, error)
 	UpdateSessionType(ctx context.Context, arg UpdateSessionTypeParams) (Session, error)
 	UpdateSessionUserGoal(ctx context.Context, arg UpdateSessionUserGoalParams) (Session, error)
+	UpdateTenantSettings(ctx context.Context, arg UpdateTenantSettingsParams) (Tenant, error)
+	UpsertAccountLink(ctx context.Context, arg UpsertAccountLinkParams) (AccountLink, error)
+	UpsertFeatureFlagOverride(ctx context.Context, arg UpsertFeatureFlagOverrideParams) (FeatureFlagOverride, error)
+	// A block that fails to be delivered again replaces the earlier payload and is pending again
+	UpsertPendingQuestionDelivery(ctx context.Context, arg UpsertPendingQuestionDeliveryParams) (PendingQuestionDelivery, error)
+	UpsertResultSection(ctx context.Context, arg UpsertResultSectionParams) (SessionResultSection, error)
+	UpsertSessionDelta(ctx context.Context, arg UpsertSessionDeltaParams) (SessionDelta, error)
+	UpsertSessionFacts(ctx context.Context, arg UpsertSessionFactsParams) (SessionFact, error)
+	UpsertSessionReview(ctx context.Context, arg UpsertSessionReviewParams) (SessionReview, error)
+	UpsertSessionTranslation(ctx context.Context, arg UpsertSessionTranslationParams) (SessionTranslation, error)
 	UpsertTelegramSession(ctx context.Context, arg UpsertTelegramSessionParams) error
 }
 
@@ -18,20 +18,26 @@ INSERT INTO iteration_questions (
     question_number,
     status,
     question,
-    explanation
+    explanation,
+    options,
+    parent_question_id,
+    answer_type
 ) VALUES (
-    $1, $2, $3, $4, $5, $6
+    $1, $2, $3, $4, $5, $6, $7, $8, $9
 )
-RETURNING id, iteration_id, question_number, status, question, explanation, answer, created_at, answered_at
+RETURNING id, iteration_id, question_number, status, question, explanation, answer, created_at, answered_at, raw_answer, skip_reason, options, parent_question_id, answer_type
 `
 
 type CreateQuestionParams struct {
-	ID             pgtype.UUID `json:"id"`
-	IterationID    pgtype.UUID `json:"iteration_id"`
-	QuestionNumber int32       `json:"question_number"`
-	Status         string      `json:"status"`
-	Question       string      `json:"question"`
-	Explanation    string      `json:"explanation"`
+	ID               pgtype.UUID `json:"id"`
+	IterationID      pgtype.UUID `json:"iteration_id"`
+	QuestionNumber   int32       `json:"question_number"`
+	Status           string      `json:"status"`
+	Question         string      `json:"question"`
+	Explanation      string      `json:"explanation"`
+	Options          []string    `json:"options"`
+	ParentQuestionID pgtype.UUID `json:"parent_question_id"`
+	AnswerType       string      `json:"answer_type"`
 }
 
 func (q *Queries) CreateQuestion(ctx context.Context, arg CreateQuestionParams) (IterationQuestion, error) {
@@ -42,6 +48,9 @@ func (q *Queries) CreateQuestion(ctx context.Context, arg CreateQuestionParams)
 		arg.Status,
 		arg.Question,
 		arg.Explanation,
+		arg.Options,
+		arg.ParentQuestionID,
+		arg.AnswerType,
 	)
 	var i IterationQuestion
 	err := row.Scan(
@@ -54,21 +63,83 @@ func (q *Queries) CreateQuestion(ctx context.Context, arg CreateQuestionParams)
 		&i.Answer,
 		&i.CreatedAt,
 		&i.AnsweredAt,
+		&i.RawAnswer,
+		&i.SkipReason,
+		&i.Options,
+		&i.ParentQuestionID,
+		&i.AnswerType,
 	)
 	return i, err
 }
 
 type CreateQuestionsParams struct {
-	ID             pgtype.UUID `json:"id"`
-	IterationID    pgtype.UUID `json:"iteration_id"`
-	QuestionNumber int32       `json:"question_number"`
-	Status         string      `json:"status"`
-	Question       string      `json:"question"`
-	Explanation    string      `json:"explanation"`
+	ID               pgtype.UUID `json:"id"`
+	IterationID      pgtype.UUID `json:"iteration_id"`
+	QuestionNumber   int32       `json:"question_number"`
+	Status           string      `json:"status"`
+	Question         string      `json:"question"`
+	Explanation      string      `json:"explanation"`
+	Options          []string    `json:"options"`
+	ParentQuestionID pgtype.UUID `json:"parent_question_id"`
+	AnswerType       string      `json:"answer_type"`
+}
+
+const deferQuestion = `-- name: DeferQuestion :exec
+UPDATE iteration_questions
+SET status = 'DEFERRED'
+WHERE id = $1 AND status = 'UNANSWERED'
+`
+
+func (q *Queries) DeferQuestion(ctx context.Context, id pgtype.UUID) error {
+	_, err := q.db.Exec(ctx, deferQuestion, id)
+	return err
+}
+
+const getDeferredQuestions = `-- name: GetDeferredQuestions :many
+SELECT iq.id, iq.iteration_id, iq.question_number, iq.status, iq.question, iq.explanation, iq.answer, iq.created_at, iq.answered_at, iq.raw_answer, iq.skip_reason, iq.options, iq.parent_question_id, iq.answer_type FROM iteration_questions iq
+JOIN session_iterations si ON si.id = iq.iteration_id
+WHERE si.session_id = $1
+  AND iq.status = 'DEFERRED'
+ORDER BY si.iteration_number ASC, iq.question_number ASC
+`
+
+func (q *Queries) GetDeferredQuestions(ctx context.Context, sessionID pgtype.UUID) ([]IterationQuestion, error) {
+	rows, err := q.db.Query(ctx, getDeferredQuestions, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []IterationQuestion{}
+	for rows.Next() {
+		var i IterationQuestion
+		if err := rows.Scan(
+			&i.ID,
+			&i.IterationID,
+			&i.QuestionNumber,
+			&i.Status,
+			&i.Question,
+			&i.Explanation,
+			&i.Answer,
+			&i.CreatedAt,
+			&i.AnsweredAt,
+			&i.RawAnswer,
+			&i.SkipReason,
+			&i.Options,
+			&i.ParentQuestionID,
+			&i.AnswerType,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
 const getQuestionByID = `-- name: GetQuestionByID :one
-SELECT id, iteration_id, question_number, status, question, explanation, answer, created_at, answered_at FROM iteration_questions
+SELECT id, iteration_id, question_number, status, question, explanation, answer, created_at, answered_at, raw_answer, skip_reason, options, parent_question_id, answer_type FROM iteration_questions
 WHERE id = $1
 `
 
@@ -85,15 +156,20 @@ func (q *Queries) GetQuestionByID(ctx context.Context, id pgtype.UUID) (Iteratio
 		&i.Answer,
 		&i.CreatedAt,
 		&i.AnsweredAt,
+		&i.RawAnswer,
+		&i.SkipReason,
+		&i.Options,
+		&i.ParentQuestionID,
+		&i.AnswerType,
 	)
 	return i, err
 }
 
 const getUnansweredQuestions = `-- name: GetUnansweredQuestions :many
-SELECT iq.id, iq.iteration_id, iq.question_number, iq.status, iq.question, iq.explanation, iq.answer, iq.created_at, iq.answered_at FROM iteration_questions iq
+SELECT iq.id, iq.iteration_id, iq.question_number, iq.status, iq.question, iq.explanation, iq.answer, iq.created_at, iq.answered_at, iq.raw_answer, iq.skip_reason, iq.options, iq.parent_question_id, iq.answer_type FROM iteration_questions iq
 JOIN session_iterations si ON si.id = iq.iteration_id
 WHERE si.session_id = $1
-  AND (iq.status = 'UNANSWERED' OR iq.status = 'SKIPED')
+  AND iq.status IN ('UNANSWERED', 'SKIPED', 'DEFERRED')
 ORDER BY si.iteration_number ASC, iq.question_number ASC
 `
 
@@ -116,6 +192,11 @@ func (q *Queries) GetUnansweredQuestions(ctx context.Context, sessionID pgtype.U
 			&i.Answer,
 			&i.CreatedAt,
 			&i.AnsweredAt,
+			&i.RawAnswer,
+			&i.SkipReason,
+			&i.Options,
+			&i.ParentQuestionID,
+			&i.AnswerType,
 		); err != nil {
 			return nil, err
 		}
@@ -128,7 +209,7 @@ func (q *Queries) GetUnansweredQuestions(ctx context.Context, sessionID pgtype.U
 }
 
 const listQuestionsByIteration = `-- name: ListQuestionsByIteration :many
-SELECT id, iteration_id, question_number, status, question, explanation, answer, created_at, answered_at FROM iteration_questions
+SELECT id, iteration_id, question_number, status, question, explanation, answer, created_at, answered_at, raw_answer, skip_reason, options, parent_question_id, answer_type FROM iteration_questions
 WHERE iteration_id = $1
 ORDER BY question_number ASC
 `
@@ -152,6 +233,11 @@ func (q *Queries) ListQuestionsByIteration(ctx context.Context, iterationID pgty
 			&i.Answer,
 			&i.CreatedAt,
 			&i.AnsweredAt,
+			&i.RawAnswer,
+			&i.SkipReason,
+			&i.Options,
+			&i.ParentQuestionID,
+			&i.AnswerType,
 		); err != nil {
 			return nil, err
 		}
@@ -164,7 +250,7 @@ func (q *Queries) ListQuestionsByIteration(ctx context.Context, iterationID pgty
 }
 
 const listQuestionsBySession = `-- name: ListQuestionsBySession :many
-SELECT iq.id, iq.iteration_id, iq.question_number, iq.status, iq.question, iq.explanation, iq.answer, iq.created_at, iq.answered_at FROM iteration_questions iq
+SELECT iq.id, iq.iteration_id, iq.question_number, iq.status, iq.question, iq.explanation, iq.answer, iq.created_at, iq.answered_at, iq.raw_answer, iq.skip_reason, iq.options, iq.parent_question_id, iq.answer_type FROM iteration_questions iq
 JOIN session_iterations si ON si.id = iq.iteration_id
 WHERE si.session_id = $1
 ORDER BY si.iteration_number ASC, iq.question_number ASC
@@ -189,6 +275,11 @@ func (q *Queries) ListQuestionsBySession(ctx context.Context, sessionID pgtype.U
 			&i.Answer,
 			&i.CreatedAt,
 			&i.AnsweredAt,
+			&i.RawAnswer,
+			&i.SkipReason,
+			&i.Options,
+			&i.ParentQuestionID,
+			&i.AnswerType,
 		); err != nil {
 			return nil, err
 		}
@@ -200,10 +291,31 @@ func (q *Queries) ListQuestionsBySession(ctx context.Context, sessionID pgtype.U
 	return items, nil
 }
 
+const setQuestionSkipReason = `-- name: SetQuestionSkipReason :execrows
+UPDATE iteration_questions
+SET skip_reason = $2
+WHERE id = $1 AND status = 'SKIPED'
+  AND iteration_id IN (SELECT id FROM session_iterations WHERE session_id = $3)
+`
+
+type SetQuestionSkipReasonParams struct {
+	ID         pgtype.UUID `json:"id"`
+	SkipReason pgtype.Text `json:"skip_reason"`
+	SessionID  pgtype.UUID `json:"session_id"`
+}
+
+func (q *Queries) SetQuestionSkipReason(ctx context.Context, arg SetQuestionSkipReasonParams) (int64, error) {
+	result, err := q.db.Exec(ctx, setQuestionSkipReason, arg.ID, arg.SkipReason, arg.SessionID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const skipQustion = `-- name: SkipQustion :exec
 UPDATE iteration_questions
 SET status = 'SKIPED'
-WHERE id = $1 AND status = 'UNANSWERED'
+WHERE id = $1 AND status IN ('UNANSWERED', 'DEFERRED')
 `
 
 func (q *Queries) SkipQustion(ctx context.Context, id pgtype.UUID) error {
@@ -211,20 +323,73 @@ func (q *Queries) SkipQustion(ctx context.Context, id pgtype.UUID) error {
 	return err
 }
 
-const updateQuestionAnswer = `-- name: UpdateQuestionAnswer :exec
+const skipUnansweredSessionQuestions = `-- name: SkipUnansweredSessionQuestions :execrows
+UPDATE iteration_questions
+SET status = 'SKIPED'
+WHERE status IN ('UNANSWERED', 'DEFERRED')
+  AND iteration_id IN (SELECT id FROM session_iterations WHERE session_id = $1)
+`
+
+func (q *Queries) SkipUnansweredSessionQuestions(ctx context.Context, sessionID pgtype.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, skipUnansweredSessionQuestions, sessionID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const updateQuestionAnswer = `-- name: UpdateQuestionAnswer :execrows
 UPDATE iteration_questions
 SET answer = $2,
+    raw_answer = $3,
     status = 'ANSWERED',
+    skip_reason = NULL,
     answered_at = NOW()
 WHERE id = $1
+  AND iteration_id IN (SELECT id FROM session_iterations WHERE session_id = $4)
 `
 
 type UpdateQuestionAnswerParams struct {
-	ID     pgtype.UUID `json:"id"`
-	Answer pgtype.Text `json:"answer"`
+	ID        pgtype.UUID `json:"id"`
+	Answer    pgtype.Text `json:"answer"`
+	RawAnswer pgtype.Text `json:"raw_answer"`
+	SessionID pgtype.UUID `json:"session_id"`
 }
 
-func (q *Queries) UpdateQuestionAnswer(ctx context.Context, arg UpdateQuestionAnswerParams) error {
-	_, err := q.db.Exec(ctx, updateQuestionAnswer, arg.ID, arg.Answer)
-	return err
+func (q *Queries) UpdateQuestionAnswer(ctx context.Context, arg UpdateQuestionAnswerParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updateQuestionAnswer,
+		arg.ID,
+		arg.Answer,
+		arg.RawAnswer,
+		arg.SessionID,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const answerOpenQuestion = `-- name: AnswerOpenQuestion :execrows
+UPDATE iteration_questions
+SET answer = $2,
+    raw_answer = $3,
+    status = 'ANSWERED',
+    skip_reason = NULL,
+    answered_at = NOW()
+WHERE id = $1 AND status <> 'ANSWERED'
+`
+
+type AnswerOpenQuestionParams struct {
+	ID        pgtype.UUID `json:"id"`
+	Answer    pgtype.Text `json:"answer"`
+	RawAnswer pgtype.Text `json:"raw_answer"`
+}
+
+// Saves an answer unless the question has been answered meanwhile
+func (q *Queries) AnswerOpenQuestion(ctx context.Context, arg AnswerOpenQuestionParams) (int64, error) {
+	result, err := q.db.Exec(ctx, answerOpenQuestion, arg.ID, arg.Answer, arg.RawAnswer)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
 }
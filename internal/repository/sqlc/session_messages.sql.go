@@ -12,24 +12,33 @@ import (
 )
 
 const createSessionMessage = `-- name: CreateSessionMessage :one
-INSERT INTO session_messages (session_id, message_text, created_at)
-VALUES ($1, $2, NOW())
-RETURNING id, session_id, message_text, created_at
+INSERT INTO session_messages (session_id, message_text, message_text_compressed, raw_message_text, created_at)
+VALUES ($1, $2, $3, $4, NOW())
+RETURNING id, session_id, message_text, created_at, message_text_compressed, raw_message_text
 `
 
 type CreateSessionMessageParams struct {
-	SessionID   pgtype.UUID `json:"session_id"`
-	MessageText string      `json:"message_text"`
+	SessionID             pgtype.UUID `json:"session_id"`
+	MessageText           string      `json:"message_text"`
+	MessageTextCompressed []byte      `json:"message_text_compressed"`
+	RawMessageText        pgtype.Text `json:"raw_message_text"`
 }
 
 func (q *Queries) CreateSessionMessage(ctx context.Context, arg CreateSessionMessageParams) (SessionMessage, error) {
-	row := q.db.QueryRow(ctx, createSessionMessage, arg.SessionID, arg.MessageText)
+	row := q.db.QueryRow(ctx, createSessionMessage,
+		arg.SessionID,
+		arg.MessageText,
+		arg.MessageTextCompressed,
+		arg.RawMessageText,
+	)
 	var i SessionMessage
 	err := row.Scan(
 		&i.ID,
 		&i.SessionID,
 		&i.MessageText,
 		&i.CreatedAt,
+		&i.MessageTextCompressed,
+		&i.RawMessageText,
 	)
 	return i, err
 }
@@ -45,7 +54,7 @@ func (q *Queries) DeleteSessionMessages(ctx context.Context, sessionID pgtype.UU
 }
 
 const getSessionMessages = `-- name: GetSessionMessages :many
-SELECT id, session_id, message_text, created_at
+SELECT id, session_id, message_text, created_at, message_text_compressed, raw_message_text
 FROM session_messages
 WHERE session_id = $1
 ORDER BY created_at ASC
@@ -65,6 +74,8 @@ func (q *Queries) GetSessionMessages(ctx context.Context, sessionID pgtype.UUID)
 			&i.SessionID,
 			&i.MessageText,
 			&i.CreatedAt,
+			&i.MessageTextCompressed,
+			&i.RawMessageText,
 		); err != nil {
 			return nil, err
 		}
@@ -75,3 +86,138 @@ func (q *Queries) GetSessionMessages(ctx context.Context, sessionID pgtype.UUID)
 	}
 	return items, nil
 }
+
+const listUncompressedSessionMessages = `-- name: ListUncompressedSessionMessages :many
+SELECT id, message_text
+FROM session_messages
+WHERE message_text_compressed IS NULL
+  AND octet_length(message_text) > $1::int
+  AND id > $2
+ORDER BY id
+LIMIT $3
+`
+
+type ListUncompressedSessionMessagesParams struct {
+	MinSize   int32       `json:"min_size"`
+	AfterID   pgtype.UUID `json:"after_id"`
+	BatchSize int32       `json:"batch_size"`
+}
+
+type ListUncompressedSessionMessagesRow struct {
+	ID          pgtype.UUID `json:"id"`
+	MessageText string      `json:"message_text"`
+}
+
+// Pages through plain messages above the size threshold by id, so rows that do not shrink
+// and stay plain are not returned again
+func (q *Queries) ListUncompressedSessionMessages(ctx context.Context, arg ListUncompressedSessionMessagesParams) ([]ListUncompressedSessionMessagesRow, error) {
+	rows, err := q.db.Query(ctx, listUncompressedSessionMessages, arg.MinSize, arg.AfterID, arg.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListUncompressedSessionMessagesRow{}
+	for rows.Next() {
+		var i ListUncompressedSessionMessagesRow
+		if err := rows.Scan(&i.ID, &i.MessageText); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchSessionContent = `-- name: SearchSessionContent :many
+SELECT
+    'answer'::text AS source,
+    q.id,
+    q.question AS question,
+    ts_headline('russian', q.answer, websearch_to_tsquery('russian', $1::text),
+        'StartSel=«, StopSel=», MaxFragments=2, FragmentDelimiter=" … "')::text AS snippet,
+    ts_rank(to_tsvector('russian', q.answer), websearch_to_tsquery('russian', $1::text))::real AS rank,
+    COALESCE(q.answered_at, q.created_at)::timestamp AS created_at
+FROM iteration_questions q
+JOIN session_iterations i ON i.id = q.iteration_id
+WHERE i.session_id = $2
+  AND q.answer IS NOT NULL
+  AND to_tsvector('russian', q.answer) @@ websearch_to_tsquery('russian', $1::text)
+UNION ALL
+SELECT
+    'draft_message'::text AS source,
+    m.id,
+    ''::text AS question,
+    ts_headline('russian', m.message_text, websearch_to_tsquery('russian', $1::text),
+        'StartSel=«, StopSel=», MaxFragments=2, FragmentDelimiter=" … "')::text AS snippet,
+    ts_rank(to_tsvector('russian', m.message_text), websearch_to_tsquery('russian', $1::text))::real AS rank,
+    m.created_at
+FROM session_messages m
+WHERE m.session_id = $2
+  AND to_tsvector('russian', m.message_text) @@ websearch_to_tsquery('russian', $1::text)
+ORDER BY rank DESC, created_at ASC
+LIMIT $3
+`
+
+type SearchSessionContentParams struct {
+	Query      string      `json:"query"`
+	SessionID  pgtype.UUID `json:"session_id"`
+	MaxResults int32       `json:"max_results"`
+}
+
+type SearchSessionContentRow struct {
+	Source    string           `json:"source"`
+	ID        pgtype.UUID      `json:"id"`
+	Question  string           `json:"question"`
+	Snippet   string           `json:"snippet"`
+	Rank      float32          `json:"rank"`
+	CreatedAt pgtype.Timestamp `json:"created_at"`
+}
+
+// Full-text search over answers and draft messages of one session; the session filter keeps the
+// scanned set small, so no full-text indexes are maintained. Compressed draft messages have an
+// empty message_text and are not searched
+func (q *Queries) SearchSessionContent(ctx context.Context, arg SearchSessionContentParams) ([]SearchSessionContentRow, error) {
+	rows, err := q.db.Query(ctx, searchSessionContent, arg.Query, arg.SessionID, arg.MaxResults)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []SearchSessionContentRow{}
+	for rows.Next() {
+		var i SearchSessionContentRow
+		if err := rows.Scan(
+			&i.Source,
+			&i.ID,
+			&i.Question,
+			&i.Snippet,
+			&i.Rank,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setSessionMessageCompressed = `-- name: SetSessionMessageCompressed :exec
+UPDATE session_messages
+SET message_text = '',
+    message_text_compressed = $2
+WHERE id = $1
+`
+
+type SetSessionMessageCompressedParams struct {
+	ID                    pgtype.UUID `json:"id"`
+	MessageTextCompressed []byte      `json:"message_text_compressed"`
+}
+
+func (q *Queries) SetSessionMessageCompressed(ctx context.Context, arg SetSessionMessageCompressedParams) error {
+	_, err := q.db.Exec(ctx, setSessionMessageCompressed, arg.ID, arg.MessageTextCompressed)
+	return err
+}
@@ -15,12 +15,19 @@ const aquireSessionByID = `-- name: AquireSessionByID :one
 UPDATE sessions
 SET status = 'Processing', 
     updated_at = NOW()
-WHERE id = $1 AND status = 'WaitingForAnswers'
-RETURNING id, project_id, status, type, user_goal, project_context, current_iteration, result, error, created_at, updated_at
+WHERE id = $1 AND tenant_id = $2 AND status = 'WaitingForAnswers'
+  AND ($3::UUID IS NULL OR owner_id IS NULL OR owner_id = $3)
+RETURNING id, project_id, status, type, user_goal, project_context, current_iteration, result, error, created_at, updated_at, project_context_compressed, is_demo, tenant_id, last_activity_at, callback_granularity, current_question_id, owner_id, language, language_explicit, summary_style, purged_at
 `
 
-func (q *Queries) AquireSessionByID(ctx context.Context, id pgtype.UUID) (Session, error) {
-	row := q.db.QueryRow(ctx, aquireSessionByID, id)
+type AquireSessionByIDParams struct {
+	ID       pgtype.UUID `json:"id"`
+	TenantID string      `json:"tenant_id"`
+	OwnerID  pgtype.UUID `json:"owner_id"`
+}
+
+func (q *Queries) AquireSessionByID(ctx context.Context, arg AquireSessionByIDParams) (Session, error) {
+	row := q.db.QueryRow(ctx, aquireSessionByID, arg.ID, arg.TenantID, arg.OwnerID)
 	var i Session
 	err := row.Scan(
 		&i.ID,
@@ -34,6 +41,17 @@ func (q *Queries) AquireSessionByID(ctx context.Context, id pgtype.UUID) (Sessio
 		&i.Error,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.ProjectContextCompressed,
+		&i.IsDemo,
+		&i.TenantID,
+		&i.LastActivityAt,
+		&i.CallbackGranularity,
+		&i.CurrentQuestionID,
+		&i.OwnerID,
+		&i.Language,
+		&i.LanguageExplicit,
+		&i.SummaryStyle,
+		&i.PurgedAt,
 	)
 	return i, err
 }
@@ -45,19 +63,29 @@ INSERT INTO sessions (
     status,
     type,
     user_goal,
-    project_context
+    project_context,
+    project_context_compressed,
+    is_demo,
+    tenant_id,
+    callback_granularity,
+    owner_id
 ) VALUES (
-    $1, $2, $3, $4, $5, $6
-) RETURNING id, project_id, status, type, user_goal, project_context, current_iteration, result, error, created_at, updated_at
+    $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+) RETURNING id, project_id, status, type, user_goal, project_context, current_iteration, result, error, created_at, updated_at, project_context_compressed, is_demo, tenant_id, last_activity_at, callback_granularity, current_question_id, owner_id, language, language_explicit, summary_style, purged_at
 `
 
 type CreateFilledSessionParams struct {
-	ID             pgtype.UUID `json:"id"`
-	ProjectID      pgtype.UUID `json:"project_id"`
-	Status         string      `json:"status"`
-	Type           pgtype.Text `json:"type"`
-	UserGoal       pgtype.Text `json:"user_goal"`
-	ProjectContext pgtype.Text `json:"project_context"`
+	ID                       pgtype.UUID `json:"id"`
+	ProjectID                pgtype.UUID `json:"project_id"`
+	Status                   string      `json:"status"`
+	Type                     pgtype.Text `json:"type"`
+	UserGoal                 pgtype.Text `json:"user_goal"`
+	ProjectContext           pgtype.Text `json:"project_context"`
+	ProjectContextCompressed []byte      `json:"project_context_compressed"`
+	IsDemo                   bool        `json:"is_demo"`
+	TenantID                 string      `json:"tenant_id"`
+	CallbackGranularity      string      `json:"callback_granularity"`
+	OwnerID                  pgtype.UUID `json:"owner_id"`
 }
 
 func (q *Queries) CreateFilledSession(ctx context.Context, arg CreateFilledSessionParams) (Session, error) {
@@ -68,6 +96,11 @@ func (q *Queries) CreateFilledSession(ctx context.Context, arg CreateFilledSessi
 		arg.Type,
 		arg.UserGoal,
 		arg.ProjectContext,
+		arg.ProjectContextCompressed,
+		arg.IsDemo,
+		arg.TenantID,
+		arg.CallbackGranularity,
+		arg.OwnerID,
 	)
 	var i Session
 	err := row.Scan(
@@ -82,6 +115,17 @@ func (q *Queries) CreateFilledSession(ctx context.Context, arg CreateFilledSessi
 		&i.Error,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.ProjectContextCompressed,
+		&i.IsDemo,
+		&i.TenantID,
+		&i.LastActivityAt,
+		&i.CallbackGranularity,
+		&i.CurrentQuestionID,
+		&i.OwnerID,
+		&i.Language,
+		&i.LanguageExplicit,
+		&i.SummaryStyle,
+		&i.PurgedAt,
 	)
 	return i, err
 }
@@ -89,19 +133,31 @@ func (q *Queries) CreateFilledSession(ctx context.Context, arg CreateFilledSessi
 const createSession = `-- name: CreateSession :one
 INSERT INTO sessions (
     id,
-    status
+    status,
+    is_demo,
+    tenant_id,
+    owner_id
 ) VALUES (
-    $1, $2
-) RETURNING id, project_id, status, type, user_goal, project_context, current_iteration, result, error, created_at, updated_at
+    $1, $2, $3, $4, $5
+) RETURNING id, project_id, status, type, user_goal, project_context, current_iteration, result, error, created_at, updated_at, project_context_compressed, is_demo, tenant_id, last_activity_at, callback_granularity, current_question_id, owner_id, language, language_explicit, summary_style, purged_at
 `
 
 type CreateSessionParams struct {
-	ID     pgtype.UUID `json:"id"`
-	Status string      `json:"status"`
+	ID       pgtype.UUID `json:"id"`
+	Status   string      `json:"status"`
+	IsDemo   bool        `json:"is_demo"`
+	TenantID string      `json:"tenant_id"`
+	OwnerID  pgtype.UUID `json:"owner_id"`
 }
 
 func (q *Queries) CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error) {
-	row := q.db.QueryRow(ctx, createSession, arg.ID, arg.Status)
+	row := q.db.QueryRow(ctx, createSession,
+		arg.ID,
+		arg.Status,
+		arg.IsDemo,
+		arg.TenantID,
+		arg.OwnerID,
+	)
 	var i Session
 	err := row.Scan(
 		&i.ID,
@@ -115,27 +171,258 @@ func (q *Queries) CreateSession(ctx context.Context, arg CreateSessionParams) (S
 		&i.Error,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.ProjectContextCompressed,
+		&i.IsDemo,
+		&i.TenantID,
+		&i.LastActivityAt,
+		&i.CallbackGranularity,
+		&i.CurrentQuestionID,
+		&i.OwnerID,
+		&i.Language,
+		&i.LanguageExplicit,
+		&i.SummaryStyle,
+		&i.PurgedAt,
 	)
 	return i, err
 }
 
+const deleteDemoSessionsBefore = `-- name: DeleteDemoSessionsBefore :execrows
+DELETE FROM sessions
+WHERE is_demo AND GREATEST(created_at, last_activity_at) < $1::timestamp
+`
+
+// Related rows go with the session through ON DELETE CASCADE; demo sessions of all tenants expire
+// once neither their creation nor their last heartbeat is newer than before
+func (q *Queries) DeleteDemoSessionsBefore(ctx context.Context, before pgtype.Timestamp) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteDemoSessionsBefore, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const deleteSession = `-- name: DeleteSession :exec
 DELETE FROM sessions
-WHERE id = $1
+WHERE id = $1 AND tenant_id = $2
 `
 
-func (q *Queries) DeleteSession(ctx context.Context, id pgtype.UUID) error {
-	_, err := q.db.Exec(ctx, deleteSession, id)
+type DeleteSessionParams struct {
+	ID       pgtype.UUID `json:"id"`
+	TenantID string      `json:"tenant_id"`
+}
+
+func (q *Queries) DeleteSession(ctx context.Context, arg DeleteSessionParams) error {
+	_, err := q.db.Exec(ctx, deleteSession, arg.ID, arg.TenantID)
 	return err
 }
 
+const getLatestProjectResultSession = `-- name: GetLatestProjectResultSession :one
+SELECT id, project_id, status, type, user_goal, project_context, current_iteration, result, error, created_at, updated_at, project_context_compressed, is_demo, tenant_id, last_activity_at, callback_granularity, current_question_id, owner_id, language, language_explicit, summary_style, purged_at FROM sessions
+WHERE project_id = $1 AND tenant_id = $2 AND status = 'DONE' AND NOT is_demo
+  AND ($3::UUID IS NULL OR owner_id IS NULL OR owner_id = $3)
+  AND (result IS NOT NULL OR EXISTS (SELECT 1 FROM session_result_versions v WHERE v.session_id = sessions.id))
+ORDER BY updated_at DESC
+LIMIT 1
+`
+
+type GetLatestProjectResultSessionParams struct {
+	ProjectID pgtype.UUID `json:"project_id"`
+	TenantID  string      `json:"tenant_id"`
+	OwnerID   pgtype.UUID `json:"owner_id"`
+}
+
+func (q *Queries) GetLatestProjectResultSession(ctx context.Context, arg GetLatestProjectResultSessionParams) (Session, error) {
+	row := q.db.QueryRow(ctx, getLatestProjectResultSession, arg.ProjectID, arg.TenantID, arg.OwnerID)
+	var i Session
+	err := row.Scan(
+		&i.ID,
+		&i.ProjectID,
+		&i.Status,
+		&i.Type,
+		&i.UserGoal,
+		&i.ProjectContext,
+		&i.CurrentIteration,
+		&i.Result,
+		&i.Error,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ProjectContextCompressed,
+		&i.IsDemo,
+		&i.TenantID,
+		&i.LastActivityAt,
+		&i.CallbackGranularity,
+		&i.CurrentQuestionID,
+		&i.OwnerID,
+		&i.Language,
+		&i.LanguageExplicit,
+		&i.SummaryStyle,
+		&i.PurgedAt,
+	)
+	return i, err
+}
+
+const getPreviousProjectResultSession = `-- name: GetPreviousProjectResultSession :one
+SELECT id, project_id, status, type, user_goal, project_context, current_iteration, result, error, created_at, updated_at, project_context_compressed, is_demo, tenant_id, last_activity_at, callback_granularity, current_question_id, owner_id, language, language_explicit, summary_style, purged_at FROM sessions
+WHERE project_id = $1 AND tenant_id = $2 AND status = 'DONE' AND NOT is_demo
+  AND created_at < $3::timestamp
+  AND ($4::UUID IS NULL OR owner_id IS NULL OR owner_id = $4)
+  AND (result IS NOT NULL OR EXISTS (SELECT 1 FROM session_result_versions v WHERE v.session_id = sessions.id))
+ORDER BY created_at DESC
+LIMIT 1
+`
+
+type GetPreviousProjectResultSessionParams struct {
+	ProjectID pgtype.UUID      `json:"project_id"`
+	TenantID  string           `json:"tenant_id"`
+	Before    pgtype.Timestamp `json:"before"`
+	OwnerID   pgtype.UUID      `json:"owner_id"`
+}
+
+// The latest completed session of the project with a result started before the given time
+func (q *Queries) GetPreviousProjectResultSession(ctx context.Context, arg GetPreviousProjectResultSessionParams) (Session, error) {
+	row := q.db.QueryRow(ctx, getPreviousProjectResultSession,
+		arg.ProjectID,
+		arg.TenantID,
+		arg.Before,
+		arg.OwnerID,
+	)
+	var i Session
+	err := row.Scan(
+		&i.ID,
+		&i.ProjectID,
+		&i.Status,
+		&i.Type,
+		&i.UserGoal,
+		&i.ProjectContext,
+		&i.CurrentIteration,
+		&i.Result,
+		&i.Error,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ProjectContextCompressed,
+		&i.IsDemo,
+		&i.TenantID,
+		&i.LastActivityAt,
+		&i.CallbackGranularity,
+		&i.CurrentQuestionID,
+		&i.OwnerID,
+		&i.Language,
+		&i.LanguageExplicit,
+		&i.SummaryStyle,
+		&i.PurgedAt,
+	)
+	return i, err
+}
+
 const getSessionByID = `-- name: GetSessionByID :one
-SELECT id, project_id, status, type, user_goal, project_context, current_iteration, result, error, created_at, updated_at FROM sessions
-WHERE id = $1
+SELECT id, project_id, status, type, user_goal, project_context, current_iteration, result, error, created_at, updated_at, project_context_compressed, is_demo, tenant_id, last_activity_at, callback_granularity, current_question_id, owner_id, language, language_explicit, summary_style, purged_at FROM sessions
+WHERE id = $1 AND tenant_id = $2
+  AND ($3::UUID IS NULL OR owner_id IS NULL OR owner_id = $3)
+`
+
+type GetSessionByIDParams struct {
+	ID       pgtype.UUID `json:"id"`
+	TenantID string      `json:"tenant_id"`
+	OwnerID  pgtype.UUID `json:"owner_id"`
+}
+
+// A user sees the sessions they own and the sessions nobody owns; a NULL owner_id sees the whole tenant
+func (q *Queries) GetSessionByID(ctx context.Context, arg GetSessionByIDParams) (Session, error) {
+	row := q.db.QueryRow(ctx, getSessionByID, arg.ID, arg.TenantID, arg.OwnerID)
+	var i Session
+	err := row.Scan(
+		&i.ID,
+		&i.ProjectID,
+		&i.Status,
+		&i.Type,
+		&i.UserGoal,
+		&i.ProjectContext,
+		&i.CurrentIteration,
+		&i.Result,
+		&i.Error,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ProjectContextCompressed,
+		&i.IsDemo,
+		&i.TenantID,
+		&i.LastActivityAt,
+		&i.CallbackGranularity,
+		&i.CurrentQuestionID,
+		&i.OwnerID,
+		&i.Language,
+		&i.LanguageExplicit,
+		&i.SummaryStyle,
+		&i.PurgedAt,
+	)
+	return i, err
+}
+
+const listUncompressedSessionContexts = `-- name: ListUncompressedSessionContexts :many
+SELECT id, project_context::text AS project_context
+FROM sessions
+WHERE project_context_compressed IS NULL
+  AND octet_length(project_context) > $1::int
+  AND id > $2
+ORDER BY id
+LIMIT $3
 `
 
-func (q *Queries) GetSessionByID(ctx context.Context, id pgtype.UUID) (Session, error) {
-	row := q.db.QueryRow(ctx, getSessionByID, id)
+type ListUncompressedSessionContextsParams struct {
+	MinSize   int32       `json:"min_size"`
+	AfterID   pgtype.UUID `json:"after_id"`
+	BatchSize int32       `json:"batch_size"`
+}
+
+type ListUncompressedSessionContextsRow struct {
+	ID             pgtype.UUID `json:"id"`
+	ProjectContext string      `json:"project_context"`
+}
+
+// Pages through plain project contexts above the size threshold by id, so rows that do not
+// shrink and stay plain are not returned again. Maintenance runs across all tenants
+func (q *Queries) ListUncompressedSessionContexts(ctx context.Context, arg ListUncompressedSessionContextsParams) ([]ListUncompressedSessionContextsRow, error) {
+	rows, err := q.db.Query(ctx, listUncompressedSessionContexts, arg.MinSize, arg.AfterID, arg.BatchSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListUncompressedSessionContextsRow{}
+	for rows.Next() {
+		var i ListUncompressedSessionContextsRow
+		if err := rows.Scan(&i.ID, &i.ProjectContext); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const refreshSessionCurrentQuestion = `-- name: RefreshSessionCurrentQuestion :one
+UPDATE sessions
+SET current_question_id = (
+    SELECT iq.id FROM iteration_questions iq
+    JOIN session_iterations si ON si.id = iq.iteration_id
+    WHERE si.session_id = sessions.id
+      AND iq.status IN ('UNANSWERED', 'DEFERRED')
+    ORDER BY iq.status = 'DEFERRED', si.iteration_number ASC, iq.question_number ASC
+    LIMIT 1
+)
+WHERE id = $1 AND tenant_id = $2
+RETURNING id, project_id, status, type, user_goal, project_context, current_iteration, result, error, created_at, updated_at, project_context_compressed, is_demo, tenant_id, last_activity_at, callback_granularity, current_question_id, owner_id, language, language_explicit, summary_style, purged_at
+`
+
+type RefreshSessionCurrentQuestionParams struct {
+	ID       pgtype.UUID `json:"id"`
+	TenantID string      `json:"tenant_id"`
+}
+
+// Points the session at its first open question: the unanswered ones in interview order,
+// then the deferred ones, which are asked last
+func (q *Queries) RefreshSessionCurrentQuestion(ctx context.Context, arg RefreshSessionCurrentQuestionParams) (Session, error) {
+	row := q.db.QueryRow(ctx, refreshSessionCurrentQuestion, arg.ID, arg.TenantID)
 	var i Session
 	err := row.Scan(
 		&i.ID,
@@ -149,6 +436,17 @@ func (q *Queries) GetSessionByID(ctx context.Context, id pgtype.UUID) (Session,
 		&i.Error,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.ProjectContextCompressed,
+		&i.IsDemo,
+		&i.TenantID,
+		&i.LastActivityAt,
+		&i.CallbackGranularity,
+		&i.CurrentQuestionID,
+		&i.OwnerID,
+		&i.Language,
+		&i.LanguageExplicit,
+		&i.SummaryStyle,
+		&i.PurgedAt,
 	)
 	return i, err
 }
@@ -157,12 +455,129 @@ const resetSessionIteration = `-- name: ResetSessionIteration :one
 UPDATE sessions
 SET current_iteration = current_iteration - 1,
     updated_at = NOW()
+WHERE id = $1 AND tenant_id = $2
+RETURNING id, project_id, status, type, user_goal, project_context, current_iteration, result, error, created_at, updated_at, project_context_compressed, is_demo, tenant_id, last_activity_at, callback_granularity, current_question_id, owner_id, language, language_explicit, summary_style, purged_at
+`
+
+type ResetSessionIterationParams struct {
+	ID       pgtype.UUID `json:"id"`
+	TenantID string      `json:"tenant_id"`
+}
+
+func (q *Queries) ResetSessionIteration(ctx context.Context, arg ResetSessionIterationParams) (Session, error) {
+	row := q.db.QueryRow(ctx, resetSessionIteration, arg.ID, arg.TenantID)
+	var i Session
+	err := row.Scan(
+		&i.ID,
+		&i.ProjectID,
+		&i.Status,
+		&i.Type,
+		&i.UserGoal,
+		&i.ProjectContext,
+		&i.CurrentIteration,
+		&i.Result,
+		&i.Error,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ProjectContextCompressed,
+		&i.IsDemo,
+		&i.TenantID,
+		&i.LastActivityAt,
+		&i.CallbackGranularity,
+		&i.CurrentQuestionID,
+		&i.OwnerID,
+		&i.Language,
+		&i.LanguageExplicit,
+		&i.SummaryStyle,
+		&i.PurgedAt,
+	)
+	return i, err
+}
+
+const setSessionProjectContextCompressed = `-- name: SetSessionProjectContextCompressed :exec
+UPDATE sessions
+SET project_context = NULL,
+    project_context_compressed = $2
 WHERE id = $1
-RETURNING id, project_id, status, type, user_goal, project_context, current_iteration, result, error, created_at, updated_at
 `
 
-func (q *Queries) ResetSessionIteration(ctx context.Context, id pgtype.UUID) (Session, error) {
-	row := q.db.QueryRow(ctx, resetSessionIteration, id)
+type SetSessionProjectContextCompressedParams struct {
+	ID                       pgtype.UUID `json:"id"`
+	ProjectContextCompressed []byte      `json:"project_context_compressed"`
+}
+
+// Leaves updated_at untouched: compression does not change the session
+func (q *Queries) SetSessionProjectContextCompressed(ctx context.Context, arg SetSessionProjectContextCompressedParams) error {
+	_, err := q.db.Exec(ctx, setSessionProjectContextCompressed, arg.ID, arg.ProjectContextCompressed)
+	return err
+}
+
+const touchSessionActivity = `-- name: TouchSessionActivity :one
+UPDATE sessions
+SET last_activity_at = NOW()
+WHERE id = $1 AND tenant_id = $2
+RETURNING id, project_id, status, type, user_goal, project_context, current_iteration, result, error, created_at, updated_at, project_context_compressed, is_demo, tenant_id, last_activity_at, callback_granularity, current_question_id, owner_id, language, language_explicit, summary_style, purged_at
+`
+
+type TouchSessionActivityParams struct {
+	ID       pgtype.UUID `json:"id"`
+	TenantID string      `json:"tenant_id"`
+}
+
+func (q *Queries) TouchSessionActivity(ctx context.Context, arg TouchSessionActivityParams) (Session, error) {
+	row := q.db.QueryRow(ctx, touchSessionActivity, arg.ID, arg.TenantID)
+	var i Session
+	err := row.Scan(
+		&i.ID,
+		&i.ProjectID,
+		&i.Status,
+		&i.Type,
+		&i.UserGoal,
+		&i.ProjectContext,
+		&i.CurrentIteration,
+		&i.Result,
+		&i.Error,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ProjectContextCompressed,
+		&i.IsDemo,
+		&i.TenantID,
+		&i.LastActivityAt,
+		&i.CallbackGranularity,
+		&i.CurrentQuestionID,
+		&i.OwnerID,
+		&i.Language,
+		&i.LanguageExplicit,
+		&i.SummaryStyle,
+		&i.PurgedAt,
+	)
+	return i, err
+}
+
+const transitionSessionStatus = `-- name: TransitionSessionStatus :one
+UPDATE sessions
+SET status = $1,
+    updated_at = NOW()
+WHERE id = $2 AND tenant_id = $3 AND status = $4
+RETURNING id, project_id, status, type, user_goal, project_context, current_iteration, result, error, created_at, updated_at, project_context_compressed, is_demo, tenant_id, last_activity_at, callback_granularity, current_question_id, owner_id, language, language_explicit, summary_style, purged_at
+`
+
+type TransitionSessionStatusParams struct {
+	ToStatus   string      `json:"to_status"`
+	ID         pgtype.UUID `json:"id"`
+	TenantID   string      `json:"tenant_id"`
+	FromStatus string      `json:"from_status"`
+}
+
+// Changes the status only while the session is still in the expected one,
+// so a concurrent or repeated transition affects no row
+func (q *Queries) TransitionSessionStatus(ctx context.Context, arg TransitionSessionStatusParams) (Session, error) {
+	row := q.db.QueryRow(ctx, transitionSessionStatus,
+		arg.ToStatus,
+		arg.ID,
+		arg.TenantID,
+		arg.FromStatus,
+	)
 	var i Session
 	err := row.Scan(
 		&i.ID,
@@ -176,6 +591,17 @@ func (q *Queries) ResetSessionIteration(ctx context.Context, id pgtype.UUID) (Se
 		&i.Error,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.ProjectContextCompressed,
+		&i.IsDemo,
+		&i.TenantID,
+		&i.LastActivityAt,
+		&i.CallbackGranularity,
+		&i.CurrentQuestionID,
+		&i.OwnerID,
+		&i.Language,
+		&i.LanguageExplicit,
+		&i.SummaryStyle,
+		&i.PurgedAt,
 	)
 	return i, err
 }
@@ -184,12 +610,64 @@ const updateSessionIteration = `-- name: UpdateSessionIteration :one
 UPDATE sessions
 SET current_iteration = current_iteration + 1,
     updated_at = NOW()
-WHERE id = $1
-RETURNING id, project_id, status, type, user_goal, project_context, current_iteration, result, error, created_at, updated_at
+WHERE id = $1 AND tenant_id = $2
+RETURNING id, project_id, status, type, user_goal, project_context, current_iteration, result, error, created_at, updated_at, project_context_compressed, is_demo, tenant_id, last_activity_at, callback_granularity, current_question_id, owner_id, language, language_explicit, summary_style, purged_at
+`
+
+type UpdateSessionIterationParams struct {
+	ID       pgtype.UUID `json:"id"`
+	TenantID string      `json:"tenant_id"`
+}
+
+func (q *Queries) UpdateSessionIteration(ctx context.Context, arg UpdateSessionIterationParams) (Session, error) {
+	row := q.db.QueryRow(ctx, updateSessionIteration, arg.ID, arg.TenantID)
+	var i Session
+	err := row.Scan(
+		&i.ID,
+		&i.ProjectID,
+		&i.Status,
+		&i.Type,
+		&i.UserGoal,
+		&i.ProjectContext,
+		&i.CurrentIteration,
+		&i.Result,
+		&i.Error,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ProjectContextCompressed,
+		&i.IsDemo,
+		&i.TenantID,
+		&i.LastActivityAt,
+		&i.CallbackGranularity,
+		&i.CurrentQuestionID,
+		&i.OwnerID,
+		&i.Language,
+		&i.LanguageExplicit,
+		&i.SummaryStyle,
+		&i.PurgedAt,
+	)
+	return i, err
+}
+
+const updateSessionLanguage = `-- name: UpdateSessionLanguage :one
+UPDATE sessions
+SET language = CASE WHEN language_explicit AND NOT $3::boolean THEN language ELSE $2 END,
+    language_explicit = language_explicit OR $3::boolean,
+    updated_at = NOW()
+WHERE id = $1 AND tenant_id = $4
+RETURNING id, project_id, status, type, user_goal, project_context, current_iteration, result, error, created_at, updated_at, project_context_compressed, is_demo, tenant_id, last_activity_at, callback_granularity, current_question_id, owner_id, language, language_explicit, summary_style, purged_at
 `
 
-func (q *Queries) UpdateSessionIteration(ctx context.Context, id pgtype.UUID) (Session, error) {
-	row := q.db.QueryRow(ctx, updateSessionIteration, id)
+type UpdateSessionLanguageParams struct {
+	ID       pgtype.UUID `json:"id"`
+	Language pgtype.Text `json:"language"`
+	Explicit bool        `json:"explicit"`
+	TenantID string      `json:"tenant_id"`
+}
+
+// A detected language ($3 false) never replaces a language the user has set explicitly
+func (q *Queries) UpdateSessionLanguage(ctx context.Context, arg UpdateSessionLanguageParams) (Session, error) {
+	row := q.db.QueryRow(ctx, updateSessionLanguage, arg.ID, arg.Language, arg.Explicit, arg.TenantID)
 	var i Session
 	err := row.Scan(
 		&i.ID,
@@ -203,6 +681,17 @@ func (q *Queries) UpdateSessionIteration(ctx context.Context, id pgtype.UUID) (S
 		&i.Error,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.ProjectContextCompressed,
+		&i.IsDemo,
+		&i.TenantID,
+		&i.LastActivityAt,
+		&i.CallbackGranularity,
+		&i.CurrentQuestionID,
+		&i.OwnerID,
+		&i.Language,
+		&i.LanguageExplicit,
+		&i.SummaryStyle,
+		&i.PurgedAt,
 	)
 	return i, err
 }
@@ -211,18 +700,26 @@ const updateSessionProjectContext = `-- name: UpdateSessionProjectContext :one
 UPDATE sessions
 SET project_context = $1, 
     project_id = NULL, 
+    project_context_compressed = $3,
     updated_at = NOW()
-WHERE id = $2
-RETURNING id, project_id, status, type, user_goal, project_context, current_iteration, result, error, created_at, updated_at
+WHERE id = $2 AND tenant_id = $4
+RETURNING id, project_id, status, type, user_goal, project_context, current_iteration, result, error, created_at, updated_at, project_context_compressed, is_demo, tenant_id, last_activity_at, callback_granularity, current_question_id, owner_id, language, language_explicit, summary_style, purged_at
 `
 
 type UpdateSessionProjectContextParams struct {
-	ProjectContext pgtype.Text `json:"project_context"`
-	ID             pgtype.UUID `json:"id"`
+	ProjectContext           pgtype.Text `json:"project_context"`
+	ID                       pgtype.UUID `json:"id"`
+	ProjectContextCompressed []byte      `json:"project_context_compressed"`
+	TenantID                 string      `json:"tenant_id"`
 }
 
 func (q *Queries) UpdateSessionProjectContext(ctx context.Context, arg UpdateSessionProjectContextParams) (Session, error) {
-	row := q.db.QueryRow(ctx, updateSessionProjectContext, arg.ProjectContext, arg.ID)
+	row := q.db.QueryRow(ctx, updateSessionProjectContext,
+		arg.ProjectContext,
+		arg.ID,
+		arg.ProjectContextCompressed,
+		arg.TenantID,
+	)
 	var i Session
 	err := row.Scan(
 		&i.ID,
@@ -236,6 +733,17 @@ func (q *Queries) UpdateSessionProjectContext(ctx context.Context, arg UpdateSes
 		&i.Error,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.ProjectContextCompressed,
+		&i.IsDemo,
+		&i.TenantID,
+		&i.LastActivityAt,
+		&i.CallbackGranularity,
+		&i.CurrentQuestionID,
+		&i.OwnerID,
+		&i.Language,
+		&i.LanguageExplicit,
+		&i.SummaryStyle,
+		&i.PurgedAt,
 	)
 	return i, err
 }
@@ -244,19 +752,28 @@ const updateSessionRAGProjectContext = `-- name: UpdateSessionRAGProjectContext
 UPDATE sessions
 SET project_context = $1, 
     project_id = $3, 
+    project_context_compressed = $4,
     updated_at = NOW()
-WHERE id = $2
-RETURNING id, project_id, status, type, user_goal, project_context, current_iteration, result, error, created_at, updated_at
+WHERE id = $2 AND tenant_id = $5
+RETURNING id, project_id, status, type, user_goal, project_context, current_iteration, result, error, created_at, updated_at, project_context_compressed, is_demo, tenant_id, last_activity_at, callback_granularity, current_question_id, owner_id, language, language_explicit, summary_style, purged_at
 `
 
 type UpdateSessionRAGProjectContextParams struct {
-	ProjectContext pgtype.Text `json:"project_context"`
-	ID             pgtype.UUID `json:"id"`
-	ProjectID      pgtype.UUID `json:"project_id"`
+	ProjectContext           pgtype.Text `json:"project_context"`
+	ID                       pgtype.UUID `json:"id"`
+	ProjectID                pgtype.UUID `json:"project_id"`
+	ProjectContextCompressed []byte      `json:"project_context_compressed"`
+	TenantID                 string      `json:"tenant_id"`
 }
 
 func (q *Queries) UpdateSessionRAGProjectContext(ctx context.Context, arg UpdateSessionRAGProjectContextParams) (Session, error) {
-	row := q.db.QueryRow(ctx, updateSessionRAGProjectContext, arg.ProjectContext, arg.ID, arg.ProjectID)
+	row := q.db.QueryRow(ctx, updateSessionRAGProjectContext,
+		arg.ProjectContext,
+		arg.ID,
+		arg.ProjectID,
+		arg.ProjectContextCompressed,
+		arg.TenantID,
+	)
 	var i Session
 	err := row.Scan(
 		&i.ID,
@@ -270,6 +787,17 @@ func (q *Queries) UpdateSessionRAGProjectContext(ctx context.Context, arg Update
 		&i.Error,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.ProjectContextCompressed,
+		&i.IsDemo,
+		&i.TenantID,
+		&i.LastActivityAt,
+		&i.CallbackGranularity,
+		&i.CurrentQuestionID,
+		&i.OwnerID,
+		&i.Language,
+		&i.LanguageExplicit,
+		&i.SummaryStyle,
+		&i.PurgedAt,
 	)
 	return i, err
 }
@@ -280,15 +808,16 @@ SET status = $2,
     result = $3,
     error = $4,
     updated_at = NOW()
-WHERE id = $1
-RETURNING id, project_id, status, type, user_goal, project_context, current_iteration, result, error, created_at, updated_at
+WHERE id = $1 AND tenant_id = $5
+RETURNING id, project_id, status, type, user_goal, project_context, current_iteration, result, error, created_at, updated_at, project_context_compressed, is_demo, tenant_id, last_activity_at, callback_granularity, current_question_id, owner_id, language, language_explicit, summary_style, purged_at
 `
 
 type UpdateSessionResultParams struct {
-	ID     pgtype.UUID `json:"id"`
-	Status string      `json:"status"`
-	Result pgtype.Text `json:"result"`
-	Error  pgtype.Text `json:"error"`
+	ID       pgtype.UUID `json:"id"`
+	Status   string      `json:"status"`
+	Result   pgtype.Text `json:"result"`
+	Error    pgtype.Text `json:"error"`
+	TenantID string      `json:"tenant_id"`
 }
 
 func (q *Queries) UpdateSessionResult(ctx context.Context, arg UpdateSessionResultParams) (Session, error) {
@@ -297,6 +826,7 @@ func (q *Queries) UpdateSessionResult(ctx context.Context, arg UpdateSessionResu
 		arg.Status,
 		arg.Result,
 		arg.Error,
+		arg.TenantID,
 	)
 	var i Session
 	err := row.Scan(
@@ -311,6 +841,17 @@ func (q *Queries) UpdateSessionResult(ctx context.Context, arg UpdateSessionResu
 		&i.Error,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.ProjectContextCompressed,
+		&i.IsDemo,
+		&i.TenantID,
+		&i.LastActivityAt,
+		&i.CallbackGranularity,
+		&i.CurrentQuestionID,
+		&i.OwnerID,
+		&i.Language,
+		&i.LanguageExplicit,
+		&i.SummaryStyle,
+		&i.PurgedAt,
 	)
 	return i, err
 }
@@ -319,17 +860,62 @@ const updateSessionStatus = `-- name: UpdateSessionStatus :one
 UPDATE sessions
 SET status = $2,
     updated_at = NOW()
-WHERE id = $1
-RETURNING id, project_id, status, type, user_goal, project_context, current_iteration, result, error, created_at, updated_at
+WHERE id = $1 AND tenant_id = $3
+RETURNING id, project_id, status, type, user_goal, project_context, current_iteration, result, error, created_at, updated_at, project_context_compressed, is_demo, tenant_id, last_activity_at, callback_granularity, current_question_id, owner_id, language, language_explicit, summary_style, purged_at
 `
 
 type UpdateSessionStatusParams struct {
-	ID     pgtype.UUID `json:"id"`
-	Status string      `json:"status"`
+	ID       pgtype.UUID `json:"id"`
+	Status   string      `json:"status"`
+	TenantID string      `json:"tenant_id"`
 }
 
 func (q *Queries) UpdateSessionStatus(ctx context.Context, arg UpdateSessionStatusParams) (Session, error) {
-	row := q.db.QueryRow(ctx, updateSessionStatus, arg.ID, arg.Status)
+	row := q.db.QueryRow(ctx, updateSessionStatus, arg.ID, arg.Status, arg.TenantID)
+	var i Session
+	err := row.Scan(
+		&i.ID,
+		&i.ProjectID,
+		&i.Status,
+		&i.Type,
+		&i.UserGoal,
+		&i.ProjectContext,
+		&i.CurrentIteration,
+		&i.Result,
+		&i.Error,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.ProjectContextCompressed,
+		&i.IsDemo,
+		&i.TenantID,
+		&i.LastActivityAt,
+		&i.CallbackGranularity,
+		&i.CurrentQuestionID,
+		&i.OwnerID,
+		&i.Language,
+		&i.LanguageExplicit,
+		&i.SummaryStyle,
+		&i.PurgedAt,
+	)
+	return i, err
+}
+
+const updateSessionSummaryStyle = `-- name: UpdateSessionSummaryStyle :one
+UPDATE sessions
+SET summary_style = $2,
+    updated_at = NOW()
+WHERE id = $1 AND tenant_id = $3
+RETURNING id, project_id, status, type, user_goal, project_context, current_iteration, result, error, created_at, updated_at, project_context_compressed, is_demo, tenant_id, last_activity_at, callback_granularity, current_question_id, owner_id, language, language_explicit, summary_style, purged_at
+`
+
+type UpdateSessionSummaryStyleParams struct {
+	ID           pgtype.UUID `json:"id"`
+	SummaryStyle pgtype.Text `json:"summary_style"`
+	TenantID     string      `json:"tenant_id"`
+}
+
+func (q *Queries) UpdateSessionSummaryStyle(ctx context.Context, arg UpdateSessionSummaryStyleParams) (Session, error) {
+	row := q.db.QueryRow(ctx, updateSessionSummaryStyle, arg.ID, arg.SummaryStyle, arg.TenantID)
 	var i Session
 	err := row.Scan(
 		&i.ID,
@@ -343,6 +929,17 @@ func (q *Queries) UpdateSessionStatus(ctx context.Context, arg UpdateSessionStat
 		&i.Error,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.ProjectContextCompressed,
+		&i.IsDemo,
+		&i.TenantID,
+		&i.LastActivityAt,
+		&i.CallbackGranularity,
+		&i.CurrentQuestionID,
+		&i.OwnerID,
+		&i.Language,
+		&i.LanguageExplicit,
+		&i.SummaryStyle,
+		&i.PurgedAt,
 	)
 	return i, err
 }
@@ -351,17 +948,18 @@ const updateSessionType = `-- name: UpdateSessionType :one
 UPDATE sessions
 SET type = $2,
     updated_at = NOW()
-WHERE id = $1
-RETURNING id, project_id, status, type, user_goal, project_context, current_iteration, result, error, created_at, updated_at
+WHERE id = $1 AND tenant_id = $3
+RETURNING id, project_id, status, type, user_goal, project_context, current_iteration, result, error, created_at, updated_at, project_context_compressed, is_demo, tenant_id, last_activity_at, callback_granularity, current_question_id, owner_id, language, language_explicit, summary_style, purged_at
 `
 
 type UpdateSessionTypeParams struct {
-	ID   pgtype.UUID `json:"id"`
-	Type pgtype.Text `json:"type"`
+	ID       pgtype.UUID `json:"id"`
+	Type     pgtype.Text `json:"type"`
+	TenantID string      `json:"tenant_id"`
 }
 
 func (q *Queries) UpdateSessionType(ctx context.Context, arg UpdateSessionTypeParams) (Session, error) {
-	row := q.db.QueryRow(ctx, updateSessionType, arg.ID, arg.Type)
+	row := q.db.QueryRow(ctx, updateSessionType, arg.ID, arg.Type, arg.TenantID)
 	var i Session
 	err := row.Scan(
 		&i.ID,
@@ -375,6 +973,17 @@ func (q *Queries) UpdateSessionType(ctx context.Context, arg UpdateSessionTypePa
 		&i.Error,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.ProjectContextCompressed,
+		&i.IsDemo,
+		&i.TenantID,
+		&i.LastActivityAt,
+		&i.CallbackGranularity,
+		&i.CurrentQuestionID,
+		&i.OwnerID,
+		&i.Language,
+		&i.LanguageExplicit,
+		&i.SummaryStyle,
+		&i.PurgedAt,
 	)
 	return i, err
 }
@@ -383,17 +992,18 @@ const updateSessionUserGoal = `-- name: UpdateSessionUserGoal :one
 UPDATE sessions
 SET user_goal = $2,
     updated_at = NOW()
-WHERE id = $1
-RETURNING id, project_id, status, type, user_goal, project_context, current_iteration, result, error, created_at, updated_at
+WHERE id = $1 AND tenant_id = $3
+RETURNING id, project_id, status, type, user_goal, project_context, current_iteration, result, error, created_at, updated_at, project_context_compressed, is_demo, tenant_id, last_activity_at, callback_granularity, current_question_id, owner_id, language, language_explicit, summary_style, purged_at
 `
 
 type UpdateSessionUserGoalParams struct {
 	ID       pgtype.UUID `json:"id"`
 	UserGoal pgtype.Text `json:"user_goal"`
+	TenantID string      `json:"tenant_id"`
 }
 
 func (q *Queries) UpdateSessionUserGoal(ctx context.Context, arg UpdateSessionUserGoalParams) (Session, error) {
-	row := q.db.QueryRow(ctx, updateSessionUserGoal, arg.ID, arg.UserGoal)
+	row := q.db.QueryRow(ctx, updateSessionUserGoal, arg.ID, arg.UserGoal, arg.TenantID)
 	var i Session
 	err := row.Scan(
 		&i.ID,
@@ -407,6 +1017,17 @@ func (q *Queries) UpdateSessionUserGoal(ctx context.Context, arg UpdateSessionUs
 		&i.Error,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.ProjectContextCompressed,
+		&i.IsDemo,
+		&i.TenantID,
+		&i.LastActivityAt,
+		&i.CallbackGranularity,
+		&i.CurrentQuestionID,
+		&i.OwnerID,
+		&i.Language,
+		&i.LanguageExplicit,
+		&i.SummaryStyle,
+		&i.PurgedAt,
 	)
 	return i, err
 }
@@ -11,24 +11,131 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const createTelegramInboxMessage = `-- name: CreateTelegramInboxMessage :one
+INSERT INTO telegram_inbox (tenant_id, user_id, session_id, question_id, text, created_at)
+VALUES ($1, $2, $3, $4, $5, NOW())
+RETURNING id, tenant_id, user_id, session_id, question_id, text, created_at
+`
+
+type CreateTelegramInboxMessageParams struct {
+	TenantID   string      `json:"tenant_id"`
+	UserID     int64       `json:"user_id"`
+	SessionID  pgtype.UUID `json:"session_id"`
+	QuestionID pgtype.UUID `json:"question_id"`
+	Text       string      `json:"text"`
+}
+
+func (q *Queries) CreateTelegramInboxMessage(ctx context.Context, arg CreateTelegramInboxMessageParams) (TelegramInbox, error) {
+	row := q.db.QueryRow(ctx, createTelegramInboxMessage,
+		arg.TenantID,
+		arg.UserID,
+		arg.SessionID,
+		arg.QuestionID,
+		arg.Text,
+	)
+	var i TelegramInbox
+	err := row.Scan(
+		&i.ID,
+		&i.TenantID,
+		&i.UserID,
+		&i.SessionID,
+		&i.QuestionID,
+		&i.Text,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const deleteTelegramInboxMessage = `-- name: DeleteTelegramInboxMessage :exec
+DELETE FROM telegram_inbox
+WHERE id = $1 AND user_id = $2 AND tenant_id = $3
+`
+
+type DeleteTelegramInboxMessageParams struct {
+	ID       pgtype.UUID `json:"id"`
+	UserID   int64       `json:"user_id"`
+	TenantID string      `json:"tenant_id"`
+}
+
+func (q *Queries) DeleteTelegramInboxMessage(ctx context.Context, arg DeleteTelegramInboxMessageParams) error {
+	_, err := q.db.Exec(ctx, deleteTelegramInboxMessage, arg.ID, arg.UserID, arg.TenantID)
+	return err
+}
+
 const deleteTelegramSession = `-- name: DeleteTelegramSession :exec
 DELETE FROM telegram_sessions
-WHERE user_id = $1
+WHERE user_id = $1 AND tenant_id = $2
 `
 
-func (q *Queries) DeleteTelegramSession(ctx context.Context, userID int64) error {
-	_, err := q.db.Exec(ctx, deleteTelegramSession, userID)
+type DeleteTelegramSessionParams struct {
+	UserID   int64  `json:"user_id"`
+	TenantID string `json:"tenant_id"`
+}
+
+func (q *Queries) DeleteTelegramSession(ctx context.Context, arg DeleteTelegramSessionParams) error {
+	_, err := q.db.Exec(ctx, deleteTelegramSession, arg.UserID, arg.TenantID)
 	return err
 }
 
+const getSessionNormalizeTranscripts = `-- name: GetSessionNormalizeTranscripts :one
+SELECT u.normalize_transcripts
+FROM telegram_sessions ts
+JOIN telegram_users u ON u.tenant_id = ts.tenant_id AND u.user_id = ts.user_id
+WHERE ts.session_id = $1 AND ts.tenant_id = $2
+`
+
+type GetSessionNormalizeTranscriptsParams struct {
+	SessionID pgtype.UUID `json:"session_id"`
+	TenantID  string      `json:"tenant_id"`
+}
+
+func (q *Queries) GetSessionNormalizeTranscripts(ctx context.Context, arg GetSessionNormalizeTranscriptsParams) (bool, error) {
+	row := q.db.QueryRow(ctx, getSessionNormalizeTranscripts, arg.SessionID, arg.TenantID)
+	var normalize_transcripts bool
+	err := row.Scan(&normalize_transcripts)
+	return normalize_transcripts, err
+}
+
+const getTelegramInboxMessage = `-- name: GetTelegramInboxMessage :one
+SELECT id, tenant_id, user_id, session_id, question_id, text, created_at
+FROM telegram_inbox
+WHERE id = $1 AND user_id = $2 AND tenant_id = $3
+`
+
+type GetTelegramInboxMessageParams struct {
+	ID       pgtype.UUID `json:"id"`
+	UserID   int64       `json:"user_id"`
+	TenantID string      `json:"tenant_id"`
+}
+
+func (q *Queries) GetTelegramInboxMessage(ctx context.Context, arg GetTelegramInboxMessageParams) (TelegramInbox, error) {
+	row := q.db.QueryRow(ctx, getTelegramInboxMessage, arg.ID, arg.UserID, arg.TenantID)
+	var i TelegramInbox
+	err := row.Scan(
+		&i.ID,
+		&i.TenantID,
+		&i.UserID,
+		&i.SessionID,
+		&i.QuestionID,
+		&i.Text,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
 const getTelegramSession = `-- name: GetTelegramSession :one
-SELECT user_id, session_id, state_data, created_at, updated_at
+SELECT user_id, session_id, state_data, created_at, updated_at, tenant_id
 FROM telegram_sessions
-WHERE user_id = $1
+WHERE user_id = $1 AND tenant_id = $2
 `
 
-func (q *Queries) GetTelegramSession(ctx context.Context, userID int64) (TelegramSession, error) {
-	row := q.db.QueryRow(ctx, getTelegramSession, userID)
+type GetTelegramSessionParams struct {
+	UserID   int64  `json:"user_id"`
+	TenantID string `json:"tenant_id"`
+}
+
+func (q *Queries) GetTelegramSession(ctx context.Context, arg GetTelegramSessionParams) (TelegramSession, error) {
+	row := q.db.QueryRow(ctx, getTelegramSession, arg.UserID, arg.TenantID)
 	var i TelegramSession
 	err := row.Scan(
 		&i.UserID,
@@ -36,18 +143,24 @@ func (q *Queries) GetTelegramSession(ctx context.Context, userID int64) (Telegra
 		&i.StateData,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.TenantID,
 	)
 	return i, err
 }
 
 const getTelegramSessionBySessionID = `-- name: GetTelegramSessionBySessionID :one
-SELECT user_id, session_id, state_data, created_at, updated_at
+SELECT user_id, session_id, state_data, created_at, updated_at, tenant_id
 FROM telegram_sessions
-WHERE session_id = $1
+WHERE session_id = $1 AND tenant_id = $2
 `
 
-func (q *Queries) GetTelegramSessionBySessionID(ctx context.Context, sessionID pgtype.UUID) (TelegramSession, error) {
-	row := q.db.QueryRow(ctx, getTelegramSessionBySessionID, sessionID)
+type GetTelegramSessionBySessionIDParams struct {
+	SessionID pgtype.UUID `json:"session_id"`
+	TenantID  string      `json:"tenant_id"`
+}
+
+func (q *Queries) GetTelegramSessionBySessionID(ctx context.Context, arg GetTelegramSessionBySessionIDParams) (TelegramSession, error) {
+	row := q.db.QueryRow(ctx, getTelegramSessionBySessionID, arg.SessionID, arg.TenantID)
 	var i TelegramSession
 	err := row.Scan(
 		&i.UserID,
@@ -55,6 +168,7 @@ func (q *Queries) GetTelegramSessionBySessionID(ctx context.Context, sessionID p
 		&i.StateData,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.TenantID,
 	)
 	return i, err
 }
@@ -72,9 +186,14 @@ SELECT
     s.project_id as session_project_id
 FROM telegram_sessions ts
 LEFT JOIN sessions s ON ts.session_id = s.id
-WHERE ts.user_id = $1
+WHERE ts.user_id = $1 AND ts.tenant_id = $2
 `
 
+type GetTelegramSessionWithSessionParams struct {
+	UserID   int64  `json:"user_id"`
+	TenantID string `json:"tenant_id"`
+}
+
 type GetTelegramSessionWithSessionRow struct {
 	UserID           int64            `json:"user_id"`
 	SessionID        pgtype.UUID      `json:"session_id"`
@@ -87,8 +206,8 @@ type GetTelegramSessionWithSessionRow struct {
 	SessionProjectID pgtype.UUID      `json:"session_project_id"`
 }
 
-func (q *Queries) GetTelegramSessionWithSession(ctx context.Context, userID int64) (GetTelegramSessionWithSessionRow, error) {
-	row := q.db.QueryRow(ctx, getTelegramSessionWithSession, userID)
+func (q *Queries) GetTelegramSessionWithSession(ctx context.Context, arg GetTelegramSessionWithSessionParams) (GetTelegramSessionWithSessionRow, error) {
+	row := q.db.QueryRow(ctx, getTelegramSessionWithSession, arg.UserID, arg.TenantID)
 	var i GetTelegramSessionWithSessionRow
 	err := row.Scan(
 		&i.UserID,
@@ -104,10 +223,300 @@ func (q *Queries) GetTelegramSessionWithSession(ctx context.Context, userID int6
 	return i, err
 }
 
+const getTelegramUserNormalizeTranscripts = `-- name: GetTelegramUserNormalizeTranscripts :one
+SELECT normalize_transcripts
+FROM telegram_users
+WHERE user_id = $1 AND tenant_id = $2
+`
+
+type GetTelegramUserNormalizeTranscriptsParams struct {
+	UserID   int64  `json:"user_id"`
+	TenantID string `json:"tenant_id"`
+}
+
+func (q *Queries) GetTelegramUserNormalizeTranscripts(ctx context.Context, arg GetTelegramUserNormalizeTranscriptsParams) (bool, error) {
+	row := q.db.QueryRow(ctx, getTelegramUserNormalizeTranscripts, arg.UserID, arg.TenantID)
+	var normalize_transcripts bool
+	err := row.Scan(&normalize_transcripts)
+	return normalize_transcripts, err
+}
+
+const getTelegramUserProgressHeader = `-- name: GetTelegramUserProgressHeader :one
+SELECT progress_header
+FROM telegram_users
+WHERE user_id = $1 AND tenant_id = $2
+`
+
+type GetTelegramUserProgressHeaderParams struct {
+	UserID   int64  `json:"user_id"`
+	TenantID string `json:"tenant_id"`
+}
+
+func (q *Queries) GetTelegramUserProgressHeader(ctx context.Context, arg GetTelegramUserProgressHeaderParams) (pgtype.Bool, error) {
+	row := q.db.QueryRow(ctx, getTelegramUserProgressHeader, arg.UserID, arg.TenantID)
+	var progress_header pgtype.Bool
+	err := row.Scan(&progress_header)
+	return progress_header, err
+}
+
+const getTelegramUserQuestionNumbering = `-- name: GetTelegramUserQuestionNumbering :one
+SELECT question_numbering
+FROM telegram_users
+WHERE user_id = $1 AND tenant_id = $2
+`
+
+type GetTelegramUserQuestionNumberingParams struct {
+	UserID   int64  `json:"user_id"`
+	TenantID string `json:"tenant_id"`
+}
+
+func (q *Queries) GetTelegramUserQuestionNumbering(ctx context.Context, arg GetTelegramUserQuestionNumberingParams) (pgtype.Text, error) {
+	row := q.db.QueryRow(ctx, getTelegramUserQuestionNumbering, arg.UserID, arg.TenantID)
+	var question_numbering pgtype.Text
+	err := row.Scan(&question_numbering)
+	return question_numbering, err
+}
+
+const getTelegramUserSummaryStyle = `-- name: GetTelegramUserSummaryStyle :one
+SELECT summary_style
+FROM telegram_users
+WHERE user_id = $1 AND tenant_id = $2
+`
+
+type GetTelegramUserSummaryStyleParams struct {
+	UserID   int64  `json:"user_id"`
+	TenantID string `json:"tenant_id"`
+}
+
+func (q *Queries) GetTelegramUserSummaryStyle(ctx context.Context, arg GetTelegramUserSummaryStyleParams) (pgtype.Text, error) {
+	row := q.db.QueryRow(ctx, getTelegramUserSummaryStyle, arg.UserID, arg.TenantID)
+	var summary_style pgtype.Text
+	err := row.Scan(&summary_style)
+	return summary_style, err
+}
+
+const getTelegramUserTimezone = `-- name: GetTelegramUserTimezone :one
+SELECT timezone
+FROM telegram_users
+WHERE user_id = $1 AND tenant_id = $2
+`
+
+type GetTelegramUserTimezoneParams struct {
+	UserID   int64  `json:"user_id"`
+	TenantID string `json:"tenant_id"`
+}
+
+func (q *Queries) GetTelegramUserTimezone(ctx context.Context, arg GetTelegramUserTimezoneParams) (pgtype.Text, error) {
+	row := q.db.QueryRow(ctx, getTelegramUserTimezone, arg.UserID, arg.TenantID)
+	var timezone pgtype.Text
+	err := row.Scan(&timezone)
+	return timezone, err
+}
+
+const listOutdatedTelegramStateData = `-- name: ListOutdatedTelegramStateData :many
+SELECT tenant_id, user_id, state_data
+FROM telegram_sessions
+WHERE (tenant_id, user_id) > ($1::varchar, $2::bigint)
+  AND CASE WHEN jsonb_typeof(state_data->'version') = 'number'
+        THEN (state_data->>'version')::numeric
+        ELSE 0
+      END < $3::int
+ORDER BY tenant_id, user_id
+LIMIT $4
+`
+
+type ListOutdatedTelegramStateDataParams struct {
+	AfterTenantID string `json:"after_tenant_id"`
+	AfterUserID   int64  `json:"after_user_id"`
+	Version       int32  `json:"version"`
+	BatchSize     int32  `json:"batch_size"`
+}
+
+type ListOutdatedTelegramStateDataRow struct {
+	TenantID  string `json:"tenant_id"`
+	UserID    int64  `json:"user_id"`
+	StateData []byte `json:"state_data"`
+}
+
+// Pages through the telegram sessions of all tenants whose state data is older than the version;
+// a version that is not a number counts as 0, so the caller sees the broken blob
+func (q *Queries) ListOutdatedTelegramStateData(ctx context.Context, arg ListOutdatedTelegramStateDataParams) ([]ListOutdatedTelegramStateDataRow, error) {
+	rows, err := q.db.Query(ctx, listOutdatedTelegramStateData,
+		arg.AfterTenantID,
+		arg.AfterUserID,
+		arg.Version,
+		arg.BatchSize,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListOutdatedTelegramStateDataRow{}
+	for rows.Next() {
+		var i ListOutdatedTelegramStateDataRow
+		if err := rows.Scan(&i.TenantID, &i.UserID, &i.StateData); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markTelegramUserOnboarded = `-- name: MarkTelegramUserOnboarded :execrows
+INSERT INTO telegram_users (user_id, onboarded_at, tenant_id)
+VALUES ($1, NOW(), $2)
+ON CONFLICT (tenant_id, user_id) DO UPDATE SET
+    onboarded_at = NOW(),
+    last_active_at = NOW()
+WHERE telegram_users.onboarded_at IS NULL
+`
+
+type MarkTelegramUserOnboardedParams struct {
+	UserID   int64  `json:"user_id"`
+	TenantID string `json:"tenant_id"`
+}
+
+// Affects a row only the first time, so concurrent /start commands show the tutorial once
+func (q *Queries) MarkTelegramUserOnboarded(ctx context.Context, arg MarkTelegramUserOnboardedParams) (int64, error) {
+	result, err := q.db.Exec(ctx, markTelegramUserOnboarded, arg.UserID, arg.TenantID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const replaceTelegramStateData = `-- name: ReplaceTelegramStateData :execrows
+UPDATE telegram_sessions
+SET state_data = $1
+WHERE tenant_id = $2
+  AND user_id = $3
+  AND state_data IS NOT DISTINCT FROM $4::jsonb
+`
+
+type ReplaceTelegramStateDataParams struct {
+	StateData         []byte `json:"state_data"`
+	TenantID          string `json:"tenant_id"`
+	UserID            int64  `json:"user_id"`
+	PreviousStateData []byte `json:"previous_state_data"`
+}
+
+// Affects no row when the state data changed since it was read, e.g. by the bot meanwhile;
+// updated_at is kept, so a backfill does not extend the lifetime of idle sessions
+func (q *Queries) ReplaceTelegramStateData(ctx context.Context, arg ReplaceTelegramStateDataParams) (int64, error) {
+	result, err := q.db.Exec(ctx, replaceTelegramStateData,
+		arg.StateData,
+		arg.TenantID,
+		arg.UserID,
+		arg.PreviousStateData,
+	)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const setTelegramUserNormalizeTranscripts = `-- name: SetTelegramUserNormalizeTranscripts :exec
+INSERT INTO telegram_users (user_id, normalize_transcripts, tenant_id)
+VALUES ($1, $2, $3)
+ON CONFLICT (tenant_id, user_id) DO UPDATE SET
+    normalize_transcripts = EXCLUDED.normalize_transcripts,
+    last_active_at = NOW()
+`
+
+type SetTelegramUserNormalizeTranscriptsParams struct {
+	UserID               int64  `json:"user_id"`
+	NormalizeTranscripts bool   `json:"normalize_transcripts"`
+	TenantID             string `json:"tenant_id"`
+}
+
+func (q *Queries) SetTelegramUserNormalizeTranscripts(ctx context.Context, arg SetTelegramUserNormalizeTranscriptsParams) error {
+	_, err := q.db.Exec(ctx, setTelegramUserNormalizeTranscripts, arg.UserID, arg.NormalizeTranscripts, arg.TenantID)
+	return err
+}
+
+const setTelegramUserProgressHeader = `-- name: SetTelegramUserProgressHeader :exec
+INSERT INTO telegram_users (user_id, progress_header, tenant_id)
+VALUES ($1, $2, $3)
+ON CONFLICT (tenant_id, user_id) DO UPDATE SET
+    progress_header = EXCLUDED.progress_header,
+    last_active_at = NOW()
+`
+
+type SetTelegramUserProgressHeaderParams struct {
+	UserID         int64       `json:"user_id"`
+	ProgressHeader pgtype.Bool `json:"progress_header"`
+	TenantID       string      `json:"tenant_id"`
+}
+
+func (q *Queries) SetTelegramUserProgressHeader(ctx context.Context, arg SetTelegramUserProgressHeaderParams) error {
+	_, err := q.db.Exec(ctx, setTelegramUserProgressHeader, arg.UserID, arg.ProgressHeader, arg.TenantID)
+	return err
+}
+
+const setTelegramUserQuestionNumbering = `-- name: SetTelegramUserQuestionNumbering :exec
+INSERT INTO telegram_users (user_id, question_numbering, tenant_id)
+VALUES ($1, $2, $3)
+ON CONFLICT (tenant_id, user_id) DO UPDATE SET
+    question_numbering = EXCLUDED.question_numbering,
+    last_active_at = NOW()
+`
+
+type SetTelegramUserQuestionNumberingParams struct {
+	UserID            int64       `json:"user_id"`
+	QuestionNumbering pgtype.Text `json:"question_numbering"`
+	TenantID          string      `json:"tenant_id"`
+}
+
+func (q *Queries) SetTelegramUserQuestionNumbering(ctx context.Context, arg SetTelegramUserQuestionNumberingParams) error {
+	_, err := q.db.Exec(ctx, setTelegramUserQuestionNumbering, arg.UserID, arg.QuestionNumbering, arg.TenantID)
+	return err
+}
+
+const setTelegramUserSummaryStyle = `-- name: SetTelegramUserSummaryStyle :exec
+INSERT INTO telegram_users (user_id, summary_style, tenant_id)
+VALUES ($1, $2, $3)
+ON CONFLICT (tenant_id, user_id) DO UPDATE SET
+    summary_style = EXCLUDED.summary_style,
+    last_active_at = NOW()
+`
+
+type SetTelegramUserSummaryStyleParams struct {
+	UserID       int64       `json:"user_id"`
+	SummaryStyle pgtype.Text `json:"summary_style"`
+	TenantID     string      `json:"tenant_id"`
+}
+
+func (q *Queries) SetTelegramUserSummaryStyle(ctx context.Context, arg SetTelegramUserSummaryStyleParams) error {
+	_, err := q.db.Exec(ctx, setTelegramUserSummaryStyle, arg.UserID, arg.SummaryStyle, arg.TenantID)
+	return err
+}
+
+const setTelegramUserTimezone = `-- name: SetTelegramUserTimezone :exec
+INSERT INTO telegram_users (user_id, timezone, tenant_id)
+VALUES ($1, $2, $3)
+ON CONFLICT (tenant_id, user_id) DO UPDATE SET
+    timezone = EXCLUDED.timezone,
+    last_active_at = NOW()
+`
+
+type SetTelegramUserTimezoneParams struct {
+	UserID   int64       `json:"user_id"`
+	Timezone pgtype.Text `json:"timezone"`
+	TenantID string      `json:"tenant_id"`
+}
+
+func (q *Queries) SetTelegramUserTimezone(ctx context.Context, arg SetTelegramUserTimezoneParams) error {
+	_, err := q.db.Exec(ctx, setTelegramUserTimezone, arg.UserID, arg.Timezone, arg.TenantID)
+	return err
+}
+
 const upsertTelegramSession = `-- name: UpsertTelegramSession :exec
-INSERT INTO telegram_sessions (user_id, session_id, state_data, created_at, updated_at)
-VALUES ($1, $2, $3, $4, $5)
-ON CONFLICT (user_id) DO UPDATE SET
+INSERT INTO telegram_sessions (user_id, session_id, state_data, created_at, updated_at, tenant_id)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (tenant_id, user_id) DO UPDATE SET
     session_id = EXCLUDED.session_id,
     state_data = EXCLUDED.state_data,
     updated_at = EXCLUDED.updated_at
@@ -119,6 +528,7 @@ type UpsertTelegramSessionParams struct {
 	StateData []byte           `json:"state_data"`
 	CreatedAt pgtype.Timestamp `json:"created_at"`
 	UpdatedAt pgtype.Timestamp `json:"updated_at"`
+	TenantID  string           `json:"tenant_id"`
 }
 
 func (q *Queries) UpsertTelegramSession(ctx context.Context, arg UpsertTelegramSessionParams) error {
@@ -128,6 +538,7 @@ func (q *Queries) UpsertTelegramSession(ctx context.Context, arg UpsertTelegramS
 		arg.StateData,
 		arg.CreatedAt,
 		arg.UpdatedAt,
+		arg.TenantID,
 	)
 	return err
 }
@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/futig/agent-backend/internal/repository/sqlc"
+	"github.com/futig/agent-backend/internal/telegram/state"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+const stateDataBackfillBatchSize = 500
+
+// StateDataBackfill counts the telegram sessions seen by BackfillStateData
+type StateDataBackfill struct {
+	Outdated int // state data older than the current version
+	Upgraded int // rewritten at the current version
+	Changed  int // written by the bot meanwhile, upgraded on its next read instead
+	Failed   int // not readable by the migrations, left as it is
+}
+
+// StateDataCache drops cached telegram sessions whose state data was rewritten in Postgres
+type StateDataCache interface {
+	Forget(ctx context.Context, tenantID string, userID int64)
+}
+
+// BackfillStateData upgrades the telegram state data of all tenants to the current version,
+// so a deploy does not rely on every user coming back for the upgrade on read. With dryRun
+// the outdated state data is only counted. The cached sessions of rewritten rows are dropped
+// from cache, which is nil when the bot runs without the state cache
+func BackfillStateData(ctx context.Context, db *pgxpool.Pool, cache StateDataCache, dryRun bool, logger *zap.Logger) (*StateDataBackfill, error) {
+	queries := sqlc.New(db)
+	report := &StateDataBackfill{}
+
+	// The empty tenant sorts before every tenant, so paging starts from it
+	var afterTenantID string
+	var afterUserID int64
+	for {
+		rows, err := queries.ListOutdatedTelegramStateData(ctx, sqlc.ListOutdatedTelegramStateDataParams{
+			AfterTenantID: afterTenantID,
+			AfterUserID:   afterUserID,
+			Version:       state.StateDataCurrentVersion,
+			BatchSize:     stateDataBackfillBatchSize,
+		})
+		if err != nil {
+			return report, fmt.Errorf("list outdated state data: %w", err)
+		}
+
+		for _, row := range rows {
+			report.Outdated++
+
+			upgraded, changed, err := state.UpgradeStateData(row.StateData)
+			if err != nil {
+				logger.Warn("Failed to upgrade state data",
+					zap.Error(err),
+					zap.String("tenant_id", row.TenantID),
+					zap.Int64("user_id", row.UserID),
+				)
+				report.Failed++
+				continue
+			}
+			if !changed || dryRun {
+				continue
+			}
+
+			replaced, err := queries.ReplaceTelegramStateData(ctx, sqlc.ReplaceTelegramStateDataParams{
+				StateData:         upgraded,
+				TenantID:          row.TenantID,
+				UserID:            row.UserID,
+				PreviousStateData: row.StateData,
+			})
+			if err != nil {
+				return report, fmt.Errorf("replace state data: %w", err)
+			}
+			if replaced == 0 {
+				report.Changed++
+				continue
+			}
+			if cache != nil {
+				cache.Forget(ctx, row.TenantID, row.UserID)
+			}
+			report.Upgraded++
+		}
+
+		if len(rows) < stateDataBackfillBatchSize {
+			break
+		}
+		last := rows[len(rows)-1]
+		afterTenantID, afterUserID = last.TenantID, last.UserID
+	}
+
+	return report, nil
+}
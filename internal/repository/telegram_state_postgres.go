@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/futig/agent-backend/internal/entity"
 	"github.com/futig/agent-backend/internal/repository/sqlc"
 	"github.com/futig/agent-backend/internal/telegram/state"
 	"github.com/google/uuid"
@@ -30,7 +31,10 @@ func NewTelegramStateRepository(db *pgxpool.Pool) *TelegramSessionRepository {
 
 // Get retrieves telegram session by user ID
 func (r *TelegramSessionRepository) Get(ctx context.Context, userID int64) (*state.TelegramSession, error) {
-	dbSession, err := r.queries.GetTelegramSession(ctx, userID)
+	dbSession, err := r.queries.GetTelegramSession(ctx, sqlc.GetTelegramSessionParams{
+		UserID:   userID,
+		TenantID: entity.TenantIDFromContext(ctx),
+	})
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, fmt.Errorf("telegram session not found: %d", userID)
@@ -43,7 +47,10 @@ func (r *TelegramSessionRepository) Get(ctx context.Context, userID int64) (*sta
 
 // GetWithSession retrieves telegram session with joined session data by user ID
 func (r *TelegramSessionRepository) GetWithSession(ctx context.Context, userID int64) (*state.TelegramSessionWithSession, error) {
-	row, err := r.queries.GetTelegramSessionWithSession(ctx, userID)
+	row, err := r.queries.GetTelegramSessionWithSession(ctx, sqlc.GetTelegramSessionWithSessionParams{
+		UserID:   userID,
+		TenantID: entity.TenantIDFromContext(ctx),
+	})
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, fmt.Errorf("telegram session not found: %d", userID)
@@ -57,6 +64,7 @@ func (r *TelegramSessionRepository) GetWithSession(ctx context.Context, userID i
 // Set saves telegram session
 func (r *TelegramSessionRepository) Set(ctx context.Context, telegramSession *state.TelegramSession) error {
 	params := toDBUpsertParams(telegramSession)
+	params.TenantID = entity.TenantIDFromContext(ctx)
 
 	err := r.queries.UpsertTelegramSession(ctx, params)
 	if err != nil {
@@ -68,7 +76,10 @@ func (r *TelegramSessionRepository) Set(ctx context.Context, telegramSession *st
 
 // Delete removes telegram session
 func (r *TelegramSessionRepository) Delete(ctx context.Context, userID int64) error {
-	err := r.queries.DeleteTelegramSession(ctx, userID)
+	err := r.queries.DeleteTelegramSession(ctx, sqlc.DeleteTelegramSessionParams{
+		UserID:   userID,
+		TenantID: entity.TenantIDFromContext(ctx),
+	})
 	if err != nil {
 		return fmt.Errorf("delete telegram session: %w", err)
 	}
@@ -87,7 +98,10 @@ func (r *TelegramSessionRepository) GetBySessionID(ctx context.Context, sessionI
 	sessionUUID.Bytes = parsedUUID
 	sessionUUID.Valid = true
 
-	dbSession, err := r.queries.GetTelegramSessionBySessionID(ctx, sessionUUID)
+	dbSession, err := r.queries.GetTelegramSessionBySessionID(ctx, sqlc.GetTelegramSessionBySessionIDParams{
+		SessionID: sessionUUID,
+		TenantID:  entity.TenantIDFromContext(ctx),
+	})
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, fmt.Errorf("telegram session not found for session: %s", sessionID)
@@ -98,6 +112,306 @@ func (r *TelegramSessionRepository) GetBySessionID(ctx context.Context, sessionI
 	return toStateTelegramSession(&dbSession), nil
 }
 
+// GetNormalizeTranscripts reports whether voice transcriptions of the user are normalized;
+// users without a profile get the default
+func (r *TelegramSessionRepository) GetNormalizeTranscripts(ctx context.Context, userID int64) (bool, error) {
+	enabled, err := r.queries.GetTelegramUserNormalizeTranscripts(ctx, sqlc.GetTelegramUserNormalizeTranscriptsParams{
+		UserID:   userID,
+		TenantID: entity.TenantIDFromContext(ctx),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return true, nil
+		}
+		return false, fmt.Errorf("query normalize transcripts: %w", err)
+	}
+
+	return enabled, nil
+}
+
+// SetNormalizeTranscripts saves the transcription normalization preference of the user
+func (r *TelegramSessionRepository) SetNormalizeTranscripts(ctx context.Context, userID int64, enabled bool) error {
+	err := r.queries.SetTelegramUserNormalizeTranscripts(ctx, sqlc.SetTelegramUserNormalizeTranscriptsParams{
+		UserID:               userID,
+		NormalizeTranscripts: enabled,
+		TenantID:             entity.TenantIDFromContext(ctx),
+	})
+	if err != nil {
+		return fmt.Errorf("save normalize transcripts: %w", err)
+	}
+
+	return nil
+}
+
+// NormalizeTranscripts reports whether the Telegram user of a session wants transcriptions
+// normalized; sessions not started from Telegram use the default
+func (r *TelegramSessionRepository) NormalizeTranscripts(ctx context.Context, sessionID string) (bool, error) {
+	parsedUUID, err := uuid.Parse(sessionID)
+	if err != nil {
+		return false, fmt.Errorf("invalid session ID format: %w", err)
+	}
+
+	enabled, err := r.queries.GetSessionNormalizeTranscripts(ctx, sqlc.GetSessionNormalizeTranscriptsParams{
+		SessionID: pgtype.UUID{
+			Bytes: parsedUUID,
+			Valid: true,
+		},
+		TenantID: entity.TenantIDFromContext(ctx),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return true, nil
+		}
+		return false, fmt.Errorf("query session normalize transcripts: %w", err)
+	}
+
+	return enabled, nil
+}
+
+// GetQuestionNumbering returns the question numbering chosen by the user;
+// an empty value means the user has not chosen one
+func (r *TelegramSessionRepository) GetQuestionNumbering(ctx context.Context, userID int64) (state.QuestionNumbering, error) {
+	numbering, err := r.queries.GetTelegramUserQuestionNumbering(ctx, sqlc.GetTelegramUserQuestionNumberingParams{
+		UserID:   userID,
+		TenantID: entity.TenantIDFromContext(ctx),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", nil
+		}
+		return "", fmt.Errorf("query question numbering: %w", err)
+	}
+
+	return state.QuestionNumbering(numbering.String), nil
+}
+
+// SetQuestionNumbering saves the question numbering chosen by the user
+func (r *TelegramSessionRepository) SetQuestionNumbering(ctx context.Context, userID int64, numbering state.QuestionNumbering) error {
+	err := r.queries.SetTelegramUserQuestionNumbering(ctx, sqlc.SetTelegramUserQuestionNumberingParams{
+		UserID: userID,
+		QuestionNumbering: pgtype.Text{
+			String: string(numbering),
+			Valid:  numbering != "",
+		},
+		TenantID: entity.TenantIDFromContext(ctx),
+	})
+	if err != nil {
+		return fmt.Errorf("save question numbering: %w", err)
+	}
+
+	return nil
+}
+
+// GetProgressHeader reports whether question messages of the user show the progress header;
+// nil means the user has not chosen
+func (r *TelegramSessionRepository) GetProgressHeader(ctx context.Context, userID int64) (*bool, error) {
+	show, err := r.queries.GetTelegramUserProgressHeader(ctx, sqlc.GetTelegramUserProgressHeaderParams{
+		UserID:   userID,
+		TenantID: entity.TenantIDFromContext(ctx),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("query progress header: %w", err)
+	}
+	if !show.Valid {
+		return nil, nil
+	}
+
+	return &show.Bool, nil
+}
+
+// SetProgressHeader saves whether question messages of the user show the progress header
+func (r *TelegramSessionRepository) SetProgressHeader(ctx context.Context, userID int64, show bool) error {
+	err := r.queries.SetTelegramUserProgressHeader(ctx, sqlc.SetTelegramUserProgressHeaderParams{
+		UserID:         userID,
+		ProgressHeader: pgtype.Bool{Bool: show, Valid: true},
+		TenantID:       entity.TenantIDFromContext(ctx),
+	})
+	if err != nil {
+		return fmt.Errorf("save progress header: %w", err)
+	}
+
+	return nil
+}
+
+// GetSummaryStyle returns the document style the user chose last; an empty value means
+// the user has not chosen one
+func (r *TelegramSessionRepository) GetSummaryStyle(ctx context.Context, userID int64) (entity.SummaryStyle, error) {
+	style, err := r.queries.GetTelegramUserSummaryStyle(ctx, sqlc.GetTelegramUserSummaryStyleParams{
+		UserID:   userID,
+		TenantID: entity.TenantIDFromContext(ctx),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", nil
+		}
+		return "", fmt.Errorf("query summary style: %w", err)
+	}
+
+	return entity.SummaryStyle(style.String), nil
+}
+
+// SetSummaryStyle saves the document style chosen by the user
+func (r *TelegramSessionRepository) SetSummaryStyle(ctx context.Context, userID int64, style entity.SummaryStyle) error {
+	err := r.queries.SetTelegramUserSummaryStyle(ctx, sqlc.SetTelegramUserSummaryStyleParams{
+		UserID: userID,
+		SummaryStyle: pgtype.Text{
+			String: string(style),
+			Valid:  style != "",
+		},
+		TenantID: entity.TenantIDFromContext(ctx),
+	})
+	if err != nil {
+		return fmt.Errorf("save summary style: %w", err)
+	}
+
+	return nil
+}
+
+// GetTimezone returns the IANA timezone chosen by the user; an empty value means
+// the user has not chosen one
+func (r *TelegramSessionRepository) GetTimezone(ctx context.Context, userID int64) (string, error) {
+	timezone, err := r.queries.GetTelegramUserTimezone(ctx, sqlc.GetTelegramUserTimezoneParams{
+		UserID:   userID,
+		TenantID: entity.TenantIDFromContext(ctx),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", nil
+		}
+		return "", fmt.Errorf("query timezone: %w", err)
+	}
+
+	return timezone.String, nil
+}
+
+// SetTimezone saves the IANA timezone chosen by the user
+func (r *TelegramSessionRepository) SetTimezone(ctx context.Context, userID int64, timezone string) error {
+	err := r.queries.SetTelegramUserTimezone(ctx, sqlc.SetTelegramUserTimezoneParams{
+		UserID: userID,
+		Timezone: pgtype.Text{
+			String: timezone,
+			Valid:  timezone != "",
+		},
+		TenantID: entity.TenantIDFromContext(ctx),
+	})
+	if err != nil {
+		return fmt.Errorf("save timezone: %w", err)
+	}
+
+	return nil
+}
+
+// MarkOnboarded records that the user has seen the onboarding tutorial;
+// it reports true only for the call that recorded it
+func (r *TelegramSessionRepository) MarkOnboarded(ctx context.Context, userID int64) (bool, error) {
+	affected, err := r.queries.MarkTelegramUserOnboarded(ctx, sqlc.MarkTelegramUserOnboardedParams{
+		UserID:   userID,
+		TenantID: entity.TenantIDFromContext(ctx),
+	})
+	if err != nil {
+		return false, fmt.Errorf("mark telegram user onboarded: %w", err)
+	}
+
+	return affected > 0, nil
+}
+
+// SaveInboxMessage stores the raw text of an incoming message in the tenant ctx is scoped to
+func (r *TelegramSessionRepository) SaveInboxMessage(ctx context.Context, message *state.InboxMessage) (*state.InboxMessage, error) {
+	sessID, err := uuid.Parse(message.SessionID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid session ID: %w", err)
+	}
+
+	params := sqlc.CreateTelegramInboxMessageParams{
+		TenantID: entity.TenantIDFromContext(ctx),
+		UserID:   message.UserID,
+		SessionID: pgtype.UUID{
+			Bytes: sessID,
+			Valid: true,
+		},
+		Text: message.Text,
+	}
+	if message.QuestionID != "" {
+		qID, err := uuid.Parse(message.QuestionID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid question ID: %w", err)
+		}
+		params.QuestionID = pgtype.UUID{Bytes: qID, Valid: true}
+	}
+
+	row, err := r.queries.CreateTelegramInboxMessage(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("create telegram inbox message: %w", err)
+	}
+
+	return toStateInboxMessage(&row), nil
+}
+
+// GetInboxMessage retrieves a stored message of the user
+func (r *TelegramSessionRepository) GetInboxMessage(ctx context.Context, userID int64, id string) (*state.InboxMessage, error) {
+	messageID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, fmt.Errorf("invalid inbox message ID: %w", err)
+	}
+
+	row, err := r.queries.GetTelegramInboxMessage(ctx, sqlc.GetTelegramInboxMessageParams{
+		ID: pgtype.UUID{
+			Bytes: messageID,
+			Valid: true,
+		},
+		UserID:   userID,
+		TenantID: entity.TenantIDFromContext(ctx),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("telegram inbox message not found: %s", id)
+		}
+		return nil, fmt.Errorf("query telegram inbox message: %w", err)
+	}
+
+	return toStateInboxMessage(&row), nil
+}
+
+// DeleteInboxMessage removes a stored message of the user
+func (r *TelegramSessionRepository) DeleteInboxMessage(ctx context.Context, userID int64, id string) error {
+	messageID, err := uuid.Parse(id)
+	if err != nil {
+		return fmt.Errorf("invalid inbox message ID: %w", err)
+	}
+
+	if err := r.queries.DeleteTelegramInboxMessage(ctx, sqlc.DeleteTelegramInboxMessageParams{
+		ID: pgtype.UUID{
+			Bytes: messageID,
+			Valid: true,
+		},
+		UserID:   userID,
+		TenantID: entity.TenantIDFromContext(ctx),
+	}); err != nil {
+		return fmt.Errorf("delete telegram inbox message: %w", err)
+	}
+
+	return nil
+}
+
+// toStateInboxMessage converts from sqlc TelegramInbox to state.InboxMessage
+func toStateInboxMessage(row *sqlc.TelegramInbox) *state.InboxMessage {
+	message := &state.InboxMessage{
+		ID:        uuid.UUID(row.ID.Bytes).String(),
+		UserID:    row.UserID,
+		SessionID: uuid.UUID(row.SessionID.Bytes).String(),
+		Text:      row.Text,
+		CreatedAt: row.CreatedAt.Time,
+	}
+	if row.QuestionID.Valid {
+		message.QuestionID = uuid.UUID(row.QuestionID.Bytes).String()
+	}
+
+	return message
+}
+
 // toStateTelegramSession converts from sqlc TelegramSession to state.TelegramSession
 func toStateTelegramSession(dbSession *sqlc.TelegramSession) *state.TelegramSession {
 	telegramSession := &state.TelegramSession{
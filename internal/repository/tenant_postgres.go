@@ -0,0 +1,174 @@
+package repository
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/repository/sqlc"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// TenantRepository defines the interface for tenant persistence.
+// API keys and bot tokens are only stored as hashes, lookups hash the given secret.
+type TenantRepository interface {
+	Create(ctx context.Context, tenant entity.Tenant, apiKey, botToken string) (*entity.Tenant, error)
+	Get(ctx context.Context, id string) (*entity.Tenant, error)
+	GetByAPIKey(ctx context.Context, apiKey string) (*entity.Tenant, error)
+	GetByBotToken(ctx context.Context, botToken string) (*entity.Tenant, error)
+	// GetByProjectID resolves the tenant owning a project regardless of the tenant ctx is scoped to
+	GetByProjectID(ctx context.Context, projectID string) (*entity.Tenant, error)
+	List(ctx context.Context) ([]*entity.Tenant, error)
+	UpdateSettings(ctx context.Context, id string, settings entity.TenantSettings) (*entity.Tenant, error)
+}
+
+var _ TenantRepository = &TenantPostgres{}
+
+// TenantPostgres implements TenantRepository using PostgreSQL
+type TenantPostgres struct {
+	db      *pgxpool.Pool
+	queries *sqlc.Queries
+}
+
+func NewTenantPostgres(db *pgxpool.Pool) *TenantPostgres {
+	return &TenantPostgres{
+		db:      db,
+		queries: sqlc.New(db),
+	}
+}
+
+func (r *TenantPostgres) Create(ctx context.Context, tenant entity.Tenant, apiKey, botToken string) (*entity.Tenant, error) {
+	settings, err := json.Marshal(tenant.Settings)
+	if err != nil {
+		return nil, fmt.Errorf("marshal tenant settings: %w", err)
+	}
+
+	dbTenant, err := r.queries.CreateTenant(ctx, sqlc.CreateTenantParams{
+		ID:           tenant.ID,
+		Name:         tenant.Name,
+		ApiKeyHash:   secretHash(apiKey),
+		BotTokenHash: secretHash(botToken),
+		Settings:     settings,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create tenant: %w", err)
+	}
+
+	return toEntityTenant(&dbTenant)
+}
+
+func (r *TenantPostgres) Get(ctx context.Context, id string) (*entity.Tenant, error) {
+	dbTenant, err := r.queries.GetTenant(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, entity.ErrTenantNotFound
+		}
+		return nil, fmt.Errorf("get tenant: %w", err)
+	}
+
+	return toEntityTenant(&dbTenant)
+}
+
+func (r *TenantPostgres) GetByAPIKey(ctx context.Context, apiKey string) (*entity.Tenant, error) {
+	if apiKey == "" {
+		return nil, entity.ErrTenantNotFound
+	}
+
+	dbTenant, err := r.queries.GetTenantByAPIKeyHash(ctx, secretHash(apiKey))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, entity.ErrTenantNotFound
+		}
+		return nil, fmt.Errorf("get tenant by API key: %w", err)
+	}
+
+	return toEntityTenant(&dbTenant)
+}
+
+func (r *TenantPostgres) GetByBotToken(ctx context.Context, botToken string) (*entity.Tenant, error) {
+	if botToken == "" {
+		return nil, entity.ErrTenantNotFound
+	}
+
+	dbTenant, err := r.queries.GetTenantByBotTokenHash(ctx, secretHash(botToken))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, entity.ErrTenantNotFound
+		}
+		return nil, fmt.Errorf("get tenant by bot token: %w", err)
+	}
+
+	return toEntityTenant(&dbTenant)
+}
+
+func (r *TenantPostgres) GetByProjectID(ctx context.Context, projectID string) (*entity.Tenant, error) {
+	projID, err := uuid.Parse(projectID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid project ID: %w", err)
+	}
+
+	dbTenant, err := r.queries.GetProjectTenant(ctx, pgtype.UUID{Bytes: projID, Valid: true})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, entity.ErrProjectNotFound
+		}
+		return nil, fmt.Errorf("get project tenant: %w", err)
+	}
+
+	return toEntityTenant(&dbTenant)
+}
+
+func (r *TenantPostgres) List(ctx context.Context) ([]*entity.Tenant, error) {
+	dbTenants, err := r.queries.ListTenants(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("list tenants: %w", err)
+	}
+
+	tenants := make([]*entity.Tenant, 0, len(dbTenants))
+	for _, dbTenant := range dbTenants {
+		tenant, err := toEntityTenant(&dbTenant)
+		if err != nil {
+			return nil, err
+		}
+		tenants = append(tenants, tenant)
+	}
+
+	return tenants, nil
+}
+
+func (r *TenantPostgres) UpdateSettings(ctx context.Context, id string, settings entity.TenantSettings) (*entity.Tenant, error) {
+	settingsJSON, err := json.Marshal(settings)
+	if err != nil {
+		return nil, fmt.Errorf("marshal tenant settings: %w", err)
+	}
+
+	dbTenant, err := r.queries.UpdateTenantSettings(ctx, sqlc.UpdateTenantSettingsParams{
+		ID:       id,
+		Settings: settingsJSON,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, entity.ErrTenantNotFound
+		}
+		return nil, fmt.Errorf("update tenant settings: %w", err)
+	}
+
+	return toEntityTenant(&dbTenant)
+}
+
+// secretHash returns the stored form of an API key or bot token; empty secrets are stored as NULL
+func secretHash(secret string) pgtype.Text {
+	if secret == "" {
+		return pgtype.Text{}
+	}
+
+	sum := sha256.Sum256([]byte(secret))
+	return pgtype.Text{String: hex.EncodeToString(sum[:]), Valid: true}
+}
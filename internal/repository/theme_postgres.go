@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/repository/sqlc"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ThemeRepository defines the interface for document theme persistence.
+// Themes belong to the tenant ctx is scoped to; asset files are stored by the caller.
+type ThemeRepository interface {
+	Create(ctx context.Context, theme entity.DocumentTheme) (*entity.DocumentTheme, error)
+	Get(ctx context.Context, id string) (*entity.DocumentTheme, error)
+	List(ctx context.Context) ([]*entity.DocumentTheme, error)
+	Delete(ctx context.Context, id string) error
+}
+
+var _ ThemeRepository = &ThemePostgres{}
+
+// ThemePostgres implements ThemeRepository using PostgreSQL
+type ThemePostgres struct {
+	db      *pgxpool.Pool
+	queries *sqlc.Queries
+}
+
+func NewThemePostgres(db *pgxpool.Pool) *ThemePostgres {
+	return &ThemePostgres{
+		db:      db,
+		queries: sqlc.New(db),
+	}
+}
+
+func (r *ThemePostgres) Create(ctx context.Context, theme entity.DocumentTheme) (*entity.DocumentTheme, error) {
+	themeID, err := uuid.Parse(theme.ID)
+	if err != nil {
+		return nil, fmt.Errorf("parse theme ID: %w", err)
+	}
+
+	dbTheme, err := r.queries.CreateDocumentTheme(ctx, sqlc.CreateDocumentThemeParams{
+		ID:           pgtype.UUID{Bytes: themeID, Valid: true},
+		TenantID:     entity.TenantIDFromContext(ctx),
+		Name:         theme.Name,
+		PrimaryColor: pgtype.Text{String: theme.PrimaryColor, Valid: theme.PrimaryColor != ""},
+		HeaderText:   pgtype.Text{String: theme.HeaderText, Valid: theme.HeaderText != ""},
+		FooterText:   pgtype.Text{String: theme.FooterText, Valid: theme.FooterText != ""},
+		FontName:     pgtype.Text{String: theme.FontName, Valid: theme.FontName != ""},
+		LogoKey:      pgtype.Text{String: theme.LogoKey, Valid: theme.LogoKey != ""},
+		FontKey:      pgtype.Text{String: theme.FontKey, Valid: theme.FontKey != ""},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create document theme: %w", err)
+	}
+
+	return toEntityDocumentTheme(&dbTheme), nil
+}
+
+func (r *ThemePostgres) Get(ctx context.Context, id string) (*entity.DocumentTheme, error) {
+	themeID, err := uuid.Parse(id)
+	if err != nil {
+		return nil, entity.ErrThemeNotFound
+	}
+
+	dbTheme, err := r.queries.GetDocumentTheme(ctx, sqlc.GetDocumentThemeParams{
+		ID:       pgtype.UUID{Bytes: themeID, Valid: true},
+		TenantID: entity.TenantIDFromContext(ctx),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, entity.ErrThemeNotFound
+		}
+		return nil, fmt.Errorf("get document theme: %w", err)
+	}
+
+	return toEntityDocumentTheme(&dbTheme), nil
+}
+
+func (r *ThemePostgres) List(ctx context.Context) ([]*entity.DocumentTheme, error) {
+	dbThemes, err := r.queries.ListDocumentThemes(ctx, entity.TenantIDFromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("list document themes: %w", err)
+	}
+
+	themes := make([]*entity.DocumentTheme, 0, len(dbThemes))
+	for _, dbTheme := range dbThemes {
+		themes = append(themes, toEntityDocumentTheme(&dbTheme))
+	}
+
+	return themes, nil
+}
+
+func (r *ThemePostgres) Delete(ctx context.Context, id string) error {
+	themeID, err := uuid.Parse(id)
+	if err != nil {
+		return entity.ErrThemeNotFound
+	}
+
+	rows, err := r.queries.DeleteDocumentTheme(ctx, sqlc.DeleteDocumentThemeParams{
+		ID:       pgtype.UUID{Bytes: themeID, Valid: true},
+		TenantID: entity.TenantIDFromContext(ctx),
+	})
+	if err != nil {
+		return fmt.Errorf("delete document theme: %w", err)
+	}
+	if rows == 0 {
+		return entity.ErrThemeNotFound
+	}
+
+	return nil
+}
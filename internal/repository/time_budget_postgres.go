@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/repository/sqlc"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// TimeBudgetRepository defines the interface for interview time budgets persistence
+type TimeBudgetRepository interface {
+	StartBudget(ctx context.Context, sessionID string, budget time.Duration) (*entity.SessionTimeBudget, error)
+	GetBudget(ctx context.Context, sessionID string) (*entity.SessionTimeBudget, error)
+	MarkWarned(ctx context.Context, sessionID string) (bool, error)
+}
+
+var _ TimeBudgetRepository = &TimeBudgetPostgres{}
+
+// TimeBudgetPostgres implements TimeBudgetRepository using PostgreSQL
+type TimeBudgetPostgres struct {
+	db      *pgxpool.Pool
+	queries *sqlc.Queries
+}
+
+func NewTimeBudgetPostgres(db *pgxpool.Pool) *TimeBudgetPostgres {
+	return &TimeBudgetPostgres{
+		db:      db,
+		queries: sqlc.New(db),
+	}
+}
+
+// StartBudget sets the time budget of the session and starts its clock unless it already runs
+func (r *TimeBudgetPostgres) StartBudget(ctx context.Context, sessionID string, budget time.Duration) (*entity.SessionTimeBudget, error) {
+	sessID, err := uuid.Parse(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid session ID: %w", err)
+	}
+
+	dbBudget, err := r.queries.StartSessionTimeBudget(ctx, sqlc.StartSessionTimeBudgetParams{
+		SessionID:     pgtype.UUID{Bytes: sessID, Valid: true},
+		BudgetSeconds: int32(budget / time.Second),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("start session time budget: %w", err)
+	}
+
+	return toEntitySessionTimeBudget(&dbBudget), nil
+}
+
+func (r *TimeBudgetPostgres) GetBudget(ctx context.Context, sessionID string) (*entity.SessionTimeBudget, error) {
+	sessID, err := uuid.Parse(sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid session ID: %w", err)
+	}
+
+	dbBudget, err := r.queries.GetSessionTimeBudget(ctx, pgtype.UUID{Bytes: sessID, Valid: true})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, entity.ErrTimeBudgetNotFound
+		}
+		return nil, fmt.Errorf("get session time budget: %w", err)
+	}
+
+	return toEntitySessionTimeBudget(&dbBudget), nil
+}
+
+// MarkWarned records the time budget warning and reports whether it was not recorded before
+func (r *TimeBudgetPostgres) MarkWarned(ctx context.Context, sessionID string) (bool, error) {
+	sessID, err := uuid.Parse(sessionID)
+	if err != nil {
+		return false, fmt.Errorf("invalid session ID: %w", err)
+	}
+
+	rows, err := r.queries.MarkSessionTimeBudgetWarned(ctx, pgtype.UUID{Bytes: sessID, Valid: true})
+	if err != nil {
+		return false, fmt.Errorf("mark session time budget warned: %w", err)
+	}
+
+	return rows > 0, nil
+}
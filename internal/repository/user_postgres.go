@@ -0,0 +1,144 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/repository/sqlc"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// UserRepository defines the interface for user persistence.
+// API keys are only stored as hashes, lookups hash the given key.
+type UserRepository interface {
+	Create(ctx context.Context, user entity.User, apiKey string) (*entity.User, error)
+	// GetByAPIKey resolves a user regardless of the tenant ctx is scoped to
+	GetByAPIKey(ctx context.Context, apiKey string) (*entity.User, error)
+	// EnsureTelegramUser returns the user of the Telegram user in the tenant of ctx, creating it on first contact
+	EnsureTelegramUser(ctx context.Context, telegramUserID int64, name string) (*entity.User, error)
+	List(ctx context.Context) ([]*entity.User, error)
+	Delete(ctx context.Context, id string) error
+}
+
+var _ UserRepository = &UserPostgres{}
+
+// UserPostgres implements UserRepository using PostgreSQL
+type UserPostgres struct {
+	db      *pgxpool.Pool
+	queries *sqlc.Queries
+}
+
+func NewUserPostgres(db *pgxpool.Pool) *UserPostgres {
+	return &UserPostgres{
+		db:      db,
+		queries: sqlc.New(db),
+	}
+}
+
+func (r *UserPostgres) Create(ctx context.Context, user entity.User, apiKey string) (*entity.User, error) {
+	userID, err := uuid.Parse(user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	params := sqlc.CreateUserParams{
+		ID:         pgtype.UUID{Bytes: userID, Valid: true},
+		TenantID:   user.TenantID,
+		Name:       user.Name,
+		ApiKeyHash: secretHash(apiKey),
+	}
+	if user.TelegramUserID != nil {
+		params.TelegramUserID = pgtype.Int8{Int64: *user.TelegramUserID, Valid: true}
+	}
+
+	dbUser, err := r.queries.CreateUser(ctx, params)
+	if err != nil {
+		return nil, fmt.Errorf("create user: %w", err)
+	}
+
+	return toEntityUser(&dbUser), nil
+}
+
+func (r *UserPostgres) GetByAPIKey(ctx context.Context, apiKey string) (*entity.User, error) {
+	if apiKey == "" {
+		return nil, entity.ErrUserNotFound
+	}
+
+	dbUser, err := r.queries.GetUserByAPIKeyHash(ctx, secretHash(apiKey))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, entity.ErrUserNotFound
+		}
+		return nil, fmt.Errorf("get user by API key: %w", err)
+	}
+
+	return toEntityUser(&dbUser), nil
+}
+
+func (r *UserPostgres) EnsureTelegramUser(ctx context.Context, telegramUserID int64, name string) (*entity.User, error) {
+	dbUser, err := r.queries.EnsureTelegramUser(ctx, sqlc.EnsureTelegramUserParams{
+		ID:             pgtype.UUID{Bytes: uuid.New(), Valid: true},
+		TenantID:       entity.TenantIDFromContext(ctx),
+		Name:           name,
+		TelegramUserID: pgtype.Int8{Int64: telegramUserID, Valid: true},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("ensure telegram user: %w", err)
+	}
+
+	return toEntityUser(&dbUser), nil
+}
+
+func (r *UserPostgres) List(ctx context.Context) ([]*entity.User, error) {
+	dbUsers, err := r.queries.ListUsers(ctx, entity.TenantIDFromContext(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("list users: %w", err)
+	}
+
+	users := make([]*entity.User, 0, len(dbUsers))
+	for _, dbUser := range dbUsers {
+		users = append(users, toEntityUser(&dbUser))
+	}
+
+	return users, nil
+}
+
+func (r *UserPostgres) Delete(ctx context.Context, id string) error {
+	userID, err := uuid.Parse(id)
+	if err != nil {
+		return entity.ErrUserNotFound
+	}
+
+	deleted, err := r.queries.DeleteUser(ctx, sqlc.DeleteUserParams{
+		ID:       pgtype.UUID{Bytes: userID, Valid: true},
+		TenantID: entity.TenantIDFromContext(ctx),
+	})
+	if err != nil {
+		return fmt.Errorf("delete user: %w", err)
+	}
+	if deleted == 0 {
+		return entity.ErrUserNotFound
+	}
+
+	return nil
+}
+
+// ownerFilter returns the owner the projects and sessions of ctx are limited to; NULL when ctx sees the whole tenant
+func ownerFilter(ctx context.Context) pgtype.UUID {
+	ownerID := entity.OwnerIDFromContext(ctx)
+	if ownerID == nil {
+		return pgtype.UUID{}
+	}
+
+	id, err := uuid.Parse(*ownerID)
+	if err != nil {
+		return pgtype.UUID{}
+	}
+
+	return pgtype.UUID{Bytes: id, Valid: true}
+}
@@ -0,0 +1,153 @@
+package retention
+
+import (
+	"context"
+	"time"
+
+	"github.com/futig/agent-backend/internal/config"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// OperationPurger removes tracked async operations
+type OperationPurger interface {
+	PurgeOperations(ctx context.Context, before time.Time) (int, error)
+}
+
+// CallbackDeliveryPurger removes settled callback events of the outbox
+type CallbackDeliveryPurger interface {
+	PurgeCallbackDeliveries(ctx context.Context, before time.Time) (int, error)
+}
+
+// DemoSessionPurger removes sandbox demo sessions
+type DemoSessionPurger interface {
+	PurgeDemoSessions(ctx context.Context, before time.Time) (int, error)
+}
+
+// PendingVoiceAnswerPurger removes voice answers queued while speech recognition was unavailable
+type PendingVoiceAnswerPurger interface {
+	PurgePendingVoiceAnswers(ctx context.Context, before time.Time) (int, error)
+}
+
+// IncidentPurger removes incidents recorded for errors
+type IncidentPurger interface {
+	PurgeIncidents(ctx context.Context, before time.Time) (int, error)
+}
+
+// ContentPurger removes the session content of every tenant past the retention period of the tenant
+type ContentPurger interface {
+	PurgeExpiredContent(ctx context.Context, now time.Time) (int, error)
+}
+
+// Cleaner periodically removes records older than the retention period
+type Cleaner struct {
+	name      string
+	purge     func(ctx context.Context, before time.Time) (int, error)
+	retention time.Duration
+	interval  time.Duration
+	logger    *zap.Logger
+}
+
+// New creates a cleaner purging expired operations every cfg.CleanupInterval
+func New(cfg config.OperationsConfig, purger OperationPurger, logger *zap.Logger) *Cleaner {
+	return &Cleaner{
+		name:      "operations",
+		purge:     purger.PurgeOperations,
+		retention: cfg.Retention,
+		interval:  cfg.CleanupInterval,
+		logger:    logger,
+	}
+}
+
+// NewCallbackDeliveries creates a cleaner purging delivered and failed callback events every cfg.CleanupInterval
+func NewCallbackDeliveries(cfg config.CallbackOutboxConfig, purger CallbackDeliveryPurger, logger *zap.Logger) *Cleaner {
+	return &Cleaner{
+		name:      "callback deliveries",
+		purge:     purger.PurgeCallbackDeliveries,
+		retention: cfg.Retention,
+		interval:  cfg.CleanupInterval,
+		logger:    logger,
+	}
+}
+
+// NewDemoSessions creates a cleaner purging demo sessions older than cfg.SessionTTL
+func NewDemoSessions(cfg config.DemoConfig, purger DemoSessionPurger, logger *zap.Logger) *Cleaner {
+	return &Cleaner{
+		name:      "demo sessions",
+		purge:     purger.PurgeDemoSessions,
+		retention: cfg.SessionTTL,
+		interval:  cfg.CleanupInterval,
+		logger:    logger,
+	}
+}
+
+// NewPendingVoiceAnswers creates a cleaner dropping queued voice answers older than cfg.MaxAge
+func NewPendingVoiceAnswers(cfg config.VoiceQueueConfig, purger PendingVoiceAnswerPurger, logger *zap.Logger) *Cleaner {
+	return &Cleaner{
+		name:      "pending voice answers",
+		purge:     purger.PurgePendingVoiceAnswers,
+		retention: cfg.MaxAge,
+		interval:  cfg.PollInterval,
+		logger:    logger,
+	}
+}
+
+// NewIncidents creates a cleaner purging incidents older than cfg.Retention
+func NewIncidents(cfg config.IncidentsConfig, purger IncidentPurger, logger *zap.Logger) *Cleaner {
+	return &Cleaner{
+		name:      "incidents",
+		purge:     purger.PurgeIncidents,
+		retention: cfg.Retention,
+		interval:  cfg.CleanupInterval,
+		logger:    logger,
+	}
+}
+
+// NewContent creates a cleaner purging the content of inactive sessions every cfg.CleanupInterval.
+// Retention periods differ per tenant, so the purger resolves them and is passed the current time
+func NewContent(cfg config.ContentRetentionConfig, purger ContentPurger, logger *zap.Logger) *Cleaner {
+	return &Cleaner{
+		name:     "session content",
+		purge:    purger.PurgeExpiredContent,
+		interval: cfg.CleanupInterval,
+		logger:   logger,
+	}
+}
+
+// Run purges expired records until ctx is cancelled
+func (c *Cleaner) Run(ctx context.Context) {
+	ctx = ctxzap.ToContext(ctx, c.logger.With(
+		zap.String("component", "retention"),
+		zap.String("target", c.name),
+	))
+	ctxzap.Info(ctx, "cleaner started",
+		zap.Duration("retention", c.retention),
+		zap.Duration("interval", c.interval),
+	)
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		c.tick(ctx)
+
+		select {
+		case <-ctx.Done():
+			ctxzap.Info(ctx, "cleaner stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (c *Cleaner) tick(ctx context.Context) {
+	deleted, err := c.purge(ctx, time.Now().UTC().Add(-c.retention))
+	if err != nil {
+		ctxzap.Error(ctx, "failed to purge expired records", zap.Error(err))
+		return
+	}
+
+	if deleted > 0 {
+		ctxzap.Info(ctx, "expired records purged", zap.Int("count", deleted))
+	}
+}
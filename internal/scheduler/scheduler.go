@@ -0,0 +1,63 @@
+package scheduler
+
+import (
+	"context"
+	"time"
+
+	"github.com/futig/agent-backend/internal/config"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// SessionStarter starts check-in sessions of due project schedules
+type SessionStarter interface {
+	StartDueScheduledSessions(ctx context.Context, now time.Time) (int, error)
+}
+
+// Scheduler periodically starts scheduled check-in sessions
+type Scheduler struct {
+	starter  SessionStarter
+	interval time.Duration
+	logger   *zap.Logger
+}
+
+// New creates a scheduler polling due schedules every cfg.PollInterval
+func New(cfg config.SchedulerConfig, starter SessionStarter, logger *zap.Logger) *Scheduler {
+	return &Scheduler{
+		starter:  starter,
+		interval: cfg.PollInterval,
+		logger:   logger,
+	}
+}
+
+// Run polls due schedules until ctx is cancelled
+func (s *Scheduler) Run(ctx context.Context) {
+	ctx = ctxzap.ToContext(ctx, s.logger.With(zap.String("component", "scheduler")))
+	ctxzap.Info(ctx, "scheduler started", zap.Duration("interval", s.interval))
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.tick(ctx)
+
+		select {
+		case <-ctx.Done():
+			ctxzap.Info(ctx, "scheduler stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Scheduler) tick(ctx context.Context) {
+	started, err := s.starter.StartDueScheduledSessions(ctx, time.Now().UTC())
+	if err != nil {
+		ctxzap.Error(ctx, "failed to start scheduled sessions", zap.Error(err))
+		return
+	}
+
+	if started > 0 {
+		ctxzap.Info(ctx, "scheduled sessions started", zap.Int("count", started))
+	}
+}
@@ -2,19 +2,32 @@ package bot
 
 import (
 	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"net/http"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/futig/agent-backend/internal/config"
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/pkg/logger"
+	"github.com/futig/agent-backend/internal/pkg/metrics"
+	"github.com/futig/agent-backend/internal/pkg/tracing"
 	"github.com/futig/agent-backend/internal/telegram/handlers"
 	"github.com/futig/agent-backend/internal/telegram/keyboard"
 	"github.com/futig/agent-backend/internal/telegram/middleware"
 	"github.com/futig/agent-backend/internal/telegram/render"
 	"github.com/futig/agent-backend/internal/telegram/state"
+	"github.com/futig/agent-backend/internal/telegram/store"
 	"github.com/futig/agent-backend/internal/usecase/project"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
 
@@ -22,35 +35,64 @@ import (
 type Bot struct {
 	api          *tgbotapi.BotAPI
 	cfg          *config.TelegramConfig
+	tenant       *entity.Tenant
 	stateManager *state.Manager
+	store        store.Store
 	handlers     map[string]handlers.Handler
 	sessionUC    handlers.SessionUsecase
 	projectUC    *project.ProjectUsecase
+	linkUC       handlers.AccountLinkUsecase
+	userUC       handlers.UserUsecase
 	contextQ     []string
 	keyboard     *keyboard.Builder
 	logger       *zap.Logger
 	loggingMW    *middleware.LoggingMiddleware
 	recoveryMW   *middleware.RecoveryMiddleware
-	rateLimitMW  *middleware.RateLimiterMiddleware
+	rateLimitMW  middleware.RateLimiter
+	dedupMW      *middleware.CallbackDedupMiddleware
+	health       *serviceHealth
+	mediaGroups  *mediaGroupCollector
+	takeovers    *takeovers
+	calls        *handlerCalls
+	users        sync.Map // user of each Telegram user ID, cached as users never change once created
 	updatesChan  tgbotapi.UpdatesChannel
-	stopChan     chan struct{}
-	wg           sync.WaitGroup
+	webhookChan  chan tgbotapi.Update
+	// webhookSecret is the token Telegram sends in the X-Telegram-Bot-Api-Secret-Token header
+	webhookSecret string
+	stopChan      chan struct{}
+	wg            sync.WaitGroup
 }
 
 // New creates a new Telegram bot
 func New(
 	cfg *config.TelegramConfig,
+	tenant *entity.Tenant,
 	stateManager *state.Manager,
+	store store.Store,
 	sessionUC handlers.SessionUsecase,
 	projectUC *project.ProjectUsecase,
+	linkUC handlers.AccountLinkUsecase,
+	userUC handlers.UserUsecase,
 	contextQuestions []string,
 	logger *zap.Logger,
 ) (*Bot, error) {
 	// Create bot API instance
-	api, err := tgbotapi.NewBotAPI(cfg.BotToken)
+	apiEndpoint := tgbotapi.APIEndpoint
+	if cfg.APIEndpoint != "" {
+		apiEndpoint = cfg.APIEndpoint
+	}
+	api, err := tgbotapi.NewBotAPIWithAPIEndpoint(cfg.BotToken, apiEndpoint)
 	if err != nil {
 		return nil, fmt.Errorf("create bot API: %w", err)
 	}
+	if cfg.FileEndpoint != "" {
+		handlers.SetFileEndpoint(cfg.FileEndpoint)
+	}
+
+	questionButtons, err := keyboard.NewQuestionButtons(cfg.QuestionButtons.Disabled, cfg.QuestionButtons.DisabledByType())
+	if err != nil {
+		return nil, fmt.Errorf("question buttons: %w", err)
+	}
 
 	// Set debug mode in development
 	api.Debug = false
@@ -61,27 +103,34 @@ func New(
 	)
 
 	bot := &Bot{
-		api:          api,
-		cfg:          cfg,
-		stateManager: stateManager,
-		sessionUC:    sessionUC,
-		projectUC:    projectUC,
-		contextQ:     contextQuestions,
-		keyboard:     keyboard.NewBuilder(),
-		logger:       logger,
-		handlers:     make(map[string]handlers.Handler),
-		stopChan:     make(chan struct{}),
+		api:           api,
+		cfg:           cfg,
+		tenant:        tenant,
+		stateManager:  stateManager,
+		store:         store,
+		sessionUC:     sessionUC,
+		projectUC:     projectUC,
+		linkUC:        linkUC,
+		userUC:        userUC,
+		contextQ:      contextQuestions,
+		keyboard:      keyboard.NewBuilder().WithQuestionButtons(questionButtons),
+		logger:        logger,
+		handlers:      make(map[string]handlers.Handler),
+		takeovers:     newTakeovers(),
+		calls:         newHandlerCalls(),
+		health:        newServiceHealth(),
+		webhookChan:   make(chan tgbotapi.Update, api.Buffer),
+		webhookSecret: webhookSecret(cfg),
+		stopChan:      make(chan struct{}),
 	}
 
 	// Initialize middleware
 	bot.loggingMW = middleware.NewLoggingMiddleware(logger)
 	bot.recoveryMW = middleware.NewRecoveryMiddleware(logger, api)
-	bot.rateLimitMW = middleware.NewRateLimiterMiddleware(
-		cfg.RateLimitPerMinute,
-		cfg.RateLimitBurst,
-		logger,
-		api,
-	)
+	bot.rateLimitMW = bot.newRateLimiter(api)
+	bot.dedupMW = middleware.NewCallbackDedupMiddleware(store, cfg.Store.CallbackDedupWindow, logger, api)
+
+	bot.mediaGroups = newMediaGroupCollector(cfg.MediaGroupWindow, bot.handleMediaGroup)
 
 	// Register handlers (will be implemented)
 	// bot.registerHandlers()
@@ -97,6 +146,11 @@ func (b *Bot) Start(ctx context.Context) error {
 	u := tgbotapi.NewUpdate(0)
 	u.Timeout = b.cfg.UpdateTimeout
 
+	// getUpdates is rejected while a webhook is set, e.g. after the bot ran in webhook mode
+	if _, err := b.api.Request(tgbotapi.DeleteWebhookConfig{}); err != nil {
+		b.logger.Warn("failed to delete telegram webhook", zap.Error(err))
+	}
+
 	// Get updates channel
 	updates := b.api.GetUpdatesChan(u)
 	b.updatesChan = updates
@@ -111,6 +165,65 @@ func (b *Bot) Start(ctx context.Context) error {
 	return nil
 }
 
+// StartWebhook registers the webhook URL with Telegram and processes updates passed to ServeHTTP
+func (b *Bot) StartWebhook(ctx context.Context, webhookURL string) error {
+	b.logger.Info("starting telegram bot with webhook",
+		zap.String("webhook_url", webhookURL),
+	)
+
+	// WebhookConfig of the library has no secret token, so the method is called directly
+	params := tgbotapi.Params{
+		"url":          webhookURL,
+		"secret_token": b.webhookSecret,
+	}
+	if _, err := b.api.MakeRequest("setWebhook", params); err != nil {
+		return fmt.Errorf("set webhook: %w", err)
+	}
+
+	b.updatesChan = b.webhookChan
+
+	// Add logger to context for processUpdates
+	ctx = ctxzap.ToContext(ctx, b.logger)
+
+	// Start update processing loop
+	go b.processUpdates(ctx)
+
+	b.logger.Info("telegram bot started successfully")
+	return nil
+}
+
+// ServeHTTP accepts webhook updates of the bot
+func (b *Bot) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	token := r.Header.Get("X-Telegram-Bot-Api-Secret-Token")
+	if subtle.ConstantTimeCompare([]byte(token), []byte(b.webhookSecret)) != 1 {
+		b.logger.Warn("webhook update with invalid secret token", zap.String("remote_addr", r.RemoteAddr))
+		http.Error(w, "invalid secret token", http.StatusUnauthorized)
+		return
+	}
+
+	update, err := b.api.HandleUpdate(r)
+	if err != nil {
+		b.logger.Warn("invalid webhook update", zap.Error(err))
+		http.Error(w, "invalid update", http.StatusBadRequest)
+		return
+	}
+
+	select {
+	case b.webhookChan <- *update:
+	case <-b.stopChan:
+		http.Error(w, "bot is stopping", http.StatusServiceUnavailable)
+	}
+}
+
+// webhookSecret returns the configured webhook secret token or derives a stable one from the bot token
+func webhookSecret(cfg *config.TelegramConfig) string {
+	if cfg.WebhookSecret != "" {
+		return cfg.WebhookSecret
+	}
+	sum := sha256.Sum256([]byte("webhook:" + cfg.BotToken))
+	return hex.EncodeToString(sum[:16])
+}
+
 // Stop stops the bot gracefully with timeout
 func (b *Bot) Stop() error {
 	b.logger.Info("stopping telegram bot")
@@ -165,23 +278,122 @@ func (b *Bot) processUpdates(ctx context.Context) {
 
 // handleUpdateWithMiddleware processes update through middleware chain
 func (b *Bot) handleUpdateWithMiddleware(update tgbotapi.Update) {
-	// Rate limiter middleware (first to check)
+	metrics.TelegramUpdates.WithLabelValues(b.api.Self.UserName, updateType(update)).Inc()
+
+	// Rate limiter middleware (first to check); updates it drops never reach next
+	allowed := false
 	b.rateLimitMW.Handle(update, func(u tgbotapi.Update) {
-		// Logging middleware
-		b.loggingMW.Handle(u, func(u2 tgbotapi.Update) {
-			// Recovery middleware
-			b.recoveryMW.Handle(u2, func(u3 tgbotapi.Update) {
-				// Actual handler
-				b.handleUpdate(u3)
+		allowed = true
+		// Repeated button presses are dropped before they reach the handlers
+		b.dedupMW.Handle(u, func(u1 tgbotapi.Update) {
+			// Logging middleware
+			b.loggingMW.Handle(u1, func(u2 tgbotapi.Update) {
+				// Recovery middleware
+				b.recoveryMW.Handle(u2, func(u3 tgbotapi.Update) {
+					// Actual handler
+					b.handleUpdate(u3)
+				})
 			})
 		})
 	})
+	if !allowed {
+		metrics.RateLimitRejections.WithLabelValues("telegram").Inc()
+	}
+}
+
+// updateType names the kind of an update for the update metrics
+func updateType(update tgbotapi.Update) string {
+	switch {
+	case update.Message != nil:
+		return "message"
+	case update.CallbackQuery != nil:
+		return "callback_query"
+	case update.EditedMessage != nil:
+		return "edited_message"
+	default:
+		return "other"
+	}
+}
+
+// updateContext creates the context of an update with the logger and the tenant of the bot;
+// chat users wait for the replies, so their LLM calls go ahead of batch work. Each update gets
+// its own correlation ID, so the errors it logs are found by the incident code shown to the user
+func (b *Bot) updateContext() context.Context {
+	correlationID := entity.NewCorrelationID()
+	logger := b.logger.With(zap.String(entity.CorrelationIDField, correlationID))
+
+	ctx := entity.WithLLMPriority(ctxzap.ToContext(context.Background(), logger), entity.LLMPriorityInteractive)
+	ctx = entity.WithCorrelationID(ctx, correlationID)
+	return entity.WithTenant(ctx, b.tenant)
+}
+
+// updateSpan starts the root span of handling an update, the LLM and other calls it makes are
+// traced as its children, and adds the trace ID to the logger of the update
+func (b *Bot) updateSpan(ctx context.Context, kind string) (context.Context, trace.Span) {
+	ctx, span := tracing.StartKind(ctx, trace.SpanKindConsumer, "telegram "+kind,
+		attribute.String("telegram.bot", b.api.Self.UserName),
+	)
+	if traceID := tracing.TraceID(ctx); traceID != "" {
+		ctx = logger.AddFields(ctx, zap.String("trace_id", traceID))
+	}
+	return ctx, span
+}
+
+// userLocationContext sets the timezone of the user on ctx, so the dates shown to them are local
+func (b *Bot) userLocationContext(ctx context.Context, userID int64) context.Context {
+	loc, err := b.stateManager.GetLocation(ctx, userID)
+	if err != nil {
+		ctxzap.Warn(ctx, "failed to get user timezone",
+			zap.Error(err),
+			zap.Int64("user_id", userID),
+		)
+	}
+	return entity.WithLocation(ctx, loc)
+}
+
+// userContext scopes ctx to the projects and sessions of the Telegram user; support operators
+// stay unscoped, so they can take over the sessions of any user of the bot
+func (b *Bot) userContext(ctx context.Context, from *tgbotapi.User) context.Context {
+	if b.isAdmin(from.ID) {
+		return ctx
+	}
+
+	if user, ok := b.users.Load(from.ID); ok {
+		return entity.WithUser(ctx, user.(*entity.User))
+	}
+
+	user, err := b.userUC.EnsureTelegramUser(ctx, from.ID, telegramUserName(from))
+	if err != nil {
+		ctxzap.Warn(ctx, "failed to resolve user",
+			zap.Error(err),
+			zap.Int64("user_id", from.ID),
+		)
+		return ctx
+	}
+	b.users.Store(from.ID, user)
+
+	return entity.WithUser(ctx, user)
+}
+
+// telegramUserName is the name a new user of a Telegram user gets
+func telegramUserName(from *tgbotapi.User) string {
+	if from.UserName != "" {
+		return "@" + from.UserName
+	}
+	return strings.TrimSpace(from.FirstName + " " + from.LastName)
 }
 
 // handleUpdate routes update to appropriate handler
 func (b *Bot) handleUpdate(update tgbotapi.Update) {
-	// Create context with logger
-	ctx := ctxzap.ToContext(context.Background(), b.logger)
+	ctx, span := b.updateSpan(b.updateContext(), updateType(update))
+	defer span.End()
+
+	if from := update.SentFrom(); from != nil {
+		span.SetAttributes(attribute.Int64("telegram.user_id", from.ID))
+		ctx = entity.WithUsageSubject(ctx, entity.TelegramUsageSubject(from.ID))
+		ctx = b.userLocationContext(ctx, from.ID)
+		ctx = b.userContext(ctx, from)
+	}
 
 	// Handle callback queries
 	if update.CallbackQuery != nil {
@@ -200,19 +412,102 @@ func (b *Bot) handleUpdate(update tgbotapi.Update) {
 func (b *Bot) handleMessage(ctx context.Context, message *tgbotapi.Message) {
 	// Handle commands
 	if message.IsCommand() {
+		ctx, release := b.handlerContext(ctx, message.From.ID, handlerStateCommand)
+		defer release()
 		b.handleCommand(ctx, message)
 		return
 	}
 
+	// Messages of a support operator attached to a user's session answer for that user
+	if userID, ok := b.takeovers.target(message.From.ID); ok {
+		b.handleOperatorMessage(ctx, message, userID)
+		return
+	}
+
+	// Album items are collected and handled together once the album is complete
+	if message.MediaGroupID != "" {
+		b.mediaGroups.add(message)
+		return
+	}
+
+	// Create normalized message
+	msg := &handlers.Message{
+		ChatID:    message.Chat.ID,
+		UserID:    message.From.ID,
+		MessageID: message.MessageID,
+		Text:      message.Text,
+		Voice:     message.Voice,
+		Document:  message.Document,
+	}
+	if message.ReplyToMessage != nil {
+		msg.ReplyToMessageID = message.ReplyToMessage.MessageID
+	}
+	if b.cfg.KeepForwardMetadata {
+		msg.Forward = forwardOrigin(message)
+	}
+
+	b.routeMessage(ctx, msg)
+}
+
+// handleMediaGroup handles all items of an album as a single message; it runs outside the
+// update middleware chain, so it recovers from panics on its own
+func (b *Bot) handleMediaGroup(messages []*tgbotapi.Message) {
+	b.wg.Add(1)
+	defer b.wg.Done()
+
+	first := messages[0]
+	b.recoveryMW.Handle(tgbotapi.Update{Message: first}, func(tgbotapi.Update) {
+		b.routeMediaGroup(messages)
+	})
+}
+
+// routeMediaGroup merges album items into one normalized message and routes it
+func (b *Bot) routeMediaGroup(messages []*tgbotapi.Message) {
+	first := messages[0]
+	ctx, span := b.updateSpan(b.updateContext(), "album")
+	defer span.End()
+
+	span.SetAttributes(attribute.Int64("telegram.user_id", first.From.ID), attribute.Int("telegram.album_size", len(messages)))
+	ctx = entity.WithUsageSubject(ctx, entity.TelegramUsageSubject(first.From.ID))
+	ctx = b.userLocationContext(ctx, first.From.ID)
+	ctx = b.userContext(ctx, first.From)
+
+	msg := &handlers.Message{
+		ChatID:    first.Chat.ID,
+		UserID:    first.From.ID,
+		MessageID: first.MessageID,
+		Album:     make([]handlers.AlbumItem, 0, len(messages)),
+	}
+	if b.cfg.KeepForwardMetadata {
+		msg.Forward = forwardOrigin(first)
+	}
+	for _, m := range messages {
+		msg.Album = append(msg.Album, handlers.AlbumItem{
+			Caption:  m.Caption,
+			Document: m.Document,
+		})
+	}
+
+	ctxzap.Info(ctx, "media group received",
+		zap.String("media_group_id", first.MediaGroupID),
+		zap.Int("items", len(messages)),
+		zap.Int64("user_id", msg.UserID),
+	)
+
+	b.routeMessage(ctx, msg)
+}
+
+// routeMessage passes a normalized message to the handler of the user's session status
+func (b *Bot) routeMessage(ctx context.Context, msg *handlers.Message) {
 	// Get telegram session with joined session data (single query)
-	userID := message.From.ID
+	userID := msg.UserID
 	sessionData, err := b.stateManager.GetSessionWithSession(ctx, userID)
 	if err != nil {
 		ctxzap.Error(ctx, "failed to get telegram session",
 			zap.Error(err),
 			zap.Int64("user_id", userID),
 		)
-		b.sendError(message.Chat.ID, render.ErrGeneric)
+		b.sendError(msg.ChatID, render.IncidentText(ctx, render.ErrGeneric))
 		return
 	}
 
@@ -221,7 +516,7 @@ func (b *Bot) handleMessage(ctx context.Context, message *tgbotapi.Message) {
 		ctxzap.Warn(ctx, "no active session for user",
 			zap.Int64("user_id", userID),
 		)
-		b.sendError(message.Chat.ID, "Нет активной сессии. Используйте /start")
+		b.sendError(msg.ChatID, "Нет активной сессии. Используйте /start")
 		return
 	}
 
@@ -232,7 +527,7 @@ func (b *Bot) handleMessage(ctx context.Context, message *tgbotapi.Message) {
 			zap.Error(err),
 			zap.Int64("user_id", userID),
 		)
-		b.sendError(message.Chat.ID, render.ErrGeneric)
+		b.sendError(msg.ChatID, render.IncidentText(ctx, render.ErrGeneric))
 		return
 	}
 	ctx = state.ContextWithStateData(ctx, stateData)
@@ -244,28 +539,30 @@ func (b *Bot) handleMessage(ctx context.Context, message *tgbotapi.Message) {
 			zap.String("state", sessionData.SessionStatus),
 			zap.Int64("user_id", userID),
 		)
-		b.sendError(message.Chat.ID, render.ErrInvalidState)
+		b.sendError(msg.ChatID, render.ErrInvalidState)
 		return
 	}
 
-	// Create normalized message
-	msg := &handlers.Message{
-		ChatID:    message.Chat.ID,
-		UserID:    message.From.ID,
-		MessageID: message.MessageID,
-		Text:      message.Text,
-		Voice:     message.Voice,
-		Document:  message.Document,
+	// Text answers are kept until they are submitted, so an error does not lose them
+	if msg.InboxID == "" && msg.Text != "" && inboxStates[sessionData.SessionStatus] {
+		b.keepInbox(ctx, msg, sessionData, stateData)
 	}
 
 	// Handle message
-	if err := handler.Handle(ctx, msg); err != nil {
+	handlerCtx, release := b.handlerContext(ctx, userID, sessionData.SessionStatus)
+	defer release()
+	err = handler.Handle(handlerCtx, msg)
+	// The kept text is released outside the handler deadline, which may have passed
+	b.releaseInbox(ctx, msg, err)
+	if err != nil {
 		ctxzap.Error(ctx, "handler error",
 			zap.Error(err),
 			zap.String("state", sessionData.SessionStatus),
 			zap.Int64("user_id", userID),
 		)
-		b.sendError(message.Chat.ID, render.ErrGeneric)
+		if text, ok := handlerErrorText(handlerCtx); ok {
+			b.sendHandlerError(msg, text)
+		}
 	}
 }
 
@@ -285,17 +582,60 @@ func (b *Bot) handleCommand(ctx context.Context, message *tgbotapi.Message) {
 		b.handleHelpCommand(ctx, message)
 	case "cancel":
 		b.handleCancelCommand(ctx, message)
+	case "resume":
+		b.handleResumeCommand(ctx, message)
+	case "normalize":
+		b.handleNormalizeCommand(ctx, message)
+	case "numbering":
+		b.handleNumberingCommand(ctx, message)
+	case "tutorial":
+		b.sendTutorial(ctx, message.Chat.ID)
+	case "demo":
+		b.handleDemoCommand(ctx, message)
+	case "settings":
+		b.handleSettingsCommand(ctx, message)
+	case "takeover":
+		b.handleTakeoverCommand(ctx, message)
+	case "quota":
+		b.handleQuotaCommand(ctx, message)
+	case "link":
+		b.handleLinkCommand(ctx, message)
+	case "timezone":
+		b.handleTimezoneCommand(ctx, message)
 	default:
 		b.sendError(message.Chat.ID, "❌ Неизвестная команда. Используйте /start")
 	}
 }
 
-// handleStartCommand handles /start command
+// handleStartCommand handles /start command; the first /start of a user opens the tutorial and
+// guesses their timezone from the language of the client
 func (b *Bot) handleStartCommand(ctx context.Context, message *tgbotapi.Message) {
 	chatID := message.Chat.ID
 
+	firstTime, err := b.stateManager.MarkOnboarded(ctx, message.From.ID)
+	if err != nil {
+		ctxzap.Error(ctx, "failed to mark user onboarded",
+			zap.Error(err),
+			zap.Int64("user_id", message.From.ID),
+		)
+	}
+	if firstTime {
+		if _, err := b.stateManager.DetectTimezone(ctx, message.From.ID, message.From.LanguageCode); err != nil {
+			ctxzap.Warn(ctx, "failed to detect user timezone",
+				zap.Error(err),
+				zap.Int64("user_id", message.From.ID),
+			)
+		}
+		b.sendTutorial(ctx, chatID)
+		return
+	}
+
 	// Show welcome message with "start session" button.
-	if _, err := b.sendMessage(chatID, render.MsgWelcome, b.keyboard.StartKeyboard()); err != nil {
+	welcome := render.MsgWelcome
+	if b.cfg.Branding.WelcomeText != "" {
+		welcome = b.cfg.Branding.WelcomeText
+	}
+	if _, err := b.sendMessage(chatID, welcome, b.keyboard.StartKeyboard()); err != nil {
 		ctxzap.Error(ctx, "failed to send welcome message",
 			zap.Error(err),
 			zap.Int64("chat_id", chatID),
@@ -303,37 +643,204 @@ func (b *Bot) handleStartCommand(ctx context.Context, message *tgbotapi.Message)
 	}
 }
 
-// handleHelpCommand handles /help command
-func (b *Bot) handleHelpCommand(ctx context.Context, message *tgbotapi.Message) {
-	helpText := `🤖 **Команды бота:**
+// sendTutorial sends the first onboarding tutorial page
+func (b *Bot) sendTutorial(ctx context.Context, chatID int64) {
+	kb := b.keyboard.TutorialKeyboard(0, len(render.TutorialCards))
+	if _, err := b.sendMessage(chatID, render.TutorialCards[0], kb); err != nil {
+		ctxzap.Error(ctx, "failed to send tutorial",
+			zap.Error(err),
+			zap.Int64("chat_id", chatID),
+		)
+	}
+}
 
-/start - Начать новую сессию
-/help - Показать эту справку
-/cancel - Отменить текущую сессию
+// handleResumeCommand handles /resume command that continues the session of the user from the
+// step it was interrupted at, e.g. by a restart of the bot
+func (b *Bot) handleResumeCommand(ctx context.Context, message *tgbotapi.Message) {
+	resumer, ok := b.handlers[handlers.HandlerStateCallback].(handlers.Resumer)
+	if !ok {
+		ctxzap.Warn(ctx, "callback handler not registered")
+		b.sendError(message.Chat.ID, render.ErrGeneric)
+		return
+	}
 
-**Как это работает:**
-1. Опиши цель проекта
-2. Выбери существующий проект или создай контекст вручную
-3. Выбери режим: Интервью или Драфт
-4. Ответь на вопросы или пришли материалы
-5. Получи готовые бизнес-требования
+	userID := message.From.ID
+	msg := &handlers.Message{
+		ChatID: message.Chat.ID,
+		UserID: userID,
+	}
 
-Начни с /start`
+	// Resuming may go on to generation, which outlasts the command timeout, so it runs like a
+	// pressed button of the user
+	go func(ctx context.Context) {
+		ctx, release := b.handlerContext(ctx, userID, handlers.HandlerStateCallback)
+		defer release()
+		if err := resumer.Resume(ctx, msg); err != nil {
+			ctxzap.Error(ctx, "resume error",
+				zap.Error(err),
+				zap.Int64("user_id", userID),
+			)
+			if text, ok := handlerErrorText(ctx); ok {
+				b.sendHandlerError(msg, text)
+			}
+		}
+	}(context.WithoutCancel(ctx))
+}
 
-	msg := tgbotapi.NewMessage(message.Chat.ID, helpText)
-	msg.ParseMode = "Markdown"
-	if _, err := b.api.Send(msg); err != nil {
-		ctxzap.Error(ctx, "failed to send help message",
+// handleDemoCommand handles /demo command that offers a sandbox demo session
+func (b *Bot) handleDemoCommand(ctx context.Context, message *tgbotapi.Message) {
+	if _, err := b.sendMessage(message.Chat.ID, render.MsgDemoSessionOffer, b.keyboard.DemoSessionKeyboard()); err != nil {
+		ctxzap.Error(ctx, "failed to send demo session offer",
 			zap.Error(err),
+			zap.Int64("chat_id", message.Chat.ID),
 		)
 	}
 }
 
+// inDemoSession reports whether the user is in a demo session; such users are exempt from rate limiting
+func (b *Bot) inDemoSession(userID int64) bool {
+	ctx, cancel := context.WithTimeout(entity.WithTenant(context.Background(), b.tenant), 2*time.Second)
+	defer cancel()
+
+	telegramSession, err := b.stateManager.GetSession(ctx, userID)
+	if err != nil || telegramSession.SessionID == "" {
+		return false
+	}
+
+	session, err := b.sessionUC.GetSession(ctx, telegramSession.SessionID)
+	if err != nil {
+		return false
+	}
+
+	return session.IsDemo
+}
+
+// handleSettingsCommand handles /settings command that opens the settings menu
+func (b *Bot) handleSettingsCommand(ctx context.Context, message *tgbotapi.Message) {
+	if _, err := b.sendMessage(message.Chat.ID, render.MsgSettings, b.keyboard.SettingsKeyboard()); err != nil {
+		ctxzap.Error(ctx, "failed to send settings menu",
+			zap.Error(err),
+			zap.Int64("chat_id", message.Chat.ID),
+		)
+	}
+}
+
+// handleTimezoneCommand handles /timezone command: without arguments it shows the timezone of the
+// user with the common timezones to pick from, with an IANA name it sets the timezone
+func (b *Bot) handleTimezoneCommand(ctx context.Context, message *tgbotapi.Message) {
+	name := strings.TrimSpace(message.CommandArguments())
+	if name == "" {
+		loc := entity.LocationFromContext(ctx)
+		text := handlers.RenderTimezone(render.MsgTimezone, loc)
+		if _, err := b.sendMessage(message.Chat.ID, text, b.keyboard.TimezoneKeyboard(loc.String())); err != nil {
+			ctxzap.Error(ctx, "failed to send timezone",
+				zap.Error(err),
+				zap.Int64("chat_id", message.Chat.ID),
+			)
+		}
+		return
+	}
+
+	loc, err := handlers.ApplyTimezone(ctx, b.stateManager, b.projectUC, message.From.ID, name)
+	if errors.Is(err, entity.ErrInvalidParameter) {
+		b.sendError(message.Chat.ID, render.ErrInvalidTimezone)
+		return
+	}
+	if err != nil {
+		ctxzap.Error(ctx, "failed to set user timezone",
+			zap.Error(err),
+			zap.Int64("user_id", message.From.ID),
+		)
+		b.sendError(message.Chat.ID, render.IncidentText(ctx, render.ErrGeneric))
+		return
+	}
+
+	b.sendMessage(message.Chat.ID, handlers.RenderTimezone(render.MsgTimezoneSet, loc), nil)
+}
+
+// handleQuotaCommand handles /quota command that shows the usage of the user's quotas
+func (b *Bot) handleQuotaCommand(ctx context.Context, message *tgbotapi.Message) {
+	usage, err := b.sessionUC.GetQuotaUsage(ctx)
+	if err != nil {
+		ctxzap.Error(ctx, "failed to get quota usage",
+			zap.Error(err),
+			zap.Int64("user_id", message.From.ID),
+		)
+		b.sendError(message.Chat.ID, render.IncidentText(ctx, render.ErrGeneric))
+		return
+	}
+
+	b.sendMessage(message.Chat.ID, handlers.RenderQuotaUsage(ctx, usage.Quotas), nil)
+}
+
+// handleLinkCommand handles /link command that issues a one-time code continuing the user's
+// session in another client
+func (b *Bot) handleLinkCommand(ctx context.Context, message *tgbotapi.Message) {
+	code, err := b.linkUC.IssueCode(ctx, message.From.ID)
+	if err != nil {
+		ctxzap.Error(ctx, "failed to issue account link code",
+			zap.Error(err),
+			zap.Int64("user_id", message.From.ID),
+		)
+		b.sendError(message.Chat.ID, render.IncidentText(ctx, render.ErrGeneric))
+		return
+	}
+
+	text := fmt.Sprintf(render.MsgLinkCode, code.Code, entity.LocalTime(ctx, code.ExpiresAt).Format(render.LinkCodeExpiryLayout))
+	b.sendMessage(message.Chat.ID, text, nil)
+}
+
+// handleNormalizeCommand handles /normalize command that toggles transcription normalization
+func (b *Bot) handleNormalizeCommand(ctx context.Context, message *tgbotapi.Message) {
+	enabled, err := b.stateManager.ToggleNormalizeTranscripts(ctx, message.From.ID)
+	if err != nil {
+		ctxzap.Error(ctx, "failed to toggle transcript normalization",
+			zap.Error(err),
+			zap.Int64("user_id", message.From.ID),
+		)
+		b.sendError(message.Chat.ID, render.IncidentText(ctx, render.ErrGeneric))
+		return
+	}
+
+	text := render.MsgNormalizeOff
+	if enabled {
+		text = render.MsgNormalizeOn
+	}
+	b.sendMessage(message.Chat.ID, text, nil)
+}
+
+// handleNumberingCommand handles /numbering command that switches between per-block and global question numbering
+func (b *Bot) handleNumberingCommand(ctx context.Context, message *tgbotapi.Message) {
+	numbering, err := b.stateManager.ToggleQuestionNumbering(ctx, message.From.ID)
+	if err != nil {
+		ctxzap.Error(ctx, "failed to toggle question numbering",
+			zap.Error(err),
+			zap.Int64("user_id", message.From.ID),
+		)
+		b.sendError(message.Chat.ID, render.IncidentText(ctx, render.ErrGeneric))
+		return
+	}
+
+	text := render.MsgNumberingBlock
+	if numbering == state.NumberingGlobal {
+		text = render.MsgNumberingGlobal
+	}
+	b.sendMessage(message.Chat.ID, text, nil)
+}
+
 // handleCancelCommand handles /cancel command
 func (b *Bot) handleCancelCommand(ctx context.Context, message *tgbotapi.Message) {
 	userID := message.From.ID
 	chatID := message.Chat.ID
 
+	// Work started by the previous updates of the user, e.g. a hung generation, stops right away
+	if cancelled := b.calls.cancelOthers(ctx, userID); cancelled > 0 {
+		ctxzap.Info(ctx, "running handlers cancelled",
+			zap.Int("handlers", cancelled),
+			zap.Int64("user_id", userID),
+		)
+	}
+
 	// Get telegram session
 	telegramSession, err := b.stateManager.GetSession(ctx, userID)
 	if err != nil {
@@ -395,6 +902,29 @@ func performCancellation(ctx context.Context, b *Bot, sessionID string, userID i
 	b.sendMessage(chatID, render.MsgSessionFinished, nil)
 }
 
+// questionButtonDisabled reports whether the callback comes from a question button turned off for the
+// session type of the user. Buttons shared with other keyboards are rejected only while the session
+// waits for answers; a failed lookup lets the callback through
+func (b *Bot) questionButtonDisabled(ctx context.Context, userID int64, data *keyboard.CallbackData) bool {
+	button, only, ok := keyboard.CallbackQuestionButton(data)
+	if !ok || b.keyboard.QuestionButtons().AllEnabled() {
+		return false
+	}
+
+	session, err := b.stateManager.GetSessionWithSession(ctx, userID)
+	if err != nil {
+		ctxzap.Warn(ctx, "failed to get session type of callback",
+			zap.Error(err),
+			zap.Int64("user_id", userID),
+		)
+		return false
+	}
+	if b.keyboard.QuestionButtons().Enabled(entity.SessionType(session.SessionType), button) {
+		return false
+	}
+	return only || session.SessionStatus == string(entity.SessionStatusWaitingForAnswers)
+}
+
 // handleCallbackQuery handles callback button clicks
 func (b *Bot) handleCallbackQuery(ctx context.Context, query *tgbotapi.CallbackQuery) {
 	// Parse callback data
@@ -415,6 +945,24 @@ func (b *Bot) handleCallbackQuery(ctx context.Context, query *tgbotapi.CallbackQ
 		zap.Int64("user_id", query.From.ID),
 	)
 
+	// Buttons turned off for the session type are rejected, also when pressed under an old message
+	if b.questionButtonDisabled(ctx, query.From.ID, callbackData) {
+		b.answerCallback(query.ID, render.MsgQuestionButtonDisabled)
+		return
+	}
+
+	// Quick answers of scale questions are answers, routed like text messages
+	if callbackData.Action == "scale" {
+		b.handleQuickAnswer(ctx, query, callbackData.Value)
+		return
+	}
+
+	// Kept texts sent again after a failed submission are routed like text messages too
+	if callbackData.Action == "inbox" {
+		b.handleInboxRetry(ctx, query, callbackData.Value)
+		return
+	}
+
 	// Route callback to handler
 	// This will be implemented in callback handler
 	userID := query.From.ID
@@ -443,8 +991,12 @@ func (b *Bot) handleCallbackQuery(ctx context.Context, query *tgbotapi.CallbackQ
 			return
 		}
 		ctx = state.ContextWithStateData(ctx, stateData)
-	} else if !(callbackData.Action == "action" && callbackData.Value == "start") {
-		// For "action:start" callback, we don't need existing StateData (creating new session)
+	} else if !(callbackData.Action == "action" && (callbackData.Value == "start" || callbackData.Value == "start_demo")) &&
+		callbackData.Action != "review" && callbackData.Action != "scheduled" &&
+		callbackData.Action != "tutorial" && callbackData.Action != "demo" {
+		// For "action:start", "action:start_demo" and "scheduled" callbacks, we don't need existing StateData (binding a new session)
+		// For "review" callbacks, approvers decide on other users' sessions
+		// "tutorial" and "demo" callbacks keep no state and are used before the first session
 		// For other actions, load StateData
 		// Load StateData once and attach to context for request-scoped caching
 		stateData, err := b.stateManager.GetStateData(ctx, userID)
@@ -473,24 +1025,67 @@ func (b *Bot) handleCallbackQuery(ctx context.Context, query *tgbotapi.CallbackQ
 	// Дальнейшая тяжёлая обработка выполняется асинхронно,
 	// а результаты/ошибки отправляются как обычные сообщения в чат.
 	go func(ctx context.Context, m *handlers.Message, uid, cid int64) {
+		ctx, release := b.handlerContext(ctx, uid, handlers.HandlerStateCallback)
+		defer release()
 		if err := handler.Handle(ctx, m); err != nil {
 			ctxzap.Error(ctx, "callback handler error",
 				zap.Error(err),
 				zap.Int64("user_id", uid),
 			)
 			// Сообщаем об ошибке в чат, чтобы пользователь видел результат
-			b.sendError(cid, render.ErrGeneric)
+			if text, ok := handlerErrorText(ctx); ok {
+				b.sendError(cid, text)
+			}
 		}
 	}(ctx, msg, userID, chatID)
 }
 
-// sendMessage sends a message to chat
+// handleQuickAnswer submits the rating chosen with the buttons of a scale question as the answer text;
+// value is "<question_id>:<rating>"
+func (b *Bot) handleQuickAnswer(ctx context.Context, query *tgbotapi.CallbackQuery, value string) {
+	questionID, answer, ok := strings.Cut(value, ":")
+	if _, valid := entity.ParseScaleAnswer(answer); !ok || !valid || questionID == "" {
+		b.answerCallback(query.ID, "❌ Неверные данные")
+		return
+	}
+
+	sessionData, err := b.stateManager.GetSessionWithSession(ctx, query.From.ID)
+	if err != nil || sessionData.SessionStatus != handlers.HandlerStateWaitingAnswers {
+		b.answerCallback(query.ID, render.MsgQuickAnswerUnavailable)
+		return
+	}
+
+	b.answerCallback(query.ID, "✅ "+answer)
+
+	msg := &handlers.Message{
+		ChatID:             query.Message.Chat.ID,
+		UserID:             query.From.ID,
+		MessageID:          query.Message.MessageID,
+		Text:               answer,
+		AnsweredQuestionID: questionID,
+	}
+	go b.routeMessage(ctx, msg)
+}
+
+// sendMessage sends a message to chat, split into several messages when the text is too long.
+// The reply markup goes with the last part, which is returned
 func (b *Bot) sendMessage(chatID int64, text string, replyMarkup interface{}) (tgbotapi.Message, error) {
-	msg := tgbotapi.NewMessage(chatID, text)
-	if replyMarkup != nil {
-		msg.ReplyMarkup = replyMarkup
+	parts := render.SplitMessage(text, "", render.MaxMessageLength)
+
+	var sent tgbotapi.Message
+	for i, part := range parts {
+		msg := tgbotapi.NewMessage(chatID, part)
+		if replyMarkup != nil && i == len(parts)-1 {
+			msg.ReplyMarkup = replyMarkup
+		}
+
+		var err error
+		if sent, err = b.api.Send(msg); err != nil {
+			return sent, err
+		}
 	}
-	return b.api.Send(msg)
+
+	return sent, nil
 }
 
 // sendError sends an error message
@@ -556,6 +1151,11 @@ func (b *Bot) GetStateManager() *state.Manager {
 	return b.stateManager
 }
 
+// GetStore returns the store of the state shared between updates (for handlers)
+func (b *Bot) GetStore() store.Store {
+	return b.store
+}
+
 // GetKeyboard returns the keyboard builder (for handlers)
 func (b *Bot) GetKeyboard() *keyboard.Builder {
 	return b.keyboard
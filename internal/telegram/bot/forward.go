@@ -0,0 +1,38 @@
+package bot
+
+import (
+	"strings"
+	"time"
+
+	"github.com/futig/agent-backend/internal/telegram/handlers"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// forwardOrigin returns the origin of a forwarded message or nil when the message is not forwarded
+func forwardOrigin(message *tgbotapi.Message) *handlers.ForwardOrigin {
+	if message.ForwardDate == 0 {
+		return nil
+	}
+
+	origin := &handlers.ForwardOrigin{
+		Date: time.Unix(int64(message.ForwardDate), 0).UTC(),
+	}
+
+	switch {
+	case message.ForwardFrom != nil:
+		origin.Name = strings.TrimSpace(message.ForwardFrom.FirstName + " " + message.ForwardFrom.LastName)
+		if origin.Name == "" {
+			origin.Name = message.ForwardFrom.UserName
+		}
+	case message.ForwardFromChat != nil:
+		origin.Name = message.ForwardFromChat.Title
+		if message.ForwardSignature != "" {
+			origin.Name += " (" + message.ForwardSignature + ")"
+		}
+	default:
+		// Users hiding their account in forwards only expose a display name
+		origin.Name = message.ForwardSenderName
+	}
+
+	return origin
+}
@@ -0,0 +1,86 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/futig/agent-backend/internal/telegram/handlers"
+	"github.com/futig/agent-backend/internal/telegram/render"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// botCommand describes a command handled by handleCommand
+type botCommand struct {
+	name        string
+	description string
+}
+
+// botCommands lists the bot commands in /help order
+var botCommands = []botCommand{
+	{"start", "Начать новую сессию"},
+	{"help", "Показать справку по текущему шагу"},
+	{"cancel", "Отменить текущую сессию"},
+	{"resume", "Продолжить прерванную сессию с места остановки"},
+	{"normalize", "Включить или выключить исправление расшифровок голосовых"},
+	{"numbering", "Переключить нумерацию вопросов: внутри блока или сквозная"},
+	{"settings", "Настройки: избранные проекты, часовой пояс, стиль документа и прогресс над вопросами"},
+	{"timezone", "Показать или сменить часовой пояс"},
+	{"quota", "Показать лимиты использования"},
+	{"link", "Получить код для продолжения сессии на другом устройстве"},
+	{"tutorial", "Пройти обучение и попробовать демо"},
+	{"demo", "Начать демо-сессию в песочнице на своей цели"},
+}
+
+// handleHelpCommand handles /help command: the command list followed by what the user can do right now
+func (b *Bot) handleHelpCommand(ctx context.Context, message *tgbotapi.Message) {
+	header := render.MsgHelpHeader
+	if b.cfg.Branding.HelpHeader != "" {
+		header = b.cfg.Branding.HelpHeader
+	}
+
+	var sb strings.Builder
+	sb.WriteString(header)
+	sb.WriteString("\n\n")
+	for _, command := range botCommands {
+		fmt.Fprintf(&sb, "/%s - %s\n", command.name, command.description)
+	}
+	sb.WriteString("\n")
+	sb.WriteString(b.stateHelp(ctx, message.From.ID))
+
+	// Help texts are written in markdown, which the legacy Markdown parse mode does not fully support
+	msg := tgbotapi.NewMessage(message.Chat.ID, render.RenderMarkdown(sb.String()))
+	msg.ParseMode = render.MarkdownParseMode
+	if _, err := b.api.Send(msg); err != nil {
+		ctxzap.Error(ctx, "failed to send help message",
+			zap.Error(err),
+		)
+	}
+}
+
+// stateHelp describes the current step using the handler registered for the session status;
+// statuses without a describing handler fall back to the general overview
+func (b *Bot) stateHelp(ctx context.Context, userID int64) string {
+	sessionData, err := b.stateManager.GetSessionWithSession(ctx, userID)
+	if err != nil || sessionData.SessionID == "" {
+		return render.MsgHelpNoSession
+	}
+
+	provider, ok := b.handlers[sessionData.SessionStatus].(handlers.HelpProvider)
+	if !ok {
+		return render.MsgHelpHowItWorks
+	}
+
+	stateData, err := b.stateManager.GetStateData(ctx, userID)
+	if err != nil {
+		ctxzap.Warn(ctx, "failed to get state data for help",
+			zap.Error(err),
+			zap.Int64("user_id", userID),
+		)
+		return render.MsgHelpHowItWorks
+	}
+
+	return fmt.Sprintf(render.MsgHelpNow, provider.Help(stateData))
+}
@@ -0,0 +1,122 @@
+package bot
+
+import (
+	"context"
+
+	"github.com/futig/agent-backend/internal/telegram/handlers"
+	"github.com/futig/agent-backend/internal/telegram/render"
+	"github.com/futig/agent-backend/internal/telegram/state"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// inboxStates are the handler states whose text messages are kept until they are submitted
+var inboxStates = map[string]bool{
+	handlers.HandlerStateWaitingAnswers:  true,
+	handlers.HandlerStateDraftCollecting: true,
+}
+
+// keepInbox stores the raw text of the message before it is handled, so a failed submission
+// does not lose a long answer. The message is handled anyway when the text cannot be stored
+func (b *Bot) keepInbox(ctx context.Context, msg *handlers.Message, sessionData *state.TelegramSessionWithSession, stateData *state.StateData) {
+	inbox := &state.InboxMessage{
+		UserID:    msg.UserID,
+		SessionID: sessionData.SessionID,
+		Text:      msg.Text,
+	}
+	// The answered question is kept, so a retry answers it even after the interview moved on
+	if sessionData.SessionStatus == handlers.HandlerStateWaitingAnswers {
+		inbox.QuestionID = msg.AnsweredQuestionID
+		if inbox.QuestionID == "" && msg.ReplyToMessageID != 0 {
+			inbox.QuestionID = stateData.QuestionMessages[msg.ReplyToMessageID]
+		}
+		if inbox.QuestionID == "" {
+			inbox.QuestionID = stateData.CurrentQuestionID
+		}
+	}
+
+	saved, err := b.stateManager.SaveInboxMessage(ctx, inbox)
+	if err != nil {
+		ctxzap.Warn(ctx, "failed to keep message in inbox",
+			zap.Error(err),
+			zap.Int64("user_id", msg.UserID),
+		)
+		return
+	}
+	msg.InboxID = saved.ID
+}
+
+// releaseInbox drops the kept text of a handled message unless the handler left it for a retry
+func (b *Bot) releaseInbox(ctx context.Context, msg *handlers.Message, handleErr error) {
+	if msg.InboxID == "" || msg.KeepInbox || handleErr != nil {
+		return
+	}
+
+	if err := b.stateManager.DeleteInboxMessage(ctx, msg.UserID, msg.InboxID); err != nil {
+		ctxzap.Warn(ctx, "failed to delete inbox message",
+			zap.Error(err),
+			zap.String("inbox_id", msg.InboxID),
+			zap.Int64("user_id", msg.UserID),
+		)
+	}
+}
+
+// sendHandlerError tells the user that handling the message failed; a kept text comes with
+// the button sending it again
+func (b *Bot) sendHandlerError(msg *handlers.Message, text string) {
+	if msg.InboxID == "" {
+		b.sendError(msg.ChatID, text)
+		return
+	}
+
+	if _, err := b.sendMessage(msg.ChatID, text+"\n\n"+render.MsgInboxRetryHint, b.keyboard.InboxRetryKeyboard(msg.InboxID)); err != nil {
+		b.logger.Error("failed to send error message",
+			zap.Error(err),
+			zap.Int64("chat_id", msg.ChatID),
+		)
+	}
+}
+
+// handleInboxRetry sends a kept text again as a new message of the user; value is the inbox ID
+func (b *Bot) handleInboxRetry(ctx context.Context, query *tgbotapi.CallbackQuery, value string) {
+	userID := query.From.ID
+
+	inbox, err := b.stateManager.GetInboxMessage(ctx, userID, value)
+	if err != nil {
+		ctxzap.Warn(ctx, "inbox message unavailable",
+			zap.Error(err),
+			zap.String("inbox_id", value),
+			zap.Int64("user_id", userID),
+		)
+		b.answerCallback(query.ID, render.MsgInboxRetryUnavailable)
+		return
+	}
+
+	// A text of a finished session or of another step is not sent to the current one
+	sessionData, err := b.stateManager.GetSessionWithSession(ctx, userID)
+	if err != nil || sessionData.SessionID != inbox.SessionID || !inboxStates[sessionData.SessionStatus] {
+		b.answerCallback(query.ID, render.MsgInboxRetryUnavailable)
+		return
+	}
+
+	b.answerCallback(query.ID, render.MsgInboxRetrying)
+
+	// The pressed button is used up, so the text is not sent twice
+	edit := tgbotapi.NewEditMessageReplyMarkup(query.Message.Chat.ID, query.Message.MessageID, tgbotapi.InlineKeyboardMarkup{
+		InlineKeyboard: [][]tgbotapi.InlineKeyboardButton{},
+	})
+	if _, err := b.api.Request(edit); err != nil {
+		ctxzap.Debug(ctx, "failed to remove retry button", zap.Error(err))
+	}
+
+	msg := &handlers.Message{
+		ChatID:             query.Message.Chat.ID,
+		UserID:             userID,
+		MessageID:          query.Message.MessageID,
+		Text:               inbox.Text,
+		AnsweredQuestionID: inbox.QuestionID,
+		InboxID:            inbox.ID,
+	}
+	go b.routeMessage(ctx, msg)
+}
@@ -0,0 +1,70 @@
+package bot
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// mediaGroupCollector buffers album items. Telegram delivers every item of an album as a separate
+// update sharing a media_group_id, so items are collected until none arrives within the window
+// and are then released together.
+type mediaGroupCollector struct {
+	window  time.Duration
+	release func(messages []*tgbotapi.Message)
+	mu      sync.Mutex
+	groups  map[string]*pendingMediaGroup
+}
+
+type pendingMediaGroup struct {
+	messages []*tgbotapi.Message
+	timer    *time.Timer
+}
+
+func newMediaGroupCollector(window time.Duration, release func(messages []*tgbotapi.Message)) *mediaGroupCollector {
+	return &mediaGroupCollector{
+		window:  window,
+		release: release,
+		groups:  make(map[string]*pendingMediaGroup),
+	}
+}
+
+// add buffers an album item and restarts the debounce window of its album
+func (c *mediaGroupCollector) add(message *tgbotapi.Message) {
+	key := fmt.Sprintf("%d:%s", message.Chat.ID, message.MediaGroupID)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if group, ok := c.groups[key]; ok {
+		group.messages = append(group.messages, message)
+		group.timer.Reset(c.window)
+		return
+	}
+
+	c.groups[key] = &pendingMediaGroup{
+		messages: []*tgbotapi.Message{message},
+		timer:    time.AfterFunc(c.window, func() { c.flush(key) }),
+	}
+}
+
+// flush releases the collected items of an album in their original order
+func (c *mediaGroupCollector) flush(key string) {
+	c.mu.Lock()
+	group, ok := c.groups[key]
+	delete(c.groups, key)
+	c.mu.Unlock()
+
+	// A timer reset after it already fired flushes an album that is gone
+	if !ok {
+		return
+	}
+
+	sort.Slice(group.messages, func(i, j int) bool {
+		return group.messages[i].MessageID < group.messages[j].MessageID
+	})
+	c.release(group.messages)
+}
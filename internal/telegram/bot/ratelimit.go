@@ -0,0 +1,97 @@
+package bot
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/futig/agent-backend/internal/config"
+	"github.com/futig/agent-backend/internal/telegram/middleware"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// healthWeight is the weight of the latest handler outcome in the health score
+const healthWeight = 0.05
+
+// llmCallbacks are the callback actions, or whole callback data, that start LLM or RAG work
+var llmCallbacks = map[string]bool{
+	"explain":                true,
+	"proj":                   true,
+	"action:generate":        true,
+	"action:translate":       true,
+	"action:regen_section":   true,
+	"action:start_interview": true,
+	"action:search":          true,
+}
+
+// serviceHealth is a moving average of handler outcomes: 1 while handlers finish in time,
+// falling towards 0 while they time out
+type serviceHealth struct {
+	mu    sync.Mutex
+	score float64
+}
+
+func newServiceHealth() *serviceHealth {
+	return &serviceHealth{score: 1}
+}
+
+func (h *serviceHealth) record(ok bool) {
+	outcome := 0.0
+	if ok {
+		outcome = 1
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.score += healthWeight * (outcome - h.score)
+}
+
+func (h *serviceHealth) value() float64 {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.score
+}
+
+// newRateLimiter creates the limiter of the configured mode
+func (b *Bot) newRateLimiter(api *tgbotapi.BotAPI) middleware.RateLimiter {
+	if b.cfg.RateLimit.Mode == config.RateLimitModeSimple {
+		return middleware.NewRateLimiterMiddleware(
+			b.cfg.RateLimitPerMinute,
+			b.cfg.RateLimitBurst,
+			b.store,
+			b.inDemoSession,
+			b.logger,
+			api,
+		)
+	}
+
+	return middleware.NewAdaptiveRateLimiterMiddleware(
+		b.cfg.RateLimitPerMinute,
+		b.cfg.RateLimit,
+		b.store,
+		b.updateCost,
+		b.health.value,
+		b.inDemoSession,
+		b.logger,
+		api,
+	)
+}
+
+// updateCost weighs an update for the adaptive limiter: buttons starting LLM work, voice messages
+// and files cost the LLM weight, other buttons the navigation weight and text messages one token
+func (b *Bot) updateCost(update tgbotapi.Update) float64 {
+	switch {
+	case update.CallbackQuery != nil:
+		data := update.CallbackQuery.Data
+		action, _, _ := strings.Cut(data, ":")
+		if llmCallbacks[data] || llmCallbacks[action] {
+			return b.cfg.RateLimit.LLMCost
+		}
+		return b.cfg.RateLimit.NavigationCost
+	case update.Message != nil:
+		message := update.Message
+		if message.Voice != nil || message.Audio != nil || message.VideoNote != nil || message.Document != nil {
+			return b.cfg.RateLimit.LLMCost
+		}
+	}
+	return 1
+}
@@ -0,0 +1,290 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/telegram/handlers"
+	"github.com/futig/agent-backend/internal/telegram/keyboard"
+	"github.com/futig/agent-backend/internal/telegram/render"
+	"github.com/futig/agent-backend/internal/telegram/state"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// takeovers keeps the user each support operator is attached to. Attachments live in memory
+// and end with the bot process
+type takeovers struct {
+	mu      sync.Mutex
+	targets map[int64]int64 // operator ID -> user ID
+}
+
+func newTakeovers() *takeovers {
+	return &takeovers{targets: make(map[int64]int64)}
+}
+
+func (t *takeovers) attach(operatorID, userID int64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.targets[operatorID] = userID
+}
+
+func (t *takeovers) target(operatorID int64) (int64, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	userID, ok := t.targets[operatorID]
+	return userID, ok
+}
+
+func (t *takeovers) release(operatorID int64) (int64, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	userID, ok := t.targets[operatorID]
+	delete(t.targets, operatorID)
+	return userID, ok
+}
+
+// isAdmin reports whether the user is a support operator of the bot
+func (b *Bot) isAdmin(userID int64) bool {
+	return slices.Contains(b.cfg.AdminIDs, userID)
+}
+
+// handleTakeoverCommand handles /takeover of support operators:
+// /takeover <user_id> attaches to the user's session, /takeover shows the current question,
+// /takeover generate starts generation and /takeover release detaches
+func (b *Bot) handleTakeoverCommand(ctx context.Context, message *tgbotapi.Message) {
+	operatorID := message.From.ID
+	if !b.isAdmin(operatorID) {
+		b.sendError(message.Chat.ID, "❌ Неизвестная команда. Используйте /start")
+		return
+	}
+
+	args := strings.TrimSpace(message.CommandArguments())
+	switch args {
+	case "":
+		userID, ok := b.takeovers.target(operatorID)
+		if !ok {
+			b.sendMessage(message.Chat.ID, render.MsgTakeoverUsage, nil)
+			return
+		}
+		b.sendTakeoverStatus(ctx, message.Chat.ID, userID)
+	case "generate":
+		b.takeoverGenerate(ctx, message)
+	case "release":
+		b.releaseTakeover(ctx, message)
+	default:
+		userID, err := strconv.ParseInt(args, 10, 64)
+		if err != nil || userID <= 0 {
+			b.sendError(message.Chat.ID, render.MsgTakeoverInvalidUser)
+			return
+		}
+		b.attachTakeover(ctx, message, userID)
+	}
+}
+
+// attachTakeover attaches the operator to the active session of the user
+func (b *Bot) attachTakeover(ctx context.Context, message *tgbotapi.Message, userID int64) {
+	operatorID := message.From.ID
+
+	sessionData, err := b.stateManager.GetSessionWithSession(ctx, userID)
+	if err != nil || sessionData.SessionID == "" {
+		b.sendMessage(message.Chat.ID, fmt.Sprintf(render.MsgTakeoverNoSession, userID), nil)
+		return
+	}
+
+	if !b.recordTakeover(ctx, message.Chat.ID, sessionData.SessionID, operatorID, userID, entity.TakeoverActionAttach, nil) {
+		return
+	}
+
+	b.takeovers.attach(operatorID, userID)
+
+	ctxzap.Info(ctx, "operator attached to user session",
+		zap.Int64("operator_id", operatorID),
+		zap.Int64("user_id", userID),
+		zap.String("session_id", sessionData.SessionID),
+	)
+
+	b.sendMessage(userID, render.MsgTakeoverAttached, nil)
+	b.sendMessage(message.Chat.ID, fmt.Sprintf(render.MsgTakeoverStarted, userID), nil)
+	b.sendTakeoverStatus(ctx, message.Chat.ID, userID)
+}
+
+// releaseTakeover detaches the operator from the user's session
+func (b *Bot) releaseTakeover(ctx context.Context, message *tgbotapi.Message) {
+	operatorID := message.From.ID
+
+	userID, ok := b.takeovers.release(operatorID)
+	if !ok {
+		b.sendMessage(message.Chat.ID, render.MsgTakeoverNotAttached, nil)
+		return
+	}
+
+	// Detaching only reduces the operator's access, so it is not refused when auditing fails
+	if sessionData, err := b.stateManager.GetSessionWithSession(ctx, userID); err == nil && sessionData.SessionID != "" {
+		if err := b.sessionUC.RecordTakeoverAction(ctx, sessionData.SessionID, operatorID, userID, entity.TakeoverActionRelease, nil); err != nil {
+			ctxzap.Error(ctx, "failed to record takeover release", zap.Error(err))
+		}
+	}
+
+	b.sendMessage(userID, render.MsgTakeoverReleased, nil)
+	b.sendMessage(message.Chat.ID, fmt.Sprintf(render.MsgTakeoverEnded, userID), nil)
+}
+
+// handleOperatorMessage submits a message of an attached operator as the answer of the user
+func (b *Bot) handleOperatorMessage(ctx context.Context, message *tgbotapi.Message, userID int64) {
+	operatorID := message.From.ID
+
+	sessionData, err := b.stateManager.GetSessionWithSession(ctx, userID)
+	if err != nil || sessionData.SessionID == "" {
+		b.sendMessage(message.Chat.ID, fmt.Sprintf(render.MsgTakeoverNoSession, userID), nil)
+		return
+	}
+
+	details := map[string]any{
+		"session_status": sessionData.SessionStatus,
+		"answer":         message.Text,
+		"voice":          message.Voice != nil,
+	}
+	if stateData, err := b.stateManager.GetStateData(ctx, userID); err == nil && stateData.CurrentQuestionID != "" {
+		details["question_id"] = stateData.CurrentQuestionID
+	}
+	if !b.recordTakeover(ctx, message.Chat.ID, sessionData.SessionID, operatorID, userID, entity.TakeoverActionAnswer, details) {
+		return
+	}
+
+	if message.Voice != nil {
+		b.sendMessage(userID, render.MsgTakeoverAnsweredVoice, nil)
+	} else {
+		b.sendMessage(userID, fmt.Sprintf(render.MsgTakeoverAnswered, message.Text), nil)
+	}
+
+	// The answer is handled as the user's own message, so the user sees the next question
+	// and the usage counts against the user's quotas
+	userCtx := entity.WithUsageSubject(ctx, entity.TelegramUsageSubject(userID))
+	b.routeMessage(userCtx, &handlers.Message{
+		ChatID: userID,
+		UserID: userID,
+		Text:   message.Text,
+		Voice:  message.Voice,
+	})
+
+	b.sendMessage(message.Chat.ID, render.MsgTakeoverAnswerSent, nil)
+	b.sendTakeoverStatus(ctx, message.Chat.ID, userID)
+}
+
+// takeoverGenerate starts requirement generation in the session of the attached user
+func (b *Bot) takeoverGenerate(ctx context.Context, message *tgbotapi.Message) {
+	operatorID := message.From.ID
+
+	userID, ok := b.takeovers.target(operatorID)
+	if !ok {
+		b.sendMessage(message.Chat.ID, render.MsgTakeoverNotAttached, nil)
+		return
+	}
+
+	sessionData, err := b.stateManager.GetSessionWithSession(ctx, userID)
+	if err != nil || sessionData.SessionID == "" {
+		b.sendMessage(message.Chat.ID, fmt.Sprintf(render.MsgTakeoverNoSession, userID), nil)
+		return
+	}
+
+	if sessionData.SessionStatus != handlers.HandlerStateWaitingAnswers &&
+		sessionData.SessionStatus != handlers.HandlerStateDraftCollecting {
+		b.sendMessage(message.Chat.ID, render.MsgTakeoverGenerateDenied, nil)
+		return
+	}
+
+	handler, exists := b.handlers[handlers.HandlerStateCallback]
+	if !exists {
+		ctxzap.Warn(ctx, "callback handler not registered")
+		b.sendError(message.Chat.ID, render.ErrGeneric)
+		return
+	}
+
+	stateData, err := b.stateManager.GetStateData(ctx, userID)
+	if err != nil {
+		ctxzap.Error(ctx, "failed to get state data",
+			zap.Error(err),
+			zap.Int64("user_id", userID),
+		)
+		b.sendError(message.Chat.ID, render.IncidentText(ctx, render.ErrGeneric))
+		return
+	}
+
+	if !b.recordTakeover(ctx, message.Chat.ID, sessionData.SessionID, operatorID, userID, entity.TakeoverActionGenerate, nil) {
+		return
+	}
+
+	b.sendMessage(userID, render.MsgTakeoverGenerated, nil)
+	b.sendMessage(message.Chat.ID, render.MsgTakeoverGenerateSent, nil)
+
+	// Generation takes long, it runs like a pressed generate button of the user
+	msg := &handlers.Message{
+		ChatID:       userID,
+		UserID:       userID,
+		CallbackData: keyboard.EncodeCallback("action", "generate"),
+	}
+	go func(ctx context.Context) {
+		ctx, release := b.handlerContext(ctx, userID, handlers.HandlerStateCallback)
+		defer release()
+		if err := handler.Handle(ctx, msg); err != nil {
+			ctxzap.Error(ctx, "takeover generate error",
+				zap.Error(err),
+				zap.Int64("user_id", userID),
+			)
+			if text, ok := handlerErrorText(ctx); ok {
+				b.sendError(userID, text)
+			}
+		}
+	}(state.ContextWithStateData(entity.WithUsageSubject(ctx, entity.TelegramUsageSubject(userID)), stateData))
+}
+
+// sendTakeoverStatus shows the operator the session status and the current question of the user
+func (b *Bot) sendTakeoverStatus(ctx context.Context, chatID, userID int64) {
+	sessionData, err := b.stateManager.GetSessionWithSession(ctx, userID)
+	if err != nil || sessionData.SessionID == "" {
+		b.sendMessage(chatID, fmt.Sprintf(render.MsgTakeoverNoSession, userID), nil)
+		return
+	}
+
+	text := fmt.Sprintf(render.MsgTakeoverStatus, userID, sessionData.SessionStatus)
+	if sessionData.SessionStatus == handlers.HandlerStateWaitingAnswers {
+		stateData, err := b.stateManager.GetStateData(ctx, userID)
+		if err == nil && stateData.CurrentQuestionID != "" {
+			if question, err := b.sessionUC.GetQuestionByID(ctx, stateData.CurrentQuestionID); err == nil {
+				text += fmt.Sprintf(render.MsgTakeoverQuestion, question.Question)
+			}
+		}
+	}
+
+	b.sendMessage(chatID, text, nil)
+}
+
+// recordTakeover audits a takeover action and tells the operator when it is refused for lack of an audit record
+func (b *Bot) recordTakeover(
+	ctx context.Context,
+	chatID int64,
+	sessionID string,
+	operatorID, userID int64,
+	action entity.TakeoverAction,
+	details map[string]any,
+) bool {
+	if err := b.sessionUC.RecordTakeoverAction(ctx, sessionID, operatorID, userID, action, details); err != nil {
+		ctxzap.Error(ctx, "failed to record takeover action",
+			zap.Error(err),
+			zap.String("action", string(action)),
+			zap.Int64("operator_id", operatorID),
+			zap.Int64("user_id", userID),
+		)
+		b.sendError(chatID, render.MsgTakeoverAuditFailed)
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,143 @@
+package bot
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/futig/agent-backend/internal/pkg/metrics"
+	"github.com/futig/agent-backend/internal/pkg/tracing"
+	"github.com/futig/agent-backend/internal/telegram/handlers"
+	"github.com/futig/agent-backend/internal/telegram/render"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// handlerStateCommand is the timeout key of bot commands, which run outside the handler states
+const handlerStateCommand = "COMMAND"
+
+// generationStates are the handler states that can start requirement generation
+var generationStates = map[string]bool{
+	handlers.HandlerStateCallback:           true,
+	handlers.HandlerStateWaitingAnswers:     true,
+	handlers.HandlerStateAskSectionGuidance: true,
+	handlers.HandlerStateAskResultFeedback:  true,
+}
+
+// errCancelledByUser is the cancellation cause of handlers stopped by /cancel
+var errCancelledByUser = errors.New("cancelled by user")
+
+// handlerCallKey keys the ID of the running handler in its context
+type handlerCallKey struct{}
+
+// handlerCalls keeps the cancel functions of the running handlers of every user, so that
+// /cancel stops the work started by the user's previous updates
+type handlerCalls struct {
+	mu     sync.Mutex
+	nextID uint64
+	calls  map[int64]map[uint64]context.CancelCauseFunc // user ID -> call ID -> cancel
+}
+
+func newHandlerCalls() *handlerCalls {
+	return &handlerCalls{calls: make(map[int64]map[uint64]context.CancelCauseFunc)}
+}
+
+func (c *handlerCalls) add(userID int64, cancel context.CancelCauseFunc) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.nextID++
+	if c.calls[userID] == nil {
+		c.calls[userID] = make(map[uint64]context.CancelCauseFunc)
+	}
+	c.calls[userID][c.nextID] = cancel
+	return c.nextID
+}
+
+func (c *handlerCalls) remove(userID int64, id uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.calls[userID], id)
+	if len(c.calls[userID]) == 0 {
+		delete(c.calls, userID)
+	}
+}
+
+// cancelOthers cancels the running handlers of the user except the one of ctx and returns their number
+func (c *handlerCalls) cancelOthers(ctx context.Context, userID int64) int {
+	current, _ := ctx.Value(handlerCallKey{}).(uint64)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	cancelled := 0
+	for id, cancel := range c.calls[userID] {
+		if id == current {
+			continue
+		}
+		cancel(errCancelledByUser)
+		cancelled++
+	}
+	return cancelled
+}
+
+// handlerTimeout returns the timeout of a handler state: the configured override, else the
+// generation timeout for the states that can start generation, else the default one
+func (b *Bot) handlerTimeout(handlerState string) time.Duration {
+	if timeout, ok := b.cfg.HandlerTimeouts[handlerState]; ok {
+		return timeout
+	}
+	if generationStates[handlerState] {
+		return b.cfg.GenerationTimeout
+	}
+	return b.cfg.HandlerTimeout
+}
+
+// handlerContext bounds the handling of the user's update in the handler state with its timeout
+// and makes it cancellable by /cancel. The returned release must be called once the handler returns
+func (b *Bot) handlerContext(ctx context.Context, userID int64, handlerState string) (context.Context, func()) {
+	start := time.Now()
+	ctx, span := tracing.Start(ctx, "telegram handler "+handlerState)
+	ctx, cancel := context.WithCancelCause(ctx)
+	id := b.calls.add(userID, cancel)
+	ctx = context.WithValue(ctx, handlerCallKey{}, id)
+	ctx, stop := context.WithTimeout(ctx, b.handlerTimeout(handlerState))
+
+	return ctx, func() {
+		switch {
+		case errors.Is(ctx.Err(), context.DeadlineExceeded):
+			b.health.record(false)
+			metrics.TelegramHandlerTimeouts.WithLabelValues(handlerState).Inc()
+			tracing.Fail(span, ctx.Err())
+			ctxzap.Warn(ctx, "handler timed out",
+				zap.String("state", handlerState),
+				zap.Duration("timeout", b.handlerTimeout(handlerState)),
+				zap.Int64("user_id", userID),
+			)
+		case errors.Is(context.Cause(ctx), errCancelledByUser):
+			metrics.TelegramHandlerCancellations.WithLabelValues(handlerState).Inc()
+			ctxzap.Info(ctx, "handler cancelled by user",
+				zap.String("state", handlerState),
+				zap.Int64("user_id", userID),
+			)
+		default:
+			b.health.record(true)
+		}
+		metrics.TelegramHandlerDuration.WithLabelValues(handlerState).Observe(time.Since(start).Seconds())
+		span.End()
+		stop()
+		b.calls.remove(userID, id)
+		cancel(nil)
+	}
+}
+
+// handlerErrorText returns the error message for the user of a failed handler with the incident code
+// of the update; a handler stopped by /cancel reports nothing, the user already got the answer of the command
+func handlerErrorText(ctx context.Context) (string, bool) {
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return render.ErrTimeout, true
+	case errors.Is(context.Cause(ctx), errCancelledByUser):
+		return "", false
+	}
+	return render.IncidentText(ctx, render.ErrGeneric), true
+}
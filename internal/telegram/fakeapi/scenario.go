@@ -0,0 +1,309 @@
+package fakeapi
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	// defaultQuiet is how long the bot must stay silent in a chat for its reply to be complete
+	defaultQuiet = 700 * time.Millisecond
+	// defaultStepTimeout bounds the wait for the bot to reply to one step
+	defaultStepTimeout = 60 * time.Second
+	pollInterval       = 50 * time.Millisecond
+	// voiceDuration is the length of the voice messages of scenarios
+	voiceDuration = 2 * time.Second
+)
+
+// Step is one action or expectation of a scenario user
+type Step struct {
+	Name string
+	Run  func(ctx context.Context, c *Conversation) error
+}
+
+// Scenario is a conversation of one user with the bot, e.g.
+//
+//	NewScenario("interview").
+//		Command("start").
+//		Press("action:start").
+//		Expect("цель").
+//		Say("Нужен сервис бронирования переговорных")
+type Scenario struct {
+	Name  string
+	Steps []Step
+
+	voice bool
+}
+
+// NewScenario creates an empty scenario
+func NewScenario(name string) *Scenario {
+	return &Scenario{Name: name}
+}
+
+// Command sends a /command, e.g. "start"
+func (s *Scenario) Command(command string) *Scenario {
+	return s.step("/"+command, func(ctx context.Context, c *Conversation) error {
+		return c.act(ctx, "/"+command, func() error {
+			c.server.SendCommand(c.UserID, command)
+			return nil
+		})
+	})
+}
+
+// Say sends a text message
+func (s *Scenario) Say(text string) *Scenario {
+	return s.step(fmt.Sprintf("say %q", shorten(text)), func(ctx context.Context, c *Conversation) error {
+		return c.act(ctx, "say", func() error {
+			c.server.SendText(c.UserID, text)
+			return nil
+		})
+	})
+}
+
+// Voice sends a voice message of silence; the ASR mock transcribes it with the canned
+// transcript of the file name, see Transcripts
+func (s *Scenario) Voice(fileName string) *Scenario {
+	s.voice = true
+	return s.step("voice "+fileName, func(ctx context.Context, c *Conversation) error {
+		return c.act(ctx, "voice", func() error {
+			c.server.SendVoice(c.UserID, fileName, SilentVoice(voiceDuration))
+			return nil
+		})
+	})
+}
+
+// Press presses the button with the callback data on the latest message showing it;
+// a callback data prefix, e.g. "skip:", presses the first button it prefixes
+func (s *Scenario) Press(data string) *Scenario {
+	return s.step("press "+data, func(ctx context.Context, c *Conversation) error {
+		return c.act(ctx, "press "+data, func() error {
+			return c.server.PressButton(c.UserID, data)
+		})
+	})
+}
+
+// Expect requires a reply to the previous action containing the text; replies that are
+// slower than the quiet period, like generated results, are waited for
+func (s *Scenario) Expect(text string) *Scenario {
+	return s.step(fmt.Sprintf("expect %q", shorten(text)), func(ctx context.Context, c *Conversation) error {
+		return c.await(ctx, func(event Event) bool {
+			return strings.Contains(event.Text, text)
+		})
+	})
+}
+
+// ExpectButton requires a reply to the previous action with the button of the callback data
+func (s *Scenario) ExpectButton(data string) *Scenario {
+	return s.step("expect button "+data, func(ctx context.Context, c *Conversation) error {
+		return c.await(ctx, func(event Event) bool {
+			return event.HasButton(data)
+		})
+	})
+}
+
+// ExpectDocument requires a document among the replies to the previous action
+func (s *Scenario) ExpectDocument() *Scenario {
+	return s.step("expect document", func(ctx context.Context, c *Conversation) error {
+		return c.await(ctx, func(event Event) bool {
+			return event.Kind == EventDocument
+		})
+	})
+}
+
+// AnswerUntil answers every question of the bot with the text until a reply shows the button
+// of the callback data; it fails after maxAnswers answers
+func (s *Scenario) AnswerUntil(text, data string, maxAnswers int) *Scenario {
+	return s.step(fmt.Sprintf("answer %q until %s", shorten(text), data), func(ctx context.Context, c *Conversation) error {
+		for answers := 0; ; answers++ {
+			for _, event := range c.replies() {
+				if event.HasButton(data) {
+					return nil
+				}
+			}
+			if answers == maxAnswers {
+				return fmt.Errorf("no button %q after %d answers, got:\n%s", data, maxAnswers, c.transcript())
+			}
+
+			err := c.act(ctx, "answer", func() error {
+				c.server.SendText(c.UserID, text)
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+		}
+	})
+}
+
+// HasVoice reports whether the scenario sends voice messages, which the bot converts with ffmpeg
+func (s *Scenario) HasVoice() bool {
+	return s.voice
+}
+
+func (s *Scenario) step(name string, run func(ctx context.Context, c *Conversation) error) *Scenario {
+	s.Steps = append(s.Steps, Step{Name: name, Run: run})
+	return s
+}
+
+// Run plays the scenario as the user against the bot served by the server
+func (s *Scenario) Run(ctx context.Context, server *Server, userID int64) error {
+	return s.Play(ctx, NewConversation(server, userID))
+}
+
+// Play plays the scenario in the conversation
+func (s *Scenario) Play(ctx context.Context, c *Conversation) error {
+	for i, step := range s.Steps {
+		if err := step.Run(ctx, c); err != nil {
+			return fmt.Errorf("scenario '%s' step %d (%s): %w", s.Name, i+1, step.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// Conversation is the state of a running scenario
+type Conversation struct {
+	UserID  int64
+	Quiet   time.Duration
+	Timeout time.Duration
+	// Think returns the pause of the user before an action, none when nil
+	Think func() time.Duration
+	// OnReply is called with the time the bot took to reply to an action, e.g. "press action:generate"
+	OnReply func(action string, latency time.Duration)
+
+	server *Server
+	cursor int // index of the first event replying to the last action
+	last   int // index after the last event replying to the last action
+}
+
+// NewConversation creates a conversation of the user with the bot served by the server
+func NewConversation(server *Server, userID int64) *Conversation {
+	return &Conversation{
+		UserID:  userID,
+		Quiet:   defaultQuiet,
+		Timeout: defaultStepTimeout,
+		server:  server,
+	}
+}
+
+// act performs an action of the user and waits until the bot replied to it and went silent in the chat
+func (c *Conversation) act(ctx context.Context, name string, action func() error) error {
+	if c.Think != nil {
+		select {
+		case <-time.After(c.Think()):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	c.cursor = len(c.server.Events(c.UserID))
+	c.last = c.cursor
+	started := time.Now()
+	if err := action(); err != nil {
+		return err
+	}
+	replied := false
+
+	ctx, cancel := context.WithTimeout(ctx, c.Timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("bot did not reply within %s, got:\n%s", c.Timeout, c.transcript())
+		case <-ticker.C:
+		}
+
+		events := c.server.Events(c.UserID)
+		if len(events) > c.cursor && !replied {
+			replied = true
+			if c.OnReply != nil {
+				c.OnReply(name, events[c.cursor].At.Sub(started))
+			}
+		}
+		if len(events) > c.cursor && c.server.Idle(c.UserID) >= c.Quiet {
+			c.last = len(events)
+			return nil
+		}
+	}
+}
+
+// await waits for a reply to the last action matching the expectation and makes the
+// replies up to it part of the action
+func (c *Conversation) await(ctx context.Context, match func(Event) bool) error {
+	ctx, cancel := context.WithTimeout(ctx, c.Timeout)
+	defer cancel()
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		events := c.server.Events(c.UserID)
+		for i := c.cursor; i < len(events); i++ {
+			if match(events[i]) {
+				if c.last < i+1 {
+					c.last = i + 1
+				}
+				return nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("no matching reply within %s, got:\n%s", c.Timeout, c.transcript())
+		case <-ticker.C:
+		}
+	}
+}
+
+// replies returns the events replying to the last action
+func (c *Conversation) replies() []Event {
+	events := c.server.Events(c.UserID)
+	if c.last > len(events) {
+		return nil
+	}
+	return events[c.cursor:c.last]
+}
+
+// transcript renders the events since the last action for failure messages
+func (c *Conversation) transcript() string {
+	events := c.server.Events(c.UserID)
+	if c.cursor >= len(events) {
+		return "  (no replies)"
+	}
+
+	var b strings.Builder
+	for _, event := range events[c.cursor:] {
+		fmt.Fprintf(&b, "  [%s] %s", event.Kind, shorten(event.Text))
+		if event.FileName != "" {
+			fmt.Fprintf(&b, " (%s)", event.FileName)
+		}
+		if event.Markup != nil {
+			var buttons []string
+			for _, row := range event.Markup.InlineKeyboard {
+				for _, button := range row {
+					if button.CallbackData != nil {
+						buttons = append(buttons, *button.CallbackData)
+					}
+				}
+			}
+			fmt.Fprintf(&b, " %v", buttons)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+func shorten(text string) string {
+	const maxRunes = 80
+	text = strings.ReplaceAll(text, "\n", " ")
+	if runes := []rune(text); len(runes) > maxRunes {
+		return string(runes[:maxRunes]) + "…"
+	}
+	return text
+}
@@ -0,0 +1,136 @@
+package fakeapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/futig/agent-backend/internal/config"
+)
+
+const (
+	scenarioGoal = "Нужен сервис бронирования переговорных комнат для сотрудников офиса с календарём, " +
+		"уведомлениями и отчётами о загрузке комнат для администраторов"
+	scenarioContext = "Компания на 300 сотрудников, два офиса. Сейчас комнаты бронируют в общей таблице, " +
+		"часто бывают пересечения. Пользователи — сотрудники и офис-менеджеры"
+	scenarioAnswer = "Бронировать должны все сотрудники, отменять — автор брони и офис-менеджер. " +
+		"Бронь на срок от 15 минут до 8 часов, не дальше чем на месяц вперёд"
+	scenarioDraft = "Черновик: сотрудник выбирает комнату и время в календаре, система проверяет пересечения " +
+		"и присылает напоминание за 10 минут. Офис-менеджер видит отчёт о загрузке за неделю"
+
+	// Voice messages of the scenarios, transcribed by the canned transcripts of their file names
+	goalVoice   = "goal.ogg"
+	answerVoice = "answer.ogg"
+	draftVoice  = "draft.ogg"
+)
+
+// Transcripts returns the canned transcripts of the voice messages of the built-in scenarios
+func Transcripts() []config.MockTranscript {
+	return []config.MockTranscript{
+		{FileName: goalVoice, Text: scenarioGoal},
+		{FileName: answerVoice, Text: scenarioAnswer},
+		{FileName: draftVoice, Text: scenarioDraft},
+	}
+}
+
+// writeTranscripts writes Transcripts to the file in the format of ASR_MOCK_TRANSCRIPTS_FILE
+func writeTranscripts(path string) error {
+	data, err := json.MarshalIndent(struct {
+		Transcripts []config.MockTranscript `json:"transcripts"`
+	}{Transcripts()}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal transcripts: %w", err)
+	}
+
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("write transcripts: %w", err)
+	}
+	return nil
+}
+
+// Scenarios returns the built-in conversations that must keep working: the onboarding of a new
+// user followed by the interview and the draft flows up to the downloaded result
+func Scenarios() []*Scenario {
+	return []*Scenario{
+		interviewScenario(),
+		completeInterviewScenario(),
+		draftScenario(),
+		voiceInterviewScenario(),
+		voiceDraftScenario(),
+	}
+}
+
+// startSession plays the way of a new user from /start to the mode selection, telling the goal in text
+func startSession(s *Scenario) *Scenario {
+	return startSessionWith(s, func(s *Scenario) *Scenario { return s.Say(scenarioGoal) })
+}
+
+// startSessionWith plays the way of a new user from /start to the mode selection, telling the goal with tell
+func startSessionWith(s *Scenario, tell func(s *Scenario) *Scenario) *Scenario {
+	s = s.
+		// The first /start of a user opens the tutorial, the next one the welcome message
+		Command("start").ExpectButton("tutorial:").
+		Command("start").ExpectButton("action:start").
+		Press("action:start").Expect("О чём проект?")
+	return tell(s).ExpectButton("proj:none").
+		Press("proj:none").Expect("Ответь, пожалуйста, на несколько вопросов о проекте").
+		Say(scenarioContext).ExpectButton("mode:interview")
+}
+
+// downloadResult expects the generated result and downloads it
+func downloadResult(s *Scenario) *Scenario {
+	return s.
+		Expect("Бизнес-требования сформированы").ExpectButton("dl:markdown").
+		Press("dl:markdown").ExpectDocument()
+}
+
+// interviewScenario answers the first question of the interview and generates the result early
+func interviewScenario() *Scenario {
+	s := startSession(NewScenario("interview")).
+		Press("mode:interview").ExpectButton("action:start_interview").
+		Press("action:start_interview").ExpectButton("skip:").
+		Say(scenarioAnswer).ExpectButton("action:generate").
+		Press("action:generate")
+	return downloadResult(s)
+}
+
+// completeInterviewScenario answers every question until the interview ends with the result
+func completeInterviewScenario() *Scenario {
+	s := startSession(NewScenario("complete interview")).
+		Press("mode:interview").ExpectButton("action:start_interview").
+		Press("action:start_interview").ExpectButton("skip:").
+		AnswerUntil(scenarioAnswer, "dl:markdown", 40)
+	return downloadResult(s)
+}
+
+// draftScenario collects draft materials and generates the result from them
+func draftScenario() *Scenario {
+	s := startSession(NewScenario("draft")).
+		Press("mode:draft").ExpectButton("action:start_draft").
+		Press("action:start_draft").
+		Say(scenarioDraft).ExpectButton("action:generate").
+		Say(scenarioAnswer).ExpectButton("action:generate").
+		Press("action:generate")
+	return downloadResult(s)
+}
+
+// voiceInterviewScenario tells the goal and answers the first question by voice
+func voiceInterviewScenario() *Scenario {
+	s := startSessionWith(NewScenario("voice interview"), func(s *Scenario) *Scenario { return s.Voice(goalVoice) }).
+		Press("mode:interview").ExpectButton("action:start_interview").
+		Press("action:start_interview").ExpectButton("skip:").
+		Voice(answerVoice).Expect("Принял ответ").ExpectButton("action:generate").
+		Press("action:generate")
+	return downloadResult(s)
+}
+
+// voiceDraftScenario dictates the draft materials
+func voiceDraftScenario() *Scenario {
+	s := startSession(NewScenario("voice draft")).
+		Press("mode:draft").ExpectButton("action:start_draft").
+		Press("action:start_draft").
+		Voice(draftVoice).ExpectButton("action:generate").
+		Voice(answerVoice).ExpectButton("action:generate").
+		Press("action:generate")
+	return downloadResult(s)
+}
@@ -0,0 +1,64 @@
+package fakeapi_test
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/futig/agent-backend/internal/builder"
+	"github.com/futig/agent-backend/internal/telegram/fakeapi"
+)
+
+// TestScenarios plays the built-in interview and draft flows, in text and by voice, against the
+// real bot handlers; it needs the Postgres of DATABASE_URL, e.g. the test database of CI
+func TestScenarios(t *testing.T) {
+	if testing.Short() {
+		t.Skip("bot scenarios are slow")
+	}
+	if os.Getenv("DATABASE_URL") == "" {
+		t.Skip("DATABASE_URL is not set")
+	}
+	_, ffmpegErr := exec.LookPath("ffmpeg")
+
+	// The configuration is read relative to the repository root
+	t.Chdir("../../..")
+
+	server := fakeapi.NewServer()
+	t.Cleanup(server.Close)
+	for key, value := range server.BotEnv() {
+		t.Setenv(key, value)
+	}
+
+	bot, _, err := builder.BuildTelegramBot()
+	if err != nil {
+		t.Fatalf("BuildTelegramBot() error = %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := bot.Start(ctx); err != nil {
+		cancel()
+		t.Fatalf("Start() error = %v", err)
+	}
+	t.Cleanup(func() {
+		cancel()
+		if err := bot.Stop(); err != nil {
+			t.Errorf("Stop() error = %v", err)
+		}
+	})
+
+	// Every run plays new users, so onboarding starts from scratch
+	baseUserID := time.Now().UnixMilli() * 10
+
+	for i, scenario := range fakeapi.Scenarios() {
+		t.Run(scenario.Name, func(t *testing.T) {
+			if scenario.HasVoice() && ffmpegErr != nil {
+				t.Skip("voice messages need ffmpeg")
+			}
+			if err := scenario.Run(ctx, server, baseUserID+int64(i)); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
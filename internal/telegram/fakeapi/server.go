@@ -0,0 +1,653 @@
+// Package fakeapi is an in-process fake of the Telegram Bot API. The bot talks to it instead
+// of Telegram, and scenarios act as users, so whole conversations run against the real handlers.
+package fakeapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// BotID is the user ID of the bot behind the fake API
+const BotID int64 = 7000000001
+
+const maxMultipartMemory = 32 << 20
+
+// EventKind is what the bot did in a chat
+type EventKind string
+
+const (
+	EventMessage  EventKind = "message"  // a message was sent
+	EventEdit     EventKind = "edit"     // the text or the keyboard of a message was edited
+	EventDocument EventKind = "document" // a document was sent
+	EventAnswer   EventKind = "answer"   // a callback query was answered with a notification
+)
+
+// Event is one action of the bot in a chat
+type Event struct {
+	Kind      EventKind
+	MessageID int
+	Text      string
+	Markup    *tgbotapi.InlineKeyboardMarkup
+	FileName  string // name of a sent document
+	At        time.Time
+}
+
+// HasButton reports whether the event shows a button with the callback data
+func (e Event) HasButton(data string) bool {
+	return findButton(e.Markup, data) != nil
+}
+
+// file is a file of a user the bot can download, e.g. a voice message
+type file struct {
+	path string
+	data []byte
+}
+
+// chat is the conversation of the bot with one user
+type chat struct {
+	events       []Event
+	messages     map[int]*tgbotapi.Message // messages of the bot by ID, deleted ones removed
+	lastActivity time.Time
+}
+
+// Server is a fake Bot API serving any token. It keeps the updates of simulated users for
+// getUpdates and records what the bot sends to every chat.
+type Server struct {
+	http           *httptest.Server
+	transcriptsDir string // holds the canned transcripts of the scenario voices
+
+	mu            sync.Mutex
+	updates       []tgbotapi.Update
+	nextUpdateID  int
+	nextMessageID int
+	chats         map[int64]*chat
+	files         map[string]file // by file ID
+	newUpdate     chan struct{}   // closed and replaced when an update is queued
+}
+
+// NewServer starts a fake Bot API on a local port; like httptest, it panics when it fails
+func NewServer() *Server {
+	transcriptsDir, err := os.MkdirTemp("", "fakeapi")
+	if err != nil {
+		panic(fmt.Sprintf("fakeapi: create transcripts dir: %v", err))
+	}
+	if err := writeTranscripts(filepath.Join(transcriptsDir, "transcripts.json")); err != nil {
+		panic(fmt.Sprintf("fakeapi: %v", err))
+	}
+
+	s := &Server{
+		transcriptsDir: transcriptsDir,
+		nextUpdateID:   1,
+		nextMessageID:  1,
+		chats:          make(map[int64]*chat),
+		files:          make(map[string]file),
+		newUpdate:      make(chan struct{}),
+	}
+	s.http = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// APIEndpoint returns the endpoint of the server in the format of TELEGRAM_API_ENDPOINT
+func (s *Server) APIEndpoint() string {
+	return s.http.URL + "/bot%s/%s"
+}
+
+// FileEndpoint returns the file download endpoint of the server in the format of TELEGRAM_FILE_ENDPOINT
+func (s *Server) FileEndpoint() string {
+	return s.http.URL + "/file/bot%s/%s"
+}
+
+// BotEnv returns the environment that makes the bot of the process talk to the server with
+// mocked external services; the rate limits are raised for scripted users and the ASR mock
+// transcribes the scenario voices with Transcripts
+func (s *Server) BotEnv() map[string]string {
+	return map[string]string{
+		"TELEGRAM_API_ENDPOINT":          s.APIEndpoint(),
+		"TELEGRAM_FILE_ENDPOINT":         s.FileEndpoint(),
+		"TELEGRAM_BOT_TOKEN":             "scenario:token",
+		"TELEGRAM_USE_WEBHOOK":           "false",
+		"TELEGRAM_WEBHOOK_URL":           "http://localhost",
+		"TELEGRAM_RATE_LIMIT_PER_MINUTE": "60",
+		"TELEGRAM_RATE_LIMIT_BURST":      "20",
+		"ENABLE_MOCKS":                   "true",
+		"ASR_MOCK_TRANSCRIPTS_FILE":      filepath.Join(s.transcriptsDir, "transcripts.json"),
+	}
+}
+
+// Close stops the server
+func (s *Server) Close() {
+	s.http.Close()
+	_ = os.RemoveAll(s.transcriptsDir)
+}
+
+// SendText queues a text message of the user
+func (s *Server) SendText(userID int64, text string) {
+	s.queueMessage(userID, text, nil)
+}
+
+// SendCommand queues a /command of the user
+func (s *Server) SendCommand(userID int64, command string) {
+	text := "/" + strings.TrimPrefix(command, "/")
+	name, _, _ := strings.Cut(text, " ")
+	s.queueMessage(userID, text, []tgbotapi.MessageEntity{{
+		Type:   "bot_command",
+		Offset: 0,
+		Length: len(name),
+	}})
+}
+
+// SendVoice queues a voice message of the user; the bot downloads the audio as
+// voice/<file ID>/<fileName>, so canned transcripts of the ASR mock can be keyed by the file name
+func (s *Server) SendVoice(userID int64, fileName string, audio []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	fileID := fmt.Sprintf("voice-%d", len(s.files)+1)
+	s.files[fileID] = file{path: "voice/" + fileID + "/" + fileName, data: audio}
+
+	s.queueUpdate(tgbotapi.Update{
+		Message: &tgbotapi.Message{
+			MessageID: s.messageID(),
+			From:      user(userID),
+			Chat:      privateChat(userID),
+			Date:      int(time.Now().Unix()),
+			Voice: &tgbotapi.Voice{
+				FileID:       fileID,
+				FileUniqueID: fileID,
+				Duration:     1,
+				MimeType:     "audio/ogg",
+				FileSize:     len(audio),
+			},
+		},
+	})
+	s.touch(s.chat(userID))
+}
+
+// PressButton queues a press of the button with the callback data on the latest message of
+// the bot that shows it; data that is no exact match selects the first button it prefixes
+func (s *Server) PressButton(userID int64, data string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c := s.chat(userID)
+	var (
+		message *tgbotapi.Message
+		button  *tgbotapi.InlineKeyboardButton
+	)
+	for _, candidate := range c.messages {
+		if message != nil && candidate.MessageID < message.MessageID {
+			continue
+		}
+		if found := findButton(candidate.ReplyMarkup, data); found != nil {
+			message, button = candidate, found
+		}
+	}
+	if button == nil {
+		return fmt.Errorf("no message in chat %d has a button '%s'", userID, data)
+	}
+
+	pressed := *message
+	s.queueUpdate(tgbotapi.Update{
+		CallbackQuery: &tgbotapi.CallbackQuery{
+			ID:           strconv.Itoa(s.nextUpdateID),
+			From:         user(userID),
+			Message:      &pressed,
+			ChatInstance: strconv.FormatInt(userID, 10),
+			Data:         *button.CallbackData,
+		},
+	})
+	s.touch(c)
+	return nil
+}
+
+// Events returns the actions of the bot in the chat of the user, oldest first
+func (s *Server) Events(userID int64) []Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Event(nil), s.chat(userID).events...)
+}
+
+// Idle reports how long the bot has done nothing in the chat of the user
+func (s *Server) Idle(userID int64) time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return time.Since(s.chat(userID).lastActivity)
+}
+
+func (s *Server) queueMessage(userID int64, text string, entities []tgbotapi.MessageEntity) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.queueUpdate(tgbotapi.Update{
+		Message: &tgbotapi.Message{
+			MessageID: s.messageID(),
+			From:      user(userID),
+			Chat:      privateChat(userID),
+			Date:      int(time.Now().Unix()),
+			Text:      text,
+			Entities:  entities,
+		},
+	})
+	s.touch(s.chat(userID))
+}
+
+// queueUpdate adds the update for getUpdates and wakes up a waiting long poll; s.mu must be held
+func (s *Server) queueUpdate(update tgbotapi.Update) {
+	update.UpdateID = s.nextUpdateID
+	s.nextUpdateID++
+	s.updates = append(s.updates, update)
+
+	close(s.newUpdate)
+	s.newUpdate = make(chan struct{})
+}
+
+// chat returns the chat of the user, creating it; s.mu must be held
+func (s *Server) chat(chatID int64) *chat {
+	c, ok := s.chats[chatID]
+	if !ok {
+		c = &chat{
+			messages:     make(map[int]*tgbotapi.Message),
+			lastActivity: time.Now(),
+		}
+		s.chats[chatID] = c
+	}
+	return c
+}
+
+// messageID returns a new message ID; s.mu must be held
+func (s *Server) messageID() int {
+	id := s.nextMessageID
+	s.nextMessageID++
+	return id
+}
+
+func (s *Server) touch(c *chat) {
+	c.lastActivity = time.Now()
+}
+
+func (s *Server) record(chatID int64, event Event) {
+	c := s.chat(chatID)
+	event.At = time.Now()
+	c.events = append(c.events, event)
+	s.touch(c)
+}
+
+// serveHTTP answers /bot<token>/<method> requests like the Bot API and serves files on
+// /file/bot<token>/<path>
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/")
+	if filePath, ok := strings.CutPrefix(path, "file/bot"); ok {
+		s.serveFile(w, r, filePath)
+		return
+	}
+	if !strings.HasPrefix(path, "bot") {
+		http.NotFound(w, r)
+		return
+	}
+	_, method, ok := strings.Cut(path, "/")
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		if err := r.ParseMultipartForm(maxMultipartMemory); err != nil {
+			writeError(w, http.StatusBadRequest, "Bad Request: "+err.Error())
+			return
+		}
+	} else if err := r.ParseForm(); err != nil {
+		writeError(w, http.StatusBadRequest, "Bad Request: "+err.Error())
+		return
+	}
+
+	var (
+		result any
+		err    error
+	)
+	switch method {
+	case "getMe":
+		result = botUser()
+	case "getUpdates":
+		result = s.getUpdates(r)
+	case "getFile":
+		result, err = s.getFile(r)
+	case "sendMessage":
+		result, err = s.sendMessage(r)
+	case "editMessageText", "editMessageReplyMarkup":
+		result, err = s.editMessage(r, method == "editMessageText")
+	case "sendDocument":
+		result, err = s.sendDocument(r)
+	case "answerCallbackQuery":
+		s.answerCallbackQuery(r)
+		result = true
+	case "deleteMessage":
+		err = s.deleteMessage(r)
+		result = true
+	default:
+		// sendChatAction, setMyCommands, setWebhook and the like only succeed
+		result = true
+	}
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "Bad Request: "+err.Error())
+		return
+	}
+
+	raw, err := json.Marshal(result)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, tgbotapi.APIResponse{Ok: true, Result: raw})
+}
+
+// getUpdates returns the updates from the offset on, holding the request up to its timeout
+// while there are none
+func (s *Server) getUpdates(r *http.Request) []tgbotapi.Update {
+	offset, _ := strconv.Atoi(r.FormValue("offset"))
+	timeout, _ := strconv.Atoi(r.FormValue("timeout"))
+	deadline := time.After(time.Duration(timeout) * time.Second)
+
+	for {
+		s.mu.Lock()
+		var pending []tgbotapi.Update
+		for _, update := range s.updates {
+			if update.UpdateID >= offset {
+				pending = append(pending, update)
+			}
+		}
+		wait := s.newUpdate
+		s.mu.Unlock()
+
+		if len(pending) > 0 || timeout <= 0 {
+			return pending
+		}
+
+		select {
+		case <-wait:
+		case <-deadline:
+			return []tgbotapi.Update{}
+		case <-r.Context().Done():
+			return []tgbotapi.Update{}
+		}
+	}
+}
+
+func (s *Server) getFile(r *http.Request) (*tgbotapi.File, error) {
+	fileID := r.FormValue("file_id")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	f, ok := s.files[fileID]
+	if !ok {
+		return nil, fmt.Errorf("invalid file_id")
+	}
+	return &tgbotapi.File{
+		FileID:       fileID,
+		FileUniqueID: fileID,
+		FileSize:     len(f.data),
+		FilePath:     f.path,
+	}, nil
+}
+
+// serveFile serves the file of the <token>/<path> request path
+func (s *Server) serveFile(w http.ResponseWriter, r *http.Request, tokenPath string) {
+	_, filePath, _ := strings.Cut(tokenPath, "/")
+
+	s.mu.Lock()
+	var data []byte
+	for _, f := range s.files {
+		if f.path == filePath {
+			data = f.data
+			break
+		}
+	}
+	s.mu.Unlock()
+
+	if data == nil {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/octet-stream")
+	_, _ = w.Write(data)
+}
+
+func (s *Server) sendMessage(r *http.Request) (*tgbotapi.Message, error) {
+	chatID, err := strconv.ParseInt(r.FormValue("chat_id"), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("chat not found")
+	}
+	markup, err := parseMarkup(r.FormValue("reply_markup"))
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	message := &tgbotapi.Message{
+		MessageID:   s.messageID(),
+		From:        botUser(),
+		Chat:        privateChat(chatID),
+		Date:        int(time.Now().Unix()),
+		Text:        r.FormValue("text"),
+		ReplyMarkup: markup,
+	}
+	s.chat(chatID).messages[message.MessageID] = message
+	s.record(chatID, Event{
+		Kind:      EventMessage,
+		MessageID: message.MessageID,
+		Text:      message.Text,
+		Markup:    markup,
+	})
+
+	return message, nil
+}
+
+func (s *Server) editMessage(r *http.Request, withText bool) (*tgbotapi.Message, error) {
+	chatID, err := strconv.ParseInt(r.FormValue("chat_id"), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("chat not found")
+	}
+	messageID, err := strconv.Atoi(r.FormValue("message_id"))
+	if err != nil {
+		return nil, fmt.Errorf("message to edit not found")
+	}
+	markup, err := parseMarkup(r.FormValue("reply_markup"))
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	message, ok := s.chat(chatID).messages[messageID]
+	if !ok {
+		return nil, fmt.Errorf("message to edit not found")
+	}
+	if withText {
+		message.Text = r.FormValue("text")
+	}
+	// Like in Telegram, an edit without a keyboard removes the keyboard of the message
+	message.ReplyMarkup = markup
+
+	s.record(chatID, Event{
+		Kind:      EventEdit,
+		MessageID: message.MessageID,
+		Text:      message.Text,
+		Markup:    markup,
+	})
+
+	edited := *message
+	return &edited, nil
+}
+
+func (s *Server) sendDocument(r *http.Request) (*tgbotapi.Message, error) {
+	chatID, err := strconv.ParseInt(r.FormValue("chat_id"), 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("chat not found")
+	}
+	markup, err := parseMarkup(r.FormValue("reply_markup"))
+	if err != nil {
+		return nil, err
+	}
+
+	fileName := r.FormValue("document")
+	if r.MultipartForm != nil {
+		if files := r.MultipartForm.File["document"]; len(files) > 0 {
+			fileName = files[0].Filename
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	message := &tgbotapi.Message{
+		MessageID: s.messageID(),
+		From:      botUser(),
+		Chat:      privateChat(chatID),
+		Date:      int(time.Now().Unix()),
+		Caption:   r.FormValue("caption"),
+		Document: &tgbotapi.Document{
+			FileID:   fmt.Sprintf("document-%d", s.nextMessageID),
+			FileName: fileName,
+		},
+		ReplyMarkup: markup,
+	}
+	s.chat(chatID).messages[message.MessageID] = message
+	s.record(chatID, Event{
+		Kind:      EventDocument,
+		MessageID: message.MessageID,
+		Text:      message.Caption,
+		Markup:    markup,
+		FileName:  fileName,
+	})
+
+	return message, nil
+}
+
+// answerCallbackQuery records the notification of the answer; the query ID is the chat it came from
+func (s *Server) answerCallbackQuery(r *http.Request) {
+	text := r.FormValue("text")
+	if text == "" {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, update := range s.updates {
+		query := update.CallbackQuery
+		if query != nil && query.ID == r.FormValue("callback_query_id") {
+			s.record(query.From.ID, Event{Kind: EventAnswer, Text: text})
+			return
+		}
+	}
+}
+
+func (s *Server) deleteMessage(r *http.Request) error {
+	chatID, err := strconv.ParseInt(r.FormValue("chat_id"), 10, 64)
+	if err != nil {
+		return fmt.Errorf("chat not found")
+	}
+	messageID, _ := strconv.Atoi(r.FormValue("message_id"))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c := s.chat(chatID)
+	if _, ok := c.messages[messageID]; !ok {
+		return fmt.Errorf("message to delete not found")
+	}
+	delete(c.messages, messageID)
+	s.touch(c)
+	return nil
+}
+
+// parseMarkup decodes the inline keyboard of a request; other reply markups are ignored
+func parseMarkup(raw string) (*tgbotapi.InlineKeyboardMarkup, error) {
+	if raw == "" {
+		return nil, nil
+	}
+
+	var markup tgbotapi.InlineKeyboardMarkup
+	if err := json.Unmarshal([]byte(raw), &markup); err != nil {
+		return nil, fmt.Errorf("can't parse reply keyboard markup JSON object")
+	}
+	if len(markup.InlineKeyboard) == 0 {
+		return nil, nil
+	}
+	return &markup, nil
+}
+
+// findButton returns the callback button with the data, or else the first one the data prefixes
+func findButton(markup *tgbotapi.InlineKeyboardMarkup, data string) *tgbotapi.InlineKeyboardButton {
+	if markup == nil {
+		return nil
+	}
+
+	var prefixed *tgbotapi.InlineKeyboardButton
+	for i := range markup.InlineKeyboard {
+		for j := range markup.InlineKeyboard[i] {
+			button := &markup.InlineKeyboard[i][j]
+			if button.CallbackData == nil {
+				continue
+			}
+			if *button.CallbackData == data {
+				return button
+			}
+			if prefixed == nil && strings.HasPrefix(*button.CallbackData, data) {
+				prefixed = button
+			}
+		}
+	}
+	return prefixed
+}
+
+func botUser() *tgbotapi.User {
+	return &tgbotapi.User{
+		ID:        BotID,
+		IsBot:     true,
+		FirstName: "Fake",
+		UserName:  "fake_agent_bot",
+	}
+}
+
+func user(userID int64) *tgbotapi.User {
+	return &tgbotapi.User{
+		ID:           userID,
+		FirstName:    "Scenario",
+		UserName:     fmt.Sprintf("scenario_%d", userID),
+		LanguageCode: "ru",
+	}
+}
+
+func privateChat(chatID int64) *tgbotapi.Chat {
+	return &tgbotapi.Chat{ID: chatID, Type: "private"}
+}
+
+func writeError(w http.ResponseWriter, status int, description string) {
+	writeJSON(w, status, tgbotapi.APIResponse{
+		Ok:          false,
+		ErrorCode:   status,
+		Description: description,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, response tgbotapi.APIResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(response)
+}
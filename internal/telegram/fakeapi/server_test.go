@@ -0,0 +1,213 @@
+package fakeapi
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+	"time"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+const testToken = "test:token"
+
+func newTestBot(t *testing.T) (*Server, *tgbotapi.BotAPI) {
+	t.Helper()
+
+	server := NewServer()
+	t.Cleanup(server.Close)
+
+	bot, err := tgbotapi.NewBotAPIWithAPIEndpoint(testToken, server.APIEndpoint())
+	if err != nil {
+		t.Fatalf("NewBotAPIWithAPIEndpoint() error = %v", err)
+	}
+	return server, bot
+}
+
+func TestServerMessagesAndButtons(t *testing.T) {
+	server, bot := newTestBot(t)
+	const userID = 42
+
+	server.SendCommand(userID, "start")
+	updates, err := bot.GetUpdates(tgbotapi.NewUpdate(0))
+	if err != nil {
+		t.Fatalf("GetUpdates() error = %v", err)
+	}
+	if len(updates) != 1 || !updates[0].Message.IsCommand() || updates[0].Message.Command() != "start" {
+		t.Fatalf("GetUpdates() = %+v, want the /start command", updates)
+	}
+
+	msg := tgbotapi.NewMessage(userID, "Выберите режим")
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("Интервью", "mode:interview"),
+		tgbotapi.NewInlineKeyboardButtonData("Черновик", "mode:draft"),
+	))
+	if _, err := bot.Send(msg); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	events := server.Events(userID)
+	if len(events) != 1 || events[0].Kind != EventMessage || events[0].Text != "Выберите режим" {
+		t.Fatalf("Events() = %+v, want the sent message", events)
+	}
+	if !events[0].HasButton("mode:draft") {
+		t.Error("the sent message has no button mode:draft")
+	}
+
+	if err := server.PressButton(userID, "mode:"); err != nil {
+		t.Fatalf("PressButton() error = %v", err)
+	}
+	updates, err = bot.GetUpdates(tgbotapi.NewUpdate(updates[0].UpdateID + 1))
+	if err != nil {
+		t.Fatalf("GetUpdates() error = %v", err)
+	}
+	if len(updates) != 1 || updates[0].CallbackQuery == nil || updates[0].CallbackQuery.Data != "mode:interview" {
+		t.Fatalf("GetUpdates() = %+v, want the press of mode:interview", updates)
+	}
+
+	if err := server.PressButton(userID, "action:generate"); err == nil {
+		t.Error("PressButton() of a button that was never sent succeeded, want an error")
+	}
+}
+
+func TestServerVoice(t *testing.T) {
+	server, bot := newTestBot(t)
+	const userID = 42
+	audio := SilentVoice(time.Second)
+
+	server.SendVoice(userID, "goal.ogg", audio)
+	updates, err := bot.GetUpdates(tgbotapi.NewUpdate(0))
+	if err != nil {
+		t.Fatalf("GetUpdates() error = %v", err)
+	}
+	if len(updates) != 1 || updates[0].Message.Voice == nil {
+		t.Fatalf("GetUpdates() = %+v, want a voice message", updates)
+	}
+	voice := updates[0].Message.Voice
+	if voice.FileSize != len(audio) {
+		t.Errorf("voice file size = %d, want %d", voice.FileSize, len(audio))
+	}
+
+	file, err := bot.GetFile(tgbotapi.FileConfig{FileID: voice.FileID})
+	if err != nil {
+		t.Fatalf("GetFile() error = %v", err)
+	}
+	if path.Base(file.FilePath) != "goal.ogg" {
+		t.Errorf("file path = %q, want the base name goal.ogg", file.FilePath)
+	}
+
+	resp, err := http.Get(fmt.Sprintf(server.FileEndpoint(), testToken, file.FilePath))
+	if err != nil {
+		t.Fatalf("download error = %v", err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("read download error = %v", err)
+	}
+	if resp.StatusCode != http.StatusOK || !bytes.Equal(data, audio) {
+		t.Errorf("download = %d with %d bytes, want 200 with the %d bytes of the voice", resp.StatusCode, len(data), len(audio))
+	}
+
+	if _, err := bot.GetFile(tgbotapi.FileConfig{FileID: "unknown"}); err == nil {
+		t.Error("GetFile() of an unknown file succeeded, want an error")
+	}
+}
+
+func TestSilentVoice(t *testing.T) {
+	// 6 s are 300 frames, more than the 255 packets of a page
+	audio := SilentVoice(6 * time.Second)
+
+	var (
+		pages   int
+		packets int
+		granule uint64
+		last    byte
+	)
+	for rest := audio; len(rest) > 0; pages++ {
+		if len(rest) < 27 || string(rest[:4]) != "OggS" {
+			t.Fatalf("page %d has no Ogg header", pages)
+		}
+		segments := int(rest[26])
+		size := 27 + segments
+		for _, lacing := range rest[27 : 27+segments] {
+			size += int(lacing)
+		}
+		page := bytes.Clone(rest[:size])
+
+		want := binary.LittleEndian.Uint32(page[22:26])
+		binary.LittleEndian.PutUint32(page[22:26], 0)
+		if got := oggChecksum(page); got != want {
+			t.Errorf("page %d checksum = %#x, want %#x", pages, got, want)
+		}
+
+		switch pages {
+		case 0:
+			if !bytes.HasPrefix(page[27+segments:], []byte("OpusHead")) {
+				t.Error("the first page holds no OpusHead")
+			}
+		case 1:
+			if !bytes.HasPrefix(page[27+segments:], []byte("OpusTags")) {
+				t.Error("the second page holds no OpusTags")
+			}
+		default:
+			packets += segments
+		}
+		granule = binary.LittleEndian.Uint64(page[6:14])
+		last = page[5]
+		rest = rest[size:]
+	}
+
+	if packets != 300 {
+		t.Errorf("audio packets = %d, want 300", packets)
+	}
+	if granule != 300*opusFrameSamples {
+		t.Errorf("last granule position = %d, want %d", granule, 300*opusFrameSamples)
+	}
+	if last&0x04 == 0 {
+		t.Error("the last page does not end the stream")
+	}
+}
+
+func TestBotEnvTranscripts(t *testing.T) {
+	server := NewServer()
+	defer server.Close()
+
+	data, err := os.ReadFile(server.BotEnv()["ASR_MOCK_TRANSCRIPTS_FILE"])
+	if err != nil {
+		t.Fatalf("read transcripts file error = %v", err)
+	}
+
+	var file struct {
+		Transcripts []struct {
+			FileName string `json:"file_name"`
+			Text     string `json:"text"`
+		} `json:"transcripts"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		t.Fatalf("parse transcripts file error = %v", err)
+	}
+
+	want := Transcripts()
+	if len(file.Transcripts) != len(want) {
+		t.Fatalf("transcripts = %d, want %d", len(file.Transcripts), len(want))
+	}
+	for i, transcript := range file.Transcripts {
+		if transcript.FileName != want[i].FileName || transcript.Text != want[i].Text {
+			t.Errorf("transcript %d = %+v, want %+v", i, transcript, want[i])
+		}
+	}
+
+	dir := filepath.Dir(server.BotEnv()["ASR_MOCK_TRANSCRIPTS_FILE"])
+	server.Close()
+	if _, err := os.Stat(dir); !os.IsNotExist(err) {
+		t.Errorf("transcripts dir exists after Close, stat error = %v", err)
+	}
+}
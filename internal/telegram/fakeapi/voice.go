@@ -0,0 +1,97 @@
+package fakeapi
+
+import (
+	"bytes"
+	"encoding/binary"
+	"time"
+)
+
+const (
+	// opusFrameSamples is the length of a 20 ms Opus frame at the 48 kHz granule rate of Ogg Opus
+	opusFrameSamples = 960
+	// opusPreSkip is the number of samples the decoder drops at the start
+	opusPreSkip = 312
+	// oggSerial identifies the only logical stream of the generated files
+	oggSerial = 0x46414b45
+)
+
+// opusSilence is a mono 20 ms CELT frame of silence
+var opusSilence = []byte{0xf8, 0xff, 0xfe}
+
+// SilentVoice returns a voice message of silence in the OGG/Opus format of Telegram voices,
+// lasting d rounded up to 20 ms; the bot converts it like any recorded voice
+func SilentVoice(d time.Duration) []byte {
+	frames := max(int((d+20*time.Millisecond-1)/(20*time.Millisecond)), 1)
+
+	head := []byte("OpusHead")
+	head = append(head, 1, 1) // version, mono
+	head = binary.LittleEndian.AppendUint16(head, opusPreSkip)
+	head = binary.LittleEndian.AppendUint32(head, 48000)
+	head = binary.LittleEndian.AppendUint16(head, 0) // output gain
+	head = append(head, 0)                           // channel mapping family
+
+	vendor := "fakeapi"
+	tags := []byte("OpusTags")
+	tags = binary.LittleEndian.AppendUint32(tags, uint32(len(vendor)))
+	tags = append(tags, vendor...)
+	tags = binary.LittleEndian.AppendUint32(tags, 0) // no user comments
+
+	var out bytes.Buffer
+	out.Write(oggPage(0x02, 0, 0, [][]byte{head}))
+	out.Write(oggPage(0x00, 0, 1, [][]byte{tags}))
+
+	// A page holds up to 255 packets of one lacing value each
+	sequence := uint32(2)
+	for written := 0; written < frames; sequence++ {
+		n := min(frames-written, 255)
+		packets := make([][]byte, n)
+		for i := range packets {
+			packets[i] = opusSilence
+		}
+		written += n
+
+		headerType := byte(0x00)
+		if written == frames {
+			headerType = 0x04 // end of stream
+		}
+		out.Write(oggPage(headerType, uint64(written*opusFrameSamples), sequence, packets))
+	}
+
+	return out.Bytes()
+}
+
+// oggPage builds an Ogg page of packets shorter than 255 bytes each
+func oggPage(headerType byte, granule uint64, sequence uint32, packets [][]byte) []byte {
+	page := []byte("OggS")
+	page = append(page, 0, headerType)
+	page = binary.LittleEndian.AppendUint64(page, granule)
+	page = binary.LittleEndian.AppendUint32(page, oggSerial)
+	page = binary.LittleEndian.AppendUint32(page, sequence)
+	page = binary.LittleEndian.AppendUint32(page, 0) // checksum, set below
+	page = append(page, byte(len(packets)))
+	for _, packet := range packets {
+		page = append(page, byte(len(packet)))
+	}
+	for _, packet := range packets {
+		page = append(page, packet...)
+	}
+
+	binary.LittleEndian.PutUint32(page[22:26], oggChecksum(page))
+	return page
+}
+
+// oggChecksum is the CRC-32 of Ogg: polynomial 0x04c11db7, no reflection, zero initial value
+func oggChecksum(data []byte) uint32 {
+	var crc uint32
+	for _, b := range data {
+		crc ^= uint32(b) << 24
+		for range 8 {
+			if crc&0x80000000 != 0 {
+				crc = crc<<1 ^ 0x04c11db7
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return crc
+}
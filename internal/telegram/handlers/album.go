@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// maxAlbumDocumentSize limits text documents read from albums
+const maxAlbumDocumentSize = 256 * 1024 // 256 KB
+
+// albumText joins captions and contents of text documents of an album in item order.
+// Documents that cannot be read are skipped so that the rest of the album is kept.
+func albumText(ctx context.Context, bot *tgbotapi.BotAPI, album []AlbumItem) string {
+	parts := make([]string, 0, len(album))
+	for _, item := range album {
+		if item.Document != nil && isTextDocument(item.Document) {
+			data, err := downloadFile(ctx, bot, item.Document.FileID, maxAlbumDocumentSize)
+			if err != nil {
+				ctxzap.Warn(ctx, "failed to download album document",
+					zap.Error(err),
+					zap.String("file_name", item.Document.FileName),
+				)
+			} else if text := strings.TrimSpace(string(data)); text != "" && utf8.ValidString(text) {
+				parts = append(parts, text)
+			}
+		}
+
+		if caption := strings.TrimSpace(item.Caption); caption != "" {
+			parts = append(parts, caption)
+		}
+	}
+
+	return strings.Join(parts, "\n\n")
+}
+
+// isTextDocument reports whether a document holds plain text that can be read as is
+func isTextDocument(doc *tgbotapi.Document) bool {
+	if strings.HasPrefix(doc.MimeType, "text/") {
+		return true
+	}
+
+	switch strings.ToLower(filepath.Ext(doc.FileName)) {
+	case ".txt", ".md", ".csv":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,216 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/telegram/render"
+	"github.com/futig/agent-backend/internal/telegram/state"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// answerReviewPageSize is the number of answers listed per page of the review
+const answerReviewPageSize = 8
+
+// handleReviewAnswers lists the answered questions of all blocks, value is the page from 0
+func (h *CallbackHandler) handleReviewAnswers(ctx context.Context, msg *Message, value string) error {
+	telegramSession, err := h.stateManager.GetSession(ctx, msg.UserID)
+	if err != nil {
+		return fmt.Errorf("get user state: %w", err)
+	}
+	sessionID := telegramSession.SessionID
+
+	session, err := h.sessionUC.GetSession(ctx, sessionID)
+	if err != nil {
+		h.HandleError(ctx, msg.ChatID, err)
+		return nil
+	}
+	if session.Status != entity.SessionStatusWaitingForAnswers {
+		h.sendMessage(msg.ChatID, render.MsgAnswerReviewUnavailable, nil)
+		return nil
+	}
+
+	questions, err := h.sessionUC.ListAnsweredQuestions(ctx, sessionID)
+	if err != nil {
+		ctxzap.Error(ctx, "failed to list answered questions",
+			zap.Error(err),
+			zap.String("session_id", sessionID),
+		)
+		h.sendMessage(msg.ChatID, render.ClassifyError(ctx, err), nil)
+		return nil
+	}
+	if len(questions) == 0 {
+		h.sendMessage(msg.ChatID, render.MsgAnswerReviewEmpty, nil)
+		return nil
+	}
+
+	pages := (len(questions) + answerReviewPageSize - 1) / answerReviewPageSize
+	page, _ := strconv.Atoi(value)
+	page = max(0, min(page, pages-1))
+
+	offset := page * answerReviewPageSize
+	listed := questions[offset:min(offset+answerReviewPageSize, len(questions))]
+	ids := make([]string, 0, len(listed))
+	for _, q := range listed {
+		ids = append(ids, q.ID)
+	}
+
+	h.sendMessage(msg.ChatID,
+		render.RenderAnswerReview(len(questions), offset, page, pages, listed),
+		h.keyboard.AnswerReviewKeyboard(ids, offset, page, pages),
+	)
+	return nil
+}
+
+// handleEditAnswer waits for the next text message to replace the answer to the question
+func (h *CallbackHandler) handleEditAnswer(ctx context.Context, msg *Message, questionID string) error {
+	telegramSession, err := h.stateManager.GetSession(ctx, msg.UserID)
+	if err != nil {
+		return fmt.Errorf("get user state: %w", err)
+	}
+
+	session, err := h.sessionUC.GetSession(ctx, telegramSession.SessionID)
+	if err != nil {
+		h.HandleError(ctx, msg.ChatID, err)
+		return nil
+	}
+	if session.Status != entity.SessionStatusWaitingForAnswers {
+		h.sendMessage(msg.ChatID, render.MsgAnswerReviewUnavailable, nil)
+		return nil
+	}
+
+	question, err := h.sessionUC.GetQuestionByID(ctx, questionID)
+	if err != nil {
+		ctxzap.Error(ctx, "failed to get question",
+			zap.Error(err),
+			zap.String("question_id", questionID),
+		)
+		h.sendMessage(msg.ChatID, render.ClassifyError(ctx, err), nil)
+		return nil
+	}
+
+	iteration, err := h.sessionUC.GetIterationByID(ctx, question.IterationID)
+	if err != nil {
+		ctxzap.Error(ctx, "failed to get iteration",
+			zap.Error(err),
+			zap.String("iteration_id", question.IterationID),
+		)
+		h.sendMessage(msg.ChatID, render.ClassifyError(ctx, err), nil)
+		return nil
+	}
+	// Buttons of an earlier session must not edit the answers of the current one
+	if iteration.SessionID != session.ID || question.Status != entity.AnswerStatusAnswered || question.Answer == nil {
+		h.sendMessage(msg.ChatID, render.MsgAnswerReviewEmpty, nil)
+		return nil
+	}
+
+	stateData, err := h.stateManager.GetStateData(ctx, msg.UserID)
+	if err != nil {
+		return fmt.Errorf("get state data: %w", err)
+	}
+	stateData.EditingQuestionID = questionID
+	stateData.AwaitingSearch = false
+	if err := h.stateManager.UpdateStateData(ctx, msg.UserID, stateData); err != nil {
+		return fmt.Errorf("update state data: %w", err)
+	}
+
+	h.sendMessage(msg.ChatID, render.RenderEditAnswer(question.Question, *question.Answer), h.keyboard.EditAnswerKeyboard())
+	return nil
+}
+
+// handleEditAnswerCancel keeps the answer and shows the current question again
+func (h *CallbackHandler) handleEditAnswerCancel(ctx context.Context, msg *Message) error {
+	clearEditingQuestion(ctx, msg.UserID, h.stateManager)
+
+	telegramSession, err := h.stateManager.GetSession(ctx, msg.UserID)
+	if err != nil {
+		return fmt.Errorf("get user state: %w", err)
+	}
+	session, err := h.sessionUC.GetSession(ctx, telegramSession.SessionID)
+	if err != nil {
+		h.HandleError(ctx, msg.ChatID, err)
+		return nil
+	}
+
+	h.sendMessage(msg.ChatID, render.MsgEditAnswerCancelled, nil)
+	if session.Status != entity.SessionStatusWaitingForAnswers {
+		return nil
+	}
+	return h.resumeQuestion(ctx, msg, session)
+}
+
+// handlePendingEdit saves the message as the new answer to the question picked for editing
+// instead of answering the current question, then shows the current question again. The
+// navigation state is left as it is, so the interview continues where it was. Returns true
+// when the message was consumed.
+func (h *QuestionsHandler) handlePendingEdit(ctx context.Context, msg *Message, sessionID string, stateData *state.StateData) bool {
+	if stateData.EditingQuestionID == "" {
+		return false
+	}
+
+	if msg.Text == "" {
+		h.sendMessage(msg.ChatID, render.MsgEditAnswerTextOnly, h.keyboard.EditAnswerKeyboard())
+		return true
+	}
+
+	questionID := stateData.EditingQuestionID
+	ctxzap.Info(ctx, "processing edited answer",
+		zap.Int64("user_id", msg.UserID),
+		zap.String("question_id", questionID),
+	)
+
+	if err := h.sessionUC.UpdateAnswer(ctx, sessionID, questionID, msg.Text); err != nil {
+		h.HandleSubmitError(ctx, h.keyboard, msg, err)
+		return true
+	}
+
+	clearEditingQuestion(ctx, msg.UserID, h.stateManager)
+
+	sendCriticalMessage(h.bot, msg.ChatID, render.MsgAnswerUpdated, nil, h.logger)
+
+	session, err := h.sessionUC.GetSession(ctx, sessionID)
+	if err != nil {
+		h.HandleError(ctx, msg.ChatID, err)
+		return true
+	}
+	if err := resumeCurrentQuestion(
+		ctx,
+		msg,
+		session,
+		h.sessionUC,
+		h.projectUC,
+		h.stateManager,
+		h.keyboard,
+		h.bot,
+		h.logger,
+		h.sendMessage,
+	); err != nil {
+		ctxzap.Error(ctx, "failed to show current question after edit",
+			zap.Error(err),
+			zap.String("session_id", sessionID),
+		)
+		h.sendMessage(msg.ChatID, render.ClassifyError(ctx, err), nil)
+	}
+
+	return true
+}
+
+// clearEditingQuestion drops the question picked for editing; an edit the user left open must
+// not replace the picked answer with an answer to a later question, e.g. after validation
+func clearEditingQuestion(ctx context.Context, userID int64, stateManager *state.Manager) {
+	stateData, err := stateManager.GetStateData(ctx, userID)
+	if err != nil || stateData.EditingQuestionID == "" {
+		return
+	}
+
+	stateData.EditingQuestionID = ""
+	if err := stateManager.UpdateStateData(ctx, userID, stateData); err != nil {
+		ctxzap.Warn(ctx, "failed to clear edited question from state",
+			zap.Error(err),
+			zap.Int64("user_id", userID),
+		)
+	}
+}
@@ -8,10 +8,12 @@ import (
 	"os/exec"
 	"net/http"
 	"net/url"
+	"path"
 	"time"
 
 	"bytes"
 
+	"github.com/futig/agent-backend/internal/entity"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
 
@@ -20,6 +22,15 @@ const (
 	downloadTimeout  = 30 * time.Second
 )
 
+// fileEndpoint is the format of file download links, see SetFileEndpoint
+var fileEndpoint = tgbotapi.FileEndpoint
+
+// SetFileEndpoint makes files download from a local Bot API server or a fake instead of Telegram;
+// downloads from Telegram itself must use HTTPS
+func SetFileEndpoint(endpoint string) {
+	fileEndpoint = endpoint
+}
+
 var secureHTTPClient = &http.Client{
 	Timeout: downloadTimeout,
 	Transport: &http.Transport{
@@ -29,46 +40,69 @@ var secureHTTPClient = &http.Client{
 	},
 }
 
-// downloadVoiceFile is a shared helper for downloading voice files from Telegram
-func downloadVoiceFile(ctx context.Context, bot *tgbotapi.BotAPI, fileID string) ([]byte, error) {
+// downloadVoiceFile is a shared helper for downloading voice files from Telegram; the returned
+// context carries the Telegram file name for the transcription
+func downloadVoiceFile(ctx context.Context, bot *tgbotapi.BotAPI, fileID string) (context.Context, []byte, error) {
+	data, name, err := downloadNamedFile(ctx, bot, fileID, maxVoiceFileSize)
+	if err != nil {
+		return ctx, nil, err
+	}
+
+	// Convert downloaded voice (OGG/Opus) to WAV using ffmpeg
+	wavData, err := convertToWav(ctx, data)
+	if err != nil {
+		return ctx, nil, err
+	}
+
+	return entity.WithAudioFileName(ctx, name), wavData, nil
+}
+
+// downloadFile downloads a Telegram file of at most maxSize bytes
+func downloadFile(ctx context.Context, bot *tgbotapi.BotAPI, fileID string, maxSize int) ([]byte, error) {
+	data, _, err := downloadNamedFile(ctx, bot, fileID, maxSize)
+	return data, err
+}
+
+// downloadNamedFile downloads a Telegram file of at most maxSize bytes along with the base name of its path
+func downloadNamedFile(ctx context.Context, bot *tgbotapi.BotAPI, fileID string, maxSize int) ([]byte, string, error) {
 	file, err := bot.GetFile(tgbotapi.FileConfig{FileID: fileID})
 	if err != nil {
-		return nil, fmt.Errorf("get file info: %w", err)
+		return nil, "", fmt.Errorf("get file info: %w", err)
 	}
 
 	// Check file size before download
-	if file.FileSize > maxVoiceFileSize {
-		return nil, fmt.Errorf("file too large: %d bytes (max %d)", file.FileSize, maxVoiceFileSize)
+	if file.FileSize > maxSize {
+		return nil, "", fmt.Errorf("file too large: %d bytes (max %d)", file.FileSize, maxSize)
 	}
 
-	fileURL := file.Link(bot.Token)
+	fileURL := fmt.Sprintf(fileEndpoint, bot.Token, file.FilePath)
 
 	// Validate URL
 	parsedURL, err := url.Parse(fileURL)
 	if err != nil {
-		return nil, fmt.Errorf("invalid file URL: %w", err)
+		return nil, "", fmt.Errorf("invalid file URL: %w", err)
 	}
 
-	// Ensure HTTPS
-	if parsedURL.Scheme != "https" {
-		return nil, fmt.Errorf("insecure URL scheme: %s (expected https)", parsedURL.Scheme)
+	// Ensure HTTPS, except for a configured endpoint such as the fake API of the bot scenarios
+	if parsedURL.Scheme != "https" && fileEndpoint == tgbotapi.FileEndpoint {
+		return nil, "", fmt.Errorf("insecure URL scheme: %s (expected https)", parsedURL.Scheme)
 	}
 
 	// Create request with context
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fileURL, nil)
 	if err != nil {
-		return nil, fmt.Errorf("create request: %w", err)
+		return nil, "", fmt.Errorf("create request: %w", err)
 	}
 
 	// Download file
 	resp, err := secureHTTPClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("download file: %w", err)
+		return nil, "", fmt.Errorf("download file: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+		return nil, "", fmt.Errorf("unexpected status code: %d", resp.StatusCode)
 	}
 
 	// Read file data with buffered reader for better performance
@@ -85,17 +119,11 @@ func downloadVoiceFile(ctx context.Context, bot *tgbotapi.BotAPI, fileID string)
 			break
 		}
 		if err != nil {
-			return nil, fmt.Errorf("read file data: %w", err)
+			return nil, "", fmt.Errorf("read file data: %w", err)
 		}
 	}
 
-	// Convert downloaded voice (OGG/Opus) to WAV using ffmpeg
-	wavData, err := convertToWav(ctx, data)
-	if err != nil {
-		return nil, err
-	}
-
-	return wavData, nil
+	return data, path.Base(file.FilePath), nil
 }
 
 // convertToWav uses ffmpeg to convert arbitrary audio data (e.g. OGG/Opus from Telegram)
@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/futig/agent-backend/internal/telegram/fakeapi"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+func TestDownloadNamedFile(t *testing.T) {
+	server := fakeapi.NewServer()
+	t.Cleanup(server.Close)
+	SetFileEndpoint(server.FileEndpoint())
+	t.Cleanup(func() { SetFileEndpoint(tgbotapi.FileEndpoint) })
+
+	bot, err := tgbotapi.NewBotAPIWithAPIEndpoint("test:token", server.APIEndpoint())
+	if err != nil {
+		t.Fatalf("NewBotAPIWithAPIEndpoint() error = %v", err)
+	}
+
+	audio := fakeapi.SilentVoice(time.Second)
+	server.SendVoice(42, "answer.ogg", audio)
+	updates, err := bot.GetUpdates(tgbotapi.NewUpdate(0))
+	if err != nil || len(updates) != 1 || updates[0].Message.Voice == nil {
+		t.Fatalf("GetUpdates() = %+v, %v, want a voice message", updates, err)
+	}
+	fileID := updates[0].Message.Voice.FileID
+
+	data, name, err := downloadNamedFile(context.Background(), bot, fileID, maxVoiceFileSize)
+	if err != nil {
+		t.Fatalf("downloadNamedFile() error = %v", err)
+	}
+	if name != "answer.ogg" {
+		t.Errorf("downloadNamedFile() name = %q, want answer.ogg", name)
+	}
+	if !bytes.Equal(data, audio) {
+		t.Errorf("downloadNamedFile() = %d bytes, want the %d bytes of the voice", len(data), len(audio))
+	}
+
+	if _, _, err := downloadNamedFile(context.Background(), bot, fileID, len(audio)-1); err == nil {
+		t.Error("downloadNamedFile() of a file over the limit succeeded, want an error")
+	}
+}
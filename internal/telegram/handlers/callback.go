@@ -2,7 +2,9 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/futig/agent-backend/internal/entity"
@@ -22,6 +24,7 @@ type CallbackHandler struct {
 	stateManager *state.Manager
 	sessionUC    SessionUsecase
 	projectUC    ProjectUsecase
+	demoUC       DemoUsecase
 	keyboard     *keyboard.Builder
 	logger       *zap.Logger
 	questions    []string
@@ -33,6 +36,7 @@ func NewCallbackHandler(
 	stateManager *state.Manager,
 	sessionUC SessionUsecase,
 	projectUC ProjectUsecase,
+	demoUC DemoUsecase,
 	questions []string,
 	kb *keyboard.Builder,
 	logger *zap.Logger,
@@ -46,6 +50,7 @@ func NewCallbackHandler(
 		stateManager: stateManager,
 		sessionUC:    sessionUC,
 		projectUC:    projectUC,
+		demoUC:       demoUC,
 		keyboard:     kb,
 		logger:       logger,
 		questions:    questions,
@@ -78,18 +83,54 @@ func (h *CallbackHandler) Handle(ctx context.Context, msg *Message) error {
 		return h.handleModeSelection(ctx, msg, data.Value)
 	case "proj":
 		return h.handleProjectSelection(ctx, msg, data.Value)
+	case "pin":
+		return h.handleProjectPin(ctx, msg, data.Value)
+	case "settings":
+		return h.handleSettings(ctx, msg, data.Value)
 	case "skip":
 		return h.handleSkipQuestion(ctx, msg, data.Value)
+	case "defer":
+		return h.handleDeferQuestion(ctx, msg, data.Value)
+	case "skipwhy":
+		return h.handleSkipReason(ctx, msg, data.Value)
 	case "prev":
 		return h.handlePreviousQuestion(ctx, msg, data.Value)
 	case "explain":
 		return h.handleExplainQuestion(ctx, msg, data.Value)
+	case "answers":
+		return h.handleReviewAnswers(ctx, msg, data.Value)
+	case "edit":
+		return h.handleEditAnswer(ctx, msg, data.Value)
 	case "dl":
 		return h.handleDownload(ctx, msg, data.Value)
+	case "ver":
+		return h.handleResultVersion(ctx, msg, data.Value)
 	case "confirm":
 		return h.handleConfirmation(ctx, msg, data.Value)
 	case "page":
 		return h.handlePageNavigation(ctx, msg, data.Value)
+	case "lang":
+		return h.handleLanguageSelection(ctx, msg, data.Value)
+	case "doclang":
+		return h.handleDocumentLanguage(ctx, msg, data.Value)
+	case "style":
+		return h.handleSummaryStyle(ctx, msg, data.Value)
+	case "section":
+		return h.handleSectionCallback(ctx, msg, data.Value)
+	case "comment":
+		return h.handleResolveComment(ctx, msg, data.Value)
+	case "conflict":
+		return h.handleResolveConflict(ctx, msg, data.Value)
+	case "review":
+		return h.handleReviewDecision(ctx, msg, data.Value)
+	case "scheduled":
+		return h.handleStartScheduled(ctx, msg, data.Value)
+	case "tutorial":
+		return h.handleTutorialPage(ctx, msg, data.Value)
+	case "preview":
+		return h.handleResultPreview(ctx, msg, data.Value)
+	case "demo":
+		return h.handleDemo(ctx, msg, data.Value)
 	default:
 		ctxzap.Warn(ctx, "unknown callback action",
 			zap.String("action", data.Action),
@@ -104,18 +145,27 @@ func (h *CallbackHandler) handleAction(ctx context.Context, msg *Message, value
 	case "start":
 		// Start button clicked
 		return h.handleStart(ctx, msg)
+	case "start_demo":
+		// Start a sandbox demo session
+		return h.handleStartDemo(ctx, msg)
 	case "start_interview":
 		// Begin interview
 		return h.handleStartInterview(ctx, msg)
 	case "start_draft":
 		// Begin draft mode
 		return h.handleStartDraft(ctx, msg)
+	case "skip_goal_clarification":
+		// Keep a vague goal as it is
+		return h.handleSkipGoalClarification(ctx, msg)
 	case "choose_mode":
 		// Return to mode selection
 		return h.handleChooseMode(ctx, msg)
 	case "generate":
 		// Force generate requirements
 		return h.handleGenerate(ctx, msg)
+	case "timebox_generate":
+		// Skip the rest of a time-boxed interview and generate
+		return h.handleTimeboxGenerate(ctx, msg)
 	case "finish":
 		// Finish session
 		return h.handleFinish(ctx, msg)
@@ -131,6 +181,47 @@ func (h *CallbackHandler) handleAction(ctx context.Context, msg *Message, value
 	case "save_to_project":
 		// Save requirements to existing project
 		return h.handleSaveToProject(ctx, msg)
+	case "download_all":
+		// Send all artifacts as a ZIP archive
+		return h.handleDownloadBundle(ctx, msg)
+	case "translate":
+		// Choose result translation language
+		return h.handleTranslate(ctx, msg)
+	case "regen_section":
+		// Choose result section to regenerate
+		return h.handleRegenSection(ctx, msg)
+	case "feedback":
+		// Ask what to improve in the whole result
+		return h.handleResultFeedback(ctx, msg)
+	case "feedback_cancel":
+		return h.handleResultFeedbackCancel(ctx, msg)
+	case "comments":
+		// Show open review comments
+		return h.handleComments(ctx, msg)
+	case "export_questions":
+		// Send the generated questions as a document without answers
+		return h.handleQuestionScriptExport(ctx, msg)
+	case "diff_previous":
+		// Compare the result with the previous requirements of the project
+		return h.handleResultDiff(ctx, msg)
+	case "versions":
+		// Pick a stored result version to download
+		return h.handleResultVersions(ctx, msg)
+	case "summary_style":
+		return h.handleSummaryStyleMenu(ctx, msg)
+	case "search":
+		// Ask for a query over collected material
+		return h.handleSearch(ctx, msg)
+	case "search_cancel":
+		return h.handleSearchCancel(ctx, msg)
+	case "check_draft":
+		// Preview what the draft materials cover without starting the validation
+		return h.handleDraftCoverage(ctx, msg)
+	case "review_answers":
+		// List the given answers to pick one to edit
+		return h.handleReviewAnswers(ctx, msg, "0")
+	case "edit_cancel":
+		return h.handleEditAnswerCancel(ctx, msg)
 	default:
 		return fmt.Errorf("unknown action value: %s", value)
 	}
@@ -149,6 +240,8 @@ func (h *CallbackHandler) handleModeSelection(ctx context.Context, msg *Message,
 		sessionType = entity.SessionTypeInterview
 	case "draft":
 		sessionType = entity.SessionTypeDraft
+	case "delta":
+		sessionType = entity.SessionTypeDelta
 	default:
 		return fmt.Errorf("invalid mode: %s", value)
 	}
@@ -160,18 +253,26 @@ func (h *CallbackHandler) handleModeSelection(ctx context.Context, msg *Message,
 		return nil
 	}
 
-	// Send appropriate info message
-	if sessionType == entity.SessionTypeInterview {
+	h.sendModeInfo(msg.ChatID, sessionType)
+
+	return nil
+}
+
+// sendModeInfo explains the format of the chosen session type
+func (h *CallbackHandler) sendModeInfo(chatID int64, sessionType entity.SessionType) {
+	switch sessionType {
+	case entity.SessionTypeInterview:
 		// Show interview info
 		infoText := render.RenderInterviewInfo(15, 3, 10) // Example values
-		h.sendMessage(msg.ChatID, infoText, h.keyboard.InterviewInfoKeyboard())
-	} else {
+		h.sendMessage(chatID, infoText, h.keyboard.InterviewInfoKeyboard())
+	case entity.SessionTypeDelta:
+		// Delta sessions run the regular interview flow over the stored baseline
+		h.sendMessage(chatID, render.MsgDeltaInfo, h.keyboard.InterviewInfoKeyboard())
+	default:
 		// Show draft info
 		infoText := render.RenderDraftInfo(30) // Example value for max draft messages
-		h.sendMessage(msg.ChatID, infoText, h.keyboard.DraftInfoKeyboard())
+		h.sendMessage(chatID, infoText, h.keyboard.DraftInfoKeyboard())
 	}
-
-	return nil
 }
 
 // handleStartInterview handles starting the interview
@@ -204,8 +305,25 @@ func (h *CallbackHandler) handleStartInterview(ctx context.Context, msg *Message
 
 	// Calculate total questions and blocks
 	totalQuestions := 0
+	answered := 0
 	for _, it := range iterations {
 		totalQuestions += len(it.Questions)
+		for _, q := range it.Questions {
+			if q.Status == entity.AnswerStatusAnswered {
+				answered++
+			}
+		}
+	}
+
+	// Questions kept after returning to the same project continue from the first open one
+	if answered > 0 {
+		session, err := h.sessionUC.GetSession(ctx, telegramSession.SessionID)
+		if err != nil {
+			h.HandleError(ctx, msg.ChatID, err)
+			return nil
+		}
+		h.sendMessage(msg.ChatID, fmt.Sprintf(render.MsgQuestionsKept, answered, totalQuestions), nil)
+		return h.resumeQuestion(ctx, msg, session)
 	}
 	blockCount := len(iterations)
 
@@ -223,8 +341,7 @@ func (h *CallbackHandler) handleStartInterview(ctx context.Context, msg *Message
 		firstQuestion := firstIteration.Questions[0]
 		questionText := render.RenderQuestion(
 			firstIteration.Title,
-			1,
-			len(firstIteration.Questions),
+			questionPosition(ctx, h.sessionUC, h.stateManager, msg.UserID, telegramSession.SessionID, firstQuestion.ID, 1, len(firstIteration.Questions)),
 			firstQuestion.Question,
 		)
 
@@ -235,7 +352,7 @@ func (h *CallbackHandler) handleStartInterview(ctx context.Context, msg *Message
 				zap.Error(err),
 				zap.Int64("user_id", msg.UserID),
 			)
-			h.sendMessage(msg.ChatID, render.ClassifyError(err), nil)
+			h.sendMessage(msg.ChatID, render.ClassifyError(ctx, err), nil)
 			return nil
 		}
 
@@ -253,7 +370,7 @@ func (h *CallbackHandler) handleStartInterview(ctx context.Context, msg *Message
 		h.stateManager.UpdateStateData(ctx, msg.UserID, stateData)
 
 		// First question has no previous
-		h.sendMessage(msg.ChatID, questionText, h.keyboard.QuestionNavigationKeyboard(firstQuestion.ID, false))
+		sendQuestionMessage(ctx, h.bot, h.stateManager, msg, stateData, questionText, firstQuestion.ID, questionKeyboard(ctx, h.stateManager, h.keyboard, msg.UserID, firstQuestion.ID, firstQuestion.AnswerType, false))
 	}
 
 	return nil
@@ -272,7 +389,7 @@ func (h *CallbackHandler) handleStartDraft(ctx context.Context, msg *Message) er
 			zap.Error(err),
 			zap.String("session_id", telegramSession.SessionID),
 		)
-		h.sendMessage(msg.ChatID, render.ClassifyError(err), nil)
+		h.sendMessage(msg.ChatID, render.ClassifyError(ctx, err), nil)
 		return nil
 	}
 
@@ -326,7 +443,7 @@ func (h *CallbackHandler) handleSkipQuestion(ctx context.Context, msg *Message,
 				zap.Error(err),
 				zap.String("session_id", telegramSession.SessionID),
 			)
-			h.sendMessage(msg.ChatID, render.ClassifyError(err), nil)
+			h.sendMessage(msg.ChatID, render.ClassifyError(ctx, err), nil)
 		}
 
 		return nil
@@ -339,18 +456,35 @@ func (h *CallbackHandler) handleSkipQuestion(ctx context.Context, msg *Message,
 			zap.Error(err),
 			zap.String("question_id", questionID),
 		)
-		h.sendMessage(msg.ChatID, render.ClassifyError(err), nil)
+		h.sendMessage(msg.ChatID, render.ClassifyError(ctx, err), nil)
 		return nil
 	}
 
-	// If no more questions, move to validation
-	if nextIteration == nil || len(nextIteration.Questions) == 0 {
-		h.sendMessage(msg.ChatID, render.MsgValidating, nil)
+	h.sendMessage(msg.ChatID, render.MsgSkipReasonPrompt, h.keyboard.SkipReasonKeyboard(questionID))
 
-		if err := handleValidationAndSummaryCommon(
+	// All blocks are done while deferred questions are asked, move on to the next deferred one
+	if stateData.AnsweringDeferred {
+		nextIteration = nil
+	}
+
+	return h.continueInterview(ctx, msg, telegramSession.SessionID, stateData, nextIteration)
+}
+
+// continueInterview asks the first unanswered question of the next block or, after the last
+// block, moves on to deferred questions and validation
+func (h *CallbackHandler) continueInterview(
+	ctx context.Context,
+	msg *Message,
+	sessionID string,
+	stateData *state.StateData,
+	nextIteration *entity.IterationWithQuestions,
+) error {
+	// If no more questions, move to deferred questions and validation
+	if nextIteration == nil || len(nextIteration.Questions) == 0 {
+		if err := finishInterview(
 			ctx,
 			msg,
-			telegramSession.SessionID,
+			sessionID,
 			h.sessionUC,
 			h.projectUC,
 			h.stateManager,
@@ -359,11 +493,11 @@ func (h *CallbackHandler) handleSkipQuestion(ctx context.Context, msg *Message,
 			h.logger,
 			h.sendMessage,
 		); err != nil {
-			ctxzap.Error(ctx, "failed to validate answers or generate summary after skip",
+			ctxzap.Error(ctx, "failed to finish interview",
 				zap.Error(err),
-				zap.String("session_id", telegramSession.SessionID),
+				zap.String("session_id", sessionID),
 			)
-			h.sendMessage(msg.ChatID, render.ClassifyError(err), nil)
+			h.sendMessage(msg.ChatID, render.ClassifyError(ctx, err), nil)
 		}
 
 		return nil
@@ -389,13 +523,10 @@ func (h *CallbackHandler) handleSkipQuestion(ctx context.Context, msg *Message,
 			zap.String("iteration_id", nextIteration.IterationID),
 		)
 
-		// Inform user that validation may take some time
-		h.sendMessage(msg.ChatID, render.MsgValidating, nil)
-
-		if err := handleValidationAndSummaryCommon(
+		if err := finishInterview(
 			ctx,
 			msg,
-			telegramSession.SessionID,
+			sessionID,
 			h.sessionUC,
 			h.projectUC,
 			h.stateManager,
@@ -406,9 +537,9 @@ func (h *CallbackHandler) handleSkipQuestion(ctx context.Context, msg *Message,
 		); err != nil {
 			ctxzap.Error(ctx, "failed to validate answers or generate summary",
 				zap.Error(err),
-				zap.String("session_id", telegramSession.SessionID),
+				zap.String("session_id", sessionID),
 			)
-			h.sendMessage(msg.ChatID, render.ClassifyError(err), nil)
+			h.sendMessage(msg.ChatID, render.ClassifyError(ctx, err), nil)
 		}
 
 		return nil
@@ -420,8 +551,7 @@ func (h *CallbackHandler) handleSkipQuestion(ctx context.Context, msg *Message,
 
 	questionText := render.RenderQuestion(
 		title,
-		questionIndex,
-		len(nextIteration.Questions),
+		questionPosition(ctx, h.sessionUC, h.stateManager, msg.UserID, sessionID, nextQuestion.ID, questionIndex, len(nextIteration.Questions)),
 		nextQuestion.Question,
 	)
 
@@ -439,7 +569,7 @@ func (h *CallbackHandler) handleSkipQuestion(ctx context.Context, msg *Message,
 	h.stateManager.UpdateStateData(ctx, msg.UserID, stateData)
 
 	hasPrevious := stateData.PreviousQuestionID != ""
-	h.sendMessage(msg.ChatID, questionText, h.keyboard.QuestionNavigationKeyboard(nextQuestion.ID, hasPrevious))
+	sendQuestionMessage(ctx, h.bot, h.stateManager, msg, stateData, questionText, nextQuestion.ID, questionKeyboard(ctx, h.stateManager, h.keyboard, msg.UserID, nextQuestion.ID, nextQuestion.AnswerType, hasPrevious))
 
 	return nil
 }
@@ -447,22 +577,25 @@ func (h *CallbackHandler) handleSkipQuestion(ctx context.Context, msg *Message,
 // handleExplainQuestion shows question explanation
 func (h *CallbackHandler) handleExplainQuestion(ctx context.Context, msg *Message, questionID string) error {
 	explanation, err := h.sessionUC.GetQuestionExplanation(ctx, questionID)
+	if errors.Is(err, entity.ErrExplanationCooldown) {
+		h.sendMessage(msg.ChatID, render.MsgExplanationCooldown, nil)
+		return nil
+	}
 	if err != nil {
 		ctxzap.Error(ctx, "failed to get question explanation",
 			zap.Error(err),
 			zap.String("question_id", questionID),
 		)
-		h.sendMessage(msg.ChatID, render.ClassifyError(err), nil)
+		h.sendMessage(msg.ChatID, render.ClassifyError(ctx, err), nil)
 		return nil
 	}
 
 	if explanation == "" {
-		h.sendMessage(msg.ChatID, "💡 К этому вопросу пока нет отдельного пояснения. Ответь как можно подробнее.", nil)
+		h.sendMessage(msg.ChatID, render.MsgNoExplanation, nil)
 		return nil
 	}
 
-	text := fmt.Sprintf("💡 Пояснение к вопросу:\n\n%s", explanation)
-	h.sendMessage(msg.ChatID, text, nil)
+	h.messageSender.SendRendered(msg.ChatID, render.RenderExplanation(explanation), nil)
 	return nil
 }
 
@@ -474,7 +607,7 @@ func (h *CallbackHandler) handlePreviousQuestion(ctx context.Context, msg *Messa
 			zap.Error(err),
 			zap.Int64("user_id", msg.UserID),
 		)
-		h.sendMessage(msg.ChatID, render.ClassifyError(err), nil)
+		h.sendMessage(msg.ChatID, render.ClassifyError(ctx, err), nil)
 		return nil
 	}
 
@@ -501,7 +634,7 @@ func (h *CallbackHandler) handlePreviousQuestion(ctx context.Context, msg *Messa
 			zap.Error(err),
 			zap.String("question_id", previousQuestionID),
 		)
-		h.sendMessage(msg.ChatID, render.ClassifyError(err), nil)
+		h.sendMessage(msg.ChatID, render.ClassifyError(ctx, err), nil)
 		return nil
 	}
 
@@ -512,7 +645,7 @@ func (h *CallbackHandler) handlePreviousQuestion(ctx context.Context, msg *Messa
 			zap.Error(err),
 			zap.String("iteration_id", question.IterationID),
 		)
-		h.sendMessage(msg.ChatID, render.ClassifyError(err), nil)
+		h.sendMessage(msg.ChatID, render.ClassifyError(ctx, err), nil)
 		return nil
 	}
 
@@ -550,8 +683,7 @@ func (h *CallbackHandler) handlePreviousQuestion(ctx context.Context, msg *Messa
 
 		questionText = render.RenderQuestion(
 			title,
-			questionIndex,
-			len(iteration.Questions),
+			questionPosition(ctx, h.sessionUC, h.stateManager, msg.UserID, iteration.SessionID, previousQuestionID, questionIndex, len(iteration.Questions)),
 			question.Question,
 		)
 	}
@@ -570,23 +702,26 @@ func (h *CallbackHandler) handlePreviousQuestion(ctx context.Context, msg *Messa
 			zap.Error(err),
 			zap.Int64("user_id", msg.UserID),
 		)
-		h.sendMessage(msg.ChatID, render.ClassifyError(err), nil)
+		h.sendMessage(msg.ChatID, render.ClassifyError(ctx, err), nil)
 		return nil
 	}
 
 	hasPrevious := stateData.PreviousQuestionID != ""
-	h.sendMessage(msg.ChatID, questionText, h.keyboard.QuestionNavigationKeyboard(previousQuestionID, hasPrevious))
+	sendQuestionMessage(ctx, h.bot, h.stateManager, msg, stateData, questionText, previousQuestionID, questionKeyboard(ctx, h.stateManager, h.keyboard, msg.UserID, previousQuestionID, question.AnswerType, hasPrevious))
 
 	return nil
 }
 
-// handleDownload handles result download
-func (h *CallbackHandler) handleDownload(ctx context.Context, msg *Message, format string) error {
+// handleDownload handles result download, value is "format" or "format:lang"
+func (h *CallbackHandler) handleDownload(ctx context.Context, msg *Message, value string) error {
 	telegramSession, err := h.stateManager.GetSession(ctx, msg.UserID)
 	if err != nil {
 		return fmt.Errorf("get user state: %w", err)
 	}
 
+	format, lang, _ := strings.Cut(value, ":")
+	language := entity.ResultLanguage(lang)
+
 	// Validate and normalize format
 	resultFormat := entity.ResultFormat(format)
 	if !resultFormat.IsValid() {
@@ -595,96 +730,189 @@ func (h *CallbackHandler) handleDownload(ctx context.Context, msg *Message, form
 		return nil
 	}
 
-	// Get plain text result
-	result, err := h.sessionUC.GetSessionResult(ctx, telegramSession.SessionID)
+	// Get plain text result, translated if language is requested
+	var result string
+	if language != "" {
+		typing := NewTypingNotifier(h.bot, msg.ChatID, h.logger)
+		typing.Start(ctx)
+		result, err = h.sessionUC.GetTranslatedSessionResult(ctx, telegramSession.SessionID, language)
+		typing.Stop()
+	} else {
+		result, err = h.sessionUC.GetSessionResult(ctx, telegramSession.SessionID)
+	}
 	if err != nil {
 		ctxzap.Error(ctx, "failed to get result",
 			zap.Error(err),
 			zap.String("session_id", telegramSession.SessionID),
 		)
-		h.sendMessage(msg.ChatID, render.ClassifyError(err), nil)
+		h.sendMessage(msg.ChatID, render.ClassifyError(ctx, err), nil)
+		return nil
+	}
+
+	fileInfo, err := h.sessionUC.GetResultFileInfo(ctx, telegramSession.SessionID)
+	if err != nil {
+		ctxzap.Error(ctx, "failed to get result file info",
+			zap.Error(err),
+			zap.String("session_id", telegramSession.SessionID),
+		)
+		h.sendMessage(msg.ChatID, "❌ Не удалось подготовить файл", nil)
 		return nil
 	}
 
-	// Create formatter and format result
+	h.sendResultDocument(ctx, msg.ChatID, result, resultFormat, language, fileInfo, string(language))
+	return nil
+}
+
+// sendResultDocument formats a result and sends it as a file named after fileInfo and suffix
+func (h *CallbackHandler) sendResultDocument(
+	ctx context.Context,
+	chatID int64,
+	result string,
+	resultFormat entity.ResultFormat,
+	language entity.ResultLanguage,
+	fileInfo *entity.ResultFileInfo,
+	suffix string,
+) {
+	// Create formatter localized for the document language and format result
 	factory := formatter.NewFactory()
-	fmtr, err := factory.Create(resultFormat)
+	fmtr, err := factory.Create(resultFormat, language, fileInfo.Date, fileInfo.Theme)
 	if err != nil {
 		ctxzap.Error(ctx, "format not implemented", zap.Error(err))
-		h.sendMessage(msg.ChatID, "❌ Формат не поддерживается", nil)
-		return nil
+		h.sendMessage(chatID, "❌ Формат не поддерживается", nil)
+		return
 	}
 
 	formattedResult, err := fmtr.Format(result)
 	if err != nil {
 		ctxzap.Error(ctx, "failed to format result", zap.Error(err))
-		h.sendMessage(msg.ChatID, "❌ Не удалось подготовить файл", nil)
-		return nil
+		h.sendMessage(chatID, "❌ Не удалось подготовить файл", nil)
+		return
 	}
 
 	// Send as document
-	filename := fmt.Sprintf("requirements-%s%s", telegramSession.SessionID, fmtr.FileExtension())
+	filename := formatter.FileName(fileInfo, suffix, fmtr.FileExtension())
 	doc := tgbotapi.FileBytes{
-		Name:  filename,
+		Name:  formatter.ASCIIFileName(filename),
 		Bytes: formattedResult,
 	}
 
-	docMsg := tgbotapi.NewDocument(msg.ChatID, doc)
+	docMsg := tgbotapi.NewDocument(chatID, doc)
 	if _, err := h.bot.Send(docMsg); err != nil {
 		ctxzap.Error(ctx, "failed to send document",
 			zap.Error(err),
 		)
-		h.sendMessage(msg.ChatID, "❌ Не удалось отправить файл", nil)
+		h.sendMessage(chatID, "❌ Не удалось отправить файл", nil)
 	}
+}
 
+// handleDownloadBundle sends the result in all formats with the Q&A, transcript and JSON as one ZIP
+func (h *CallbackHandler) handleDownloadBundle(ctx context.Context, msg *Message) error {
+	telegramSession, err := h.stateManager.GetSession(ctx, msg.UserID)
+	if err != nil {
+		return fmt.Errorf("get user state: %w", err)
+	}
+
+	typing := NewTypingNotifier(h.bot, msg.ChatID, h.logger)
+	typing.Start(ctx)
+	defer typing.Stop()
+
+	bundle, err := h.sessionUC.GetSessionBundle(ctx, telegramSession.SessionID)
+	if err != nil {
+		ctxzap.Error(ctx, "failed to get session bundle",
+			zap.Error(err),
+			zap.String("session_id", telegramSession.SessionID),
+		)
+		h.sendMessage(msg.ChatID, render.ClassifyError(ctx, err), nil)
+		return nil
+	}
+
+	archive, skipped, err := formatter.BuildBundle(bundle)
+	if err != nil {
+		ctxzap.Error(ctx, "failed to build bundle", zap.Error(err))
+		h.sendMessage(msg.ChatID, "❌ Не удалось подготовить архив", nil)
+		return nil
+	}
+	if len(skipped) > 0 {
+		ctxzap.Warn(ctx, "bundle built without some artifacts", zap.Strings("skipped", skipped))
+	}
+
+	docMsg := tgbotapi.NewDocument(msg.ChatID, tgbotapi.FileBytes{
+		Name:  formatter.ASCIIFileName(formatter.BundleFileName(bundle.FileInfo)),
+		Bytes: archive,
+	})
+	if _, err := h.bot.Send(docMsg); err != nil {
+		ctxzap.Error(ctx, "failed to send bundle",
+			zap.Error(err),
+		)
+		h.sendMessage(msg.ChatID, "❌ Не удалось отправить архив", nil)
+	}
+
+	return nil
+}
+
+// handleTranslate shows target language selection for the result
+func (h *CallbackHandler) handleTranslate(ctx context.Context, msg *Message) error {
+	h.sendMessage(msg.ChatID, render.MsgChooseLanguage, h.keyboard.LanguageSelectionKeyboard())
+	return nil
+}
+
+// handleLanguageSelection offers downloads of the result in the selected language
+func (h *CallbackHandler) handleLanguageSelection(ctx context.Context, msg *Message, lang string) error {
+	language := entity.ResultLanguage(lang)
+	if !language.IsValid() {
+		ctxzap.Warn(ctx, "invalid translation language", zap.String("lang", lang))
+		h.sendMessage(msg.ChatID, "❌ Язык не поддерживается", nil)
+		return nil
+	}
+
+	h.sendMessage(msg.ChatID, render.MsgTranslationFormat, h.keyboard.TranslatedDownloadKeyboard(lang))
 	return nil
 }
 
 // handleGenerate forces requirement generation
 func (h *CallbackHandler) handleGenerate(ctx context.Context, msg *Message) error {
+	return h.generate(ctx, msg, false)
+}
+
+// handleTimeboxGenerate skips the remaining questions once the time budget runs low and generates
+func (h *CallbackHandler) handleTimeboxGenerate(ctx context.Context, msg *Message) error {
 	telegramSession, err := h.stateManager.GetSession(ctx, msg.UserID)
 	if err != nil {
 		return fmt.Errorf("get user state: %w", err)
 	}
 
-	// Check if already processing (idempotency)
-	stateData, err := h.stateManager.GetStateData(ctx, msg.UserID)
+	skipped, err := h.sessionUC.SkipRemainingQuestions(ctx, telegramSession.SessionID)
 	if err != nil {
-		return fmt.Errorf("get state data: %w", err)
-	}
-
-	if stateData.IsProcessing {
-		elapsed := time.Since(stateData.ProcessingStarted)
-		if elapsed < 5*time.Minute {
-			// Still processing, ignore duplicate request
-			h.sendMessage(msg.ChatID, "⏳ Уже обрабатываю запрос, подождите немного...", nil)
-			ctxzap.Info(ctx, "duplicate generate request ignored",
-				zap.Int64("user_id", msg.UserID),
-				zap.Duration("elapsed", elapsed),
-			)
-			return nil
-		}
-		// Processing timeout exceeded (>5 min), allow retry
-		ctxzap.Warn(ctx, "processing timeout exceeded, allowing retry",
-			zap.Int64("user_id", msg.UserID),
-			zap.Duration("elapsed", elapsed),
+		ctxzap.Error(ctx, "failed to skip remaining questions",
+			zap.Error(err),
+			zap.String("session_id", telegramSession.SessionID),
 		)
+		h.sendMessage(msg.ChatID, render.ClassifyError(ctx, err), nil)
+		return nil
 	}
 
-	// Set processing flag
-	stateData.IsProcessing = true
-	stateData.ProcessingStarted = time.Now()
-	if err := h.stateManager.UpdateStateData(ctx, msg.UserID, stateData); err != nil {
-		ctxzap.Error(ctx, "failed to set processing flag", zap.Error(err))
+	h.sendMessage(msg.ChatID, fmt.Sprintf(render.MsgTimeBudgetSkipped, skipped), nil)
+
+	return h.generate(ctx, msg, false)
+}
+
+// generate runs final generation; confirmed skips the large session confirmation step
+func (h *CallbackHandler) generate(ctx context.Context, msg *Message, confirmed bool) error {
+	telegramSession, err := h.stateManager.GetSession(ctx, msg.UserID)
+	if err != nil {
+		return fmt.Errorf("get user state: %w", err)
 	}
 
-	// Ensure flag is cleared on exit
-	defer func() {
-		stateData.IsProcessing = false
-		if err := h.stateManager.UpdateStateData(ctx, msg.UserID, stateData); err != nil {
-			ctxzap.Error(ctx, "failed to clear processing flag", zap.Error(err))
-		}
-	}()
+	// A generation already running on the session, started here or over the API, is not repeated;
+	// the usecase registers the generation itself, so a request slipping past this check fails there
+	if op, err := h.sessionUC.GetActiveOperation(ctx, telegramSession.SessionID); err == nil {
+		h.sendMessage(msg.ChatID, render.ErrOperationInProgress, nil)
+		ctxzap.Info(ctx, "generate request ignored while another operation runs",
+			zap.Int64("user_id", msg.UserID),
+			zap.String("operation", string(op.Kind)),
+		)
+		return nil
+	}
 
 	h.sendMessage(msg.ChatID, render.MsgProcessing, nil)
 
@@ -695,32 +923,51 @@ func (h *CallbackHandler) handleGenerate(ctx context.Context, msg *Message) erro
 			zap.Error(err),
 			zap.String("session_id", telegramSession.SessionID),
 		)
-		h.sendMessage(msg.ChatID, render.ClassifyError(err), nil)
+		h.sendMessage(msg.ChatID, render.ClassifyError(ctx, err), nil)
 		return nil
 	}
 
+	clearEditingQuestion(ctx, msg.UserID, h.stateManager)
+
 	if session.Type != nil && *session.Type == entity.SessionTypeDraft {
-		return h.handleGenerateDraft(ctx, msg, telegramSession.SessionID)
+		return h.handleGenerateDraft(ctx, msg, telegramSession.SessionID, confirmed)
 	}
 
-	return h.handleGenerateInterview(ctx, msg, telegramSession.SessionID)
+	return h.handleGenerateInterview(ctx, msg, telegramSession.SessionID, confirmed)
 }
 
 // handleGenerateInterview handles final generation for interview mode
-func (h *CallbackHandler) handleGenerateInterview(ctx context.Context, msg *Message, sessionID string) error {
+func (h *CallbackHandler) handleGenerateInterview(ctx context.Context, msg *Message, sessionID string, confirmed bool) error {
+	proceed, err := checkGenerationEstimate(ctx, msg, sessionID, confirmed, h.sessionUC, h.stateManager, h.keyboard, h.sendMessage)
+	if err != nil {
+		ctxzap.Error(ctx, "failed to estimate generation",
+			zap.Error(err),
+			zap.String("session_id", sessionID),
+		)
+		h.sendMessage(msg.ChatID, render.ClassifyError(ctx, err), nil)
+		return nil
+	}
+	if !proceed {
+		return nil
+	}
+
 	// Start typing indicator during summary generation
 	typing := NewTypingNotifier(h.bot, msg.ChatID, h.logger)
 	typing.Start(ctx)
 	defer typing.Stop()
 
-	// Generate summary
-	session, err := h.sessionUC.GenerateSummary(ctx, sessionID)
+	applySummaryStyle(ctx, msg.UserID, sessionID, h.sessionUC, h.stateManager)
+
+	// Generate summary, showing the document while it is streamed
+	preview := NewSummaryStreamer(ctx, h.bot, msg.ChatID)
+	session, err := h.sessionUC.GenerateSummaryStream(ctx, sessionID, preview.Update)
+	preview.Finish()
 	if err != nil {
 		ctxzap.Error(ctx, "failed to generate interview summary",
 			zap.Error(err),
 			zap.String("session_id", sessionID),
 		)
-		h.sendMessage(msg.ChatID, render.ClassifyError(err), nil)
+		h.sendMessage(msg.ChatID, render.ClassifyError(ctx, err), nil)
 		return nil
 	}
 
@@ -728,6 +975,7 @@ func (h *CallbackHandler) handleGenerateInterview(ctx context.Context, msg *Mess
 		zap.String("session_id", sessionID),
 		zap.String("status", string(session.Status)),
 	)
+	notifyQuota(ctx, msg.ChatID, entity.QuotaKindGenerations, h.sessionUC, h.sendMessage)
 
 	hasSkipped, err := h.sessionUC.HasSkippedQuestions(ctx, sessionID)
 	if err != nil {
@@ -737,13 +985,24 @@ func (h *CallbackHandler) handleGenerateInterview(ctx context.Context, msg *Mess
 		)
 	}
 
+	if session.Status == entity.SessionStatusPartial {
+		h.sendMessage(msg.ChatID, render.MsgPartialResult, h.keyboard.PartialResultKeyboard(hasSkipped))
+		return nil
+	}
+
+	sendChangeLog(ctx, h.bot, msg.ChatID, session, h.sessionUC)
+
+	if presentConflicts(ctx, msg.ChatID, sessionID, h.sessionUC, h.keyboard, h.sendMessage) {
+		return nil
+	}
+
 	h.sendMessage(msg.ChatID, render.MsgResultReady, h.keyboard.ResultDownloadKeyboard(hasSkipped))
 
 	return nil
 }
 
 // handleGenerateDraft handles validation + generation for draft mode
-func (h *CallbackHandler) handleGenerateDraft(ctx context.Context, msg *Message, sessionID string) error {
+func (h *CallbackHandler) handleGenerateDraft(ctx context.Context, msg *Message, sessionID string, confirmed bool) error {
 	// If мы уже вышли из этапа сбора драфта (например, отвечаем на дополнительные вопросы),
 	// повторно валидировать драфт не нужно — просто генерируем требования как в интервью-режиме.
 	session, err := h.sessionUC.GetSession(ctx, sessionID)
@@ -752,7 +1011,7 @@ func (h *CallbackHandler) handleGenerateDraft(ctx context.Context, msg *Message,
 			zap.Error(err),
 			zap.String("session_id", sessionID),
 		)
-		h.sendMessage(msg.ChatID, render.ClassifyError(err), nil)
+		h.sendMessage(msg.ChatID, render.ClassifyError(ctx, err), nil)
 		return nil
 	}
 
@@ -771,7 +1030,7 @@ func (h *CallbackHandler) handleGenerateDraft(ctx context.Context, msg *Message,
 				zap.Error(err),
 				zap.String("session_id", sessionID),
 			)
-			h.sendMessage(msg.ChatID, render.ClassifyError(err), nil)
+			h.sendMessage(msg.ChatID, render.ClassifyError(ctx, err), nil)
 			return nil
 		}
 	}
@@ -785,8 +1044,7 @@ func (h *CallbackHandler) handleGenerateDraft(ctx context.Context, msg *Message,
 
 		questionText := render.RenderQuestion(
 			additionalIteration.Title,
-			1,
-			len(additionalIteration.Questions),
+			questionPosition(ctx, h.sessionUC, h.stateManager, msg.UserID, sessionID, additionalIteration.Questions[0].ID, 1, len(additionalIteration.Questions)),
 			additionalIteration.Questions[0].Question,
 		)
 
@@ -797,7 +1055,7 @@ func (h *CallbackHandler) handleGenerateDraft(ctx context.Context, msg *Message,
 				zap.Error(err),
 				zap.Int64("user_id", msg.UserID),
 			)
-			h.sendMessage(msg.ChatID, render.ClassifyError(err), nil)
+			h.sendMessage(msg.ChatID, render.ClassifyError(ctx, err), nil)
 			return nil
 		}
 
@@ -809,11 +1067,26 @@ func (h *CallbackHandler) handleGenerateDraft(ctx context.Context, msg *Message,
 		h.stateManager.UpdateStateData(ctx, msg.UserID, stateData)
 
 		// First question has no previous
-		h.sendMessage(msg.ChatID, questionText, h.keyboard.QuestionNavigationKeyboard(additionalIteration.Questions[0].ID, false))
+		sendQuestionMessage(ctx, h.bot, h.stateManager, msg, stateData, questionText, additionalIteration.Questions[0].ID, questionKeyboard(ctx, h.stateManager, h.keyboard, msg.UserID, additionalIteration.Questions[0].ID, additionalIteration.Questions[0].AnswerType, false))
+
+		return nil
+	}
 
+	proceed, err := checkGenerationEstimate(ctx, msg, sessionID, confirmed, h.sessionUC, h.stateManager, h.keyboard, h.sendMessage)
+	if err != nil {
+		ctxzap.Error(ctx, "failed to estimate generation",
+			zap.Error(err),
+			zap.String("session_id", sessionID),
+		)
+		h.sendMessage(msg.ChatID, render.ClassifyError(ctx, err), nil)
+		return nil
+	}
+	if !proceed {
 		return nil
 	}
 
+	applySummaryStyle(ctx, msg.UserID, sessionID, h.sessionUC, h.stateManager)
+
 	// No additional questions - generate draft summary
 	session, err = h.sessionUC.GenerateDraftSummary(ctx, sessionID)
 	if err != nil {
@@ -821,7 +1094,7 @@ func (h *CallbackHandler) handleGenerateDraft(ctx context.Context, msg *Message,
 			zap.Error(err),
 			zap.String("session_id", sessionID),
 		)
-		h.sendMessage(msg.ChatID, render.ClassifyError(err), nil)
+		h.sendMessage(msg.ChatID, render.ClassifyError(ctx, err), nil)
 		return nil
 	}
 
@@ -829,6 +1102,7 @@ func (h *CallbackHandler) handleGenerateDraft(ctx context.Context, msg *Message,
 		zap.String("session_id", sessionID),
 		zap.String("status", string(session.Status)),
 	)
+	notifyQuota(ctx, msg.ChatID, entity.QuotaKindGenerations, h.sessionUC, h.sendMessage)
 
 	hasSkipped, err := h.sessionUC.HasSkippedQuestions(ctx, sessionID)
 	if err != nil {
@@ -838,6 +1112,15 @@ func (h *CallbackHandler) handleGenerateDraft(ctx context.Context, msg *Message,
 		)
 	}
 
+	if session.Status == entity.SessionStatusPartial {
+		h.sendMessage(msg.ChatID, render.MsgPartialResult, h.keyboard.PartialResultKeyboard(hasSkipped))
+		return nil
+	}
+
+	if presentConflicts(ctx, msg.ChatID, sessionID, h.sessionUC, h.keyboard, h.sendMessage) {
+		return nil
+	}
+
 	h.sendMessage(msg.ChatID, render.MsgResultReady, h.keyboard.ResultDownloadKeyboard(hasSkipped))
 
 	return nil
@@ -909,7 +1192,7 @@ func (h *CallbackHandler) handleStart(ctx context.Context, msg *Message) error {
 			zap.Error(err),
 			zap.Int64("user_id", msg.UserID),
 		)
-		h.sendMessage(msg.ChatID, render.ClassifyError(err), nil)
+		h.sendMessage(msg.ChatID, render.ClassifyError(ctx, err), nil)
 		return nil
 	}
 
@@ -919,12 +1202,39 @@ func (h *CallbackHandler) handleStart(ctx context.Context, msg *Message) error {
 			zap.Error(err),
 			zap.Int64("user_id", msg.UserID),
 		)
-		h.sendMessage(msg.ChatID, render.ClassifyError(err), nil)
+		h.sendMessage(msg.ChatID, render.ClassifyError(ctx, err), nil)
 		return nil
 	}
 
 	// Ask for user goal
 	h.sendMessage(msg.ChatID, render.MsgAskGoal, nil)
+	notifyQuota(ctx, msg.ChatID, entity.QuotaKindSessions, h.sessionUC, h.sendMessage)
+	return nil
+}
+
+// handleStartDemo starts a sandbox demo session that runs on mock connectors
+func (h *CallbackHandler) handleStartDemo(ctx context.Context, msg *Message) error {
+	session, err := h.sessionUC.StartDemoSession(ctx)
+	if err != nil {
+		ctxzap.Error(ctx, "failed to start demo session",
+			zap.Error(err),
+			zap.Int64("user_id", msg.UserID),
+		)
+		h.sendMessage(msg.ChatID, render.ClassifyError(ctx, err), nil)
+		return nil
+	}
+
+	if err := h.stateManager.CreateOrUpdateSession(ctx, msg.UserID, session.ID); err != nil {
+		ctxzap.Error(ctx, "failed to create telegram session",
+			zap.Error(err),
+			zap.Int64("user_id", msg.UserID),
+		)
+		h.sendMessage(msg.ChatID, render.ClassifyError(ctx, err), nil)
+		return nil
+	}
+
+	h.sendMessage(msg.ChatID, render.MsgDemoSessionStarted, nil)
+	h.sendMessage(msg.ChatID, render.MsgAskGoal, nil)
 	return nil
 }
 
@@ -946,7 +1256,7 @@ func (h *CallbackHandler) handleChooseMode(ctx context.Context, msg *Message) er
 			zap.Error(err),
 			zap.String("session_id", telegramSession.SessionID),
 		)
-		h.sendMessage(msg.ChatID, render.ClassifyError(err), nil)
+		h.sendMessage(msg.ChatID, render.ClassifyError(ctx, err), nil)
 		return nil
 	}
 
@@ -955,7 +1265,41 @@ func (h *CallbackHandler) handleChooseMode(ctx context.Context, msg *Message) er
 	return nil
 }
 
-// handleChangeProject handles project change
+// handleSkipGoalClarification moves on to project selection without clarifying a vague goal
+func (h *CallbackHandler) handleSkipGoalClarification(ctx context.Context, msg *Message) error {
+	telegramSession, err := h.stateManager.GetSession(ctx, msg.UserID)
+	if err != nil {
+		return fmt.Errorf("get user state: %w", err)
+	}
+
+	if _, err := h.sessionUC.SkipGoalClarification(ctx, telegramSession.SessionID); err != nil {
+		ctxzap.Error(ctx, "failed to skip goal clarification",
+			zap.Error(err),
+			zap.String("session_id", telegramSession.SessionID),
+		)
+		h.sendMessage(msg.ChatID, render.ClassifyError(ctx, err), nil)
+		return nil
+	}
+
+	kbProjects, pagination, err := loadProjectSelection(ctx, h.projectUC, msg.UserID, 0)
+	if err != nil {
+		ctxzap.Error(ctx, "failed to list projects",
+			zap.Error(err),
+			zap.Int64("user_id", msg.UserID),
+		)
+		h.sendMessage(msg.ChatID, render.ClassifyError(ctx, err), nil)
+		return nil
+	}
+
+	h.sendMessage(msg.ChatID, render.MsgSelectProject, h.keyboard.ProjectSelectionKeyboardWithPagination(kbProjects, pagination))
+	return nil
+}
+
+// pendingChangeProjectConfirmation marks a session waiting for the user to confirm that changing
+// the project discards the answers
+const pendingChangeProjectConfirmation = "change_project"
+
+// handleChangeProject handles project change; answered questions are discarded only after confirmation
 func (h *CallbackHandler) handleChangeProject(ctx context.Context, msg *Message) error {
 	telegramSession, err := h.stateManager.GetSession(ctx, msg.UserID)
 	if err != nil {
@@ -967,19 +1311,46 @@ func (h *CallbackHandler) handleChangeProject(ctx context.Context, msg *Message)
 		return nil
 	}
 
+	// Answers given in this session are lost with another project, so the user confirms first
+	answered, err := h.sessionUC.CountAnsweredQuestions(ctx, telegramSession.SessionID)
+	if err != nil {
+		ctxzap.Error(ctx, "failed to count answered questions",
+			zap.Error(err),
+			zap.String("session_id", telegramSession.SessionID),
+		)
+		h.sendMessage(msg.ChatID, render.ClassifyError(ctx, err), nil)
+		return nil
+	}
+	if answered > 0 {
+		stateData, err := h.stateManager.GetStateData(ctx, msg.UserID)
+		if err != nil {
+			return fmt.Errorf("get state data: %w", err)
+		}
+		stateData.PendingConfirmation = pendingChangeProjectConfirmation
+		if err := h.stateManager.UpdateStateData(ctx, msg.UserID, stateData); err != nil {
+			return fmt.Errorf("update state data: %w", err)
+		}
+
+		h.sendMessage(msg.ChatID, fmt.Sprintf(render.MsgChangeProjectConfirm, answered), h.keyboard.ChangeProjectConfirmKeyboard())
+		return nil
+	}
+
+	return h.changeProject(ctx, msg, telegramSession.SessionID)
+}
+
+// changeProject moves the session back to the project selection
+func (h *CallbackHandler) changeProject(ctx context.Context, msg *Message, sessionID string) error {
 	// Move backend session back to SELECT_OR_CREATE_PROJECT so that
 	// project selection and context flow can be started again.
-	if _, err := h.sessionUC.RestartProjectSelection(ctx, telegramSession.SessionID); err != nil {
+	if _, err := h.sessionUC.RestartProjectSelection(ctx, sessionID); err != nil {
 		ctxzap.Error(ctx, "failed to restart project selection",
 			zap.Error(err),
-			zap.String("session_id", telegramSession.SessionID),
+			zap.String("session_id", sessionID),
 		)
-		h.sendMessage(msg.ChatID, render.ClassifyError(err), nil)
+		h.sendMessage(msg.ChatID, render.ClassifyError(ctx, err), nil)
 		return nil
 	}
 
-	const pageSize = 10
-
 	// Get state data to get current page
 	stateData, err := h.stateManager.GetStateData(ctx, msg.UserID)
 	if err != nil {
@@ -987,7 +1358,7 @@ func (h *CallbackHandler) handleChangeProject(ctx context.Context, msg *Message)
 			zap.Error(err),
 			zap.Int64("user_id", msg.UserID),
 		)
-		h.sendMessage(msg.ChatID, render.ClassifyError(err), nil)
+		h.sendMessage(msg.ChatID, render.ClassifyError(ctx, err), nil)
 		return nil
 	}
 
@@ -1000,35 +1371,17 @@ func (h *CallbackHandler) handleChangeProject(ctx context.Context, msg *Message)
 		)
 	}
 
-	// Fetch projects with one extra to check if there are more
-	projects, err := h.projectUC.ListProjects(ctx, &entity.ListProjectsRequest{
-		Skip:  0,
-		Limit: pageSize + 1,
-	})
+	kbProjects, pagination, err := loadProjectSelection(ctx, h.projectUC, msg.UserID, 0)
 	if err != nil {
 		ctxzap.Error(ctx, "failed to list projects",
 			zap.Error(err),
 			zap.Int64("user_id", msg.UserID),
 		)
-		h.sendMessage(msg.ChatID, render.ClassifyError(err), nil)
+		h.sendMessage(msg.ChatID, render.ClassifyError(ctx, err), nil)
 		return nil
 	}
 
-	// Check if there are more pages
-	hasNextPage := len(projects) > pageSize
-	if hasNextPage {
-		projects = projects[:pageSize]
-	}
-
-	kbProjects := make([]keyboard.Project, 0, len(projects))
-	for _, p := range projects {
-		kbProjects = append(kbProjects, keyboard.Project{
-			ID:    p.ID,
-			Title: p.Title,
-		})
-	}
-
-	h.sendMessage(msg.ChatID, render.MsgSelectProject, h.keyboard.ProjectSelectionKeyboardWithPagination(kbProjects, false, hasNextPage))
+	h.sendMessage(msg.ChatID, render.MsgSelectProject, h.keyboard.ProjectSelectionKeyboardWithPagination(kbProjects, pagination))
 
 	return nil
 }
@@ -1047,13 +1400,13 @@ func (h *CallbackHandler) handleProjectSelection(ctx context.Context, msg *Messa
 				zap.Error(err),
 				zap.String("session_id", telegramSession.SessionID),
 			)
-			h.sendMessage(msg.ChatID, render.ClassifyError(err), nil)
+			h.sendMessage(msg.ChatID, render.ClassifyError(ctx, err), nil)
 			return nil
 		}
 
 		if len(h.questions) == 0 {
 			ctxzap.Error(ctx, "context questions not configured")
-			h.sendMessage(msg.ChatID, render.ClassifyError(err), nil)
+			h.sendMessage(msg.ChatID, render.ClassifyError(ctx, err), nil)
 			return nil
 		}
 
@@ -1078,10 +1431,20 @@ func (h *CallbackHandler) handleProjectSelection(ctx context.Context, msg *Messa
 			zap.Error(err),
 			zap.String("project_id", projectID),
 		)
-		h.sendMessage(msg.ChatID, render.ClassifyError(err), nil)
+		h.sendMessage(msg.ChatID, render.ClassifyError(ctx, err), nil)
 		return nil
 	}
 
+	// Recently picked projects are listed first next time
+	if err := h.projectUC.MarkProjectUsed(ctx, projectID, msg.UserID); err != nil {
+		ctxzap.Warn(ctx, "failed to mark project as used",
+			zap.Error(err),
+			zap.String("project_id", projectID),
+		)
+	}
+
+	h.warnStaleProject(ctx, msg.ChatID, projectID)
+
 	// Show mode selection
 	h.sendMessage(msg.ChatID, render.MsgChooseMode, h.keyboard.ModeSelectionKeyboard())
 
@@ -1101,7 +1464,7 @@ func (h *CallbackHandler) handleAnswerSkipped(ctx context.Context, msg *Message)
 			zap.Error(err),
 			zap.String("session_id", telegramSession.SessionID),
 		)
-		h.sendMessage(msg.ChatID, render.ClassifyError(err), nil)
+		h.sendMessage(msg.ChatID, render.ClassifyError(ctx, err), nil)
 		return nil
 	}
 
@@ -1116,7 +1479,7 @@ func (h *CallbackHandler) handleAnswerSkipped(ctx context.Context, msg *Message)
 			zap.Error(err),
 			zap.String("session_id", telegramSession.SessionID),
 		)
-		h.sendMessage(msg.ChatID, render.ClassifyError(err), nil)
+		h.sendMessage(msg.ChatID, render.ClassifyError(ctx, err), nil)
 		return nil
 	}
 
@@ -1157,12 +1520,12 @@ func (h *CallbackHandler) handleAnswerSkipped(ctx context.Context, msg *Message)
 			zap.Error(err),
 			zap.Int64("user_id", msg.UserID),
 		)
-		h.sendMessage(msg.ChatID, render.ClassifyError(err), nil)
+		h.sendMessage(msg.ChatID, render.ClassifyError(ctx, err), nil)
 		return nil
 	}
 
 	// First skipped question has no previous
-	h.sendMessage(msg.ChatID, questionText, h.keyboard.QuestionNavigationKeyboard(q.ID, false))
+	sendQuestionMessage(ctx, h.bot, h.stateManager, msg, stateData, questionText, q.ID, questionKeyboard(ctx, h.stateManager, h.keyboard, msg.UserID, q.ID, q.AnswerType, false))
 
 	return nil
 }
@@ -1180,7 +1543,7 @@ func (h *CallbackHandler) handleConfirmation(ctx context.Context, msg *Message,
 		if stateData.PendingConfirmation == "cancel" || stateData.PendingConfirmation == "finish" {
 			telegramSession, err := h.stateManager.GetSession(ctx, msg.UserID)
 			if err != nil {
-				h.sendMessage(msg.ChatID, render.ClassifyError(err), nil)
+				h.sendMessage(msg.ChatID, render.ClassifyError(ctx, err), nil)
 				return nil
 			}
 
@@ -1205,6 +1568,36 @@ func (h *CallbackHandler) handleConfirmation(ctx context.Context, msg *Message,
 			h.sendMessage(msg.ChatID, render.MsgSessionFinished, nil)
 		}
 
+	case pendingGenerateConfirmation:
+		// User confirmed generation of a large session
+		if stateData.PendingConfirmation != pendingGenerateConfirmation {
+			return nil
+		}
+		stateData.PendingConfirmation = ""
+		if err := h.stateManager.UpdateStateData(ctx, msg.UserID, stateData); err != nil {
+			ctxzap.Error(ctx, "failed to clear pending confirmation", zap.Error(err))
+		}
+		return h.generate(ctx, msg, true)
+
+	case pendingChangeProjectConfirmation:
+		// User confirmed discarding the answers with the project change
+		if stateData.PendingConfirmation != pendingChangeProjectConfirmation {
+			return nil
+		}
+		stateData.PendingConfirmation = ""
+		if err := h.stateManager.UpdateStateData(ctx, msg.UserID, stateData); err != nil {
+			ctxzap.Error(ctx, "failed to clear pending confirmation", zap.Error(err))
+		}
+		telegramSession, err := h.stateManager.GetSession(ctx, msg.UserID)
+		if err != nil {
+			return fmt.Errorf("get user state: %w", err)
+		}
+		if telegramSession.SessionID == "" {
+			h.sendMessage(msg.ChatID, render.ErrSessionNotFound, nil)
+			return nil
+		}
+		return h.changeProject(ctx, msg, telegramSession.SessionID)
+
 	case "continue":
 		// User cancelled the destructive action
 		stateData.PendingConfirmation = ""
@@ -1218,9 +1611,11 @@ func (h *CallbackHandler) handleConfirmation(ctx context.Context, msg *Message,
 	return nil
 }
 
-// handlePageNavigation handles pagination navigation (prev/next)
+// handlePageNavigation handles pagination navigation (prev/next/jump)
 func (h *CallbackHandler) handlePageNavigation(ctx context.Context, msg *Message, direction string) error {
-	const pageSize = 10
+	if direction == "jump" {
+		return h.handlePageJump(ctx, msg)
+	}
 
 	// Get state data
 	stateData, err := h.stateManager.GetStateData(ctx, msg.UserID)
@@ -1229,7 +1624,7 @@ func (h *CallbackHandler) handlePageNavigation(ctx context.Context, msg *Message
 			zap.Error(err),
 			zap.Int64("user_id", msg.UserID),
 		)
-		h.sendMessage(msg.ChatID, render.ClassifyError(err), nil)
+		h.sendMessage(msg.ChatID, render.ClassifyError(ctx, err), nil)
 		return nil
 	}
 
@@ -1239,9 +1634,7 @@ func (h *CallbackHandler) handlePageNavigation(ctx context.Context, msg *Message
 	} else if direction == "prev" && stateData.ProjectListPage > 0 {
 		stateData.ProjectListPage--
 	}
-
-	// Calculate offset
-	offset := stateData.ProjectListPage * pageSize
+	stateData.AwaitingProjectPage = false
 
 	// Save updated state
 	if err := h.stateManager.UpdateStateData(ctx, msg.UserID, stateData); err != nil {
@@ -1251,36 +1644,17 @@ func (h *CallbackHandler) handlePageNavigation(ctx context.Context, msg *Message
 		)
 	}
 
-	// Fetch projects with one extra to check if there are more
-	projects, err := h.projectUC.ListProjects(ctx, &entity.ListProjectsRequest{
-		Skip:  offset,
-		Limit: pageSize + 1,
-	})
+	kbProjects, pagination, err := loadProjectSelection(ctx, h.projectUC, msg.UserID, stateData.ProjectListPage)
 	if err != nil {
 		ctxzap.Error(ctx, "failed to list projects",
 			zap.Error(err),
 			zap.Int64("user_id", msg.UserID),
 		)
-		h.sendMessage(msg.ChatID, render.ClassifyError(err), nil)
+		h.sendMessage(msg.ChatID, render.ClassifyError(ctx, err), nil)
 		return nil
 	}
 
-	// Check if there are more pages
-	hasNextPage := len(projects) > pageSize
-	if hasNextPage {
-		projects = projects[:pageSize]
-	}
-
-	kbProjects := make([]keyboard.Project, 0, len(projects))
-	for _, p := range projects {
-		kbProjects = append(kbProjects, keyboard.Project{
-			ID:    p.ID,
-			Title: p.Title,
-		})
-	}
-
-	hasPrevPage := stateData.ProjectListPage > 0
-	h.sendMessage(msg.ChatID, render.MsgSelectProject, h.keyboard.ProjectSelectionKeyboardWithPagination(kbProjects, hasPrevPage, hasNextPage))
+	h.sendMessage(msg.ChatID, render.MsgSelectProject, h.keyboard.ProjectSelectionKeyboardWithPagination(kbProjects, pagination))
 
 	return nil
 }
@@ -1292,6 +1666,22 @@ func (h *CallbackHandler) handleSaveNewProject(ctx context.Context, msg *Message
 		return fmt.Errorf("get user state: %w", err)
 	}
 
+	session, err := h.sessionUC.GetSession(ctx, telegramSession.SessionID)
+	if err != nil {
+		h.HandleError(ctx, msg.ChatID, err)
+		return nil
+	}
+
+	if session.IsDemo {
+		h.HandleError(ctx, msg.ChatID, entity.ErrDemoSession)
+		return nil
+	}
+
+	if err := h.sessionUC.EnsureResultReleasable(ctx, telegramSession.SessionID); err != nil {
+		h.HandleError(ctx, msg.ChatID, err)
+		return nil
+	}
+
 	// Change session status to ask for project name
 	if _, err = h.sessionUC.UpdateSessionStatus(ctx, telegramSession.SessionID, entity.SessionStatusAskProjectName); err != nil {
 		ctxzap.Error(ctx, "failed to update session status",
@@ -1323,6 +1713,11 @@ func (h *CallbackHandler) handleSaveToProject(ctx context.Context, msg *Message)
 		return nil
 	}
 
+	if session.IsDemo {
+		h.HandleError(ctx, msg.ChatID, entity.ErrDemoSession)
+		return nil
+	}
+
 	if session.ProjectID == nil || *session.ProjectID == "" {
 		h.sendMessage(msg.ChatID, "❌ Проект не выбран. Используйте 'Сохранить в новый проект'.", nil)
 		return nil
@@ -1333,6 +1728,11 @@ func (h *CallbackHandler) handleSaveToProject(ctx context.Context, msg *Message)
 		return nil
 	}
 
+	if err := h.sessionUC.EnsureResultReleasable(ctx, telegramSession.SessionID); err != nil {
+		h.HandleError(ctx, msg.ChatID, err)
+		return nil
+	}
+
 	// Get project title for display
 	project, err := h.projectUC.GetProject(ctx, *session.ProjectID)
 	if err != nil {
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/futig/agent-backend/internal/telegram/render"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// handleComments shows open review comments of the result with resolve buttons
+func (h *CallbackHandler) handleComments(ctx context.Context, msg *Message) error {
+	telegramSession, err := h.stateManager.GetSession(ctx, msg.UserID)
+	if err != nil {
+		return fmt.Errorf("get user state: %w", err)
+	}
+	sessionID := telegramSession.SessionID
+
+	comments, err := h.sessionUC.ListComments(ctx, sessionID, true)
+	if err != nil {
+		ctxzap.Error(ctx, "failed to list comments",
+			zap.Error(err),
+			zap.String("session_id", sessionID),
+		)
+		h.HandleError(ctx, msg.ChatID, err)
+		return nil
+	}
+
+	if len(comments) == 0 {
+		h.sendMessage(msg.ChatID, render.MsgNoComments, nil)
+		return nil
+	}
+
+	// Section titles are only used to label comments
+	sections, err := h.sessionUC.ListResultSections(ctx, sessionID)
+	if err != nil {
+		ctxzap.Warn(ctx, "failed to list result sections",
+			zap.Error(err),
+			zap.String("session_id", sessionID),
+		)
+	}
+
+	lines := []string{fmt.Sprintf(render.MsgCommentsHeader, len(comments))}
+	ids := make([]string, 0, len(comments))
+	for i, c := range comments {
+		var anchor string
+		switch {
+		case c.RequirementID != nil:
+			anchor = *c.RequirementID
+		case c.SectionIndex != nil && *c.SectionIndex < len(sections):
+			anchor = sectionTitle(sections[*c.SectionIndex])
+		}
+		lines = append(lines, render.RenderComment(i+1, anchor, c.Author, c.Text))
+		ids = append(ids, c.ID)
+	}
+
+	h.sendMessage(msg.ChatID, strings.Join(lines, "\n\n"), h.keyboard.CommentsKeyboard(ids))
+	return nil
+}
+
+// handleResolveComment resolves a review comment and shows the remaining ones
+func (h *CallbackHandler) handleResolveComment(ctx context.Context, msg *Message, commentID string) error {
+	telegramSession, err := h.stateManager.GetSession(ctx, msg.UserID)
+	if err != nil {
+		return fmt.Errorf("get user state: %w", err)
+	}
+
+	if _, err := h.sessionUC.ResolveComment(ctx, telegramSession.SessionID, commentID); err != nil {
+		ctxzap.Error(ctx, "failed to resolve comment",
+			zap.Error(err),
+			zap.String("session_id", telegramSession.SessionID),
+			zap.String("comment_id", commentID),
+		)
+		h.HandleError(ctx, msg.ChatID, err)
+		return nil
+	}
+
+	h.sendMessage(msg.ChatID, render.MsgCommentResolved, nil)
+	return h.handleComments(ctx, msg)
+}
@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/telegram/keyboard"
+	"github.com/futig/agent-backend/internal/telegram/render"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// presentConflicts announces unresolved requirements conflicts of a freshly generated result
+// and shows the first one. Returns false when there is nothing to resolve.
+func presentConflicts(
+	ctx context.Context,
+	chatID int64,
+	sessionID string,
+	sessionUC SessionUsecase,
+	kb *keyboard.Builder,
+	send func(chatID int64, text string, replyMarkup interface{}),
+) bool {
+	conflicts, err := sessionUC.ListConflicts(ctx, sessionID)
+	if err != nil {
+		ctxzap.Error(ctx, "failed to list conflicts",
+			zap.Error(err),
+			zap.String("session_id", sessionID),
+		)
+		return false
+	}
+
+	open := 0
+	for _, c := range conflicts {
+		if c.Resolution == nil {
+			open++
+		}
+	}
+	if open == 0 {
+		return false
+	}
+
+	send(chatID, fmt.Sprintf(render.MsgConflictsFound, open), nil)
+	return sendNextConflict(chatID, conflicts, kb, send)
+}
+
+// sendNextConflict shows the first unresolved conflict; returns false when all are resolved
+func sendNextConflict(
+	chatID int64,
+	conflicts []*entity.RequirementConflict,
+	kb *keyboard.Builder,
+	send func(chatID int64, text string, replyMarkup interface{}),
+) bool {
+	for i, c := range conflicts {
+		if c.Resolution != nil {
+			continue
+		}
+		text := render.RenderConflict(i+1, len(conflicts), c.Previous, c.Source, c.Requirement)
+		send(chatID, text, kb.ConflictResolutionKeyboard(c.ID))
+		return true
+	}
+	return false
+}
+
+// handleResolveConflict records the decision and shows the next conflict or the ready result
+func (h *CallbackHandler) handleResolveConflict(ctx context.Context, msg *Message, value string) error {
+	resolution, conflictID, ok := strings.Cut(value, ":")
+	if !ok || !entity.ConflictResolution(resolution).IsValid() {
+		return fmt.Errorf("invalid conflict resolution: %s", value)
+	}
+
+	telegramSession, err := h.stateManager.GetSession(ctx, msg.UserID)
+	if err != nil {
+		return fmt.Errorf("get user state: %w", err)
+	}
+	sessionID := telegramSession.SessionID
+
+	if _, err := h.sessionUC.ResolveConflict(ctx, sessionID, conflictID, entity.ConflictResolution(resolution)); err != nil {
+		ctxzap.Error(ctx, "failed to resolve conflict",
+			zap.Error(err),
+			zap.String("session_id", sessionID),
+			zap.String("conflict_id", conflictID),
+		)
+		h.sendMessage(msg.ChatID, render.ClassifyError(ctx, err), nil)
+		return nil
+	}
+
+	conflicts, err := h.sessionUC.ListConflicts(ctx, sessionID)
+	if err != nil {
+		ctxzap.Error(ctx, "failed to list conflicts",
+			zap.Error(err),
+			zap.String("session_id", sessionID),
+		)
+		h.HandleError(ctx, msg.ChatID, err)
+		return nil
+	}
+
+	if sendNextConflict(msg.ChatID, conflicts, h.keyboard, h.sendMessage) {
+		return nil
+	}
+
+	hasSkipped, err := h.sessionUC.HasSkippedQuestions(ctx, sessionID)
+	if err != nil {
+		ctxzap.Error(ctx, "failed to check skipped questions",
+			zap.Error(err),
+			zap.String("session_id", sessionID),
+		)
+	}
+
+	h.sendMessage(msg.ChatID, render.MsgConflictsResolved, nil)
+	h.sendMessage(msg.ChatID, render.MsgResultReady, h.keyboard.ResultDownloadKeyboard(hasSkipped))
+	return nil
+}
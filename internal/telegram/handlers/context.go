@@ -61,7 +61,7 @@ func (h *ContextHandler) Handle(ctx context.Context, msg *Message) error {
 
 	if len(h.questions) == 0 {
 		ctxzap.Error(ctx, "context questions not configured")
-		h.sendMessage(msg.ChatID, render.ClassifyError(err), nil)
+		h.sendMessage(msg.ChatID, render.ClassifyError(ctx, err), nil)
 		return nil
 	}
 
@@ -74,7 +74,7 @@ func (h *ContextHandler) Handle(ctx context.Context, msg *Message) error {
 			zap.String("session_id", sessionID),
 		)
 
-		audioData, err := downloadVoiceFile(ctx, h.bot, msg.Voice.FileID)
+		ctx, audioData, err := downloadVoiceFile(ctx, h.bot, msg.Voice.FileID)
 		if err != nil {
 			ctxzap.Error(ctx, "failed to download context voice file",
 				zap.Error(err),
@@ -96,7 +96,7 @@ func (h *ContextHandler) Handle(ctx context.Context, msg *Message) error {
 				zap.Error(err),
 				zap.String("session_id", sessionID),
 			)
-			h.sendMessage(msg.ChatID, render.ErrTranscription, nil)
+			h.sendMessage(msg.ChatID, voiceSubmitErrorMessage(err), nil)
 			return nil
 		}
 	} else if msg.Text != "" {
@@ -0,0 +1,204 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/telegram/keyboard"
+	"github.com/futig/agent-backend/internal/telegram/render"
+	"github.com/futig/agent-backend/internal/telegram/state"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// finishInterview is called once the last question block is done: deferred questions
+// are asked first, then answers are validated and the summary is generated
+func finishInterview(
+	ctx context.Context,
+	msg *Message,
+	sessionID string,
+	sessionUC SessionUsecase,
+	projectUC ProjectUsecase,
+	stateManager *state.Manager,
+	kb *keyboard.Builder,
+	bot *tgbotapi.BotAPI,
+	logger *zap.Logger,
+	send func(chatID int64, text string, replyMarkup interface{}),
+) error {
+	asked, err := handleNextDeferredQuestion(ctx, msg, sessionID, sessionUC, stateManager, kb, bot, send)
+	if err != nil {
+		return fmt.Errorf("handle deferred questions: %w", err)
+	}
+	if asked {
+		return nil
+	}
+
+	send(msg.ChatID, render.MsgValidating, nil)
+
+	return handleValidationAndSummaryCommon(ctx, msg, sessionID, sessionUC, projectUC, stateManager, kb, bot, logger, send)
+}
+
+// handleNextDeferredQuestion asks the next deferred question, starting the queue on first call.
+// Returns false once no deferred questions are left.
+func handleNextDeferredQuestion(
+	ctx context.Context,
+	msg *Message,
+	sessionID string,
+	sessionUC SessionUsecase,
+	stateManager *state.Manager,
+	kb *keyboard.Builder,
+	bot *tgbotapi.BotAPI,
+	send func(chatID int64, text string, replyMarkup interface{}),
+) (bool, error) {
+	stateData, err := stateManager.GetStateData(ctx, msg.UserID)
+	if err != nil {
+		return false, fmt.Errorf("get state data: %w", err)
+	}
+
+	if !stateData.AnsweringDeferred {
+		deferred, err := sessionUC.GetDeferredQuestions(ctx, sessionID)
+		if err != nil {
+			return false, fmt.Errorf("get deferred questions: %w", err)
+		}
+
+		if len(deferred) == 0 {
+			return false, nil
+		}
+
+		stateData.DeferredQuestionIDs = make([]string, len(deferred))
+		for i, q := range deferred {
+			stateData.DeferredQuestionIDs[i] = q.ID
+		}
+		stateData.CurrentDeferredQuestionIndex = 0
+		stateData.AnsweringDeferred = true
+		stateData.NextQuestionIDs = []string{}
+
+		send(msg.ChatID, fmt.Sprintf(render.MsgDeferredQueueStart, len(deferred)), nil)
+	} else {
+		stateData.CurrentDeferredQuestionIndex++
+	}
+
+	// Questions answered meanwhile, e.g. by replying to them, are not asked again
+	for ; stateData.CurrentDeferredQuestionIndex < len(stateData.DeferredQuestionIDs); stateData.CurrentDeferredQuestionIndex++ {
+		questionID := stateData.DeferredQuestionIDs[stateData.CurrentDeferredQuestionIndex]
+		question, err := sessionUC.GetQuestionByID(ctx, questionID)
+		if err != nil {
+			return false, fmt.Errorf("get question by id: %w", err)
+		}
+
+		if question.Status != entity.AnswerStatusDeferred {
+			continue
+		}
+
+		questionText := render.RenderDeferredQuestion(
+			stateData.CurrentDeferredQuestionIndex+1,
+			len(stateData.DeferredQuestionIDs),
+			question.Question,
+		)
+
+		// Track question history for back navigation (only one level)
+		if stateData.CurrentQuestionID != "" {
+			stateData.PreviousQuestionID = stateData.CurrentQuestionID
+		}
+
+		stateData.CurrentIterationID = question.IterationID
+		stateData.CurrentQuestionID = question.ID
+
+		if err := stateManager.UpdateStateData(ctx, msg.UserID, stateData); err != nil {
+			return false, fmt.Errorf("update state data: %w", err)
+		}
+
+		hasPrevious := stateData.PreviousQuestionID != ""
+		sendQuestionMessage(ctx, bot, stateManager, msg, stateData, questionText, question.ID, questionKeyboard(ctx, stateManager, kb, msg.UserID, question.ID, question.AnswerType, hasPrevious))
+
+		return true, nil
+	}
+
+	ctxzap.Info(ctx, "deferred questions completed",
+		zap.String("session_id", sessionID),
+	)
+
+	stateData.AnsweringDeferred = false
+	stateData.DeferredQuestionIDs = nil
+	stateData.CurrentDeferredQuestionIndex = 0
+	if err := stateManager.UpdateStateData(ctx, msg.UserID, stateData); err != nil {
+		return false, fmt.Errorf("update state data: %w", err)
+	}
+
+	return false, nil
+}
+
+// handleDeferQuestion moves the current question to the deferred queue. Within the queue
+// the question goes to its end; previously skipped questions cannot be deferred.
+func (h *CallbackHandler) handleDeferQuestion(ctx context.Context, msg *Message, questionID string) error {
+	telegramSession, err := h.stateManager.GetSession(ctx, msg.UserID)
+	if err != nil {
+		return fmt.Errorf("get user state: %w", err)
+	}
+
+	stateData, err := h.stateManager.GetStateData(ctx, msg.UserID)
+	if err != nil {
+		return fmt.Errorf("get state data: %w", err)
+	}
+
+	if stateData.CurrentQuestionID == "" {
+		h.sendMessage(msg.ChatID, "❌ Текущий вопрос не найден. Нажмите /start", nil)
+		return nil
+	}
+
+	if stateData.AnsweringSkipped {
+		h.sendMessage(msg.ChatID, render.MsgDeferUnavailable, nil)
+		return nil
+	}
+
+	if stateData.AnsweringDeferred {
+		return h.requeueDeferredQuestion(ctx, msg, telegramSession.SessionID, stateData)
+	}
+
+	nextIteration, err := h.sessionUC.DeferQuestion(ctx, telegramSession.SessionID, questionID)
+	if err != nil {
+		ctxzap.Error(ctx, "failed to defer question",
+			zap.Error(err),
+			zap.String("question_id", questionID),
+		)
+		h.sendMessage(msg.ChatID, render.ClassifyError(ctx, err), nil)
+		return nil
+	}
+
+	h.sendMessage(msg.ChatID, render.MsgQuestionDeferred, nil)
+
+	return h.continueInterview(ctx, msg, telegramSession.SessionID, stateData, nextIteration)
+}
+
+// requeueDeferredQuestion moves the current deferred question to the end of the queue
+func (h *CallbackHandler) requeueDeferredQuestion(ctx context.Context, msg *Message, sessionID string, stateData *state.StateData) error {
+	index := stateData.CurrentDeferredQuestionIndex
+	if index >= len(stateData.DeferredQuestionIDs)-1 {
+		h.sendMessage(msg.ChatID, render.MsgDeferLastQuestion, nil)
+		return nil
+	}
+
+	questionID := stateData.DeferredQuestionIDs[index]
+	queue := append(stateData.DeferredQuestionIDs[:index:index], stateData.DeferredQuestionIDs[index+1:]...)
+	stateData.DeferredQuestionIDs = append(queue, questionID)
+	// handleNextDeferredQuestion advances the index to the question that took this place
+	stateData.CurrentDeferredQuestionIndex--
+
+	if err := h.stateManager.UpdateStateData(ctx, msg.UserID, stateData); err != nil {
+		return fmt.Errorf("update state data: %w", err)
+	}
+
+	h.sendMessage(msg.ChatID, render.MsgQuestionDeferred, nil)
+
+	if err := finishInterview(ctx, msg, sessionID, h.sessionUC, h.projectUC, h.stateManager, h.keyboard, h.bot, h.logger, h.sendMessage); err != nil {
+		ctxzap.Error(ctx, "failed to ask next deferred question",
+			zap.Error(err),
+			zap.String("session_id", sessionID),
+		)
+		h.sendMessage(msg.ChatID, render.ClassifyError(ctx, err), nil)
+	}
+
+	return nil
+}
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/pkg/formatter"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// sendChangeLog sends the change log of a delta session as a markdown document.
+// Nothing is sent while the result is not releasable; the change log stays available over HTTP.
+func sendChangeLog(ctx context.Context, bot *tgbotapi.BotAPI, chatID int64, session *entity.Session, sessionUC SessionUsecase) {
+	if session.Type == nil || *session.Type != entity.SessionTypeDelta {
+		return
+	}
+
+	delta, err := sessionUC.GetChangeLog(ctx, session.ID)
+	if err != nil {
+		if errors.Is(err, entity.ErrResultNotApproved) || errors.Is(err, entity.ErrNoChangeLog) {
+			ctxzap.Debug(ctx, "change log is not available", zap.Error(err))
+			return
+		}
+		ctxzap.Error(ctx, "failed to get change log",
+			zap.Error(err),
+			zap.String("session_id", session.ID),
+		)
+		return
+	}
+
+	fileInfo, err := sessionUC.GetResultFileInfo(ctx, session.ID)
+	if err != nil {
+		ctxzap.Error(ctx, "failed to get result file info",
+			zap.Error(err),
+			zap.String("session_id", session.ID),
+		)
+		return
+	}
+
+	filename := formatter.FileName(fileInfo, "changelog", ".md")
+	doc := tgbotapi.NewDocument(chatID, tgbotapi.FileBytes{
+		Name:  formatter.ASCIIFileName(filename),
+		Bytes: []byte(*delta.ChangeLog),
+	})
+	if _, err := bot.Send(doc); err != nil {
+		ctxzap.Error(ctx, "failed to send change log",
+			zap.Error(err),
+			zap.String("session_id", session.ID),
+		)
+	}
+}
@@ -47,7 +47,15 @@ func NewDraftHandler(
 	}
 }
 
-// Handle processes draft messages (text or voice) in DRAFT_COLLECTING state
+// draftMessageLimit returns the configured draft messages limit, 10 when unset
+func (h *DraftHandler) draftMessageLimit() int {
+	if h.maxDraftMessages <= 0 {
+		return 10
+	}
+	return h.maxDraftMessages
+}
+
+// Handle processes draft messages (text, voice or album) in DRAFT_COLLECTING state
 func (h *DraftHandler) Handle(ctx context.Context, msg *Message) error {
 	telegramSession, err := h.stateManager.GetSession(ctx, msg.UserID)
 	if err != nil {
@@ -64,12 +72,13 @@ func (h *DraftHandler) Handle(ctx context.Context, msg *Message) error {
 		return fmt.Errorf("get state data: %w", err)
 	}
 
-	// Enforce max draft messages
-	maxMessages := h.maxDraftMessages
-	if maxMessages <= 0 {
-		maxMessages = 10
+	if handlePendingSearch(ctx, msg, sessionID, stateData, h.sessionUC, h.stateManager, h.sendMessage) {
+		return nil
 	}
 
+	// Enforce max draft messages
+	maxMessages := h.draftMessageLimit()
+
 	if stateData.DraftMessageCount >= maxMessages {
 		h.sendMessage(msg.ChatID, render.RenderMaxDraftMessagesError(maxMessages), h.keyboard.DraftCollectionKeyboard())
 		return nil
@@ -84,7 +93,7 @@ func (h *DraftHandler) Handle(ctx context.Context, msg *Message) error {
 			zap.String("session_id", sessionID),
 		)
 
-		audioData, err := downloadVoiceFile(ctx, h.bot, msg.Voice.FileID)
+		ctx, audioData, err := downloadVoiceFile(ctx, h.bot, msg.Voice.FileID)
 		if err != nil {
 			ctxzap.Error(ctx, "failed to download draft voice file",
 				zap.Error(err),
@@ -107,7 +116,7 @@ func (h *DraftHandler) Handle(ctx context.Context, msg *Message) error {
 				zap.Error(err),
 				zap.String("session_id", sessionID),
 			)
-			h.sendMessage(msg.ChatID, render.ErrTranscription, nil)
+			h.sendMessage(msg.ChatID, voiceSubmitErrorMessage(err), nil)
 			return nil
 		}
 	} else if msg.Text != "" {
@@ -117,7 +126,26 @@ func (h *DraftHandler) Handle(ctx context.Context, msg *Message) error {
 			zap.String("session_id", sessionID),
 		)
 
-		createdMsg, err = h.sessionUC.AddDraftMessage(ctx, sessionID, msg.Text)
+		createdMsg, err = h.sessionUC.AddDraftMessage(ctx, sessionID, forwardedDraftText(ctx, msg, msg.Text))
+		if err != nil {
+			h.HandleSubmitError(ctx, h.keyboard, msg, err)
+			return nil
+		}
+	} else if len(msg.Album) > 0 {
+		// Album: captions and text documents of all items become one draft message
+		ctxzap.Info(ctx, "processing draft album",
+			zap.Int64("user_id", msg.UserID),
+			zap.String("session_id", sessionID),
+			zap.Int("items", len(msg.Album)),
+		)
+
+		text := albumText(ctx, h.bot, msg.Album)
+		if text == "" {
+			h.sendMessage(msg.ChatID, render.ErrEmptyAlbum, nil)
+			return nil
+		}
+
+		createdMsg, err = h.sessionUC.AddDraftMessage(ctx, sessionID, forwardedDraftText(ctx, msg, text))
 		if err != nil {
 			h.HandleError(ctx, msg.ChatID, err)
 			return nil
@@ -131,7 +159,7 @@ func (h *DraftHandler) Handle(ctx context.Context, msg *Message) error {
 		ctxzap.Warn(ctx, "draft message created is nil",
 			zap.String("session_id", sessionID),
 		)
-		h.sendMessage(msg.ChatID, render.ClassifyError(err), nil)
+		h.sendMessage(msg.ChatID, render.ClassifyError(ctx, err), nil)
 		return nil
 	}
 
@@ -153,3 +181,12 @@ func (h *DraftHandler) Handle(ctx context.Context, msg *Message) error {
 
 	return nil
 }
+
+// forwardedDraftText prepends the forward origin to the text of forwarded draft messages,
+// dated in the timezone of the user
+func forwardedDraftText(ctx context.Context, msg *Message, text string) string {
+	if msg.Forward == nil {
+		return text
+	}
+	return render.RenderForwardedDraft(msg.Forward.Name, entity.LocalTime(ctx, msg.Forward.Date), text)
+}
@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/futig/agent-backend/internal/telegram/render"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// handleDraftCoverage shows what the collected draft materials cover and what is still missing;
+// the session keeps collecting messages, so the user can add materials before generating
+func (h *CallbackHandler) handleDraftCoverage(ctx context.Context, msg *Message) error {
+	telegramSession, err := h.stateManager.GetSession(ctx, msg.UserID)
+	if err != nil {
+		return fmt.Errorf("get user state: %w", err)
+	}
+	sessionID := telegramSession.SessionID
+
+	h.sendMessage(msg.ChatID, render.MsgDraftCoverageChecking, nil)
+
+	coverage, err := h.sessionUC.PreviewDraftValidation(ctx, sessionID)
+	if err != nil {
+		ctxzap.Error(ctx, "failed to preview draft validation",
+			zap.Error(err),
+			zap.String("session_id", sessionID),
+		)
+		h.HandleError(ctx, msg.ChatID, err)
+		return nil
+	}
+
+	h.sendMessage(msg.ChatID, render.RenderDraftCoverage(coverage.MessageCount, coverage.Covered, coverage.Missing), h.keyboard.DraftCollectionKeyboard())
+	return nil
+}
@@ -6,6 +6,7 @@ import (
 	"net"
 
 	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/telegram/keyboard"
 	"github.com/futig/agent-backend/internal/telegram/render"
 	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
 	"go.uber.org/zap"
@@ -76,6 +77,48 @@ func classifyHandlerError(err error) *HandlerError {
 			LogMessage:  "question not found",
 			Severity:    SeverityWarning,
 		}
+	case errors.Is(err, entity.ErrContentBlocked):
+		return &HandlerError{
+			Err:         err,
+			UserMessage: render.ErrContentBlocked,
+			LogMessage:  "content blocked by moderation",
+			Severity:    SeverityWarning,
+		}
+	case errors.Is(err, entity.ErrLLMOverloaded):
+		return &HandlerError{
+			Err:         err,
+			UserMessage: render.ErrLLMOverloaded,
+			LogMessage:  "llm service is overloaded",
+			Severity:    SeverityWarning,
+		}
+	case errors.Is(err, entity.ErrSessionBusy):
+		return &HandlerError{
+			Err:         err,
+			UserMessage: render.ErrSessionBusy,
+			LogMessage:  "session is busy with another action",
+			Severity:    SeverityWarning,
+		}
+	case errors.Is(err, entity.ErrOperationInProgress):
+		return &HandlerError{
+			Err:         err,
+			UserMessage: render.ErrOperationInProgress,
+			LogMessage:  "another operation is in progress on the session",
+			Severity:    SeverityWarning,
+		}
+	case errors.Is(err, entity.ErrASRUnavailable):
+		return &HandlerError{
+			Err:         err,
+			UserMessage: render.ErrVoiceUnavailable,
+			LogMessage:  "speech recognition is unavailable",
+			Severity:    SeverityWarning,
+		}
+	case errors.Is(err, entity.ErrResultNotApproved):
+		return &HandlerError{
+			Err:         err,
+			UserMessage: render.ErrResultNotApproved,
+			LogMessage:  "result is not approved",
+			Severity:    SeverityWarning,
+		}
 	case errors.Is(err, entity.ErrSessionNotActive):
 		return &HandlerError{
 			Err:         err,
@@ -131,8 +174,45 @@ func (h *BaseHandler) HandleError(ctx context.Context, chatID int64, err error)
 	}
 
 	handlerErr := classifyHandlerError(err)
+	logHandlerError(ctx, chatID, handlerErr)
+
+	// Send user-friendly message
+	if h.messageSender != nil {
+		h.messageSender.Send(chatID, render.IncidentText(ctx, handlerErr.UserMessage), nil)
+	}
+}
+
+// HandleSubmitError reports a failed submission of the message text. A text kept in the inbox
+// stays there, and unless sending it again cannot help, the error comes with a button doing so
+func (h *BaseHandler) HandleSubmitError(ctx context.Context, kb *keyboard.Builder, msg *Message, err error) {
+	if msg.InboxID == "" || !retryableSubmitError(err) {
+		h.HandleError(ctx, msg.ChatID, err)
+		return
+	}
+
+	handlerErr := classifyHandlerError(err)
+	logHandlerError(ctx, msg.ChatID, handlerErr)
 
-	// Log with appropriate severity level
+	msg.KeepInbox = true
+	if h.messageSender != nil {
+		h.messageSender.Send(msg.ChatID, render.IncidentText(ctx, handlerErr.UserMessage)+"\n\n"+render.MsgInboxRetryHint, kb.InboxRetryKeyboard(msg.InboxID))
+	}
+}
+
+// retryableSubmitError reports whether the same text may be accepted when it is sent again
+func retryableSubmitError(err error) bool {
+	switch {
+	case errors.Is(err, entity.ErrContentBlocked),
+		errors.Is(err, entity.ErrSessionNotFound),
+		errors.Is(err, entity.ErrSessionNotActive),
+		errors.Is(err, entity.ErrQuestionNotFound):
+		return false
+	}
+	return true
+}
+
+// logHandlerError logs a classified handler error with its severity level
+func logHandlerError(ctx context.Context, chatID int64, handlerErr *HandlerError) {
 	switch handlerErr.Severity {
 	case SeverityCritical:
 		ctxzap.Error(ctx, handlerErr.LogMessage,
@@ -150,9 +230,15 @@ func (h *BaseHandler) HandleError(ctx context.Context, chatID int64, err error)
 			zap.Int64("chat_id", chatID),
 		)
 	}
+}
 
-	// Send user-friendly message
-	if h.messageSender != nil {
-		h.messageSender.Send(chatID, handlerErr.UserMessage, nil)
+// voiceSubmitErrorMessage returns the user message for a failed voice submission
+func voiceSubmitErrorMessage(err error) string {
+	switch {
+	case errors.Is(err, entity.ErrContentBlocked):
+		return render.ErrContentBlocked
+	case errors.Is(err, entity.ErrASRUnavailable):
+		return render.ErrVoiceUnavailable
 	}
+	return render.ErrTranscription
 }
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/futig/agent-backend/internal/telegram/keyboard"
+	"github.com/futig/agent-backend/internal/telegram/render"
+	"github.com/futig/agent-backend/internal/telegram/state"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// pendingGenerateConfirmation marks a large session waiting for the user to confirm generation
+const pendingGenerateConfirmation = "generate"
+
+// checkGenerationEstimate shows the generation estimate before the final step and reports
+// whether generation may start right away. Large sessions ask for confirmation (unless
+// already confirmed), oversized ones wait for admin approval.
+func checkGenerationEstimate(
+	ctx context.Context,
+	msg *Message,
+	sessionID string,
+	confirmed bool,
+	sessionUC SessionUsecase,
+	stateManager *state.Manager,
+	kb *keyboard.Builder,
+	send func(chatID int64, text string, replyMarkup interface{}),
+) (bool, error) {
+	estimate, err := sessionUC.EstimateGeneration(ctx, sessionID)
+	if err != nil {
+		return false, fmt.Errorf("estimate generation: %w", err)
+	}
+
+	ctxzap.Info(ctx, "generation estimated",
+		zap.String("session_id", sessionID),
+		zap.Int("estimated_tokens", estimate.EstimatedTokens),
+		zap.Int("estimated_seconds", estimate.EstimatedSeconds),
+		zap.Bool("requires_confirmation", estimate.RequiresConfirmation),
+		zap.Bool("awaiting_approval", estimate.AwaitingApproval()),
+	)
+
+	text := render.RenderGenerationEstimate(
+		estimate.MaterialChars,
+		estimate.EstimatedTokens,
+		estimate.EstimatedSeconds,
+		estimate.EstimatedCost,
+		estimate.Currency,
+	)
+
+	if estimate.AwaitingApproval() {
+		send(msg.ChatID, text+"\n\n"+render.MsgGenerationAwaitsApproval, kb.GenerationApprovalKeyboard())
+		return false, nil
+	}
+
+	if confirmed {
+		return true, nil
+	}
+
+	if estimate.RequiresConfirmation {
+		stateData, err := stateManager.GetStateData(ctx, msg.UserID)
+		if err != nil {
+			return false, fmt.Errorf("get state data: %w", err)
+		}
+
+		stateData.PendingConfirmation = pendingGenerateConfirmation
+		if err := stateManager.UpdateStateData(ctx, msg.UserID, stateData); err != nil {
+			return false, fmt.Errorf("update state data: %w", err)
+		}
+
+		send(msg.ChatID, text+"\n\n"+render.MsgGenerationConfirmRequired, kb.GenerationConfirmKeyboard())
+		return false, nil
+	}
+
+	send(msg.ChatID, text, nil)
+	return true, nil
+}
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/futig/agent-backend/internal/entity"
@@ -68,7 +69,7 @@ func (h *GoalHandler) Handle(ctx context.Context, msg *Message) error {
 		)
 
 		// Download voice file
-		audioData, err := downloadVoiceFile(ctx, h.bot, msg.Voice.FileID)
+		ctx, audioData, err := downloadVoiceFile(ctx, h.bot, msg.Voice.FileID)
 		if err != nil {
 			ctxzap.Error(ctx, "failed to download voice file",
 				zap.Error(err),
@@ -88,12 +89,16 @@ func (h *GoalHandler) Handle(ctx context.Context, msg *Message) error {
 
 		// Submit audio goal
 		_, err = h.sessionUC.SubmitAudioUserGoal(ctx, sessionID, audioData)
+		if errors.Is(err, entity.ErrGoalTooVague) {
+			h.sendMessage(msg.ChatID, render.MsgGoalClarification, h.keyboard.GoalClarificationKeyboard())
+			return nil
+		}
 		if err != nil {
 			ctxzap.Error(ctx, "failed to submit audio goal",
 				zap.Error(err),
 				zap.String("session_id", sessionID),
 			)
-			h.sendMessage(msg.ChatID, render.ErrTranscription, nil)
+			h.sendMessage(msg.ChatID, voiceSubmitErrorMessage(err), nil)
 			return nil
 		}
 	} else if msg.Text != "" {
@@ -104,6 +109,10 @@ func (h *GoalHandler) Handle(ctx context.Context, msg *Message) error {
 		)
 
 		_, err = h.sessionUC.SubmitTextUserGoal(ctx, sessionID, msg.Text)
+		if errors.Is(err, entity.ErrGoalTooVague) {
+			h.sendMessage(msg.ChatID, render.MsgGoalClarification, h.keyboard.GoalClarificationKeyboard())
+			return nil
+		}
 		if err != nil {
 			h.HandleError(ctx, msg.ChatID, err)
 			return nil
@@ -124,42 +133,17 @@ func (h *GoalHandler) Handle(ctx context.Context, msg *Message) error {
 
 // showProjectSelection lists projects with pagination and shows selection keyboard
 func (h *GoalHandler) showProjectSelection(ctx context.Context, userID int64, chatID int64) error {
-	const pageSize = 10
-
 	// Get state data to get current page
 	stateData, err := h.stateManager.GetStateData(ctx, userID)
 	if err != nil {
 		return fmt.Errorf("get state data: %w", err)
 	}
 
-	// Calculate offset
-	page := stateData.ProjectListPage
-	offset := page * pageSize
-
-	// Fetch projects with one extra to check if there are more
-	projects, err := h.projectUC.ListProjects(ctx, &entity.ListProjectsRequest{
-		Skip:  offset,
-		Limit: pageSize + 1, // Fetch one extra to check if there are more pages
-	})
+	kbProjects, pagination, err := loadProjectSelection(ctx, h.projectUC, userID, stateData.ProjectListPage)
 	if err != nil {
-		return fmt.Errorf("list projects: %w", err)
-	}
-
-	// Check if there are more pages
-	hasNextPage := len(projects) > pageSize
-	if hasNextPage {
-		projects = projects[:pageSize] // Trim to page size
-	}
-
-	kbProjects := make([]keyboard.Project, 0, len(projects))
-	for _, p := range projects {
-		kbProjects = append(kbProjects, keyboard.Project{
-			ID:    p.ID,
-			Title: p.Title,
-		})
+		return err
 	}
 
-	hasPrevPage := page > 0
-	h.sendMessage(chatID, render.MsgSelectProject, h.keyboard.ProjectSelectionKeyboardWithPagination(kbProjects, hasPrevPage, hasNextPage))
+	h.sendMessage(chatID, render.MsgSelectProject, h.keyboard.ProjectSelectionKeyboardWithPagination(kbProjects, pagination))
 	return nil
 }
@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"context"
+	"time"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
@@ -15,18 +16,42 @@ const (
 	HandlerStateDraftCollecting       = "DRAFT_COLLECTING"
 	HandlerStateAskProjectName        = "ASK_PROJECT_NAME"
 	HandlerStateAskProjectDescription = "ASK_PROJECT_DESCRIPTION"
+	HandlerStateAskSectionGuidance    = "ASK_SECTION_GUIDANCE"
+	HandlerStateAskResultFeedback     = "ASK_RESULT_FEEDBACK"
+	HandlerStateSelectProject         = "SELECT_OR_CREATE_PROJECT"
 )
 
 // Message represents a normalized Telegram message
 type Message struct {
-	ChatID       int64
-	UserID       int64
-	MessageID    int
-	Text         string
-	Voice        *tgbotapi.Voice
-	Document     *tgbotapi.Document
-	CallbackData string
-	CallbackID   string
+	ChatID           int64
+	UserID           int64
+	MessageID        int
+	ReplyToMessageID int
+	Text             string
+	Voice            *tgbotapi.Voice
+	Document         *tgbotapi.Document
+	Album            []AlbumItem    // items of a media group delivered as a single message
+	Forward          *ForwardOrigin // set for forwarded messages when forward metadata is kept
+	CallbackData     string
+	CallbackID       string
+	// AnsweredQuestionID is set for quick answers given with the buttons of a question message
+	AnsweredQuestionID string
+	// InboxID is the ID of the kept raw text, set while the submission of the text can fail
+	InboxID string
+	// KeepInbox is set by the handler when the submission failed and the text stays for a retry
+	KeepInbox bool
+}
+
+// ForwardOrigin describes where a forwarded message comes from
+type ForwardOrigin struct {
+	Name string // empty when the sender is unknown
+	Date time.Time
+}
+
+// AlbumItem is one item of a forwarded media group (album)
+type AlbumItem struct {
+	Caption  string
+	Document *tgbotapi.Document
 }
 
 // Handler defines the interface for state-specific handlers
@@ -65,6 +90,9 @@ var validStates = map[string]bool{
 	HandlerStateDraftCollecting:       true,
 	HandlerStateAskProjectName:        true,
 	HandlerStateAskProjectDescription: true,
+	HandlerStateAskSectionGuidance:    true,
+	HandlerStateAskResultFeedback:     true,
+	HandlerStateSelectProject:         true,
 }
 
 // IsValidState checks if a state is valid for handler registration
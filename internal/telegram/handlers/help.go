@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"github.com/futig/agent-backend/internal/telegram/render"
+	"github.com/futig/agent-backend/internal/telegram/state"
+)
+
+// HelpProvider is implemented by handlers that describe what the user can do in their state.
+// /help shows the description of the handler registered for the current session status.
+type HelpProvider interface {
+	Help(stateData *state.StateData) string
+}
+
+// Help implements HelpProvider
+func (h *GoalHandler) Help(_ *state.StateData) string {
+	return render.MsgHelpAskGoal
+}
+
+// Help implements HelpProvider
+func (h *ContextHandler) Help(_ *state.StateData) string {
+	return render.MsgHelpAskContext
+}
+
+// Help implements HelpProvider
+func (h *QuestionsHandler) Help(stateData *state.StateData) string {
+	switch {
+	case stateData.AwaitingSearch:
+		return render.MsgHelpSearch
+	case stateData.EditingQuestionID != "":
+		return render.MsgHelpEditAnswer
+	case stateData.AnsweringSkipped:
+		return render.MsgHelpQuestionsSkipped
+	case stateData.AnsweringDeferred:
+		return render.MsgHelpQuestionsDeferred
+	default:
+		return render.MsgHelpQuestions
+	}
+}
+
+// Help implements HelpProvider
+func (h *DraftHandler) Help(stateData *state.StateData) string {
+	if stateData.AwaitingSearch {
+		return render.MsgHelpSearch
+	}
+
+	return render.RenderDraftHelp(
+		stateData.DraftMessageCount,
+		h.draftMessageLimit(),
+		maxVoiceFileSize/(1024*1024),
+		maxAlbumDocumentSize/1024,
+	)
+}
+
+// Help implements HelpProvider
+func (h *ProjectNameHandler) Help(_ *state.StateData) string {
+	return render.MsgHelpProjectName
+}
+
+// Help implements HelpProvider
+func (h *ProjectDescriptionHandler) Help(_ *state.StateData) string {
+	return render.MsgHelpProjectDescription
+}
+
+// Help implements HelpProvider
+func (h *SectionGuidanceHandler) Help(_ *state.StateData) string {
+	return render.MsgHelpSectionGuidance
+}
+
+// Help implements HelpProvider
+func (h *ResultFeedbackHandler) Help(_ *state.StateData) string {
+	return render.MsgHelpResultFeedback
+}
@@ -11,8 +11,10 @@ import (
 type SessionUsecase interface {
 	// Bot methods - granular operations for Telegram bot workflow
 	StartSession(ctx context.Context) (*entity.Session, error)
+	StartDemoSession(ctx context.Context) (*entity.Session, error)
 	SubmitTextUserGoal(ctx context.Context, sessionID, goal string) (*entity.Session, error)
 	SubmitAudioUserGoal(ctx context.Context, sessionID string, audioGoal []byte) (*entity.Session, error)
+	SkipGoalClarification(ctx context.Context, sessionID string) (*entity.Session, error)
 	SubmitRAGProjectContext(ctx context.Context, sessionID, projectID string) (*entity.Session, error)
 	SubmitTextUserProjectContext(ctx context.Context, sessionID, questions, answers string) (*entity.Session, error)
 	SubmitAudioUserProjectContext(ctx context.Context, sessionID, questions string, audioAnswers []byte) (*entity.Session, error)
@@ -20,38 +22,99 @@ type SessionUsecase interface {
 	StartManualContext(ctx context.Context, sessionID string) (*entity.Session, error)
 	RestartModeSelection(ctx context.Context, sessionID string) (*entity.Session, error)
 	RestartProjectSelection(ctx context.Context, sessionID string) (*entity.Session, error)
+	CountAnsweredQuestions(ctx context.Context, sessionID string) (int, error)
 	StartDraftCollecting(ctx context.Context, sessionID string) (*entity.Session, error)
 	LoadSessionQuestions(ctx context.Context, sessionID string) ([]*entity.IterationWithQuestions, error)
 	SkipAnswer(ctx context.Context, sessionID, questionID string) (*entity.IterationWithQuestions, error)
+	DeferQuestion(ctx context.Context, sessionID, questionID string) (*entity.IterationWithQuestions, error)
+	GetDeferredQuestions(ctx context.Context, sessionID string) ([]*entity.Question, error)
+	SetSkipReason(ctx context.Context, sessionID, questionID string, reason entity.SkipReason) error
 	SubmitTextAnswer(ctx context.Context, sessionID, questionID, answer string) (*entity.IterationWithQuestions, error)
 	SubmitAudioAnswer(ctx context.Context, sessionID, questionID string, audioAnswer []byte) (*entity.IterationWithQuestions, error)
+	ListAnsweredQuestions(ctx context.Context, sessionID string) ([]*entity.Question, error)
+	UpdateAnswer(ctx context.Context, sessionID, questionID, answer string) error
+	DetectLanguageSwitch(ctx context.Context, sessionID, text string) (entity.ResultLanguage, error)
+	SetSessionLanguage(ctx context.Context, sessionID string, language entity.ResultLanguage) (*entity.Session, error)
+	SetSummaryStyle(ctx context.Context, sessionID string, style entity.SummaryStyle) (*entity.Session, error)
+	QueueVoiceAnswer(ctx context.Context, sessionID, questionID string, telegramUserID int64, audio []byte) (bool, error)
 	HasSkippedQuestions(ctx context.Context, sessionID string) (bool, error)
 	SetWaitingForAnswersStatus(ctx context.Context, sessionID string) error
 	SkipSkipedQuestion(ctx context.Context, sessionID, questionID string) ([]*entity.Question, error)
 	GetUnansweredQuestions(ctx context.Context, sessionID string) ([]*entity.Question, error)
 	GetQuestionExplanation(ctx context.Context, questionID string) (string, error)
 	GetQuestionByID(ctx context.Context, questionID string) (*entity.Question, error)
+	GetQuestionProgress(ctx context.Context, sessionID, questionID string) (*entity.QuestionProgress, error)
 	GetIterationByID(ctx context.Context, iterationID string) (*entity.IterationWithQuestions, error)
 	ValidateAnswers(ctx context.Context, sessionID string) (*entity.IterationWithQuestions, error)
 	GenerateSummary(ctx context.Context, sessionID string) (*entity.Session, error)
+	GenerateSummaryStream(ctx context.Context, sessionID string, onProgress func(partial string)) (*entity.Session, error)
+	EstimateGeneration(ctx context.Context, sessionID string) (*entity.GenerationEstimate, error)
+	CheckTimeBudget(ctx context.Context, sessionID string) (*entity.TimeBudgetStatus, error)
+	SkipRemainingQuestions(ctx context.Context, sessionID string) (int, error)
 	// Draft mode methods
 	AddDraftMessage(ctx context.Context, sessionID, messageText string) (*entity.SessionMessage, error)
 	AddAudioDraftMessage(ctx context.Context, sessionID string, audioData []byte) (*entity.SessionMessage, error)
 	ValidateDraftMessages(ctx context.Context, sessionID string) (*entity.IterationWithQuestions, error)
+	PreviewDraftValidation(ctx context.Context, sessionID string) (*entity.DraftCoverage, error)
 	GenerateDraftSummary(ctx context.Context, sessionID string) (*entity.Session, error)
 	// Common methods
+	SearchSessionContent(ctx context.Context, sessionID, query string) ([]*entity.SessionSearchHit, error)
 	GetSession(ctx context.Context, sessionID string) (*entity.Session, error)
+	ResumeSession(ctx context.Context, sessionID string) (*entity.Session, error)
 	GetSessionResult(ctx context.Context, sessionID string) (string, error)
+	ListResultVersions(ctx context.Context, sessionID string) (*entity.ResultVersionsResponse, error)
+	GetResultVersion(ctx context.Context, sessionID string, number int) (*entity.ResultVersion, string, error)
+	GetResultFileInfo(ctx context.Context, sessionID string) (*entity.ResultFileInfo, error)
+	GetSessionBundle(ctx context.Context, sessionID string) (*entity.SessionBundle, error)
+	GetQuestionScript(ctx context.Context, sessionID string) ([]*entity.BundleIteration, error)
+	GetTranslatedSessionResult(ctx context.Context, sessionID string, language entity.ResultLanguage) (string, error)
+	ListResultSections(ctx context.Context, sessionID string) ([]*entity.ResultSection, error)
+	RegenerateResultSection(ctx context.Context, sessionID string, sectionIndex int, guidance string) (*entity.Session, error)
+	RegenerateSummaryWithFeedback(ctx context.Context, sessionID, feedback string) (*entity.Session, error)
+	GetActiveOperation(ctx context.Context, sessionID string) (*entity.SessionOperation, error)
+	ListComments(ctx context.Context, sessionID string, unresolvedOnly bool) ([]*entity.SessionComment, error)
+	ResolveComment(ctx context.Context, sessionID, commentID string) (*entity.SessionComment, error)
+	ListConflicts(ctx context.Context, sessionID string) ([]*entity.RequirementConflict, error)
+	ResolveConflict(ctx context.Context, sessionID, conflictID string, resolution entity.ConflictResolution) (*entity.RequirementConflict, error)
+	DecideReview(ctx context.Context, sessionID string, approver entity.ResultApprover, approve bool, comment string) (*entity.ResultReview, error)
+	EnsureResultReleasable(ctx context.Context, sessionID string) error
+	GetChangeLog(ctx context.Context, sessionID string) (*entity.SessionDelta, error)
+	DiffWithPreviousResult(ctx context.Context, sessionID string) (*entity.ResultDiff, error)
 	CancelSession(ctx context.Context, sessionID string) error
 	UpdateSessionStatus(ctx context.Context, sessionID string, status entity.SessionStatus) (*entity.Session, error)
+	// Support operator methods
+	RecordTakeoverAction(ctx context.Context, sessionID string, operatorID, userID int64, action entity.TakeoverAction, details map[string]any) error
+	GetQuotaUsage(ctx context.Context) (*entity.QuotaUsage, error)
 }
 
 // ProjectUsecase defines the subset of project operations needed by Telegram handlers
 type ProjectUsecase interface {
-	ListProjects(ctx context.Context, req *entity.ListProjectsRequest) ([]*entity.Project, error)
+	ListProjects(ctx context.Context, req *entity.ListProjectsRequest) ([]*entity.Project, int, error)
 	GetProject(ctx context.Context, projectID string) (*entity.Project, error)
+	GetProjectFreshness(ctx context.Context, projectID string) (*entity.ProjectFreshness, error)
 	CreateProject(ctx context.Context, req *entity.CreateProjectRequest) (*entity.Project, error)
 	CreateProjectFromContent(ctx context.Context, title, description, filename string, content []byte, contentType string) (*entity.Project, error)
 	AddFiles(ctx context.Context, req *entity.AddFilesRequest) ([]*entity.File, error)
 	AddFileFromContent(ctx context.Context, projectID, filename string, content []byte, contentType string) (*entity.File, error)
+	MarkProjectUsed(ctx context.Context, projectID string, telegramUserID int64) error
+	ListPinnedProjects(ctx context.Context, telegramUserID int64) ([]*entity.Project, error)
+	TogglePinnedProject(ctx context.Context, projectID string, telegramUserID int64) (bool, error)
+	UnpinProject(ctx context.Context, projectID string, telegramUserID int64) error
+	SetUserScheduleTimezone(ctx context.Context, telegramUserID int64, timezone string) error
+}
+
+// AccountLinkUsecase defines the links of other clients to Telegram users used by the bot
+type AccountLinkUsecase interface {
+	IssueCode(ctx context.Context, telegramUserID int64) (*entity.AccountLinkCode, error)
+}
+
+// UserUsecase defines the users Telegram users act as in the tenant of the bot
+type UserUsecase interface {
+	EnsureTelegramUser(ctx context.Context, telegramUserID int64, name string) (*entity.User, error)
+}
+
+// DemoUsecase defines the onboarding demo interview used by Telegram handlers
+type DemoUsecase interface {
+	GetQuestion(ctx context.Context, index int) (*entity.DemoQuestion, error)
+	GenerateSummary(ctx context.Context) (string, error)
 }
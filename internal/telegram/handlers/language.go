@@ -0,0 +1,74 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/telegram/render"
+	"github.com/futig/agent-backend/internal/telegram/state"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// warnLanguageSwitch asks which language the document is written in when the answer is in
+// another language than the session; the user is asked once per language
+func (h *QuestionsHandler) warnLanguageSwitch(ctx context.Context, msg *Message, sessionID string, stateData *state.StateData) {
+	detected, err := h.sessionUC.DetectLanguageSwitch(ctx, sessionID, msg.Text)
+	if err != nil {
+		// The warning is a hint, the answer is already saved
+		ctxzap.Warn(ctx, "failed to check answer language",
+			zap.Error(err),
+			zap.String("session_id", sessionID),
+		)
+		return
+	}
+	if detected == "" || stateData.LanguageSwitchWarned == string(detected) {
+		return
+	}
+
+	session, err := h.sessionUC.GetSession(ctx, sessionID)
+	if err != nil || session.Language == nil {
+		return
+	}
+
+	stateData.LanguageSwitchWarned = string(detected)
+	if err := h.stateManager.UpdateStateData(ctx, msg.UserID, stateData); err != nil {
+		ctxzap.Warn(ctx, "failed to save language switch warning",
+			zap.Error(err),
+			zap.Int64("user_id", msg.UserID),
+		)
+	}
+
+	h.sendMessage(msg.ChatID,
+		render.RenderLanguageSwitch(detected, *session.Language),
+		h.keyboard.DocumentLanguageKeyboard(*session.Language, detected),
+	)
+}
+
+// handleDocumentLanguage sets the language of the document explicitly, value is the language code
+func (h *CallbackHandler) handleDocumentLanguage(ctx context.Context, msg *Message, value string) error {
+	language := entity.ResultLanguage(value)
+	if !language.IsValid() {
+		ctxzap.Warn(ctx, "invalid document language", zap.String("lang", value))
+		h.sendMessage(msg.ChatID, "❌ Язык не поддерживается", nil)
+		return nil
+	}
+
+	telegramSession, err := h.stateManager.GetSession(ctx, msg.UserID)
+	if err != nil {
+		return fmt.Errorf("get user state: %w", err)
+	}
+
+	if _, err := h.sessionUC.SetSessionLanguage(ctx, telegramSession.SessionID, language); err != nil {
+		ctxzap.Error(ctx, "failed to set session language",
+			zap.Error(err),
+			zap.String("session_id", telegramSession.SessionID),
+		)
+		h.sendMessage(msg.ChatID, render.ClassifyError(ctx, err), nil)
+		return nil
+	}
+
+	h.sendMessage(msg.ChatID, render.RenderDocumentLanguageSet(language), nil)
+	return nil
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"github.com/futig/agent-backend/internal/telegram/render"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"go.uber.org/zap"
 )
@@ -19,14 +20,14 @@ func NewMessageSender(bot *tgbotapi.BotAPI, logger *zap.Logger) *MessageSender {
 	}
 }
 
-// Send sends a message to the specified chat
+// Send sends a message to the specified chat, split into several messages when the text is too long
 func (s *MessageSender) Send(chatID int64, text string, markup interface{}) error {
 	msg := tgbotapi.NewMessage(chatID, text)
 	if markup != nil {
 		msg.ReplyMarkup = markup
 	}
 
-	_, err := s.bot.Send(msg)
+	_, err := sendSplitMessage(s.bot, msg)
 	if err != nil {
 		s.logger.Error("failed to send message",
 			zap.Error(err),
@@ -37,3 +38,63 @@ func (s *MessageSender) Send(chatID int64, text string, markup interface{}) erro
 
 	return nil
 }
+
+// SendRendered sends a text produced by the render markdown pipeline with its parse mode
+func (s *MessageSender) SendRendered(chatID int64, text string, markup interface{}) error {
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ParseMode = render.MarkdownParseMode
+	if markup != nil {
+		msg.ReplyMarkup = markup
+	}
+
+	_, err := sendSplitMessage(s.bot, msg)
+	if err != nil {
+		s.logger.Error("failed to send rendered message",
+			zap.Error(err),
+			zap.Int64("chat_id", chatID),
+		)
+		return err
+	}
+
+	return nil
+}
+
+// splitMessageConfig splits a message over the Telegram text limit into several messages.
+// The reply markup is attached to the last part only, so buttons stay under the whole text
+func splitMessageConfig(msg tgbotapi.MessageConfig) []tgbotapi.MessageConfig {
+	parts := render.SplitMessage(msg.Text, msg.ParseMode, render.MaxMessageLength)
+	if len(parts) == 1 {
+		return []tgbotapi.MessageConfig{msg}
+	}
+
+	configs := make([]tgbotapi.MessageConfig, len(parts))
+	for i, part := range parts {
+		cfg := msg
+		cfg.Text = part
+		if i < len(parts)-1 {
+			cfg.ReplyMarkup = nil
+		}
+		if i > 0 {
+			cfg.ReplyToMessageID = 0
+		}
+		configs[i] = cfg
+	}
+
+	return configs
+}
+
+// sendSplitMessage sends a message split by splitMessageConfig and returns the sent parts in order.
+// Sending stops at the first failed part
+func sendSplitMessage(bot *tgbotapi.BotAPI, msg tgbotapi.MessageConfig) ([]tgbotapi.Message, error) {
+	configs := splitMessageConfig(msg)
+	sent := make([]tgbotapi.Message, 0, len(configs))
+	for _, config := range configs {
+		m, err := bot.Send(config)
+		if err != nil {
+			return sent, err
+		}
+		sent = append(sent, m)
+	}
+
+	return sent, nil
+}
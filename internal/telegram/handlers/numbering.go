@@ -0,0 +1,55 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/futig/agent-backend/internal/telegram/render"
+	"github.com/futig/agent-backend/internal/telegram/state"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// questionPosition returns the numbers shown for a question. Block numbers are always set;
+// global ones are added when the user chose global numbering and the progress header unless
+// the user hid it, so a failed lookup falls back to per-block numbering instead of failing the message
+func questionPosition(
+	ctx context.Context,
+	sessionUC SessionUsecase,
+	stateManager *state.Manager,
+	userID int64,
+	sessionID, questionID string,
+	number, total int,
+) render.QuestionPosition {
+	position := render.QuestionPosition{Number: number, Total: total}
+
+	numbering, err := stateManager.GetQuestionNumbering(ctx, userID)
+	if err != nil {
+		ctxzap.Warn(ctx, "failed to get question numbering, using default", zap.Error(err))
+	}
+	showHeader, err := stateManager.ShowProgressHeader(ctx, userID)
+	if err != nil {
+		ctxzap.Warn(ctx, "failed to get progress header preference, using default", zap.Error(err))
+	}
+	if numbering != state.NumberingGlobal && !showHeader {
+		return position
+	}
+
+	progress, err := sessionUC.GetQuestionProgress(ctx, sessionID, questionID)
+	if err != nil {
+		ctxzap.Warn(ctx, "failed to get question progress, using block numbering",
+			zap.Error(err),
+			zap.String("session_id", sessionID),
+			zap.String("question_id", questionID),
+		)
+		return position
+	}
+
+	if numbering == state.NumberingGlobal {
+		position.GlobalNumber = progress.Number
+		position.GlobalTotal = progress.Total
+	}
+	if showHeader {
+		position.Progress = progress
+	}
+	return position
+}
@@ -0,0 +1,76 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/futig/agent-backend/internal/pkg/formatter"
+	"github.com/futig/agent-backend/internal/telegram/render"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// previewPageSize keeps a preview page with its header well below the Telegram message limit
+const previewPageSize = 3500
+
+// handleResultPreview sends a page of the result as formatted messages before it is downloaded;
+// value is the page number, the "Дальше" button of a page moves on to the next one
+func (h *CallbackHandler) handleResultPreview(ctx context.Context, msg *Message, value string) error {
+	page, err := strconv.Atoi(value)
+	if err != nil || page < 0 {
+		return fmt.Errorf("invalid preview page: %s", value)
+	}
+
+	telegramSession, err := h.stateManager.GetSession(ctx, msg.UserID)
+	if err != nil {
+		return fmt.Errorf("get user state: %w", err)
+	}
+
+	result, err := h.sessionUC.GetSessionResult(ctx, telegramSession.SessionID)
+	if err != nil {
+		ctxzap.Error(ctx, "failed to get result",
+			zap.Error(err),
+			zap.String("session_id", telegramSession.SessionID),
+		)
+		h.sendMessage(msg.ChatID, render.ClassifyError(ctx, err), nil)
+		return nil
+	}
+
+	pages := formatter.PreviewPages(result, previewPageSize)
+	if len(pages) == 0 {
+		h.sendMessage(msg.ChatID, render.MsgPreviewEmpty, nil)
+		return nil
+	}
+	if page >= len(pages) {
+		return fmt.Errorf("invalid preview page: %s", value)
+	}
+
+	// The pressed "Дальше" button of the previous page is used up
+	if page > 0 {
+		edit := tgbotapi.NewEditMessageReplyMarkup(msg.ChatID, msg.MessageID, tgbotapi.InlineKeyboardMarkup{
+			InlineKeyboard: [][]tgbotapi.InlineKeyboardButton{},
+		})
+		if _, err := h.bot.Request(edit); err != nil {
+			ctxzap.Debug(ctx, "failed to remove preview button", zap.Error(err))
+		}
+	}
+
+	text := fmt.Sprintf(render.MsgPreviewPage, page+1, len(pages)) + "\n\n" + pages[page]
+	reply := tgbotapi.NewMessage(msg.ChatID, text)
+	reply.ParseMode = render.MarkdownParseMode
+	if page+1 < len(pages) {
+		reply.ReplyMarkup = h.keyboard.ResultPreviewKeyboard(page, len(pages))
+	}
+
+	if _, err := sendSplitMessage(h.bot, reply); err != nil {
+		ctxzap.Error(ctx, "failed to send result preview",
+			zap.Error(err),
+			zap.Int("page", page),
+		)
+		h.sendMessage(msg.ChatID, render.ClassifyError(ctx, err), nil)
+	}
+
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"time"
 
+	"github.com/futig/agent-backend/internal/telegram/render"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
 
@@ -56,7 +57,8 @@ func (pn *ProgressNotifier) Start(ctx context.Context) {
 				message := pn.messages[pn.index%len(pn.messages)]
 				pn.index++
 
-				msg := tgbotapi.NewMessage(pn.chatID, message)
+				msg := tgbotapi.NewMessage(pn.chatID, render.RenderMarkdown(message))
+				msg.ParseMode = render.MarkdownParseMode
 				pn.bot.Send(msg)
 
 			case <-pn.done:
@@ -188,6 +188,13 @@ func (h *ProjectDescriptionHandler) Handle(ctx context.Context, msg *Message) er
 		zap.String("title", project.Title),
 	)
 
+	if err := h.projectUC.MarkProjectUsed(ctx, project.ID, msg.UserID); err != nil {
+		ctxzap.Warn(ctx, "failed to mark project as used",
+			zap.Error(err),
+			zap.String("project_id", project.ID),
+		)
+	}
+
 	// Update session with new project ID
 	session.ProjectID = &project.ID
 	if _, err = h.sessionUC.UpdateSessionStatus(ctx, sessionID, entity.SessionStatusDone); err != nil {
@@ -219,6 +226,10 @@ func (h *ProjectDescriptionHandler) Handle(ctx context.Context, msg *Message) er
 
 	// Show success message with download buttons
 	successMsg := fmt.Sprintf("✅ Проект '%s' создан и требования сохранены!\n\nМожешь скачать их в удобном формате:", project.Title)
+	// The description may have been generated from the requirements
+	if project.Description != msg.Text {
+		successMsg = fmt.Sprintf("✅ Проект '%s' создан и требования сохранены!\n\n📝 Описание проекта: %s\n\nМожешь скачать их в удобном формате:", project.Title, project.Description)
+	}
 	h.sendMessage(msg.ChatID, successMsg, h.keyboard.ResultDownloadOnlyKeyboard(hasSkipped))
 	return nil
 }
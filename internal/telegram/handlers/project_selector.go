@@ -0,0 +1,228 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/pkg/formatter"
+	"github.com/futig/agent-backend/internal/telegram/keyboard"
+	"github.com/futig/agent-backend/internal/telegram/render"
+	"github.com/futig/agent-backend/internal/telegram/state"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// projectPageSize is the number of not pinned projects on a page of the project selector
+const projectPageSize = 10
+
+// loadProjectSelection returns the projects of a selector page: the projects pinned by the user,
+// which are shown on every page, followed by a page of the other projects, and the position of
+// the page. A page past the end, left after projects were deleted, shows the last page instead
+func loadProjectSelection(ctx context.Context, projectUC ProjectUsecase, userID int64, page int) ([]keyboard.Project, keyboard.Pagination, error) {
+	pinned, err := projectUC.ListPinnedProjects(ctx, userID)
+	if err != nil {
+		return nil, keyboard.Pagination{}, fmt.Errorf("list pinned projects: %w", err)
+	}
+
+	projects, total, err := listProjectPage(ctx, projectUC, userID, page)
+	if err != nil {
+		return nil, keyboard.Pagination{}, err
+	}
+	pagination := keyboard.NewPagination(page, total, projectPageSize)
+	if page >= pagination.Pages {
+		pagination.Page = pagination.Pages - 1
+		projects, _, err = listProjectPage(ctx, projectUC, userID, pagination.Page)
+		if err != nil {
+			return nil, keyboard.Pagination{}, err
+		}
+	}
+
+	kbProjects := make([]keyboard.Project, 0, len(pinned)+len(projects))
+	for _, p := range append(pinned, projects...) {
+		kbProjects = append(kbProjects, keyboard.Project{
+			ID:           p.ID,
+			Title:        p.Title,
+			SessionCount: p.SessionCount,
+			LastUsedAt:   p.LastUsedAt,
+			Stale:        p.Stale,
+			Pinned:       p.Pinned,
+		})
+	}
+
+	return kbProjects, pagination, nil
+}
+
+// listProjectPage returns a page of the not pinned projects and their total number
+func listProjectPage(ctx context.Context, projectUC ProjectUsecase, userID int64, page int) ([]*entity.Project, int, error) {
+	projects, total, err := projectUC.ListProjects(ctx, &entity.ListProjectsRequest{
+		Skip:           page * projectPageSize,
+		Limit:          projectPageSize,
+		TelegramUserID: userID,
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("list projects: %w", err)
+	}
+
+	return projects, total, nil
+}
+
+// handlePageJump asks for the number of the selector page to jump to, sent as the next text message
+func (h *CallbackHandler) handlePageJump(ctx context.Context, msg *Message) error {
+	stateData, err := h.stateManager.GetStateData(ctx, msg.UserID)
+	if err != nil {
+		return fmt.Errorf("get state data: %w", err)
+	}
+
+	_, pagination, err := loadProjectSelection(ctx, h.projectUC, msg.UserID, stateData.ProjectListPage)
+	if err != nil {
+		ctxzap.Error(ctx, "failed to list projects",
+			zap.Error(err),
+			zap.Int64("user_id", msg.UserID),
+		)
+		h.sendMessage(msg.ChatID, render.ClassifyError(ctx, err), nil)
+		return nil
+	}
+
+	stateData.AwaitingProjectPage = true
+	if err := h.stateManager.UpdateStateData(ctx, msg.UserID, stateData); err != nil {
+		return fmt.Errorf("update state data: %w", err)
+	}
+
+	h.sendMessage(msg.ChatID, fmt.Sprintf(render.MsgProjectPagePrompt, pagination.Pages), nil)
+	return nil
+}
+
+// ProjectSelectHandler handles SELECT_OR_CREATE_PROJECT state: projects are picked with buttons,
+// text messages only carry the page number requested with the page indicator
+type ProjectSelectHandler struct {
+	BaseHandler
+	stateManager *state.Manager
+	projectUC    ProjectUsecase
+	keyboard     *keyboard.Builder
+	logger       *zap.Logger
+}
+
+// NewProjectSelectHandler creates a new project selection handler
+func NewProjectSelectHandler(
+	bot *tgbotapi.BotAPI,
+	stateManager *state.Manager,
+	projectUC ProjectUsecase,
+	kb *keyboard.Builder,
+	logger *zap.Logger,
+) *ProjectSelectHandler {
+	return &ProjectSelectHandler{
+		BaseHandler: BaseHandler{
+			stateName:     HandlerStateSelectProject,
+			messageSender: NewMessageSender(bot, logger),
+		},
+		stateManager: stateManager,
+		projectUC:    projectUC,
+		keyboard:     kb,
+		logger:       logger,
+	}
+}
+
+// Handle shows the requested selector page
+func (h *ProjectSelectHandler) Handle(ctx context.Context, msg *Message) error {
+	stateData, err := h.stateManager.GetStateData(ctx, msg.UserID)
+	if err != nil {
+		return fmt.Errorf("get state data: %w", err)
+	}
+
+	if !stateData.AwaitingProjectPage {
+		h.sendMessage(msg.ChatID, render.MsgSelectProjectButtons, nil)
+		return nil
+	}
+
+	_, current, err := loadProjectSelection(ctx, h.projectUC, msg.UserID, stateData.ProjectListPage)
+	if err != nil {
+		return err
+	}
+
+	number, err := strconv.Atoi(strings.TrimSpace(msg.Text))
+	if err != nil || number < 1 || number > current.Pages {
+		h.sendMessage(msg.ChatID, fmt.Sprintf(render.ErrProjectPageOutOfRange, current.Pages), nil)
+		return nil
+	}
+
+	kbProjects, pagination, err := loadProjectSelection(ctx, h.projectUC, msg.UserID, number-1)
+	if err != nil {
+		return err
+	}
+
+	stateData.AwaitingProjectPage = false
+	stateData.ProjectListPage = pagination.Page
+	if err := h.stateManager.UpdateStateData(ctx, msg.UserID, stateData); err != nil {
+		return fmt.Errorf("update state data: %w", err)
+	}
+
+	h.sendMessage(msg.ChatID, render.MsgSelectProject, h.keyboard.ProjectSelectionKeyboardWithPagination(kbProjects, pagination))
+	return nil
+}
+
+// warnStaleProject tells the user when the session starts on project materials that were last indexed too long ago
+func (h *CallbackHandler) warnStaleProject(ctx context.Context, chatID int64, projectID string) {
+	freshness, err := h.projectUC.GetProjectFreshness(ctx, projectID)
+	if err != nil {
+		ctxzap.Warn(ctx, "failed to get project freshness",
+			zap.Error(err),
+			zap.String("project_id", projectID),
+		)
+		return
+	}
+	if !freshness.Stale {
+		return
+	}
+
+	now := time.Now().In(entity.LocationFromContext(ctx))
+	h.sendMessage(chatID, fmt.Sprintf(render.MsgProjectStale, formatter.MaterialsAge(*freshness.LastIndexedAt, now)), nil)
+}
+
+// handleProjectPin toggles a pinned project from the selector and redraws the selector in place
+func (h *CallbackHandler) handleProjectPin(ctx context.Context, msg *Message, projectID string) error {
+	if _, err := h.projectUC.TogglePinnedProject(ctx, projectID, msg.UserID); err != nil {
+		if errors.Is(err, entity.ErrPinLimitReached) {
+			h.sendMessage(msg.ChatID, fmt.Sprintf(render.ErrPinLimitReached, entity.MaxPinnedProjects), nil)
+			return nil
+		}
+		ctxzap.Error(ctx, "failed to toggle pinned project",
+			zap.Error(err),
+			zap.String("project_id", projectID),
+		)
+		h.sendMessage(msg.ChatID, render.ClassifyError(ctx, err), nil)
+		return nil
+	}
+
+	stateData, err := h.stateManager.GetStateData(ctx, msg.UserID)
+	if err != nil {
+		return fmt.Errorf("get state data: %w", err)
+	}
+
+	kbProjects, pagination, err := loadProjectSelection(ctx, h.projectUC, msg.UserID, stateData.ProjectListPage)
+	if err != nil {
+		ctxzap.Error(ctx, "failed to list projects",
+			zap.Error(err),
+			zap.Int64("user_id", msg.UserID),
+		)
+		h.sendMessage(msg.ChatID, render.ClassifyError(ctx, err), nil)
+		return nil
+	}
+
+	h.replaceMessage(ctx, msg, render.MsgSelectProject, h.keyboard.ProjectSelectionKeyboardWithPagination(kbProjects, pagination))
+	return nil
+}
+
+// replaceMessage edits the message with the pressed button; a message that can no longer be edited is sent anew
+func (h *CallbackHandler) replaceMessage(ctx context.Context, msg *Message, text string, kb tgbotapi.InlineKeyboardMarkup) {
+	edit := tgbotapi.NewEditMessageTextAndMarkup(msg.ChatID, msg.MessageID, text, kb)
+	if _, err := h.bot.Send(edit); err != nil {
+		ctxzap.Debug(ctx, "failed to edit message, sending new message", zap.Error(err))
+		h.sendMessage(msg.ChatID, text, kb)
+	}
+}
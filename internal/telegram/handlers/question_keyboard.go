@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/telegram/keyboard"
+	"github.com/futig/agent-backend/internal/telegram/state"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// questionKeyboard creates the navigation keyboard of a question with the buttons of the session
+// type of the user; a failed lookup uses the buttons of every session type instead of failing the message
+func questionKeyboard(
+	ctx context.Context,
+	stateManager *state.Manager,
+	kb *keyboard.Builder,
+	userID int64,
+	questionID string,
+	answerType entity.QuestionAnswerType,
+	hasPrevious bool,
+) tgbotapi.InlineKeyboardMarkup {
+	var sessionType entity.SessionType
+	if !kb.QuestionButtons().AllEnabled() {
+		session, err := stateManager.GetSessionWithSession(ctx, userID)
+		if err != nil {
+			ctxzap.Warn(ctx, "failed to get session type, using default question buttons",
+				zap.Error(err),
+				zap.Int64("user_id", userID),
+			)
+		} else {
+			sessionType = entity.SessionType(session.SessionType)
+		}
+	}
+	return kb.QuestionNavigationKeyboard(sessionType, questionID, answerType, hasPrevious)
+}
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/pkg/formatter"
+	"github.com/futig/agent-backend/internal/telegram/render"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// handleQuestionScriptExport sends the generated blocks and questions as a PDF document for analysts
+// who conduct the interview live; markdown is sent when the PDF cannot be rendered
+func (h *CallbackHandler) handleQuestionScriptExport(ctx context.Context, msg *Message) error {
+	telegramSession, err := h.stateManager.GetSession(ctx, msg.UserID)
+	if err != nil {
+		return fmt.Errorf("get user state: %w", err)
+	}
+	sessionID := telegramSession.SessionID
+
+	script, err := h.sessionUC.GetQuestionScript(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, entity.ErrQuestionsNotReady) {
+			h.sendMessage(msg.ChatID, render.MsgQuestionScriptNotReady, nil)
+			return nil
+		}
+		ctxzap.Error(ctx, "failed to get question script",
+			zap.Error(err),
+			zap.String("session_id", sessionID),
+		)
+		h.HandleError(ctx, msg.ChatID, err)
+		return nil
+	}
+
+	fileInfo, err := h.sessionUC.GetResultFileInfo(ctx, sessionID)
+	if err != nil {
+		ctxzap.Error(ctx, "failed to get result file info",
+			zap.Error(err),
+			zap.String("session_id", sessionID),
+		)
+		h.HandleError(ctx, msg.ChatID, err)
+		return nil
+	}
+
+	text := formatter.FormatQuestionScript(script)
+	var data []byte
+	var ext string
+	var formatErr error
+	for _, format := range []entity.ResultFormat{entity.FormatPDF, entity.FormatMarkdown} {
+		fmtr, err := formatter.NewQuestionScriptFormatter(format, fileInfo.Date, fileInfo.Theme)
+		if err != nil {
+			return fmt.Errorf("create question script formatter: %w", err)
+		}
+		if data, formatErr = fmtr.Format(text); formatErr == nil {
+			ext = fmtr.FileExtension()
+			break
+		}
+		ctxzap.Warn(ctx, "failed to format question script",
+			zap.Error(formatErr),
+			zap.String("format", string(format)),
+		)
+	}
+	if formatErr != nil {
+		h.HandleError(ctx, msg.ChatID, formatErr)
+		return nil
+	}
+
+	doc := tgbotapi.NewDocument(msg.ChatID, tgbotapi.FileBytes{
+		Name:  formatter.ASCIIFileName(formatter.QuestionScriptFileName(fileInfo, ext)),
+		Bytes: data,
+	})
+	doc.Caption = render.MsgQuestionScript
+	if _, err := h.bot.Send(doc); err != nil {
+		ctxzap.Error(ctx, "failed to send question script",
+			zap.Error(err),
+			zap.String("session_id", sessionID),
+		)
+	}
+
+	return nil
+}
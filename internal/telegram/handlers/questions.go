@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/futig/agent-backend/internal/entity"
@@ -64,13 +65,42 @@ func (h *QuestionsHandler) Handle(ctx context.Context, msg *Message) error {
 		return fmt.Errorf("get state data: %w", err)
 	}
 
+	// A quick answer is never a search query even when the search prompt is still open
+	if msg.AnsweredQuestionID == "" &&
+		handlePendingSearch(ctx, msg, sessionID, stateData, h.sessionUC, h.stateManager, h.sendMessage) {
+		return nil
+	}
+
+	// An answer picked in the answers review is replaced, the current question stays open
+	if msg.AnsweredQuestionID == "" && h.handlePendingEdit(ctx, msg, sessionID, stateData) {
+		return nil
+	}
+
 	currentQuestionID := stateData.CurrentQuestionID
 	if currentQuestionID == "" {
 		h.sendMessage(msg.ChatID, "❌ Текущий вопрос не найден. Нажмите /start", nil)
 		return nil
 	}
 
+	// A quick answer button or a Telegram reply to an earlier question message answers that question
+	// instead of the current one
+	answeredID := msg.AnsweredQuestionID
+	if answeredID == "" {
+		answeredID = repliedQuestionID(msg, stateData)
+	}
+	if answeredID != "" && answeredID != currentQuestionID {
+		if h.isSessionQuestion(ctx, sessionID, answeredID) {
+			return h.handleReplyAnswer(ctx, msg, sessionID, answeredID)
+		}
+		// The buttons of a question from another session must not answer the current question
+		if msg.AnsweredQuestionID != "" {
+			h.sendMessage(msg.ChatID, render.MsgQuickAnswerUnavailable, nil)
+			return nil
+		}
+	}
+
 	var nextIteration *entity.IterationWithQuestions
+	acknowledgment := "✅ Принял ответ"
 
 	// Handle voice message
 	if msg.Voice != nil {
@@ -80,7 +110,7 @@ func (h *QuestionsHandler) Handle(ctx context.Context, msg *Message) error {
 		)
 
 		// Download voice file
-		audioData, err := downloadVoiceFile(ctx, h.bot, msg.Voice.FileID)
+		ctx, audioData, err := downloadVoiceFile(ctx, h.bot, msg.Voice.FileID)
 		if err != nil {
 			ctxzap.Error(ctx, "failed to download voice file",
 				zap.Error(err),
@@ -99,11 +129,17 @@ func (h *QuestionsHandler) Handle(ctx context.Context, msg *Message) error {
 
 		// Submit audio answer
 		nextIteration, err = h.sessionUC.SubmitAudioAnswer(ctx, sessionID, currentQuestionID, audioData)
-		if err != nil {
+		if errors.Is(err, entity.ErrASRUnavailable) {
+			nextIteration, err = h.deferVoiceAnswer(ctx, msg, sessionID, currentQuestionID, stateData, audioData)
+			if err != nil || nextIteration == nil {
+				return nil
+			}
+			acknowledgment = render.MsgVoiceAnswerDeferred
+		} else if err != nil {
 			ctxzap.Error(ctx, "failed to submit audio answer",
 				zap.Error(err),
 			)
-			h.sendMessage(msg.ChatID, render.ErrTranscription, nil)
+			h.sendMessage(msg.ChatID, voiceSubmitErrorMessage(err), nil)
 			return nil
 		}
 	} else if msg.Text != "" {
@@ -115,7 +151,7 @@ func (h *QuestionsHandler) Handle(ctx context.Context, msg *Message) error {
 
 		nextIteration, err = h.sessionUC.SubmitTextAnswer(ctx, sessionID, currentQuestionID, msg.Text)
 		if err != nil {
-			h.HandleError(ctx, msg.ChatID, err)
+			h.HandleSubmitError(ctx, h.keyboard, msg, err)
 			return nil
 		}
 	} else {
@@ -124,7 +160,13 @@ func (h *QuestionsHandler) Handle(ctx context.Context, msg *Message) error {
 	}
 
 	// Send acknowledgment (critical - must be delivered)
-	sendCriticalMessage(h.bot, msg.ChatID, "✅ Принял ответ", nil, h.logger)
+	sendCriticalMessage(h.bot, msg.ChatID, acknowledgment, nil, h.logger)
+
+	if msg.Text != "" {
+		h.warnLanguageSwitch(ctx, msg, sessionID, stateData)
+	}
+
+	notifyTimeBudget(ctx, msg.ChatID, sessionID, h.sessionUC, h.keyboard, h.sendMessage)
 
 	// Defensive check: if AnsweringSkipped is true but TotalSkippedQuestions is 0,
 	// we're not really in the skipped flow, so reset the flag
@@ -142,6 +184,42 @@ func (h *QuestionsHandler) Handle(ctx context.Context, msg *Message) error {
 		}
 	}
 
+	// Deferred questions are asked after the last block, move on to the next one
+	if stateData.AnsweringDeferred {
+		// The user went back to an earlier question: ask the interrupted deferred question again
+		if len(stateData.NextQuestionIDs) > 0 {
+			stateData.NextQuestionIDs = []string{}
+			stateData.CurrentDeferredQuestionIndex--
+			if err := h.stateManager.UpdateStateData(ctx, msg.UserID, stateData); err != nil {
+				ctxzap.Error(ctx, "failed to update state data",
+					zap.Error(err),
+					zap.Int64("user_id", msg.UserID),
+				)
+			}
+		}
+
+		if err := finishInterview(
+			ctx,
+			msg,
+			sessionID,
+			h.sessionUC,
+			h.projectUC,
+			h.stateManager,
+			h.keyboard,
+			h.bot,
+			h.logger,
+			h.sendMessage,
+		); err != nil {
+			ctxzap.Error(ctx, "failed to finish interview",
+				zap.Error(err),
+				zap.String("session_id", sessionID),
+			)
+			h.sendMessage(msg.ChatID, render.ClassifyError(ctx, err), nil)
+		}
+
+		return nil
+	}
+
 	// If we are in "answer skipped" flow, move to the next skipped/unanswered question
 	if stateData.AnsweringSkipped {
 		// Clear forward navigation - not applicable when answering skipped questions
@@ -172,7 +250,7 @@ func (h *QuestionsHandler) Handle(ctx context.Context, msg *Message) error {
 				zap.Error(err),
 				zap.String("session_id", sessionID),
 			)
-			h.sendMessage(msg.ChatID, render.ClassifyError(err), nil)
+			h.sendMessage(msg.ChatID, render.ClassifyError(ctx, err), nil)
 		}
 
 		return nil
@@ -218,8 +296,7 @@ func (h *QuestionsHandler) Handle(ctx context.Context, msg *Message) error {
 
 				questionText := render.RenderQuestion(
 					title,
-					questionIndex,
-					len(iteration.Questions),
+					questionPosition(ctx, h.sessionUC, h.stateManager, msg.UserID, sessionID, nextQuestionID, questionIndex, len(iteration.Questions)),
 					question.Question,
 				)
 
@@ -240,7 +317,7 @@ func (h *QuestionsHandler) Handle(ctx context.Context, msg *Message) error {
 				}
 
 				hasPrevious := stateData.PreviousQuestionID != ""
-				h.sendMessage(msg.ChatID, questionText, h.keyboard.QuestionNavigationKeyboard(nextQuestionID, hasPrevious))
+				sendQuestionMessage(ctx, h.bot, h.stateManager, msg, stateData, questionText, nextQuestionID, questionKeyboard(ctx, h.stateManager, h.keyboard, msg.UserID, nextQuestionID, question.AnswerType, hasPrevious))
 
 				return nil
 			}
@@ -253,9 +330,7 @@ func (h *QuestionsHandler) Handle(ctx context.Context, msg *Message) error {
 			zap.String("session_id", sessionID),
 		)
 
-		h.sendMessage(msg.ChatID, render.MsgValidating, nil)
-
-		if err := handleValidationAndSummaryCommon(
+		if err := finishInterview(
 			ctx,
 			msg,
 			sessionID,
@@ -271,7 +346,7 @@ func (h *QuestionsHandler) Handle(ctx context.Context, msg *Message) error {
 				zap.Error(err),
 				zap.String("session_id", sessionID),
 			)
-			h.sendMessage(msg.ChatID, render.ClassifyError(err), nil)
+			h.sendMessage(msg.ChatID, render.ClassifyError(ctx, err), nil)
 		}
 
 		return nil
@@ -297,10 +372,7 @@ func (h *QuestionsHandler) Handle(ctx context.Context, msg *Message) error {
 			zap.String("iteration_id", nextIteration.IterationID),
 		)
 
-		// Inform user that validation may take some time
-		h.sendMessage(msg.ChatID, render.MsgValidating, nil)
-
-		if err := handleValidationAndSummaryCommon(
+		if err := finishInterview(
 			ctx,
 			msg,
 			sessionID,
@@ -316,7 +388,7 @@ func (h *QuestionsHandler) Handle(ctx context.Context, msg *Message) error {
 				zap.Error(err),
 				zap.String("session_id", sessionID),
 			)
-			h.sendMessage(msg.ChatID, render.ClassifyError(err), nil)
+			h.sendMessage(msg.ChatID, render.ClassifyError(ctx, err), nil)
 		}
 
 		return nil
@@ -328,8 +400,7 @@ func (h *QuestionsHandler) Handle(ctx context.Context, msg *Message) error {
 
 	questionText := render.RenderQuestion(
 		title,
-		questionIndex,
-		len(nextIteration.Questions),
+		questionPosition(ctx, h.sessionUC, h.stateManager, msg.UserID, sessionID, nextQuestion.ID, questionIndex, len(nextIteration.Questions)),
 		nextQuestion.Question,
 	)
 
@@ -349,7 +420,135 @@ func (h *QuestionsHandler) Handle(ctx context.Context, msg *Message) error {
 
 	// Check if there is a previous question to show back button
 	hasPrevious := stateData.PreviousQuestionID != ""
-	h.sendMessage(msg.ChatID, questionText, h.keyboard.QuestionNavigationKeyboard(nextQuestion.ID, hasPrevious))
+	sendQuestionMessage(ctx, h.bot, h.stateManager, msg, stateData, questionText, nextQuestion.ID, questionKeyboard(ctx, h.stateManager, h.keyboard, msg.UserID, nextQuestion.ID, nextQuestion.AnswerType, hasPrevious))
+
+	return nil
+}
+
+// isSessionQuestion checks that the question belongs to the given session
+func (h *QuestionsHandler) isSessionQuestion(ctx context.Context, sessionID, questionID string) bool {
+	question, err := h.sessionUC.GetQuestionByID(ctx, questionID)
+	if err != nil {
+		ctxzap.Warn(ctx, "failed to get replied question",
+			zap.Error(err),
+			zap.String("question_id", questionID),
+		)
+		return false
+	}
+
+	iteration, err := h.sessionUC.GetIterationByID(ctx, question.IterationID)
+	if err != nil {
+		ctxzap.Warn(ctx, "failed to get replied question iteration",
+			zap.Error(err),
+			zap.String("iteration_id", question.IterationID),
+		)
+		return false
+	}
+
+	return iteration.SessionID == sessionID
+}
+
+// handleReplyAnswer submits an answer to the question the user replied to, keeping the current question unchanged
+func (h *QuestionsHandler) handleReplyAnswer(ctx context.Context, msg *Message, sessionID, questionID string) error {
+	ctxzap.Info(ctx, "routing reply answer to replied question",
+		zap.Int64("user_id", msg.UserID),
+		zap.String("question_id", questionID),
+		zap.Int("reply_to_message_id", msg.ReplyToMessageID),
+	)
 
+	if msg.Voice != nil {
+		ctx, audioData, err := downloadVoiceFile(ctx, h.bot, msg.Voice.FileID)
+		if err != nil {
+			ctxzap.Error(ctx, "failed to download voice file",
+				zap.Error(err),
+			)
+			h.sendMessage(msg.ChatID, render.ErrTranscription, nil)
+			return nil
+		}
+
+		h.sendMessage(msg.ChatID, "🎤 Расшифровываю...", nil)
+
+		progress := NewProgressNotifier(h.bot, msg.ChatID)
+		progress.Start(ctx)
+		defer progress.Stop()
+
+		_, err = h.sessionUC.SubmitAudioAnswer(ctx, sessionID, questionID, audioData)
+		if errors.Is(err, entity.ErrASRUnavailable) {
+			h.queueVoiceAnswer(ctx, msg, sessionID, questionID, audioData)
+			return nil
+		}
+		if err != nil {
+			ctxzap.Error(ctx, "failed to submit audio reply answer",
+				zap.Error(err),
+			)
+			h.sendMessage(msg.ChatID, voiceSubmitErrorMessage(err), nil)
+			return nil
+		}
+	} else if msg.Text != "" {
+		if _, err := h.sessionUC.SubmitTextAnswer(ctx, sessionID, questionID, msg.Text); err != nil {
+			h.HandleSubmitError(ctx, h.keyboard, msg, err)
+			return nil
+		}
+	} else {
+		h.sendMessage(msg.ChatID, "❌ Пожалуйста, отправьте текст или голосовое сообщение", nil)
+		return nil
+	}
+
+	sendCriticalMessage(h.bot, msg.ChatID, render.MsgReplyAnswerAccepted, nil, h.logger)
 	return nil
 }
+
+// deferVoiceAnswer keeps a voice answer speech recognition is unavailable for until it recovers.
+// In the regular flow the question is deferred so the interview goes on; a nil iteration means
+// the user stays on the question and has already been told why.
+func (h *QuestionsHandler) deferVoiceAnswer(
+	ctx context.Context,
+	msg *Message,
+	sessionID, questionID string,
+	stateData *state.StateData,
+	audioData []byte,
+) (*entity.IterationWithQuestions, error) {
+	if stateData.AnsweringSkipped || stateData.AnsweringDeferred {
+		h.queueVoiceAnswer(ctx, msg, sessionID, questionID, audioData)
+		return nil, nil
+	}
+
+	queued, err := h.sessionUC.QueueVoiceAnswer(ctx, sessionID, questionID, msg.UserID, audioData)
+	if err != nil {
+		ctxzap.Error(ctx, "failed to queue voice answer",
+			zap.Error(err),
+		)
+	}
+	if !queued {
+		h.sendMessage(msg.ChatID, render.ErrVoiceUnavailable, nil)
+		return nil, nil
+	}
+
+	nextIteration, err := h.sessionUC.DeferQuestion(ctx, sessionID, questionID)
+	if err != nil {
+		ctxzap.Error(ctx, "failed to defer question with queued voice answer",
+			zap.Error(err),
+			zap.String("question_id", questionID),
+		)
+		h.sendMessage(msg.ChatID, render.MsgVoiceAnswerQueued, nil)
+		return nil, err
+	}
+
+	return nextIteration, nil
+}
+
+// queueVoiceAnswer keeps a voice answer until speech recognition recovers and asks for a text answer meanwhile
+func (h *QuestionsHandler) queueVoiceAnswer(ctx context.Context, msg *Message, sessionID, questionID string, audioData []byte) {
+	queued, err := h.sessionUC.QueueVoiceAnswer(ctx, sessionID, questionID, msg.UserID, audioData)
+	if err != nil {
+		ctxzap.Error(ctx, "failed to queue voice answer",
+			zap.Error(err),
+		)
+	}
+
+	if queued {
+		h.sendMessage(msg.ChatID, render.MsgVoiceAnswerQueued, nil)
+		return
+	}
+	h.sendMessage(msg.ChatID, render.ErrVoiceUnavailable, nil)
+}
@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/telegram/render"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// notifyQuota warns the user about the quotas of kind past the warn threshold, right after
+// the usage that brought them there; usage is blocked once a quota is used up
+func notifyQuota(
+	ctx context.Context,
+	chatID int64,
+	kind entity.QuotaKind,
+	sessionUC SessionUsecase,
+	send func(chatID int64, text string, replyMarkup interface{}),
+) {
+	usage, err := sessionUC.GetQuotaUsage(ctx)
+	if err != nil {
+		ctxzap.Warn(ctx, "failed to get quota usage", zap.Error(err))
+		return
+	}
+
+	for _, quota := range usage.Quotas {
+		if quota.Kind == kind && quota.Warning {
+			send(chatID, RenderQuotaWarning(quota), nil)
+		}
+	}
+}
+
+// RenderQuotaUsage formats the usage of the quotas of a user for /quota; reset times are shown
+// in the timezone of the user
+func RenderQuotaUsage(ctx context.Context, quotas []entity.QuotaStatus) string {
+	if len(quotas) == 0 {
+		return render.MsgQuotaUnlimited
+	}
+
+	lines := []string{render.MsgQuotaHeader, ""}
+	for _, quota := range quotas {
+		kind, scope := quotaNames(quota)
+		line := fmt.Sprintf(render.MsgQuotaLine, kind, scope, quota.Used, quota.Limit,
+			entity.LocalTime(ctx, quota.ResetsAt).Format(render.QuotaResetLayout))
+		if quota.Exceeded {
+			line += render.MsgQuotaExhausted
+		}
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// RenderQuotaWarning formats the warning about a quota past the warn threshold
+func RenderQuotaWarning(quota entity.QuotaStatus) string {
+	kind, scope := quotaNames(quota)
+	return fmt.Sprintf(render.MsgQuotaWarning, kind, scope, quota.Used, quota.Limit)
+}
+
+func quotaNames(quota entity.QuotaStatus) (string, string) {
+	kind, scope := render.MsgQuotaSessions, render.MsgQuotaUser
+	if quota.Kind == entity.QuotaKindGenerations {
+		kind = render.MsgQuotaGenerations
+	}
+	if quota.Scope == entity.QuotaScopeTenant {
+		scope = render.MsgQuotaTenant
+	}
+	return kind, scope
+}
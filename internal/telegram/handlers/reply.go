@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"context"
+	"sort"
+
+	"github.com/futig/agent-backend/internal/telegram/state"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// maxQuestionMessages limits how many message_id -> question_id mappings are kept in state
+const maxQuestionMessages = 100
+
+// sendQuestionMessage sends a question and remembers its message ID so that
+// Telegram replies to it can be routed to that question later
+func sendQuestionMessage(
+	ctx context.Context,
+	bot *tgbotapi.BotAPI,
+	stateManager *state.Manager,
+	msg *Message,
+	stateData *state.StateData,
+	text string,
+	questionID string,
+	markup interface{},
+) {
+	out := tgbotapi.NewMessage(msg.ChatID, text)
+	if markup != nil {
+		out.ReplyMarkup = markup
+	}
+
+	sent, err := sendSplitMessage(bot, out)
+	if err != nil {
+		ctxzap.Error(ctx, "failed to send question message",
+			zap.Error(err),
+			zap.Int64("chat_id", msg.ChatID),
+			zap.String("question_id", questionID),
+		)
+		return
+	}
+
+	// Every part of a split question routes replies to the same question
+	for _, m := range sent {
+		rememberQuestionMessage(stateData, m.MessageID, questionID)
+	}
+
+	if err := stateManager.UpdateStateData(ctx, msg.UserID, stateData); err != nil {
+		ctxzap.Warn(ctx, "failed to save question message mapping",
+			zap.Error(err),
+			zap.Int64("user_id", msg.UserID),
+			zap.Int("message_id", sent[len(sent)-1].MessageID),
+		)
+	}
+}
+
+// rememberQuestionMessage stores message_id -> question_id, evicting the oldest messages over the limit
+func rememberQuestionMessage(stateData *state.StateData, messageID int, questionID string) {
+	if stateData.QuestionMessages == nil {
+		stateData.QuestionMessages = make(map[int]string)
+	}
+	stateData.QuestionMessages[messageID] = questionID
+
+	if len(stateData.QuestionMessages) <= maxQuestionMessages {
+		return
+	}
+
+	ids := make([]int, 0, len(stateData.QuestionMessages))
+	for id := range stateData.QuestionMessages {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	for _, id := range ids[:len(ids)-maxQuestionMessages] {
+		delete(stateData.QuestionMessages, id)
+	}
+}
+
+// repliedQuestionID returns the question ID the message replies to, or empty string
+func repliedQuestionID(msg *Message, stateData *state.StateData) string {
+	if msg.ReplyToMessageID == 0 || stateData.QuestionMessages == nil {
+		return ""
+	}
+
+	return stateData.QuestionMessages[msg.ReplyToMessageID]
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/pkg/formatter"
+	"github.com/futig/agent-backend/internal/telegram/render"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// handleResultDiff compares the result with the previous requirements of the project: the counts
+// of changed sections go to the chat, the sections that differ are sent as a markdown document
+func (h *CallbackHandler) handleResultDiff(ctx context.Context, msg *Message) error {
+	telegramSession, err := h.stateManager.GetSession(ctx, msg.UserID)
+	if err != nil {
+		return fmt.Errorf("get user state: %w", err)
+	}
+	sessionID := telegramSession.SessionID
+
+	diff, err := h.sessionUC.DiffWithPreviousResult(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, entity.ErrNoBaseline) {
+			h.sendMessage(msg.ChatID, render.MsgNoPreviousVersion, nil)
+			return nil
+		}
+		ctxzap.Error(ctx, "failed to compare result with previous version",
+			zap.Error(err),
+			zap.String("session_id", sessionID),
+		)
+		h.HandleError(ctx, msg.ChatID, err)
+		return nil
+	}
+
+	summary := diff.Summary
+	if summary.Added+summary.Removed+summary.Changed == 0 {
+		h.sendMessage(msg.ChatID, render.MsgResultDiffSame, nil)
+		return nil
+	}
+
+	h.sendMessage(msg.ChatID, fmt.Sprintf(render.MsgResultDiff, summary.Added, summary.Removed, summary.Changed, summary.Unchanged), nil)
+
+	fileInfo, err := h.sessionUC.GetResultFileInfo(ctx, sessionID)
+	if err != nil {
+		ctxzap.Error(ctx, "failed to get result file info",
+			zap.Error(err),
+			zap.String("session_id", sessionID),
+		)
+		h.HandleError(ctx, msg.ChatID, err)
+		return nil
+	}
+
+	filename := formatter.FileName(fileInfo, "diff", ".md")
+	doc := tgbotapi.NewDocument(msg.ChatID, tgbotapi.FileBytes{
+		Name:  formatter.ASCIIFileName(filename),
+		Bytes: []byte(diff.Markdown),
+	})
+	if _, err := h.bot.Send(doc); err != nil {
+		ctxzap.Error(ctx, "failed to send result diff",
+			zap.Error(err),
+			zap.String("session_id", sessionID),
+		)
+	}
+
+	return nil
+}
@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/telegram/keyboard"
+	"github.com/futig/agent-backend/internal/telegram/render"
+	"github.com/futig/agent-backend/internal/telegram/state"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// ResultFeedbackHandler handles ASK_RESULT_FEEDBACK state
+type ResultFeedbackHandler struct {
+	BaseHandler
+	bot          *tgbotapi.BotAPI
+	stateManager *state.Manager
+	sessionUC    SessionUsecase
+	keyboard     *keyboard.Builder
+	logger       *zap.Logger
+}
+
+// NewResultFeedbackHandler creates a new result feedback handler
+func NewResultFeedbackHandler(
+	bot *tgbotapi.BotAPI,
+	stateManager *state.Manager,
+	sessionUC SessionUsecase,
+	kb *keyboard.Builder,
+	logger *zap.Logger,
+) *ResultFeedbackHandler {
+	return &ResultFeedbackHandler{
+		BaseHandler: BaseHandler{
+			stateName:     HandlerStateAskResultFeedback,
+			messageSender: NewMessageSender(bot, logger),
+		},
+		bot:          bot,
+		stateManager: stateManager,
+		sessionUC:    sessionUC,
+		keyboard:     kb,
+		logger:       logger,
+	}
+}
+
+// Handle regenerates the whole result using the message text as feedback
+func (h *ResultFeedbackHandler) Handle(ctx context.Context, msg *Message) error {
+	if msg.Text == "" {
+		h.sendMessage(msg.ChatID, render.MsgFeedbackTextOnly, h.keyboard.FeedbackKeyboard())
+		return nil
+	}
+
+	telegramSession, err := h.stateManager.GetSession(ctx, msg.UserID)
+	if err != nil {
+		return fmt.Errorf("get telegram session: %w", err)
+	}
+	sessionID := telegramSession.SessionID
+
+	h.sendMessage(msg.ChatID, render.MsgFeedbackRegenerating, nil)
+
+	typing := NewTypingNotifier(h.bot, msg.ChatID, h.logger)
+	typing.Start(ctx)
+	defer typing.Stop()
+
+	if _, err := h.sessionUC.RegenerateSummaryWithFeedback(ctx, sessionID, msg.Text); err != nil {
+		ctxzap.Error(ctx, "failed to regenerate result with feedback",
+			zap.Error(err),
+			zap.String("session_id", sessionID),
+		)
+
+		// Let the user rephrase blocked feedback
+		if errors.Is(err, entity.ErrContentBlocked) {
+			h.sendMessage(msg.ChatID, render.ErrContentBlocked, h.keyboard.FeedbackKeyboard())
+			return nil
+		}
+
+		finishResultFeedback(ctx, sessionID, h.sessionUC)
+		h.sendMessage(msg.ChatID, render.ClassifyError(ctx, err), nil)
+		return nil
+	}
+
+	typing.Stop()
+	finishResultFeedback(ctx, sessionID, h.sessionUC)
+
+	hasSkipped, err := h.sessionUC.HasSkippedQuestions(ctx, sessionID)
+	if err != nil {
+		ctxzap.Error(ctx, "failed to check skipped questions",
+			zap.Error(err),
+			zap.String("session_id", sessionID),
+		)
+	}
+
+	h.sendMessage(msg.ChatID, render.MsgFeedbackRegenerated, h.keyboard.ResultDownloadKeyboard(hasSkipped))
+	return nil
+}
+
+// handleResultFeedback asks what to improve in the whole result
+func (h *CallbackHandler) handleResultFeedback(ctx context.Context, msg *Message) error {
+	telegramSession, err := h.stateManager.GetSession(ctx, msg.UserID)
+	if err != nil {
+		return fmt.Errorf("get user state: %w", err)
+	}
+
+	session, err := h.sessionUC.GetSession(ctx, telegramSession.SessionID)
+	if err != nil {
+		h.HandleError(ctx, msg.ChatID, err)
+		return nil
+	}
+
+	if session.Status != entity.SessionStatusDone && session.Status != entity.SessionStatusAskResultFeedback {
+		h.sendMessage(msg.ChatID, render.ErrInvalidState, nil)
+		return nil
+	}
+
+	if _, err := h.sessionUC.UpdateSessionStatus(ctx, telegramSession.SessionID, entity.SessionStatusAskResultFeedback); err != nil {
+		ctxzap.Error(ctx, "failed to update session status",
+			zap.Error(err),
+			zap.String("session_id", telegramSession.SessionID),
+		)
+		h.HandleError(ctx, msg.ChatID, err)
+		return nil
+	}
+
+	h.sendMessage(msg.ChatID, render.MsgFeedbackPrompt, h.keyboard.FeedbackKeyboard())
+	return nil
+}
+
+// handleResultFeedbackCancel returns the session to DONE without changes
+func (h *CallbackHandler) handleResultFeedbackCancel(ctx context.Context, msg *Message) error {
+	telegramSession, err := h.stateManager.GetSession(ctx, msg.UserID)
+	if err != nil {
+		return fmt.Errorf("get user state: %w", err)
+	}
+
+	finishResultFeedback(ctx, telegramSession.SessionID, h.sessionUC)
+
+	h.sendMessage(msg.ChatID, render.MsgFeedbackCancel, nil)
+	return nil
+}
+
+// finishResultFeedback returns the session waiting for feedback to DONE
+func finishResultFeedback(ctx context.Context, sessionID string, sessionUC SessionUsecase) {
+	session, err := sessionUC.GetSession(ctx, sessionID)
+	if err != nil || session.Status != entity.SessionStatusAskResultFeedback {
+		return
+	}
+
+	if _, err := sessionUC.UpdateSessionStatus(ctx, sessionID, entity.SessionStatusDone); err != nil {
+		ctxzap.Warn(ctx, "failed to update session status to done",
+			zap.Error(err),
+			zap.String("session_id", sessionID),
+		)
+	}
+}
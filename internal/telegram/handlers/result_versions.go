@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/telegram/keyboard"
+	"github.com/futig/agent-backend/internal/telegram/render"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// maxListedResultVersions limits the version buttons, older versions stay downloadable through the API
+const maxListedResultVersions = 10
+
+// handleResultVersions lists the stored result versions that can still be downloaded
+func (h *CallbackHandler) handleResultVersions(ctx context.Context, msg *Message) error {
+	telegramSession, err := h.stateManager.GetSession(ctx, msg.UserID)
+	if err != nil {
+		return fmt.Errorf("get user state: %w", err)
+	}
+	sessionID := telegramSession.SessionID
+
+	versions, err := h.sessionUC.ListResultVersions(ctx, sessionID)
+	if err != nil {
+		ctxzap.Error(ctx, "failed to list result versions",
+			zap.Error(err),
+			zap.String("session_id", sessionID),
+		)
+		h.HandleError(ctx, msg.ChatID, err)
+		return nil
+	}
+
+	buttons := make([]keyboard.ResultVersion, 0, maxListedResultVersions)
+	for _, v := range versions.Versions {
+		if !v.Available {
+			continue
+		}
+		buttons = append(buttons, keyboard.ResultVersion{
+			Number:    v.Version,
+			CreatedAt: entity.LocalTime(ctx, v.CreatedAt),
+			Current:   v.Version == versions.Current,
+		})
+		if len(buttons) == maxListedResultVersions {
+			break
+		}
+	}
+
+	if len(buttons) == 0 {
+		h.sendMessage(msg.ChatID, render.MsgNoResultVersions, nil)
+		return nil
+	}
+
+	h.sendMessage(msg.ChatID, render.MsgChooseResultVersion, h.keyboard.ResultVersionsKeyboard(buttons))
+	return nil
+}
+
+// handleResultVersion handles "ver:N", offering the formats of a version, and "ver:N:format",
+// sending the version as a document
+func (h *CallbackHandler) handleResultVersion(ctx context.Context, msg *Message, value string) error {
+	telegramSession, err := h.stateManager.GetSession(ctx, msg.UserID)
+	if err != nil {
+		return fmt.Errorf("get user state: %w", err)
+	}
+	sessionID := telegramSession.SessionID
+
+	numberValue, format, withFormat := strings.Cut(value, ":")
+	number, err := strconv.Atoi(numberValue)
+	if err != nil {
+		ctxzap.Warn(ctx, "invalid result version parameter", zap.String("value", value))
+		return nil
+	}
+
+	resultFormat := entity.ResultFormat(format)
+	if withFormat && !resultFormat.IsValid() {
+		ctxzap.Warn(ctx, "invalid download format parameter", zap.String("format", format))
+		h.sendMessage(msg.ChatID, "❌ Неверный формат. Доступны: markdown, docx, pdf", nil)
+		return nil
+	}
+
+	version, result, err := h.sessionUC.GetResultVersion(ctx, sessionID, number)
+	if err != nil {
+		ctxzap.Error(ctx, "failed to get result version",
+			zap.Error(err),
+			zap.String("session_id", sessionID),
+			zap.Int("version", number),
+		)
+		h.sendMessage(msg.ChatID, render.ClassifyError(ctx, err), nil)
+		return nil
+	}
+
+	createdAt := entity.LocalTime(ctx, version.CreatedAt)
+	if !withFormat {
+		text := fmt.Sprintf(render.MsgResultVersionFormat, version.Version, createdAt.Format("02.01.2006 15:04"))
+		h.sendMessage(msg.ChatID, text, h.keyboard.ResultVersionDownloadKeyboard(version.Version))
+		return nil
+	}
+
+	fileInfo, err := h.sessionUC.GetResultFileInfo(ctx, sessionID)
+	if err != nil {
+		ctxzap.Error(ctx, "failed to get result file info",
+			zap.Error(err),
+			zap.String("session_id", sessionID),
+		)
+		h.sendMessage(msg.ChatID, "❌ Не удалось подготовить файл", nil)
+		return nil
+	}
+	fileInfo.Date = createdAt
+
+	h.sendResultDocument(ctx, msg.ChatID, result, resultFormat, "", fileInfo, fmt.Sprintf("r%d", version.Version))
+	return nil
+}
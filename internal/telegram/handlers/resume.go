@@ -0,0 +1,205 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/telegram/keyboard"
+	"github.com/futig/agent-backend/internal/telegram/render"
+	"github.com/futig/agent-backend/internal/telegram/state"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// Resumer continues the session of the user from the step it was interrupted at
+type Resumer interface {
+	Resume(ctx context.Context, msg *Message) error
+}
+
+// Resume implements Resumer: the session is recovered and its current step is shown again, the
+// current question with its buttons in interviews and the collected materials in drafts
+func (h *CallbackHandler) Resume(ctx context.Context, msg *Message) error {
+	telegramSession, err := h.stateManager.GetSession(ctx, msg.UserID)
+	if err != nil {
+		return fmt.Errorf("get user state: %w", err)
+	}
+	if telegramSession.SessionID == "" {
+		h.sendMessage(msg.ChatID, render.MsgResumeNoSession, nil)
+		return nil
+	}
+
+	// A generation still running must not have its session moved back; the registration of an
+	// operation that died with the bot expires on its own
+	session, err := h.sessionUC.ResumeSession(ctx, telegramSession.SessionID)
+	if errors.Is(err, entity.ErrOperationInProgress) {
+		h.sendMessage(msg.ChatID, render.MsgResumeBusy, nil)
+		return nil
+	}
+	if err != nil {
+		h.HandleError(ctx, msg.ChatID, err)
+		return nil
+	}
+
+	switch session.Status {
+	case entity.SessionStatusWaitingForAnswers:
+		h.sendMessage(msg.ChatID, render.MsgResumed, nil)
+		return h.resumeQuestion(ctx, msg, session)
+	case entity.SessionStatusDraftCollecting:
+		stateData, err := h.stateManager.GetStateData(ctx, msg.UserID)
+		if err != nil {
+			return fmt.Errorf("get state data: %w", err)
+		}
+		h.sendMessage(msg.ChatID, render.MsgResumed, nil)
+		h.sendMessage(msg.ChatID, fmt.Sprintf(render.MsgResumeDraft, stateData.DraftMessageCount), h.keyboard.DraftCollectionKeyboard())
+	case entity.SessionStatusNew, entity.SessionStatusAskUserGoal:
+		h.sendMessage(msg.ChatID, render.MsgAskGoal, nil)
+	case entity.SessionStatusSelectOrCreateProject:
+		kbProjects, pagination, err := loadProjectSelection(ctx, h.projectUC, msg.UserID, 0)
+		if err != nil {
+			ctxzap.Error(ctx, "failed to list projects",
+				zap.Error(err),
+				zap.Int64("user_id", msg.UserID),
+			)
+			h.sendMessage(msg.ChatID, render.ClassifyError(ctx, err), nil)
+			return nil
+		}
+		h.sendMessage(msg.ChatID, render.MsgSelectProject, h.keyboard.ProjectSelectionKeyboardWithPagination(kbProjects, pagination))
+	case entity.SessionStatusChooseMode:
+		h.sendMessage(msg.ChatID, render.MsgChooseMode, h.keyboard.ModeSelectionKeyboard())
+	case entity.SessionStatusInterviewInfo, entity.SessionStatusDraftInfo:
+		sessionType := entity.SessionTypeDraft
+		if session.Type != nil {
+			sessionType = *session.Type
+		}
+		h.sendModeInfo(msg.ChatID, sessionType)
+	case entity.SessionStatusDone:
+		hasSkipped, err := h.sessionUC.HasSkippedQuestions(ctx, session.ID)
+		if err != nil {
+			ctxzap.Error(ctx, "failed to check skipped questions",
+				zap.Error(err),
+				zap.String("session_id", session.ID),
+			)
+		}
+		h.sendMessage(msg.ChatID, render.MsgResultReady, h.keyboard.ResultDownloadKeyboard(hasSkipped))
+	case entity.SessionStatusPartial:
+		hasSkipped, err := h.sessionUC.HasSkippedQuestions(ctx, session.ID)
+		if err != nil {
+			ctxzap.Error(ctx, "failed to check skipped questions",
+				zap.Error(err),
+				zap.String("session_id", session.ID),
+			)
+		}
+		h.sendMessage(msg.ChatID, render.MsgPartialResult, h.keyboard.PartialResultKeyboard(hasSkipped))
+	case entity.SessionStatusCanceled, entity.SessionStatusError:
+		h.sendMessage(msg.ChatID, render.MsgResumeUnavailable, nil)
+	default:
+		// Steps answered with a text message keep working after a restart
+		h.sendMessage(msg.ChatID, render.MsgResumeNotStuck, nil)
+	}
+
+	return nil
+}
+
+// resumeQuestion shows the current question again; an interview without open questions goes on
+// to validation and generation like after its last answer
+func (h *CallbackHandler) resumeQuestion(ctx context.Context, msg *Message, session *entity.Session) error {
+	return resumeCurrentQuestion(
+		ctx,
+		msg,
+		session,
+		h.sessionUC,
+		h.projectUC,
+		h.stateManager,
+		h.keyboard,
+		h.bot,
+		h.logger,
+		h.sendMessage,
+	)
+}
+
+// resumeCurrentQuestion shows the question the user is on, e.g. after a restart or an edited answer
+func resumeCurrentQuestion(
+	ctx context.Context,
+	msg *Message,
+	session *entity.Session,
+	sessionUC SessionUsecase,
+	projectUC ProjectUsecase,
+	stateManager *state.Manager,
+	kb *keyboard.Builder,
+	bot *tgbotapi.BotAPI,
+	logger *zap.Logger,
+	send func(chatID int64, text string, replyMarkup interface{}),
+) error {
+	stateData, err := stateManager.GetStateData(ctx, msg.UserID)
+	if err != nil {
+		return fmt.Errorf("get state data: %w", err)
+	}
+
+	// The bot state knows the question the user was on, also while answering skipped or deferred
+	// questions; the session only points at its first open question
+	question := session.CurrentQuestion
+	if stateData.CurrentQuestionID != "" {
+		current, err := sessionUC.GetQuestionByID(ctx, stateData.CurrentQuestionID)
+		if err == nil && current.Status != entity.AnswerStatusAnswered {
+			question = current
+		}
+	}
+
+	if question == nil {
+		if err := finishInterview(
+			ctx,
+			msg,
+			session.ID,
+			sessionUC,
+			projectUC,
+			stateManager,
+			kb,
+			bot,
+			logger,
+			send,
+		); err != nil {
+			ctxzap.Error(ctx, "failed to validate answers or generate summary",
+				zap.Error(err),
+				zap.String("session_id", session.ID),
+			)
+			send(msg.ChatID, render.ClassifyError(ctx, err), nil)
+		}
+		return nil
+	}
+
+	iteration, err := sessionUC.GetIterationByID(ctx, question.IterationID)
+	if err != nil {
+		return fmt.Errorf("get iteration: %w", err)
+	}
+
+	questionIndex := 1
+	for i, q := range iteration.Questions {
+		if q.ID == question.ID {
+			questionIndex = i + 1
+			break
+		}
+	}
+
+	questionText := render.RenderQuestion(
+		iteration.Title,
+		questionPosition(ctx, sessionUC, stateManager, msg.UserID, session.ID, question.ID, questionIndex, len(iteration.Questions)),
+		question.Question,
+	)
+
+	stateData.CurrentIterationID = iteration.IterationID
+	stateData.CurrentQuestionID = question.ID
+	if err := stateManager.UpdateStateData(ctx, msg.UserID, stateData); err != nil {
+		ctxzap.Error(ctx, "failed to update state data",
+			zap.Error(err),
+			zap.Int64("user_id", msg.UserID),
+		)
+	}
+
+	hasPrevious := stateData.PreviousQuestionID != ""
+	sendQuestionMessage(ctx, bot, stateManager, msg, stateData, questionText, question.ID, questionKeyboard(ctx, stateManager, kb, msg.UserID, question.ID, question.AnswerType, hasPrevious))
+
+	return nil
+}
@@ -27,6 +27,25 @@ func sendMessageWithRetry(
 		msg.ReplyMarkup = markup
 	}
 
+	// Each part of a long message is retried on its own so delivered parts are not sent twice
+	for _, part := range splitMessageConfig(msg) {
+		if err := sendPartWithRetry(bot, part, maxRetries, logger); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// sendPartWithRetry sends a single message within the Telegram limit, retrying failed attempts
+func sendPartWithRetry(
+	bot *tgbotapi.BotAPI,
+	msg tgbotapi.MessageConfig,
+	maxRetries int,
+	logger *zap.Logger,
+) error {
+	chatID := msg.ChatID
+
 	var lastErr error
 	for attempt := 0; attempt < maxRetries; attempt++ {
 		_, err := bot.Send(msg)
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/telegram/render"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// handleReviewDecision records an approver decision ("approve:<session_id>" or "reject:<session_id>")
+// and notifies the session owner
+func (h *CallbackHandler) handleReviewDecision(ctx context.Context, msg *Message, value string) error {
+	decision, sessionID, ok := strings.Cut(value, ":")
+	if !ok || (decision != "approve" && decision != "reject") {
+		return fmt.Errorf("invalid review decision: %s", value)
+	}
+	approve := decision == "approve"
+
+	approver := entity.ResultApprover{
+		Type: entity.ApproverTypeTelegram,
+		ID:   strconv.FormatInt(msg.UserID, 10),
+	}
+
+	if _, err := h.sessionUC.DecideReview(ctx, sessionID, approver, approve, ""); err != nil {
+		ctxzap.Error(ctx, "failed to decide review",
+			zap.Error(err),
+			zap.String("session_id", sessionID),
+		)
+		h.sendMessage(msg.ChatID, render.ClassifyError(ctx, err), nil)
+		return nil
+	}
+
+	approverMsg, ownerMsg := render.MsgReviewRejected, render.MsgOwnerReviewRejected
+	if approve {
+		approverMsg, ownerMsg = render.MsgReviewApproved, render.MsgOwnerReviewApproved
+	}
+	h.sendMessage(msg.ChatID, approverMsg, nil)
+
+	// Sessions started via HTTP API have no Telegram owner
+	owner, err := h.stateManager.GetBySessionID(ctx, sessionID)
+	if err != nil {
+		ctxzap.Debug(ctx, "review owner not found in telegram",
+			zap.Error(err),
+			zap.String("session_id", sessionID),
+		)
+		return nil
+	}
+
+	if owner.UserID != msg.UserID {
+		h.sendMessage(owner.UserID, ownerMsg, nil)
+	}
+
+	return nil
+}
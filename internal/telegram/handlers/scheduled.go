@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/telegram/render"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// handleStartScheduled binds the user to a scheduled check-in session ("scheduled:<session_id>");
+// the session already has its goal and project context, so the flow continues from mode selection
+func (h *CallbackHandler) handleStartScheduled(ctx context.Context, msg *Message, sessionID string) error {
+	session, err := h.sessionUC.GetSession(ctx, sessionID)
+	if err != nil {
+		ctxzap.Error(ctx, "failed to get scheduled session",
+			zap.Error(err),
+			zap.String("session_id", sessionID),
+		)
+		h.sendMessage(msg.ChatID, render.ClassifyError(ctx, err), nil)
+		return nil
+	}
+
+	if session.Status != entity.SessionStatusChooseMode {
+		h.sendMessage(msg.ChatID, render.ErrScheduledSessionUnavailable, nil)
+		return nil
+	}
+
+	if err := h.stateManager.CreateOrUpdateSession(ctx, msg.UserID, session.ID); err != nil {
+		ctxzap.Error(ctx, "failed to create telegram session",
+			zap.Error(err),
+			zap.Int64("user_id", msg.UserID),
+		)
+		h.sendMessage(msg.ChatID, render.ClassifyError(ctx, err), nil)
+		return nil
+	}
+
+	goal := ""
+	if session.UserGoal != nil {
+		goal = *session.UserGoal
+	}
+
+	h.sendMessage(msg.ChatID, fmt.Sprintf(render.MsgScheduledSessionStarted, goal), nil)
+	h.sendMessage(msg.ChatID, render.MsgChooseMode, h.keyboard.ModeSelectionKeyboard())
+	return nil
+}
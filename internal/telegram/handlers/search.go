@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/futig/agent-backend/internal/telegram/render"
+	"github.com/futig/agent-backend/internal/telegram/state"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// handleSearch waits for the next text message to be used as a search query
+func (h *CallbackHandler) handleSearch(ctx context.Context, msg *Message) error {
+	if err := setAwaitingSearch(ctx, msg.UserID, h.stateManager, true); err != nil {
+		return err
+	}
+
+	h.sendMessage(msg.ChatID, render.MsgSearchPrompt, h.keyboard.SearchPromptKeyboard())
+	return nil
+}
+
+// handleSearchCancel returns to collecting answers or draft messages
+func (h *CallbackHandler) handleSearchCancel(ctx context.Context, msg *Message) error {
+	if err := setAwaitingSearch(ctx, msg.UserID, h.stateManager, false); err != nil {
+		return err
+	}
+
+	h.sendMessage(msg.ChatID, render.MsgSearchCancelled, nil)
+	return nil
+}
+
+// handlePendingSearch runs a requested search instead of treating the message as an answer
+// or draft message. Returns true when the message was consumed.
+func handlePendingSearch(
+	ctx context.Context,
+	msg *Message,
+	sessionID string,
+	stateData *state.StateData,
+	sessionUC SessionUsecase,
+	stateManager *state.Manager,
+	send func(chatID int64, text string, replyMarkup interface{}),
+) bool {
+	if !stateData.AwaitingSearch {
+		return false
+	}
+
+	if msg.Text == "" {
+		send(msg.ChatID, render.MsgSearchTextOnly, nil)
+		return true
+	}
+
+	stateData.AwaitingSearch = false
+	if err := stateManager.UpdateStateData(ctx, msg.UserID, stateData); err != nil {
+		ctxzap.Warn(ctx, "failed to clear search request from state",
+			zap.Error(err),
+			zap.Int64("user_id", msg.UserID),
+		)
+	}
+
+	hits, err := sessionUC.SearchSessionContent(ctx, sessionID, msg.Text)
+	if err != nil {
+		ctxzap.Error(ctx, "failed to search session content",
+			zap.Error(err),
+			zap.String("session_id", sessionID),
+		)
+		send(msg.ChatID, render.ClassifyError(ctx, err), nil)
+		return true
+	}
+
+	if len(hits) == 0 {
+		send(msg.ChatID, fmt.Sprintf(render.MsgSearchNoResults, msg.Text), nil)
+		return true
+	}
+
+	lines := []string{fmt.Sprintf(render.MsgSearchResults, msg.Text)}
+	for i, hit := range hits {
+		lines = append(lines, render.RenderSearchHit(i+1, hit.Question, hit.Snippet))
+	}
+
+	send(msg.ChatID, strings.Join(lines, "\n\n"), nil)
+	return true
+}
+
+func setAwaitingSearch(ctx context.Context, userID int64, stateManager *state.Manager, awaiting bool) error {
+	stateData, err := stateManager.GetStateData(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("get state data: %w", err)
+	}
+
+	stateData.AwaitingSearch = awaiting
+	if err := stateManager.UpdateStateData(ctx, userID, stateData); err != nil {
+		return fmt.Errorf("update state data: %w", err)
+	}
+
+	return nil
+}
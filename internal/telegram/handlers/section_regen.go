@@ -0,0 +1,277 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/telegram/keyboard"
+	"github.com/futig/agent-backend/internal/telegram/render"
+	"github.com/futig/agent-backend/internal/telegram/state"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// SectionGuidanceHandler handles ASK_SECTION_GUIDANCE state
+type SectionGuidanceHandler struct {
+	BaseHandler
+	bot          *tgbotapi.BotAPI
+	stateManager *state.Manager
+	sessionUC    SessionUsecase
+	keyboard     *keyboard.Builder
+	logger       *zap.Logger
+}
+
+// NewSectionGuidanceHandler creates a new section guidance handler
+func NewSectionGuidanceHandler(
+	bot *tgbotapi.BotAPI,
+	stateManager *state.Manager,
+	sessionUC SessionUsecase,
+	kb *keyboard.Builder,
+	logger *zap.Logger,
+) *SectionGuidanceHandler {
+	return &SectionGuidanceHandler{
+		BaseHandler: BaseHandler{
+			stateName:     HandlerStateAskSectionGuidance,
+			messageSender: NewMessageSender(bot, logger),
+		},
+		bot:          bot,
+		stateManager: stateManager,
+		sessionUC:    sessionUC,
+		keyboard:     kb,
+		logger:       logger,
+	}
+}
+
+// Handle regenerates the selected section using the message text as guidance
+func (h *SectionGuidanceHandler) Handle(ctx context.Context, msg *Message) error {
+	if msg.Text == "" {
+		h.sendMessage(msg.ChatID, "❌ Пожалуйста, напишите пожелания текстом.", h.keyboard.SectionGuidanceKeyboard())
+		return nil
+	}
+
+	return regenerateSection(ctx, msg, msg.Text, h.sessionUC, h.stateManager, h.keyboard, h.bot, h.logger, h.sendMessage)
+}
+
+// handleRegenSection offers the sections of the result for regeneration
+func (h *CallbackHandler) handleRegenSection(ctx context.Context, msg *Message) error {
+	telegramSession, err := h.stateManager.GetSession(ctx, msg.UserID)
+	if err != nil {
+		return fmt.Errorf("get user state: %w", err)
+	}
+
+	sections, err := h.sessionUC.ListResultSections(ctx, telegramSession.SessionID)
+	if err != nil {
+		ctxzap.Error(ctx, "failed to list result sections",
+			zap.Error(err),
+			zap.String("session_id", telegramSession.SessionID),
+		)
+		h.HandleError(ctx, msg.ChatID, err)
+		return nil
+	}
+
+	if len(sections) == 0 {
+		h.sendMessage(msg.ChatID, render.MsgNoSections, nil)
+		return nil
+	}
+
+	titles := make([]string, 0, len(sections))
+	for _, s := range sections {
+		titles = append(titles, sectionTitle(s))
+	}
+
+	h.sendMessage(msg.ChatID, render.MsgChooseSection, h.keyboard.SectionSelectionKeyboard(titles))
+	return nil
+}
+
+// handleSectionCallback handles section selection, regeneration without guidance and cancel
+func (h *CallbackHandler) handleSectionCallback(ctx context.Context, msg *Message, value string) error {
+	switch value {
+	case "go":
+		return regenerateSection(ctx, msg, "", h.sessionUC, h.stateManager, h.keyboard, h.bot, h.logger, h.sendMessage)
+	case "cancel":
+		return h.cancelSectionRegeneration(ctx, msg)
+	}
+
+	sectionIndex, err := strconv.Atoi(value)
+	if err != nil {
+		return fmt.Errorf("invalid section index: %s", value)
+	}
+
+	telegramSession, err := h.stateManager.GetSession(ctx, msg.UserID)
+	if err != nil {
+		return fmt.Errorf("get user state: %w", err)
+	}
+
+	sections, err := h.sessionUC.ListResultSections(ctx, telegramSession.SessionID)
+	if err != nil {
+		h.HandleError(ctx, msg.ChatID, err)
+		return nil
+	}
+
+	if sectionIndex < 0 || sectionIndex >= len(sections) {
+		h.sendMessage(msg.ChatID, render.MsgNoSections, nil)
+		return nil
+	}
+
+	stateData, err := h.stateManager.GetStateData(ctx, msg.UserID)
+	if err != nil {
+		return fmt.Errorf("get state data: %w", err)
+	}
+
+	stateData.RegenSectionIndex = &sectionIndex
+	if err := h.stateManager.UpdateStateData(ctx, msg.UserID, stateData); err != nil {
+		return fmt.Errorf("update state data: %w", err)
+	}
+
+	if _, err := h.sessionUC.UpdateSessionStatus(ctx, telegramSession.SessionID, entity.SessionStatusAskSectionGuidance); err != nil {
+		ctxzap.Error(ctx, "failed to update session status",
+			zap.Error(err),
+			zap.String("session_id", telegramSession.SessionID),
+		)
+		h.HandleError(ctx, msg.ChatID, err)
+		return nil
+	}
+
+	h.sendMessage(msg.ChatID,
+		fmt.Sprintf(render.MsgSectionGuidance, sectionTitle(sections[sectionIndex])),
+		h.keyboard.SectionGuidanceKeyboard(),
+	)
+	return nil
+}
+
+// cancelSectionRegeneration returns the session to DONE without changes
+func (h *CallbackHandler) cancelSectionRegeneration(ctx context.Context, msg *Message) error {
+	telegramSession, err := h.stateManager.GetSession(ctx, msg.UserID)
+	if err != nil {
+		return fmt.Errorf("get user state: %w", err)
+	}
+
+	finishSectionRegeneration(ctx, msg, telegramSession.SessionID, h.sessionUC, h.stateManager)
+
+	h.sendMessage(msg.ChatID, render.MsgSectionRegenCancel, nil)
+	return nil
+}
+
+// regenerateSection regenerates the section selected in state data with optional guidance
+func regenerateSection(
+	ctx context.Context,
+	msg *Message,
+	guidance string,
+	sessionUC SessionUsecase,
+	stateManager *state.Manager,
+	kb *keyboard.Builder,
+	bot *tgbotapi.BotAPI,
+	logger *zap.Logger,
+	send func(chatID int64, text string, replyMarkup interface{}),
+) error {
+	telegramSession, err := stateManager.GetSession(ctx, msg.UserID)
+	if err != nil {
+		return fmt.Errorf("get telegram session: %w", err)
+	}
+	sessionID := telegramSession.SessionID
+
+	stateData, err := stateManager.GetStateData(ctx, msg.UserID)
+	if err != nil {
+		return fmt.Errorf("get state data: %w", err)
+	}
+
+	if stateData.RegenSectionIndex == nil {
+		send(msg.ChatID, render.ErrInvalidState, nil)
+		return nil
+	}
+	sectionIndex := *stateData.RegenSectionIndex
+
+	sections, err := sessionUC.ListResultSections(ctx, sessionID)
+	if err != nil {
+		return fmt.Errorf("list result sections: %w", err)
+	}
+	if sectionIndex >= len(sections) {
+		finishSectionRegeneration(ctx, msg, sessionID, sessionUC, stateManager)
+		send(msg.ChatID, render.MsgNoSections, nil)
+		return nil
+	}
+	title := sectionTitle(sections[sectionIndex])
+
+	send(msg.ChatID, fmt.Sprintf(render.MsgSectionRegenerating, title), nil)
+
+	typing := NewTypingNotifier(bot, msg.ChatID, logger)
+	typing.Start(ctx)
+	defer typing.Stop()
+
+	if _, err := sessionUC.RegenerateResultSection(ctx, sessionID, sectionIndex, guidance); err != nil {
+		ctxzap.Error(ctx, "failed to regenerate result section",
+			zap.Error(err),
+			zap.String("session_id", sessionID),
+			zap.Int("section_index", sectionIndex),
+		)
+
+		// Let the user rephrase blocked guidance
+		if errors.Is(err, entity.ErrContentBlocked) {
+			send(msg.ChatID, render.ErrContentBlocked, kb.SectionGuidanceKeyboard())
+			return nil
+		}
+
+		finishSectionRegeneration(ctx, msg, sessionID, sessionUC, stateManager)
+		send(msg.ChatID, render.ClassifyError(ctx, err), nil)
+		return nil
+	}
+
+	typing.Stop()
+	finishSectionRegeneration(ctx, msg, sessionID, sessionUC, stateManager)
+
+	hasSkipped, err := sessionUC.HasSkippedQuestions(ctx, sessionID)
+	if err != nil {
+		ctxzap.Error(ctx, "failed to check skipped questions",
+			zap.Error(err),
+			zap.String("session_id", sessionID),
+		)
+	}
+
+	send(msg.ChatID, fmt.Sprintf(render.MsgSectionRegenerated, title), kb.ResultDownloadKeyboard(hasSkipped))
+	return nil
+}
+
+// finishSectionRegeneration clears the selected section and returns the session to DONE
+func finishSectionRegeneration(
+	ctx context.Context,
+	msg *Message,
+	sessionID string,
+	sessionUC SessionUsecase,
+	stateManager *state.Manager,
+) {
+	stateData, err := stateManager.GetStateData(ctx, msg.UserID)
+	if err == nil {
+		stateData.RegenSectionIndex = nil
+		err = stateManager.UpdateStateData(ctx, msg.UserID, stateData)
+	}
+	if err != nil {
+		ctxzap.Warn(ctx, "failed to clear regenerated section from state",
+			zap.Error(err),
+			zap.Int64("user_id", msg.UserID),
+		)
+	}
+
+	session, err := sessionUC.GetSession(ctx, sessionID)
+	if err != nil || session.Status != entity.SessionStatusAskSectionGuidance {
+		return
+	}
+
+	if _, err := sessionUC.UpdateSessionStatus(ctx, sessionID, entity.SessionStatusDone); err != nil {
+		ctxzap.Warn(ctx, "failed to update session status to done",
+			zap.Error(err),
+			zap.String("session_id", sessionID),
+		)
+	}
+}
+
+// sectionTitle returns a display title for a result section
+func sectionTitle(section *entity.ResultSection) string {
+	if section.Title == "" {
+		return render.MsgSectionUntitled
+	}
+	return section.Title
+}
@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/telegram/keyboard"
+	"github.com/futig/agent-backend/internal/telegram/render"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// handleSettings handles the /settings menu: "menu" returns to the menu, "pins" lists the
+// pinned projects, "unpin:<project_id>" unpins one of them, "tz" shows the timezone of the user,
+// "tz:<timezone>" sets it, "style" shows the document style, "style:<style>" sets it and "progress"
+// shows or hides the progress header of questions
+func (h *CallbackHandler) handleSettings(ctx context.Context, msg *Message, value string) error {
+	if timezone, ok := strings.CutPrefix(value, "tz:"); ok {
+		return h.setTimezone(ctx, msg, timezone)
+	}
+	if style, ok := strings.CutPrefix(value, "style:"); ok {
+		return h.setSummaryStyle(ctx, msg, style)
+	}
+	if projectID, ok := strings.CutPrefix(value, "unpin:"); ok {
+		if err := h.projectUC.UnpinProject(ctx, projectID, msg.UserID); err != nil {
+			ctxzap.Error(ctx, "failed to unpin project",
+				zap.Error(err),
+				zap.String("project_id", projectID),
+			)
+			h.sendMessage(msg.ChatID, render.ClassifyError(ctx, err), nil)
+			return nil
+		}
+		return h.showPinnedProjects(ctx, msg)
+	}
+
+	switch value {
+	case "menu":
+		h.replaceMessage(ctx, msg, render.MsgSettings, h.keyboard.SettingsKeyboard())
+		return nil
+	case "pins":
+		return h.showPinnedProjects(ctx, msg)
+	case "tz":
+		return h.showTimezone(ctx, msg)
+	case "style":
+		return h.showSummaryStyle(ctx, msg)
+	case "progress":
+		return h.toggleProgressHeader(ctx, msg)
+	default:
+		return fmt.Errorf("unknown settings action: %s", value)
+	}
+}
+
+// showPinnedProjects replaces the settings message with the list of pinned projects
+func (h *CallbackHandler) showPinnedProjects(ctx context.Context, msg *Message) error {
+	pinned, err := h.projectUC.ListPinnedProjects(ctx, msg.UserID)
+	if err != nil {
+		ctxzap.Error(ctx, "failed to list pinned projects",
+			zap.Error(err),
+			zap.Int64("user_id", msg.UserID),
+		)
+		h.sendMessage(msg.ChatID, render.ClassifyError(ctx, err), nil)
+		return nil
+	}
+
+	text := render.MsgPinnedProjects
+	if len(pinned) == 0 {
+		text = fmt.Sprintf(render.MsgNoPinnedProjects, entity.MaxPinnedProjects)
+	}
+
+	kbProjects := make([]keyboard.Project, 0, len(pinned))
+	for _, p := range pinned {
+		kbProjects = append(kbProjects, keyboard.Project{ID: p.ID, Title: p.Title, Pinned: true})
+	}
+
+	h.replaceMessage(ctx, msg, text, h.keyboard.PinnedProjectsKeyboard(kbProjects))
+	return nil
+}
+
+// showTimezone replaces the settings message with the timezone of the user and the common timezones
+func (h *CallbackHandler) showTimezone(ctx context.Context, msg *Message) error {
+	loc, err := h.stateManager.GetLocation(ctx, msg.UserID)
+	if err != nil {
+		ctxzap.Warn(ctx, "failed to get user timezone",
+			zap.Error(err),
+			zap.Int64("user_id", msg.UserID),
+		)
+	}
+
+	h.replaceMessage(ctx, msg, RenderTimezone(render.MsgTimezone, loc), h.keyboard.TimezoneKeyboard(loc.String()))
+	return nil
+}
+
+// setTimezone saves the timezone picked in the settings and replaces the message with the confirmation
+func (h *CallbackHandler) setTimezone(ctx context.Context, msg *Message, timezone string) error {
+	loc, err := ApplyTimezone(ctx, h.stateManager, h.projectUC, msg.UserID, timezone)
+	if errors.Is(err, entity.ErrInvalidParameter) {
+		h.sendMessage(msg.ChatID, render.ErrInvalidTimezone, nil)
+		return nil
+	}
+	if err != nil {
+		ctxzap.Error(ctx, "failed to set user timezone",
+			zap.Error(err),
+			zap.Int64("user_id", msg.UserID),
+		)
+		h.sendMessage(msg.ChatID, render.ClassifyError(ctx, err), nil)
+		return nil
+	}
+
+	h.replaceMessage(ctx, msg, RenderTimezone(render.MsgTimezoneSet, loc), h.keyboard.SettingsKeyboard())
+	return nil
+}
+
+// toggleProgressHeader shows or hides the progress header above questions and replaces the settings
+// message with the confirmation
+func (h *CallbackHandler) toggleProgressHeader(ctx context.Context, msg *Message) error {
+	show, err := h.stateManager.ToggleProgressHeader(ctx, msg.UserID)
+	if err != nil {
+		ctxzap.Error(ctx, "failed to toggle progress header",
+			zap.Error(err),
+			zap.Int64("user_id", msg.UserID),
+		)
+		h.sendMessage(msg.ChatID, render.ClassifyError(ctx, err), nil)
+		return nil
+	}
+
+	text := render.MsgProgressHeaderOff
+	if show {
+		text = render.MsgProgressHeaderOn
+	}
+	h.replaceMessage(ctx, msg, text, h.keyboard.SettingsKeyboard())
+	return nil
+}
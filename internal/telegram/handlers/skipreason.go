@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/telegram/render"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// skipReasonLabels are shown in place of the reason keyboard once a reason is chosen
+var skipReasonLabels = map[entity.SkipReason]string{
+	entity.SkipReasonUnknown:    "не знаю",
+	entity.SkipReasonIrrelevant: "не релевантно, больше не спрошу",
+	entity.SkipReasonLater:      "отвечу позже",
+}
+
+// handleSkipReason records the reason for a skipped question; value is "<questionID>:<reason>"
+func (h *CallbackHandler) handleSkipReason(ctx context.Context, msg *Message, value string) error {
+	questionID, rawReason, ok := strings.Cut(value, ":")
+	reason := entity.SkipReason(rawReason)
+	if !ok || !reason.IsValid() {
+		return fmt.Errorf("invalid skip reason callback: %s", value)
+	}
+
+	telegramSession, err := h.stateManager.GetSession(ctx, msg.UserID)
+	if err != nil {
+		return fmt.Errorf("get user state: %w", err)
+	}
+
+	if err := h.sessionUC.SetSkipReason(ctx, telegramSession.SessionID, questionID, reason); err != nil {
+		ctxzap.Error(ctx, "failed to set skip reason",
+			zap.Error(err),
+			zap.String("question_id", questionID),
+		)
+		h.sendMessage(msg.ChatID, render.ClassifyError(ctx, err), nil)
+		return nil
+	}
+
+	text := fmt.Sprintf(render.MsgSkipReasonSaved, skipReasonLabels[reason])
+	edit := tgbotapi.NewEditMessageText(msg.ChatID, msg.MessageID, text)
+	if _, err := h.bot.Send(edit); err != nil {
+		ctxzap.Debug(ctx, "failed to edit skip reason prompt", zap.Error(err))
+	}
+
+	return nil
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/futig/agent-backend/internal/telegram/render"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+const (
+	// summaryStreamEditInterval keeps the preview edits well under the Telegram rate limits
+	summaryStreamEditInterval = 3 * time.Second
+	// summaryStreamPreviewLength leaves room for the preview header within a single message
+	summaryStreamPreviewLength = render.MaxMessageLength - 256
+)
+
+// SummaryStreamer shows the requirements being generated in a single message edited as new parts arrive
+type SummaryStreamer struct {
+	ctx       context.Context
+	bot       *tgbotapi.BotAPI
+	chatID    int64
+	messageID int
+	lastEdit  time.Time
+	lastText  string
+}
+
+// NewSummaryStreamer creates a streamer of the preview for the chat
+func NewSummaryStreamer(ctx context.Context, bot *tgbotapi.BotAPI, chatID int64) *SummaryStreamer {
+	return &SummaryStreamer{
+		ctx:    ctx,
+		bot:    bot,
+		chatID: chatID,
+	}
+}
+
+// Update shows the partial document; it is the progress callback of the streamed generation,
+// so updates coming faster than summaryStreamEditInterval are dropped
+func (s *SummaryStreamer) Update(partial string) {
+	if time.Since(s.lastEdit) < summaryStreamEditInterval {
+		return
+	}
+
+	// Partial Markdown may have unclosed entities, so the preview is sent as plain text
+	text := fmt.Sprintf(render.MsgSummaryStreamPreview, render.TailMessage(partial, summaryStreamPreviewLength))
+	if text == s.lastText {
+		return
+	}
+	s.lastEdit = time.Now()
+
+	if s.messageID == 0 {
+		sent, err := s.bot.Send(tgbotapi.NewMessage(s.chatID, text))
+		if err != nil {
+			ctxzap.Warn(s.ctx, "failed to send summary preview", zap.Error(err))
+			return
+		}
+		s.messageID = sent.MessageID
+		s.lastText = text
+		return
+	}
+
+	if _, err := s.bot.Send(tgbotapi.NewEditMessageText(s.chatID, s.messageID, text)); err != nil {
+		ctxzap.Debug(s.ctx, "failed to edit summary preview", zap.Error(err))
+		return
+	}
+	s.lastText = text
+}
+
+// Finish removes the preview, the final document is sent as usual
+func (s *SummaryStreamer) Finish() {
+	if s.messageID == 0 {
+		return
+	}
+
+	if _, err := s.bot.Request(tgbotapi.NewDeleteMessage(s.chatID, s.messageID)); err != nil {
+		ctxzap.Debug(s.ctx, "failed to delete summary preview", zap.Error(err))
+	}
+	s.messageID = 0
+}
@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/telegram/render"
+	"github.com/futig/agent-backend/internal/telegram/state"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// handleSummaryStyleMenu offers the styles the generated document can be rewritten in
+func (h *CallbackHandler) handleSummaryStyleMenu(ctx context.Context, msg *Message) error {
+	telegramSession, err := h.stateManager.GetSession(ctx, msg.UserID)
+	if err != nil {
+		return fmt.Errorf("get user state: %w", err)
+	}
+
+	session, err := h.sessionUC.GetSession(ctx, telegramSession.SessionID)
+	if err != nil {
+		h.HandleError(ctx, msg.ChatID, err)
+		return nil
+	}
+
+	var current entity.SummaryStyle
+	if session.SummaryStyle != nil {
+		current = *session.SummaryStyle
+	}
+
+	h.sendMessage(msg.ChatID, render.RenderSummaryStyleChoice(), h.keyboard.SummaryStyleKeyboard(current))
+	return nil
+}
+
+// handleSummaryStyle sets the style of the session document, value is the style. A generated
+// result is rewritten in it; the style is remembered for the next sessions of the user
+func (h *CallbackHandler) handleSummaryStyle(ctx context.Context, msg *Message, value string) error {
+	style := entity.SummaryStyle(value)
+	if !style.IsValid() {
+		ctxzap.Warn(ctx, "invalid summary style", zap.String("style", value))
+		h.sendMessage(msg.ChatID, "❌ Стиль не поддерживается", nil)
+		return nil
+	}
+
+	telegramSession, err := h.stateManager.GetSession(ctx, msg.UserID)
+	if err != nil {
+		return fmt.Errorf("get user state: %w", err)
+	}
+	sessionID := telegramSession.SessionID
+
+	typing := NewTypingNotifier(h.bot, msg.ChatID, h.logger)
+	typing.Start(ctx)
+	session, err := h.sessionUC.SetSummaryStyle(ctx, sessionID, style)
+	typing.Stop()
+	if err != nil {
+		ctxzap.Error(ctx, "failed to set summary style",
+			zap.Error(err),
+			zap.String("session_id", sessionID),
+			zap.String("style", value),
+		)
+		h.sendMessage(msg.ChatID, render.ClassifyError(ctx, err), nil)
+		return nil
+	}
+
+	if err := h.stateManager.SetSummaryStyle(ctx, msg.UserID, style); err != nil {
+		ctxzap.Warn(ctx, "failed to remember summary style",
+			zap.Error(err),
+			zap.Int64("user_id", msg.UserID),
+		)
+	}
+
+	if session.Status != entity.SessionStatusDone {
+		h.sendMessage(msg.ChatID, render.RenderSummaryStyleSet(render.MsgSummaryStyleSet, style), nil)
+		return nil
+	}
+
+	hasSkipped, err := h.sessionUC.HasSkippedQuestions(ctx, sessionID)
+	if err != nil {
+		ctxzap.Error(ctx, "failed to check skipped questions",
+			zap.Error(err),
+			zap.String("session_id", sessionID),
+		)
+	}
+
+	h.sendMessage(msg.ChatID, render.RenderSummaryStyleSet(render.MsgResultRestyled, style), h.keyboard.ResultDownloadKeyboard(hasSkipped))
+	return nil
+}
+
+// showSummaryStyle replaces the settings message with the style of the next documents
+func (h *CallbackHandler) showSummaryStyle(ctx context.Context, msg *Message) error {
+	style, err := h.stateManager.GetSummaryStyle(ctx, msg.UserID)
+	if err != nil {
+		ctxzap.Warn(ctx, "failed to get summary style",
+			zap.Error(err),
+			zap.Int64("user_id", msg.UserID),
+		)
+	}
+
+	h.replaceMessage(ctx, msg, render.RenderSummaryStyleSettings(style), h.keyboard.SettingsSummaryStyleKeyboard(style))
+	return nil
+}
+
+// setSummaryStyle saves the style picked in the settings and replaces the message with the confirmation
+func (h *CallbackHandler) setSummaryStyle(ctx context.Context, msg *Message, value string) error {
+	style := entity.SummaryStyle(value)
+	if err := h.stateManager.SetSummaryStyle(ctx, msg.UserID, style); err != nil {
+		ctxzap.Error(ctx, "failed to set summary style",
+			zap.Error(err),
+			zap.Int64("user_id", msg.UserID),
+		)
+		h.sendMessage(msg.ChatID, render.ClassifyError(ctx, err), nil)
+		return nil
+	}
+
+	h.replaceMessage(ctx, msg, render.RenderSummaryStyleSet(render.MsgSettingsStyleSet, style), h.keyboard.SettingsKeyboard())
+	return nil
+}
+
+// applySummaryStyle gives a session without a style the style the user chose last, so the
+// document is generated in it
+func applySummaryStyle(
+	ctx context.Context,
+	userID int64,
+	sessionID string,
+	sessionUC SessionUsecase,
+	stateManager *state.Manager,
+) {
+	style, err := stateManager.GetSummaryStyle(ctx, userID)
+	if err != nil {
+		ctxzap.Warn(ctx, "failed to get summary style",
+			zap.Error(err),
+			zap.Int64("user_id", userID),
+		)
+		return
+	}
+	if style == "" {
+		return
+	}
+
+	session, err := sessionUC.GetSession(ctx, sessionID)
+	if err != nil || session.SummaryStyle != nil {
+		return
+	}
+
+	if _, err := sessionUC.SetSummaryStyle(ctx, sessionID, style); err != nil {
+		// The document is still generated, in the default style
+		ctxzap.Warn(ctx, "failed to apply summary style",
+			zap.Error(err),
+			zap.String("session_id", sessionID),
+		)
+	}
+}
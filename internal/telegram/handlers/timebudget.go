@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"context"
+
+	"github.com/futig/agent-backend/internal/telegram/keyboard"
+	"github.com/futig/agent-backend/internal/telegram/render"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// notifyTimeBudget sends a one-time soft warning once the interview time budget is nearly used.
+// The warning only offers to wrap up: answering may continue past the budget.
+func notifyTimeBudget(
+	ctx context.Context,
+	chatID int64,
+	sessionID string,
+	sessionUC SessionUsecase,
+	kb *keyboard.Builder,
+	send func(chatID int64, text string, replyMarkup interface{}),
+) {
+	status, err := sessionUC.CheckTimeBudget(ctx, sessionID)
+	if err != nil {
+		ctxzap.Warn(ctx, "failed to check time budget",
+			zap.Error(err),
+			zap.String("session_id", sessionID),
+		)
+		return
+	}
+
+	if status == nil || !status.Warn {
+		return
+	}
+
+	send(chatID, render.RenderTimeBudgetWarning(status.Remaining()), kb.TimeBudgetKeyboard())
+}
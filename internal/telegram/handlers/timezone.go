@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/futig/agent-backend/internal/telegram/render"
+	"github.com/futig/agent-backend/internal/telegram/state"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// ApplyTimezone saves the timezone chosen by the user and moves the check-in schedules bound to
+// them to it; a failure to move the schedules is logged, the timezone of the user stays saved
+func ApplyTimezone(ctx context.Context, stateManager *state.Manager, projectUC ProjectUsecase, userID int64, timezone string) (*time.Location, error) {
+	loc, err := stateManager.SetTimezone(ctx, userID, timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := projectUC.SetUserScheduleTimezone(ctx, userID, loc.String()); err != nil {
+		ctxzap.Warn(ctx, "failed to move schedules to the new timezone",
+			zap.Error(err),
+			zap.Int64("user_id", userID),
+			zap.String("timezone", loc.String()),
+		)
+	}
+
+	return loc, nil
+}
+
+// RenderTimezone formats the timezone of the user with the time there now
+func RenderTimezone(format string, loc *time.Location) string {
+	return fmt.Sprintf(format, loc.String(), time.Now().In(loc).Format(render.TimezoneNowLayout))
+}
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/futig/agent-backend/internal/telegram/render"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// handleTutorialPage shows an onboarding tutorial page in place of the current one
+func (h *CallbackHandler) handleTutorialPage(ctx context.Context, msg *Message, value string) error {
+	page, err := strconv.Atoi(value)
+	if err != nil || page < 0 || page >= len(render.TutorialCards) {
+		return fmt.Errorf("invalid tutorial page: %s", value)
+	}
+
+	kb := h.keyboard.TutorialKeyboard(page, len(render.TutorialCards))
+	edit := tgbotapi.NewEditMessageTextAndMarkup(msg.ChatID, msg.MessageID, render.TutorialCards[page], kb)
+	if _, err := h.bot.Send(edit); err != nil {
+		// The message may be too old to edit, e.g. the demo result keyboard; send the page anew
+		ctxzap.Debug(ctx, "failed to edit tutorial page, sending new message", zap.Error(err))
+		h.sendMessage(msg.ChatID, render.TutorialCards[page], kb)
+	}
+
+	return nil
+}
+
+// handleDemo runs the demo interview: "start" introduces it, "answer:<index>" answers
+// a question with the prepared answer and moves on to the next one or to the result
+func (h *CallbackHandler) handleDemo(ctx context.Context, msg *Message, value string) error {
+	if value == "start" {
+		question, err := h.demoUC.GetQuestion(ctx, 0)
+		if err != nil {
+			ctxzap.Error(ctx, "failed to get demo question", zap.Error(err))
+			h.sendMessage(msg.ChatID, render.ClassifyError(ctx, err), nil)
+			return nil
+		}
+
+		h.sendMessage(msg.ChatID, fmt.Sprintf(render.MsgDemoIntro, question.Goal, question.Total), nil)
+		return h.sendDemoQuestion(ctx, msg, 0)
+	}
+
+	indexValue, ok := strings.CutPrefix(value, "answer:")
+	index, err := strconv.Atoi(indexValue)
+	if !ok || err != nil {
+		return fmt.Errorf("invalid demo callback: %s", value)
+	}
+
+	question, err := h.demoUC.GetQuestion(ctx, index)
+	if err != nil {
+		ctxzap.Error(ctx, "failed to get demo question",
+			zap.Error(err),
+			zap.Int("index", index),
+		)
+		h.sendMessage(msg.ChatID, render.ClassifyError(ctx, err), nil)
+		return nil
+	}
+	h.sendMessage(msg.ChatID, fmt.Sprintf(render.MsgDemoAnswer, question.SampleAnswer), nil)
+
+	if question.Number < question.Total {
+		return h.sendDemoQuestion(ctx, msg, index+1)
+	}
+
+	typing := NewTypingNotifier(h.bot, msg.ChatID, h.logger)
+	typing.Start(ctx)
+	defer typing.Stop()
+
+	result, err := h.demoUC.GenerateSummary(ctx)
+	if err != nil {
+		ctxzap.Error(ctx, "failed to generate demo summary", zap.Error(err))
+		h.sendMessage(msg.ChatID, render.ClassifyError(ctx, err), nil)
+		return nil
+	}
+
+	h.sendMessage(msg.ChatID, result, nil)
+	h.sendMessage(msg.ChatID, render.MsgDemoFinished, h.keyboard.DemoFinishedKeyboard())
+	return nil
+}
+
+// sendDemoQuestion sends a demo question with the button answering it
+func (h *CallbackHandler) sendDemoQuestion(ctx context.Context, msg *Message, index int) error {
+	question, err := h.demoUC.GetQuestion(ctx, index)
+	if err != nil {
+		ctxzap.Error(ctx, "failed to get demo question",
+			zap.Error(err),
+			zap.Int("index", index),
+		)
+		h.sendMessage(msg.ChatID, render.ClassifyError(ctx, err), nil)
+		return nil
+	}
+
+	title := ""
+	if index == 0 {
+		title = question.Title
+	}
+	text := render.RenderDemoQuestion(title, question.Number, question.Total, question.Question, question.Explanation)
+	h.sendMessage(msg.ChatID, text, h.keyboard.DemoQuestionKeyboard(index))
+	return nil
+}
@@ -32,6 +32,8 @@ func handleValidationAndSummaryCommon(
 		return fmt.Errorf("get session: %w", err)
 	}
 
+	clearEditingQuestion(ctx, msg.UserID, stateManager)
+
 	// Start typing indicator during validation
 	typing := NewTypingNotifier(bot, msg.ChatID, logger)
 	typing.Start(ctx)
@@ -61,8 +63,7 @@ func handleValidationAndSummaryCommon(
 
 		questionText := render.RenderQuestion(
 			additionalIteration.Title,
-			1,
-			len(additionalIteration.Questions),
+			questionPosition(ctx, sessionUC, stateManager, msg.UserID, sessionID, additionalIteration.Questions[0].ID, 1, len(additionalIteration.Questions)),
 			additionalIteration.Questions[0].Question,
 		)
 
@@ -85,7 +86,7 @@ func handleValidationAndSummaryCommon(
 		}
 
 		hasPrevious := stateData.PreviousQuestionID != ""
-		send(msg.ChatID, questionText, kb.QuestionNavigationKeyboard(additionalIteration.Questions[0].ID, hasPrevious))
+		sendQuestionMessage(ctx, bot, stateManager, msg, stateData, questionText, additionalIteration.Questions[0].ID, questionKeyboard(ctx, stateManager, kb, msg.UserID, additionalIteration.Questions[0].ID, additionalIteration.Questions[0].AnswerType, hasPrevious))
 
 		return nil
 	}
@@ -103,6 +104,14 @@ func handleValidationAndSummaryCommon(
 	// Stop typing indicator before starting progress notifier
 	typing.Stop()
 
+	proceed, err := checkGenerationEstimate(ctx, msg, sessionID, false, sessionUC, stateManager, kb, send)
+	if err != nil {
+		return err
+	}
+	if !proceed {
+		return nil
+	}
+
 	// Inform user that summary generation may take some time
 	send(msg.ChatID, render.MsgProcessing, nil)
 
@@ -111,6 +120,8 @@ func handleValidationAndSummaryCommon(
 	progress.Start(ctx)
 	defer progress.Stop()
 
+	applySummaryStyle(ctx, msg.UserID, sessionID, sessionUC, stateManager)
+
 	// Call appropriate summary generation method based on session type
 	var finalSession *entity.Session
 	if session.Type != nil && *session.Type == entity.SessionTypeDraft {
@@ -119,7 +130,9 @@ func handleValidationAndSummaryCommon(
 			return fmt.Errorf("generate draft summary: %w", err)
 		}
 	} else {
-		finalSession, err = sessionUC.GenerateSummary(ctx, sessionID)
+		preview := NewSummaryStreamer(ctx, bot, msg.ChatID)
+		finalSession, err = sessionUC.GenerateSummaryStream(ctx, sessionID, preview.Update)
+		preview.Finish()
 		if err != nil {
 			return fmt.Errorf("generate summary: %w", err)
 		}
@@ -129,6 +142,7 @@ func handleValidationAndSummaryCommon(
 		zap.String("session_id", sessionID),
 		zap.String("status", string(finalSession.Status)),
 	)
+	notifyQuota(ctx, msg.ChatID, entity.QuotaKindGenerations, sessionUC, send)
 
 	hasSkipped, err := sessionUC.HasSkippedQuestions(ctx, sessionID)
 	if err != nil {
@@ -138,6 +152,12 @@ func handleValidationAndSummaryCommon(
 		)
 	}
 
+	// Generation kept failing and the collected materials were saved instead
+	if finalSession.Status == entity.SessionStatusPartial {
+		send(msg.ChatID, render.MsgPartialResult, kb.PartialResultKeyboard(hasSkipped))
+		return nil
+	}
+
 	// Get project title if session has a project
 	projectTitle := ""
 	if projectUC != nil && finalSession.ProjectID != nil && *finalSession.ProjectID != "" {
@@ -152,6 +172,13 @@ func handleValidationAndSummaryCommon(
 		}
 	}
 
+	sendChangeLog(ctx, bot, msg.ChatID, finalSession, sessionUC)
+
+	// The result is released once conflicts with earlier requirements are resolved
+	if presentConflicts(ctx, msg.ChatID, sessionID, sessionUC, kb, send) {
+		return nil
+	}
+
 	// Show result and save/download buttons
 	send(msg.ChatID, render.MsgResultReady, kb.ResultSaveKeyboard(hasSkipped, projectTitle))
 
@@ -289,7 +316,7 @@ func handleNextSkippedQuestion(
 	}
 
 	hasPrevious := stateData.PreviousQuestionID != ""
-	send(msg.ChatID, questionText, kb.QuestionNavigationKeyboard(nextQuestion.ID, hasPrevious))
+	sendQuestionMessage(ctx, bot, stateManager, msg, stateData, questionText, nextQuestion.ID, questionKeyboard(ctx, stateManager, kb, msg.UserID, nextQuestion.ID, nextQuestion.AnswerType, hasPrevious))
 
 	return true, nil
 }
@@ -315,6 +342,8 @@ func handleSkipCurrentQuestion(
 		return false, fmt.Errorf("skip question: %w", err)
 	}
 
+	send(msg.ChatID, render.MsgSkipReasonPrompt, kb.SkipReasonKeyboard(currentQuestionID))
+
 	stateData, err := stateManager.GetStateData(ctx, msg.UserID)
 	if err != nil {
 		return false, fmt.Errorf("get state data: %w", err)
@@ -381,7 +410,7 @@ func handleSkipCurrentQuestion(
 	}
 
 	hasPrevious := stateData.PreviousQuestionID != ""
-	send(msg.ChatID, questionText, kb.QuestionNavigationKeyboard(nextQuestion.ID, hasPrevious))
+	sendQuestionMessage(ctx, bot, stateManager, msg, stateData, questionText, nextQuestion.ID, questionKeyboard(ctx, stateManager, kb, msg.UserID, nextQuestion.ID, nextQuestion.AnswerType, hasPrevious))
 
 	return true, nil
 }
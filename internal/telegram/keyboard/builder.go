@@ -2,18 +2,35 @@ package keyboard
 
 import (
 	"fmt"
+	"strconv"
+	"time"
 
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/pkg/formatter"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
 
 // Builder creates inline keyboards
-type Builder struct{}
+type Builder struct {
+	questionButtons QuestionButtons
+}
 
 // NewBuilder creates a keyboard builder
 func NewBuilder() *Builder {
 	return &Builder{}
 }
 
+// WithQuestionButtons sets the composition of the question keyboard; without it every button is shown
+func (b *Builder) WithQuestionButtons(buttons QuestionButtons) *Builder {
+	b.questionButtons = buttons
+	return b
+}
+
+// QuestionButtons returns the composition of the question keyboard
+func (b *Builder) QuestionButtons() QuestionButtons {
+	return b.questionButtons
+}
+
 // StartKeyboard creates the initial start button
 func (b *Builder) StartKeyboard() tgbotapi.InlineKeyboardMarkup {
 	return tgbotapi.NewInlineKeyboardMarkup(
@@ -23,13 +40,34 @@ func (b *Builder) StartKeyboard() tgbotapi.InlineKeyboardMarkup {
 	)
 }
 
-// ModeSelectionKeyboard creates Interview/Draft selection buttons
+// DemoSessionKeyboard creates the button that starts a sandbox demo session
+func (b *Builder) DemoSessionKeyboard() tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🧪 Начать демо-сессию", "action:start_demo"),
+		),
+	)
+}
+
+// GoalClarificationKeyboard lets the user keep a vague goal without clarifying it
+func (b *Builder) GoalClarificationKeyboard() tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("⏭ Продолжить как есть", "action:skip_goal_clarification"),
+		),
+	)
+}
+
+// ModeSelectionKeyboard creates Interview/Draft/Delta selection buttons
 func (b *Builder) ModeSelectionKeyboard() tgbotapi.InlineKeyboardMarkup {
 	return tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(
 			tgbotapi.NewInlineKeyboardButtonData("📝 Интервью", "mode:interview"),
 			tgbotapi.NewInlineKeyboardButtonData("📄 Драфт", "mode:draft"),
 		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🔁 Что изменилось", "mode:delta"),
+		),
 		tgbotapi.NewInlineKeyboardRow(
 			tgbotapi.NewInlineKeyboardButtonData("🔄 Сменить проект", "action:change_project"),
 		),
@@ -65,16 +103,26 @@ func (b *Builder) ProjectSelectionKeyboard(projects []Project) tgbotapi.InlineKe
 }
 
 // ProjectSelectionKeyboardWithPagination creates project selection buttons with pagination
-func (b *Builder) ProjectSelectionKeyboardWithPagination(projects []Project, hasPrev, hasNext bool) tgbotapi.InlineKeyboardMarkup {
+func (b *Builder) ProjectSelectionKeyboardWithPagination(projects []Project, pagination Pagination) tgbotapi.InlineKeyboardMarkup {
 	rows := [][]tgbotapi.InlineKeyboardButton{}
 
-	// Add project buttons
+	// Add project buttons, each with a toggle that pins the project on top of every page
+	now := time.Now()
 	for _, proj := range projects {
+		pin := "☆"
+		if proj.Pinned {
+			pin = "⭐️"
+		}
+		title := proj.Title
+		if proj.Stale {
+			title = "⚠️ " + title
+		}
 		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
 			tgbotapi.NewInlineKeyboardButtonData(
-				proj.Title,
+				title+" "+formatter.ProjectUsage(proj.SessionCount, proj.LastUsedAt, now),
 				"proj:"+proj.ID,
 			),
+			tgbotapi.NewInlineKeyboardButtonData(pin, "pin:"+proj.ID),
 		))
 	}
 
@@ -84,50 +132,108 @@ func (b *Builder) ProjectSelectionKeyboardWithPagination(projects []Project, has
 	))
 
 	// Add pagination buttons if needed
-	if hasPrev || hasNext {
-		navRow := []tgbotapi.InlineKeyboardButton{}
-		if hasPrev {
-			navRow = append(navRow,
-				tgbotapi.NewInlineKeyboardButtonData("◀️ Назад", "page:prev"))
-		}
-		if hasNext {
-			navRow = append(navRow,
-				tgbotapi.NewInlineKeyboardButtonData("Вперёд ▶️", "page:next"))
-		}
+	if navRow := paginationRow(pagination, "page"); navRow != nil {
 		rows = append(rows, navRow)
 	}
 
 	return tgbotapi.InlineKeyboardMarkup{InlineKeyboard: rows}
 }
 
-// QuestionNavigationKeyboard creates question navigation buttons
-func (b *Builder) QuestionNavigationKeyboard(questionID string, hasPrevious bool) tgbotapi.InlineKeyboardMarkup {
-	rows := [][]tgbotapi.InlineKeyboardButton{
+// SkipReasonKeyboard offers quick reasons for a skipped question
+func (b *Builder) SkipReasonKeyboard(questionID string) tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🤷 Не знаю", "skipwhy:"+questionID+":DONT_KNOW"),
+			tgbotapi.NewInlineKeyboardButtonData("🚫 Не релевантно", "skipwhy:"+questionID+":NOT_RELEVANT"),
+		),
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("⏭ Пропустить", "skip:"+questionID),
-			tgbotapi.NewInlineKeyboardButtonData("❓ Поясни вопрос", "explain:"+questionID),
+			tgbotapi.NewInlineKeyboardButtonData("⏳ Отвечу позже", "skipwhy:"+questionID+":ANSWER_LATER"),
+		),
+	)
+}
+
+// QuestionNavigationKeyboard creates question navigation buttons; scale questions get a row of
+// quick rating buttons answering the question with the chosen value. Buttons turned off for the
+// session type are left out and rows left empty are dropped
+func (b *Builder) QuestionNavigationKeyboard(
+	sessionType entity.SessionType,
+	questionID string,
+	answerType entity.QuestionAnswerType,
+	hasPrevious bool,
+) tgbotapi.InlineKeyboardMarkup {
+	enabled := func(button QuestionButton) bool {
+		return b.questionButtons.Enabled(sessionType, button)
+	}
+
+	// Not nil, Telegram rejects a null keyboard when every button is turned off
+	rows := [][]tgbotapi.InlineKeyboardButton{}
+	if answerType == entity.QuestionAnswerTypeScale && enabled(QuestionButtonScale) {
+		scale := make([]tgbotapi.InlineKeyboardButton, 0, entity.ScaleMax-entity.ScaleMin+1)
+		for value := entity.ScaleMin; value <= entity.ScaleMax; value++ {
+			scale = append(scale, tgbotapi.NewInlineKeyboardButtonData(
+				strconv.Itoa(value),
+				fmt.Sprintf("scale:%s:%d", questionID, value),
+			))
+		}
+		rows = append(rows, scale)
+	}
+
+	// Each row keeps its enabled buttons
+	row := func(buttons ...questionButton) []tgbotapi.InlineKeyboardButton {
+		var kept []tgbotapi.InlineKeyboardButton
+		for _, button := range buttons {
+			if enabled(button.name) {
+				kept = append(kept, tgbotapi.NewInlineKeyboardButtonData(button.text, button.data))
+			}
+		}
+		return kept
+	}
+	candidates := [][]tgbotapi.InlineKeyboardButton{
+		row(
+			questionButton{QuestionButtonSkip, "⏭ Пропустить", "skip:" + questionID},
+			questionButton{QuestionButtonExplain, "❓ Поясни вопрос", "explain:" + questionID},
+		),
+		row(
+			questionButton{QuestionButtonDefer, "⏰ Спросить позже", "defer:" + questionID},
+			questionButton{QuestionButtonAnswers, "📝 Мои ответы", "action:review_answers"},
 		),
 	}
 
 	// Add back button if there are previous questions
 	if hasPrevious {
-		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("◀️ Предыдущий вопрос", "prev:"+questionID),
+		candidates = append(candidates, row(
+			questionButton{QuestionButtonPrevious, "◀️ Предыдущий вопрос", "prev:" + questionID},
 		))
 	}
 
-	rows = append(rows,
-		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("✅ Сформировать требования", "action:generate"),
+	candidates = append(candidates,
+		row(
+			questionButton{QuestionButtonSearch, "🔎 Найти в материалах", "action:search"},
+			questionButton{QuestionButtonScript, "📄 Сценарий интервью", "action:export_questions"},
 		),
-		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("🛑 Завершить диалог", "action:finish"),
+		row(
+			questionButton{QuestionButtonGenerate, "✅ Сформировать требования", "action:generate"},
+		),
+		row(
+			questionButton{QuestionButtonFinish, "🛑 Завершить диалог", "action:finish"},
 		),
 	)
+	for _, candidate := range candidates {
+		if len(candidate) > 0 {
+			rows = append(rows, candidate)
+		}
+	}
 
 	return tgbotapi.InlineKeyboardMarkup{InlineKeyboard: rows}
 }
 
+// questionButton is a button of the question keyboard with its name in QuestionButtons
+type questionButton struct {
+	name QuestionButton
+	text string
+	data string
+}
+
 // InterviewInfoKeyboard creates interview info confirmation buttons
 func (b *Builder) InterviewInfoKeyboard() tgbotapi.InlineKeyboardMarkup {
 	return tgbotapi.NewInlineKeyboardMarkup(
@@ -155,6 +261,10 @@ func (b *Builder) DraftInfoKeyboard() tgbotapi.InlineKeyboardMarkup {
 // DraftCollectionKeyboard creates draft collection control buttons
 func (b *Builder) DraftCollectionKeyboard() tgbotapi.InlineKeyboardMarkup {
 	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🔎 Найти в материалах", "action:search"),
+			tgbotapi.NewInlineKeyboardButtonData("🔍 Проверить материалы", "action:check_draft"),
+		),
 		tgbotapi.NewInlineKeyboardRow(
 			tgbotapi.NewInlineKeyboardButtonData("✅ Сформировать требования", "action:generate"),
 		),
@@ -164,6 +274,77 @@ func (b *Builder) DraftCollectionKeyboard() tgbotapi.InlineKeyboardMarkup {
 	)
 }
 
+// AnswerReviewKeyboard creates a button per listed answer, numbered from offset+1, and buttons
+// to the neighbouring pages of the list
+func (b *Builder) AnswerReviewKeyboard(questionIDs []string, offset, page, pages int) tgbotapi.InlineKeyboardMarkup {
+	const perRow = 5
+
+	var rows [][]tgbotapi.InlineKeyboardButton
+	var row []tgbotapi.InlineKeyboardButton
+	for i, id := range questionIDs {
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(strconv.Itoa(offset+i+1), "edit:"+id))
+		if len(row) == perRow {
+			rows = append(rows, row)
+			row = nil
+		}
+	}
+	if len(row) > 0 {
+		rows = append(rows, row)
+	}
+
+	var navRow []tgbotapi.InlineKeyboardButton
+	if page > 0 {
+		navRow = append(navRow, tgbotapi.NewInlineKeyboardButtonData("◀️ Назад", "answers:"+strconv.Itoa(page-1)))
+	}
+	if page < pages-1 {
+		navRow = append(navRow, tgbotapi.NewInlineKeyboardButtonData("Вперёд ▶️", "answers:"+strconv.Itoa(page+1)))
+	}
+	if len(navRow) > 0 {
+		rows = append(rows, navRow)
+	}
+
+	return tgbotapi.InlineKeyboardMarkup{InlineKeyboard: rows}
+}
+
+// EditAnswerKeyboard creates a cancel button while waiting for an edited answer
+func (b *Builder) EditAnswerKeyboard() tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("❌ Отмена", "action:edit_cancel"),
+		),
+	)
+}
+
+// documentLanguageLabels are the buttons choosing the language of the document
+var documentLanguageLabels = map[entity.ResultLanguage]string{
+	entity.LanguageRussian: "🇷🇺 На русском",
+	entity.LanguageEnglish: "🇬🇧 На английском",
+	entity.LanguageGerman:  "🇩🇪 На немецком",
+	entity.LanguageFrench:  "🇫🇷 На французском",
+	entity.LanguageSpanish: "🇪🇸 На испанском",
+	entity.LanguageChinese: "🇨🇳 На китайском",
+}
+
+// DocumentLanguageKeyboard offers to keep the language of the document or to switch to the
+// language the user now answers in
+func (b *Builder) DocumentLanguageKeyboard(current, detected entity.ResultLanguage) tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(documentLanguageLabels[current], "doclang:"+string(current)),
+			tgbotapi.NewInlineKeyboardButtonData(documentLanguageLabels[detected], "doclang:"+string(detected)),
+		),
+	)
+}
+
+// SearchPromptKeyboard creates a cancel button while waiting for a search query
+func (b *Builder) SearchPromptKeyboard() tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("❌ Отмена", "action:search_cancel"),
+		),
+	)
+}
+
 // ResultSaveKeyboard creates result save and download buttons
 func (b *Builder) ResultSaveKeyboard(hasSkipped bool, projectTitle string) tgbotapi.InlineKeyboardMarkup {
 	rows := [][]tgbotapi.InlineKeyboardButton{
@@ -180,10 +361,37 @@ func (b *Builder) ResultSaveKeyboard(hasSkipped bool, projectTitle string) tgbot
 	}
 
 	// Download buttons
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("👁 Предпросмотр", "preview:0"),
+	))
 	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
 		tgbotapi.NewInlineKeyboardButtonData("📄 Скачать .md", "dl:markdown"),
 		tgbotapi.NewInlineKeyboardButtonData("📕 Скачать .pdf", "dl:pdf"),
 	))
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("⬇️ Скачать всё", "action:download_all"),
+	))
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("🌐 Перевести", "action:translate"),
+	))
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("♻️ Перегенерировать раздел", "action:regen_section"),
+	))
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("🔁 Доработать", "action:feedback"),
+	))
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("🎨 Стиль документа", "action:summary_style"),
+	))
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("💬 Комментарии", "action:comments"),
+	))
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("🔀 Сравнить с предыдущей версией", "action:diff_previous"),
+	))
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("🗂 Версии документа", "action:versions"),
+	))
 
 	if hasSkipped {
 		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
@@ -206,10 +414,37 @@ func (b *Builder) ResultDownloadKeyboard(hasSkipped bool) tgbotapi.InlineKeyboar
 // ResultDownloadOnlyKeyboard creates download buttons without save options (after project is already saved)
 func (b *Builder) ResultDownloadOnlyKeyboard(hasSkipped bool) tgbotapi.InlineKeyboardMarkup {
 	rows := [][]tgbotapi.InlineKeyboardButton{
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("👁 Предпросмотр", "preview:0"),
+		),
 		tgbotapi.NewInlineKeyboardRow(
 			tgbotapi.NewInlineKeyboardButtonData("📄 Скачать .md", "dl:markdown"),
 			tgbotapi.NewInlineKeyboardButtonData("📕 Скачать .pdf", "dl:pdf"),
 		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("⬇️ Скачать всё", "action:download_all"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🌐 Перевести", "action:translate"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("♻️ Перегенерировать раздел", "action:regen_section"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🔁 Доработать", "action:feedback"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🎨 Стиль документа", "action:summary_style"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("💬 Комментарии", "action:comments"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🔀 Сравнить с предыдущей версией", "action:diff_previous"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🗂 Версии документа", "action:versions"),
+		),
 	}
 
 	if hasSkipped {
@@ -225,8 +460,411 @@ func (b *Builder) ResultDownloadOnlyKeyboard(hasSkipped bool) tgbotapi.InlineKey
 	return tgbotapi.InlineKeyboardMarkup{InlineKeyboard: rows}
 }
 
+// SectionSelectionKeyboard creates one button per result section
+func (b *Builder) SectionSelectionKeyboard(titles []string) tgbotapi.InlineKeyboardMarkup {
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0, len(titles)+1)
+	for i, title := range titles {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("%d. %s", i+1, title), fmt.Sprintf("section:%d", i)),
+		))
+	}
+
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("❌ Отмена", "section:cancel"),
+	))
+
+	return tgbotapi.InlineKeyboardMarkup{InlineKeyboard: rows}
+}
+
+// ResultVersion is a stored result version offered for download
+type ResultVersion struct {
+	Number    int
+	CreatedAt time.Time // in the timezone of the user
+	Current   bool
+}
+
+// ResultVersionsKeyboard creates one button per result version, newest first
+func (b *Builder) ResultVersionsKeyboard(versions []ResultVersion) tgbotapi.InlineKeyboardMarkup {
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0, len(versions))
+	for _, v := range versions {
+		label := fmt.Sprintf("v%d · %s", v.Number, v.CreatedAt.Format("02.01 15:04"))
+		if v.Current {
+			label += " (текущая)"
+		}
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(label, fmt.Sprintf("ver:%d", v.Number)),
+		))
+	}
+
+	return tgbotapi.InlineKeyboardMarkup{InlineKeyboard: rows}
+}
+
+// ResultVersionDownloadKeyboard creates download buttons for a result version
+func (b *Builder) ResultVersionDownloadKeyboard(number int) tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("📄 Скачать .md", fmt.Sprintf("ver:%d:markdown", number)),
+			tgbotapi.NewInlineKeyboardButtonData("📕 Скачать .pdf", fmt.Sprintf("ver:%d:pdf", number)),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🗂 Другая версия", "action:versions"),
+		),
+	)
+}
+
+// CommentsKeyboard creates a resolve button per open review comment
+func (b *Builder) CommentsKeyboard(commentIDs []string) tgbotapi.InlineKeyboardMarkup {
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0, len(commentIDs))
+	for i, id := range commentIDs {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf("✅ Закрыть #%d", i+1), "comment:"+id),
+		))
+	}
+
+	return tgbotapi.InlineKeyboardMarkup{InlineKeyboard: rows}
+}
+
+// ConflictResolutionKeyboard creates keep-new and keep-previous buttons for a requirements conflict
+func (b *Builder) ConflictResolutionKeyboard(conflictID string) tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🆕 Оставить новое", "conflict:keep_new:"+conflictID),
+			tgbotapi.NewInlineKeyboardButtonData("↩️ Оставить прежнее", "conflict:keep_previous:"+conflictID),
+		),
+	)
+}
+
+// InboxRetryKeyboard creates a button sending a kept text again after a failed submission
+func (b *Builder) InboxRetryKeyboard(inboxID string) tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🔁 Отправить ещё раз", "inbox:"+inboxID),
+		),
+	)
+}
+
+// ReviewDecisionKeyboard creates approve and reject buttons for an approver
+func (b *Builder) ReviewDecisionKeyboard(sessionID string) tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("✅ Согласовать", "review:approve:"+sessionID),
+			tgbotapi.NewInlineKeyboardButtonData("❌ Отклонить", "review:reject:"+sessionID),
+		),
+	)
+}
+
+// ScheduledSessionKeyboard creates a button starting a scheduled check-in session
+func (b *Builder) ScheduledSessionKeyboard(sessionID string) tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("▶️ Начать", "scheduled:"+sessionID),
+		),
+	)
+}
+
+// SectionGuidanceKeyboard creates buttons for regenerating a section without guidance
+func (b *Builder) SectionGuidanceKeyboard() tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("✨ Без пожеланий", "section:go"),
+			tgbotapi.NewInlineKeyboardButtonData("❌ Отмена", "section:cancel"),
+		),
+	)
+}
+
+// FeedbackKeyboard creates the cancel button of the result feedback prompt
+func (b *Builder) FeedbackKeyboard() tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("❌ Отмена", "action:feedback_cancel"),
+		),
+	)
+}
+
+// GenerationConfirmKeyboard creates confirmation buttons for generating a large session
+func (b *Builder) GenerationConfirmKeyboard() tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("✅ Да, сформировать", "confirm:generate"),
+			tgbotapi.NewInlineKeyboardButtonData("❌ Нет, продолжить", "confirm:continue"),
+		),
+	)
+}
+
+// ChangeProjectConfirmKeyboard creates confirmation buttons for changing the project of a session with answers
+func (b *Builder) ChangeProjectConfirmKeyboard() tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🔄 Да, сменить проект", "confirm:change_project"),
+			tgbotapi.NewInlineKeyboardButtonData("❌ Нет, продолжить", "confirm:continue"),
+		),
+	)
+}
+
+// TimeBudgetKeyboard offers to wrap up an interview that is running out of its time budget
+func (b *Builder) TimeBudgetKeyboard() tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("⏩ Пропустить остальное и сформировать", "action:timebox_generate"),
+		),
+	)
+}
+
+// GenerationApprovalKeyboard creates a retry button for sessions awaiting admin approval
+func (b *Builder) GenerationApprovalKeyboard() tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🔄 Проверить снова", "action:generate"),
+		),
+	)
+}
+
+// PartialResultKeyboard creates the buttons of the collected materials saved instead of the requirements:
+// downloads and a new generation attempt
+func (b *Builder) PartialResultKeyboard(hasSkipped bool) tgbotapi.InlineKeyboardMarkup {
+	rows := [][]tgbotapi.InlineKeyboardButton{
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("👁 Предпросмотр", "preview:0"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("📄 Скачать .md", "dl:markdown"),
+			tgbotapi.NewInlineKeyboardButtonData("📕 Скачать .pdf", "dl:pdf"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🔁 Сформировать требования заново", "action:generate"),
+		),
+	}
+
+	if hasSkipped {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("📝 Ответить на пропущенные", "action:answer_skipped"),
+		))
+	}
+
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("✅ Завершить диалог", "action:finish"),
+	))
+
+	return tgbotapi.InlineKeyboardMarkup{InlineKeyboard: rows}
+}
+
+// LanguageSelectionKeyboard creates target language buttons for result translation
+func (b *Builder) LanguageSelectionKeyboard() tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🇬🇧 English", "lang:en"),
+			tgbotapi.NewInlineKeyboardButtonData("🇩🇪 Deutsch", "lang:de"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🇫🇷 Français", "lang:fr"),
+			tgbotapi.NewInlineKeyboardButtonData("🇪🇸 Español", "lang:es"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🇨🇳 中文", "lang:zh"),
+			tgbotapi.NewInlineKeyboardButtonData("🇷🇺 Русский", "lang:ru"),
+		),
+	)
+}
+
+// TranslatedDownloadKeyboard creates download buttons for a translated result
+func (b *Builder) TranslatedDownloadKeyboard(language string) tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("📄 Скачать .md", "dl:markdown:"+language),
+			tgbotapi.NewInlineKeyboardButtonData("📕 Скачать .pdf", "dl:pdf:"+language),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🌐 Другой язык", "action:translate"),
+		),
+	)
+}
+
 // Project represents a project for keyboard building
 type Project struct {
-	ID    string
-	Title string
+	ID           string
+	Title        string
+	SessionCount int
+	LastUsedAt   *time.Time
+	Stale        bool // the materials of the project were last indexed too long ago
+	Pinned       bool
+}
+
+// SettingsKeyboard creates the /settings menu
+func (b *Builder) SettingsKeyboard() tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("⭐️ Избранные проекты", "settings:pins"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🕒 Часовой пояс", "settings:tz"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🎨 Стиль документа", "settings:style"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("📊 Прогресс над вопросами", "settings:progress"),
+		),
+	)
+}
+
+// summaryStyleChoices are the document styles in the order they are offered
+var summaryStyleChoices = []struct {
+	title string
+	style entity.SummaryStyle
+}{
+	{"📜 Формальный (ГОСТ)", entity.SummaryStyleFormal},
+	{"📊 Краткая сводка", entity.SummaryStyleExecutive},
+	{"🛠 Для разработчиков", entity.SummaryStyleDeveloper},
+}
+
+// summaryStyleRows creates a button per document style, the current one marked
+func summaryStyleRows(current entity.SummaryStyle, prefix string) [][]tgbotapi.InlineKeyboardButton {
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0, len(summaryStyleChoices)+1)
+	for _, choice := range summaryStyleChoices {
+		title := choice.title
+		if choice.style == current {
+			title = "✅ " + title
+		}
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(title, prefix+string(choice.style)),
+		))
+	}
+	return rows
+}
+
+// SummaryStyleKeyboard offers the styles a generated document can be rewritten in
+func (b *Builder) SummaryStyleKeyboard(current entity.SummaryStyle) tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.InlineKeyboardMarkup{InlineKeyboard: summaryStyleRows(current, "style:")}
+}
+
+// SettingsSummaryStyleKeyboard offers the style of the next documents in the settings
+func (b *Builder) SettingsSummaryStyleKeyboard(current entity.SummaryStyle) tgbotapi.InlineKeyboardMarkup {
+	rows := summaryStyleRows(current, "settings:style:")
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("◀️ Назад", "settings:menu"),
+	))
+	return tgbotapi.InlineKeyboardMarkup{InlineKeyboard: rows}
+}
+
+// timezoneChoices are the timezones offered by TimezoneKeyboard; others are set with /timezone <name>
+var timezoneChoices = []struct {
+	title    string
+	timezone string
+}{
+	{"Калининград", "Europe/Kaliningrad"},
+	{"Москва", "Europe/Moscow"},
+	{"Самара", "Europe/Samara"},
+	{"Екатеринбург", "Asia/Yekaterinburg"},
+	{"Омск", "Asia/Omsk"},
+	{"Новосибирск", "Asia/Novosibirsk"},
+	{"Иркутск", "Asia/Irkutsk"},
+	{"Якутск", "Asia/Yakutsk"},
+	{"Владивосток", "Asia/Vladivostok"},
+	{"Магадан", "Asia/Magadan"},
+	{"Камчатка", "Asia/Kamchatka"},
+	{"UTC", "UTC"},
+}
+
+// TimezoneKeyboard lists the common timezones, marking the current one
+func (b *Builder) TimezoneKeyboard(current string) tgbotapi.InlineKeyboardMarkup {
+	rows := [][]tgbotapi.InlineKeyboardButton{}
+	var row []tgbotapi.InlineKeyboardButton
+	for _, choice := range timezoneChoices {
+		title := choice.title
+		if choice.timezone == current {
+			title = "✅ " + title
+		}
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(title, "settings:tz:"+choice.timezone))
+		if len(row) == 2 {
+			rows = append(rows, row)
+			row = nil
+		}
+	}
+	if len(row) > 0 {
+		rows = append(rows, row)
+	}
+
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("◀️ Назад", "settings:menu"),
+	))
+
+	return tgbotapi.InlineKeyboardMarkup{InlineKeyboard: rows}
+}
+
+// PinnedProjectsKeyboard lists pinned projects with buttons that unpin them
+func (b *Builder) PinnedProjectsKeyboard(projects []Project) tgbotapi.InlineKeyboardMarkup {
+	rows := [][]tgbotapi.InlineKeyboardButton{}
+	for _, proj := range projects {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("✖️ "+proj.Title, "settings:unpin:"+proj.ID),
+		))
+	}
+
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("◀️ Назад", "settings:menu"),
+	))
+
+	return tgbotapi.InlineKeyboardMarkup{InlineKeyboard: rows}
+}
+
+// ResultPreviewKeyboard creates the button showing the next page of the result preview
+func (b *Builder) ResultPreviewKeyboard(page, total int) tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(
+				fmt.Sprintf("Дальше ▶️ (%d/%d)", page+2, total),
+				fmt.Sprintf("preview:%d", page+1),
+			),
+		),
+	)
+}
+
+// TutorialKeyboard creates onboarding tutorial pagination; the last page also offers to start a session
+func (b *Builder) TutorialKeyboard(page, total int) tgbotapi.InlineKeyboardMarkup {
+	var nav []tgbotapi.InlineKeyboardButton
+	if page > 0 {
+		nav = append(nav, tgbotapi.NewInlineKeyboardButtonData("◀️ Назад", fmt.Sprintf("tutorial:%d", page-1)))
+	}
+	if page < total-1 {
+		nav = append(nav, tgbotapi.NewInlineKeyboardButtonData("Далее ▶️", fmt.Sprintf("tutorial:%d", page+1)))
+	}
+
+	rows := [][]tgbotapi.InlineKeyboardButton{}
+	if len(nav) > 0 {
+		rows = append(rows, nav)
+	}
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData("🎮 Попробовать демо", "demo:start"),
+	))
+	if page == total-1 {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🚀 Начать сессию", "action:start"),
+		))
+	}
+
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// DemoQuestionKeyboard creates the button answering a demo question with the prepared answer
+func (b *Builder) DemoQuestionKeyboard(index int) tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("✍️ Ответить примером", fmt.Sprintf("demo:answer:%d", index)),
+		),
+	)
+}
+
+// DemoFinishedKeyboard creates buttons shown after the demo interview
+func (b *Builder) DemoFinishedKeyboard() tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🚀 Начать сессию", "action:start"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("🧪 Демо-сессия на своей цели", "action:start_demo"),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData("📖 Вернуться к обучению", "tutorial:0"),
+		),
+	)
 }
@@ -0,0 +1,55 @@
+package keyboard
+
+import (
+	"fmt"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// Pagination is the position of a page in a paged list
+type Pagination struct {
+	Page  int // zero-based
+	Pages int
+}
+
+// NewPagination returns the position of the zero-based page of a list of total items; an
+// empty list still has one page
+func NewPagination(page, total, pageSize int) Pagination {
+	return Pagination{
+		Page:  page,
+		Pages: max((total+pageSize-1)/pageSize, 1),
+	}
+}
+
+// HasPrev reports whether there is a page before the current one
+func (p Pagination) HasPrev() bool {
+	return p.Page > 0
+}
+
+// HasNext reports whether there is a page after the current one
+func (p Pagination) HasNext() bool {
+	return p.Page < p.Pages-1
+}
+
+// paginationRow creates the navigation row of a paged list: back, the "стр. 3/12" indicator and
+// forward, with the callback data prefix:prev, prefix:jump and prefix:next. Tapping the indicator
+// asks for the number of a page to jump to. A list of a single page has no row
+func paginationRow(p Pagination, prefix string) []tgbotapi.InlineKeyboardButton {
+	if p.Pages <= 1 {
+		return nil
+	}
+
+	row := []tgbotapi.InlineKeyboardButton{}
+	if p.HasPrev() {
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData("◀️ Назад", prefix+":prev"))
+	}
+	row = append(row, tgbotapi.NewInlineKeyboardButtonData(
+		fmt.Sprintf("стр. %d/%d", p.Page+1, p.Pages),
+		prefix+":jump",
+	))
+	if p.HasNext() {
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData("Вперёд ▶️", prefix+":next"))
+	}
+
+	return row
+}
@@ -0,0 +1,141 @@
+package keyboard
+
+import (
+	"fmt"
+
+	"github.com/futig/agent-backend/internal/entity"
+)
+
+// QuestionButton names a button of the question navigation keyboard
+type QuestionButton string
+
+const (
+	QuestionButtonScale    QuestionButton = "scale"    // quick rating row of scale questions
+	QuestionButtonSkip     QuestionButton = "skip"     // skip the question
+	QuestionButtonExplain  QuestionButton = "explain"  // explain the question
+	QuestionButtonDefer    QuestionButton = "defer"    // ask the question after the last block
+	QuestionButtonAnswers  QuestionButton = "answers"  // list the given answers
+	QuestionButtonPrevious QuestionButton = "previous" // go back one question
+	QuestionButtonSearch   QuestionButton = "search"   // search the collected materials
+	QuestionButtonScript   QuestionButton = "script"   // export the interview script
+	QuestionButtonGenerate QuestionButton = "generate" // generate requirements before all questions are answered
+	QuestionButtonFinish   QuestionButton = "finish"   // finish the session
+)
+
+// KnownQuestionButtons lists the buttons that can be turned off
+var KnownQuestionButtons = []QuestionButton{
+	QuestionButtonScale,
+	QuestionButtonSkip,
+	QuestionButtonExplain,
+	QuestionButtonDefer,
+	QuestionButtonAnswers,
+	QuestionButtonPrevious,
+	QuestionButtonSearch,
+	QuestionButtonScript,
+	QuestionButtonGenerate,
+	QuestionButtonFinish,
+}
+
+// QuestionButtons is the composition of the question keyboard: the buttons turned off for every
+// session type and the own sets of the session types that replace them
+type QuestionButtons struct {
+	disabled map[QuestionButton]bool
+	byType   map[entity.SessionType]map[QuestionButton]bool
+}
+
+// NewQuestionButtons builds the composition from button names; byType maps session types to the
+// buttons turned off in their sessions instead of disabled
+func NewQuestionButtons(disabled []string, byType map[string][]string) (QuestionButtons, error) {
+	buttons := QuestionButtons{byType: make(map[entity.SessionType]map[QuestionButton]bool, len(byType))}
+
+	var err error
+	if buttons.disabled, err = questionButtonSet(disabled); err != nil {
+		return QuestionButtons{}, err
+	}
+	for name, names := range byType {
+		sessionType := entity.SessionType(name)
+		if err := sessionType.Validate(); err != nil {
+			return QuestionButtons{}, err
+		}
+		if buttons.byType[sessionType], err = questionButtonSet(names); err != nil {
+			return QuestionButtons{}, fmt.Errorf("%s: %w", name, err)
+		}
+	}
+	return buttons, nil
+}
+
+// questionButtonSet converts button names to a set, rejecting unknown names
+func questionButtonSet(names []string) (map[QuestionButton]bool, error) {
+	set := make(map[QuestionButton]bool, len(names))
+	for _, name := range names {
+		button := QuestionButton(name)
+		if !isKnownQuestionButton(button) {
+			return nil, fmt.Errorf("unknown question button %q", name)
+		}
+		set[button] = true
+	}
+	return set, nil
+}
+
+// isKnownQuestionButton reports whether button is one of KnownQuestionButtons
+func isKnownQuestionButton(button QuestionButton) bool {
+	for _, known := range KnownQuestionButtons {
+		if known == button {
+			return true
+		}
+	}
+	return false
+}
+
+// Enabled reports whether the button is shown in the questions of sessions of the type;
+// an unknown or empty type uses the buttons of every session type
+func (q QuestionButtons) Enabled(sessionType entity.SessionType, button QuestionButton) bool {
+	if disabled, ok := q.byType[sessionType]; ok {
+		return !disabled[button]
+	}
+	return !q.disabled[button]
+}
+
+// AllEnabled reports whether no session type has a button turned off
+func (q QuestionButtons) AllEnabled() bool {
+	if len(q.disabled) > 0 {
+		return false
+	}
+	for _, disabled := range q.byType {
+		if len(disabled) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// CallbackQuestionButton returns the question button that sends the callback. Only reports whether
+// no other keyboard sends it; the buttons of general actions are shared with other keyboards
+func CallbackQuestionButton(data *CallbackData) (button QuestionButton, only bool, ok bool) {
+	switch data.Action {
+	case "scale":
+		return QuestionButtonScale, true, true
+	case "skip":
+		return QuestionButtonSkip, true, true
+	case "explain":
+		return QuestionButtonExplain, true, true
+	case "defer":
+		return QuestionButtonDefer, true, true
+	case "prev":
+		return QuestionButtonPrevious, true, true
+	case "action":
+		switch data.Value {
+		case "review_answers":
+			return QuestionButtonAnswers, true, true
+		case "export_questions":
+			return QuestionButtonScript, true, true
+		case "search":
+			return QuestionButtonSearch, false, true
+		case "generate":
+			return QuestionButtonGenerate, false, true
+		case "finish":
+			return QuestionButtonFinish, false, true
+		}
+	}
+	return "", false, false
+}
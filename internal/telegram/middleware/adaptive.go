@@ -0,0 +1,172 @@
+package middleware
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"github.com/futig/agent-backend/internal/config"
+	"github.com/futig/agent-backend/internal/telegram/store"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"go.uber.org/zap"
+)
+
+const (
+	// reputationGain is added to the reputation of a user for every allowed update
+	reputationGain = 0.01
+	// reputationPenalty is taken from the reputation of a user for every rejected update
+	reputationPenalty = 0.1
+	// minHealth bounds the health factor, so LLM work costs at most five times its weight
+	minHealth = 0.2
+)
+
+var _ RateLimiter = &AdaptiveRateLimiterMiddleware{}
+
+// AdaptiveRateLimiterMiddleware is a token bucket per user where updates cost tokens by their weight.
+// The bucket size and refill rate of a user follow the user's reputation, which grows with allowed
+// updates and drops with rejected ones, and updates heavier than one token cost more while the
+// service is unhealthy
+type AdaptiveRateLimiterMiddleware struct {
+	store           store.Store
+	maxTokens       float64 // bucket size at reputation 1
+	refillRate      float64 // tokens added per second at reputation 1
+	cfg             config.TelegramRateLimitConfig
+	warningInterval time.Duration
+	cost            func(update tgbotapi.Update) float64
+	health          func() float64          // 1 when healthy, down to 0 when every call fails; nil is always healthy
+	exempt          func(userID int64) bool // consulted only once the bucket is empty; nil exempts nobody
+	logger          *zap.Logger
+	api             *tgbotapi.BotAPI
+}
+
+// NewAdaptiveRateLimiterMiddleware creates a new adaptive rate limiter middleware
+func NewAdaptiveRateLimiterMiddleware(
+	requestsPerMinute int,
+	cfg config.TelegramRateLimitConfig,
+	store store.Store,
+	cost func(update tgbotapi.Update) float64,
+	health func() float64,
+	exempt func(userID int64) bool,
+	logger *zap.Logger,
+	api *tgbotapi.BotAPI,
+) *AdaptiveRateLimiterMiddleware {
+	return &AdaptiveRateLimiterMiddleware{
+		store:           store,
+		maxTokens:       float64(requestsPerMinute),
+		refillRate:      float64(requestsPerMinute) / 60.0,
+		cfg:             cfg,
+		warningInterval: 30 * time.Second,
+		cost:            cost,
+		health:          health,
+		exempt:          exempt,
+		logger:          logger,
+		api:             api,
+	}
+}
+
+// Handle processes the update through rate limiting
+func (rl *AdaptiveRateLimiterMiddleware) Handle(update tgbotapi.Update, next func(tgbotapi.Update)) {
+	userID, chatID, mediaGroupID, ok := updateSender(update)
+	if !ok {
+		// Unknown update type, allow it
+		next(update)
+		return
+	}
+
+	if !rl.allowRequest(userID, chatID, mediaGroupID, rl.updateCost(update)) {
+		return
+	}
+
+	next(update)
+}
+
+// updateCost returns the tokens the update costs: its weight, raised for heavy updates while the
+// service is unhealthy, so navigation keeps working when LLM calls time out
+func (rl *AdaptiveRateLimiterMiddleware) updateCost(update tgbotapi.Update) float64 {
+	cost := 1.0
+	if rl.cost != nil {
+		cost = rl.cost(update)
+	}
+	if cost <= 1 || rl.health == nil {
+		return cost
+	}
+	return cost / math.Max(rl.health(), minHealth)
+}
+
+// allowRequest checks if the update is allowed under the user's limit; all items of an album count as one update.
+// A failing store allows the update
+func (rl *AdaptiveRateLimiterMiddleware) allowRequest(userID, chatID int64, mediaGroupID string, cost float64) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), storeTimeout)
+	defer cancel()
+
+	var allowed, warn bool
+	var limit store.Bucket
+	err := rl.store.UpdateBucket(ctx, limitKey(rl.api, userID), limitTTL, func(bucket *store.Bucket) {
+		allowed, warn = false, false
+		now := time.Now()
+
+		if bucket.LastRefill.IsZero() {
+			bucket.Tokens = rl.maxTokens
+			bucket.Reputation = 1
+			bucket.LastRefill = now
+		}
+		// Buckets of the simple limiter have no reputation
+		if bucket.Reputation == 0 {
+			bucket.Reputation = 1
+		}
+
+		if mediaGroupID != "" && mediaGroupID == bucket.MediaGroupID {
+			allowed = true
+			return
+		}
+
+		elapsed := now.Sub(bucket.LastRefill).Seconds()
+		bucket.Tokens = math.Min(bucket.Tokens+elapsed*rl.refillRate*bucket.Reputation, rl.maxTokens*bucket.Reputation)
+		bucket.LastRefill = now
+
+		if bucket.Tokens >= cost {
+			bucket.Tokens -= cost
+			bucket.Reputation = math.Min(bucket.Reputation+reputationGain, rl.cfg.MaxReputation)
+			bucket.WarningsSent = 0
+			bucket.MediaGroupID = mediaGroupID
+			allowed = true
+			return
+		}
+
+		bucket.Reputation = math.Max(bucket.Reputation-reputationPenalty, rl.cfg.MinReputation)
+		if now.Sub(bucket.LastWarningAt) > rl.warningInterval {
+			bucket.WarningsSent++
+			bucket.LastWarningAt = now
+			warn = true
+		}
+		limit = *bucket
+	})
+	if err != nil {
+		rl.logger.Error("failed to update rate limit, update allowed",
+			zap.Error(err),
+			zap.Int64("user_id", userID),
+		)
+		return true
+	}
+
+	if allowed {
+		return true
+	}
+	if rl.exempt != nil && rl.exempt(userID) {
+		return true
+	}
+
+	rl.logger.Warn("rate limit exceeded",
+		zap.Int64("user_id", userID),
+		zap.Int64("chat_id", chatID),
+		zap.Float64("cost", cost),
+		zap.Float64("tokens", limit.Tokens),
+		zap.Float64("reputation", limit.Reputation),
+	)
+
+	if warn {
+		sendRateLimitWarning(rl.api, rl.logger, chatID, limit.WarningsSent)
+	}
+
+	return false
+}
@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/futig/agent-backend/internal/telegram/store"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"go.uber.org/zap"
+)
+
+// CallbackDedupMiddleware drops repeated presses of a button: a press of the button of a message the
+// user already pressed within the window is only answered, so a double tap starts the work once
+type CallbackDedupMiddleware struct {
+	store  store.Store
+	window time.Duration
+	logger *zap.Logger
+	api    *tgbotapi.BotAPI
+}
+
+// NewCallbackDedupMiddleware creates a new callback dedup middleware; a zero window disables it
+func NewCallbackDedupMiddleware(store store.Store, window time.Duration, logger *zap.Logger, api *tgbotapi.BotAPI) *CallbackDedupMiddleware {
+	return &CallbackDedupMiddleware{
+		store:  store,
+		window: window,
+		logger: logger,
+		api:    api,
+	}
+}
+
+// Handle processes the update through deduplication; a failing store lets the press through
+func (m *CallbackDedupMiddleware) Handle(update tgbotapi.Update, next func(tgbotapi.Update)) {
+	query := update.CallbackQuery
+	if m.window <= 0 || query == nil || query.Message == nil {
+		next(update)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), storeTimeout)
+	defer cancel()
+
+	key := fmt.Sprintf("%d:callback:%d:%d:%s", m.api.Self.ID, query.From.ID, query.Message.MessageID, query.Data)
+	first, err := m.store.SetIfAbsent(ctx, key, m.window)
+	if err != nil {
+		m.logger.Error("failed to check repeated button press",
+			zap.Error(err),
+			zap.Int64("user_id", query.From.ID),
+		)
+		next(update)
+		return
+	}
+
+	if !first {
+		m.logger.Info("repeated button press ignored",
+			zap.Int64("user_id", query.From.ID),
+			zap.String("data", query.Data),
+		)
+		// Stop the loading indicator of the button
+		if _, err := m.api.Request(tgbotapi.NewCallback(query.ID, "")); err != nil {
+			m.logger.Warn("failed to answer repeated button press", zap.Error(err))
+		}
+		return
+	}
+
+	next(update)
+}
@@ -1,30 +1,36 @@
 package middleware
 
 import (
-	"sync"
+	"context"
+	"fmt"
 	"time"
 
+	"github.com/futig/agent-backend/internal/telegram/store"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"go.uber.org/zap"
 )
 
-// userLimit tracks rate limit state for a single user
-type userLimit struct {
-	tokens        float64
-	lastRefill    time.Time
-	warningsSent  int
-	lastWarningAt time.Time
-	mu            sync.Mutex
+// RateLimiter decides whether an update is handled; next is called only for allowed updates
+type RateLimiter interface {
+	Handle(update tgbotapi.Update, next func(tgbotapi.Update))
 }
 
+// storeTimeout bounds a call of the rate limiter to the store
+const storeTimeout = 2 * time.Second
+
+// limitTTL is how long the bucket of an inactive user is kept
+const limitTTL = time.Hour
+
+var _ RateLimiter = &RateLimiterMiddleware{}
+
 // RateLimiterMiddleware implements token bucket rate limiting per user
 type RateLimiterMiddleware struct {
-	limits          map[int64]*userLimit
-	mu              sync.RWMutex
+	store           store.Store
 	maxTokens       float64 // Maximum tokens in bucket
 	refillRate      float64 // Tokens added per second
 	burstSize       int     // Max burst size
 	warningInterval time.Duration
+	exempt          func(userID int64) bool // consulted only once the bucket is empty; nil exempts nobody
 	logger          *zap.Logger
 	api             *tgbotapi.BotAPI
 }
@@ -33,45 +39,34 @@ type RateLimiterMiddleware struct {
 func NewRateLimiterMiddleware(
 	requestsPerMinute int,
 	burstSize int,
+	store store.Store,
+	exempt func(userID int64) bool,
 	logger *zap.Logger,
 	api *tgbotapi.BotAPI,
 ) *RateLimiterMiddleware {
-	rl := &RateLimiterMiddleware{
-		limits:          make(map[int64]*userLimit),
+	return &RateLimiterMiddleware{
+		store:           store,
 		maxTokens:       float64(requestsPerMinute),
 		refillRate:      float64(requestsPerMinute) / 60.0, // tokens per second
 		burstSize:       burstSize,
 		warningInterval: 30 * time.Second,
+		exempt:          exempt,
 		logger:          logger,
 		api:             api,
 	}
-
-	// Start cleanup goroutine to remove inactive users
-	go rl.cleanupInactiveUsers()
-
-	return rl
 }
 
 // Handle processes the update through rate limiting
 func (rl *RateLimiterMiddleware) Handle(update tgbotapi.Update, next func(tgbotapi.Update)) {
-	var userID int64
-	var chatID int64
-
-	// Extract user and chat ID
-	if update.Message != nil {
-		userID = update.Message.From.ID
-		chatID = update.Message.Chat.ID
-	} else if update.CallbackQuery != nil {
-		userID = update.CallbackQuery.From.ID
-		chatID = update.CallbackQuery.Message.Chat.ID
-	} else {
+	userID, chatID, mediaGroupID, ok := updateSender(update)
+	if !ok {
 		// Unknown update type, allow it
 		next(update)
 		return
 	}
 
 	// Check rate limit
-	if !rl.allowRequest(userID, chatID) {
+	if !rl.allowRequest(userID, chatID, mediaGroupID) {
 		rl.logger.Warn("rate limit exceeded",
 			zap.Int64("user_id", userID),
 			zap.Int64("chat_id", chatID),
@@ -83,52 +78,93 @@ func (rl *RateLimiterMiddleware) Handle(update tgbotapi.Update, next func(tgbota
 	next(update)
 }
 
-// allowRequest checks if request is allowed under rate limit
-func (rl *RateLimiterMiddleware) allowRequest(userID, chatID int64) bool {
-	rl.mu.Lock()
-	limit, exists := rl.limits[userID]
-	if !exists {
-		limit = &userLimit{
-			tokens:     rl.maxTokens,
-			lastRefill: time.Now(),
+// allowRequest checks if request is allowed under rate limit; all items of an album count as one request.
+// A failing store allows the request
+func (rl *RateLimiterMiddleware) allowRequest(userID, chatID int64, mediaGroupID string) bool {
+	ctx, cancel := context.WithTimeout(context.Background(), storeTimeout)
+	defer cancel()
+
+	var allowed, limited bool
+	var warnings int
+	err := rl.store.UpdateBucket(ctx, limitKey(rl.api, userID), limitTTL, func(limit *store.Bucket) {
+		allowed, limited, warnings = false, false, 0
+		now := time.Now()
+
+		if limit.LastRefill.IsZero() {
+			limit.Tokens = rl.maxTokens
+			limit.LastRefill = now
 		}
-		rl.limits[userID] = limit
-	}
-	rl.mu.Unlock()
 
-	limit.mu.Lock()
-	defer limit.mu.Unlock()
+		if mediaGroupID != "" && mediaGroupID == limit.MediaGroupID {
+			allowed = true
+			return
+		}
 
-	now := time.Now()
+		// Refill tokens based on elapsed time
+		elapsed := now.Sub(limit.LastRefill).Seconds()
+		limit.Tokens += elapsed * rl.refillRate
+		if limit.Tokens > rl.maxTokens {
+			limit.Tokens = rl.maxTokens
+		}
+		limit.LastRefill = now
+
+		// Check if we have enough tokens
+		if limit.Tokens >= 1.0 {
+			limit.Tokens -= 1.0
+			limit.WarningsSent = 0 // Reset warnings on successful request
+			limit.MediaGroupID = mediaGroupID
+			allowed = true
+			return
+		}
 
-	// Refill tokens based on elapsed time
-	elapsed := now.Sub(limit.lastRefill).Seconds()
-	limit.tokens += elapsed * rl.refillRate
-	if limit.tokens > rl.maxTokens {
-		limit.tokens = rl.maxTokens
-	}
-	limit.lastRefill = now
+		limited = true
 
-	// Check if we have enough tokens
-	if limit.tokens >= 1.0 {
-		limit.tokens -= 1.0
-		limit.warningsSent = 0 // Reset warnings on successful request
+		// Rate limit exceeded - send warning if not sent recently
+		if now.Sub(limit.LastWarningAt) > rl.warningInterval {
+			limit.WarningsSent++
+			limit.LastWarningAt = now
+			warnings = limit.WarningsSent
+		}
+	})
+	if err != nil {
+		rl.logger.Error("failed to update rate limit, request allowed",
+			zap.Error(err),
+			zap.Int64("user_id", userID),
+		)
 		return true
 	}
 
-	// Rate limit exceeded - send warning if not sent recently
-	if now.Sub(limit.lastWarningAt) > rl.warningInterval {
-		limit.warningsSent++
-		limit.lastWarningAt = now
-
-		rl.sendRateLimitWarning(chatID, limit.warningsSent)
+	if allowed {
+		return true
+	}
+	if limited && rl.exempt != nil && rl.exempt(userID) {
+		return true
+	}
+	if warnings > 0 {
+		sendRateLimitWarning(rl.api, rl.logger, chatID, warnings)
 	}
 
 	return false
 }
 
+// limitKey is the store key of the rate limit of a user of the bot
+func limitKey(api *tgbotapi.BotAPI, userID int64) string {
+	return fmt.Sprintf("%d:ratelimit:%d", api.Self.ID, userID)
+}
+
+// updateSender returns the user and chat of a message or button update and the album of a message
+func updateSender(update tgbotapi.Update) (userID, chatID int64, mediaGroupID string, ok bool) {
+	switch {
+	case update.Message != nil:
+		return update.Message.From.ID, update.Message.Chat.ID, update.Message.MediaGroupID, true
+	case update.CallbackQuery != nil:
+		return update.CallbackQuery.From.ID, update.CallbackQuery.Message.Chat.ID, "", true
+	}
+	return 0, 0, "", false
+}
+
 // sendRateLimitWarning sends a warning message to the user
-func (rl *RateLimiterMiddleware) sendRateLimitWarning(chatID int64, warningCount int) {
+func sendRateLimitWarning(api *tgbotapi.BotAPI, logger *zap.Logger, chatID int64, warningCount int) {
 	var text string
 
 	switch {
@@ -141,34 +177,10 @@ func (rl *RateLimiterMiddleware) sendRateLimitWarning(chatID int64, warningCount
 	}
 
 	msg := tgbotapi.NewMessage(chatID, text)
-	if _, err := rl.api.Send(msg); err != nil {
-		rl.logger.Error("failed to send rate limit warning",
+	if _, err := api.Send(msg); err != nil {
+		logger.Error("failed to send rate limit warning",
 			zap.Error(err),
 			zap.Int64("chat_id", chatID),
 		)
 	}
 }
-
-// cleanupInactiveUsers removes users that haven't sent requests in 1 hour
-func (rl *RateLimiterMiddleware) cleanupInactiveUsers() {
-	ticker := time.NewTicker(10 * time.Minute)
-	defer ticker.Stop()
-
-	for range ticker.C {
-		rl.mu.Lock()
-		now := time.Now()
-		inactiveThreshold := 1 * time.Hour
-
-		for userID, limit := range rl.limits {
-			limit.mu.Lock()
-			if now.Sub(limit.lastRefill) > inactiveThreshold {
-				delete(rl.limits, userID)
-				rl.logger.Debug("cleaned up inactive user from rate limiter",
-					zap.Int64("user_id", userID),
-				)
-			}
-			limit.mu.Unlock()
-		}
-		rl.mu.Unlock()
-	}
-}
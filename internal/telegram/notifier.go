@@ -0,0 +1,147 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/futig/agent-backend/internal/config"
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/pkg/formatter"
+	"github.com/futig/agent-backend/internal/telegram/keyboard"
+	"github.com/futig/agent-backend/internal/telegram/render"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+const notifierTimeout = 10 * time.Second
+
+// Notifier sends review requests and scheduled session invitations on behalf of the bot.
+// It does not poll updates, so it can be used outside of the bot process.
+type Notifier struct {
+	api        *tgbotapi.BotAPI
+	tenantAPIs map[string]*tgbotapi.BotAPI // bots of tenants served by a bot of their own
+	keyboard   *keyboard.Builder
+	logger     *zap.Logger
+}
+
+// NewNotifier creates a notifier for the configured bot; tenantTokens maps tenant IDs to the
+// tokens of their bots, users of other tenants are notified by the configured bot
+func NewNotifier(cfg *config.TelegramConfig, tenantTokens map[string]string, logger *zap.Logger) *Notifier {
+	tenantAPIs := make(map[string]*tgbotapi.BotAPI, len(tenantTokens))
+	for tenantID, token := range tenantTokens {
+		tenantAPIs[tenantID] = newNotifierAPI(token, cfg.APIEndpoint)
+	}
+
+	return &Notifier{
+		api:        newNotifierAPI(cfg.BotToken, cfg.APIEndpoint),
+		tenantAPIs: tenantAPIs,
+		keyboard:   keyboard.NewBuilder(),
+		logger:     logger,
+	}
+}
+
+// newNotifierAPI creates a bot API client without the getMe call of tgbotapi.NewBotAPI;
+// an empty endpoint is the Telegram one
+func newNotifierAPI(token, endpoint string) *tgbotapi.BotAPI {
+	api := &tgbotapi.BotAPI{
+		Token:  token,
+		Client: &http.Client{Timeout: notifierTimeout},
+		Buffer: 100,
+	}
+	if endpoint == "" {
+		endpoint = tgbotapi.APIEndpoint
+	}
+	api.SetAPIEndpoint(endpoint)
+	return api
+}
+
+// botAPI returns the bot serving the tenant ctx is scoped to
+func (n *Notifier) botAPI(ctx context.Context) *tgbotapi.BotAPI {
+	if api, ok := n.tenantAPIs[entity.TenantIDFromContext(ctx)]; ok {
+		return api
+	}
+	return n.api
+}
+
+// NotifyReviewRequested sends the document with decision buttons to a Telegram approver;
+// API approvers are notified via callbacks
+func (n *Notifier) NotifyReviewRequested(
+	ctx context.Context,
+	approver entity.ResultApprover,
+	review *entity.ResultReview,
+	result string,
+	fileInfo *entity.ResultFileInfo,
+) error {
+	if approver.Type != entity.ApproverTypeTelegram {
+		return nil
+	}
+
+	chatID, err := strconv.ParseInt(approver.ID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid telegram user ID '%s': %w", approver.ID, err)
+	}
+
+	doc := tgbotapi.NewDocument(chatID, tgbotapi.FileBytes{
+		Name:  formatter.ASCIIFileName(formatter.FileName(fileInfo, "", ".md")),
+		Bytes: []byte(result),
+	})
+	doc.Caption = fmt.Sprintf(render.MsgReviewRequested, review.SessionID)
+	doc.ReplyMarkup = n.keyboard.ReviewDecisionKeyboard(review.SessionID)
+
+	if _, err := n.botAPI(ctx).Send(doc); err != nil {
+		return fmt.Errorf("send review request: %w", err)
+	}
+
+	ctxzap.Info(ctx, "review request sent to telegram approver",
+		zap.String("session_id", review.SessionID),
+		zap.Int64("chat_id", chatID),
+	)
+
+	return nil
+}
+
+// NotifyScheduledSession invites the bound Telegram user to a scheduled check-in session
+func (n *Notifier) NotifyScheduledSession(
+	ctx context.Context,
+	telegramUserID int64,
+	session *entity.Session,
+	projectTitle string,
+) error {
+	msg := tgbotapi.NewMessage(telegramUserID, fmt.Sprintf(render.MsgScheduledSession, projectTitle))
+	msg.ReplyMarkup = n.keyboard.ScheduledSessionKeyboard(session.ID)
+
+	if _, err := n.botAPI(ctx).Send(msg); err != nil {
+		return fmt.Errorf("send scheduled session invitation: %w", err)
+	}
+
+	ctxzap.Info(ctx, "scheduled session invitation sent",
+		zap.String("session_id", session.ID),
+		zap.Int64("chat_id", telegramUserID),
+	)
+
+	return nil
+}
+
+// NotifyVoiceAnswerAccepted tells the Telegram user that a voice answer queued while speech
+// recognition was unavailable has been transcribed and saved
+func (n *Notifier) NotifyVoiceAnswerAccepted(
+	ctx context.Context,
+	telegramUserID int64,
+	question, answer string,
+) error {
+	msg := tgbotapi.NewMessage(telegramUserID, fmt.Sprintf(render.MsgVoiceAnswerAccepted, question, answer))
+
+	if _, err := n.botAPI(ctx).Send(msg); err != nil {
+		return fmt.Errorf("send voice answer confirmation: %w", err)
+	}
+
+	ctxzap.Info(ctx, "voice answer confirmation sent",
+		zap.Int64("chat_id", telegramUserID),
+	)
+
+	return nil
+}
@@ -0,0 +1,153 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/futig/agent-backend/internal/config"
+	"github.com/futig/agent-backend/internal/pkg/metrics"
+	"github.com/futig/agent-backend/internal/telegram/bot"
+	"github.com/futig/agent-backend/internal/telegram/store"
+	"go.uber.org/zap"
+)
+
+// registeredBot is a bot of the registry with its webhook path
+type registeredBot struct {
+	name        string
+	webhookPath string
+	bot         *bot.Bot
+}
+
+// Registry runs several bots in one process. Each bot has its own update loop; in webhook
+// mode a single HTTP server routes updates to the bots by webhook path.
+type Registry struct {
+	cfg           *config.TelegramConfig
+	store         store.Store // shared by the bots, closed once they stopped
+	closers       []io.Closer // other shared connections, closed after the store
+	bots          []registeredBot
+	server        *http.Server
+	metricsServer *http.Server
+	logger        *zap.Logger
+}
+
+var _ Bot = &Registry{}
+
+// NewRegistry creates an empty bot registry
+func NewRegistry(cfg *config.TelegramConfig, store store.Store, logger *zap.Logger) *Registry {
+	return &Registry{
+		cfg:    cfg,
+		store:  store,
+		logger: logger,
+	}
+}
+
+// Register adds a bot to the registry
+func (r *Registry) Register(name, webhookPath string, b *bot.Bot) {
+	r.bots = append(r.bots, registeredBot{
+		name:        name,
+		webhookPath: webhookPath,
+		bot:         b,
+	})
+}
+
+// AddCloser registers a connection shared by the bots to close once they stopped
+func (r *Registry) AddCloser(closer io.Closer) {
+	r.closers = append(r.closers, closer)
+}
+
+// Start starts all registered bots
+func (r *Registry) Start(ctx context.Context) error {
+	if r.cfg.MetricsAddr != "" {
+		r.startMetrics()
+	}
+
+	if !r.cfg.UseWebhook {
+		for _, rb := range r.bots {
+			if err := rb.bot.Start(ctx); err != nil {
+				return fmt.Errorf("start bot '%s': %w", rb.name, err)
+			}
+		}
+		return nil
+	}
+
+	mux := http.NewServeMux()
+	baseURL := strings.TrimRight(r.cfg.WebhookURL, "/")
+	for _, rb := range r.bots {
+		if err := rb.bot.StartWebhook(ctx, baseURL+rb.webhookPath); err != nil {
+			return fmt.Errorf("start bot '%s': %w", rb.name, err)
+		}
+		mux.Handle(rb.webhookPath, rb.bot)
+	}
+
+	r.server = &http.Server{
+		Addr:              r.cfg.WebhookListenAddr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	r.logger.Info("starting telegram webhook server",
+		zap.String("addr", r.cfg.WebhookListenAddr),
+		zap.Int("bots", len(r.bots)),
+	)
+	if err := r.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("webhook server: %w", err)
+	}
+
+	return nil
+}
+
+// startMetrics serves the process metrics, e.g. handler timeouts, in the background
+func (r *Registry) startMetrics() {
+	r.metricsServer = &http.Server{
+		Addr:              r.cfg.MetricsAddr,
+		Handler:           metrics.NewServeMux(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	r.logger.Info("starting telegram metrics server", zap.String("addr", r.cfg.MetricsAddr))
+	go func() {
+		if err := r.metricsServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			r.logger.Error("metrics server failed", zap.Error(err))
+		}
+	}()
+}
+
+// Stop stops the webhook server and all registered bots
+func (r *Registry) Stop() error {
+	var errs []error
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(r.cfg.ShutdownTimeout)*time.Second)
+	defer cancel()
+	if r.server != nil {
+		if err := r.server.Shutdown(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("shutdown webhook server: %w", err))
+		}
+	}
+	if r.metricsServer != nil {
+		if err := r.metricsServer.Shutdown(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("shutdown metrics server: %w", err))
+		}
+	}
+
+	for _, rb := range r.bots {
+		if err := rb.bot.Stop(); err != nil {
+			errs = append(errs, fmt.Errorf("stop bot '%s': %w", rb.name, err))
+		}
+	}
+
+	if err := r.store.Close(); err != nil {
+		errs = append(errs, fmt.Errorf("close store: %w", err))
+	}
+	for _, closer := range r.closers {
+		if err := closer.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("close connection: %w", err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
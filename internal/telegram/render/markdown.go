@@ -0,0 +1,28 @@
+package render
+
+import (
+	"fmt"
+	"html"
+
+	"github.com/futig/agent-backend/internal/pkg/formatter"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// MarkdownParseMode is the parse mode of texts produced by RenderMarkdown
+const MarkdownParseMode = tgbotapi.ModeHTML
+
+// RenderMarkdown converts markdown from the LLM or from message templates into Telegram HTML.
+// The result must be sent with MarkdownParseMode; text that is not markup is escaped
+func RenderMarkdown(markdown string) string {
+	return formatter.TelegramHTML(markdown)
+}
+
+// EscapeHTML escapes plain text to be inserted into a message sent with MarkdownParseMode
+func EscapeHTML(text string) string {
+	return html.EscapeString(text)
+}
+
+// RenderExplanation formats a question explanation generated by the LLM
+func RenderExplanation(explanation string) string {
+	return fmt.Sprintf(MsgQuestionExplanation, RenderMarkdown(explanation))
+}
@@ -7,6 +7,9 @@ import (
 	"net"
 	"strings"
 	"syscall"
+	"time"
+
+	"github.com/futig/agent-backend/internal/entity"
 )
 
 const (
@@ -23,10 +26,61 @@ const (
 
 О чём проект? Можешь написать текстом или записать голосовое сообщение.`
 
+	// Too vague goals get one clarifying question before project selection
+	MsgGoalClarification = `🤔 Цель пока слишком общая, и вопросы получатся поверхностными. Уточни, пожалуйста: что именно нужно сделать, для кого и какой результат ты ждёшь?
+
+Ответь текстом или голосовым — я добавлю это к цели. Или продолжи с целью как есть.`
+
 	// Project selection
 	MsgSelectProject = `📁 Отлично! Теперь выбери проект, в рамках которого будут вноситься изменения.
 
-Или нажми "Проекта нет", если работаешь над новым проектом.`
+Или нажми "Проекта нет", если работаешь над новым проектом.
+
+⚠️ — материалы проекта давно не обновлялись.`
+
+	// MsgProjectStale warns that the questions of the session rely on old project materials; %s is how long ago
+	// they were indexed, e.g. "8 месяцев назад"
+	MsgProjectStale = `⚠️ Материалы проекта обновлялись %s.
+
+Вопросы и требования могут опираться на устаревшие данные — если есть свежие документы, загрузи их в проект.`
+
+	// Favorite projects pinned with ☆ in the selector are shown on top of every page
+	MsgSettings         = `⚙️ Настройки`
+	MsgPinnedProjects   = `⭐️ Избранные проекты показываются первыми на каждой странице выбора проекта. Нажми на проект, чтобы открепить его.`
+	MsgNoPinnedProjects = `⭐️ Избранных проектов пока нет. Закрепи до %d проектов кнопкой ☆ рядом с проектом при его выборе.`
+	ErrPinLimitReached  = `⭐️ Закрепить можно не больше %d проектов. Открепи один из них кнопкой ⭐️ или в /settings.`
+
+	// Jump to a page of the project selector by tapping the "стр. 3/12" indicator
+	MsgProjectPagePrompt     = `🔢 Отправь номер страницы от 1 до %d.`
+	ErrProjectPageOutOfRange = `❌ Нет такой страницы. Отправь число от 1 до %d.`
+	MsgSelectProjectButtons  = `👆 Выбери проект кнопкой в списке. Чтобы перейти к нужной странице, нажми на номер страницы под списком.`
+
+	// Timezone of the user (/timezone, settings): dates in messages, documents and check-in schedules
+	MsgTimezone = `🕒 Часовой пояс: %s, сейчас %s.
+
+Выбери свой пояс или пришли его название командой, например /timezone Asia/Tbilisi`
+	MsgTimezoneSet     = `🕒 Часовой пояс изменён: %s, сейчас %s. Время в сообщениях, документах и плановых сессиях теперь указывается по нему.`
+	ErrInvalidTimezone = `❌ Не знаю такой часовой пояс. Пришли название из базы IANA, например Europe/Moscow или Asia/Novosibirsk.`
+	TimezoneNowLayout  = "15:04"
+
+	// Style of the generated document (result keyboard, settings)
+	MsgSummaryStyle = `🎨 Стиль документа:
+• Формальный — по образцу ГОСТ 34: разделы, нумерованные пункты, строгие формулировки
+• Краткая сводка — главное на одной-двух страницах для руководителя
+• Для разработчиков — пользовательские истории, критерии приёмки и технические детали`
+	MsgSummaryStyleRestyle  = `Выбери стиль — документ будет переписан, содержание не изменится.`
+	MsgSummaryStyleSettings = `Выбранный стиль применяется к следующим документам: %s.`
+	MsgSummaryStyleDefault  = `стиль не выбран, документ пишется в стиле по умолчанию`
+	MsgResultRestyled       = `🎨 Документ переписан в стиле «%s». Этот стиль будет и у следующих документов.`
+	MsgSummaryStyleSet      = `🎨 Требования будут сформированы в стиле «%s». Этот стиль будет и у следующих документов.`
+	MsgSettingsStyleSet     = `🎨 Стиль документа изменён: «%s». Он применяется к следующим документам.`
+
+	// Progress header of questions (settings)
+	MsgProgressHeaderOn  = `📊 Над каждым вопросом показывается прогресс: «Блок 2/5 • Вопрос 3/4 • Всего отвечено 7/15».`
+	MsgProgressHeaderOff = `📊 Прогресс над вопросами скрыт.`
+
+	// Callback of a question button turned off in the bot
+	MsgQuestionButtonDisabled = `🚫 Это действие недоступно в этом боте.`
 
 	// Context questions
 	MsgContextQuestion = `❓ %s
@@ -48,6 +102,15 @@ const (
 
 ⚠️ Вопросы можно пропускать, но тогда бизнес-требования получатся не совсем полными.
 
+Подходит такой вариант?`
+
+	// Delta info
+	MsgDeltaInfo = `🔁 Что изменилось
+
+Я возьму последние бизнес-требования проекта и задам вопросы только о том, что поменялось с тех пор.
+
+В конце ты получишь обновлённый документ и отдельный список изменений.
+
 Подходит такой вариант?`
 
 	// Draft info
@@ -76,6 +139,49 @@ const (
 	MsgProcessing = `⏳ Обрабатываю материалы и формирую бизнес-требования...
 
 Это может занять несколько минут.`
+	MsgSummaryStreamPreview = `📝 Формирую требования, уже готово:
+
+%s`
+
+	// Generation estimate
+	MsgGenerationEstimate = `📏 Объём материалов: ~%d символов (~%d токенов).
+⏱ Ориентировочное время генерации: %s.`
+	MsgGenerationCost            = `💰 Примерная стоимость: %.4f %s.`
+	MsgGenerationConfirmRequired = `⚠️ Сессия получилась необычно большой. Запустить генерацию?`
+	MsgGenerationAwaitsApproval  = `🛡 Сессия превышает допустимый объём, генерацию должен одобрить администратор.
+
+Когда одобрение будет получено, нажми "Проверить снова".`
+
+	// Skip reasons
+	MsgSkipReasonPrompt = `🤔 Почему пропускаешь? Можно не отвечать.`
+	MsgSkipReasonSaved  = `📝 Причина пропуска: %s`
+
+	// Interview time budget
+	MsgTimeBudgetWarning = `⏰ Время интервью почти вышло: осталось %s
+
+Можно продолжить отвечать или пропустить оставшиеся вопросы и сразу сформировать требования.`
+	MsgTimeBudgetExceeded = `⏰ Отведённое на интервью время вышло.
+
+Можно продолжить отвечать или пропустить оставшиеся вопросы и сразу сформировать требования.`
+	MsgTimeBudgetSkipped = `⏩ Пропущено вопросов: %d. Формирую требования по собранным ответам.`
+
+	// Reply to an earlier question
+	MsgReplyAnswerAccepted = `✅ Принял ответ на вопрос, на который ты ответил реплаем.
+
+Текущий вопрос всё ещё ждёт ответа.`
+
+	// Voice answers while speech recognition is unavailable
+	MsgVoiceAnswerDeferred = `🎙 Распознавание голоса временно недоступно. Я сохранил голосовое и приму его как ответ, как только сервис восстановится, а вопрос пока отложил в конец интервью.`
+	MsgVoiceAnswerQueued   = `🎙 Распознавание голоса временно недоступно. Я сохранил голосовое и приму его как ответ, как только сервис восстановится. Чтобы не ждать, напиши ответ текстом.`
+	MsgVoiceAnswerAccepted = `✅ Распознавание голоса восстановилось — принял голосовой ответ на вопрос «%s»:
+
+%s`
+
+	// Project change of a session with answers
+	MsgChangeProjectConfirm = `⚠️ В этой сессии уже есть ответы: %d. Если выбрать другой проект, вопросы и ответы будут удалены. Если вернуться к этому же проекту, они сохранятся.
+
+Сменить проект?`
+	MsgQuestionsKept = `📌 Проект не изменился — продолжаем с сохранёнными вопросами: отвечено %d из %d.`
 
 	// Validation
 	MsgValidating = `🔍 Проверяю полноту информации...`
@@ -90,43 +196,458 @@ const (
 	// Result ready
 	MsgResultReady = `✅ Готово! Бизнес-требования сформированы.
 
-Можешь скачать их в удобном формате:`
+Можешь посмотреть их прямо здесь или скачать в удобном формате:`
+
+	// Collected materials saved instead of the requirements
+	MsgPartialResult = `⚠️ Сформировать требования не получилось несколько раз подряд.
+
+Чтобы ничего не потерялось, я собрал все твои ответы и материалы в один документ — его можно посмотреть или скачать. Сформировать требования можно попробовать заново в любой момент:`
+
+	// Result preview
+	MsgPreviewPage  = `👁 Предпросмотр, страница %d из %d`
+	MsgPreviewEmpty = `👁 Документ пуст, показывать нечего.`
+
+	// Translation
+	MsgChooseLanguage    = `🌐 На какой язык перевести бизнес-требования?`
+	MsgTranslationFormat = `🌐 Выбери формат. Перевод займёт немного времени при первом запросе.`
+
+	// Section regeneration
+	MsgChooseSection   = `♻️ Какой раздел перегенерировать? Остальные разделы останутся без изменений.`
+	MsgSectionGuidance = `✏️ Раздел «%s».
+
+Напиши, что изменить или добавить, или нажми "Без пожеланий".`
+	MsgSectionRegenerating = `⏳ Перегенерирую раздел «%s»...`
+	MsgSectionRegenerated  = `✅ Раздел «%s» обновлён. Можешь скачать новую версию:`
+	MsgNoSections          = `❌ В документе не найдено разделов для перегенерации.`
+	MsgSectionUntitled     = `Вступление`
+	MsgSectionRegenCancel  = `👌 Перегенерация раздела отменена.`
+
+	// Result versions
+	MsgChooseResultVersion = `🗂 Какую версию документа скачать? Новая версия появляется при каждой генерации, доработке и смене стиля.`
+	MsgResultVersionFormat = `🗂 Версия %d от %s. Выбери формат:`
+	MsgNoResultVersions    = `ℹ️ Сохранённых версий документа пока нет.`
+
+	// Regeneration of the whole result with user feedback
+	MsgFeedbackPrompt = `🔁 Напиши, что доработать в документе: что добавить, убрать или изменить.
+Предыдущая версия сохранится, к ней можно будет вернуться.`
+	MsgFeedbackTextOnly     = `❌ Пожалуйста, напиши пожелания текстом.`
+	MsgFeedbackRegenerating = `⏳ Дорабатываю документ с учётом пожеланий...`
+	MsgFeedbackRegenerated  = `✅ Документ доработан, это новая версия. Можешь скачать её или доработать ещё раз:`
+	MsgFeedbackCancel       = `👌 Доработка отменена.`
+
+	// Review comments
+	MsgCommentsHeader  = `💬 Открытые комментарии (%d):`
+	MsgNoComments      = `💬 Открытых комментариев нет.`
+	MsgCommentResolved = `✅ Комментарий закрыт.`
+
+	// Comparison of the result with the previous requirements of the project
+	MsgResultDiff        = `🔀 Сравнение с предыдущей версией: новых разделов %d, удалённых %d, изменённых %d, без изменений %d. Подробности в файле.`
+	MsgResultDiffSame    = `🔀 Требования совпадают с предыдущей версией.`
+	MsgNoPreviousVersion = `🔀 Предыдущей версии требований нет: это первый результат проекта или сессия не привязана к проекту.`
+
+	// Interview script export for analysts conducting the interview live
+	MsgQuestionScript         = `📄 Сценарий интервью: все блоки и вопросы с пояснениями, без ответов.`
+	MsgQuestionScriptNotReady = `📄 Вопросы ещё не сформированы, попробуй чуть позже.`
+
+	// Coverage preview of the collected draft materials
+	MsgDraftCoverageChecking = `🔍 Проверяю, хватает ли материалов. Статус сессии не изменится, можно продолжать присылать материалы.`
+	MsgDraftCoverage         = `🔍 Проверка материалов (сообщений: %d)`
+	MsgDraftCoverageCovered  = `✅ Уже есть в материалах:`
+	MsgDraftCoverageMissing  = `❓ Не хватает информации:`
+	MsgDraftCoverageComplete = `Материалов достаточно — можно формировать требования.`
+	MsgDraftCoverageAddMore  = `Добавь материалы по этим пунктам или сформируй требования: недостающее я уточню дополнительными вопросами.`
+
+	// Search over collected material
+	MsgSearchPrompt    = `🔎 Напиши, что найти в твоих ответах и сообщениях.`
+	MsgSearchCancelled = `👌 Поиск отменён. Можно продолжать.`
+	MsgSearchNoResults = `🔎 По запросу «%s» ничего не нашлось.`
+	MsgSearchResults   = `🔎 Найдено по запросу «%s»:`
+	MsgSearchTextOnly  = `❌ Запрос для поиска нужно написать текстом.`
+
+	// Reviewing and editing submitted answers before generation
+	MsgAnswerReview            = `📝 Твои ответы (%d). Выбери номер, чтобы изменить ответ:`
+	MsgAnswerReviewPage        = `Страница %d из %d`
+	MsgAnswerReviewEmpty       = `📝 Пока нет ответов, которые можно изменить.`
+	MsgAnswerReviewUnavailable = `📝 Ответы можно изменить только во время интервью, до формирования требований.`
+	MsgEditAnswer              = `✏️ Вопрос:
+%s
+
+📝 Текущий ответ:
+%s
+
+Отправь новый ответ текстом — он заменит текущий.`
+	MsgEditAnswerTextOnly  = `❌ Новый ответ нужно написать текстом.`
+	MsgAnswerUpdated       = `✅ Ответ обновлён. Продолжаем с того же места.`
+	MsgEditAnswerCancelled = `👌 Ответ не изменён. Продолжаем.`
+
+	// Language of the answers changed during the interview
+	MsgLanguageSwitch = `🌐 Этот ответ написан на %s, а требования будут на %s.
+
+Выбери язык документа — отвечать можно на любом.`
+	MsgDocumentLanguageSet = `✅ Требования будут на %s.`
+
+	// Requirements conflicts
+	MsgConflictsFound = `⚠️ Нашёл противоречия с прежними требованиями проекта (%d).
+
+Реши, какое требование оставить. Документ станет доступен после разбора всех противоречий.`
+	MsgConflict = `⚖️ Противоречие %d из %d
+
+Ранее было указано: «%s»%s
+
+Сейчас: «%s»`
+	MsgConflictSource    = ` (%s)`
+	MsgConflictsResolved = `✅ Все противоречия разобраны, решения добавлены в документ.`
+
+	// Result approval
+	MsgReviewRequested = `📋 Тебя назначили согласующим бизнес-требований (сессия %s).
+
+Ознакомься с документом и прими решение:`
+	MsgReviewApproved      = `✅ Ты согласовал бизнес-требования.`
+	MsgReviewRejected      = `❌ Ты отклонил бизнес-требования.`
+	MsgOwnerReviewApproved = `✅ Бизнес-требования согласованы. Теперь их можно сохранить и скачать.`
+	MsgOwnerReviewRejected = `❌ Бизнес-требования отклонены согласующим. Доработай документ и отправь его на согласование повторно.`
+
+	// Scheduled check-in sessions
+	MsgScheduledSession = `🗓 Пора сверить требования по проекту «%s».
+
+Я подготовил сессию «что изменилось»: вопросы будут о том, что поменялось с прошлого раза.`
+	MsgScheduledSessionStarted = `🗓 Начинаем плановую сессию. Цель: %s`
+
+	// Voice transcription normalization
+	MsgNormalizeOn  = `✍️ Исправление расшифровок голосовых включено: опечатки и слова-паразиты будут убраны, оригинал сохраняется.`
+	MsgNormalizeOff = `✍️ Исправление расшифровок голосовых выключено: ответы сохраняются так, как их распознал сервис.`
+
+	// Question numbering
+	MsgNumberingBlock  = `🔢 Вопросы нумеруются внутри блока: «Вопрос 2 из 5».`
+	MsgNumberingGlobal = `🔢 Вопросы нумеруются сквозным счётом по всей сессии: «Вопрос 7 из 15».`
 
 	// Session finished
 	MsgSessionFinished = `👋 Сессия завершена.
 
 Чтобы начать новую, нажми /start`
 
+	// Resuming an interrupted session
+	MsgResumed           = `🔄 Продолжаем с того места, где остановились.`
+	MsgResumeBusy        = `⏳ Предыдущий запрос ещё обрабатывается. Если результата так и не будет, повтори /resume через пару минут.`
+	MsgResumeDraft       = `📥 Принято сообщений: %d. Присылай материалы дальше или нажми «Сформировать требования».`
+	MsgResumeNoSession   = `Нет активной сессии, продолжать нечего. Начни новую с /start`
+	MsgResumeNotStuck    = `👌 Сессия не прерывалась, можно продолжать. Подсказка по текущему шагу: /help`
+	MsgResumeUnavailable = `❌ Эту сессию продолжить нельзя. Начни новую с /start`
+
 	// Errors
-	ErrGeneric            = `❌ Произошла ошибка. Попробуйте ещё раз или нажмите /start`
-	ErrTranscription      = `❌ Не удалось распознать голосовое сообщение. Попробуйте ещё раз или напишите текстом.`
-	ErrSessionNotFound    = `❌ Сессия не найдена. Начните новую с /start`
-	ErrInvalidState       = `❌ Неверное состояние. Нажмите /start чтобы начать заново.`
-	ErrInvalidFile        = `❌ Неверный формат файла. Поддерживаются только WAV файлы.`
-	ErrProjectNotFound    = `❌ Проект не найден. Попробуйте выбрать другой или создайте новый.`
-	ErrMaxDraftMessages   = `❌ Достигнуто максимальное количество сообщений (%d). Нажмите "Сформировать требования".`
-	ErrNetworkIssue       = `❌ Проблема с соединением. Попробуй чуть позже.`
-	ErrServiceUnavailable = `❌ Сервис временно недоступен. Попробуй через пару минут.`
-	ErrInvalidInput       = `❌ Неверный формат ответа. Попробуй по-другому.`
-	ErrTimeout            = `❌ Операция заняла слишком много времени. Попробуй ещё раз.`
-	ErrQuotaExceeded      = `❌ Превышен лимит запросов. Подожди немного.`
+	ErrGeneric                     = `❌ Произошла ошибка. Попробуйте ещё раз или нажмите /start`
+	ErrTranscription               = `❌ Не удалось распознать голосовое сообщение. Попробуйте ещё раз или напишите текстом.`
+	ErrSessionNotFound             = `❌ Сессия не найдена. Начните новую с /start`
+	ErrInvalidState                = `❌ Неверное состояние. Нажмите /resume, чтобы продолжить с места остановки, или /start, чтобы начать заново.`
+	ErrEmptyAlbum                  = `❌ В альбоме нет подписей и текстовых файлов (.txt, .md, .csv). Добавь подпись или пришли текст.`
+	ErrInvalidFile                 = `❌ Неверный формат файла. Поддерживаются только WAV файлы.`
+	ErrProjectNotFound             = `❌ Проект не найден. Попробуйте выбрать другой или создайте новый.`
+	ErrMaxDraftMessages            = `❌ Достигнуто максимальное количество сообщений (%d). Нажмите "Сформировать требования".`
+	ErrNetworkIssue                = `❌ Проблема с соединением. Попробуй чуть позже.`
+	ErrServiceUnavailable          = `❌ Сервис временно недоступен. Попробуй через пару минут.`
+	ErrInvalidInput                = `❌ Неверный формат ответа. Попробуй по-другому.`
+	ErrTimeout                     = `❌ Операция заняла слишком много времени. Попробуй ещё раз.`
+	ErrQuotaExceeded               = `❌ Превышен лимит запросов. Подожди немного.`
+	ErrUsageQuotaExceeded          = `❌ Достигнут лимит использования. Подробности — /quota`
+	ErrLLMOverloaded               = `⏳ Сейчас слишком много запросов к модели. Попробуй через минуту.`
+	ErrSessionBusy                 = `⏳ С этой сессией сейчас работают с другого устройства. Попробуй через минуту.`
+	ErrOperationInProgress         = `⏳ Предыдущий запрос по этой сессии ещё обрабатывается, например генерация требований. Дождись результата и попробуй снова.`
+	ErrVoiceUnavailable            = `🎙 Распознавание голоса временно недоступно. Пожалуйста, напиши ответ текстом.`
+	ErrContentBlocked              = `🚫 Сообщение содержит недопустимые выражения и не было принято. Переформулируй, пожалуйста.`
+	ErrApprovalRequired            = `🛡 Генерация требует одобрения администратора. Попробуй позже.`
+	ErrResultNotApproved           = `🔒 Бизнес-требования ещё не согласованы. Сохранение и скачивание станут доступны после согласования.`
+	ErrNotApprover                 = `❌ Ты не назначен согласующим этого документа.`
+	ErrScheduledSessionUnavailable = `ℹ️ Эта плановая сессия уже начата или больше недоступна.`
+	ErrUnresolvedConflicts         = `⚖️ Сначала разбери противоречия с прежними требованиями проекта.`
+	ErrConflictResolved            = `ℹ️ Это противоречие уже разобрано.`
+	ErrNoBaseline                  = `ℹ️ У проекта ещё нет готовых бизнес-требований для сравнения. Выбери режим «Интервью» или «Драфт».`
+	ErrDemoSession                 = `🧪 В демо-сессии это недоступно. Начни обычную сессию командой /start, чтобы сохранять и согласовывать требования.`
+	ErrReviewClosed                = `ℹ️ Решение по документу уже принято или согласование отменено.`
+	ErrResultVersionPurged         = `ℹ️ Эта версия документа больше не хранится. Выбери другую.`
+
+	// MsgIncidentCode is appended to the generic error, so support finds the logs of the failure
+	MsgIncidentCode = "\n\nКод ошибки: %s"
 )
 
 const (
 	// MsgQuestionNoTitle is used for questions without iteration title
 	MsgQuestionNoTitle = `❓ Вопрос %d из %d: %s`
 
+	// MsgQuestionProgressHeader is shown above questions: block, question within the block, answered in the session
+	MsgQuestionProgressHeader = `Блок %d/%d • Вопрос %d/%d • Всего отвечено %d/%d`
+
 	// MsgSkippedQuestion is used for skipped/unanswered questions after summary
 	MsgSkippedQuestion = `❓ Пропущенный вопрос %d из %d: %s`
+
+	// Context-aware /help: what the user can do in the current state
+	MsgHelpHeader     = `🤖 **Команды бота:**`
+	MsgHelpNoSession  = `📍 **Сейчас:** активной сессии нет. Начни с /start или попробуй /demo.`
+	MsgHelpNow        = `📍 **Сейчас:** %s`
+	MsgHelpHowItWorks = `**Как это работает:**
+1. Опиши цель проекта
+2. Выбери существующий проект или создай контекст вручную
+3. Выбери режим: Интервью или Драфт
+4. Ответь на вопросы или пришли материалы
+5. Получи готовые бизнес-требования`
+	MsgHelpAskGoal    = `опиши цель проекта текстом или голосовым сообщением.`
+	MsgHelpAskContext = `ответь на вопросы о контексте проекта одним сообщением: текстом или голосовым.`
+	MsgHelpQuestions  = `идёт интервью. Отвечай на вопрос текстом или голосовым, а также можно:
+• ⏭ Пропустить — вопрос попадёт в список пропущенных
+• ⏰ Спросить позже — вопрос вернётся в конце интервью
+• ❓ Поясни вопрос — короткое пояснение, зачем он нужен
+• ◀️ Предыдущий вопрос — вернуться на шаг назад
+• 📝 Мои ответы — выбрать и изменить любой данный ответ
+• ответить реплаем на любой прошлый вопрос — ответ уйдёт именно к нему
+• 🔎 Найти в материалах, ✅ Сформировать требования, 🛑 Завершить диалог`
+	MsgHelpQuestionsSkipped  = `отвечаешь на пропущенные вопросы. Ответь на вопрос или снова пропусти его.`
+	MsgHelpQuestionsDeferred = `отвечаешь на отложенные вопросы. Ответь, пропусти или отложи вопрос в конец очереди.`
+	MsgHelpSearch            = `жду поисковый запрос по собранным материалам.`
+	MsgHelpEditAnswer        = `жду новый ответ на выбранный вопрос. Отправь его текстом или нажми «Отмена».`
+	MsgHelpDraft             = `собираю материалы для драфта (%d из %d сообщений). Присылай:
+• текстовые сообщения, в том числе пересланные
+• голосовые до %d МБ — я их расшифрую
+• альбомы из текстовых файлов (.txt, .md, .csv) до %d КБ каждый
+Когда материалов достаточно, нажми кнопку формирования требований.`
+	MsgHelpProjectName        = `введи название нового проекта текстом.`
+	MsgHelpProjectDescription = `введи описание нового проекта текстом.`
+	MsgHelpSectionGuidance    = `напиши, что изменить в выбранном разделе результата.`
+	MsgHelpResultFeedback     = `напиши, что доработать во всём документе, или нажми «Отмена».`
+
+	// Scale questions are answered with the 1–5 buttons under the question or with text
+	MsgQuickAnswerUnavailable = `Этот вопрос уже нельзя оценить кнопкой. Ответь на текущий вопрос текстом или голосовым.`
+
+	// Text answers are kept until submitted; a failed one can be sent again with a button
+	MsgInboxRetryHint        = `Твой текст сохранён — его можно отправить ещё раз, не набирая заново.`
+	MsgInboxRetrying         = `🔁 Отправляю ещё раз`
+	MsgInboxRetryUnavailable = `Этот текст уже нельзя отправить повторно.`
+
+	// Question explanations come from the LLM and are rendered with RenderMarkdown
+	MsgQuestionExplanation = `💡 <b>Пояснение к вопросу:</b>
+
+%s`
+	MsgNoExplanation       = `💡 К этому вопросу пока нет отдельного пояснения. Ответь как можно подробнее.`
+	MsgExplanationCooldown = `⏳ Пояснения можно запрашивать не так часто. Попробуй чуть позже.`
+
+	// Deferred ("ask later") questions are asked after the last block
+	MsgQuestionDeferred   = `⏰ Хорошо, вернусь к этому вопросу в конце интервью.`
+	MsgDeferredQueueStart = `⏰ Основные вопросы закончились. Вернёмся к отложенным: %d.`
+	MsgDeferredQuestion   = `⏰ Отложенный вопрос %d из %d: %s`
+	MsgDeferLastQuestion  = `Это последний отложенный вопрос — ответь на него или пропусти.`
+	MsgDeferUnavailable   = `В этом режиме вопрос нельзя отложить — ответь на него или пропусти.`
+
+	// Forwarded draft materials keep their origin for the LLM
+	MsgForwardedDraftHeader          = "[Переслано от %s, %s]\n%s"
+	MsgForwardedDraftHeaderAnonymous = "[Переслано, %s]\n%s"
+
+	// Operator takeover: the user always sees what a support operator does in the session
+	MsgTakeoverAttached       = `🛟 Оператор поддержки подключился к вашей сессии и может помочь с ответами.`
+	MsgTakeoverAnswered       = `🛟 Оператор помог с ответом: «%s»`
+	MsgTakeoverAnsweredVoice  = `🛟 Оператор помог с ответом голосовым сообщением.`
+	MsgTakeoverGenerated      = `🛟 Оператор запустил формирование требований.`
+	MsgTakeoverReleased       = `🛟 Оператор отключился от вашей сессии.`
+	MsgTakeoverUsage          = "Использование:\n/takeover <user_id> — подключиться к сессии пользователя\n/takeover — текущий вопрос\n/takeover generate — сформировать требования\n/takeover release — отключиться\n\nПока вы подключены, ваши сообщения отправляются как ответы пользователя."
+	MsgTakeoverStarted        = `🛟 Вы подключились к сессии пользователя %d. Ваши сообщения будут отправлены как его ответы.`
+	MsgTakeoverStatus         = "👤 Пользователь %d\nСтатус сессии: %s"
+	MsgTakeoverQuestion       = "\n\nТекущий вопрос:\n%s"
+	MsgTakeoverAnswerSent     = `✅ Ответ отправлен от имени пользователя.`
+	MsgTakeoverGenerateSent   = `✅ Формирование требований запущено у пользователя.`
+	MsgTakeoverEnded          = `Вы отключились от сессии пользователя %d.`
+	MsgTakeoverNotAttached    = `Вы не подключены к сессии пользователя. Используйте /takeover <user_id>.`
+	MsgTakeoverNoSession      = `У пользователя %d нет активной сессии.`
+	MsgTakeoverInvalidUser    = `❌ Неверный ID пользователя.`
+	MsgTakeoverAuditFailed    = `❌ Не удалось записать действие в журнал аудита, действие отменено.`
+	MsgTakeoverGenerateDenied = `Сформировать требования можно только во время интервью или сбора черновиков.`
+
+	// Usage quotas (/quota)
+	MsgQuotaHeader      = `📊 Лимиты использования`
+	MsgQuotaLine        = "%s (%s): %d из %d, сброс %s"
+	MsgQuotaUnlimited   = `📊 Лимиты использования не установлены.`
+	MsgQuotaWarning     = `⚠️ Лимит почти исчерпан — %s (%s): %d из %d. Подробности — /quota`
+	MsgQuotaExhausted   = " — исчерпан"
+	MsgQuotaSessions    = "Сессии за день"
+	MsgQuotaGenerations = "Генерации за месяц"
+	MsgQuotaUser        = "ваш лимит"
+	MsgQuotaTenant      = "лимит организации"
+	QuotaResetLayout    = "02.01.2006 15:04 MST"
+
+	// Account linking (/link)
+	MsgLinkCode = `🔗 Код для продолжения сессии на другом устройстве: %s
+
+Введи его в веб-клиенте до %s — там откроется эта же сессия. Код одноразовый.`
+	LinkCodeExpiryLayout = "15:04 MST"
+
+	forwardDateLayout = "02.01.2006 15:04 MST"
 )
 
+// QuestionPosition holds the numbers shown in a question message: the position within the
+// block and, with global numbering, the position across the whole session
+type QuestionPosition struct {
+	Number int
+	Total  int
+	// GlobalNumber and GlobalTotal replace the block numbers when set
+	GlobalNumber int
+	GlobalTotal  int
+	// Progress is shown as a header above the question when set
+	Progress *entity.QuestionProgress
+}
+
 // RenderQuestion formats a question with context
-func RenderQuestion(iterationTitle string, questionNumber, totalQuestions int, question string) string {
+func RenderQuestion(iterationTitle string, position QuestionPosition, question string) string {
+	number, total := position.Number, position.Total
+	if position.GlobalNumber > 0 {
+		number, total = position.GlobalNumber, position.GlobalTotal
+	}
+
+	text := fmt.Sprintf(MsgQuestion, iterationTitle, number, total, question)
 	if iterationTitle == "" {
-		return fmt.Sprintf(MsgQuestionNoTitle, questionNumber, totalQuestions, question)
+		text = fmt.Sprintf(MsgQuestionNoTitle, number, total, question)
+	}
+
+	if p := position.Progress; p != nil {
+		// The header always counts questions within the block, the session totals follow it
+		header := fmt.Sprintf(MsgQuestionProgressHeader, p.Block, p.Blocks, position.Number, position.Total, p.Answered, p.Total)
+		text = header + "\n\n" + text
+	}
+
+	return text
+}
+
+// RenderGenerationEstimate formats the generation estimate; cost is shown only when token accounting is enabled
+func RenderGenerationEstimate(materialChars, tokens, seconds int, cost *float64, currency string) string {
+	text := fmt.Sprintf(MsgGenerationEstimate, materialChars, tokens, formatEstimateDuration(seconds))
+	if cost != nil {
+		text += "\n" + fmt.Sprintf(MsgGenerationCost, *cost, currency)
+	}
+	return text
+}
+
+// RenderTimeBudgetWarning formats the soft warning about the remaining interview time
+func RenderTimeBudgetWarning(remaining time.Duration) string {
+	if remaining <= 0 {
+		return MsgTimeBudgetExceeded
+	}
+	return fmt.Sprintf(MsgTimeBudgetWarning, formatEstimateDuration(int(remaining.Seconds())))
+}
+
+func formatEstimateDuration(seconds int) string {
+	if seconds < 60 {
+		return fmt.Sprintf("~%d сек.", seconds)
+	}
+	return fmt.Sprintf("~%d мин.", (seconds+59)/60)
+}
+
+// RenderComment formats a review comment list item; anchor is a section title or requirement ID
+func RenderComment(number int, anchor, author, text string) string {
+	item := fmt.Sprintf("%d. ", number)
+	if anchor != "" {
+		item += fmt.Sprintf("[%s] ", anchor)
+	}
+	item += text
+	if author != "" {
+		item += fmt.Sprintf(" — %s", author)
+	}
+	return item
+}
+
+// RenderSearchHit formats a search result list item; answers are shown with their question
+func RenderSearchHit(number int, question *string, snippet string) string {
+	if question != nil {
+		return fmt.Sprintf("%d. ❓ %s\n💬 %s", number, *question, snippet)
+	}
+	return fmt.Sprintf("%d. 📝 %s", number, snippet)
+}
+
+// Answers in the review list are shortened, the full text is shown when the answer is picked
+const (
+	reviewQuestionRunes = 120
+	reviewAnswerRunes   = 80
+)
+
+// RenderAnswerReview lists answered questions numbered from offset+1 with their shortened answers
+func RenderAnswerReview(total, offset, page, pages int, questions []*entity.Question) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf(MsgAnswerReview, total) + "\n")
+	for i, q := range questions {
+		answer := ""
+		if q.Answer != nil {
+			answer = *q.Answer
+		}
+		sb.WriteString(fmt.Sprintf("\n%d. ❓ %s\n💬 %s\n", offset+i+1, shorten(q.Question, reviewQuestionRunes), shorten(answer, reviewAnswerRunes)))
+	}
+	if pages > 1 {
+		sb.WriteString("\n" + fmt.Sprintf(MsgAnswerReviewPage, page+1, pages))
+	}
+	return sb.String()
+}
+
+// shorten cuts text to at most limit runes, marking the cut with an ellipsis
+func shorten(text string, limit int) string {
+	runes := []rune(strings.TrimSpace(text))
+	if len(runes) <= limit {
+		return string(runes)
+	}
+	return strings.TrimSpace(string(runes[:limit-1])) + "…"
+}
+
+// languageNames are the languages of the documents in the prepositional case, "на русском"
+var languageNames = map[entity.ResultLanguage]string{
+	entity.LanguageRussian: "русском",
+	entity.LanguageEnglish: "английском",
+	entity.LanguageGerman:  "немецком",
+	entity.LanguageFrench:  "французском",
+	entity.LanguageSpanish: "испанском",
+	entity.LanguageChinese: "китайском",
+}
+
+// summaryStyleTitles are the names of the document styles
+var summaryStyleTitles = map[entity.SummaryStyle]string{
+	entity.SummaryStyleFormal:    "формальный (ГОСТ)",
+	entity.SummaryStyleExecutive: "краткая сводка",
+	entity.SummaryStyleDeveloper: "для разработчиков",
+}
+
+// RenderSummaryStyleSettings describes the styles with the style of the next documents
+func RenderSummaryStyleSettings(current entity.SummaryStyle) string {
+	title := MsgSummaryStyleDefault
+	if current != "" {
+		title = "«" + summaryStyleTitles[current] + "»"
 	}
+	return MsgSummaryStyle + "\n\n" + fmt.Sprintf(MsgSummaryStyleSettings, title)
+}
+
+// RenderSummaryStyleChoice describes the styles a generated document can be rewritten in
+func RenderSummaryStyleChoice() string {
+	return MsgSummaryStyle + "\n\n" + MsgSummaryStyleRestyle
+}
+
+// RenderSummaryStyleSet confirms the style with MsgResultRestyled, MsgSummaryStyleSet or MsgSettingsStyleSet
+func RenderSummaryStyleSet(format string, style entity.SummaryStyle) string {
+	return fmt.Sprintf(format, summaryStyleTitles[style])
+}
+
+// RenderLanguageSwitch asks which language the document is written in after an answer in
+// another language
+func RenderLanguageSwitch(detected, current entity.ResultLanguage) string {
+	return fmt.Sprintf(MsgLanguageSwitch, languageNames[detected], languageNames[current])
+}
+
+// RenderDocumentLanguageSet confirms the language of the document
+func RenderDocumentLanguageSet(language entity.ResultLanguage) string {
+	return fmt.Sprintf(MsgDocumentLanguageSet, languageNames[language])
+}
+
+// RenderEditAnswer asks for a new answer to an answered question
+func RenderEditAnswer(question, answer string) string {
+	return fmt.Sprintf(MsgEditAnswer, question, answer)
+}
 
-	return fmt.Sprintf(MsgQuestion, iterationTitle, questionNumber, totalQuestions, question)
+// RenderConflict formats a requirements conflict with its position among all conflicts
+func RenderConflict(number, total int, previous string, source *string, requirement string) string {
+	sourceText := ""
+	if source != nil && *source != "" {
+		sourceText = fmt.Sprintf(MsgConflictSource, *source)
+	}
+	return fmt.Sprintf(MsgConflict, number, total, previous, sourceText, requirement)
 }
 
 // RenderSkippedQuestion formats a question in the "answer skipped" flow
@@ -134,6 +655,38 @@ func RenderSkippedQuestion(currentNumber, totalQuestions int, question string) s
 	return fmt.Sprintf(MsgSkippedQuestion, currentNumber, totalQuestions, question)
 }
 
+// RenderDraftHelp describes the draft materials the bot accepts and how many messages are left
+func RenderDraftHelp(used, maxMessages, maxVoiceMB, maxDocumentKB int) string {
+	return fmt.Sprintf(MsgHelpDraft, used, maxMessages, maxVoiceMB, maxDocumentKB)
+}
+
+// RenderDeferredQuestion formats a question in the deferred questions flow
+func RenderDeferredQuestion(currentNumber, totalQuestions int, question string) string {
+	return fmt.Sprintf(MsgDeferredQuestion, currentNumber, totalQuestions, question)
+}
+
+// RenderDraftCoverage formats the coverage preview of the draft materials
+func RenderDraftCoverage(messageCount int, covered, missing []string) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf(MsgDraftCoverage, messageCount) + "\n")
+	if len(covered) > 0 {
+		sb.WriteString("\n" + MsgDraftCoverageCovered + "\n")
+		for _, fact := range covered {
+			sb.WriteString("• " + fact + "\n")
+		}
+	}
+	if len(missing) == 0 {
+		sb.WriteString("\n" + MsgDraftCoverageComplete)
+		return sb.String()
+	}
+	sb.WriteString("\n" + MsgDraftCoverageMissing + "\n")
+	for i, q := range missing {
+		sb.WriteString(fmt.Sprintf("%d. %s\n", i+1, q))
+	}
+	sb.WriteString("\n" + MsgDraftCoverageAddMore)
+	return sb.String()
+}
+
 // RenderAdditionalQuestions formats additional questions list
 func RenderAdditionalQuestions(questions []string) string {
 	var sb strings.Builder
@@ -153,6 +706,14 @@ func RenderDraftInfo(maxMessages int) string {
 	return fmt.Sprintf(MsgDraftInfo, maxMessages)
 }
 
+// RenderForwardedDraft prepends the original sender and date to a forwarded draft message
+func RenderForwardedDraft(sender string, date time.Time, text string) string {
+	if sender == "" {
+		return fmt.Sprintf(MsgForwardedDraftHeaderAnonymous, date.Format(forwardDateLayout), text)
+	}
+	return fmt.Sprintf(MsgForwardedDraftHeader, sender, date.Format(forwardDateLayout), text)
+}
+
 // RenderDraftProgress formats draft collection progress with visual progress bar
 func RenderDraftProgress(current, max int) string {
 	progressBar := renderProgressBar(current, max)
@@ -204,9 +765,11 @@ func RenderMaxDraftMessagesError(max int) string {
 	return fmt.Sprintf(ErrMaxDraftMessages, max)
 }
 
-// EscapeMarkdown escapes special markdown characters
+// EscapeMarkdown escapes special MarkdownV2 characters, including the escaping backslash itself.
+// Messages with LLM output use RenderMarkdown and the HTML parse mode instead
 func EscapeMarkdown(text string) string {
 	replacer := strings.NewReplacer(
+		"\\", "\\\\",
 		"_", "\\_",
 		"*", "\\*",
 		"[", "\\[",
@@ -229,10 +792,24 @@ func EscapeMarkdown(text string) string {
 	return replacer.Replace(text)
 }
 
-// ClassifyError analyzes an error and returns an appropriate user-friendly message
-func ClassifyError(err error) string {
+// IncidentText appends the incident code of the work in ctx to the generic error message, so the
+// user can quote it to support; specific messages already tell what went wrong
+func IncidentText(ctx context.Context, text string) string {
+	if text != ErrGeneric {
+		return text
+	}
+	code := entity.IncidentCodeFromContext(ctx)
+	if code == "" {
+		return text
+	}
+	return text + fmt.Sprintf(MsgIncidentCode, code)
+}
+
+// ClassifyError analyzes an error and returns an appropriate user-friendly message;
+// the generic message carries the incident code of the work in ctx
+func ClassifyError(ctx context.Context, err error) string {
 	if err == nil {
-		return ErrGeneric
+		return IncidentText(ctx, ErrGeneric)
 	}
 
 	// Check for timeout errors
@@ -269,6 +846,36 @@ func ClassifyError(err error) string {
 		return ErrNetworkIssue
 	case strings.Contains(errMsg, "unavailable"):
 		return ErrServiceUnavailable
+	case strings.Contains(errMsg, "overloaded"):
+		return ErrLLMOverloaded
+	case strings.Contains(errMsg, "session is busy"):
+		return ErrSessionBusy
+	case strings.Contains(errMsg, "another operation is in progress"):
+		return ErrOperationInProgress
+	case strings.Contains(errMsg, "speech recognition"):
+		return ErrVoiceUnavailable
+	case strings.Contains(errMsg, "content blocked"):
+		return ErrContentBlocked
+	case strings.Contains(errMsg, "admin approval"):
+		return ErrApprovalRequired
+	case strings.Contains(errMsg, "not approved"):
+		return ErrResultNotApproved
+	case strings.Contains(errMsg, "no requirements to compare with"):
+		return ErrNoBaseline
+	case strings.Contains(errMsg, "unresolved conflicts"):
+		return ErrUnresolvedConflicts
+	case strings.Contains(errMsg, "conflict not found"):
+		return ErrConflictResolved
+	case strings.Contains(errMsg, "not an assigned approver"):
+		return ErrNotApprover
+	case strings.Contains(errMsg, "demo session"):
+		return ErrDemoSession
+	case strings.Contains(errMsg, "invalid review transition"):
+		return ErrReviewClosed
+	case strings.Contains(errMsg, "result version is no longer stored"):
+		return ErrResultVersionPurged
+	case strings.Contains(errMsg, "usage quota exceeded"):
+		return ErrUsageQuotaExceeded
 	case strings.Contains(errMsg, "quota"):
 		return ErrQuotaExceeded
 	case strings.Contains(errMsg, "session not found"):
@@ -284,5 +891,5 @@ func ClassifyError(err error) string {
 	}
 
 	// Default to generic error
-	return ErrGeneric
+	return IncidentText(ctx, ErrGeneric)
 }
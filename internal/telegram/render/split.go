@@ -0,0 +1,183 @@
+package render
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf16"
+)
+
+// MaxMessageLength is the Telegram limit of a message text, in UTF-16 code units
+const MaxMessageLength = 4096
+
+// htmlTagReserve leaves room for the tags closed at the end of a part and reopened in the next one
+const htmlTagReserve = 128
+
+var htmlTagPattern = regexp.MustCompile(`<(/?)([a-zA-Z-]+)[^>]*>`)
+
+// TailMessage keeps the end of a text longer than limit behind an ellipsis, for previews of
+// a text still growing at the end
+func TailMessage(text string, limit int) string {
+	if textLength(text) <= limit {
+		return text
+	}
+
+	runes := []rune(text)
+	length := 1 // the ellipsis
+	start := len(runes)
+	for start > 0 {
+		n := utf16.RuneLen(runes[start-1])
+		if length+n > limit {
+			break
+		}
+		length += n
+		start--
+	}
+
+	return "…" + string(runes[start:])
+}
+
+// SplitMessage splits a message text longer than limit into parts sent one after another.
+// Parts break between paragraphs where possible, then between lines, then between words; a heading
+// is kept together with the paragraph that follows it. With the HTML parse mode parts never break
+// inside a tag or an entity, and tags open at a break are closed and reopened in the next part.
+func SplitMessage(text, parseMode string, limit int) []string {
+	if textLength(text) <= limit {
+		return []string{text}
+	}
+
+	isHTML := strings.EqualFold(parseMode, "HTML")
+	window := limit
+	if isHTML {
+		window = max(limit-htmlTagReserve, limit/2)
+	}
+
+	var parts []string
+	var reopen string
+	rest := text
+	for rest != "" {
+		chunk := reopen + rest
+		if textLength(chunk) <= limit {
+			parts = append(parts, chunk)
+			break
+		}
+
+		cut := splitPoint(chunk, len(reopen), window, isHTML)
+		part := strings.TrimRight(chunk[:cut], " \n")
+		rest = strings.TrimLeft(chunk[cut:], " \n")
+
+		reopen = ""
+		if isHTML {
+			open := openTags(part)
+			for i := len(open) - 1; i >= 0; i-- {
+				part += "</" + tagName(open[i]) + ">"
+			}
+			reopen = strings.Join(open, "")
+		}
+
+		parts = append(parts, part)
+	}
+
+	return parts
+}
+
+// splitPoint returns the byte offset of the best break of text within window code units,
+// never before minCut, where the tags reopened from the previous part end
+func splitPoint(text string, minCut, window int, isHTML bool) int {
+	end := byteOffset(text, window)
+	head := text[:end]
+
+	// A heading stays with its paragraph, so the break goes before the heading rather than after it
+	for _, sep := range []string{"\n\n", "\n", " "} {
+		idx := strings.LastIndex(head, sep)
+		for idx > minCut {
+			if !isHTML || !insideMarkup(text, idx) {
+				if sep == "\n\n" && isHeading(text[lastLineStart(head[:idx]):idx]) {
+					if prev := strings.LastIndex(head[:idx], sep); prev > minCut {
+						idx = prev
+						continue
+					}
+				}
+				return idx
+			}
+			idx = strings.LastIndex(head[:idx], sep)
+		}
+	}
+
+	// A single word longer than the window is cut where it must be
+	for isHTML && end > minCut+1 && insideMarkup(text, end) {
+		end--
+	}
+	return end
+}
+
+// isHeading reports whether a line looks like a heading: markdown "#", a bold line or a line ending with a colon
+func isHeading(line string) bool {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return false
+	}
+	return strings.HasPrefix(line, "#") ||
+		(strings.HasPrefix(line, "<b>") && strings.HasSuffix(line, "</b>")) ||
+		strings.HasSuffix(line, ":")
+}
+
+func lastLineStart(text string) int {
+	return strings.LastIndex(text, "\n") + 1
+}
+
+// insideMarkup reports whether offset falls inside an HTML tag or entity
+func insideMarkup(text string, offset int) bool {
+	before := text[:offset]
+	if strings.LastIndex(before, "<") > strings.LastIndex(before, ">") {
+		return true
+	}
+	amp := strings.LastIndex(before, "&")
+	return amp >= 0 && !strings.ContainsAny(before[amp:], "; \n")
+}
+
+// openTags returns the opening tags left unclosed at the end of an HTML text, outermost first
+func openTags(text string) []string {
+	var open []string
+	for _, m := range htmlTagPattern.FindAllStringSubmatch(text, -1) {
+		if m[1] == "" {
+			open = append(open, m[0])
+			continue
+		}
+		for i := len(open) - 1; i >= 0; i-- {
+			if tagName(open[i]) == strings.ToLower(m[2]) {
+				open = append(open[:i], open[i+1:]...)
+				break
+			}
+		}
+	}
+	return open
+}
+
+func tagName(tag string) string {
+	m := htmlTagPattern.FindStringSubmatch(tag)
+	if m == nil {
+		return ""
+	}
+	return strings.ToLower(m[2])
+}
+
+// textLength counts text in UTF-16 code units the way Telegram does
+func textLength(text string) int {
+	n := 0
+	for _, r := range text {
+		n += utf16.RuneLen(r)
+	}
+	return n
+}
+
+// byteOffset returns the byte offset after the first units UTF-16 code units of text
+func byteOffset(text string, units int) int {
+	n := 0
+	for i, r := range text {
+		n += utf16.RuneLen(r)
+		if n > units {
+			return i
+		}
+	}
+	return len(text)
+}
@@ -0,0 +1,75 @@
+package render
+
+import "fmt"
+
+// TutorialCards are the onboarding tutorial pages shown on the first /start and by /tutorial
+var TutorialCards = []string{
+	`👋 Привет! Я помогаю превратить мысли о проекте в готовые бизнес-требования.
+
+Сессия проходит так:
+1. Ты описываешь цель проекта
+2. Выбираешь проект с материалами или рассказываешь контекст сам
+3. Выбираешь режим: «Интервью» или «Драфт»
+4. Получаешь документ с требованиями
+
+Листай дальше, чтобы узнать, чем отличаются режимы.`,
+
+	`📝 Режим «Интервью»
+
+Я задаю вопросы блоками, а ты отвечаешь текстом или голосовым. Например:
+
+📌 Общая информация о проекте
+❓ Вопрос 1 из 3: Кто является целевой аудиторией?
+💬 «Постоянные клиенты барбершопа и администраторы»
+
+Вопрос можно пропустить, вернуться к предыдущему или попросить пояснение. Подходит, когда требования ещё не сформулированы.`,
+
+	`📄 Режим «Драфт»
+
+Присылай материалы в свободной форме: заметки, пересланные сообщения, голосовые. Например:
+
+💬 «Нужна онлайн-запись к мастеру»
+💬 «Клиенты часто забывают о визите, нужны напоминания»
+
+Когда материалы собраны, я задам уточняющие вопросы, если чего-то не хватает. Подходит, когда мысли уже есть, но разрознены.`,
+
+	`✅ Результат
+
+После ответов я сформирую бизнес-требования: обзор, функциональные и нефункциональные требования, ограничения. Документ можно скачать в Markdown, DOCX или PDF, перевести и сохранить в проект.
+
+Полезные команды:
+/cancel — отменить сессию
+/tutorial — открыть это обучение снова
+/help — все команды
+
+Попробуй демо на трёх вопросах или начни свою сессию.`,
+}
+
+const (
+	MsgDemoIntro = `🎮 Демо-интервью: проект «%s».
+
+Я задам %d вопроса, а ответы подставлю за тебя. Настоящая сессия при этом не создаётся.`
+	MsgDemoQuestion = `❓ Вопрос %d из %d: %s
+
+💡 %s`
+	MsgDemoAnswer   = `💬 Ответ: %s`
+	MsgDemoFinished = `🎉 Готово! Так выглядят требования по итогам интервью. В настоящей сессии вопросы строятся по твоей цели и материалам проекта.`
+
+	MsgDemoSessionOffer = `🧪 Демо-сессия — песочница, где можно пройти весь путь на своей цели, ничего не тратя.
+
+• Вопросы и требования генерирует тестовый движок, а не настоящая модель
+• Лимит запросов в песочнице не действует
+• Сохранить результат в проект и отправить на согласование нельзя
+• Сессия удаляется автоматически через несколько часов`
+
+	MsgDemoSessionStarted = `🧪 Это демо-сессия: ответы тестовые, а сессия скоро удалится. Для настоящей работы используй /start.`
+)
+
+// RenderDemoQuestion formats a demo interview question with its block title and explanation
+func RenderDemoQuestion(title string, number, total int, question, explanation string) string {
+	text := fmt.Sprintf(MsgDemoQuestion, number, total, question, explanation)
+	if title == "" {
+		return text
+	}
+	return "📌 " + title + "\n\n" + text
+}
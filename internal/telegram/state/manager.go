@@ -5,6 +5,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"time"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
 )
 
 // contextKey is a type for context keys to avoid collisions
@@ -23,15 +27,32 @@ func ContextWithStateData(ctx context.Context, data *StateData) context.Context
 	return context.WithValue(ctx, stateDataKey, data)
 }
 
+// QuestionNumbering selects how questions are numbered in bot messages
+type QuestionNumbering string
+
+const (
+	// NumberingBlock numbers questions within their block: "Вопрос 2 из 5"
+	NumberingBlock QuestionNumbering = "block"
+	// NumberingGlobal numbers questions across the whole session: "Вопрос 7 из 15"
+	NumberingGlobal QuestionNumbering = "global"
+)
+
 // Manager manages telegram sessions
 type Manager struct {
-	storage Storage
+	storage           Storage
+	questionNumbering QuestionNumbering
+	progressHeader    bool
+	location          *time.Location
 }
 
-// NewManager creates a new state manager
-func NewManager(storage Storage) *Manager {
+// NewManager creates a new state manager; questionNumbering, progressHeader and location are used
+// for users who have not chosen a numbering, the progress header or a timezone themselves
+func NewManager(storage Storage, questionNumbering QuestionNumbering, progressHeader bool, location *time.Location) *Manager {
 	return &Manager{
-		storage: storage,
+		storage:           storage,
+		questionNumbering: questionNumbering,
+		progressHeader:    progressHeader,
+		location:          location,
 	}
 }
 
@@ -76,7 +97,8 @@ func (m *Manager) DeleteSession(ctx context.Context, userID int64) error {
 }
 
 // GetStateData extracts typed state data
-// First checks context for cached data, then loads from storage if needed
+// First checks context for cached data, then loads from storage if needed;
+// blobs of older versions are upgraded on read and stored upgraded on the next write
 func (m *Manager) GetStateData(ctx context.Context, userID int64) (*StateData, error) {
 	// Check context cache first
 	if data, ok := StateDataFromContext(ctx); ok {
@@ -96,17 +118,21 @@ func (m *Manager) GetStateData(ctx context.Context, userID int64) (*StateData, e
 		}, nil
 	}
 
-	var data StateData
-	if err := json.Unmarshal(session.StateData, &data); err != nil {
-		return nil, fmt.Errorf("unmarshal state data: %w", err)
+	data, err := DecodeStateData(session.StateData)
+	if err != nil {
+		return nil, err
 	}
 
-	// Auto-upgrade from old versions without version field
-	if data.Version == 0 {
-		data.Version = StateDataCurrentVersion
+	// Written by a newer bot, e.g. before a rollback: unknown fields are dropped on the next write
+	if data.Version > StateDataCurrentVersion {
+		ctxzap.Warn(ctx, "state data of a newer version",
+			zap.Int64("user_id", userID),
+			zap.Int("version", data.Version),
+			zap.Int("current_version", StateDataCurrentVersion),
+		)
 	}
 
-	return &data, nil
+	return data, nil
 }
 
 // UpdateStateData updates state data
@@ -119,6 +145,10 @@ func (m *Manager) UpdateStateData(ctx context.Context, userID int64, data *State
 	// Ensure version is set to current version
 	data.Version = StateDataCurrentVersion
 
+	if err := data.Validate(); err != nil {
+		return fmt.Errorf("validate state data: %w", err)
+	}
+
 	jsonData, err := json.Marshal(data)
 	if err != nil {
 		return fmt.Errorf("marshal state data: %w", err)
@@ -155,3 +185,198 @@ func (m *Manager) CreateOrUpdateSession(ctx context.Context, userID int64, sessi
 func (m *Manager) GetBySessionID(ctx context.Context, sessionID string) (*TelegramSession, error) {
 	return m.storage.GetBySessionID(ctx, sessionID)
 }
+
+// ToggleNormalizeTranscripts flips the transcription normalization preference of the user
+// and returns the new value
+func (m *Manager) ToggleNormalizeTranscripts(ctx context.Context, userID int64) (bool, error) {
+	enabled, err := m.storage.GetNormalizeTranscripts(ctx, userID)
+	if err != nil {
+		return false, fmt.Errorf("get normalize transcripts: %w", err)
+	}
+
+	if err := m.storage.SetNormalizeTranscripts(ctx, userID, !enabled); err != nil {
+		return false, fmt.Errorf("set normalize transcripts: %w", err)
+	}
+
+	return !enabled, nil
+}
+
+// GetQuestionNumbering returns the question numbering of the user, falling back to the default
+func (m *Manager) GetQuestionNumbering(ctx context.Context, userID int64) (QuestionNumbering, error) {
+	numbering, err := m.storage.GetQuestionNumbering(ctx, userID)
+	if err != nil {
+		return m.questionNumbering, fmt.Errorf("get question numbering: %w", err)
+	}
+	if numbering == "" {
+		return m.questionNumbering, nil
+	}
+
+	return numbering, nil
+}
+
+// ToggleQuestionNumbering switches the user between block and global numbering
+// and returns the new value
+func (m *Manager) ToggleQuestionNumbering(ctx context.Context, userID int64) (QuestionNumbering, error) {
+	numbering, err := m.GetQuestionNumbering(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+
+	next := NumberingGlobal
+	if numbering == NumberingGlobal {
+		next = NumberingBlock
+	}
+
+	if err := m.storage.SetQuestionNumbering(ctx, userID, next); err != nil {
+		return "", fmt.Errorf("set question numbering: %w", err)
+	}
+
+	return next, nil
+}
+
+// ShowProgressHeader reports whether question messages of the user show the progress header,
+// falling back to the default
+func (m *Manager) ShowProgressHeader(ctx context.Context, userID int64) (bool, error) {
+	show, err := m.storage.GetProgressHeader(ctx, userID)
+	if err != nil {
+		return m.progressHeader, fmt.Errorf("get progress header: %w", err)
+	}
+	if show == nil {
+		return m.progressHeader, nil
+	}
+
+	return *show, nil
+}
+
+// ToggleProgressHeader shows or hides the progress header of question messages of the user
+// and returns the new value
+func (m *Manager) ToggleProgressHeader(ctx context.Context, userID int64) (bool, error) {
+	show, err := m.ShowProgressHeader(ctx, userID)
+	if err != nil {
+		return false, err
+	}
+
+	if err := m.storage.SetProgressHeader(ctx, userID, !show); err != nil {
+		return false, fmt.Errorf("set progress header: %w", err)
+	}
+
+	return !show, nil
+}
+
+// GetSummaryStyle returns the document style the user chose last, empty when not chosen
+func (m *Manager) GetSummaryStyle(ctx context.Context, userID int64) (entity.SummaryStyle, error) {
+	style, err := m.storage.GetSummaryStyle(ctx, userID)
+	if err != nil {
+		return "", fmt.Errorf("get summary style: %w", err)
+	}
+
+	return style, nil
+}
+
+// SetSummaryStyle remembers the document style chosen by the user for the next sessions
+func (m *Manager) SetSummaryStyle(ctx context.Context, userID int64, style entity.SummaryStyle) error {
+	if !style.IsValid() {
+		return fmt.Errorf("unsupported summary style '%s': %w", style, entity.ErrInvalidParameter)
+	}
+
+	if err := m.storage.SetSummaryStyle(ctx, userID, style); err != nil {
+		return fmt.Errorf("set summary style: %w", err)
+	}
+
+	return nil
+}
+
+// GetLocation returns the timezone of the user, falling back to the default
+func (m *Manager) GetLocation(ctx context.Context, userID int64) (*time.Location, error) {
+	timezone, err := m.storage.GetTimezone(ctx, userID)
+	if err != nil {
+		return m.location, fmt.Errorf("get timezone: %w", err)
+	}
+	if timezone == "" {
+		return m.location, nil
+	}
+
+	loc, err := entity.LoadTimezone(timezone)
+	if err != nil {
+		return m.location, err
+	}
+
+	return loc, nil
+}
+
+// SetTimezone saves the IANA timezone chosen by the user and returns its location
+func (m *Manager) SetTimezone(ctx context.Context, userID int64, timezone string) (*time.Location, error) {
+	loc, err := entity.LoadTimezone(timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := m.storage.SetTimezone(ctx, userID, loc.String()); err != nil {
+		return nil, fmt.Errorf("set timezone: %w", err)
+	}
+
+	return loc, nil
+}
+
+// DetectTimezone saves the timezone guessed from the language of the client of a user who has
+// not chosen one; it reports the timezone saved, empty when there was nothing to guess
+func (m *Manager) DetectTimezone(ctx context.Context, userID int64, languageCode string) (string, error) {
+	guess := entity.TimezoneForLanguage(languageCode)
+	if guess == "" {
+		return "", nil
+	}
+
+	timezone, err := m.storage.GetTimezone(ctx, userID)
+	if err != nil {
+		return "", fmt.Errorf("get timezone: %w", err)
+	}
+	if timezone != "" {
+		return "", nil
+	}
+
+	if err := m.storage.SetTimezone(ctx, userID, guess); err != nil {
+		return "", fmt.Errorf("set timezone: %w", err)
+	}
+
+	return guess, nil
+}
+
+// MarkOnboarded records that the user has seen the onboarding tutorial and
+// reports whether this is the first time
+func (m *Manager) MarkOnboarded(ctx context.Context, userID int64) (bool, error) {
+	first, err := m.storage.MarkOnboarded(ctx, userID)
+	if err != nil {
+		return false, fmt.Errorf("mark onboarded: %w", err)
+	}
+
+	return first, nil
+}
+
+// SaveInboxMessage keeps the raw text of an incoming message until its submission succeeds
+func (m *Manager) SaveInboxMessage(ctx context.Context, message *InboxMessage) (*InboxMessage, error) {
+	saved, err := m.storage.SaveInboxMessage(ctx, message)
+	if err != nil {
+		return nil, fmt.Errorf("save inbox message: %w", err)
+	}
+
+	return saved, nil
+}
+
+// GetInboxMessage retrieves a kept message of the user, e.g. to send it again
+func (m *Manager) GetInboxMessage(ctx context.Context, userID int64, id string) (*InboxMessage, error) {
+	message, err := m.storage.GetInboxMessage(ctx, userID, id)
+	if err != nil {
+		return nil, fmt.Errorf("get inbox message: %w", err)
+	}
+
+	return message, nil
+}
+
+// DeleteInboxMessage drops a kept message of the user once it has been submitted
+func (m *Manager) DeleteInboxMessage(ctx context.Context, userID int64, id string) error {
+	if err := m.storage.DeleteInboxMessage(ctx, userID, id); err != nil {
+		return fmt.Errorf("delete inbox message: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,155 @@
+package state
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/futig/agent-backend/internal/config"
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+// cacheConnectTimeout bounds the check of the connection when the cache is created
+const cacheConnectTimeout = 5 * time.Second
+
+var _ Storage = &RedisCachedStorage{}
+
+// RedisCachedStorage keeps the telegram sessions of a storage in Redis, so reading the state of an
+// update does not query Postgres. Writes go to the storage first and then to Redis; a failing Redis
+// falls back to the storage. User preferences and the session joins are not cached
+type RedisCachedStorage struct {
+	Storage
+	client *redis.Client
+	prefix string
+	ttl    time.Duration
+	logger *zap.Logger
+}
+
+// NewRedisCachedStorage connects to the Redis of the bot store and wraps storage with the cache
+func NewRedisCachedStorage(
+	storage Storage,
+	storeCfg config.TelegramStoreConfig,
+	cacheCfg config.TelegramStateCacheConfig,
+	logger *zap.Logger,
+) (*RedisCachedStorage, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:     storeCfg.RedisAddr,
+		Password: storeCfg.RedisPassword,
+		DB:       storeCfg.RedisDB,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), cacheConnectTimeout)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("ping redis: %w", err)
+	}
+
+	return &RedisCachedStorage{
+		Storage: storage,
+		client:  client,
+		prefix:  storeCfg.KeyPrefix + "state:",
+		ttl:     cacheCfg.TTL,
+		logger:  logger,
+	}, nil
+}
+
+// Get returns the cached telegram session, loading it from the storage on a miss
+func (s *RedisCachedStorage) Get(ctx context.Context, userID int64) (*TelegramSession, error) {
+	key := s.key(ctx, userID)
+
+	data, err := s.client.Get(ctx, key).Bytes()
+	switch {
+	case errors.Is(err, redis.Nil):
+	case err != nil:
+		ctxzap.Warn(ctx, "failed to read cached telegram session, reading storage",
+			zap.Error(err),
+			zap.Int64("user_id", userID),
+		)
+		return s.Storage.Get(ctx, userID)
+	default:
+		var session TelegramSession
+		if err := json.Unmarshal(data, &session); err == nil {
+			return &session, nil
+		}
+		ctxzap.Warn(ctx, "failed to unmarshal cached telegram session", zap.Error(err), zap.Int64("user_id", userID))
+	}
+
+	session, err := s.Storage.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.cache(ctx, key, session)
+	return session, nil
+}
+
+// Set saves the telegram session to the storage and then to the cache. A failed write drops the
+// cached session: its row may be gone with the session it points to, e.g. removed by the demo purge
+// through ON DELETE CASCADE, so the write is rejected and the next read has to see the storage
+func (s *RedisCachedStorage) Set(ctx context.Context, session *TelegramSession) error {
+	if err := s.Storage.Set(ctx, session); err != nil {
+		s.invalidate(ctx, s.key(ctx, session.UserID))
+		return err
+	}
+
+	s.cache(ctx, s.key(ctx, session.UserID), session)
+	return nil
+}
+
+// Delete removes the telegram session from the storage and invalidates the cached one
+func (s *RedisCachedStorage) Delete(ctx context.Context, userID int64) error {
+	if err := s.Storage.Delete(ctx, userID); err != nil {
+		return err
+	}
+
+	s.invalidate(ctx, s.key(ctx, userID))
+	return nil
+}
+
+// Forget drops the cached session of a user of a tenant, for writes to the storage that do not go
+// through the cache, such as the state data backfill
+func (s *RedisCachedStorage) Forget(ctx context.Context, tenantID string, userID int64) {
+	s.invalidate(ctx, s.tenantKey(tenantID, userID))
+}
+
+// Close closes the connections to Redis
+func (s *RedisCachedStorage) Close() error {
+	return s.client.Close()
+}
+
+// cache stores the session for the TTL; when that fails, the previous value is dropped,
+// so the next read does not see a stale state
+func (s *RedisCachedStorage) cache(ctx context.Context, key string, session *TelegramSession) {
+	data, err := json.Marshal(session)
+	if err != nil {
+		ctxzap.Warn(ctx, "failed to marshal telegram session for cache", zap.Error(err))
+		s.invalidate(ctx, key)
+		return
+	}
+
+	if err := s.client.Set(ctx, key, data, s.ttl).Err(); err != nil {
+		ctxzap.Warn(ctx, "failed to cache telegram session", zap.Error(err), zap.String("key", key))
+		s.invalidate(ctx, key)
+	}
+}
+
+func (s *RedisCachedStorage) invalidate(ctx context.Context, key string) {
+	if err := s.client.Del(ctx, key).Err(); err != nil {
+		ctxzap.Warn(ctx, "failed to invalidate cached telegram session", zap.Error(err), zap.String("key", key))
+	}
+}
+
+// key scopes the cached session to the tenant of ctx like the storage scopes its rows
+func (s *RedisCachedStorage) key(ctx context.Context, userID int64) string {
+	return s.tenantKey(entity.TenantIDFromContext(ctx), userID)
+}
+
+func (s *RedisCachedStorage) tenantKey(tenantID string, userID int64) string {
+	return fmt.Sprintf("%s%s:%d", s.prefix, tenantID, userID)
+}
@@ -0,0 +1,154 @@
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/futig/agent-backend/internal/entity"
+)
+
+// StateDataCurrentVersion is the current version of StateData
+//
+// Version history:
+//   - 1: the version field was added; older blobs have none and count as version 0
+//   - 2: is_processing and processing_started were dropped, heavy operations are
+//     registered in session_operations instead
+const StateDataCurrentVersion = 2
+
+// stateDataMigration upgrades the fields of a StateData blob by one version
+type stateDataMigration func(fields map[string]json.RawMessage) error
+
+// stateDataMigrations is indexed by the version a migration upgrades from, so it holds
+// exactly StateDataCurrentVersion migrations. A renamed or reshaped field needs a new
+// migration here and a bumped StateDataCurrentVersion, otherwise stored values are lost
+var stateDataMigrations = []stateDataMigration{
+	// 0 -> 1: nothing to change, the version is stamped after the last migration
+	func(map[string]json.RawMessage) error { return nil },
+	// 1 -> 2
+	dropStateFields("is_processing", "processing_started"),
+}
+
+// dropStateFields returns a migration removing fields that are no longer read
+func dropStateFields(names ...string) stateDataMigration {
+	return func(fields map[string]json.RawMessage) error {
+		for _, name := range names {
+			delete(fields, name)
+		}
+		return nil
+	}
+}
+
+// UpgradeStateData applies the migrations from the version of the blob up to the current one
+// and reports whether the blob changed. Blobs of the current or a newer version are returned
+// as they are, so a rolled back bot keeps reading the state written by a newer one
+func UpgradeStateData(raw json.RawMessage) (json.RawMessage, bool, error) {
+	fields := map[string]json.RawMessage{}
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &fields); err != nil {
+			return nil, false, fmt.Errorf("unmarshal state data: %w", err)
+		}
+	}
+
+	version := 0
+	if value, ok := fields["version"]; ok {
+		if err := json.Unmarshal(value, &version); err != nil {
+			return nil, false, fmt.Errorf("unmarshal state data version: %w", err)
+		}
+	}
+
+	if version >= StateDataCurrentVersion {
+		return raw, false, nil
+	}
+
+	for ; version < StateDataCurrentVersion; version++ {
+		if err := stateDataMigrations[version](fields); err != nil {
+			return nil, false, fmt.Errorf("migrate state data from version %d: %w", version, err)
+		}
+	}
+	fields["version"] = json.RawMessage(strconv.Itoa(StateDataCurrentVersion))
+
+	upgraded, err := json.Marshal(fields)
+	if err != nil {
+		return nil, false, fmt.Errorf("marshal state data: %w", err)
+	}
+
+	return upgraded, true, nil
+}
+
+// DecodeStateData upgrades a stored blob to the current version and decodes it
+func DecodeStateData(raw json.RawMessage) (*StateData, error) {
+	upgraded, _, err := UpgradeStateData(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	var data StateData
+	if err := json.Unmarshal(upgraded, &data); err != nil {
+		return nil, fmt.Errorf("unmarshal state data: %w", err)
+	}
+
+	return &data, nil
+}
+
+// Validate checks the state before it is stored, so a broken flow fails on write
+// instead of leaving a blob no handler can continue from
+func (d *StateData) Validate() error {
+	if d.Version != StateDataCurrentVersion {
+		return fmt.Errorf("state data version %d, expected %d: %w", d.Version, StateDataCurrentVersion, entity.ErrInvalidParameter)
+	}
+
+	counters := []struct {
+		name  string
+		value int
+	}{
+		{"current_question_index", d.CurrentQuestionIndex},
+		{"draft_message_count", d.DraftMessageCount},
+		{"total_skipped_questions", d.TotalSkippedQuestions},
+		{"current_skipped_question_number", d.CurrentSkippedQuestionNumber},
+		{"current_skipped_question_index", d.CurrentSkippedQuestionIndex},
+		{"current_deferred_question_index", d.CurrentDeferredQuestionIndex},
+		{"project_list_page", d.ProjectListPage},
+		{"project_list_offset", d.ProjectListOffset},
+		{"last_message_id", d.LastMessageID},
+	}
+	for _, counter := range counters {
+		if counter.value < 0 {
+			return fmt.Errorf("negative %s %d: %w", counter.name, counter.value, entity.ErrInvalidParameter)
+		}
+	}
+
+	if d.RegenSectionIndex != nil && *d.RegenSectionIndex < 0 {
+		return fmt.Errorf("negative regen_section_index %d: %w", *d.RegenSectionIndex, entity.ErrInvalidParameter)
+	}
+
+	// The index reaches the length once the last question of the flow is answered
+	if d.CurrentSkippedQuestionIndex > len(d.SkippedQuestionIDs) {
+		return fmt.Errorf("current_skipped_question_index %d out of %d skipped questions: %w",
+			d.CurrentSkippedQuestionIndex, len(d.SkippedQuestionIDs), entity.ErrInvalidParameter)
+	}
+	if d.CurrentDeferredQuestionIndex > len(d.DeferredQuestionIDs) {
+		return fmt.Errorf("current_deferred_question_index %d out of %d deferred questions: %w",
+			d.CurrentDeferredQuestionIndex, len(d.DeferredQuestionIDs), entity.ErrInvalidParameter)
+	}
+
+	for name, ids := range map[string][]string{
+		"skipped_question_ids":  d.SkippedQuestionIDs,
+		"deferred_question_ids": d.DeferredQuestionIDs,
+		"next_question_ids":     d.NextQuestionIDs,
+	} {
+		for _, id := range ids {
+			if id == "" {
+				return fmt.Errorf("empty question ID in %s: %w", name, entity.ErrInvalidParameter)
+			}
+		}
+	}
+
+	for messageID, questionID := range d.QuestionMessages {
+		if questionID == "" {
+			return fmt.Errorf("empty question ID of message %d: %w", messageID, entity.ErrInvalidParameter)
+		}
+	}
+
+	return nil
+}
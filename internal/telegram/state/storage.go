@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"time"
+
+	"github.com/futig/agent-backend/internal/entity"
 )
 
 // TelegramSession represents telegram user -> session mapping with UI state
@@ -24,10 +26,10 @@ type TelegramSessionWithSession struct {
 	ProjectID       string // Empty if no active session or no project
 }
 
-// StateData contains telegram-specific UI state (stored in StateData JSONB)
-// Version 1: Initial implementation
+// StateData contains telegram-specific UI state (stored in StateData JSONB).
+// Stored blobs are upgraded on read by the migrations in schema.go
 type StateData struct {
-	// Version for compatibility tracking (current version: 1)
+	// Schema version of the blob, see StateDataCurrentVersion
 	Version int `json:"version,omitempty"`
 
 	// Context question tracking
@@ -43,6 +45,10 @@ type StateData struct {
 	CurrentSkippedQuestionNumber int      `json:"current_skipped_question_number,omitempty"` // Current position in skipped flow (1-based)
 	SkippedQuestionIDs           []string `json:"skipped_question_ids,omitempty"`            // List of all skipped question IDs
 	CurrentSkippedQuestionIndex  int      `json:"current_skipped_question_index,omitempty"`  // Current index in SkippedQuestionIDs (0-based)
+	// Deferred ("ask later") questions flow tracking, asked after the last block
+	AnsweringDeferred            bool     `json:"answering_deferred,omitempty"`
+	DeferredQuestionIDs          []string `json:"deferred_question_ids,omitempty"`           // Deferred question IDs in the order they are asked
+	CurrentDeferredQuestionIndex int      `json:"current_deferred_question_index,omitempty"` // Current index in DeferredQuestionIDs (0-based)
 	// Question history tracking (for back/forward navigation)
 	// Only one step back allowed
 	PreviousQuestionID string   `json:"previous_question_id,omitempty"` // Previous question ID (only one level back)
@@ -52,25 +58,44 @@ type StateData struct {
 	ProjectID         string `json:"project_id,omitempty"`
 	ProjectListPage   int    `json:"project_list_page,omitempty"`
 	ProjectListOffset int    `json:"project_list_offset,omitempty"`
+	// Next text message is the number of a selector page to jump to
+	AwaitingProjectPage bool `json:"awaiting_project_page,omitempty"`
 
 	// Project creation tracking (for save-to-new-project flow)
 	ProjectName string `json:"project_name,omitempty"`
 
+	// Result section selected for regeneration
+	RegenSectionIndex *int `json:"regen_section_index,omitempty"`
+
 	// Last message ID (for editing)
 	LastMessageID int `json:"last_message_id,omitempty"`
 
-	// Processing state (for idempotency)
-	IsProcessing      bool      `json:"is_processing,omitempty"`
-	ProcessingStarted time.Time `json:"processing_started,omitempty"`
+	// Sent question messages (message_id -> question_id) for reply-based answer routing
+	QuestionMessages map[int]string `json:"question_messages,omitempty"`
 
 	// Confirmation for destructive actions
 	PendingConfirmation string `json:"pending_confirmation,omitempty"` // "cancel", "finish"
+
+	// Next text message is a search query over collected material, not an answer
+	AwaitingSearch bool `json:"awaiting_search,omitempty"`
+
+	// Next text message replaces the answer to this earlier question instead of answering the current one
+	EditingQuestionID string `json:"editing_question_id,omitempty"`
+
+	// Detected answer language the user was already asked about, asked once per language
+	LanguageSwitchWarned string `json:"language_switch_warned,omitempty"`
 }
 
-const (
-	// StateDataCurrentVersion is the current version of StateData
-	StateDataCurrentVersion = 1
-)
+// InboxMessage is the raw text of a user's message kept until its submission succeeds,
+// so that a failed answer can be sent again without retyping
+type InboxMessage struct {
+	ID         string
+	UserID     int64
+	SessionID  string
+	QuestionID string // Empty when the text does not answer a known question
+	Text       string
+	CreatedAt  time.Time
+}
 
 // Storage defines the interface for telegram session persistence
 type Storage interface {
@@ -88,4 +113,48 @@ type Storage interface {
 
 	// GetBySessionID retrieves telegram session by session ID
 	GetBySessionID(ctx context.Context, sessionID string) (*TelegramSession, error)
+
+	// GetNormalizeTranscripts reports whether voice transcriptions of the user are normalized
+	GetNormalizeTranscripts(ctx context.Context, userID int64) (bool, error)
+
+	// SetNormalizeTranscripts saves the transcription normalization preference of the user
+	SetNormalizeTranscripts(ctx context.Context, userID int64, enabled bool) error
+
+	// GetQuestionNumbering returns the question numbering chosen by the user, empty when not chosen
+	GetQuestionNumbering(ctx context.Context, userID int64) (QuestionNumbering, error)
+
+	// SetQuestionNumbering saves the question numbering chosen by the user
+	SetQuestionNumbering(ctx context.Context, userID int64, numbering QuestionNumbering) error
+
+	// GetProgressHeader reports whether question messages of the user show the progress header,
+	// nil when not chosen
+	GetProgressHeader(ctx context.Context, userID int64) (*bool, error)
+
+	// SetProgressHeader saves whether question messages of the user show the progress header
+	SetProgressHeader(ctx context.Context, userID int64, show bool) error
+
+	// GetSummaryStyle returns the document style the user chose last, empty when not chosen
+	GetSummaryStyle(ctx context.Context, userID int64) (entity.SummaryStyle, error)
+
+	// SetSummaryStyle saves the document style chosen by the user
+	SetSummaryStyle(ctx context.Context, userID int64, style entity.SummaryStyle) error
+
+	// GetTimezone returns the IANA timezone chosen by the user, empty when not chosen
+	GetTimezone(ctx context.Context, userID int64) (string, error)
+
+	// SetTimezone saves the IANA timezone chosen by the user
+	SetTimezone(ctx context.Context, userID int64, timezone string) error
+
+	// MarkOnboarded records that the user has seen the onboarding tutorial;
+	// it reports true only the first time
+	MarkOnboarded(ctx context.Context, userID int64) (bool, error)
+
+	// SaveInboxMessage stores the raw text of an incoming message and returns it with its ID
+	SaveInboxMessage(ctx context.Context, message *InboxMessage) (*InboxMessage, error)
+
+	// GetInboxMessage retrieves a stored message of the user
+	GetInboxMessage(ctx context.Context, userID int64, id string) (*InboxMessage, error)
+
+	// DeleteInboxMessage removes a stored message of the user
+	DeleteInboxMessage(ctx context.Context, userID int64, id string) error
 }
@@ -0,0 +1,114 @@
+package store
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// cleanupInterval is how often expired entries are removed from the memory store
+const cleanupInterval = 10 * time.Minute
+
+var _ Store = &MemoryStore{}
+
+type memoryBucket struct {
+	bucket    Bucket
+	expiresAt time.Time
+}
+
+// MemoryStore keeps the state in the process; it is lost on restart and not shared between replicas
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]*memoryBucket
+	keys    map[string]time.Time // key -> expiry
+	stop    chan struct{}
+}
+
+// NewMemoryStore creates a new memory store
+func NewMemoryStore() *MemoryStore {
+	s := &MemoryStore{
+		buckets: make(map[string]*memoryBucket),
+		keys:    make(map[string]time.Time),
+		stop:    make(chan struct{}),
+	}
+
+	go s.cleanupExpired()
+
+	return s
+}
+
+// UpdateBucket applies update to the bucket of key
+func (s *MemoryStore) UpdateBucket(ctx context.Context, key string, ttl time.Duration, update func(bucket *Bucket)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	entry, ok := s.buckets[key]
+	if !ok || now.After(entry.expiresAt) {
+		entry = &memoryBucket{}
+		s.buckets[key] = entry
+	}
+
+	update(&entry.bucket)
+	entry.expiresAt = now.Add(ttl)
+
+	return nil
+}
+
+// SetIfAbsent sets key for ttl and reports whether it was absent
+func (s *MemoryStore) SetIfAbsent(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if expiresAt, ok := s.keys[key]; ok && now.Before(expiresAt) {
+		return false, nil
+	}
+
+	s.keys[key] = now.Add(ttl)
+	return true, nil
+}
+
+// Delete removes key
+func (s *MemoryStore) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.keys, key)
+	delete(s.buckets, key)
+	return nil
+}
+
+// Close stops the cleanup of expired entries
+func (s *MemoryStore) Close() error {
+	close(s.stop)
+	return nil
+}
+
+// cleanupExpired removes expired buckets and keys
+func (s *MemoryStore) cleanupExpired() {
+	ticker := time.NewTicker(cleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+		}
+
+		s.mu.Lock()
+		now := time.Now()
+		for key, entry := range s.buckets {
+			if now.After(entry.expiresAt) {
+				delete(s.buckets, key)
+			}
+		}
+		for key, expiresAt := range s.keys {
+			if now.After(expiresAt) {
+				delete(s.keys, key)
+			}
+		}
+		s.mu.Unlock()
+	}
+}
@@ -0,0 +1,110 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/futig/agent-backend/internal/config"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	// redisConnectTimeout bounds the check of the connection when the store is created
+	redisConnectTimeout = 5 * time.Second
+	// maxBucketUpdateAttempts bounds the retries of a bucket update racing with another replica
+	maxBucketUpdateAttempts = 5
+)
+
+var _ Store = &RedisStore{}
+
+// RedisStore keeps the state in Redis, so it survives restarts and is shared by the replicas of the bot
+type RedisStore struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewRedisStore connects to Redis and creates a new Redis store
+func NewRedisStore(cfg config.TelegramStoreConfig) (*RedisStore, error) {
+	client := redis.NewClient(&redis.Options{
+		Addr:     cfg.RedisAddr,
+		Password: cfg.RedisPassword,
+		DB:       cfg.RedisDB,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), redisConnectTimeout)
+	defer cancel()
+	if err := client.Ping(ctx).Err(); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("ping redis: %w", err)
+	}
+
+	return &RedisStore{
+		client: client,
+		prefix: cfg.KeyPrefix,
+	}, nil
+}
+
+// UpdateBucket applies update to the bucket of key in an optimistic transaction, retried when
+// another replica changed the bucket meanwhile
+func (s *RedisStore) UpdateBucket(ctx context.Context, key string, ttl time.Duration, update func(bucket *Bucket)) error {
+	key = s.prefix + key
+
+	apply := func(tx *redis.Tx) error {
+		var bucket Bucket
+		data, err := tx.Get(ctx, key).Bytes()
+		switch {
+		case errors.Is(err, redis.Nil):
+		case err != nil:
+			return fmt.Errorf("get bucket: %w", err)
+		default:
+			if err := json.Unmarshal(data, &bucket); err != nil {
+				return fmt.Errorf("unmarshal bucket: %w", err)
+			}
+		}
+
+		update(&bucket)
+
+		data, err = json.Marshal(bucket)
+		if err != nil {
+			return fmt.Errorf("marshal bucket: %w", err)
+		}
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.Set(ctx, key, data, ttl)
+			return nil
+		})
+		return err
+	}
+
+	for attempt := 0; attempt < maxBucketUpdateAttempts; attempt++ {
+		err := s.client.Watch(ctx, apply, key)
+		if !errors.Is(err, redis.TxFailedErr) {
+			return err
+		}
+	}
+	return fmt.Errorf("update bucket %s: %w", key, redis.TxFailedErr)
+}
+
+// SetIfAbsent sets key for ttl and reports whether it was absent
+func (s *RedisStore) SetIfAbsent(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	set, err := s.client.SetNX(ctx, s.prefix+key, 1, ttl).Result()
+	if err != nil {
+		return false, fmt.Errorf("set key: %w", err)
+	}
+	return set, nil
+}
+
+// Delete removes key
+func (s *RedisStore) Delete(ctx context.Context, key string) error {
+	if err := s.client.Del(ctx, s.prefix+key).Err(); err != nil {
+		return fmt.Errorf("delete key: %w", err)
+	}
+	return nil
+}
+
+// Close closes the connections to Redis
+func (s *RedisStore) Close() error {
+	return s.client.Close()
+}
@@ -0,0 +1,49 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/futig/agent-backend/internal/config"
+)
+
+// Store keeps the short-lived state of the bots: rate limit buckets of users and recent button
+// presses. The memory store keeps it in the process; the Redis store survives restarts
+// and is shared by the replicas of the bot
+type Store interface {
+	// UpdateBucket applies update to the bucket of key atomically; a missing bucket is passed zero.
+	// The bucket expires ttl after its last update. update may run more than once, so it must not
+	// have side effects beyond the bucket
+	UpdateBucket(ctx context.Context, key string, ttl time.Duration, update func(bucket *Bucket)) error
+
+	// SetIfAbsent sets key for ttl and reports whether it was absent
+	SetIfAbsent(ctx context.Context, key string, ttl time.Duration) (bool, error)
+
+	// Delete removes key
+	Delete(ctx context.Context, key string) error
+
+	// Close releases the connections of the store
+	Close() error
+}
+
+// Bucket is the rate limit state of a user
+type Bucket struct {
+	Tokens        float64   `json:"tokens"`
+	Reputation    float64   `json:"reputation,omitempty"`
+	LastRefill    time.Time `json:"last_refill"`
+	WarningsSent  int       `json:"warnings_sent,omitempty"`
+	LastWarningAt time.Time `json:"last_warning_at"`
+	MediaGroupID  string    `json:"media_group_id,omitempty"` // album items after the first one are not charged
+}
+
+// New creates the store of the configured backend
+func New(cfg config.TelegramStoreConfig) (Store, error) {
+	switch cfg.Backend {
+	case config.StoreBackendMemory:
+		return NewMemoryStore(), nil
+	case config.StoreBackendRedis:
+		return NewRedisStore(cfg)
+	}
+	return nil, fmt.Errorf("unknown store backend '%s'", cfg.Backend)
+}
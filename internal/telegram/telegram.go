@@ -5,9 +5,11 @@ import (
 	"fmt"
 
 	"github.com/futig/agent-backend/internal/config"
+	"github.com/futig/agent-backend/internal/entity"
 	"github.com/futig/agent-backend/internal/telegram/bot"
 	"github.com/futig/agent-backend/internal/telegram/handlers"
 	"github.com/futig/agent-backend/internal/telegram/state"
+	"github.com/futig/agent-backend/internal/telegram/store"
 	"github.com/futig/agent-backend/internal/usecase/project"
 	"go.uber.org/zap"
 )
@@ -18,26 +20,36 @@ type Bot interface {
 	Stop() error
 }
 
-// NewBot initializes the telegram bot with all dependencies
+// NewBot initializes a telegram bot with all dependencies; bots of one process share the
+// storage and use cases and are isolated by their tenants
 func NewBot(
 	cfg *config.TelegramConfig,
+	tenant *entity.Tenant,
 	contextQuestions []string,
 	storage state.Storage,
+	store store.Store,
 	sessionUC handlers.SessionUsecase,
 	projectUC *project.ProjectUsecase,
+	demoUC handlers.DemoUsecase,
+	linkUC handlers.AccountLinkUsecase,
+	userUC handlers.UserUsecase,
 	logger *zap.Logger,
-) (Bot, error) {
-	// Create state manager
-	stateManager := state.NewManager(storage)
+) (*bot.Bot, error) {
+	// Create state manager; the default timezone is validated with the config
+	location, err := entity.LoadTimezone(cfg.DefaultTimezone)
+	if err != nil {
+		return nil, fmt.Errorf("load default timezone: %w", err)
+	}
+	stateManager := state.NewManager(storage, state.QuestionNumbering(cfg.QuestionNumbering), cfg.ProgressHeader, location)
 
 	// Create bot instance
-	b, err := bot.New(cfg, stateManager, sessionUC, projectUC, contextQuestions, logger)
+	b, err := bot.New(cfg, tenant, stateManager, store, sessionUC, projectUC, linkUC, userUC, contextQuestions, logger)
 	if err != nil {
 		return nil, fmt.Errorf("create bot: %w", err)
 	}
 
 	// Register handlers
-	registerHandlers(b, logger)
+	registerHandlers(b, demoUC, logger)
 
 	logger.Info("telegram bot initialized successfully")
 
@@ -45,7 +57,7 @@ func NewBot(
 }
 
 // registerHandlers registers all handlers with the bot
-func registerHandlers(b *bot.Bot, logger *zap.Logger) {
+func registerHandlers(b *bot.Bot, demoUC handlers.DemoUsecase, logger *zap.Logger) {
 	// Get bot dependencies
 	api := b.GetAPI()
 	stateManager := b.GetStateManager()
@@ -56,7 +68,7 @@ func registerHandlers(b *bot.Bot, logger *zap.Logger) {
 	contextQuestions := b.GetContextQuestions()
 
 	// Register callback handler (handles all button clicks)
-	callbackHandler := handlers.NewCallbackHandler(api, stateManager, sessionUC, projectUC, contextQuestions, keyboard, logger)
+	callbackHandler := handlers.NewCallbackHandler(api, stateManager, sessionUC, projectUC, demoUC, contextQuestions, keyboard, logger)
 	b.RegisterHandler(callbackHandler)
 
 	// Register goal handler (ASK_USER_GOAL state)
@@ -83,12 +95,23 @@ func registerHandlers(b *bot.Bot, logger *zap.Logger) {
 	projectDescriptionHandler := handlers.NewProjectDescriptionHandler(api, stateManager, sessionUC, projectUC, keyboard, logger)
 	b.RegisterHandler(projectDescriptionHandler)
 
+	// Register section guidance handler (ASK_SECTION_GUIDANCE state)
+	sectionGuidanceHandler := handlers.NewSectionGuidanceHandler(api, stateManager, sessionUC, keyboard, logger)
+	b.RegisterHandler(sectionGuidanceHandler)
+
+	// Register result feedback handler (ASK_RESULT_FEEDBACK state)
+	resultFeedbackHandler := handlers.NewResultFeedbackHandler(api, stateManager, sessionUC, keyboard, logger)
+	b.RegisterHandler(resultFeedbackHandler)
+
+	// Register project selection handler (SELECT_OR_CREATE_PROJECT state)
+	projectSelectHandler := handlers.NewProjectSelectHandler(api, stateManager, projectUC, keyboard, logger)
+	b.RegisterHandler(projectSelectHandler)
+
 	logger.Info("telegram handlers registered",
-		zap.Int("handler_count", 7),
+		zap.Int("handler_count", 10),
 	)
 
 	// TODO: Optional handlers to implement:
-	// - ProjectHandler (SELECT_OR_CREATE_PROJECT state)
 	// - ContextHandler (ASK_USER_CONTEXT state)
 	// - ResultHandler (DONE state) - for displaying results
 }
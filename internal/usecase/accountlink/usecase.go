@@ -0,0 +1,144 @@
+package accountlink
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/futig/agent-backend/internal/config"
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/repository"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// codeAlphabet leaves out the characters that are easy to mistype, such as 0/O and 1/I
+const codeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// codeLength is the length of link codes, long enough to not be guessed within their lifetime
+const codeLength = 8
+
+// AccountLinkUsecase links API clients to Telegram users, so that a user can continue the
+// session of the bot in another client, e.g. the web client
+type AccountLinkUsecase struct {
+	linkRepo    repository.AccountLinkRepository
+	sessionRepo repository.SessionRepository
+	cfg         config.AccountLinkConfig
+	logger      *zap.Logger
+}
+
+// NewUsecase creates a new account link use case
+func NewUsecase(
+	linkRepo repository.AccountLinkRepository,
+	sessionRepo repository.SessionRepository,
+	cfg config.AccountLinkConfig,
+	logger *zap.Logger,
+) *AccountLinkUsecase {
+	return &AccountLinkUsecase{
+		linkRepo:    linkRepo,
+		sessionRepo: sessionRepo,
+		cfg:         cfg,
+		logger:      logger,
+	}
+}
+
+// IssueCode creates a one-time code that links the client redeeming it to the Telegram user
+func (uc *AccountLinkUsecase) IssueCode(ctx context.Context, telegramUserID int64) (*entity.AccountLinkCode, error) {
+	code, err := generateCode()
+	if err != nil {
+		return nil, fmt.Errorf("generate link code: %w", err)
+	}
+
+	linkCode, err := uc.linkRepo.CreateCode(ctx, code, telegramUserID, uc.cfg.CodeTTL)
+	if err != nil {
+		return nil, fmt.Errorf("save link code: %w", err)
+	}
+
+	ctxzap.Info(ctx, "account link code issued",
+		zap.Int64("telegram_user_id", telegramUserID),
+		zap.Time("expires_at", linkCode.ExpiresAt),
+	)
+
+	return linkCode, nil
+}
+
+// Redeem links the client to the Telegram user that issued the code and returns the user's
+// current session
+func (uc *AccountLinkUsecase) Redeem(ctx context.Context, clientID, code string) (*entity.LinkedSession, error) {
+	if clientID == "" {
+		return nil, fmt.Errorf("%w: X-Client-ID", entity.ErrMissingField)
+	}
+
+	code = normalizeCode(code)
+	if code == "" {
+		return nil, fmt.Errorf("%w: code", entity.ErrMissingField)
+	}
+
+	linkCode, err := uc.linkRepo.RedeemCode(ctx, code)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.linkRepo.SaveLink(ctx, clientID, linkCode.TelegramUserID); err != nil {
+		return nil, fmt.Errorf("save account link: %w", err)
+	}
+
+	ctxzap.Info(ctx, "client linked to telegram user",
+		zap.String("client_id", clientID),
+		zap.Int64("telegram_user_id", linkCode.TelegramUserID),
+	)
+
+	return uc.LinkedSession(ctx, clientID)
+}
+
+// LinkedSession returns the current session of the Telegram user the client is linked to
+func (uc *AccountLinkUsecase) LinkedSession(ctx context.Context, clientID string) (*entity.LinkedSession, error) {
+	if clientID == "" {
+		return nil, fmt.Errorf("%w: X-Client-ID", entity.ErrMissingField)
+	}
+
+	linked, err := uc.linkRepo.GetLinkedSession(ctx, clientID)
+	if err != nil {
+		return nil, err
+	}
+	if linked.SessionID == "" {
+		return linked, nil
+	}
+
+	session, err := uc.sessionRepo.GetSessionByID(ctx, linked.SessionID)
+	if err != nil {
+		if errors.Is(err, entity.ErrSessionNotFound) {
+			linked.SessionID = ""
+			return linked, nil
+		}
+		return nil, fmt.Errorf("get linked session: %w", err)
+	}
+	linked.Status = session.Status
+
+	return linked, nil
+}
+
+func generateCode() (string, error) {
+	raw := make([]byte, codeLength)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+
+	code := make([]byte, codeLength)
+	for i, b := range raw {
+		code[i] = codeAlphabet[int(b)%len(codeAlphabet)]
+	}
+	return string(code), nil
+}
+
+// normalizeCode accepts codes typed in lower case or with separators
+func normalizeCode(code string) string {
+	return strings.Map(func(r rune) rune {
+		if r == ' ' || r == '-' {
+			return -1
+		}
+		return r
+	}, strings.ToUpper(strings.TrimSpace(code)))
+}
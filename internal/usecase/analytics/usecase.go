@@ -0,0 +1,176 @@
+package analytics
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/futig/agent-backend/internal/config"
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/repository"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// hashLength is the number of bytes of the keyed hashes kept in the export
+const hashLength = 16
+
+// bucket is an upper bound of a generalized value and its label; the last bucket has no bound
+type bucket struct {
+	max   int
+	label string
+}
+
+var (
+	questionLengthBuckets = []bucket{{50, "1-50"}, {100, "51-100"}, {200, "101-200"}, {-1, "200+"}}
+	answerLengthBuckets   = []bucket{{0, "0"}, {20, "1-20"}, {100, "21-100"}, {500, "101-500"}, {2000, "501-2000"}, {-1, "2000+"}}
+	answerWordBuckets     = []bucket{{0, "0"}, {5, "1-5"}, {20, "6-20"}, {50, "21-50"}, {200, "51-200"}, {-1, "200+"}}
+	responseTimeBuckets   = []struct {
+		max   time.Duration
+		label string
+	}{
+		{time.Minute, "<1m"},
+		{5 * time.Minute, "1-5m"},
+		{30 * time.Minute, "5-30m"},
+		{2 * time.Hour, "30m-2h"},
+		{24 * time.Hour, "2h-1d"},
+	}
+)
+
+// AnalyticsUsecase exports the question-answer patterns of the tenant in ctx without customer
+// content: identifiers are replaced by keyed hashes and texts by their generalized lengths
+type AnalyticsUsecase struct {
+	analyticsRepo repository.AnalyticsRepository
+	cfg           config.AnalyticsConfig
+	logger        *zap.Logger
+}
+
+// NewUsecase creates a new analytics use case
+func NewUsecase(analyticsRepo repository.AnalyticsRepository, cfg config.AnalyticsConfig, logger *zap.Logger) *AnalyticsUsecase {
+	return &AnalyticsUsecase{
+		analyticsRepo: analyticsRepo,
+		cfg:           cfg,
+		logger:        logger,
+	}
+}
+
+// ExportAnswers returns the anonymized questions of the sessions created in [from, to), demo
+// sessions excluded. A period with more questions than a single export holds is rejected, so an
+// export is never silently cut short
+func (uc *AnalyticsUsecase) ExportAnswers(ctx context.Context, from, to time.Time) ([]*entity.AnalyticsAnswer, error) {
+	if !to.After(from) {
+		return nil, fmt.Errorf("%w: to must be after from", entity.ErrInvalidParameter)
+	}
+	if to.Sub(from) > uc.cfg.MaxRange {
+		return nil, fmt.Errorf("%w: the period must not exceed %s", entity.ErrInvalidParameter, uc.cfg.MaxRange)
+	}
+
+	stats, err := uc.analyticsRepo.ListAnswerStats(ctx, from.UTC(), to.UTC(), uc.cfg.MaxRows+1)
+	if err != nil {
+		return nil, fmt.Errorf("list answer stats: %w", err)
+	}
+	if len(stats) > uc.cfg.MaxRows {
+		return nil, fmt.Errorf("%w: more than %d questions in the period, narrow it", entity.ErrInvalidParameter, uc.cfg.MaxRows)
+	}
+
+	key, err := uc.hashKey()
+	if err != nil {
+		return nil, err
+	}
+
+	answers := make([]*entity.AnalyticsAnswer, 0, len(stats))
+	for _, s := range stats {
+		answers = append(answers, anonymize(key, s))
+	}
+
+	ctxzap.Info(ctx, "analytics answers exported",
+		zap.Time("from", from),
+		zap.Time("to", to),
+		zap.Int("rows", len(answers)),
+	)
+
+	return answers, nil
+}
+
+// hashKey returns the key of the hashes: the configured salt or a random key of this export only
+func (uc *AnalyticsUsecase) hashKey() ([]byte, error) {
+	if uc.cfg.HashSalt != "" {
+		return []byte(uc.cfg.HashSalt), nil
+	}
+
+	key := make([]byte, sha256.Size)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("generate hash key: %w", err)
+	}
+	return key, nil
+}
+
+func anonymize(key []byte, s *entity.AnswerStats) *entity.AnalyticsAnswer {
+	answer := &entity.AnalyticsAnswer{
+		SessionHash:     keyedHash(key, "session", s.SessionID),
+		SessionType:     string(s.SessionType),
+		SessionStatus:   string(s.SessionStatus),
+		HasProject:      s.HasProject,
+		SessionWeek:     isoWeek(s.SessionCreatedAt),
+		IterationNumber: int64(s.IterationNumber),
+		BlockCategory:   "generated",
+		QuestionNumber:  int64(s.QuestionNumber),
+		QuestionLength:  bucketLabel(questionLengthBuckets, s.QuestionChars),
+		Status:          string(s.Status),
+		AnswerType:      s.AnswerType,
+		FollowUp:        s.FollowUp,
+		AnswerLength:    bucketLabel(answerLengthBuckets, s.AnswerChars),
+		AnswerWords:     bucketLabel(answerWordBuckets, s.AnswerWords),
+		Normalized:      s.Normalized,
+	}
+
+	if s.OwnerID != nil {
+		answer.UserHash = keyedHash(key, "user", *s.OwnerID)
+	}
+	if s.Additional {
+		answer.BlockCategory = "additional"
+	}
+	if s.SkipReason != nil {
+		answer.SkipReason = string(*s.SkipReason)
+	}
+	if s.AnsweredAt != nil {
+		answer.ResponseTime = responseTime(s.AnsweredAt.Sub(s.CreatedAt))
+	}
+
+	return answer
+}
+
+// keyedHash hashes an identifier of the kind, so equal session and user IDs still differ
+func keyedHash(key []byte, kind, id string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(kind + ":" + id))
+	return hex.EncodeToString(mac.Sum(nil)[:hashLength])
+}
+
+func isoWeek(t time.Time) string {
+	year, week := t.ISOWeek()
+	return fmt.Sprintf("%d-W%02d", year, week)
+}
+
+// bucketLabel returns the label of the first bucket holding n; a bound below zero is open
+func bucketLabel(buckets []bucket, n int) string {
+	for _, b := range buckets {
+		if b.max < 0 || n <= b.max {
+			return b.label
+		}
+	}
+	return buckets[len(buckets)-1].label
+}
+
+func responseTime(d time.Duration) string {
+	for _, b := range responseTimeBuckets {
+		if d < b.max {
+			return b.label
+		}
+	}
+	return ">1d"
+}
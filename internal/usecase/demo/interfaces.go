@@ -0,0 +1,12 @@
+package demo
+
+import (
+	"context"
+
+	"github.com/futig/agent-backend/internal/entity"
+)
+
+type LLMConnector interface {
+	GenerateQuestions(ctx context.Context, req *entity.LLMGenerateQuestionsRequest) (*entity.LLMGenerateQuestionsResponse, error)
+	GenerateSummary(ctx context.Context, req *entity.LLMGenerateSummaryRequest) (string, error)
+}
@@ -0,0 +1,108 @@
+package demo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/futig/agent-backend/internal/entity"
+)
+
+// QuestionCount is the number of questions in the demo interview
+const QuestionCount = 3
+
+// demoGoal is the example project the demo interview is about
+const demoGoal = "Онлайн-запись клиентов в барбершоп"
+
+// sampleAnswers are the prepared answers to the demo questions, in order
+var sampleAnswers = [QuestionCount]string{
+	"Клиенты сами выбирают мастера, услугу и свободное время, а администратор больше не ведёт запись по телефону.",
+	"Постоянные клиенты барбершопа, которые записываются со смартфона, и администраторы, которые управляют расписанием.",
+	"Сократить пропуски визитов за счёт напоминаний и разгрузить администратора в часы пик.",
+}
+
+// DemoUsecase runs the onboarding demo interview. It works with the mock LLM connector and
+// keeps no state, so trying the demo creates no session and costs no LLM tokens
+type DemoUsecase struct {
+	llmConnector LLMConnector
+}
+
+// NewUsecase creates a new demo use case
+func NewUsecase(llmConnector LLMConnector) *DemoUsecase {
+	return &DemoUsecase{
+		llmConnector: llmConnector,
+	}
+}
+
+// GetQuestion returns the demo question with the given 0-based index
+func (uc *DemoUsecase) GetQuestion(ctx context.Context, index int) (*entity.DemoQuestion, error) {
+	if index < 0 || index >= QuestionCount {
+		return nil, fmt.Errorf("demo question index out of range: %d", index)
+	}
+
+	questions, err := uc.questions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	question := questions[index]
+	question.SampleAnswer = sampleAnswers[index]
+	return &question, nil
+}
+
+// GenerateSummary returns the requirements generated from the prepared answers
+func (uc *DemoUsecase) GenerateSummary(ctx context.Context) (string, error) {
+	questions, err := uc.questions(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	answered := make([]entity.QuestionWithAnswer, 0, len(questions))
+	for i, q := range questions {
+		answered = append(answered, entity.QuestionWithAnswer{
+			Question: q.Question,
+			Answer:   sampleAnswers[i],
+		})
+	}
+
+	result, err := uc.llmConnector.GenerateSummary(ctx, &entity.LLMGenerateSummaryRequest{
+		CompleteQuestions: answered,
+		UserGoal:          demoGoal,
+	})
+	if err != nil {
+		return "", fmt.Errorf("generate demo summary: %w", err)
+	}
+
+	return result, nil
+}
+
+// questions returns the first QuestionCount generated questions across blocks
+func (uc *DemoUsecase) questions(ctx context.Context) ([]entity.DemoQuestion, error) {
+	resp, err := uc.llmConnector.GenerateQuestions(ctx, &entity.LLMGenerateQuestionsRequest{
+		UserGoal: demoGoal,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("generate demo questions: %w", err)
+	}
+
+	questions := make([]entity.DemoQuestion, 0, QuestionCount)
+	for _, block := range resp.Iterations {
+		for _, q := range block.Questions {
+			if len(questions) == QuestionCount {
+				return questions, nil
+			}
+			questions = append(questions, entity.DemoQuestion{
+				Goal:        demoGoal,
+				Number:      len(questions) + 1,
+				Total:       QuestionCount,
+				Title:       block.Title,
+				Question:    q.Text,
+				Explanation: q.Explanation,
+			})
+		}
+	}
+
+	if len(questions) < QuestionCount {
+		return nil, fmt.Errorf("demo needs %d questions, got %d", QuestionCount, len(questions))
+	}
+	return questions, nil
+}
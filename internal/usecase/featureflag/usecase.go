@@ -0,0 +1,204 @@
+package featureflag
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/pkg/validator"
+	"github.com/futig/agent-backend/internal/repository"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// FeatureFlagUsecase evaluates feature flags and manages their admin overrides.
+// A flag is on for a stable share of subjects: the configured rollout percent,
+// unless an override from the database replaces the percent or disables the flag.
+type FeatureFlagUsecase struct {
+	flagRepo        repository.FeatureFlagRepository
+	rollouts        map[string]int
+	refreshInterval time.Duration
+	validator       *validator.Validator
+	logger          *zap.Logger
+
+	mu        sync.Mutex
+	overrides map[string]*entity.FeatureFlagOverride
+	loadedAt  time.Time
+}
+
+// NewUsecase creates a new feature flag use case
+func NewUsecase(
+	flagRepo repository.FeatureFlagRepository,
+	rollouts map[string]int,
+	refreshInterval time.Duration,
+	validator *validator.Validator,
+	logger *zap.Logger,
+) *FeatureFlagUsecase {
+	for name := range rollouts {
+		if !entity.IsKnownFeatureFlag(name) {
+			logger.Warn("rollout configured for unknown feature flag", zap.String("flag", name))
+		}
+	}
+
+	return &FeatureFlagUsecase{
+		flagRepo:        flagRepo,
+		rollouts:        rollouts,
+		refreshInterval: refreshInterval,
+		validator:       validator,
+		logger:          logger,
+	}
+}
+
+// Enabled reports whether flag is on for subject, e.g. a session ID; a subject stays in
+// or out of the rollout as long as the percent does not change
+func (uc *FeatureFlagUsecase) Enabled(ctx context.Context, flag entity.FeatureFlag, subject string) bool {
+	state := uc.state(string(flag), uc.loadOverrides(ctx))
+	if state.Disabled || state.RolloutPercent <= 0 {
+		return false
+	}
+	if state.RolloutPercent >= 100 {
+		return true
+	}
+
+	return bucket(flag, subject) < state.RolloutPercent
+}
+
+// ListFlags returns the effective state of every known flag
+func (uc *FeatureFlagUsecase) ListFlags(ctx context.Context) ([]*entity.FeatureFlagState, error) {
+	overrides, err := uc.reloadOverrides(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	states := make([]*entity.FeatureFlagState, 0, len(entity.KnownFeatureFlags))
+	for _, flag := range entity.KnownFeatureFlags {
+		states = append(states, uc.state(string(flag), overrides))
+	}
+
+	return states, nil
+}
+
+// SetFlag overrides the rollout of a flag or disables it for everyone
+func (uc *FeatureFlagUsecase) SetFlag(ctx context.Context, name string, req *entity.SetFeatureFlagRequest) (*entity.FeatureFlagState, error) {
+	if !entity.IsKnownFeatureFlag(name) {
+		return nil, fmt.Errorf("%w: %s", entity.ErrFeatureFlagNotFound, name)
+	}
+	if err := uc.validator.ValidateSetFeatureFlag(req); err != nil {
+		return nil, err
+	}
+
+	if _, err := uc.flagRepo.SetOverride(ctx, entity.FeatureFlagOverride{
+		Name:           name,
+		RolloutPercent: req.RolloutPercent,
+		Disabled:       req.Disabled,
+	}); err != nil {
+		return nil, err
+	}
+
+	overrides, err := uc.reloadOverrides(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	ctxzap.Info(ctx, "feature flag overridden",
+		zap.String("flag", name),
+		zap.Bool("disabled", req.Disabled),
+	)
+
+	return uc.state(name, overrides), nil
+}
+
+// ResetFlag removes the override of a flag, the configured rollout applies again
+func (uc *FeatureFlagUsecase) ResetFlag(ctx context.Context, name string) error {
+	if !entity.IsKnownFeatureFlag(name) {
+		return fmt.Errorf("%w: %s", entity.ErrFeatureFlagNotFound, name)
+	}
+
+	if err := uc.flagRepo.DeleteOverride(ctx, name); err != nil {
+		return err
+	}
+
+	if _, err := uc.reloadOverrides(ctx); err != nil {
+		return err
+	}
+
+	ctxzap.Info(ctx, "feature flag override removed", zap.String("flag", name))
+	return nil
+}
+
+// state combines the configured rollout of a flag with its override
+func (uc *FeatureFlagUsecase) state(name string, overrides map[string]*entity.FeatureFlagOverride) *entity.FeatureFlagState {
+	state := &entity.FeatureFlagState{
+		Name:              name,
+		ConfiguredPercent: uc.rollouts[name],
+		RolloutPercent:    uc.rollouts[name],
+	}
+
+	if override, ok := overrides[name]; ok {
+		state.OverridePercent = override.RolloutPercent
+		state.Disabled = override.Disabled
+		updatedAt := override.UpdatedAt
+		state.UpdatedAt = &updatedAt
+		if override.RolloutPercent != nil {
+			state.RolloutPercent = *override.RolloutPercent
+		}
+	}
+	if state.Disabled {
+		state.RolloutPercent = 0
+	}
+
+	return state
+}
+
+// loadOverrides returns the cached overrides, reloading them once refreshInterval has passed.
+// When the database is unavailable the last loaded overrides keep applying.
+func (uc *FeatureFlagUsecase) loadOverrides(ctx context.Context) map[string]*entity.FeatureFlagOverride {
+	uc.mu.Lock()
+	overrides, fresh := uc.overrides, time.Since(uc.loadedAt) < uc.refreshInterval
+	uc.mu.Unlock()
+	if fresh {
+		return overrides
+	}
+
+	reloaded, err := uc.reloadOverrides(ctx)
+	if err != nil {
+		ctxzap.Warn(ctx, "failed to load feature flag overrides, using the last loaded ones", zap.Error(err))
+		return overrides
+	}
+
+	return reloaded
+}
+
+func (uc *FeatureFlagUsecase) reloadOverrides(ctx context.Context) (map[string]*entity.FeatureFlagOverride, error) {
+	list, err := uc.flagRepo.ListOverrides(ctx)
+	if err != nil {
+		// Retry on the next refresh rather than on every evaluation
+		uc.mu.Lock()
+		uc.loadedAt = time.Now()
+		uc.mu.Unlock()
+		return nil, err
+	}
+
+	overrides := make(map[string]*entity.FeatureFlagOverride, len(list))
+	for _, override := range list {
+		overrides[override.Name] = override
+	}
+
+	uc.mu.Lock()
+	uc.overrides = overrides
+	uc.loadedAt = time.Now()
+	uc.mu.Unlock()
+
+	return overrides, nil
+}
+
+// bucket maps a subject to a stable 0..99 bucket of a flag; hashing the flag name with the subject
+// keeps the rollouts of different flags independent
+func bucket(flag entity.FeatureFlag, subject string) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(string(flag) + ":" + subject))
+	return int(h.Sum32() % 100)
+}
@@ -0,0 +1,87 @@
+package incident
+
+import (
+	"time"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"go.uber.org/zap/zapcore"
+)
+
+// recordTimeout bounds the write of an incident, which blocks the logging call
+const recordTimeout = 2 * time.Second
+
+// core is a zap core recording the entries of error level and above that carry a correlation ID
+type core struct {
+	uc            *IncidentUsecase
+	correlationID string
+	fields        []zapcore.Field
+}
+
+func (c *core) Enabled(level zapcore.Level) bool {
+	return level >= zapcore.ErrorLevel
+}
+
+func (c *core) With(fields []zapcore.Field) zapcore.Core {
+	clone := &core{
+		uc:            c.uc,
+		correlationID: c.correlationID,
+		fields:        make([]zapcore.Field, 0, len(c.fields)+len(fields)),
+	}
+	clone.fields = append(clone.fields, c.fields...)
+	clone.fields = append(clone.fields, fields...)
+	if correlationID := findCorrelationID(fields); correlationID != "" {
+		clone.correlationID = correlationID
+	}
+
+	return clone
+}
+
+func (c *core) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+func (c *core) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	correlationID := c.correlationID
+	if id := findCorrelationID(fields); id != "" {
+		correlationID = id
+	}
+	if correlationID == "" {
+		return nil
+	}
+
+	enc := zapcore.NewMapObjectEncoder()
+	for _, field := range c.fields {
+		field.AddTo(enc)
+	}
+	for _, field := range fields {
+		field.AddTo(enc)
+	}
+	delete(enc.Fields, entity.CorrelationIDField)
+
+	c.uc.record(&entity.Incident{
+		Code:          entity.IncidentCode(correlationID),
+		CorrelationID: correlationID,
+		Level:         ent.Level.String(),
+		Message:       ent.Message,
+		Fields:        enc.Fields,
+		CreatedAt:     ent.Time,
+	})
+
+	return nil
+}
+
+func (c *core) Sync() error {
+	return nil
+}
+
+func findCorrelationID(fields []zapcore.Field) string {
+	for _, field := range fields {
+		if field.Key == entity.CorrelationIDField && field.Type == zapcore.StringType {
+			return field.String
+		}
+	}
+	return ""
+}
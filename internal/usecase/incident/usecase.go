@@ -0,0 +1,78 @@
+package incident
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/repository"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// lookupLimit bounds the incidents returned for a code; codes of different correlation IDs may collide
+const lookupLimit = 50
+
+// IncidentUsecase records the errors of requests and bot updates as incidents and finds them by the
+// incident code shown to the user
+type IncidentUsecase struct {
+	incidentRepo repository.IncidentRepository
+	logger       *zap.Logger
+}
+
+// NewUsecase creates a new incident use case; logger must not record incidents itself, so a failed
+// write is not recorded again
+func NewUsecase(incidentRepo repository.IncidentRepository, logger *zap.Logger) *IncidentUsecase {
+	return &IncidentUsecase{
+		incidentRepo: incidentRepo,
+		logger:       logger,
+	}
+}
+
+// Core returns a zap core recording the errors logged with a correlation ID as incidents
+func (uc *IncidentUsecase) Core() zapcore.Core {
+	return &core{uc: uc}
+}
+
+// Lookup returns the incidents with the code, newest first
+func (uc *IncidentUsecase) Lookup(ctx context.Context, code string) ([]*entity.Incident, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if code == "" {
+		return nil, fmt.Errorf("%w: code", entity.ErrMissingField)
+	}
+
+	incidents, err := uc.incidentRepo.ListIncidentsByCode(ctx, code, lookupLimit)
+	if err != nil {
+		return nil, fmt.Errorf("list incidents: %w", err)
+	}
+	if len(incidents) == 0 {
+		return nil, entity.ErrIncidentNotFound
+	}
+
+	return incidents, nil
+}
+
+// PurgeIncidents removes incidents recorded before before
+func (uc *IncidentUsecase) PurgeIncidents(ctx context.Context, before time.Time) (int, error) {
+	deleted, err := uc.incidentRepo.DeleteIncidentsBefore(ctx, before)
+	if err != nil {
+		return 0, fmt.Errorf("delete incidents: %w", err)
+	}
+
+	return deleted, nil
+}
+
+// record stores an incident; failures are only logged, logging must not fail because of them
+func (uc *IncidentUsecase) record(incident *entity.Incident) {
+	ctx, cancel := context.WithTimeout(context.Background(), recordTimeout)
+	defer cancel()
+
+	if err := uc.incidentRepo.CreateIncident(ctx, incident); err != nil {
+		uc.logger.Warn("failed to record incident",
+			zap.Error(err),
+			zap.String("incident_code", incident.Code),
+		)
+	}
+}
@@ -0,0 +1,188 @@
+package operation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/repository"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// OperationUsecase tracks async workflows of the session API for clients polling by request ID,
+// along with the delivery of the callback events they sent
+type OperationUsecase struct {
+	operationRepo      repository.OperationRepository
+	callbackOutboxRepo repository.CallbackOutboxRepository
+	logger             *zap.Logger
+}
+
+// NewUsecase creates a new operation use case
+func NewUsecase(
+	operationRepo repository.OperationRepository,
+	callbackOutboxRepo repository.CallbackOutboxRepository,
+	logger *zap.Logger,
+) *OperationUsecase {
+	return &OperationUsecase{
+		operationRepo:      operationRepo,
+		callbackOutboxRepo: callbackOutboxRepo,
+		logger:             logger,
+	}
+}
+
+// TrackOperation records an accepted async request. Tracking is best-effort: failures are
+// logged and never fail the request itself. Requests without an ID are not tracked.
+func (uc *OperationUsecase) TrackOperation(
+	ctx context.Context,
+	requestID, clientID string,
+	kind entity.OperationKind,
+	sessionID string,
+) {
+	if requestID == "" {
+		return
+	}
+
+	operation := &entity.Operation{
+		RequestID: requestID,
+		Kind:      kind,
+		Status:    entity.OperationStatusQueued,
+	}
+	if clientID != "" {
+		operation.ClientID = &clientID
+	}
+	if sessionID != "" {
+		operation.SessionID = &sessionID
+	}
+
+	if _, err := uc.operationRepo.CreateOperation(ctx, operation); err != nil {
+		ctxzap.Warn(ctx, "failed to track operation",
+			zap.Error(err),
+			zap.String("request_id", requestID),
+		)
+	}
+}
+
+// MarkProcessing moves the operation out of the queue once its workflow starts
+func (uc *OperationUsecase) MarkProcessing(ctx context.Context, requestID string) {
+	if requestID == "" {
+		return
+	}
+
+	if err := uc.operationRepo.UpdateOperationStatus(ctx, requestID, entity.OperationStatusProcessing); err != nil {
+		ctxzap.Warn(ctx, "failed to mark operation as processing",
+			zap.Error(err),
+			zap.String("request_id", requestID),
+		)
+	}
+}
+
+// CompleteOperation stores the callback event produced by the workflow as the operation result
+func (uc *OperationUsecase) CompleteOperation(
+	ctx context.Context,
+	requestID string,
+	event entity.CallbackEventType,
+	data any,
+) {
+	if requestID == "" {
+		return
+	}
+
+	result, err := json.Marshal(data)
+	if err != nil {
+		ctxzap.Warn(ctx, "failed to encode operation result",
+			zap.Error(err),
+			zap.String("request_id", requestID),
+		)
+		return
+	}
+
+	status := entity.OperationStatusDone
+	if event == entity.CallbackEventTypeError {
+		status = entity.OperationStatusError
+	}
+
+	if err := uc.operationRepo.CompleteOperation(ctx, requestID, status, event, result); err != nil {
+		ctxzap.Warn(ctx, "failed to complete operation",
+			zap.Error(err),
+			zap.String("request_id", requestID),
+		)
+	}
+}
+
+// GetOperation returns the operation; operations started with a client ID are only visible to that client
+func (uc *OperationUsecase) GetOperation(ctx context.Context, requestID, clientID string) (*entity.Operation, error) {
+	operation, err := uc.operationRepo.GetOperation(ctx, requestID)
+	if err != nil {
+		return nil, fmt.Errorf("get operation: %w", err)
+	}
+
+	if operation.ClientID != nil && *operation.ClientID != clientID {
+		return nil, entity.ErrOperationNotFound
+	}
+
+	return operation, nil
+}
+
+// ListOperations pages through the operations of one client, newest first
+func (uc *OperationUsecase) ListOperations(
+	ctx context.Context,
+	req *entity.ListOperationsRequest,
+) (*entity.ListOperationsResponse, error) {
+	if req.ClientID == "" {
+		return nil, fmt.Errorf("%w: X-Client-ID", entity.ErrMissingField)
+	}
+
+	operations, total, err := uc.operationRepo.ListClientOperations(ctx, req.ClientID, req.Skip, req.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("list client operations: %w", err)
+	}
+
+	return &entity.ListOperationsResponse{
+		Operations: operations,
+		Total:      total,
+	}, nil
+}
+
+// PurgeOperations removes operations not updated since before
+func (uc *OperationUsecase) PurgeOperations(ctx context.Context, before time.Time) (int, error) {
+	deleted, err := uc.operationRepo.DeleteOperationsBefore(ctx, before)
+	if err != nil {
+		return 0, fmt.Errorf("delete operations: %w", err)
+	}
+
+	return deleted, nil
+}
+
+// ListCallbackDeliveries returns the delivery status of the callback events sent for a request of the
+// tenant; only events sent while the callback outbox is enabled are tracked
+func (uc *OperationUsecase) ListCallbackDeliveries(
+	ctx context.Context,
+	requestID string,
+) (*entity.ListCallbackDeliveriesResponse, error) {
+	deliveries, err := uc.callbackOutboxRepo.ListCallbackDeliveries(ctx, requestID)
+	if err != nil {
+		return nil, fmt.Errorf("list callback deliveries: %w", err)
+	}
+
+	if len(deliveries) == 0 {
+		return nil, entity.ErrCallbackDeliveryNotFound
+	}
+
+	return &entity.ListCallbackDeliveriesResponse{
+		RequestID:  requestID,
+		Deliveries: deliveries,
+	}, nil
+}
+
+// PurgeCallbackDeliveries removes delivered and failed callback events not updated since before
+func (uc *OperationUsecase) PurgeCallbackDeliveries(ctx context.Context, before time.Time) (int, error) {
+	deleted, err := uc.callbackOutboxRepo.DeleteSettledCallbackDeliveriesBefore(ctx, before)
+	if err != nil {
+		return 0, fmt.Errorf("delete callback deliveries: %w", err)
+	}
+
+	return deleted, nil
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"mime/multipart"
+	"strings"
 
 	"github.com/futig/agent-backend/internal/entity"
 	"github.com/futig/agent-backend/internal/pkg/validator"
@@ -46,15 +47,17 @@ func (uc *ProjectUsecase) prepareFileData(
 	return fileDataList, nil
 }
 
-// saveFileMetadata saves file metadata to database after successful RAG indexing
+// saveFileMetadata saves file metadata and content after successful RAG indexing;
+// fileData holds the prepared content of files in the same order
 func (uc *ProjectUsecase) saveFileMetadata(
 	ctx context.Context,
 	projectID string,
 	files []*multipart.FileHeader,
+	fileData []entity.FileData,
 ) ([]*entity.File, error) {
 	savedFiles := make([]*entity.File, 0, len(files))
 
-	for _, fh := range files {
+	for i, fh := range files {
 		fileID := uuid.New().String()
 
 		file := &entity.File{
@@ -65,9 +68,9 @@ func (uc *ProjectUsecase) saveFileMetadata(
 			ContentType: fh.Header.Get("Content-Type"),
 		}
 
-		savedFile, err := uc.projectFileRepo.AddFile(ctx, *file)
+		savedFile, err := uc.addFile(ctx, file, fileData[i].Content)
 		if err != nil {
-			uc.cleanupFileMetadata(ctx, uc.extractFileIDs(savedFiles))
+			uc.cleanupFileMetadata(ctx, savedFiles)
 			return nil, fmt.Errorf("save file metadata for %s: %w", fh.Filename, err)
 		}
 		savedFiles = append(savedFiles, savedFile)
@@ -82,23 +85,156 @@ func (uc *ProjectUsecase) saveFileMetadata(
 	return savedFiles, nil
 }
 
-// extractFileIDs extracts file IDs from a slice of files
-func (uc *ProjectUsecase) extractFileIDs(files []*entity.File) []string {
-	ids := make([]string, len(files))
-	for i, f := range files {
-		ids[i] = f.ID
+// addFile keeps the original content of a file in the blob storage and saves its metadata;
+// without the blob storage only the metadata is saved
+func (uc *ProjectUsecase) addFile(ctx context.Context, file *entity.File, content []byte) (*entity.File, error) {
+	if uc.fileStore != nil {
+		contentType := file.ContentType
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+
+		key := projectFileKey(ctx, file.ProjectID, file.ID)
+		if err := uc.fileStore.PutObject(ctx, key, content, contentType); err != nil {
+			return nil, fmt.Errorf("store file content: %w", err)
+		}
+		file.ObjectKey = &key
 	}
-	return ids
+
+	savedFile, err := uc.projectFileRepo.AddFile(ctx, *file)
+	if err != nil {
+		uc.deleteFileContent(ctx, file)
+		return nil, err
+	}
+
+	return savedFile, nil
 }
 
-// cleanupFileMetadata removes file metadata from database
-func (uc *ProjectUsecase) cleanupFileMetadata(ctx context.Context, fileIDs []string) {
-	for _, fileID := range fileIDs {
-		if err := uc.projectFileRepo.DeleteFile(ctx, fileID); err != nil {
+// getFile returns a file of a project the caller may see
+func (uc *ProjectUsecase) getFile(ctx context.Context, projectID, fileID string) (*entity.File, error) {
+	if _, err := uuid.Parse(projectID); err != nil {
+		return nil, fmt.Errorf("%w: invalid project ID format", entity.ErrInvalidParameter)
+	}
+	if _, err := uuid.Parse(fileID); err != nil {
+		return nil, fmt.Errorf("%w: invalid file ID format", entity.ErrInvalidParameter)
+	}
+
+	if _, err := uc.projectRepo.Get(ctx, projectID); err != nil {
+		return nil, err
+	}
+
+	file, err := uc.projectFileRepo.GetFile(ctx, projectID, fileID)
+	if err != nil {
+		return nil, err
+	}
+
+	return file, nil
+}
+
+// cleanupFileMetadata removes file metadata and stored content of saved files
+func (uc *ProjectUsecase) cleanupFileMetadata(ctx context.Context, files []*entity.File) {
+	for _, file := range files {
+		if err := uc.projectFileRepo.DeleteFile(ctx, file.ID); err != nil {
 			ctxzap.Warn(ctx, "failed to delete file metadata during cleanup",
-				zap.String("file_id", fileID),
+				zap.String("file_id", file.ID),
+				zap.Error(err),
+			)
+		}
+		uc.deleteFileContent(ctx, file)
+	}
+}
+
+// deleteFileContent removes the stored content of a file; a failure only leaves an unused object behind
+func (uc *ProjectUsecase) deleteFileContent(ctx context.Context, file *entity.File) {
+	if file.ObjectKey == nil || uc.fileStore == nil {
+		return
+	}
+
+	if err := uc.fileStore.DeleteObject(ctx, *file.ObjectKey); err != nil {
+		ctxzap.Warn(ctx, "failed to delete stored file content",
+			zap.String("file_id", file.ID),
+			zap.String("key", *file.ObjectKey),
+			zap.Error(err),
+		)
+	}
+}
+
+// reindexNamesakes indexes again the files of the project sharing the name of a deleted file, whose
+// chunks the RAG service removed with it; files without stored content stay out of the index
+func (uc *ProjectUsecase) reindexNamesakes(ctx context.Context, deleted *entity.File) {
+	files, err := uc.projectFileRepo.GetFiles(ctx, deleted.ProjectID)
+	if err != nil {
+		ctxzap.Warn(ctx, "failed to list files to index again", zap.Error(err))
+		return
+	}
+
+	for _, file := range files {
+		if file.Filename != deleted.Filename {
+			continue
+		}
+
+		if file.ObjectKey == nil || uc.fileStore == nil {
+			ctxzap.Warn(ctx, "file with the same name left out of the RAG index, its content is not stored",
+				zap.String("file_id", file.ID),
+			)
+			continue
+		}
+
+		content, err := uc.fileStore.GetObject(ctx, *file.ObjectKey)
+		if err == nil {
+			err = uc.ragConnector.IndexFiles(ctx, deleted.ProjectID, []entity.FileData{{
+				Filename: file.Filename,
+				Content:  content,
+			}})
+		}
+		if err != nil {
+			ctxzap.Warn(ctx, "failed to index file with the same name again",
+				zap.String("file_id", file.ID),
 				zap.Error(err),
 			)
 		}
 	}
 }
+
+// projectFileKey builds the blob storage key of the original content of a project file
+func projectFileKey(ctx context.Context, projectID, fileID string) string {
+	return fmt.Sprintf("files/%s/%s/%s", entity.TenantIDFromContext(ctx), projectID, fileID)
+}
+
+// describeProject replaces the description typed by the user with one generated from the saved
+// requirements; the typed description is kept when generation fails
+func (uc *ProjectUsecase) describeProject(ctx context.Context, project *entity.Project, requirements []byte) {
+	description, err := uc.llmConnector.DescribeProject(ctx, &entity.LLMDescribeProjectRequest{
+		Title:        project.Title,
+		Description:  project.Description,
+		Requirements: string(requirements),
+		MaxChars:     uc.descriptionCfg.MaxChars,
+	})
+	if err != nil {
+		ctxzap.Warn(ctx, "failed to generate project description, keeping the typed one",
+			zap.Error(err),
+			zap.String("project_id", project.ID),
+		)
+		return
+	}
+
+	description = strings.TrimSpace(description)
+	if runes := []rune(description); len(runes) > uc.descriptionCfg.MaxChars {
+		description = strings.TrimSpace(string(runes[:uc.descriptionCfg.MaxChars]))
+	}
+
+	if err := uc.projectRepo.SetDescription(ctx, project.ID, description); err != nil {
+		ctxzap.Warn(ctx, "failed to save generated project description",
+			zap.Error(err),
+			zap.String("project_id", project.ID),
+		)
+		return
+	}
+
+	project.Description = description
+
+	ctxzap.Info(ctx, "project description generated",
+		zap.String("project_id", project.ID),
+		zap.Int("description_length", len(description)),
+	)
+}
@@ -10,4 +10,21 @@ type RagConnector interface {
 	GetContext(ctx context.Context, req *entity.RAGGetContextRequest) (string, error)
 	IndexFiles(ctx context.Context, projectID string, files []entity.FileData) error
 	DeleteIndex(ctx context.Context, projectID string) error
+	DeleteFile(ctx context.Context, projectID, filename string) error
+}
+
+// FileStore keeps the original content of project files; it is the blob storage of generated results
+type FileStore interface {
+	PutObject(ctx context.Context, key string, data []byte, contentType string) error
+	GetObject(ctx context.Context, key string) ([]byte, error)
+	DeleteObject(ctx context.Context, key string) error
+}
+
+type LLMConnector interface {
+	DescribeProject(ctx context.Context, req *entity.LLMDescribeProjectRequest) (string, error)
+}
+
+// UserTimezones returns the timezones chosen by Telegram users, empty when a user has not chosen one
+type UserTimezones interface {
+	GetTimezone(ctx context.Context, userID int64) (string, error)
 }
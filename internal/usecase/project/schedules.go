@@ -0,0 +1,157 @@
+package project
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/pkg/cron"
+	"github.com/google/uuid"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// CreateSchedule sets up recurring check-in sessions for the project; cron is evaluated in the
+// requested timezone, by default in the timezone of the bound Telegram user
+func (uc *ProjectUsecase) CreateSchedule(
+	ctx context.Context,
+	req *entity.CreateScheduleRequest,
+) (*entity.ProjectSchedule, error) {
+	if _, err := uuid.Parse(req.ProjectID); err != nil {
+		return nil, fmt.Errorf("%w: invalid project ID format", entity.ErrInvalidParameter)
+	}
+
+	if _, err := uc.projectRepo.Get(ctx, req.ProjectID); err != nil {
+		return nil, err
+	}
+
+	sched, err := cron.Parse(req.CronExpr)
+	if err != nil {
+		return nil, fmt.Errorf("%w: cron: %v", entity.ErrInvalidParameter, err)
+	}
+
+	timezone := req.Timezone
+	if timezone == "" {
+		timezone = uc.userTimezone(ctx, req.TelegramUserID)
+	}
+	loc, err := entity.LoadTimezone(timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	nextRunAt := sched.Next(time.Now().In(loc))
+	if nextRunAt.IsZero() {
+		return nil, fmt.Errorf("%w: cron never fires", entity.ErrInvalidParameter)
+	}
+
+	goal := req.UserGoal
+	if goal == "" {
+		goal = entity.DefaultScheduleGoal
+	}
+
+	schedule, err := uc.scheduleRepo.CreateSchedule(ctx, &entity.ProjectSchedule{
+		ProjectID:      req.ProjectID,
+		CronExpr:       req.CronExpr,
+		Timezone:       loc.String(),
+		TelegramUserID: req.TelegramUserID,
+		UserGoal:       goal,
+		NextRunAt:      nextRunAt.UTC(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create schedule: %w", err)
+	}
+
+	ctxzap.Info(ctx, "project schedule created",
+		zap.String("schedule_id", schedule.ID),
+		zap.String("cron", schedule.CronExpr),
+		zap.String("timezone", schedule.Timezone),
+		zap.Time("next_run_at", schedule.NextRunAt),
+	)
+
+	return schedule, nil
+}
+
+// SetUserScheduleTimezone moves the schedules bound to the Telegram user to the timezone the user
+// chose, so check-ins keep firing at the same local hours
+func (uc *ProjectUsecase) SetUserScheduleTimezone(ctx context.Context, telegramUserID int64, timezone string) error {
+	loc, err := entity.LoadTimezone(timezone)
+	if err != nil {
+		return err
+	}
+
+	schedules, err := uc.scheduleRepo.ListUserSchedules(ctx, telegramUserID)
+	if err != nil {
+		return fmt.Errorf("list user schedules: %w", err)
+	}
+
+	now := time.Now().In(loc)
+	for _, schedule := range schedules {
+		if schedule.Timezone == loc.String() {
+			continue
+		}
+
+		sched, err := cron.Parse(schedule.CronExpr)
+		if err != nil {
+			ctxzap.Warn(ctx, "skipping schedule with invalid cron",
+				zap.Error(err),
+				zap.String("schedule_id", schedule.ID),
+			)
+			continue
+		}
+
+		if err := uc.scheduleRepo.SetTimezone(ctx, schedule.ID, loc.String(), sched.Next(now).UTC()); err != nil {
+			return fmt.Errorf("set schedule timezone: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// userTimezone returns the timezone chosen by the Telegram user, the default when the user
+// has not chosen one or it cannot be read
+func (uc *ProjectUsecase) userTimezone(ctx context.Context, telegramUserID int64) string {
+	timezone, err := uc.userTimezones.GetTimezone(ctx, telegramUserID)
+	if err != nil {
+		ctxzap.Warn(ctx, "failed to get user timezone, using default",
+			zap.Error(err),
+			zap.Int64("telegram_user_id", telegramUserID),
+		)
+	}
+	if timezone == "" {
+		return entity.DefaultTimezone
+	}
+	return timezone
+}
+
+// ListSchedules returns check-in schedules of the project
+func (uc *ProjectUsecase) ListSchedules(ctx context.Context, projectID string) ([]*entity.ProjectSchedule, error) {
+	if _, err := uuid.Parse(projectID); err != nil {
+		return nil, fmt.Errorf("%w: invalid project ID format", entity.ErrInvalidParameter)
+	}
+
+	if _, err := uc.projectRepo.Get(ctx, projectID); err != nil {
+		return nil, err
+	}
+
+	schedules, err := uc.scheduleRepo.ListSchedules(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("list schedules: %w", err)
+	}
+
+	return schedules, nil
+}
+
+// DeleteSchedule stops recurring check-in sessions; sessions already created are kept
+func (uc *ProjectUsecase) DeleteSchedule(ctx context.Context, projectID, scheduleID string) error {
+	if _, err := uuid.Parse(projectID); err != nil {
+		return fmt.Errorf("%w: invalid project ID format", entity.ErrInvalidParameter)
+	}
+
+	if err := uc.scheduleRepo.DeleteSchedule(ctx, projectID, scheduleID); err != nil {
+		return fmt.Errorf("delete schedule: %w", err)
+	}
+
+	ctxzap.Info(ctx, "project schedule deleted", zap.String("schedule_id", scheduleID))
+	return nil
+}
@@ -2,9 +2,13 @@ package project
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
+	"github.com/futig/agent-backend/internal/config"
 	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/pkg/tracing"
 	"github.com/futig/agent-backend/internal/pkg/validator"
 	"github.com/futig/agent-backend/internal/repository"
 	"github.com/google/uuid"
@@ -16,8 +20,14 @@ import (
 type ProjectUsecase struct {
 	projectRepo     repository.ProjectRepository
 	projectFileRepo repository.ProjectFileRepository
+	scheduleRepo    repository.ScheduleRepository
+	userTimezones   UserTimezones
 	validator       *validator.Validator
 	ragConnector    RagConnector
+	llmConnector    LLMConnector
+	fileStore       FileStore
+	descriptionCfg  config.ProjectDescriptionConfig
+	freshnessCfg    config.ProjectFreshnessConfig
 	logger          *zap.Logger
 }
 
@@ -25,15 +35,27 @@ type ProjectUsecase struct {
 func NewUsecase(
 	projectRepo repository.ProjectRepository,
 	projectFileRepo repository.ProjectFileRepository,
+	scheduleRepo repository.ScheduleRepository,
+	userTimezones UserTimezones,
 	validator *validator.Validator,
 	ragConnector RagConnector,
+	llmConnector LLMConnector,
+	fileStore FileStore,
+	descriptionCfg config.ProjectDescriptionConfig,
+	freshnessCfg config.ProjectFreshnessConfig,
 	logger *zap.Logger,
 ) *ProjectUsecase {
 	return &ProjectUsecase{
 		projectRepo:     projectRepo,
 		projectFileRepo: projectFileRepo,
+		scheduleRepo:    scheduleRepo,
+		userTimezones:   userTimezones,
 		validator:       validator,
 		ragConnector:    ragConnector,
+		llmConnector:    llmConnector,
+		fileStore:       fileStore,
+		descriptionCfg:  descriptionCfg,
+		freshnessCfg:    freshnessCfg,
 		logger:          logger,
 	}
 }
@@ -43,6 +65,9 @@ func (uc *ProjectUsecase) CreateProject(
 	ctx context.Context,
 	req *entity.CreateProjectRequest,
 ) (*entity.Project, error) {
+	ctx, span := tracing.Start(ctx, "ProjectUsecase.CreateProject")
+	defer span.End()
+
 	project := &entity.Project{
 		ID:          uuid.New().String(),
 		Title:       req.Title,
@@ -72,7 +97,7 @@ func (uc *ProjectUsecase) CreateProject(
 
 	ctxzap.Info(ctx, "files indexed in RAG successfully", zap.Int("file_count", len(fileDataList)))
 
-	savedFiles, err := uc.saveFileMetadata(ctx, project.ID, req.Files)
+	savedFiles, err := uc.saveFileMetadata(ctx, project.ID, req.Files, fileDataList)
 	if err != nil {
 		uc.ragConnector.DeleteIndex(ctx, project.ID)
 		uc.projectRepo.Delete(ctx, project.ID)
@@ -87,6 +112,9 @@ func (uc *ProjectUsecase) CreateProject(
 }
 
 func (uc *ProjectUsecase) AddFiles(ctx context.Context, req *entity.AddFilesRequest) ([]*entity.File, error) {
+	ctx, span := tracing.Start(ctx, "ProjectUsecase.AddFiles", tracing.ProjectID(req.ProjectID))
+	defer span.End()
+
 	if _, err := uc.projectRepo.Get(ctx, req.ProjectID); err != nil {
 		return nil, err
 	}
@@ -102,7 +130,7 @@ func (uc *ProjectUsecase) AddFiles(ctx context.Context, req *entity.AddFilesRequ
 
 	ctxzap.Info(ctx, "files indexed in RAG successfully", zap.Int("file_count", len(fileDataList)))
 
-	savedFiles, err := uc.saveFileMetadata(ctx, req.ProjectID, req.Files)
+	savedFiles, err := uc.saveFileMetadata(ctx, req.ProjectID, req.Files, fileDataList)
 	if err != nil {
 		return nil, fmt.Errorf("save file metadata: %w", err)
 	}
@@ -121,6 +149,9 @@ func (uc *ProjectUsecase) AddFileFromContent(
 	content []byte,
 	contentType string,
 ) (*entity.File, error) {
+	ctx, span := tracing.Start(ctx, "ProjectUsecase.AddFileFromContent", tracing.ProjectID(projectID))
+	defer span.End()
+
 	// Validate project exists
 	if _, err := uc.projectRepo.Get(ctx, projectID); err != nil {
 		return nil, err
@@ -152,7 +183,7 @@ func (uc *ProjectUsecase) AddFileFromContent(
 		ContentType: contentType,
 	}
 
-	savedFile, err := uc.projectFileRepo.AddFile(ctx, *file)
+	savedFile, err := uc.addFile(ctx, file, content)
 	if err != nil {
 		return nil, fmt.Errorf("save file metadata: %w", err)
 	}
@@ -175,6 +206,9 @@ func (uc *ProjectUsecase) CreateProjectFromContent(
 	content []byte,
 	contentType string,
 ) (*entity.Project, error) {
+	ctx, span := tracing.Start(ctx, "ProjectUsecase.CreateProjectFromContent")
+	defer span.End()
+
 	project := &entity.Project{
 		ID:          uuid.New().String(),
 		Title:       title,
@@ -217,7 +251,7 @@ func (uc *ProjectUsecase) CreateProjectFromContent(
 		ContentType: contentType,
 	}
 
-	savedFile, err := uc.projectFileRepo.AddFile(ctx, *file)
+	savedFile, err := uc.addFile(ctx, file, content)
 	if err != nil {
 		uc.ragConnector.DeleteIndex(ctx, project.ID)
 		uc.projectRepo.Delete(ctx, project.ID)
@@ -226,6 +260,10 @@ func (uc *ProjectUsecase) CreateProjectFromContent(
 
 	project.Files = []*entity.File{savedFile}
 
+	if uc.descriptionCfg.AutoGenerate {
+		uc.describeProject(ctx, project, content)
+	}
+
 	ctxzap.Info(ctx, "project created successfully with initial file",
 		zap.String("project_id", project.ID),
 		zap.String("file_id", savedFile.ID),
@@ -234,18 +272,83 @@ func (uc *ProjectUsecase) CreateProjectFromContent(
 	return project, nil
 }
 
-// ListProjects retrieves projects with pagination
-func (uc *ProjectUsecase) ListProjects(ctx context.Context, req *entity.ListProjectsRequest) ([]*entity.Project, error) {
-	projects, err := uc.projectRepo.List(ctx, req.Skip, req.Limit)
+// ListProjects retrieves a page of projects and the total number of projects paged through
+func (uc *ProjectUsecase) ListProjects(ctx context.Context, req *entity.ListProjectsRequest) ([]*entity.Project, int, error) {
+	ctx, span := tracing.Start(ctx, "ProjectUsecase.ListProjects")
+	defer span.End()
+
+	projects, total, err := uc.projectRepo.List(ctx, req.Skip, req.Limit, req.TelegramUserID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list projects: %w", err)
+	}
+	uc.markStale(projects)
+
+	return projects, total, nil
+}
+
+// MarkProjectUsed records that a Telegram user picked the project, so the selector lists it first
+func (uc *ProjectUsecase) MarkProjectUsed(ctx context.Context, projectID string, telegramUserID int64) error {
+	if err := uc.projectRepo.TouchUsage(ctx, projectID, telegramUserID); err != nil {
+		return fmt.Errorf("mark project used: %w", err)
+	}
+
+	return nil
+}
+
+// ListPinnedProjects returns the favorite projects of a Telegram user
+func (uc *ProjectUsecase) ListPinnedProjects(ctx context.Context, telegramUserID int64) ([]*entity.Project, error) {
+	projects, err := uc.projectRepo.ListPinned(ctx, telegramUserID)
 	if err != nil {
-		return nil, fmt.Errorf("list projects: %w", err)
+		return nil, fmt.Errorf("list pinned projects: %w", err)
 	}
+	uc.markStale(projects)
 
 	return projects, nil
 }
 
+// UnpinProject unpins a project of a Telegram user; unpinning a project that is not pinned is a no-op
+func (uc *ProjectUsecase) UnpinProject(ctx context.Context, projectID string, telegramUserID int64) error {
+	if _, err := uc.projectRepo.Unpin(ctx, projectID, telegramUserID); err != nil {
+		return fmt.Errorf("unpin project: %w", err)
+	}
+
+	return nil
+}
+
+// TogglePinnedProject pins or unpins a project for a Telegram user and reports whether it is pinned now
+func (uc *ProjectUsecase) TogglePinnedProject(ctx context.Context, projectID string, telegramUserID int64) (bool, error) {
+	if _, err := uuid.Parse(projectID); err != nil {
+		return false, fmt.Errorf("%w: invalid project ID format", entity.ErrInvalidParameter)
+	}
+
+	unpinned, err := uc.projectRepo.Unpin(ctx, projectID, telegramUserID)
+	if err != nil {
+		return false, fmt.Errorf("toggle pinned project: %w", err)
+	}
+	if unpinned {
+		return false, nil
+	}
+
+	if _, err := uc.projectRepo.Get(ctx, projectID); err != nil {
+		return false, fmt.Errorf("get project: %w", err)
+	}
+
+	pinned, err := uc.projectRepo.Pin(ctx, projectID, telegramUserID, entity.MaxPinnedProjects)
+	if err != nil {
+		return false, fmt.Errorf("toggle pinned project: %w", err)
+	}
+	if !pinned {
+		return false, entity.ErrPinLimitReached
+	}
+
+	return true, nil
+}
+
 // GetProject retrieves a project by ID
 func (uc *ProjectUsecase) GetProject(ctx context.Context, id string) (*entity.Project, error) {
+	ctx, span := tracing.Start(ctx, "ProjectUsecase.GetProject", tracing.ProjectID(id))
+	defer span.End()
+
 	if _, err := uuid.Parse(id); err != nil {
 		return nil, fmt.Errorf("%w: invalid project ID format", entity.ErrInvalidParameter)
 	}
@@ -258,12 +361,56 @@ func (uc *ProjectUsecase) GetProject(ctx context.Context, id string) (*entity.Pr
 	return project, nil
 }
 
+// GetProjectFreshness returns how old the indexed materials of a project are
+func (uc *ProjectUsecase) GetProjectFreshness(ctx context.Context, id string) (*entity.ProjectFreshness, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, fmt.Errorf("%w: invalid project ID format", entity.ErrInvalidParameter)
+	}
+
+	freshness, err := uc.projectRepo.GetFreshness(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("get project freshness: %w", err)
+	}
+	freshness.Stale = uc.isStale(freshness.LastIndexedAt)
+
+	return freshness, nil
+}
+
+// markStale flags listed projects whose files were last indexed too long ago
+func (uc *ProjectUsecase) markStale(projects []*entity.Project) {
+	for _, p := range projects {
+		p.Stale = uc.isStale(p.LastIndexedAt)
+	}
+}
+
+// isStale reports whether materials last indexed at lastIndexedAt are older than the configured threshold;
+// projects without files are never stale
+func (uc *ProjectUsecase) isStale(lastIndexedAt *time.Time) bool {
+	staleAfter := uc.freshnessCfg.StaleAfter
+	return staleAfter > 0 && lastIndexedAt != nil && time.Since(*lastIndexedAt) > staleAfter
+}
+
 // DeleteProject deletes a project and all its files
 func (uc *ProjectUsecase) DeleteProject(ctx context.Context, id string) error {
+	ctx, span := tracing.Start(ctx, "ProjectUsecase.DeleteProject", tracing.ProjectID(id))
+	defer span.End()
+
 	if _, err := uuid.Parse(id); err != nil {
 		return fmt.Errorf("%w: invalid project ID format", entity.ErrInvalidParameter)
 	}
 
+	// The RAG index is keyed by the project ID alone, so the project must be visible to the caller
+	if _, err := uc.projectRepo.Get(ctx, id); err != nil {
+		return err
+	}
+
+	// Stored file contents are listed before the file rows cascade and removed only once the
+	// project row is gone, so a delete that matched no row keeps them
+	files, err := uc.projectFileRepo.GetFiles(ctx, id)
+	if err != nil {
+		ctxzap.Warn(ctx, "failed to list files, their stored content is kept", zap.Error(err))
+	}
+
 	ctxzap.Info(ctx, "deleting RAG index")
 	if err := uc.ragConnector.DeleteIndex(ctx, id); err != nil {
 		return fmt.Errorf("delete RAG index: %w", err)
@@ -273,12 +420,20 @@ func (uc *ProjectUsecase) DeleteProject(ctx context.Context, id string) error {
 		return fmt.Errorf("delete project: %w", err)
 	}
 
+	// The project row is gone, its files can no longer be reached
+	for _, file := range files {
+		uc.deleteFileContent(ctx, file)
+	}
+
 	ctxzap.Info(ctx, "project deleted successfully")
 	return nil
 }
 
 // ListFiles retrieves all files for a project
 func (uc *ProjectUsecase) ListFiles(ctx context.Context, projectID string) ([]*entity.File, error) {
+	ctx, span := tracing.Start(ctx, "ProjectUsecase.ListFiles", tracing.ProjectID(projectID))
+	defer span.End()
+
 	if _, err := uuid.Parse(projectID); err != nil {
 		return nil, fmt.Errorf("%w: invalid project ID format", entity.ErrInvalidParameter)
 	}
@@ -294,3 +449,59 @@ func (uc *ProjectUsecase) ListFiles(ctx context.Context, projectID string) ([]*e
 
 	return files, nil
 }
+
+// GetFileContent returns a project file with its original content; ErrFileNotStored for files
+// uploaded before their content was kept or while the blob storage is disabled
+func (uc *ProjectUsecase) GetFileContent(ctx context.Context, projectID, fileID string) (*entity.File, []byte, error) {
+	ctx, span := tracing.Start(ctx, "ProjectUsecase.GetFileContent", tracing.ProjectID(projectID))
+	defer span.End()
+
+	file, err := uc.getFile(ctx, projectID, fileID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if file.ObjectKey == nil || uc.fileStore == nil {
+		return nil, nil, entity.ErrFileNotStored
+	}
+
+	content, err := uc.fileStore.GetObject(ctx, *file.ObjectKey)
+	if err != nil {
+		if errors.Is(err, entity.ErrObjectNotFound) {
+			return nil, nil, entity.ErrFileNotStored
+		}
+		return nil, nil, fmt.Errorf("get file content: %w", err)
+	}
+
+	return file, content, nil
+}
+
+// DeleteFile removes a file from the RAG index of its project, then its metadata and stored content.
+// The RAG service knows files by name, so other files of the project with the same name are indexed again
+func (uc *ProjectUsecase) DeleteFile(ctx context.Context, projectID, fileID string) error {
+	ctx, span := tracing.Start(ctx, "ProjectUsecase.DeleteFile", tracing.ProjectID(projectID))
+	defer span.End()
+
+	file, err := uc.getFile(ctx, projectID, fileID)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.ragConnector.DeleteFile(ctx, projectID, file.Filename); err != nil {
+		return fmt.Errorf("delete file from RAG: %w", err)
+	}
+
+	if err := uc.projectFileRepo.DeleteFile(ctx, fileID); err != nil {
+		return fmt.Errorf("delete file metadata: %w", err)
+	}
+
+	uc.deleteFileContent(ctx, file)
+	uc.reindexNamesakes(ctx, file)
+
+	ctxzap.Info(ctx, "file deleted",
+		zap.String("file_id", fileID),
+		zap.String("filename", file.Filename),
+	)
+
+	return nil
+}
@@ -0,0 +1,220 @@
+package quota
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/futig/agent-backend/internal/config"
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/repository"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// topSubjects is the number of heaviest users listed in the quota analytics
+const topSubjects = 20
+
+// QuotaUsecase enforces the usage quotas of users and tenants. Usage counts against the tenant
+// in ctx and, when ctx carries a usage subject, against that user too
+type QuotaUsecase struct {
+	quotaRepo repository.QuotaRepository
+	cfg       config.QuotaConfig
+	logger    *zap.Logger
+}
+
+// NewUsecase creates a new quota use case
+func NewUsecase(quotaRepo repository.QuotaRepository, cfg config.QuotaConfig, logger *zap.Logger) *QuotaUsecase {
+	return &QuotaUsecase{
+		quotaRepo: quotaRepo,
+		cfg:       cfg,
+		logger:    logger,
+	}
+}
+
+// Reserve counts one usage of kind against the quotas of the user and the tenant in ctx, or returns
+// ErrQuotaExceeded when one is used up. Checking and counting is one atomic step, so concurrent requests
+// cannot pass a limit, and a reservation that fails on the database is an error rather than an allowed usage.
+// release gives the usage back when the reserved operation fails
+func (uc *QuotaUsecase) Reserve(ctx context.Context, kind entity.QuotaKind) (release func(), err error) {
+	userLimit := uc.limit(kind, entity.QuotaScopeUser)
+	tenantLimit := uc.limit(kind, entity.QuotaScopeTenant)
+	if userLimit == 0 && tenantLimit == 0 {
+		return func() {}, nil
+	}
+
+	subject := entity.UsageSubjectFromContext(ctx)
+	limits := repository.QuotaLimits{Tenant: tenantLimit}
+	if subject != "" {
+		limits.Subject = userLimit
+	}
+
+	periodStart, resetsAt := period(kind, time.Now().UTC())
+	usageID, exceeded, err := uc.quotaRepo.ReserveUsage(ctx, subject, kind, periodStart, limits)
+	if err != nil {
+		return nil, fmt.Errorf("reserve quota: %w", err)
+	}
+	if exceeded != "" {
+		limit := limits.Tenant
+		if exceeded == entity.QuotaScopeUser {
+			limit = limits.Subject
+		}
+		return nil, fmt.Errorf("%w: %s %s limit of %d reached, resets at %s", entity.ErrQuotaExceeded,
+			exceeded, kind, limit, resetsAt.Format(time.RFC3339))
+	}
+
+	uc.warnNearlyUsedUp(ctx, kind)
+
+	return func() {
+		// The usage is given back even when the operation failed with its request canceled
+		ctx := context.WithoutCancel(ctx)
+		if err := uc.quotaRepo.ReleaseUsage(ctx, usageID, subject, kind, periodStart); err != nil {
+			ctxzap.Warn(ctx, "failed to release quota usage", zap.Error(err), zap.String("kind", string(kind)))
+		}
+	}, nil
+}
+
+// warnNearlyUsedUp logs the quotas of kind past the warn threshold
+func (uc *QuotaUsecase) warnNearlyUsedUp(ctx context.Context, kind entity.QuotaKind) {
+	statuses, err := uc.statuses(ctx, kind)
+	if err != nil {
+		ctxzap.Warn(ctx, "failed to check quota usage", zap.Error(err), zap.String("kind", string(kind)))
+		return
+	}
+
+	for _, status := range statuses {
+		if status.Warning {
+			ctxzap.Warn(ctx, "quota nearly used up",
+				zap.String("kind", string(status.Kind)),
+				zap.String("scope", string(status.Scope)),
+				zap.Int("used", status.Used),
+				zap.Int("limit", status.Limit),
+			)
+		}
+	}
+}
+
+// Usage returns the usage of every enabled quota of the user and the tenant in ctx
+func (uc *QuotaUsecase) Usage(ctx context.Context) (*entity.QuotaUsage, error) {
+	usage := &entity.QuotaUsage{
+		Subject: entity.UsageSubjectFromContext(ctx),
+		Quotas:  []entity.QuotaStatus{},
+	}
+
+	for _, kind := range []entity.QuotaKind{entity.QuotaKindSessions, entity.QuotaKindGenerations} {
+		statuses, err := uc.statuses(ctx, kind)
+		if err != nil {
+			return nil, err
+		}
+		usage.Quotas = append(usage.Quotas, statuses...)
+	}
+
+	return usage, nil
+}
+
+// Analytics returns the tenant quotas of the tenant in ctx with the users that use them most
+func (uc *QuotaUsecase) Analytics(ctx context.Context) (*entity.QuotaAnalytics, error) {
+	analytics := &entity.QuotaAnalytics{
+		TenantID:    entity.TenantIDFromContext(ctx),
+		Quotas:      []entity.QuotaStatus{},
+		TopSubjects: []entity.SubjectQuotaUsage{},
+	}
+
+	now := time.Now().UTC()
+	for _, kind := range []entity.QuotaKind{entity.QuotaKindSessions, entity.QuotaKindGenerations} {
+		since, resetsAt := period(kind, now)
+
+		tenantUsed, _, err := uc.quotaRepo.CountUsage(ctx, "", kind, since)
+		if err != nil {
+			return nil, err
+		}
+		analytics.Quotas = append(analytics.Quotas,
+			uc.status(kind, entity.QuotaScopeTenant, tenantUsed, uc.limit(kind, entity.QuotaScopeTenant), resetsAt))
+
+		subjects, err := uc.quotaRepo.ListUsageBySubject(ctx, kind, since, topSubjects)
+		if err != nil {
+			return nil, err
+		}
+		for _, subject := range subjects {
+			if subject.Subject == "" {
+				continue
+			}
+			subject.Limit = uc.limit(kind, entity.QuotaScopeUser)
+			analytics.TopSubjects = append(analytics.TopSubjects, subject)
+		}
+	}
+
+	return analytics, nil
+}
+
+// statuses returns the enabled quotas of kind for the user and the tenant in ctx
+func (uc *QuotaUsecase) statuses(ctx context.Context, kind entity.QuotaKind) ([]entity.QuotaStatus, error) {
+	subject := entity.UsageSubjectFromContext(ctx)
+	userLimit := uc.limit(kind, entity.QuotaScopeUser)
+	tenantLimit := uc.limit(kind, entity.QuotaScopeTenant)
+	if subject == "" {
+		userLimit = 0
+	}
+	if userLimit == 0 && tenantLimit == 0 {
+		return nil, nil
+	}
+
+	since, resetsAt := period(kind, time.Now().UTC())
+	tenantUsed, subjectUsed, err := uc.quotaRepo.CountUsage(ctx, subject, kind, since)
+	if err != nil {
+		return nil, err
+	}
+
+	var statuses []entity.QuotaStatus
+	if userLimit > 0 {
+		statuses = append(statuses, uc.status(kind, entity.QuotaScopeUser, subjectUsed, userLimit, resetsAt))
+	}
+	if tenantLimit > 0 {
+		statuses = append(statuses, uc.status(kind, entity.QuotaScopeTenant, tenantUsed, tenantLimit, resetsAt))
+	}
+
+	return statuses, nil
+}
+
+func (uc *QuotaUsecase) status(kind entity.QuotaKind, scope entity.QuotaScope, used, limit int, resetsAt time.Time) entity.QuotaStatus {
+	status := entity.QuotaStatus{
+		Kind:     kind,
+		Scope:    scope,
+		Used:     used,
+		Limit:    limit,
+		ResetsAt: resetsAt,
+	}
+	if limit > 0 {
+		status.Exceeded = used >= limit
+		status.Warning = float64(used) >= float64(limit)*uc.cfg.WarnThreshold
+	}
+
+	return status
+}
+
+// limit returns the configured limit of the quota, 0 when it is disabled
+func (uc *QuotaUsecase) limit(kind entity.QuotaKind, scope entity.QuotaScope) int {
+	switch {
+	case kind == entity.QuotaKindSessions && scope == entity.QuotaScopeUser:
+		return uc.cfg.UserSessionsPerDay
+	case kind == entity.QuotaKindSessions && scope == entity.QuotaScopeTenant:
+		return uc.cfg.TenantSessionsPerDay
+	case kind == entity.QuotaKindGenerations && scope == entity.QuotaScopeUser:
+		return uc.cfg.UserGenerationsPerMonth
+	case kind == entity.QuotaKindGenerations && scope == entity.QuotaScopeTenant:
+		return uc.cfg.TenantGenerationsPerMonth
+	}
+	return 0
+}
+
+// period returns the start of the current period of kind and the time it resets:
+// sessions count per UTC day, generations per UTC month
+func period(kind entity.QuotaKind, now time.Time) (time.Time, time.Time) {
+	if kind == entity.QuotaKindGenerations {
+		start := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+		return start, start.AddDate(0, 1, 0)
+	}
+
+	start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	return start, start.AddDate(0, 0, 1)
+}
@@ -0,0 +1,97 @@
+package session
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/pkg/tracing"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// ListAnsweredQuestions returns the answered questions of all blocks of the session in the order
+// they were asked, the answers the user may still edit before generation
+func (uc *SessionUsecase) ListAnsweredQuestions(ctx context.Context, sessionID string) ([]*entity.Question, error) {
+	questions, err := uc.questionRepo.ListQuestionsBySession(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("list questions: %w", err)
+	}
+
+	answered := make([]*entity.Question, 0, len(questions))
+	for _, q := range questions {
+		if q.Status == entity.AnswerStatusAnswered {
+			answered = append(answered, q)
+		}
+	}
+
+	return answered, nil
+}
+
+// UpdateAnswer replaces the answer to an already answered question of the session. Unlike
+// submitting an answer it neither moves the interview on nor changes the session status, so the
+// user continues from the question they were on. Answers are editable until generation starts
+func (uc *SessionUsecase) UpdateAnswer(ctx context.Context, sessionID, questionID, answer string) error {
+	ctx, span := tracing.Start(ctx, "SessionUsecase.UpdateAnswer", tracing.SessionID(sessionID))
+	defer span.End()
+
+	unlock, err := uc.lockSession(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	session, err := uc.sessionRepo.GetSessionByID(ctx, sessionID)
+	if err != nil {
+		return fmt.Errorf("get session: %w", err)
+	}
+
+	if session.Status != entity.SessionStatusWaitingForAnswers {
+		return fmt.Errorf("%w: answers cannot be edited on status '%s'", entity.ErrInvalidSessionStatus, session.Status)
+	}
+
+	question, err := uc.questionRepo.GetQuestionByID(ctx, questionID)
+	if err != nil {
+		return fmt.Errorf("get question: %w", err)
+	}
+
+	iteration, err := uc.iterationRepo.GetIterationByID(ctx, question.IterationID)
+	if err != nil {
+		return fmt.Errorf("get iteration: %w", err)
+	}
+	if iteration.SessionID != sessionID {
+		return fmt.Errorf("%w: question %s is not part of session %s", entity.ErrQuestionNotFound, questionID, sessionID)
+	}
+
+	if question.Status != entity.AnswerStatusAnswered {
+		return fmt.Errorf("%w: question %s is not answered", entity.ErrInvalidParameter, questionID)
+	}
+
+	answer, err = uc.moderateInput(ctx, sessionID, entity.ModerationSourceAnswer, answer)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.questionRepo.UpdateQuestionAnswer(ctx, sessionID, questionID, answer, nil); err != nil {
+		return fmt.Errorf("save answer: %w", err)
+	}
+	uc.logConversation(ctx, sessionID, questionID, entity.ConversationEntryEdit, answer)
+
+	previousLength := 0
+	if question.Answer != nil {
+		previousLength = len([]rune(*question.Answer))
+	}
+	if err := uc.auditRepo.RecordEvent(ctx, &entity.AuditEvent{
+		SessionID: sessionID,
+		Type:      entity.AuditEventAnswerEdited,
+		Details: map[string]any{
+			"question_id":     questionID,
+			"previous_length": previousLength,
+			"length":          len([]rune(answer)),
+		},
+	}); err != nil {
+		ctxzap.Error(ctx, "failed to record answer edit", zap.Error(err))
+	}
+
+	return nil
+}
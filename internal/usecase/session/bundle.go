@@ -0,0 +1,82 @@
+package session
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/futig/agent-backend/internal/entity"
+)
+
+// GetSessionBundle collects the result, questions with answers, draft messages and sections
+// of a releasable session for the bundle download
+func (uc *SessionUsecase) GetSessionBundle(ctx context.Context, sessionID string) (*entity.SessionBundle, error) {
+	if _, err := uc.GetSessionResult(ctx, sessionID); err != nil {
+		return nil, err
+	}
+
+	session, err := uc.sessionRepo.GetSessionByID(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("get session: %w", err)
+	}
+
+	bundleIterations, err := uc.bundleIterations(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	messages, err := uc.sessionMessageRepo.GetSessionMessages(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("get session messages: %w", err)
+	}
+	localizeMessageTimes(ctx, messages)
+
+	sections, err := uc.sectionRepo.ListSections(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("list result sections: %w", err)
+	}
+
+	fileInfo, err := uc.GetResultFileInfo(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.SessionBundle{
+		Session:        session,
+		Iterations:     bundleIterations,
+		DraftMessages:  messages,
+		ResultSections: sections,
+		FileInfo:       fileInfo,
+	}, nil
+}
+
+// bundleIterations returns the iterations of the session with their questions in interview order
+func (uc *SessionUsecase) bundleIterations(ctx context.Context, sessionID string) ([]*entity.BundleIteration, error) {
+	iterations, err := uc.iterationRepo.ListIterationsBySession(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("list iterations: %w", err)
+	}
+
+	questions, err := uc.questionRepo.ListQuestionsBySession(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("list questions: %w", err)
+	}
+
+	byIteration := make(map[string]*entity.BundleIteration, len(iterations))
+	bundleIterations := make([]*entity.BundleIteration, 0, len(iterations))
+	for _, iteration := range iterations {
+		bundleIteration := &entity.BundleIteration{
+			IterationNumber: iteration.IterationNumber,
+			Title:           iteration.Title,
+			Questions:       make([]*entity.Question, 0),
+		}
+		byIteration[iteration.ID] = bundleIteration
+		bundleIterations = append(bundleIterations, bundleIteration)
+	}
+	for _, question := range questions {
+		if bundleIteration, ok := byIteration[question.IterationID]; ok {
+			bundleIteration.Questions = append(bundleIteration.Questions, question)
+		}
+	}
+
+	return bundleIterations, nil
+}
@@ -0,0 +1,28 @@
+package session
+
+import (
+	"context"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// CallbackGranularity returns which callback events the session emits. When the session cannot be
+// read the default iteration granularity is used, so callbacks are not lost
+func (uc *SessionUsecase) CallbackGranularity(ctx context.Context, sessionID string) entity.CallbackGranularity {
+	session, err := uc.sessionRepo.GetSessionByID(ctx, sessionID)
+	if err != nil {
+		ctxzap.Warn(ctx, "failed to get callback granularity",
+			zap.String("session_id", sessionID),
+			zap.Error(err),
+		)
+		return entity.CallbackGranularityIteration
+	}
+
+	if session.CallbackGranularity == "" {
+		return entity.CallbackGranularityIteration
+	}
+
+	return session.CallbackGranularity
+}
@@ -0,0 +1,195 @@
+package session
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/pkg/tracing"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// AddComment attaches a reviewer comment to a section or requirement of the session result
+func (uc *SessionUsecase) AddComment(
+	ctx context.Context,
+	sessionID string,
+	req *entity.CreateCommentRequest,
+) (*entity.SessionComment, error) {
+	session, err := uc.sessionRepo.GetSessionByID(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("get session: %w", err)
+	}
+
+	if session.Status != entity.SessionStatusDone {
+		return nil, entity.ErrNoResult
+	}
+
+	if req.SectionIndex != nil {
+		sections, err := uc.resultSections(ctx, session)
+		if err != nil {
+			return nil, err
+		}
+		if *req.SectionIndex >= len(sections) {
+			return nil, fmt.Errorf("section %d: %w", *req.SectionIndex, entity.ErrSectionNotFound)
+		}
+	}
+
+	text, err := uc.moderateInput(ctx, sessionID, entity.ModerationSourceComment, req.Text)
+	if err != nil {
+		return nil, err
+	}
+
+	comment := &entity.SessionComment{
+		SessionID:    sessionID,
+		SectionIndex: req.SectionIndex,
+		Author:       req.Author,
+		Text:         text,
+	}
+	if req.RequirementID != "" {
+		comment.RequirementID = &req.RequirementID
+	}
+
+	createdComment, err := uc.commentRepo.CreateComment(ctx, comment)
+	if err != nil {
+		return nil, fmt.Errorf("save comment: %w", err)
+	}
+
+	return createdComment, nil
+}
+
+// ListComments returns reviewer comments of the session in creation order
+func (uc *SessionUsecase) ListComments(
+	ctx context.Context,
+	sessionID string,
+	unresolvedOnly bool,
+) ([]*entity.SessionComment, error) {
+	if _, err := uc.sessionRepo.GetSessionByID(ctx, sessionID); err != nil {
+		return nil, fmt.Errorf("get session: %w", err)
+	}
+
+	comments, err := uc.commentRepo.ListComments(ctx, sessionID, unresolvedOnly)
+	if err != nil {
+		return nil, fmt.Errorf("list comments: %w", err)
+	}
+
+	return comments, nil
+}
+
+// ResolveComment marks a reviewer comment as resolved
+func (uc *SessionUsecase) ResolveComment(ctx context.Context, sessionID, commentID string) (*entity.SessionComment, error) {
+	if _, err := uc.sessionRepo.GetSessionByID(ctx, sessionID); err != nil {
+		return nil, fmt.Errorf("get session: %w", err)
+	}
+
+	comment, err := uc.commentRepo.ResolveComment(ctx, sessionID, commentID)
+	if err != nil {
+		return nil, fmt.Errorf("resolve comment: %w", err)
+	}
+
+	return comment, nil
+}
+
+// RefineResult revises the session result to address unresolved comments in a single LLM pass;
+// the addressed comments are resolved
+func (uc *SessionUsecase) RefineResult(ctx context.Context, sessionID string) (*entity.Session, error) {
+	ctx, span := tracing.Start(ctx, "SessionUsecase.RefineResult", tracing.SessionID(sessionID))
+	defer span.End()
+
+	finish, err := uc.startOperation(ctx, sessionID, entity.OperationKindRefineResult)
+	if err != nil {
+		return nil, err
+	}
+	defer finish()
+
+	session, err := uc.sessionRepo.GetSessionByID(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("get session: %w", err)
+	}
+
+	if err := uc.loadResult(ctx, session); err != nil {
+		return nil, err
+	}
+
+	if session.Status != entity.SessionStatusDone || session.Result == nil || *session.Result == "" {
+		return nil, entity.ErrNoResult
+	}
+
+	comments, err := uc.commentRepo.ListComments(ctx, sessionID, true)
+	if err != nil {
+		return nil, fmt.Errorf("list comments: %w", err)
+	}
+
+	if len(comments) == 0 {
+		return nil, entity.ErrNoOpenComments
+	}
+
+	sections, err := uc.resultSections(ctx, session)
+	if err != nil {
+		return nil, err
+	}
+
+	docComments := make([]entity.DocumentComment, 0, len(comments))
+	commentIDs := make([]string, 0, len(comments))
+	for _, c := range comments {
+		docComment := entity.DocumentComment{Text: c.Text}
+		if c.SectionIndex != nil && *c.SectionIndex < len(sections) {
+			docComment.SectionTitle = sections[*c.SectionIndex].Title
+		}
+		if c.RequirementID != nil {
+			docComment.RequirementID = *c.RequirementID
+		}
+		docComments = append(docComments, docComment)
+		commentIDs = append(commentIDs, c.ID)
+	}
+
+	result, err := uc.llm(session).RefineResult(ctx, &entity.LLMRefineResultRequest{
+		Result:         *session.Result,
+		Comments:       docComments,
+		TargetLanguage: targetLanguage(session),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("refine result: %w", err)
+	}
+
+	updatedSession, err := uc.saveRefinedResult(ctx, session, result)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.commentRepo.ResolveComments(ctx, sessionID, commentIDs); err != nil {
+		return nil, fmt.Errorf("resolve comments: %w", err)
+	}
+
+	ctxzap.Info(ctx, "result refined by comments",
+		zap.String("session_id", sessionID),
+		zap.Int("comments", len(comments)),
+	)
+
+	return updatedSession, nil
+}
+
+// saveRefinedResult stores a revised document as a new result version and drops what was
+// derived from the previous one
+func (uc *SessionUsecase) saveRefinedResult(ctx context.Context, session *entity.Session, result string) (*entity.Session, error) {
+	// Stored sections describe the previous document; the refined one is split on demand
+	if err := uc.sectionRepo.ReplaceSections(ctx, session.ID, nil); err != nil {
+		return nil, fmt.Errorf("reset result sections: %w", err)
+	}
+
+	updatedSession, err := uc.saveResult(ctx, session, result)
+	if err != nil {
+		return nil, fmt.Errorf("save summary: %w", err)
+	}
+
+	if err := uc.translationRepo.DeleteTranslations(ctx, session.ID); err != nil {
+		return nil, fmt.Errorf("invalidate translations: %w", err)
+	}
+
+	// A changed result has to be reviewed again
+	if err := uc.resetReview(ctx, session.ID); err != nil {
+		return nil, err
+	}
+
+	return updatedSession, nil
+}
@@ -0,0 +1,199 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// ListConflicts returns contradictions detected between the session result and earlier project requirements
+func (uc *SessionUsecase) ListConflicts(ctx context.Context, sessionID string) ([]*entity.RequirementConflict, error) {
+	if _, err := uc.sessionRepo.GetSessionByID(ctx, sessionID); err != nil {
+		return nil, fmt.Errorf("get session: %w", err)
+	}
+
+	conflicts, err := uc.conflictRepo.ListConflicts(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("list conflicts: %w", err)
+	}
+
+	return conflicts, nil
+}
+
+// ResolveConflict records the decision on a conflict; once the last one is resolved
+// the result is annotated with all decisions
+func (uc *SessionUsecase) ResolveConflict(
+	ctx context.Context,
+	sessionID, conflictID string,
+	resolution entity.ConflictResolution,
+) (*entity.RequirementConflict, error) {
+	session, err := uc.sessionRepo.GetSessionByID(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("get session: %w", err)
+	}
+
+	if err := uc.loadResult(ctx, session); err != nil {
+		return nil, err
+	}
+
+	if session.Status != entity.SessionStatusDone || session.Result == nil || *session.Result == "" {
+		return nil, entity.ErrNoResult
+	}
+
+	conflict, err := uc.conflictRepo.ResolveConflict(ctx, sessionID, conflictID, resolution)
+	if err != nil {
+		return nil, fmt.Errorf("resolve conflict: %w", err)
+	}
+
+	unresolved, err := uc.conflictRepo.CountUnresolvedConflicts(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("count unresolved conflicts: %w", err)
+	}
+
+	if unresolved == 0 {
+		if err := uc.annotateResolvedConflicts(ctx, session); err != nil {
+			return nil, err
+		}
+	}
+
+	return conflict, nil
+}
+
+func (uc *SessionUsecase) ensureConflictsResolved(ctx context.Context, sessionID string) error {
+	unresolved, err := uc.conflictRepo.CountUnresolvedConflicts(ctx, sessionID)
+	if err != nil {
+		return fmt.Errorf("count unresolved conflicts: %w", err)
+	}
+
+	if unresolved > 0 {
+		return fmt.Errorf("%d open: %w", unresolved, entity.ErrUnresolvedConflicts)
+	}
+
+	return nil
+}
+
+// detectConflicts compares a freshly generated result with earlier project requirements
+// and stores the contradictions for the user to resolve. Detection is best-effort:
+// a failed check is logged and leaves the result without conflicts.
+// Must run before the result is saved, otherwise the session is its own latest project result.
+func (uc *SessionUsecase) detectConflicts(ctx context.Context, session *entity.Session, result string) {
+	// Delta sessions change the latest requirements on purpose
+	if session.ProjectID == nil || *session.ProjectID == "" || isDeltaSession(session) {
+		return
+	}
+
+	req := &entity.LLMDetectConflictsRequest{Requirements: result}
+	if session.ProjectContext != nil {
+		req.ProjectContext = *session.ProjectContext
+	}
+
+	previous, err := uc.sessionRepo.GetLatestProjectResultSession(ctx, *session.ProjectID)
+	switch {
+	case err == nil && previous.ID != session.ID:
+		if err := uc.loadResult(ctx, previous); err != nil {
+			ctxzap.Warn(ctx, "failed to load previous project requirements", zap.Error(err))
+			return
+		}
+		req.PreviousRequirements = previous.Result
+	case err != nil && !errors.Is(err, entity.ErrSessionNotFound):
+		ctxzap.Warn(ctx, "failed to get previous project requirements", zap.Error(err))
+		return
+	}
+
+	resp, err := uc.llm(session).DetectConflicts(ctx, req)
+	if err != nil {
+		ctxzap.Warn(ctx, "failed to detect requirement conflicts", zap.Error(err))
+		return
+	}
+
+	conflicts := make([]*entity.RequirementConflict, 0, len(resp.Conflicts))
+	for _, c := range resp.Conflicts {
+		conflict := &entity.RequirementConflict{
+			SessionID:   session.ID,
+			Requirement: c.Requirement,
+			Previous:    c.Previous,
+		}
+		if c.Source != "" {
+			source := c.Source
+			conflict.Source = &source
+		}
+		conflicts = append(conflicts, conflict)
+	}
+
+	if _, err := uc.conflictRepo.ReplaceConflicts(ctx, session.ID, conflicts); err != nil {
+		ctxzap.Warn(ctx, "failed to save requirement conflicts", zap.Error(err))
+		return
+	}
+
+	ctxzap.Info(ctx, "requirement conflicts detected",
+		zap.String("session_id", session.ID),
+		zap.Int("conflicts", len(conflicts)),
+	)
+}
+
+// annotateResolvedConflicts appends the decisions on all conflicts to the result.
+// Kept previous requirements are stated in the annotation, the document text is not rewritten.
+func (uc *SessionUsecase) annotateResolvedConflicts(ctx context.Context, session *entity.Session) error {
+	conflicts, err := uc.conflictRepo.ListConflicts(ctx, session.ID)
+	if err != nil {
+		return fmt.Errorf("list conflicts: %w", err)
+	}
+
+	if len(conflicts) == 0 {
+		return nil
+	}
+
+	result := strings.TrimRight(*session.Result, "\n") + "\n\n" + formatResolvedConflicts(conflicts)
+
+	// Stored sections describe the previous document; the annotated one is split on demand
+	if err := uc.sectionRepo.ReplaceSections(ctx, session.ID, nil); err != nil {
+		return fmt.Errorf("reset result sections: %w", err)
+	}
+
+	if _, err := uc.saveResult(ctx, session, result); err != nil {
+		return fmt.Errorf("save summary: %w", err)
+	}
+
+	if err := uc.translationRepo.DeleteTranslations(ctx, session.ID); err != nil {
+		return fmt.Errorf("invalidate translations: %w", err)
+	}
+
+	// A changed result has to be reviewed again
+	if err := uc.resetReview(ctx, session.ID); err != nil {
+		return err
+	}
+
+	ctxzap.Info(ctx, "result annotated with resolved conflicts",
+		zap.String("session_id", session.ID),
+		zap.Int("conflicts", len(conflicts)),
+	)
+
+	return nil
+}
+
+func formatResolvedConflicts(conflicts []*entity.RequirementConflict) string {
+	var sb strings.Builder
+	sb.WriteString("## Разрешённые противоречия\n")
+
+	for i, c := range conflicts {
+		previous := fmt.Sprintf("«%s»", c.Previous)
+		if c.Source != nil {
+			previous += fmt.Sprintf(" (источник: %s)", *c.Source)
+		}
+
+		decision := "принято новое требование"
+		if c.Resolution != nil && *c.Resolution == entity.ConflictResolutionKeepPrevious {
+			decision = "сохранено ранее указанное требование"
+		}
+
+		fmt.Fprintf(&sb, "\n%d. Ранее было указано: %s.\n   Новое требование: «%s».\n   Решение: %s.\n",
+			i+1, previous, c.Requirement, decision)
+	}
+
+	return sb.String()
+}
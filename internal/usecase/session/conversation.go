@@ -0,0 +1,74 @@
+package session
+
+import (
+	"context"
+	"unicode/utf8"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// ConversationWindow limits the interview transcript sent to the LLM; zero MaxEntries leaves it out
+type ConversationWindow struct {
+	MaxEntries int
+	MaxChars   int
+}
+
+// logConversation records what happened to a question in the interview log.
+// Failures are only logged: the log is supplementary to the questions themselves.
+func (uc *SessionUsecase) logConversation(
+	ctx context.Context,
+	sessionID, questionID string,
+	kind entity.ConversationEntryKind,
+	content string,
+) {
+	if err := uc.conversationRepo.AppendEntry(ctx, sessionID, questionID, kind, content); err != nil {
+		ctxzap.Warn(ctx, "failed to log conversation entry",
+			zap.Error(err),
+			zap.String("session_id", sessionID),
+			zap.String("question_id", questionID),
+			zap.String("kind", string(kind)),
+		)
+	}
+}
+
+// recentConversation returns the latest part of the interview for an LLM request, oldest first,
+// or nil when transcripts are disabled. A failure to read the log leaves the transcript out.
+func (uc *SessionUsecase) recentConversation(ctx context.Context, sessionID string) []entity.ConversationEntry {
+	if uc.conversationWindow.MaxEntries <= 0 {
+		return nil
+	}
+
+	entries, err := uc.conversationRepo.ListRecentEntries(ctx, sessionID, uc.conversationWindow.MaxEntries)
+	if err != nil {
+		ctxzap.Warn(ctx, "failed to read conversation log",
+			zap.Error(err),
+			zap.String("session_id", sessionID),
+		)
+		return nil
+	}
+
+	return trimConversation(entries, uc.conversationWindow.MaxChars)
+}
+
+// trimConversation drops the oldest entries until the transcript fits into maxChars
+func trimConversation(entries []entity.ConversationEntry, maxChars int) []entity.ConversationEntry {
+	if maxChars <= 0 {
+		return entries
+	}
+
+	total := 0
+	start := len(entries)
+	for start > 0 {
+		e := entries[start-1]
+		size := utf8.RuneCountInString(e.Question) + utf8.RuneCountInString(e.Content)
+		if total+size > maxChars {
+			break
+		}
+		total += size
+		start--
+	}
+
+	return entries[start:]
+}
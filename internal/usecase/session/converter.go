@@ -31,10 +31,13 @@ func questionModelToQuestionDTO(question *entity.Question) *entity.QuestionDTO {
 	}
 
 	return &entity.QuestionDTO{
-		ID:             question.ID,
-		QuestionNumber: question.QuestionNumber,
-		Status:         question.Status,
-		Question:       question.Question,
-		Explanation:    question.Explanation,
+		ID:               question.ID,
+		QuestionNumber:   question.QuestionNumber,
+		Status:           question.Status,
+		Question:         question.Question,
+		Explanation:      question.Explanation,
+		Options:          question.Options,
+		AnswerType:       question.AnswerType,
+		ParentQuestionID: question.ParentQuestionID,
 	}
 }
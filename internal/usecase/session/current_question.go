@@ -0,0 +1,35 @@
+package session
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// refreshCurrentQuestion points the session at its first open question once its questions changed.
+// The pointer only lets clients without their own state resume the interview, so a failure is logged
+func (uc *SessionUsecase) refreshCurrentQuestion(ctx context.Context, sessionID string) {
+	if _, err := uc.sessionRepo.RefreshSessionCurrentQuestion(ctx, sessionID); err != nil {
+		ctxzap.Warn(ctx, "failed to refresh current question",
+			zap.Error(err),
+			zap.String("session_id", sessionID),
+		)
+	}
+}
+
+// loadCurrentQuestion fills the current question of a session waiting for answers
+func (uc *SessionUsecase) loadCurrentQuestion(ctx context.Context, session *entity.Session) error {
+	if session.CurrentQuestionID == nil || session.Status != entity.SessionStatusWaitingForAnswers {
+		return nil
+	}
+
+	question, err := uc.questionRepo.GetQuestionByID(ctx, *session.CurrentQuestionID)
+	if err != nil {
+		return fmt.Errorf("get current question: %w", err)
+	}
+	session.CurrentQuestion = question
+	return nil
+}
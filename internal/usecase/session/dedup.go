@@ -0,0 +1,138 @@
+package session
+
+import (
+	"context"
+	"strings"
+	"unicode"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// dedupStemRunes is the length words are cut to before comparison, so that the inflected forms
+// of a word match
+const dedupStemRunes = 5
+
+// dedupQuestions merges near-duplicate questions across the generated blocks before they are
+// saved: a question similar to an earlier one is dropped and its options go to the kept one.
+// Blocks left without questions are dropped; a zero threshold disables the pass.
+func (uc *SessionUsecase) dedupQuestions(ctx context.Context, sessionID string, blocks []entity.QuestionsBlock) []entity.QuestionsBlock {
+	if uc.dedupThreshold <= 0 {
+		return blocks
+	}
+
+	type keptQuestion struct {
+		block, index int
+		words        map[string]struct{}
+	}
+	var kept []keptQuestion
+
+	result := make([]entity.QuestionsBlock, 0, len(blocks))
+	for _, block := range blocks {
+		questions := make([]entity.LLMQuestion, 0, len(block.Questions))
+		for _, q := range block.Questions {
+			words := questionWords(q.Text)
+
+			duplicateOf := -1
+			for i, k := range kept {
+				if questionSimilarity(words, k.words) >= uc.dedupThreshold {
+					duplicateOf = i
+					break
+				}
+			}
+
+			if duplicateOf < 0 {
+				kept = append(kept, keptQuestion{block: len(result), index: len(questions), words: words})
+				questions = append(questions, q)
+				continue
+			}
+
+			k := kept[duplicateOf]
+			var original *entity.LLMQuestion
+			if k.block == len(result) {
+				original = &questions[k.index]
+			} else {
+				original = &result[k.block].Questions[k.index]
+			}
+			if original.Type == q.Type && len(original.Options) > 0 {
+				original.Options = mergeOptions(original.Options, q.Options)
+			}
+
+			ctxzap.Info(ctx, "duplicate question removed",
+				zap.String("session_id", sessionID),
+				zap.String("removed", q.Text),
+				zap.String("removed_block", block.Title),
+				zap.String("kept", original.Text),
+			)
+		}
+
+		if len(questions) == 0 {
+			ctxzap.Info(ctx, "question block left empty after deduplication, dropped",
+				zap.String("session_id", sessionID),
+				zap.String("block", block.Title),
+			)
+			continue
+		}
+		block.Questions = questions
+		result = append(result, block)
+	}
+
+	return result
+}
+
+// questionWords returns the set of stemmed words of a question, leaving out one- and two-letter
+// words such as prepositions
+func questionWords(text string) map[string]struct{} {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	words := make(map[string]struct{}, len(fields))
+	for _, field := range fields {
+		word := []rune(field)
+		if len(word) < 3 {
+			continue
+		}
+		if len(word) > dedupStemRunes {
+			word = word[:dedupStemRunes]
+		}
+		words[string(word)] = struct{}{}
+	}
+	return words
+}
+
+// questionSimilarity is the Jaccard similarity of the word sets of two questions
+func questionSimilarity(a, b map[string]struct{}) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+
+	common := 0
+	for word := range a {
+		if _, ok := b[word]; ok {
+			common++
+		}
+	}
+	return float64(common) / float64(len(a)+len(b)-common)
+}
+
+// mergeOptions appends the options of a removed duplicate the kept question does not offer yet
+func mergeOptions(options, extra []string) []string {
+	if len(extra) == 0 {
+		return options
+	}
+
+	seen := make(map[string]struct{}, len(options))
+	for _, option := range options {
+		seen[normalizeQuestionText(option)] = struct{}{}
+	}
+	for _, option := range extra {
+		if _, ok := seen[normalizeQuestionText(option)]; ok {
+			continue
+		}
+		seen[normalizeQuestionText(option)] = struct{}{}
+		options = append(options, option)
+	}
+	return options
+}
@@ -0,0 +1,146 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// GetChangeLog returns the change log of a completed delta session
+func (uc *SessionUsecase) GetChangeLog(ctx context.Context, sessionID string) (*entity.SessionDelta, error) {
+	session, err := uc.sessionRepo.GetSessionByID(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("get session: %w", err)
+	}
+
+	if !isDeltaSession(session) {
+		return nil, fmt.Errorf("session type is not %s: %w", entity.SessionTypeDelta, entity.ErrNoChangeLog)
+	}
+
+	if err := uc.EnsureResultReleasable(ctx, sessionID); err != nil {
+		return nil, err
+	}
+
+	delta, err := uc.deltaRepo.GetDelta(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, entity.ErrNoBaseline) {
+			return nil, entity.ErrNoChangeLog
+		}
+		return nil, fmt.Errorf("get session delta: %w", err)
+	}
+
+	if delta.ChangeLog == nil {
+		return nil, entity.ErrNoChangeLog
+	}
+
+	return delta, nil
+}
+
+// prepareDeltaBaseline stores the latest requirements of the session project as the baseline
+func (uc *SessionUsecase) prepareDeltaBaseline(ctx context.Context, session *entity.Session) error {
+	if session.ProjectID == nil || *session.ProjectID == "" {
+		return fmt.Errorf("delta session requires a project: %w", entity.ErrNoBaseline)
+	}
+
+	baselineSession, err := uc.sessionRepo.GetLatestProjectResultSession(ctx, *session.ProjectID)
+	if err != nil {
+		if errors.Is(err, entity.ErrSessionNotFound) {
+			return entity.ErrNoBaseline
+		}
+		return fmt.Errorf("get baseline session: %w", err)
+	}
+
+	if err := uc.loadResult(ctx, baselineSession); err != nil {
+		return fmt.Errorf("load baseline result: %w", err)
+	}
+
+	if _, err := uc.deltaRepo.SaveBaseline(ctx, session.ID, &baselineSession.ID, *baselineSession.Result); err != nil {
+		return fmt.Errorf("save baseline: %w", err)
+	}
+
+	ctxzap.Info(ctx, "delta baseline prepared",
+		zap.String("session_id", session.ID),
+		zap.String("baseline_session_id", baselineSession.ID),
+	)
+
+	return nil
+}
+
+// generateDeltaQuestionsBlocks generates questions about changes since the session baseline
+func (uc *SessionUsecase) generateDeltaQuestionsBlocks(
+	ctx context.Context,
+	session *entity.Session,
+	projectDescription *string,
+) ([]entity.QuestionsBlock, error) {
+	delta, err := uc.deltaRepo.GetDelta(ctx, session.ID)
+	if err != nil {
+		return nil, fmt.Errorf("get session delta: %w", err)
+	}
+
+	response, err := uc.llm(session).GenerateDeltaQuestions(ctx, &entity.LLMGenerateDeltaQuestionsRequest{
+		Baseline:           delta.Baseline,
+		UserGoal:           *session.UserGoal,
+		ProjectContext:     *session.ProjectContext,
+		ProjectDescription: projectDescription,
+		TargetLanguage:     targetLanguage(session),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("generate delta questions: %w", err)
+	}
+
+	if len(response.Iterations) == 0 {
+		return nil, fmt.Errorf("no questions generated")
+	}
+
+	return response.Iterations, nil
+}
+
+// generateDeltaSummary applies the answers to the baseline, stores the change log
+// and returns the updated full document
+func (uc *SessionUsecase) generateDeltaSummary(ctx context.Context, session *entity.Session) (string, error) {
+	delta, err := uc.deltaRepo.GetDelta(ctx, session.ID)
+	if err != nil {
+		return "", fmt.Errorf("get session delta: %w", err)
+	}
+
+	allAnswers, err := uc.collectAllAnswers(ctx, session.ID)
+	if err != nil {
+		return "", fmt.Errorf("collect answers: %w", err)
+	}
+
+	var projectDescription *string
+	if session.ProjectID != nil && *session.ProjectID != "" {
+		project, err := uc.projectRepo.Get(ctx, *session.ProjectID)
+		if err != nil {
+			return "", fmt.Errorf("get project: %w", err)
+		}
+		projectDescription = &project.Description
+	}
+
+	resp, err := uc.llm(session).GenerateDeltaSummary(ctx, &entity.LLMGenerateDeltaSummaryRequest{
+		Baseline:           delta.Baseline,
+		CompleteQuestions:  allAnswers,
+		UserGoal:           *session.UserGoal,
+		ProjectContext:     *session.ProjectContext,
+		ProjectDescription: projectDescription,
+		TargetLanguage:     targetLanguage(session),
+		Style:              summaryStyle(session),
+	})
+	if err != nil {
+		return "", fmt.Errorf("generate delta summary: %w", err)
+	}
+
+	if _, err := uc.deltaRepo.SaveChangeLog(ctx, session.ID, resp.ChangeLog); err != nil {
+		return "", fmt.Errorf("save change log: %w", err)
+	}
+
+	return resp.Result, nil
+}
+
+func isDeltaSession(session *entity.Session) bool {
+	return session.Type != nil && *session.Type == entity.SessionTypeDelta
+}
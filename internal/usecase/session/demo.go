@@ -0,0 +1,76 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/google/uuid"
+)
+
+// demoBanner labels every result generated in a demo session
+const demoBanner = "> ⚠️ ДЕМО: документ сгенерирован в песочнице на тестовых данных и будет удалён автоматически.\n\n"
+
+// DemoConnectors serve demo sessions instead of the real external services
+type DemoConnectors struct {
+	RAG RagConnector
+	LLM LLMConnector
+	ASR ASRConnector
+}
+
+// StartDemoSession creates an empty sandbox session that always runs against mock connectors
+func (uc *SessionUsecase) StartDemoSession(ctx context.Context) (*entity.Session, error) {
+	session := entity.Session{
+		ID:     uuid.New().String(),
+		Status: entity.SessionStatusAskUserGoal,
+		IsDemo: true,
+	}
+
+	createdSession, err := uc.sessionRepo.CreateSession(ctx, session)
+	if err != nil {
+		return nil, fmt.Errorf("create demo session: %w", err)
+	}
+
+	return createdSession, nil
+}
+
+// PurgeDemoSessions deletes demo sessions neither created nor heartbeated since the given time
+func (uc *SessionUsecase) PurgeDemoSessions(ctx context.Context, before time.Time) (int, error) {
+	deleted, err := uc.sessionRepo.DeleteDemoSessionsBefore(ctx, before)
+	if err != nil {
+		return 0, fmt.Errorf("delete demo sessions: %w", err)
+	}
+
+	return deleted, nil
+}
+
+func (uc *SessionUsecase) rag(session *entity.Session) RagConnector {
+	if session.IsDemo {
+		return uc.demoConnectors.RAG
+	}
+	return uc.ragConnector
+}
+
+func (uc *SessionUsecase) llm(session *entity.Session) LLMConnector {
+	if session.IsDemo {
+		return uc.demoConnectors.LLM
+	}
+	return uc.llmConnector
+}
+
+func (uc *SessionUsecase) asr(session *entity.Session) ASRConnector {
+	if session.IsDemo {
+		return uc.demoConnectors.ASR
+	}
+	return uc.asrConnector
+}
+
+// labelDemoResult prepends the demo banner to results of demo sessions
+func labelDemoResult(session *entity.Session, result string) string {
+	if !session.IsDemo || strings.HasPrefix(result, strings.TrimSpace(demoBanner)) {
+		return result
+	}
+	return demoBanner + result
+}
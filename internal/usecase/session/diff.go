@@ -0,0 +1,171 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/pkg/formatter"
+	"github.com/futig/agent-backend/internal/pkg/textdiff"
+	"github.com/google/uuid"
+)
+
+// DiffResults compares the requirements of two sessions section by section; sections are
+// matched by their titles and the lines of matched sections are compared
+func (uc *SessionUsecase) DiffResults(ctx context.Context, baseSessionID, compareSessionID string) (*entity.ResultDiff, error) {
+	if _, err := uuid.Parse(baseSessionID); err != nil {
+		return nil, fmt.Errorf("%w: base", entity.ErrInvalidParameter)
+	}
+	if _, err := uuid.Parse(compareSessionID); err != nil {
+		return nil, fmt.Errorf("%w: compare", entity.ErrInvalidParameter)
+	}
+	if baseSessionID == compareSessionID {
+		return nil, fmt.Errorf("%w: base and compare are the same session", entity.ErrInvalidParameter)
+	}
+
+	base, err := uc.releasedResultSections(ctx, baseSessionID)
+	if err != nil {
+		return nil, fmt.Errorf("base: %w", err)
+	}
+	compare, err := uc.releasedResultSections(ctx, compareSessionID)
+	if err != nil {
+		return nil, fmt.Errorf("compare: %w", err)
+	}
+
+	diff := diffResultSections(base, compare)
+	diff.BaseSessionID = baseSessionID
+	diff.CompareSessionID = compareSessionID
+	diff.Markdown = formatter.FormatResultDiff(diff)
+
+	return diff, nil
+}
+
+// DiffWithPreviousResult compares the requirements of the session with the previous requirements
+// of its project; ErrNoBaseline when the session has no project or the project no earlier result
+func (uc *SessionUsecase) DiffWithPreviousResult(ctx context.Context, sessionID string) (*entity.ResultDiff, error) {
+	session, err := uc.sessionRepo.GetSessionByID(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("get session: %w", err)
+	}
+
+	if session.ProjectID == nil || *session.ProjectID == "" {
+		return nil, entity.ErrNoBaseline
+	}
+
+	previous, err := uc.sessionRepo.GetPreviousProjectResultSession(ctx, *session.ProjectID, session.CreatedAt)
+	if err != nil {
+		if errors.Is(err, entity.ErrSessionNotFound) {
+			return nil, entity.ErrNoBaseline
+		}
+		return nil, fmt.Errorf("get previous project result session: %w", err)
+	}
+
+	return uc.DiffResults(ctx, previous.ID, sessionID)
+}
+
+// releasedResultSections returns the sections of a result the client may read: a completed or
+// partial session whose result, when review is required, was approved
+func (uc *SessionUsecase) releasedResultSections(ctx context.Context, sessionID string) ([]*entity.ResultSection, error) {
+	session, err := uc.sessionRepo.GetSessionByID(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("get session: %w", err)
+	}
+
+	switch session.Status {
+	case entity.SessionStatusDone:
+		if err := uc.EnsureResultReleasable(ctx, sessionID); err != nil {
+			return nil, err
+		}
+	case entity.SessionStatusPartial:
+	default:
+		return nil, entity.ErrNoResult
+	}
+
+	return uc.resultSections(ctx, session)
+}
+
+// diffResultSections aligns the sections of two results by their titles, in the order of the
+// compared result, and diffs the lines of the sections found in both
+func diffResultSections(base, compare []*entity.ResultSection) *entity.ResultDiff {
+	diff := &entity.ResultDiff{Sections: make([]*entity.SectionDiff, 0, len(compare))}
+
+	edits := textdiff.Diff(sectionKeys(base), sectionKeys(compare))
+	for _, edit := range edits {
+		var section *entity.SectionDiff
+		switch edit.Op {
+		case textdiff.Equal:
+			b, c := base[edit.AIndex], compare[edit.BIndex]
+			section = &entity.SectionDiff{
+				Title:        c.Title,
+				Change:       entity.SectionChangeUnchanged,
+				BaseIndex:    &b.SectionIndex,
+				CompareIndex: &c.SectionIndex,
+			}
+			if b.Content != c.Content {
+				section.Change = entity.SectionChangeChanged
+				section.Lines = diffLines(b.Content, c.Content)
+				diff.Summary.Changed++
+			} else {
+				diff.Summary.Unchanged++
+			}
+		case textdiff.Delete:
+			b := base[edit.AIndex]
+			section = &entity.SectionDiff{
+				Title:     b.Title,
+				Change:    entity.SectionChangeRemoved,
+				BaseIndex: &b.SectionIndex,
+				Lines:     diffLines(b.Content, ""),
+			}
+			diff.Summary.Removed++
+		case textdiff.Insert:
+			c := compare[edit.BIndex]
+			section = &entity.SectionDiff{
+				Title:        c.Title,
+				Change:       entity.SectionChangeAdded,
+				CompareIndex: &c.SectionIndex,
+				Lines:        diffLines("", c.Content),
+			}
+			diff.Summary.Added++
+		}
+		diff.Sections = append(diff.Sections, section)
+	}
+
+	return diff
+}
+
+// sectionKeys returns the titles the sections are matched by, ignoring case and spacing
+func sectionKeys(sections []*entity.ResultSection) []string {
+	keys := make([]string, 0, len(sections))
+	for _, section := range sections {
+		keys = append(keys, strings.ToLower(strings.Join(strings.Fields(section.Title), " ")))
+	}
+	return keys
+}
+
+// diffLines compares two section contents line by line
+func diffLines(base, compare string) []entity.DiffLine {
+	a, b := splitLines(base), splitLines(compare)
+
+	lines := make([]entity.DiffLine, 0, len(a)+len(b))
+	for _, edit := range textdiff.Diff(a, b) {
+		switch edit.Op {
+		case textdiff.Equal:
+			lines = append(lines, entity.DiffLine{Op: entity.DiffLineEqual, Text: b[edit.BIndex]})
+		case textdiff.Delete:
+			lines = append(lines, entity.DiffLine{Op: entity.DiffLineDelete, Text: a[edit.AIndex]})
+		case textdiff.Insert:
+			lines = append(lines, entity.DiffLine{Op: entity.DiffLineInsert, Text: b[edit.BIndex]})
+		}
+	}
+	return lines
+}
+
+// splitLines splits a section content into lines, none for an empty content
+func splitLines(content string) []string {
+	if content == "" {
+		return nil
+	}
+	return strings.Split(content, "\n")
+}
@@ -0,0 +1,108 @@
+package session
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/pkg/tracing"
+)
+
+// PreviewDraftValidation runs the draft validation without its side effects: the status of the
+// session stays the same and no additional questions are saved. The facts known from the materials
+// are reported as covered and the questions the validation would ask as missing, so the user can
+// add materials before generating the requirements. Covered is empty when context snapshots are off
+func (uc *SessionUsecase) PreviewDraftValidation(ctx context.Context, sessionID string) (*entity.DraftCoverage, error) {
+	ctx, span := tracing.Start(ctx, "SessionUsecase.PreviewDraftValidation", tracing.SessionID(sessionID))
+	defer span.End()
+
+	session, err := uc.sessionRepo.GetSessionByID(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("get session: %w", err)
+	}
+
+	if session.Status != entity.SessionStatusDraftCollecting {
+		return nil, fmt.Errorf("invalid session status for validation preview: %s", session.Status)
+	}
+
+	if session.UserGoal == nil || *session.UserGoal == "" {
+		return nil, fmt.Errorf("user goal not set")
+	}
+
+	if session.ProjectContext == nil || *session.ProjectContext == "" {
+		return nil, fmt.Errorf("project context not set")
+	}
+
+	req, err := uc.draftValidationRequest(ctx, session)
+	if err != nil {
+		return nil, err
+	}
+
+	validateResp, err := uc.llm(session).ValidateDraft(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("validate draft: %w", err)
+	}
+
+	coverage := &entity.DraftCoverage{
+		MessageCount: len(req.Messages),
+		Covered:      req.KnownFacts,
+		Missing:      make([]string, 0, len(validateResp.Questions)),
+	}
+	for _, q := range validateResp.Questions {
+		coverage.Missing = append(coverage.Missing, q.Text)
+	}
+
+	return coverage, nil
+}
+
+// draftValidationRequest collects the draft messages, the answered additional questions and the
+// project description of a session for the draft validation
+func (uc *SessionUsecase) draftValidationRequest(ctx context.Context, session *entity.Session) (*entity.LLMValidateDraftRequest, error) {
+	messages, err := uc.sessionMessageRepo.GetSessionMessages(ctx, session.ID)
+	if err != nil {
+		return nil, fmt.Errorf("get session messages: %w", err)
+	}
+
+	if len(messages) == 0 {
+		return nil, fmt.Errorf("no draft messages to validate")
+	}
+
+	questions, err := uc.questionRepo.ListQuestionsBySession(ctx, session.ID)
+	if err != nil {
+		return nil, fmt.Errorf("get questions by session: %w", err)
+	}
+
+	additionalQuestions := make([]entity.QuestionWithAnswer, 0, len(questions))
+	for _, q := range questions {
+		if q.Answer != nil {
+			additionalQuestions = append(additionalQuestions, entity.QuestionWithAnswer{
+				Question: q.Question,
+				Answer:   *q.Answer,
+			})
+		}
+	}
+
+	messageTexts := make([]string, 0, len(messages))
+	for _, m := range messages {
+		messageTexts = append(messageTexts, m.MessageText)
+	}
+
+	var projectDescription *string
+	if session.ProjectID != nil && *session.ProjectID != "" {
+		project, err := uc.projectRepo.Get(ctx, *session.ProjectID)
+		if err != nil {
+			return nil, fmt.Errorf("get project description: %w", err)
+		}
+		projectDescription = &project.Description
+	}
+
+	return &entity.LLMValidateDraftRequest{
+		Messages:            messageTexts,
+		AdditionalQuestions: additionalQuestions,
+		UserGoal:            *session.UserGoal,
+		ProjectContext:      *session.ProjectContext,
+		ProjectDescription:  projectDescription,
+		KnownFacts:          uc.knownFacts(ctx, session, messageTexts, additionalQuestions, projectDescription),
+		TargetLanguage:      targetLanguage(session),
+	}, nil
+}
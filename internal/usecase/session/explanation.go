@@ -0,0 +1,135 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/pkg/metrics"
+	"github.com/futig/agent-backend/internal/pkg/tracing"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// GetQuestionExplanation returns explanation text for a given question
+func (uc *SessionUsecase) GetQuestionExplanation(ctx context.Context, questionID string) (string, error) {
+	ctx, span := tracing.Start(ctx, "SessionUsecase.GetQuestionExplanation")
+	defer span.End()
+
+	question, err := uc.questionRepo.GetQuestionByID(ctx, questionID)
+	if err != nil {
+		return "", fmt.Errorf("get question: %w", err)
+	}
+
+	explanation, err := uc.explainQuestion(ctx, nil, question)
+	if err != nil {
+		return "", err
+	}
+
+	return explanation.Explanation, nil
+}
+
+// ExplainQuestion returns the explanation of a question of the session, sharing the cache and
+// the cooldown of GetQuestionExplanation
+func (uc *SessionUsecase) ExplainQuestion(ctx context.Context, sessionID, questionID string) (*entity.QuestionExplanation, error) {
+	ctx, span := tracing.Start(ctx, "SessionUsecase.ExplainQuestion", tracing.SessionID(sessionID))
+	defer span.End()
+
+	session, err := uc.sessionRepo.GetSessionByID(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("get session: %w", err)
+	}
+
+	question, err := uc.questionRepo.GetQuestionByID(ctx, questionID)
+	if err != nil {
+		return nil, fmt.Errorf("get question: %w", err)
+	}
+
+	iteration, err := uc.iterationRepo.GetIterationByID(ctx, question.IterationID)
+	if err != nil {
+		return nil, fmt.Errorf("get iteration: %w", err)
+	}
+	if iteration.SessionID != session.ID {
+		return nil, fmt.Errorf("%w: question %s is not part of the session", entity.ErrQuestionNotFound, questionID)
+	}
+
+	return uc.explainQuestion(ctx, session, question)
+}
+
+// explainQuestion serves the explanation of the question. Without on-demand explanations it is the
+// one generated with the question. Otherwise a cached explanation is returned, and a new one is generated
+// at most once per cooldown of the user; the one generated with the question is the fallback when the
+// LLM fails. session is loaded from the question when nil.
+func (uc *SessionUsecase) explainQuestion(ctx context.Context, session *entity.Session, question *entity.Question) (*entity.QuestionExplanation, error) {
+	stored := &entity.QuestionExplanation{QuestionID: question.ID, Explanation: question.Explanation}
+	if !uc.explainOnDemand {
+		metrics.ExplanationRequests.WithLabelValues("stored").Inc()
+		return stored, nil
+	}
+
+	cached, err := uc.explanationRepo.GetExplanation(ctx, question.ID)
+	if err == nil {
+		metrics.ExplanationRequests.WithLabelValues("hit").Inc()
+		cached.Cached = true
+		return cached, nil
+	}
+	if !errors.Is(err, entity.ErrExplanationNotFound) {
+		return nil, fmt.Errorf("get cached explanation: %w", err)
+	}
+
+	if uc.explanationCooldown > 0 {
+		started, err := uc.explanationRepo.StartCooldown(ctx, entity.UsageSubjectFromContext(ctx), uc.explanationCooldown)
+		if err != nil {
+			return nil, fmt.Errorf("start explanation cooldown: %w", err)
+		}
+		if !started {
+			metrics.ExplanationRequests.WithLabelValues("throttled").Inc()
+			return nil, fmt.Errorf("%w: one explanation per %s", entity.ErrExplanationCooldown, uc.explanationCooldown)
+		}
+	}
+
+	if session == nil {
+		iteration, err := uc.iterationRepo.GetIterationByID(ctx, question.IterationID)
+		if err != nil {
+			return nil, fmt.Errorf("get iteration: %w", err)
+		}
+		if session, err = uc.sessionRepo.GetSessionByID(ctx, iteration.SessionID); err != nil {
+			return nil, fmt.Errorf("get session: %w", err)
+		}
+	}
+
+	req := &entity.LLMExplainQuestionRequest{
+		Question:       question.Question,
+		Explanation:    question.Explanation,
+		TargetLanguage: targetLanguage(session),
+	}
+	if session.UserGoal != nil {
+		req.UserGoal = *session.UserGoal
+	}
+	if session.ProjectContext != nil {
+		req.ProjectContext = *session.ProjectContext
+	}
+
+	text, err := uc.llm(session).ExplainQuestion(ctx, req)
+	if err != nil {
+		ctxzap.Warn(ctx, "failed to generate explanation, returning the stored one",
+			zap.String("question_id", question.ID),
+			zap.Error(err),
+		)
+		metrics.ExplanationRequests.WithLabelValues("stored").Inc()
+		return stored, nil
+	}
+	metrics.ExplanationRequests.WithLabelValues("miss").Inc()
+
+	explanation, err := uc.explanationRepo.SaveExplanation(ctx, question.ID, text)
+	if err != nil {
+		ctxzap.Warn(ctx, "failed to cache explanation",
+			zap.String("question_id", question.ID),
+			zap.Error(err),
+		)
+		return &entity.QuestionExplanation{QuestionID: question.ID, Explanation: text}, nil
+	}
+
+	return explanation, nil
+}
@@ -0,0 +1,86 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/pkg/formatter"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// fallbackSaveTimeout bounds saving the collected materials after a generation that ran out of time
+const fallbackSaveTimeout = 10 * time.Second
+
+// generationFailed counts a failed final generation of the session. Once maxGenFailures generations
+// failed in a row, the collected materials become the result and the session is marked PARTIAL, so the
+// user leaves with something and can generate again later. Until then, and when the materials cannot
+// be saved, the generation error is returned.
+func (uc *SessionUsecase) generationFailed(ctx context.Context, session *entity.Session, genErr error) (*entity.Session, error) {
+	// Generations stopped by the user do not count
+	if uc.maxGenFailures <= 0 || errors.Is(ctx.Err(), context.Canceled) {
+		return nil, genErr
+	}
+
+	// A generation that ran out of time counts too, so the bookkeeping outlives its context
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), fallbackSaveTimeout)
+	defer cancel()
+
+	failures, err := uc.genFailureRepo.RecordFailure(ctx, session.ID, genErr.Error())
+	if err != nil {
+		ctxzap.Warn(ctx, "failed to record generation failure",
+			zap.Error(err),
+			zap.String("session_id", session.ID),
+		)
+		return nil, genErr
+	}
+	if failures < uc.maxGenFailures {
+		return nil, genErr
+	}
+
+	updated, err := uc.saveCollectedMaterials(ctx, session)
+	if err != nil {
+		ctxzap.Error(ctx, "failed to save collected materials",
+			zap.Error(err),
+			zap.String("session_id", session.ID),
+		)
+		return nil, genErr
+	}
+
+	// Later generations of the PARTIAL session count from zero again
+	if err := uc.genFailureRepo.ResetFailures(ctx, session.ID); err != nil {
+		ctxzap.Warn(ctx, "failed to reset generation failures",
+			zap.Error(err),
+			zap.String("session_id", session.ID),
+		)
+	}
+
+	ctxzap.Warn(ctx, "generation failed repeatedly, collected materials saved as the result",
+		zap.Error(genErr),
+		zap.String("session_id", session.ID),
+		zap.Int("failures", failures),
+	)
+
+	return updated, nil
+}
+
+// saveCollectedMaterials stores the document of the collected materials as the result of a PARTIAL session
+func (uc *SessionUsecase) saveCollectedMaterials(ctx context.Context, session *entity.Session) (*entity.Session, error) {
+	iterations, err := uc.bundleIterations(ctx, session.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	messages, err := uc.sessionMessageRepo.GetSessionMessages(ctx, session.ID)
+	if err != nil {
+		return nil, fmt.Errorf("get session messages: %w", err)
+	}
+	localizeMessageTimes(ctx, messages)
+
+	document := formatter.FormatCollectedMaterials(session, iterations, messages)
+
+	return uc.saveResultWithStatus(ctx, session, document, entity.SessionStatusPartial)
+}
@@ -0,0 +1,30 @@
+package session
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/futig/agent-backend/internal/entity"
+)
+
+// GetSessionFeatures evaluates every known feature flag for the session, so clients gate
+// the capabilities under rollout the same way the backend does
+func (uc *SessionUsecase) GetSessionFeatures(ctx context.Context, sessionID string) (map[entity.FeatureFlag]bool, error) {
+	session, err := uc.sessionRepo.GetSessionByID(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("get session: %w", err)
+	}
+
+	features := make(map[entity.FeatureFlag]bool, len(entity.KnownFeatureFlags))
+	for _, flag := range entity.KnownFeatureFlags {
+		features[flag] = uc.featureEnabled(ctx, session, flag)
+	}
+
+	return features, nil
+}
+
+// featureEnabled reports whether flag is on for the session; the session ID is the rollout
+// subject, so a session keeps its flags while the rollout percent does not change
+func (uc *SessionUsecase) featureEnabled(ctx context.Context, session *entity.Session, flag entity.FeatureFlag) bool {
+	return uc.featureFlags.Enabled(ctx, flag, session.ID)
+}
@@ -0,0 +1,75 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/pkg/tracing"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// RegenerateSummaryWithFeedback revises the whole result following free-text feedback of the
+// user and stores it as a new result version, so the document can be iterated on without
+// restarting the interview
+func (uc *SessionUsecase) RegenerateSummaryWithFeedback(ctx context.Context, sessionID, feedback string) (*entity.Session, error) {
+	ctx, span := tracing.Start(ctx, "SessionUsecase.RegenerateSummaryWithFeedback", tracing.SessionID(sessionID))
+	defer span.End()
+
+	feedback = strings.TrimSpace(feedback)
+	if feedback == "" {
+		return nil, fmt.Errorf("%w: feedback", entity.ErrMissingField)
+	}
+
+	finish, err := uc.startOperation(ctx, sessionID, entity.OperationKindResultFeedback)
+	if err != nil {
+		return nil, err
+	}
+	defer finish()
+
+	session, err := uc.sessionRepo.GetSessionByID(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("get session: %w", err)
+	}
+
+	if session.Status != entity.SessionStatusDone && session.Status != entity.SessionStatusAskResultFeedback {
+		return nil, entity.ErrNoResult
+	}
+
+	if err := uc.loadResult(ctx, session); err != nil {
+		return nil, err
+	}
+
+	if session.Result == nil || *session.Result == "" {
+		return nil, entity.ErrNoResult
+	}
+
+	feedback, err = uc.moderateInput(ctx, sessionID, entity.ModerationSourceFeedback, feedback)
+	if err != nil {
+		return nil, err
+	}
+
+	// Feedback is refined like a single comment on the whole document
+	result, err := uc.llm(session).RefineResult(ctx, &entity.LLMRefineResultRequest{
+		Result:         *session.Result,
+		Comments:       []entity.DocumentComment{{Text: feedback}},
+		TargetLanguage: targetLanguage(session),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("regenerate result with feedback: %w", err)
+	}
+
+	updatedSession, err := uc.saveRefinedResult(ctx, session, result)
+	if err != nil {
+		return nil, err
+	}
+
+	ctxzap.Info(ctx, "result regenerated with feedback",
+		zap.String("session_id", sessionID),
+		zap.Int("feedback_length", len(feedback)),
+	)
+
+	return updatedSession, nil
+}
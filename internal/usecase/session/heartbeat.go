@@ -0,0 +1,36 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/futig/agent-backend/internal/entity"
+)
+
+// Heartbeat records that an external orchestrator still drives the session, so it is not
+// reaped as idle. Heartbeats sooner than the minimum interval after the previous one are rejected.
+func (uc *SessionUsecase) Heartbeat(ctx context.Context, sessionID string) (*entity.Session, error) {
+	session, err := uc.sessionRepo.GetSessionByID(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("get session: %w", err)
+	}
+
+	switch session.Status {
+	case entity.SessionStatusDone, entity.SessionStatusCanceled, entity.SessionStatusError:
+		return nil, fmt.Errorf("%w: status '%s'", entity.ErrSessionNotActive, session.Status)
+	}
+
+	if session.LastActivityAt != nil {
+		if wait := uc.heartbeatInterval - time.Since(*session.LastActivityAt); wait > 0 {
+			return nil, fmt.Errorf("%w: retry in %s", entity.ErrHeartbeatTooFrequent, wait.Round(time.Second))
+		}
+	}
+
+	touched, err := uc.sessionRepo.TouchSessionActivity(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("touch session activity: %w", err)
+	}
+
+	return touched, nil
+}
@@ -4,25 +4,29 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/futig/agent-backend/internal/entity"
 	"github.com/google/uuid"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
 )
 
 // generateQuestionsBlocks calls LLM to generate question blocks
 func (uc *SessionUsecase) generateQuestionsBlocks(
 	ctx context.Context,
-	userGoal string,
-	projectContext string,
+	session *entity.Session,
 	projectDescription *string,
 ) ([]entity.QuestionsBlock, error) {
 	req := &entity.LLMGenerateQuestionsRequest{
-		UserGoal:           userGoal,
-		ProjectContext:     projectContext,
+		UserGoal:           *session.UserGoal,
+		ProjectContext:     *session.ProjectContext,
 		ProjectDescription: projectDescription,
+		TargetLanguage:     targetLanguage(session),
 	}
 
-	response, err := uc.llmConnector.GenerateQuestions(ctx, req)
+	response, err := uc.llm(session).GenerateQuestions(ctx, req)
 	if err != nil {
 		return nil, fmt.Errorf("generate questions: %w", err)
 	}
@@ -45,6 +49,13 @@ func (uc *SessionUsecase) saveQuestionsToDatabase(
 		maxIterationNumber = 0
 	}
 
+	blocks = uc.dedupQuestions(ctx, sessionID, blocks)
+
+	parentIDs, err := uc.parentQuestionIDs(ctx, sessionID, blocks)
+	if err != nil {
+		return nil, err
+	}
+
 	iterations := make([]*entity.IterationWithQuestions, 0, len(blocks))
 
 	for idx, block := range blocks {
@@ -73,6 +84,11 @@ func (uc *SessionUsecase) saveQuestionsToDatabase(
 				Status:         entity.AnswerStatusUnanswered,
 				Question:       q.Text,
 				Explanation:    q.Explanation,
+				Options:        q.Options,
+				AnswerType:     entity.ParseQuestionAnswerType(q.Type),
+			}
+			if parentID, ok := parentIDs[normalizeQuestionText(q.ParentQuestion)]; ok && q.ParentQuestion != "" {
+				question.ParentQuestionID = &parentID
 			}
 
 			if _, err := uc.questionRepo.CreateQuestion(ctx, question); err != nil {
@@ -85,9 +101,39 @@ func (uc *SessionUsecase) saveQuestionsToDatabase(
 		iterations = append(iterations, questionsToIterationDTO(savedIteration, questions))
 	}
 
+	uc.refreshCurrentQuestion(ctx, sessionID)
+
 	return iterations, nil
 }
 
+// parentQuestionIDs maps the texts of the session's questions to their IDs when any of the new
+// questions follows up on an earlier one; the LLM refers to parents by text
+func (uc *SessionUsecase) parentQuestionIDs(
+	ctx context.Context, sessionID string, blocks []entity.QuestionsBlock,
+) (map[string]string, error) {
+	hasParents := false
+	for _, block := range blocks {
+		for _, q := range block.Questions {
+			hasParents = hasParents || q.ParentQuestion != ""
+		}
+	}
+	if !hasParents {
+		return nil, nil
+	}
+
+	questions, err := uc.questionRepo.ListQuestionsBySession(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("list questions by session: %w", err)
+	}
+
+	ids := make(map[string]string, len(questions))
+	for _, q := range questions {
+		ids[normalizeQuestionText(q.Question)] = q.ID
+	}
+
+	return ids, nil
+}
+
 func (uc *SessionUsecase) getCurrentIteration(ctx context.Context, sessionID string) (*entity.IterationWithQuestions, error) {
 	currentIteration, err := uc.iterationRepo.GetCurrentIteration(ctx, sessionID)
 	if err != nil {
@@ -133,9 +179,26 @@ func (uc *SessionUsecase) formatManualContext(questions []entity.QuestionWithAns
 	return sb.String()
 }
 
-// transcribeAudio transcribes audio file to text
-func (uc *SessionUsecase) transcribeAudio(ctx context.Context, filename string, audioData []byte) (string, error) {
-	transcript, err := uc.asrConnector.TranscribeBytes(ctx, audioData, filename)
+// isVagueGoal reports whether a goal has too few words to generate meaningful questions, e.g. "сайт"
+func (uc *SessionUsecase) isVagueGoal(goal string) bool {
+	if uc.minGoalWords <= 0 {
+		return false
+	}
+
+	words := strings.FieldsFunc(goal, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return len(words) < uc.minGoalWords
+}
+
+// transcribeAudio transcribes audio file to text; the file is named after the upload when its name is known
+func (uc *SessionUsecase) transcribeAudio(ctx context.Context, session *entity.Session, audioData []byte) (string, error) {
+	filename := entity.AudioFileNameFromContext(ctx)
+	if filename == "" {
+		filename = session.ID
+	}
+
+	transcript, err := uc.asr(session).TranscribeBytes(ctx, audioData, filename)
 	if err != nil {
 		return "", fmt.Errorf("transcribe audio: %w", err)
 	}
@@ -147,6 +210,29 @@ func (uc *SessionUsecase) transcribeAudio(ctx context.Context, filename string,
 	return transcript, nil
 }
 
+// normalizeTranscript applies the normalization pass unless the session user turned it off
+// or the session is a demo; it returns the text to store and the original transcription when the pass changed it
+func (uc *SessionUsecase) normalizeTranscript(ctx context.Context, session *entity.Session, transcript string) (string, *string) {
+	if session.IsDemo {
+		return transcript, nil
+	}
+
+	enabled, err := uc.transcriptPrefs.NormalizeTranscripts(ctx, session.ID)
+	if err != nil {
+		ctxzap.Warn(ctx, "failed to get transcript normalization preference", zap.Error(err))
+	}
+	if !enabled {
+		return transcript, nil
+	}
+
+	normalized := uc.normalizer.Normalize(ctx, transcript)
+	if normalized == transcript || normalized == "" {
+		return transcript, nil
+	}
+
+	return normalized, &transcript
+}
+
 // collectAllAnswers collects all answered questions from all iterations
 func (uc *SessionUsecase) collectAllAnswers(ctx context.Context, sessionID string) ([]entity.QuestionWithAnswer, error) {
 	questions, err := uc.questionRepo.ListQuestionsBySession(ctx, sessionID)
@@ -178,3 +264,130 @@ func (uc *SessionUsecase) HasSkippedQuestions(ctx context.Context, sessionID str
 
 	return len(questions) > 0, nil
 }
+
+// moderateInput applies input moderation, records flagged inputs in the audit log
+// and returns the text to persist (redacted if configured) or ErrContentBlocked
+func (uc *SessionUsecase) moderateInput(
+	ctx context.Context,
+	sessionID string,
+	source entity.ModerationSource,
+	text string,
+) (string, error) {
+	result, err := uc.moderator.Moderate(ctx, text)
+	if err != nil {
+		return "", fmt.Errorf("moderate input: %w", err)
+	}
+
+	if !result.Flagged {
+		return text, nil
+	}
+
+	ctxzap.Warn(ctx, "user input flagged by moderation",
+		zap.String("session_id", sessionID),
+		zap.String("source", string(source)),
+		zap.String("moderation_action", string(result.Action)),
+		zap.Strings("matched_terms", result.MatchedTerms),
+		zap.Strings("categories", result.Categories),
+	)
+
+	if err := uc.auditRepo.RecordEvent(ctx, &entity.AuditEvent{
+		SessionID: sessionID,
+		Type:      entity.AuditEventModeration,
+		Details: map[string]any{
+			"source":        source,
+			"action":        result.Action,
+			"matched_terms": result.MatchedTerms,
+			"categories":    result.Categories,
+		},
+	}); err != nil {
+		ctxzap.Error(ctx, "failed to record moderation event", zap.Error(err))
+	}
+
+	if result.Action == entity.ModerationActionBlock {
+		return "", entity.ErrContentBlocked
+	}
+
+	return result.Text, nil
+}
+
+// estimateGeneration sums up the material that goes into the final generation prompt
+func (uc *SessionUsecase) estimateGeneration(ctx context.Context, session *entity.Session) (*entity.GenerationEstimate, error) {
+	chars := 0
+	if session.UserGoal != nil {
+		chars += utf8.RuneCountInString(*session.UserGoal)
+	}
+	if session.ProjectContext != nil {
+		chars += utf8.RuneCountInString(*session.ProjectContext)
+	}
+
+	answers, err := uc.collectAllAnswers(ctx, session.ID)
+	if err != nil {
+		return nil, fmt.Errorf("collect answers: %w", err)
+	}
+	for _, a := range answers {
+		chars += utf8.RuneCountInString(a.Question) + utf8.RuneCountInString(a.Answer)
+	}
+
+	if isDeltaSession(session) {
+		delta, err := uc.deltaRepo.GetDelta(ctx, session.ID)
+		if err != nil {
+			return nil, fmt.Errorf("get session delta: %w", err)
+		}
+		chars += utf8.RuneCountInString(delta.Baseline)
+	}
+
+	if session.Type != nil && *session.Type == entity.SessionTypeDraft {
+		messages, err := uc.sessionMessageRepo.GetSessionMessages(ctx, session.ID)
+		if err != nil {
+			return nil, fmt.Errorf("get session messages: %w", err)
+		}
+		for _, m := range messages {
+			chars += utf8.RuneCountInString(m.MessageText)
+		}
+	}
+
+	estimate := uc.estimator.Estimate(chars)
+	estimate.SessionID = session.ID
+
+	if estimate.RequiresAdminApproval {
+		approved, err := uc.approvalRepo.IsApproved(ctx, session.ID)
+		if err != nil {
+			return nil, fmt.Errorf("check generation approval: %w", err)
+		}
+		estimate.AdminApproved = approved
+	}
+
+	return estimate, nil
+}
+
+// ensureGenerationAllowed returns ErrAdminApprovalRequired for oversized sessions without admin approval
+// and ErrQuotaExceeded when the generation quota is used up; otherwise the generation is reserved in the quota
+func (uc *SessionUsecase) ensureGenerationAllowed(ctx context.Context, session *entity.Session) (*entity.GenerationEstimate, *quotaReservation, error) {
+	estimate, err := uc.estimateGeneration(ctx, session)
+	if err != nil {
+		return nil, nil, fmt.Errorf("estimate generation: %w", err)
+	}
+
+	if estimate.AwaitingApproval() && !session.IsDemo {
+		ctxzap.Warn(ctx, "generation blocked until admin approval",
+			zap.String("session_id", session.ID),
+			zap.Int("estimated_tokens", estimate.EstimatedTokens),
+		)
+		return nil, nil, entity.ErrAdminApprovalRequired
+	}
+
+	quota, err := uc.reserveQuota(ctx, entity.QuotaKindGenerations, session.IsDemo)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return estimate, quota, nil
+}
+
+// localizeMessageTimes moves the times of draft messages to the timezone of the user in ctx,
+// so the documents listing them show the local time
+func localizeMessageTimes(ctx context.Context, messages []*entity.SessionMessage) {
+	for _, message := range messages {
+		message.CreatedAt = entity.LocalTime(ctx, message.CreatedAt)
+	}
+}
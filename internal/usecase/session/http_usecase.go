@@ -5,26 +5,43 @@ import (
 	"fmt"
 	"io"
 	"mime/multipart"
+	"time"
 
 	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/pkg/tracing"
 	"github.com/google/uuid"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
 )
 
-// StartHTTPSession aggregates session creation, context generation, and question loading
+// StartHTTPSession aggregates session creation, context generation, and question loading.
+// The session is created in GENERATING_QUESTIONS status first so that clients can poll it,
+// and is switched to ERROR when question generation fails.
 func (uc *SessionUsecase) StartHTTPSession(
 	ctx context.Context,
 	req *entity.StartSessionRequest,
 ) (*entity.IterationWithQuestions, error) {
+	ctx, span := tracing.Start(ctx, "SessionUsecase.StartHTTPSession")
+	defer span.End()
+
 	session := &entity.Session{
-		ID:     uuid.New().String(),
-		Status: entity.SessionStatusWaitingForAnswers,
+		ID:     req.SessionID,
+		Status: entity.SessionStatusGeneratingQuestions,
+		IsDemo: req.Demo,
+
+		CallbackGranularity: req.CallbackGranularity,
+	}
+	if session.ID == "" {
+		session.ID = uuid.New().String()
 	}
 
 	sessionType := entity.SessionTypeInterview
+	if req.SessionType == entity.SessionTypeDelta {
+		sessionType = entity.SessionTypeDelta
+	}
 	session.Type = &sessionType
 	session.UserGoal = &req.UserGoal
 
-	var projectContext string
 	var projectDescription *string
 
 	if req.ProjectID != nil {
@@ -36,28 +53,61 @@ func (uc *SessionUsecase) StartHTTPSession(
 		}
 
 		projectDescription = &project.Description
-
-		projectContext, err = uc.ragConnector.GetContext(ctx, &entity.RAGGetContextRequest{
-			ProjectID:    *req.ProjectID,
-			UserGoal:     *session.UserGoal,
-			TopK:         5,
-			MaxQuestions: 10,
-		})
-		if err != nil {
-			return nil, fmt.Errorf("get RAG context: %w", err)
-		}
 	} else {
-		projectContext = uc.formatManualContext(req.ContextQuestions)
+		projectContext := uc.formatManualContext(req.ContextQuestions)
+		session.ProjectContext = &projectContext
 	}
 
-	session.ProjectContext = &projectContext
+	quota, err := uc.reserveQuota(ctx, entity.QuotaKindSessions, req.Demo)
+	if err != nil {
+		return nil, err
+	}
+	defer quota.done()
 
-	session, err := uc.sessionRepo.CreateFilledSession(ctx, session)
+	session, err = uc.sessionRepo.CreateFilledSession(ctx, session)
 	if err != nil {
 		return nil, fmt.Errorf("create filled session: %w", err)
 	}
+	quota.commit()
+	uc.detectLanguage(ctx, session, req.UserGoal)
+
+	iteration, err := uc.generateHTTPSessionQuestions(ctx, session, projectDescription)
+	if err != nil {
+		errMsg := err.Error()
+		if _, updateErr := uc.sessionRepo.UpdateSessionResult(ctx, session.ID, entity.SessionStatusError, nil, &errMsg); updateErr != nil {
+			ctxzap.Error(ctx, "failed to mark session as failed",
+				zap.Error(updateErr),
+				zap.String("session_id", session.ID),
+			)
+		}
+		return nil, err
+	}
+
+	uc.startTimeBudget(ctx, session.ID, time.Duration(req.TimeBudgetMin)*time.Minute)
+
+	return iteration, nil
+}
+
+// generateHTTPSessionQuestions fetches the project context and generates the first questions of a created session
+func (uc *SessionUsecase) generateHTTPSessionQuestions(
+	ctx context.Context,
+	session *entity.Session,
+	projectDescription *string,
+) (*entity.IterationWithQuestions, error) {
+	session, err := uc.loadProjectContext(ctx, session)
+	if err != nil {
+		return nil, err
+	}
 
-	blocks, err := uc.generateQuestionsBlocks(ctx, req.UserGoal, projectContext, projectDescription)
+	var blocks []entity.QuestionsBlock
+	if isDeltaSession(session) {
+		if err := uc.prepareDeltaBaseline(ctx, session); err != nil {
+			return nil, err
+		}
+		blocks, err = uc.generateDeltaQuestionsBlocks(ctx, session, projectDescription)
+	} else {
+		blocks, err = uc.generateQuestionsBlocks(ctx, session, projectDescription)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("generate questions: %w", err)
 	}
@@ -67,12 +117,95 @@ func (uc *SessionUsecase) StartHTTPSession(
 		return nil, fmt.Errorf("save questions: %w", err)
 	}
 
+	if _, err := uc.sessionRepo.UpdateSessionStatus(ctx, session.ID, entity.SessionStatusWaitingForAnswers); err != nil {
+		return nil, fmt.Errorf("update session status: %w", err)
+	}
+
 	return savedIterations[0], nil
 }
 
+// loadProjectContext fills the context of a project session from RAG; sessions without a project
+// keep the context formatted from the manual context questions
+func (uc *SessionUsecase) loadProjectContext(ctx context.Context, session *entity.Session) (*entity.Session, error) {
+	if session.ProjectID == nil {
+		return session, nil
+	}
+
+	projectContext, err := uc.rag(session).GetContext(ctx, uc.ragContextRequest(ctx, *session.ProjectID, *session.UserGoal))
+	if err != nil {
+		return nil, fmt.Errorf("get RAG context: %w", err)
+	}
+
+	session, err = uc.sessionRepo.UpdateSessionRAGProjectContext(ctx, session.ID, *session.ProjectID, projectContext)
+	if err != nil {
+		return nil, fmt.Errorf("update project context: %w", err)
+	}
+
+	return session, nil
+}
+
+// ragContextRequest builds the RAG context request of a project with the freshness of its
+// indexed materials; the freshness is left out when it cannot be read
+func (uc *SessionUsecase) ragContextRequest(ctx context.Context, projectID, userGoal string) *entity.RAGGetContextRequest {
+	req := &entity.RAGGetContextRequest{
+		ProjectID:    projectID,
+		UserGoal:     userGoal,
+		TopK:         5,
+		MaxQuestions: 10,
+	}
+
+	freshness, err := uc.projectRepo.GetFreshness(ctx, projectID)
+	if err != nil {
+		ctxzap.Warn(ctx, "failed to get project freshness",
+			zap.Error(err),
+			zap.String("project_id", projectID),
+		)
+		return req
+	}
+	req.Freshness = &entity.RAGFreshness{
+		FileCount:     freshness.FileCount,
+		LastIndexedAt: freshness.LastIndexedAt,
+		OldestFileAt:  freshness.OldestFileAt,
+	}
+
+	return req
+}
+
+// GetCurrentQuestions returns the current iteration of an HTTP session without advancing it,
+// for clients polling after an asynchronous start
+func (uc *SessionUsecase) GetCurrentQuestions(ctx context.Context, sessionID string) (*entity.IterationWithQuestions, error) {
+	session, err := uc.sessionRepo.GetSessionByID(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("get session: %w", err)
+	}
+
+	switch session.Status {
+	case entity.SessionStatusWaitingForAnswers:
+	case entity.SessionStatusGeneratingQuestions:
+		return nil, entity.ErrQuestionsNotReady
+	default:
+		return nil, fmt.Errorf("%w: '%s'", entity.ErrInvalidSessionStatus, session.Status)
+	}
+
+	iteration, err := uc.iterationRepo.GetCurrentIteration(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("get current iteration: %w", err)
+	}
+
+	questions, err := uc.questionRepo.ListQuestionsByIteration(ctx, iteration.ID)
+	if err != nil {
+		return nil, fmt.Errorf("list questions by iteration: %w", err)
+	}
+
+	return questionsToIterationDTO(iteration, questions), nil
+}
+
 func (uc *SessionUsecase) SubmitHTTPAudioAnswer(
 	ctx context.Context, sessionID, questionID string, audioFile *multipart.FileHeader,
 ) (*entity.IterationWithQuestions, error) {
+	ctx, span := tracing.Start(ctx, "SessionUsecase.SubmitHTTPAudioAnswer", tracing.SessionID(sessionID))
+	defer span.End()
+
 	session, err := uc.sessionRepo.GetSessionByID(ctx, sessionID)
 	if err != nil {
 		return nil, fmt.Errorf("get session: %w", err)
@@ -95,5 +228,6 @@ func (uc *SessionUsecase) SubmitHTTPAudioAnswer(
 
 	file.Close()
 
+	ctx = entity.WithAudioFileName(ctx, audioFile.Filename)
 	return uc.SubmitAudioAnswer(ctx, sessionID, questionID, audioData)
 }
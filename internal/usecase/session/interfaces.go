@@ -13,9 +13,77 @@ type RagConnector interface {
 type LLMConnector interface {
 	GenerateQuestions(ctx context.Context, req *entity.LLMGenerateQuestionsRequest) (*entity.LLMGenerateQuestionsResponse, error)
 	GenerateSummary(ctx context.Context, req *entity.LLMGenerateSummaryRequest) (string, error)
+	GenerateSummaryStream(ctx context.Context, req *entity.LLMGenerateSummaryRequest) (<-chan entity.LLMSummaryChunk, error)
 	ValidateAnswers(ctx context.Context, req *entity.LLMValidateAnswersRequest) (*entity.LLMValidateAnswersResponse, error)
 	ValidateDraft(ctx context.Context, req *entity.LLMValidateDraftRequest) (*entity.LLMValidateAnswersResponse, error)
 	GenerateDraftSummary(ctx context.Context, req *entity.LLMGenerateDraftSummaryRequest) (string, error)
+	GenerateOutline(ctx context.Context, req *entity.LLMGenerateOutlineRequest) (*entity.LLMGenerateOutlineResponse, error)
+	GenerateSection(ctx context.Context, req *entity.LLMGenerateSectionRequest) (string, error)
+	RefineResult(ctx context.Context, req *entity.LLMRefineResultRequest) (string, error)
+	RestyleResult(ctx context.Context, req *entity.LLMRestyleResultRequest) (string, error)
+	GenerateDeltaQuestions(ctx context.Context, req *entity.LLMGenerateDeltaQuestionsRequest) (*entity.LLMGenerateQuestionsResponse, error)
+	GenerateDeltaSummary(ctx context.Context, req *entity.LLMGenerateDeltaSummaryRequest) (*entity.LLMGenerateDeltaSummaryResponse, error)
+	DetectConflicts(ctx context.Context, req *entity.LLMDetectConflictsRequest) (*entity.LLMDetectConflictsResponse, error)
+	Translate(ctx context.Context, req *entity.LLMTranslateRequest) (string, error)
+	NormalizeTranscript(ctx context.Context, req *entity.LLMNormalizeTranscriptRequest) (string, error)
+	DescribeProject(ctx context.Context, req *entity.LLMDescribeProjectRequest) (string, error)
+	ExtractFacts(ctx context.Context, req *entity.LLMExtractFactsRequest) ([]string, error)
+	ExplainQuestion(ctx context.Context, req *entity.LLMExplainQuestionRequest) (string, error)
+}
+
+type Moderator interface {
+	Moderate(ctx context.Context, text string) (*entity.ModerationResult, error)
+}
+
+// Normalizer cleans up voice transcriptions before they are stored
+type Normalizer interface {
+	Normalize(ctx context.Context, text string) string
+}
+
+// TranscriptPreferences tells whether the user of a session wants transcriptions normalized
+type TranscriptPreferences interface {
+	NormalizeTranscripts(ctx context.Context, sessionID string) (bool, error)
+}
+
+type Estimator interface {
+	Estimate(materialChars int) *entity.GenerationEstimate
+}
+
+type ReviewNotifier interface {
+	NotifyReviewRequested(ctx context.Context, approver entity.ResultApprover, review *entity.ResultReview, result string, fileInfo *entity.ResultFileInfo) error
+}
+
+// ResultStore keeps large result bodies in S3-compatible storage
+type ResultStore interface {
+	PutObject(ctx context.Context, key string, data []byte, contentType string) error
+	GetObject(ctx context.Context, key string) ([]byte, error)
+	MarkSuperseded(ctx context.Context, key string) error
+	DeleteObject(ctx context.Context, key string) error
+}
+
+// FeatureFlags evaluates the gradual rollouts of risky capabilities for a subject
+type FeatureFlags interface {
+	Enabled(ctx context.Context, flag entity.FeatureFlag, subject string) bool
+}
+
+// Quotas enforces the usage quotas of the user and the tenant in ctx
+type Quotas interface {
+	Reserve(ctx context.Context, kind entity.QuotaKind) (release func(), err error)
+	Usage(ctx context.Context) (*entity.QuotaUsage, error)
+}
+
+// ThemeResolver returns the document theme of a result, nil for unbranded results
+type ThemeResolver interface {
+	ResolveTheme(ctx context.Context, project *entity.Project) (*entity.DocumentTheme, error)
+}
+
+type ScheduleNotifier interface {
+	NotifyScheduledSession(ctx context.Context, telegramUserID int64, session *entity.Session, projectTitle string) error
+}
+
+// VoiceAnswerNotifier tells Telegram users that their queued voice answer has been transcribed and accepted
+type VoiceAnswerNotifier interface {
+	NotifyVoiceAnswerAccepted(ctx context.Context, telegramUserID int64, question, answer string) error
 }
 
 type ASRConnector interface {
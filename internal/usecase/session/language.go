@@ -0,0 +1,78 @@
+package session
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/pkg/langdetect"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// targetLanguage is the language the LLM writes in, empty leaves it to the LLM
+func targetLanguage(session *entity.Session) string {
+	if session.Language == nil {
+		return ""
+	}
+	return string(*session.Language)
+}
+
+// detectLanguage stores the language of the first user input it is confident about; later inputs
+// in another language do not change it, the user is asked instead (see DetectLanguageSwitch)
+func (uc *SessionUsecase) detectLanguage(ctx context.Context, session *entity.Session, text string) {
+	if session.Language != nil {
+		return
+	}
+
+	language, confident := langdetect.Detect(text)
+	if !confident {
+		return
+	}
+
+	updated, err := uc.sessionRepo.UpdateSessionLanguage(ctx, session.ID, language, false)
+	if err != nil {
+		// Detection is best-effort, the LLM still follows the language of the inputs
+		ctxzap.Warn(ctx, "failed to save detected session language",
+			zap.Error(err),
+			zap.String("session_id", session.ID),
+		)
+		return
+	}
+	session.Language = updated.Language
+}
+
+// DetectLanguageSwitch returns the language of the text when it is confidently detected and
+// differs from the language of the session, empty otherwise. A language set by the user is kept
+// without asking again
+func (uc *SessionUsecase) DetectLanguageSwitch(ctx context.Context, sessionID, text string) (entity.ResultLanguage, error) {
+	session, err := uc.sessionRepo.GetSessionByID(ctx, sessionID)
+	if err != nil {
+		return "", fmt.Errorf("get session: %w", err)
+	}
+	if session.Language == nil || session.LanguageExplicit {
+		return "", nil
+	}
+
+	language, confident := langdetect.Detect(text)
+	if !confident || language == *session.Language {
+		return "", nil
+	}
+	return language, nil
+}
+
+// SetSessionLanguage sets the language of the generated documents explicitly; detection no
+// longer changes it
+func (uc *SessionUsecase) SetSessionLanguage(
+	ctx context.Context, sessionID string, language entity.ResultLanguage,
+) (*entity.Session, error) {
+	if !language.IsValid() {
+		return nil, fmt.Errorf("unsupported language '%s': %w", language, entity.ErrInvalidParameter)
+	}
+
+	session, err := uc.sessionRepo.UpdateSessionLanguage(ctx, sessionID, language, true)
+	if err != nil {
+		return nil, fmt.Errorf("update session language: %w", err)
+	}
+	return session, nil
+}
@@ -0,0 +1,125 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// lockSession waits until no other action works on the session, e.g. one of the bot while a
+// linked client submits an answer, and returns the function releasing the session. Waiting past
+// the lock timeout fails with ErrSessionBusy
+func (uc *SessionUsecase) lockSession(ctx context.Context, sessionID string) (func(), error) {
+	if uc.lockWait <= 0 {
+		return func() {}, nil
+	}
+
+	lockCtx, cancel := context.WithTimeout(ctx, uc.lockWait)
+	defer cancel()
+
+	unlock, err := uc.sessionLocks.Lock(lockCtx, sessionID)
+	if err != nil {
+		if ctx.Err() == nil && errors.Is(lockCtx.Err(), context.DeadlineExceeded) {
+			return nil, fmt.Errorf("%w: waited %s", entity.ErrSessionBusy, uc.lockWait)
+		}
+		return nil, fmt.Errorf("lock session: %w", err)
+	}
+
+	return unlock, nil
+}
+
+// operationFinishTimeout bounds the removal of an operation registration, which runs after the
+// context of the operation may be done
+const operationFinishTimeout = 5 * time.Second
+
+// startOperation registers a heavy operation on the session, so that no other one runs at the same
+// time, e.g. a second generation started from another device, and returns the function finishing
+// it. Unlike lockSession it fails right away with ErrOperationInProgress. The registration is
+// renewed while the operation runs, so it expires soon after its process dies
+func (uc *SessionUsecase) startOperation(ctx context.Context, sessionID string, kind entity.OperationKind) (func(), error) {
+	if uc.operationTTL <= 0 {
+		return func() {}, nil
+	}
+
+	owner := entity.UsageSubjectFromContext(ctx)
+	if owner == "" {
+		owner = "system"
+	}
+
+	op, err := uc.sessionOperations.StartOperation(ctx, &entity.SessionOperation{
+		SessionID: sessionID,
+		Kind:      kind,
+		Owner:     owner,
+	}, uc.operationTTL)
+	if err != nil {
+		if errors.Is(err, entity.ErrOperationInProgress) {
+			if current, getErr := uc.sessionOperations.GetOperation(ctx, sessionID); getErr == nil {
+				return nil, current.InProgressError()
+			}
+		}
+		return nil, err
+	}
+
+	renewCtx, stopRenewal := context.WithCancel(context.WithoutCancel(ctx))
+	renewed := make(chan struct{})
+	go func() {
+		defer close(renewed)
+		uc.renewOperation(renewCtx, op)
+	}()
+
+	return func() {
+		stopRenewal()
+		<-renewed
+
+		finishCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), operationFinishTimeout)
+		defer cancel()
+		if err := uc.sessionOperations.FinishOperation(finishCtx, op); err != nil {
+			ctxzap.Warn(ctx, "failed to finish session operation",
+				zap.Error(err),
+				zap.String("session_id", sessionID),
+				zap.String("operation", string(kind)),
+			)
+		}
+	}, nil
+}
+
+// renewOperation keeps the operation registered every third of its TTL until ctx is done
+func (uc *SessionUsecase) renewOperation(ctx context.Context, op *entity.SessionOperation) {
+	ticker := time.NewTicker(uc.operationTTL / 3)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := uc.sessionOperations.RenewOperation(ctx, op, uc.operationTTL)
+			if err == nil || ctx.Err() != nil {
+				continue
+			}
+			ctxzap.Warn(ctx, "failed to renew session operation",
+				zap.Error(err),
+				zap.String("session_id", op.SessionID),
+				zap.String("operation", string(op.Kind)),
+			)
+			// Taken over after it expired, the session belongs to the other operation now
+			if errors.Is(err, entity.ErrNoOperation) {
+				return
+			}
+		}
+	}
+}
+
+// GetActiveOperation returns the heavy operation in flight on the session, ErrNoOperation when none runs
+func (uc *SessionUsecase) GetActiveOperation(ctx context.Context, sessionID string) (*entity.SessionOperation, error) {
+	if uc.operationTTL <= 0 {
+		return nil, entity.ErrNoOperation
+	}
+
+	return uc.sessionOperations.GetOperation(ctx, sessionID)
+}
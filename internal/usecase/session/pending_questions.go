@@ -0,0 +1,93 @@
+package session
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// SavePendingQuestions keeps a questions block the consumer's callback URL did not accept, so the
+// consumer can pull it instead of the session stalling. Saving is best-effort
+func (uc *SessionUsecase) SavePendingQuestions(
+	ctx context.Context,
+	sessionID, requestID string,
+	data *entity.IterationWithQuestions,
+	deliveryErr error,
+) {
+	if data == nil {
+		return
+	}
+
+	_, err := uc.pendingQuestions.SavePendingQuestions(ctx, &entity.PendingQuestions{
+		SessionID:     sessionID,
+		IterationID:   data.IterationID,
+		RequestID:     requestID,
+		Questions:     data,
+		DeliveryError: deliveryErr.Error(),
+	})
+	if err != nil {
+		ctxzap.Warn(ctx, "failed to save undelivered questions",
+			zap.String("iteration_id", data.IterationID),
+			zap.Error(err),
+		)
+	}
+}
+
+// ListPendingQuestions returns the questions blocks of the session whose callback was not delivered, oldest first
+func (uc *SessionUsecase) ListPendingQuestions(ctx context.Context, sessionID string) ([]*entity.PendingQuestions, error) {
+	if _, err := uc.sessionRepo.GetSessionByID(ctx, sessionID); err != nil {
+		return nil, fmt.Errorf("get session: %w", err)
+	}
+
+	pending, err := uc.pendingQuestions.ListPendingQuestions(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("list pending questions: %w", err)
+	}
+
+	return pending, nil
+}
+
+// AcknowledgePendingQuestions marks a pulled questions block as received. The session counts as
+// active again, and when no block is left pending the current questions are returned
+func (uc *SessionUsecase) AcknowledgePendingQuestions(
+	ctx context.Context,
+	sessionID, iterationID string,
+) (*entity.PendingQuestionsAck, error) {
+	if _, err := uc.sessionRepo.GetSessionByID(ctx, sessionID); err != nil {
+		return nil, fmt.Errorf("get session: %w", err)
+	}
+
+	acknowledged, err := uc.pendingQuestions.AcknowledgePendingQuestions(ctx, sessionID, iterationID)
+	if err != nil {
+		return nil, err
+	}
+	if !acknowledged {
+		return nil, fmt.Errorf("iteration %s: %w", iterationID, entity.ErrPendingQuestionsNotFound)
+	}
+
+	session, err := uc.sessionRepo.TouchSessionActivity(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("touch session activity: %w", err)
+	}
+
+	pending, err := uc.pendingQuestions.ListPendingQuestions(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("list pending questions: %w", err)
+	}
+
+	ack := &entity.PendingQuestionsAck{Pending: len(pending)}
+	if len(pending) > 0 || session.Status != entity.SessionStatusWaitingForAnswers {
+		return ack, nil
+	}
+
+	current, err := uc.GetCurrentQuestions(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	ack.CurrentQuestions = current
+
+	return ack, nil
+}
@@ -0,0 +1,106 @@
+package session
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// CountAnsweredQuestions returns how many questions of the session are answered, the answers a
+// change of the project discards
+func (uc *SessionUsecase) CountAnsweredQuestions(ctx context.Context, sessionID string) (int, error) {
+	questions, err := uc.questionRepo.ListQuestionsBySession(ctx, sessionID)
+	if err != nil {
+		return 0, fmt.Errorf("list questions: %w", err)
+	}
+
+	return countAnswered(questions), nil
+}
+
+// settleIterationsForProject decides what happens to the questions of a session whose project was
+// selected again. Returning to the project they were generated for keeps them, any other project or
+// manual context discards them with their answers. Both outcomes are audited
+func (uc *SessionUsecase) settleIterationsForProject(ctx context.Context, session *entity.Session, projectID *string) error {
+	iterations, err := uc.iterationRepo.ListIterationsBySession(ctx, session.ID)
+	if err != nil {
+		return fmt.Errorf("list iterations: %w", err)
+	}
+	if len(iterations) == 0 {
+		return nil
+	}
+
+	questions, err := uc.questionRepo.ListQuestionsBySession(ctx, session.ID)
+	if err != nil {
+		return fmt.Errorf("list questions: %w", err)
+	}
+	answered := countAnswered(questions)
+
+	preserved := session.ProjectID != nil && projectID != nil && *session.ProjectID == *projectID
+	if !preserved {
+		if _, err := uc.iterationRepo.DeleteIterationsBySession(ctx, session.ID); err != nil {
+			return err
+		}
+	}
+
+	ctxzap.Info(ctx, "session project changed",
+		zap.String("session_id", session.ID),
+		zap.Bool("questions_preserved", preserved),
+		zap.Int("iterations", len(iterations)),
+		zap.Int("answers", answered),
+	)
+
+	details := map[string]any{
+		"preserved":  preserved,
+		"iterations": len(iterations),
+		"questions":  len(questions),
+		"answers":    answered,
+	}
+	if session.ProjectID != nil {
+		details["previous_project_id"] = *session.ProjectID
+	}
+	if projectID != nil {
+		details["project_id"] = *projectID
+	}
+	if err := uc.auditRepo.RecordEvent(ctx, &entity.AuditEvent{
+		SessionID: session.ID,
+		Type:      entity.AuditEventProjectChanged,
+		Details:   details,
+	}); err != nil {
+		ctxzap.Error(ctx, "failed to record project change", zap.Error(err))
+	}
+
+	return nil
+}
+
+// loadedIterations returns the questions a session already has, e.g. kept after the user returned
+// to the same project, so the interview continues without generating them again
+func (uc *SessionUsecase) loadedIterations(ctx context.Context, sessionID string) ([]*entity.IterationWithQuestions, error) {
+	iterations, err := uc.iterationRepo.ListIterationsBySession(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("list iterations: %w", err)
+	}
+
+	loaded := make([]*entity.IterationWithQuestions, 0, len(iterations))
+	for _, it := range iterations {
+		iteration, err := uc.GetIterationByID(ctx, it.ID)
+		if err != nil {
+			return nil, err
+		}
+		loaded = append(loaded, iteration)
+	}
+
+	return loaded, nil
+}
+
+func countAnswered(questions []*entity.Question) int {
+	answered := 0
+	for _, q := range questions {
+		if q.Status == entity.AnswerStatusAnswered {
+			answered++
+		}
+	}
+	return answered
+}
@@ -0,0 +1,33 @@
+package session
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/futig/agent-backend/internal/entity"
+)
+
+// GetQuestionScript returns the generated interview blocks with their questions for the script
+// export; blocks without questions are left out
+func (uc *SessionUsecase) GetQuestionScript(ctx context.Context, sessionID string) ([]*entity.BundleIteration, error) {
+	if _, err := uc.sessionRepo.GetSessionByID(ctx, sessionID); err != nil {
+		return nil, fmt.Errorf("get session: %w", err)
+	}
+
+	iterations, err := uc.bundleIterations(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	script := make([]*entity.BundleIteration, 0, len(iterations))
+	for _, iteration := range iterations {
+		if len(iteration.Questions) > 0 {
+			script = append(script, iteration)
+		}
+	}
+	if len(script) == 0 {
+		return nil, entity.ErrQuestionsNotReady
+	}
+
+	return script, nil
+}
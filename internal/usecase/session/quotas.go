@@ -0,0 +1,45 @@
+package session
+
+import (
+	"context"
+
+	"github.com/futig/agent-backend/internal/entity"
+)
+
+// GetQuotaUsage returns the usage of the quotas of the user and the tenant in ctx
+func (uc *SessionUsecase) GetQuotaUsage(ctx context.Context) (*entity.QuotaUsage, error) {
+	return uc.quotas.Usage(ctx)
+}
+
+// quotaReservation is one usage of a quota reserved for an operation; the usage is given back
+// when the operation ends without committing it
+type quotaReservation struct {
+	release   func()
+	committed bool
+}
+
+// commit keeps the usage, the operation succeeded
+func (r *quotaReservation) commit() {
+	r.committed = true
+}
+
+// done gives the usage back unless it was committed; deferred right after the reservation
+func (r *quotaReservation) done() {
+	if !r.committed {
+		r.release()
+	}
+}
+
+// reserveQuota reserves one usage of kind or returns ErrQuotaExceeded when the quota is used up;
+// demo sessions run on mocks and do not count against quotas
+func (uc *SessionUsecase) reserveQuota(ctx context.Context, kind entity.QuotaKind, demo bool) (*quotaReservation, error) {
+	if demo {
+		return &quotaReservation{release: func() {}}, nil
+	}
+
+	release, err := uc.quotas.Reserve(ctx, kind)
+	if err != nil {
+		return nil, err
+	}
+	return &quotaReservation{release: release}, nil
+}
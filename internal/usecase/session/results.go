@@ -0,0 +1,215 @@
+package session
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+const resultContentType = "text/markdown; charset=utf-8"
+
+// saveResult stores a new version of the session result and marks the session done.
+// Results above the inline threshold go to blob storage and only their metadata stays in Postgres.
+// Demo session results are labeled as such. The returned session always carries the result body.
+func (uc *SessionUsecase) saveResult(ctx context.Context, session *entity.Session, result string) (*entity.Session, error) {
+	updated, err := uc.saveResultWithStatus(ctx, session, result, entity.SessionStatusDone)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.genFailureRepo.ResetFailures(ctx, session.ID); err != nil {
+		ctxzap.Warn(ctx, "failed to reset generation failures",
+			zap.Error(err),
+			zap.String("session_id", session.ID),
+		)
+	}
+
+	return updated, nil
+}
+
+// saveResultWithStatus stores a new version of the session result and moves the session to status
+func (uc *SessionUsecase) saveResultWithStatus(
+	ctx context.Context, session *entity.Session, result string, status entity.SessionStatus,
+) (*entity.Session, error) {
+	sessionID := session.ID
+	result = labelDemoResult(session, result)
+
+	previous, err := uc.resultVersionRepo.GetLatestVersion(ctx, sessionID)
+	if err != nil && !errors.Is(err, entity.ErrNoResult) {
+		return nil, fmt.Errorf("get latest result version: %w", err)
+	}
+
+	checksum := sha256.Sum256([]byte(result))
+	version := &entity.ResultVersion{
+		SessionID: sessionID,
+		Version:   1,
+		Storage:   entity.ResultStorageInline,
+		SizeBytes: len(result),
+		Checksum:  hex.EncodeToString(checksum[:]),
+	}
+	if previous != nil {
+		version.Version = previous.Version + 1
+	}
+
+	inlineResult := &result
+	if uc.resultStore != nil && len(result) > uc.inlineResultLimit {
+		key := fmt.Sprintf("%s/v%d.md", sessionID, version.Version)
+		if err := uc.resultStore.PutObject(ctx, key, []byte(result), resultContentType); err != nil {
+			return nil, fmt.Errorf("store result: %w", err)
+		}
+		version.Storage = entity.ResultStorageBlob
+		version.ObjectKey = &key
+		inlineResult = nil
+	}
+
+	// sessions.result holds only the current body, so the previous inline body moves into its
+	// version before it is overwritten
+	if previous != nil && previous.Storage == entity.ResultStorageInline {
+		if err := uc.resultVersionRepo.ArchiveContent(ctx, previous); err != nil {
+			return nil, err
+		}
+	}
+
+	// Metadata goes first so a stored body is never left without a reference
+	if _, err := uc.resultVersionRepo.CreateVersion(ctx, version); err != nil {
+		return nil, fmt.Errorf("create result version: %w", err)
+	}
+
+	updated, err := uc.sessionRepo.UpdateSessionResult(ctx, sessionID, status, inlineResult, nil)
+	if err != nil {
+		return nil, fmt.Errorf("update session result: %w", err)
+	}
+	updated.Result = &result
+
+	ctxzap.Debug(ctx, "session result saved",
+		zap.String("session_id", sessionID),
+		zap.Int("version", version.Version),
+		zap.String("storage", string(version.Storage)),
+		zap.Int("size_bytes", version.SizeBytes),
+	)
+
+	return updated, nil
+}
+
+// loadResult fetches the body of a finished session's result from blob storage when it is not
+// kept inline; repository reads leave it out, so only callers that need the body touch the storage
+func (uc *SessionUsecase) loadResult(ctx context.Context, session *entity.Session) error {
+	if session.Result != nil || (session.Status != entity.SessionStatusDone && session.Status != entity.SessionStatusPartial) {
+		return nil
+	}
+
+	version, err := uc.resultVersionRepo.GetLatestVersion(ctx, session.ID)
+	if err != nil {
+		if errors.Is(err, entity.ErrNoResult) {
+			return nil
+		}
+		return fmt.Errorf("get latest result version: %w", err)
+	}
+
+	if version.Storage != entity.ResultStorageBlob || version.ObjectKey == nil {
+		return nil
+	}
+
+	if uc.resultStore == nil {
+		return fmt.Errorf("result of session %s is in blob storage, but the storage is disabled", session.ID)
+	}
+
+	data, err := uc.resultStore.GetObject(ctx, *version.ObjectKey)
+	if err != nil {
+		return fmt.Errorf("load result: %w", err)
+	}
+
+	result := string(data)
+	session.Result = &result
+	return nil
+}
+
+// currentInlineVersion reports whether version is the inline version whose body is the session result
+func currentInlineVersion(session *entity.Session, version *entity.ResultVersion) bool {
+	if version.Storage != entity.ResultStorageInline || version.Content != nil || session.Result == nil {
+		return false
+	}
+
+	checksum := sha256.Sum256([]byte(*session.Result))
+	return hex.EncodeToString(checksum[:]) == version.Checksum
+}
+
+// ListResultVersions returns the generated result versions of a session, newest first; every
+// generation, refinement and restyle of the result adds one
+func (uc *SessionUsecase) ListResultVersions(ctx context.Context, sessionID string) (*entity.ResultVersionsResponse, error) {
+	session, err := uc.sessionRepo.GetSessionByID(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("get session: %w", err)
+	}
+
+	versions, err := uc.resultVersionRepo.ListVersions(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("list result versions: %w", err)
+	}
+
+	resp := &entity.ResultVersionsResponse{
+		SessionID: sessionID,
+		Versions:  versions,
+	}
+	if len(versions) > 0 && (session.Status == entity.SessionStatusDone || session.Status == entity.SessionStatusPartial) {
+		resp.Current = versions[0].Version
+	}
+	if len(versions) > 0 && currentInlineVersion(session, versions[0]) {
+		versions[0].Available = true
+	}
+
+	return resp, nil
+}
+
+// GetResultVersion returns a result version of a session with its body. Like the current result
+// it is released only once the review allows it; ErrResultVersionPurged when the body is gone
+func (uc *SessionUsecase) GetResultVersion(ctx context.Context, sessionID string, number int) (*entity.ResultVersion, string, error) {
+	if number < 1 {
+		return nil, "", fmt.Errorf("result version must be positive: %w", entity.ErrInvalidParameter)
+	}
+
+	session, err := uc.sessionRepo.GetSessionByID(ctx, sessionID)
+	if err != nil {
+		return nil, "", fmt.Errorf("get session: %w", err)
+	}
+
+	version, err := uc.resultVersionRepo.GetVersion(ctx, sessionID, number)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if session.Status == entity.SessionStatusDone {
+		if err := uc.EnsureResultReleasable(ctx, sessionID); err != nil {
+			return nil, "", err
+		}
+	}
+
+	switch {
+	case version.Storage == entity.ResultStorageInline && version.Content != nil:
+		return version, *version.Content, nil
+	case currentInlineVersion(session, version):
+		return version, *session.Result, nil
+	case version.Storage == entity.ResultStorageBlob && version.ObjectKey != nil:
+		if uc.resultStore == nil {
+			return nil, "", fmt.Errorf("result of session %s is in blob storage, but the storage is disabled", sessionID)
+		}
+
+		// Objects are removed only by content retention
+		data, err := uc.resultStore.GetObject(ctx, *version.ObjectKey)
+		if err != nil {
+			if errors.Is(err, entity.ErrObjectNotFound) {
+				return nil, "", fmt.Errorf("%w: version %d", entity.ErrResultVersionPurged, number)
+			}
+			return nil, "", fmt.Errorf("load result version: %w", err)
+		}
+		return version, string(data), nil
+	default:
+		return nil, "", fmt.Errorf("%w: version %d", entity.ErrResultVersionPurged, number)
+	}
+}
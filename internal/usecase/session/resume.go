@@ -0,0 +1,81 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// ResumeSession recovers a session whose flow was interrupted, e.g. by a restart of the bot while
+// answers were validated or requirements generated. A session stuck in processing goes back to the
+// step the user continues from: an interview waits for answers and a draft collects messages again.
+// An interview whose questions were saved before the interruption starts waiting for answers.
+// Sessions that were not interrupted are returned unchanged; a session whose operation still runs
+// fails with ErrOperationInProgress until the operation finishes or its registration expires
+func (uc *SessionUsecase) ResumeSession(ctx context.Context, sessionID string) (*entity.Session, error) {
+	op, err := uc.GetActiveOperation(ctx, sessionID)
+	if err == nil {
+		return nil, op.InProgressError()
+	}
+	if !errors.Is(err, entity.ErrNoOperation) {
+		return nil, fmt.Errorf("get session operation: %w", err)
+	}
+
+	unlock, err := uc.lockSession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	session, err := uc.sessionRepo.GetSessionByID(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("get session: %w", err)
+	}
+
+	status, err := uc.resumeStatus(ctx, session)
+	if err != nil {
+		return nil, err
+	}
+
+	if status != session.Status {
+		if _, err := uc.transitionStatus(ctx, sessionID, session.Status, status); err != nil {
+			return nil, err
+		}
+		if status == entity.SessionStatusWaitingForAnswers {
+			uc.refreshCurrentQuestion(ctx, sessionID)
+		}
+
+		ctxzap.Info(ctx, "interrupted session resumed",
+			zap.String("session_id", sessionID),
+			zap.String("from", string(session.Status)),
+			zap.String("to", string(status)),
+		)
+	}
+
+	return uc.GetSession(ctx, sessionID)
+}
+
+// resumeStatus returns the status an interrupted session continues from
+func (uc *SessionUsecase) resumeStatus(ctx context.Context, session *entity.Session) (entity.SessionStatus, error) {
+	switch session.Status {
+	case entity.SessionStatusValidating, entity.SessionStatusGeneratingRequirements:
+		if session.Type != nil && *session.Type == entity.SessionTypeDraft {
+			return entity.SessionStatusDraftCollecting, nil
+		}
+		return entity.SessionStatusWaitingForAnswers, nil
+	case entity.SessionStatusInterviewInfo:
+		iterations, err := uc.iterationRepo.ListIterationsBySession(ctx, session.ID)
+		if err != nil {
+			return "", fmt.Errorf("list iterations: %w", err)
+		}
+		if len(iterations) > 0 {
+			return entity.SessionStatusWaitingForAnswers, nil
+		}
+	}
+
+	return session.Status, nil
+}
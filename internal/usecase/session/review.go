@@ -0,0 +1,221 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// GetReview returns the approval state of the session result; results never submitted are drafts
+func (uc *SessionUsecase) GetReview(ctx context.Context, sessionID string) (*entity.ResultReview, error) {
+	if _, err := uc.sessionRepo.GetSessionByID(ctx, sessionID); err != nil {
+		return nil, fmt.Errorf("get session: %w", err)
+	}
+
+	return uc.getReview(ctx, sessionID)
+}
+
+// SubmitForReview assigns approvers, moves the result to review and notifies the approvers
+func (uc *SessionUsecase) SubmitForReview(
+	ctx context.Context,
+	sessionID string,
+	approvers []entity.ResultApprover,
+) (*entity.ResultReview, error) {
+	session, err := uc.sessionRepo.GetSessionByID(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("get session: %w", err)
+	}
+
+	if session.IsDemo {
+		return nil, entity.ErrDemoSession
+	}
+
+	if err := uc.loadResult(ctx, session); err != nil {
+		return nil, err
+	}
+
+	if session.Status != entity.SessionStatusDone || session.Result == nil || *session.Result == "" {
+		return nil, entity.ErrNoResult
+	}
+
+	if len(approvers) == 0 {
+		return nil, fmt.Errorf("%w: approvers", entity.ErrMissingField)
+	}
+
+	if err := uc.ensureConflictsResolved(ctx, sessionID); err != nil {
+		return nil, err
+	}
+
+	review, err := uc.getReview(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if review.Status != entity.ReviewStatusDraft && review.Status != entity.ReviewStatusRejected {
+		return nil, fmt.Errorf("submit review in status '%s': %w", review.Status, entity.ErrInvalidReviewTransition)
+	}
+
+	fileInfo, err := uc.GetResultFileInfo(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	review.Status = entity.ReviewStatusInReview
+	review.Approvers = approvers
+	review.DecidedBy = nil
+	review.DecisionComment = nil
+
+	savedReview, err := uc.reviewRepo.SaveReview(ctx, review)
+	if err != nil {
+		return nil, fmt.Errorf("save review: %w", err)
+	}
+
+	uc.recordReviewEvent(ctx, entity.AuditEventReviewSubmitted, savedReview, map[string]any{
+		"approvers": len(savedReview.Approvers),
+	})
+
+	for _, approver := range savedReview.Approvers {
+		if err := uc.reviewNotifier.NotifyReviewRequested(ctx, approver, savedReview, *session.Result, fileInfo); err != nil {
+			ctxzap.Warn(ctx, "failed to notify approver",
+				zap.Error(err),
+				zap.String("approver_type", string(approver.Type)),
+				zap.String("approver_id", approver.ID),
+			)
+		}
+	}
+
+	return savedReview, nil
+}
+
+// DecideReview records an approve or reject decision of an assigned approver
+func (uc *SessionUsecase) DecideReview(
+	ctx context.Context,
+	sessionID string,
+	approver entity.ResultApprover,
+	approve bool,
+	comment string,
+) (*entity.ResultReview, error) {
+	review, err := uc.GetReview(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if review.Status != entity.ReviewStatusInReview {
+		return nil, fmt.Errorf("decide review in status '%s': %w", review.Status, entity.ErrInvalidReviewTransition)
+	}
+
+	if !review.HasApprover(approver) {
+		return nil, entity.ErrNotApprover
+	}
+
+	review.Status = entity.ReviewStatusRejected
+	if approve {
+		review.Status = entity.ReviewStatusApproved
+	}
+	review.DecidedBy = &approver
+	review.DecisionComment = nil
+	if comment != "" {
+		review.DecisionComment = &comment
+	}
+
+	savedReview, err := uc.reviewRepo.SaveReview(ctx, review)
+	if err != nil {
+		return nil, fmt.Errorf("save review: %w", err)
+	}
+
+	uc.recordReviewEvent(ctx, entity.AuditEventReviewDecided, savedReview, map[string]any{
+		"status":        string(savedReview.Status),
+		"approver_type": string(approver.Type),
+		"approver_id":   approver.ID,
+		"comment":       comment,
+	})
+
+	return savedReview, nil
+}
+
+// EnsureResultReleasable returns ErrUnresolvedConflicts while detected conflicts are open
+// and ErrResultNotApproved when approval is required and the result is not approved yet
+func (uc *SessionUsecase) EnsureResultReleasable(ctx context.Context, sessionID string) error {
+	if err := uc.ensureConflictsResolved(ctx, sessionID); err != nil {
+		return err
+	}
+
+	if !uc.requireApproval {
+		return nil
+	}
+
+	review, err := uc.getReview(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+
+	if review.Status != entity.ReviewStatusApproved {
+		return entity.ErrResultNotApproved
+	}
+
+	return nil
+}
+
+// getReview returns the stored review or a draft review without approvers
+func (uc *SessionUsecase) getReview(ctx context.Context, sessionID string) (*entity.ResultReview, error) {
+	review, err := uc.reviewRepo.GetReview(ctx, sessionID)
+	if err == nil {
+		return review, nil
+	}
+	if !errors.Is(err, entity.ErrReviewNotFound) {
+		return nil, fmt.Errorf("get review: %w", err)
+	}
+
+	return &entity.ResultReview{
+		SessionID: sessionID,
+		Status:    entity.ReviewStatusDraft,
+		Approvers: []entity.ResultApprover{},
+	}, nil
+}
+
+// resetReview returns a submitted or decided review to draft after the result has changed
+func (uc *SessionUsecase) resetReview(ctx context.Context, sessionID string) error {
+	review, err := uc.reviewRepo.GetReview(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, entity.ErrReviewNotFound) {
+			return nil
+		}
+		return fmt.Errorf("get review: %w", err)
+	}
+
+	if review.Status == entity.ReviewStatusDraft {
+		return nil
+	}
+
+	review.Status = entity.ReviewStatusDraft
+	review.DecidedBy = nil
+	review.DecisionComment = nil
+
+	if _, err := uc.reviewRepo.SaveReview(ctx, review); err != nil {
+		return fmt.Errorf("reset review: %w", err)
+	}
+
+	return nil
+}
+
+func (uc *SessionUsecase) recordReviewEvent(
+	ctx context.Context,
+	eventType entity.AuditEventType,
+	review *entity.ResultReview,
+	details map[string]any,
+) {
+	if err := uc.auditRepo.RecordEvent(ctx, &entity.AuditEvent{
+		SessionID: review.SessionID,
+		Type:      eventType,
+		Details:   details,
+	}); err != nil {
+		ctxzap.Error(ctx, "failed to record review event",
+			zap.Error(err),
+			zap.String("event_type", string(eventType)),
+		)
+	}
+}
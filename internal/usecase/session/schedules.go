@@ -0,0 +1,154 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/pkg/cron"
+	"github.com/google/uuid"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// StartDueScheduledSessions creates check-in sessions for schedules due at now and offers
+// them to the bound Telegram users. Missed runs are collapsed into a single session.
+// Returns the number of sessions started.
+func (uc *SessionUsecase) StartDueScheduledSessions(ctx context.Context, now time.Time) (int, error) {
+	schedules, err := uc.scheduleRepo.ListDueSchedules(ctx, now)
+	if err != nil {
+		return 0, fmt.Errorf("list due schedules: %w", err)
+	}
+
+	started := 0
+	for _, schedule := range schedules {
+		scheduleCtx := ctxzap.ToContext(ctx, ctxzap.Extract(ctx).With(
+			zap.String("schedule_id", schedule.ID),
+			zap.String("project_id", schedule.ProjectID),
+		))
+
+		// Schedules are listed across tenants, each session is created in the tenant of its project
+		tenant, err := uc.tenantRepo.GetByProjectID(scheduleCtx, schedule.ProjectID)
+		if err != nil {
+			ctxzap.Error(scheduleCtx, "failed to resolve schedule tenant", zap.Error(err))
+			continue
+		}
+		scheduleCtx = entity.WithTenant(scheduleCtx, tenant)
+
+		loc, err := entity.LoadTimezone(schedule.Timezone)
+		if err != nil {
+			ctxzap.Warn(scheduleCtx, "invalid schedule timezone, using UTC", zap.Error(err))
+			loc = time.UTC
+		}
+		scheduleCtx = entity.WithLocation(scheduleCtx, loc)
+
+		session, err := uc.startScheduledSession(scheduleCtx, schedule, now)
+		if err != nil {
+			ctxzap.Error(scheduleCtx, "failed to start scheduled session", zap.Error(err))
+			continue
+		}
+		if session != nil {
+			started++
+		}
+	}
+
+	return started, nil
+}
+
+// startScheduledSession claims the schedule run and creates the session; the next run is found
+// in the timezone of the schedule in ctx. nil session means the run was claimed by another worker
+func (uc *SessionUsecase) startScheduledSession(
+	ctx context.Context,
+	schedule *entity.ProjectSchedule,
+	now time.Time,
+) (*entity.Session, error) {
+	sched, err := cron.Parse(schedule.CronExpr)
+	if err != nil {
+		return nil, fmt.Errorf("parse cron '%s': %w", schedule.CronExpr, err)
+	}
+
+	nextRunAt := sched.Next(entity.LocalTime(ctx, now)).UTC()
+	claimed, err := uc.scheduleRepo.ClaimSchedule(ctx, schedule.ID, schedule.NextRunAt, nextRunAt)
+	if err != nil {
+		return nil, fmt.Errorf("claim schedule: %w", err)
+	}
+	if !claimed {
+		return nil, nil
+	}
+
+	project, err := uc.projectRepo.Get(ctx, schedule.ProjectID)
+	if err != nil {
+		return nil, fmt.Errorf("get project: %w", err)
+	}
+
+	projectContext, err := uc.scheduledSessionContext(ctx, schedule)
+	if err != nil {
+		return nil, err
+	}
+
+	session, err := uc.sessionRepo.CreateFilledSession(ctx, &entity.Session{
+		ID:             uuid.New().String(),
+		ProjectID:      &schedule.ProjectID,
+		Status:         entity.SessionStatusChooseMode,
+		UserGoal:       &schedule.UserGoal,
+		ProjectContext: &projectContext,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create session: %w", err)
+	}
+
+	if err := uc.scheduleRepo.SetLastSession(ctx, schedule.ID, session.ID); err != nil {
+		ctxzap.Warn(ctx, "failed to link scheduled session", zap.Error(err))
+	}
+
+	if err := uc.auditRepo.RecordEvent(ctx, &entity.AuditEvent{
+		SessionID: session.ID,
+		Type:      entity.AuditEventSessionScheduled,
+		Details: map[string]any{
+			"schedule_id": schedule.ID,
+			"project_id":  schedule.ProjectID,
+		},
+	}); err != nil {
+		ctxzap.Error(ctx, "failed to record schedule event", zap.Error(err))
+	}
+
+	if err := uc.scheduleNotifier.NotifyScheduledSession(ctx, schedule.TelegramUserID, session, project.Title); err != nil {
+		ctxzap.Warn(ctx, "failed to notify about scheduled session",
+			zap.Error(err),
+			zap.Int64("telegram_user_id", schedule.TelegramUserID),
+		)
+	}
+
+	ctxzap.Info(ctx, "scheduled session started", zap.String("session_id", session.ID))
+
+	return session, nil
+}
+
+// scheduledSessionContext combines RAG context with the latest project requirements,
+// so that questions focus on what has changed since then
+func (uc *SessionUsecase) scheduledSessionContext(ctx context.Context, schedule *entity.ProjectSchedule) (string, error) {
+	projectContext, err := uc.ragConnector.GetContext(ctx, uc.ragContextRequest(ctx, schedule.ProjectID, schedule.UserGoal))
+	if err != nil {
+		return "", fmt.Errorf("get RAG context: %w", err)
+	}
+
+	previous, err := uc.sessionRepo.GetLatestProjectResultSession(ctx, schedule.ProjectID)
+	if err != nil {
+		if errors.Is(err, entity.ErrSessionNotFound) {
+			return projectContext, nil
+		}
+		return "", fmt.Errorf("get previous requirements: %w", err)
+	}
+
+	if err := uc.loadResult(ctx, previous); err != nil {
+		return "", fmt.Errorf("load previous requirements: %w", err)
+	}
+
+	return fmt.Sprintf(
+		"%s\n\nТребования, собранные на прошлой сессии (%s). "+
+			"Уточняй, что изменилось с тех пор, не повторяя уже известное:\n%s",
+		projectContext, entity.LocalTime(ctx, previous.UpdatedAt).Format("02.01.2006"), *previous.Result,
+	), nil
+}
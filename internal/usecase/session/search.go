@@ -0,0 +1,64 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/pkg/tracing"
+)
+
+const (
+	searchResultsLimit = 10
+	maxSearchQueryLen  = 200
+)
+
+// SearchSessionContent finds the user's own answers and draft messages matching the query
+func (uc *SessionUsecase) SearchSessionContent(ctx context.Context, sessionID, query string) ([]*entity.SessionSearchHit, error) {
+	ctx, span := tracing.Start(ctx, "SessionUsecase.SearchSessionContent", tracing.SessionID(sessionID))
+	defer span.End()
+
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, fmt.Errorf("%w: q", entity.ErrMissingField)
+	}
+	if utf8.RuneCountInString(query) > maxSearchQueryLen {
+		return nil, fmt.Errorf("%w: q is longer than %d characters", entity.ErrInvalidParameter, maxSearchQueryLen)
+	}
+
+	if _, err := uc.sessionRepo.GetSessionByID(ctx, sessionID); err != nil {
+		return nil, fmt.Errorf("get session: %w", err)
+	}
+
+	hits, err := uc.sessionMessageRepo.SearchSessionContent(ctx, sessionID, query, searchResultsLimit)
+	if err != nil {
+		return nil, fmt.Errorf("search session content: %w", err)
+	}
+
+	return hits, nil
+}
+
+// AdminSearch finds projects, files and sessions matching the query for support staff
+func (uc *SessionUsecase) AdminSearch(ctx context.Context, req *entity.AdminSearchRequest) (*entity.AdminSearchResponse, error) {
+	req.Query = strings.TrimSpace(req.Query)
+	if req.Query == "" {
+		return nil, fmt.Errorf("%w: q", entity.ErrMissingField)
+	}
+	if utf8.RuneCountInString(req.Query) > maxSearchQueryLen {
+		return nil, fmt.Errorf("%w: q is longer than %d characters", entity.ErrInvalidParameter, maxSearchQueryLen)
+	}
+
+	hits, total, err := uc.searchRepo.SearchAll(ctx, req.Query, req.Skip, req.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("search all: %w", err)
+	}
+
+	return &entity.AdminSearchResponse{
+		Hits:  hits,
+		Total: total,
+		Skip:  req.Skip,
+		Limit: req.Limit,
+	}, nil
+}
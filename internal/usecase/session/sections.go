@@ -0,0 +1,280 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/pkg/tracing"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// ListResultSections returns sections of the session result. Results generated in a single
+// pass are split by their second-level headings.
+func (uc *SessionUsecase) ListResultSections(ctx context.Context, sessionID string) ([]*entity.ResultSection, error) {
+	session, err := uc.sessionRepo.GetSessionByID(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("get session: %w", err)
+	}
+
+	return uc.resultSections(ctx, session)
+}
+
+// RegenerateResultSection regenerates a single section of the result with optional user
+// guidance and reassembles the document, keeping the other sections unchanged
+func (uc *SessionUsecase) RegenerateResultSection(
+	ctx context.Context,
+	sessionID string,
+	sectionIndex int,
+	guidance string,
+) (*entity.Session, error) {
+	ctx, span := tracing.Start(ctx, "SessionUsecase.RegenerateResultSection", tracing.SessionID(sessionID))
+	defer span.End()
+
+	finish, err := uc.startOperation(ctx, sessionID, entity.OperationKindRegenerateSection)
+	if err != nil {
+		return nil, err
+	}
+	defer finish()
+
+	session, err := uc.sessionRepo.GetSessionByID(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("get session: %w", err)
+	}
+
+	if session.Status != entity.SessionStatusDone && session.Status != entity.SessionStatusAskSectionGuidance {
+		return nil, entity.ErrNoResult
+	}
+
+	sections, err := uc.resultSections(ctx, session)
+	if err != nil {
+		return nil, err
+	}
+
+	if sectionIndex < 0 || sectionIndex >= len(sections) {
+		return nil, fmt.Errorf("section %d: %w", sectionIndex, entity.ErrSectionNotFound)
+	}
+
+	if guidance != "" {
+		guidance, err = uc.moderateInput(ctx, sessionID, entity.ModerationSourceGuidance, guidance)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	material, err := uc.collectSectionedContext(ctx, session)
+	if err != nil {
+		return nil, err
+	}
+
+	outline := make([]entity.DocumentSection, 0, len(sections))
+	for _, s := range sections {
+		outline = append(outline, entity.DocumentSection{Title: s.Title, Description: s.Description})
+	}
+
+	section := sections[sectionIndex]
+	content, err := uc.llm(session).GenerateSection(ctx, &entity.LLMGenerateSectionRequest{
+		LLMSectionedContext: *material,
+		Outline:             outline,
+		Section:             outline[sectionIndex],
+		SectionIndex:        sectionIndex,
+		CurrentContent:      section.Content,
+		Guidance:            guidance,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("generate section %d: %w", sectionIndex, err)
+	}
+
+	section.Content = content
+	if err := uc.sectionRepo.ReplaceSections(ctx, sessionID, sections); err != nil {
+		return nil, fmt.Errorf("save result sections: %w", err)
+	}
+
+	result := assembleSections(sections)
+	updatedSession, err := uc.saveResult(ctx, session, result)
+	if err != nil {
+		return nil, fmt.Errorf("save summary: %w", err)
+	}
+
+	// Cached translations were made from the previous document
+	if err := uc.translationRepo.DeleteTranslations(ctx, sessionID); err != nil {
+		return nil, fmt.Errorf("invalidate translations: %w", err)
+	}
+
+	// A changed result has to be reviewed again
+	if err := uc.resetReview(ctx, sessionID); err != nil {
+		return nil, err
+	}
+
+	ctxzap.Info(ctx, "result section regenerated",
+		zap.String("session_id", sessionID),
+		zap.Int("section_index", sectionIndex),
+		zap.Bool("with_guidance", guidance != ""),
+	)
+
+	return updatedSession, nil
+}
+
+// resultSections returns stored sections or splits a single-pass result into sections
+func (uc *SessionUsecase) resultSections(ctx context.Context, session *entity.Session) ([]*entity.ResultSection, error) {
+	sections, err := uc.sectionRepo.ListSections(ctx, session.ID)
+	if err != nil {
+		return nil, fmt.Errorf("list result sections: %w", err)
+	}
+
+	if len(sections) > 0 {
+		return sections, nil
+	}
+
+	if err := uc.loadResult(ctx, session); err != nil {
+		return nil, err
+	}
+
+	if session.Result == nil || *session.Result == "" {
+		return nil, entity.ErrNoResult
+	}
+
+	return splitResultSections(session.ID, *session.Result), nil
+}
+
+// generateSectioned generates the document section by section guided by an outline pass,
+// so that very long results are not truncated by LLM output limits
+func (uc *SessionUsecase) generateSectioned(ctx context.Context, session *entity.Session) (string, error) {
+	material, err := uc.collectSectionedContext(ctx, session)
+	if err != nil {
+		return "", err
+	}
+
+	outline, err := uc.llm(session).GenerateOutline(ctx, &entity.LLMGenerateOutlineRequest{
+		LLMSectionedContext: *material,
+	})
+	if err != nil {
+		return "", fmt.Errorf("generate outline: %w", err)
+	}
+
+	ctxzap.Info(ctx, "generating sectioned result",
+		zap.String("session_id", session.ID),
+		zap.Int("sections", len(outline.Sections)),
+	)
+
+	sections := make([]*entity.ResultSection, 0, len(outline.Sections))
+	for idx, s := range outline.Sections {
+		content, err := uc.llm(session).GenerateSection(ctx, &entity.LLMGenerateSectionRequest{
+			LLMSectionedContext: *material,
+			Outline:             outline.Sections,
+			Section:             s,
+			SectionIndex:        idx,
+		})
+		if err != nil {
+			return "", fmt.Errorf("generate section %d: %w", idx, err)
+		}
+
+		sections = append(sections, &entity.ResultSection{
+			SessionID:    session.ID,
+			SectionIndex: idx,
+			Title:        s.Title,
+			Description:  s.Description,
+			Content:      content,
+		})
+	}
+
+	if err := uc.sectionRepo.ReplaceSections(ctx, session.ID, sections); err != nil {
+		return "", fmt.Errorf("save result sections: %w", err)
+	}
+
+	return assembleSections(sections), nil
+}
+
+// collectSectionedContext gathers the material used by outline and section generation
+func (uc *SessionUsecase) collectSectionedContext(ctx context.Context, session *entity.Session) (*entity.LLMSectionedContext, error) {
+	material := &entity.LLMSectionedContext{
+		TargetLanguage: targetLanguage(session),
+		Style:          summaryStyle(session),
+	}
+	if session.UserGoal != nil {
+		material.UserGoal = *session.UserGoal
+	}
+	if session.ProjectContext != nil {
+		material.ProjectContext = *session.ProjectContext
+	}
+
+	answers, err := uc.collectAllAnswers(ctx, session.ID)
+	if err != nil {
+		return nil, fmt.Errorf("collect answers: %w", err)
+	}
+	material.CompleteQuestions = answers
+	material.Conversation = uc.recentConversation(ctx, session.ID)
+
+	if session.Type != nil && *session.Type == entity.SessionTypeDraft {
+		messages, err := uc.sessionMessageRepo.GetSessionMessages(ctx, session.ID)
+		if err != nil {
+			return nil, fmt.Errorf("get session messages: %w", err)
+		}
+		for _, m := range messages {
+			material.Messages = append(material.Messages, m.MessageText)
+		}
+	}
+
+	if session.ProjectID != nil && *session.ProjectID != "" {
+		project, err := uc.projectRepo.Get(ctx, *session.ProjectID)
+		if err != nil {
+			return nil, fmt.Errorf("get project description: %w", err)
+		}
+		material.ProjectDescription = &project.Description
+	}
+
+	return material, nil
+}
+
+// assembleSections joins sections into a single markdown document;
+// an untitled section is the preamble before the first heading
+func assembleSections(sections []*entity.ResultSection) string {
+	var sb strings.Builder
+	for i, s := range sections {
+		if i > 0 {
+			sb.WriteString("\n\n")
+		}
+		if s.Title == "" {
+			sb.WriteString(strings.TrimSpace(s.Content))
+			continue
+		}
+		fmt.Fprintf(&sb, "%s%s\n\n%s", sectionHeadingPrefix, s.Title, strings.TrimSpace(s.Content))
+	}
+	return sb.String()
+}
+
+// sectionHeadingPrefix marks top-level document sections in markdown results
+const sectionHeadingPrefix = "## "
+
+// splitResultSections splits a markdown result by its second-level headings
+func splitResultSections(sessionID, result string) []*entity.ResultSection {
+	sections := make([]*entity.ResultSection, 0)
+	current := &entity.ResultSection{SessionID: sessionID}
+	var content []string
+
+	flush := func() {
+		current.Content = strings.TrimSpace(strings.Join(content, "\n"))
+		if current.Title != "" || current.Content != "" {
+			current.SectionIndex = len(sections)
+			sections = append(sections, current)
+		}
+	}
+
+	for _, line := range strings.Split(result, "\n") {
+		if strings.HasPrefix(line, sectionHeadingPrefix) {
+			flush()
+			current = &entity.ResultSection{
+				SessionID: sessionID,
+				Title:     strings.TrimSpace(strings.TrimPrefix(line, sectionHeadingPrefix)),
+			}
+			content = nil
+			continue
+		}
+		content = append(content, line)
+	}
+	flush()
+
+	return sections
+}
@@ -0,0 +1,126 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// skipSummaryTitle heads the appendix listing the questions left without an answer
+const skipSummaryTitle = "## Приложение: вопросы без ответа"
+
+// skipSummaryGroups orders the appendix groups; the empty reason collects questions skipped without one
+var skipSummaryGroups = []struct {
+	reason entity.SkipReason
+	title  string
+}{
+	{entity.SkipReasonUnknown, "Ответ неизвестен"},
+	{entity.SkipReasonLater, "Ответ будет дан позже"},
+	{entity.SkipReasonIrrelevant, "Не относится к задаче"},
+	{"", "Причина не указана"},
+}
+
+// SetSkipReason records why the user skipped a question
+func (uc *SessionUsecase) SetSkipReason(ctx context.Context, sessionID, questionID string, reason entity.SkipReason) error {
+	if !reason.IsValid() {
+		return fmt.Errorf("%w: skip_reason", entity.ErrInvalidParameter)
+	}
+
+	// The session lookup is scoped to the tenant and owner, the update to the session
+	if _, err := uc.sessionRepo.GetSessionByID(ctx, sessionID); err != nil {
+		return fmt.Errorf("get session: %w", err)
+	}
+
+	if err := uc.questionRepo.SetSkipReason(ctx, sessionID, questionID, reason); err != nil {
+		return fmt.Errorf("set skip reason: %w", err)
+	}
+
+	ctxzap.Info(ctx, "skip reason recorded",
+		zap.String("session_id", sessionID),
+		zap.String("question_id", questionID),
+		zap.String("reason", string(reason)),
+	)
+
+	return nil
+}
+
+// declinedQuestions returns the texts of questions the user skipped as not relevant
+func declinedQuestions(questions []*entity.Question) []string {
+	declined := make([]string, 0)
+	for _, q := range questions {
+		if q.Status == entity.AnswerStatusSkiped && q.SkipReason != nil && *q.SkipReason == entity.SkipReasonIrrelevant {
+			declined = append(declined, q.Question)
+		}
+	}
+	return declined
+}
+
+// dropDeclinedQuestions removes additional questions that repeat ones declined as not relevant
+func dropDeclinedQuestions(questions []entity.LLMQuestion, declined []string) []entity.LLMQuestion {
+	if len(declined) == 0 {
+		return questions
+	}
+
+	seen := make(map[string]struct{}, len(declined))
+	for _, q := range declined {
+		seen[normalizeQuestionText(q)] = struct{}{}
+	}
+
+	kept := make([]entity.LLMQuestion, 0, len(questions))
+	for _, q := range questions {
+		if _, ok := seen[normalizeQuestionText(q.Text)]; ok {
+			continue
+		}
+		kept = append(kept, q)
+	}
+	return kept
+}
+
+func normalizeQuestionText(text string) string {
+	return strings.ToLower(strings.Join(strings.Fields(text), " "))
+}
+
+// appendSkipSummary adds an appendix listing the questions left without an answer, grouped by skip reason
+func (uc *SessionUsecase) appendSkipSummary(ctx context.Context, sessionID, result string) (string, error) {
+	questions, err := uc.questionRepo.GetUnansweredQuestions(ctx, sessionID)
+	if err != nil {
+		return "", fmt.Errorf("get unanswered questions: %w", err)
+	}
+
+	if len(questions) == 0 {
+		return result, nil
+	}
+
+	grouped := make(map[entity.SkipReason][]string)
+	for _, q := range questions {
+		var reason entity.SkipReason
+		if q.SkipReason != nil {
+			reason = *q.SkipReason
+		}
+		grouped[reason] = append(grouped[reason], q.Question)
+	}
+
+	var b strings.Builder
+	b.WriteString(strings.TrimRight(result, "\n"))
+	b.WriteString("\n\n")
+	b.WriteString(skipSummaryTitle)
+	b.WriteString("\n")
+
+	for _, group := range skipSummaryGroups {
+		texts := grouped[group.reason]
+		if len(texts) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(&b, "\n**%s**\n\n", group.title)
+		for _, text := range texts {
+			fmt.Fprintf(&b, "- %s\n", text)
+		}
+	}
+
+	return b.String(), nil
+}
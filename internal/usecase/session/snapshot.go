@@ -0,0 +1,110 @@
+package session
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// ContextSnapshot limits the facts already provided in a session that are sent along with
+// validation requests; zero MaxFacts leaves them out
+type ContextSnapshot struct {
+	MaxFacts int
+}
+
+// knownFacts returns the facts the user already provided in the session, or nil when snapshots
+// are disabled. The facts are extracted again only when the material of the session changed since
+// the stored snapshot; a failed extraction falls back to the stored facts.
+func (uc *SessionUsecase) knownFacts(
+	ctx context.Context,
+	session *entity.Session,
+	messages []string,
+	answers []entity.QuestionWithAnswer,
+	projectDescription *string,
+) []string {
+	if uc.contextSnapshot.MaxFacts <= 0 {
+		return nil
+	}
+
+	hash := materialHash(session, messages, answers)
+
+	stored, err := uc.factsRepo.GetFacts(ctx, session.ID)
+	if err != nil && !errors.Is(err, entity.ErrSessionFactsNotFound) {
+		ctxzap.Warn(ctx, "failed to read context snapshot",
+			zap.Error(err),
+			zap.String("session_id", session.ID),
+		)
+	}
+	var previous []string
+	if stored != nil {
+		if stored.MaterialHash == hash {
+			return stored.Facts
+		}
+		previous = stored.Facts
+	}
+
+	req := &entity.LLMExtractFactsRequest{
+		Messages:           messages,
+		CompleteQuestions:  answers,
+		ProjectDescription: projectDescription,
+		KnownFacts:         previous,
+		MaxFacts:           uc.contextSnapshot.MaxFacts,
+	}
+	if session.UserGoal != nil {
+		req.UserGoal = *session.UserGoal
+	}
+	if session.ProjectContext != nil {
+		req.ProjectContext = *session.ProjectContext
+	}
+
+	facts, err := uc.llm(session).ExtractFacts(ctx, req)
+	if err != nil {
+		ctxzap.Warn(ctx, "failed to extract session facts",
+			zap.Error(err),
+			zap.String("session_id", session.ID),
+		)
+		return previous
+	}
+	if len(facts) > uc.contextSnapshot.MaxFacts {
+		facts = facts[:uc.contextSnapshot.MaxFacts]
+	}
+
+	if _, err := uc.factsRepo.SaveFacts(ctx, session.ID, facts, hash); err != nil {
+		ctxzap.Warn(ctx, "failed to save context snapshot",
+			zap.Error(err),
+			zap.String("session_id", session.ID),
+		)
+	}
+
+	return facts
+}
+
+// materialHash identifies what the facts of a session are extracted from
+func materialHash(session *entity.Session, messages []string, answers []entity.QuestionWithAnswer) string {
+	h := sha256.New()
+	write := func(value string) {
+		h.Write([]byte(value))
+		h.Write([]byte{0})
+	}
+
+	if session.UserGoal != nil {
+		write(*session.UserGoal)
+	}
+	if session.ProjectContext != nil {
+		write(*session.ProjectContext)
+	}
+	for _, message := range messages {
+		write(message)
+	}
+	for _, qa := range answers {
+		write(qa.Question)
+		write(qa.Answer)
+	}
+
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,100 @@
+package session
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// summaryStyle is the style the LLM writes the document in, empty leaves it to the LLM
+func summaryStyle(session *entity.Session) entity.SummaryStyle {
+	if session.SummaryStyle == nil {
+		return ""
+	}
+	return *session.SummaryStyle
+}
+
+// SetSummaryStyle stores the style of the session document. A generated result is rewritten in
+// the new style in a single LLM pass; before generation the style applies to the generation
+func (uc *SessionUsecase) SetSummaryStyle(ctx context.Context, sessionID string, style entity.SummaryStyle) (*entity.Session, error) {
+	if !style.IsValid() {
+		return nil, fmt.Errorf("unsupported summary style '%s': %w", style, entity.ErrInvalidParameter)
+	}
+
+	unlock, err := uc.lockSession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	session, err := uc.sessionRepo.GetSessionByID(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("get session: %w", err)
+	}
+
+	if session.Status == entity.SessionStatusError || session.Status == entity.SessionStatusCanceled {
+		return nil, fmt.Errorf("%w: '%s'", entity.ErrInvalidSessionStatus, session.Status)
+	}
+
+	if err := uc.loadResult(ctx, session); err != nil {
+		return nil, err
+	}
+
+	restyle := session.Status == entity.SessionStatusDone && session.Result != nil && *session.Result != "" &&
+		summaryStyle(session) != style
+
+	var result string
+	if restyle {
+		finish, err := uc.startOperation(ctx, sessionID, entity.OperationKindRestyleResult)
+		if err != nil {
+			return nil, err
+		}
+		defer finish()
+
+		result, err = uc.llm(session).RestyleResult(ctx, &entity.LLMRestyleResultRequest{
+			Result:         *session.Result,
+			Style:          style,
+			TargetLanguage: targetLanguage(session),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("restyle result: %w", err)
+		}
+	}
+
+	updatedSession, err := uc.sessionRepo.UpdateSessionSummaryStyle(ctx, sessionID, style)
+	if err != nil {
+		return nil, fmt.Errorf("update summary style: %w", err)
+	}
+	if !restyle {
+		return updatedSession, nil
+	}
+
+	// Stored sections describe the previous document; the restyled one is split on demand
+	if err := uc.sectionRepo.ReplaceSections(ctx, sessionID, nil); err != nil {
+		return nil, fmt.Errorf("reset result sections: %w", err)
+	}
+
+	updatedSession, err = uc.saveResult(ctx, updatedSession, result)
+	if err != nil {
+		return nil, fmt.Errorf("save summary: %w", err)
+	}
+
+	if err := uc.translationRepo.DeleteTranslations(ctx, sessionID); err != nil {
+		return nil, fmt.Errorf("invalidate translations: %w", err)
+	}
+
+	// A changed result has to be reviewed again
+	if err := uc.resetReview(ctx, sessionID); err != nil {
+		return nil, err
+	}
+
+	ctxzap.Info(ctx, "result restyled",
+		zap.String("session_id", sessionID),
+		zap.String("style", string(style)),
+	)
+
+	return updatedSession, nil
+}
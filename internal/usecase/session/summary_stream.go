@@ -0,0 +1,52 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/pkg/tracing"
+)
+
+// GenerateSummaryStream generates final requirements like GenerateSummary; while the streaming
+// feature is on for the session, onProgress receives the document generated so far after every chunk
+func (uc *SessionUsecase) GenerateSummaryStream(ctx context.Context, sessionID string, onProgress func(partial string)) (*entity.Session, error) {
+	ctx, span := tracing.Start(ctx, "SessionUsecase.GenerateSummaryStream", tracing.SessionID(sessionID))
+	defer span.End()
+
+	return uc.generateSummary(ctx, sessionID, onProgress)
+}
+
+// streamSummary reads a streamed summary to the end, the sectioned and delta generations are never streamed
+func (uc *SessionUsecase) streamSummary(
+	ctx context.Context,
+	session *entity.Session,
+	req *entity.LLMGenerateSummaryRequest,
+	onProgress func(partial string),
+) (string, error) {
+	chunks, err := uc.llm(session).GenerateSummaryStream(ctx, req)
+	if err != nil {
+		return "", err
+	}
+
+	var summary strings.Builder
+	for chunk := range chunks {
+		if chunk.Err != nil {
+			return "", chunk.Err
+		}
+		summary.WriteString(chunk.Content)
+		onProgress(summary.String())
+	}
+
+	// The channel is also closed when ctx is done before the stream ends
+	if err := ctx.Err(); err != nil {
+		return "", fmt.Errorf("summary stream interrupted: %w", err)
+	}
+	if summary.Len() == 0 {
+		return "", errors.New("invalid summary stream: empty result")
+	}
+
+	return summary.String(), nil
+}
@@ -0,0 +1,37 @@
+package session
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/futig/agent-backend/internal/entity"
+)
+
+// RecordTakeoverAction audits a step of a support operator acting in the session on behalf of its
+// user. The operator must not act when the step cannot be recorded
+func (uc *SessionUsecase) RecordTakeoverAction(
+	ctx context.Context,
+	sessionID string,
+	operatorID, userID int64,
+	action entity.TakeoverAction,
+	details map[string]any,
+) error {
+	eventDetails := map[string]any{
+		"operator_id": operatorID,
+		"user_id":     userID,
+		"action":      action,
+	}
+	for k, v := range details {
+		eventDetails[k] = v
+	}
+
+	if err := uc.auditRepo.RecordEvent(ctx, &entity.AuditEvent{
+		SessionID: sessionID,
+		Type:      entity.AuditEventOperatorTakeover,
+		Details:   eventDetails,
+	}); err != nil {
+		return fmt.Errorf("record takeover action: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,82 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// startTimeBudget starts the interview clock with the given budget or the configured default.
+// Failures are only logged: time-boxing must never break the interview itself.
+func (uc *SessionUsecase) startTimeBudget(ctx context.Context, sessionID string, budget time.Duration) {
+	if budget <= 0 {
+		budget = uc.defaultTimeBudget
+	}
+	if budget <= 0 {
+		return
+	}
+
+	if _, err := uc.timeBudgetRepo.StartBudget(ctx, sessionID, budget); err != nil {
+		ctxzap.Warn(ctx, "failed to start interview time budget",
+			zap.Error(err),
+			zap.String("session_id", sessionID),
+		)
+	}
+}
+
+// CheckTimeBudget returns how much of the interview time budget is used, or nil for sessions without one.
+// The returned status asks for a warning once, when the warning threshold is crossed.
+func (uc *SessionUsecase) CheckTimeBudget(ctx context.Context, sessionID string) (*entity.TimeBudgetStatus, error) {
+	budget, err := uc.timeBudgetRepo.GetBudget(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, entity.ErrTimeBudgetNotFound) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get time budget: %w", err)
+	}
+
+	status := &entity.TimeBudgetStatus{
+		Budget:  budget.Budget,
+		Elapsed: time.Now().UTC().Sub(budget.StartedAt),
+	}
+
+	if budget.WarnedAt == nil && float64(status.Elapsed) >= float64(status.Budget)*uc.timeBudgetWarnAt {
+		// Concurrent checks race for the mark so that only one of them warns
+		status.Warn, err = uc.timeBudgetRepo.MarkWarned(ctx, sessionID)
+		if err != nil {
+			return nil, fmt.Errorf("mark time budget warned: %w", err)
+		}
+	}
+
+	return status, nil
+}
+
+// SkipRemainingQuestions marks all unanswered questions as skipped so that generation can start early
+func (uc *SessionUsecase) SkipRemainingQuestions(ctx context.Context, sessionID string) (int, error) {
+	session, err := uc.sessionRepo.GetSessionByID(ctx, sessionID)
+	if err != nil {
+		return 0, fmt.Errorf("get session: %w", err)
+	}
+
+	if session.Status != entity.SessionStatusWaitingForAnswers {
+		return 0, fmt.Errorf("wrong action on status '%s'", session.Status)
+	}
+
+	skipped, err := uc.questionRepo.SkipUnansweredQuestions(ctx, sessionID)
+	if err != nil {
+		return 0, fmt.Errorf("skip remaining questions: %w", err)
+	}
+	uc.refreshCurrentQuestion(ctx, sessionID)
+
+	ctxzap.Info(ctx, "remaining questions skipped",
+		zap.String("session_id", sessionID),
+		zap.Int("count", skipped),
+	)
+
+	return skipped, nil
+}
@@ -0,0 +1,150 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/pkg/tracing"
+	"github.com/google/uuid"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// transcriptChunkChars is the size of the draft messages a transcript is split into,
+// so that moderation and the draft prompts see it the way they see collected Telegram messages
+const transcriptChunkChars = 4000
+
+// StartTranscriptSession creates a draft session from a meeting transcript and validates it
+// without interactive steps. Follow-up questions of the validation stay open and are listed
+// in the result. The session is switched to ERROR when any step fails.
+func (uc *SessionUsecase) StartTranscriptSession(
+	ctx context.Context,
+	req *entity.TranscriptSessionRequest,
+) (*entity.Session, error) {
+	ctx, span := tracing.Start(ctx, "SessionUsecase.StartTranscriptSession")
+	defer span.End()
+
+	sessionType := entity.SessionTypeDraft
+	session := &entity.Session{
+		ID:        req.SessionID,
+		Status:    entity.SessionStatusDraftCollecting,
+		Type:      &sessionType,
+		UserGoal:  &req.UserGoal,
+		ProjectID: req.ProjectID,
+		IsDemo:    req.Demo,
+	}
+	if session.ID == "" {
+		session.ID = uuid.New().String()
+	}
+
+	if req.ProjectID == nil {
+		projectContext := uc.formatManualContext(req.ContextQuestions)
+		session.ProjectContext = &projectContext
+	}
+
+	quota, err := uc.reserveQuota(ctx, entity.QuotaKindSessions, req.Demo)
+	if err != nil {
+		return nil, err
+	}
+	defer quota.done()
+
+	session, err = uc.sessionRepo.CreateFilledSession(ctx, session)
+	if err != nil {
+		return nil, fmt.Errorf("create filled session: %w", err)
+	}
+	quota.commit()
+	uc.detectLanguage(ctx, session, req.UserGoal)
+
+	if err := uc.prepareTranscriptSession(ctx, session, req.Transcript); err != nil {
+		errMsg := err.Error()
+		if _, updateErr := uc.sessionRepo.UpdateSessionResult(ctx, session.ID, entity.SessionStatusError, nil, &errMsg); updateErr != nil {
+			ctxzap.Error(ctx, "failed to mark session as failed",
+				zap.Error(updateErr),
+				zap.String("session_id", session.ID),
+			)
+		}
+		return nil, err
+	}
+
+	return uc.sessionRepo.GetSessionByID(ctx, session.ID)
+}
+
+// prepareTranscriptSession loads the project context, saves the transcript as draft messages and validates them
+func (uc *SessionUsecase) prepareTranscriptSession(ctx context.Context, session *entity.Session, transcript string) error {
+	session, err := uc.loadProjectContext(ctx, session)
+	if err != nil {
+		return err
+	}
+
+	chunks := splitTranscript(transcript, transcriptChunkChars)
+	for _, chunk := range chunks {
+		if _, err := uc.addDraftMessage(ctx, session.ID, chunk, false); err != nil {
+			return err
+		}
+	}
+
+	ctxzap.Info(ctx, "transcript saved as draft messages",
+		zap.String("session_id", session.ID),
+		zap.Int("messages", len(chunks)),
+	)
+
+	openQuestions, err := uc.ValidateDraftMessages(ctx, session.ID)
+	if err != nil {
+		return err
+	}
+
+	if openQuestions != nil {
+		ctxzap.Info(ctx, "transcript leaves follow-up questions open",
+			zap.String("session_id", session.ID),
+			zap.Int("questions", len(openQuestions.Questions)),
+		)
+	}
+
+	return nil
+}
+
+// splitTranscript splits a transcript into chunks of up to limit characters, packing whole lines
+// into a chunk while they fit; longer lines are cut
+func splitTranscript(transcript string, limit int) []string {
+	var chunks []string
+	var current strings.Builder
+
+	flush := func() {
+		if text := strings.TrimSpace(current.String()); text != "" {
+			chunks = append(chunks, text)
+		}
+		current.Reset()
+	}
+
+	for _, line := range strings.Split(strings.ReplaceAll(transcript, "\r\n", "\n"), "\n") {
+		for utf8.RuneCountInString(line) > limit {
+			flush()
+			cut := runeOffset(line, limit)
+			chunks = append(chunks, strings.TrimSpace(line[:cut]))
+			line = line[cut:]
+		}
+
+		if utf8.RuneCountInString(current.String())+utf8.RuneCountInString(line)+1 > limit {
+			flush()
+		}
+		current.WriteString(line)
+		current.WriteString("\n")
+	}
+	flush()
+
+	return chunks
+}
+
+// runeOffset returns the byte offset of the n-th rune of s
+func runeOffset(s string, n int) int {
+	for i := range s {
+		if n == 0 {
+			return i
+		}
+		n--
+	}
+	return len(s)
+}
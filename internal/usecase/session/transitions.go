@@ -0,0 +1,44 @@
+package session
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/pkg/metrics"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// transitionStatus moves a session from one status to another only while it is still in the from
+// status. A repeated transition that finds the session already in the target status is a no-op;
+// any other status means a concurrent flow moved the session on and the transition is rejected
+func (uc *SessionUsecase) transitionStatus(ctx context.Context, sessionID string, from, to entity.SessionStatus) (*entity.Session, error) {
+	session, applied, err := uc.sessionRepo.TransitionSessionStatus(ctx, sessionID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("update session status: %w", err)
+	}
+	if applied {
+		return session, nil
+	}
+
+	key := string(from) + "->" + string(to)
+	if session.Status == to {
+		metrics.SessionTransitionsRepeated.WithLabelValues(key).Inc()
+		ctxzap.Info(ctx, "session status transition repeated",
+			zap.String("session_id", sessionID),
+			zap.String("from", string(from)),
+			zap.String("to", string(to)),
+		)
+		return session, nil
+	}
+
+	metrics.SessionTransitionsRejected.WithLabelValues(key).Inc()
+	ctxzap.Warn(ctx, "session status transition rejected",
+		zap.String("session_id", sessionID),
+		zap.String("from", string(from)),
+		zap.String("to", string(to)),
+		zap.String("status", string(session.Status)),
+	)
+	return nil, fmt.Errorf("%w: wrong action on status '%s'", entity.ErrInvalidSessionStatus, session.Status)
+}
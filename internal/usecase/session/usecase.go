@@ -2,9 +2,12 @@ package session
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
 
 	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/pkg/tracing"
 	"github.com/futig/agent-backend/internal/pkg/validator"
 	"github.com/futig/agent-backend/internal/repository"
 	"github.com/google/uuid"
@@ -19,10 +22,58 @@ type SessionUsecase struct {
 	questionRepo       repository.QuestionRepository
 	projectRepo        repository.ProjectRepository
 	sessionMessageRepo repository.SessionMessageRepository
+	translationRepo    repository.SessionTranslationRepository
+	auditRepo          repository.AuditRepository
+	approvalRepo       repository.GenerationApprovalRepository
+	sectionRepo        repository.ResultSectionRepository
+	commentRepo        repository.CommentRepository
+	reviewRepo         repository.ReviewRepository
+	scheduleRepo       repository.ScheduleRepository
+	deltaRepo          repository.DeltaRepository
+	conflictRepo       repository.ConflictRepository
+	searchRepo         repository.SearchRepository
+	resultVersionRepo  repository.ResultVersionRepository
+	timeBudgetRepo     repository.TimeBudgetRepository
+	tenantRepo         repository.TenantRepository
+	conversationRepo   repository.ConversationLogRepository
+	pendingVoiceRepo   repository.PendingVoiceAnswerRepository
+	pendingQuestions   repository.PendingQuestionsRepository
+	factsRepo          repository.SessionFactsRepository
+	sessionLocks       repository.SessionLockRepository
+	sessionOperations  repository.SessionOperationRepository
+	genFailureRepo     repository.GenerationFailureRepository
+	explanationRepo    repository.QuestionExplanationRepository
 	validator          *validator.Validator
 	ragConnector       RagConnector
 	llmConnector       LLMConnector
 	asrConnector       ASRConnector
+	demoConnectors     DemoConnectors
+	moderator          Moderator
+	normalizer         Normalizer
+	transcriptPrefs    TranscriptPreferences
+	estimator          Estimator
+	reviewNotifier     ReviewNotifier
+	scheduleNotifier   ScheduleNotifier
+	voiceNotifier      VoiceAnswerNotifier
+	resultStore        ResultStore // nil keeps all results inline
+	themeResolver      ThemeResolver
+	featureFlags       FeatureFlags
+	quotas             Quotas
+	requireApproval    bool        // result must be approved before project save and export
+	inlineResultLimit  int         // results above this size in bytes go to resultStore
+	defaultTimeBudget  time.Duration
+	timeBudgetWarnAt   float64 // share of the time budget after which the user is warned
+	heartbeatInterval  time.Duration // minimum time between two heartbeats of a session
+	queueVoiceAnswers  bool          // voice answers are kept until speech recognition recovers
+	conversationWindow ConversationWindow
+	contextSnapshot    ContextSnapshot
+	minGoalWords       int // goals with fewer words get one clarifying question; 0 disables the check
+	dedupThreshold     float64 // similarity from which generated questions are merged; 0 disables the pass
+	lockWait           time.Duration // how long an action waits for another one on the same session; 0 disables the locks
+	operationTTL       time.Duration // how long a heavy operation holds its session after its last renewal; 0 disables the check
+	maxGenFailures     int // failed generations in a row after which the collected materials become the result; 0 disables it
+	explainOnDemand    bool          // explanations are generated by the LLM when asked for and cached per question
+	explanationCooldown time.Duration // minimum time between two generated explanations of a user; 0 disables it
 	logger             *zap.Logger
 }
 
@@ -33,10 +84,58 @@ func NewUsecase(
 	questionRepo repository.QuestionRepository,
 	projectRepo repository.ProjectRepository,
 	sessionMessageRepo repository.SessionMessageRepository,
+	translationRepo repository.SessionTranslationRepository,
+	auditRepo repository.AuditRepository,
+	approvalRepo repository.GenerationApprovalRepository,
+	sectionRepo repository.ResultSectionRepository,
+	commentRepo repository.CommentRepository,
+	reviewRepo repository.ReviewRepository,
+	scheduleRepo repository.ScheduleRepository,
+	deltaRepo repository.DeltaRepository,
+	conflictRepo repository.ConflictRepository,
+	searchRepo repository.SearchRepository,
+	resultVersionRepo repository.ResultVersionRepository,
+	timeBudgetRepo repository.TimeBudgetRepository,
+	tenantRepo repository.TenantRepository,
+	conversationRepo repository.ConversationLogRepository,
+	pendingVoiceRepo repository.PendingVoiceAnswerRepository,
+	pendingQuestions repository.PendingQuestionsRepository,
+	factsRepo repository.SessionFactsRepository,
+	sessionLocks repository.SessionLockRepository,
+	sessionOperations repository.SessionOperationRepository,
+	genFailureRepo repository.GenerationFailureRepository,
+	explanationRepo repository.QuestionExplanationRepository,
 	validator *validator.Validator,
 	ragConnector RagConnector,
 	llmConnector LLMConnector,
 	asrConnector ASRConnector,
+	demoConnectors DemoConnectors,
+	moderator Moderator,
+	normalizer Normalizer,
+	transcriptPrefs TranscriptPreferences,
+	estimator Estimator,
+	reviewNotifier ReviewNotifier,
+	scheduleNotifier ScheduleNotifier,
+	voiceNotifier VoiceAnswerNotifier,
+	resultStore ResultStore,
+	themeResolver ThemeResolver,
+	featureFlags FeatureFlags,
+	quotas Quotas,
+	requireApproval bool,
+	inlineResultLimit int,
+	defaultTimeBudget time.Duration,
+	timeBudgetWarnAt float64,
+	heartbeatInterval time.Duration,
+	queueVoiceAnswers bool,
+	conversationWindow ConversationWindow,
+	contextSnapshot ContextSnapshot,
+	minGoalWords int,
+	dedupThreshold float64,
+	lockWait time.Duration,
+	operationTTL time.Duration,
+	maxGenFailures int,
+	explainOnDemand bool,
+	explanationCooldown time.Duration,
 	logger *zap.Logger,
 ) *SessionUsecase {
 	return &SessionUsecase{
@@ -45,16 +144,73 @@ func NewUsecase(
 		questionRepo:       questionRepo,
 		projectRepo:        projectRepo,
 		sessionMessageRepo: sessionMessageRepo,
+		translationRepo:    translationRepo,
+		auditRepo:          auditRepo,
+		approvalRepo:       approvalRepo,
+		sectionRepo:        sectionRepo,
+		commentRepo:        commentRepo,
+		reviewRepo:         reviewRepo,
+		scheduleRepo:       scheduleRepo,
+		deltaRepo:          deltaRepo,
+		conflictRepo:       conflictRepo,
+		searchRepo:         searchRepo,
+		resultVersionRepo:  resultVersionRepo,
+		timeBudgetRepo:     timeBudgetRepo,
+		tenantRepo:         tenantRepo,
+		conversationRepo:   conversationRepo,
+		pendingVoiceRepo:   pendingVoiceRepo,
+		pendingQuestions:   pendingQuestions,
+		factsRepo:          factsRepo,
+		sessionLocks:       sessionLocks,
+		sessionOperations:  sessionOperations,
+		genFailureRepo:     genFailureRepo,
+		explanationRepo:    explanationRepo,
 		validator:          validator,
 		ragConnector:       ragConnector,
 		llmConnector:       llmConnector,
 		asrConnector:       asrConnector,
+		demoConnectors:     demoConnectors,
+		moderator:          moderator,
+		normalizer:         normalizer,
+		transcriptPrefs:    transcriptPrefs,
+		estimator:          estimator,
+		reviewNotifier:     reviewNotifier,
+		scheduleNotifier:   scheduleNotifier,
+		voiceNotifier:      voiceNotifier,
+		resultStore:        resultStore,
+		themeResolver:      themeResolver,
+		featureFlags:       featureFlags,
+		quotas:             quotas,
+		requireApproval:    requireApproval,
+		inlineResultLimit:  inlineResultLimit,
+		defaultTimeBudget:  defaultTimeBudget,
+		timeBudgetWarnAt:   timeBudgetWarnAt,
+		heartbeatInterval:  heartbeatInterval,
+		queueVoiceAnswers:  queueVoiceAnswers,
+		conversationWindow: conversationWindow,
+		contextSnapshot:    contextSnapshot,
+		minGoalWords:       minGoalWords,
+		dedupThreshold:     dedupThreshold,
+		lockWait:           lockWait,
+		operationTTL:       operationTTL,
+		maxGenFailures:     maxGenFailures,
+		explainOnDemand:    explainOnDemand,
+		explanationCooldown: explanationCooldown,
 		logger:             logger,
 	}
 }
 
 // StartSession creates an empty session in the database
 func (uc *SessionUsecase) StartSession(ctx context.Context) (*entity.Session, error) {
+	ctx, span := tracing.Start(ctx, "SessionUsecase.StartSession")
+	defer span.End()
+
+	quota, err := uc.reserveQuota(ctx, entity.QuotaKindSessions, false)
+	if err != nil {
+		return nil, err
+	}
+	defer quota.done()
+
 	session := entity.Session{
 		ID:     uuid.New().String(),
 		Status: entity.SessionStatusAskUserGoal,
@@ -64,12 +220,16 @@ func (uc *SessionUsecase) StartSession(ctx context.Context) (*entity.Session, er
 	if err != nil {
 		return nil, fmt.Errorf("create session: %w", err)
 	}
+	quota.commit()
 
 	return createdSession, nil
 }
 
 // SubmitAudioUserGoal transcribes audio and submits the goal as text
 func (uc *SessionUsecase) SubmitAudioUserGoal(ctx context.Context, sessionID string, audioGoal []byte) (*entity.Session, error) {
+	ctx, span := tracing.Start(ctx, "SessionUsecase.SubmitAudioUserGoal", tracing.SessionID(sessionID))
+	defer span.End()
+
 	session, err := uc.sessionRepo.GetSessionByID(ctx, sessionID)
 	if err != nil {
 		return nil, fmt.Errorf("get session: %w", err)
@@ -79,7 +239,7 @@ func (uc *SessionUsecase) SubmitAudioUserGoal(ctx context.Context, sessionID str
 		return nil, fmt.Errorf("wrong action on status '%s'", session.Status)
 	}
 
-	transcription, err := uc.transcribeAudio(ctx, sessionID, audioGoal)
+	transcription, err := uc.transcribeAudio(ctx, session, audioGoal)
 	if err != nil {
 		return nil, fmt.Errorf("failed to transcribe audio: %w", err)
 	}
@@ -89,6 +249,9 @@ func (uc *SessionUsecase) SubmitAudioUserGoal(ctx context.Context, sessionID str
 
 // SubmitTextUserGoal saves the user goal to the session
 func (uc *SessionUsecase) SubmitTextUserGoal(ctx context.Context, sessionID, goal string) (*entity.Session, error) {
+	ctx, span := tracing.Start(ctx, "SessionUsecase.SubmitTextUserGoal", tracing.SessionID(sessionID))
+	defer span.End()
+
 	session, err := uc.sessionRepo.GetSessionByID(ctx, sessionID)
 	if err != nil {
 		return nil, fmt.Errorf("get session: %w", err)
@@ -98,21 +261,45 @@ func (uc *SessionUsecase) SubmitTextUserGoal(ctx context.Context, sessionID, goa
 		return nil, fmt.Errorf("wrong action on status '%s'", session.Status)
 	}
 
+	// A goal saved while still asking for it is a vague one waiting for its clarification,
+	// which is appended to it; the clarifying question is asked only once
+	if session.UserGoal != nil && *session.UserGoal != "" {
+		goal = *session.UserGoal + "\n" + goal
+	} else if uc.isVagueGoal(goal) {
+		if _, err := uc.sessionRepo.UpdateSessionUserGoal(ctx, sessionID, goal); err != nil {
+			return nil, fmt.Errorf("update user goal: %w", err)
+		}
+		return nil, entity.ErrGoalTooVague
+	}
+
 	_, err = uc.sessionRepo.UpdateSessionUserGoal(ctx, sessionID, goal)
 	if err != nil {
 		return nil, fmt.Errorf("update user goal: %w", err)
 	}
+	uc.detectLanguage(ctx, session, goal)
+
+	return uc.transitionStatus(ctx, sessionID, entity.SessionStatusAskUserGoal, entity.SessionStatusSelectOrCreateProject)
+}
 
-	session, err = uc.sessionRepo.UpdateSessionStatus(ctx, sessionID, entity.SessionStatusSelectOrCreateProject)
+// SkipGoalClarification moves on to project selection with the vague goal as it is
+func (uc *SessionUsecase) SkipGoalClarification(ctx context.Context, sessionID string) (*entity.Session, error) {
+	session, err := uc.sessionRepo.GetSessionByID(ctx, sessionID)
 	if err != nil {
-		return nil, fmt.Errorf("update session status: %w", err)
+		return nil, fmt.Errorf("get session: %w", err)
 	}
 
-	return session, nil
+	if session.Status != entity.SessionStatusAskUserGoal || session.UserGoal == nil || *session.UserGoal == "" {
+		return nil, fmt.Errorf("wrong action on status '%s'", session.Status)
+	}
+
+	return uc.transitionStatus(ctx, sessionID, entity.SessionStatusAskUserGoal, entity.SessionStatusSelectOrCreateProject)
 }
 
 // SubmitRAGProjectContext generates RAG context for the project and saves it
 func (uc *SessionUsecase) SubmitRAGProjectContext(ctx context.Context, sessionID, projectID string) (*entity.Session, error) {
+	ctx, span := tracing.Start(ctx, "SessionUsecase.SubmitRAGProjectContext", tracing.SessionID(sessionID), tracing.ProjectID(projectID))
+	defer span.End()
+
 	session, err := uc.sessionRepo.GetSessionByID(ctx, sessionID)
 	if err != nil {
 		return nil, fmt.Errorf("get session: %w", err)
@@ -131,12 +318,11 @@ func (uc *SessionUsecase) SubmitRAGProjectContext(ctx context.Context, sessionID
 		return nil, fmt.Errorf("get project: %w", err)
 	}
 
-	ragContext, err := uc.ragConnector.GetContext(ctx, &entity.RAGGetContextRequest{
-		ProjectID:    projectID,
-		UserGoal:     *session.UserGoal,
-		TopK:         5,
-		MaxQuestions: 10,
-	})
+	if err := uc.settleIterationsForProject(ctx, session, &projectID); err != nil {
+		return nil, err
+	}
+
+	ragContext, err := uc.rag(session).GetContext(ctx, uc.ragContextRequest(ctx, projectID, *session.UserGoal))
 	if err != nil {
 		return nil, fmt.Errorf("get RAG context: %w", err)
 	}
@@ -146,16 +332,14 @@ func (uc *SessionUsecase) SubmitRAGProjectContext(ctx context.Context, sessionID
 		return nil, fmt.Errorf("update project context: %w", err)
 	}
 
-	session, err = uc.sessionRepo.UpdateSessionStatus(ctx, sessionID, entity.SessionStatusChooseMode)
-	if err != nil {
-		return nil, fmt.Errorf("update session status: %w", err)
-	}
-
-	return session, nil
+	return uc.transitionStatus(ctx, sessionID, entity.SessionStatusSelectOrCreateProject, entity.SessionStatusChooseMode)
 }
 
 // SubmitAudioUserProjectContext transcribes audio and submits manual context
 func (uc *SessionUsecase) SubmitAudioUserProjectContext(ctx context.Context, sessionID, questions string, audioAnswers []byte) (*entity.Session, error) {
+	ctx, span := tracing.Start(ctx, "SessionUsecase.SubmitAudioUserProjectContext", tracing.SessionID(sessionID))
+	defer span.End()
+
 	session, err := uc.sessionRepo.GetSessionByID(ctx, sessionID)
 	if err != nil {
 		return nil, fmt.Errorf("get session: %w", err)
@@ -165,7 +349,7 @@ func (uc *SessionUsecase) SubmitAudioUserProjectContext(ctx context.Context, ses
 		return nil, fmt.Errorf("wrong action on status '%s'", session.Status)
 	}
 
-	transcription, err := uc.transcribeAudio(ctx, sessionID, audioAnswers)
+	transcription, err := uc.transcribeAudio(ctx, session, audioAnswers)
 	if err != nil {
 		return nil, fmt.Errorf("failed to transcribe audio: %w", err)
 	}
@@ -175,6 +359,9 @@ func (uc *SessionUsecase) SubmitAudioUserProjectContext(ctx context.Context, ses
 
 // SubmitTextUserProjectContext formats and saves manual context from Q&A
 func (uc *SessionUsecase) SubmitTextUserProjectContext(ctx context.Context, sessionID, questions, answers string) (*entity.Session, error) {
+	ctx, span := tracing.Start(ctx, "SessionUsecase.SubmitTextUserProjectContext", tracing.SessionID(sessionID))
+	defer span.End()
+
 	session, err := uc.sessionRepo.GetSessionByID(ctx, sessionID)
 	if err != nil {
 		return nil, fmt.Errorf("get session: %w", err)
@@ -191,12 +378,7 @@ func (uc *SessionUsecase) SubmitTextUserProjectContext(ctx context.Context, sess
 		return nil, fmt.Errorf("update project context: %w", err)
 	}
 
-	session, err = uc.sessionRepo.UpdateSessionStatus(ctx, sessionID, entity.SessionStatusChooseMode)
-	if err != nil {
-		return nil, fmt.Errorf("update session status: %w", err)
-	}
-
-	return session, nil
+	return uc.transitionStatus(ctx, sessionID, entity.SessionStatusAskUserContext, entity.SessionStatusChooseMode)
 }
 
 // SetSessionType sets the session type (Interview or Draft mode)
@@ -214,6 +396,12 @@ func (uc *SessionUsecase) SetSessionType(ctx context.Context, sessionID string,
 		return nil, fmt.Errorf("wrong action on status '%s'", session.Status)
 	}
 
+	if sessionType == entity.SessionTypeDelta {
+		if err := uc.prepareDeltaBaseline(ctx, session); err != nil {
+			return nil, err
+		}
+	}
+
 	_, err = uc.sessionRepo.UpdateSessionType(ctx, sessionID, sessionType)
 	if err != nil {
 		return nil, fmt.Errorf("update session type: %w", err)
@@ -221,19 +409,14 @@ func (uc *SessionUsecase) SetSessionType(ctx context.Context, sessionID string,
 
 	var status entity.SessionStatus
 	switch sessionType {
-	case entity.SessionTypeInterview:
+	case entity.SessionTypeInterview, entity.SessionTypeDelta:
 		status = entity.SessionStatusInterviewInfo
 	case entity.SessionTypeDraft:
 		status = entity.SessionStatusDraftInfo
 	default:
 	}
 
-	session, err = uc.sessionRepo.UpdateSessionStatus(ctx, sessionID, status)
-	if err != nil {
-		return nil, fmt.Errorf("update session status: %w", err)
-	}
-
-	return session, nil
+	return uc.transitionStatus(ctx, sessionID, entity.SessionStatusChooseMode, status)
 }
 
 // StartManualContext switches session from SELECT_OR_CREATE_PROJECT to ASK_USER_CONTEXT
@@ -247,12 +430,11 @@ func (uc *SessionUsecase) StartManualContext(ctx context.Context, sessionID stri
 		return nil, fmt.Errorf("wrong action on status '%s'", session.Status)
 	}
 
-	updated, err := uc.sessionRepo.UpdateSessionStatus(ctx, sessionID, entity.SessionStatusAskUserContext)
-	if err != nil {
-		return nil, fmt.Errorf("update session status: %w", err)
+	if err := uc.settleIterationsForProject(ctx, session, nil); err != nil {
+		return nil, err
 	}
 
-	return updated, nil
+	return uc.transitionStatus(ctx, sessionID, entity.SessionStatusSelectOrCreateProject, entity.SessionStatusAskUserContext)
 }
 
 // RestartModeSelection switches session from INTERVIEW_INFO/DRAFT_INFO back to CHOOSE_MODE
@@ -267,16 +449,12 @@ func (uc *SessionUsecase) RestartModeSelection(ctx context.Context, sessionID st
 		return nil, fmt.Errorf("wrong action on status '%s'", session.Status)
 	}
 
-	updated, err := uc.sessionRepo.UpdateSessionStatus(ctx, sessionID, entity.SessionStatusChooseMode)
-	if err != nil {
-		return nil, fmt.Errorf("update session status: %w", err)
-	}
-
-	return updated, nil
+	return uc.transitionStatus(ctx, sessionID, session.Status, entity.SessionStatusChooseMode)
 }
 
 // RestartProjectSelection switches session from CHOOSE_MODE back to SELECT_OR_CREATE_PROJECT
 // so that user can re-select project or choose manual context again.
+// Questions generated before stay until the project is selected: the same project keeps them.
 func (uc *SessionUsecase) RestartProjectSelection(ctx context.Context, sessionID string) (*entity.Session, error) {
 	session, err := uc.sessionRepo.GetSessionByID(ctx, sessionID)
 	if err != nil {
@@ -287,12 +465,7 @@ func (uc *SessionUsecase) RestartProjectSelection(ctx context.Context, sessionID
 		return nil, fmt.Errorf("wrong action on status '%s'", session.Status)
 	}
 
-	updated, err := uc.sessionRepo.UpdateSessionStatus(ctx, sessionID, entity.SessionStatusSelectOrCreateProject)
-	if err != nil {
-		return nil, fmt.Errorf("update session status: %w", err)
-	}
-
-	return updated, nil
+	return uc.transitionStatus(ctx, sessionID, entity.SessionStatusChooseMode, entity.SessionStatusSelectOrCreateProject)
 }
 
 // StartDraftCollecting switches draft session from DRAFT_INFO to DRAFT_COLLECTING
@@ -310,16 +483,14 @@ func (uc *SessionUsecase) StartDraftCollecting(ctx context.Context, sessionID st
 		return nil, fmt.Errorf("wrong action on status '%s'", session.Status)
 	}
 
-	updated, err := uc.sessionRepo.UpdateSessionStatus(ctx, sessionID, entity.SessionStatusDraftCollecting)
-	if err != nil {
-		return nil, fmt.Errorf("update session status: %w", err)
-	}
-
-	return updated, nil
+	return uc.transitionStatus(ctx, sessionID, entity.SessionStatusDraftInfo, entity.SessionStatusDraftCollecting)
 }
 
 // LoadSessionQuestions generates questions and saves them to the database
 func (uc *SessionUsecase) LoadSessionQuestions(ctx context.Context, sessionID string) ([]*entity.IterationWithQuestions, error) {
+	ctx, span := tracing.Start(ctx, "SessionUsecase.LoadSessionQuestions", tracing.SessionID(sessionID))
+	defer span.End()
+
 	session, err := uc.sessionRepo.GetSessionByID(ctx, sessionID)
 	if err != nil {
 		return nil, fmt.Errorf("get session: %w", err)
@@ -346,7 +517,31 @@ func (uc *SessionUsecase) LoadSessionQuestions(ctx context.Context, sessionID st
 		projectDescription = &project.Description
 	}
 
-	blocks, err := uc.generateQuestionsBlocks(ctx, *session.UserGoal, *session.ProjectContext, projectDescription)
+	// Questions kept after returning to the same project are continued instead of generated again
+	loaded, err := uc.loadedIterations(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if len(loaded) > 0 {
+		if _, err := uc.transitionStatus(ctx, sessionID, entity.SessionStatusInterviewInfo, entity.SessionStatusWaitingForAnswers); err != nil {
+			return nil, err
+		}
+		uc.refreshCurrentQuestion(ctx, sessionID)
+
+		ctxzap.Info(ctx, "kept questions continued",
+			zap.String("session_id", sessionID),
+			zap.Int("iteration_count", len(loaded)),
+		)
+
+		return loaded, nil
+	}
+
+	var blocks []entity.QuestionsBlock
+	if isDeltaSession(session) {
+		blocks, err = uc.generateDeltaQuestionsBlocks(ctx, session, projectDescription)
+	} else {
+		blocks, err = uc.generateQuestionsBlocks(ctx, session, projectDescription)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("generate questions: %w", err)
 	}
@@ -357,15 +552,11 @@ func (uc *SessionUsecase) LoadSessionQuestions(ctx context.Context, sessionID st
 	}
 
 	// Update session status to waiting for answers
-	_, err = uc.sessionRepo.UpdateSessionStatus(ctx, sessionID, entity.SessionStatusWaitingForAnswers)
-	if err != nil {
-		return nil, fmt.Errorf("update session status: %w", err)
+	if _, err := uc.transitionStatus(ctx, sessionID, entity.SessionStatusInterviewInfo, entity.SessionStatusWaitingForAnswers); err != nil {
+		return nil, err
 	}
 
-	_, err = uc.sessionRepo.UpdateSessionStatus(ctx, sessionID, entity.SessionStatusWaitingForAnswers)
-	if err != nil {
-		return nil, fmt.Errorf("update session status: %w", err)
-	}
+	uc.startTimeBudget(ctx, sessionID, 0)
 
 	ctxzap.Info(ctx, "questions loaded successfully",
 		zap.String("session_id", sessionID),
@@ -377,6 +568,15 @@ func (uc *SessionUsecase) LoadSessionQuestions(ctx context.Context, sessionID st
 
 // SkipAnswer marks a question as skipped and returns the next question block
 func (uc *SessionUsecase) SkipAnswer(ctx context.Context, sessionID, questionID string) (*entity.IterationWithQuestions, error) {
+	ctx, span := tracing.Start(ctx, "SessionUsecase.SkipAnswer", tracing.SessionID(sessionID))
+	defer span.End()
+
+	unlock, err := uc.lockSession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
 	session, err := uc.sessionRepo.GetSessionByID(ctx, sessionID)
 	if err != nil {
 		return nil, fmt.Errorf("get session: %w", err)
@@ -389,6 +589,8 @@ func (uc *SessionUsecase) SkipAnswer(ctx context.Context, sessionID, questionID
 	if err := uc.questionRepo.SkipQuestion(ctx, questionID); err != nil {
 		return nil, fmt.Errorf("skip question: %w", err)
 	}
+	uc.logConversation(ctx, sessionID, questionID, entity.ConversationEntrySkip, "")
+	uc.refreshCurrentQuestion(ctx, sessionID)
 
 	iteration, err := uc.getCurrentIteration(ctx, sessionID)
 	if err != nil {
@@ -396,16 +598,68 @@ func (uc *SessionUsecase) SkipAnswer(ctx context.Context, sessionID, questionID
 	}
 
 	if iteration == nil {
-		_, err = uc.sessionRepo.UpdateSessionStatus(ctx, sessionID, entity.SessionStatusValidating)
+		// Deferred questions are still to be asked before validation
+		deferred, err := uc.questionRepo.GetDeferredQuestions(ctx, sessionID)
 		if err != nil {
-			return nil, fmt.Errorf("update session status: %w", err)
+			return nil, fmt.Errorf("get deferred questions: %w", err)
 		}
+
+		if len(deferred) == 0 {
+			_, err = uc.sessionRepo.UpdateSessionStatus(ctx, sessionID, entity.SessionStatusValidating)
+			if err != nil {
+				return nil, fmt.Errorf("update session status: %w", err)
+			}
+		}
+	}
+
+	return iteration, nil
+}
+
+// DeferQuestion moves a question to the end of the interview and returns the next question block
+func (uc *SessionUsecase) DeferQuestion(ctx context.Context, sessionID, questionID string) (*entity.IterationWithQuestions, error) {
+	unlock, err := uc.lockSession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	session, err := uc.sessionRepo.GetSessionByID(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("get session: %w", err)
+	}
+
+	if session.Status != entity.SessionStatusWaitingForAnswers {
+		return nil, fmt.Errorf("wrong action on status '%s'", session.Status)
+	}
+
+	if err := uc.questionRepo.DeferQuestion(ctx, questionID); err != nil {
+		return nil, fmt.Errorf("defer question: %w", err)
+	}
+	uc.logConversation(ctx, sessionID, questionID, entity.ConversationEntryDefer, "")
+	uc.refreshCurrentQuestion(ctx, sessionID)
+
+	iteration, err := uc.getCurrentIteration(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("get current/next iteration: %w", err)
 	}
 
 	return iteration, nil
 }
 
+// GetDeferredQuestions returns the questions deferred to the end of the interview
+func (uc *SessionUsecase) GetDeferredQuestions(ctx context.Context, sessionID string) ([]*entity.Question, error) {
+	questions, err := uc.questionRepo.GetDeferredQuestions(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("get deferred questions: %w", err)
+	}
+
+	return questions, nil
+}
+
 func (uc *SessionUsecase) SubmitAudioAnswer(ctx context.Context, sessionID, questionID string, audioAnswer []byte) (*entity.IterationWithQuestions, error) {
+	ctx, span := tracing.Start(ctx, "SessionUsecase.SubmitAudioAnswer", tracing.SessionID(sessionID))
+	defer span.End()
+
 	session, err := uc.sessionRepo.GetSessionByID(ctx, sessionID)
 	if err != nil {
 		return nil, fmt.Errorf("get session: %w", err)
@@ -415,15 +669,33 @@ func (uc *SessionUsecase) SubmitAudioAnswer(ctx context.Context, sessionID, ques
 		return nil, fmt.Errorf("wrong action on status '%s'", session.Status)
 	}
 
-	transcription, err := uc.transcribeAudio(ctx, sessionID, audioAnswer)
+	transcription, err := uc.transcribeAudio(ctx, session, audioAnswer)
 	if err != nil {
 		return nil, fmt.Errorf("failed to transcribe audio: %w", err)
 	}
 
-	return uc.SubmitTextAnswer(ctx, sessionID, questionID, transcription)
+	return uc.submitAnswer(ctx, sessionID, questionID, transcription, true)
 }
 
 func (uc *SessionUsecase) SubmitTextAnswer(ctx context.Context, sessionID, questionID, answer string) (*entity.IterationWithQuestions, error) {
+	ctx, span := tracing.Start(ctx, "SessionUsecase.SubmitTextAnswer", tracing.SessionID(sessionID))
+	defer span.End()
+
+	return uc.submitAnswer(ctx, sessionID, questionID, answer, false)
+}
+
+// submitAnswer saves an answer; transcribed answers go through the normalization pass
+func (uc *SessionUsecase) submitAnswer(
+	ctx context.Context,
+	sessionID, questionID, answer string,
+	transcribed bool,
+) (*entity.IterationWithQuestions, error) {
+	unlock, err := uc.lockSession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
 	session, err := uc.sessionRepo.GetSessionByID(ctx, sessionID)
 	if err != nil {
 		return nil, fmt.Errorf("get session: %w", err)
@@ -433,9 +705,22 @@ func (uc *SessionUsecase) SubmitTextAnswer(ctx context.Context, sessionID, quest
 		return nil, fmt.Errorf("wrong action on status '%s'", session.Status)
 	}
 
-	if err := uc.questionRepo.UpdateQuestionAnswer(ctx, questionID, answer); err != nil {
+	answer, err = uc.moderateInput(ctx, sessionID, entity.ModerationSourceAnswer, answer)
+	if err != nil {
+		return nil, err
+	}
+
+	var rawAnswer *string
+	if transcribed {
+		answer, rawAnswer = uc.normalizeTranscript(ctx, session, answer)
+	}
+	uc.detectLanguage(ctx, session, answer)
+
+	if err := uc.questionRepo.UpdateQuestionAnswer(ctx, sessionID, questionID, answer, rawAnswer); err != nil {
 		return nil, fmt.Errorf("save answer: %w", err)
 	}
+	uc.logConversation(ctx, sessionID, questionID, entity.ConversationEntryAnswer, answer)
+	uc.refreshCurrentQuestion(ctx, sessionID)
 
 	iteration, err := uc.getCurrentIteration(ctx, sessionID)
 	if err != nil {
@@ -463,7 +748,7 @@ func (uc *SessionUsecase) SubmitTextAnswer(ctx context.Context, sessionID, quest
 	return iteration, nil
 }
 
-// GetUnansweredQuestions returns all unanswered and skipped questions for a session
+// GetUnansweredQuestions returns all unanswered, skipped and deferred questions for a session
 func (uc *SessionUsecase) GetUnansweredQuestions(ctx context.Context, sessionID string) ([]*entity.Question, error) {
 	questions, err := uc.questionRepo.GetUnansweredQuestions(ctx, sessionID)
 	if err != nil {
@@ -487,6 +772,8 @@ func (uc *SessionUsecase) SkipSkipedQuestion(ctx context.Context, sessionID, que
 	if err := uc.questionRepo.SkipQuestion(ctx, questionID); err != nil {
 		return nil, fmt.Errorf("skip question: %w", err)
 	}
+	uc.logConversation(ctx, sessionID, questionID, entity.ConversationEntrySkip, "")
+	uc.refreshCurrentQuestion(ctx, sessionID)
 
 	questions, err := uc.questionRepo.GetUnansweredQuestions(ctx, sessionID)
 	if err != nil {
@@ -519,24 +806,52 @@ func (uc *SessionUsecase) SetWaitingForAnswersStatus(ctx context.Context, sessio
 	return nil
 }
 
-// GetQuestionExplanation returns explanation text for a given question
-func (uc *SessionUsecase) GetQuestionExplanation(ctx context.Context, questionID string) (string, error) {
+// GetQuestionByID returns a question by ID
+func (uc *SessionUsecase) GetQuestionByID(ctx context.Context, questionID string) (*entity.Question, error) {
 	question, err := uc.questionRepo.GetQuestionByID(ctx, questionID)
 	if err != nil {
-		return "", fmt.Errorf("get question: %w", err)
+		return nil, fmt.Errorf("get question: %w", err)
 	}
 
-	return question.Explanation, nil
+	return question, nil
 }
 
-// GetQuestionByID returns a question by ID
-func (uc *SessionUsecase) GetQuestionByID(ctx context.Context, questionID string) (*entity.Question, error) {
-	question, err := uc.questionRepo.GetQuestionByID(ctx, questionID)
+// GetQuestionProgress returns the position of a question across all blocks of the session,
+// counting blocks in order and questions within a block by their number, along with its block
+// and the number of answered questions
+func (uc *SessionUsecase) GetQuestionProgress(ctx context.Context, sessionID, questionID string) (*entity.QuestionProgress, error) {
+	questions, err := uc.questionRepo.ListQuestionsBySession(ctx, sessionID)
 	if err != nil {
-		return nil, fmt.Errorf("get question: %w", err)
+		return nil, fmt.Errorf("get questions by session: %w", err)
 	}
 
-	return question, nil
+	var progress *entity.QuestionProgress
+	blocks, answered := 0, 0
+	lastIterationID := ""
+	for i, q := range questions {
+		if q.IterationID != lastIterationID {
+			blocks++
+			lastIterationID = q.IterationID
+		}
+		if q.Status == entity.AnswerStatusAnswered {
+			answered++
+		}
+		if q.ID == questionID {
+			progress = &entity.QuestionProgress{
+				Number: i + 1,
+				Total:  len(questions),
+				Block:  blocks,
+			}
+		}
+	}
+
+	if progress == nil {
+		return nil, fmt.Errorf("question %s not found in session", questionID)
+	}
+	progress.Blocks = blocks
+	progress.Answered = answered
+
+	return progress, nil
 }
 
 // GetIterationByID returns an iteration with all its questions
@@ -554,13 +869,7 @@ func (uc *SessionUsecase) GetIterationByID(ctx context.Context, iterationID stri
 	// Convert to DTOs
 	questionDTOs := make([]entity.QuestionDTO, 0, len(questions))
 	for _, q := range questions {
-		questionDTOs = append(questionDTOs, entity.QuestionDTO{
-			ID:             q.ID,
-			QuestionNumber: q.QuestionNumber,
-			Question:       q.Question,
-			Explanation:    q.Explanation,
-			Status:         q.Status,
-		})
+		questionDTOs = append(questionDTOs, *questionModelToQuestionDTO(q))
 	}
 
 	return &entity.IterationWithQuestions{
@@ -574,6 +883,21 @@ func (uc *SessionUsecase) GetIterationByID(ctx context.Context, iterationID stri
 
 // ValidateAnswers validates completeness of answers and may return additional questions
 func (uc *SessionUsecase) ValidateAnswers(ctx context.Context, sessionID string) (*entity.IterationWithQuestions, error) {
+	ctx, span := tracing.Start(ctx, "SessionUsecase.ValidateAnswers", tracing.SessionID(sessionID))
+	defer span.End()
+
+	finish, err := uc.startOperation(ctx, sessionID, entity.OperationKindValidateAnswers)
+	if err != nil {
+		return nil, err
+	}
+	defer finish()
+
+	unlock, err := uc.lockSession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
 	session, err := uc.sessionRepo.GetSessionByID(ctx, sessionID)
 	if err != nil {
 		return nil, fmt.Errorf("get session: %w", err)
@@ -624,17 +948,30 @@ func (uc *SessionUsecase) ValidateAnswers(ctx context.Context, sessionID string)
 		return nil, fmt.Errorf("collect answers: %w", err)
 	}
 
+	skippedQuestions, err := uc.questionRepo.GetUnansweredQuestions(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("get unanswered questions: %w", err)
+	}
+	declined := declinedQuestions(skippedQuestions)
+
 	validateReq := &entity.LLMValidateAnswersRequest{
 		UserGoal:          *session.UserGoal,
 		ProjectContext:    *session.ProjectContext,
 		CompleteQuestions: allAnswers,
+		DeclinedQuestions: declined,
+		Conversation:      uc.recentConversation(ctx, sessionID),
+		KnownFacts:        uc.knownFacts(ctx, session, nil, allAnswers, nil),
+		TargetLanguage:    targetLanguage(session),
 	}
 
-	validateResp, err := uc.llmConnector.ValidateAnswers(ctx, validateReq)
+	validateResp, err := uc.llm(session).ValidateAnswers(ctx, validateReq)
 	if err != nil {
 		return nil, fmt.Errorf("validate answers: %w", err)
 	}
 
+	// Questions declined as not relevant are never asked again
+	validateResp.Questions = dropDeclinedQuestions(validateResp.Questions, declined)
+
 	status := entity.SessionStatusGeneratingRequirements
 	var additionalIteration *entity.IterationWithQuestions
 
@@ -651,6 +988,9 @@ func (uc *SessionUsecase) ValidateAnswers(ctx context.Context, sessionID string)
 		}
 
 		additionalIteration = savedIterations[0]
+		for _, q := range additionalIteration.Questions {
+			uc.logConversation(ctx, sessionID, q.ID, entity.ConversationEntryClarification, "")
+		}
 		status = entity.SessionStatusWaitingForAnswers
 	}
 
@@ -664,12 +1004,38 @@ func (uc *SessionUsecase) ValidateAnswers(ctx context.Context, sessionID string)
 
 // GenerateSummaty generates final requirements from all answers
 func (uc *SessionUsecase) GenerateSummary(ctx context.Context, sessionID string) (*entity.Session, error) {
+	ctx, span := tracing.Start(ctx, "SessionUsecase.GenerateSummary", tracing.SessionID(sessionID))
+	defer span.End()
+
+	return uc.generateSummary(ctx, sessionID, nil)
+}
+
+// generateSummary generates final requirements, onProgress receives the partial document while
+// a plain generation is streamed and may be nil
+func (uc *SessionUsecase) generateSummary(ctx context.Context, sessionID string, onProgress func(partial string)) (*entity.Session, error) {
+	finish, err := uc.startOperation(ctx, sessionID, entity.OperationKindGenerateSummary)
+	if err != nil {
+		return nil, err
+	}
+	defer finish()
+
+	unlock, err := uc.lockSession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
 	session, err := uc.sessionRepo.GetSessionByID(ctx, sessionID)
 	if err != nil {
 		return nil, fmt.Errorf("get session: %w", err)
 	}
 
-	if session.Status != entity.SessionStatusGeneratingRequirements && session.Status != entity.SessionStatusWaitingForAnswers {
+	// Draft sessions started over the API are one-shot transcript sessions
+	if session.Type != nil && *session.Type == entity.SessionTypeDraft {
+		return uc.generateDraftSummary(ctx, sessionID, true)
+	}
+
+	if session.Status != entity.SessionStatusGeneratingRequirements && session.Status != entity.SessionStatusWaitingForAnswers && session.Status != entity.SessionStatusPartial {
 		return nil, fmt.Errorf("wrong action on status '%s'", session.Status)
 	}
 
@@ -681,36 +1047,122 @@ func (uc *SessionUsecase) GenerateSummary(ctx context.Context, sessionID string)
 		return nil, fmt.Errorf("project context not set")
 	}
 
-	allAnswers, err := uc.collectAllAnswers(ctx, sessionID)
+	estimate, quota, err := uc.ensureGenerationAllowed(ctx, session)
 	if err != nil {
-		return nil, fmt.Errorf("collect answers: %w", err)
+		return nil, err
 	}
+	defer quota.done()
 
-	summaryReq := &entity.LLMGenerateSummaryRequest{
-		UserGoal:          *session.UserGoal,
-		ProjectContext:    *session.ProjectContext,
-		CompleteQuestions: allAnswers,
+	var summaryResp string
+	if isDeltaSession(session) {
+		// The change log and the updated document come from one pass over the baseline
+		summaryResp, err = uc.generateDeltaSummary(ctx, session)
+		if err != nil {
+			return uc.generationFailed(ctx, session, err)
+		}
+	} else if estimate.Sectioned {
+		summaryResp, err = uc.generateSectioned(ctx, session)
+		if err != nil {
+			return uc.generationFailed(ctx, session, err)
+		}
+	} else {
+		allAnswers, err := uc.collectAllAnswers(ctx, sessionID)
+		if err != nil {
+			return nil, fmt.Errorf("collect answers: %w", err)
+		}
+
+		summaryReq := &entity.LLMGenerateSummaryRequest{
+			UserGoal:          *session.UserGoal,
+			ProjectContext:    *session.ProjectContext,
+			CompleteQuestions: allAnswers,
+			Conversation:      uc.recentConversation(ctx, sessionID),
+			TargetLanguage:    targetLanguage(session),
+			Style:             summaryStyle(session),
+		}
+
+		if onProgress != nil && uc.featureEnabled(ctx, session, entity.FeatureStreaming) {
+			summaryResp, err = uc.streamSummary(ctx, session, summaryReq, onProgress)
+		} else {
+			summaryResp, err = uc.llm(session).GenerateSummary(ctx, summaryReq)
+		}
+		if err != nil {
+			return uc.generationFailed(ctx, session, fmt.Errorf("generate summary: %w", err))
+		}
 	}
 
-	summaryResp, err := uc.llmConnector.GenerateSummary(ctx, summaryReq)
+	uc.detectConflicts(ctx, session, summaryResp)
+
+	summaryResp, err = uc.appendSkipSummary(ctx, sessionID, summaryResp)
 	if err != nil {
-		return nil, fmt.Errorf("generate summary: %w", err)
+		return nil, err
 	}
 
-	updatedSession, err := uc.sessionRepo.UpdateSessionResult(ctx, sessionID, entity.SessionStatusDone, &summaryResp, nil)
+	updatedSession, err := uc.saveResult(ctx, session, summaryResp)
 	if err != nil {
 		return nil, fmt.Errorf("save summary: %w", err)
 	}
+	quota.commit()
+
+	// A changed result has to be reviewed again
+	if err := uc.resetReview(ctx, sessionID); err != nil {
+		return nil, err
+	}
 
 	return updatedSession, nil
 }
 
+// EstimateGeneration estimates final generation time and cost from the collected material size
+func (uc *SessionUsecase) EstimateGeneration(ctx context.Context, sessionID string) (*entity.GenerationEstimate, error) {
+	session, err := uc.sessionRepo.GetSessionByID(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("get session: %w", err)
+	}
+
+	return uc.estimateGeneration(ctx, session)
+}
+
+// ApproveGeneration records an admin approval for generating an unusually large session
+func (uc *SessionUsecase) ApproveGeneration(ctx context.Context, sessionID string) (*entity.GenerationEstimate, error) {
+	session, err := uc.sessionRepo.GetSessionByID(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("get session: %w", err)
+	}
+
+	if err := uc.approvalRepo.Approve(ctx, sessionID); err != nil {
+		return nil, fmt.Errorf("approve generation: %w", err)
+	}
+
+	estimate, err := uc.estimateGeneration(ctx, session)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.auditRepo.RecordEvent(ctx, &entity.AuditEvent{
+		SessionID: sessionID,
+		Type:      entity.AuditEventGenerationApproved,
+		Details: map[string]any{
+			"estimated_tokens":  estimate.EstimatedTokens,
+			"estimated_seconds": estimate.EstimatedSeconds,
+		},
+	}); err != nil {
+		ctxzap.Error(ctx, "failed to record generation approval event", zap.Error(err))
+	}
+
+	return estimate, nil
+}
+
 // GetSession retrieves a session by ID
 func (uc *SessionUsecase) GetSession(ctx context.Context, sessionID string) (*entity.Session, error) {
 	session, err := uc.sessionRepo.GetSessionByID(ctx, sessionID)
 	if err != nil {
 		return nil, fmt.Errorf("get session: %w", err)
 	}
+	if err := uc.loadResult(ctx, session); err != nil {
+		return nil, err
+	}
+	if err := uc.loadCurrentQuestion(ctx, session); err != nil {
+		return nil, err
+	}
 	return session, nil
 }
 
@@ -720,19 +1172,114 @@ func (uc *SessionUsecase) GetSessionResult(ctx context.Context, sessionID string
 		return "", fmt.Errorf("get session: %w", err)
 	}
 
-	if session.Status != entity.SessionStatusDone {
+	if session.Status != entity.SessionStatusDone && session.Status != entity.SessionStatusPartial {
 		return "", fmt.Errorf("wrong action on status '%s'", session.Status)
 	}
 
+	if err := uc.loadResult(ctx, session); err != nil {
+		return "", err
+	}
+
 	if session.Result == nil || *session.Result == "" {
 		return "", entity.ErrNoResult
 	}
 
+	// The collected materials hold only what the user provided, there is nothing to review
+	if session.Status == entity.SessionStatusDone {
+		if err := uc.EnsureResultReleasable(ctx, sessionID); err != nil {
+			return "", err
+		}
+	}
+
 	return *session.Result, nil
 }
 
+// GetResultFileInfo returns the project title, date and version used to name result documents;
+// the date is in the timezone of the user in ctx
+func (uc *SessionUsecase) GetResultFileInfo(ctx context.Context, sessionID string) (*entity.ResultFileInfo, error) {
+	session, err := uc.sessionRepo.GetSessionByID(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("get session: %w", err)
+	}
+
+	info := &entity.ResultFileInfo{
+		Date:    entity.LocalTime(ctx, session.UpdatedAt),
+		Version: session.CurrentIteration,
+	}
+	var project *entity.Project
+	if session.ProjectID != nil {
+		project, err = uc.projectRepo.Get(ctx, *session.ProjectID)
+		if err != nil {
+			return nil, fmt.Errorf("get project: %w", err)
+		}
+		info.Title = project.Title
+	}
+
+	// A broken theme must not block the download, the result is then rendered unbranded
+	info.Theme, err = uc.themeResolver.ResolveTheme(ctx, project)
+	if err != nil {
+		ctxzap.Warn(ctx, "failed to resolve document theme", zap.Error(err))
+	}
+
+	return info, nil
+}
+
+// GetTranslatedSessionResult returns the session result translated into the given language, cached per language
+func (uc *SessionUsecase) GetTranslatedSessionResult(ctx context.Context, sessionID string, language entity.ResultLanguage) (string, error) {
+	ctx, span := tracing.Start(ctx, "SessionUsecase.GetTranslatedSessionResult", tracing.SessionID(sessionID))
+	defer span.End()
+
+	if !language.IsValid() {
+		return "", fmt.Errorf("unsupported language '%s': %w", language, entity.ErrInvalidParameter)
+	}
+
+	result, err := uc.GetSessionResult(ctx, sessionID)
+	if err != nil {
+		return "", err
+	}
+
+	translated, err := uc.translationRepo.GetTranslation(ctx, sessionID, language)
+	if err == nil {
+		return translated, nil
+	}
+	if !errors.Is(err, entity.ErrTranslationNotFound) {
+		return "", fmt.Errorf("get translation: %w", err)
+	}
+
+	session, err := uc.sessionRepo.GetSessionByID(ctx, sessionID)
+	if err != nil {
+		return "", fmt.Errorf("get session: %w", err)
+	}
+
+	translated, err = uc.llm(session).Translate(ctx, &entity.LLMTranslateRequest{
+		Text:           result,
+		TargetLanguage: string(language),
+	})
+	if err != nil {
+		return "", fmt.Errorf("translate result: %w", err)
+	}
+
+	if err := uc.translationRepo.SaveTranslation(ctx, sessionID, language, translated); err != nil {
+		ctxzap.Warn(ctx, "failed to cache translation",
+			zap.Error(err),
+			zap.String("language", string(language)),
+		)
+	}
+
+	return translated, nil
+}
+
 // CancelSession cancels an active session
 func (uc *SessionUsecase) CancelSession(ctx context.Context, sessionID string) error {
+	ctx, span := tracing.Start(ctx, "SessionUsecase.CancelSession", tracing.SessionID(sessionID))
+	defer span.End()
+
+	unlock, err := uc.lockSession(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
 	session, err := uc.sessionRepo.GetSessionByID(ctx, sessionID)
 	if err != nil {
 		return fmt.Errorf("get session: %w", err)
@@ -775,6 +1322,19 @@ func (uc *SessionUsecase) AddDraftMessage(
 	ctx context.Context,
 	sessionID,
 	messageText string,
+) (*entity.SessionMessage, error) {
+	ctx, span := tracing.Start(ctx, "SessionUsecase.AddDraftMessage", tracing.SessionID(sessionID))
+	defer span.End()
+
+	return uc.addDraftMessage(ctx, sessionID, messageText, false)
+}
+
+// addDraftMessage saves a draft message; transcribed messages go through the normalization pass
+func (uc *SessionUsecase) addDraftMessage(
+	ctx context.Context,
+	sessionID,
+	messageText string,
+	transcribed bool,
 ) (*entity.SessionMessage, error) {
 	session, err := uc.sessionRepo.GetSessionByID(ctx, sessionID)
 	if err != nil {
@@ -785,7 +1345,18 @@ func (uc *SessionUsecase) AddDraftMessage(
 		return nil, fmt.Errorf("invalid session status for adding draft message: %s", session.Status)
 	}
 
-	msg, err := uc.sessionMessageRepo.CreateMessage(ctx, sessionID, messageText)
+	messageText, err = uc.moderateInput(ctx, sessionID, entity.ModerationSourceDraftMessage, messageText)
+	if err != nil {
+		return nil, err
+	}
+
+	var rawMessageText *string
+	if transcribed {
+		messageText, rawMessageText = uc.normalizeTranscript(ctx, session, messageText)
+	}
+	uc.detectLanguage(ctx, session, messageText)
+
+	msg, err := uc.sessionMessageRepo.CreateMessage(ctx, sessionID, messageText, rawMessageText)
 	if err != nil {
 		return nil, fmt.Errorf("create draft message: %w", err)
 	}
@@ -799,6 +1370,9 @@ func (uc *SessionUsecase) AddAudioDraftMessage(
 	sessionID string,
 	audioData []byte,
 ) (*entity.SessionMessage, error) {
+	ctx, span := tracing.Start(ctx, "SessionUsecase.AddAudioDraftMessage", tracing.SessionID(sessionID))
+	defer span.End()
+
 	session, err := uc.sessionRepo.GetSessionByID(ctx, sessionID)
 	if err != nil {
 		return nil, fmt.Errorf("get session: %w", err)
@@ -808,12 +1382,12 @@ func (uc *SessionUsecase) AddAudioDraftMessage(
 		return nil, fmt.Errorf("invalid session status for adding draft message: %s", session.Status)
 	}
 
-	transcription, err := uc.transcribeAudio(ctx, sessionID, audioData)
+	transcription, err := uc.transcribeAudio(ctx, session, audioData)
 	if err != nil {
 		return nil, fmt.Errorf("failed to transcribe audio: %w", err)
 	}
 
-	return uc.AddDraftMessage(ctx, sessionID, transcription)
+	return uc.addDraftMessage(ctx, sessionID, transcription, true)
 }
 
 // ValidateDraftMessages validates collected draft messages and may return additional questions
@@ -821,6 +1395,9 @@ func (uc *SessionUsecase) ValidateDraftMessages(
 	ctx context.Context,
 	sessionID string,
 ) (*entity.IterationWithQuestions, error) {
+	ctx, span := tracing.Start(ctx, "SessionUsecase.ValidateDraftMessages", tracing.SessionID(sessionID))
+	defer span.End()
+
 	session, err := uc.sessionRepo.GetSessionByID(ctx, sessionID)
 	if err != nil {
 		return nil, fmt.Errorf("get session: %w", err)
@@ -870,53 +1447,12 @@ func (uc *SessionUsecase) ValidateDraftMessages(
 		return nil, fmt.Errorf("update session status: %w", err)
 	}
 
-	messages, err := uc.sessionMessageRepo.GetSessionMessages(ctx, sessionID)
-	if err != nil {
-		return nil, fmt.Errorf("get session messages: %w", err)
-	}
-
-	if len(messages) == 0 {
-		return nil, fmt.Errorf("no draft messages to validate")
-	}
-
-	questions, err := uc.questionRepo.ListQuestionsBySession(ctx, sessionID)
+	req, err := uc.draftValidationRequest(ctx, session)
 	if err != nil {
-		return nil, fmt.Errorf("get questions by session: %w", err)
-	}
-
-	additionalQuestions := make([]entity.QuestionWithAnswer, 0, len(questions))
-	for _, q := range questions {
-		if q.Answer != nil {
-			additionalQuestions = append(additionalQuestions, entity.QuestionWithAnswer{
-				Question: q.Question,
-				Answer:   *q.Answer,
-			})
-		}
-	}
-
-	messageTexts := make([]string, 0, len(messages))
-	for _, m := range messages {
-		messageTexts = append(messageTexts, m.MessageText)
-	}
-
-	var projectDescription *string
-	if session.ProjectID != nil && *session.ProjectID != "" {
-		project, err := uc.projectRepo.Get(ctx, *session.ProjectID)
-		if err != nil {
-			return nil, fmt.Errorf("get project description: %w", err)
-		}
-		projectDescription = &project.Description
-	}
-
-	req := &entity.LLMValidateDraftRequest{
-		Messages:            messageTexts,
-		AdditionalQuestions: additionalQuestions,
-		UserGoal:            *session.UserGoal,
-		ProjectContext:      *session.ProjectContext,
-		ProjectDescription:  projectDescription,
+		return nil, err
 	}
 
-	validateResp, err := uc.llmConnector.ValidateDraft(ctx, req)
+	validateResp, err := uc.llm(session).ValidateDraft(ctx, req)
 	if err != nil {
 		return nil, fmt.Errorf("validate draft: %w", err)
 	}
@@ -949,12 +1485,33 @@ func (uc *SessionUsecase) ValidateDraftMessages(
 
 // GenerateDraftSummary generates final business requirements from draft messages and answers
 func (uc *SessionUsecase) GenerateDraftSummary(ctx context.Context, sessionID string) (*entity.Session, error) {
+	ctx, span := tracing.Start(ctx, "SessionUsecase.GenerateDraftSummary", tracing.SessionID(sessionID))
+	defer span.End()
+
+	finish, err := uc.startOperation(ctx, sessionID, entity.OperationKindGenerateSummary)
+	if err != nil {
+		return nil, err
+	}
+	defer finish()
+
+	unlock, err := uc.lockSession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	return uc.generateDraftSummary(ctx, sessionID, false)
+}
+
+// generateDraftSummary generates the draft result; withOpenQuestions appends the follow-up questions
+// left without an answer, for one-shot sessions nobody answers them in
+func (uc *SessionUsecase) generateDraftSummary(ctx context.Context, sessionID string, withOpenQuestions bool) (*entity.Session, error) {
 	session, err := uc.sessionRepo.GetSessionByID(ctx, sessionID)
 	if err != nil {
 		return nil, fmt.Errorf("get session: %w", err)
 	}
 
-	if session.Status != entity.SessionStatusGeneratingRequirements && session.Status != entity.SessionStatusWaitingForAnswers {
+	if session.Status != entity.SessionStatusGeneratingRequirements && session.Status != entity.SessionStatusWaitingForAnswers && session.Status != entity.SessionStatusPartial {
 		return nil, fmt.Errorf("invalid session status: %s", session.Status)
 	}
 
@@ -966,6 +1523,12 @@ func (uc *SessionUsecase) GenerateDraftSummary(ctx context.Context, sessionID st
 		return nil, fmt.Errorf("project context not set")
 	}
 
+	estimate, quota, err := uc.ensureGenerationAllowed(ctx, session)
+	if err != nil {
+		return nil, err
+	}
+	defer quota.done()
+
 	messages, err := uc.sessionMessageRepo.GetSessionMessages(ctx, sessionID)
 	if err != nil {
 		return nil, fmt.Errorf("get session messages: %w", err)
@@ -975,6 +1538,33 @@ func (uc *SessionUsecase) GenerateDraftSummary(ctx context.Context, sessionID st
 		return nil, fmt.Errorf("no draft messages to generate summary")
 	}
 
+	if estimate.Sectioned {
+		summary, err := uc.generateSectioned(ctx, session)
+		if err != nil {
+			return uc.generationFailed(ctx, session, err)
+		}
+
+		uc.detectConflicts(ctx, session, summary)
+
+		if withOpenQuestions {
+			if summary, err = uc.appendSkipSummary(ctx, sessionID, summary); err != nil {
+				return nil, err
+			}
+		}
+
+		updatedSession, err := uc.saveResult(ctx, session, summary)
+		if err != nil {
+			return nil, fmt.Errorf("save draft summary: %w", err)
+		}
+		quota.commit()
+
+		if err := uc.resetReview(ctx, sessionID); err != nil {
+			return nil, err
+		}
+
+		return updatedSession, nil
+	}
+
 	additionalQuestions, err := uc.collectAllAnswers(ctx, sessionID)
 	if err != nil {
 		return nil, fmt.Errorf("collect answers: %w", err)
@@ -1000,23 +1590,33 @@ func (uc *SessionUsecase) GenerateDraftSummary(ctx context.Context, sessionID st
 		UserGoal:            *session.UserGoal,
 		ProjectContext:      *session.ProjectContext,
 		ProjectDescription:  projectDescription,
+		TargetLanguage:      targetLanguage(session),
+		Style:               summaryStyle(session),
 	}
 
-	summary, err := uc.llmConnector.GenerateDraftSummary(ctx, req)
+	summary, err := uc.llm(session).GenerateDraftSummary(ctx, req)
 	if err != nil {
-		return nil, fmt.Errorf("generate draft summary: %w", err)
+		return uc.generationFailed(ctx, session, fmt.Errorf("generate draft summary: %w", err))
 	}
 
-	updatedSession, err := uc.sessionRepo.UpdateSessionResult(
-		ctx,
-		sessionID,
-		entity.SessionStatusDone,
-		&summary,
-		nil,
-	)
+	uc.detectConflicts(ctx, session, summary)
+
+	if withOpenQuestions {
+		if summary, err = uc.appendSkipSummary(ctx, sessionID, summary); err != nil {
+			return nil, err
+		}
+	}
+
+	updatedSession, err := uc.saveResult(ctx, session, summary)
 	if err != nil {
 		return nil, fmt.Errorf("save draft summary: %w", err)
 	}
+	quota.commit()
+
+	// A changed result has to be reviewed again
+	if err := uc.resetReview(ctx, sessionID); err != nil {
+		return nil, err
+	}
 
 	return updatedSession, nil
 }
@@ -0,0 +1,156 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// pendingVoiceLease is how long a claimed voice answer is hidden from other workers;
+// answers left behind while speech recognition is still down are retried after it
+const pendingVoiceLease = 5 * time.Minute
+
+// QueueVoiceAnswer keeps a voice answer that could not be transcribed because speech recognition
+// is unavailable; it is submitted once recognition recovers. Returns false when queueing is disabled.
+func (uc *SessionUsecase) QueueVoiceAnswer(
+	ctx context.Context,
+	sessionID, questionID string,
+	telegramUserID int64,
+	audio []byte,
+) (bool, error) {
+	if !uc.queueVoiceAnswers {
+		return false, nil
+	}
+
+	pending, err := uc.pendingVoiceRepo.CreatePendingVoiceAnswer(ctx, sessionID, questionID, telegramUserID, audio)
+	if err != nil {
+		return false, fmt.Errorf("queue voice answer: %w", err)
+	}
+
+	ctxzap.Info(ctx, "voice answer queued until speech recognition recovers",
+		zap.String("pending_id", pending.ID),
+		zap.String("session_id", sessionID),
+		zap.String("question_id", questionID),
+	)
+
+	return true, nil
+}
+
+// SubmitPendingVoiceAnswers transcribes queued voice answers of all tenants and saves them unless
+// their questions have been answered meanwhile. It stops at the first answer speech recognition
+// is still unavailable for. Returns the number of answers submitted.
+func (uc *SessionUsecase) SubmitPendingVoiceAnswers(ctx context.Context, limit int) (int, error) {
+	answers, err := uc.pendingVoiceRepo.ClaimPendingVoiceAnswers(ctx, pendingVoiceLease, limit)
+	if err != nil {
+		return 0, fmt.Errorf("claim pending voice answers: %w", err)
+	}
+
+	submitted := 0
+	for _, pending := range answers {
+		answerCtx := ctxzap.ToContext(ctx, ctxzap.Extract(ctx).With(
+			zap.String("pending_id", pending.ID),
+			zap.String("session_id", pending.SessionID),
+			zap.String("question_id", pending.QuestionID),
+		))
+
+		// Answers are claimed across tenants, each is submitted in the tenant it was queued in
+		tenant, err := uc.tenantRepo.Get(answerCtx, pending.TenantID)
+		if err != nil {
+			ctxzap.Error(answerCtx, "failed to resolve pending voice answer tenant", zap.Error(err))
+			continue
+		}
+		answerCtx = entity.WithTenant(answerCtx, tenant)
+
+		ok, err := uc.submitPendingVoiceAnswer(answerCtx, pending)
+		if errors.Is(err, entity.ErrASRUnavailable) {
+			ctxzap.Info(answerCtx, "speech recognition still unavailable, pending voice answers wait")
+			break
+		}
+		if err != nil {
+			ctxzap.Error(answerCtx, "failed to submit pending voice answer, dropping it", zap.Error(err))
+		}
+		if ok {
+			submitted++
+		}
+
+		if err := uc.pendingVoiceRepo.DeletePendingVoiceAnswer(answerCtx, pending.ID); err != nil {
+			ctxzap.Error(answerCtx, "failed to delete pending voice answer", zap.Error(err))
+		}
+	}
+
+	return submitted, nil
+}
+
+// submitPendingVoiceAnswer transcribes and saves a queued answer; false means the answer is no
+// longer needed because the question has been answered or the interview has moved on
+func (uc *SessionUsecase) submitPendingVoiceAnswer(ctx context.Context, pending *entity.PendingVoiceAnswer) (bool, error) {
+	session, err := uc.sessionRepo.GetSessionByID(ctx, pending.SessionID)
+	if err != nil {
+		return false, fmt.Errorf("get session: %w", err)
+	}
+
+	if session.Status != entity.SessionStatusWaitingForAnswers {
+		ctxzap.Info(ctx, "interview moved on, pending voice answer dropped",
+			zap.String("session_status", string(session.Status)),
+		)
+		return false, nil
+	}
+
+	question, err := uc.questionRepo.GetQuestionByID(ctx, pending.QuestionID)
+	if err != nil {
+		return false, fmt.Errorf("get question: %w", err)
+	}
+
+	if question.Status == entity.AnswerStatusAnswered {
+		ctxzap.Info(ctx, "question answered meanwhile, pending voice answer dropped")
+		return false, nil
+	}
+
+	transcription, err := uc.transcribeAudio(ctx, session, pending.Audio)
+	if err != nil {
+		return false, err
+	}
+
+	answer, err := uc.moderateInput(ctx, session.ID, entity.ModerationSourceAnswer, transcription)
+	if err != nil {
+		return false, err
+	}
+
+	answer, rawAnswer := uc.normalizeTranscript(ctx, session, answer)
+
+	saved, err := uc.questionRepo.AnswerOpenQuestion(ctx, pending.QuestionID, answer, rawAnswer)
+	if err != nil {
+		return false, fmt.Errorf("save answer: %w", err)
+	}
+	if !saved {
+		ctxzap.Info(ctx, "question answered meanwhile, pending voice answer dropped")
+		return false, nil
+	}
+	uc.logConversation(ctx, session.ID, pending.QuestionID, entity.ConversationEntryAnswer, answer)
+	uc.refreshCurrentQuestion(ctx, session.ID)
+
+	if err := uc.voiceNotifier.NotifyVoiceAnswerAccepted(ctx, pending.TelegramUserID, question.Question, answer); err != nil {
+		ctxzap.Warn(ctx, "failed to notify about accepted voice answer", zap.Error(err))
+	}
+
+	ctxzap.Info(ctx, "pending voice answer submitted",
+		zap.Duration("delay", time.Now().UTC().Sub(pending.CreatedAt)),
+	)
+
+	return true, nil
+}
+
+// PurgePendingVoiceAnswers drops voice answers queued before the given time
+func (uc *SessionUsecase) PurgePendingVoiceAnswers(ctx context.Context, before time.Time) (int, error) {
+	deleted, err := uc.pendingVoiceRepo.DeletePendingVoiceAnswersBefore(ctx, before)
+	if err != nil {
+		return 0, fmt.Errorf("delete pending voice answers: %w", err)
+	}
+
+	return deleted, nil
+}
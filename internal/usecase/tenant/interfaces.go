@@ -0,0 +1,8 @@
+package tenant
+
+import "context"
+
+// ResultObjectRemover deletes result bodies kept in S3-compatible storage
+type ResultObjectRemover interface {
+	DeleteObject(ctx context.Context, key string) error
+}
@@ -0,0 +1,321 @@
+package tenant
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/futig/agent-backend/internal/config"
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/pkg/validator"
+	"github.com/futig/agent-backend/internal/repository"
+	"github.com/google/uuid"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// apiKeyPrefix makes tenant API keys recognizable in configs and secret scanners
+const apiKeyPrefix = "ak_"
+
+// userAPIKeyPrefix tells the API keys of users from the keys of their tenants
+const userAPIKeyPrefix = "uk_"
+
+// purgeReportLimit is the number of latest purge reports returned for a tenant
+const purgeReportLimit = 100
+
+// TenantUsecase registers tenants and their users, resolves the tenant and user of API keys and bot tokens
+// and purges session content past the retention period of each tenant
+type TenantUsecase struct {
+	tenantRepo    repository.TenantRepository
+	userRepo      repository.UserRepository
+	retentionRepo repository.RetentionRepository
+	resultStore   ResultObjectRemover // nil when results are kept inline
+	retentionCfg  config.ContentRetentionConfig
+	validator     *validator.Validator
+	logger        *zap.Logger
+}
+
+// NewUsecase creates a new tenant use case
+func NewUsecase(
+	tenantRepo repository.TenantRepository,
+	userRepo repository.UserRepository,
+	retentionRepo repository.RetentionRepository,
+	resultStore ResultObjectRemover,
+	retentionCfg config.ContentRetentionConfig,
+	validator *validator.Validator,
+	logger *zap.Logger,
+) *TenantUsecase {
+	return &TenantUsecase{
+		tenantRepo:    tenantRepo,
+		userRepo:      userRepo,
+		retentionRepo: retentionRepo,
+		resultStore:   resultStore,
+		retentionCfg:  retentionCfg,
+		validator:     validator,
+		logger:        logger,
+	}
+}
+
+// CreateTenant registers a tenant and issues its API key
+func (uc *TenantUsecase) CreateTenant(ctx context.Context, req *entity.CreateTenantRequest) (*entity.CreateTenantResponse, error) {
+	if err := uc.validator.ValidateCreateTenant(req); err != nil {
+		return nil, err
+	}
+
+	if _, err := uc.tenantRepo.Get(ctx, req.ID); err == nil {
+		return nil, fmt.Errorf("%w: %s", entity.ErrTenantExists, req.ID)
+	} else if !errors.Is(err, entity.ErrTenantNotFound) {
+		return nil, err
+	}
+
+	apiKey, err := newAPIKey(apiKeyPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	tenant, err := uc.tenantRepo.Create(ctx, entity.Tenant{
+		ID:       req.ID,
+		Name:     req.Name,
+		Settings: req.Settings,
+	}, apiKey, req.BotToken)
+	if err != nil {
+		return nil, err
+	}
+
+	ctxzap.Info(ctx, "tenant created",
+		zap.String("tenant_id", tenant.ID),
+		zap.Bool("bot_mapped", req.BotToken != ""),
+	)
+
+	return &entity.CreateTenantResponse{
+		Tenant: tenant,
+		APIKey: apiKey,
+	}, nil
+}
+
+// ListTenants returns all registered tenants
+func (uc *TenantUsecase) ListTenants(ctx context.Context) ([]*entity.Tenant, error) {
+	return uc.tenantRepo.List(ctx)
+}
+
+// UpdateSettings replaces the configuration overrides of a tenant
+func (uc *TenantUsecase) UpdateSettings(ctx context.Context, id string, settings *entity.TenantSettings) (*entity.Tenant, error) {
+	if err := uc.validator.ValidateTenantSettings(settings); err != nil {
+		return nil, err
+	}
+
+	return uc.tenantRepo.UpdateSettings(ctx, id, *settings)
+}
+
+// GetTenant returns the tenant with the given ID
+func (uc *TenantUsecase) GetTenant(ctx context.Context, id string) (*entity.Tenant, error) {
+	return uc.tenantRepo.Get(ctx, id)
+}
+
+// ResolveAPIKey returns the tenant of an API key and, for user API keys, the user acting in it;
+// an empty key resolves to the default tenant
+func (uc *TenantUsecase) ResolveAPIKey(ctx context.Context, apiKey string) (*entity.Tenant, *entity.User, error) {
+	if apiKey == "" {
+		tenant, err := uc.tenantRepo.Get(ctx, entity.DefaultTenantID)
+		return tenant, nil, err
+	}
+
+	if strings.HasPrefix(apiKey, userAPIKeyPrefix) {
+		user, err := uc.userRepo.GetByAPIKey(ctx, apiKey)
+		if errors.Is(err, entity.ErrUserNotFound) {
+			return nil, nil, entity.ErrInvalidAPIKey
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		tenant, err := uc.tenantRepo.Get(ctx, user.TenantID)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return tenant, user, nil
+	}
+
+	tenant, err := uc.tenantRepo.GetByAPIKey(ctx, apiKey)
+	if errors.Is(err, entity.ErrTenantNotFound) {
+		return nil, nil, entity.ErrInvalidAPIKey
+	}
+
+	return tenant, nil, err
+}
+
+// ResolveBotToken returns the tenant a Telegram bot is mapped to; bots without a mapping
+// serve the default tenant
+func (uc *TenantUsecase) ResolveBotToken(ctx context.Context, botToken string) (*entity.Tenant, error) {
+	tenant, err := uc.tenantRepo.GetByBotToken(ctx, botToken)
+	if errors.Is(err, entity.ErrTenantNotFound) {
+		return uc.tenantRepo.Get(ctx, entity.DefaultTenantID)
+	}
+
+	return tenant, err
+}
+
+// CreateUser registers a user of the tenant and issues its API key
+func (uc *TenantUsecase) CreateUser(ctx context.Context, tenantID string, req *entity.CreateUserRequest) (*entity.CreateUserResponse, error) {
+	if err := uc.validator.ValidateCreateUser(req); err != nil {
+		return nil, err
+	}
+
+	tenant, err := uc.tenantRepo.Get(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	apiKey, err := newAPIKey(userAPIKeyPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := uc.userRepo.Create(ctx, entity.User{
+		ID:             uuid.New().String(),
+		TenantID:       tenant.ID,
+		Name:           req.Name,
+		TelegramUserID: req.TelegramUserID,
+	}, apiKey)
+	if err != nil {
+		return nil, err
+	}
+
+	ctxzap.Info(ctx, "user created",
+		zap.String("tenant_id", tenant.ID),
+		zap.String("user_id", user.ID),
+		zap.Bool("telegram_linked", user.TelegramUserID != nil),
+	)
+
+	return &entity.CreateUserResponse{
+		User:   user,
+		APIKey: apiKey,
+	}, nil
+}
+
+// ListUsers returns the users of the tenant
+func (uc *TenantUsecase) ListUsers(ctx context.Context, tenantID string) ([]*entity.User, error) {
+	tenant, err := uc.tenantRepo.Get(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.userRepo.List(entity.WithTenant(ctx, tenant))
+}
+
+// DeleteUser removes a user of the tenant; the projects and sessions of the user become shared by the tenant
+func (uc *TenantUsecase) DeleteUser(ctx context.Context, tenantID, userID string) error {
+	tenant, err := uc.tenantRepo.Get(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.userRepo.Delete(entity.WithTenant(ctx, tenant), userID); err != nil {
+		return err
+	}
+
+	ctxzap.Info(ctx, "user deleted", zap.String("tenant_id", tenant.ID), zap.String("user_id", userID))
+
+	return nil
+}
+
+// EnsureTelegramUser returns the user of a Telegram user in the tenant of ctx, creating it on first contact
+func (uc *TenantUsecase) EnsureTelegramUser(ctx context.Context, telegramUserID int64, name string) (*entity.User, error) {
+	return uc.userRepo.EnsureTelegramUser(ctx, telegramUserID, name)
+}
+
+// PurgeExpiredContent removes the goal, answers, drafts and results of the sessions without activity
+// for the retention period of their tenant and returns how many sessions were purged. The session
+// rows, question statuses and result sizes stay, so aggregate metrics still count them
+func (uc *TenantUsecase) PurgeExpiredContent(ctx context.Context, now time.Time) (int, error) {
+	tenants, err := uc.tenantRepo.List(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("list tenants: %w", err)
+	}
+
+	purged := 0
+	for _, tenant := range tenants {
+		days := uc.retentionDays(tenant)
+		if days == 0 {
+			continue
+		}
+
+		purge, objectKeys, err := uc.retentionRepo.PurgeSessions(ctx, tenant.ID, now.AddDate(0, 0, -days))
+		if err != nil {
+			// Other tenants are still purged, this one is retried on the next run
+			ctxzap.Error(ctx, "failed to purge session content",
+				zap.Error(err),
+				zap.String("tenant_id", tenant.ID),
+			)
+			continue
+		}
+		if purge == nil {
+			continue
+		}
+
+		uc.deleteResultObjects(ctx, objectKeys)
+
+		ctxzap.Info(ctx, "session content purged",
+			zap.String("tenant_id", tenant.ID),
+			zap.Int("retention_days", days),
+			zap.Int("sessions", purge.Sessions),
+			zap.Int("questions", purge.Questions),
+			zap.Int("messages", purge.Messages),
+			zap.Int("result_versions", purge.ResultVersions),
+			zap.Int64("result_bytes", purge.ResultBytes),
+		)
+		purged += purge.Sessions
+	}
+
+	return purged, nil
+}
+
+// ListPurges returns the latest content purge reports of the tenant
+func (uc *TenantUsecase) ListPurges(ctx context.Context, tenantID string) ([]*entity.RetentionPurge, error) {
+	tenant, err := uc.tenantRepo.Get(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.retentionRepo.ListPurges(ctx, tenant.ID, purgeReportLimit)
+}
+
+// retentionDays returns the content retention of the tenant, the configured default when it has no override
+func (uc *TenantUsecase) retentionDays(tenant *entity.Tenant) int {
+	if tenant.Settings.ContentRetentionDays > 0 {
+		return tenant.Settings.ContentRetentionDays
+	}
+	return uc.retentionCfg.Days
+}
+
+// deleteResultObjects removes the blob bodies of purged result versions; a failed deletion is only
+// logged, since the versions no longer point at the object
+func (uc *TenantUsecase) deleteResultObjects(ctx context.Context, objectKeys []string) {
+	if uc.resultStore == nil {
+		return
+	}
+
+	for _, key := range objectKeys {
+		if err := uc.resultStore.DeleteObject(ctx, key); err != nil {
+			ctxzap.Error(ctx, "failed to delete purged result object",
+				zap.Error(err),
+				zap.String("object_key", key),
+			)
+		}
+	}
+}
+
+// newAPIKey generates a random API key with the given prefix
+func newAPIKey(prefix string) (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generate API key: %w", err)
+	}
+
+	return prefix + hex.EncodeToString(buf), nil
+}
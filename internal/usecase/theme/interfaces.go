@@ -0,0 +1,10 @@
+package theme
+
+import "context"
+
+// AssetStore keeps theme logos and fonts; it is the blob storage of generated results
+type AssetStore interface {
+	PutObject(ctx context.Context, key string, data []byte, contentType string) error
+	GetObject(ctx context.Context, key string) ([]byte, error)
+	MarkSuperseded(ctx context.Context, key string) error
+}
@@ -0,0 +1,188 @@
+package theme
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/futig/agent-backend/internal/entity"
+	"github.com/futig/agent-backend/internal/pkg/validator"
+	"github.com/futig/agent-backend/internal/repository"
+	"github.com/google/uuid"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// ThemeUsecase manages document themes and resolves the theme of a result
+type ThemeUsecase struct {
+	themeRepo   repository.ThemeRepository
+	projectRepo repository.ProjectRepository
+	assetStore  AssetStore // nil when result blob storage is disabled, themes then have no assets
+	validator   *validator.Validator
+	logger      *zap.Logger
+}
+
+// NewUsecase creates a new theme use case
+func NewUsecase(
+	themeRepo repository.ThemeRepository,
+	projectRepo repository.ProjectRepository,
+	assetStore AssetStore,
+	validator *validator.Validator,
+	logger *zap.Logger,
+) *ThemeUsecase {
+	return &ThemeUsecase{
+		themeRepo:   themeRepo,
+		projectRepo: projectRepo,
+		assetStore:  assetStore,
+		validator:   validator,
+		logger:      logger,
+	}
+}
+
+// CreateTheme stores the theme assets in blob storage and creates the theme in the tenant of ctx
+func (uc *ThemeUsecase) CreateTheme(ctx context.Context, req *entity.CreateThemeRequest) (*entity.DocumentTheme, error) {
+	if err := uc.validator.ValidateCreateTheme(req); err != nil {
+		return nil, err
+	}
+	if (req.Logo != nil || req.Font != nil) && uc.assetStore == nil {
+		return nil, entity.ErrThemeAssetsUnavailable
+	}
+
+	theme := entity.DocumentTheme{
+		ID:           uuid.New().String(),
+		Name:         req.Name,
+		PrimaryColor: req.PrimaryColor,
+		HeaderText:   req.HeaderText,
+		FooterText:   req.FooterText,
+		FontName:     req.FontName,
+	}
+
+	if req.Logo != nil {
+		key := themeAssetKey(ctx, theme.ID, "logo")
+		if err := uc.assetStore.PutObject(ctx, key, req.Logo.Data, req.Logo.ContentType); err != nil {
+			return nil, fmt.Errorf("store theme logo: %w", err)
+		}
+		theme.LogoKey = key
+	}
+	if req.Font != nil {
+		key := themeAssetKey(ctx, theme.ID, "font")
+		if err := uc.assetStore.PutObject(ctx, key, req.Font.Data, req.Font.ContentType); err != nil {
+			return nil, fmt.Errorf("store theme font: %w", err)
+		}
+		theme.FontKey = key
+	}
+
+	created, err := uc.themeRepo.Create(ctx, theme)
+	if err != nil {
+		return nil, err
+	}
+
+	ctxzap.Info(ctx, "document theme created",
+		zap.String("theme_id", created.ID),
+		zap.Bool("has_logo", created.HasLogo),
+		zap.Bool("has_font", created.HasFont),
+	)
+
+	return created, nil
+}
+
+// ListThemes returns the themes of the tenant of ctx
+func (uc *ThemeUsecase) ListThemes(ctx context.Context) ([]*entity.DocumentTheme, error) {
+	return uc.themeRepo.List(ctx)
+}
+
+// DeleteTheme deletes a theme; projects using it fall back to the tenant theme and
+// its assets expire with the superseded results
+func (uc *ThemeUsecase) DeleteTheme(ctx context.Context, id string) error {
+	theme, err := uc.themeRepo.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.themeRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	for _, key := range []string{theme.LogoKey, theme.FontKey} {
+		if key == "" || uc.assetStore == nil {
+			continue
+		}
+		if err := uc.assetStore.MarkSuperseded(ctx, key); err != nil {
+			ctxzap.Warn(ctx, "failed to expire theme asset", zap.String("key", key), zap.Error(err))
+		}
+	}
+
+	ctxzap.Info(ctx, "document theme deleted", zap.String("theme_id", id))
+	return nil
+}
+
+// SetProjectTheme selects the theme of a project; a nil theme ID falls back to the tenant theme
+func (uc *ThemeUsecase) SetProjectTheme(ctx context.Context, projectID string, req *entity.SetProjectThemeRequest) error {
+	if err := uc.validator.ValidateSetProjectTheme(req); err != nil {
+		return err
+	}
+
+	if req.ThemeID != nil {
+		if _, err := uc.themeRepo.Get(ctx, *req.ThemeID); err != nil {
+			return err
+		}
+	}
+
+	return uc.projectRepo.SetTheme(ctx, projectID, req.ThemeID)
+}
+
+// ResolveTheme returns the theme of a result with its assets loaded: the theme of the project,
+// otherwise the theme of the tenant of ctx. Nil means the result is rendered unbranded.
+func (uc *ThemeUsecase) ResolveTheme(ctx context.Context, project *entity.Project) (*entity.DocumentTheme, error) {
+	var themeID string
+	if project != nil && project.ThemeID != nil {
+		themeID = *project.ThemeID
+	} else if tenant := entity.TenantFromContext(ctx); tenant != nil {
+		themeID = tenant.Settings.ThemeID
+	}
+	if themeID == "" {
+		return nil, nil
+	}
+
+	theme, err := uc.themeRepo.Get(ctx, themeID)
+	if errors.Is(err, entity.ErrThemeNotFound) {
+		// Tenant settings may still reference a deleted theme
+		ctxzap.Warn(ctx, "document theme not found, rendering without theme", zap.String("theme_id", themeID))
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if theme.Logo, err = uc.loadAsset(ctx, theme.LogoKey); err != nil {
+		return nil, fmt.Errorf("load theme logo: %w", err)
+	}
+	if theme.Font, err = uc.loadAsset(ctx, theme.FontKey); err != nil {
+		return nil, fmt.Errorf("load theme font: %w", err)
+	}
+
+	return theme, nil
+}
+
+// loadAsset downloads a theme asset; assets are skipped when blob storage was disabled afterwards
+func (uc *ThemeUsecase) loadAsset(ctx context.Context, key string) (*entity.ThemeAsset, error) {
+	if key == "" || uc.assetStore == nil {
+		return nil, nil
+	}
+
+	data, err := uc.assetStore.GetObject(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.ThemeAsset{
+		Data:        data,
+		ContentType: http.DetectContentType(data),
+	}, nil
+}
+
+// themeAssetKey returns the blob storage key of a theme asset
+func themeAssetKey(ctx context.Context, themeID, name string) string {
+	return fmt.Sprintf("themes/%s/%s/%s", entity.TenantIDFromContext(ctx), themeID, name)
+}
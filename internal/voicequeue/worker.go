@@ -0,0 +1,66 @@
+package voicequeue
+
+import (
+	"context"
+	"time"
+
+	"github.com/futig/agent-backend/internal/config"
+	"github.com/grpc-ecosystem/go-grpc-middleware/logging/zap/ctxzap"
+	"go.uber.org/zap"
+)
+
+// Submitter submits voice answers queued while speech recognition was unavailable
+type Submitter interface {
+	SubmitPendingVoiceAnswers(ctx context.Context, limit int) (int, error)
+}
+
+// Worker periodically retries queued voice answers, so they are submitted soon after
+// speech recognition recovers
+type Worker struct {
+	submitter Submitter
+	interval  time.Duration
+	batchSize int
+	logger    *zap.Logger
+}
+
+// New creates a worker retrying queued voice answers every cfg.PollInterval
+func New(cfg config.VoiceQueueConfig, submitter Submitter, logger *zap.Logger) *Worker {
+	return &Worker{
+		submitter: submitter,
+		interval:  cfg.PollInterval,
+		batchSize: cfg.BatchSize,
+		logger:    logger,
+	}
+}
+
+// Run retries queued voice answers until ctx is cancelled
+func (w *Worker) Run(ctx context.Context) {
+	ctx = ctxzap.ToContext(ctx, w.logger.With(zap.String("component", "voice_queue")))
+	ctxzap.Info(ctx, "voice queue worker started", zap.Duration("interval", w.interval))
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		w.tick(ctx)
+
+		select {
+		case <-ctx.Done():
+			ctxzap.Info(ctx, "voice queue worker stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *Worker) tick(ctx context.Context) {
+	submitted, err := w.submitter.SubmitPendingVoiceAnswers(ctx, w.batchSize)
+	if err != nil {
+		ctxzap.Error(ctx, "failed to submit queued voice answers", zap.Error(err))
+		return
+	}
+
+	if submitted > 0 {
+		ctxzap.Info(ctx, "queued voice answers submitted", zap.Int("count", submitted))
+	}
+}
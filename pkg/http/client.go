@@ -20,6 +20,7 @@ type httpConfig struct {
 	maxIdleConnsPerHost   int
 	transports            []TransportFunc
 	insecureSkipVerify    bool
+	dnsRefresh            time.Duration // 0 resolves hosts on every new connection
 }
 
 func defaultHTTPConfig() *httpConfig {
@@ -52,8 +53,13 @@ func newInternal(cfg *httpConfig) *http.Client {
 		KeepAlive: cfg.clientKeepAlive,
 	}
 
+	dialContext := dialer.DialContext
+	if cfg.dnsRefresh > 0 {
+		dialContext = newDNSCache(cfg.dnsRefresh).dialContext(&dialer)
+	}
+
 	transport := &http.Transport{
-		DialContext:           dialer.DialContext,
+		DialContext:           dialContext,
 		MaxIdleConns:          cfg.maxIdleConns,
 		MaxIdleConnsPerHost:   cfg.maxIdleConnsPerHost,
 		TLSHandshakeTimeout:   cfg.tlsHandshakeTimeout,
@@ -8,6 +8,8 @@ import (
 	"io"
 	"mime/multipart"
 	"net/http"
+	"sync/atomic"
+	"time"
 
 	"go.uber.org/zap"
 )
@@ -16,6 +18,7 @@ type Connector struct {
 	baseURL    string
 	httpClient *http.Client
 	logger     *zap.Logger
+	lastUsed   atomic.Int64 // unix nanoseconds of the last request
 }
 
 type ConnectorConfig struct {
@@ -24,11 +27,13 @@ type ConnectorConfig struct {
 }
 
 func NewConnector(config *ConnectorConfig, options ...HttpOpts) *Connector {
-	return &Connector{
+	c := &Connector{
 		baseURL:    config.BaseURL,
 		httpClient: newClient(options...),
 		logger:     config.Logger,
 	}
+	c.lastUsed.Store(time.Now().UnixNano())
+	return c
 }
 
 type RequestOpt func(*requestConfig)
@@ -97,6 +102,7 @@ func (c *Connector) DoRequest(ctx context.Context, method, endpoint string, reqB
 		req.Header.Set(key, value)
 	}
 
+	c.touch()
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return &NetworkError{Err: err}
@@ -127,6 +133,67 @@ func (c *Connector) DoRequest(ctx context.Context, method, endpoint string, reqB
 	return nil
 }
 
+// DoStreamRequest sends a JSON request and returns the body of a successful response unread, for
+// endpoints that stream server-sent events; the caller must close it
+func (c *Connector) DoStreamRequest(ctx context.Context, method, endpoint string, reqBody any, opts ...RequestOpt) (io.ReadCloser, error) {
+	// Apply request options
+	cfg := &requestConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	// Use override URL if provided, otherwise use baseURL + endpoint
+	var url string
+	if cfg.overrideURL != "" {
+		url = cfg.overrideURL
+	} else {
+		url = c.baseURL + endpoint
+	}
+
+	var bodyReader io.Reader
+	if reqBody != nil {
+		jsonData, err := json.Marshal(reqBody)
+		if err != nil {
+			return nil, fmt.Errorf("marshal request body: %w", err)
+		}
+		bodyReader = bytes.NewReader(jsonData)
+		// Attach payload to context for logging transport
+		ctx = context.WithValue(ctx, payloadContextKey{}, jsonData)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, bodyReader)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+
+	if reqBody != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "text/event-stream")
+
+	// Add custom headers
+	for key, value := range cfg.headers {
+		req.Header.Set(key, value)
+	}
+
+	c.touch()
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, &NetworkError{Err: err}
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		defer resp.Body.Close()
+		bodyBytes, _ := io.ReadAll(resp.Body)
+		return nil, &HTTPError{
+			StatusCode: resp.StatusCode,
+			Message:    string(bodyBytes),
+		}
+	}
+
+	return resp.Body, nil
+}
+
 // doSingleMultipartRequest performs a single multipart request
 func (c *Connector) DoMultipartRequest(ctx context.Context, method, endpoint string, prepareBody func(*multipart.Writer) error, respBody any, opts ...RequestOpt) error {
 	// Apply request options
@@ -169,6 +236,7 @@ func (c *Connector) DoMultipartRequest(ctx context.Context, method, endpoint str
 		req.Header.Set(key, value)
 	}
 
+	c.touch()
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
 		return &NetworkError{Err: err}
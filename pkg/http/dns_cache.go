@@ -0,0 +1,92 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"time"
+)
+
+// dnsCache keeps resolved addresses of hosts so new connections skip the DNS lookup. Addresses
+// older than the refresh period are resolved again; when that fails the old ones are used
+type dnsCache struct {
+	refresh  time.Duration
+	resolver *net.Resolver
+
+	mu      sync.Mutex
+	entries map[string]dnsEntry
+}
+
+type dnsEntry struct {
+	addrs      []string
+	resolvedAt time.Time
+}
+
+func newDNSCache(refresh time.Duration) *dnsCache {
+	return &dnsCache{
+		refresh:  refresh,
+		resolver: net.DefaultResolver,
+		entries:  make(map[string]dnsEntry),
+	}
+}
+
+// lookup returns the addresses of the host, resolving them when they are missing or stale
+func (c *dnsCache) lookup(ctx context.Context, host string) ([]string, error) {
+	c.mu.Lock()
+	entry, ok := c.entries[host]
+	c.mu.Unlock()
+
+	if ok && time.Since(entry.resolvedAt) < c.refresh {
+		return entry.addrs, nil
+	}
+
+	addrs, err := c.resolver.LookupHost(ctx, host)
+	if err != nil || len(addrs) == 0 {
+		if ok {
+			return entry.addrs, nil
+		}
+		if err == nil {
+			err = errors.New("no addresses resolved")
+		}
+		return nil, err
+	}
+
+	c.mu.Lock()
+	c.entries[host] = dnsEntry{addrs: addrs, resolvedAt: time.Now()}
+	c.mu.Unlock()
+
+	return addrs, nil
+}
+
+// dialContext dials the resolved addresses of the host in turn; IP addresses are dialed as they are
+func (c *dnsCache) dialContext(dialer *net.Dialer) func(ctx context.Context, network, address string) (net.Conn, error) {
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(address)
+		if err != nil || net.ParseIP(host) != nil {
+			return dialer.DialContext(ctx, network, address)
+		}
+
+		addrs, err := c.lookup(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+
+		var dialErr error
+		for _, addr := range addrs {
+			conn, err := dialer.DialContext(ctx, network, net.JoinHostPort(addr, port))
+			if err == nil {
+				return conn, nil
+			}
+			dialErr = err
+		}
+		return nil, dialErr
+	}
+}
+
+// WithDNSCache resolves hosts once per refresh period instead of on every new connection
+func WithDNSCache(refresh time.Duration) HttpOpts {
+	return func(c *httpConfig) {
+		c.dnsRefresh = refresh
+	}
+}
@@ -0,0 +1,58 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type otelTransport struct {
+	name      string
+	tracer    trace.Tracer
+	transport http.RoundTripper
+}
+
+func (t *otelTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, span := t.tracer.Start(req.Context(), t.name+" "+req.Method+" "+req.URL.Path,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.request.method", req.Method),
+			attribute.String("server.address", req.URL.Host),
+			attribute.String("url.path", req.URL.Path),
+		),
+	)
+	defer span.End()
+
+	reqCopy := req.Clone(ctx)
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(reqCopy.Header))
+
+	resp, err := t.transport.RoundTrip(reqCopy)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+	if resp.StatusCode >= http.StatusBadRequest {
+		span.SetStatus(codes.Error, strconv.Itoa(resp.StatusCode))
+	}
+	return resp, nil
+}
+
+// WithTracing records a client span named after the service for every request and propagates
+// the trace context of the request to the service in its headers
+func WithTracing(name string) HttpOpts {
+	return WithTransport(func(rt http.RoundTripper) http.RoundTripper {
+		return &otelTransport{
+			name:      name,
+			tracer:    otel.Tracer("github.com/futig/agent-backend/pkg/http"),
+			transport: rt,
+		}
+	})
+}
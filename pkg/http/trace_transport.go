@@ -0,0 +1,48 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptrace"
+	"time"
+)
+
+// RequestStats describes an outbound request for latency metrics
+type RequestStats struct {
+	Duration time.Duration
+	// NewConn is true when the request had to set up a connection instead of reusing an idle one
+	NewConn bool
+	Err     error
+}
+
+type traceTransport struct {
+	observe   func(RequestStats)
+	transport http.RoundTripper
+}
+
+func (t *traceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	stats := RequestStats{}
+	trace := &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			stats.NewConn = !info.Reused
+		},
+	}
+
+	start := time.Now()
+	resp, err := t.transport.RoundTrip(req.WithContext(httptrace.WithClientTrace(req.Context(), trace)))
+	stats.Duration = time.Since(start)
+	stats.Err = err
+	t.observe(stats)
+
+	return resp, err
+}
+
+// WithRequestObserver reports the duration of every request up to its response headers and whether
+// it reused a connection
+func WithRequestObserver(observe func(RequestStats)) HttpOpts {
+	return WithTransport(func(rt http.RoundTripper) http.RoundTripper {
+		return &traceTransport{
+			observe:   observe,
+			transport: rt,
+		}
+	})
+}
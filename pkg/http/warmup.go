@@ -0,0 +1,38 @@
+package http
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Warmup sends a GET request to the endpoint so the DNS lookup, the connection and the TLS handshake
+// are done before a real request needs them. Any HTTP status counts: the connection stays in the pool
+func (c *Connector) Warmup(ctx context.Context, endpoint string) (time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+endpoint, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	c.touch()
+	start := time.Now()
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, &NetworkError{Err: err}
+	}
+	// The body is drained so the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	resp.Body.Close()
+
+	return time.Since(start), nil
+}
+
+// IdleFor returns how long the connector has sent no requests, warm-up pings included
+func (c *Connector) IdleFor() time.Duration {
+	return time.Since(time.Unix(0, c.lastUsed.Load()))
+}
+
+func (c *Connector) touch() {
+	c.lastUsed.Store(time.Now().UnixNano())
+}